@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DRBulkActionType is the action a DRBulkAction applies to its DRPCs
+// +kubebuilder:validation:Enum=Failover;Relocate;Pause;Resume
+type DRBulkActionType string
+
+// Valid values for DRBulkActionType
+const (
+	// Fail the DRPCs over to the failover cluster
+	DRBulkActionFailover = DRBulkActionType("Failover")
+
+	// Relocate the DRPCs to the preferred cluster
+	DRBulkActionRelocate = DRBulkActionType("Relocate")
+
+	// Pause the reconcile of the DRPCs, holding their actions
+	DRBulkActionPause = DRBulkActionType("Pause")
+
+	// Resume the reconcile of paused DRPCs
+	DRBulkActionResume = DRBulkActionType("Resume")
+)
+
+// DRBulkActionSpec defines the action to apply to the DRPCs matching a label selector
+// +kubebuilder:validation:XValidation:rule="self.action != 'Failover' || (has(self.failoverCluster) && size(self.failoverCluster) > 0)", message="failoverCluster is required for Failover"
+// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="spec is immutable"
+type DRBulkActionSpec struct {
+	// Selector selects the DRPCs to apply the action to
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Namespaces are the namespaces of the DRPCs to select from, all namespaces if not set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Action to apply to each DRPC
+	Action DRBulkActionType `json:"action"`
+
+	// FailoverCluster is the cluster to fail the DRPCs over to, required for Failover
+	// +optional
+	FailoverCluster string `json:"failoverCluster,omitempty"`
+
+	// PreferredCluster is the cluster to relocate the DRPCs to, for Relocate, the preferred cluster of each DRPC if
+	// not set
+	// +optional
+	PreferredCluster string `json:"preferredCluster,omitempty"`
+
+	// MaxConcurrency is the maximum number of DRPCs whose action is in progress at once, 5 if not set
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+
+	// AbortOnError stops applying the action to more DRPCs once the action of a DRPC fails
+	// +optional
+	AbortOnError bool `json:"abortOnError,omitempty"`
+}
+
+// DRBulkActionPhase is the phase of a DRBulkAction
+// +kubebuilder:validation:Enum=Running;Completed;Aborted
+type DRBulkActionPhase string
+
+// Valid values for DRBulkActionPhase
+const (
+	DRBulkActionRunning   = DRBulkActionPhase("Running")
+	DRBulkActionCompleted = DRBulkActionPhase("Completed")
+	DRBulkActionAborted   = DRBulkActionPhase("Aborted")
+)
+
+// DRBulkActionDRPCState is the state of the action of a DRPC
+// +kubebuilder:validation:Enum=Pending;InProgress;Succeeded;Failed;Skipped
+type DRBulkActionDRPCState string
+
+// Valid values for DRBulkActionDRPCState
+const (
+	DRBulkActionDRPCPending    = DRBulkActionDRPCState("Pending")
+	DRBulkActionDRPCInProgress = DRBulkActionDRPCState("InProgress")
+	DRBulkActionDRPCSucceeded  = DRBulkActionDRPCState("Succeeded")
+	DRBulkActionDRPCFailed     = DRBulkActionDRPCState("Failed")
+	DRBulkActionDRPCSkipped    = DRBulkActionDRPCState("Skipped")
+)
+
+// DRBulkActionDRPCStatus is the result of the action of a DRPC
+type DRBulkActionDRPCStatus struct {
+	DRPCReference `json:",inline"`
+
+	State DRBulkActionDRPCState `json:"state"`
+
+	// TargetCluster is the cluster the DRPC is failed over or relocated to
+	// +optional
+	TargetCluster string `json:"targetCluster,omitempty"`
+
+	// Message is why the action of the DRPC failed or was skipped
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// StartTime is when the action of the DRPC was requested
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the DRPC completed its action
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// DRBulkActionSummary counts the DRPCs of a DRBulkAction by the state of their action
+type DRBulkActionSummary struct {
+	Pending    int `json:"pending,omitempty"`
+	InProgress int `json:"inProgress,omitempty"`
+	Succeeded  int `json:"succeeded,omitempty"`
+	Failed     int `json:"failed,omitempty"`
+	Skipped    int `json:"skipped,omitempty"`
+}
+
+// DRBulkActionStatus is the result of the action of each DRPC a DRBulkAction selected
+type DRBulkActionStatus struct {
+	Phase DRBulkActionPhase `json:"phase,omitempty"`
+
+	// DRPCs are the DRPCs the selector matched when the DRBulkAction started, and the results of their actions
+	// +optional
+	DRPCs []DRBulkActionDRPCStatus `json:"drpcs,omitempty"`
+
+	// Summary counts the DRPCs by the state of their action
+	// +optional
+	Summary DRBulkActionSummary `json:"summary,omitempty"`
+
+	// StartTime is when the DRBulkAction started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the DRBulkAction completed or was aborted
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:JSONPath=".spec.action",name=action,type=string
+//+kubebuilder:printcolumn:JSONPath=".status.phase",name=phase,type=string
+//+kubebuilder:printcolumn:JSONPath=".status.summary.succeeded",name=succeeded,type=integer
+//+kubebuilder:printcolumn:JSONPath=".status.summary.failed",name=failed,type=integer
+
+// DRBulkAction applies an action to all the DRPCs matching a label selector, with bounded concurrency
+type DRBulkAction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DRBulkActionSpec   `json:"spec,omitempty"`
+	Status DRBulkActionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DRBulkActionList contains a list of DRBulkAction
+type DRBulkActionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DRBulkAction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DRBulkAction{}, &DRBulkActionList{})
+}
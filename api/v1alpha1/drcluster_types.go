@@ -4,6 +4,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,6 +21,32 @@ const (
 
 type Region string
 
+// OperatorDeploymentMode is how the hub deploys the dr-cluster operator to a managed cluster
+// +kubebuilder:validation:Enum=OLM;Manifests
+type OperatorDeploymentMode string
+
+const (
+	// OperatorDeploymentModeOLM deploys the operator with an OLM Subscription
+	OperatorDeploymentModeOLM = OperatorDeploymentMode("OLM")
+
+	// OperatorDeploymentModeManifests deploys the operator's Deployment and RBAC, for clusters without OLM
+	OperatorDeploymentModeManifests = OperatorDeploymentMode("Manifests")
+)
+
+// OperatorProfile is the footprint the dr-cluster operator runs with on a managed cluster
+// +kubebuilder:validation:Enum=Standard;Edge
+type OperatorProfile string
+
+const (
+	// OperatorProfileStandard runs all the controllers of the operator
+	OperatorProfileStandard = OperatorProfile("Standard")
+
+	// OperatorProfileEdge runs the operator with a reduced footprint, for small edge clusters: without kube object
+	// protection, and so without Velero nor recipes, with a single reconcile at a time, longer resyncs and a lower
+	// memory limit
+	OperatorProfileEdge = OperatorProfile("Edge")
+)
+
 // DRClusterSpec defines the desired state of DRCluster
 type DRClusterSpec struct {
 	// CIDRs is a list of CIDR strings. An admin can use this field to indicate
@@ -33,9 +60,16 @@ type DRClusterSpec struct {
 
 	// Region of a managed cluster determines it DR group.
 	// All managed clusters in a region are considered to be in a sync group.
-	// +kubebuilder:validation:Required
+	// Defaults to the value of the region.open-cluster-management.io cluster claim of the managed cluster.
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
-	Region Region `json:"region"`
+	Region Region `json:"region,omitempty"`
+
+	// Zone of a managed cluster within its region, such as a data center or an availability zone. The managed
+	// clusters of a DRPolicy in the same region are expected to be in different zones. Defaults to the value of the
+	// zone.open-cluster-management.io cluster claim of the managed cluster.
+	// +optional
+	Zone string `json:"zone,omitempty"`
 
 	// S3 profile name (in Ramen config) to use as a source to restore PV
 	// related cluster state during recovery or relocate actions of applications
@@ -47,6 +81,171 @@ type DRClusterSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="s3ProfileName is immutable"
 	S3ProfileName string `json:"s3ProfileName"`
+
+	// ImageRegistryMirrors rewrite the container images of the workloads recovered to this managed cluster, from
+	// registries local to other regions to the mirrors local to its region. Velero rewrites them as it restores the
+	// workloads, with its change image name restore item action, and Ramen rewrites those it did not afterwards.
+	// +optional
+	ImageRegistryMirrors []ImageRegistryMirror `json:"imageRegistryMirrors,omitempty"`
+
+	// TopologyTranslations rewrite the node affinity of the PVs restored to this managed cluster, from the topology
+	// of the cluster they were protected on to the topology of this cluster
+	// +optional
+	TopologyTranslations []TopologyTranslation `json:"topologyTranslations,omitempty"`
+
+	// StorageClassMappings rewrite the storage classes of the PVCs and PVs restored to this managed cluster, and of
+	// the VolSync destinations replicated to it, from the storage classes of the clusters they were protected on to
+	// the storage classes of this cluster, for clusters whose storage classes are named differently
+	// +optional
+	StorageClassMappings []StorageClassMapping `json:"storageClassMappings,omitempty"`
+
+	// AccessModeMappings rewrite the access modes of the PVCs and PVs restored to this managed cluster, and of the
+	// VolSync destinations replicated to it, for storage of this cluster that does not support the access modes of
+	// the clusters they were protected on. The restored PVCs whose access modes are rewritten are reported in the
+	// AccessModesPreserved condition of the VRG.
+	// +optional
+	AccessModeMappings []AccessModeMapping `json:"accessModeMappings,omitempty"`
+
+	// DomainSuffixTranslations rewrite the hostnames of the Ingresses, Gateways and HTTPRoutes recovered to this
+	// managed cluster, from the domains of the clusters they were protected on to the domains of this cluster
+	// +optional
+	DomainSuffixTranslations []DomainSuffixTranslation `json:"domainSuffixTranslations,omitempty"`
+
+	// GatewayClassTranslations rewrite the gateway classes of the Gateways recovered to this managed cluster, from
+	// the gateway classes of the clusters they were protected on to the gateway classes of this cluster
+	// +optional
+	GatewayClassTranslations []GatewayClassTranslation `json:"gatewayClassTranslations,omitempty"`
+
+	// SecretRewrites rewrite the values of the Secrets recovered to this managed cluster, such as the connection
+	// strings of external databases that embed the endpoints of the regions of the clusters they were protected on
+	// +optional
+	SecretRewrites []SecretRewrite `json:"secretRewrites,omitempty"`
+
+	// NetworkAttachmentMappings rewrite the NetworkAttachmentDefinitions the workloads recovered to this managed
+	// cluster attach their pods to, from the networks of the clusters they were protected on to the networks of this
+	// cluster
+	// +optional
+	NetworkAttachmentMappings []NetworkAttachmentMapping `json:"networkAttachmentMappings,omitempty"`
+
+	// OperatorDeploymentMode is how the hub deploys the dr-cluster operator to this managed cluster, when its
+	// deployment automation is enabled: OLM, the default, or Manifests, for clusters without OLM, such as kubeadm,
+	// EKS or GKE clusters. The CRDs of the dr-cluster operator are to be installed on clusters deployed to with
+	// Manifests.
+	// +optional
+	OperatorDeploymentMode OperatorDeploymentMode `json:"operatorDeploymentMode,omitempty"`
+
+	// OperatorProfile is the footprint the hub configures the dr-cluster operator of this managed cluster to run
+	// with, when its deployment automation is enabled: Standard, the default, or Edge, for small edge clusters
+	// +optional
+	OperatorProfile OperatorProfile `json:"operatorProfile,omitempty"`
+}
+
+// ImageRegistryMirror maps the images of a registry, or of a repository path in it, to a mirror
+type ImageRegistryMirror struct {
+	// Source is the registry host, optionally followed by a repository path, of the images to rewrite,
+	// such as registry.us-east.example.com/apps
+	Source string `json:"source"`
+
+	// Mirror replaces the source in the rewritten images, such as registry.eu-west.example.com/apps
+	Mirror string `json:"mirror"`
+}
+
+// TopologyTranslation maps a topology label of the node affinity of a PV, such as a zone, to a label of the
+// target topology
+type TopologyTranslation struct {
+	// Key of the topology label, such as topology.kubernetes.io/zone
+	Key string `json:"key"`
+
+	// Value of the topology label to translate. All values are translated if empty.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// TargetKey replaces the key of the label. Defaults to the key.
+	// +optional
+	TargetKey string `json:"targetKey,omitempty"`
+
+	// TargetValue replaces the value of the label. Defaults to the value.
+	// +optional
+	TargetValue string `json:"targetValue,omitempty"`
+}
+
+// StorageClassMapping maps a storage class of PVCs and PVs to another
+type StorageClassMapping struct {
+	// Source is the name of the storage class to rewrite
+	Source string `json:"source"`
+
+	// Target is the name of the storage class that replaces it
+	Target string `json:"target"`
+
+	// VolumeAttributes are set on the CSI volume attributes of the PVs mapped, in addition to the parameters of the
+	// target storage class, for the attributes its CSI driver expects that are specific to a volume
+	// +optional
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+}
+
+// AccessModeMapping maps an access mode of PVCs and PVs to another, such as ReadWriteMany to ReadWriteOnce
+type AccessModeMapping struct {
+	// Selector selects the PVCs to rewrite, and the PVs they are bound to, by the labels of the PVCs. All the
+	// restored PVCs are rewritten if not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Source is the access mode to rewrite
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany;ReadWriteOncePod
+	Source corev1.PersistentVolumeAccessMode `json:"source"`
+
+	// Target is the access mode that replaces it
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadOnlyMany;ReadWriteMany;ReadWriteOncePod
+	Target corev1.PersistentVolumeAccessMode `json:"target"`
+}
+
+// DomainSuffixTranslation maps the hostnames in a domain to the same hostnames in another domain
+type DomainSuffixTranslation struct {
+	// Source is the domain suffix of the hostnames to rewrite, such as apps.cluster1.example.com. It matches the
+	// hostnames that equal it or end with a dot followed by it, including wildcard hostnames.
+	Source string `json:"source"`
+
+	// Target replaces the source in the rewritten hostnames, such as apps.cluster2.example.com
+	Target string `json:"target"`
+}
+
+// GatewayClassTranslation maps a gateway class of Gateways to another
+type GatewayClassTranslation struct {
+	// Source is the name of the gateway class to rewrite
+	Source string `json:"source"`
+
+	// Target is the name of the gateway class that replaces it
+	Target string `json:"target"`
+}
+
+// NetworkAttachmentMapping maps a NetworkAttachmentDefinition that pods attach to to another
+type NetworkAttachmentMapping struct {
+	// Source is the NetworkAttachmentDefinition to rewrite, as the pods reference it in their
+	// k8s.v1.cni.cncf.io/networks annotation: its name, or its namespace and name separated by a slash
+	Source string `json:"source"`
+
+	// Target is the NetworkAttachmentDefinition that replaces it, as its name, or its namespace and name separated
+	// by a slash
+	Target string `json:"target"`
+}
+
+// SecretRewrite rewrites the matches of a regular expression in the values of Secrets
+type SecretRewrite struct {
+	// Selector selects the Secrets to rewrite by their labels. All the recovered Secrets are rewritten if not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Keys are the keys of the values to rewrite. All the values of a Secret are rewritten if not set.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Pattern is the regular expression, in RE2 syntax, to match in the values, such as
+	// (db|cache)\.us-east-1\.example\.com
+	Pattern string `json:"pattern"`
+
+	// Replacement replaces each match of the pattern, and may refer to its submatches as $1 or ${name}, such as
+	// ${1}.us-west-2.example.com
+	Replacement string `json:"replacement"`
 }
 
 const (
@@ -60,6 +259,18 @@ const (
 	// Fencing CR to fence off this cluster
 	// has been created
 	DRClusterConditionTypeFenced = "Fenced"
+
+	// Hub operations against the managed cluster are succeeding, and
+	// are not being refused after repeated failures
+	DRClusterConditionTypeReachable = "Reachable"
+
+	// The dr-cluster operator on the managed cluster recently reported
+	// that the components it depends on are healthy
+	DRClusterConditionTypeOperatorHealthy = "OperatorHealthy"
+
+	// The version of the dr-cluster operator on the managed cluster
+	// differs from the hub operator's
+	DRClusterConditionTypeSkewDetected = "SkewDetected"
 )
 
 type DRClusterPhase string
@@ -115,6 +326,93 @@ type DRClusterStatus struct {
 	Phase            DRClusterPhase           `json:"phase,omitempty"`
 	Conditions       []metav1.Condition       `json:"conditions,omitempty"`
 	MaintenanceModes []ClusterMaintenanceMode `json:"maintenanceModes,omitempty"`
+
+	// Operator is the health last reported by the dr-cluster operator on the managed cluster
+	Operator *DRClusterOperatorReport `json:"operator,omitempty"`
+
+	// OperatorUpgrade is the progress of the rollout of the dr-cluster operator to a version
+	OperatorUpgrade *DRClusterOperatorUpgradeStatus `json:"operatorUpgrade,omitempty"`
+
+	// ObjectsRollout is the progress of the rollout of the objects deployed to the cluster, such as the dr-cluster
+	// operator configuration and RBAC, to their revision
+	ObjectsRollout *DRClusterObjectsRolloutStatus `json:"objectsRollout,omitempty"`
+
+	// Region is the region of the managed cluster, as set in the spec, or as claimed by the managed cluster otherwise
+	Region Region `json:"region,omitempty"`
+
+	// Zone is the zone of the managed cluster, as set in the spec, or as claimed by the managed cluster otherwise
+	Zone string `json:"zone,omitempty"`
+}
+
+// DRClusterOperatorUpgradePhase is the phase of the upgrade of a dr-cluster operator
+type DRClusterOperatorUpgradePhase string
+
+const (
+	// The upgrade waits for the canary clusters to be upgraded
+	DRClusterOperatorUpgradePending = DRClusterOperatorUpgradePhase("Pending")
+
+	// The operator subscription is upgraded, and the operator is yet to report the version and to report healthy
+	DRClusterOperatorUpgradeUpgrading = DRClusterOperatorUpgradePhase("Upgrading")
+
+	// The operator reports the version and reports healthy
+	DRClusterOperatorUpgradeUpgraded = DRClusterOperatorUpgradePhase("Upgraded")
+
+	// The operator did not report the version or healthy in time, and its subscription manifest is reverted, with
+	// manual install plan approval. OLM does not downgrade an installed operator, so the operator keeps the upgraded
+	// cluster service version until it is removed from the cluster.
+	DRClusterOperatorUpgradeRolledBack = DRClusterOperatorUpgradePhase("RolledBack")
+)
+
+// DRClusterOperatorUpgradeStatus is the progress of the upgrade of a dr-cluster operator
+type DRClusterOperatorUpgradeStatus struct {
+	// Version the operator is upgraded to
+	Version string `json:"version"`
+
+	Phase DRClusterOperatorUpgradePhase `json:"phase"`
+
+	// PreviousChannelName is the channel of the subscription before the upgrade, to roll back to
+	PreviousChannelName string `json:"previousChannelName,omitempty"`
+
+	// PreviousClusterServiceVersionName is the starting cluster service version of the subscription before the
+	// upgrade, to roll back to
+	PreviousClusterServiceVersionName string `json:"previousClusterServiceVersionName,omitempty"`
+
+	// StartTime is when the operator subscription was upgraded
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+// DRClusterObjectsRolloutPhase is the phase of the rollout of the objects deployed to a cluster
+type DRClusterObjectsRolloutPhase string
+
+const (
+	// The rollout waits for the clusters before this one to apply the revision, and the cluster keeps the objects
+	// of the previous revision
+	DRClusterObjectsRolloutPending = DRClusterObjectsRolloutPhase("Pending")
+
+	// The objects of the revision are deployed, and are yet to be applied, with the operator reporting healthy
+	DRClusterObjectsRolloutApplying = DRClusterObjectsRolloutPhase("Applying")
+
+	// The objects of the revision are applied, and the operator reports healthy
+	DRClusterObjectsRolloutApplied = DRClusterObjectsRolloutPhase("Applied")
+
+	// The objects of the revision were not applied, or the operator did not report healthy, in time, and the rollout
+	// of the revision to the clusters after this one is halted
+	DRClusterObjectsRolloutHalted = DRClusterObjectsRolloutPhase("Halted")
+)
+
+// DRClusterObjectsRolloutStatus is the progress of the rollout of the objects deployed to a cluster
+type DRClusterObjectsRolloutStatus struct {
+	// Revision is a hash of the objects rolled out
+	Revision string `json:"revision"`
+
+	Phase DRClusterObjectsRolloutPhase `json:"phase"`
+
+	// StartTime is when the objects of the revision were deployed
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
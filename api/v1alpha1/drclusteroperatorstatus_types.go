@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DRClusterOperatorStatusName is the name of the DRClusterOperatorStatus that the dr-cluster operator reports to
+const DRClusterOperatorStatusName = "ramen-dr-cluster-operator"
+
+// DRClusterOperatorStatus condition types, each reporting the health of a component the dr-cluster operator
+// depends on
+const (
+	// Admission webhooks that fail closed have ready endpoints
+	DRClusterOperatorConditionWebhooksHealthy = "WebhooksHealthy"
+
+	// Velero, which captures and recovers kube objects, is available
+	DRClusterOperatorConditionVeleroAvailable = "VeleroAvailable"
+
+	// The storage mirror daemons, which replicate volume data, are ready
+	DRClusterOperatorConditionMirrorDaemonsHealthy = "MirrorDaemonsHealthy"
+)
+
+// DRClusterOperatorReport is the health that the dr-cluster operator reports of itself and of the components it
+// depends on
+type DRClusterOperatorReport struct {
+	// Version of the dr-cluster operator
+	Version string `json:"version,omitempty"`
+
+	// Features enabled in the dr-cluster operator configuration
+	Features []string `json:"features,omitempty"`
+
+	// VRGSpecFields are the json paths of the VolumeReplicationGroup spec fields the dr-cluster operator parses,
+	// including nested ones, like kubeObjectProtection.captureInterval, for the hub to not send those it does not
+	VRGSpecFields []string `json:"vrgSpecFields,omitempty"`
+
+	// LastReportTime is when the dr-cluster operator last reported
+	LastReportTime *metav1.Time `json:"lastReportTime,omitempty"`
+
+	// Conditions report the health of the components the dr-cluster operator depends on
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// VeleroMissing is set if kube object protection is enabled and the Velero deployment is not found, for the hub
+	// to deploy Velero if configured to
+	VeleroMissing bool `json:"veleroMissing,omitempty"`
+
+	// StorageCapacities are the capacities available to provision volumes of the storage classes whose CSI drivers
+	// report them
+	StorageCapacities []StorageClassCapacity `json:"storageCapacities,omitempty"`
+
+	// AvailableResources are the cpu and memory allocatable on the schedulable nodes, less the requests of the pods
+	// running on them
+	AvailableResources corev1.ResourceList `json:"availableResources,omitempty"`
+}
+
+// StorageClassCapacity is the capacity available to provision volumes of a storage class, as reported by its CSI
+// driver with CSIStorageCapacity objects
+type StorageClassCapacity struct {
+	StorageClassName string `json:"storageClassName"`
+
+	// Provisioner is the CSI driver of the storage class
+	//+optional
+	Provisioner string `json:"provisioner,omitempty"`
+
+	// StorageID is the value of the storage class label "ramendr.openshift.io/storageid", identifying its storage
+	// backend across clusters
+	//+optional
+	StorageID string `json:"storageID,omitempty"`
+
+	// Capacity is the total capacity of the topology segments of the storage class
+	Capacity resource.Quantity `json:"capacity"`
+
+	// LargestVolumeSize is the size of the largest volume that can be provisioned in a topology segment of the
+	// storage class
+	LargestVolumeSize resource.Quantity `json:"largestVolumeSize"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+
+// DRClusterOperatorStatus is where the dr-cluster operator periodically reports its own health, for the hub to roll
+// up into the DRCluster conditions
+type DRClusterOperatorStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status DRClusterOperatorReport `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DRClusterOperatorStatusList contains a list of DRClusterOperatorStatus
+type DRClusterOperatorStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DRClusterOperatorStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DRClusterOperatorStatus{}, &DRClusterOperatorStatusList{})
+}
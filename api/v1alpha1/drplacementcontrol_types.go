@@ -76,6 +76,30 @@ const (
 	// Protected condition provides the latest available observation regarding the protection status of the workload,
 	// on the cluster it is expected to be available on.
 	ConditionProtected = "Protected"
+
+	// StalePeerStatus condition, present only while true, reports that the status of the VRG on a DR cluster, as
+	// viewed by the hub, is missing or older than expected, so that the status of the workload on that cluster
+	// cannot be relied upon.
+	ConditionStalePeerStatus = "StalePeerStatus"
+
+	// DataSynced condition, reported only for async DR while the workload is deployed, failed over or relocated,
+	// provides the latest observation of how long ago the workload data last synced to the peer cluster, against
+	// thresholds of multiples of the DRPolicy scheduling interval.
+	ConditionDataSynced = "DataSynced"
+
+	// PeerCapacityAvailable condition, checked from when the workload is deployed until its replication is set up,
+	// provides the observation of whether the storage classes of the peer cluster have the capacity for the PVCs
+	// protected on the home cluster, as reported by the dr-cluster operator of the peer cluster.
+	ConditionPeerCapacityAvailable = "PeerCapacityAvailable"
+
+	// RolledBack condition, reported once an action exceeds its timeout and is rolled back, provides the step the
+	// action was at, for the generation of the DRPC its action was reverted at. It is removed once the DRPC is
+	// changed.
+	ConditionRolledBack = "RolledBack"
+
+	// Paused condition, reported while the DRPC is annotated to be paused, provides the observation that its
+	// reconcile, and so its action, is held until the annotation is removed.
+	ConditionPaused = "Paused"
 )
 
 const (
@@ -84,6 +108,12 @@ const (
 	ReasonSuccess     = "Success"
 	ReasonNotStarted  = "NotStarted"
 	ReasonPaused      = "Paused"
+	ReasonTimedOut    = "TimedOut"
+)
+
+const (
+	ReasonPeerStatusStale   = "PeerStatusStale"
+	ReasonPeerStatusMissing = "PeerStatusMissing"
 )
 
 const (
@@ -93,6 +123,37 @@ const (
 	ReasonProtected            = "Protected"
 )
 
+const (
+	ReasonCapacityAvailable    = "CapacityAvailable"
+	ReasonCapacityInsufficient = "CapacityInsufficient"
+	ReasonCapacityUnknown      = "CapacityUnknown"
+	ReasonCapacityPending      = "CapacityPending"
+)
+
+const (
+	ReasonSyncOnTime   = "SyncOnTime"
+	ReasonSyncLagging  = "SyncLagging"
+	ReasonSyncCritical = "SyncCritical"
+	ReasonSyncUnknown  = "SyncUnknown"
+)
+
+// Health is a summary of the state of a DR resource, meant for display by UIs
+type Health string
+
+const (
+	// HealthHealthy indicates that the resource requires no action
+	HealthHealthy = Health("Healthy")
+
+	// HealthProgressing indicates that the resource is converging to its desired state
+	HealthProgressing = Health("Progressing")
+
+	// HealthDegraded indicates that the resource requires user intervention
+	HealthDegraded = Health("Degraded")
+
+	// HealthUnknown indicates that the state of the resource could not be determined
+	HealthUnknown = Health("Unknown")
+)
+
 type ProgressionStatus string
 
 const (
@@ -118,6 +179,7 @@ const (
 	ProgressionDeleting                            = ProgressionStatus("Deleting")
 	ProgressionDeleted                             = ProgressionStatus("Deleted")
 	ProgressionActionPaused                        = ProgressionStatus("Paused")
+	ProgressionRolledBack                          = ProgressionStatus("RolledBack")
 )
 
 // DRPlacementControlSpec defines the desired state of DRPlacementControl
@@ -135,7 +197,9 @@ type DRPlacementControlSpec struct {
 	// +kubebuilder:validation:Optional
 	ProtectedNamespaces *[]string `json:"protectedNamespaces,omitempty"`
 
-	// DRPolicyRef is the reference to the DRPolicy participating in the DR replication for this DRPC
+	// DRPolicyRef is the reference to the DRPolicy participating in the DR replication for this DRPC.
+	// If its name is not set on creation, it is set to the default DRPolicy of the namespace or of its cluster sets,
+	// annotated with ramendr.openshift.io/default-drpolicy, by the DRPC defaulting webhook if it is enabled.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="drPolicyRef is immutable"
 	DRPolicyRef v1.ObjectReference `json:"drPolicyRef"`
@@ -147,6 +211,14 @@ type DRPlacementControlSpec struct {
 	// If not sepcified, then the DRPC will select the surviving cluster from the DRPolicy
 	FailoverCluster string `json:"failoverCluster,omitempty"`
 
+	// PrepareFailover when set, prepares the FailoverCluster for a failover ahead of time, without moving the
+	// workload, so that a failover later requested by setting Action to Failover has less to do. Preparing
+	// pre-creates the workload namespace on the FailoverCluster, stages the Secrets, ConfigMaps and ServiceAccounts
+	// of the latest kube objects capture there, without scaling up the workload, and pulls the images of the
+	// workload on its nodes. Preparedness is reported in status.failoverPreparation
+	// +optional
+	PrepareFailover bool `json:"prepareFailover,omitempty"`
+
 	// Label selector to identify all the PVCs that need DR protection.
 	// This selector is assumed to be the same for all subscriptions that
 	// need DR protection. It will be passed in to the VRG when it is created
@@ -159,6 +231,42 @@ type DRPlacementControlSpec struct {
 
 	// +optional
 	KubeObjectProtection *KubeObjectProtectionSpec `json:"kubeObjectProtection,omitempty"`
+
+	// VRGMetadata is a set of labels and annotations to stamp onto the VRG and its ManifestWork
+	// +optional
+	VRGMetadata *VRGMetadata `json:"vrgMetadata,omitempty"`
+
+	// HelperPodScheduling contains scheduling hints for any helper pods Ramen creates on the managed clusters,
+	// the recipe hook jobs and the VolSync mover pods, it is passed in to the VRG when it is created
+	// +optional
+	HelperPodScheduling *HelperPodSchedulingSpec `json:"helperPodScheduling,omitempty"`
+
+	// VolSyncMoverConfig overrides the VolSync mover tunables of the DRPolicy
+	// +optional
+	VolSyncMoverConfig *VolSyncMoverConfig `json:"volSyncMoverConfig,omitempty"`
+
+	// ActionTimeouts are how long the DR actions of the DRPC may take before they are rolled back
+	// +optional
+	ActionTimeouts *DRActionTimeouts `json:"actionTimeouts,omitempty"`
+}
+
+// DRActionTimeouts are how long each DR action of a DRPC may take before it is rolled back
+type DRActionTimeouts struct {
+	// Relocate is how long a relocation may take, from its start, before it is rolled back to the cluster it is
+	// from, and the action of the DRPC reverted to the failover or deployment to it. A relocation is not rolled back
+	// once the VRG of the cluster it is from is moved to secondary, its point of no return.
+	// +optional
+	Relocate *metav1.Duration `json:"relocate,omitempty"`
+}
+
+// VRGMetadata defines user supplied metadata for the VRG and its ManifestWork. Labels and annotations
+// that are reserved by Ramen are not overridden.
+type VRGMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // PlacementDecision defines the decision made by controller
@@ -203,6 +311,29 @@ type VRGConditions struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// DRPCClusterStatus is the status of a DRPC's VRG on one of its DR clusters
+type DRPCClusterStatus struct {
+	// name is the name of the DR cluster
+	Name string `json:"name"`
+
+	// desiredRole is the replication state the DRPC requests of the VRG on the cluster, unset if it deploys none
+	//+optional
+	DesiredRole ReplicationState `json:"desiredRole,omitempty"`
+
+	// reportedRole is the state the VRG on the cluster reports, unset if no VRG is reported
+	//+optional
+	ReportedRole State `json:"reportedRole,omitempty"`
+
+	// conditions are the conditions the VRG on the cluster reports
+	//+optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// lastHeard is when the status of the VRG was last heard from the cluster, refreshed at most once a minute,
+	// unset if it never was
+	//+optional
+	LastHeard *metav1.Time `json:"lastHeard,omitempty"`
+}
+
 // DRPlacementControlStatus defines the observed state of DRPlacementControl
 type DRPlacementControlStatus struct {
 	Phase              DRState            `json:"phase,omitempty"`
@@ -214,6 +345,11 @@ type DRPlacementControlStatus struct {
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ResourceConditions VRGConditions      `json:"resourceConditions,omitempty"`
 
+	// clusters are the statuses of the VRGs on each of the DR clusters, for the cluster whose VRG is unhealthy to be
+	// told apart from its peer. resourceConditions are those of the VRG of the cluster the workload is placed on.
+	//+optional
+	Clusters []DRPCClusterStatus `json:"clusters,omitempty"`
+
 	// LastUpdateTime is when was the last time a condition or the overall status was updated
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 
@@ -234,6 +370,215 @@ type DRPlacementControlStatus struct {
 	// lastKubeObjectProtectionTime is the time of the most recent successful kube object protection
 	//+optional
 	LastKubeObjectProtectionTime *metav1.Time `json:"lastKubeObjectProtectionTime,omitempty"`
+
+	// failoverPreparation is the state of the failover preparation requested by spec.prepareFailover
+	//+optional
+	FailoverPreparation *FailoverPreparationStatus `json:"failoverPreparation,omitempty"`
+
+	// workloadRequests are the resource requests of the workload last reported by its primary VRG, checked
+	// against the available resources of a failover cluster
+	//+optional
+	WorkloadRequests v1.ResourceList `json:"workloadRequests,omitempty"`
+
+	// readiness is the result of the most recent DR readiness check, requested by setting the
+	// drplacementcontrol.ramendr.openshift.io/readiness-check annotation to a new value
+	//+optional
+	Readiness *ReadinessStatus `json:"readiness,omitempty"`
+
+	// failoverPlan is the most recent failover plan, requested by setting the
+	// drplacementcontrol.ramendr.openshift.io/failover-plan annotation to a new value
+	//+optional
+	FailoverPlan *FailoverPlan `json:"failoverPlan,omitempty"`
+
+	// stateGenerations is the most recent listing of the protected state stored for the DRPC, requested by setting
+	// the drplacementcontrol.ramendr.openshift.io/state-generations annotation to a new value
+	//+optional
+	StateGenerations *StateGenerationsListing `json:"stateGenerations,omitempty"`
+
+	// restorePreview is the most recent preview of the recovery of the DRPC's kube objects on its failover
+	// cluster, requested by setting the drplacementcontrol.ramendr.openshift.io/restore-preview annotation to a
+	// new value
+	//+optional
+	RestorePreview *RestorePreview `json:"restorePreview,omitempty"`
+
+	// runbook is the most recent export of the failover runbook of the DRPC to the s3 stores of its DRClusters
+	//+optional
+	Runbook *RunbookStatus `json:"runbook,omitempty"`
+
+	// health is a summary of the DRPC conditions and progression
+	//+optional
+	Health Health `json:"health,omitempty"`
+
+	// nextStep is a human readable description of what is expected next, from the user or the reconciler
+	//+optional
+	NextStep string `json:"nextStep,omitempty"`
+}
+
+// FailoverPreparationStatus defines the observed state of a failover preparation
+type FailoverPreparationStatus struct {
+	// cluster is the failover cluster being prepared
+	Cluster string `json:"cluster"`
+
+	// prepared is true once the cluster is ready to take over the workload
+	Prepared bool `json:"prepared"`
+
+	// message describes the preparation step in progress, or the reason preparation failed
+	//+optional
+	Message string `json:"message,omitempty"`
+
+	// lastTransitionTime is the time prepared last changed
+	//+optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ReadinessStatus is the result of a check that everything needed to fail over right now is in place
+type ReadinessStatus struct {
+	// request is the readiness check annotation value that the check ran for
+	Request string `json:"request"`
+
+	// cluster is the failover target that was checked
+	//+optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// ready is true if all the checks passed
+	Ready bool `json:"ready"`
+
+	// checkTime is the time the check ran
+	//+optional
+	CheckTime *metav1.Time `json:"checkTime,omitempty"`
+
+	// checks is the pass or fail breakdown of the check
+	//+optional
+	Checks []ReadinessCheck `json:"checks,omitempty"`
+}
+
+// ReadinessCheck is the result of one of the checks of a readiness check
+type ReadinessCheck struct {
+	// name of the check
+	Name string `json:"name"`
+
+	// passed is true if the check passed
+	Passed bool `json:"passed"`
+
+	// message describes the check result
+	//+optional
+	Message string `json:"message,omitempty"`
+}
+
+// FailoverPlan is what a failover would do, as of when it was planned, without doing any of it
+type FailoverPlan struct {
+	// request is the failover plan annotation value that the plan was made for
+	Request string `json:"request"`
+
+	// cluster is the failover target that was planned for
+	//+optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// homeCluster is the cluster the workload is placed on
+	//+optional
+	HomeCluster string `json:"homeCluster,omitempty"`
+
+	// planTime is the time the plan was made
+	//+optional
+	PlanTime *metav1.Time `json:"planTime,omitempty"`
+
+	// steps are the steps of the failover, in order
+	//+optional
+	Steps []FailoverPlanStep `json:"steps,omitempty"`
+
+	// estimatedDataBytes is the storage requested by the protected PVCs, which bounds the data to replicate to the
+	// home cluster once it is recovered
+	//+optional
+	EstimatedDataBytes *int64 `json:"estimatedDataBytes,omitempty"`
+
+	// blockers are the reasons the failover would not start or not complete now
+	//+optional
+	Blockers []string `json:"blockers,omitempty"`
+}
+
+// FailoverPlanStep is one of the steps of a failover plan
+type FailoverPlanStep struct {
+	// cluster is the cluster the step acts on, or empty for the hub
+	//+optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// description of what the step does
+	Description string `json:"description"`
+
+	// resources are the resources the step creates or modifies, as kind namespace/name
+	//+optional
+	Resources []string `json:"resources,omitempty"`
+}
+
+// RestorePreview is the preview of the recovery of a DRPC's kube objects on a cluster, as reported by its VRG there
+type RestorePreview struct {
+	// cluster is the cluster the recovery was previewed on
+	//+optional
+	Cluster string `json:"cluster,omitempty"`
+
+	KubeObjectsRestorePreview `json:",inline"`
+}
+
+// RunbookStatus is the export of the failover runbook of a DRPC, the steps to fail its workload over by hand, to the
+// s3 stores of its DRClusters
+type RunbookStatus struct {
+	// hash of the content of the runbook exported, which is exported again when it changes
+	Hash string `json:"hash"`
+
+	// exportTime is the time the runbook was exported
+	//+optional
+	ExportTime *metav1.Time `json:"exportTime,omitempty"`
+
+	// s3ProfileNames are the profiles of the s3 stores the runbook was exported to
+	//+optional
+	S3ProfileNames []string `json:"s3ProfileNames,omitempty"`
+
+	// errors are the failures to export the runbook to s3 stores
+	//+optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// StateGenerationsListing lists the generations of the protected state of a DRPC stored in the s3 stores of its
+// DRClusters
+type StateGenerationsListing struct {
+	// request is the state generations annotation value that the listing was made for
+	Request string `json:"request"`
+
+	// listTime is the time the s3 stores were listed
+	//+optional
+	ListTime *metav1.Time `json:"listTime,omitempty"`
+
+	// generations are the generations of state stored, in order of s3 profile, kind and name
+	//+optional
+	Generations []StateGeneration `json:"generations,omitempty"`
+
+	// errors are the failures to list s3 stores, whose generations are missing from the listing
+	//+optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// StateGeneration is a generation of the protected state of a DRPC stored in an s3 store
+type StateGeneration struct {
+	// s3ProfileName is the s3 profile of the store the state is stored in
+	S3ProfileName string `json:"s3ProfileName"`
+
+	// kind of the state: KubeObjectsCapture for a capture of kube objects, or the kind of the objects stored, such
+	// as VolumeReplicationGroup, PersistentVolume or PersistentVolumeClaim
+	Kind string `json:"kind"`
+
+	// name of the generation: the number of a kube objects capture, empty for other kinds
+	//+optional
+	Name string `json:"name,omitempty"`
+
+	// objects is the count of the s3 objects the generation is stored in
+	Objects int `json:"objects"`
+
+	// sizeBytes is the total size of the s3 objects the generation is stored in
+	SizeBytes int64 `json:"sizeBytes"`
+
+	// lastModified is the latest modification time of the s3 objects the generation is stored in
+	//+optional
+	LastModified *metav1.Time `json:"lastModified,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -247,6 +592,7 @@ type DRPlacementControlStatus struct {
 // +kubebuilder:printcolumn:JSONPath=".status.actionStartTime",name=start time,type=string,priority=2
 // +kubebuilder:printcolumn:JSONPath=".status.actionDuration",name=duration,type=string,priority=2
 // +kubebuilder:printcolumn:JSONPath=".status.conditions[1].status",name=peer ready,type=string,priority=2
+// +kubebuilder:printcolumn:JSONPath=".status.health",name=health,type=string,priority=2
 // +kubebuilder:resource:shortName=drpc
 
 // DRPlacementControl is the Schema for the drplacementcontrols API
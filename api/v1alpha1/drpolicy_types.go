@@ -43,15 +43,63 @@ type DRPolicySpec struct {
 	// +kubebuilder:validation:XValidation:rule="size(self) == 2", message="drClusters requires a list of 2 clusters"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="drClusters is immutable"
 	DRClusters []string `json:"drClusters"`
+
+	// VolSync mover tunables for workloads protected by this policy, a DRPlacementControl may override these.
+	// It will be passed in to the VRG when it is created
+	//+optional
+	VolSyncMoverConfig *VolSyncMoverConfig `json:"volSyncMoverConfig,omitempty"`
+
+	// ActionPermissions are the users and groups that may request each DR action of the DRPCs of the DRPolicy,
+	// enforced by the DRPC action permissions webhook of the hub operator regardless of who else may edit the DRPCs
+	// +optional
+	ActionPermissions *DRActionPermissions `json:"actionPermissions,omitempty"`
+}
+
+// DRActionPermissions are the actors that may request each DR action of the DRPCs of a DRPolicy. An action whose
+// actors are not set may be requested by anyone that may edit the DRPCs.
+type DRActionPermissions struct {
+	// Failover are the actors that may fail a DRPC over, retarget its failover, or undo it
+	// +optional
+	Failover *DRActors `json:"failover,omitempty"`
+
+	// Relocate are the actors that may relocate a DRPC, retarget its relocation, or undo it
+	// +optional
+	Relocate *DRActors `json:"relocate,omitempty"`
+
+	// PrepareFailover are the actors that may prepare the failover cluster of a DRPC, as failover drills do
+	// +optional
+	PrepareFailover *DRActors `json:"prepareFailover,omitempty"`
+}
+
+// DRActors are users and groups, as the API server authenticates them
+type DRActors struct {
+	// Users are the names of the users
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// Groups are the names of the groups
+	// +optional
+	Groups []string `json:"groups,omitempty"`
 }
 
 // DRPolicyStatus defines the observed state of DRPolicy
 type DRPolicyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// health is a summary of the DRPolicy conditions
+	//+optional
+	Health Health `json:"health,omitempty"`
+
+	// nextStep is a human readable description of what is expected next, from the user or the reconciler
+	//+optional
+	NextStep string `json:"nextStep,omitempty"`
 }
 
 const (
 	DRPolicyValidated string = `Validated`
+
+	// DRPolicyFailureDomainsDistinct is whether the clusters of the DRPolicy are in distinct failure domains
+	DRPolicyFailureDomainsDistinct string = `FailureDomainsDistinct`
 )
 
 // +kubebuilder:object:root=true
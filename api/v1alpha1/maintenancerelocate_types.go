@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DRPCReference is a reference to a DRPlacementControl
+type DRPCReference struct {
+	// Namespace of the DRPlacementControl
+	Namespace string `json:"namespace"`
+
+	// Name of the DRPlacementControl
+	Name string `json:"name"`
+}
+
+// MaintenanceRelocateSpec defines the DRPCs to relocate off a cluster for a maintenance window
+// +kubebuilder:validation:XValidation:rule="timestamp(self.windowEnd) > timestamp(self.windowStart)", message="windowEnd must be after windowStart"
+type MaintenanceRelocateSpec struct {
+	// Cluster is the name of the DRCluster under maintenance, that the DRPCs are relocated off
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf", message="cluster is immutable"
+	Cluster string `json:"cluster"`
+
+	// DRPCs are the DRPlacementControls to relocate off the cluster. Those not running on the cluster when the
+	// relocations are planned are skipped.
+	DRPCs []DRPCReference `json:"drpcs"`
+
+	// WindowStart is when the maintenance starts, by which the DRPCs are to be relocated off the cluster
+	WindowStart metav1.Time `json:"windowStart"`
+
+	// WindowEnd is when the maintenance ends, after which the DRPCs are relocated back to the cluster
+	WindowEnd metav1.Time `json:"windowEnd"`
+
+	// LeadTime is how long before the window starts the DRPCs start being relocated off the cluster, one hour if
+	// not set
+	// +optional
+	LeadTime *metav1.Duration `json:"leadTime,omitempty"`
+
+	// DryRun plans the relocations and reports them in the status, without relocating any DRPC
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// MaintenanceRelocatePhase is the phase of a maintenance relocate
+// +kubebuilder:validation:Enum=Planned;Pending;Evacuating;Holding;Returning;Completed
+type MaintenanceRelocatePhase string
+
+// Valid values for MaintenanceRelocatePhase
+const (
+	// The relocations are planned, and not carried out as dry run is set
+	MaintenanceRelocatePlanned = MaintenanceRelocatePhase("Planned")
+
+	// The relocations are planned, and wait on the lead time before the window
+	MaintenanceRelocatePending = MaintenanceRelocatePhase("Pending")
+
+	// The DRPCs are being relocated off the cluster
+	MaintenanceRelocateEvacuating = MaintenanceRelocatePhase("Evacuating")
+
+	// The DRPCs are held off the cluster until the window ends
+	MaintenanceRelocateHolding = MaintenanceRelocatePhase("Holding")
+
+	// The DRPCs are being relocated back to the cluster
+	MaintenanceRelocateReturning = MaintenanceRelocatePhase("Returning")
+
+	// The DRPCs are relocated back to the cluster, or were skipped or failed
+	MaintenanceRelocateCompleted = MaintenanceRelocatePhase("Completed")
+)
+
+// MaintenanceRelocateDRPCState is the state of the relocations of a DRPC
+// +kubebuilder:validation:Enum=Pending;Relocating;Relocated;Returning;Returned;Skipped;Failed
+type MaintenanceRelocateDRPCState string
+
+// Valid values for MaintenanceRelocateDRPCState
+const (
+	MaintenanceRelocateDRPCPending    = MaintenanceRelocateDRPCState("Pending")
+	MaintenanceRelocateDRPCRelocating = MaintenanceRelocateDRPCState("Relocating")
+	MaintenanceRelocateDRPCRelocated  = MaintenanceRelocateDRPCState("Relocated")
+	MaintenanceRelocateDRPCReturning  = MaintenanceRelocateDRPCState("Returning")
+	MaintenanceRelocateDRPCReturned   = MaintenanceRelocateDRPCState("Returned")
+	MaintenanceRelocateDRPCSkipped    = MaintenanceRelocateDRPCState("Skipped")
+	MaintenanceRelocateDRPCFailed     = MaintenanceRelocateDRPCState("Failed")
+)
+
+// MaintenanceRelocateDRPCStatus is the plan and the state of the relocations of a DRPC
+type MaintenanceRelocateDRPCStatus struct {
+	DRPCReference `json:",inline"`
+
+	// TargetCluster is the peer cluster of the DRPolicy of the DRPC, that the DRPC is relocated to for the window
+	// +optional
+	TargetCluster string `json:"targetCluster,omitempty"`
+
+	State MaintenanceRelocateDRPCState `json:"state"`
+
+	// Message is why the DRPC was skipped or failed, or what its relocation waits on
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// RelocatedTime is when the DRPC completed its relocation off the cluster
+	// +optional
+	RelocatedTime *metav1.Time `json:"relocatedTime,omitempty"`
+
+	// ReturnedTime is when the DRPC completed its relocation back to the cluster
+	// +optional
+	ReturnedTime *metav1.Time `json:"returnedTime,omitempty"`
+}
+
+// MaintenanceRelocateSummary counts the DRPCs of a maintenance relocate by their state
+type MaintenanceRelocateSummary struct {
+	Pending    int `json:"pending,omitempty"`
+	Relocating int `json:"relocating,omitempty"`
+	Relocated  int `json:"relocated,omitempty"`
+	Returning  int `json:"returning,omitempty"`
+	Returned   int `json:"returned,omitempty"`
+	Skipped    int `json:"skipped,omitempty"`
+	Failed     int `json:"failed,omitempty"`
+}
+
+// MaintenanceRelocateStatus is the consolidated report of the relocations of a maintenance relocate
+type MaintenanceRelocateStatus struct {
+	Phase              MaintenanceRelocatePhase `json:"phase,omitempty"`
+	ObservedGeneration int64                    `json:"observedGeneration,omitempty"`
+
+	// DRPCs are the plan and the state of the relocations of each DRPC
+	// +optional
+	DRPCs []MaintenanceRelocateDRPCStatus `json:"drpcs,omitempty"`
+
+	// Summary counts the DRPCs by their state
+	// +optional
+	Summary MaintenanceRelocateSummary `json:"summary,omitempty"`
+
+	// LastUpdateTime is when the status was last updated
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:JSONPath=".spec.cluster",name=cluster,type=string
+//+kubebuilder:printcolumn:JSONPath=".spec.windowStart",name=windowstart,type=string
+//+kubebuilder:printcolumn:JSONPath=".spec.windowEnd",name=windowend,type=string
+//+kubebuilder:printcolumn:JSONPath=".status.phase",name=phase,type=string
+
+// MaintenanceRelocate relocates a set of DRPCs off a cluster before a planned maintenance window, holds them off
+// the cluster during the window, and relocates them back after it
+type MaintenanceRelocate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaintenanceRelocateSpec   `json:"spec,omitempty"`
+	Status MaintenanceRelocateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MaintenanceRelocateList contains a list of MaintenanceRelocate
+type MaintenanceRelocateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaintenanceRelocate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaintenanceRelocate{}, &MaintenanceRelocateList{})
+}
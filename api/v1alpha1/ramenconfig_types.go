@@ -74,8 +74,64 @@ type S3StoreProfile struct {
 	// A CA bundle to use when verifying TLS connections to the provider
 	//+optional
 	CACertificates []byte `json:"caCertificates,omitempty"`
+	// OIDC, if set, has the credentials of this S3 profile be short-lived ones, that the hub operator obtains
+	// from the security token service of the object store for a token of an OIDC provider, and refreshes in the
+	// secret before they expire. The secret then has the client id and secret of the OIDC client with the keys
+	// OIDC_CLIENT_ID and OIDC_CLIENT_SECRET.
+	//+optional
+	OIDC *S3OIDCProfile `json:"oidc,omitempty"`
+}
+
+// S3OIDCProfile is how the short-lived credentials of an S3 profile are obtained, such as those of a Ceph RGW whose
+// security token service trusts a Keycloak realm
+type S3OIDCProfile struct {
+	// Token endpoint of the OIDC provider, that a token is requested of with the client credentials grant
+	TokenEndpoint string `json:"tokenEndpoint"`
+
+	// Scope of the token requested
+	//+optional
+	Scope string `json:"scope,omitempty"`
+
+	// ARN of the role to assume with the token
+	RoleARN string `json:"roleARN"`
+
+	// Endpoint of the security token service, the S3 compatible endpoint if not set
+	//+optional
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
+
+	// Duration of the credentials. Defaults to 1 hour.
+	//+optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// How long before they expire the credentials are refreshed. Defaults to 10 minutes.
+	//+optional
+	RefreshBefore *metav1.Duration `json:"refreshBefore,omitempty"`
 }
 
+// FailoverCapacityCheckMode is how a failover cluster that cannot host the workload is handled
+type FailoverCapacityCheckMode string
+
+const (
+	FailoverCapacityCheckWarn   FailoverCapacityCheckMode = "Warn"
+	FailoverCapacityCheckRefuse FailoverCapacityCheckMode = "Refuse"
+)
+
+// ProtectionCapacityCheckMode is how a peer cluster that lacks the storage capacity for the protected PVCs is handled
+type ProtectionCapacityCheckMode string
+
+const (
+	ProtectionCapacityCheckWarn   ProtectionCapacityCheckMode = "Warn"
+	ProtectionCapacityCheckRefuse ProtectionCapacityCheckMode = "Refuse"
+)
+
+// FailureDomainCheckMode is how a DRPolicy whose clusters share a failure domain is handled
+type FailureDomainCheckMode string
+
+const (
+	FailureDomainCheckWarn   FailureDomainCheckMode = "Warn"
+	FailureDomainCheckRefuse FailureDomainCheckMode = "Refuse"
+)
+
 //+kubebuilder:object:root=true
 
 // RamenConfig is the Schema for the ramenconfig API
@@ -103,6 +159,17 @@ type RamenConfig struct {
 		// Enable s3 secret distribution and management across dr-clusters
 		S3SecretDistributionEnabled bool `json:"s3SecretDistributionEnabled,omitempty"`
 
+		// S3SecretNameTemplate is a Go template of the name of the s3 secrets distributed to the dr-clusters,
+		// executed with .SecretName, the name of the s3 secret on the hub, .S3ProfileName, the name of the s3
+		// profile, and .HubNamespace, the namespace of the hub operator. The s3 profiles sharing a secret must
+		// resolve to the same name. Defaults to the name of the s3 secret on the hub.
+		S3SecretNameTemplate string `json:"s3SecretNameTemplate,omitempty"`
+
+		// S3SecretNamespaceTemplate is a Go template of the namespace of the s3 secrets distributed to the
+		// dr-clusters, executed with the same values as S3SecretNameTemplate. Defaults to the dr-cluster operator
+		// namespace.
+		S3SecretNamespaceTemplate string `json:"s3SecretNamespaceTemplate,omitempty"`
+
 		// channel name
 		ChannelName string `json:"channelName,omitempty"`
 
@@ -120,6 +187,9 @@ type RamenConfig struct {
 
 		// cluster service version name
 		ClusterServiceVersionName string `json:"clusterServiceVersionName,omitempty"`
+
+		// Image of the dr-cluster operator deployed to the DRClusters whose operatorDeploymentMode is Manifests
+		Image string `json:"image,omitempty"`
 	} `json:"drClusterOperator,omitempty"`
 
 	// VolSync configuration
@@ -143,8 +213,77 @@ type RamenConfig struct {
 		Disabled bool `json:"disabled,omitempty"`
 		// Velero namespace input
 		VeleroNamespaceName string `json:"veleroNamespaceName,omitempty"`
+		// IdentitiesRecoverFirst has the default recover workflow recover the service accounts, roles, role bindings
+		// and secrets of the protected namespaces first, in a restore of their own, and the other objects after them
+		IdentitiesRecoverFirst bool `json:"identitiesRecoverFirst,omitempty"`
+		// HookJobServiceAccountName is the name of the service account that recipe hooks of type job run as. The
+		// administrator creates it, with the permissions hooks may use, in each namespace hooks may run jobs in.
+		// Hooks of type job fail if it is not set, or in namespaces without it.
+		HookJobServiceAccountName string `json:"hookJobServiceAccountName,omitempty"`
+		// HookCallHosts are the hosts, as host or host:port separated by commas, that recipe hooks of type http may
+		// call. Hooks of type http fail if the host of their url is not one of them.
+		HookCallHosts string `json:"hookCallHosts,omitempty"`
+		// OperatorsRecoverFirst has the default recover workflow recover the operators subscribed to in the protected
+		// namespaces first, and their custom resources once they are ready, instead of all together
+		OperatorsRecoverFirst bool `json:"operatorsRecoverFirst,omitempty"`
+		// OperatorsReadyTimeoutSeconds is how long each step of waiting for recovered operators to be ready, before
+		// their custom resources are recovered, waits before the recovery continues regardless. Defaults to 600.
+		OperatorsReadyTimeoutSeconds int64 `json:"operatorsReadyTimeoutSeconds,omitempty"`
+		// RecipeValidationWebhookEnabled serves a validating webhook that rejects recipes the VRGs referring to them
+		// would fail to protect or recover with. Requires the webhook configuration and its serving certificate.
+		RecipeValidationWebhookEnabled bool `json:"recipeValidationWebhookEnabled,omitempty"`
+		// APIVersionPriorities are the versions, in order of priority, that Velero restores the objects of resources
+		// at, as lines of <resource>.<group>=<version>,<version>, for clusters that do not serve the versions the
+		// objects were captured at. They are written, with those of kinds known to be compatible across versions,
+		// to Velero's enableapigroupversions config map. Requires Velero's EnableAPIGroupVersions feature.
+		APIVersionPriorities string `json:"apiVersionPriorities,omitempty"`
+		// QuotaAdjustEnabled allows the VRGs whose quota policy is Adjust to raise the hard limits of the resource
+		// quotas that block their recovery. The quota policy is set by the owners of the VRGs, whose own quotas are
+		// raised, so otherwise it is handled as Create, reporting the quotas that block the recovery.
+		QuotaAdjustEnabled bool `json:"quotaAdjustEnabled,omitempty"`
+		// DifferentialCapture captures only the groups whose objects changed since the previous capture, and
+		// refers to the previous captures of the others, rather than capturing every group every interval
+		DifferentialCapture struct {
+			// Enabled is used to enable differential captures. Defaults to false.
+			Enabled bool `json:"enabled,omitempty"`
+			// FullCaptureInterval is every how many captures every group is captured regardless, so that the
+			// captures referred to do not grow old. Defaults to 12.
+			FullCaptureInterval int64 `json:"fullCaptureInterval,omitempty"`
+		} `json:"differentialCapture,omitempty"`
+		// VeleroDeployment deploys Velero, with the OADP operator, to the DRClusters whose dr-cluster operator
+		// reports it missing, along with the dr-cluster operator
+		VeleroDeployment struct {
+			// AutomationEnabled is used to enable Velero deployment. Requires DrClusterOperator
+			// DeploymentAutomationEnabled and S3SecretDistributionEnabled, and VeleroNamespaceName. Defaults to false.
+			AutomationEnabled bool `json:"automationEnabled,omitempty"`
+			// S3ProfileName is the name of the s3 profile of the default backup storage location of Velero,
+			// referencing the s3 secret distributed to the velero namespace. Defaults to the first s3 profile.
+			S3ProfileName string `json:"s3ProfileName,omitempty"`
+			// NodeAgentEnabled deploys the Velero node agent, for file system backups of volumes
+			NodeAgentEnabled bool `json:"nodeAgentEnabled,omitempty"`
+			// Velero image, deployed without OLM to the DRClusters whose operatorDeploymentMode is Manifests.
+			// Defaults to velero/velero:v1.13.2.
+			Image string `json:"image,omitempty"`
+			// Velero AWS plugin image, deployed along with the Velero image. Defaults to
+			// velero/velero-plugin-for-aws:v1.9.2.
+			AWSPluginImage string `json:"awsPluginImage,omitempty"`
+			// OADP operator channel name. Defaults to stable-1.3.
+			ChannelName string `json:"channelName,omitempty"`
+			// OADP operator package name. Defaults to redhat-oadp-operator.
+			PackageName string `json:"packageName,omitempty"`
+			// OADP operator catalog source name. Defaults to redhat-operators.
+			CatalogSourceName string `json:"catalogSourceName,omitempty"`
+			// OADP operator catalog source namespace name. Defaults to openshift-marketplace.
+			CatalogSourceNamespaceName string `json:"catalogSourceNamespaceName,omitempty"`
+		} `json:"veleroDeployment,omitempty"`
 	} `json:"kubeObjectProtection,omitempty"`
 
+	// VeleroItemActionConfigs are configurations of Velero item action plugins, each deployed as a ConfigMap to the
+	// Velero namespace of the dr-clusters. A plugin configuration applies to all the backups or restores of Velero
+	// on a cluster, so it is configured here rather than by a VolumeReplicationGroup, and a plugin may be
+	// configured once only.
+	VeleroItemActionConfigs []VeleroItemActionConfig `json:"veleroItemActionConfigs,omitempty"`
+
 	MultiNamespace struct {
 		// Enables feature to protect resources in namespaces other than VRG's
 		FeatureEnabled   bool `json:"FeatureEnabled,omitempty"`
@@ -156,6 +295,354 @@ type RamenConfig struct {
 
 	// RamenOpsNamespace is the namespace where resources for unmanaged apps are created
 	RamenOpsNamespace string `json:"ramenOpsNamespace,omitempty"`
+
+	// FinalizerTimeout configures how deletions escalate when dependent resources do not complete their cleanup,
+	// such as VRGs that are not deleted from a managed cluster, or Velero restores that do not complete
+	FinalizerTimeout struct {
+		// Seconds a deletion waits on dependent resources before escalating. Defaults to 0, to wait indefinitely.
+		Seconds int64 `json:"seconds,omitempty"`
+		// ForceCleanup, if true, escalates by completing the deletion without the dependent resources, leaving
+		// them behind to be cleaned up by hand. Otherwise the deletion continues to wait, and reports in its
+		// status that it timed out.
+		ForceCleanup bool `json:"forceCleanup,omitempty"`
+	} `json:"finalizerTimeout,omitempty"`
+
+	// FailoverCapacityCheck configures checking, before a failover, that the failover cluster has the available cpu
+	// and memory to host the workload, as last reported by its primary VRG, and the storage capacity for the PVCs
+	// the failover provisions
+	FailoverCapacityCheck struct {
+		// Mode is Warn to report a failover cluster that cannot host the workload with an event, or Refuse to also
+		// refuse the failover, unless the DRPC is annotated to skip the check. The check is disabled by default.
+		// +kubebuilder:validation:Enum=Warn;Refuse
+		Mode FailoverCapacityCheckMode `json:"mode,omitempty"`
+	} `json:"failoverCapacityCheck,omitempty"`
+
+	// ProtectionCapacityCheck configures checking that the storage classes of the peer cluster of a DRPC have the
+	// capacity for the PVCs protected on its home cluster, as reported by the dr-cluster operator of the peer cluster
+	ProtectionCapacityCheck struct {
+		// Mode is Warn to report a peer cluster that lacks the capacity with the PeerCapacityAvailable condition of
+		// the DRPC, or Refuse to also hold the replication of the PVCs of an async DRPC, by VolumeReplication or
+		// VolSync, during its initial deployment until the capacity is checked and while it is short, unless the
+		// DRPC is annotated to skip the capacity check. Defaults to Warn.
+		// +kubebuilder:validation:Enum=Warn;Refuse
+		Mode ProtectionCapacityCheckMode `json:"mode,omitempty"`
+	} `json:"protectionCapacityCheck,omitempty"`
+
+	// FailureDomainCheck configures checking that the clusters of a DRPolicy are in distinct failure domains, so
+	// that a disaster does not take down the clusters it is to recover between. Clusters in the same zone of a region
+	// share a failure domain.
+	FailureDomainCheck struct {
+		// Mode is Warn to report a DRPolicy whose clusters share a failure domain with its FailureDomainsDistinct
+		// condition, or Refuse to also fail its validation. Defaults to Warn.
+		// +kubebuilder:validation:Enum=Warn;Refuse
+		Mode FailureDomainCheckMode `json:"mode,omitempty"`
+	} `json:"failureDomainCheck,omitempty"`
+
+	// Standalone mode runs the dr-cluster operator without a hub. VolumeReplicationGroups are applied to the
+	// clusters directly, and peer clusters coordinate their replication state through the shared S3 stores
+	// instead of through ManifestWorks.
+	Standalone struct {
+		// Enabled is used to enable standalone mode. Defaults to false.
+		Enabled bool `json:"enabled,omitempty"`
+		// ClusterName identifies this cluster to its peers in the S3 stores. It must be unique among the peers.
+		ClusterName string `json:"clusterName,omitempty"`
+	} `json:"standalone,omitempty"`
+
+	// HealthReport configures the health the dr-cluster operator reports of itself in its DRClusterOperatorStatus
+	HealthReport struct {
+		// MirrorDaemonNamespaceName is the namespace of the storage mirror daemon pods, such as rbd-mirror
+		MirrorDaemonNamespaceName string `json:"mirrorDaemonNamespaceName,omitempty"`
+		// MirrorDaemonLabelSelector selects the storage mirror daemon pods, whose readiness is reported. Mirror
+		// daemon health is not reported if unset.
+		MirrorDaemonLabelSelector string `json:"mirrorDaemonLabelSelector,omitempty"`
+	} `json:"healthReport,omitempty"`
+
+	// DrClusterOperatorUpgrade, if set along with DrClusterOperator.DeploymentAutomationEnabled, rolls out the
+	// dr-cluster operators to a version, canary clusters first, and reverts the subscriptions of those that fail to
+	// report healthy. Only the subscription manifest is reverted: OLM does not downgrade the operator.
+	DrClusterOperatorUpgrade *DrClusterOperatorUpgrade `json:"drClusterOperatorUpgrade,omitempty"`
+
+	// DrClusterObjectsRollout, if set along with DrClusterOperator.DeploymentAutomationEnabled, rolls out changes of
+	// the objects deployed to the clusters, such as the dr-cluster operator configuration and RBAC, one cluster at a
+	// time, in the order of their names, and halts the rollout on the first cluster that fails to apply them
+	DrClusterObjectsRollout *DrClusterObjectsRollout `json:"drClusterObjectsRollout,omitempty"`
+
+	// DrClusterOperatorAddOn, if set along with DrClusterOperator.DeploymentAutomationEnabled, delivers the
+	// dr-cluster operator as an OCM add-on instead of with an OLM Subscription, for clusters without OLM
+	DrClusterOperatorAddOn *DrClusterOperatorAddOn `json:"drClusterOperatorAddOn,omitempty"`
+
+	// DrClusterOperatorResources, if set, are the compute resources of the dr-cluster operator deployed to the
+	// DRClusters whose operatorDeploymentMode is Manifests, in place of the defaults and those of their profile
+	DrClusterOperatorResources *v1.ResourceRequirements `json:"drClusterOperatorResources,omitempty"`
+
+	// ArrayReplicationPlugins are the plugins, served over gRPC by sidecars of the dr-cluster operator, of array
+	// based replication providers. Each is a sync replication provider, that storage classes select by name.
+	ArrayReplicationPlugins []ArrayReplicationPlugin `json:"arrayReplicationPlugins,omitempty"`
+
+	// Log configures the operator's logs, overriding its command line flags. Changes apply without a restart.
+	Log *LogConfig `json:"log,omitempty"`
+
+	// DRFreeze, if set, holds every failover and relocate a DRPC has not yet started until approvers sign it off,
+	// for change control
+	DRFreeze *DRFreeze `json:"drFreeze,omitempty"`
+
+	// DRPCActionPermissionsWebhookEnabled serves a validating webhook that rejects the DR actions of the DRPCs
+	// requested by users the actionPermissions of their DRPolicy do not permit. Requires the webhook configuration
+	// and its serving certificate. Changes apply with a restart.
+	DRPCActionPermissionsWebhookEnabled bool `json:"drpcActionPermissionsWebhookEnabled,omitempty"`
+
+	// DRPCDefaultDRPolicyWebhookEnabled serves a mutating webhook that sets the DRPolicy reference of the DRPCs
+	// created without one to the default DRPolicy of their namespace or cluster sets. Requires the webhook
+	// configuration and its serving certificate. Changes apply with a restart.
+	DRPCDefaultDRPolicyWebhookEnabled bool `json:"drpcDefaultDRPolicyWebhookEnabled,omitempty"`
+
+	// DRPCApprovalWebhookEnabled serves a validating webhook that rejects the approval annotations of the DRPCs set
+	// by users other than the approvers they are named for. A DR freeze requires it. Requires the webhook
+	// configuration and its serving certificate. Changes apply with a restart.
+	DRPCApprovalWebhookEnabled bool `json:"drpcApprovalWebhookEnabled,omitempty"`
+
+	// PolicyExemptionsAllowed are the admission policies the VRGs may exempt the protected namespaces from while
+	// they are recovered: Kyverno ClusterPolicies by name, and Gatekeeper constraints as kind/name. None are allowed
+	// by default.
+	PolicyExemptionsAllowed []string `json:"policyExemptionsAllowed,omitempty"`
+
+	// PolicyExemptionsTimeout is how long after a VRG applied its policy exemptions they are removed if the recovered
+	// workload is not ready by then. Defaults to 1 hour.
+	PolicyExemptionsTimeout *metav1.Duration `json:"policyExemptionsTimeout,omitempty"`
+
+	// Notifications, if set, sends messages on DR events, such as failovers and RPO violations, to sinks
+	Notifications *Notifications `json:"notifications,omitempty"`
+
+	// SyncThresholds are how many scheduling intervals of its DRPolicy the last group sync of an async DRPC may be
+	// older than before its DataSynced condition and sync status metric report it lagging, and critically so
+	SyncThresholds struct {
+		// WarningFactor is the multiple of the scheduling interval beyond which a sync is lagging. Defaults to 2.
+		WarningFactor int `json:"warningFactor,omitempty"`
+		// CriticalFactor is the multiple of the scheduling interval beyond which a sync is critically lagging.
+		// Defaults to 3.
+		CriticalFactor int `json:"criticalFactor,omitempty"`
+	} `json:"syncThresholds,omitempty"`
+
+	// Profiling, if set, serves the Go profiles of the operator on its metrics server, and captures its CPU and heap
+	// profiles to an S3 store when its reconciles are slow. Changes apply with a restart.
+	Profiling *Profiling `json:"profiling,omitempty"`
+
+	// LeaderElectionGroups split the controllers of the hub operator across its replicas. The controllers of a group
+	// run on the replica that holds the lease of the group, named after the lease of the operator suffixed with the
+	// name of the group, and the other controllers on the replica that holds the lease of the operator. Changes
+	// apply with a restart.
+	LeaderElectionGroups []LeaderElectionGroup `json:"leaderElectionGroups,omitempty"`
+
+	// OperatorProfile is the footprint the dr-cluster operator runs with, as the hub configures it from the
+	// operatorProfile of its DRCluster. Changes apply with a restart.
+	OperatorProfile OperatorProfile `json:"operatorProfile,omitempty"`
+
+	// UploadRedactions remove fields of the PVs and PVCs the VRGs upload to the S3 stores, and exclude kinds from
+	// their kube object captures, for data minimization. The fields of the kinds Velero captures cannot be removed,
+	// only their objects excluded. The redactions are recorded in a manifest uploaded with each VRG.
+	UploadRedactions []UploadRedaction `json:"uploadRedactions,omitempty"`
+}
+
+// UploadRedaction redacts the objects of a kind before they are uploaded
+type UploadRedaction struct {
+	// Group of the kind, empty for the core group
+	Group string `json:"group,omitempty"`
+
+	// Kind of the objects to redact
+	Kind string `json:"kind"`
+
+	// Fields are the JSONPaths of the fields to remove, such as .metadata.annotations['example.com/token'], of
+	// PersistentVolumes and PersistentVolumeClaims only. The objects of a kind with no fields are excluded from the
+	// kube object captures instead.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// LeaderElectionGroup is a group of controllers of the hub operator that run on the replica holding its lease
+type LeaderElectionGroup struct {
+	// Name of the group, a DNS label unique among the groups
+	Name string `json:"name"`
+
+	// Controllers of the group: DRPlacementControl, DRPolicy, DRCluster, MaintenanceRelocate, DRBulkAction or
+	// DRPCJanitor. A controller is in one group at most.
+	Controllers []string `json:"controllers"`
+}
+
+// Profiling configures the profiles of the operator. The pprof endpoints are served at /debug/pprof/ on the
+// metrics server.
+type Profiling struct {
+	// CaptureThreshold, if set along with S3ProfileName, is the average reconcile time of a controller, over a
+	// check interval, beyond which the CPU and heap profiles of the operator are captured and uploaded
+	CaptureThreshold *metav1.Duration `json:"captureThreshold,omitempty"`
+
+	// S3ProfileName is the profile of the S3 store the captured profiles are uploaded to, under the diagnostics/
+	// prefix
+	S3ProfileName string `json:"s3ProfileName,omitempty"`
+
+	// CheckInterval is the interval the average reconcile times are checked over. Defaults to 1 minute.
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+
+	// MinCaptureInterval is the least time between two captures. Defaults to 30 minutes.
+	MinCaptureInterval *metav1.Duration `json:"minCaptureInterval,omitempty"`
+
+	// CPUProfileDuration is how long the CPU profile is captured for. Defaults to 30 seconds.
+	CPUProfileDuration *metav1.Duration `json:"cpuProfileDuration,omitempty"`
+}
+
+// NotificationEvent is a DR event that messages are sent on
+type NotificationEvent string
+
+const (
+	NotificationEventFailoverStarted          = NotificationEvent("FailoverStarted")
+	NotificationEventFailoverCompleted        = NotificationEvent("FailoverCompleted")
+	NotificationEventRelocateStarted          = NotificationEvent("RelocateStarted")
+	NotificationEventRelocateCompleted        = NotificationEvent("RelocateCompleted")
+	NotificationEventRPOViolated              = NotificationEvent("RPOViolated")
+	NotificationEventSecretDistributionFailed = NotificationEvent("SecretDistributionFailed")
+)
+
+// NotificationSinkType is the kind of endpoint messages are sent to
+type NotificationSinkType string
+
+const (
+	// NotificationSinkSlack posts the message as the text of a Slack-compatible incoming webhook
+	NotificationSinkSlack = NotificationSinkType("Slack")
+
+	// NotificationSinkWebhook posts the event and the message as JSON
+	NotificationSinkWebhook = NotificationSinkType("Webhook")
+
+	// NotificationSinkSMTP mails the message
+	NotificationSinkSMTP = NotificationSinkType("SMTP")
+)
+
+// Notifications are the sinks messages on DR events are sent to
+type Notifications struct {
+	// Sinks are the endpoints messages are sent to
+	Sinks []NotificationSink `json:"sinks"`
+
+	// MinInterval is the least time between the messages of an event of an object sent to a sink, further
+	// messages being dropped. Defaults to 10 minutes.
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+}
+
+// NotificationSink is an endpoint messages on DR events are sent to
+type NotificationSink struct {
+	// Name of the sink, unique among the sinks
+	Name string `json:"name"`
+
+	// Type of the sink: Slack, Webhook or SMTP
+	Type NotificationSinkType `json:"type"`
+
+	// Events are those messages are sent to the sink on. Defaults to all events.
+	Events []NotificationEvent `json:"events,omitempty"`
+
+	// URL messages are posted to, for the Slack and Webhook sinks
+	URL string `json:"url,omitempty"`
+
+	// SecretName is the name of a secret, in the namespace of the operator, whose url key overrides the URL, such as
+	// for webhook URLs that embed a token, and whose username and password keys authenticate to the SMTP server
+	SecretName string `json:"secretName,omitempty"`
+
+	// SMTP server and addresses messages are mailed with, for the SMTP sink
+	SMTP *SMTPNotificationSink `json:"smtp,omitempty"`
+
+	// Template is a Go template of the message, executed with .Event, .Kind, .Namespace, .Name, .Cluster, .Message
+	// and .Time. Defaults to a line with each of them.
+	Template string `json:"template,omitempty"`
+}
+
+// SMTPNotificationSink is an SMTP server and the addresses messages are mailed with
+type SMTPNotificationSink struct {
+	// Host of the SMTP server
+	Host string `json:"host"`
+
+	// Port of the SMTP server. Defaults to 587.
+	Port int `json:"port,omitempty"`
+
+	// From is the address messages are mailed from
+	From string `json:"from"`
+
+	// To are the addresses messages are mailed to
+	To []string `json:"to"`
+}
+
+// DRFreeze holds the DR actions of the DRPCs until they are approved, by annotating them with the approvals of
+// named approvers
+type DRFreeze struct {
+	// Approvers are the names whose approvals are accepted
+	Approvers []string `json:"approvers"`
+
+	// RequiredApprovals is how many of the approvers must approve an action. Defaults to 1.
+	RequiredApprovals int `json:"requiredApprovals,omitempty"`
+
+	// ExemptDRPCs are the namespace/name of the DRPCs whose actions are not held
+	ExemptDRPCs []string `json:"exemptDRPCs,omitempty"`
+}
+
+// LogConfig is the level and encoding of the operator's logs
+type LogConfig struct {
+	// Level is the least severe level logged: debug, info, error, or an integer verbosity, with 0 being info and
+	// greater being more verbose
+	Level string `json:"level,omitempty"`
+
+	// Encoder is the encoding of the logs: json or console
+	Encoder string `json:"encoder,omitempty"`
+}
+
+// DrClusterObjectsRollout is a progressive rollout of the objects deployed to the clusters
+type DrClusterObjectsRollout struct {
+	// HealthCheckTimeout is how long a cluster has to apply the objects, and its operator to report healthy, before
+	// the rollout is halted. Defaults to 10 minutes.
+	HealthCheckTimeout *metav1.Duration `json:"healthCheckTimeout,omitempty"`
+}
+
+// DrClusterOperatorAddOn is the OCM add-on the dr-cluster operator is delivered as. The add-on manager installs
+// it, from an AddOnTemplate, on the clusters of the DRClusters and on those its placements select.
+type DrClusterOperatorAddOn struct {
+	// AddOnTemplateName is the name of the AddOnTemplate that deploys the dr-cluster operator, such as the one
+	// hack/dr-cluster-addon-template.sh generates. Defaults to ramen-dr-cluster.
+	AddOnTemplateName string `json:"addOnTemplateName,omitempty"`
+
+	// Placements select clusters to install the add-on on ahead of their DRClusters, such as those to be added to
+	// DRPolicies
+	Placements []DrClusterOperatorAddOnPlacement `json:"placements,omitempty"`
+}
+
+// DrClusterOperatorAddOnPlacement refers to a Placement that selects clusters to install the add-on on
+type DrClusterOperatorAddOnPlacement struct {
+	// Namespace of the Placement
+	Namespace string `json:"namespace"`
+
+	// Name of the Placement
+	Name string `json:"name"`
+}
+
+// ArrayReplicationPlugin is the plugin of an array based replication provider
+type ArrayReplicationPlugin struct {
+	// Name of the provider, that storage classes select with the label
+	// ramendr.openshift.io/sync-replication-provider
+	Name string `json:"name"`
+
+	// Endpoint the plugin serves on, such as unix:///plugins/array.sock
+	Endpoint string `json:"endpoint"`
+}
+
+// DrClusterOperatorUpgrade is a rollout of the dr-cluster operators to a version
+type DrClusterOperatorUpgrade struct {
+	// Version the dr-cluster operators report once upgraded
+	Version string `json:"version"`
+
+	// ChannelName of the subscription to upgrade from. Defaults to the channel of DrClusterOperator.
+	ChannelName string `json:"channelName,omitempty"`
+
+	// ClusterServiceVersionName to upgrade to
+	ClusterServiceVersionName string `json:"clusterServiceVersionName,omitempty"`
+
+	// CanaryClusterNames are the clusters upgraded first. The other clusters are upgraded once all of these are.
+	CanaryClusterNames []string `json:"canaryClusterNames,omitempty"`
+
+	// HealthCheckTimeout is how long an upgraded operator has to report the version and to report healthy before
+	// its subscription is reverted. Defaults to 30 minutes.
+	HealthCheckTimeout *metav1.Duration `json:"healthCheckTimeout,omitempty"`
 }
 
 func init() {
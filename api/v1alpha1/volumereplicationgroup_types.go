@@ -7,6 +7,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -86,6 +87,98 @@ type VolSyncSpec struct {
 
 	// disabled when set, all the VolSync code is bypassed. Default is 'false'
 	Disabled bool `json:"disabled,omitempty"`
+
+	// moverConfig contains tunables for the VolSync data movers
+	//+optional
+	MoverConfig *VolSyncMoverConfig `json:"moverConfig,omitempty"`
+
+	// syncthingPeers are the syncthing devices of the peer clusters that replicate PVCs using the Syncthing mover,
+	// set from the status of the peer VRGs
+	//+optional
+	SyncthingPeers []VolSyncSyncthingDevice `json:"syncthingPeers,omitempty"`
+}
+
+// VolSyncSyncthingDevice is the syncthing device that replicates a PVC on a cluster
+type VolSyncSyncthingDevice struct {
+	// namespace of the PVC
+	Namespace string `json:"namespace"`
+
+	// name of the PVC
+	Name string `json:"name"`
+
+	// id is the ID of the syncthing device
+	ID string `json:"id"`
+}
+
+// VolSyncMover is the VolSync data mover used to replicate PVCs
+// +kubebuilder:validation:Enum=RsyncTLS;Restic;Syncthing
+type VolSyncMover string
+
+const (
+	// VolSyncMoverRsyncTLS replicates PVCs directly to the peer cluster over rsync-tls
+	VolSyncMoverRsyncTLS = VolSyncMover("RsyncTLS")
+
+	// VolSyncMoverRestic replicates PVCs using restic repositories in object storage
+	VolSyncMoverRestic = VolSyncMover("Restic")
+
+	// VolSyncMoverSyncthing keeps PVCs continuously in sync with their copies on the peer cluster over syncthing
+	VolSyncMoverSyncthing = VolSyncMover("Syncthing")
+)
+
+// VolSyncMoverConfig defines tunables for the VolSync data movers created by the VRG
+// +kubebuilder:validation:XValidation:rule="!has(self.mover) || self.mover != 'Restic' || has(self.restic)", message="restic is required when mover is Restic"
+type VolSyncMoverConfig struct {
+	// mover is the VolSync data mover used to replicate PVCs. Default is 'RsyncTLS'
+	//+optional
+	Mover VolSyncMover `json:"mover,omitempty"`
+
+	// storageClassNames restricts mover to PVCs of the listed storage classes, PVCs of other storage classes
+	// are replicated using RsyncTLS. An empty list applies mover to all PVCs
+	//+optional
+	StorageClassNames []string `json:"storageClassNames,omitempty"`
+
+	// restic contains the Restic mover configuration, required when mover is 'Restic'
+	//+optional
+	Restic *VolSyncResticConfig `json:"restic,omitempty"`
+
+	// copyMethod used by the ReplicationSource to create a point-in-time copy of the PVC. Default is 'Snapshot'
+	//+optional
+	//+kubebuilder:validation:Enum=Snapshot;Clone;Direct
+	CopyMethod string `json:"copyMethod,omitempty"`
+
+	// moverSecurityContext is the PodSecurityContext the mover pods run with
+	//+optional
+	MoverSecurityContext *corev1.PodSecurityContext `json:"moverSecurityContext,omitempty"`
+
+	// cephFSReadWriteManySnapshots when set, protects ReadWriteMany CephFS PVCs using scheduled VolumeSnapshots
+	// replicated to the peer cluster by VolSync, even when a VolumeReplicationClass matches their provisioner.
+	// Each sync sends only the changes since the snapshot of the previous sync, and reports the bytes sent in the
+	// lastSyncBytes of the PVC. Default is 'false'
+	//+optional
+	CephFSReadWriteManySnapshots bool `json:"cephFSReadWriteManySnapshots,omitempty"`
+
+	// localVolumes when set, protects PVCs of local persistent volumes, which support neither VolumeSnapshots nor
+	// volume replication, by forcing the Direct copyMethod for them: the mover pods mount the PVCs and rsync their
+	// data to the peer cluster on schedule. Default is 'false'
+	//+optional
+	LocalVolumes bool `json:"localVolumes,omitempty"`
+}
+
+// VolSyncResticConfig defines the Restic mover configuration
+type VolSyncResticConfig struct {
+	// repository is the name prefix of the Secrets holding the restic repository configuration. Each PVC
+	// uses its own repository, stored in the Secret named <repository>-<pvc name> in the PVC namespace on
+	// both clusters
+	//+kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// cacheCapacity is the size of the restic metadata cache volume
+	//+optional
+	CacheCapacity *resource.Quantity `json:"cacheCapacity,omitempty"`
+
+	// pruneIntervalDays is the interval between repository prune operations
+	//+optional
+	PruneIntervalDays *int32 `json:"pruneIntervalDays,omitempty"`
 }
 
 // VRGAction which will be either a Failover or Relocate
@@ -122,6 +215,174 @@ type KubeObjectProtectionSpec struct {
 	// Label selector to identify all the kube objects that need DR protection.
 	// +optional
 	KubeObjectSelector *metav1.LabelSelector `json:"kubeObjectSelector,omitempty"`
+
+	// Overrides applied to Services of type LoadBalancer as they are recovered to a cluster
+	// +optional
+	ServiceOverrides []ServiceOverride `json:"serviceOverrides,omitempty"`
+
+	// Pin the workloads scaled by HorizontalPodAutoscalers at the minimum replicas of their autoscalers as they are
+	// recovered, suspending autoscaling until the recovered workload is ready, for it not to scale while the cluster
+	// warms up
+	// +optional
+	PinRecoveredScale bool `json:"pinRecoveredScale,omitempty"`
+
+	// Annotate the pod templates of the recovered deployments and stateful sets with a hash of the contents of the
+	// config maps and secrets they reference, for their pods to restart with the recovered configuration
+	// +optional
+	AnnotateConfigHashes bool `json:"annotateConfigHashes,omitempty"`
+
+	// Gate the scheduling of the pods of the recovered deployments, stateful sets and daemon sets until the PVCs
+	// they mount are bound and their data is ready, for the pods not to crash loop or remain pending while large
+	// volumes are restored
+	// +optional
+	GateSchedulingOnVolumes bool `json:"gateSchedulingOnVolumes,omitempty"`
+
+	// Protect the ready volume snapshots of the protected PVCs with their contents, and recreate them on recovery
+	// where the snapshot class of their driver is labeled as replicating them, for the application's snapshots to
+	// survive a failover
+	// +optional
+	ProtectVolumeSnapshots bool `json:"protectVolumeSnapshots,omitempty"`
+
+	// Velero settings of the kube object captures and recoveries
+	// +optional
+	Velero *KubeObjectVeleroSpec `json:"velero,omitempty"`
+
+	// How the resource quotas of the protected namespaces are reconciled with the workload as it is recovered to a
+	// cluster. Defaults to Report.
+	// +optional
+	QuotaPolicy QuotaPolicy `json:"quotaPolicy,omitempty"`
+
+	// Exemptions of the protected namespaces from admission policies, of Kyverno or Gatekeeper, while they are
+	// recovered to a cluster, for the policies not to refuse the recovered objects. Only the exemptions the
+	// cluster's operator configuration allows are made, and they are removed once the recovered workload is ready.
+	// +optional
+	PolicyExemptions []PolicyExemption `json:"policyExemptions,omitempty"`
+}
+
+// PolicyEngine is an admission policy engine
+// +kubebuilder:validation:Enum=Kyverno;Gatekeeper
+type PolicyEngine string
+
+const (
+	PolicyEngineKyverno    = PolicyEngine("Kyverno")
+	PolicyEngineGatekeeper = PolicyEngine("Gatekeeper")
+)
+
+// PolicyExemption is an exemption from the admission policy of an engine
+// +kubebuilder:validation:XValidation:rule="self.engine != 'Gatekeeper' || (has(self.kind) && size(self.kind) > 0)",message="kind is required for Gatekeeper"
+type PolicyExemption struct {
+	// Engine of the policy
+	Engine PolicyEngine `json:"engine"`
+
+	// Name of the Kyverno ClusterPolicy, or of the Gatekeeper constraint
+	Name string `json:"name"`
+
+	// Kind of the Gatekeeper constraint, such as K8sRequiredLabels
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Rules of the Kyverno ClusterPolicy to exempt from, all of its rules if not set
+	// +optional
+	Rules []string `json:"rules,omitempty"`
+}
+
+// QuotaPolicy is how the resource quotas of a namespace recovered to are reconciled with the workload recovered:
+// Report reports the resource quotas and limit ranges that block the recovery, Create also creates the resource
+// quotas captured that are missing, and Adjust also raises the hard limits of the resource quotas that block it, if
+// the operator's configuration allows it
+// +kubebuilder:validation:Enum=Report;Create;Adjust
+type QuotaPolicy string
+
+const (
+	QuotaPolicyReport = QuotaPolicy("Report")
+	QuotaPolicyCreate = QuotaPolicy("Create")
+	QuotaPolicyAdjust = QuotaPolicy("Adjust")
+)
+
+// VolumeBackupMethod is how Velero backs up the data of the volumes in a kube objects capture
+// +kubebuilder:validation:Enum=None;Snapshot;FsBackup
+type VolumeBackupMethod string
+
+const (
+	// Volume data is not backed up by Velero, as it is replicated by Ramen
+	VolumeBackupMethodNone = VolumeBackupMethod("None")
+
+	// Volume data is backed up with volume snapshots
+	VolumeBackupMethodSnapshot = VolumeBackupMethod("Snapshot")
+
+	// Volume data is backed up with file system backup
+	VolumeBackupMethodFsBackup = VolumeBackupMethod("FsBackup")
+)
+
+// VeleroItemActionKind is the kind of Velero item action plugin that an item action configuration is for
+// +kubebuilder:validation:Enum=BackupItemAction;RestoreItemAction
+type VeleroItemActionKind string
+
+const (
+	VeleroItemActionKindBackup  = VeleroItemActionKind("BackupItemAction")
+	VeleroItemActionKindRestore = VeleroItemActionKind("RestoreItemAction")
+)
+
+// KubeObjectVeleroSpec configures the Velero backups and restores that capture and recover kube objects
+type KubeObjectVeleroSpec struct {
+	// How long Velero keeps a capture before garbage collecting it. Velero's default applies if unset.
+	//+optional
+	//+kubebuilder:validation:Format=duration
+	BackupTTL *metav1.Duration `json:"backupTTL,omitempty"`
+
+	// How Velero backs up the data of volumes not selected by a resource policy. Defaults to None.
+	//+optional
+	DefaultVolumeBackupMethod VolumeBackupMethod `json:"defaultVolumeBackupMethod,omitempty"`
+
+	// Name of a ConfigMap, in the VolumeReplicationGroup namespace, of Velero resource policies, which select per
+	// volume whether its data is snapshotted, backed up with file system backup, or skipped. The ConfigMap is copied
+	// to the Velero namespace and referenced by each capture, which requires Velero 1.12 or later.
+	//+optional
+	ResourcePolicyConfigMapName string `json:"resourcePolicyConfigMapName,omitempty"`
+}
+
+// VeleroItemActionConfig is the configuration of a Velero item action plugin
+type VeleroItemActionConfig struct {
+	// Name of the plugin, e.g. velero.io/change-storage-class
+	Plugin string `json:"plugin"`
+
+	// Kind of the plugin
+	Kind VeleroItemActionKind `json:"kind"`
+
+	// Configuration data of the plugin
+	//+optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// ServiceOverride is applied to a recovered Service of type LoadBalancer, for the Service to come up with
+// networking that is valid on the cluster it is recovered to
+type ServiceOverride struct {
+	// Clusters the override applies to. Applies to all clusters if empty.
+	//+optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Namespace of the Service. Defaults to the VolumeReplicationGroup namespace.
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the Service
+	Name string `json:"name"`
+
+	// Annotations to set on the Service, an empty value removes the annotation
+	//+optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// LoadBalancerClass of the Service. As it cannot be changed, a Service with a different class is recreated.
+	//+optional
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+
+	// LoadBalancerIP is the static IP address requested for the Service
+	//+optional
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// LoadBalancerSourceRanges restrict the client IP ranges allowed by the load balancer
+	//+optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
 }
 
 type RecipeRef struct {
@@ -136,6 +397,17 @@ type RecipeRef struct {
 
 const KubeObjectProtectionCaptureIntervalDefault = 5 * time.Minute
 
+// HelperPodSchedulingSpec carries scheduling hints applied to pods that Ramen creates on a managed cluster
+type HelperPodSchedulingSpec struct {
+	// NodeSelector to apply to helper pods
+	//+optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations to apply to helper pods other than the VolSync mover pods, which take no tolerations
+	//+optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
 // VolumeReplicationGroup (VRG) spec declares the desired schedule for data
 // replication and replication state of all PVCs identified via the given
 // PVC label selector. For each such PVC, the VRG will do the following:
@@ -193,6 +465,86 @@ type VolumeReplicationGroupSpec struct {
 	// You can use a recipe to filter and coordinate the order of the resources that are protected.
 	//+optional
 	ProtectedNamespaces *[]string `json:"protectedNamespaces,omitempty"`
+
+	// HelperPodScheduling contains scheduling hints for any helper pods created by the VRG: the recipe hook jobs and
+	// failover preparation pods take its node selector and tolerations, the VolSync mover pods take its node selector
+	// as a node affinity
+	//+optional
+	HelperPodScheduling *HelperPodSchedulingSpec `json:"helperPodScheduling,omitempty"`
+
+	// ImageRegistryMirrors rewrite the container images of the recovered workload, as set by the hub from the
+	// DRCluster this VRG is placed on
+	//+optional
+	ImageRegistryMirrors []ImageRegistryMirror `json:"imageRegistryMirrors,omitempty"`
+
+	// TopologyTranslations rewrite the node affinity of the restored PVs, as set by the hub from the DRCluster this
+	// VRG is placed on
+	//+optional
+	TopologyTranslations []TopologyTranslation `json:"topologyTranslations,omitempty"`
+
+	// StorageClassMappings rewrite the storage classes of the restored PVCs and PVs, and of the VolSync
+	// destinations, as set by the hub from the DRCluster this VRG is placed on
+	//+optional
+	StorageClassMappings []StorageClassMapping `json:"storageClassMappings,omitempty"`
+
+	// AccessModeMappings rewrite the access modes of the restored PVCs and PVs, and of the VolSync destinations, as
+	// set by the hub from the DRCluster this VRG is placed on
+	//+optional
+	AccessModeMappings []AccessModeMapping `json:"accessModeMappings,omitempty"`
+
+	// DomainSuffixTranslations rewrite the hostnames of the recovered Ingresses, Gateways and HTTPRoutes, as set by
+	// the hub from the DRCluster this VRG is placed on
+	//+optional
+	DomainSuffixTranslations []DomainSuffixTranslation `json:"domainSuffixTranslations,omitempty"`
+
+	// GatewayClassTranslations rewrite the gateway classes of the recovered Gateways, as set by the hub from the
+	// DRCluster this VRG is placed on
+	//+optional
+	GatewayClassTranslations []GatewayClassTranslation `json:"gatewayClassTranslations,omitempty"`
+
+	// SecretRewrites rewrite the values of the recovered Secrets, as set by the hub from the DRCluster this VRG is
+	// placed on
+	//+optional
+	SecretRewrites []SecretRewrite `json:"secretRewrites,omitempty"`
+
+	// NetworkAttachmentMappings rewrite the NetworkAttachmentDefinitions the recovered workloads attach their pods
+	// to, as set by the hub from the DRCluster this VRG is placed on
+	//+optional
+	NetworkAttachmentMappings []NetworkAttachmentMapping `json:"networkAttachmentMappings,omitempty"`
+
+	// PrepareForFailover when set on a secondary VRG, prepares its cluster to take over the workload, as set by the
+	// hub for the failover cluster of a DRPC that prepares failover
+	//+optional
+	PrepareForFailover *FailoverPreparationSpec `json:"prepareForFailover,omitempty"`
+}
+
+// FailoverPreparationSpec is the preparation of a cluster to take over a workload. The Secrets, ConfigMaps and
+// ServiceAccounts of the workload are staged from its latest kube objects capture, without its workload resources,
+// so nothing is scaled up, and its images are pulled on the nodes.
+type FailoverPreparationSpec struct {
+	// images are the container images of the workload to pull
+	//+optional
+	Images []string `json:"images,omitempty"`
+
+	// windowsImages are the container images of the Windows pods of the workload to pull, on the Windows nodes
+	//+optional
+	WindowsImages []string `json:"windowsImages,omitempty"`
+}
+
+// VRGFailoverPreparationStatus is the progress of the preparation of a cluster to take over a workload
+type VRGFailoverPreparationStatus struct {
+	// stagedCapture is the number of the kube objects capture the workload Secrets, ConfigMaps and ServiceAccounts
+	// were last staged from
+	//+optional
+	StagedCapture *int64 `json:"stagedCapture,omitempty"`
+
+	// imagesPulled are the images of the workload pulled on all the nodes the image pull pods run on
+	//+optional
+	ImagesPulled []string `json:"imagesPulled,omitempty"`
+
+	// message describes the preparation step in progress, or the reason it failed
+	//+optional
+	Message string `json:"message,omitempty"`
 }
 
 type Identifier struct {
@@ -293,11 +645,204 @@ type KubeObjectsCaptureIdentifier struct {
 	//+nullable
 	EndTime         metav1.Time `json:"endTime,omitempty"`
 	StartGeneration int64       `json:"startGeneration,omitempty"`
+
+	// Sequence counts the differential captures, whose groups are captured apart from the numbered captures
+	//+optional
+	Sequence int64 `json:"sequence,omitempty"`
+
+	// FullSequence is the sequence of the latest differential capture that captured every group
+	//+optional
+	FullSequence int64 `json:"fullSequence,omitempty"`
+
+	// Groups locate the capture of each group of a differential capture, which is that of an earlier capture for
+	// a group whose objects did not change since
+	//+optional
+	Groups []KubeObjectsCaptureGroupIdentifier `json:"groups,omitempty"`
+}
+
+// KubeObjectsCaptureGroupIdentifier locates the capture of a group of a differential capture
+type KubeObjectsCaptureGroupIdentifier struct {
+	Name string `json:"name"`
+
+	// Sequence of the differential capture the group was captured in
+	Sequence int64 `json:"sequence"`
+
+	// NamePrefix of the requests that captured the group
+	NamePrefix string `json:"namePrefix"`
+
+	// Hash of the identities, generations and metadata of the group's objects when it was captured, or empty if
+	// the group is captured every time, such as one that includes cluster resources
+	//+optional
+	Hash string `json:"hash,omitempty"`
+}
+
+// KubeObjectsCaptureManifestSummary summarizes the manifest written alongside a kube objects capture
+type KubeObjectsCaptureManifestSummary struct {
+	Number int64 `json:"number"`
+	//+nullable
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// Count of the objects captured
+	Objects int `json:"objects"`
+
+	// Count of the objects captured of each kind, keyed by group/version/Kind
+	//+optional
+	Kinds map[string]int `json:"kinds,omitempty"`
+
+	// SHA-256 of the content of the objects captured, other than their status and server-set metadata, equal for
+	// captures of the same content
+	Checksum string `json:"checksum"`
+}
+
+// KubeObjectsRestorePreview previews the recovery of the capture to recover from on a VRG's cluster: which of its
+// objects already exist, and would be kept or overwritten, or fail to be updated, by the recovery
+type KubeObjectsRestorePreview struct {
+	// Request is the restore preview annotation value that the preview was made for
+	Request string `json:"request"`
+
+	// PreviewTime is the time the preview was made
+	//+optional
+	PreviewTime *metav1.Time `json:"previewTime,omitempty"`
+
+	// CaptureNumber is the number of the capture to recover from
+	//+optional
+	CaptureNumber int64 `json:"captureNumber,omitempty"`
+
+	// Objects is the count of the objects of the capture to recover from
+	//+optional
+	Objects int `json:"objects,omitempty"`
+
+	// ConflictCount is the count of the conflicts, of which the first 100 are listed
+	//+optional
+	ConflictCount int `json:"conflictCount,omitempty"`
+
+	// Conflicts are the objects of the capture to recover from that conflict with the cluster
+	//+optional
+	Conflicts []KubeObjectsRestoreConflict `json:"conflicts,omitempty"`
+
+	// Errors are the failures to preview, whose conflicts are missing from the preview
+	//+optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// KubeObjectsRestoreConflictReason is why an object of a capture conflicts with a cluster
+// +kubebuilder:validation:Enum=Exists;ImmutableFields;KindNotServed
+type KubeObjectsRestoreConflictReason string
+
+const (
+	// KubeObjectsRestoreConflictExists is of an object that exists, and is kept, or overwritten if its group
+	// recovers with an existing resource policy of update
+	KubeObjectsRestoreConflictExists = KubeObjectsRestoreConflictReason("Exists")
+
+	// KubeObjectsRestoreConflictImmutableFields is of an object that exists, of a kind with immutable fields that
+	// fail its update if they differ
+	KubeObjectsRestoreConflictImmutableFields = KubeObjectsRestoreConflictReason("ImmutableFields")
+
+	// KubeObjectsRestoreConflictKindNotServed is of the objects of a kind that the cluster does not serve, and fail
+	// to be recovered
+	KubeObjectsRestoreConflictKindNotServed = KubeObjectsRestoreConflictReason("KindNotServed")
+)
+
+// KubeObjectsRestoreConflict is an object of a capture that conflicts with a cluster
+type KubeObjectsRestoreConflict struct {
+	// Kind of the object, as group/version/Kind
+	Kind string `json:"kind"`
+
+	// Name of the object, as namespace/name, or name if cluster scoped, empty for a kind not served
+	//+optional
+	Name string `json:"name,omitempty"`
+
+	Reason KubeObjectsRestoreConflictReason `json:"reason"`
+
+	//+optional
+	Message string `json:"message,omitempty"`
+}
+
+// KubeObjectsHookJobStatus is the status of the latest run of a recipe hook that runs as a job
+type KubeObjectsHookJobStatus struct {
+	// Name of the hook and its operation, as referred to by the workflow
+	Name string `json:"name"`
+
+	// Name of the job that ran the hook
+	JobName string `json:"jobName"`
+
+	// Whether the job succeeded
+	//+optional
+	Succeeded bool `json:"succeeded,omitempty"`
+
+	// Exit code of the job's container, once it terminated
+	//+optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
+	// Tail of the log of the job's container, once it terminated
+	//+optional
+	Log string `json:"log,omitempty"`
+
+	//+optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// KubeObjectsHookCallStatus is the status of the latest run of a recipe hook that calls out over http
+type KubeObjectsHookCallStatus struct {
+	// Name of the hook and its operation, as referred to by the workflow
+	Name string `json:"name"`
+
+	// Run is the capture or recovery the hook was called for
+	Run string `json:"run"`
+
+	// Number of calls made since the run started or last failed
+	//+optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// Whether a call succeeded
+	//+optional
+	Succeeded bool `json:"succeeded,omitempty"`
+
+	// Status code of the response to the latest call
+	//+optional
+	StatusCode int32 `json:"statusCode,omitempty"`
+
+	// Error of the latest call, if it failed
+	//+optional
+	Message string `json:"message,omitempty"`
+
+	//+optional
+	Time *metav1.Time `json:"time,omitempty"`
 }
 
 type KubeObjectProtectionStatus struct {
 	//+optional
 	CaptureToRecoverFrom *KubeObjectsCaptureIdentifier `json:"captureToRecoverFrom,omitempty"`
+
+	// Groups of the differential capture in progress, as each was reached, captured or unchanged
+	//+optional
+	CaptureInProgress *KubeObjectsCaptureIdentifier `json:"captureInProgress,omitempty"`
+
+	// Summary of the manifest of the latest capture
+	//+optional
+	LastCaptureManifest *KubeObjectsCaptureManifestSummary `json:"lastCaptureManifest,omitempty"`
+
+	// Most recent preview of the recovery of the capture to recover from on this cluster, requested by setting the
+	// volumereplicationgroups.ramendr.openshift.io/restore-preview annotation to a new value
+	//+optional
+	RestorePreview *KubeObjectsRestorePreview `json:"restorePreview,omitempty"`
+
+	// Most recent request to resume the cron jobs suspended as they were recovered, the value of the
+	// volumereplicationgroups.ramendr.openshift.io/resume-cron-jobs annotation they were resumed for
+	//+optional
+	CronJobsResumed string `json:"cronJobsResumed,omitempty"`
+
+	// Time the policy exemptions were applied to recover the kube objects, until they are removed
+	//+optional
+	PolicyExemptionsApplied *metav1.Time `json:"policyExemptionsApplied,omitempty"`
+
+	// Latest runs of the recipe hooks that run as jobs
+	//+optional
+	HookJobs []KubeObjectsHookJobStatus `json:"hookJobs,omitempty"`
+
+	// Latest runs of the recipe hooks that call out over http
+	//+optional
+	HookCalls []KubeObjectsHookCallStatus `json:"hookCalls,omitempty"`
 }
 
 // VolumeReplicationGroupStatus defines the observed state of VolumeReplicationGroup
@@ -333,8 +878,141 @@ type VolumeReplicationGroupStatus struct {
 	// successful synchronization of all PVCs
 	//+optional
 	LastGroupSyncBytes *int64 `json:"lastGroupSyncBytes,omitempty"`
+
+	// skippedPVCs are the PVCs selected by the VRG that are not protected, with the reason
+	//+optional
+	SkippedPVCs []SkippedPVC `json:"skippedPVCs,omitempty"`
+
+	// workloadRequests are the cpu and memory requests of the pods, and the storage requests of the protected PVCs,
+	// of the workload while the VRG is primary
+	//+optional
+	WorkloadRequests corev1.ResourceList `json:"workloadRequests,omitempty"`
+
+	// namespaceSizings are what the workload of each protected namespace requires of the resource quotas and limit
+	// ranges of the namespace it is recovered to, while the VRG is primary
+	//+optional
+	NamespaceSizings []NamespaceSizing `json:"namespaceSizings,omitempty"`
+
+	// s3Transfer is the progress of a transfer of cluster data with an S3 store that runs in the background. The
+	// transfer is resumed with its S3 profile after an operator restart.
+	//+optional
+	S3Transfer *S3TransferStatus `json:"s3Transfer,omitempty"`
+
+	// syncthingDevices are the syncthing devices of this cluster that replicate PVCs using the Syncthing mover
+	//+optional
+	SyncthingDevices []VolSyncSyncthingDevice `json:"syncthingDevices,omitempty"`
+
+	// workloadImages are the container images of the pods of the workload while the VRG is primary, for the hub to
+	// prepare a failover cluster
+	//+optional
+	WorkloadImages []string `json:"workloadImages,omitempty"`
+
+	// workloadWindowsImages are the container images of the Windows pods of the workload while the VRG is primary
+	//+optional
+	WorkloadWindowsImages []string `json:"workloadWindowsImages,omitempty"`
+
+	// failoverPreparation is the progress of the preparation of this cluster to take over the workload, requested by
+	// spec.prepareForFailover
+	//+optional
+	FailoverPreparation *VRGFailoverPreparationStatus `json:"failoverPreparation,omitempty"`
+
+	// clusterDataDrift is the result of the last check of the PV cluster data in the S3 store against the PVs on
+	// this, secondary, cluster
+	//+optional
+	ClusterDataDrift *ClusterDataDriftStatus `json:"clusterDataDrift,omitempty"`
 }
 
+// ClusterDataDriftStatus is the result of a check of the PV cluster data in an S3 store against the PVs on a cluster
+type ClusterDataDriftStatus struct {
+	// lastCheckTime is the time of the check
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+
+	// drifts describe each drift detected
+	//+optional
+	Drifts []string `json:"drifts,omitempty"`
+
+	// presentPVs are the names of the PVs of the cluster data that exist, or existed, on the cluster. A PV of the
+	// cluster data that existed, and no longer does, was deleted, possibly with its volume.
+	//+optional
+	PresentPVs []string `json:"presentPVs,omitempty"`
+}
+
+// S3TransferOperation is the operation of an S3 transfer
+type S3TransferOperation string
+
+const (
+	S3TransferOperationDownload S3TransferOperation = "Download"
+)
+
+// S3TransferStatus is the progress of a transfer of cluster data with an S3 store
+type S3TransferStatus struct {
+	// operation of the transfer
+	Operation S3TransferOperation `json:"operation"`
+
+	// s3ProfileName is the S3 profile of the store transferred with
+	S3ProfileName string `json:"s3ProfileName"`
+
+	// completed is the number of objects transferred
+	Completed int `json:"completed"`
+
+	// total is the number of objects to transfer, once known
+	Total int `json:"total"`
+
+	// startTime is the time the transfer started
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// NamespaceSizing is what the workload of a protected namespace requires of the resource quotas and limit ranges of
+// the namespace it is recovered to
+type NamespaceSizing struct {
+	Namespace string `json:"namespace"`
+
+	// usage of the workload, by the resource names of resource quotas, such as requests.cpu, limits.memory, pods,
+	// persistentvolumeclaims and requests.storage
+	//+optional
+	Usage corev1.ResourceList `json:"usage,omitempty"`
+
+	// containerMaximums are the largest cpu and memory limits, or requests if without limits, of a container,
+	// checked against the maximums of the limit ranges for containers
+	//+optional
+	ContainerMaximums corev1.ResourceList `json:"containerMaximums,omitempty"`
+
+	// persistentVolumeClaimMaximums are the largest storage request of a protected PVC, checked against the
+	// maximums of the limit ranges for PVCs
+	//+optional
+	PersistentVolumeClaimMaximums corev1.ResourceList `json:"persistentVolumeClaimMaximums,omitempty"`
+
+	// resourceQuotas are the hard limits of the resource quotas of the namespace without scopes, by name
+	//+optional
+	ResourceQuotas []NamespaceResourceQuota `json:"resourceQuotas,omitempty"`
+}
+
+// NamespaceResourceQuota is the hard limits of a resource quota
+type NamespaceResourceQuota struct {
+	Name string `json:"name"`
+
+	//+optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+// SkippedPVC identifies a PVC that matched the VRG PVC selector, but is not protected
+type SkippedPVC struct {
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+
+	//+optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// SkippedPVC reasons
+	SkippedPVCReasonPVCExcluded          = "PVCExcluded"
+	SkippedPVCReasonStorageClassExcluded = "StorageClassExcluded"
+	SkippedPVCReasonVolSyncRequired      = "VolSyncRequired"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=vrg
@@ -13,6 +13,67 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessModeMapping) DeepCopyInto(out *AccessModeMapping) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessModeMapping.
+func (in *AccessModeMapping) DeepCopy() *AccessModeMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessModeMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArrayReplicationPlugin) DeepCopyInto(out *ArrayReplicationPlugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArrayReplicationPlugin.
+func (in *ArrayReplicationPlugin) DeepCopy() *ArrayReplicationPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(ArrayReplicationPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDataDriftStatus) DeepCopyInto(out *ClusterDataDriftStatus) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	if in.Drifts != nil {
+		in, out := &in.Drifts, &out.Drifts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PresentPVs != nil {
+		in, out := &in.PresentPVs, &out.PresentPVs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDataDriftStatus.
+func (in *ClusterDataDriftStatus) DeepCopy() *ClusterDataDriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDataDriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterMaintenanceMode) DeepCopyInto(out *ClusterMaintenanceMode) {
 	*out = *in
@@ -36,7 +97,82 @@ func (in *ClusterMaintenanceMode) DeepCopy() *ClusterMaintenanceMode {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRCluster) DeepCopyInto(out *DRCluster) {
+func (in *DRActionPermissions) DeepCopyInto(out *DRActionPermissions) {
+	*out = *in
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(DRActors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Relocate != nil {
+		in, out := &in.Relocate, &out.Relocate
+		*out = new(DRActors)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrepareFailover != nil {
+		in, out := &in.PrepareFailover, &out.PrepareFailover
+		*out = new(DRActors)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRActionPermissions.
+func (in *DRActionPermissions) DeepCopy() *DRActionPermissions {
+	if in == nil {
+		return nil
+	}
+	out := new(DRActionPermissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRActionTimeouts) DeepCopyInto(out *DRActionTimeouts) {
+	*out = *in
+	if in.Relocate != nil {
+		in, out := &in.Relocate, &out.Relocate
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRActionTimeouts.
+func (in *DRActionTimeouts) DeepCopy() *DRActionTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(DRActionTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRActors) DeepCopyInto(out *DRActors) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRActors.
+func (in *DRActors) DeepCopy() *DRActors {
+	if in == nil {
+		return nil
+	}
+	out := new(DRActors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRBulkAction) DeepCopyInto(out *DRBulkAction) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -44,18 +180,18 @@ func (in *DRCluster) DeepCopyInto(out *DRCluster) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRCluster.
-func (in *DRCluster) DeepCopy() *DRCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkAction.
+func (in *DRBulkAction) DeepCopy() *DRBulkAction {
 	if in == nil {
 		return nil
 	}
-	out := new(DRCluster)
+	out := new(DRBulkAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRCluster) DeepCopyObject() runtime.Object {
+func (in *DRBulkAction) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -63,31 +199,55 @@ func (in *DRCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRClusterList) DeepCopyInto(out *DRClusterList) {
+func (in *DRBulkActionDRPCStatus) DeepCopyInto(out *DRBulkActionDRPCStatus) {
+	*out = *in
+	out.DRPCReference = in.DRPCReference
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkActionDRPCStatus.
+func (in *DRBulkActionDRPCStatus) DeepCopy() *DRBulkActionDRPCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DRBulkActionDRPCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRBulkActionList) DeepCopyInto(out *DRBulkActionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DRCluster, len(*in))
+		*out = make([]DRBulkAction, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterList.
-func (in *DRClusterList) DeepCopy() *DRClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkActionList.
+func (in *DRBulkActionList) DeepCopy() *DRBulkActionList {
 	if in == nil {
 		return nil
 	}
-	out := new(DRClusterList)
+	out := new(DRBulkActionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRClusterList) DeepCopyObject() runtime.Object {
+func (in *DRBulkActionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -95,56 +255,74 @@ func (in *DRClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRClusterSpec) DeepCopyInto(out *DRClusterSpec) {
+func (in *DRBulkActionSpec) DeepCopyInto(out *DRBulkActionSpec) {
 	*out = *in
-	if in.CIDRs != nil {
-		in, out := &in.CIDRs, &out.CIDRs
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterSpec.
-func (in *DRClusterSpec) DeepCopy() *DRClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkActionSpec.
+func (in *DRBulkActionSpec) DeepCopy() *DRBulkActionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DRClusterSpec)
+	out := new(DRBulkActionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRClusterStatus) DeepCopyInto(out *DRClusterStatus) {
+func (in *DRBulkActionStatus) DeepCopyInto(out *DRBulkActionStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.DRPCs != nil {
+		in, out := &in.DRPCs, &out.DRPCs
+		*out = make([]DRBulkActionDRPCStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.MaintenanceModes != nil {
-		in, out := &in.MaintenanceModes, &out.MaintenanceModes
-		*out = make([]ClusterMaintenanceMode, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out.Summary = in.Summary
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterStatus.
-func (in *DRClusterStatus) DeepCopy() *DRClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkActionStatus.
+func (in *DRBulkActionStatus) DeepCopy() *DRBulkActionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DRClusterStatus)
+	out := new(DRBulkActionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPlacementControl) DeepCopyInto(out *DRPlacementControl) {
+func (in *DRBulkActionSummary) DeepCopyInto(out *DRBulkActionSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRBulkActionSummary.
+func (in *DRBulkActionSummary) DeepCopy() *DRBulkActionSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DRBulkActionSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRCluster) DeepCopyInto(out *DRCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -152,18 +330,18 @@ func (in *DRPlacementControl) DeepCopyInto(out *DRPlacementControl) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControl.
-func (in *DRPlacementControl) DeepCopy() *DRPlacementControl {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRCluster.
+func (in *DRCluster) DeepCopy() *DRCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPlacementControl)
+	out := new(DRCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRPlacementControl) DeepCopyObject() runtime.Object {
+func (in *DRCluster) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -171,31 +349,31 @@ func (in *DRPlacementControl) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPlacementControlList) DeepCopyInto(out *DRPlacementControlList) {
+func (in *DRClusterList) DeepCopyInto(out *DRClusterList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DRPlacementControl, len(*in))
+		*out = make([]DRCluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlList.
-func (in *DRPlacementControlList) DeepCopy() *DRPlacementControlList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterList.
+func (in *DRClusterList) DeepCopy() *DRClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPlacementControlList)
+	out := new(DRClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRPlacementControlList) DeepCopyObject() runtime.Object {
+func (in *DRClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -203,50 +381,41 @@ func (in *DRPlacementControlList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPlacementControlSpec) DeepCopyInto(out *DRPlacementControlSpec) {
+func (in *DRClusterObjectsRolloutStatus) DeepCopyInto(out *DRClusterObjectsRolloutStatus) {
 	*out = *in
-	out.PlacementRef = in.PlacementRef
-	if in.ProtectedNamespaces != nil {
-		in, out := &in.ProtectedNamespaces, &out.ProtectedNamespaces
-		*out = new([]string)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]string, len(*in))
-			copy(*out, *in)
-		}
-	}
-	out.DRPolicyRef = in.DRPolicyRef
-	in.PVCSelector.DeepCopyInto(&out.PVCSelector)
-	if in.KubeObjectProtection != nil {
-		in, out := &in.KubeObjectProtection, &out.KubeObjectProtection
-		*out = new(KubeObjectProtectionSpec)
-		(*in).DeepCopyInto(*out)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlSpec.
-func (in *DRPlacementControlSpec) DeepCopy() *DRPlacementControlSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterObjectsRolloutStatus.
+func (in *DRClusterObjectsRolloutStatus) DeepCopy() *DRClusterObjectsRolloutStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPlacementControlSpec)
+	out := new(DRClusterObjectsRolloutStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPlacementControlStatus) DeepCopyInto(out *DRPlacementControlStatus) {
+func (in *DRClusterOperatorReport) DeepCopyInto(out *DRClusterOperatorReport) {
 	*out = *in
-	if in.ActionStartTime != nil {
-		in, out := &in.ActionStartTime, &out.ActionStartTime
-		*out = (*in).DeepCopy()
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.ActionDuration != nil {
-		in, out := &in.ActionDuration, &out.ActionDuration
-		*out = new(v1.Duration)
-		**out = **in
+	if in.VRGSpecFields != nil {
+		in, out := &in.VRGSpecFields, &out.VRGSpecFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastReportTime != nil {
+		in, out := &in.LastReportTime, &out.LastReportTime
+		*out = (*in).DeepCopy()
 	}
-	out.PreferredDecision = in.PreferredDecision
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -254,62 +423,52 @@ func (in *DRPlacementControlStatus) DeepCopyInto(out *DRPlacementControlStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.ResourceConditions.DeepCopyInto(&out.ResourceConditions)
-	if in.LastUpdateTime != nil {
-		in, out := &in.LastUpdateTime, &out.LastUpdateTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastGroupSyncTime != nil {
-		in, out := &in.LastGroupSyncTime, &out.LastGroupSyncTime
-		*out = (*in).DeepCopy()
-	}
-	if in.LastGroupSyncDuration != nil {
-		in, out := &in.LastGroupSyncDuration, &out.LastGroupSyncDuration
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.LastGroupSyncBytes != nil {
-		in, out := &in.LastGroupSyncBytes, &out.LastGroupSyncBytes
-		*out = new(int64)
-		**out = **in
+	if in.StorageCapacities != nil {
+		in, out := &in.StorageCapacities, &out.StorageCapacities
+		*out = make([]StorageClassCapacity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.LastKubeObjectProtectionTime != nil {
-		in, out := &in.LastKubeObjectProtectionTime, &out.LastKubeObjectProtectionTime
-		*out = (*in).DeepCopy()
+	if in.AvailableResources != nil {
+		in, out := &in.AvailableResources, &out.AvailableResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlStatus.
-func (in *DRPlacementControlStatus) DeepCopy() *DRPlacementControlStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterOperatorReport.
+func (in *DRClusterOperatorReport) DeepCopy() *DRClusterOperatorReport {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPlacementControlStatus)
+	out := new(DRClusterOperatorReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPolicy) DeepCopyInto(out *DRPolicy) {
+func (in *DRClusterOperatorStatus) DeepCopyInto(out *DRClusterOperatorStatus) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicy.
-func (in *DRPolicy) DeepCopy() *DRPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterOperatorStatus.
+func (in *DRClusterOperatorStatus) DeepCopy() *DRClusterOperatorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPolicy)
+	out := new(DRClusterOperatorStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRPolicy) DeepCopyObject() runtime.Object {
+func (in *DRClusterOperatorStatus) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -317,31 +476,31 @@ func (in *DRPolicy) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPolicyList) DeepCopyInto(out *DRPolicyList) {
+func (in *DRClusterOperatorStatusList) DeepCopyInto(out *DRClusterOperatorStatusList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DRPolicy, len(*in))
+		*out = make([]DRClusterOperatorStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicyList.
-func (in *DRPolicyList) DeepCopy() *DRPolicyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterOperatorStatusList.
+func (in *DRClusterOperatorStatusList) DeepCopy() *DRClusterOperatorStatusList {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPolicyList)
+	out := new(DRClusterOperatorStatusList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DRPolicyList) DeepCopyObject() runtime.Object {
+func (in *DRClusterOperatorStatusList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -349,154 +508,202 @@ func (in *DRPolicyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPolicySpec) DeepCopyInto(out *DRPolicySpec) {
+func (in *DRClusterOperatorUpgradeStatus) DeepCopyInto(out *DRClusterOperatorUpgradeStatus) {
 	*out = *in
-	in.ReplicationClassSelector.DeepCopyInto(&out.ReplicationClassSelector)
-	in.VolumeSnapshotClassSelector.DeepCopyInto(&out.VolumeSnapshotClassSelector)
-	if in.DRClusters != nil {
-		in, out := &in.DRClusters, &out.DRClusters
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicySpec.
-func (in *DRPolicySpec) DeepCopy() *DRPolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterOperatorUpgradeStatus.
+func (in *DRClusterOperatorUpgradeStatus) DeepCopy() *DRClusterOperatorUpgradeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPolicySpec)
+	out := new(DRClusterOperatorUpgradeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DRPolicyStatus) DeepCopyInto(out *DRPolicyStatus) {
+func (in *DRClusterSpec) DeepCopyInto(out *DRClusterSpec) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageRegistryMirrors != nil {
+		in, out := &in.ImageRegistryMirrors, &out.ImageRegistryMirrors
+		*out = make([]ImageRegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopologyTranslations != nil {
+		in, out := &in.TopologyTranslations, &out.TopologyTranslations
+		*out = make([]TopologyTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClassMappings != nil {
+		in, out := &in.StorageClassMappings, &out.StorageClassMappings
+		*out = make([]StorageClassMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccessModeMappings != nil {
+		in, out := &in.AccessModeMappings, &out.AccessModeMappings
+		*out = make([]AccessModeMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DomainSuffixTranslations != nil {
+		in, out := &in.DomainSuffixTranslations, &out.DomainSuffixTranslations
+		*out = make([]DomainSuffixTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.GatewayClassTranslations != nil {
+		in, out := &in.GatewayClassTranslations, &out.GatewayClassTranslations
+		*out = make([]GatewayClassTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRewrites != nil {
+		in, out := &in.SecretRewrites, &out.SecretRewrites
+		*out = make([]SecretRewrite, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NetworkAttachmentMappings != nil {
+		in, out := &in.NetworkAttachmentMappings, &out.NetworkAttachmentMappings
+		*out = make([]NetworkAttachmentMapping, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicyStatus.
-func (in *DRPolicyStatus) DeepCopy() *DRPolicyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterSpec.
+func (in *DRClusterSpec) DeepCopy() *DRClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DRPolicyStatus)
+	out := new(DRClusterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Identifier) DeepCopyInto(out *Identifier) {
+func (in *DRClusterStatus) DeepCopyInto(out *DRClusterStatus) {
 	*out = *in
-	if in.Modes != nil {
-		in, out := &in.Modes, &out.Modes
-		*out = make([]MMode, len(*in))
-		copy(*out, *in)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaintenanceModes != nil {
+		in, out := &in.MaintenanceModes, &out.MaintenanceModes
+		*out = make([]ClusterMaintenanceMode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Operator != nil {
+		in, out := &in.Operator, &out.Operator
+		*out = new(DRClusterOperatorReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OperatorUpgrade != nil {
+		in, out := &in.OperatorUpgrade, &out.OperatorUpgrade
+		*out = new(DRClusterOperatorUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectsRollout != nil {
+		in, out := &in.ObjectsRollout, &out.ObjectsRollout
+		*out = new(DRClusterObjectsRolloutStatus)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Identifier.
-func (in *Identifier) DeepCopy() *Identifier {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRClusterStatus.
+func (in *DRClusterStatus) DeepCopy() *DRClusterStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Identifier)
+	out := new(DRClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeObjectProtectionSpec) DeepCopyInto(out *KubeObjectProtectionSpec) {
+func (in *DRFreeze) DeepCopyInto(out *DRFreeze) {
 	*out = *in
-	if in.CaptureInterval != nil {
-		in, out := &in.CaptureInterval, &out.CaptureInterval
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.RecipeRef != nil {
-		in, out := &in.RecipeRef, &out.RecipeRef
-		*out = new(RecipeRef)
-		**out = **in
-	}
-	if in.RecipeParameters != nil {
-		in, out := &in.RecipeParameters, &out.RecipeParameters
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				inVal := (*in)[key]
-				in, out := &inVal, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.KubeObjectSelector != nil {
-		in, out := &in.KubeObjectSelector, &out.KubeObjectSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.ExemptDRPCs != nil {
+		in, out := &in.ExemptDRPCs, &out.ExemptDRPCs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionSpec.
-func (in *KubeObjectProtectionSpec) DeepCopy() *KubeObjectProtectionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRFreeze.
+func (in *DRFreeze) DeepCopy() *DRFreeze {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeObjectProtectionSpec)
+	out := new(DRFreeze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeObjectProtectionStatus) DeepCopyInto(out *KubeObjectProtectionStatus) {
+func (in *DRPCClusterStatus) DeepCopyInto(out *DRPCClusterStatus) {
 	*out = *in
-	if in.CaptureToRecoverFrom != nil {
-		in, out := &in.CaptureToRecoverFrom, &out.CaptureToRecoverFrom
-		*out = new(KubeObjectsCaptureIdentifier)
-		(*in).DeepCopyInto(*out)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastHeard != nil {
+		in, out := &in.LastHeard, &out.LastHeard
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionStatus.
-func (in *KubeObjectProtectionStatus) DeepCopy() *KubeObjectProtectionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPCClusterStatus.
+func (in *DRPCClusterStatus) DeepCopy() *DRPCClusterStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeObjectProtectionStatus)
+	out := new(DRPCClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeObjectsCaptureIdentifier) DeepCopyInto(out *KubeObjectsCaptureIdentifier) {
+func (in *DRPCReference) DeepCopyInto(out *DRPCReference) {
 	*out = *in
-	in.StartTime.DeepCopyInto(&out.StartTime)
-	in.EndTime.DeepCopyInto(&out.EndTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureIdentifier.
-func (in *KubeObjectsCaptureIdentifier) DeepCopy() *KubeObjectsCaptureIdentifier {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPCReference.
+func (in *DRPCReference) DeepCopy() *DRPCReference {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeObjectsCaptureIdentifier)
+	out := new(DRPCReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceMode) DeepCopyInto(out *MaintenanceMode) {
+func (in *DRPlacementControl) DeepCopyInto(out *DRPlacementControl) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -504,18 +711,18 @@ func (in *MaintenanceMode) DeepCopyInto(out *MaintenanceMode) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceMode.
-func (in *MaintenanceMode) DeepCopy() *MaintenanceMode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControl.
+func (in *DRPlacementControl) DeepCopy() *DRPlacementControl {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceMode)
+	out := new(DRPlacementControl)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaintenanceMode) DeepCopyObject() runtime.Object {
+func (in *DRPlacementControl) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -523,31 +730,31 @@ func (in *MaintenanceMode) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeList) DeepCopyInto(out *MaintenanceModeList) {
+func (in *DRPlacementControlList) DeepCopyInto(out *DRPlacementControlList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MaintenanceMode, len(*in))
+		*out = make([]DRPlacementControl, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeList.
-func (in *MaintenanceModeList) DeepCopy() *MaintenanceModeList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlList.
+func (in *DRPlacementControlList) DeepCopy() *DRPlacementControlList {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeList)
+	out := new(DRPlacementControlList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaintenanceModeList) DeepCopyObject() runtime.Object {
+func (in *DRPlacementControlList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -555,28 +762,70 @@ func (in *MaintenanceModeList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeSpec) DeepCopyInto(out *MaintenanceModeSpec) {
+func (in *DRPlacementControlSpec) DeepCopyInto(out *DRPlacementControlSpec) {
 	*out = *in
-	if in.Modes != nil {
-		in, out := &in.Modes, &out.Modes
-		*out = make([]MMode, len(*in))
-		copy(*out, *in)
+	out.PlacementRef = in.PlacementRef
+	if in.ProtectedNamespaces != nil {
+		in, out := &in.ProtectedNamespaces, &out.ProtectedNamespaces
+		*out = new([]string)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]string, len(*in))
+			copy(*out, *in)
+		}
+	}
+	out.DRPolicyRef = in.DRPolicyRef
+	in.PVCSelector.DeepCopyInto(&out.PVCSelector)
+	if in.KubeObjectProtection != nil {
+		in, out := &in.KubeObjectProtection, &out.KubeObjectProtection
+		*out = new(KubeObjectProtectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VRGMetadata != nil {
+		in, out := &in.VRGMetadata, &out.VRGMetadata
+		*out = new(VRGMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HelperPodScheduling != nil {
+		in, out := &in.HelperPodScheduling, &out.HelperPodScheduling
+		*out = new(HelperPodSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VolSyncMoverConfig != nil {
+		in, out := &in.VolSyncMoverConfig, &out.VolSyncMoverConfig
+		*out = new(VolSyncMoverConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActionTimeouts != nil {
+		in, out := &in.ActionTimeouts, &out.ActionTimeouts
+		*out = new(DRActionTimeouts)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeSpec.
-func (in *MaintenanceModeSpec) DeepCopy() *MaintenanceModeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlSpec.
+func (in *DRPlacementControlSpec) DeepCopy() *DRPlacementControlSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeSpec)
+	out := new(DRPlacementControlSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeStatus) DeepCopyInto(out *MaintenanceModeStatus) {
+func (in *DRPlacementControlStatus) DeepCopyInto(out *DRPlacementControlStatus) {
 	*out = *in
+	if in.ActionStartTime != nil {
+		in, out := &in.ActionStartTime, &out.ActionStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActionDuration != nil {
+		in, out := &in.ActionDuration, &out.ActionDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	out.PreferredDecision = in.PreferredDecision
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -584,62 +833,179 @@ func (in *MaintenanceModeStatus) DeepCopyInto(out *MaintenanceModeStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.ResourceConditions.DeepCopyInto(&out.ResourceConditions)
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]DRPCClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastGroupSyncTime != nil {
+		in, out := &in.LastGroupSyncTime, &out.LastGroupSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastGroupSyncDuration != nil {
+		in, out := &in.LastGroupSyncDuration, &out.LastGroupSyncDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LastGroupSyncBytes != nil {
+		in, out := &in.LastGroupSyncBytes, &out.LastGroupSyncBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LastKubeObjectProtectionTime != nil {
+		in, out := &in.LastKubeObjectProtectionTime, &out.LastKubeObjectProtectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailoverPreparation != nil {
+		in, out := &in.FailoverPreparation, &out.FailoverPreparation
+		*out = new(FailoverPreparationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadRequests != nil {
+		in, out := &in.WorkloadRequests, &out.WorkloadRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ReadinessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverPlan != nil {
+		in, out := &in.FailoverPlan, &out.FailoverPlan
+		*out = new(FailoverPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StateGenerations != nil {
+		in, out := &in.StateGenerations, &out.StateGenerations
+		*out = new(StateGenerationsListing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestorePreview != nil {
+		in, out := &in.RestorePreview, &out.RestorePreview
+		*out = new(RestorePreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Runbook != nil {
+		in, out := &in.Runbook, &out.Runbook
+		*out = new(RunbookStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeStatus.
-func (in *MaintenanceModeStatus) DeepCopy() *MaintenanceModeStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPlacementControlStatus.
+func (in *DRPlacementControlStatus) DeepCopy() *DRPlacementControlStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeStatus)
+	out := new(DRPlacementControlStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PlacementDecision) DeepCopyInto(out *PlacementDecision) {
+func (in *DRPolicy) DeepCopyInto(out *DRPolicy) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementDecision.
-func (in *PlacementDecision) DeepCopy() *PlacementDecision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicy.
+func (in *DRPolicy) DeepCopy() *DRPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(PlacementDecision)
+	out := new(DRPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DRPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProtectedPVC) DeepCopyInto(out *ProtectedPVC) {
+func (in *DRPolicyList) DeepCopyInto(out *DRPolicyList) {
 	*out = *in
-	in.StorageIdentifiers.DeepCopyInto(&out.StorageIdentifiers)
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
-		**out = **in
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DRPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicyList.
+func (in *DRPolicyList) DeepCopy() *DRPolicyList {
+	if in == nil {
+		return nil
 	}
-	if in.AccessModes != nil {
-		in, out := &in.AccessModes, &out.AccessModes
-		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+	out := new(DRPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DRPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRPolicySpec) DeepCopyInto(out *DRPolicySpec) {
+	*out = *in
+	in.ReplicationClassSelector.DeepCopyInto(&out.ReplicationClassSelector)
+	in.VolumeSnapshotClassSelector.DeepCopyInto(&out.VolumeSnapshotClassSelector)
+	if in.DRClusters != nil {
+		in, out := &in.DRClusters, &out.DRClusters
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
+	if in.VolSyncMoverConfig != nil {
+		in, out := &in.VolSyncMoverConfig, &out.VolSyncMoverConfig
+		*out = new(VolSyncMoverConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActionPermissions != nil {
+		in, out := &in.ActionPermissions, &out.ActionPermissions
+		*out = new(DRActionPermissions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicySpec.
+func (in *DRPolicySpec) DeepCopy() *DRPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DRPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DRPolicyStatus) DeepCopyInto(out *DRPolicyStatus) {
+	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -647,206 +1013,1701 @@ func (in *ProtectedPVC) DeepCopyInto(out *ProtectedPVC) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LastSyncTime != nil {
-		in, out := &in.LastSyncTime, &out.LastSyncTime
-		*out = (*in).DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DRPolicyStatus.
+func (in *DRPolicyStatus) DeepCopy() *DRPolicyStatus {
+	if in == nil {
+		return nil
 	}
-	if in.LastSyncDuration != nil {
-		in, out := &in.LastSyncDuration, &out.LastSyncDuration
+	out := new(DRPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainSuffixTranslation) DeepCopyInto(out *DomainSuffixTranslation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainSuffixTranslation.
+func (in *DomainSuffixTranslation) DeepCopy() *DomainSuffixTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainSuffixTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrClusterObjectsRollout) DeepCopyInto(out *DrClusterObjectsRollout) {
+	*out = *in
+	if in.HealthCheckTimeout != nil {
+		in, out := &in.HealthCheckTimeout, &out.HealthCheckTimeout
 		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.LastSyncBytes != nil {
-		in, out := &in.LastSyncBytes, &out.LastSyncBytes
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrClusterObjectsRollout.
+func (in *DrClusterObjectsRollout) DeepCopy() *DrClusterObjectsRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(DrClusterObjectsRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrClusterOperatorAddOn) DeepCopyInto(out *DrClusterOperatorAddOn) {
+	*out = *in
+	if in.Placements != nil {
+		in, out := &in.Placements, &out.Placements
+		*out = make([]DrClusterOperatorAddOnPlacement, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrClusterOperatorAddOn.
+func (in *DrClusterOperatorAddOn) DeepCopy() *DrClusterOperatorAddOn {
+	if in == nil {
+		return nil
+	}
+	out := new(DrClusterOperatorAddOn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrClusterOperatorAddOnPlacement) DeepCopyInto(out *DrClusterOperatorAddOnPlacement) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrClusterOperatorAddOnPlacement.
+func (in *DrClusterOperatorAddOnPlacement) DeepCopy() *DrClusterOperatorAddOnPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(DrClusterOperatorAddOnPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DrClusterOperatorUpgrade) DeepCopyInto(out *DrClusterOperatorUpgrade) {
+	*out = *in
+	if in.CanaryClusterNames != nil {
+		in, out := &in.CanaryClusterNames, &out.CanaryClusterNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheckTimeout != nil {
+		in, out := &in.HealthCheckTimeout, &out.HealthCheckTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrClusterOperatorUpgrade.
+func (in *DrClusterOperatorUpgrade) DeepCopy() *DrClusterOperatorUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(DrClusterOperatorUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverPlan) DeepCopyInto(out *FailoverPlan) {
+	*out = *in
+	if in.PlanTime != nil {
+		in, out := &in.PlanTime, &out.PlanTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]FailoverPlanStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EstimatedDataBytes != nil {
+		in, out := &in.EstimatedDataBytes, &out.EstimatedDataBytes
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Blockers != nil {
+		in, out := &in.Blockers, &out.Blockers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPVC.
-func (in *ProtectedPVC) DeepCopy() *ProtectedPVC {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPlan.
+func (in *FailoverPlan) DeepCopy() *FailoverPlan {
 	if in == nil {
 		return nil
 	}
-	out := new(ProtectedPVC)
+	out := new(FailoverPlan)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProtectedVolumeReplicationGroupList) DeepCopyInto(out *ProtectedVolumeReplicationGroupList) {
+func (in *FailoverPlanStep) DeepCopyInto(out *FailoverPlanStep) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(ProtectedVolumeReplicationGroupListStatus)
-		(*in).DeepCopyInto(*out)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupList.
-func (in *ProtectedVolumeReplicationGroupList) DeepCopy() *ProtectedVolumeReplicationGroupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPlanStep.
+func (in *FailoverPlanStep) DeepCopy() *FailoverPlanStep {
 	if in == nil {
 		return nil
 	}
-	out := new(ProtectedVolumeReplicationGroupList)
+	out := new(FailoverPlanStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProtectedVolumeReplicationGroupList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailoverPreparationSpec) DeepCopyInto(out *FailoverPreparationSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	if in.WindowsImages != nil {
+		in, out := &in.WindowsImages, &out.WindowsImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPreparationSpec.
+func (in *FailoverPreparationSpec) DeepCopy() *FailoverPreparationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverPreparationSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProtectedVolumeReplicationGroupListList) DeepCopyInto(out *ProtectedVolumeReplicationGroupListList) {
+func (in *FailoverPreparationStatus) DeepCopyInto(out *FailoverPreparationStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ProtectedVolumeReplicationGroupList, len(*in))
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverPreparationStatus.
+func (in *FailoverPreparationStatus) DeepCopy() *FailoverPreparationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FailoverPreparationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayClassTranslation) DeepCopyInto(out *GatewayClassTranslation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayClassTranslation.
+func (in *GatewayClassTranslation) DeepCopy() *GatewayClassTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayClassTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelperPodSchedulingSpec) DeepCopyInto(out *HelperPodSchedulingSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListList.
-func (in *ProtectedVolumeReplicationGroupListList) DeepCopy() *ProtectedVolumeReplicationGroupListList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelperPodSchedulingSpec.
+func (in *HelperPodSchedulingSpec) DeepCopy() *HelperPodSchedulingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelperPodSchedulingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Identifier) DeepCopyInto(out *Identifier) {
+	*out = *in
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make([]MMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Identifier.
+func (in *Identifier) DeepCopy() *Identifier {
+	if in == nil {
+		return nil
+	}
+	out := new(Identifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageRegistryMirror) DeepCopyInto(out *ImageRegistryMirror) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageRegistryMirror.
+func (in *ImageRegistryMirror) DeepCopy() *ImageRegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageRegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectProtectionSpec) DeepCopyInto(out *KubeObjectProtectionSpec) {
+	*out = *in
+	if in.CaptureInterval != nil {
+		in, out := &in.CaptureInterval, &out.CaptureInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RecipeRef != nil {
+		in, out := &in.RecipeRef, &out.RecipeRef
+		*out = new(RecipeRef)
+		**out = **in
+	}
+	if in.RecipeParameters != nil {
+		in, out := &in.RecipeParameters, &out.RecipeParameters
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.KubeObjectSelector != nil {
+		in, out := &in.KubeObjectSelector, &out.KubeObjectSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceOverrides != nil {
+		in, out := &in.ServiceOverrides, &out.ServiceOverrides
+		*out = make([]ServiceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(KubeObjectVeleroSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyExemptions != nil {
+		in, out := &in.PolicyExemptions, &out.PolicyExemptions
+		*out = make([]PolicyExemption, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionSpec.
+func (in *KubeObjectProtectionSpec) DeepCopy() *KubeObjectProtectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectProtectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectProtectionStatus) DeepCopyInto(out *KubeObjectProtectionStatus) {
+	*out = *in
+	if in.CaptureToRecoverFrom != nil {
+		in, out := &in.CaptureToRecoverFrom, &out.CaptureToRecoverFrom
+		*out = new(KubeObjectsCaptureIdentifier)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CaptureInProgress != nil {
+		in, out := &in.CaptureInProgress, &out.CaptureInProgress
+		*out = new(KubeObjectsCaptureIdentifier)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastCaptureManifest != nil {
+		in, out := &in.LastCaptureManifest, &out.LastCaptureManifest
+		*out = new(KubeObjectsCaptureManifestSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestorePreview != nil {
+		in, out := &in.RestorePreview, &out.RestorePreview
+		*out = new(KubeObjectsRestorePreview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyExemptionsApplied != nil {
+		in, out := &in.PolicyExemptionsApplied, &out.PolicyExemptionsApplied
+		*out = (*in).DeepCopy()
+	}
+	if in.HookJobs != nil {
+		in, out := &in.HookJobs, &out.HookJobs
+		*out = make([]KubeObjectsHookJobStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HookCalls != nil {
+		in, out := &in.HookCalls, &out.HookCalls
+		*out = make([]KubeObjectsHookCallStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectProtectionStatus.
+func (in *KubeObjectProtectionStatus) DeepCopy() *KubeObjectProtectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectProtectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectVeleroSpec) DeepCopyInto(out *KubeObjectVeleroSpec) {
+	*out = *in
+	if in.BackupTTL != nil {
+		in, out := &in.BackupTTL, &out.BackupTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectVeleroSpec.
+func (in *KubeObjectVeleroSpec) DeepCopy() *KubeObjectVeleroSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectVeleroSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsCaptureGroupIdentifier) DeepCopyInto(out *KubeObjectsCaptureGroupIdentifier) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureGroupIdentifier.
+func (in *KubeObjectsCaptureGroupIdentifier) DeepCopy() *KubeObjectsCaptureGroupIdentifier {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsCaptureGroupIdentifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsCaptureIdentifier) DeepCopyInto(out *KubeObjectsCaptureIdentifier) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]KubeObjectsCaptureGroupIdentifier, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureIdentifier.
+func (in *KubeObjectsCaptureIdentifier) DeepCopy() *KubeObjectsCaptureIdentifier {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsCaptureIdentifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsCaptureManifestSummary) DeepCopyInto(out *KubeObjectsCaptureManifestSummary) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsCaptureManifestSummary.
+func (in *KubeObjectsCaptureManifestSummary) DeepCopy() *KubeObjectsCaptureManifestSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsCaptureManifestSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsHookCallStatus) DeepCopyInto(out *KubeObjectsHookCallStatus) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsHookCallStatus.
+func (in *KubeObjectsHookCallStatus) DeepCopy() *KubeObjectsHookCallStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsHookCallStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsHookJobStatus) DeepCopyInto(out *KubeObjectsHookJobStatus) {
+	*out = *in
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsHookJobStatus.
+func (in *KubeObjectsHookJobStatus) DeepCopy() *KubeObjectsHookJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsHookJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsRestoreConflict) DeepCopyInto(out *KubeObjectsRestoreConflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsRestoreConflict.
+func (in *KubeObjectsRestoreConflict) DeepCopy() *KubeObjectsRestoreConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsRestoreConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeObjectsRestorePreview) DeepCopyInto(out *KubeObjectsRestorePreview) {
+	*out = *in
+	if in.PreviewTime != nil {
+		in, out := &in.PreviewTime, &out.PreviewTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]KubeObjectsRestoreConflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeObjectsRestorePreview.
+func (in *KubeObjectsRestorePreview) DeepCopy() *KubeObjectsRestorePreview {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeObjectsRestorePreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionGroup) DeepCopyInto(out *LeaderElectionGroup) {
+	*out = *in
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionGroup.
+func (in *LeaderElectionGroup) DeepCopy() *LeaderElectionGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogConfig) DeepCopyInto(out *LogConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogConfig.
+func (in *LogConfig) DeepCopy() *LogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceMode) DeepCopyInto(out *MaintenanceMode) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceMode.
+func (in *MaintenanceMode) DeepCopy() *MaintenanceMode {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceMode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceMode) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeList) DeepCopyInto(out *MaintenanceModeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaintenanceMode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeList.
+func (in *MaintenanceModeList) DeepCopy() *MaintenanceModeList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceModeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeSpec) DeepCopyInto(out *MaintenanceModeSpec) {
+	*out = *in
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make([]MMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeSpec.
+func (in *MaintenanceModeSpec) DeepCopy() *MaintenanceModeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeStatus) DeepCopyInto(out *MaintenanceModeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeStatus.
+func (in *MaintenanceModeStatus) DeepCopy() *MaintenanceModeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocate) DeepCopyInto(out *MaintenanceRelocate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocate.
+func (in *MaintenanceRelocate) DeepCopy() *MaintenanceRelocate {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceRelocate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocateDRPCStatus) DeepCopyInto(out *MaintenanceRelocateDRPCStatus) {
+	*out = *in
+	out.DRPCReference = in.DRPCReference
+	if in.RelocatedTime != nil {
+		in, out := &in.RelocatedTime, &out.RelocatedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReturnedTime != nil {
+		in, out := &in.ReturnedTime, &out.ReturnedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocateDRPCStatus.
+func (in *MaintenanceRelocateDRPCStatus) DeepCopy() *MaintenanceRelocateDRPCStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocateDRPCStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocateList) DeepCopyInto(out *MaintenanceRelocateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaintenanceRelocate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocateList.
+func (in *MaintenanceRelocateList) DeepCopy() *MaintenanceRelocateList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaintenanceRelocateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocateSpec) DeepCopyInto(out *MaintenanceRelocateSpec) {
+	*out = *in
+	if in.DRPCs != nil {
+		in, out := &in.DRPCs, &out.DRPCs
+		*out = make([]DRPCReference, len(*in))
+		copy(*out, *in)
+	}
+	in.WindowStart.DeepCopyInto(&out.WindowStart)
+	in.WindowEnd.DeepCopyInto(&out.WindowEnd)
+	if in.LeadTime != nil {
+		in, out := &in.LeadTime, &out.LeadTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocateSpec.
+func (in *MaintenanceRelocateSpec) DeepCopy() *MaintenanceRelocateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocateStatus) DeepCopyInto(out *MaintenanceRelocateStatus) {
+	*out = *in
+	if in.DRPCs != nil {
+		in, out := &in.DRPCs, &out.DRPCs
+		*out = make([]MaintenanceRelocateDRPCStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Summary = in.Summary
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocateStatus.
+func (in *MaintenanceRelocateStatus) DeepCopy() *MaintenanceRelocateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceRelocateSummary) DeepCopyInto(out *MaintenanceRelocateSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceRelocateSummary.
+func (in *MaintenanceRelocateSummary) DeepCopy() *MaintenanceRelocateSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceRelocateSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceResourceQuota) DeepCopyInto(out *NamespaceResourceQuota) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceResourceQuota.
+func (in *NamespaceResourceQuota) DeepCopy() *NamespaceResourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceResourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSizing) DeepCopyInto(out *NamespaceSizing) {
+	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ContainerMaximums != nil {
+		in, out := &in.ContainerMaximums, &out.ContainerMaximums
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.PersistentVolumeClaimMaximums != nil {
+		in, out := &in.PersistentVolumeClaimMaximums, &out.PersistentVolumeClaimMaximums
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.ResourceQuotas != nil {
+		in, out := &in.ResourceQuotas, &out.ResourceQuotas
+		*out = make([]NamespaceResourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSizing.
+func (in *NamespaceSizing) DeepCopy() *NamespaceSizing {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSizing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkAttachmentMapping) DeepCopyInto(out *NetworkAttachmentMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkAttachmentMapping.
+func (in *NetworkAttachmentMapping) DeepCopy() *NetworkAttachmentMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkAttachmentMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(SMTPNotificationSink)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notifications) DeepCopyInto(out *Notifications) {
+	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notifications.
+func (in *Notifications) DeepCopy() *Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementDecision) DeepCopyInto(out *PlacementDecision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementDecision.
+func (in *PlacementDecision) DeepCopy() *PlacementDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyExemption) DeepCopyInto(out *PolicyExemption) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyExemption.
+func (in *PolicyExemption) DeepCopy() *PolicyExemption {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyExemption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Profiling) DeepCopyInto(out *Profiling) {
+	*out = *in
+	if in.CaptureThreshold != nil {
+		in, out := &in.CaptureThreshold, &out.CaptureThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CheckInterval != nil {
+		in, out := &in.CheckInterval, &out.CheckInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinCaptureInterval != nil {
+		in, out := &in.MinCaptureInterval, &out.MinCaptureInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CPUProfileDuration != nil {
+		in, out := &in.CPUProfileDuration, &out.CPUProfileDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profiling.
+func (in *Profiling) DeepCopy() *Profiling {
+	if in == nil {
+		return nil
+	}
+	out := new(Profiling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedPVC) DeepCopyInto(out *ProtectedPVC) {
+	*out = *in
+	in.StorageIdentifiers.DeepCopyInto(&out.StorageIdentifiers)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AccessModes != nil {
+		in, out := &in.AccessModes, &out.AccessModes
+		*out = make([]corev1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSyncDuration != nil {
+		in, out := &in.LastSyncDuration, &out.LastSyncDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.LastSyncBytes != nil {
+		in, out := &in.LastSyncBytes, &out.LastSyncBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedPVC.
+func (in *ProtectedPVC) DeepCopy() *ProtectedPVC {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedPVC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedVolumeReplicationGroupList) DeepCopyInto(out *ProtectedVolumeReplicationGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(ProtectedVolumeReplicationGroupListStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupList.
+func (in *ProtectedVolumeReplicationGroupList) DeepCopy() *ProtectedVolumeReplicationGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedVolumeReplicationGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedVolumeReplicationGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedVolumeReplicationGroupListList) DeepCopyInto(out *ProtectedVolumeReplicationGroupListList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProtectedVolumeReplicationGroupList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListList.
+func (in *ProtectedVolumeReplicationGroupListList) DeepCopy() *ProtectedVolumeReplicationGroupListList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedVolumeReplicationGroupListList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProtectedVolumeReplicationGroupListList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedVolumeReplicationGroupListSpec) DeepCopyInto(out *ProtectedVolumeReplicationGroupListSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListSpec.
+func (in *ProtectedVolumeReplicationGroupListSpec) DeepCopy() *ProtectedVolumeReplicationGroupListSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedVolumeReplicationGroupListSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtectedVolumeReplicationGroupListStatus) DeepCopyInto(out *ProtectedVolumeReplicationGroupListStatus) {
+	*out = *in
+	in.SampleTime.DeepCopyInto(&out.SampleTime)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VolumeReplicationGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListStatus.
+func (in *ProtectedVolumeReplicationGroupListStatus) DeepCopy() *ProtectedVolumeReplicationGroupListStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtectedVolumeReplicationGroupListStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RamenConfig) DeepCopyInto(out *RamenConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ControllerManagerConfigurationSpec.DeepCopyInto(&out.ControllerManagerConfigurationSpec)
+	if in.S3StoreProfiles != nil {
+		in, out := &in.S3StoreProfiles, &out.S3StoreProfiles
+		*out = make([]S3StoreProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.DrClusterOperator = in.DrClusterOperator
+	out.VolSync = in.VolSync
+	out.KubeObjectProtection = in.KubeObjectProtection
+	if in.VeleroItemActionConfigs != nil {
+		in, out := &in.VeleroItemActionConfigs, &out.VeleroItemActionConfigs
+		*out = make([]VeleroItemActionConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.MultiNamespace = in.MultiNamespace
+	out.FinalizerTimeout = in.FinalizerTimeout
+	out.FailoverCapacityCheck = in.FailoverCapacityCheck
+	out.ProtectionCapacityCheck = in.ProtectionCapacityCheck
+	out.FailureDomainCheck = in.FailureDomainCheck
+	out.Standalone = in.Standalone
+	out.HealthReport = in.HealthReport
+	if in.DrClusterOperatorUpgrade != nil {
+		in, out := &in.DrClusterOperatorUpgrade, &out.DrClusterOperatorUpgrade
+		*out = new(DrClusterOperatorUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrClusterObjectsRollout != nil {
+		in, out := &in.DrClusterObjectsRollout, &out.DrClusterObjectsRollout
+		*out = new(DrClusterObjectsRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrClusterOperatorAddOn != nil {
+		in, out := &in.DrClusterOperatorAddOn, &out.DrClusterOperatorAddOn
+		*out = new(DrClusterOperatorAddOn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrClusterOperatorResources != nil {
+		in, out := &in.DrClusterOperatorResources, &out.DrClusterOperatorResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArrayReplicationPlugins != nil {
+		in, out := &in.ArrayReplicationPlugins, &out.ArrayReplicationPlugins
+		*out = make([]ArrayReplicationPlugin, len(*in))
+		copy(*out, *in)
+	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(LogConfig)
+		**out = **in
+	}
+	if in.DRFreeze != nil {
+		in, out := &in.DRFreeze, &out.DRFreeze
+		*out = new(DRFreeze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyExemptionsAllowed != nil {
+		in, out := &in.PolicyExemptionsAllowed, &out.PolicyExemptionsAllowed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyExemptionsTimeout != nil {
+		in, out := &in.PolicyExemptionsTimeout, &out.PolicyExemptionsTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(Notifications)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiling != nil {
+		in, out := &in.Profiling, &out.Profiling
+		*out = new(Profiling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeaderElectionGroups != nil {
+		in, out := &in.LeaderElectionGroups, &out.LeaderElectionGroups
+		*out = make([]LeaderElectionGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UploadRedactions != nil {
+		in, out := &in.UploadRedactions, &out.UploadRedactions
+		*out = make([]UploadRedaction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RamenConfig.
+func (in *RamenConfig) DeepCopy() *RamenConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RamenConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RamenConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
+func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessStatus) DeepCopyInto(out *ReadinessStatus) {
+	*out = *in
+	if in.CheckTime != nil {
+		in, out := &in.CheckTime, &out.CheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ReadinessCheck, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessStatus.
+func (in *ReadinessStatus) DeepCopy() *ReadinessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecipeRef) DeepCopyInto(out *RecipeRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecipeRef.
+func (in *RecipeRef) DeepCopy() *RecipeRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RecipeRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestorePreview) DeepCopyInto(out *RestorePreview) {
+	*out = *in
+	in.KubeObjectsRestorePreview.DeepCopyInto(&out.KubeObjectsRestorePreview)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestorePreview.
+func (in *RestorePreview) DeepCopy() *RestorePreview {
+	if in == nil {
+		return nil
+	}
+	out := new(RestorePreview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunbookStatus) DeepCopyInto(out *RunbookStatus) {
+	*out = *in
+	if in.ExportTime != nil {
+		in, out := &in.ExportTime, &out.ExportTime
+		*out = (*in).DeepCopy()
+	}
+	if in.S3ProfileNames != nil {
+		in, out := &in.S3ProfileNames, &out.S3ProfileNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunbookStatus.
+func (in *RunbookStatus) DeepCopy() *RunbookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RunbookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3OIDCProfile) DeepCopyInto(out *S3OIDCProfile) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RefreshBefore != nil {
+		in, out := &in.RefreshBefore, &out.RefreshBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3OIDCProfile.
+func (in *S3OIDCProfile) DeepCopy() *S3OIDCProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(S3OIDCProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3StoreProfile) DeepCopyInto(out *S3StoreProfile) {
+	*out = *in
+	out.S3SecretRef = in.S3SecretRef
+	if in.VeleroNamespaceSecretKeyRef != nil {
+		in, out := &in.VeleroNamespaceSecretKeyRef, &out.VeleroNamespaceSecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CACertificates != nil {
+		in, out := &in.CACertificates, &out.CACertificates
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(S3OIDCProfile)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StoreProfile.
+func (in *S3StoreProfile) DeepCopy() *S3StoreProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(S3StoreProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3TransferStatus) DeepCopyInto(out *S3TransferStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3TransferStatus.
+func (in *S3TransferStatus) DeepCopy() *S3TransferStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(S3TransferStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMTPNotificationSink) DeepCopyInto(out *SMTPNotificationSink) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTPNotificationSink.
+func (in *SMTPNotificationSink) DeepCopy() *SMTPNotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(SMTPNotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretRewrite) DeepCopyInto(out *SecretRewrite) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretRewrite.
+func (in *SecretRewrite) DeepCopy() *SecretRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceOverride) DeepCopyInto(out *ServiceOverride) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerClass != nil {
+		in, out := &in.LoadBalancerClass, &out.LoadBalancerClass
+		*out = new(string)
+		**out = **in
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceOverride.
+func (in *ServiceOverride) DeepCopy() *ServiceOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(ProtectedVolumeReplicationGroupListList)
+	out := new(ServiceOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ProtectedVolumeReplicationGroupListList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProtectedVolumeReplicationGroupListSpec) DeepCopyInto(out *ProtectedVolumeReplicationGroupListSpec) {
+func (in *SkippedPVC) DeepCopyInto(out *SkippedPVC) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListSpec.
-func (in *ProtectedVolumeReplicationGroupListSpec) DeepCopy() *ProtectedVolumeReplicationGroupListSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SkippedPVC.
+func (in *SkippedPVC) DeepCopy() *SkippedPVC {
 	if in == nil {
 		return nil
 	}
-	out := new(ProtectedVolumeReplicationGroupListSpec)
+	out := new(SkippedPVC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProtectedVolumeReplicationGroupListStatus) DeepCopyInto(out *ProtectedVolumeReplicationGroupListStatus) {
+func (in *StateGeneration) DeepCopyInto(out *StateGeneration) {
 	*out = *in
-	in.SampleTime.DeepCopyInto(&out.SampleTime)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]VolumeReplicationGroup, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastModified != nil {
+		in, out := &in.LastModified, &out.LastModified
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtectedVolumeReplicationGroupListStatus.
-func (in *ProtectedVolumeReplicationGroupListStatus) DeepCopy() *ProtectedVolumeReplicationGroupListStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateGeneration.
+func (in *StateGeneration) DeepCopy() *StateGeneration {
 	if in == nil {
 		return nil
 	}
-	out := new(ProtectedVolumeReplicationGroupListStatus)
+	out := new(StateGeneration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RamenConfig) DeepCopyInto(out *RamenConfig) {
+func (in *StateGenerationsListing) DeepCopyInto(out *StateGenerationsListing) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ControllerManagerConfigurationSpec.DeepCopyInto(&out.ControllerManagerConfigurationSpec)
-	if in.S3StoreProfiles != nil {
-		in, out := &in.S3StoreProfiles, &out.S3StoreProfiles
-		*out = make([]S3StoreProfile, len(*in))
+	if in.ListTime != nil {
+		in, out := &in.ListTime, &out.ListTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Generations != nil {
+		in, out := &in.Generations, &out.Generations
+		*out = make([]StateGeneration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.DrClusterOperator = in.DrClusterOperator
-	out.VolSync = in.VolSync
-	out.KubeObjectProtection = in.KubeObjectProtection
-	out.MultiNamespace = in.MultiNamespace
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RamenConfig.
-func (in *RamenConfig) DeepCopy() *RamenConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateGenerationsListing.
+func (in *StateGenerationsListing) DeepCopy() *StateGenerationsListing {
 	if in == nil {
 		return nil
 	}
-	out := new(RamenConfig)
+	out := new(StateGenerationsListing)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RamenConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RecipeRef) DeepCopyInto(out *RecipeRef) {
+func (in *StorageClassCapacity) DeepCopyInto(out *StorageClassCapacity) {
 	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	out.LargestVolumeSize = in.LargestVolumeSize.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecipeRef.
-func (in *RecipeRef) DeepCopy() *RecipeRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassCapacity.
+func (in *StorageClassCapacity) DeepCopy() *StorageClassCapacity {
 	if in == nil {
 		return nil
 	}
-	out := new(RecipeRef)
+	out := new(StorageClassCapacity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3StoreProfile) DeepCopyInto(out *S3StoreProfile) {
+func (in *StorageClassMapping) DeepCopyInto(out *StorageClassMapping) {
 	*out = *in
-	out.S3SecretRef = in.S3SecretRef
-	if in.VeleroNamespaceSecretKeyRef != nil {
-		in, out := &in.VeleroNamespaceSecretKeyRef, &out.VeleroNamespaceSecretKeyRef
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.CACertificates != nil {
-		in, out := &in.CACertificates, &out.CACertificates
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StoreProfile.
-func (in *S3StoreProfile) DeepCopy() *S3StoreProfile {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageClassMapping.
+func (in *StorageClassMapping) DeepCopy() *StorageClassMapping {
 	if in == nil {
 		return nil
 	}
-	out := new(S3StoreProfile)
+	out := new(StorageClassMapping)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -868,6 +2729,41 @@ func (in *StorageIdentifiers) DeepCopy() *StorageIdentifiers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyTranslation) DeepCopyInto(out *TopologyTranslation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyTranslation.
+func (in *TopologyTranslation) DeepCopy() *TopologyTranslation {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyTranslation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UploadRedaction) DeepCopyInto(out *UploadRedaction) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UploadRedaction.
+func (in *UploadRedaction) DeepCopy() *UploadRedaction {
+	if in == nil {
+		return nil
+	}
+	out := new(UploadRedaction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VRGAsyncSpec) DeepCopyInto(out *VRGAsyncSpec) {
 	*out = *in
@@ -908,6 +2804,60 @@ func (in *VRGConditions) DeepCopy() *VRGConditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VRGFailoverPreparationStatus) DeepCopyInto(out *VRGFailoverPreparationStatus) {
+	*out = *in
+	if in.StagedCapture != nil {
+		in, out := &in.StagedCapture, &out.StagedCapture
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ImagesPulled != nil {
+		in, out := &in.ImagesPulled, &out.ImagesPulled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VRGFailoverPreparationStatus.
+func (in *VRGFailoverPreparationStatus) DeepCopy() *VRGFailoverPreparationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VRGFailoverPreparationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VRGMetadata) DeepCopyInto(out *VRGMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VRGMetadata.
+func (in *VRGMetadata) DeepCopy() *VRGMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(VRGMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VRGResourceMeta) DeepCopyInto(out *VRGResourceMeta) {
 	*out = *in
@@ -943,6 +2893,36 @@ func (in *VRGSyncSpec) DeepCopy() *VRGSyncSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolSyncMoverConfig) DeepCopyInto(out *VolSyncMoverConfig) {
+	*out = *in
+	if in.StorageClassNames != nil {
+		in, out := &in.StorageClassNames, &out.StorageClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Restic != nil {
+		in, out := &in.Restic, &out.Restic
+		*out = new(VolSyncResticConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MoverSecurityContext != nil {
+		in, out := &in.MoverSecurityContext, &out.MoverSecurityContext
+		*out = new(corev1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolSyncMoverConfig.
+func (in *VolSyncMoverConfig) DeepCopy() *VolSyncMoverConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VolSyncMoverConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolSyncReplicationDestinationSpec) DeepCopyInto(out *VolSyncReplicationDestinationSpec) {
 	*out = *in
@@ -975,6 +2955,31 @@ func (in *VolSyncReplicationSourceSpec) DeepCopy() *VolSyncReplicationSourceSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolSyncResticConfig) DeepCopyInto(out *VolSyncResticConfig) {
+	*out = *in
+	if in.CacheCapacity != nil {
+		in, out := &in.CacheCapacity, &out.CacheCapacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PruneIntervalDays != nil {
+		in, out := &in.PruneIntervalDays, &out.PruneIntervalDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolSyncResticConfig.
+func (in *VolSyncResticConfig) DeepCopy() *VolSyncResticConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VolSyncResticConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolSyncSpec) DeepCopyInto(out *VolSyncSpec) {
 	*out = *in
@@ -985,6 +2990,16 @@ func (in *VolSyncSpec) DeepCopyInto(out *VolSyncSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MoverConfig != nil {
+		in, out := &in.MoverConfig, &out.MoverConfig
+		*out = new(VolSyncMoverConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncthingPeers != nil {
+		in, out := &in.SyncthingPeers, &out.SyncthingPeers
+		*out = make([]VolSyncSyncthingDevice, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolSyncSpec.
@@ -997,6 +3012,43 @@ func (in *VolSyncSpec) DeepCopy() *VolSyncSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolSyncSyncthingDevice) DeepCopyInto(out *VolSyncSyncthingDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolSyncSyncthingDevice.
+func (in *VolSyncSyncthingDevice) DeepCopy() *VolSyncSyncthingDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(VolSyncSyncthingDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroItemActionConfig) DeepCopyInto(out *VeleroItemActionConfig) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroItemActionConfig.
+func (in *VeleroItemActionConfig) DeepCopy() *VeleroItemActionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroItemActionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeReplicationGroup) DeepCopyInto(out *VolumeReplicationGroup) {
 	*out = *in
@@ -1090,6 +3142,62 @@ func (in *VolumeReplicationGroupSpec) DeepCopyInto(out *VolumeReplicationGroupSp
 			copy(*out, *in)
 		}
 	}
+	if in.HelperPodScheduling != nil {
+		in, out := &in.HelperPodScheduling, &out.HelperPodScheduling
+		*out = new(HelperPodSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRegistryMirrors != nil {
+		in, out := &in.ImageRegistryMirrors, &out.ImageRegistryMirrors
+		*out = make([]ImageRegistryMirror, len(*in))
+		copy(*out, *in)
+	}
+	if in.TopologyTranslations != nil {
+		in, out := &in.TopologyTranslations, &out.TopologyTranslations
+		*out = make([]TopologyTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.StorageClassMappings != nil {
+		in, out := &in.StorageClassMappings, &out.StorageClassMappings
+		*out = make([]StorageClassMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AccessModeMappings != nil {
+		in, out := &in.AccessModeMappings, &out.AccessModeMappings
+		*out = make([]AccessModeMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DomainSuffixTranslations != nil {
+		in, out := &in.DomainSuffixTranslations, &out.DomainSuffixTranslations
+		*out = make([]DomainSuffixTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.GatewayClassTranslations != nil {
+		in, out := &in.GatewayClassTranslations, &out.GatewayClassTranslations
+		*out = make([]GatewayClassTranslation, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRewrites != nil {
+		in, out := &in.SecretRewrites, &out.SecretRewrites
+		*out = make([]SecretRewrite, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkAttachmentMappings != nil {
+		in, out := &in.NetworkAttachmentMappings, &out.NetworkAttachmentMappings
+		*out = make([]NetworkAttachmentMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrepareForFailover != nil {
+		in, out := &in.PrepareForFailover, &out.PrepareForFailover
+		*out = new(FailoverPreparationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeReplicationGroupSpec.
@@ -1135,6 +3243,55 @@ func (in *VolumeReplicationGroupStatus) DeepCopyInto(out *VolumeReplicationGroup
 		*out = new(int64)
 		**out = **in
 	}
+	if in.SkippedPVCs != nil {
+		in, out := &in.SkippedPVCs, &out.SkippedPVCs
+		*out = make([]SkippedPVC, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadRequests != nil {
+		in, out := &in.WorkloadRequests, &out.WorkloadRequests
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NamespaceSizings != nil {
+		in, out := &in.NamespaceSizings, &out.NamespaceSizings
+		*out = make([]NamespaceSizing, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.S3Transfer != nil {
+		in, out := &in.S3Transfer, &out.S3Transfer
+		*out = new(S3TransferStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncthingDevices != nil {
+		in, out := &in.SyncthingDevices, &out.SyncthingDevices
+		*out = make([]VolSyncSyncthingDevice, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadImages != nil {
+		in, out := &in.WorkloadImages, &out.WorkloadImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkloadWindowsImages != nil {
+		in, out := &in.WorkloadWindowsImages, &out.WorkloadWindowsImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailoverPreparation != nil {
+		in, out := &in.FailoverPreparation, &out.FailoverPreparation
+		*out = new(VRGFailoverPreparationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterDataDrift != nil {
+		in, out := &in.ClusterDataDrift, &out.ClusterDataDrift
+		*out = new(ClusterDataDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeReplicationGroupStatus.
@@ -0,0 +1,13 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rbac embeds the roles of the dr-cluster operator, as generated by controller-gen, for the hub operator to
+// deploy them to the clusters without OLM
+package rbac
+
+import _ "embed"
+
+// Roles are the ClusterRole and Role of the dr-cluster operator, in yaml
+//
+//go:embed role.yaml
+var Roles []byte
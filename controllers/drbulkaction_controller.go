@@ -0,0 +1,408 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// drBulkActionMaxConcurrencyDefault is the maximum number of DRPCs whose action is in progress at once
+	drBulkActionMaxConcurrencyDefault = 5
+
+	// drBulkActionPollInterval is how often actions in progress are checked, besides on DRPC updates
+	drBulkActionPollInterval = 30 * time.Second
+)
+
+// DRBulkActionReconciler applies the action of a DRBulkAction to the DRPCs its selector matched when it started,
+// starting no more actions than its maximum concurrency allows at once
+type DRBulkActionReconciler struct {
+	client.Client
+	APIReader     client.Reader
+	Log           logr.Logger
+	eventRecorder *rmnutil.EventReporter
+}
+
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drbulkactions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=drbulkactions/status,verbs=get;update;patch
+
+func (r *DRBulkActionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("DRBulkAction", req.NamespacedName.Name, "rid", uuid.New())
+
+	bulk := &rmn.DRBulkAction{}
+	if err := r.APIReader.Get(ctx, req.NamespacedName, bulk); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !bulk.GetDeletionTimestamp().IsZero() || bulk.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := bulk.Status.DeepCopy()
+
+	if bulk.Status.Phase == "" {
+		if err := r.selectDRPCs(ctx, bulk); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.advance(ctx, bulk, log)
+
+	bulk.Status.Summary = drBulkActionSummary(bulk.Status.DRPCs)
+
+	requeueAfter := drBulkActionPollInterval
+
+	if bulk.Status.Summary.Pending == 0 && bulk.Status.Summary.InProgress == 0 {
+		now := metav1.Now()
+		bulk.Status.CompletionTime = &now
+		bulk.Status.Phase = rmn.DRBulkActionCompleted
+		requeueAfter = 0
+
+		if bulk.Spec.AbortOnError && bulk.Status.Summary.Failed > 0 {
+			bulk.Status.Phase = rmn.DRBulkActionAborted
+		}
+	}
+
+	if !reflect.DeepEqual(status, &bulk.Status) {
+		if err := r.Status().Update(ctx, bulk); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update DRBulkAction %s status (%w)", bulk.Name, err)
+		}
+
+		log.Info("Status updated", "phase", bulk.Status.Phase, "summary", bulk.Status.Summary)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// selectDRPCs lists the DRPCs the selector matches, in the order their actions are started
+func (r *DRBulkActionReconciler) selectDRPCs(ctx context.Context, bulk *rmn.DRBulkAction) error {
+	selector, err := metav1.LabelSelectorAsSelector(&bulk.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("DRBulkAction %s selector invalid (%w)", bulk.Name, err)
+	}
+
+	namespaces := bulk.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	drpcs := []rmn.DRPlacementControl{}
+
+	for _, namespace := range namespaces {
+		list := &rmn.DRPlacementControlList{}
+		if err := r.APIReader.List(ctx, list, client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return fmt.Errorf("failed to list DRPCs in namespace %q (%w)", namespace, err)
+		}
+
+		drpcs = append(drpcs, list.Items...)
+	}
+
+	sort.Slice(drpcs, func(i, j int) bool {
+		if drpcs[i].Namespace != drpcs[j].Namespace {
+			return drpcs[i].Namespace < drpcs[j].Namespace
+		}
+
+		return drpcs[i].Name < drpcs[j].Name
+	})
+
+	now := metav1.Now()
+	bulk.Status.StartTime = &now
+	bulk.Status.Phase = rmn.DRBulkActionRunning
+	bulk.Status.DRPCs = make([]rmn.DRBulkActionDRPCStatus, 0, len(drpcs))
+
+	for i := range drpcs {
+		bulk.Status.DRPCs = append(bulk.Status.DRPCs, rmn.DRBulkActionDRPCStatus{
+			DRPCReference: rmn.DRPCReference{Namespace: drpcs[i].Namespace, Name: drpcs[i].Name},
+			State:         rmn.DRBulkActionDRPCPending,
+		})
+	}
+
+	return nil
+}
+
+// advance checks on the actions in progress, and starts the actions of pending DRPCs up to the maximum
+// concurrency, unless an action failed and the DRBulkAction aborts on error, in which case the pending DRPCs are
+// skipped
+func (r *DRBulkActionReconciler) advance(ctx context.Context, bulk *rmn.DRBulkAction, log logr.Logger) {
+	inProgress, failed := 0, 0
+
+	for i := range bulk.Status.DRPCs {
+		entry := &bulk.Status.DRPCs[i]
+
+		if entry.State == rmn.DRBulkActionDRPCInProgress {
+			r.actionCheck(ctx, bulk, entry)
+		}
+
+		switch entry.State {
+		case rmn.DRBulkActionDRPCInProgress:
+			inProgress++
+		case rmn.DRBulkActionDRPCFailed:
+			failed++
+		}
+	}
+
+	maxConcurrency := bulk.Spec.MaxConcurrency
+	if maxConcurrency == 0 {
+		maxConcurrency = drBulkActionMaxConcurrencyDefault
+	}
+
+	for i := range bulk.Status.DRPCs {
+		entry := &bulk.Status.DRPCs[i]
+
+		if entry.State != rmn.DRBulkActionDRPCPending {
+			continue
+		}
+
+		if bulk.Spec.AbortOnError && failed > 0 {
+			entry.State = rmn.DRBulkActionDRPCSkipped
+			entry.Message = "aborted on the failure of the action of another DRPC"
+
+			continue
+		}
+
+		if inProgress >= maxConcurrency {
+			break
+		}
+
+		r.actionStart(ctx, bulk, entry, log)
+
+		switch entry.State {
+		case rmn.DRBulkActionDRPCInProgress:
+			inProgress++
+		case rmn.DRBulkActionDRPCFailed:
+			failed++
+		}
+	}
+}
+
+// actionStart applies the action to a DRPC. Failover and Relocate are in progress until the DRPC completes them,
+// while Pause and Resume succeed once applied.
+func (r *DRBulkActionReconciler) actionStart(ctx context.Context, bulk *rmn.DRBulkAction,
+	entry *rmn.DRBulkActionDRPCStatus, log logr.Logger,
+) {
+	drpc, err := drpcReferenceGet(ctx, r.APIReader, entry.DRPCReference)
+	if err != nil {
+		entry.State, entry.Message = drpcReferenceGetFailed(err, rmn.DRBulkActionDRPCSkipped,
+			rmn.DRBulkActionDRPCFailed)
+
+		return
+	}
+
+	now := metav1.Now()
+	entry.StartTime = &now
+
+	var applied bool
+
+	switch bulk.Spec.Action {
+	case rmn.DRBulkActionPause, rmn.DRBulkActionResume:
+		applied, err = drBulkActionPauseApply(ctx, r.Client, drpc, bulk.Spec.Action == rmn.DRBulkActionPause)
+		entry.State = rmn.DRBulkActionDRPCSucceeded
+		entry.CompletionTime = &now
+	default:
+		action := drBulkActionDRPCAction(bulk)
+		entry.TargetCluster = drBulkActionTargetCluster(bulk, drpc)
+		entry.State = rmn.DRBulkActionDRPCInProgress
+
+		switch {
+		case entry.TargetCluster == "":
+			entry.State = rmn.DRBulkActionDRPCFailed
+			entry.Message = "DRPC has no preferred cluster to relocate to"
+
+			return
+		case drpcPaused(drpc):
+			entry.State = rmn.DRBulkActionDRPCFailed
+			entry.Message = "DRPC is paused"
+
+			return
+		}
+
+		if err := drpcActionLimited(ctx, r.APIReader, drpc, action); err != nil {
+			entry.State = rmn.DRBulkActionDRPCFailed
+			entry.Message = err.Error()
+
+			return
+		}
+
+		applied, err = drpcActionRequest(ctx, r.Client, drpc, action, entry.TargetCluster)
+	}
+
+	if err != nil {
+		entry.State = rmn.DRBulkActionDRPCFailed
+		entry.Message = err.Error()
+		entry.CompletionTime = nil
+
+		return
+	}
+
+	if !applied {
+		return
+	}
+
+	log.Info("DRPC action applied", "drpc", entry.Name, "namespace", entry.Namespace, "action", bulk.Spec.Action,
+		"cluster", entry.TargetCluster)
+	rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeNormal, rmnutil.EventReasonDRBulkAction,
+		fmt.Sprintf("%s applied by DRBulkAction %s", bulk.Spec.Action, bulk.Name))
+}
+
+// actionCheck moves a DRPC to the succeeded state once it completed its action, and to the failed state if its
+// action was changed meanwhile
+func (r *DRBulkActionReconciler) actionCheck(ctx context.Context, bulk *rmn.DRBulkAction,
+	entry *rmn.DRBulkActionDRPCStatus,
+) {
+	drpc, err := drpcReferenceGet(ctx, r.APIReader, entry.DRPCReference)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			entry.State, entry.Message = drpcReferenceGetFailed(err, rmn.DRBulkActionDRPCSkipped,
+				rmn.DRBulkActionDRPCFailed)
+		}
+
+		return
+	}
+
+	completed, err := drpcActionCompletedTo(drpc, drBulkActionDRPCAction(bulk), entry.TargetCluster)
+	if err != nil {
+		entry.State = rmn.DRBulkActionDRPCFailed
+		entry.Message = err.Error()
+
+		return
+	}
+
+	if !completed {
+		return
+	}
+
+	now := metav1.Now()
+	entry.State = rmn.DRBulkActionDRPCSucceeded
+	entry.CompletionTime = &now
+}
+
+// drBulkActionDRPCAction returns the DRPC action of the failover or relocation of a DRBulkAction
+func drBulkActionDRPCAction(bulk *rmn.DRBulkAction) rmn.DRAction {
+	if bulk.Spec.Action == rmn.DRBulkActionFailover {
+		return rmn.ActionFailover
+	}
+
+	return rmn.ActionRelocate
+}
+
+// drBulkActionTargetCluster returns the cluster a DRPC is failed over or relocated to: the failover cluster of the
+// DRBulkAction, or its preferred cluster, if set, and that of the DRPC otherwise
+func drBulkActionTargetCluster(bulk *rmn.DRBulkAction, drpc *rmn.DRPlacementControl) string {
+	switch {
+	case bulk.Spec.Action == rmn.DRBulkActionFailover:
+		return bulk.Spec.FailoverCluster
+	case bulk.Spec.PreferredCluster != "":
+		return bulk.Spec.PreferredCluster
+	default:
+		return drpc.Spec.PreferredCluster
+	}
+}
+
+// drBulkActionPauseApply annotates a DRPC to pause it, or removes the annotation to resume it, and returns true if
+// it changed the annotation
+func drBulkActionPauseApply(ctx context.Context, c client.Client, drpc *rmn.DRPlacementControl, pause bool,
+) (bool, error) {
+	if drpcPaused(drpc) == pause {
+		return false, nil
+	}
+
+	original := drpc.DeepCopy()
+	action := "pause"
+
+	if pause {
+		rmnutil.AddAnnotation(drpc, DRPCPausedAnnotation, DRPCPausedAnnotationVal)
+	} else {
+		delete(drpc.Annotations, DRPCPausedAnnotation)
+		action = "resume"
+	}
+
+	if err := c.Patch(ctx, drpc, client.MergeFrom(original)); err != nil {
+		return false, fmt.Errorf("failed to %s DRPC: %w", action, err)
+	}
+
+	return true, nil
+}
+
+func drBulkActionSummary(entries []rmn.DRBulkActionDRPCStatus) rmn.DRBulkActionSummary {
+	summary := rmn.DRBulkActionSummary{}
+
+	for i := range entries {
+		switch entries[i].State {
+		case rmn.DRBulkActionDRPCPending:
+			summary.Pending++
+		case rmn.DRBulkActionDRPCInProgress:
+			summary.InProgress++
+		case rmn.DRBulkActionDRPCSucceeded:
+			summary.Succeeded++
+		case rmn.DRBulkActionDRPCFailed:
+			summary.Failed++
+		case rmn.DRBulkActionDRPCSkipped:
+			summary.Skipped++
+		}
+	}
+
+	return summary
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *DRBulkActionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("drbulkaction"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rmn.DRBulkAction{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&rmn.DRPlacementControl{},
+			handler.EnqueueRequestsFromMapFunc(r.drpcMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// drpcMapFunc returns the running DRBulkActions whose action of the DRPC is in progress
+func (r *DRBulkActionReconciler) drpcMapFunc(ctx context.Context, drpc client.Object) []reconcile.Request {
+	bulks := &rmn.DRBulkActionList{}
+	if err := r.Client.List(ctx, bulks); err != nil {
+		r.Log.Info("Failed to list DRBulkActions", "error", err)
+
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+
+	for i := range bulks.Items {
+		for _, entry := range bulks.Items[i].Status.DRPCs {
+			if entry.State == rmn.DRBulkActionDRPCInProgress && entry.Namespace == drpc.GetNamespace() &&
+				entry.Name == drpc.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name: bulks.Items[i].Name,
+				}})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
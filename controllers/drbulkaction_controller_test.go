@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the actions applied to the DRPCs a label selector matches
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRBulkAction", func() {
+	var (
+		c    client.Client
+		r    *DRBulkActionReconciler
+		bulk *rmn.DRBulkAction
+	)
+
+	site := map[string]string{"site": "east"}
+	states := func() []rmn.DRBulkActionDRPCState {
+		states := []rmn.DRBulkActionDRPCState{}
+		for _, entry := range bulk.Status.DRPCs {
+			states = append(states, entry.State)
+		}
+
+		return states
+	}
+	drpcGet := func(name string) *rmn.DRPlacementControl {
+		drpc := &rmn.DRPlacementControl{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, drpc)).To(Succeed())
+
+		return drpc
+	}
+	advance := func() []rmn.DRBulkActionDRPCState {
+		r.advance(context.TODO(), bulk, r.Log)
+
+		return states()
+	}
+
+	BeforeEach(func() {
+		paused := drpcOn("c-paused", "east", site)
+		paused.Annotations = map[string]string{DRPCPausedAnnotation: DRPCPausedAnnotationVal}
+
+		c = drpcActionTestClient(drpcOn("b", "east", site), drpcOn("a", "east", site), paused,
+			drpcOn("unselected", "east", nil))
+		r = &DRBulkActionReconciler{
+			Client:        c,
+			APIReader:     c,
+			Log:           ctrl.Log.WithName("drbulkaction-test"),
+			eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+		}
+		bulk = &rmn.DRBulkAction{
+			ObjectMeta: metav1.ObjectMeta{Name: "east-down"},
+			Spec: rmn.DRBulkActionSpec{
+				Selector:        metav1.LabelSelector{MatchLabels: site},
+				Action:          rmn.DRBulkActionFailover,
+				FailoverCluster: "west",
+				MaxConcurrency:  1,
+			},
+		}
+		Expect(r.selectDRPCs(context.TODO(), bulk)).To(Succeed())
+	})
+
+	It("selects the DRPCs the selector matches in namespace and name order", func() {
+		Expect(bulk.Status.Phase).To(Equal(rmn.DRBulkActionRunning))
+		Expect(bulk.Status.DRPCs).To(HaveEach(HaveField("Namespace", "app")))
+		Expect(bulk.Status.DRPCs).To(HaveExactElements(HaveField("Name", "a"), HaveField("Name", "b"),
+			HaveField("Name", "c-paused")))
+	})
+
+	It("fails the DRPCs over no more than the maximum concurrency at once, and fails the paused ones", func() {
+		Expect(advance()).To(Equal([]rmn.DRBulkActionDRPCState{
+			rmn.DRBulkActionDRPCInProgress, rmn.DRBulkActionDRPCPending, rmn.DRBulkActionDRPCPending,
+		}))
+		Expect(drpcGet("a").Spec).To(And(HaveField("Action", rmn.ActionFailover),
+			HaveField("FailoverCluster", "west")))
+		Expect(drpcGet("b").Spec.Action).To(BeEmpty())
+
+		drpcActionComplete(c, "a")
+		Expect(advance()).To(Equal([]rmn.DRBulkActionDRPCState{
+			rmn.DRBulkActionDRPCSucceeded, rmn.DRBulkActionDRPCInProgress, rmn.DRBulkActionDRPCPending,
+		}))
+
+		drpcActionComplete(c, "b")
+		Expect(advance()).To(Equal([]rmn.DRBulkActionDRPCState{
+			rmn.DRBulkActionDRPCSucceeded, rmn.DRBulkActionDRPCSucceeded, rmn.DRBulkActionDRPCFailed,
+		}))
+		Expect(bulk.Status.DRPCs[2].Message).To(Equal("DRPC is paused"))
+	})
+
+	It("skips the pending DRPCs once an action failed, if it aborts on error", func() {
+		bulk.Spec.AbortOnError = true
+		bulk.Spec.MaxConcurrency = 3
+
+		Expect(advance()).To(Equal([]rmn.DRBulkActionDRPCState{
+			rmn.DRBulkActionDRPCInProgress, rmn.DRBulkActionDRPCInProgress, rmn.DRBulkActionDRPCFailed,
+		}))
+
+		drpc := drpcGet("a")
+		drpc.Spec.FailoverCluster = "east"
+		Expect(c.Update(context.TODO(), drpc)).To(Succeed())
+
+		bulk.Status.DRPCs = append(bulk.Status.DRPCs, rmn.DRBulkActionDRPCStatus{
+			DRPCReference: rmn.DRPCReference{Namespace: "app", Name: "unselected"},
+			State:         rmn.DRBulkActionDRPCPending,
+		})
+		Expect(advance()).To(Equal([]rmn.DRBulkActionDRPCState{
+			rmn.DRBulkActionDRPCFailed, rmn.DRBulkActionDRPCInProgress, rmn.DRBulkActionDRPCFailed,
+			rmn.DRBulkActionDRPCSkipped,
+		}))
+		Expect(bulk.Status.DRPCs[0].Message).To(ContainSubstring(`to cluster "east"`))
+	})
+
+	It("pauses and resumes the DRPCs without waiting on them", func() {
+		bulk.Spec.Action = rmn.DRBulkActionPause
+		Expect(advance()).To(HaveEach(rmn.DRBulkActionDRPCSucceeded))
+		Expect(drpcPaused(drpcGet("a"))).To(BeTrue())
+
+		bulk.Status.DRPCs = nil
+		bulk.Spec.Action = rmn.DRBulkActionResume
+		Expect(r.selectDRPCs(context.TODO(), bulk)).To(Succeed())
+		Expect(advance()).To(HaveEach(rmn.DRBulkActionDRPCSucceeded))
+		Expect(drpcPaused(drpcGet("c-paused"))).To(BeFalse())
+	})
+
+	It("reports a paused DRPC in its Paused condition", func() {
+		scheme := runtime.NewScheme()
+		Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+		drpc := drpcGet("c-paused")
+		drpc.ResourceVersion = ""
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(drpc).WithStatusSubresource(drpc).Build()
+		drpcReconciler := &DRPlacementControlReconciler{Client: c}
+
+		Expect(drpcReconciler.setPausedStatusAndUpdate(context.TODO(), drpc)).To(Succeed())
+		Expect(drpcGet("c-paused").Status.Conditions).To(ContainElement(And(
+			HaveField("Type", rmn.ConditionPaused), HaveField("Status", metav1.ConditionTrue),
+			HaveField("Reason", rmn.ReasonPaused))))
+	})
+})
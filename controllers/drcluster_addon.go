@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DrClusterAddOnName is the name of the add-on the dr-cluster operator is delivered as
+	DrClusterAddOnName = "ramen-dr-cluster"
+
+	addOnGroup                        = "addon.open-cluster-management.io"
+	addOnVersion                      = "v1alpha1"
+	clusterManagementAddOnKind        = "ClusterManagementAddOn"
+	managedClusterAddOnKind           = "ManagedClusterAddOn"
+	addOnTemplatesResource            = "addontemplates"
+	addOnLifecycleAnnotation          = "addon.open-cluster-management.io/lifecycle"
+	addOnLifecycleAddOnManager        = "addon-manager"
+	addOnInstallStrategyManual        = "Manual"
+	addOnInstallStrategyPlacements    = "Placements"
+	drClusterAddOnTemplateNameDefault = DrClusterAddOnName
+	drClusterAddOnDisplayName         = "Ramen DR cluster operator"
+	drClusterAddOnDescription         = "Protects and recovers the workloads of a managed cluster for Ramen DR"
+)
+
+func drClusterAddOnTemplateNameOrDefault(addOn *rmn.DrClusterOperatorAddOn) string {
+	if addOn.AddOnTemplateName == "" {
+		return drClusterAddOnTemplateNameDefault
+	}
+
+	return addOn.AddOnTemplateName
+}
+
+// drClusterAddOnDeploy installs the dr-cluster operator add-on on a cluster. The add-on manager deploys it from the
+// add-on's template, and installs it as well on the clusters its placements select.
+//
+// Should be called from the Hub
+func drClusterAddOnDeploy(ctx context.Context, k8sClient client.Client, clusterName string,
+	addOn *rmn.DrClusterOperatorAddOn, log logr.Logger,
+) error {
+	if err := clusterManagementAddOnCreateOrUpdate(ctx, k8sClient, addOn, log); err != nil {
+		return err
+	}
+
+	return managedClusterAddOnCreateOrUpdate(ctx, k8sClient, clusterName, log)
+}
+
+// clusterManagementAddOnCreateOrUpdate registers the dr-cluster operator add-on with the add-on manager, which
+// manages its lifecycle, deploying the template it refers to on the clusters it is installed on
+func clusterManagementAddOnCreateOrUpdate(ctx context.Context, k8sClient client.Client,
+	addOn *rmn.DrClusterOperatorAddOn, log logr.Logger,
+) error {
+	installStrategy := map[string]interface{}{"type": addOnInstallStrategyManual}
+
+	if len(addOn.Placements) > 0 {
+		placements := make([]interface{}, len(addOn.Placements))
+		for i, placement := range addOn.Placements {
+			placements[i] = map[string]interface{}{
+				"namespace": placement.Namespace,
+				"name":      placement.Name,
+			}
+		}
+
+		installStrategy = map[string]interface{}{
+			"type":       addOnInstallStrategyPlacements,
+			"placements": placements,
+		}
+	}
+
+	// Using unstructured to avoid needing to require ClusterManagementAddOn in client scheme
+	cmao := &unstructured.Unstructured{}
+	cmao.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   addOnGroup,
+		Version: addOnVersion,
+		Kind:    clusterManagementAddOnKind,
+	})
+	cmao.SetName(DrClusterAddOnName)
+
+	op, err := ctrlutil.CreateOrUpdate(ctx, k8sClient, cmao, func() error {
+		annotations := cmao.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[addOnLifecycleAnnotation] = addOnLifecycleAddOnManager
+		cmao.SetAnnotations(annotations)
+
+		cmao.Object["spec"] = map[string]interface{}{
+			"addOnMeta": map[string]interface{}{
+				"displayName": drClusterAddOnDisplayName,
+				"description": drClusterAddOnDescription,
+			},
+			"supportedConfigs": []interface{}{
+				map[string]interface{}{
+					"group":    addOnGroup,
+					"resource": addOnTemplatesResource,
+					"defaultConfig": map[string]interface{}{
+						"name": drClusterAddOnTemplateNameOrDefault(addOn),
+					},
+				},
+			},
+			"installStrategy": installStrategy,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ClusterManagementAddOn %s create or update: %w", DrClusterAddOnName, err)
+	}
+
+	if op != ctrlutil.OperationResultNone {
+		log.Info("ClusterManagementAddOn created or updated", "name", DrClusterAddOnName, "op", op)
+	}
+
+	return nil
+}
+
+// managedClusterAddOnCreateOrUpdate installs the dr-cluster operator add-on on a cluster, in its namespace on the
+// hub. Its spec is left for users to update once created.
+func managedClusterAddOnCreateOrUpdate(ctx context.Context, k8sClient client.Client, clusterName string,
+	log logr.Logger,
+) error {
+	mcao := managedClusterAddOn(clusterName)
+
+	op, err := ctrlutil.CreateOrUpdate(ctx, k8sClient, mcao, func() error {
+		creationTimeStamp := mcao.GetCreationTimestamp()
+		if creationTimeStamp.IsZero() {
+			mcao.Object["spec"] = map[string]interface{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("ManagedClusterAddOn %s/%s create or update: %w", clusterName, DrClusterAddOnName, err)
+	}
+
+	if op != ctrlutil.OperationResultNone {
+		log.Info("ManagedClusterAddOn created or updated", "name", DrClusterAddOnName, "op", op)
+	}
+
+	return nil
+}
+
+// drClusterAddOnUndeploy uninstalls the dr-cluster operator add-on from a cluster, unless its placements select it
+func drClusterAddOnUndeploy(ctx context.Context, k8sClient client.Client, clusterName string) error {
+	err := k8sClient.Delete(ctx, managedClusterAddOn(clusterName))
+	if err == nil || k8serrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	return fmt.Errorf("ManagedClusterAddOn %s/%s delete: %w", clusterName, DrClusterAddOnName, err)
+}
+
+func managedClusterAddOn(clusterName string) *unstructured.Unstructured {
+	mcao := &unstructured.Unstructured{}
+	mcao.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   addOnGroup,
+		Version: addOnVersion,
+		Kind:    managedClusterAddOnKind,
+	})
+	mcao.SetNamespace(clusterName)
+	mcao.SetName(DrClusterAddOnName)
+
+	return mcao
+}
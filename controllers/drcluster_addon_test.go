@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the add-on the dr-cluster operator is delivered as
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_AddOn", func() {
+	var c client.Client
+
+	log := ctrl.Log.WithName("drcluster-addon-test")
+	clusterManagementAddOnGet := func() *unstructured.Unstructured {
+		cmao := &unstructured.Unstructured{}
+		cmao.SetGroupVersionKind(schema.GroupVersionKind{
+			Group: addOnGroup, Version: addOnVersion, Kind: clusterManagementAddOnKind,
+		})
+		Expect(c.Get(context.TODO(), client.ObjectKey{Name: DrClusterAddOnName}, cmao)).To(Succeed())
+
+		return cmao
+	}
+	nestedString := func(object *unstructured.Unstructured, fields ...string) string {
+		value, _, err := unstructured.NestedString(object.Object, fields...)
+		Expect(err).ToNot(HaveOccurred())
+
+		return value
+	}
+	managedClusterAddOnGet := func(clusterName string) error {
+		return c.Get(context.TODO(), client.ObjectKeyFromObject(managedClusterAddOn(clusterName)),
+			managedClusterAddOn(clusterName))
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().Build()
+	})
+
+	It("registers the add-on with its template, to be installed manually on the clusters of the DRClusters", func() {
+		Expect(drClusterAddOnDeploy(context.TODO(), c, "east", &rmn.DrClusterOperatorAddOn{}, log)).To(Succeed())
+
+		cmao := clusterManagementAddOnGet()
+		Expect(cmao.GetAnnotations()).To(HaveKeyWithValue(addOnLifecycleAnnotation, addOnLifecycleAddOnManager))
+		Expect(nestedString(cmao, "spec", "installStrategy", "type")).To(Equal(addOnInstallStrategyManual))
+
+		configs, _, err := unstructured.NestedSlice(cmao.Object, "spec", "supportedConfigs")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configs).To(ConsistOf(HaveKeyWithValue("defaultConfig",
+			HaveKeyWithValue("name", drClusterAddOnTemplateNameDefault))))
+
+		Expect(managedClusterAddOnGet("east")).To(Succeed())
+	})
+
+	It("installs the add-on on the clusters its placements select, from the configured template", func() {
+		Expect(drClusterAddOnDeploy(context.TODO(), c, "east", &rmn.DrClusterOperatorAddOn{
+			AddOnTemplateName: "ramen-dr-cluster-v2",
+			Placements:        []rmn.DrClusterOperatorAddOnPlacement{{Namespace: "ramen-ops", Name: "dr"}},
+		}, log)).To(Succeed())
+
+		cmao := clusterManagementAddOnGet()
+		Expect(nestedString(cmao, "spec", "installStrategy", "type")).To(Equal(addOnInstallStrategyPlacements))
+
+		placements, _, err := unstructured.NestedSlice(cmao.Object, "spec", "installStrategy", "placements")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(placements).To(ConsistOf(And(HaveKeyWithValue("namespace", "ramen-ops"),
+			HaveKeyWithValue("name", "dr"))))
+
+		configs, _, err := unstructured.NestedSlice(cmao.Object, "spec", "supportedConfigs")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configs).To(ConsistOf(HaveKeyWithValue("defaultConfig",
+			HaveKeyWithValue("name", "ramen-dr-cluster-v2"))))
+	})
+
+	It("leaves the spec of an installed add-on to its users", func() {
+		Expect(drClusterAddOnDeploy(context.TODO(), c, "east", &rmn.DrClusterOperatorAddOn{}, log)).To(Succeed())
+
+		mcao := managedClusterAddOn("east")
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(mcao), mcao)).To(Succeed())
+		Expect(unstructured.SetNestedField(mcao.Object, "ramen-ops", "spec", "installNamespace")).To(Succeed())
+		// as the API server sets it on creation
+		mcao.SetCreationTimestamp(metav1.Now())
+		Expect(c.Update(context.TODO(), mcao)).To(Succeed())
+
+		Expect(drClusterAddOnDeploy(context.TODO(), c, "east", &rmn.DrClusterOperatorAddOn{}, log)).To(Succeed())
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(mcao), mcao)).To(Succeed())
+		Expect(nestedString(mcao, "spec", "installNamespace")).To(Equal("ramen-ops"))
+	})
+
+	It("uninstalls the add-on from a cluster, whether or not it is installed", func() {
+		Expect(drClusterAddOnDeploy(context.TODO(), c, "east", &rmn.DrClusterOperatorAddOn{}, log)).To(Succeed())
+		Expect(drClusterAddOnUndeploy(context.TODO(), c, "east")).To(Succeed())
+		Expect(managedClusterAddOnGet("east")).ToNot(Succeed())
+		Expect(drClusterAddOnUndeploy(context.TODO(), c, "east")).To(Succeed())
+	})
+
+	It("rejects an add-on along with an upgrade, or with placements without a namespace and a name", func() {
+		Expect(ramenConfigValidate(&rmn.RamenConfig{
+			DrClusterOperatorAddOn:   &rmn.DrClusterOperatorAddOn{},
+			DrClusterOperatorUpgrade: &rmn.DrClusterOperatorUpgrade{},
+		})).To(MatchError(ContainSubstring("drClusterOperatorUpgrade")))
+		Expect(ramenConfigValidate(&rmn.RamenConfig{DrClusterOperatorAddOn: &rmn.DrClusterOperatorAddOn{
+			Placements: []rmn.DrClusterOperatorAddOnPlacement{{Name: "dr"}},
+		}})).To(MatchError(ContainSubstring("requires a namespace and a name")))
+	})
+})
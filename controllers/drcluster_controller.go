@@ -11,7 +11,6 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -20,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -44,6 +44,7 @@ type DRClusterReconciler struct {
 	MCVGetter         util.ManagedClusterViewGetter
 	ObjectStoreGetter ObjectStoreGetter
 	RateLimiter       *workqueue.RateLimiter
+	ClusterBreaker    *util.ClusterCircuitBreaker
 }
 
 // DRCluster condition reasons
@@ -127,7 +128,7 @@ func (r *DRClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		})
 	}
 
-	return controller.
+	return r.clusterBreakerWatch(controller).
 		For(&ramen.DRCluster{}).
 		Watches(&ramen.DRPlacementControl{}, drpcMapFun, builder.WithPredicates(drpcPred())).
 		Watches(&ocmworkv1.ManifestWork{}, mwMapFun, builder.WithPredicates(mwPred)).
@@ -332,7 +333,8 @@ func filterDRClusterSecret(ctx context.Context, reader client.Reader, secret *co
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drclusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drpolicies,verbs=get;list;watch
-// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=managedclusteraddons,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=clustermanagementaddons,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=managedclusteraddons,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=work.open-cluster-management.io,resources=manifestworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=view.open-cluster-management.io,resources=managedclusterviews,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=placementrules,verbs=get;list;watch
@@ -344,7 +346,7 @@ func filterDRClusterSecret(ctx context.Context, reader client.Reader, secret *co
 func (r *DRClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// TODO: Validate managedCluster name? and also ensure it is not deleted!
 	// TODO: Setup views for storage class and VRClass to read and report IDs
-	log := r.Log.WithValues("name", req.NamespacedName.Name, "rid", uuid.New())
+	log := r.Log.WithValues(logKeyCluster, req.NamespacedName.Name, "rid", uuid.New())
 	log.Info("reconcile enter")
 
 	defer log.Info("reconcile exit")
@@ -404,6 +406,11 @@ func (r DRClusterReconciler) processCreateOrUpdate(u *drclusterInstance) (ctrl.R
 			u.validatedSetFalseAndUpdate(ReasonValidationFailed, err))
 	}
 
+	if err = validateSecretRewrites(u.object); err != nil {
+		return ctrl.Result{}, fmt.Errorf("drclusters secret rewrites validate: %w",
+			u.validatedSetFalseAndUpdate(ReasonValidationFailed, err))
+	}
+
 	requeue, err = u.clusterFenceHandle()
 	if err != nil {
 		// On error proceed with S3 validation, as fencing is independent of S3
@@ -430,11 +437,25 @@ func (r DRClusterReconciler) processCreateOrUpdate(u *drclusterInstance) (ctrl.R
 
 	setDRClusterValidatedCondition(&u.object.Status.Conditions, u.object.Generation, "Validated the cluster")
 
+	retryAfter := u.reachableConditionSet()
+
+	if staleIn := u.operatorHealthConditionSet(); staleIn > 0 && (retryAfter == 0 || staleIn < retryAfter) {
+		retryAfter = staleIn
+	}
+
+	if rolloutIn := u.objectsRolloutRetryAfter(); rolloutIn > 0 && (retryAfter == 0 || rolloutIn < retryAfter) {
+		retryAfter = rolloutIn
+	}
+
+	u.versionSkewConditionSet()
+
+	u.failureDomainSet()
+
 	if err := u.statusUpdate(); err != nil {
 		u.log.Info("failed to update status", "failure", err)
 	}
 
-	return ctrl.Result{Requeue: requeue || u.requeue}, reconcileError
+	return ctrl.Result{Requeue: requeue || u.requeue, RequeueAfter: retryAfter}, reconcileError
 }
 
 func (u *drclusterInstance) initializeStatus() {
@@ -545,6 +566,9 @@ func (r DRClusterReconciler) processDeletion(u *drclusterInstance) (ctrl.Result,
 		return ctrl.Result{}, fmt.Errorf("finalizer remove update: %w", err)
 	}
 
+	DeleteDRClusterVersionSkewMetric(DRClusterVersionSkewMetricLabels(u.object))
+	DeleteDRClusterS3SecretsMetrics(DRClusterS3SecretsMetricLabels(u.object.Name))
+
 	return ctrl.Result{}, nil
 }
 
@@ -1021,7 +1045,7 @@ func getPeerFromPolicy(ctx context.Context, reconciler *DRClusterReconciler, log
 			continue
 		}
 
-		if drCluster.Spec.Region == peerCluster.Spec.Region {
+		if util.DRClusterRegion(drCluster) == util.DRClusterRegion(peerCluster) {
 			found = true
 
 			break
@@ -11,7 +11,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	gomegaTypes "github.com/onsi/gomega/types"
-	workv1 "github.com/open-cluster-management/api/work/v1"
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
 	"github.com/ramendr/ramen/controllers"
 	"github.com/ramendr/ramen/controllers/util"
@@ -20,6 +19,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	workv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -62,6 +62,16 @@ func (f FakeMCVGetter) DeleteNFManagedClusterView(
 	return nil
 }
 
+func (f FakeMCVGetter) GetDRClusterOperatorStatusFromManagedCluster(managedCluster string,
+	annotations map[string]string,
+) (*ramen.DRClusterOperatorStatus, error) {
+	return nil, errors.NewNotFound(schema.GroupResource{}, ramen.DRClusterOperatorStatusName)
+}
+
+func (f FakeMCVGetter) DeleteDRClusterOperatorStatusManagedClusterView(clusterName string) error {
+	return nil
+}
+
 func drclusterConditionExpectEventually(
 	drcluster *ramen.DRCluster,
 	disabled bool,
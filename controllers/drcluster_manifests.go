@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ramendr/ramen/config/dr-cluster/rbac"
+	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	drClusterOperatorConfigVolumeName = "ramen-manager-config-vol"
+	drClusterOperatorConfigMountPath  = "/config"
+	drClusterOperatorProbePort        = 8081
+)
+
+// drClusterOperatorRoleVerbsDenied are the verbs the roles deployed to the clusters may not grant, as they would let
+// the dr-cluster operator grant itself, or act as, any other identity
+var drClusterOperatorRoleVerbsDenied = []string{"*", "escalate", "bind", "impersonate"}
+
+// drClusterOperatorLeaderElectionRules are the rules of the dr-cluster operator's leader election, in its namespace
+var drClusterOperatorLeaderElectionRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{"coordination.k8s.io"},
+		Resources: []string{"leases"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"events"},
+		Verbs:     []string{"create", "patch"},
+	},
+}
+
+// appendOperatorManifestObjects appends the objects that deploy the dr-cluster operator without OLM: its service
+// account, its roles and their bindings, and its deployment, which runs with the configuration deployed along
+func appendOperatorManifestObjects(
+	drcluster *rmn.DRCluster,
+	ramenConfig *rmn.RamenConfig,
+	objects []interface{},
+) ([]interface{}, error) {
+	image := ramenConfig.DrClusterOperator.Image
+	if image == "" {
+		return nil, fmt.Errorf("drClusterOperator image is required to deploy to drcluster '%v' with "+
+			"operatorDeploymentMode %s", drcluster.Name, rmn.OperatorDeploymentModeManifests)
+	}
+
+	clusterRoleRules, roleRules, err := drClusterOperatorRoleRules()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceName := drClusterOperatorNamespaceNameOrDefault(ramenConfig)
+	name := drClusterOperatorNameDefault
+	subjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: name, Namespace: namespaceName}}
+
+	return append(objects,
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespaceName},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      clusterRoleRules,
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: name},
+		},
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{Kind: "Role", APIVersion: "rbac.authorization.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespaceName},
+			Rules:      append(roleRules, drClusterOperatorLeaderElectionRules...),
+		},
+		&rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespaceName},
+			Subjects:   subjects,
+			RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: name},
+		},
+		drClusterOperatorDeployment(namespaceName, name, image, drcluster.Spec.OperatorProfile,
+			ramenConfig.DrClusterOperatorResources),
+	), nil
+}
+
+// drClusterOperatorRoleRules returns the rules of the dr-cluster operator's ClusterRole and Role, as generated from
+// its RBAC markers, or an error if a rule grants more than the operator's own resources
+func drClusterOperatorRoleRules() ([]rbacv1.PolicyRule, []rbacv1.PolicyRule, error) {
+	var clusterRoleRules, roleRules []rbacv1.PolicyRule
+
+	for _, document := range bytes.Split(rbac.Roles, []byte("\n---\n")) {
+		role := &rbacv1.ClusterRole{}
+		if err := yaml.Unmarshal(document, role); err != nil {
+			return nil, nil, fmt.Errorf("dr-cluster operator roles yaml unmarshal: %w", err)
+		}
+
+		switch role.Kind {
+		case "ClusterRole":
+			clusterRoleRules = append(clusterRoleRules, role.Rules...)
+		case "Role":
+			roleRules = append(roleRules, role.Rules...)
+		}
+	}
+
+	if clusterRoleRules == nil {
+		return nil, nil, errors.New("dr-cluster operator roles have no ClusterRole")
+	}
+
+	for _, rule := range append(clusterRoleRules, roleRules...) {
+		if err := drClusterOperatorRoleRuleValidate(rule); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return clusterRoleRules, roleRules, nil
+}
+
+// drClusterOperatorRoleRuleValidate returns an error if a rule grants access to the resources of every API group, or
+// a verb that escalates privileges
+func drClusterOperatorRoleRuleValidate(rule rbacv1.PolicyRule) error {
+	if slices.Contains(rule.APIGroups, "*") {
+		return fmt.Errorf("dr-cluster operator role rule grants %v on all API groups", rule.Verbs)
+	}
+
+	for _, verb := range rule.Verbs {
+		if slices.Contains(drClusterOperatorRoleVerbsDenied, verb) {
+			return fmt.Errorf("dr-cluster operator role rule grants %s on %v %v", verb, rule.APIGroups,
+				rule.Resources)
+		}
+	}
+
+	return nil
+}
+
+func drClusterOperatorDeployment(namespaceName, name, image string, profile rmn.OperatorProfile,
+	resources *corev1.ResourceRequirements,
+) *appsv1.Deployment {
+	labels := map[string]string{"app": "ramen-dr-cluster", "control-plane": "controller-manager"}
+	replicas := int32(1)
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	terminationGracePeriodSeconds := int64(10)
+
+	probe := func(path string, initialDelaySeconds, periodSeconds int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt(drClusterOperatorProbePort)},
+			},
+			InitialDelaySeconds: initialDelaySeconds,
+			PeriodSeconds:       periodSeconds,
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespaceName, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: map[string]string{"kubectl.kubernetes.io/default-container": "manager"},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName:            name,
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+					SecurityContext:               &corev1.PodSecurityContext{RunAsNonRoot: &runAsNonRoot},
+					Containers: []corev1.Container{{
+						Name:            "manager",
+						Image:           image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         []string{"/manager"},
+						Args: []string{
+							"--config=" + drClusterOperatorConfigMountPath + "/" + ConfigMapRamenConfigKeyName,
+						},
+						Env: []corev1.EnvVar{{
+							Name: "POD_NAMESPACE",
+							ValueFrom: &corev1.EnvVarSource{
+								FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+							},
+						}},
+						SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: &allowPrivilegeEscalation},
+						LivenessProbe:   probe("/healthz", 15, 20),
+						ReadinessProbe:  probe("/readyz", 5, 10),
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("300Mi"),
+							},
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("100m"),
+								corev1.ResourceMemory: resource.MustParse("200Mi"),
+							},
+						},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      drClusterOperatorConfigVolumeName,
+							MountPath: drClusterOperatorConfigMountPath,
+							ReadOnly:  true,
+						}},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: drClusterOperatorConfigVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: DrClusterOperatorConfigMapName},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	operatorProfileContainerApply(&deployment.Spec.Template.Spec.Containers[0], profile)
+
+	if resources != nil {
+		deployment.Spec.Template.Spec.Containers[0].Resources = *resources.DeepCopy()
+	}
+
+	return deployment
+}
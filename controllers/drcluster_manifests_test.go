@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the manifests that deploy the dr-cluster operator to the clusters without OLM
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_OperatorManifests", func() {
+	var (
+		drcluster   *rmn.DRCluster
+		ramenConfig *rmn.RamenConfig
+	)
+
+	deployment := func() *appsv1.Deployment {
+		objects, err := appendOperatorManifestObjects(drcluster, ramenConfig, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, object := range objects {
+			if deployment, ok := object.(*appsv1.Deployment); ok {
+				return deployment
+			}
+		}
+
+		Fail("no deployment")
+
+		return nil
+	}
+
+	BeforeEach(func() {
+		drcluster = &rmn.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: "east"}}
+		ramenConfig = &rmn.RamenConfig{}
+		ramenConfig.DrClusterOperator.Image = "quay.io/ramendr/ramen-operator:latest"
+	})
+
+	It("deploys the operator's roles, as generated from its RBAC markers", func() {
+		clusterRoleRules, roleRules, err := drClusterOperatorRoleRules()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusterRoleRules).ToNot(BeEmpty())
+		Expect(roleRules).ToNot(BeEmpty())
+
+		objects, err := appendOperatorManifestObjects(drcluster, ramenConfig, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objects).To(ContainElement(BeAssignableToTypeOf(&rbacv1.ClusterRole{})))
+		Expect(objects).To(ContainElement(BeAssignableToTypeOf(&corev1.ServiceAccount{})))
+	})
+
+	It("refuses role rules on all API groups, or with verbs that escalate privileges", func() {
+		Expect(drClusterOperatorRoleRuleValidate(rbacv1.PolicyRule{
+			APIGroups: []string{"constraints.gatekeeper.sh"}, Resources: []string{"*"}, Verbs: []string{"patch"},
+		})).To(Succeed())
+		Expect(drClusterOperatorRoleRuleValidate(rbacv1.PolicyRule{
+			APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"get"},
+		})).To(MatchError(ContainSubstring("all API groups")))
+
+		for _, verb := range drClusterOperatorRoleVerbsDenied {
+			Expect(drClusterOperatorRoleRuleValidate(rbacv1.PolicyRule{
+				APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"roles"}, Verbs: []string{verb},
+			})).To(MatchError(ContainSubstring(verb)))
+		}
+	})
+
+	It("requires the operator image", func() {
+		ramenConfig.DrClusterOperator.Image = ""
+		_, err := appendOperatorManifestObjects(drcluster, ramenConfig, nil)
+		Expect(err).To(MatchError(ContainSubstring("image is required")))
+	})
+
+	It("runs the operator with the default resources, reduced for the Edge profile", func() {
+		Expect(deployment().Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String()).To(Equal("300Mi"))
+
+		drcluster.Spec.OperatorProfile = rmn.OperatorProfileEdge
+		Expect(deployment().Spec.Template.Spec.Containers[0].Resources.Limits.Memory().String()).To(
+			Equal(edgeProfileMemoryLimit))
+	})
+
+	It("runs the operator with the configured resources, in place of those of its profile", func() {
+		resources := &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		ramenConfig.DrClusterOperatorResources = resources
+		drcluster.Spec.OperatorProfile = rmn.OperatorProfileEdge
+
+		Expect(deployment().Spec.Template.Spec.Containers[0].Resources).To(Equal(*resources))
+	})
+})
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// drClusterObjectsRolloutHealthCheckTimeoutDefault is how long a cluster has, by default, to apply the objects
+	// of a revision, and its operator to report healthy, before the rollout is halted
+	drClusterObjectsRolloutHealthCheckTimeoutDefault = 10 * time.Minute
+
+	// drClusterObjectsRolloutRetryInterval is how often a cluster whose rollout is in progress is reconciled
+	drClusterObjectsRolloutRetryInterval = 30 * time.Second
+)
+
+// objectsRollout advances the rollout of the objects to deploy to the cluster, and returns the objects for its phase:
+// those the cluster's ManifestWork has while the rollout is pending, and the objects to deploy once started. A
+// cluster without a ManifestWork is deployed the objects right away.
+func (u *drclusterInstance) objectsRollout(
+	ramenConfig *rmn.RamenConfig,
+	objects []interface{},
+) ([]interface{}, error) {
+	revision, err := objectsRevision(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	mw, err := u.mwUtil.GetDrClusterManifestWork(u.object.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching cluster manifest work %w", err)
+	}
+
+	status := u.object.Status.ObjectsRollout
+	if status == nil || status.Revision != revision {
+		phase := rmn.DRClusterObjectsRolloutPending
+		if mw == nil {
+			phase = rmn.DRClusterObjectsRolloutApplying
+		}
+
+		status = &rmn.DRClusterObjectsRolloutStatus{Revision: revision, Phase: phase}
+		if phase == rmn.DRClusterObjectsRolloutApplying {
+			now := metav1.Now()
+			status.StartTime = &now
+		}
+
+		u.object.Status.ObjectsRollout = status
+	}
+
+	switch status.Phase {
+	case rmn.DRClusterObjectsRolloutPending:
+		waiting, err := u.objectsRolloutWaiting(revision)
+		if err != nil {
+			return nil, err
+		}
+
+		if waiting != "" && mw != nil {
+			status.Message = waiting
+
+			return objectsDeployed(mw)
+		}
+
+		now := metav1.Now()
+		status.StartTime = &now
+		u.objectsRolloutPhaseSet(status, rmn.DRClusterObjectsRolloutApplying, "Objects deployed")
+	case rmn.DRClusterObjectsRolloutApplying:
+		if u.objectsApplied(mw) {
+			u.objectsRolloutPhaseSet(status, rmn.DRClusterObjectsRolloutApplied,
+				"Objects applied and operator reports healthy")
+
+			break
+		}
+
+		timeout := drClusterObjectsRolloutHealthCheckTimeout(ramenConfig.DrClusterObjectsRollout)
+		if status.StartTime != nil && time.Since(status.StartTime.Time) > timeout {
+			u.objectsRolloutPhaseSet(status, rmn.DRClusterObjectsRolloutHalted,
+				fmt.Sprintf("Objects not applied, or operator not healthy, within %v", timeout))
+		}
+	}
+
+	return objects, nil
+}
+
+func (u *drclusterInstance) objectsRolloutPhaseSet(status *rmn.DRClusterObjectsRolloutStatus,
+	phase rmn.DRClusterObjectsRolloutPhase, message string,
+) {
+	u.log.Info("Objects rollout phase changed", "revision", status.Revision, "from", status.Phase, "to", phase,
+		"message", message)
+
+	status.Phase = phase
+	status.Message = message
+}
+
+// objectsApplied returns whether the cluster's ManifestWork applied its current generation, and the cluster's
+// dr-cluster operator reports healthy
+func (u *drclusterInstance) objectsApplied(mw *ocmworkv1.ManifestWork) bool {
+	if mw == nil || !util.IsManifestInAppliedState(mw) {
+		return false
+	}
+
+	applied := meta.FindStatusCondition(mw.Status.Conditions, ocmworkv1.WorkApplied)
+	if applied == nil || applied.ObservedGeneration != mw.Generation {
+		return false
+	}
+
+	return meta.IsStatusConditionTrue(u.object.Status.Conditions, rmn.DRClusterConditionTypeOperatorHealthy)
+}
+
+// objectsRolloutWaiting returns why the rollout of a revision to the cluster waits for the clusters before it, in
+// the order of their names, or an empty string once they all applied it
+func (u *drclusterInstance) objectsRolloutWaiting(revision string) (string, error) {
+	drClusters := &rmn.DRClusterList{}
+	if err := u.client.List(u.ctx, drClusters); err != nil {
+		return "", fmt.Errorf("drclusters list: %w", err)
+	}
+
+	sort.Slice(drClusters.Items, func(i, j int) bool {
+		return drClusters.Items[i].Name < drClusters.Items[j].Name
+	})
+
+	pending := []string{}
+
+	for i := range drClusters.Items {
+		drCluster := &drClusters.Items[i]
+		status := drCluster.Status.ObjectsRollout
+
+		if status != nil && status.Revision == revision && status.Phase == rmn.DRClusterObjectsRolloutHalted {
+			return fmt.Sprintf("Rollout halted on cluster %s: %s", drCluster.Name, status.Message), nil
+		}
+
+		if drCluster.Name >= u.object.Name || util.ResourceIsDeleted(drCluster) {
+			continue
+		}
+
+		if status == nil || status.Revision != revision || status.Phase != rmn.DRClusterObjectsRolloutApplied {
+			pending = append(pending, drCluster.Name)
+		}
+	}
+
+	if len(pending) > 0 {
+		return "Waiting for clusters to apply the objects: " + strings.Join(pending, ", "), nil
+	}
+
+	return "", nil
+}
+
+// objectsRolloutRetryAfter returns how long until the cluster is to be reconciled again to advance its rollout, or
+// zero if its rollout is not in progress
+func (u *drclusterInstance) objectsRolloutRetryAfter() time.Duration {
+	status := u.object.Status.ObjectsRollout
+	if status == nil ||
+		(status.Phase != rmn.DRClusterObjectsRolloutPending && status.Phase != rmn.DRClusterObjectsRolloutApplying) {
+		return 0
+	}
+
+	return drClusterObjectsRolloutRetryInterval
+}
+
+// objectsDeployed returns the objects deployed by the cluster's ManifestWork, other than its subscription, which
+// is rolled out by the operator upgrade
+func objectsDeployed(mw *ocmworkv1.ManifestWork) ([]interface{}, error) {
+	raws, err := util.DrClusterManifestWorkAppendedManifests(mw, "Subscription")
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]interface{}, len(raws))
+	for i := range raws {
+		objects[i] = raws[i]
+	}
+
+	return objects, nil
+}
+
+// objectsRevision returns a hash of the objects
+func objectsRevision(objects []interface{}) (string, error) {
+	objectsJSON, err := json.Marshal(objects)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal objects to deploy: %w", err)
+	}
+
+	hash := sha256.Sum256(objectsJSON)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func drClusterObjectsRolloutHealthCheckTimeout(rollout *rmn.DrClusterObjectsRollout) time.Duration {
+	if rollout == nil || rollout.HealthCheckTimeout == nil {
+		return drClusterObjectsRolloutHealthCheckTimeoutDefault
+	}
+
+	return rollout.HealthCheckTimeout.Duration
+}
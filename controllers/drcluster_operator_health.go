@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	DRClusterConditionReasonOperatorHealthy     = "Healthy"
+	DRClusterConditionReasonOperatorUnhealthy   = "Unhealthy"
+	DRClusterConditionReasonOperatorStale       = "ReportStale"
+	DRClusterConditionReasonOperatorNotReported = "NotReported"
+
+	// drClusterOperatorReportStaleAfter is how long after its last report the dr-cluster operator is considered to
+	// have stopped reporting
+	drClusterOperatorReportStaleAfter = 5 * DRClusterOperatorStatusReportInterval
+)
+
+// operatorHealthConditionSet views the health the dr-cluster operator reports of itself, copies it to the DRCluster
+// status and rolls it up into the OperatorHealthy condition. It returns the time after which a healthy report turns
+// stale, for the condition to be reevaluated then.
+func (u *drclusterInstance) operatorHealthConditionSet() time.Duration {
+	annotations := map[string]string{DRClusterNameAnnotation: u.object.Name}
+
+	operatorStatus, err := u.reconciler.MCVGetter.GetDRClusterOperatorStatusFromManagedCluster(u.object.Name,
+		annotations)
+	if err != nil {
+		message := fmt.Sprintf("Operator health not viewed: %v", err)
+		if k8serrors.IsNotFound(err) {
+			message = "Operator health not reported yet"
+		}
+
+		setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionUnknown, DRClusterConditionReasonOperatorNotReported, message)
+
+		return 0
+	}
+
+	report := operatorStatus.Status.DeepCopy()
+	u.object.Status.Operator = report
+
+	if report.LastReportTime == nil {
+		setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionUnknown, DRClusterConditionReasonOperatorNotReported, "Operator health not reported yet")
+
+		return 0
+	}
+
+	staleIn := time.Until(report.LastReportTime.Add(drClusterOperatorReportStaleAfter))
+	if staleIn <= 0 {
+		setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionFalse, DRClusterConditionReasonOperatorStale,
+			fmt.Sprintf("Operator last reported at %s", report.LastReportTime.UTC().Format(time.RFC3339)))
+
+		return 0
+	}
+
+	unhealthy, unchecked := drClusterOperatorConditionsUnhealthy(report.Conditions)
+	if len(unhealthy) > 0 {
+		setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionFalse, DRClusterConditionReasonOperatorUnhealthy,
+			"Operator reports unhealthy: "+strings.Join(unhealthy, ", "))
+
+		return staleIn
+	}
+
+	message := fmt.Sprintf("Operator version %s reports healthy", report.Version)
+	if len(unchecked) > 0 {
+		message += ", except for unchecked: " + strings.Join(unchecked, ", ")
+	}
+
+	setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, u.object.Generation,
+		metav1.ConditionTrue, DRClusterConditionReasonOperatorHealthy, message)
+
+	return staleIn
+}
+
+// drClusterOperatorConditionsUnhealthy returns the types of the reported conditions that are false, and of those
+// that are unknown, as their checks failed. A failed check, such as a list the API server times out, does not tell
+// that the operator is unhealthy.
+func drClusterOperatorConditionsUnhealthy(conditions []metav1.Condition) (unhealthy, unchecked []string) {
+	for _, condition := range conditions {
+		switch condition.Status {
+		case metav1.ConditionFalse:
+			unhealthy = append(unhealthy, condition.Type)
+		case metav1.ConditionUnknown:
+			unchecked = append(unchecked, condition.Type)
+		}
+	}
+
+	return unhealthy, unchecked
+}
+
+func setDRClusterOperatorHealthyCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	status metav1.ConditionStatus, reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               ramen.DRClusterConditionTypeOperatorHealthy,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             status,
+		Message:            message,
+	})
+}
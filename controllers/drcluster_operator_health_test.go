@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the scope of the dr-cluster operator health report and its roll up
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("DRCluster_OperatorHealth", func() {
+	Describe("webhookServices", func() {
+		webhook := func(name string, failurePolicy admissionregistrationv1.FailurePolicyType, group, resource string,
+		) admissionregistrationv1.ValidatingWebhook {
+			return admissionregistrationv1.ValidatingWebhook{
+				Name:          name,
+				FailurePolicy: &failurePolicy,
+				Rules: []admissionregistrationv1.RuleWithOperations{{Rule: admissionregistrationv1.Rule{
+					APIGroups: []string{group}, Resources: []string{resource},
+				}}},
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{Namespace: "webhooks", Name: name},
+				},
+			}
+		}
+
+		It("checks the webhooks that fail closed on the resources the operator writes only", func() {
+			c := fake.NewClientBuilder().WithObjects(&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "webhooks"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{
+					webhook("pvcs", admissionregistrationv1.Fail, "", "persistentvolumeclaims"),
+					webhook("pvc-status", admissionregistrationv1.Fail, "", "persistentvolumeclaims/status"),
+					webhook("vrgs", admissionregistrationv1.Fail, "ramendr.openshift.io", "volumereplicationgroups"),
+					webhook("all", admissionregistrationv1.Fail, "*", "*"),
+					webhook("pods", admissionregistrationv1.Fail, "", "pods"),
+					webhook("ingresses", admissionregistrationv1.Fail, "networking.k8s.io", "*"),
+					webhook("ignored", admissionregistrationv1.Ignore, "", "secrets"),
+				},
+			}).Build()
+
+			services, err := webhookServices(context.TODO(), c)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(services).To(HaveLen(4))
+			Expect(services).To(HaveKey(types.NamespacedName{Namespace: "webhooks", Name: "pvc-status"}))
+			Expect(services).ToNot(HaveKey(types.NamespacedName{Namespace: "webhooks", Name: "pods"}))
+			Expect(services).ToNot(HaveKey(types.NamespacedName{Namespace: "webhooks", Name: "ingresses"}))
+		})
+	})
+
+	Describe("drClusterOperatorConditionsUnhealthy", func() {
+		It("rolls up the false conditions, and not those whose checks failed", func() {
+			unhealthy, unchecked := drClusterOperatorConditionsUnhealthy([]metav1.Condition{
+				{Type: "WebhooksHealthy", Status: metav1.ConditionUnknown},
+				{Type: "VeleroAvailable", Status: metav1.ConditionFalse},
+				{Type: "MirrorDaemonsHealthy", Status: metav1.ConditionTrue},
+			})
+			Expect(unhealthy).To(Equal([]string{"VeleroAvailable"}))
+			Expect(unchecked).To(Equal([]string{"WebhooksHealthy"}))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// edgeProfileSyncPeriod is how often the caches of the dr-cluster operator of the Edge profile resync
+	edgeProfileSyncPeriod = 24 * time.Hour
+
+	// edgeProfileStatusReportInterval is the interval between two health reports of the dr-cluster operator of the
+	// Edge profile
+	edgeProfileStatusReportInterval = 10 * time.Minute
+
+	// edgeProfileClusterDataDriftCheckInterval is the minimum time between two checks of the PV cluster data in the
+	// S3 store by the dr-cluster operator of the Edge profile
+	edgeProfileClusterDataDriftCheckInterval = time.Hour
+
+	// edgeProfileMemoryLimit is the memory limit of the dr-cluster operator of the Edge profile, whose Go runtime
+	// collects garbage more often as its heap approaches edgeProfileGoMemoryLimit
+	edgeProfileMemoryLimit   = "96Mi"
+	edgeProfileMemoryRequest = "64Mi"
+	edgeProfileGoMemoryLimit = "80MiB"
+)
+
+// operatorProfileApply configures the dr-cluster operator for the profile of its DRCluster
+func operatorProfileApply(ramenConfig *rmn.RamenConfig, profile rmn.OperatorProfile) {
+	ramenConfig.OperatorProfile = profile
+
+	if profile != rmn.OperatorProfileEdge {
+		return
+	}
+
+	ramenConfig.KubeObjectProtection.Disabled = true
+	ramenConfig.KubeObjectProtection.RecipeValidationWebhookEnabled = false
+	ramenConfig.MaxConcurrentReconciles = 1
+	ramenConfig.SyncPeriod = &metav1.Duration{Duration: edgeProfileSyncPeriod}
+	ramenConfig.Profiling = nil
+}
+
+func operatorProfileValidate(profile rmn.OperatorProfile) error {
+	switch profile {
+	case "", rmn.OperatorProfileStandard, rmn.OperatorProfileEdge:
+		return nil
+	}
+
+	return fmt.Errorf("operatorProfile %s is not one of %s, %s", profile, rmn.OperatorProfileStandard,
+		rmn.OperatorProfileEdge)
+}
+
+// DRClusterOperatorStatusReportIntervalFor returns the interval between two health reports of the dr-cluster
+// operator of a configuration
+func DRClusterOperatorStatusReportIntervalFor(ramenConfig *rmn.RamenConfig) time.Duration {
+	if ramenConfig.OperatorProfile == rmn.OperatorProfileEdge {
+		return edgeProfileStatusReportInterval
+	}
+
+	return DRClusterOperatorStatusReportInterval
+}
+
+func clusterDataDriftCheckIntervalFor(ramenConfig *rmn.RamenConfig) time.Duration {
+	if ramenConfig != nil && ramenConfig.OperatorProfile == rmn.OperatorProfileEdge {
+		return edgeProfileClusterDataDriftCheckInterval
+	}
+
+	return clusterDataDriftCheckInterval
+}
+
+// operatorProfileContainerApply lowers the resources of the dr-cluster operator container deployed with manifests
+// for the profile of its DRCluster
+func operatorProfileContainerApply(container *corev1.Container, profile rmn.OperatorProfile) {
+	if profile != rmn.OperatorProfileEdge {
+		return
+	}
+
+	container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse(edgeProfileMemoryLimit)
+	container.Resources.Requests[corev1.ResourceMemory] = resource.MustParse(edgeProfileMemoryRequest)
+	container.Env = append(container.Env, corev1.EnvVar{Name: "GOMEMLIMIT", Value: edgeProfileGoMemoryLimit})
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the operator profiles of the dr-cluster operator
+package controllers //nolint: testpackage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_OperatorProfile", func() {
+	DescribeTable("operatorProfileValidate",
+		func(profile rmn.OperatorProfile, valid bool) {
+			if valid {
+				Expect(operatorProfileValidate(profile)).To(Succeed())
+			} else {
+				Expect(operatorProfileValidate(profile)).To(MatchError(
+					"operatorProfile Tiny is not one of Standard, Edge"))
+			}
+		},
+		Entry("the default", rmn.OperatorProfile(""), true),
+		Entry("standard", rmn.OperatorProfileStandard, true),
+		Entry("edge", rmn.OperatorProfileEdge, true),
+		Entry("unknown", rmn.OperatorProfile("Tiny"), false),
+	)
+
+	Describe("operatorProfileApply", func() {
+		var ramenConfig *rmn.RamenConfig
+
+		BeforeEach(func() {
+			ramenConfig = &rmn.RamenConfig{MaxConcurrentReconciles: 50, Profiling: &rmn.Profiling{}}
+			ramenConfig.KubeObjectProtection.RecipeValidationWebhookEnabled = true
+		})
+
+		It("keeps the configuration of the standard profile", func() {
+			operatorProfileApply(ramenConfig, rmn.OperatorProfileStandard)
+			Expect(ramenConfig.OperatorProfile).To(Equal(rmn.OperatorProfileStandard))
+			Expect(ramenConfig.MaxConcurrentReconciles).To(Equal(50))
+			Expect(ramenConfig.KubeObjectProtection.Disabled).To(BeFalse())
+			Expect(ramenConfig.Profiling).ToNot(BeNil())
+		})
+
+		It("reduces the footprint of the edge profile", func() {
+			operatorProfileApply(ramenConfig, rmn.OperatorProfileEdge)
+			Expect(ramenConfig.OperatorProfile).To(Equal(rmn.OperatorProfileEdge))
+			Expect(ramenConfig.MaxConcurrentReconciles).To(Equal(1))
+			Expect(ramenConfig.KubeObjectProtection.Disabled).To(BeTrue())
+			Expect(ramenConfig.KubeObjectProtection.RecipeValidationWebhookEnabled).To(BeFalse())
+			Expect(ramenConfig.SyncPeriod).To(Equal(&metav1.Duration{Duration: edgeProfileSyncPeriod}))
+			Expect(ramenConfig.Profiling).To(BeNil())
+		})
+	})
+
+	It("reports health and checks for cluster data drift less often with the edge profile", func() {
+		ramenConfig := &rmn.RamenConfig{}
+		Expect(DRClusterOperatorStatusReportIntervalFor(ramenConfig)).To(Equal(DRClusterOperatorStatusReportInterval))
+		Expect(clusterDataDriftCheckIntervalFor(ramenConfig)).To(Equal(clusterDataDriftCheckInterval))
+		Expect(clusterDataDriftCheckIntervalFor(nil)).To(Equal(clusterDataDriftCheckInterval))
+
+		ramenConfig.OperatorProfile = rmn.OperatorProfileEdge
+		Expect(DRClusterOperatorStatusReportIntervalFor(ramenConfig)).To(Equal(10 * time.Minute))
+		Expect(clusterDataDriftCheckIntervalFor(ramenConfig)).To(Equal(time.Hour))
+	})
+
+	Describe("drClusterOperatorDeployment", func() {
+		container := func(profile rmn.OperatorProfile) corev1.Container {
+			deployment := drClusterOperatorDeployment("ramen-system", "ramen-dr-cluster-operator", "ramen:latest",
+				profile, nil)
+
+			return deployment.Spec.Template.Spec.Containers[0]
+		}
+
+		It("lowers the memory of the operator of the edge profile", func() {
+			standard := container(rmn.OperatorProfileStandard)
+			Expect(standard.Resources.Limits.Memory().Equal(resource.MustParse("300Mi"))).To(BeTrue())
+			Expect(standard.Env).ToNot(ContainElement(HaveField("Name", "GOMEMLIMIT")))
+
+			edge := container(rmn.OperatorProfileEdge)
+			Expect(edge.Resources.Limits.Memory().Equal(resource.MustParse(edgeProfileMemoryLimit))).To(BeTrue())
+			Expect(edge.Resources.Requests.Memory().Equal(resource.MustParse(edgeProfileMemoryRequest))).To(BeTrue())
+			Expect(edge.Env).To(ContainElement(corev1.EnvVar{Name: "GOMEMLIMIT", Value: edgeProfileGoMemoryLimit}))
+		})
+	})
+})
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// drClusterOperatorUpgradeHealthCheckTimeoutDefault is how long an upgraded dr-cluster operator has, by default, to
+// report the version and to report healthy before its upgrade is rolled back
+const drClusterOperatorUpgradeHealthCheckTimeoutDefault = 30 * time.Minute
+
+// appendUpgradeSubscriptionObject appends the dr-cluster operator subscription for the cluster's phase of the
+// operator upgrade
+func (u *drclusterInstance) appendUpgradeSubscriptionObject(
+	ramenConfig *rmn.RamenConfig,
+	objects []interface{},
+) ([]interface{}, error) {
+	current, err := SubscriptionFromDrClusterManifestWork(u.mwUtil, u.object.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if current == nil {
+		current = subscription(
+			drClusterOperatorNamespaceNameOrDefault(ramenConfig),
+			drClusterOperatorChannelNameOrDefault(ramenConfig),
+			drClusterOperatorPackageNameOrDefault(ramenConfig),
+			drClusterOperatorCatalogSourceNameOrDefault(ramenConfig),
+			drClusterOperatorCatalogSourceNamespaceNameOrDefault(ramenConfig),
+			drClusterOperatorClusterServiceVersionNameOrDefault(ramenConfig),
+		)
+	}
+
+	sub, err := u.operatorUpgradeSubscription(ramenConfig, current)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(objects, sub), nil
+}
+
+// operatorUpgradeSubscription advances the upgrade of the cluster's dr-cluster operator, and returns the subscription
+// for its phase: the current subscription while the upgrade is pending, the upgraded subscription once started, and
+// the previous subscription, with manual install plan approval, once rolled back
+func (u *drclusterInstance) operatorUpgradeSubscription(
+	ramenConfig *rmn.RamenConfig,
+	current *operatorsv1alpha1.Subscription,
+) (*operatorsv1alpha1.Subscription, error) {
+	upgrade := ramenConfig.DrClusterOperatorUpgrade
+	status := u.object.Status.OperatorUpgrade
+
+	if status == nil || status.Version != upgrade.Version {
+		status = &rmn.DRClusterOperatorUpgradeStatus{
+			Version:                           upgrade.Version,
+			Phase:                             rmn.DRClusterOperatorUpgradePending,
+			PreviousChannelName:               current.Spec.Channel,
+			PreviousClusterServiceVersionName: current.Spec.StartingCSV,
+		}
+		u.object.Status.OperatorUpgrade = status
+	}
+
+	switch status.Phase {
+	case rmn.DRClusterOperatorUpgradePending:
+		if u.operatorUpgraded(upgrade.Version) {
+			u.operatorUpgradePhaseSet(status, rmn.DRClusterOperatorUpgradeUpgraded, "Operator reports the version")
+
+			return operatorUpgradeSubscription(ramenConfig, upgrade), nil
+		}
+
+		waiting, err := u.operatorUpgradeCanariesWaiting(upgrade)
+		if err != nil {
+			return nil, err
+		}
+
+		if waiting != "" {
+			status.Message = waiting
+
+			return current, nil
+		}
+
+		now := metav1.Now()
+		status.StartTime = &now
+		u.operatorUpgradePhaseSet(status, rmn.DRClusterOperatorUpgradeUpgrading, "Operator subscription upgraded")
+
+		return operatorUpgradeSubscription(ramenConfig, upgrade), nil
+	case rmn.DRClusterOperatorUpgradeUpgrading:
+		if u.operatorUpgraded(upgrade.Version) {
+			u.operatorUpgradePhaseSet(status, rmn.DRClusterOperatorUpgradeUpgraded,
+				"Operator reports the version and reports healthy")
+
+			return operatorUpgradeSubscription(ramenConfig, upgrade), nil
+		}
+
+		timeout := drClusterOperatorUpgradeHealthCheckTimeout(upgrade)
+		if status.StartTime != nil && time.Since(status.StartTime.Time) > timeout {
+			u.operatorUpgradePhaseSet(status, rmn.DRClusterOperatorUpgradeRolledBack,
+				fmt.Sprintf("Operator did not report version %s and healthy within %v; subscription reverted, "+
+					"operator kept at the upgraded cluster service version until it is removed",
+					upgrade.Version, timeout))
+
+			return operatorRollbackSubscription(ramenConfig, status), nil
+		}
+
+		return operatorUpgradeSubscription(ramenConfig, upgrade), nil
+	case rmn.DRClusterOperatorUpgradeUpgraded:
+		return operatorUpgradeSubscription(ramenConfig, upgrade), nil
+	default:
+		return operatorRollbackSubscription(ramenConfig, status), nil
+	}
+}
+
+func (u *drclusterInstance) operatorUpgradePhaseSet(status *rmn.DRClusterOperatorUpgradeStatus,
+	phase rmn.DRClusterOperatorUpgradePhase, message string,
+) {
+	u.log.Info("Operator upgrade phase changed", "version", status.Version, "from", status.Phase, "to", phase,
+		"message", message)
+
+	status.Phase = phase
+	status.Message = message
+}
+
+// operatorUpgraded returns whether the cluster's dr-cluster operator reports a version and reports healthy
+func (u *drclusterInstance) operatorUpgraded(version string) bool {
+	report := u.object.Status.Operator
+	if report == nil || report.Version == "" {
+		return false
+	}
+
+	skew, err := versionCompare(report.Version, version)
+	if err != nil || skew != 0 {
+		return false
+	}
+
+	return meta.IsStatusConditionTrue(u.object.Status.Conditions, rmn.DRClusterConditionTypeOperatorHealthy)
+}
+
+// operatorUpgradeCanariesWaiting returns why the upgrade of a cluster, that is not a canary, waits for the canary
+// clusters, or an empty string once they are all upgraded
+func (u *drclusterInstance) operatorUpgradeCanariesWaiting(upgrade *rmn.DrClusterOperatorUpgrade) (string, error) {
+	if len(upgrade.CanaryClusterNames) == 0 || slices.Contains(upgrade.CanaryClusterNames, u.object.Name) {
+		return "", nil
+	}
+
+	drClusters := &rmn.DRClusterList{}
+	if err := u.client.List(u.ctx, drClusters); err != nil {
+		return "", fmt.Errorf("drclusters list: %w", err)
+	}
+
+	pending := []string{}
+
+	for _, canaryName := range upgrade.CanaryClusterNames {
+		idx := slices.IndexFunc(drClusters.Items, func(drCluster rmn.DRCluster) bool {
+			return drCluster.Name == canaryName
+		})
+		if idx == -1 {
+			pending = append(pending, canaryName)
+
+			continue
+		}
+
+		status := drClusters.Items[idx].Status.OperatorUpgrade
+		if status == nil || status.Version != upgrade.Version {
+			pending = append(pending, canaryName)
+
+			continue
+		}
+
+		if status.Phase == rmn.DRClusterOperatorUpgradeRolledBack {
+			return fmt.Sprintf("Canary cluster %s rolled back", canaryName), nil
+		}
+
+		if status.Phase != rmn.DRClusterOperatorUpgradeUpgraded {
+			pending = append(pending, canaryName)
+		}
+	}
+
+	if len(pending) > 0 {
+		return "Waiting for canary clusters to be upgraded: " + strings.Join(pending, ", "), nil
+	}
+
+	return "", nil
+}
+
+func drClusterOperatorUpgradeHealthCheckTimeout(upgrade *rmn.DrClusterOperatorUpgrade) time.Duration {
+	if upgrade.HealthCheckTimeout == nil {
+		return drClusterOperatorUpgradeHealthCheckTimeoutDefault
+	}
+
+	return upgrade.HealthCheckTimeout.Duration
+}
+
+func operatorUpgradeSubscription(
+	ramenConfig *rmn.RamenConfig,
+	upgrade *rmn.DrClusterOperatorUpgrade,
+) *operatorsv1alpha1.Subscription {
+	channelName := upgrade.ChannelName
+	if channelName == "" {
+		channelName = drClusterOperatorChannelNameOrDefault(ramenConfig)
+	}
+
+	clusterServiceVersionName := upgrade.ClusterServiceVersionName
+	if clusterServiceVersionName == "" {
+		clusterServiceVersionName = drClusterOperatorClusterServiceVersionNameOrDefault(ramenConfig)
+	}
+
+	return subscription(
+		drClusterOperatorNamespaceNameOrDefault(ramenConfig),
+		channelName,
+		drClusterOperatorPackageNameOrDefault(ramenConfig),
+		drClusterOperatorCatalogSourceNameOrDefault(ramenConfig),
+		drClusterOperatorCatalogSourceNamespaceNameOrDefault(ramenConfig),
+		clusterServiceVersionName,
+	)
+}
+
+// operatorRollbackSubscription returns the subscription before the upgrade, with manual install plan approval, for
+// OLM to not upgrade the operator again. It reverts the subscription manifest only: OLM does not downgrade the
+// installed cluster service version, which is to be removed from the cluster for OLM to install the previous one.
+func operatorRollbackSubscription(
+	ramenConfig *rmn.RamenConfig,
+	status *rmn.DRClusterOperatorUpgradeStatus,
+) *operatorsv1alpha1.Subscription {
+	sub := subscription(
+		drClusterOperatorNamespaceNameOrDefault(ramenConfig),
+		status.PreviousChannelName,
+		drClusterOperatorPackageNameOrDefault(ramenConfig),
+		drClusterOperatorCatalogSourceNameOrDefault(ramenConfig),
+		drClusterOperatorCatalogSourceNamespaceNameOrDefault(ramenConfig),
+		status.PreviousClusterServiceVersionName,
+	)
+	sub.Spec.InstallPlanApproval = operatorsv1alpha1.ApprovalManual
+
+	return sub
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the phases of the dr-cluster operator upgrades and their subscriptions
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_OperatorUpgrade", func() {
+	var (
+		ramenConfig *ramen.RamenConfig
+		u           *drclusterInstance
+		current     *operatorsv1alpha1.Subscription
+	)
+
+	healthy := func(version string) {
+		u.object.Status.Operator = &ramen.DRClusterOperatorReport{Version: version}
+		setDRClusterOperatorHealthyCondition(&u.object.Status.Conditions, 0, metav1.ConditionTrue,
+			DRClusterConditionReasonOperatorHealthy, "")
+	}
+
+	BeforeEach(func() {
+		ramenConfig = &ramen.RamenConfig{DrClusterOperatorUpgrade: &ramen.DrClusterOperatorUpgrade{
+			Version:                   "0.2.0",
+			ChannelName:               "beta",
+			ClusterServiceVersionName: "ramen-dr-cluster-operator.v0.2.0",
+			HealthCheckTimeout:        &metav1.Duration{Duration: time.Minute},
+		}}
+		u = &drclusterInstance{
+			ctx:    context.TODO(),
+			object: &ramen.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: "east"}},
+			client: fake.NewClientBuilder().Build(),
+			log:    ctrl.Log.WithName("drcluster-operator-upgrade-test"),
+		}
+		current = subscription("ramen-system", "alpha", "ramen-dr-cluster-operator", "ramen-catalog",
+			"openshift-marketplace", "ramen-dr-cluster-operator.v0.1.0")
+	})
+
+	It("upgrades the subscription, until the operator reports the version and healthy", func() {
+		sub, err := u.operatorUpgradeSubscription(ramenConfig, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.object.Status.OperatorUpgrade.Phase).To(Equal(ramen.DRClusterOperatorUpgradeUpgrading))
+		Expect(sub.Spec.Channel).To(Equal("beta"))
+		Expect(sub.Spec.StartingCSV).To(Equal("ramen-dr-cluster-operator.v0.2.0"))
+
+		healthy("0.2.0")
+
+		_, err = u.operatorUpgradeSubscription(ramenConfig, sub)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.object.Status.OperatorUpgrade.Phase).To(Equal(ramen.DRClusterOperatorUpgradeUpgraded))
+	})
+
+	It("reverts the subscription manifest, with manual approval, once the health check times out", func() {
+		sub, err := u.operatorUpgradeSubscription(ramenConfig, current)
+		Expect(err).ToNot(HaveOccurred())
+
+		healthy("0.1.0")
+
+		startTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		u.object.Status.OperatorUpgrade.StartTime = &startTime
+
+		sub, err = u.operatorUpgradeSubscription(ramenConfig, sub)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.object.Status.OperatorUpgrade.Phase).To(Equal(ramen.DRClusterOperatorUpgradeRolledBack))
+		Expect(u.object.Status.OperatorUpgrade.Message).To(ContainSubstring("until it is removed"))
+		Expect(sub.Spec.Channel).To(Equal("alpha"))
+		Expect(sub.Spec.StartingCSV).To(Equal("ramen-dr-cluster-operator.v0.1.0"))
+		Expect(sub.Spec.InstallPlanApproval).To(Equal(operatorsv1alpha1.ApprovalManual))
+
+		healthy("0.2.0")
+
+		sub, err = u.operatorUpgradeSubscription(ramenConfig, sub)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u.object.Status.OperatorUpgrade.Phase).To(Equal(ramen.DRClusterOperatorUpgradeRolledBack))
+		Expect(sub.Spec.StartingCSV).To(Equal("ramen-dr-cluster-operator.v0.1.0"))
+	})
+
+	It("waits for the canary clusters to be upgraded", func() {
+		scheme := runtime.NewScheme()
+		Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+		ramenConfig.DrClusterOperatorUpgrade.CanaryClusterNames = []string{"west"}
+		u.client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&ramen.DRCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "west"},
+			Status: ramen.DRClusterStatus{OperatorUpgrade: &ramen.DRClusterOperatorUpgradeStatus{
+				Version: "0.2.0", Phase: ramen.DRClusterOperatorUpgradeRolledBack,
+			}},
+		}).WithStatusSubresource(&ramen.DRCluster{}).Build()
+
+		sub, err := u.operatorUpgradeSubscription(ramenConfig, current)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sub).To(Equal(current))
+		Expect(u.object.Status.OperatorUpgrade.Phase).To(Equal(ramen.DRClusterOperatorUpgradePending))
+		Expect(u.object.Status.OperatorUpgrade.Message).To(Equal("Canary cluster west rolled back"))
+	})
+})
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	DRClusterConditionReasonReachable   = "Reachable"
+	DRClusterConditionReasonUnreachable = "CircuitOpen"
+
+	clusterBreakerEventsSize = 64
+)
+
+// clusterBreakerWatch reconciles a DRCluster as the circuit breaker of its managed cluster opens or closes, to
+// report its Reachable condition
+func (r *DRClusterReconciler) clusterBreakerWatch(controller *builder.Builder) *builder.Builder {
+	if r.ClusterBreaker == nil {
+		return controller
+	}
+
+	events := make(chan event.GenericEvent, clusterBreakerEventsSize)
+
+	r.ClusterBreaker.OnStateChange(func(cluster string, open bool) {
+		r.Log.Info("Managed cluster circuit breaker state changed", "cluster", cluster, "open", open)
+
+		select {
+		case events <- event.GenericEvent{Object: &ramen.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: cluster}}}:
+		default:
+			// The DRCluster reconciles on its next event, or the requeue of an open breaker
+		}
+	})
+
+	return controller.WatchesRawSource(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})
+}
+
+// reachableConditionSet sets the Reachable condition from the state of the managed cluster's circuit breaker, and
+// returns the time after which an open breaker allows a probe
+func (u *drclusterInstance) reachableConditionSet() time.Duration {
+	if u.reconciler.ClusterBreaker == nil {
+		return 0
+	}
+
+	state := u.reconciler.ClusterBreaker.State(u.object.Name)
+	if !state.Open {
+		setDRClusterReachableCondition(&u.object.Status.Conditions, u.object.Generation, metav1.ConditionTrue,
+			DRClusterConditionReasonReachable, "Managed cluster operations are succeeding")
+
+		return 0
+	}
+
+	setDRClusterReachableCondition(&u.object.Status.Conditions, u.object.Generation, metav1.ConditionFalse,
+		DRClusterConditionReasonUnreachable,
+		fmt.Sprintf("Managed cluster operations refused after %d consecutive failures, last error: %s",
+			state.Failures, state.LastError))
+
+	return state.RetryAfter + time.Second
+}
+
+func setDRClusterReachableCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	status metav1.ConditionStatus, reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               ramen.DRClusterConditionTypeReachable,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             status,
+		Message:            message,
+	})
+}
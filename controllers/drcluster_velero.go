@@ -0,0 +1,409 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	veleroOperatorChannelNameDefault                = "stable-1.3"
+	veleroOperatorPackageNameDefault                = "redhat-oadp-operator"
+	veleroOperatorCatalogSourceNameDefault          = "redhat-operators"
+	veleroOperatorCatalogSourceNamespaceNameDefault = "openshift-marketplace"
+	veleroImageDefault                              = "velero/velero:v1.13.2"
+	veleroAWSPluginImageDefault                     = "velero/velero-plugin-for-aws:v1.9.2"
+
+	// veleroDataProtectionApplicationName is the name of the OADP DataProtectionApplication that deploys Velero
+	veleroDataProtectionApplicationName = "ramen-velero"
+
+	// veleroBackupStorageLocationPrefix is the key prefix of the default backup storage location of Velero. The
+	// backup storage locations of the VRGs have key prefixes of their own.
+	veleroBackupStorageLocationPrefix = "velero"
+
+	veleroEditClusterRoleName = "open-cluster-management:klusterlet-work-sa:agent:oadp-edit"
+
+	veleroBackupStorageLocationEditClusterRoleName = "open-cluster-management:klusterlet-work-sa:agent:velero-edit"
+
+	// veleroServiceAccountName is the name of the service account Velero runs as, when deployed without OLM
+	veleroServiceAccountName = "velero"
+)
+
+// veleroDeploy deploys Velero to the cluster, if its deployment automation is enabled and the cluster's dr-cluster
+// operator reports Velero missing: with the OADP operator, or without OLM if the dr-cluster operator is deployed
+// without it. Once deployed, Velero stays deployed until the DRCluster is deleted, so that it is not undeployed once
+// it is reported available.
+func (u *drclusterInstance) veleroDeploy(ramenConfig *rmn.RamenConfig) error {
+	if !veleroDeploymentAutomationEnabled(ramenConfig) {
+		return nil
+	}
+
+	deployed := true
+
+	if _, err := u.mwUtil.FindManifestWork(util.DrClusterVeleroManifestWorkName, u.object.Name); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed fetching cluster velero manifest work %w", err)
+		}
+
+		deployed = false
+	}
+
+	if !deployed && !veleroMissing(u.object.Status.Operator) {
+		return nil
+	}
+
+	objects, err := veleroObjects(u.object, ramenConfig)
+	if err != nil {
+		return fmt.Errorf("velero objects: %w", err)
+	}
+
+	if !deployed {
+		u.log.Info("Deploying Velero, as the cluster reports it missing")
+	}
+
+	return u.mwUtil.CreateOrUpdateDrClusterVeleroManifestWork(u.object.Name, objects,
+		map[string]string{DRClusterNameAnnotation: u.mwUtil.InstName})
+}
+
+func veleroDeploymentAutomationEnabled(ramenConfig *rmn.RamenConfig) bool {
+	return ramenConfig.DrClusterOperator.DeploymentAutomationEnabled &&
+		!ramenConfig.KubeObjectProtection.Disabled &&
+		ramenConfig.KubeObjectProtection.VeleroDeployment.AutomationEnabled
+}
+
+// veleroMissing returns whether the dr-cluster operator reports that the Velero deployment is not found
+func veleroMissing(report *rmn.DRClusterOperatorReport) bool {
+	return report != nil && report.VeleroMissing
+}
+
+// veleroObjects returns the objects that deploy Velero to the velero namespace, with a default backup storage
+// location of the s3 profile, whose credentials are those of the velero formatted s3 secret distributed to the
+// velero namespace
+func veleroObjects(drcluster *rmn.DRCluster, ramenConfig *rmn.RamenConfig) ([]interface{}, error) {
+	namespaceName := ramenConfig.KubeObjectProtection.VeleroNamespaceName
+	if namespaceName == "" {
+		return nil, fmt.Errorf("kubeObjectProtection veleroNamespaceName is not set")
+	}
+
+	backupLocation, err := veleroBackupLocation(ramenConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if drcluster.Spec.OperatorDeploymentMode == rmn.OperatorDeploymentModeManifests {
+		return veleroManifestObjects(namespaceName, backupLocation, ramenConfig), nil
+	}
+
+	return veleroOADPObjects(namespaceName, backupLocation, ramenConfig), nil
+}
+
+// veleroOADPObjects returns the objects that deploy Velero with the OADP operator: its operator group and
+// subscription, and a DataProtectionApplication with the backup storage location
+func veleroOADPObjects(namespaceName string, backupLocation map[string]interface{}, ramenConfig *rmn.RamenConfig,
+) []interface{} {
+	veleroDeployment := ramenConfig.KubeObjectProtection.VeleroDeployment
+
+	configuration := map[string]interface{}{
+		"velero": map[string]interface{}{
+			"defaultPlugins": []string{"openshift", "aws"},
+		},
+	}
+
+	if veleroDeployment.NodeAgentEnabled {
+		configuration["nodeAgent"] = map[string]interface{}{
+			"enable":       true,
+			"uploaderType": "kopia",
+		}
+	}
+
+	return []interface{}{
+		util.Namespace(namespaceName),
+		olmRoleBinding(namespaceName),
+		workAgentEditClusterRole(veleroEditClusterRoleName, "oadp.openshift.io", "dataprotectionapplications"),
+		workAgentEditClusterRoleBinding(veleroEditClusterRoleName),
+		&operatorsv1.OperatorGroup{
+			TypeMeta:   metav1.TypeMeta{Kind: "OperatorGroup", APIVersion: "operators.coreos.com/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "ramen-operator-group", Namespace: namespaceName},
+			Spec:       operatorsv1.OperatorGroupSpec{TargetNamespaces: []string{namespaceName}},
+		},
+		&operatorsv1alpha1.Subscription{
+			TypeMeta:   metav1.TypeMeta{Kind: "Subscription", APIVersion: "operators.coreos.com/v1alpha1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "ramen-velero-subscription", Namespace: namespaceName},
+			Spec: &operatorsv1alpha1.SubscriptionSpec{
+				CatalogSource: stringOrDefault(veleroDeployment.CatalogSourceName,
+					veleroOperatorCatalogSourceNameDefault),
+				CatalogSourceNamespace: stringOrDefault(veleroDeployment.CatalogSourceNamespaceName,
+					veleroOperatorCatalogSourceNamespaceNameDefault),
+				Package:             stringOrDefault(veleroDeployment.PackageName, veleroOperatorPackageNameDefault),
+				Channel:             stringOrDefault(veleroDeployment.ChannelName, veleroOperatorChannelNameDefault),
+				InstallPlanApproval: "Automatic",
+			},
+		},
+		map[string]interface{}{
+			"apiVersion": "oadp.openshift.io/v1alpha1",
+			"kind":       "DataProtectionApplication",
+			"metadata": map[string]interface{}{
+				"name":      veleroDataProtectionApplicationName,
+				"namespace": namespaceName,
+			},
+			"spec": map[string]interface{}{
+				"configuration":   configuration,
+				"backupLocations": []interface{}{map[string]interface{}{"velero": backupLocation}},
+			},
+		},
+	}
+}
+
+// veleroManifestObjects returns the objects that deploy Velero without OLM, as its install command does: its
+// service account, bound to the cluster-admin role, a job that installs its custom resource definitions, its
+// deployment with the AWS plugin, the backup storage location, and the node agent if enabled
+func veleroManifestObjects(namespaceName string, backupLocation map[string]interface{},
+	ramenConfig *rmn.RamenConfig,
+) []interface{} {
+	veleroDeployment := ramenConfig.KubeObjectProtection.VeleroDeployment
+	image := stringOrDefault(veleroDeployment.Image, veleroImageDefault)
+	labels := map[string]string{"component": "velero"}
+	env := []corev1.EnvVar{
+		{Name: "VELERO_SCRATCH_DIR", Value: "/scratch"},
+		{Name: "VELERO_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+		}},
+	}
+	scratch := corev1.Volume{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+
+	objects := []interface{}{
+		util.Namespace(namespaceName),
+		workAgentEditClusterRole(veleroBackupStorageLocationEditClusterRoleName, "velero.io",
+			"backupstoragelocations"),
+		workAgentEditClusterRoleBinding(veleroBackupStorageLocationEditClusterRoleName),
+		&corev1.ServiceAccount{
+			TypeMeta:   metav1.TypeMeta{Kind: "ServiceAccount", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: veleroServiceAccountName, Namespace: namespaceName, Labels: labels},
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "velero-" + namespaceName, Labels: labels},
+			Subjects: []rbacv1.Subject{
+				{Kind: "ServiceAccount", Name: veleroServiceAccountName, Namespace: namespaceName},
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "cluster-admin"},
+		},
+		&batchv1.Job{
+			TypeMeta:   metav1.TypeMeta{Kind: "Job", APIVersion: "batch/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "velero-crds", Namespace: namespaceName, Labels: labels},
+			Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ServiceAccountName: veleroServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					Containers: []corev1.Container{{
+						Name:    "velero-crds",
+						Image:   image,
+						Command: []string{"/velero"},
+						Args:    []string{"install", "--crds-only"},
+					}},
+				},
+			}},
+		},
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: veleroDeploymentName, Namespace: namespaceName, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"deploy": veleroDeploymentName}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+						"component": "velero", "deploy": veleroDeploymentName,
+					}},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: veleroServiceAccountName,
+						InitContainers: []corev1.Container{{
+							Name:         "velero-plugin-for-aws",
+							Image:        stringOrDefault(veleroDeployment.AWSPluginImage, veleroAWSPluginImageDefault),
+							VolumeMounts: []corev1.VolumeMount{{Name: "plugins", MountPath: "/target"}},
+						}},
+						Containers: []corev1.Container{{
+							Name:    veleroDeploymentName,
+							Image:   image,
+							Command: []string{"/velero"},
+							Args:    []string{"server", "--uploader-type=kopia", "--features=" + veleroFeatureAPIGroupVersions},
+							Env:     env,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "plugins", MountPath: "/plugins"},
+								{Name: scratch.Name, MountPath: "/scratch"},
+							},
+						}},
+						Volumes: []corev1.Volume{
+							{Name: "plugins", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+							scratch,
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"apiVersion": "velero.io/v1",
+			"kind":       "BackupStorageLocation",
+			"metadata": map[string]interface{}{
+				"name":      "default",
+				"namespace": namespaceName,
+			},
+			"spec": backupLocation,
+		},
+	}
+
+	if !veleroDeployment.NodeAgentEnabled {
+		return objects
+	}
+
+	hostToContainer := corev1.MountPropagationHostToContainer
+	rootUser := int64(0)
+
+	return append(objects, &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{Kind: "DaemonSet", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "node-agent", Namespace: namespaceName, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "node-agent"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"component": "velero", "name": "node-agent"}},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: veleroServiceAccountName,
+					SecurityContext:    &corev1.PodSecurityContext{RunAsUser: &rootUser},
+					Containers: []corev1.Container{{
+						Name:    "node-agent",
+						Image:   image,
+						Command: []string{"/velero"},
+						Args:    []string{"node-agent", "server"},
+						Env: append([]corev1.EnvVar{{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{
+							FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+						}}}, env...),
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "host-pods", MountPath: "/host_pods", MountPropagation: &hostToContainer},
+							{Name: scratch.Name, MountPath: "/scratch"},
+						},
+					}},
+					Volumes: []corev1.Volume{
+						{Name: "host-pods", VolumeSource: corev1.VolumeSource{
+							HostPath: &corev1.HostPathVolumeSource{Path: "/var/lib/kubelet/pods"},
+						}},
+						scratch,
+					},
+				},
+			},
+		},
+	})
+}
+
+// veleroBackupLocation returns the default backup storage location of Velero, of the configured s3 profile, or
+// the first one if not configured
+func veleroBackupLocation(ramenConfig *rmn.RamenConfig) (map[string]interface{}, error) {
+	if !ramenConfig.DrClusterOperator.S3SecretDistributionEnabled {
+		return nil, fmt.Errorf("drClusterOperator s3SecretDistributionEnabled is not set")
+	}
+
+	if len(ramenConfig.S3StoreProfiles) == 0 {
+		return nil, fmt.Errorf("no s3 profiles")
+	}
+
+	s3StoreProfile := &ramenConfig.S3StoreProfiles[0]
+
+	if s3ProfileName := ramenConfig.KubeObjectProtection.VeleroDeployment.S3ProfileName; s3ProfileName != "" {
+		s3StoreProfile = nil
+
+		for i := range ramenConfig.S3StoreProfiles {
+			if ramenConfig.S3StoreProfiles[i].S3ProfileName == s3ProfileName {
+				s3StoreProfile = &ramenConfig.S3StoreProfiles[i]
+
+				break
+			}
+		}
+
+		if s3StoreProfile == nil {
+			return nil, fmt.Errorf("s3 profile %s not found", s3ProfileName)
+		}
+	}
+
+	secretRef, err := drClusterSecretRef(s3StoreProfile, ramenConfig)
+	if err != nil {
+		return nil, fmt.Errorf("s3 profile %s: %w", s3StoreProfile.S3ProfileName, err)
+	}
+
+	objectStorage := map[string]interface{}{
+		"bucket": s3StoreProfile.S3Bucket,
+		"prefix": veleroBackupStorageLocationPrefix,
+	}
+
+	if len(s3StoreProfile.CACertificates) > 0 {
+		objectStorage["caCert"] = s3StoreProfile.CACertificates
+	}
+
+	return map[string]interface{}{
+		"provider":      "aws",
+		"default":       true,
+		"objectStorage": objectStorage,
+		"config": map[string]string{
+			"region":           s3StoreProfile.S3Region,
+			"s3Url":            s3StoreProfile.S3CompatibleEndpoint,
+			"s3ForcePathStyle": "true",
+			"profile":          "default",
+		},
+		"credential": map[string]string{
+			"name": util.GenerateVeleroSecretName(secretRef.Name),
+			"key":  util.VeleroSecretKeyNameDefault,
+		},
+	}, nil
+}
+
+func stringOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// workAgentEditClusterRole returns the ClusterRole that lets the work agent create and update the custom resources
+// it deploys Velero with
+func workAgentEditClusterRole(name, group, resource string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{group},
+				Resources: []string{resource},
+				Verbs:     []string{"create", "get", "list", "update", "delete"},
+			},
+		},
+	}
+}
+
+func workAgentEditClusterRoleBinding(name string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      "klusterlet-work-sa",
+				Namespace: "open-cluster-management-agent",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     name,
+		},
+	}
+}
@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the deployment of Velero to the clusters that report it missing
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_Velero", func() {
+	var (
+		drcluster   *rmn.DRCluster
+		ramenConfig *rmn.RamenConfig
+	)
+
+	kinds := func(objects []interface{}) []string {
+		kinds := []string{}
+
+		for _, object := range objects {
+			switch object := object.(type) {
+			case map[string]interface{}:
+				kinds = append(kinds, object["kind"].(string))
+			case runtime.Object:
+				kinds = append(kinds, object.GetObjectKind().GroupVersionKind().Kind)
+			}
+		}
+
+		return kinds
+	}
+
+	BeforeEach(func() {
+		drcluster = &rmn.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: "east"}}
+		ramenConfig = &rmn.RamenConfig{}
+		ramenConfig.DrClusterOperator.S3SecretDistributionEnabled = true
+		ramenConfig.KubeObjectProtection.VeleroNamespaceName = "velero"
+		ramenConfig.S3StoreProfiles = []rmn.S3StoreProfile{
+			{S3ProfileName: "east", S3Bucket: "east-bucket", S3SecretRef: corev1.SecretReference{Name: "east-secret"}},
+			{
+				S3ProfileName: "west", S3Bucket: "west-bucket", S3SecretRef: corev1.SecretReference{Name: "west-secret"},
+				CACertificates: []byte("ca"),
+			},
+		}
+	})
+
+	It("deploys Velero once the dr-cluster operator reports it missing", func() {
+		Expect(veleroMissing(nil)).To(BeFalse())
+		Expect(veleroMissing(&rmn.DRClusterOperatorReport{})).To(BeFalse())
+		Expect(veleroMissing(&rmn.DRClusterOperatorReport{VeleroMissing: true})).To(BeTrue())
+	})
+
+	Describe("veleroBackupLocation", func() {
+		It("stores to the first s3 profile, unless configured otherwise", func() {
+			location, err := veleroBackupLocation(ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(location["objectStorage"]).To(Equal(map[string]interface{}{
+				"bucket": "east-bucket", "prefix": veleroBackupStorageLocationPrefix,
+			}))
+
+			ramenConfig.KubeObjectProtection.VeleroDeployment.S3ProfileName = "west"
+			location, err = veleroBackupLocation(ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(location["objectStorage"]).To(HaveKeyWithValue("caCert", []byte("ca")))
+		})
+
+		It("fails for an s3 profile not found, or s3 secrets not distributed", func() {
+			ramenConfig.KubeObjectProtection.VeleroDeployment.S3ProfileName = "north"
+			_, err := veleroBackupLocation(ramenConfig)
+			Expect(err).To(MatchError(ContainSubstring("north")))
+
+			ramenConfig.DrClusterOperator.S3SecretDistributionEnabled = false
+			_, err = veleroBackupLocation(ramenConfig)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("veleroObjects", func() {
+		It("deploys Velero with the OADP operator", func() {
+			objects, err := veleroObjects(drcluster, ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(objects)).To(ContainElements("Subscription", "DataProtectionApplication"))
+			Expect(kinds(objects)).ToNot(ContainElement("BackupStorageLocation"))
+			Expect(objects).To(ContainElement(WithTransform(func(object interface{}) string {
+				subscription, ok := object.(*operatorsv1alpha1.Subscription)
+				if !ok {
+					return ""
+				}
+
+				return subscription.Spec.Package
+			}, Equal(veleroOperatorPackageNameDefault))))
+		})
+
+		It("deploys Velero without OLM to the clusters the dr-cluster operator is deployed to without it", func() {
+			drcluster.Spec.OperatorDeploymentMode = rmn.OperatorDeploymentModeManifests
+			ramenConfig.KubeObjectProtection.VeleroDeployment.Image = "velero/velero:test"
+
+			objects, err := veleroObjects(drcluster, ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(objects)).To(ContainElements("ServiceAccount", "Job", "Deployment", "BackupStorageLocation"))
+			Expect(kinds(objects)).ToNot(ContainElements("Subscription", "DaemonSet"))
+
+			for _, object := range objects {
+				switch object := object.(type) {
+				case *appsv1.Deployment:
+					Expect(object.Name).To(Equal(veleroDeploymentName))
+					Expect(object.Spec.Template.Spec.Containers[0].Image).To(Equal("velero/velero:test"))
+					Expect(object.Spec.Template.Spec.InitContainers[0].Image).To(Equal(veleroAWSPluginImageDefault))
+				case *batchv1.Job:
+					Expect(object.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"install", "--crds-only"}))
+				}
+			}
+
+			ramenConfig.KubeObjectProtection.VeleroDeployment.NodeAgentEnabled = true
+			objects, err = veleroObjects(drcluster, ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(kinds(objects)).To(ContainElement("DaemonSet"))
+		})
+
+		It("fails if the velero namespace is not configured", func() {
+			ramenConfig.KubeObjectProtection.VeleroNamespaceName = ""
+			_, err := veleroObjects(drcluster, ramenConfig)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("veleroAvailableCondition", func() {
+		It("reports the Velero deployment unhealthy and missing if it is not found", func() {
+			r := &DRClusterOperatorStatusReporter{APIReader: fake.NewClientBuilder().Build()}
+
+			condition, missing := r.veleroAvailableCondition(context.TODO(), ramenConfig)
+			Expect(missing).To(BeTrue())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(DRClusterOperatorReasonUnhealthy))
+
+			r.APIReader = fake.NewClientBuilder().WithObjects(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "velero", Name: veleroDeploymentName},
+				Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+				}},
+			}).Build()
+
+			condition, missing = r.veleroAvailableCondition(context.TODO(), ramenConfig)
+			Expect(missing).To(BeFalse())
+			Expect(condition.Reason).To(Equal(DRClusterOperatorReasonHealthy))
+
+			ramenConfig.KubeObjectProtection.Disabled = true
+			_, missing = r.veleroAvailableCondition(context.TODO(), ramenConfig)
+			Expect(missing).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	DRClusterConditionReasonVersionsMatch      = "VersionsMatch"
+	DRClusterConditionReasonOlderThanHub       = "OlderThanHub"
+	DRClusterConditionReasonNewerThanHub       = "NewerThanHub"
+	DRClusterConditionReasonVersionNotParsed   = "VersionNotParsed"
+	DRClusterConditionReasonVersionNotReported = "NotReported"
+)
+
+// versionSkewConditionSet compares the version the dr-cluster operator reports to the hub operator's, and sets the
+// SkewDetected condition and the version skew metric. It is to be called once the operator report is copied to the
+// DRCluster status.
+func (u *drclusterInstance) versionSkewConditionSet() {
+	report := u.object.Status.Operator
+
+	if report == nil || report.Version == "" {
+		DeleteDRClusterVersionSkewMetric(DRClusterVersionSkewMetricLabels(u.object))
+		setDRClusterSkewDetectedCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionUnknown, DRClusterConditionReasonVersionNotReported, "Operator version not reported yet")
+
+		return
+	}
+
+	skew, err := versionCompare(report.Version, Version)
+	if err != nil {
+		DeleteDRClusterVersionSkewMetric(DRClusterVersionSkewMetricLabels(u.object))
+		setDRClusterSkewDetectedCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionUnknown, DRClusterConditionReasonVersionNotParsed, err.Error())
+
+		return
+	}
+
+	NewDRClusterVersionSkewMetric(DRClusterVersionSkewMetricLabels(u.object)).VersionSkew.Set(float64(skew))
+
+	message := fmt.Sprintf("Operator version %s, hub version %s", report.Version, Version)
+
+	if unparsed := vrgSpecFieldsUnparsed(report.VRGSpecFields); len(unparsed) > 0 {
+		message += "; VRG fields not sent: " + strings.Join(unparsed, ", ")
+	}
+
+	switch {
+	case skew < 0:
+		setDRClusterSkewDetectedCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionTrue, DRClusterConditionReasonOlderThanHub, message)
+	case skew > 0:
+		setDRClusterSkewDetectedCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionTrue, DRClusterConditionReasonNewerThanHub, message)
+	default:
+		setDRClusterSkewDetectedCondition(&u.object.Status.Conditions, u.object.Generation,
+			metav1.ConditionFalse, DRClusterConditionReasonVersionsMatch, message)
+	}
+}
+
+// versionCompare returns -1, 0 or 1 as a version is older than, the same as, or newer than another
+func versionCompare(version, other string) (int, error) {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return 0, fmt.Errorf("version %s not parsed: %w", version, err)
+	}
+
+	o, err := semver.ParseTolerant(other)
+	if err != nil {
+		return 0, fmt.Errorf("version %s not parsed: %w", other, err)
+	}
+
+	return v.Compare(o), nil
+}
+
+func setDRClusterSkewDetectedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	status metav1.ConditionStatus, reason, message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               ramen.DRClusterConditionTypeSkewDetected,
+		Reason:             reason,
+		ObservedGeneration: observedGeneration,
+		Status:             status,
+		Message:            message,
+	})
+}
+
+// vrgSpecFields returns the json paths of the VolumeReplicationGroup spec fields this operator parses, including the
+// fields nested in the structures of the ramen API, like kubeObjectProtection.captureInterval
+func vrgSpecFields() []string {
+	return apiStructFieldPaths(reflect.TypeOf(ramen.VolumeReplicationGroupSpec{}), "")
+}
+
+// apiStructFieldPaths returns the json paths, prefixed, of the fields of a structure, and of the fields nested in the
+// structures of the ramen API it refers to, directly or by pointer, slice or map. The fields of the structures of
+// other APIs, such as a label selector, are not listed, as they are parsed by an operator that parses the field.
+func apiStructFieldPaths(structType reflect.Type, prefix string) []string {
+	paths := []string{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		name := jsonFieldName(structType.Field(i))
+		if name == "" {
+			continue
+		}
+
+		path := prefix + name
+		paths = append(paths, path)
+
+		if nested := apiStructType(structType.Field(i).Type); nested != nil {
+			paths = append(paths, apiStructFieldPaths(nested, path+".")...)
+		}
+	}
+
+	return paths
+}
+
+// apiStructType returns the structure of the ramen API a type refers to, or nil
+func apiStructType(fieldType reflect.Type) reflect.Type {
+	for fieldType.Kind() == reflect.Pointer || fieldType.Kind() == reflect.Slice ||
+		fieldType.Kind() == reflect.Array || fieldType.Kind() == reflect.Map {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType.Kind() != reflect.Struct ||
+		fieldType.PkgPath() != reflect.TypeOf(ramen.VolumeReplicationGroupSpec{}).PkgPath() {
+		return nil
+	}
+
+	return fieldType
+}
+
+// vrgSpecFieldsUnparsed returns the paths of the VolumeReplicationGroup spec fields of this operator that are not
+// among those a dr-cluster operator parses, but those nested in a field it does not parse either. Operators that do
+// not report the fields they parse are assumed to parse all of them.
+func vrgSpecFieldsUnparsed(parsed []string) []string {
+	unparsed := []string{}
+
+	if len(parsed) == 0 {
+		return unparsed
+	}
+
+	parsedSet := map[string]struct{}{}
+	for _, path := range parsed {
+		parsedSet[path] = struct{}{}
+	}
+
+	for _, path := range vrgSpecFields() {
+		if _, ok := parsedSet[path]; ok {
+			continue
+		}
+
+		if len(unparsed) > 0 && strings.HasPrefix(path, unparsed[len(unparsed)-1]+".") {
+			continue
+		}
+
+		unparsed = append(unparsed, path)
+	}
+
+	return unparsed
+}
+
+// vrgSpecFieldsClear clears the fields of a VolumeReplicationGroup spec that a dr-cluster operator does not parse,
+// in each element of the slices and maps they are nested in, and returns the paths of those that were set
+func vrgSpecFieldsClear(spec *ramen.VolumeReplicationGroupSpec, parsed []string) []string {
+	cleared := []string{}
+
+	for _, path := range vrgSpecFieldsUnparsed(parsed) {
+		if fieldPathClear(reflect.ValueOf(spec).Elem(), strings.Split(path, ".")) {
+			cleared = append(cleared, path)
+		}
+	}
+
+	return cleared
+}
+
+// fieldPathClear clears the field of a value at a json path, and returns whether it was set
+func fieldPathClear(value reflect.Value, names []string) bool {
+	cleared := false
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			cleared = fieldPathClear(value.Elem(), names)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			cleared = fieldPathClear(value.Index(i), names) || cleared
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			elem := reflect.New(value.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+
+			if fieldPathClear(elem, names) {
+				value.SetMapIndex(iter.Key(), elem)
+
+				cleared = true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if jsonFieldName(value.Type().Field(i)) != names[0] {
+				continue
+			}
+
+			field := value.Field(i)
+			if len(names) > 1 {
+				return fieldPathClear(field, names[1:])
+			}
+
+			if field.IsZero() {
+				return false
+			}
+
+			field.Set(reflect.Zero(field.Type()))
+
+			return true
+		}
+	}
+
+	return cleared
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// vrgSpecGate clears the fields of a VRG spec that the dr-cluster operator of the cluster it is sent to does not
+// parse, as reported in the cluster's DRCluster status
+func (d *DRPCInstance) vrgSpecGate(vrg *ramen.VolumeReplicationGroup, cluster string) {
+	drCluster := d.drCluster(cluster)
+	if drCluster == nil || drCluster.Status.Operator == nil {
+		return
+	}
+
+	if cleared := vrgSpecFieldsClear(&vrg.Spec, drCluster.Status.Operator.VRGSpecFields); len(cleared) > 0 {
+		d.log.Info("VRG fields not sent, as the cluster operator does not parse them", "cluster", cluster,
+			"version", drCluster.Status.Operator.Version, "fields", cleared)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the VRG spec fields not sent to older dr-cluster operators
+package controllers //nolint: testpackage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRCluster_VersionSkew", func() {
+	without := func(paths []string, excluded ...string) []string {
+		result := []string{}
+
+		for _, path := range paths {
+			if !containsString(excluded, path) {
+				result = append(result, path)
+			}
+		}
+
+		return result
+	}
+
+	Describe("vrgSpecFields", func() {
+		It("lists the nested fields of the ramen API, and not those of other APIs", func() {
+			fields := vrgSpecFields()
+			Expect(fields).To(ContainElements("kubeObjectProtection", "kubeObjectProtection.captureInterval",
+				"pvcSelector"))
+			Expect(fields).ToNot(ContainElement("pvcSelector.matchLabels"))
+		})
+	})
+
+	Describe("vrgSpecFieldsUnparsed", func() {
+		It("lists the unparsed nested fields, but those nested in an unparsed field", func() {
+			Expect(vrgSpecFieldsUnparsed(nil)).To(BeEmpty())
+			Expect(vrgSpecFieldsUnparsed(vrgSpecFields())).To(BeEmpty())
+
+			parsed := without(vrgSpecFields(), "kubeObjectProtection.captureInterval")
+			Expect(vrgSpecFieldsUnparsed(parsed)).To(Equal([]string{"kubeObjectProtection.captureInterval"}))
+
+			parsed = without(vrgSpecFields(), "kubeObjectProtection", "kubeObjectProtection.captureInterval")
+			Expect(vrgSpecFieldsUnparsed(parsed)).To(Equal([]string{"kubeObjectProtection"}))
+		})
+	})
+
+	Describe("vrgSpecFieldsClear", func() {
+		It("clears the nested fields an operator does not parse, and keeps the others", func() {
+			spec := &ramen.VolumeReplicationGroupSpec{
+				KubeObjectProtection: &ramen.KubeObjectProtectionSpec{
+					CaptureInterval: &metav1.Duration{Duration: time.Minute},
+				},
+			}
+			expected := spec.DeepCopy()
+			expected.KubeObjectProtection.CaptureInterval = nil
+
+			parsed := without(vrgSpecFields(), "kubeObjectProtection.captureInterval")
+			Expect(vrgSpecFieldsClear(spec, parsed)).To(Equal([]string{"kubeObjectProtection.captureInterval"}))
+			Expect(spec).To(Equal(expected))
+			Expect(vrgSpecFieldsClear(spec, parsed)).To(BeEmpty())
+		})
+
+		It("clears a nested field in each element of a list", func() {
+			spec := &ramen.VolumeReplicationGroupSpec{ImageRegistryMirrors: []ramen.ImageRegistryMirror{
+				{Source: "registry.east.example.com", Mirror: "registry.west.example.com"},
+				{Source: "quay.io/team", Mirror: "registry.west.example.com/team"},
+			}}
+
+			parsed := without(vrgSpecFields(), "imageRegistryMirrors.mirror")
+			Expect(vrgSpecFieldsClear(spec, parsed)).To(Equal([]string{"imageRegistryMirrors.mirror"}))
+			Expect(spec.ImageRegistryMirrors).To(Equal([]ramen.ImageRegistryMirror{
+				{Source: "registry.east.example.com"},
+				{Source: "quay.io/team"},
+			}))
+		})
+
+		It("clears a field nested in a nil structure without setting it", func() {
+			spec := &ramen.VolumeReplicationGroupSpec{}
+
+			parsed := without(vrgSpecFields(), "kubeObjectProtection.captureInterval")
+			Expect(vrgSpecFieldsClear(spec, parsed)).To(BeEmpty())
+			Expect(spec.KubeObjectProtection).To(BeNil())
+		})
+	})
+})
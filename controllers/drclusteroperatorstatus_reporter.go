@@ -0,0 +1,558 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// Version is the version of the operator, set at build time
+var Version = "0.0.0"
+
+// DRClusterOperatorStatusReportInterval is the default interval between two health reports of the dr-cluster
+// operator
+const DRClusterOperatorStatusReportInterval = time.Minute
+
+// DRClusterOperatorStatus condition reasons
+const (
+	DRClusterOperatorReasonHealthy       = "Healthy"
+	DRClusterOperatorReasonUnhealthy     = "Unhealthy"
+	DRClusterOperatorReasonNotConfigured = "NotConfigured"
+	DRClusterOperatorReasonDisabled      = "Disabled"
+	DRClusterOperatorReasonCheckFailed   = "CheckFailed"
+)
+
+const veleroDeploymentName = "velero"
+
+// drClusterOperatorWebhookResources are the resources, by group, that the dr-cluster operator writes, and so whose
+// admission webhooks refuse its requests if they fail closed without ready endpoints
+var drClusterOperatorWebhookResources = map[string][]string{
+	"":                                 {"persistentvolumeclaims", "persistentvolumes", "secrets", "configmaps"},
+	rmn.GroupVersion.Group:             {"*"},
+	"replication.storage.openshift.io": {"volumereplications"},
+	"volsync.backube":                  {"replicationsources", "replicationdestinations"},
+	"snapshot.storage.k8s.io":          {"volumesnapshots", "volumesnapshotcontents"},
+	"velero.io":                        {"backups", "restores"},
+}
+
+// DRClusterOperatorStatusReporter periodically reports the version and enabled features of the dr-cluster operator,
+// and the health of the components it depends on, to its DRClusterOperatorStatus
+type DRClusterOperatorStatusReporter struct {
+	client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+	Interval  time.Duration
+
+	// ConfigAppliedCondition returns the ConfigApplied condition of the operator's configuration, if any
+	ConfigAppliedCondition func() *metav1.Condition
+}
+
+// SetupWithManager adds the reporter to the manager, to run on the leader only
+func (r *DRClusterOperatorStatusReporter) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Interval == 0 {
+		r.Interval = DRClusterOperatorStatusReportInterval
+	}
+
+	return mgr.Add(r)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (r *DRClusterOperatorStatusReporter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable
+func (r *DRClusterOperatorStatusReporter) Start(ctx context.Context) error {
+	r.Log.Info("Starting", "interval", r.Interval, "version", Version)
+
+	wait.UntilWithContext(ctx, r.report, r.Interval)
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drclusteroperatorstatuses,verbs=get;create;update
+// +kubebuilder:rbac:groups=ramendr.openshift.io,resources=drclusteroperatorstatuses/status,verbs=get;update
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations;mutatingwebhookconfigurations,verbs=get;list
+// +kubebuilder:rbac:groups=core,resources=endpoints,verbs=get
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csistoragecapacities,verbs=get;list
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
+func (r *DRClusterOperatorStatusReporter) report(ctx context.Context) {
+	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
+	if err != nil {
+		r.Log.Info("Health report skipped, as the config map get failed", "error", err)
+
+		return
+	}
+
+	status := &rmn.DRClusterOperatorStatus{ObjectMeta: metav1.ObjectMeta{Name: rmn.DRClusterOperatorStatusName}}
+
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Name: status.Name}, status); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			r.Log.Info("Health report skipped, as the status get failed", "error", err)
+
+			return
+		}
+
+		if err := r.Create(ctx, status); err != nil {
+			r.Log.Info("Health report skipped, as the status create failed", "error", err)
+
+			return
+		}
+	}
+
+	now := metav1.Now()
+	status.Status.Version = Version
+	status.Status.Features = drClusterOperatorFeatures(ramenConfig)
+	status.Status.VRGSpecFields = vrgSpecFields()
+	status.Status.LastReportTime = &now
+
+	veleroAvailable, veleroMissing := r.veleroAvailableCondition(ctx, ramenConfig)
+	status.Status.VeleroMissing = veleroMissing
+
+	conditions := []metav1.Condition{
+		r.webhooksHealthyCondition(ctx),
+		veleroAvailable,
+		r.mirrorDaemonsHealthyCondition(ctx, ramenConfig),
+	}
+
+	if r.ConfigAppliedCondition != nil {
+		if condition := r.ConfigAppliedCondition(); condition != nil {
+			condition.Message = fmt.Sprintf("Configuration generation %d: %s", condition.ObservedGeneration,
+				condition.Message)
+			conditions = append(conditions, *condition)
+		}
+	}
+
+	for _, condition := range conditions {
+		condition.ObservedGeneration = status.Generation
+		setStatusCondition(&status.Status.Conditions, condition)
+	}
+
+	storageCapacities, err := r.storageCapacities(ctx)
+	if err != nil {
+		r.Log.Info("Storage capacities not reported", "error", err)
+	}
+
+	status.Status.StorageCapacities = storageCapacities
+
+	availableResources, err := r.availableResources(ctx)
+	if err != nil {
+		r.Log.Info("Available resources not reported", "error", err)
+	}
+
+	status.Status.AvailableResources = availableResources
+
+	if err := r.Status().Update(ctx, status); err != nil {
+		r.Log.Info("Health report failed", "error", err)
+	}
+}
+
+// drClusterOperatorFeatures returns the features enabled in the dr-cluster operator configuration
+func drClusterOperatorFeatures(ramenConfig *rmn.RamenConfig) []string {
+	features := []string{"VolumeReplication"}
+
+	if !ramenConfig.VolSync.Disabled {
+		features = append(features, "VolSync")
+	}
+
+	if !ramenConfig.KubeObjectProtection.Disabled {
+		features = append(features, "KubeObjectProtection")
+	}
+
+	if ramenConfig.MultiNamespace.FeatureEnabled {
+		features = append(features, "MultiNamespace")
+	}
+
+	if ramenConfig.VolumeUnprotectionEnabled {
+		features = append(features, "VolumeUnprotection")
+	}
+
+	if ramenConfig.Standalone.Enabled {
+		features = append(features, "Standalone")
+	}
+
+	return features
+}
+
+func drClusterOperatorCondition(conditionType string, healthy bool, reason, message string) metav1.Condition {
+	status := metav1.ConditionTrue
+	if !healthy {
+		status = metav1.ConditionFalse
+	}
+
+	return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: message}
+}
+
+func drClusterOperatorCheckFailedCondition(conditionType string, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionUnknown,
+		Reason:  DRClusterOperatorReasonCheckFailed,
+		Message: err.Error(),
+	}
+}
+
+// webhooksHealthyCondition reports the admission webhooks that fail closed, and so refuse the operator's requests of
+// the objects they intercept, as their services have no ready endpoints
+func (r *DRClusterOperatorStatusReporter) webhooksHealthyCondition(ctx context.Context) metav1.Condition {
+	conditionType := rmn.DRClusterOperatorConditionWebhooksHealthy
+
+	services, err := webhookServices(ctx, r.APIReader)
+	if err != nil {
+		return drClusterOperatorCheckFailedCondition(conditionType, err)
+	}
+
+	unhealthy := []string{}
+
+	for key, webhookName := range services {
+		ready, err := serviceEndpointsReady(ctx, r.APIReader, key)
+		if err != nil {
+			return drClusterOperatorCheckFailedCondition(conditionType, err)
+		}
+
+		if !ready {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (service %s)", webhookName, key))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		return drClusterOperatorCondition(conditionType, false, DRClusterOperatorReasonUnhealthy,
+			"Webhooks without ready endpoints: "+strings.Join(unhealthy, ", "))
+	}
+
+	return drClusterOperatorCondition(conditionType, true, DRClusterOperatorReasonHealthy,
+		fmt.Sprintf("Webhooks that fail closed on the operator's resources have ready endpoints: %d services",
+			len(services)))
+}
+
+// webhookServices returns the services of the admission webhooks that fail closed, and intercept the resources the
+// operator writes, mapped to the name of a webhook each serves
+func webhookServices(ctx context.Context, reader client.Reader) (map[types.NamespacedName]string, error) {
+	services := map[types.NamespacedName]string{}
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := reader.List(ctx, validating); err != nil {
+		return nil, fmt.Errorf("failed to list validating webhook configurations (%w)", err)
+	}
+
+	for i := range validating.Items {
+		for _, webhook := range validating.Items[i].Webhooks {
+			webhookServiceAdd(services, webhook.Name, webhook.FailurePolicy, webhook.Rules, webhook.ClientConfig)
+		}
+	}
+
+	mutating := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := reader.List(ctx, mutating); err != nil {
+		return nil, fmt.Errorf("failed to list mutating webhook configurations (%w)", err)
+	}
+
+	for i := range mutating.Items {
+		for _, webhook := range mutating.Items[i].Webhooks {
+			webhookServiceAdd(services, webhook.Name, webhook.FailurePolicy, webhook.Rules, webhook.ClientConfig)
+		}
+	}
+
+	return services, nil
+}
+
+func webhookServiceAdd(services map[types.NamespacedName]string, webhookName string,
+	failurePolicy *admissionregistrationv1.FailurePolicyType, rules []admissionregistrationv1.RuleWithOperations,
+	clientConfig admissionregistrationv1.WebhookClientConfig,
+) {
+	// The failure policy defaults to Fail
+	if failurePolicy != nil && *failurePolicy == admissionregistrationv1.Ignore {
+		return
+	}
+
+	if !webhookRulesInterceptOperatorResources(rules) {
+		return
+	}
+
+	if clientConfig.Service == nil {
+		return
+	}
+
+	services[types.NamespacedName{Namespace: clientConfig.Service.Namespace, Name: clientConfig.Service.Name}] =
+		webhookName
+}
+
+// webhookRulesInterceptOperatorResources returns whether any of the rules of a webhook matches a resource the
+// operator writes, or a subresource of it
+func webhookRulesInterceptOperatorResources(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				resourceName, _, _ := strings.Cut(resource, "/")
+
+				for operatorGroup, operatorResources := range drClusterOperatorWebhookResources {
+					if (group == "*" || group == operatorGroup) && (resourceName == "*" ||
+						slices.Contains(operatorResources, "*") || slices.Contains(operatorResources, resourceName)) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func serviceEndpointsReady(ctx context.Context, reader client.Reader, key types.NamespacedName) (bool, error) {
+	endpoints := &corev1.Endpoints{}
+	if err := reader.Get(ctx, key, endpoints); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get endpoints %s (%w)", key, err)
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// veleroAvailableCondition reports whether the Velero deployment is available, if kube object protection is enabled,
+// and returns true as well if it is not found
+func (r *DRClusterOperatorStatusReporter) veleroAvailableCondition(ctx context.Context,
+	ramenConfig *rmn.RamenConfig,
+) (metav1.Condition, bool) {
+	conditionType := rmn.DRClusterOperatorConditionVeleroAvailable
+
+	if ramenConfig.KubeObjectProtection.Disabled {
+		return drClusterOperatorCondition(conditionType, true, DRClusterOperatorReasonDisabled,
+			"Kube object protection is disabled"), false
+	}
+
+	key := types.NamespacedName{Namespace: VeleroNamespaceNameDefault, Name: veleroDeploymentName}
+	if ramenConfig.KubeObjectProtection.VeleroNamespaceName != "" {
+		key.Namespace = ramenConfig.KubeObjectProtection.VeleroNamespaceName
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.APIReader.Get(ctx, key, deployment); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return drClusterOperatorCondition(conditionType, false, DRClusterOperatorReasonUnhealthy,
+				fmt.Sprintf("Velero deployment %s not found", key)), true
+		}
+
+		return drClusterOperatorCheckFailedCondition(conditionType, err), false
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status == corev1.ConditionTrue {
+			return drClusterOperatorCondition(conditionType, true, DRClusterOperatorReasonHealthy,
+				fmt.Sprintf("Velero deployment %s is available", key)), false
+		}
+	}
+
+	return drClusterOperatorCondition(conditionType, false, DRClusterOperatorReasonUnhealthy,
+		fmt.Sprintf("Velero deployment %s is not available", key)), false
+}
+
+// mirrorDaemonsHealthyCondition reports whether the storage mirror daemon pods are ready, if they are configured
+func (r *DRClusterOperatorStatusReporter) mirrorDaemonsHealthyCondition(ctx context.Context,
+	ramenConfig *rmn.RamenConfig,
+) metav1.Condition {
+	conditionType := rmn.DRClusterOperatorConditionMirrorDaemonsHealthy
+	healthReport := ramenConfig.HealthReport
+
+	if healthReport.MirrorDaemonLabelSelector == "" {
+		return drClusterOperatorCondition(conditionType, true, DRClusterOperatorReasonNotConfigured,
+			"Mirror daemon health is not configured to be reported")
+	}
+
+	selector, err := labels.Parse(healthReport.MirrorDaemonLabelSelector)
+	if err != nil {
+		return drClusterOperatorCheckFailedCondition(conditionType, err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.APIReader.List(ctx, pods,
+		client.InNamespace(healthReport.MirrorDaemonNamespaceName),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return drClusterOperatorCheckFailedCondition(conditionType, err)
+	}
+
+	if len(pods.Items) == 0 {
+		return drClusterOperatorCondition(conditionType, false, DRClusterOperatorReasonUnhealthy,
+			"No mirror daemon pods found")
+	}
+
+	notReady := []string{}
+
+	for i := range pods.Items {
+		if !podReady(&pods.Items[i]) {
+			notReady = append(notReady, pods.Items[i].Name)
+		}
+	}
+
+	if len(notReady) > 0 {
+		return drClusterOperatorCondition(conditionType, false, DRClusterOperatorReasonUnhealthy,
+			"Mirror daemon pods not ready: "+strings.Join(notReady, ", "))
+	}
+
+	return drClusterOperatorCondition(conditionType, true, DRClusterOperatorReasonHealthy,
+		fmt.Sprintf("Mirror daemon pods are ready: %d", len(pods.Items)))
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// storageCapacities returns the capacities available to provision volumes of the storage classes whose CSI drivers
+// report them, sorted by storage class name, with the provisioner and storage ID of each storage class for the hub
+// to match it with the storage classes of other clusters, named differently. A volume is provisioned in a single
+// topology segment, so the largest volume of a storage class is that of the segment with the most capacity, within
+// its maximum volume size.
+func (r *DRClusterOperatorStatusReporter) storageCapacities(ctx context.Context,
+) ([]rmn.StorageClassCapacity, error) {
+	csiStorageCapacities := &storagev1.CSIStorageCapacityList{}
+	if err := r.APIReader.List(ctx, csiStorageCapacities); err != nil {
+		return nil, fmt.Errorf("csi storage capacities list failed: %w", err)
+	}
+
+	storageClasses := &storagev1.StorageClassList{}
+	if err := r.APIReader.List(ctx, storageClasses); err != nil {
+		return nil, fmt.Errorf("storage classes list failed: %w", err)
+	}
+
+	capacities := map[string]*rmn.StorageClassCapacity{}
+
+	for i := range csiStorageCapacities.Items {
+		csiStorageCapacity := &csiStorageCapacities.Items[i]
+		if csiStorageCapacity.Capacity == nil {
+			continue
+		}
+
+		capacity, ok := capacities[csiStorageCapacity.StorageClassName]
+		if !ok {
+			capacity = &rmn.StorageClassCapacity{StorageClassName: csiStorageCapacity.StorageClassName}
+			capacities[csiStorageCapacity.StorageClassName] = capacity
+
+			for j := range storageClasses.Items {
+				if storageClass := &storageClasses.Items[j]; storageClass.Name == capacity.StorageClassName {
+					capacity.Provisioner = storageClass.Provisioner
+					capacity.StorageID = storageClass.Labels[StorageIDLabel]
+				}
+			}
+		}
+
+		capacity.Capacity.Add(*csiStorageCapacity.Capacity)
+
+		largestVolumeSize := *csiStorageCapacity.Capacity
+		if maximumVolumeSize := csiStorageCapacity.MaximumVolumeSize; maximumVolumeSize != nil &&
+			maximumVolumeSize.Cmp(largestVolumeSize) < 0 {
+			largestVolumeSize = *maximumVolumeSize
+		}
+
+		if largestVolumeSize.Cmp(capacity.LargestVolumeSize) > 0 {
+			capacity.LargestVolumeSize = largestVolumeSize
+		}
+	}
+
+	storageCapacities := make([]rmn.StorageClassCapacity, 0, len(capacities))
+	for _, capacity := range capacities {
+		storageCapacities = append(storageCapacities, *capacity)
+	}
+
+	sort.Slice(storageCapacities, func(i, j int) bool {
+		return storageCapacities[i].StorageClassName < storageCapacities[j].StorageClassName
+	})
+
+	return storageCapacities, nil
+}
+
+// availableResources returns the cpu and memory allocatable on the ready, schedulable nodes, less the requests of
+// the containers of the pods that are not terminated on them
+func (r *DRClusterOperatorStatusReporter) availableResources(ctx context.Context) (corev1.ResourceList, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("nodes list failed: %w", err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return nil, fmt.Errorf("pods list failed: %w", err)
+	}
+
+	available := corev1.ResourceList{}
+	schedulable := map[string]bool{}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable || !nodeReady(node) {
+			continue
+		}
+
+		schedulable[node.Name] = true
+
+		resourceListAdd(available, node.Status.Allocatable, corev1.ResourceCPU, corev1.ResourceMemory)
+	}
+
+	requested := corev1.ResourceList{}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !schedulable[pod.Spec.NodeName] ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		for j := range pod.Spec.Containers {
+			resourceListAdd(requested, pod.Spec.Containers[j].Resources.Requests,
+				corev1.ResourceCPU, corev1.ResourceMemory)
+		}
+	}
+
+	for name, quantity := range available {
+		quantity.Sub(requested[name])
+		available[name] = quantity
+	}
+
+	return available, nil
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
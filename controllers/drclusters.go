@@ -28,16 +28,44 @@ func drClusterDeploy(drClusterInstance *drclusterInstance, ramenConfig *rmn.Rame
 	if ramenConfig.DrClusterOperator.DeploymentAutomationEnabled {
 		var err error
 
-		objects, err = objectsToDeploy(ramenConfig)
+		olm := drcluster.Spec.OperatorDeploymentMode != rmn.OperatorDeploymentModeManifests &&
+			ramenConfig.DrClusterOperatorAddOn == nil
+
+		objects, err = objectsToDeploy(ramenConfig, olm, drcluster.Spec.OperatorProfile)
 		if err != nil {
 			return err
 		}
 
-		objects, err = appendSubscriptionObject(drcluster, mwu, ramenConfig, objects)
+		if ramenConfig.DrClusterObjectsRollout != nil {
+			objects, err = drClusterInstance.objectsRollout(ramenConfig, objects)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case drcluster.Spec.OperatorDeploymentMode == rmn.OperatorDeploymentModeManifests:
+			objects, err = appendOperatorManifestObjects(drcluster, ramenConfig, objects)
+		case ramenConfig.DrClusterOperatorAddOn != nil:
+			err = drClusterAddOnDeploy(drClusterInstance.ctx, drClusterInstance.client, drcluster.GetName(),
+				ramenConfig.DrClusterOperatorAddOn, drClusterInstance.log)
+		case ramenConfig.DrClusterOperatorUpgrade != nil:
+			objects, err = drClusterInstance.appendUpgradeSubscriptionObject(ramenConfig, objects)
+		default:
+			objects, err = appendSubscriptionObject(drcluster, mwu, ramenConfig, objects)
+		}
+
 		if err != nil {
 			return err
 		}
 
+		// The Edge profile protects no kube objects, so Velero is not deployed
+		if drcluster.Spec.OperatorProfile != rmn.OperatorProfileEdge {
+			if err := drClusterInstance.veleroDeploy(ramenConfig); err != nil {
+				return fmt.Errorf("unable to deploy velero to drcluster: %w", err)
+			}
+		}
+
 		// Deploy volsync to dr cluster
 		err = volsync.DeployVolSyncToCluster(drClusterInstance.ctx, drClusterInstance.client, drcluster.GetName(),
 			drClusterInstance.log)
@@ -101,7 +129,8 @@ var olmClusterRole = &rbacv1.ClusterRole{
 	},
 }
 
-func objectsToDeploy(hubOperatorRamenConfig *rmn.RamenConfig) ([]interface{}, error) {
+func objectsToDeploy(hubOperatorRamenConfig *rmn.RamenConfig, olm bool, profile rmn.OperatorProfile,
+) ([]interface{}, error) {
 	objects := []interface{}{}
 
 	drClusterOperatorRamenConfig := *hubOperatorRamenConfig
@@ -109,6 +138,16 @@ func objectsToDeploy(hubOperatorRamenConfig *rmn.RamenConfig) ([]interface{}, er
 	drClusterOperatorNamespaceName := drClusterOperatorNamespaceNameOrDefault(ramenConfig)
 	ramenConfig.LeaderElection.ResourceName = drClusterLeaderElectionResourceName
 	ramenConfig.RamenControllerType = rmn.DRClusterType
+	operatorProfileApply(ramenConfig, profile)
+
+	if ramenConfig.DrClusterOperator.S3SecretDistributionEnabled {
+		s3StoreProfiles, err := drClusterS3StoreProfiles(ramenConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		ramenConfig.S3StoreProfiles = s3StoreProfiles
+	}
 
 	drClusterOperatorConfigMap, err := ConfigMapNew(
 		drClusterOperatorNamespaceName,
@@ -125,6 +164,14 @@ func objectsToDeploy(hubOperatorRamenConfig *rmn.RamenConfig) ([]interface{}, er
 		)
 	}
 
+	// The operator is deployed in place of OLM, by its add-on or its manifests, so the cluster may not have OLM
+	if !olm {
+		return append(objects,
+			util.Namespace(drClusterOperatorNamespaceName),
+			drClusterOperatorConfigMap,
+		), nil
+	}
+
 	return append(objects,
 		util.Namespace(drClusterOperatorNamespaceName),
 		olmClusterRole,
@@ -134,6 +181,24 @@ func objectsToDeploy(hubOperatorRamenConfig *rmn.RamenConfig) ([]interface{}, er
 	), nil
 }
 
+// drClusterS3StoreProfiles returns the s3 profiles of the dr-cluster operator configuration, referencing the s3
+// secrets distributed to the dr-clusters instead of the ones on the hub
+func drClusterS3StoreProfiles(ramenConfig *rmn.RamenConfig) ([]rmn.S3StoreProfile, error) {
+	secretRefs, err := drClusterSecretRefs(ramenConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	s3StoreProfiles := make([]rmn.S3StoreProfile, len(ramenConfig.S3StoreProfiles))
+
+	for i := range ramenConfig.S3StoreProfiles {
+		ramenConfig.S3StoreProfiles[i].DeepCopyInto(&s3StoreProfiles[i])
+		s3StoreProfiles[i].S3SecretRef = secretRefs[s3StoreProfiles[i].S3SecretRef.Name]
+	}
+
+	return s3StoreProfiles, nil
+}
+
 func olmRoleBinding(namespaceName string) *rbacv1.RoleBinding {
 	return &rbacv1.RoleBinding{
 		TypeMeta: metav1.TypeMeta{Kind: "RoleBinding", APIVersion: "rbac.authorization.k8s.io/v1"},
@@ -249,9 +314,21 @@ func drClusterUndeploy(
 		return err
 	}
 
+	if err := mcv.DeleteDRClusterOperatorStatusManagedClusterView(drcluster.Name); err != nil {
+		return fmt.Errorf("drcluster '%v' operator status view delete: %w", drcluster.Name, err)
+	}
+
 	if err := mwu.DeleteManifestWork(util.DrClusterManifestWorkName, drcluster.Name); err != nil {
 		return fmt.Errorf("drcluster '%v' manifest work delete: %w", drcluster.Name, err)
 	}
 
+	if err := mwu.DeleteManifestWork(util.DrClusterVeleroManifestWorkName, drcluster.Name); err != nil {
+		return fmt.Errorf("drcluster '%v' velero manifest work delete: %w", drcluster.Name, err)
+	}
+
+	if err := drClusterAddOnUndeploy(mwu.Ctx, mwu.Client, drcluster.Name); err != nil {
+		return fmt.Errorf("drcluster '%v' add-on undeploy: %w", drcluster.Name, err)
+	}
+
 	return nil
 }
@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// DRPCJanitorInterval is the default interval between two scans for resources left behind by deleted DRPCs
+	DRPCJanitorInterval = 10 * time.Minute
+
+	// DRPCJanitorManagedClusterViewTTL is the default time since a DRPC last read its ManagedClusterView after
+	// which the view is pruned
+	DRPCJanitorManagedClusterViewTTL = time.Hour
+)
+
+// DRPCJanitor periodically removes ManifestWorks, ManagedClusterViews and placement annotations and finalizers
+// left behind by DRPCs that were deleted without their finalizer running, for example when the finalizer was
+// removed by hand, and ManagedClusterViews of DRPCs that no longer read them, for example views of a cluster
+// the application moved off. Each resource cleaned up is reported with an event.
+type DRPCJanitor struct {
+	client.Client
+	APIReader             client.Reader
+	Log                   logr.Logger
+	Interval              time.Duration
+	ManagedClusterViewTTL time.Duration
+	eventRecorder         *rmnutil.EventReporter
+}
+
+// SetupWithManager adds the janitor to the manager, to run on the leader only
+func (j *DRPCJanitor) SetupWithManager(mgr ctrl.Manager) error {
+	if j.Interval == 0 {
+		j.Interval = DRPCJanitorInterval
+	}
+
+	if j.ManagedClusterViewTTL == 0 {
+		j.ManagedClusterViewTTL = DRPCJanitorManagedClusterViewTTL
+	}
+
+	j.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("drpc_janitor"))
+
+	return mgr.Add(j)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (j *DRPCJanitor) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements manager.Runnable
+func (j *DRPCJanitor) Start(ctx context.Context) error {
+	j.Log.Info("Starting", "interval", j.Interval)
+
+	wait.UntilWithContext(ctx, j.cleanup, j.Interval)
+
+	return nil
+}
+
+func (j *DRPCJanitor) cleanup(ctx context.Context) {
+	if err := j.cleanupManifestWorks(ctx); err != nil {
+		j.Log.Info("Orphaned ManifestWork cleanup failed", "error", err)
+	}
+
+	if err := j.cleanupManagedClusterViews(ctx); err != nil {
+		j.Log.Info("Orphaned ManagedClusterView cleanup failed", "error", err)
+	}
+
+	if err := j.cleanupPlacements(ctx); err != nil {
+		j.Log.Info("Orphaned Placement cleanup failed", "error", err)
+	}
+
+	if err := j.cleanupPlacementRules(ctx); err != nil {
+		j.Log.Info("Orphaned PlacementRule cleanup failed", "error", err)
+	}
+}
+
+// drpcOrphaned returns the DRPC named in the object annotations, and whether that DRPC no longer exists
+func (j *DRPCJanitor) drpcOrphaned(ctx context.Context, obj client.Object) (types.NamespacedName, bool, error) {
+	drpcName := types.NamespacedName{
+		Name:      obj.GetAnnotations()[DRPCNameAnnotation],
+		Namespace: obj.GetAnnotations()[DRPCNamespaceAnnotation],
+	}
+
+	if drpcName.Name == "" || drpcName.Namespace == "" || rmnutil.ResourceIsDeleted(obj) {
+		return drpcName, false, nil
+	}
+
+	// Read from the API server to avoid acting on a stale cache
+	if err := j.APIReader.Get(ctx, drpcName, &rmn.DRPlacementControl{}); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return drpcName, true, nil
+		}
+
+		return drpcName, false, fmt.Errorf("failed to get DRPC %s (%w)", drpcName, err)
+	}
+
+	return drpcName, false, nil
+}
+
+func (j *DRPCJanitor) cleanupManifestWorks(ctx context.Context) error {
+	mwList := &ocmworkv1.ManifestWorkList{}
+	if err := j.List(ctx, mwList); err != nil {
+		return fmt.Errorf("failed to list ManifestWorks (%w)", err)
+	}
+
+	vrgMWSuffix := fmt.Sprintf("-%s-mw", rmnutil.MWTypeVRG)
+
+	for idx := range mwList.Items {
+		mw := &mwList.Items[idx]
+
+		// The ManifestWork that created a Namespace is intentionally left on the server, as it is on DRPC deletion
+		if !strings.HasSuffix(mw.GetName(), vrgMWSuffix) {
+			continue
+		}
+
+		if err := j.deleteIfOrphaned(ctx, mw); err != nil {
+			j.Log.Info("Orphan cleanup failed", "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (j *DRPCJanitor) cleanupManagedClusterViews(ctx context.Context) error {
+	mcvList := &viewv1beta1.ManagedClusterViewList{}
+	if err := j.List(ctx, mcvList); err != nil {
+		return fmt.Errorf("failed to list ManagedClusterViews (%w)", err)
+	}
+
+	for idx := range mcvList.Items {
+		deleted, err := j.deleteIfStale(ctx, &mcvList.Items[idx])
+		if err != nil {
+			j.Log.Info("Stale view cleanup failed", "error", err)
+		}
+
+		if deleted {
+			continue
+		}
+
+		if err := j.deleteIfOrphaned(ctx, &mcvList.Items[idx]); err != nil {
+			j.Log.Info("Orphan cleanup failed", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteIfStale deletes a ManagedClusterView of a DRPC that was not read within the TTL, and returns whether it
+// did. A view of a DRPC that is still read is refreshed by each read, and a view deleted while still needed is
+// created again by the next read.
+func (j *DRPCJanitor) deleteIfStale(ctx context.Context, mcv *viewv1beta1.ManagedClusterView) (bool, error) {
+	if mcv.GetAnnotations()[DRPCNameAnnotation] == "" || rmnutil.ResourceIsDeleted(mcv) {
+		return false, nil
+	}
+
+	// Views created before the last read time was recorded are aged from their creation
+	lastRead := mcv.CreationTimestamp.Time
+	if mcvLastRead := rmnutil.MCVLastRead(mcv); mcvLastRead != nil {
+		lastRead = *mcvLastRead
+	}
+
+	if time.Since(lastRead) < j.ManagedClusterViewTTL {
+		return false, nil
+	}
+
+	j.Log.Info("Deleting stale ManagedClusterView", "name", mcv.GetName(), "namespace", mcv.GetNamespace(),
+		"lastRead", lastRead)
+
+	if err := j.Delete(ctx, mcv); err != nil && !k8serrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete %s/%s (%w)", mcv.GetNamespace(), mcv.GetName(), err)
+	}
+
+	rmnutil.ReportIfNotPresent(j.eventRecorder, mcv, corev1.EventTypeNormal, rmnutil.EventReasonStaleViewDeleted,
+		fmt.Sprintf("Deleted, as it was last read at %s", lastRead.UTC().Format(time.RFC3339)))
+
+	return true, nil
+}
+
+func (j *DRPCJanitor) deleteIfOrphaned(ctx context.Context, obj client.Object) error {
+	drpcName, orphaned, err := j.drpcOrphaned(ctx, obj)
+	if err != nil || !orphaned {
+		return err
+	}
+
+	kind := fmt.Sprintf("%T", obj)
+	j.Log.Info("Deleting orphaned resource", "kind", kind, "name", obj.GetName(),
+		"namespace", obj.GetNamespace(), "drpc", drpcName)
+
+	if err := j.Delete(ctx, obj); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s/%s (%w)", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	rmnutil.ReportIfNotPresent(j.eventRecorder, obj, corev1.EventTypeNormal, rmnutil.EventReasonOrphanDeleted,
+		fmt.Sprintf("Deleted, as its DRPlacementControl %s no longer exists", drpcName))
+
+	return nil
+}
+
+func (j *DRPCJanitor) cleanupPlacements(ctx context.Context) error {
+	placementList := &clrapiv1beta1.PlacementList{}
+	if err := j.List(ctx, placementList); err != nil {
+		return fmt.Errorf("failed to list Placements (%w)", err)
+	}
+
+	for idx := range placementList.Items {
+		if err := j.disownIfOrphaned(ctx, &placementList.Items[idx]); err != nil {
+			j.Log.Info("Orphan cleanup failed", "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (j *DRPCJanitor) cleanupPlacementRules(ctx context.Context) error {
+	plRuleList := &plrv1.PlacementRuleList{}
+	if err := j.List(ctx, plRuleList); err != nil {
+		return fmt.Errorf("failed to list PlacementRules (%w)", err)
+	}
+
+	for idx := range plRuleList.Items {
+		if err := j.disownIfOrphaned(ctx, &plRuleList.Items[idx]); err != nil {
+			j.Log.Info("Orphan cleanup failed", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// disownIfOrphaned removes the DRPC annotations and finalizer from a user placement whose DRPC no longer exists,
+// so that it may be deleted or protected by a new DRPC
+func (j *DRPCJanitor) disownIfOrphaned(ctx context.Context, obj client.Object) error {
+	drpcName, orphaned, err := j.drpcOrphaned(ctx, obj)
+	if err != nil || !orphaned {
+		return err
+	}
+
+	j.Log.Info("Removing orphaned DRPC annotations", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(),
+		"namespace", obj.GetNamespace(), "drpc", drpcName)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, DRPCNameAnnotation)
+	delete(annotations, DRPCNamespaceAnnotation)
+	obj.SetAnnotations(annotations)
+	controllerutil.RemoveFinalizer(obj, DRPCFinalizer)
+
+	if err := j.Update(ctx, obj); err != nil {
+		return fmt.Errorf("failed to update %s/%s (%w)", obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	rmnutil.ReportIfNotPresent(j.eventRecorder, obj, corev1.EventTypeNormal, rmnutil.EventReasonOrphanDisowned,
+		fmt.Sprintf("Removed annotations and finalizer of DRPlacementControl %s, as it no longer exists", drpcName))
+
+	return nil
+}
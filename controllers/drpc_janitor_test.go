@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the cleanup of the resources left behind by deleted DRPCs
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPC_Janitor", func() {
+	const namespace = "app"
+
+	var (
+		c client.Client
+		j *DRPCJanitor
+	)
+
+	drpcAnnotations := func(name string) map[string]string {
+		return map[string]string{DRPCNameAnnotation: name, DRPCNamespaceAnnotation: namespace}
+	}
+	objectMeta := func(namespace, name string, annotations map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations}
+	}
+	exists := func(obj client.Object) bool {
+		err := c.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj)
+		if k8serrors.IsNotFound(err) {
+			return false
+		}
+
+		Expect(err).ToNot(HaveOccurred())
+
+		return true
+	}
+	manifestWork := func(name string, annotations map[string]string) *ocmworkv1.ManifestWork {
+		return &ocmworkv1.ManifestWork{ObjectMeta: objectMeta("east", name, annotations)}
+	}
+	view := func(name string, annotations map[string]string, created time.Time) *viewv1beta1.ManagedClusterView {
+		meta := objectMeta("east", name, annotations)
+		meta.CreationTimestamp = metav1.NewTime(created)
+
+		return &viewv1beta1.ManagedClusterView{ObjectMeta: meta}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(rmn.AddToScheme(scheme)).To(Succeed())
+		Expect(ocmworkv1.AddToScheme(scheme)).To(Succeed())
+		Expect(viewv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(plrv1.AddToScheme(scheme)).To(Succeed())
+		Expect(clrapiv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&rmn.DRPlacementControl{ObjectMeta: objectMeta(namespace, "present", nil)},
+		).Build()
+		j = &DRPCJanitor{
+			Client:                c,
+			APIReader:             c,
+			Log:                   ctrl.Log.WithName("drpc-janitor-test"),
+			Interval:              DRPCJanitorInterval,
+			ManagedClusterViewTTL: DRPCJanitorManagedClusterViewTTL,
+			eventRecorder:         rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+		}
+	})
+
+	Describe("cleanupManifestWorks", func() {
+		It("deletes the VRG ManifestWorks of DRPCs that no longer exist, and skips the Namespace ManifestWorks", func() {
+			orphaned := manifestWork(rmnutil.ManifestWorkName("missing", namespace, rmnutil.MWTypeVRG),
+				drpcAnnotations("missing"))
+			owned := manifestWork(rmnutil.ManifestWorkName("present", namespace, rmnutil.MWTypeVRG),
+				drpcAnnotations("present"))
+			unannotated := manifestWork(rmnutil.ManifestWorkName("other", namespace, rmnutil.MWTypeVRG), nil)
+			namespaceMW := manifestWork(rmnutil.ManifestWorkName("missing", namespace, rmnutil.MWTypeNS),
+				drpcAnnotations("missing"))
+			deleting := manifestWork(rmnutil.ManifestWorkName("deleting", namespace, rmnutil.MWTypeVRG),
+				drpcAnnotations("deleting"))
+			deleting.Finalizers = []string{"test"}
+
+			for _, mw := range []client.Object{orphaned, owned, unannotated, namespaceMW, deleting} {
+				Expect(c.Create(context.TODO(), mw)).To(Succeed())
+			}
+
+			Expect(c.Delete(context.TODO(), deleting)).To(Succeed())
+			Expect(j.cleanupManifestWorks(context.TODO())).To(Succeed())
+
+			Expect(exists(orphaned)).To(BeFalse())
+			Expect(exists(owned)).To(BeTrue())
+			Expect(exists(unannotated)).To(BeTrue())
+			Expect(exists(namespaceMW)).To(BeTrue())
+			Expect(exists(deleting)).To(BeTrue())
+		})
+	})
+
+	Describe("cleanupManagedClusterViews", func() {
+		It("deletes the views not read within the TTL, and those of DRPCs that no longer exist", func() {
+			now := time.Now()
+			readAt := func(ago time.Duration) map[string]string {
+				annotations := drpcAnnotations("present")
+				annotations[rmnutil.MCVLastReadAnnotation] = now.Add(-ago).UTC().Format(time.RFC3339)
+
+				return annotations
+			}
+			recent := view("recent", readAt(time.Minute), now.Add(-2*DRPCJanitorManagedClusterViewTTL))
+			stale := view("stale", readAt(2*DRPCJanitorManagedClusterViewTTL), now.Add(-3*DRPCJanitorManagedClusterViewTTL))
+			unreadNew := view("unread-new", drpcAnnotations("present"), now)
+			unreadOld := view("unread-old", drpcAnnotations("present"), now.Add(-2*DRPCJanitorManagedClusterViewTTL))
+			orphaned := view("orphaned", drpcAnnotations("missing"), now)
+			unannotated := view("unannotated", nil, now.Add(-2*DRPCJanitorManagedClusterViewTTL))
+
+			for _, mcv := range []client.Object{recent, stale, unreadNew, unreadOld, orphaned, unannotated} {
+				Expect(c.Create(context.TODO(), mcv)).To(Succeed())
+			}
+
+			Expect(j.cleanupManagedClusterViews(context.TODO())).To(Succeed())
+
+			Expect(exists(recent)).To(BeTrue())
+			Expect(exists(stale)).To(BeFalse())
+			Expect(exists(unreadNew)).To(BeTrue())
+			Expect(exists(unreadOld)).To(BeFalse())
+			Expect(exists(orphaned)).To(BeFalse())
+			Expect(exists(unannotated)).To(BeTrue())
+		})
+	})
+
+	Describe("deleteIfStale", func() {
+		It("skips a view already being deleted", func() {
+			mcv := view("deleting", drpcAnnotations("present"), time.Now().Add(-2*DRPCJanitorManagedClusterViewTTL))
+			mcv.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+			Expect(j.deleteIfStale(context.TODO(), mcv)).To(BeFalse())
+		})
+	})
+
+	Describe("disownIfOrphaned", func() {
+		placementRule := func(name string, annotations map[string]string) *plrv1.PlacementRule {
+			return &plrv1.PlacementRule{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, Annotations: annotations, Finalizers: []string{DRPCFinalizer},
+			}}
+		}
+
+		It("removes the DRPC annotations and finalizer of a placement whose DRPC no longer exists", func() {
+			annotations := drpcAnnotations("missing")
+			annotations["app"] = "web"
+			orphaned := &clrapiv1beta1.Placement{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: "orphaned", Annotations: annotations, Finalizers: []string{DRPCFinalizer},
+			}}
+			Expect(c.Create(context.TODO(), orphaned)).To(Succeed())
+
+			Expect(j.cleanupPlacements(context.TODO())).To(Succeed())
+
+			Expect(exists(orphaned)).To(BeTrue())
+			Expect(orphaned.Annotations).To(Equal(map[string]string{"app": "web"}))
+			Expect(orphaned.Finalizers).To(BeEmpty())
+		})
+
+		It("keeps the annotations and finalizer of a placement rule whose DRPC exists, or without annotations", func() {
+			owned := placementRule("owned", drpcAnnotations("present"))
+			unannotated := placementRule("unannotated", nil)
+			Expect(c.Create(context.TODO(), owned)).To(Succeed())
+			Expect(c.Create(context.TODO(), unannotated)).To(Succeed())
+
+			Expect(j.cleanupPlacementRules(context.TODO())).To(Succeed())
+
+			Expect(exists(owned)).To(BeTrue())
+			Expect(owned.Annotations).To(Equal(drpcAnnotations("present")))
+			Expect(owned.Finalizers).To(ConsistOf(DRPCFinalizer))
+			Expect(exists(unannotated)).To(BeTrue())
+			Expect(unannotated.Finalizers).To(ConsistOf(DRPCFinalizer))
+		})
+
+		It("keeps the finalizer of a placement rule already being deleted", func() {
+			deleting := placementRule("deleting", drpcAnnotations("missing"))
+			Expect(c.Create(context.TODO(), deleting)).To(Succeed())
+			Expect(c.Delete(context.TODO(), deleting)).To(Succeed())
+
+			Expect(j.cleanupPlacementRules(context.TODO())).To(Succeed())
+
+			Expect(exists(deleting)).To(BeTrue())
+			Expect(deleting.Finalizers).To(ConsistOf(DRPCFinalizer))
+		})
+	})
+})
@@ -31,6 +31,13 @@ const (
 	// Annotation that stores the UID of DRPC that created the resource on the managed cluster using a ManifestWork
 	DRPCUIDAnnotation = "drplacementcontrol.ramendr.openshift.io/drpc-uid"
 
+	// VRGFailoverPreparationAnnotation marks a secondary VRG created to prepare its cluster for failover
+	VRGFailoverPreparationAnnotation = "drplacementcontrol.ramendr.openshift.io/failover-preparation"
+
+	// VRGReplicationHeldAnnotation holds the replication of the PVCs of a primary VRG, which only reports them, until
+	// the storage capacity of the peer cluster is checked for them
+	VRGReplicationHeldAnnotation = "drplacementcontrol.ramendr.openshift.io/replication-held"
+
 	// Annotation for the last cluster on which the application was running
 	LastAppDeploymentCluster = "drplacementcontrol.ramendr.openshift.io/last-app-deployment-cluster"
 
@@ -78,7 +85,8 @@ func (d *DRPCInstance) startProcessing() bool {
 	done, processingErr := d.processPlacement()
 
 	if d.shouldUpdateStatus() || d.statusUpdateTimeElapsed() {
-		if err := d.reconciler.updateDRPCStatus(d.ctx, d.instance, d.userPlacement, d.log); err != nil {
+		if err := d.reconciler.updateDRPCStatus(d.ctx, d.instance, d.userPlacement, d.vrgs, d.ramenConfig,
+			d.log); err != nil {
 			errMsg := fmt.Sprintf("error from update DRPC status: %v", err)
 			if processingErr != nil {
 				errMsg += fmt.Sprintf(", error from process placement: %v", processingErr)
@@ -105,6 +113,17 @@ func (d *DRPCInstance) startProcessing() bool {
 func (d *DRPCInstance) processPlacement() (bool, error) {
 	d.log.Info("Process DRPC Placement", "DRAction", d.instance.Spec.Action)
 
+	d.prepareFailover()
+	d.readinessCheck()
+	d.failoverPlan()
+	d.stateGenerationsList()
+	d.runbookExport()
+	d.restorePreview()
+	d.vrgsRecreate()
+	d.rpoViolationNotify()
+	d.peerCapacityCheck()
+	d.rolledBackConditionPrune()
+
 	switch d.instance.Spec.Action {
 	case rmn.ActionFailover:
 		return d.RunFailover()
@@ -172,6 +191,10 @@ func (d *DRPCInstance) RunInitialDeployment() (bool, error) {
 		return !done, err
 	}
 
+	if err := d.protectionCapacityAdmit(); err != nil {
+		return !done, err
+	}
+
 	// If we get here, the deployment is successful
 	err = d.EnsureVolSyncReplicationSetup(homeCluster)
 	if err != nil {
@@ -379,11 +402,233 @@ func (d *DRPCInstance) RunFailover() (bool, error) {
 		return !done, err
 	}
 
+	if err := d.actionAdmit(failoverCluster,
+		d.placementConstraintsSatisfied, d.failoverCapacityAdmit, d.drFreezeAdmit,
+	); err != nil {
+		return !done, err
+	}
+
 	d.setStatusInitiating()
 
 	return d.switchToFailoverCluster()
 }
 
+// actionAdmit runs the admission checks of the action to the cluster, in order, and reports the first that fails in
+// the Available condition
+func (d *DRPCInstance) actionAdmit(cluster string, admits ...func(cluster string) error) error {
+	for _, admit := range admits {
+		if err := admit(cluster); err != nil {
+			addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionAvailable, d.instance.Generation,
+				d.getConditionStatusForTypeAvailable(), string(d.instance.Status.Phase), err.Error())
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareFailover prepares spec.FailoverCluster for a failover when requested, and records the preparedness in the
+// DRPC status. Once a failover is requested the preparation status is left as is, as a record of what was prepared.
+// A preparation no longer requested, or of another cluster, is stopped.
+func (d *DRPCInstance) prepareFailover() {
+	preparation := d.instance.Status.FailoverPreparation
+
+	if !d.instance.Spec.PrepareFailover {
+		if preparation != nil {
+			if err := d.failoverPreparationUnset(preparation.Cluster); err != nil {
+				d.log.Info("Failover preparation stop failed", "cluster", preparation.Cluster, "error", err)
+
+				return
+			}
+		}
+
+		d.instance.Status.FailoverPreparation = nil
+
+		return
+	}
+
+	if d.instance.Spec.Action == rmn.ActionFailover {
+		return
+	}
+
+	if preparation != nil && preparation.Cluster != d.instance.Spec.FailoverCluster {
+		if err := d.failoverPreparationUnset(preparation.Cluster); err != nil {
+			d.log.Info("Failover preparation stop failed", "cluster", preparation.Cluster, "error", err)
+
+			return
+		}
+	}
+
+	prepared, msg := d.prepareFailoverCluster()
+	if !prepared {
+		d.log.Info("Failover preparation in progress", "cluster", d.instance.Spec.FailoverCluster, "message", msg)
+	}
+
+	d.setFailoverPreparation(d.instance.Spec.FailoverCluster, prepared, msg)
+}
+
+func (d *DRPCInstance) prepareFailoverCluster() (bool, string) {
+	failoverCluster := d.instance.Spec.FailoverCluster
+	if failoverCluster == "" {
+		return false, "missing value for spec.FailoverCluster"
+	}
+
+	if failoverCluster == d.getCurrentHomeClusterName(failoverCluster, d.drClusters) {
+		return false, fmt.Sprintf("workload is placed on cluster %s", failoverCluster)
+	}
+
+	if !d.isValidFailoverTarget(failoverCluster) {
+		return false, fmt.Sprintf("cluster %s is not a valid failover target", failoverCluster)
+	}
+
+	if err := d.ensureNamespaceManifestWork(failoverCluster); err != nil {
+		return false, err.Error()
+	}
+
+	if condition := findCondition(d.instance.Status.Conditions, rmn.ConditionPeerReady); condition == nil ||
+		condition.Status != metav1.ConditionTrue {
+		return false, fmt.Sprintf("waiting for cluster %s to be ready", failoverCluster)
+	}
+
+	if condition := findCondition(d.instance.Status.Conditions, rmn.ConditionProtected); condition == nil ||
+		condition.Status != metav1.ConditionTrue {
+		return false, "waiting for the workload to be protected"
+	}
+
+	spec := &rmn.FailoverPreparationSpec{}
+	if vrg := d.vrgs[d.getCurrentHomeClusterName(failoverCluster, d.drClusters)]; vrg != nil {
+		spec.Images = vrg.Status.WorkloadImages
+		spec.WindowsImages = vrg.Status.WorkloadWindowsImages
+	}
+
+	if err := d.failoverPreparationSet(failoverCluster, spec); err != nil {
+		return false, err.Error()
+	}
+
+	images := append(append([]string{}, spec.Images...), spec.WindowsImages...)
+
+	return d.failoverPreparationProgress(failoverCluster, images)
+}
+
+// failoverPreparationProgress returns whether the VRG of the failover cluster has staged the kube objects, unless
+// not protected, and pulled the images of the workload
+func (d *DRPCInstance) failoverPreparationProgress(failoverCluster string, images []string) (bool, string) {
+	vrg := d.vrgs[failoverCluster]
+	if vrg == nil || vrg.Status.FailoverPreparation == nil {
+		return false, fmt.Sprintf("waiting for cluster %s to start preparing", failoverCluster)
+	}
+
+	status := vrg.Status.FailoverPreparation
+	staged := "kube objects are not protected"
+
+	if d.instance.Spec.KubeObjectProtection != nil {
+		if status.StagedCapture == nil {
+			return false, fmt.Sprintf("staging kube objects on cluster %s: %s", failoverCluster, status.Message)
+		}
+
+		staged = fmt.Sprintf("secrets, config maps and service accounts of capture %d are staged",
+			*status.StagedCapture)
+	}
+
+	for _, image := range images {
+		if !slices.Contains(status.ImagesPulled, image) {
+			return false, fmt.Sprintf("pulling images on cluster %s: %s", failoverCluster, status.Message)
+		}
+	}
+
+	return true, fmt.Sprintf("namespace %s is present, %s, and %d images are pulled on cluster %s, ready for failover",
+		d.vrgNamespace, staged, len(images), failoverCluster)
+}
+
+// failoverPreparationSet requests the VRG of the failover cluster to prepare it, creating a secondary VRG for it if
+// none is there yet
+func (d *DRPCInstance) failoverPreparationSet(cluster string, spec *rmn.FailoverPreparationSpec) error {
+	vrg, err := d.getVRGFromManifestWork(cluster)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		newVRG := d.generateVRG(cluster, rmn.Secondary)
+		newVRG.Spec.VolSync.Disabled = d.volSyncDisabled
+		newVRG.Spec.PrepareForFailover = spec
+		newVRG.Annotations[VRGFailoverPreparationAnnotation] = "true"
+
+		labels, annotations := vrgManifestWorkMetadata(d.instance)
+
+		if err := d.mwu.CreateOrUpdateVRGManifestWork(
+			d.instance.Name, d.vrgNamespace, cluster, newVRG, labels, annotations); err != nil {
+			return fmt.Errorf("failed to create VRG manifest to prepare cluster %s (%w)", cluster, err)
+		}
+
+		d.log.Info("Created secondary VRG to prepare failover", "cluster", cluster)
+
+		return nil
+	}
+
+	if vrg.Spec.ReplicationState != rmn.Secondary || reflect.DeepEqual(vrg.Spec.PrepareForFailover, spec) {
+		return nil
+	}
+
+	vrg.Spec.PrepareForFailover = spec
+
+	if err := d.updateManifestWork(cluster, vrg); err != nil {
+		return fmt.Errorf("failed to update VRG manifest to prepare cluster %s (%w)", cluster, err)
+	}
+
+	d.log.Info("Updated VRG to prepare failover", "cluster", cluster, "images", len(spec.Images),
+		"windowsImages", len(spec.WindowsImages))
+
+	return nil
+}
+
+// failoverPreparationUnset stops the preparation of a cluster, deleting the secondary VRG created for it, which
+// undoes it as it is deleted
+func (d *DRPCInstance) failoverPreparationUnset(cluster string) error {
+	vrg, err := d.getVRGFromManifestWork(cluster)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if vrg.Spec.ReplicationState != rmn.Secondary || vrg.Spec.PrepareForFailover == nil {
+		return nil
+	}
+
+	if vrg.GetAnnotations()[VRGFailoverPreparationAnnotation] != "" {
+		d.log.Info("Deleting secondary VRG created to prepare failover", "cluster", cluster)
+
+		return d.mwu.DeleteManifestWork(d.mwu.BuildManifestWorkName(rmnutil.MWTypeVRG), cluster)
+	}
+
+	vrg.Spec.PrepareForFailover = nil
+
+	if err := d.updateManifestWork(cluster, vrg); err != nil {
+		return fmt.Errorf("failed to update VRG manifest to stop preparing cluster %s (%w)", cluster, err)
+	}
+
+	d.log.Info("Updated VRG to stop preparing failover", "cluster", cluster)
+
+	return nil
+}
+
+func (d *DRPCInstance) setFailoverPreparation(cluster string, prepared bool, msg string) {
+	preparation := d.instance.Status.FailoverPreparation
+	if preparation == nil || preparation.Cluster != cluster || preparation.Prepared != prepared {
+		now := metav1.Now()
+		preparation = &rmn.FailoverPreparationStatus{LastTransitionTime: &now}
+		d.instance.Status.FailoverPreparation = preparation
+	}
+
+	preparation.Cluster = cluster
+	preparation.Prepared = prepared
+	preparation.Message = msg
+}
+
 // isValidFailoverTarget determines if the passed in cluster is a valid target to failover to. A valid failover target
 // may already be Primary, if it is Secondary then it has to be protecting PVCs with VolSync.
 // NOTE: Currently there is a gap where, right after DR protection when a Secondary VRG is not yet created for VolSync
@@ -417,6 +662,11 @@ func (d *DRPCInstance) isValidFailoverTarget(cluster string) bool {
 		return true
 	}
 
+	// Valid target if the Secondary VRG was created to prepare the cluster for failover
+	if vrg.Status.State == rmn.SecondaryState && vrg.GetAnnotations()[VRGFailoverPreparationAnnotation] != "" {
+		return true
+	}
+
 	return false
 }
 
@@ -857,8 +1107,16 @@ func (d *DRPCInstance) RunRelocate() (bool, error) {
 		return d.ensureActionCompleted(preferredCluster)
 	}
 
+	if err := d.actionAdmit(preferredCluster, d.placementConstraintsSatisfied, d.drFreezeAdmit); err != nil {
+		return !done, err
+	}
+
 	d.setStatusInitiating()
 
+	if rolledBack, err := d.relocateRollBackIfTimedOut(curHomeCluster, preferredCluster); rolledBack || err != nil {
+		return rolledBack, err
+	}
+
 	// Check if current primary (that is not the preferred cluster), is ready to switch over
 	if curHomeCluster != "" && curHomeCluster != preferredCluster &&
 		!d.readyToSwitchOver(curHomeCluster, preferredCluster) {
@@ -1518,14 +1776,11 @@ func (d *DRPCInstance) createVRGManifestWork(homeCluster string, repState rmn.Re
 	vrg := d.generateVRG(homeCluster, repState)
 	vrg.Spec.VolSync.Disabled = d.volSyncDisabled
 
-	annotations := make(map[string]string)
-
-	annotations[DRPCNameAnnotation] = d.instance.Name
-	annotations[DRPCNamespaceAnnotation] = d.instance.Namespace
+	labels, annotations := vrgManifestWorkMetadata(d.instance)
 
 	if err := d.mwu.CreateOrUpdateVRGManifestWork(
 		d.instance.Name, d.vrgNamespace,
-		homeCluster, vrg, annotations); err != nil {
+		homeCluster, vrg, labels, annotations); err != nil {
 		d.log.Error(err, "failed to create or update VolumeReplicationGroup manifest")
 
 		return fmt.Errorf("failed to create or update VolumeReplicationGroup manifest in namespace %s (%w)", homeCluster, err)
@@ -1581,6 +1836,7 @@ func (d *DRPCInstance) setVRGAction(vrg *rmn.VolumeReplicationGroup) {
 }
 
 func (d *DRPCInstance) generateVRG(dstCluster string, repState rmn.ReplicationState) rmn.VolumeReplicationGroup {
+	drClusterSpec := d.drClusterSpec(dstCluster)
 	vrg := rmn.VolumeReplicationGroup{
 		TypeMeta: metav1.TypeMeta{Kind: "VolumeReplicationGroup", APIVersion: "ramendr.openshift.io/v1alpha1"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -1590,24 +1846,104 @@ func (d *DRPCInstance) generateVRG(dstCluster string, repState rmn.ReplicationSt
 				DestinationClusterAnnotationKey: dstCluster,
 				DoNotDeletePVCAnnotation:        d.instance.GetAnnotations()[DoNotDeletePVCAnnotation],
 				DRPCUIDAnnotation:               string(d.instance.UID),
+				DRPCCorrelationIDAnnotation:     actionCorrelationID(d.instance),
 			},
 		},
 		Spec: rmn.VolumeReplicationGroupSpec{
-			PVCSelector:          d.instance.Spec.PVCSelector,
-			ProtectedNamespaces:  d.instance.Spec.ProtectedNamespaces,
-			ReplicationState:     repState,
-			S3Profiles:           AvailableS3Profiles(d.drClusters),
-			KubeObjectProtection: d.instance.Spec.KubeObjectProtection,
+			PVCSelector:               d.instance.Spec.PVCSelector,
+			ProtectedNamespaces:       d.instance.Spec.ProtectedNamespaces,
+			ReplicationState:          repState,
+			S3Profiles:                AvailableS3Profiles(d.drClusters),
+			KubeObjectProtection:      serviceOverridesForCluster(d.instance.Spec.KubeObjectProtection, dstCluster),
+			HelperPodScheduling:       d.instance.Spec.HelperPodScheduling,
+			ImageRegistryMirrors:      drClusterSpec.ImageRegistryMirrors,
+			TopologyTranslations:      drClusterSpec.TopologyTranslations,
+			StorageClassMappings:      drClusterSpec.StorageClassMappings,
+			AccessModeMappings:        drClusterSpec.AccessModeMappings,
+			DomainSuffixTranslations:  drClusterSpec.DomainSuffixTranslations,
+			GatewayClassTranslations:  drClusterSpec.GatewayClassTranslations,
+			SecretRewrites:            drClusterSpec.SecretRewrites,
+			NetworkAttachmentMappings: drClusterSpec.NetworkAttachmentMappings,
 		},
 	}
 
 	d.setVRGAction(&vrg)
 	vrg.Spec.Async = d.generateVRGSpecAsync()
 	vrg.Spec.Sync = d.generateVRGSpecSync()
+	vrg.Spec.VolSync.MoverConfig = d.volSyncMoverConfig()
+	vrg.Spec.VolSync.SyncthingPeers = d.syncthingPeers(dstCluster)
+	d.vrgSpecGate(&vrg, dstCluster)
+
+	if repState == rmn.Primary && d.protectionCapacityAdmit() != nil {
+		vrg.Annotations[VRGReplicationHeldAnnotation] = "true"
+	}
+
+	if d.instance.Spec.VRGMetadata != nil {
+		vrg.Labels = mergeMetadata(vrg.Labels, d.instance.Spec.VRGMetadata.Labels)
+		vrg.Annotations = mergeMetadata(vrg.Annotations, d.instance.Spec.VRGMetadata.Annotations)
+	}
 
 	return vrg
 }
 
+// drCluster returns the DRCluster of the cluster, or nil if the DRPC's policy has none
+func (d *DRPCInstance) drCluster(cluster string) *rmn.DRCluster {
+	for i := range d.drClusters {
+		if d.drClusters[i].Name == cluster {
+			return &d.drClusters[i]
+		}
+	}
+
+	return nil
+}
+
+// drClusterSpec returns the spec of the DRCluster of the cluster, whose mappings and translations are set in the
+// VRGs sent to it, or an empty spec if the DRPC's policy has none
+func (d *DRPCInstance) drClusterSpec(cluster string) rmn.DRClusterSpec {
+	if drCluster := d.drCluster(cluster); drCluster != nil {
+		return drCluster.Spec
+	}
+
+	return rmn.DRClusterSpec{}
+}
+
+// vrgManifestWorkMetadata returns the labels and annotations to set on the VRG ManifestWork of a DRPC
+func vrgManifestWorkMetadata(drpc *rmn.DRPlacementControl) (map[string]string, map[string]string) {
+	annotations := map[string]string{
+		DRPCNameAnnotation:      drpc.Name,
+		DRPCNamespaceAnnotation: drpc.Namespace,
+	}
+
+	if drpc.Spec.VRGMetadata == nil {
+		return map[string]string{}, annotations
+	}
+
+	return mergeMetadata(map[string]string{}, drpc.Spec.VRGMetadata.Labels),
+		mergeMetadata(annotations, drpc.Spec.VRGMetadata.Annotations)
+}
+
+// mergeMetadata adds user supplied key/values to dst, keys already present in dst are reserved and are not
+// overwritten
+func mergeMetadata(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+
+	for k, v := range src {
+		if _, reserved := dst[k]; reserved {
+			continue
+		}
+
+		dst[k] = v
+	}
+
+	return dst
+}
+
 func (d *DRPCInstance) generateVRGSpecAsync() *rmn.VRGAsyncSpec {
 	if dRPolicySupportsRegional(d.drPolicy, d.drClusters) {
 		return &rmn.VRGAsyncSpec{
@@ -1620,6 +1956,15 @@ func (d *DRPCInstance) generateVRGSpecAsync() *rmn.VRGAsyncSpec {
 	return nil
 }
 
+// volSyncMoverConfig returns the VolSync mover tunables for the VRG, the DRPC takes precedence over the DRPolicy
+func (d *DRPCInstance) volSyncMoverConfig() *rmn.VolSyncMoverConfig {
+	if d.instance.Spec.VolSyncMoverConfig != nil {
+		return d.instance.Spec.VolSyncMoverConfig
+	}
+
+	return d.drPolicy.Spec.VolSyncMoverConfig
+}
+
 func (d *DRPCInstance) generateVRGSpecSync() *rmn.VRGSyncSpec {
 	if d.drType == DRTypeSync {
 		return &rmn.VRGSyncSpec{}
@@ -1640,10 +1985,11 @@ func dRPolicySupportsMetro(drpolicy *rmn.DRPolicy, drclusters []rmn.DRCluster) (
 	metroMap = make(map[rmn.Region][]string)
 
 	for _, managedCluster := range rmnutil.DRPolicyClusterNames(drpolicy) {
-		for _, v := range drclusters {
-			if v.Name == managedCluster {
-				allRegionsMap[v.Spec.Region] = append(
-					allRegionsMap[v.Spec.Region],
+		for i := range drclusters {
+			if drclusters[i].Name == managedCluster {
+				region := rmnutil.DRClusterRegion(&drclusters[i])
+				allRegionsMap[region] = append(
+					allRegionsMap[region],
 					managedCluster)
 			}
 		}
@@ -1691,7 +2037,7 @@ func (d *DRPCInstance) ensureNamespaceManifestWork(homeCluster string) error {
 	if _, ok := labels[rmnutil.OCMBackupLabelKey]; ok {
 		delete(mw.Labels, rmnutil.OCMBackupLabelKey)
 
-		return d.reconciler.Update(d.ctx, mw)
+		return d.mwu.UpdateManifestWork(mw)
 	}
 
 	return nil
@@ -2036,6 +2382,12 @@ func (d *DRPCInstance) updateVRGState(clusterName string, state rmn.ReplicationS
 	}
 
 	vrg.Spec.ReplicationState = state
+	if state == rmn.Primary {
+		// The failover commits the preparation of the cluster
+		vrg.Spec.PrepareForFailover = nil
+		delete(vrg.Annotations, VRGFailoverPreparationAnnotation)
+	}
+
 	if state == rmn.Secondary {
 		// Turn off the final sync flags
 		vrg.Spec.PrepareForFinalSync = false
@@ -2129,7 +2481,7 @@ func (d *DRPCInstance) updateManifestWork(clusterName string, vrg *rmn.VolumeRep
 
 	mw.Spec.Workload.Manifests[0] = *vrgClientManifest
 
-	return d.reconciler.Update(d.ctx, mw)
+	return d.mwu.UpdateManifestWork(mw)
 }
 
 func (d *DRPCInstance) setDRState(nextState rmn.DRState) {
@@ -2148,7 +2500,7 @@ func updateDRPCProgression(
 ) bool {
 	if drpc.Status.Progression != nextProgression {
 		log.Info(fmt.Sprintf("Progression: Current '%s'. Next '%s'",
-			drpc.Status.Progression, nextProgression))
+			drpc.Status.Progression, nextProgression), logKeyStep, nextProgression)
 
 		drpc.Status.Progression = nextProgression
 
@@ -2280,6 +2632,11 @@ func (d *DRPCInstance) shouldUpdateStatus() bool {
 		return true
 	}
 
+	if len(vrg.Status.WorkloadRequests) != 0 &&
+		!resourceListsEqual(vrg.Status.WorkloadRequests, d.instance.Status.WorkloadRequests) {
+		return true
+	}
+
 	if vrg.Status.KubeObjectProtection.CaptureToRecoverFrom != nil {
 		vrgKubeObjectProtectionTime := vrg.Status.KubeObjectProtection.CaptureToRecoverFrom.EndTime
 		if !vrgKubeObjectProtectionTime.Equal(d.instance.Status.LastKubeObjectProtectionTime) {
@@ -2325,6 +2682,7 @@ func (d *DRPCInstance) reportEvent(nextState rmn.DRState) {
 
 	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, eventType,
 		eventReason, msg)
+	d.notifyState(nextState, msg)
 }
 
 func (d *DRPCInstance) getConditionStatusForTypeAvailable() metav1.ConditionStatus {
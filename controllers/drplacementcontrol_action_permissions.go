@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// DRPCActionPermissionsWebhookPath is the path the DRPC action permissions validating webhook is served at
+const DRPCActionPermissionsWebhookPath = "/validate-ramendr-openshift-io-v1alpha1-drplacementcontrol"
+
+//+kubebuilder:webhook:path=/validate-ramendr-openshift-io-v1alpha1-drplacementcontrol,mutating=false,failurePolicy=fail,sideEffects=None,groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=create;update,versions=v1alpha1,name=vdrplacementcontrol.ramendr.openshift.io,admissionReviewVersions=v1
+
+// The DR actions of a DRPC its actionPermissions permit actors for
+const (
+	drpcPermittedActionFailover        = "failover"
+	drpcPermittedActionRelocate        = "relocate"
+	drpcPermittedActionPrepareFailover = "prepareFailover"
+)
+
+// DRPCActionValidator rejects the creation or update of a DRPC that requests a DR action by a user the
+// actionPermissions of its DRPolicy do not permit to, so that the DR actions of a DRPC are limited to their actors
+// even if other users may edit, or delete and create, it
+type DRPCActionValidator struct {
+	APIReader client.Reader
+	Log       logr.Logger
+}
+
+var _ admission.CustomValidator = &DRPCActionValidator{}
+
+func (v *DRPCActionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, nil, obj)
+}
+
+func (v *DRPCActionValidator) ValidateUpdate(ctx context.Context, oldObj, obj runtime.Object,
+) (admission.Warnings, error) {
+	return nil, v.validate(ctx, oldObj, obj)
+}
+
+func (v *DRPCActionValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *DRPCActionValidator) validate(ctx context.Context, oldObj, obj runtime.Object) error {
+	drpc, ok := obj.(*rmn.DRPlacementControl)
+	if !ok {
+		return fmt.Errorf("expected a DRPlacementControl, got %T", obj)
+	}
+
+	var oldSpec *rmn.DRPlacementControlSpec
+
+	if oldObj != nil {
+		oldDRPC, ok := oldObj.(*rmn.DRPlacementControl)
+		if !ok {
+			return fmt.Errorf("expected a DRPlacementControl, got %T", oldObj)
+		}
+
+		oldSpec = &oldDRPC.Spec
+	}
+
+	if len(drpcActionsRequested(oldSpec, &drpc.Spec)) == 0 {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The operator requests the actions of DRBulkActions and maintenance relocations only for the DRPCs whose
+	// DRPolicy does not limit them
+	if username := RamenOperatorUsername(); username != "" && req.UserInfo.Username == username {
+		return nil
+	}
+
+	log := v.Log.WithValues("drpc", drpc.Namespace+"/"+drpc.Name, "user", req.UserInfo.Username)
+
+	drPolicy := &rmn.DRPolicy{}
+	if err := v.APIReader.Get(ctx, types.NamespacedName{Name: drpc.Spec.DRPolicyRef.Name}, drPolicy); err != nil {
+		log.Info("DRPC action rejected", "error", err)
+
+		return k8serrors.NewForbidden(rmn.GroupVersion.WithResource("drplacementcontrols").GroupResource(),
+			drpc.Name, fmt.Errorf("failed to get the action permissions of DRPolicy %s (%w)",
+				drpc.Spec.DRPolicyRef.Name, err))
+	}
+
+	if err := DRPCActionPermit(oldSpec, &drpc.Spec, drPolicy.Spec.ActionPermissions, req.UserInfo); err != nil {
+		log.Info("DRPC action rejected", "error", err)
+
+		return k8serrors.NewForbidden(rmn.GroupVersion.WithResource("drplacementcontrols").GroupResource(),
+			drpc.Name, err)
+	}
+
+	return nil
+}
+
+// DRPCActionPermit returns an error if a user may not request the DR actions a DRPC's spec requests over its
+// previous spec, which is nil if the DRPC is created, by the action permissions of its DRPolicy
+func DRPCActionPermit(oldSpec, spec *rmn.DRPlacementControlSpec, permissions *rmn.DRActionPermissions,
+	user authenticationv1.UserInfo,
+) error {
+	for _, action := range drpcActionsRequested(oldSpec, spec) {
+		if !drActorsInclude(drpcActionActors(permissions, action), user) {
+			return fmt.Errorf("user %s is not permitted the %s action by the actionPermissions of DRPolicy %s",
+				user.Username, action, spec.DRPolicyRef.Name)
+		}
+	}
+
+	return nil
+}
+
+// drpcActionLimited returns an error if the DRPolicy of a DRPC limits a DR action to its actors, for the operator
+// not to request it on behalf of DRBulkActions and maintenance relocations, whose requesters it does not know
+func drpcActionLimited(ctx context.Context, reader client.Reader, drpc *rmn.DRPlacementControl,
+	action rmn.DRAction,
+) error {
+	drPolicy := &rmn.DRPolicy{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: drpc.Spec.DRPolicyRef.Name}, drPolicy); err != nil {
+		return fmt.Errorf("failed to get DRPolicy %s (%w)", drpc.Spec.DRPolicyRef.Name, err)
+	}
+
+	if drpcActionActors(drPolicy.Spec.ActionPermissions, drpcActionPermitted(action)) != nil {
+		return fmt.Errorf("the %s action is limited to its actors by the actionPermissions of DRPolicy %s",
+			drpcActionPermitted(action), drPolicy.Name)
+	}
+
+	return nil
+}
+
+// drpcActionsRequested returns the DR actions a DRPC's spec requests over its previous spec: an action that is
+// set, retargeted or cleared, which undoes it, and a failover preparation that is set or retargeted
+func drpcActionsRequested(oldSpec, spec *rmn.DRPlacementControlSpec) []string {
+	if oldSpec == nil {
+		oldSpec = &rmn.DRPlacementControlSpec{}
+	}
+
+	actions := []string{}
+
+	switch {
+	case spec.Action != oldSpec.Action && spec.Action != "":
+		actions = append(actions, drpcActionPermitted(spec.Action))
+	case spec.Action != oldSpec.Action:
+		actions = append(actions, drpcActionPermitted(oldSpec.Action))
+	case spec.Action == rmn.ActionFailover && spec.FailoverCluster != oldSpec.FailoverCluster,
+		spec.Action == rmn.ActionRelocate && spec.PreferredCluster != oldSpec.PreferredCluster:
+		actions = append(actions, drpcActionPermitted(spec.Action))
+	}
+
+	if spec.PrepareFailover && (!oldSpec.PrepareFailover || spec.FailoverCluster != oldSpec.FailoverCluster) {
+		actions = append(actions, drpcPermittedActionPrepareFailover)
+	}
+
+	return actions
+}
+
+func drpcActionPermitted(action rmn.DRAction) string {
+	if action == rmn.ActionRelocate {
+		return drpcPermittedActionRelocate
+	}
+
+	return drpcPermittedActionFailover
+}
+
+// drpcActionActors returns the actors permissions permit an action to, or nil if they do not limit it
+func drpcActionActors(permissions *rmn.DRActionPermissions, action string) *rmn.DRActors {
+	if permissions == nil {
+		return nil
+	}
+
+	switch action {
+	case drpcPermittedActionFailover:
+		return permissions.Failover
+	case drpcPermittedActionRelocate:
+		return permissions.Relocate
+	case drpcPermittedActionPrepareFailover:
+		return permissions.PrepareFailover
+	}
+
+	return nil
+}
+
+// drActorsInclude returns whether actors, if set, include a user by name or by one of its groups
+func drActorsInclude(actors *rmn.DRActors, user authenticationv1.UserInfo) bool {
+	if actors == nil || slices.Contains(actors.Users, user.Username) {
+		return true
+	}
+
+	for _, group := range user.Groups {
+		if slices.Contains(actors.Groups, group) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the DR actions a DRPC's spec requests and the limits of DRPolicies on them
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPCActionPermissions", func() {
+	type spec = rmn.DRPlacementControlSpec
+
+	failover := spec{Action: rmn.ActionFailover, FailoverCluster: "west"}
+	relocate := spec{Action: rmn.ActionRelocate, PreferredCluster: "east"}
+	prepareFailover := spec{PrepareFailover: true, FailoverCluster: "west"}
+
+	DescribeTable("drpcActionsRequested",
+		func(oldSpec *spec, spec spec, expected []string) {
+			Expect(drpcActionsRequested(oldSpec, &spec)).To(Equal(expected))
+		},
+		Entry("none on creation without an action", nil, spec{}, []string{}),
+		Entry("the action a DRPC is created with", nil, failover, []string{drpcPermittedActionFailover}),
+		Entry("none for an unchanged action", &failover, failover, []string{}),
+		Entry("an action set", &spec{}, relocate, []string{drpcPermittedActionRelocate}),
+		Entry("an action cleared, which undoes it", &failover, spec{FailoverCluster: "west"},
+			[]string{drpcPermittedActionFailover}),
+		Entry("an action changed", &failover, relocate, []string{drpcPermittedActionRelocate}),
+		Entry("a failover retargeted", &failover, spec{Action: rmn.ActionFailover, FailoverCluster: "north"},
+			[]string{drpcPermittedActionFailover}),
+		Entry("a relocation retargeted", &relocate, spec{Action: rmn.ActionRelocate, PreferredCluster: "north"},
+			[]string{drpcPermittedActionRelocate}),
+		Entry("none for a relocation whose failover cluster changes", &relocate,
+			spec{Action: rmn.ActionRelocate, PreferredCluster: "east", FailoverCluster: "north"}, []string{}),
+		Entry("a failover preparation set", &spec{}, prepareFailover, []string{drpcPermittedActionPrepareFailover}),
+		Entry("a failover preparation retargeted", &prepareFailover, spec{PrepareFailover: true, FailoverCluster: "north"},
+			[]string{drpcPermittedActionPrepareFailover}),
+		Entry("none for a failover preparation cleared", &prepareFailover, spec{FailoverCluster: "west"}, []string{}),
+		Entry("a failover set with its preparation", &spec{},
+			spec{Action: rmn.ActionFailover, PrepareFailover: true, FailoverCluster: "west"},
+			[]string{drpcPermittedActionFailover, drpcPermittedActionPrepareFailover}),
+	)
+
+	permissions := &rmn.DRActionPermissions{
+		Failover:        &rmn.DRActors{Groups: []string{"dr-team"}},
+		PrepareFailover: &rmn.DRActors{Users: []string{"alice"}, Groups: []string{"dr-team"}},
+	}
+	alice := authenticationv1.UserInfo{Username: "alice", Groups: []string{"app-owners"}}
+	bob := authenticationv1.UserInfo{Username: "bob", Groups: []string{"app-owners", "dr-team"}}
+	carol := authenticationv1.UserInfo{Username: "carol", Groups: []string{"app-owners"}}
+
+	DescribeTable("DRPCActionPermit",
+		func(oldSpec *spec, spec spec, permissions *rmn.DRActionPermissions, user authenticationv1.UserInfo,
+			permitted bool,
+		) {
+			spec.DRPolicyRef.Name = "production"
+			err := DRPCActionPermit(oldSpec, &spec, permissions, user)
+			if permitted {
+				Expect(err).ToNot(HaveOccurred())
+
+				return
+			}
+
+			Expect(err).To(MatchError(ContainSubstring("user %s is not permitted", user.Username)))
+		},
+		Entry("any action without permissions", &spec{}, failover, nil, carol, true),
+		Entry("an action the permissions do not limit", &spec{}, relocate, permissions, carol, true),
+		Entry("an action to a member of a group permitted it", &spec{}, failover, permissions, bob, true),
+		Entry("an action to a user permitted it", &spec{}, prepareFailover, permissions, alice, true),
+		Entry("an action to a user not permitted it", &spec{}, failover, permissions, alice, false),
+		Entry("the creation of a DRPC with an action to a user not permitted it", nil, failover, permissions, carol,
+			false),
+		Entry("undoing an action to a user not permitted it", &failover, spec{}, permissions, carol, false),
+		Entry("retargeting an action to a user not permitted it", &failover,
+			spec{Action: rmn.ActionFailover, FailoverCluster: "north"}, permissions, alice, false),
+		Entry("a failover preparation to a user not permitted it", &spec{}, prepareFailover, permissions, carol,
+			false),
+		Entry("any other change to a user not permitted the action", &failover,
+			spec{Action: rmn.ActionFailover, FailoverCluster: "west", PreferredCluster: "east"}, permissions, carol,
+			true),
+	)
+
+	drPolicy := func(name string, permissions *rmn.DRActionPermissions) *rmn.DRPolicy {
+		return &rmn.DRPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       rmn.DRPolicySpec{ActionPermissions: permissions},
+		}
+	}
+	drpc := func(drPolicyName string, spec spec) *rmn.DRPlacementControl {
+		spec.DRPolicyRef.Name = drPolicyName
+
+		return &rmn.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"}, Spec: spec}
+	}
+
+	var reader client.Reader
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			drPolicy("production", permissions),
+			drPolicy("development", nil),
+		).Build()
+	})
+
+	Describe("drpcActionLimited", func() {
+		It("returns an error for an action the DRPolicy of a DRPC limits", func() {
+			Expect(drpcActionLimited(context.TODO(), reader, drpc("production", spec{}), rmn.ActionFailover)).
+				To(MatchError(ContainSubstring("failover action is limited")))
+		})
+
+		It("returns none for an action the DRPolicy of a DRPC does not limit", func() {
+			Expect(drpcActionLimited(context.TODO(), reader, drpc("production", spec{}), rmn.ActionRelocate)).
+				To(Succeed())
+			Expect(drpcActionLimited(context.TODO(), reader, drpc("development", spec{}), rmn.ActionFailover)).
+				To(Succeed())
+		})
+
+		It("returns an error if the DRPolicy of a DRPC is not found", func() {
+			Expect(drpcActionLimited(context.TODO(), reader, drpc("missing", spec{}), rmn.ActionRelocate)).
+				To(MatchError(ContainSubstring("missing")))
+		})
+	})
+
+	Describe("DRPCActionValidator", func() {
+		var validator *DRPCActionValidator
+
+		validate := func(user authenticationv1.UserInfo, oldDRPC, drpc *rmn.DRPlacementControl) error {
+			ctx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: user},
+			})
+
+			if oldDRPC == nil {
+				_, err := validator.ValidateCreate(ctx, drpc)
+
+				return err
+			}
+
+			_, err := validator.ValidateUpdate(ctx, oldDRPC, drpc)
+
+			return err
+		}
+
+		BeforeEach(func() {
+			validator = &DRPCActionValidator{APIReader: reader, Log: ctrl.Log.WithName("drpc-action-permissions-test")}
+		})
+
+		It("forbids an action the DRPolicy of the DRPC does not permit the user", func() {
+			Expect(validate(carol, drpc("production", spec{}), drpc("production", failover))).
+				To(Satisfy(k8serrors.IsForbidden))
+			Expect(validate(carol, nil, drpc("production", failover))).To(Satisfy(k8serrors.IsForbidden))
+			Expect(validate(bob, drpc("production", spec{}), drpc("production", failover))).To(Succeed())
+		})
+
+		It("forbids an action if the DRPolicy of the DRPC is not found", func() {
+			Expect(validate(bob, drpc("missing", spec{}), drpc("missing", failover))).
+				To(Satisfy(k8serrors.IsForbidden))
+			Expect(validate(bob, drpc("missing", spec{}), drpc("missing", spec{PreferredCluster: "east"}))).
+				To(Succeed())
+		})
+
+		It("permits every action to the operator's own service account only", func() {
+			DeferCleanup(os.Setenv, "POD_NAMESPACE", os.Getenv("POD_NAMESPACE"))
+			DeferCleanup(os.Setenv, "POD_SERVICE_ACCOUNT", os.Getenv("POD_SERVICE_ACCOUNT"))
+			Expect(os.Setenv("POD_NAMESPACE", "ramen-system")).To(Succeed())
+			Expect(os.Setenv("POD_SERVICE_ACCOUNT", "ramen-hub-operator")).To(Succeed())
+
+			operator := authenticationv1.UserInfo{Username: "system:serviceaccount:ramen-system:ramen-hub-operator"}
+			other := authenticationv1.UserInfo{Username: "system:serviceaccount:ramen-system:default"}
+			Expect(validate(operator, drpc("production", spec{}), drpc("production", failover))).To(Succeed())
+			Expect(validate(other, drpc("production", spec{}), drpc("production", failover))).
+				To(Satisfy(k8serrors.IsForbidden))
+		})
+	})
+})
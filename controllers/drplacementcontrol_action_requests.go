@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// Requests of DRPC actions by the hub resources that fail over and relocate DRPCs on behalf of their users, such as
+// MaintenanceRelocates and DRBulkActions
+
+func drpcReferenceGet(ctx context.Context, reader client.Reader, ref rmn.DRPCReference,
+) (*rmn.DRPlacementControl, error) {
+	drpc := &rmn.DRPlacementControl{}
+
+	return drpc, reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, drpc)
+}
+
+// drpcReferenceGetFailed returns the state, skipped if the DRPC was not found and failed otherwise, and the message
+// of the entry of a DRPC that failed to be gotten
+func drpcReferenceGetFailed[S ~string](err error, skipped, failed S) (S, string) {
+	if k8serrors.IsNotFound(err) {
+		return skipped, "DRPC not found"
+	}
+
+	return failed, fmt.Sprintf("failed to get DRPC: %v", err)
+}
+
+func drpcPaused(drpc *rmn.DRPlacementControl) bool {
+	return drpc.GetAnnotations()[DRPCPausedAnnotation] == DRPCPausedAnnotationVal
+}
+
+// drpcActionCluster returns the cluster of the action of the DRPC spec, and the phase the DRPC completes it in
+//
+//nolint:exhaustive
+func drpcActionCluster(drpc *rmn.DRPlacementControl, action rmn.DRAction) (string, rmn.DRState) {
+	switch action {
+	case rmn.ActionFailover:
+		return drpc.Spec.FailoverCluster, rmn.FailedOver
+	default:
+		return drpc.Spec.PreferredCluster, rmn.Relocated
+	}
+}
+
+// drpcActionRequest sets the action and its cluster in the spec of a DRPC, unless set already, and returns true if
+// it set them
+func drpcActionRequest(ctx context.Context, c client.Client, drpc *rmn.DRPlacementControl, action rmn.DRAction,
+	cluster string,
+) (bool, error) {
+	if current, _ := drpcActionCluster(drpc, action); drpc.Spec.Action == action && current == cluster {
+		return false, nil
+	}
+
+	original := drpc.DeepCopy()
+	drpc.Spec.Action = action
+
+	if action == rmn.ActionFailover {
+		drpc.Spec.FailoverCluster = cluster
+	} else {
+		drpc.Spec.PreferredCluster = cluster
+	}
+
+	if err := c.Patch(ctx, drpc, client.MergeFrom(original)); err != nil {
+		return false, fmt.Errorf("failed to request %s of DRPC to cluster %s: %w", action, cluster, err)
+	}
+
+	return true, nil
+}
+
+// drpcActionCompletedTo returns true once a DRPC completed an action to a cluster, and an error if its action or
+// cluster was changed meanwhile
+func drpcActionCompletedTo(drpc *rmn.DRPlacementControl, action rmn.DRAction, cluster string) (bool, error) {
+	current, phase := drpcActionCluster(drpc, action)
+	if drpc.Spec.Action != action || current != cluster {
+		return false, fmt.Errorf("DRPC action changed to %q to cluster %q", drpc.Spec.Action, current)
+	}
+
+	return drpcActionCompleted(drpc) && drpc.Status.Phase == phase &&
+		drpc.Status.PreferredDecision.ClusterName == cluster, nil
+}
+
+// drpcActionCompleted returns true if the DRPC completed the action of its current spec
+func drpcActionCompleted(drpc *rmn.DRPlacementControl) bool {
+	return drpc.Status.ObservedGeneration == drpc.Generation && drpcInFinalPhase(drpc) &&
+		drpc.Status.Progression == rmn.ProgressionCompleted
+}
@@ -0,0 +1,365 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPCSkipCapacityCheckAnnotation, set to "true" on a DRPC, skips the failover capacity check, for emergencies where
+// the workload is to fail over regardless, and the protection capacity check from holding its initial deployment
+const DRPCSkipCapacityCheckAnnotation = "drplacementcontrol.ramendr.openshift.io/skip-capacity-check"
+
+// failoverCapacityAdmit returns an error if the failover cluster cannot host the workload and the ramen config
+// refuses such failovers. If it only warns, the shortfall is reported with an event, and the failover proceeds.
+func (d *DRPCInstance) failoverCapacityAdmit(cluster string) error {
+	mode := d.ramenConfig.FailoverCapacityCheck.Mode
+	if mode == "" {
+		return nil
+	}
+
+	if d.instance.GetAnnotations()[DRPCSkipCapacityCheckAnnotation] == "true" {
+		d.log.Info("Failover capacity check skipped", "annotation", DRPCSkipCapacityCheckAnnotation)
+
+		return nil
+	}
+
+	shortfall, err := d.failoverCapacityShortfall(cluster)
+	if err != nil {
+		return err
+	}
+
+	if shortfall == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("cluster %s cannot host the workload: %s", cluster, shortfall)
+	if mode != rmn.FailoverCapacityCheckRefuse {
+		d.log.Info(msg)
+		rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonFailoverCapacityInsufficient, msg)
+
+		return nil
+	}
+
+	return fmt.Errorf("%s, annotate with %s=true to fail over regardless", msg, DRPCSkipCapacityCheckAnnotation)
+}
+
+// failoverCapacityShortfall describes the cpu and memory requests of the workload that exceed the resources
+// available on the cluster, and the storage classes short of the storage of the PVCs a failover provisions, or
+// returns an empty string if the cluster can host the workload or if the workload requests are not known. The
+// available resources are those reported by the dr-cluster operator of the cluster, or else the allocatable
+// resources of its ManagedCluster, which do not account for the pods already running on it.
+func (d *DRPCInstance) failoverCapacityShortfall(cluster string) (string, error) {
+	var report *rmn.DRClusterOperatorReport
+	if drCluster := d.drCluster(cluster); drCluster != nil {
+		report = drCluster.Status.Operator
+	}
+
+	available, kind := corev1.ResourceList(nil), "available"
+	if report != nil {
+		available = report.AvailableResources
+	}
+
+	if len(available) == 0 {
+		managedCluster, err := d.readinessManagedCluster(cluster)
+		if err != nil {
+			return "", err
+		}
+
+		available, kind = corev1.ResourceList{}, "allocatable"
+		for name, quantity := range managedCluster.Status.Allocatable {
+			available[corev1.ResourceName(name)] = quantity
+		}
+	}
+
+	shortfalls := resourcesShortfalls(d.instance.Status.WorkloadRequests, available, kind)
+
+	insufficient, _, _ := peerCapacityShortfalls(failoverProvisionedPVCs(d.vrgs, cluster,
+		d.getCurrentHomeClusterName(cluster, d.drClusters)), report)
+
+	return strings.Join(append(shortfalls, insufficient...), ", "), nil
+}
+
+// resourcesShortfalls describes the cpu and memory requests that exceed the available resources
+func resourcesShortfalls(requests, available corev1.ResourceList, kind string) []string {
+	shortfalls := []string{}
+
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		requested, ok := requests[name]
+		if !ok {
+			continue
+		}
+
+		quantity, ok := available[name]
+		if !ok {
+			continue
+		}
+
+		if requested.Cmp(quantity) > 0 {
+			shortfalls = append(shortfalls, fmt.Sprintf("%s requested %s exceeds %s %s", name,
+				requested.String(), kind, quantity.String()))
+		}
+	}
+
+	return shortfalls
+}
+
+// failoverProvisionedPVCs returns a VRG with the protected PVCs a failover to the cluster provisions, those
+// protected by VolSync, whose PVCs are restored from a snapshot of their replica, from the VRG of the home cluster
+// if it is available, or else from the VRG of the failover cluster. PVCs replicated by volume replication are not,
+// as their replicas are already provisioned.
+func failoverProvisionedPVCs(vrgs map[string]*rmn.VolumeReplicationGroup, cluster, homeCluster string,
+) *rmn.VolumeReplicationGroup {
+	vrg := vrgs[homeCluster]
+	if vrg == nil {
+		vrg = vrgs[cluster]
+	}
+
+	provisioned := &rmn.VolumeReplicationGroup{}
+	if vrg == nil {
+		return provisioned
+	}
+
+	for i := range vrg.Status.ProtectedPVCs {
+		if vrg.Status.ProtectedPVCs[i].ProtectedByVolSync {
+			provisioned.Status.ProtectedPVCs = append(provisioned.Status.ProtectedPVCs, vrg.Status.ProtectedPVCs[i])
+		}
+	}
+
+	return provisioned
+}
+
+// resourceListsEqual returns true if the resource lists have the same quantities, regardless of their formats
+func resourceListsEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// peerCapacityCheck sets the PeerCapacityAvailable condition of the DRPC, comparing the storage requested by the PVCs
+// protected on its home cluster, per storage class, with the capacity the dr-cluster operator of its peer cluster
+// reports. The condition is pending until the VRG of the home cluster reports its PVCs, and is checked until the
+// initial deployment completes, as the replicas of the PVCs consume the capacity of the peer cluster once their
+// replication is set up, and is kept as last checked after.
+func (d *DRPCInstance) peerCapacityCheck() {
+	if !peerCapacityChecked(d.instance) {
+		return
+	}
+
+	homeCluster := d.getCurrentHomeClusterName("", d.drClusters)
+	vrg := d.vrgs[homeCluster]
+
+	var peerCluster *rmn.DRCluster
+
+	for i := range d.drClusters {
+		if d.drClusters[i].Name != homeCluster {
+			peerCluster = &d.drClusters[i]
+
+			break
+		}
+	}
+
+	if peerCluster == nil {
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionPeerCapacityAvailable)
+
+		return
+	}
+
+	if !vrgPVCsReported(vrg) {
+		addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionPeerCapacityAvailable,
+			d.instance.Generation, metav1.ConditionUnknown, rmn.ReasonCapacityPending,
+			fmt.Sprintf("Cluster %s: Protected PVCs not reported yet", peerCluster.Name))
+
+		return
+	}
+
+	insufficient, unknown, storageClasses := peerCapacityShortfalls(vrg, peerCluster.Status.Operator)
+	if storageClasses == 0 {
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionPeerCapacityAvailable)
+
+		return
+	}
+
+	status, reason, msg := metav1.ConditionTrue, rmn.ReasonCapacityAvailable, "Storage capacity is available"
+
+	switch {
+	case len(insufficient) > 0:
+		status, reason = metav1.ConditionFalse, rmn.ReasonCapacityInsufficient
+		msg = "Storage capacity insufficient: " + strings.Join(insufficient, ", ")
+	case len(unknown) > 0:
+		status, reason = metav1.ConditionUnknown, rmn.ReasonCapacityUnknown
+		msg = "Storage capacity not reported: " + strings.Join(unknown, ", ")
+	}
+
+	msg = fmt.Sprintf("Cluster %s: %s", peerCluster.Name, msg)
+
+	if addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionPeerCapacityAvailable,
+		d.instance.Generation, status, reason, msg) && status == metav1.ConditionFalse {
+		d.log.Info(msg)
+		rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonPeerCapacityInsufficient, msg)
+	}
+}
+
+// peerCapacityChecked returns whether the peer capacity of the DRPC is checked, until its initial deployment
+// completes
+func peerCapacityChecked(drpc *rmn.DRPlacementControl) bool {
+	switch drpc.Status.Phase {
+	case "", rmn.Initiating, rmn.Deploying:
+		return true
+	default:
+		return false
+	}
+}
+
+// vrgPVCsReported returns whether the VRG reported the PVCs it protects, for its current generation
+func vrgPVCsReported(vrg *rmn.VolumeReplicationGroup) bool {
+	return vrg != nil && vrg.Status.ObservedGeneration == vrg.Generation &&
+		meta.FindStatusCondition(vrg.Status.Conditions, VRGConditionTypeDataReady) != nil
+}
+
+// peerCapacityShortfalls returns the storage classes of the PVCs protected by a VRG whose capacity, as reported by
+// the dr-cluster operator of a peer cluster, is short of the storage they request, in total or for the largest
+// PVC, those whose capacity is not reported, and the count of storage classes of the PVCs that request storage
+func peerCapacityShortfalls(vrg *rmn.VolumeReplicationGroup, report *rmn.DRClusterOperatorReport,
+) (insufficient, unknown []string, storageClasses int) {
+	requested := map[string]*resource.Quantity{}
+	largest := map[string]*resource.Quantity{}
+	identifiers := map[string]rmn.StorageIdentifiers{}
+	storageClassNames := []string{}
+
+	for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+		storage, ok := protectedPVC.Resources.Requests[corev1.ResourceStorage]
+		if !ok || protectedPVC.StorageClassName == nil {
+			continue
+		}
+
+		name := *protectedPVC.StorageClassName
+
+		if _, ok := requested[name]; !ok {
+			requested[name], largest[name] = &resource.Quantity{}, &resource.Quantity{}
+			identifiers[name] = protectedPVC.StorageIdentifiers
+			storageClassNames = append(storageClassNames, name)
+		}
+
+		requested[name].Add(storage)
+
+		if storage.Cmp(*largest[name]) > 0 {
+			*largest[name] = storage
+		}
+	}
+
+	sort.Strings(storageClassNames)
+
+	for _, name := range storageClassNames {
+		capacity := storageClassCapacity(report, name, identifiers[name])
+		if capacity == nil {
+			unknown = append(unknown, name)
+
+			continue
+		}
+
+		peerName := ""
+		if capacity.StorageClassName != name {
+			peerName = " of " + capacity.StorageClassName
+		}
+
+		switch {
+		case requested[name].Cmp(capacity.Capacity) > 0:
+			insufficient = append(insufficient, fmt.Sprintf("%s requested %s exceeds capacity %s%s", name,
+				requested[name].String(), capacity.Capacity.String(), peerName))
+		case largest[name].Cmp(capacity.LargestVolumeSize) > 0:
+			insufficient = append(insufficient, fmt.Sprintf("%s largest PVC %s exceeds largest volume size %s%s",
+				name, largest[name].String(), capacity.LargestVolumeSize.String(), peerName))
+		}
+	}
+
+	return insufficient, unknown, len(storageClassNames)
+}
+
+// storageClassCapacity returns the capacity, reported by the dr-cluster operator of a peer cluster, of the storage
+// class of the peer cluster matching a storage class, as peers usually name their storage classes differently: the
+// storage class of the same name, else those of the same storage ID, else those of the same provisioner. Of several
+// matching, the one of the most capacity is returned.
+func storageClassCapacity(report *rmn.DRClusterOperatorReport, storageClassName string,
+	identifiers rmn.StorageIdentifiers,
+) *rmn.StorageClassCapacity {
+	if report == nil {
+		return nil
+	}
+
+	for i := range report.StorageCapacities {
+		if report.StorageCapacities[i].StorageClassName == storageClassName {
+			return &report.StorageCapacities[i]
+		}
+	}
+
+	for _, matches := range []func(*rmn.StorageClassCapacity) bool{
+		func(capacity *rmn.StorageClassCapacity) bool {
+			return identifiers.StorageID.ID != "" && capacity.StorageID == identifiers.StorageID.ID
+		},
+		func(capacity *rmn.StorageClassCapacity) bool {
+			return identifiers.StorageProvisioner != "" && capacity.Provisioner == identifiers.StorageProvisioner
+		},
+	} {
+		var matched *rmn.StorageClassCapacity
+
+		for i := range report.StorageCapacities {
+			capacity := &report.StorageCapacities[i]
+			if matches(capacity) && (matched == nil || capacity.Capacity.Cmp(matched.Capacity) > 0) {
+				matched = capacity
+			}
+		}
+
+		if matched != nil {
+			return matched
+		}
+	}
+
+	return nil
+}
+
+// protectionCapacityAdmit returns an error if the peer cluster of an async DRPC lacks the storage capacity for the
+// protected PVCs, or it is not checked yet, during the initial deployment of the DRPC, and the ramen config refuses
+// to replicate them then. The replication of the PVCs by VolumeReplication is held by annotating the primary VRG,
+// and by VolSync by not setting it up.
+func (d *DRPCInstance) protectionCapacityAdmit() error {
+	if d.ramenConfig.ProtectionCapacityCheck.Mode != rmn.ProtectionCapacityCheckRefuse || d.drType != DRTypeAsync ||
+		!peerCapacityChecked(d.instance) {
+		return nil
+	}
+
+	condition := meta.FindStatusCondition(d.instance.Status.Conditions, rmn.ConditionPeerCapacityAvailable)
+	if condition == nil ||
+		condition.Status != metav1.ConditionFalse && condition.Reason != rmn.ReasonCapacityPending {
+		return nil
+	}
+
+	if d.instance.GetAnnotations()[DRPCSkipCapacityCheckAnnotation] == "true" {
+		return nil
+	}
+
+	return fmt.Errorf("%s, annotate with %s=true to protect regardless", condition.Message,
+		DRPCSkipCapacityCheckAnnotation)
+}
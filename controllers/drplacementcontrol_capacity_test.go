@@ -0,0 +1,373 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the failover and peer capacity checks
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPC_FailoverCapacity", func() {
+	resources := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}
+	}
+
+	Describe("resourcesShortfalls", func() {
+		It("reports the requests that exceed the available resources", func() {
+			Expect(resourcesShortfalls(resources("2", "4Gi"), resources("2", "4Gi"), "available")).To(BeEmpty())
+			Expect(resourcesShortfalls(resources("2500m", "4Gi"), resources("2", "8Gi"), "available")).To(
+				ConsistOf("cpu requested 2500m exceeds available 2"))
+			Expect(resourcesShortfalls(resources("1", "5Gi"), resources("2", "4Gi"), "allocatable")).To(
+				ConsistOf("memory requested 5Gi exceeds allocatable 4Gi"))
+		})
+		It("ignores the resources that are not requested or not reported", func() {
+			Expect(resourcesShortfalls(corev1.ResourceList{}, resources("1", "1Gi"), "available")).To(BeEmpty())
+			Expect(resourcesShortfalls(resources("2", "2Gi"), corev1.ResourceList{}, "available")).To(BeEmpty())
+		})
+	})
+
+	Describe("resourceListsEqual", func() {
+		It("compares quantities regardless of their formats", func() {
+			Expect(resourceListsEqual(resources("1", "1Gi"), resources("1000m", "1024Mi"))).To(BeTrue())
+			Expect(resourceListsEqual(resources("1", "1Gi"), resources("1", "1G"))).To(BeFalse())
+			Expect(resourceListsEqual(resources("1", "1Gi"), corev1.ResourceList{})).To(BeFalse())
+			Expect(resourceListsEqual(nil, corev1.ResourceList{})).To(BeTrue())
+		})
+	})
+
+	Describe("failoverProvisionedPVCs", func() {
+		storageClassName := "rbd"
+		protectedPVC := func(name string, volSync bool) ramen.ProtectedPVC {
+			return ramen.ProtectedPVC{
+				Name:               name,
+				ProtectedByVolSync: volSync,
+				StorageClassName:   &storageClassName,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+				},
+			}
+		}
+		vrg := func(pvcs ...ramen.ProtectedPVC) *ramen.VolumeReplicationGroup {
+			return &ramen.VolumeReplicationGroup{Status: ramen.VolumeReplicationGroupStatus{ProtectedPVCs: pvcs}}
+		}
+
+		It("returns the PVCs protected by VolSync of the home cluster VRG, or else of the failover cluster VRG", func() {
+			vrgs := map[string]*ramen.VolumeReplicationGroup{
+				"east": vrg(protectedPVC("a", true), protectedPVC("b", false)),
+				"west": vrg(protectedPVC("c", true)),
+			}
+
+			pvcs := failoverProvisionedPVCs(vrgs, "west", "east").Status.ProtectedPVCs
+			Expect(pvcs).To(HaveLen(1))
+			Expect(pvcs[0].Name).To(Equal("a"))
+
+			delete(vrgs, "east")
+			pvcs = failoverProvisionedPVCs(vrgs, "west", "east").Status.ProtectedPVCs
+			Expect(pvcs).To(HaveLen(1))
+			Expect(pvcs[0].Name).To(Equal("c"))
+
+			Expect(failoverProvisionedPVCs(nil, "west", "east").Status.ProtectedPVCs).To(BeEmpty())
+		})
+
+		It("reports the storage classes short of their storage", func() {
+			report := &ramen.DRClusterOperatorReport{StorageCapacities: []ramen.StorageClassCapacity{{
+				StorageClassName:  storageClassName,
+				Capacity:          resource.MustParse("15Gi"),
+				LargestVolumeSize: resource.MustParse("15Gi"),
+			}}}
+
+			insufficient, _, _ := peerCapacityShortfalls(
+				vrg(protectedPVC("a", true), protectedPVC("b", true)), report)
+			Expect(insufficient).To(ConsistOf("rbd requested 20Gi exceeds capacity 15Gi"))
+		})
+	})
+
+	Describe("peerCapacityShortfalls", func() {
+		storageClassName := "rbd"
+		protectedPVC := func(name, storage string) ramen.ProtectedPVC {
+			return ramen.ProtectedPVC{
+				Name:             name,
+				StorageClassName: &storageClassName,
+				StorageIdentifiers: ramen.StorageIdentifiers{
+					StorageProvisioner: "rbd.csi.ceph.com",
+					StorageID:          ramen.Identifier{ID: "ceph-east"},
+				},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+				},
+			}
+		}
+		vrg := &ramen.VolumeReplicationGroup{Status: ramen.VolumeReplicationGroupStatus{
+			ProtectedPVCs: []ramen.ProtectedPVC{protectedPVC("a", "10Gi"), protectedPVC("b", "20Gi")},
+		}}
+		capacity := func(name, provisioner, storageID, capacity, largest string) ramen.StorageClassCapacity {
+			return ramen.StorageClassCapacity{
+				StorageClassName:  name,
+				Provisioner:       provisioner,
+				StorageID:         storageID,
+				Capacity:          resource.MustParse(capacity),
+				LargestVolumeSize: resource.MustParse(largest),
+			}
+		}
+		shortfalls := func(capacities ...ramen.StorageClassCapacity) []string {
+			insufficient, unknown, _ := peerCapacityShortfalls(vrg,
+				&ramen.DRClusterOperatorReport{StorageCapacities: capacities})
+			Expect(unknown).To(BeEmpty())
+
+			return insufficient
+		}
+
+		It("reports the storage classes short in total or for their largest PVC", func() {
+			Expect(shortfalls(capacity("rbd", "", "", "30Gi", "20Gi"))).To(BeEmpty())
+			Expect(shortfalls(capacity("rbd", "", "", "25Gi", "25Gi"))).To(
+				ConsistOf("rbd requested 30Gi exceeds capacity 25Gi"))
+			Expect(shortfalls(capacity("rbd", "", "", "40Gi", "15Gi"))).To(
+				ConsistOf("rbd largest PVC 20Gi exceeds largest volume size 15Gi"))
+		})
+
+		It("matches the storage class of the same name, else of the same storage ID, else of the same provisioner",
+			func() {
+				named := capacity("rbd", "other.csi.com", "", "10Gi", "10Gi")
+				sameStorageID := capacity("ceph-rbd", "rbd.csi.ceph.com", "ceph-east", "20Gi", "20Gi")
+				sameProvisioner := capacity("fast", "rbd.csi.ceph.com", "", "40Gi", "40Gi")
+				larger := capacity("slow", "rbd.csi.ceph.com", "", "50Gi", "15Gi")
+
+				Expect(shortfalls(named, sameStorageID, sameProvisioner)).To(
+					ConsistOf("rbd requested 30Gi exceeds capacity 10Gi"))
+				Expect(shortfalls(sameStorageID, sameProvisioner)).To(
+					ConsistOf("rbd requested 30Gi exceeds capacity 20Gi of ceph-rbd"))
+				Expect(shortfalls(sameProvisioner)).To(BeEmpty())
+				Expect(shortfalls(sameProvisioner, larger)).To(
+					ConsistOf("rbd largest PVC 20Gi exceeds largest volume size 15Gi of slow"))
+			})
+
+		It("reports the storage classes not matched as unknown, and counts the storage classes of the PVCs", func() {
+			report := &ramen.DRClusterOperatorReport{StorageCapacities: []ramen.StorageClassCapacity{
+				capacity("cephfs", "cephfs.csi.ceph.com", "", "1Ti", "1Ti"),
+			}}
+			insufficient, unknown, storageClasses := peerCapacityShortfalls(vrg, report)
+			Expect(insufficient).To(BeEmpty())
+			Expect(unknown).To(ConsistOf("rbd"))
+			Expect(storageClasses).To(Equal(1))
+
+			_, unknown, storageClasses = peerCapacityShortfalls(vrg, nil)
+			Expect(unknown).To(ConsistOf("rbd"))
+			Expect(storageClasses).To(Equal(1))
+
+			_, _, storageClasses = peerCapacityShortfalls(&ramen.VolumeReplicationGroup{}, nil)
+			Expect(storageClasses).To(BeZero())
+		})
+	})
+
+	Describe("peerCapacityCheck", func() {
+		var d *DRPCInstance
+
+		BeforeEach(func() {
+			storageClassName := "rbd"
+			d = &DRPCInstance{
+				reconciler: &DRPlacementControlReconciler{
+					eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+				},
+				log: ctrl.Log.WithName("drpc-peer-capacity-test"),
+				instance: &ramen.DRPlacementControl{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+					Status: ramen.DRPlacementControlStatus{
+						PreferredDecision: ramen.PlacementDecision{ClusterName: "east"},
+					},
+				},
+				ramenConfig: &ramen.RamenConfig{},
+				drPolicy: &ramen.DRPolicy{Spec: ramen.DRPolicySpec{
+					DRClusters: []string{"east", "west"}, SchedulingInterval: "5m",
+				}},
+				drType: DRTypeAsync,
+				drClusters: []ramen.DRCluster{
+					{ObjectMeta: metav1.ObjectMeta{Name: "east"}},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "west"},
+						Status: ramen.DRClusterStatus{Operator: &ramen.DRClusterOperatorReport{
+							StorageCapacities: []ramen.StorageClassCapacity{{
+								StorageClassName:  "rbd",
+								Capacity:          resource.MustParse("5Gi"),
+								LargestVolumeSize: resource.MustParse("5Gi"),
+							}},
+						}},
+					},
+				},
+				vrgs: map[string]*ramen.VolumeReplicationGroup{"east": {
+					ObjectMeta: metav1.ObjectMeta{Generation: 1},
+					Status: ramen.VolumeReplicationGroupStatus{
+						ObservedGeneration: 1,
+						Conditions:         []metav1.Condition{{Type: VRGConditionTypeDataReady}},
+						ProtectedPVCs: []ramen.ProtectedPVC{{
+							Name:             "a",
+							StorageClassName: &storageClassName,
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+							},
+						}},
+					},
+				}},
+			}
+		})
+		condition := func() *metav1.Condition {
+			return meta.FindStatusCondition(d.instance.Status.Conditions, ramen.ConditionPeerCapacityAvailable)
+		}
+
+		It("is pending until the home cluster VRG reports its PVCs", func() {
+			vrg := d.vrgs["east"]
+			delete(d.vrgs, "east")
+			d.peerCapacityCheck()
+			Expect(condition()).To(And(HaveField("Status", metav1.ConditionUnknown),
+				HaveField("Reason", ramen.ReasonCapacityPending)))
+
+			d.vrgs["east"] = vrg
+			vrg.Generation = 2
+			d.peerCapacityCheck()
+			Expect(condition().Reason).To(Equal(ramen.ReasonCapacityPending))
+
+			vrg.Generation = 1
+			d.peerCapacityCheck()
+			Expect(condition()).To(And(HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", ramen.ReasonCapacityInsufficient)))
+		})
+
+		It("is removed once the home cluster VRG reports no PVCs", func() {
+			d.peerCapacityCheck()
+			Expect(condition()).ToNot(BeNil())
+
+			d.vrgs["east"].Status.ProtectedPVCs = nil
+			d.peerCapacityCheck()
+			Expect(condition()).To(BeNil())
+		})
+
+		It("refuses to replicate, by holding the replication of the primary VRG, while pending or insufficient", func() {
+			d.ramenConfig.ProtectionCapacityCheck.Mode = ramen.ProtectionCapacityCheckRefuse
+			d.peerCapacityCheck()
+			Expect(d.protectionCapacityAdmit()).To(HaveOccurred())
+			Expect(d.generateVRG("east", ramen.Primary).Annotations).To(
+				HaveKeyWithValue(VRGReplicationHeldAnnotation, "true"))
+			Expect(d.generateVRG("west", ramen.Secondary).Annotations).ToNot(HaveKey(VRGReplicationHeldAnnotation))
+
+			d.instance.Annotations = map[string]string{DRPCSkipCapacityCheckAnnotation: "true"}
+			Expect(d.protectionCapacityAdmit()).To(Succeed())
+			Expect(d.generateVRG("east", ramen.Primary).Annotations).ToNot(HaveKey(VRGReplicationHeldAnnotation))
+
+			d.instance.Annotations = nil
+			d.drClusters[1].Status.Operator.StorageCapacities[0].Capacity = resource.MustParse("20Gi")
+			d.drClusters[1].Status.Operator.StorageCapacities[0].LargestVolumeSize = resource.MustParse("20Gi")
+			d.peerCapacityCheck()
+			Expect(condition().Status).To(Equal(metav1.ConditionTrue))
+			Expect(d.protectionCapacityAdmit()).To(Succeed())
+		})
+
+		It("keeps the condition, and refuses nothing, once the initial deployment completed", func() {
+			d.ramenConfig.ProtectionCapacityCheck.Mode = ramen.ProtectionCapacityCheckRefuse
+			d.peerCapacityCheck()
+			d.instance.Status.Phase = ramen.Deployed
+			d.vrgs["east"].Status.ProtectedPVCs = nil
+			d.peerCapacityCheck()
+			Expect(condition().Status).To(Equal(metav1.ConditionFalse))
+			Expect(d.protectionCapacityAdmit()).To(Succeed())
+		})
+	})
+
+	Describe("storageCapacities", func() {
+		It("reports the capacities of the storage classes with their provisioners and storage IDs", func() {
+			csiStorageCapacity := func(name, storageClassName, capacity string) *storagev1.CSIStorageCapacity {
+				quantity := resource.MustParse(capacity)
+
+				return &storagev1.CSIStorageCapacity{
+					ObjectMeta:       metav1.ObjectMeta{Namespace: "csi", Name: name},
+					StorageClassName: storageClassName,
+					Capacity:         &quantity,
+				}
+			}
+			c := fake.NewClientBuilder().WithObjects(
+				&storagev1.StorageClass{
+					ObjectMeta:  metav1.ObjectMeta{Name: "rbd", Labels: map[string]string{StorageIDLabel: "ceph-west"}},
+					Provisioner: "rbd.csi.ceph.com",
+				},
+				csiStorageCapacity("a", "rbd", "10Gi"),
+				csiStorageCapacity("b", "rbd", "20Gi"),
+				csiStorageCapacity("c", "gone", "1Gi"),
+			).Build()
+			reporter := &DRClusterOperatorStatusReporter{Client: c, APIReader: c}
+
+			capacities, err := reporter.storageCapacities(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(capacities).To(HaveLen(2))
+			Expect(capacities[0]).To(And(HaveField("StorageClassName", "gone"), HaveField("Provisioner", "")))
+			Expect(capacities[1]).To(And(HaveField("StorageClassName", "rbd"),
+				HaveField("Provisioner", "rbd.csi.ceph.com"), HaveField("StorageID", "ceph-west")))
+			Expect(capacities[1].Capacity.Equal(resource.MustParse("30Gi"))).To(BeTrue())
+			Expect(capacities[1].LargestVolumeSize.Equal(resource.MustParse("20Gi"))).To(BeTrue())
+		})
+	})
+
+	Describe("availableResources", func() {
+		node := func(name string, ready, unschedulable bool) *corev1.Node {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+				Status: corev1.NodeStatus{
+					Allocatable: resources("4", "8Gi"),
+					Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+				},
+			}
+		}
+		pod := func(name, nodeName string, phase corev1.PodPhase) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Containers: []corev1.Container{{
+						Name: "c", Resources: corev1.ResourceRequirements{Requests: resources("1", "1Gi")},
+					}},
+				},
+				Status: corev1.PodStatus{Phase: phase},
+			}
+		}
+
+		It("subtracts the requests of the running pods from the allocatable resources of the usable nodes", func() {
+			reporter := &DRClusterOperatorStatusReporter{
+				Client: fake.NewClientBuilder().WithObjects(
+					node("ready", true, false),
+					node("notready", false, false),
+					node("cordoned", true, true),
+					pod("running", "ready", corev1.PodRunning),
+					pod("succeeded", "ready", corev1.PodSucceeded),
+					pod("elsewhere", "cordoned", corev1.PodRunning),
+					pod("pending", "", corev1.PodPending),
+				).Build(),
+				Log: ctrl.Log.WithName("drclusteroperatorstatus-reporter-test"),
+			}
+
+			available, err := reporter.availableResources(context.TODO())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resourceListsEqual(available, resources("3", "7Gi"))).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// ClusterStatusLastHeardResolution is how much the time the status of a VRG was last heard from a cluster must
+// advance before the DRPC status reports it, for the DRPC status not to be updated at each refresh of the views
+const ClusterStatusLastHeardResolution = time.Minute
+
+// updateClusterStatuses sets the status of the VRG on each DR cluster of a DRPC: the role the DRPC requests of it in
+// its ManifestWork, read from the cache, the role and conditions it reports, and when its status was last heard, as
+// the StalePeerStatus condition timed it. The VRGs already fetched this reconcile are reported, and fetched only if
+// none were. A cluster whose VRG cannot be viewed keeps its reported role and conditions, with the time they were
+// last heard.
+func (r *DRPlacementControlReconciler) updateClusterStatuses(
+	ctx context.Context, drpc *rmn.DRPlacementControl, userPlacement client.Object,
+	vrgs map[string]*rmn.VolumeReplicationGroup, viewTimes map[string]*metav1.Time, log logr.Logger,
+) {
+	if isBeingDeleted(drpc, userPlacement) {
+		return
+	}
+
+	vrgNamespace, err := selectVRGNamespace(r.Client, r.Log, drpc, userPlacement)
+	if err != nil {
+		log.Info("Failed to select VRG namespace", "error", err)
+
+		return
+	}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		log.Info("Failed to get DRPolicy", "error", err)
+
+		return
+	}
+
+	drClusters, err := GetDRClusters(ctx, r.Client, drPolicy)
+	if err != nil {
+		log.Info("Failed to get DRClusters", "error", err)
+
+		return
+	}
+
+	failedCluster := ""
+
+	var vrgsErr error
+
+	if vrgs == nil {
+		vrgs, _, failedCluster, vrgsErr = getVRGsFromManagedClusters(r.MCVGetter, drpc, drClusters, vrgNamespace, log)
+		if vrgsErr != nil {
+			log.Info("Failed to get VRGs from DR clusters", "error", vrgsErr)
+		}
+	}
+
+	mwu := rmnutil.MWUtil{
+		Client:          r.Client,
+		APIReader:       r.APIReader,
+		Ctx:             ctx,
+		Log:             log,
+		InstName:        drpc.Name,
+		TargetNamespace: vrgNamespace,
+		Breaker:         r.ClusterBreaker,
+	}
+
+	statuses := make([]rmn.DRPCClusterStatus, 0, len(drClusters))
+
+	for i := range drClusters {
+		clusterName := drClusters[i].Name
+		previous := drpcClusterStatus(drpc, clusterName)
+
+		status := rmn.DRPCClusterStatus{
+			Name:        clusterName,
+			DesiredRole: vrgDesiredRole(&mwu, clusterName, log),
+			LastHeard:   clusterStatusLastHeard(previous.LastHeard, viewTimes[clusterName]),
+		}
+
+		switch vrg, found := vrgs[clusterName]; {
+		case found:
+			status.ReportedRole = vrg.Status.State
+			status.Conditions = vrg.Status.Conditions
+		case clusterName == failedCluster || vrgsErr != nil:
+			status.ReportedRole = previous.ReportedRole
+			status.Conditions = previous.Conditions
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	drpc.Status.Clusters = statuses
+}
+
+func drpcClusterStatus(drpc *rmn.DRPlacementControl, clusterName string) rmn.DRPCClusterStatus {
+	for _, status := range drpc.Status.Clusters {
+		if status.Name == clusterName {
+			return status
+		}
+	}
+
+	return rmn.DRPCClusterStatus{}
+}
+
+// vrgDesiredRole returns the replication state of the VRG in the ManifestWork of a cluster, or none if there is no
+// ManifestWork
+func vrgDesiredRole(mwu *rmnutil.MWUtil, clusterName string, log logr.Logger) rmn.ReplicationState {
+	mw, err := mwu.FindManifestWorkByType(rmnutil.MWTypeVRG, clusterName)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			log.Info("Failed to get VRG ManifestWork", "cluster", clusterName, "error", err)
+		}
+
+		return ""
+	}
+
+	vrg, err := rmnutil.ExtractVRGFromManifestWork(mw)
+	if err != nil {
+		log.Info("Failed to extract VRG from ManifestWork", "cluster", clusterName, "error", err)
+
+		return ""
+	}
+
+	return vrg.Spec.ReplicationState
+}
+
+// clusterStatusLastHeard returns the time the status of a VRG was last heard, as reported before unless it advanced
+// by ClusterStatusLastHeardResolution since
+func clusterStatusLastHeard(previous, viewTime *metav1.Time) *metav1.Time {
+	if viewTime == nil {
+		return previous
+	}
+
+	if previous != nil && viewTime.Time.Sub(previous.Time) < ClusterStatusLastHeardResolution {
+		return previous
+	}
+
+	return viewTime
+}
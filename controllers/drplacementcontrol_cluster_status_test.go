@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the status of the VRG on each DR cluster of a DRPC
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPC_ClusterStatus", func() {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	heard := func(ago time.Duration) *metav1.Time {
+		heard := metav1.NewTime(now.Add(-ago))
+
+		return &heard
+	}
+
+	DescribeTable("clusterStatusLastHeard",
+		func(previous, viewTime, expected *metav1.Time) {
+			Expect(clusterStatusLastHeard(previous, viewTime)).To(Equal(expected))
+		},
+		Entry("none if never heard", nil, nil, nil),
+		Entry("the time first heard", nil, heard(0), heard(0)),
+		Entry("the time heard before once no longer heard", heard(time.Hour), nil, heard(time.Hour)),
+		Entry("the time heard before within the resolution", heard(30*time.Second), heard(0), heard(30*time.Second)),
+		Entry("the time heard once advanced by the resolution", heard(ClusterStatusLastHeardResolution), heard(0),
+			heard(0)),
+	)
+
+	Describe("updateClusterStatuses", func() {
+		const namespace = "app"
+
+		var (
+			r             *DRPlacementControlReconciler
+			drpc          *rmn.DRPlacementControl
+			userPlacement *plrv1.PlacementRule
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(rmn.AddToScheme(scheme)).To(Succeed())
+			Expect(ocmworkv1.AddToScheme(scheme)).To(Succeed())
+			Expect(plrv1.AddToScheme(scheme)).To(Succeed())
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&rmn.DRPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+					Spec:       rmn.DRPolicySpec{DRClusters: []string{"east", "west"}},
+				},
+				&rmn.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: "east"}},
+				&rmn.DRCluster{ObjectMeta: metav1.ObjectMeta{Name: "west"}},
+			).Build()
+
+			// the VRGs are reported as fetched, and are never read from the clusters
+			r = &DRPlacementControlReconciler{
+				Client: c, APIReader: c, Log: ctrl.Log.WithName("drpc-cluster-status-test"),
+				MCVGetter: vrgStatusTimeGetter{},
+			}
+			drpc = &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "drpc"},
+				Spec:       rmn.DRPlacementControlSpec{DRPolicyRef: corev1.ObjectReference{Name: "policy"}},
+			}
+			userPlacement = &plrv1.PlacementRule{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "placement"}}
+
+			mwu := rmnutil.MWUtil{
+				Client: c, APIReader: c, Ctx: context.TODO(), Log: r.Log, InstName: drpc.Name, TargetNamespace: namespace,
+			}
+			vrg := rmn.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: drpc.Name},
+				Spec:       rmn.VolumeReplicationGroupSpec{ReplicationState: rmn.Primary},
+			}
+			Expect(mwu.CreateOrUpdateVRGManifestWork(drpc.Name, namespace, "east", vrg, nil, nil)).To(Succeed())
+		})
+
+		It("reports the VRGs already fetched, with their desired roles and when they were last heard", func() {
+			conditions := []metav1.Condition{{Type: VRGConditionTypeDataReady, Status: metav1.ConditionTrue}}
+			vrgs := map[string]*rmn.VolumeReplicationGroup{"east": {Status: rmn.VolumeReplicationGroupStatus{
+				State: rmn.PrimaryState, Conditions: conditions,
+			}}}
+			drpc.Status.Clusters = []rmn.DRPCClusterStatus{{Name: "west", LastHeard: heard(time.Hour)}}
+
+			r.updateClusterStatuses(context.TODO(), drpc, userPlacement, vrgs,
+				map[string]*metav1.Time{"east": heard(0)}, r.Log)
+
+			Expect(drpc.Status.Clusters).To(Equal([]rmn.DRPCClusterStatus{
+				{
+					Name: "east", DesiredRole: rmn.Primary, ReportedRole: rmn.PrimaryState, Conditions: conditions,
+					LastHeard: heard(0),
+				},
+				{Name: "west", LastHeard: heard(time.Hour)},
+			}))
+		})
+	})
+})
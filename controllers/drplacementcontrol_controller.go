@@ -7,16 +7,18 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
 	errorswrapper "github.com/pkg/errors"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
 	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
 	rmn "github.com/ramendr/ramen/api/v1alpha1"
@@ -65,6 +69,10 @@ const (
 
 	DoNotDeletePVCAnnotation    = "drplacementcontrol.ramendr.openshift.io/do-not-delete-pvc"
 	DoNotDeletePVCAnnotationVal = "true"
+
+	// DRPCPausedAnnotation set to "true" pauses the reconcile of a DRPC, holding its action, until it is removed
+	DRPCPausedAnnotation    = "drplacementcontrol.ramendr.openshift.io/paused"
+	DRPCPausedAnnotationVal = "true"
 )
 
 var InitialWaitTimeForDRPCPlacementRule = errorswrapper.New("Waiting for DRPC Placement to produces placement decision")
@@ -84,6 +92,14 @@ type DRPlacementControlReconciler struct {
 	savedInstanceStatus rmn.DRPlacementControlStatus
 	ObjStoreGetter      ObjectStoreGetter
 	RateLimiter         *workqueue.RateLimiter
+	notifier            *rmnutil.Notifier
+	ClusterBreaker      *rmnutil.ClusterCircuitBreaker
+
+	// runbookExports holds the runbook exports running in the background, keyed by DRPC namespaced name
+	runbookExports sync.Map
+
+	// runbookExportEvents requeues the DRPCs whose runbook exports completed
+	runbookExportEvents chan event.GenericEvent
 }
 
 func ManifestWorkPredicateFunc() predicate.Funcs {
@@ -633,6 +649,8 @@ func (r *DRPlacementControlReconciler) SetupWithManager(mgr ctrl.Manager) error
 		}))
 
 	r.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("controller_DRPlacementControl"))
+	r.notifier = rmnutil.NewNotifier()
+	r.runbookExportEvents = make(chan event.GenericEvent, runbookExportEventsSize)
 
 	options := ctrlcontroller.Options{
 		MaxConcurrentReconciles: getMaxConcurrentReconciles(ctrl.Log),
@@ -649,6 +667,7 @@ func (r *DRPlacementControlReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Watches(&plrv1.PlacementRule{}, usrPlRuleMapFun, builder.WithPredicates(usrPlRulePred)).
 		Watches(&clrapiv1beta1.Placement{}, usrPlmntMapFun, builder.WithPredicates(usrPlmntPred)).
 		Watches(&rmn.DRCluster{}, drClusterMapFun, builder.WithPredicates(drClusterPred)).
+		WatchesRawSource(&source.Channel{Source: r.runbookExportEvents}, &handler.EnqueueRequestForObject{}).
 		Complete(r)
 }
 
@@ -662,6 +681,8 @@ func (r *DRPlacementControlReconciler) SetupWithManager(mgr ctrl.Manager) error
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=placementrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.open-cluster-management.io,resources=placementrules/finalizers,verbs=get;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersetbindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=work.open-cluster-management.io,resources=manifestworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=view.open-cluster-management.io,resources=managedclusterviews,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy.open-cluster-management.io,resources=policies,verbs=get;list;watch;create;update;patch;delete
@@ -686,7 +707,7 @@ func (r *DRPlacementControlReconciler) SetupWithManager(mgr ctrl.Manager) error
 //
 //nolint:funlen,gocognit,gocyclo,cyclop
 func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := r.Log.WithValues("DRPC", req.NamespacedName, "rid", uuid.New())
+	logger := r.Log.WithValues(logKeyDRPC, req.NamespacedName, "rid", uuid.New())
 
 	logger.Info("Entering reconcile loop")
 	defer logger.Info("Exiting reconcile loop")
@@ -704,6 +725,11 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, errorswrapper.Wrap(err, "failed to get DRPC object")
 	}
 
+	logger = logger.WithValues(logKeyAction, drpc.Spec.Action)
+	if correlationID := actionCorrelationID(drpc); correlationID != "" {
+		logger = logger.WithValues(logKeyCorrelationID, correlationID)
+	}
+
 	// Save a copy of the instance status to be used for the VRG status update comparison
 	drpc.Status.DeepCopyInto(&r.savedInstanceStatus)
 
@@ -712,7 +738,7 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
 	if err != nil {
 		err = fmt.Errorf("failed to get the ramen configMap: %w", err)
-		r.recordFailure(ctx, drpc, nil, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, nil, ramenConfig, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
@@ -721,7 +747,7 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	placementObj, err = getPlacementOrPlacementRule(ctx, r.Client, drpc, logger)
 	if err != nil && !(errors.IsNotFound(err) && rmnutil.ResourceIsDeleted(drpc)) {
-		r.recordFailure(ctx, drpc, placementObj, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
@@ -729,38 +755,66 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if isBeingDeleted(drpc, placementObj) {
 		// DPRC depends on User PlacementRule/Placement. If DRPC or/and the User PlacementRule is deleted,
 		// then the DRPC should be deleted as well. The least we should do here is to clean up DPRC.
-		err := r.processDeletion(ctx, drpc, placementObj, logger)
+		err := r.processDeletion(ctx, drpc, placementObj, ramenConfig, logger)
 		if err != nil {
 			logger.Info(fmt.Sprintf("Error in deleting DRPC: (%v)", err))
 
+			if finalizerTimeoutElapsed(ramenConfig, drpcDeletionTimestamp(drpc, placementObj)) {
+				addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionAvailable, drpc.Generation,
+					metav1.ConditionFalse, ReasonDeletionTimedOut, finalizerTimeoutMessage(ramenConfig, err))
+			}
+
 			statusErr := r.setDeletionStatusAndUpdate(ctx, drpc)
 			if statusErr != nil {
 				err = fmt.Errorf("drpc deletion failed: %w and status update failed: %w", err, statusErr)
 			}
 
+			// Retry once the finalizer timeout elapses, rather than at the error backoff that may exceed it
+			result := ctrl.Result{}
+			finalizerTimeoutRequeue(&result, ramenConfig, drpcDeletionTimestamp(drpc, placementObj))
+
+			if result.RequeueAfter != 0 && statusErr == nil {
+				return result, nil
+			}
+
 			return ctrl.Result{}, err
 		}
 
 		return ctrl.Result{}, nil
 	}
 
+	if drpcPaused(drpc) {
+		logger.Info("DRPC is paused", "annotation", DRPCPausedAnnotation)
+
+		return ctrl.Result{}, r.setPausedStatusAndUpdate(ctx, drpc)
+	}
+
+	meta.RemoveStatusCondition(&drpc.Status.Conditions, rmn.ConditionPaused)
+
+	err = r.drPolicyRefCheck(ctx, drpc, placementObj, logger)
+	if err != nil {
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Error", err.Error(), logger)
+
+		return ctrl.Result{}, err
+	}
+
 	err = ensureDRPCValidNamespace(drpc, ramenConfig)
 	if err != nil {
-		r.recordFailure(ctx, drpc, placementObj, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
 
 	err = r.ensureNoConflictingDRPCs(ctx, drpc, ramenConfig, logger)
 	if err != nil {
-		r.recordFailure(ctx, drpc, placementObj, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
 
 	drPolicy, err := r.getAndEnsureValidDRPolicy(ctx, drpc, logger)
 	if err != nil {
-		r.recordFailure(ctx, drpc, placementObj, "Error", err.Error(), logger)
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Error", err.Error(), logger)
 
 		return ctrl.Result{}, err
 	}
@@ -783,12 +837,12 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	if requeue {
-		return ctrl.Result{Requeue: true}, r.updateDRPCStatus(ctx, drpc, placementObj, logger)
+		return ctrl.Result{Requeue: true}, r.updateDRPCStatus(ctx, drpc, placementObj, nil, ramenConfig, logger)
 	}
 
 	d, err := r.createDRPCInstance(ctx, drPolicy, drpc, placementObj, ramenConfig, logger)
 	if err != nil && !errorswrapper.Is(err, InitialWaitTimeForDRPCPlacementRule) {
-		err2 := r.updateDRPCStatus(ctx, drpc, placementObj, logger)
+		err2 := r.updateDRPCStatus(ctx, drpc, placementObj, nil, ramenConfig, logger)
 
 		return ctrl.Result{}, fmt.Errorf("failed to create DRPC instance (%w) and (%v)", err, err2)
 	}
@@ -796,7 +850,7 @@ func (r *DRPlacementControlReconciler) Reconcile(ctx context.Context, req ctrl.R
 	if errorswrapper.Is(err, InitialWaitTimeForDRPCPlacementRule) {
 		const initialWaitTime = 5
 
-		r.recordFailure(ctx, drpc, placementObj, "Waiting",
+		r.recordFailure(ctx, drpc, placementObj, ramenConfig, "Waiting",
 			fmt.Sprintf("%v - wait time: %v", InitialWaitTimeForDRPCPlacementRule, initialWaitTime), logger)
 
 		return ctrl.Result{RequeueAfter: time.Second * initialWaitTime}, nil
@@ -812,7 +866,7 @@ func (r *DRPlacementControlReconciler) setDeletionStatusAndUpdate(
 	drpc.Status.Phase = rmn.Deleting
 	drpc.Status.ObservedGeneration = drpc.Generation
 
-	if updated {
+	if updated || !reflect.DeepEqual(r.savedInstanceStatus.Conditions, drpc.Status.Conditions) {
 		if err := r.Status().Update(ctx, drpc); err != nil {
 			return fmt.Errorf("failed to update DRPC status: (%w)", err)
 		}
@@ -821,13 +875,30 @@ func (r *DRPlacementControlReconciler) setDeletionStatusAndUpdate(
 	return nil
 }
 
+// setPausedStatusAndUpdate reports a paused DRPC in its Paused condition, keeping the rest of its status as last
+// reconciled
+func (r *DRPlacementControlReconciler) setPausedStatusAndUpdate(
+	ctx context.Context, drpc *rmn.DRPlacementControl,
+) error {
+	if !addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionPaused, drpc.Generation, metav1.ConditionTrue,
+		rmn.ReasonPaused, fmt.Sprintf("Reconcile held until annotation %s is removed", DRPCPausedAnnotation)) {
+		return nil
+	}
+
+	if err := r.Status().Update(ctx, drpc); err != nil {
+		return fmt.Errorf("failed to update DRPC status: (%w)", err)
+	}
+
+	return nil
+}
+
 func (r *DRPlacementControlReconciler) recordFailure(ctx context.Context, drpc *rmn.DRPlacementControl,
-	placementObj client.Object, reason, msg string, log logr.Logger,
+	placementObj client.Object, ramenConfig *rmn.RamenConfig, reason, msg string, log logr.Logger,
 ) {
 	needsUpdate := addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionAvailable,
 		drpc.Generation, metav1.ConditionFalse, reason, msg)
 	if needsUpdate {
-		err := r.updateDRPCStatus(ctx, drpc, placementObj, log)
+		err := r.updateDRPCStatus(ctx, drpc, placementObj, nil, ramenConfig, log)
 		if err != nil {
 			log.Info(fmt.Sprintf("Failed to update DRPC status (%v)", err))
 		}
@@ -963,6 +1034,7 @@ func (r *DRPlacementControlReconciler) createDRPCInstance(
 			Log:             log,
 			InstName:        drpc.Name,
 			TargetNamespace: vrgNamespace,
+			Breaker:         r.ClusterBreaker,
 		},
 	}
 
@@ -1126,7 +1198,7 @@ func GetDRClusters(ctx context.Context, client client.Client, drPolicy *rmn.DRPo
 }
 
 // updateObjectMetadata updates drpc labels, annotations and finalizer, and also updates placementObj finalizer
-func (r DRPlacementControlReconciler) updateObjectMetadata(ctx context.Context,
+func (r *DRPlacementControlReconciler) updateObjectMetadata(ctx context.Context,
 	drpc *rmn.DRPlacementControl, placementObj client.Object, log logr.Logger,
 ) error {
 	update := false
@@ -1163,7 +1235,7 @@ func (r DRPlacementControlReconciler) updateObjectMetadata(ctx context.Context,
 }
 
 func (r *DRPlacementControlReconciler) processDeletion(ctx context.Context,
-	drpc *rmn.DRPlacementControl, placementObj client.Object, log logr.Logger,
+	drpc *rmn.DRPlacementControl, placementObj client.Object, ramenConfig *rmn.RamenConfig, log logr.Logger,
 ) error {
 	log.Info("Processing DRPC deletion")
 
@@ -1174,10 +1246,12 @@ func (r *DRPlacementControlReconciler) processDeletion(ctx context.Context,
 	// Run finalization logic for dprc.
 	// If the finalization logic fails, don't remove the finalizer so
 	// that we can retry during the next reconciliation.
-	if err := r.finalizeDRPC(ctx, drpc, placementObj, log); err != nil {
+	if err := r.finalizeDRPC(ctx, drpc, placementObj, ramenConfig, log); err != nil {
 		return err
 	}
 
+	r.runbookExports.Delete(client.ObjectKeyFromObject(drpc))
+
 	if placementObj != nil && controllerutil.ContainsFinalizer(placementObj, DRPCFinalizer) {
 		// Remove DRPCFinalizer from User PlacementRule/Placement.
 		controllerutil.RemoveFinalizer(placementObj, DRPCFinalizer)
@@ -1203,7 +1277,7 @@ func (r *DRPlacementControlReconciler) processDeletion(ctx context.Context,
 
 //nolint:funlen,cyclop
 func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *rmn.DRPlacementControl,
-	placementObj client.Object, log logr.Logger,
+	placementObj client.Object, ramenConfig *rmn.RamenConfig, log logr.Logger,
 ) error {
 	log.Info("Finalizing DRPC")
 
@@ -1234,6 +1308,7 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		Log:             r.Log,
 		InstName:        drpc.Name,
 		TargetNamespace: vrgNamespace,
+		Breaker:         r.ClusterBreaker,
 	}
 
 	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
@@ -1246,13 +1321,15 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		return fmt.Errorf("failed to get drclusters. Error (%w)", err)
 	}
 
+	forceCleanup := finalizerForceCleanup(ramenConfig, drpcDeletionTimestamp(drpc, placementObj))
+
 	// Verify VRGs have been deleted
-	vrgs, _, _, err := getVRGsFromManagedClusters(r.MCVGetter, drpc, drClusters, vrgNamespace, log)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve VRGs. We'll retry later. Error (%w)", err)
+	vrgs, _, _, vrgsErr := getVRGsFromManagedClusters(r.MCVGetter, drpc, drClusters, vrgNamespace, log)
+	if vrgsErr != nil && !forceCleanup {
+		return fmt.Errorf("failed to retrieve VRGs. We'll retry later. Error (%w)", vrgsErr)
 	}
 
-	if !ensureVRGsManagedByDRPC(r.Log, mwu, vrgs, drpc, vrgNamespace) {
+	if vrgsErr == nil && !ensureVRGsManagedByDRPC(r.Log, mwu, vrgs, drpc, vrgNamespace) && !forceCleanup {
 		return fmt.Errorf("VRG adoption in progress")
 	}
 
@@ -1264,8 +1341,8 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		}
 	}
 
-	if len(vrgs) != 0 {
-		return fmt.Errorf("waiting for VRGs count to go to zero")
+	if err := r.vrgsDeletionWait(drpc, vrgs, vrgsErr, forceCleanup, log); err != nil {
+		return err
 	}
 
 	// delete MCVs used in the previous call
@@ -1273,7 +1350,34 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 		return fmt.Errorf("error in deleting MCV (%w)", err)
 	}
 
-	// delete metrics if matching labels are found
+	deleteDRPCMetrics(drPolicy, drpc)
+
+	return nil
+}
+
+// vrgsDeletionWait returns an error while VRGs remain on the clusters, or may remain as they failed to be retrieved,
+// unless the cleanup is forced, in which case the VRGs left behind are reported with an event
+func (r *DRPlacementControlReconciler) vrgsDeletionWait(drpc *rmn.DRPlacementControl,
+	vrgs map[string]*rmn.VolumeReplicationGroup, vrgsErr error, forceCleanup bool, log logr.Logger,
+) error {
+	if len(vrgs) == 0 && vrgsErr == nil {
+		return nil
+	}
+
+	if !forceCleanup {
+		return fmt.Errorf("waiting for VRGs count to go to zero")
+	}
+
+	msg := fmt.Sprintf("Deletion forced after the finalizer timeout, VRGs may remain on clusters %v (%v)",
+		maps.Keys(vrgs), vrgsErr)
+	log.Info(msg)
+	rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeWarning, rmnutil.EventReasonDeleteForced, msg)
+
+	return nil
+}
+
+// deleteDRPCMetrics deletes the metrics of a DRPC, if matching labels are found
+func deleteDRPCMetrics(drPolicy *rmn.DRPolicy, drpc *rmn.DRPlacementControl) {
 	syncTimeMetricLabels := SyncTimeMetricLabels(drPolicy, drpc)
 	DeleteSyncTimeMetric(syncTimeMetricLabels)
 
@@ -1283,10 +1387,10 @@ func (r *DRPlacementControlReconciler) finalizeDRPC(ctx context.Context, drpc *r
 	syncDataBytesMetricLabels := SyncDataBytesMetricLabels(drPolicy, drpc)
 	DeleteSyncDataBytesMetric(syncDataBytesMetricLabels)
 
+	DeleteSyncStatusMetric(SyncStatusMetricLabels(drPolicy, drpc))
+
 	workloadProtectionLabels := WorkloadProtectionStatusLabels(drpc)
 	DeleteWorkloadProtectionStatusMetric(workloadProtectionLabels)
-
-	return nil
 }
 
 func (r *DRPlacementControlReconciler) deleteAllManagedClusterViews(
@@ -1636,7 +1740,13 @@ func getVRGsFromManagedClusters(
 	for i := range drClusters {
 		drCluster := &drClusters[i]
 
-		vrg, err := mcvGetter.GetVRGFromManagedCluster(drpc.Name, vrgNamespace, drCluster.Name, annotations)
+		vrg := vrgFromManifestWorkFeedback(mcvGetter, drpc, vrgNamespace, drCluster.Name, log)
+
+		var err error
+		if vrg == nil {
+			vrg, err = mcvGetter.GetVRGFromManagedCluster(drpc.Name, vrgNamespace, drCluster.Name, annotations)
+		}
+
 		if err != nil {
 			// Only NotFound error is accepted
 			if errors.IsNotFound(err) {
@@ -1678,6 +1788,26 @@ func getVRGsFromManagedClusters(
 	return vrgs, clustersQueriedSuccessfully, failedCluster, nil
 }
 
+// vrgFromManifestWorkFeedback returns the VRG of a cluster with the status fed back to its ManifestWork, or nil
+// to view the VRG instead. The feedback is only used once the DRPC completed its action, as an action changes the
+// VRG spec, and a VRG not updated since its spec changed is viewed for its generation, which is not fed back.
+func vrgFromManifestWorkFeedback(mcvGetter rmnutil.ManagedClusterViewGetter, drpc *rmn.DRPlacementControl,
+	vrgNamespace, cluster string, log logr.Logger,
+) *rmn.VolumeReplicationGroup {
+	if drpc.Status.Progression != rmn.ProgressionCompleted || rmnutil.ResourceIsDeleted(drpc) {
+		return nil
+	}
+
+	vrg, err := mcvGetter.GetVRGFromManifestWorkFeedback(drpc.Name, vrgNamespace, cluster)
+	if err != nil {
+		log.Info("VRG status feedback not read, viewing the VRG", "cluster", cluster, "error", err)
+
+		return nil
+	}
+
+	return vrg
+}
+
 func (r *DRPlacementControlReconciler) deleteClonedPlacementRule(ctx context.Context,
 	name, namespace string, log logr.Logger,
 ) error {
@@ -1759,15 +1889,20 @@ func (r *DRPlacementControlReconciler) getStatusCheckDelay(
 // updateDRPCStatus updates the DRPC sub-resource status with,
 // - the current instance DRPC status as updated during reconcile
 // - any updated VRG status as needs to be reflected in DRPC
+// - the VRGs already fetched from the DR clusters this reconcile, if any, per cluster
 // It also updates latest metrics for the current instance of DRPC.
 //
 //nolint:cyclop
-func (r *DRPlacementControlReconciler) updateDRPCStatus(
-	ctx context.Context, drpc *rmn.DRPlacementControl, userPlacement client.Object, log logr.Logger,
+func (r *DRPlacementControlReconciler) updateDRPCStatus(ctx context.Context, drpc *rmn.DRPlacementControl,
+	userPlacement client.Object, vrgs map[string]*rmn.VolumeReplicationGroup, ramenConfig *rmn.RamenConfig,
+	log logr.Logger,
 ) error {
 	log.Info("Updating DRPC status")
 
 	r.updateResourceCondition(ctx, drpc, userPlacement)
+	viewTimes := r.updatePeerStatusCondition(ctx, drpc, userPlacement, log)
+	r.updateClusterStatuses(ctx, drpc, userPlacement, vrgs, viewTimes, log)
+	r.updateDataSyncedCondition(ctx, drpc, userPlacement, ramenConfig, log)
 
 	// set metrics if DRPC is not being deleted and if finalizer exists
 	if !isBeingDeleted(drpc, userPlacement) && controllerutil.ContainsFinalizer(drpc, DRPCFinalizer) {
@@ -1785,10 +1920,12 @@ func (r *DRPlacementControlReconciler) updateDRPCStatus(
 		}
 	}
 
+	setDRPCHealth(drpc)
+
 	if reflect.DeepEqual(r.savedInstanceStatus, drpc.Status) {
 		log.Info("No need to update DRPC Status")
 
-		return nil
+		return r.updateDRPCHealthLabel(ctx, drpc, userPlacement)
 	}
 
 	now := metav1.Now()
@@ -1800,7 +1937,17 @@ func (r *DRPlacementControlReconciler) updateDRPCStatus(
 
 	log.Info("Updated DRPC Status")
 
-	return nil
+	return r.updateDRPCHealthLabel(ctx, drpc, userPlacement)
+}
+
+func (r *DRPlacementControlReconciler) updateDRPCHealthLabel(
+	ctx context.Context, drpc *rmn.DRPlacementControl, userPlacement client.Object,
+) error {
+	if isBeingDeleted(drpc, userPlacement) {
+		return nil
+	}
+
+	return healthLabelUpdate(ctx, r.Client, drpc, drpc.Status.Health)
 }
 
 // updateResourceCondition updates DRPC status sub-resource with updated status from VRG if one exists,
@@ -1880,6 +2027,11 @@ func (r *DRPlacementControlReconciler) updateResourceCondition(
 		drpc.Status.LastKubeObjectProtectionTime = &vrg.Status.KubeObjectProtection.CaptureToRecoverFrom.EndTime
 	}
 
+	// Retained while the primary VRG is unavailable, for a failover to check the workload fits the failover cluster
+	if len(vrg.Status.WorkloadRequests) != 0 {
+		drpc.Status.WorkloadRequests = vrg.Status.WorkloadRequests
+	}
+
 	updateDRPCProtectedCondition(drpc, vrg, clusterName)
 }
 
@@ -2506,6 +2658,7 @@ func (r *DRPlacementControlReconciler) determineDRPCState(
 		Log:             log,
 		InstName:        drpc.Name,
 		TargetNamespace: vrgNamespace,
+		Breaker:         r.ClusterBreaker,
 	}
 
 	if !ensureVRGsManagedByDRPC(log, mwu, vrgs, drpc, vrgNamespace) {
@@ -2762,11 +2915,9 @@ func adoptExistingVRGManifestWork(
 
 	vrg.Annotations[DRPCUIDAnnotation] = string(drpc.UID)
 
-	annotations := make(map[string]string)
-	annotations[DRPCNameAnnotation] = drpc.Name
-	annotations[DRPCNamespaceAnnotation] = drpc.Namespace
+	labels, annotations := vrgManifestWorkMetadata(drpc)
 
-	err := mwu.CreateOrUpdateVRGManifestWork(drpc.Name, vrgNamespace, cluster, *vrg, annotations)
+	err := mwu.CreateOrUpdateVRGManifestWork(drpc.Name, vrgNamespace, cluster, *vrg, labels, annotations)
 	if err != nil {
 		log.Info("error updating VRG via ManifestWork during adoption", "error", err, "cluster", cluster)
 	}
@@ -2784,9 +2935,7 @@ func adoptOrphanVRG(
 	log.Info("adopting orphaned VRG ManifestWork",
 		"cluster", cluster, "namespace", viewVRG.Namespace, "name", viewVRG.Name)
 
-	annotations := make(map[string]string)
-	annotations[DRPCNameAnnotation] = drpc.Name
-	annotations[DRPCNamespaceAnnotation] = drpc.Namespace
+	labels, annotations := vrgManifestWorkMetadata(drpc)
 
 	// Adopt the namespace as well
 	err := mwu.CreateOrUpdateNamespaceManifest(drpc.Name, vrgNamespace, cluster, annotations)
@@ -2805,7 +2954,7 @@ func adoptOrphanVRG(
 
 	if err := mwu.CreateOrUpdateVRGManifestWork(
 		drpc.Name, vrgNamespace,
-		cluster, *vrg, annotations); err != nil {
+		cluster, *vrg, labels, annotations); err != nil {
 		log.Info("error creating VRG via ManifestWork during adoption", "error", err, "cluster", cluster)
 	}
 }
@@ -2984,3 +3133,12 @@ func (r *DRPlacementControlReconciler) drpcHaveCommonClusters(ctx context.Contex
 
 	return drpolicyClusters.Intersection(otherDrpolicyClusters).Len() > 0, nil
 }
+
+// drpcDeletionTimestamp returns the time the DRPC, or its placement, was requested to be deleted
+func drpcDeletionTimestamp(drpc *rmn.DRPlacementControl, placementObj client.Object) *metav1.Time {
+	if drpc.GetDeletionTimestamp() != nil || placementObj == nil {
+		return drpc.GetDeletionTimestamp()
+	}
+
+	return placementObj.GetDeletionTimestamp()
+}
@@ -26,9 +26,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	spokeClusterV1 "github.com/open-cluster-management/api/cluster/v1"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 
 	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
 	rmn "github.com/ramendr/ramen/api/v1alpha1"
@@ -473,6 +473,36 @@ func fakeVRGConditionally(resourceNamespace, managedCluster string, err error) (
 	return nil, err
 }
 
+func (f FakeMCVGetter) GetVRGFromManifestWorkFeedback(resourceName, resourceNamespace, managedCluster string,
+) (*rmn.VolumeReplicationGroup, error) {
+	return nil, nil
+}
+
+func (f FakeMCVGetter) GetVRGFeedbackTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	return nil, nil
+}
+
+func (f FakeMCVGetter) GetVRGViewTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	// the conditions of the view of a steady VRG last transitioned when the view was created, while the view agent
+	// keeps refreshing its status
+	created := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	refreshed := metav1.Now()
+
+	return rmnutil.ManagedClusterViewTime(&viewv1beta1.ManagedClusterView{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: created,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "view-agent", Time: &refreshed, Subresource: "status"},
+			},
+		},
+		Status: viewv1beta1.ViewStatus{Conditions: []metav1.Condition{{
+			Type: viewv1beta1.ConditionViewProcessing, Status: metav1.ConditionTrue, LastTransitionTime: created,
+		}}},
+	}), nil
+}
+
 func (f FakeMCVGetter) DeleteVRGManagedClusterView(
 	resourceName, resourceNamespace, clusterName, resourceType string,
 ) error {
@@ -977,7 +1007,7 @@ func createVRGMW(name, namespace, homeCluster string) {
 		TargetNamespace: namespace,
 	}
 
-	Expect(mwu.CreateOrUpdateVRGManifestWork(name, namespace, homeCluster, *vrg, nil)).To(Succeed())
+	Expect(mwu.CreateOrUpdateVRGManifestWork(name, namespace, homeCluster, *vrg, nil, nil)).To(Succeed())
 }
 
 func updateManifestWorkStatus(clusterNamespace, vrgNamespace, mwType, workType string) {
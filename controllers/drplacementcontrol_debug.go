@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPCDebugPath is the path, on the metrics server, of the DRPC debug endpoint. A DRPC's view is served at
+// DRPCDebugPath<namespace>/<name>.
+const DRPCDebugPath = "/debug/drpc/"
+
+// DRPCDebugHandler serves the view a DRPC reconciler builds of a DRPC, as JSON, for triage
+type DRPCDebugHandler struct {
+	// Reconciler is set once the DRPC reconciler is set up; until then, requests fail as unavailable
+	Reconciler *DRPlacementControlReconciler
+}
+
+// drpcDebugView is the view of a DRPC served by the DRPC debug endpoint. Errors retrieving any part of the view are
+// reported in it instead of failing the request, so that the parts retrieved are served.
+type drpcDebugView struct {
+	DRPlacementControl *rmn.DRPlacementControl                `json:"drPlacementControl"`
+	DRPolicy           *rmn.DRPolicy                          `json:"drPolicy,omitempty"`
+	DRClusters         []string                               `json:"drClusters,omitempty"`
+	PlacementDecision  *clrapiv1beta1.ClusterDecision         `json:"placementDecision,omitempty"`
+	VRGNamespace       string                                 `json:"vrgNamespace,omitempty"`
+	VRGs               map[string]*rmn.VolumeReplicationGroup `json:"vrgs,omitempty"`
+	Errors             []string                               `json:"errors,omitempty"`
+}
+
+func (h *DRPCDebugHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if h.Reconciler == nil {
+		http.Error(w, "DRPC reconciler not set up", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	namespace, name, found := strings.Cut(strings.TrimPrefix(req.URL.Path, DRPCDebugPath), "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		http.Error(w, "expected path "+DRPCDebugPath+"<namespace>/<name>", http.StatusBadRequest)
+
+		return
+	}
+
+	view, err := h.Reconciler.debugView(req.Context(), types.NamespacedName{Namespace: namespace, Name: name})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+
+		http.Error(w, err.Error(), status)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(view); err != nil {
+		h.Reconciler.Log.Error(err, "DRPC debug view encode failed", "DRPC", namespace+"/"+name)
+	}
+}
+
+// debugView builds the view of a DRPC that a reconcile of it builds, from reads only
+func (r *DRPlacementControlReconciler) debugView(ctx context.Context, namespacedName types.NamespacedName,
+) (*drpcDebugView, error) {
+	log := r.Log.WithValues("DRPC", namespacedName, "debug", true)
+
+	drpc := &rmn.DRPlacementControl{}
+	if err := r.APIReader.Get(ctx, namespacedName, drpc); err != nil {
+		return nil, err
+	}
+
+	view := &drpcDebugView{DRPlacementControl: drpc}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		view.Errors = append(view.Errors, "DRPolicy: "+err.Error())
+
+		return view, nil
+	}
+
+	view.DRPolicy = drPolicy
+
+	drClusters, err := GetDRClusters(ctx, r.Client, drPolicy)
+	if err != nil {
+		view.Errors = append(view.Errors, "DRClusters: "+err.Error())
+
+		return view, nil
+	}
+
+	for i := range drClusters {
+		view.DRClusters = append(view.DRClusters, drClusters[i].Name)
+	}
+
+	placementObj, err := getPlacementOrPlacementRule(ctx, r.Client, drpc, log)
+	if err != nil {
+		view.Errors = append(view.Errors, "placement: "+err.Error())
+
+		return view, nil
+	}
+
+	view.PlacementDecision = r.getClusterDecision(placementObj)
+
+	view.VRGNamespace, err = selectVRGNamespace(r.Client, log, drpc, placementObj)
+	if err != nil {
+		view.Errors = append(view.Errors, "VRG namespace: "+err.Error())
+
+		return view, nil
+	}
+
+	view.VRGs = map[string]*rmn.VolumeReplicationGroup{}
+
+	for i := range drClusters {
+		cluster := drClusters[i].Name
+
+		vrg, err := r.debugViewVRG(ctx, drpc.Name, view.VRGNamespace, cluster)
+		if err != nil {
+			view.Errors = append(view.Errors, "VRG of "+cluster+": "+err.Error())
+
+			continue
+		}
+
+		if vrg != nil {
+			view.VRGs[cluster] = vrg
+		}
+	}
+
+	return view, nil
+}
+
+// debugViewVRG returns the VRG of a managed cluster last reported to the hub, as fed back to its ManifestWork, or else
+// as viewed by its ManagedClusterView, or nil if there is neither. Unlike a reconcile, it does not create or refresh
+// the view.
+func (r *DRPlacementControlReconciler) debugViewVRG(ctx context.Context, name, namespace, cluster string,
+) (*rmn.VolumeReplicationGroup, error) {
+	vrg, err := r.MCVGetter.GetVRGFromManifestWorkFeedback(name, namespace, cluster)
+	if err != nil || vrg != nil {
+		return vrg, err
+	}
+
+	mcv := &viewv1beta1.ManagedClusterView{}
+	key := types.NamespacedName{Namespace: cluster, Name: rmnutil.BuildManagedClusterViewName(name, namespace, "vrg")}
+
+	if err := r.Client.Get(ctx, key, mcv); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	vrg = &rmn.VolumeReplicationGroup{}
+	if err := r.MCVGetter.GetResource(mcv, vrg); err != nil {
+		return nil, err
+	}
+
+	return vrg, nil
+}
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the DRPC debug endpoint
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_Debug", func() {
+	var handler *DRPCDebugHandler
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+
+		return recorder
+	}
+	view := func(recorder *httptest.ResponseRecorder) *drpcDebugView {
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		view := &drpcDebugView{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), view)).To(Succeed())
+
+		return view
+	}
+
+	BeforeEach(func() {
+		handler = &DRPCDebugHandler{}
+	})
+
+	It("is unavailable until the DRPC reconciler is set up", func() {
+		Expect(serve(http.MethodGet, DRPCDebugPath+"app/drpc").Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	Describe("with the DRPC reconciler", func() {
+		var c client.Client
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&rmn.DRPlacementControl{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+					Spec:       rmn.DRPlacementControlSpec{DRPolicyRef: corev1.ObjectReference{Name: "policy"}},
+				},
+			).Build()
+			handler.Reconciler = &DRPlacementControlReconciler{
+				Client: c, APIReader: c, Log: ctrl.Log.WithName("drpc-debug-test"),
+			}
+		})
+
+		DescribeTable("rejects the requests of other methods or paths",
+			func(method, path string, status int) {
+				Expect(serve(method, path).Code).To(Equal(status))
+			},
+			Entry("a write", http.MethodPost, DRPCDebugPath+"app/drpc", http.StatusMethodNotAllowed),
+			Entry("no name", http.MethodGet, DRPCDebugPath+"app", http.StatusBadRequest),
+			Entry("an empty name", http.MethodGet, DRPCDebugPath+"app/", http.StatusBadRequest),
+			Entry("a subpath", http.MethodGet, DRPCDebugPath+"app/drpc/vrgs", http.StatusBadRequest),
+			Entry("a missing DRPC", http.MethodGet, DRPCDebugPath+"app/other", http.StatusNotFound),
+		)
+
+		It("serves the parts of the view retrieved, and the errors retrieving the others", func() {
+			drpcView := view(serve(http.MethodGet, DRPCDebugPath+"app/drpc"))
+			Expect(drpcView.DRPlacementControl.Name).To(Equal("drpc"))
+			Expect(drpcView.DRPolicy).To(BeNil())
+			Expect(drpcView.Errors).To(ConsistOf(HavePrefix("DRPolicy: ")))
+
+			Expect(c.Create(context.TODO(), &rmn.DRPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec:       rmn.DRPolicySpec{DRClusters: []string{"east", "west"}},
+			})).To(Succeed())
+
+			drpcView = view(serve(http.MethodGet, DRPCDebugPath+"app/drpc"))
+			Expect(drpcView.DRPolicy.Name).To(Equal("policy"))
+			Expect(drpcView.DRClusters).To(BeEmpty())
+			Expect(drpcView.Errors).To(ConsistOf(HavePrefix("DRClusters: ")))
+		})
+	})
+})
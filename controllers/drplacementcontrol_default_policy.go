@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ocmclv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DefaultDRPolicyAnnotation, on a namespace or a ManagedClusterSet, names the DRPolicy of the DRPCs, in the
+// namespace or placing on the cluster set, that do not refer to one. The annotation of the namespace takes
+// precedence over those of the cluster sets.
+const DefaultDRPolicyAnnotation = "ramendr.openshift.io/default-drpolicy"
+
+// DRPCDefaultDRPolicyWebhookPath is the path the DRPC default DRPolicy mutating webhook is served at
+const DRPCDefaultDRPolicyWebhookPath = "/mutate-ramendr-openshift-io-v1alpha1-drplacementcontrol"
+
+//+kubebuilder:webhook:path=/mutate-ramendr-openshift-io-v1alpha1-drplacementcontrol,mutating=true,failurePolicy=fail,sideEffects=None,groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=create,versions=v1alpha1,name=mdrplacementcontrol.ramendr.openshift.io,admissionReviewVersions=v1
+
+// DRPCDefaulter sets the DRPolicy reference of a DRPC created without one to its default DRPolicy. The reference
+// is immutable once the DRPC is created, so it is defaulted on admission rather than by the reconciler.
+type DRPCDefaulter struct {
+	APIReader client.Reader
+	Log       logr.Logger
+}
+
+var _ admission.CustomDefaulter = &DRPCDefaulter{}
+
+func (d *DRPCDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	drpc, ok := obj.(*rmn.DRPlacementControl)
+	if !ok {
+		return fmt.Errorf("expected a DRPlacementControl, got %T", obj)
+	}
+
+	if drpc.Spec.DRPolicyRef.Name != "" {
+		return nil
+	}
+
+	log := d.Log.WithValues("drpc", drpc.Namespace+"/"+drpc.Name)
+
+	// Only a Placement selects cluster sets of its own. The cluster sets bound to the namespace apply otherwise,
+	// including to a Placement created after its DRPC, which is validated once it is reconciled.
+	var placementObj client.Object
+
+	placement := &clrapiv1beta1.Placement{}
+	if err := d.APIReader.Get(ctx, types.NamespacedName{Namespace: drpc.GetNamespace(),
+		Name: drpc.Spec.PlacementRef.Name}, placement); err == nil {
+		placementObj = placement
+	} else if !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to get Placement %s (%w)", drpc.Spec.PlacementRef.Name, err)
+	}
+
+	name, source, err := defaultDRPolicy(ctx, d.APIReader, drpc, placementObj)
+	if err != nil || name == "" {
+		return err
+	}
+
+	log.Info("Setting default DRPolicy", "name", name, "source", source)
+
+	drpc.Spec.DRPolicyRef.Name = name
+
+	return nil
+}
+
+// drPolicyRefCheck fails for a DRPC without a DRPolicy reference, which is set on creation only. A DRPC that refers
+// to a DRPolicy other than the default keeps it, and the conflict is reported with an event, until the DRPC is first
+// reconciled with it.
+func (r *DRPlacementControlReconciler) drPolicyRefCheck(ctx context.Context, drpc *rmn.DRPlacementControl,
+	placementObj client.Object, log logr.Logger,
+) error {
+	if drpc.Spec.DRPolicyRef.Name != "" && controllerutil.ContainsFinalizer(drpc, DRPCFinalizer) {
+		return nil
+	}
+
+	name, source, err := defaultDRPolicy(ctx, r.APIReader, drpc, placementObj)
+	if err != nil {
+		return err
+	}
+
+	if drpc.Spec.DRPolicyRef.Name == "" {
+		if name == "" {
+			return fmt.Errorf("drPolicyRef is not set, and neither namespace %s nor its cluster sets are "+
+				"annotated with a default DRPolicy, %s", drpc.GetNamespace(), DefaultDRPolicyAnnotation)
+		}
+
+		return fmt.Errorf("drPolicyRef is not set, and is immutable, recreate the DRPC to set it to %s default "+
+			"DRPolicy %s, with drpcDefaultDRPolicyWebhookEnabled", source, name)
+	}
+
+	if name != "" && name != drpc.Spec.DRPolicyRef.Name {
+		msg := fmt.Sprintf("DRPolicy %s differs from %s default DRPolicy %s, which is ignored",
+			drpc.Spec.DRPolicyRef.Name, source, name)
+		log.Info(msg)
+		rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeWarning,
+			rmnutil.EventReasonDRPolicyDefaultConflict, msg)
+	}
+
+	return nil
+}
+
+// defaultDRPolicy returns the name of the default DRPolicy of a DRPC and where it is defined, or an empty name if it
+// has none. The placement is nil if it does not exist yet.
+func defaultDRPolicy(ctx context.Context, reader client.Reader, drpc *rmn.DRPlacementControl,
+	placementObj client.Object,
+) (string, string, error) {
+	namespace := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: drpc.GetNamespace()}, namespace); err != nil {
+		return "", "", fmt.Errorf("failed to get namespace %s (%w)", drpc.GetNamespace(), err)
+	}
+
+	if name := namespace.GetAnnotations()[DefaultDRPolicyAnnotation]; name != "" {
+		return name, "namespace " + namespace.GetName(), nil
+	}
+
+	clusterSetNames, err := placementClusterSetNames(ctx, reader, drpc.GetNamespace(), placementObj)
+	if err != nil {
+		return "", "", err
+	}
+
+	names := map[string][]string{}
+
+	for _, clusterSetName := range clusterSetNames {
+		clusterSet := &ocmclv1beta2.ManagedClusterSet{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: clusterSetName}, clusterSet); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return "", "", fmt.Errorf("failed to get ManagedClusterSet %s (%w)", clusterSetName, err)
+		}
+
+		if name := clusterSet.GetAnnotations()[DefaultDRPolicyAnnotation]; name != "" {
+			names[name] = append(names[name], clusterSetName)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return "", "", nil
+	case 1:
+		for name, clusterSetNames := range names {
+			return name, fmt.Sprintf("ManagedClusterSets %v", clusterSetNames), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("ManagedClusterSets of placement %s annotate different default DRPolicies %v, "+
+		"annotate namespace %s with %s to select one", drpc.Spec.PlacementRef.Name, names, drpc.GetNamespace(),
+		DefaultDRPolicyAnnotation)
+}
+
+// placementClusterSetNames returns the names of the cluster sets a placement selects clusters from: those of a
+// Placement, or, if it has none, or for a PlacementRule, those bound to its namespace, if cluster sets are installed
+func placementClusterSetNames(ctx context.Context, reader client.Reader, namespace string,
+	placementObj client.Object,
+) ([]string, error) {
+	if placement, ok := placementObj.(*clrapiv1beta1.Placement); ok && len(placement.Spec.ClusterSets) > 0 {
+		return placement.Spec.ClusterSets, nil
+	}
+
+	bindings := &ocmclv1beta2.ManagedClusterSetBindingList{}
+	if err := reader.List(ctx, bindings, client.InNamespace(namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to list ManagedClusterSetBindings of namespace %s (%w)", namespace, err)
+	}
+
+	names := make([]string, 0, len(bindings.Items))
+	for i := range bindings.Items {
+		names = append(names, bindings.Items[i].Spec.ClusterSet)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
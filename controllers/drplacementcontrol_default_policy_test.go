@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the defaulting of the DRPolicy of DRPCs on admission
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ocmclv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_DefaultDRPolicy", func() {
+	var (
+		objects []client.Object
+		drpc    *rmn.DRPlacementControl
+	)
+
+	clusterSet := func(name, drPolicyName string) *ocmclv1beta2.ManagedClusterSet {
+		return &ocmclv1beta2.ManagedClusterSet{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Annotations: map[string]string{DefaultDRPolicyAnnotation: drPolicyName},
+		}}
+	}
+	placement := func(clusterSets ...string) *clrapiv1beta1.Placement {
+		return &clrapiv1beta1.Placement{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "placement"},
+			Spec:       clrapiv1beta1.PlacementSpec{ClusterSets: clusterSets},
+		}
+	}
+	defaulter := func() *DRPCDefaulter {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(clrapiv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(ocmclv1beta2.AddToScheme(scheme)).To(Succeed())
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+		return &DRPCDefaulter{APIReader: c, Log: ctrl.Log.WithName("drpc-default-drpolicy-test")}
+	}
+
+	BeforeEach(func() {
+		objects = []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}},
+			clusterSet("east-west", "metro"),
+			clusterSet("north-south", "regional"),
+		}
+		drpc = &rmn.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+			Spec: rmn.DRPlacementControlSpec{PlacementRef: corev1.ObjectReference{
+				Kind: "Placement", Name: "placement",
+			}},
+		}
+	})
+
+	It("sets the default DRPolicy of the cluster sets of the placement", func() {
+		objects = append(objects, placement("east-west"))
+		Expect(defaulter().Default(context.TODO(), drpc)).To(Succeed())
+		Expect(drpc.Spec.DRPolicyRef.Name).To(Equal("metro"))
+	})
+
+	It("prefers the default DRPolicy of the namespace", func() {
+		objects[0].SetAnnotations(map[string]string{DefaultDRPolicyAnnotation: "namespaced"})
+		objects = append(objects, placement("east-west"))
+		Expect(defaulter().Default(context.TODO(), drpc)).To(Succeed())
+		Expect(drpc.Spec.DRPolicyRef.Name).To(Equal("namespaced"))
+	})
+
+	It("keeps the DRPolicy set on creation", func() {
+		drpc.Spec.DRPolicyRef.Name = "set"
+		Expect(defaulter().Default(context.TODO(), drpc)).To(Succeed())
+		Expect(drpc.Spec.DRPolicyRef.Name).To(Equal("set"))
+	})
+
+	It("rejects a DRPC whose cluster sets annotate different default DRPolicies", func() {
+		objects = append(objects, placement("east-west", "north-south"))
+		Expect(defaulter().Default(context.TODO(), drpc)).To(MatchError(ContainSubstring("different default")))
+		Expect(drpc.Spec.DRPolicyRef.Name).To(BeEmpty())
+	})
+})
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPCApprovalAnnotationPrefix prefixes the annotations with which approvers sign off the action of a DRPC held by a
+// DR freeze. Each is named for its approver, and set to the approval the DRPC's Available condition asks for, of the
+// action, the cluster it is to, and the DRPC's generation, so that an approval does not carry over to a later action.
+const DRPCApprovalAnnotationPrefix = "approval.ramendr.openshift.io/"
+
+// DRPCApprovalWebhookPath is the path the DRPC approval validating webhook is served at
+const DRPCApprovalWebhookPath = "/validate-ramendr-openshift-io-v1alpha1-drplacementcontrol-approval"
+
+//+kubebuilder:webhook:path=/validate-ramendr-openshift-io-v1alpha1-drplacementcontrol-approval,mutating=false,failurePolicy=fail,sideEffects=None,groups=ramendr.openshift.io,resources=drplacementcontrols,verbs=create;update,versions=v1alpha1,name=vdrplacementcontrolapproval.ramendr.openshift.io,admissionReviewVersions=v1
+
+// DRPCApprovalValidator rejects the creation or update of a DRPC that sets an approval annotation named for an
+// approver other than the user requesting it, so that the approvals a DR freeze counts are signed off by the
+// approvers they are named for, rather than by anyone who may annotate the DRPC
+type DRPCApprovalValidator struct {
+	Log logr.Logger
+}
+
+var _ admission.CustomValidator = &DRPCApprovalValidator{}
+
+func (v *DRPCApprovalValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(ctx, nil, obj)
+}
+
+func (v *DRPCApprovalValidator) ValidateUpdate(ctx context.Context, oldObj, obj runtime.Object,
+) (admission.Warnings, error) {
+	return nil, v.validate(ctx, oldObj, obj)
+}
+
+func (v *DRPCApprovalValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *DRPCApprovalValidator) validate(ctx context.Context, oldObj, obj runtime.Object) error {
+	drpc, ok := obj.(*rmn.DRPlacementControl)
+	if !ok {
+		return fmt.Errorf("expected a DRPlacementControl, got %T", obj)
+	}
+
+	oldAnnotations := map[string]string{}
+
+	if oldObj != nil {
+		oldDRPC, ok := oldObj.(*rmn.DRPlacementControl)
+		if !ok {
+			return fmt.Errorf("expected a DRPlacementControl, got %T", oldObj)
+		}
+
+		oldAnnotations = oldDRPC.GetAnnotations()
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := drFreezeApprovalsPermit(oldAnnotations, drpc.GetAnnotations(), req.UserInfo.Username); err != nil {
+		v.Log.Info("DRPC approval rejected", "drpc", drpc.Namespace+"/"+drpc.Name, "user", req.UserInfo.Username,
+			"error", err)
+
+		return k8serrors.NewForbidden(rmn.GroupVersion.WithResource("drplacementcontrols").GroupResource(),
+			drpc.Name, err)
+	}
+
+	return nil
+}
+
+// drFreezeApprovalsPermit returns an error if a user sets, over a DRPC's previous annotations, an approval
+// annotation named for another approver. Approvals may be removed by any user, as that only holds an action.
+func drFreezeApprovalsPermit(oldAnnotations, annotations map[string]string, username string) error {
+	for key, value := range annotations {
+		approver, ok := strings.CutPrefix(key, DRPCApprovalAnnotationPrefix)
+		if !ok || approver == username {
+			continue
+		}
+
+		if oldValue, ok := oldAnnotations[key]; !ok || oldValue != value {
+			return fmt.Errorf("user %s may not set the approval annotation %s of approver %s", username, key,
+				approver)
+		}
+	}
+
+	return nil
+}
+
+// drFreezeAdmit returns an error if a DR freeze holds the DRPC's action to a cluster, having fewer approvals than
+// required, or no approvals are counted as the approval webhook, which verifies who sets them, is not served. The
+// approvals admitting an action are reported with an event.
+func (d *DRPCInstance) drFreezeAdmit(cluster string) error {
+	freeze := d.ramenConfig.DRFreeze
+	if freeze == nil {
+		return nil
+	}
+
+	drpcName := d.instance.Namespace + "/" + d.instance.Name
+	if slices.Contains(freeze.ExemptDRPCs, drpcName) {
+		d.log.Info("DR freeze exempts DRPC")
+
+		return nil
+	}
+
+	if !d.ramenConfig.DRPCApprovalWebhookEnabled {
+		msg := fmt.Sprintf("DR freeze holds %s action to cluster %s: approvals are not counted unless the DRPC "+
+			"approval webhook is enabled", d.instance.Spec.Action, cluster)
+		rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonDRActionHeld, msg)
+
+		return errors.New(msg)
+	}
+
+	approval := drFreezeApproval(d.instance, cluster)
+	approvers := drFreezeApprovers(d.instance, freeze, approval)
+	required := drFreezeRequiredApprovals(freeze)
+
+	if len(approvers) >= required {
+		msg := fmt.Sprintf("%s action to cluster %s approved by %s", d.instance.Spec.Action, cluster,
+			strings.Join(approvers, ", "))
+		d.log.Info(msg)
+		rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeNormal,
+			rmnutil.EventReasonDRActionApproved, msg)
+
+		return nil
+	}
+
+	msg := fmt.Sprintf("DR freeze holds %s action to cluster %s with %d of %d approvals: approvers %s annotate with "+
+		"%s<approver>=%s", d.instance.Spec.Action, cluster, len(approvers), required,
+		strings.Join(freeze.Approvers, ", "), DRPCApprovalAnnotationPrefix, approval)
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+		rmnutil.EventReasonDRActionHeld, msg)
+
+	return errors.New(msg)
+}
+
+// drFreezeApproval returns the value of the annotations approving the DRPC's current action to a cluster
+func drFreezeApproval(drpc *rmn.DRPlacementControl, cluster string) string {
+	return fmt.Sprintf("%s:%s:%d", drpc.Spec.Action, cluster, drpc.Generation)
+}
+
+// drFreezeApprovers returns the sorted names of the approvers whose annotations of a DRPC approve an action
+func drFreezeApprovers(drpc *rmn.DRPlacementControl, freeze *rmn.DRFreeze, approval string) []string {
+	approvers := []string{}
+
+	for key, value := range drpc.GetAnnotations() {
+		approver, ok := strings.CutPrefix(key, DRPCApprovalAnnotationPrefix)
+		if ok && value == approval && slices.Contains(freeze.Approvers, approver) {
+			approvers = append(approvers, approver)
+		}
+	}
+
+	sort.Strings(approvers)
+
+	return approvers
+}
+
+func drFreezeRequiredApprovals(freeze *rmn.DRFreeze) int {
+	if freeze.RequiredApprovals == 0 {
+		return 1
+	}
+
+	return freeze.RequiredApprovals
+}
+
+// drFreezeValidate returns the errors of a DR freeze configuration
+func drFreezeValidate(freeze *rmn.DRFreeze, approvalWebhookEnabled bool) []error {
+	if freeze == nil {
+		return nil
+	}
+
+	errs := []error{}
+
+	if !approvalWebhookEnabled {
+		errs = append(errs, errors.New("drFreeze requires drpcApprovalWebhookEnabled, for its approvals to be "+
+			"set by their approvers"))
+	}
+
+	if len(freeze.Approvers) == 0 {
+		errs = append(errs, errors.New("drFreeze has no approvers"))
+	}
+
+	if freeze.RequiredApprovals < 0 || freeze.RequiredApprovals > len(freeze.Approvers) {
+		errs = append(errs, fmt.Errorf("drFreeze requiredApprovals %d is not between 0 and the %d approvers",
+			freeze.RequiredApprovals, len(freeze.Approvers)))
+	}
+
+	for _, drpcName := range freeze.ExemptDRPCs {
+		if namespace, name, ok := strings.Cut(drpcName, "/"); !ok || namespace == "" || name == "" {
+			errs = append(errs, fmt.Errorf("drFreeze exemptDRPCs %q is not a namespace/name", drpcName))
+		}
+	}
+
+	return errs
+}
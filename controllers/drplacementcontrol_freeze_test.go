@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the approvals of the actions of DRPCs held by a DR freeze
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_DRFreeze", func() {
+	const approval = "Failover:east:7"
+
+	annotations := func(approvers ...string) map[string]string {
+		annotations := map[string]string{"app": "busybox"}
+		for _, approver := range approvers {
+			annotations[DRPCApprovalAnnotationPrefix+approver] = approval
+		}
+
+		return annotations
+	}
+
+	Describe("drFreezeApprovalsPermit", func() {
+		It("permits approvers to set their own approvals", func() {
+			Expect(drFreezeApprovalsPermit(annotations(), annotations("alice"), "alice")).To(Succeed())
+			Expect(drFreezeApprovalsPermit(annotations("bob"), annotations("alice", "bob"), "alice")).To(Succeed())
+		})
+
+		It("rejects the approvals set or changed for another approver", func() {
+			Expect(drFreezeApprovalsPermit(annotations(), annotations("alice"), "mallory")).To(
+				MatchError(ContainSubstring("approver alice")))
+			Expect(drFreezeApprovalsPermit(annotations("alice"), map[string]string{
+				DRPCApprovalAnnotationPrefix + "alice": "Failover:east:8",
+			}, "mallory")).To(HaveOccurred())
+		})
+
+		It("permits any user to keep or remove the approvals of others, and to change other annotations", func() {
+			Expect(drFreezeApprovalsPermit(annotations("alice"), annotations("alice"), "mallory")).To(Succeed())
+			Expect(drFreezeApprovalsPermit(annotations("alice", "bob"), annotations("bob"), "mallory")).To(Succeed())
+			Expect(drFreezeApprovalsPermit(annotations(), map[string]string{"app": "other"}, "mallory")).To(Succeed())
+		})
+	})
+
+	Describe("DRPCApprovalValidator", func() {
+		validator := &DRPCApprovalValidator{Log: ctrl.Log.WithName("drpc-drfreeze-test")}
+		drpc := func(annotations map[string]string) *rmn.DRPlacementControl {
+			return &rmn.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "app", Name: "drpc", Annotations: annotations,
+			}}
+		}
+		requestBy := func(username string) context.Context {
+			return admission.NewContextWithRequest(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UserInfo: authenticationv1.UserInfo{Username: username},
+				},
+			})
+		}
+
+		It("admits an update by the approver the approval is named for", func() {
+			_, err := validator.ValidateUpdate(requestBy("alice"), drpc(annotations()), drpc(annotations("alice")))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("forbids a creation or an update forging the approval of another approver", func() {
+			_, err := validator.ValidateCreate(requestBy("mallory"), drpc(annotations("alice")))
+			Expect(k8serrors.IsForbidden(err)).To(BeTrue())
+
+			_, err = validator.ValidateUpdate(requestBy("mallory"), drpc(annotations()), drpc(annotations("alice")))
+			Expect(k8serrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("fails without an admission request", func() {
+			_, err := validator.ValidateCreate(context.TODO(), drpc(annotations()))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("drFreezeApprovers", func() {
+		It("counts the approvals of the configured approvers for the current action only", func() {
+			drpc := &rmn.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Annotations: annotations("carol", "alice",
+				"mallory")}}
+			drpc.Annotations[DRPCApprovalAnnotationPrefix+"bob"] = "Failover:east:6"
+
+			Expect(drFreezeApprovers(drpc, &rmn.DRFreeze{Approvers: []string{"alice", "bob", "carol"}}, approval)).To(
+				Equal([]string{"alice", "carol"}))
+		})
+	})
+
+	Describe("drFreezeValidate", func() {
+		freeze := &rmn.DRFreeze{Approvers: []string{"alice"}}
+
+		It("requires the approval webhook for a DR freeze", func() {
+			Expect(drFreezeValidate(freeze, true)).To(BeEmpty())
+			Expect(drFreezeValidate(freeze, false)).To(ConsistOf(MatchError(ContainSubstring(
+				"drpcApprovalWebhookEnabled"))))
+			Expect(drFreezeValidate(nil, false)).To(BeEmpty())
+		})
+	})
+})
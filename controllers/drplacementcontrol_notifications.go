@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// notify sends messages on an event of the DRPC to the notification sinks
+func (d *DRPCInstance) notify(event rmn.NotificationEvent, cluster, message string) {
+	d.reconciler.notifier.Notify(d.reconciler.APIReader, RamenOperatorNamespace(), d.ramenConfig.Notifications,
+		rmnutil.Notification{
+			Event:     event,
+			Kind:      "DRPlacementControl",
+			Namespace: d.instance.Namespace,
+			Name:      d.instance.Name,
+			Cluster:   cluster,
+			Message:   message,
+		}, d.log)
+}
+
+// notifyState sends messages on the DRPC's failover and relocate starting and completing
+//
+//nolint:exhaustive
+func (d *DRPCInstance) notifyState(state rmn.DRState, message string) {
+	switch state {
+	case rmn.FailingOver:
+		d.notify(rmn.NotificationEventFailoverStarted, d.instance.Spec.FailoverCluster, message)
+	case rmn.FailedOver:
+		d.notify(rmn.NotificationEventFailoverCompleted, d.instance.Spec.FailoverCluster, message)
+	case rmn.Relocating:
+		d.notify(rmn.NotificationEventRelocateStarted, d.instance.Spec.PreferredCluster, message)
+	case rmn.Relocated:
+		d.notify(rmn.NotificationEventRelocateCompleted, d.instance.Spec.PreferredCluster, message)
+	}
+}
+
+// rpoViolationNotify sends messages while the last group sync of an async DRPC is lagging, by the warning factor of
+// the sync thresholds that its DataSynced condition is set by
+func (d *DRPCInstance) rpoViolationNotify() {
+	if d.ramenConfig.Notifications == nil || d.drType != DRTypeAsync || d.instance.Status.LastGroupSyncTime == nil {
+		return
+	}
+
+	if !d.isInFinalPhase() {
+		return
+	}
+
+	seconds, err := rmnutil.GetSecondsFromSchedulingInterval(d.drPolicy)
+	if err != nil || seconds == 0 {
+		return
+	}
+
+	factor := syncWarningFactor(d.ramenConfig)
+	rpo := time.Duration(seconds*float64(factor)) * time.Second
+	age := time.Since(d.instance.Status.LastGroupSyncTime.Time)
+
+	if age <= rpo {
+		return
+	}
+
+	d.notify(rmn.NotificationEventRPOViolated, d.instance.Status.PreferredDecision.ClusterName,
+		fmt.Sprintf("last group sync %s ago exceeds %d times the scheduling interval %s",
+			age.Round(time.Second), factor, d.drPolicy.Spec.SchedulingInterval))
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// PeerStatusStaleThreshold is how old the view of a VRG's status on a DR cluster may be before the DRPC reports it
+// stale. Views are refreshed by the view agent every few seconds while the cluster is reachable, as is the status
+// fed back to the VRG ManifestWork by the work agent.
+const PeerStatusStaleThreshold = time.Minute * 5
+
+// updatePeerStatusCondition sets the StalePeerStatus condition of a DRPC when the view of the VRG on any of its DR
+// clusters is missing or older than PeerStatusStaleThreshold, with the age of each in minutes, and removes it
+// otherwise. This tells a broken status channel to a cluster apart from broken replication, which the VRG status
+// would report. It returns the times the status of each VRG was last refreshed, for the status of the clusters to
+// report, or none if they are not known.
+func (r *DRPlacementControlReconciler) updatePeerStatusCondition(
+	ctx context.Context, drpc *rmn.DRPlacementControl, userPlacement client.Object, log logr.Logger,
+) map[string]*metav1.Time {
+	if isBeingDeleted(drpc, userPlacement) {
+		return nil
+	}
+
+	vrgNamespace, err := selectVRGNamespace(r.Client, r.Log, drpc, userPlacement)
+	if err != nil {
+		log.Info("Failed to select VRG namespace", "error", err)
+
+		return nil
+	}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		log.Info("Failed to get DRPolicy", "error", err)
+
+		return nil
+	}
+
+	drClusters, err := GetDRClusters(ctx, r.Client, drPolicy)
+	if err != nil {
+		log.Info("Failed to get DRClusters", "error", err)
+
+		return nil
+	}
+
+	viewTimes := map[string]*metav1.Time{}
+
+	for i := range drClusters {
+		viewTime, err := vrgStatusTime(r.MCVGetter, drpc.Name, vrgNamespace, drClusters[i].Name)
+		if err != nil {
+			log.Info("Failed to get VRG status time", "cluster", drClusters[i].Name, "error", err)
+
+			return nil
+		}
+
+		viewTimes[drClusters[i].Name] = viewTime
+	}
+
+	setPeerStatusCondition(drpc, drClusters, viewTimes, time.Now())
+
+	return viewTimes
+}
+
+// vrgStatusTime returns the time the status of the VRG of a cluster was last refreshed, by its view or by the
+// status feedback of its ManifestWork, whichever is later, or nil if neither reports it. The status of a DRPC whose
+// action completed is read from the feedback, while its views are pruned once no longer read.
+func vrgStatusTime(mcvGetter rmnutil.ManagedClusterViewGetter, drpcName, vrgNamespace, cluster string,
+) (*metav1.Time, error) {
+	viewTime, err := mcvGetter.GetVRGViewTime(drpcName, vrgNamespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackTime, err := mcvGetter.GetVRGFeedbackTime(drpcName, vrgNamespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewTime == nil || (feedbackTime != nil && viewTime.Before(feedbackTime)) {
+		return feedbackTime, nil
+	}
+
+	return viewTime, nil
+}
+
+func setPeerStatusCondition(drpc *rmn.DRPlacementControl, drClusters []rmn.DRCluster,
+	viewTimes map[string]*metav1.Time, now time.Time,
+) {
+	stale := []string{}
+	missing := []string{}
+
+	for i := range drClusters {
+		clusterName := drClusters[i].Name
+
+		viewTime := viewTimes[clusterName]
+		if viewTime == nil {
+			missing = append(missing, fmt.Sprintf("VRG status from cluster %s missing", clusterName))
+
+			continue
+		}
+
+		if age := now.Sub(viewTime.Time); age > PeerStatusStaleThreshold {
+			stale = append(stale, fmt.Sprintf("VRG status from cluster %s is %s old", clusterName,
+				age.Truncate(time.Minute)))
+		}
+	}
+
+	if len(stale) == 0 && len(missing) == 0 {
+		meta.RemoveStatusCondition(&drpc.Status.Conditions, rmn.ConditionStalePeerStatus)
+
+		return
+	}
+
+	reason := rmn.ReasonPeerStatusStale
+	if len(stale) == 0 {
+		reason = rmn.ReasonPeerStatusMissing
+	}
+
+	addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionStalePeerStatus, drpc.Generation,
+		metav1.ConditionTrue, reason, strings.Join(append(stale, missing...), "; "))
+}
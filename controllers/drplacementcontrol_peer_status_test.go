@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the staleness of the status of the VRGs of a DRPC
+package controllers //nolint: testpackage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPC_PeerStatus", func() {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	drClusters := []rmn.DRCluster{{ObjectMeta: metav1.ObjectMeta{Name: "east"}}}
+	staleCondition := func(viewTime *metav1.Time) *metav1.Condition {
+		drpc := &rmn.DRPlacementControl{}
+		setPeerStatusCondition(drpc, drClusters, map[string]*metav1.Time{"east": viewTime}, now)
+
+		return meta.FindStatusCondition(drpc.Status.Conditions, rmn.ConditionStalePeerStatus)
+	}
+
+	It("times the view of a steady VRG by its latest refresh rather than its unchanging conditions", func() {
+		viewTime := rmnutil.ManagedClusterViewTime(steadyVRGView(now.Add(-time.Minute)))
+		Expect(viewTime.Time).To(Equal(now.Add(-time.Minute)))
+		Expect(staleCondition(viewTime)).To(BeNil())
+	})
+
+	It("reports the status of a VRG stale once its view is no longer refreshed", func() {
+		viewTime := rmnutil.ManagedClusterViewTime(steadyVRGView(now.Add(-time.Hour)))
+		condition := staleCondition(viewTime)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Reason).To(Equal(rmn.ReasonPeerStatusStale))
+		Expect(condition.Message).To(ContainSubstring("is 1h0m0s old"))
+	})
+
+	It("times a view whose status was never written by its creation", func() {
+		view := steadyVRGView(now)
+		view.ManagedFields = view.ManagedFields[:1]
+		view.Status.Conditions = nil
+		Expect(rmnutil.ManagedClusterViewTime(view).Time).To(Equal(now.Add(-24 * time.Hour)))
+	})
+
+	It("reports the status of a VRG missing without a view", func() {
+		Expect(staleCondition(nil).Reason).To(Equal(rmn.ReasonPeerStatusMissing))
+	})
+
+	Describe("vrgStatusTime", func() {
+		viewed := metav1.NewTime(now.Add(-time.Hour))
+		fedBack := metav1.NewTime(now.Add(-time.Minute))
+
+		It("times the status of a VRG read from the ManifestWork feedback once its view is pruned", func() {
+			statusTime, err := vrgStatusTime(vrgStatusTimeGetter{feedbackTime: &fedBack}, "drpc", "app", "east")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(statusTime).To(Equal(&fedBack))
+			Expect(staleCondition(statusTime)).To(BeNil())
+		})
+
+		It("times the status of a VRG by its view or its feedback, whichever is later", func() {
+			Expect(vrgStatusTime(vrgStatusTimeGetter{viewTime: &viewed, feedbackTime: &fedBack}, "drpc", "app",
+				"east")).To(Equal(&fedBack))
+			Expect(vrgStatusTime(vrgStatusTimeGetter{viewTime: &fedBack, feedbackTime: &viewed}, "drpc", "app",
+				"east")).To(Equal(&fedBack))
+			Expect(vrgStatusTime(vrgStatusTimeGetter{viewTime: &viewed}, "drpc", "app", "east")).To(Equal(&viewed))
+			Expect(vrgStatusTime(vrgStatusTimeGetter{}, "drpc", "app", "east")).To(BeNil())
+		})
+	})
+})
+
+// vrgStatusTimeGetter returns the given times of the view and the ManifestWork feedback of a VRG, and implements no
+// other getter
+type vrgStatusTimeGetter struct {
+	rmnutil.ManagedClusterViewGetter
+	viewTime     *metav1.Time
+	feedbackTime *metav1.Time
+}
+
+func (g vrgStatusTimeGetter) GetVRGViewTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	return g.viewTime, nil
+}
+
+func (g vrgStatusTimeGetter) GetVRGFeedbackTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	return g.feedbackTime, nil
+}
+
+// steadyVRGView returns a view of a VRG whose conditions last transitioned long ago, as those of a steady VRG do,
+// and whose status the view agent last refreshed at the given time
+func steadyVRGView(refreshed time.Time) *viewv1beta1.ManagedClusterView {
+	created := metav1.NewTime(refreshed.Add(-24 * time.Hour))
+	refreshedTime := metav1.NewTime(refreshed)
+
+	return &viewv1beta1.ManagedClusterView{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: created,
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "controller", Operation: metav1.ManagedFieldsOperationUpdate, Time: &created},
+				{
+					Manager: "view-agent", Operation: metav1.ManagedFieldsOperationUpdate, Time: &refreshedTime,
+					Subresource: "status",
+				},
+			},
+		},
+		Status: viewv1beta1.ViewStatus{Conditions: []metav1.Condition{{
+			Type: viewv1beta1.ConditionViewProcessing, Status: metav1.ConditionTrue, LastTransitionTime: created,
+			Reason: viewv1beta1.ReasonGetResource,
+		}}},
+	}
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// placementConstraintsSatisfied returns an error if the user Placement constraints rule out the cluster as the target
+// of a failover or relocate. Ramen schedules a disabled Placement by writing its decisions, and never updates its
+// spec, so the scheduler is not there to honor the Placement cluster sets, predicates, tolerations and spread
+// constraints for the target Ramen picks. Prioritizers only rank the clusters that satisfy these, and as the target
+// is the peer cluster in the DRPolicy, they are not considered. PlacementRules are not checked.
+func (d *DRPCInstance) placementConstraintsSatisfied(cluster string) error {
+	placement, ok := d.userPlacement.(*clrapiv1beta1.Placement)
+	if !ok {
+		return nil
+	}
+
+	managedCluster := &ocmclv1.ManagedCluster{}
+	if err := d.reconciler.Get(d.ctx, types.NamespacedName{Name: cluster}, managedCluster); err != nil {
+		return fmt.Errorf("failed to get ManagedCluster %s to check the Placement %s constraints (%w)",
+			cluster, placement.GetName(), err)
+	}
+
+	if err := placementConstraintsCheck(placement, managedCluster, time.Now()); err != nil {
+		return fmt.Errorf("cluster %s does not satisfy Placement %s: %w", cluster, placement.GetName(), err)
+	}
+
+	return nil
+}
+
+func placementConstraintsCheck(placement *clrapiv1beta1.Placement, cluster *ocmclv1.ManagedCluster,
+	now time.Time,
+) error {
+	spec := &placement.Spec
+
+	if len(spec.ClusterSets) > 0 &&
+		!slices.Contains(spec.ClusterSets, cluster.GetLabels()[clrapiv1beta1.ClusterSetLabel]) {
+		return fmt.Errorf("not in cluster sets %v", spec.ClusterSets)
+	}
+
+	if err := placementPredicatesCheck(spec.Predicates, cluster); err != nil {
+		return err
+	}
+
+	for _, taint := range cluster.Spec.Taints {
+		if !placementTaintTolerated(spec.Tolerations, taint, now) {
+			return fmt.Errorf("taint %s=%s:%s not tolerated", taint.Key, taint.Value, taint.Effect)
+		}
+	}
+
+	for _, constraint := range spec.SpreadPolicy.SpreadConstraints {
+		if constraint.WhenUnsatisfiable == clrapiv1beta1.ScheduleAnyway {
+			continue
+		}
+
+		if _, ok := clusterTopologyValue(cluster, constraint); !ok {
+			return fmt.Errorf("no %s %s to spread by", constraint.TopologyKeyType, constraint.TopologyKey)
+		}
+	}
+
+	return nil
+}
+
+// placementPredicatesCheck returns an error unless the cluster matches any of the predicates
+func placementPredicatesCheck(predicates []clrapiv1beta1.ClusterPredicate, cluster *ocmclv1.ManagedCluster) error {
+	if len(predicates) == 0 {
+		return nil
+	}
+
+	claims := labels.Set{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		claims[claim.Name] = claim.Value
+	}
+
+	for _, predicate := range predicates {
+		selector := predicate.RequiredClusterSelector
+
+		labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+		if err != nil {
+			return fmt.Errorf("invalid label selector (%w)", err)
+		}
+
+		claimSelector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+			MatchExpressions: selector.ClaimSelector.MatchExpressions,
+		})
+		if err != nil {
+			return fmt.Errorf("invalid claim selector (%w)", err)
+		}
+
+		if labelSelector.Matches(labels.Set(cluster.GetLabels())) && claimSelector.Matches(claims) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no predicate matches")
+}
+
+// placementTaintTolerated returns true if the taint does not rule out selecting the cluster, either as it only
+// prefers not to select it, or as it is tolerated. A failover or relocate newly selects the cluster, hence
+// NoSelectIfNew rules it out as NoSelect does.
+func placementTaintTolerated(tolerations []clrapiv1beta1.Toleration, taint ocmclv1.Taint, now time.Time) bool {
+	if taint.Effect == ocmclv1.TaintEffectPreferNoSelect {
+		return true
+	}
+
+	for _, toleration := range tolerations {
+		if toleration.Effect != "" && toleration.Effect != taint.Effect {
+			continue
+		}
+
+		if toleration.Key != "" && toleration.Key != taint.Key {
+			continue
+		}
+
+		if toleration.Operator != clrapiv1beta1.TolerationOpExists && toleration.Value != taint.Value {
+			continue
+		}
+
+		if toleration.TolerationSeconds != nil &&
+			now.After(taint.TimeAdded.Add(time.Duration(*toleration.TolerationSeconds)*time.Second)) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+func clusterTopologyValue(cluster *ocmclv1.ManagedCluster, constraint clrapiv1beta1.SpreadConstraintsTerm,
+) (string, bool) {
+	if constraint.TopologyKeyType == clrapiv1beta1.TopologyKeyTypeClaim {
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name == constraint.TopologyKey {
+				return claim.Value, true
+			}
+		}
+
+		return "", false
+	}
+
+	value, ok := cluster.GetLabels()[constraint.TopologyKey]
+
+	return value, ok
+}
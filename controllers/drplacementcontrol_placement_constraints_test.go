@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the Placement constraints check
+package controllers //nolint: testpackage
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_PlacementConstraints", func() {
+	var (
+		now       time.Time
+		placement *clrapiv1beta1.Placement
+		cluster   *ocmclv1.ManagedCluster
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		placement = &clrapiv1beta1.Placement{}
+		cluster = &ocmclv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "east",
+				Labels: map[string]string{
+					clrapiv1beta1.ClusterSetLabel: "dr",
+					"region":                      "us-east",
+				},
+			},
+			Status: ocmclv1.ManagedClusterStatus{
+				ClusterClaims: []ocmclv1.ManagedClusterClaim{{Name: "platform.open-cluster-management.io", Value: "AWS"}},
+			},
+		}
+	})
+
+	It("is satisfied without constraints", func() {
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+	})
+
+	It("checks the cluster sets", func() {
+		placement.Spec.ClusterSets = []string{"dr"}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+
+		placement.Spec.ClusterSets = []string{"other"}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(MatchError(ContainSubstring("cluster sets")))
+	})
+
+	It("requires any predicate to match both the labels and the claims", func() {
+		predicate := func(region, platform string) clrapiv1beta1.ClusterPredicate {
+			return clrapiv1beta1.ClusterPredicate{RequiredClusterSelector: clrapiv1beta1.ClusterSelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"region": region}},
+				ClaimSelector: clrapiv1beta1.ClusterClaimSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "platform.open-cluster-management.io",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{platform},
+				}}},
+			}}
+		}
+
+		placement.Spec.Predicates = []clrapiv1beta1.ClusterPredicate{predicate("us-east", "GCP")}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(MatchError(ContainSubstring("no predicate")))
+
+		placement.Spec.Predicates = append(placement.Spec.Predicates, predicate("us-east", "AWS"))
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+	})
+
+	It("requires the NoSelect and NoSelectIfNew taints to be tolerated", func() {
+		cluster.Spec.Taints = []ocmclv1.Taint{
+			{Key: "prefer", Effect: ocmclv1.TaintEffectPreferNoSelect},
+			{Key: "unreachable", Effect: ocmclv1.TaintEffectNoSelectIfNew},
+		}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(MatchError(ContainSubstring("not tolerated")))
+
+		placement.Spec.Tolerations = []clrapiv1beta1.Toleration{
+			{Key: "unreachable", Operator: clrapiv1beta1.TolerationOpExists},
+		}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+	})
+
+	It("stops tolerating a taint once the toleration seconds elapse", func() {
+		seconds := int64(60)
+		taint := ocmclv1.Taint{
+			Key: "maintenance", Value: "true", Effect: ocmclv1.TaintEffectNoSelect,
+			TimeAdded: metav1.NewTime(now.Add(-time.Minute / 2)),
+		}
+		tolerations := []clrapiv1beta1.Toleration{{
+			Key: "maintenance", Operator: clrapiv1beta1.TolerationOpEqual, Value: "true", TolerationSeconds: &seconds,
+		}}
+
+		Expect(placementTaintTolerated(tolerations, taint, now)).To(BeTrue())
+		Expect(placementTaintTolerated(tolerations, taint, now.Add(time.Minute))).To(BeFalse())
+
+		tolerations[0].Value = "false"
+		Expect(placementTaintTolerated(tolerations, taint, now)).To(BeFalse())
+	})
+
+	It("requires the topology of the DoNotSchedule spread constraints", func() {
+		placement.Spec.SpreadPolicy.SpreadConstraints = []clrapiv1beta1.SpreadConstraintsTerm{{
+			TopologyKey: "zone", TopologyKeyType: clrapiv1beta1.TopologyKeyTypeLabel,
+			WhenUnsatisfiable: clrapiv1beta1.ScheduleAnyway,
+		}}
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+
+		placement.Spec.SpreadPolicy.SpreadConstraints[0].WhenUnsatisfiable = clrapiv1beta1.DoNotSchedule
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(MatchError(ContainSubstring("to spread by")))
+
+		placement.Spec.SpreadPolicy.SpreadConstraints[0].TopologyKey = "platform.open-cluster-management.io"
+		placement.Spec.SpreadPolicy.SpreadConstraints[0].TopologyKeyType = clrapiv1beta1.TopologyKeyTypeClaim
+		Expect(placementConstraintsCheck(placement, cluster, now)).To(Succeed())
+	})
+
+	Describe("actionAdmit", func() {
+		It("runs the admission checks in order, and reports the first that fails in the Available condition", func() {
+			d := &DRPCInstance{instance: &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     rmn.DRPlacementControlStatus{Phase: rmn.FailedOver},
+			}}
+			checked := []string{}
+			admit := func(err error) func(string) error {
+				return func(cluster string) error {
+					checked = append(checked, cluster)
+
+					return err
+				}
+			}
+
+			Expect(d.actionAdmit("west", admit(nil), admit(nil))).To(Succeed())
+			Expect(checked).To(Equal([]string{"west", "west"}))
+			Expect(d.instance.Status.Conditions).To(BeEmpty())
+
+			checked = nil
+			Expect(d.actionAdmit("west", admit(errors.New("held")), admit(nil))).To(MatchError("held"))
+			Expect(checked).To(HaveLen(1))
+
+			condition := findCondition(d.instance.Status.Conditions, rmn.ConditionAvailable)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).To(Equal("held"))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		})
+	})
+})
@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPCFailoverPlanAnnotation requests a failover plan when set to a value that differs from the request of the most
+// recent plan in the DRPC status, such as a timestamp. Planning does not act on any cluster.
+const DRPCFailoverPlanAnnotation = "drplacementcontrol.ramendr.openshift.io/failover-plan"
+
+// failoverPlan plans a failover when requested, and records the plan in the DRPC status
+func (d *DRPCInstance) failoverPlan() {
+	request, ok := d.instance.GetAnnotations()[DRPCFailoverPlanAnnotation]
+	if !ok {
+		d.instance.Status.FailoverPlan = nil
+
+		return
+	}
+
+	if d.instance.Status.FailoverPlan != nil && d.instance.Status.FailoverPlan.Request == request {
+		return
+	}
+
+	cluster := d.readinessCheckCluster()
+	homeCluster := d.getCurrentHomeClusterName(cluster, d.drClusters)
+	now := metav1.Now()
+	plan := &rmn.FailoverPlan{Request: request, Cluster: cluster, HomeCluster: homeCluster, PlanTime: &now}
+
+	plan.Blockers = d.failoverPlanBlockers(cluster, homeCluster)
+
+	vrg := d.failoverPlanVRG(cluster, homeCluster)
+	if cluster != "" {
+		plan.Steps = d.failoverPlanSteps(cluster, homeCluster, vrg)
+	}
+
+	plan.EstimatedDataBytes = failoverPlanDataBytes(vrg)
+
+	d.log.Info("Failover planned", "request", request, "cluster", cluster, "steps", len(plan.Steps),
+		"blockers", len(plan.Blockers))
+
+	d.instance.Status.FailoverPlan = plan
+}
+
+// failoverPlanBlockers returns the failed readiness checks of the failover cluster, and, for synchronous
+// replication, whether the home cluster is yet to be fenced
+func (d *DRPCInstance) failoverPlanBlockers(cluster, homeCluster string) []string {
+	if cluster == "" {
+		return []string{"no failover target"}
+	}
+
+	blockers := []string{}
+
+	for _, check := range d.readinessChecks() {
+		if passed, msg := check.check(cluster); !passed {
+			blockers = append(blockers, fmt.Sprintf("%s: %s", check.name, msg))
+		}
+	}
+
+	if d.drType == DRTypeSync && homeCluster != "" {
+		fenced, err := d.checkClusterFenced(homeCluster, d.drClusters)
+		if err != nil {
+			blockers = append(blockers, err.Error())
+		} else if !fenced {
+			blockers = append(blockers, fmt.Sprintf("current home cluster %s is not fenced", homeCluster))
+		}
+	}
+
+	return blockers
+}
+
+// failoverPlanVRG returns the VRG that last reported the protected PVCs: that of the home cluster, or else that of
+// the failover cluster
+func (d *DRPCInstance) failoverPlanVRG(cluster, homeCluster string) *rmn.VolumeReplicationGroup {
+	if vrg, ok := d.vrgs[homeCluster]; ok {
+		return vrg
+	}
+
+	return d.vrgs[cluster]
+}
+
+func (d *DRPCInstance) failoverPlanSteps(cluster, homeCluster string,
+	vrg *rmn.VolumeReplicationGroup,
+) []rmn.FailoverPlanStep {
+	vrgResource := failoverPlanResource("VolumeReplicationGroup", d.vrgNamespace, d.instance.GetName())
+	steps := []rmn.FailoverPlanStep{}
+
+	if d.drType == DRTypeSync && homeCluster != "" {
+		steps = append(steps, rmn.FailoverPlanStep{
+			Description: fmt.Sprintf("Wait for cluster %s to be fenced", homeCluster),
+			Resources:   []string{failoverPlanResource("DRCluster", "", homeCluster)},
+		})
+	}
+
+	steps = append(steps, rmn.FailoverPlanStep{
+		Description: fmt.Sprintf("Update the VRG ManifestWork of cluster %s for the VRG to be primary", cluster),
+		Resources: []string{failoverPlanResource("ManifestWork", cluster,
+			rmnutil.ManifestWorkName(d.instance.GetName(), d.vrgNamespace, rmnutil.MWTypeVRG))},
+	}, rmn.FailoverPlanStep{
+		Cluster:     cluster,
+		Description: "Create or update the VRG as primary",
+		Resources:   []string{vrgResource},
+	})
+
+	steps = append(steps, failoverPlanPVCSteps(cluster, vrg)...)
+
+	if d.instance.Spec.KubeObjectProtection != nil {
+		description := "Recover the kube objects from the latest capture"
+		if d.instance.Status.LastKubeObjectProtectionTime != nil {
+			description = fmt.Sprintf("Recover the kube objects from the capture of %s",
+				d.instance.Status.LastKubeObjectProtectionTime.UTC())
+		}
+
+		steps = append(steps, rmn.FailoverPlanStep{Cluster: cluster, Description: description})
+	}
+
+	if d.userPlacement != nil {
+		kind := "Placement"
+		if ConvertToPlacementRule(d.userPlacement) != nil {
+			kind = "PlacementRule"
+		}
+
+		steps = append(steps, rmn.FailoverPlanStep{
+			Description: fmt.Sprintf("Place the workload on cluster %s", cluster),
+			Resources: []string{failoverPlanResource(kind, d.userPlacement.GetNamespace(),
+				d.userPlacement.GetName())},
+		})
+	}
+
+	if homeCluster != "" {
+		steps = append(steps, rmn.FailoverPlanStep{
+			Cluster:     homeCluster,
+			Description: "Once the cluster is reachable, make the VRG secondary to protect the workload again",
+			Resources:   []string{vrgResource},
+		})
+	}
+
+	return steps
+}
+
+// failoverPlanPVCSteps returns the steps restoring the protected PVCs on the failover cluster: from the cluster data
+// in the S3 stores for those replicated by storage, and from the latest snapshots for those replicated by VolSync
+func failoverPlanPVCSteps(cluster string, vrg *rmn.VolumeReplicationGroup) []rmn.FailoverPlanStep {
+	if vrg == nil {
+		return []rmn.FailoverPlanStep{{
+			Cluster:     cluster,
+			Description: "Restore the protected PVCs, none of which are reported",
+		}}
+	}
+
+	volRepPVCs, volSyncPVCs := []string{}, []string{}
+
+	for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+		resource := failoverPlanResource("PersistentVolumeClaim", protectedPVC.Namespace, protectedPVC.Name)
+
+		if protectedPVC.ProtectedByVolSync {
+			volSyncPVCs = append(volSyncPVCs, resource)
+		} else {
+			volRepPVCs = append(volRepPVCs, resource)
+		}
+	}
+
+	steps := []rmn.FailoverPlanStep{}
+
+	if len(volRepPVCs) > 0 {
+		steps = append(steps, rmn.FailoverPlanStep{
+			Cluster:     cluster,
+			Description: "Restore the PVs and PVCs from the cluster data in the S3 stores, and promote their volumes",
+			Resources:   volRepPVCs,
+		})
+	}
+
+	if len(volSyncPVCs) > 0 {
+		steps = append(steps, rmn.FailoverPlanStep{
+			Cluster:     cluster,
+			Description: "Restore the PVCs from the latest snapshots replicated by VolSync",
+			Resources:   volSyncPVCs,
+		})
+	}
+
+	return steps
+}
+
+// failoverPlanDataBytes returns the storage requested by the protected PVCs, or nil if they are not reported
+func failoverPlanDataBytes(vrg *rmn.VolumeReplicationGroup) *int64 {
+	if vrg == nil {
+		return nil
+	}
+
+	var bytes int64
+
+	for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+		if storage, ok := protectedPVC.Resources.Requests[corev1.ResourceStorage]; ok {
+			bytes += storage.Value()
+		}
+	}
+
+	return &bytes
+}
+
+func failoverPlanResource(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + " " + name
+	}
+
+	return fmt.Sprintf("%s %s/%s", kind, namespace, name)
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the steps and estimates of a failover plan
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_FailoverPlan", func() {
+	protectedPVC := func(name, storage string, volSync bool) rmn.ProtectedPVC {
+		return rmn.ProtectedPVC{
+			Namespace:          "app",
+			Name:               name,
+			ProtectedByVolSync: volSync,
+			Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse(storage),
+			}},
+		}
+	}
+
+	var (
+		vrg *rmn.VolumeReplicationGroup
+		d   *DRPCInstance
+	)
+
+	BeforeEach(func() {
+		vrg = &rmn.VolumeReplicationGroup{Status: rmn.VolumeReplicationGroupStatus{
+			ProtectedPVCs: []rmn.ProtectedPVC{
+				protectedPVC("db", "1Gi", false),
+				protectedPVC("cache", "512Mi", true),
+			},
+		}}
+		d = &DRPCInstance{
+			log: ctrl.Log.WithName("drpc-failover-plan-test"),
+			instance: &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+			},
+			vrgNamespace: "app",
+			drType:       DRTypeAsync,
+			vrgs:         map[string]*rmn.VolumeReplicationGroup{"east": vrg},
+		}
+	})
+
+	Describe("failoverPlanPVCSteps", func() {
+		It("restores the PVCs replicated by storage and by VolSync in steps of their own", func() {
+			steps := failoverPlanPVCSteps("west", vrg)
+			Expect(steps).To(HaveLen(2))
+			Expect(steps[0].Resources).To(Equal([]string{"PersistentVolumeClaim app/db"}))
+			Expect(steps[1].Resources).To(Equal([]string{"PersistentVolumeClaim app/cache"}))
+			Expect(steps[1].Cluster).To(Equal("west"))
+		})
+
+		It("plans a step for the PVCs not reported", func() {
+			Expect(failoverPlanPVCSteps("west", nil)).To(HaveLen(1))
+		})
+	})
+
+	Describe("failoverPlanDataBytes", func() {
+		It("sums the storage requested by the protected PVCs", func() {
+			Expect(failoverPlanDataBytes(vrg)).To(HaveValue(Equal(int64(1536 * 1024 * 1024))))
+			Expect(failoverPlanDataBytes(nil)).To(BeNil())
+		})
+	})
+
+	Describe("failoverPlanResource", func() {
+		It("names namespaced and cluster scoped resources", func() {
+			Expect(failoverPlanResource("DRCluster", "", "east")).To(Equal("DRCluster east"))
+			Expect(failoverPlanResource("VolumeReplicationGroup", "app", "drpc")).To(
+				Equal("VolumeReplicationGroup app/drpc"))
+		})
+	})
+
+	Describe("failoverPlanVRG", func() {
+		It("prefers the VRG of the home cluster", func() {
+			westVRG := &rmn.VolumeReplicationGroup{}
+			d.vrgs["west"] = westVRG
+			Expect(d.failoverPlanVRG("west", "east")).To(BeIdenticalTo(vrg))
+			Expect(d.failoverPlanVRG("west", "")).To(BeIdenticalTo(westVRG))
+		})
+	})
+
+	Describe("failoverPlanSteps", func() {
+		descriptions := func(steps []rmn.FailoverPlanStep) []string {
+			result := []string{}
+			for _, step := range steps {
+				result = append(result, step.Description)
+			}
+
+			return result
+		}
+
+		It("promotes the VRG on the failover cluster, and demotes that of the home cluster last", func() {
+			steps := d.failoverPlanSteps("west", "east", vrg)
+			Expect(steps).To(HaveLen(5))
+			Expect(steps[1].Cluster).To(Equal("west"))
+			Expect(steps[1].Resources).To(Equal([]string{"VolumeReplicationGroup app/drpc"}))
+			Expect(steps[4].Cluster).To(Equal("east"))
+			Expect(descriptions(steps)).ToNot(ContainElement(ContainSubstring("fenced")))
+		})
+
+		It("waits for the home cluster to be fenced first for synchronous replication", func() {
+			d.drType = DRTypeSync
+			steps := d.failoverPlanSteps("west", "east", vrg)
+			Expect(steps[0].Description).To(ContainSubstring("east to be fenced"))
+			Expect(steps[0].Resources).To(Equal([]string{"DRCluster east"}))
+		})
+
+		It("recovers the kube objects if they are protected", func() {
+			d.instance.Spec.KubeObjectProtection = &rmn.KubeObjectProtectionSpec{}
+			Expect(descriptions(d.failoverPlanSteps("west", "", vrg))).To(
+				ContainElement(ContainSubstring("Recover the kube objects")))
+		})
+	})
+
+	Describe("failoverPlan", func() {
+		It("removes the plan once it is no longer requested, and does not plan again for the same request", func() {
+			d.instance.Status.FailoverPlan = &rmn.FailoverPlan{Request: "1"}
+			d.failoverPlan()
+			Expect(d.instance.Status.FailoverPlan).To(BeNil())
+
+			plan := &rmn.FailoverPlan{Request: "1"}
+			d.instance.Annotations = map[string]string{DRPCFailoverPlanAnnotation: "1"}
+			d.instance.Status.FailoverPlan = plan
+			d.failoverPlan()
+			Expect(d.instance.Status.FailoverPlan).To(BeIdenticalTo(plan))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// DRPCReadinessCheckAnnotation requests a DR readiness check when set to a value that differs from the request of
+// the most recent check in the DRPC status, such as a timestamp
+const DRPCReadinessCheckAnnotation = "drplacementcontrol.ramendr.openshift.io/readiness-check"
+
+const (
+	ReadinessCheckFailoverTarget       = "FailoverTarget"
+	ReadinessCheckPeerReachable        = "PeerReachable"
+	ReadinessCheckSecretsPresent       = "SecretsPresent"
+	ReadinessCheckReplicationHealthy   = "ReplicationHealthy"
+	ReadinessCheckCapacityAvailable    = "CapacityAvailable"
+	ReadinessCheckPlacementConstraints = "PlacementConstraints"
+)
+
+// readinessCheck runs a DR readiness check when requested, and records the result in the DRPC status
+func (d *DRPCInstance) readinessCheck() {
+	request, ok := d.instance.GetAnnotations()[DRPCReadinessCheckAnnotation]
+	if !ok {
+		d.instance.Status.Readiness = nil
+
+		return
+	}
+
+	if d.instance.Status.Readiness != nil && d.instance.Status.Readiness.Request == request {
+		return
+	}
+
+	cluster := d.readinessCheckCluster()
+	now := metav1.Now()
+	readiness := &rmn.ReadinessStatus{Request: request, Cluster: cluster, Ready: true, CheckTime: &now}
+
+	for _, check := range d.readinessChecks() {
+		passed, msg := false, "no failover target"
+		if cluster != "" {
+			passed, msg = check.check(cluster)
+		}
+
+		readiness.Ready = readiness.Ready && passed
+		readiness.Checks = append(readiness.Checks, rmn.ReadinessCheck{Name: check.name, Passed: passed, Message: msg})
+	}
+
+	d.log.Info("Readiness checked", "request", request, "cluster", cluster, "ready", readiness.Ready)
+
+	d.instance.Status.Readiness = readiness
+}
+
+type readinessChecker struct {
+	name  string
+	check func(string) (bool, string)
+}
+
+// readinessChecks returns the checks of a readiness check, each of a failover cluster
+func (d *DRPCInstance) readinessChecks() []readinessChecker {
+	return []readinessChecker{
+		{ReadinessCheckFailoverTarget, d.readinessCheckFailoverTarget},
+		{ReadinessCheckPeerReachable, d.readinessCheckPeerReachable},
+		{ReadinessCheckSecretsPresent, d.readinessCheckSecretsPresent},
+		{ReadinessCheckReplicationHealthy, d.readinessCheckReplicationHealthy},
+		{ReadinessCheckCapacityAvailable, d.readinessCheckCapacityAvailable},
+		{ReadinessCheckPlacementConstraints, d.readinessCheckPlacementConstraints},
+	}
+}
+
+// readinessCheckCluster returns the failover cluster, or if it is not set the peer of the current home cluster
+func (d *DRPCInstance) readinessCheckCluster() string {
+	if d.instance.Spec.FailoverCluster != "" {
+		return d.instance.Spec.FailoverCluster
+	}
+
+	homeCluster := d.getCurrentHomeClusterName("", d.drClusters)
+
+	for i := range d.drClusters {
+		if d.drClusters[i].Name != homeCluster {
+			return d.drClusters[i].Name
+		}
+	}
+
+	return ""
+}
+
+func (d *DRPCInstance) readinessCheckFailoverTarget(cluster string) (bool, string) {
+	if cluster == d.getCurrentHomeClusterName(cluster, d.drClusters) {
+		return false, fmt.Sprintf("workload is placed on cluster %s", cluster)
+	}
+
+	drCluster := d.drCluster(cluster)
+	if drCluster == nil {
+		return false, fmt.Sprintf("cluster %s is not in DRPolicy %s", cluster, d.drPolicy.GetName())
+	}
+
+	fenced := meta.FindStatusCondition(drCluster.Status.Conditions, rmn.DRClusterConditionTypeFenced)
+	if fenced != nil && fenced.Status == metav1.ConditionTrue {
+		return false, fmt.Sprintf("cluster %s is fenced", cluster)
+	}
+
+	if !d.isValidFailoverTarget(cluster) {
+		return false, fmt.Sprintf("cluster %s is not a valid failover target", cluster)
+	}
+
+	return true, fmt.Sprintf("cluster %s is a valid failover target", cluster)
+}
+
+func (d *DRPCInstance) readinessCheckPeerReachable(cluster string) (bool, string) {
+	if drCluster := d.drCluster(cluster); drCluster != nil {
+		reachable := meta.FindStatusCondition(drCluster.Status.Conditions, rmn.DRClusterConditionTypeReachable)
+		if reachable != nil && reachable.Status == metav1.ConditionFalse {
+			return false, reachable.Message
+		}
+	}
+
+	managedCluster, err := d.readinessManagedCluster(cluster)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	available := meta.FindStatusCondition(managedCluster.Status.Conditions, ocmclv1.ManagedClusterConditionAvailable)
+	if available == nil || available.Status != metav1.ConditionTrue {
+		return false, fmt.Sprintf("ManagedCluster %s is not available", cluster)
+	}
+
+	return true, fmt.Sprintf("cluster %s is reachable", cluster)
+}
+
+// readinessCheckSecretsPresent checks that the secrets of the S3 stores of the DRPolicy clusters are present, as
+// the failover cluster restores the cluster data from the S3 store of the failed cluster. The secrets are checked on
+// the hub, and, when ramen distributes them, on the failover cluster, as reported by the compliance of the cluster
+// with the policies that deliver them.
+func (d *DRPCInstance) readinessCheckSecretsPresent(cluster string) (bool, string) {
+	distributed := d.ramenConfig.DrClusterOperator.DeploymentAutomationEnabled &&
+		d.ramenConfig.DrClusterOperator.S3SecretDistributionEnabled
+	formats := []rmnutil.TargetSecretFormat{rmnutil.SecretFormatRamen}
+
+	if !d.ramenConfig.KubeObjectProtection.Disabled && d.ramenConfig.KubeObjectProtection.VeleroNamespaceName != "" {
+		formats = append(formats, rmnutil.SecretFormatVelero)
+	}
+
+	secretsUtil := &rmnutil.SecretsUtil{
+		Client: d.reconciler.Client, APIReader: d.reconciler.APIReader, Ctx: d.ctx, Log: d.log,
+	}
+
+	for i := range d.drClusters {
+		s3ProfileName := d.drClusters[i].Spec.S3ProfileName
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		s3StoreProfile := RamenConfigS3StoreProfilePointerGet(d.ramenConfig, s3ProfileName)
+		if s3StoreProfile == nil {
+			return false, fmt.Sprintf("S3 profile %s not found", s3ProfileName)
+		}
+
+		secretName := s3StoreProfile.S3SecretRef.Name
+
+		accessID, secretAccessKey, err := GetS3Secret(d.ctx, d.reconciler.APIReader, s3StoreProfile.S3SecretRef)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		if len(accessID) == 0 || len(secretAccessKey) == 0 {
+			return false, fmt.Sprintf("secret %s of S3 profile %s is missing its keys", secretName, s3ProfileName)
+		}
+
+		if !distributed {
+			continue
+		}
+
+		for _, format := range formats {
+			delivered, err := secretsUtil.SecretDeliveredToCluster(secretName, cluster, RamenOperatorNamespace(),
+				format)
+			if err != nil {
+				return false, err.Error()
+			}
+
+			if !delivered {
+				return false, fmt.Sprintf("secret %s of S3 profile %s is not delivered to cluster %s in %s format",
+					secretName, s3ProfileName, cluster, format)
+			}
+		}
+	}
+
+	if !distributed {
+		return true, fmt.Sprintf("S3 secrets are present on the hub, their presence on cluster %s is not checked as "+
+			"ramen does not distribute them", cluster)
+	}
+
+	return true, fmt.Sprintf("S3 secrets are present on the hub and on cluster %s", cluster)
+}
+
+func (d *DRPCInstance) readinessCheckReplicationHealthy(_ string) (bool, string) {
+	for _, conditionType := range []string{rmn.ConditionPeerReady, rmn.ConditionProtected} {
+		condition := findCondition(d.instance.Status.Conditions, conditionType)
+		if condition == nil {
+			return false, fmt.Sprintf("condition %s not reported", conditionType)
+		}
+
+		if condition.Status != metav1.ConditionTrue {
+			return false, fmt.Sprintf("condition %s is %s: %s", conditionType, condition.Status, condition.Message)
+		}
+	}
+
+	return true, "workload is protected"
+}
+
+// readinessCheckCapacityAvailable checks that the failover cluster has the capacity for the workload: the cpu and
+// memory its pods request, as last reported by its primary VRG, and the storage its PVCs request, per storage class
+func (d *DRPCInstance) readinessCheckCapacityAvailable(cluster string) (bool, string) {
+	if len(d.instance.Status.WorkloadRequests) == 0 {
+		return false, "workload requests are not reported by the primary VRG"
+	}
+
+	shortfall, err := d.failoverCapacityShortfall(cluster)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	if shortfall != "" {
+		return false, shortfall
+	}
+
+	return true, fmt.Sprintf("cluster %s has the capacity for the workload", cluster)
+}
+
+func (d *DRPCInstance) readinessCheckPlacementConstraints(cluster string) (bool, string) {
+	if err := d.placementConstraintsSatisfied(cluster); err != nil {
+		return false, err.Error()
+	}
+
+	return true, fmt.Sprintf("cluster %s satisfies the placement constraints", cluster)
+}
+
+func (d *DRPCInstance) readinessManagedCluster(cluster string) (*ocmclv1.ManagedCluster, error) {
+	managedCluster := &ocmclv1.ManagedCluster{}
+	if err := d.reconciler.Get(d.ctx, types.NamespacedName{Name: cluster}, managedCluster); err != nil {
+		return nil, fmt.Errorf("failed to get ManagedCluster %s (%w)", cluster, err)
+	}
+
+	return managedCluster, nil
+}
@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the DR readiness checks of a DRPC
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_Readiness", func() {
+	var (
+		d *DRPCInstance
+		c client.Client
+	)
+
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status, Message: "detail"}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(ocmclv1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&ocmclv1.ManagedCluster{}).Build()
+		d = &DRPCInstance{
+			reconciler: &DRPlacementControlReconciler{Client: c, APIReader: c},
+			ctx:        context.TODO(),
+			log:        ctrl.Log.WithName("drpc-readiness-test"),
+			instance: &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+				Status: rmn.DRPlacementControlStatus{
+					PreferredDecision: rmn.PlacementDecision{ClusterName: "east"},
+				},
+			},
+			ramenConfig: &rmn.RamenConfig{},
+			drPolicy:    &rmn.DRPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}},
+			drClusters: []rmn.DRCluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "east"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "west"}},
+			},
+		}
+	})
+
+	Describe("readinessCheck", func() {
+		It("checks once per request, and forgets the check once no longer requested", func() {
+			d.drClusters = nil
+			d.instance.Status.PreferredDecision.ClusterName = ""
+
+			d.readinessCheck()
+			Expect(d.instance.Status.Readiness).To(BeNil())
+
+			d.instance.Annotations = map[string]string{DRPCReadinessCheckAnnotation: "1"}
+			d.readinessCheck()
+
+			readiness := d.instance.Status.Readiness
+			Expect(readiness).ToNot(BeNil())
+			Expect(readiness.Request).To(Equal("1"))
+			Expect(readiness.Ready).To(BeFalse())
+			Expect(readiness.Checks).To(HaveLen(len(d.readinessChecks())))
+
+			for _, check := range readiness.Checks {
+				Expect(check.Passed).To(BeFalse())
+				Expect(check.Message).To(Equal("no failover target"))
+			}
+
+			d.readinessCheck()
+			Expect(d.instance.Status.Readiness).To(BeIdenticalTo(readiness))
+
+			d.instance.Annotations[DRPCReadinessCheckAnnotation] = "2"
+			d.readinessCheck()
+			Expect(d.instance.Status.Readiness).ToNot(BeIdenticalTo(readiness))
+			Expect(d.instance.Status.Readiness.Request).To(Equal("2"))
+
+			delete(d.instance.Annotations, DRPCReadinessCheckAnnotation)
+			d.readinessCheck()
+			Expect(d.instance.Status.Readiness).To(BeNil())
+		})
+	})
+
+	Describe("readinessCheckCluster", func() {
+		It("is the failover cluster, or else the peer of the home cluster", func() {
+			Expect(d.readinessCheckCluster()).To(Equal("west"))
+
+			d.instance.Spec.FailoverCluster = "east"
+			Expect(d.readinessCheckCluster()).To(Equal("east"))
+		})
+	})
+
+	Describe("readinessCheckFailoverTarget", func() {
+		It("is not the home cluster, a cluster of another DRPolicy, nor a fenced cluster", func() {
+			passed, msg := d.readinessCheckFailoverTarget("east")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("workload is placed on cluster east"))
+
+			passed, msg = d.readinessCheckFailoverTarget("north")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("cluster north is not in DRPolicy policy"))
+
+			d.drClusters[1].Status.Conditions = []metav1.Condition{
+				condition(rmn.DRClusterConditionTypeFenced, metav1.ConditionTrue),
+			}
+			passed, msg = d.readinessCheckFailoverTarget("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("cluster west is fenced"))
+		})
+	})
+
+	Describe("readinessCheckPeerReachable", func() {
+		It("is reachable once its DRCluster is not unreachable, and its ManagedCluster available", func() {
+			passed, msg := d.readinessCheckPeerReachable("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(ContainSubstring("failed to get ManagedCluster west"))
+
+			managedCluster := &ocmclv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "west"}}
+			Expect(c.Create(context.TODO(), managedCluster)).To(Succeed())
+
+			passed, msg = d.readinessCheckPeerReachable("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("ManagedCluster west is not available"))
+
+			managedCluster.Status.Conditions = []metav1.Condition{
+				condition(ocmclv1.ManagedClusterConditionAvailable, metav1.ConditionTrue),
+			}
+			Expect(c.Status().Update(context.TODO(), managedCluster)).To(Succeed())
+
+			passed, _ = d.readinessCheckPeerReachable("west")
+			Expect(passed).To(BeTrue())
+
+			d.drClusters[1].Status.Conditions = []metav1.Condition{
+				condition(rmn.DRClusterConditionTypeReachable, metav1.ConditionFalse),
+			}
+			passed, msg = d.readinessCheckPeerReachable("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("detail"))
+		})
+	})
+
+	Describe("readinessCheckSecretsPresent", func() {
+		It("checks the S3 secrets of the DRPolicy clusters on the hub", func() {
+			d.drClusters[0].Spec.S3ProfileName = "s3-east"
+			d.drClusters[1].Spec.S3ProfileName = NoS3StoreAvailable
+
+			passed, msg := d.readinessCheckSecretsPresent("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("S3 profile s3-east not found"))
+
+			d.ramenConfig.S3StoreProfiles = []rmn.S3StoreProfile{{
+				S3ProfileName: "s3-east",
+				S3SecretRef:   corev1.SecretReference{Namespace: "ramen-system", Name: "s3-secret"},
+			}}
+			passed, _ = d.readinessCheckSecretsPresent("west")
+			Expect(passed).To(BeFalse())
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ramen-system", Name: "s3-secret"},
+				Data:       map[string][]byte{"AWS_ACCESS_KEY_ID": []byte("id")},
+			}
+			Expect(c.Create(context.TODO(), secret)).To(Succeed())
+
+			passed, msg = d.readinessCheckSecretsPresent("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("secret s3-secret of S3 profile s3-east is missing its keys"))
+
+			secret.Data["AWS_SECRET_ACCESS_KEY"] = []byte("key")
+			Expect(c.Update(context.TODO(), secret)).To(Succeed())
+
+			passed, msg = d.readinessCheckSecretsPresent("west")
+			Expect(passed).To(BeTrue())
+			Expect(msg).To(ContainSubstring("ramen does not distribute them"))
+		})
+	})
+
+	DescribeTable("readinessCheckReplicationHealthy",
+		func(conditions []metav1.Condition, healthy bool, msg string) {
+			d.instance.Status.Conditions = conditions
+
+			passed, actualMsg := d.readinessCheckReplicationHealthy("west")
+			Expect(passed).To(Equal(healthy))
+			Expect(actualMsg).To(Equal(msg))
+		},
+		Entry("no conditions", nil, false, "condition PeerReady not reported"),
+		Entry("peer not ready", []metav1.Condition{
+			condition(rmn.ConditionPeerReady, metav1.ConditionFalse),
+			condition(rmn.ConditionProtected, metav1.ConditionTrue),
+		}, false, "condition PeerReady is False: detail"),
+		Entry("not protected", []metav1.Condition{
+			condition(rmn.ConditionPeerReady, metav1.ConditionTrue),
+			condition(rmn.ConditionProtected, metav1.ConditionUnknown),
+		}, false, "condition Protected is Unknown: detail"),
+		Entry("protected", []metav1.Condition{
+			condition(rmn.ConditionPeerReady, metav1.ConditionTrue),
+			condition(rmn.ConditionProtected, metav1.ConditionTrue),
+		}, true, "workload is protected"),
+	)
+
+	Describe("readinessCheckCapacityAvailable", func() {
+		It("is not available until the primary VRG reports the workload requests", func() {
+			passed, msg := d.readinessCheckCapacityAvailable("west")
+			Expect(passed).To(BeFalse())
+			Expect(msg).To(Equal("workload requests are not reported by the primary VRG"))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// DRPCRestorePreviewAnnotation requests a preview of the recovery of the kube objects of a DRPC on its failover
+// cluster when set to a value that differs from the request of the most recent preview in the DRPC status, such as a
+// timestamp. The request is passed on to the secondary VRG of the failover cluster, whose preview is reported.
+const DRPCRestorePreviewAnnotation = "drplacementcontrol.ramendr.openshift.io/restore-preview"
+
+// restorePreview requests a restore preview of the VRG of the failover cluster when requested, and records it in the
+// DRPC status once the VRG reports it
+func (d *DRPCInstance) restorePreview() {
+	request, ok := d.instance.GetAnnotations()[DRPCRestorePreviewAnnotation]
+	if !ok {
+		d.instance.Status.RestorePreview = nil
+
+		return
+	}
+
+	if d.instance.Status.RestorePreview != nil && d.instance.Status.RestorePreview.Request == request {
+		return
+	}
+
+	cluster := d.readinessCheckCluster()
+	if cluster == "" {
+		d.instance.Status.RestorePreview = &rmn.RestorePreview{
+			KubeObjectsRestorePreview: rmn.KubeObjectsRestorePreview{
+				Request: request,
+				Errors:  []string{"no failover target"},
+			},
+		}
+
+		return
+	}
+
+	if vrg := d.vrgs[cluster]; vrg != nil {
+		if preview := vrg.Status.KubeObjectProtection.RestorePreview; preview != nil && preview.Request == request {
+			d.log.Info("Restore previewed", "request", request, "cluster", cluster,
+				"conflicts", preview.ConflictCount, "errors", len(preview.Errors))
+
+			d.instance.Status.RestorePreview = &rmn.RestorePreview{
+				Cluster:                   cluster,
+				KubeObjectsRestorePreview: *preview.DeepCopy(),
+			}
+
+			return
+		}
+	}
+
+	if err := d.restorePreviewRequest(cluster, request); err != nil {
+		d.log.Info("Restore preview request failed", "request", request, "cluster", cluster, "error", err)
+	}
+}
+
+// restorePreviewRequest annotates the VRG of a cluster, through its ManifestWork, with a restore preview request,
+// unless it is already annotated with it
+func (d *DRPCInstance) restorePreviewRequest(cluster, request string) error {
+	vrg, err := d.getVRGFromManifestWork(cluster)
+	if err != nil {
+		return fmt.Errorf("failed to get VRG ManifestWork of cluster %s: %w", cluster, err)
+	}
+
+	if vrg.Spec.ReplicationState != rmn.Secondary {
+		return fmt.Errorf("VRG of cluster %s is not secondary", cluster)
+	}
+
+	annotations := vrg.GetAnnotations()
+	if annotations[VRGRestorePreviewAnnotation] == request {
+		return nil
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[VRGRestorePreviewAnnotation] = request
+	vrg.SetAnnotations(annotations)
+
+	if err := d.updateManifestWork(cluster, vrg); err != nil {
+		return fmt.Errorf("failed to update VRG ManifestWork of cluster %s: %w", cluster, err)
+	}
+
+	d.log.Info("Restore preview requested", "request", request, "cluster", cluster)
+
+	return nil
+}
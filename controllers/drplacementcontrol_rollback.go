@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// rolledBackConditionPrune removes a RolledBack condition reported at a previous generation of the DRPC, once the
+// DRPC is changed since its action was rolled back
+func (d *DRPCInstance) rolledBackConditionPrune() {
+	condition := findCondition(d.instance.Status.Conditions, rmn.ConditionRolledBack)
+	if condition != nil && condition.ObservedGeneration != d.instance.Generation {
+		meta.RemoveStatusCondition(&d.instance.Status.Conditions, rmn.ConditionRolledBack)
+	}
+}
+
+// relocateRollBackIfTimedOut rolls the DRPC's relocation back to the cluster it is from, and returns true, if it
+// exceeded its timeout before its point of no return: the VRG of the cluster it is from being moved to secondary.
+// Until then, the relocation has only quiesced the workload for its final sync, by clearing its placement decision
+// and setting the final sync flags of the VRG, which rolling back reverts, along with the action of the DRPC.
+func (d *DRPCInstance) relocateRollBackIfTimedOut(srcCluster, dstCluster string) (bool, error) {
+	timeouts := d.instance.Spec.ActionTimeouts
+	if timeouts == nil || timeouts.Relocate == nil || d.instance.Status.ActionStartTime == nil ||
+		srcCluster == "" || srcCluster == dstCluster {
+		return false, nil
+	}
+
+	elapsed := time.Since(d.instance.Status.ActionStartTime.Time)
+	if elapsed <= timeouts.Relocate.Duration {
+		return false, nil
+	}
+
+	vrg, err := d.getVRGFromManifestWork(srcCluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to get VRG of cluster %s to roll back relocation (%w)", srcCluster, err)
+	}
+
+	if vrg.Spec.ReplicationState != rmn.Primary {
+		d.log.Info("Relocation timed out past its point of no return, not rolled back", "elapsed", elapsed)
+
+		return false, nil
+	}
+
+	step := d.getProgression()
+	if step == "" {
+		step = rmn.ProgressionStatus(d.getLastDRState())
+	}
+
+	if vrg.Spec.PrepareForFinalSync || vrg.Spec.RunFinalSync {
+		vrg.Spec.PrepareForFinalSync = false
+		vrg.Spec.RunFinalSync = false
+
+		if err := d.updateManifestWork(srcCluster, vrg); err != nil {
+			return false, fmt.Errorf("failed to clear final sync of VRG of cluster %s to roll back relocation (%w)",
+				srcCluster, err)
+		}
+	}
+
+	if err := d.updateUserPlacementRule(srcCluster, srcCluster); err != nil {
+		return false, fmt.Errorf("failed to restore placement to cluster %s to roll back relocation (%w)",
+			srcCluster, err)
+	}
+
+	if err := d.relocateRollBackSpec(srcCluster); err != nil {
+		return false, err
+	}
+
+	d.relocateRolledBackReport(srcCluster, dstCluster, step, elapsed)
+
+	return true, nil
+}
+
+// relocateRollBackSpec reverts the action of the DRPC to the one its workload is in on the cluster the relocation is
+// from, for the DRPC to resume it: its failover, if the cluster is its failover cluster, or else its deployment. The
+// status, which the patch returns as last updated, is kept.
+func (d *DRPCInstance) relocateRollBackSpec(srcCluster string) error {
+	status := d.instance.Status.DeepCopy()
+	patch := client.MergeFrom(d.instance.DeepCopy())
+
+	if srcCluster == d.instance.Spec.FailoverCluster {
+		d.instance.Spec.Action = rmn.ActionFailover
+	} else {
+		d.instance.Spec.Action = ""
+		d.instance.Spec.PreferredCluster = srcCluster
+	}
+
+	if err := d.reconciler.Patch(d.ctx, d.instance, patch); err != nil {
+		return fmt.Errorf("failed to revert action of DRPC to cluster %s to roll back relocation (%w)", srcCluster,
+			err)
+	}
+
+	d.instance.Status = *status
+
+	return nil
+}
+
+func (d *DRPCInstance) relocateRolledBackReport(srcCluster, dstCluster string, step rmn.ProgressionStatus,
+	elapsed time.Duration,
+) {
+	msg := fmt.Sprintf("Relocation to cluster %s timed out after %v at step %s, rolled back to cluster %s",
+		dstCluster, elapsed.Round(time.Second), step, srcCluster)
+
+	d.log.Info(msg)
+
+	if srcCluster == d.instance.Spec.FailoverCluster {
+		d.setDRState(rmn.FailedOver)
+	} else {
+		d.setDRState(rmn.Deployed)
+	}
+
+	d.setProgression(rmn.ProgressionRolledBack)
+
+	addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionRolledBack, d.instance.Generation,
+		metav1.ConditionTrue, rmn.ReasonTimedOut, msg)
+	addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionAvailable, d.instance.Generation,
+		metav1.ConditionTrue, string(d.instance.Status.Phase), msg)
+
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+		rmnutil.EventReasonDRActionRolledBack, msg)
+}
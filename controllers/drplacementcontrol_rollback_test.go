@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the roll back of relocations that exceed their timeout
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPC_RelocateRollback", func() {
+	const (
+		namespace  = "app"
+		name       = "drpc"
+		srcCluster = "east"
+		dstCluster = "west"
+	)
+
+	var (
+		c client.Client
+		d *DRPCInstance
+	)
+
+	vrgManifestWorkCreate := func(replicationState rmn.ReplicationState) {
+		vrg := rmn.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: rmn.VolumeReplicationGroupSpec{
+				ReplicationState:    replicationState,
+				PrepareForFinalSync: true,
+				RunFinalSync:        true,
+			},
+		}
+		Expect(d.mwu.CreateOrUpdateVRGManifestWork(name, namespace, srcCluster, vrg, nil, nil)).To(Succeed())
+	}
+	vrgFromManifestWork := func() *rmn.VolumeReplicationGroup {
+		vrg, err := d.getVRGFromManifestWork(srcCluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		return vrg
+	}
+	drpcGet := func() *rmn.DRPlacementControl {
+		drpc := &rmn.DRPlacementControl{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, drpc)).To(Succeed())
+
+		return drpc
+	}
+	placementRuleDecisions := func() []plrv1.PlacementDecision {
+		placementRule := &plrv1.PlacementRule{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "placement"}, placementRule)).
+			To(Succeed())
+
+		return placementRule.Status.Decisions
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(rmn.AddToScheme(scheme)).To(Succeed())
+		Expect(ocmworkv1.AddToScheme(scheme)).To(Succeed())
+		Expect(plrv1.AddToScheme(scheme)).To(Succeed())
+
+		actionStartTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		drpc := &rmn.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Generation:  2,
+				Annotations: map[string]string{LastAppDeploymentCluster: srcCluster},
+			},
+			Spec: rmn.DRPlacementControlSpec{
+				Action:           rmn.ActionRelocate,
+				PreferredCluster: dstCluster,
+				ActionTimeouts:   &rmn.DRActionTimeouts{Relocate: &metav1.Duration{Duration: 30 * time.Minute}},
+			},
+			Status: rmn.DRPlacementControlStatus{
+				Phase:           rmn.Relocating,
+				Progression:     rmn.ProgressionRunningFinalSync,
+				ActionStartTime: &actionStartTime,
+			},
+		}
+		placementRule := &plrv1.PlacementRule{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "placement"}}
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(drpc, placementRule).
+			WithStatusSubresource(drpc, placementRule).Build()
+
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(drpc), drpc)).To(Succeed())
+		drpc.Status.Phase = rmn.Relocating
+		drpc.Status.Progression = rmn.ProgressionRunningFinalSync
+		drpc.Status.ActionStartTime = &actionStartTime
+
+		d = &DRPCInstance{
+			reconciler: &DRPlacementControlReconciler{
+				Client:        c,
+				eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+			},
+			ctx:           context.TODO(),
+			log:           ctrl.Log.WithName("drpc-relocate-rollback-test"),
+			instance:      drpc,
+			userPlacement: placementRule,
+			ramenConfig:   &rmn.RamenConfig{},
+			mwu: rmnutil.MWUtil{
+				Client:          c,
+				APIReader:       c,
+				Ctx:             context.TODO(),
+				Log:             ctrl.Log.WithName("drpc-relocate-rollback-test"),
+				InstName:        name,
+				TargetNamespace: namespace,
+			},
+		}
+	})
+
+	Describe("relocateRollBackIfTimedOut", func() {
+		It("does not roll back a relocation within its timeout", func() {
+			vrgManifestWorkCreate(rmn.Primary)
+			d.instance.Spec.ActionTimeouts.Relocate.Duration = 2 * time.Hour
+
+			Expect(d.relocateRollBackIfTimedOut(srcCluster, dstCluster)).To(BeFalse())
+			Expect(vrgFromManifestWork().Spec.RunFinalSync).To(BeTrue())
+			Expect(drpcGet().Spec.Action).To(Equal(rmn.ActionRelocate))
+		})
+
+		It("rolls back a deployed workload's relocation timed out before its point of no return", func() {
+			vrgManifestWorkCreate(rmn.Primary)
+
+			Expect(d.relocateRollBackIfTimedOut(srcCluster, dstCluster)).To(BeTrue())
+
+			vrg := vrgFromManifestWork()
+			Expect(vrg.Spec.PrepareForFinalSync).To(BeFalse())
+			Expect(vrg.Spec.RunFinalSync).To(BeFalse())
+			Expect(placementRuleDecisions()).To(ConsistOf(HaveField("ClusterName", srcCluster)))
+
+			drpc := drpcGet()
+			Expect(drpc.Spec.Action).To(BeEmpty())
+			Expect(drpc.Spec.PreferredCluster).To(Equal(srcCluster))
+
+			Expect(d.instance.Status.Phase).To(Equal(rmn.Deployed))
+			Expect(d.instance.Status.Progression).To(Equal(rmn.ProgressionRolledBack))
+			Expect(d.instance.Status.ActionStartTime).ToNot(BeNil())
+
+			condition := findCondition(d.instance.Status.Conditions, rmn.ConditionRolledBack)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(rmn.ReasonTimedOut))
+			Expect(condition.ObservedGeneration).To(Equal(drpc.Generation))
+			Expect(condition.Message).To(ContainSubstring(string(rmn.ProgressionRunningFinalSync)))
+		})
+
+		It("reverts a failed over workload's relocation timed out before its point of no return to its failover", func() {
+			vrgManifestWorkCreate(rmn.Primary)
+			d.instance.Spec.FailoverCluster = srcCluster
+			Expect(c.Update(context.TODO(), d.instance)).To(Succeed())
+			d.instance.Status.Progression = rmn.ProgressionRunningFinalSync
+			d.instance.Status.ActionStartTime = drpcGet().Status.ActionStartTime
+
+			Expect(d.relocateRollBackIfTimedOut(srcCluster, dstCluster)).To(BeTrue())
+
+			drpc := drpcGet()
+			Expect(drpc.Spec.Action).To(Equal(rmn.ActionFailover))
+			Expect(drpc.Spec.FailoverCluster).To(Equal(srcCluster))
+			Expect(drpc.Spec.PreferredCluster).To(Equal(dstCluster))
+			Expect(d.instance.Status.Phase).To(Equal(rmn.FailedOver))
+		})
+
+		It("does not roll back a relocation timed out past its point of no return", func() {
+			vrgManifestWorkCreate(rmn.Secondary)
+
+			Expect(d.relocateRollBackIfTimedOut(srcCluster, dstCluster)).To(BeFalse())
+			Expect(vrgFromManifestWork().Spec.RunFinalSync).To(BeTrue())
+			Expect(placementRuleDecisions()).To(BeEmpty())
+			Expect(drpcGet().Spec.Action).To(Equal(rmn.ActionRelocate))
+			Expect(findCondition(d.instance.Status.Conditions, rmn.ConditionRolledBack)).To(BeNil())
+		})
+	})
+
+	Describe("rolledBackConditionPrune", func() {
+		It("removes the RolledBack condition once the DRPC is changed", func() {
+			addOrUpdateCondition(&d.instance.Status.Conditions, rmn.ConditionRolledBack, d.instance.Generation,
+				metav1.ConditionTrue, rmn.ReasonTimedOut, "rolled back")
+
+			d.rolledBackConditionPrune()
+			Expect(findCondition(d.instance.Status.Conditions, rmn.ConditionRolledBack)).ToNot(BeNil())
+
+			d.instance.Generation++
+			d.rolledBackConditionPrune()
+			Expect(findCondition(d.instance.Status.Conditions, rmn.ConditionRolledBack)).To(BeNil())
+		})
+	})
+})
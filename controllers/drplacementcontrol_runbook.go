@@ -0,0 +1,321 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/yaml"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// runbookKeyMarkdown and runbookKeyYAML are the keys of the runbook of a DRPC, as a markdown document and as
+	// YAML, in its VRG's path of the s3 stores
+	runbookKeyMarkdown = "runbook.md"
+	runbookKeyYAML     = "runbook.yaml"
+
+	// runbookExportRetryInterval is the least time before an export of a runbook that failed is retried
+	runbookExportRetryInterval = 5 * time.Minute
+
+	runbookExportEventsSize = 64
+
+	// workAgentNamespaceName and workAgentDeploymentName are the namespace and deployment of the OCM work agent of
+	// a managed cluster, which reapplies the manifests of its ManifestWorks while the hub is not reachable
+	workAgentNamespaceName  = "open-cluster-management-agent"
+	workAgentDeploymentName = "klusterlet-work-agent"
+)
+
+// Runbook describes the steps to fail over the workload of a DRPC by hand, on the managed clusters, when the hub
+// or the automation is not available
+type Runbook struct {
+	// DRPlacementControl is the namespaced name of the DRPC
+	DRPlacementControl string `json:"drPlacementControl"`
+
+	// DRPolicy is the name of the DRPolicy of the DRPC
+	DRPolicy string `json:"drPolicy"`
+
+	// VolumeReplicationGroup is the namespaced name of the VRG of the DRPC on the managed clusters
+	VolumeReplicationGroup string `json:"volumeReplicationGroup"`
+
+	// HomeCluster is the cluster the workload is placed on
+	HomeCluster string `json:"homeCluster"`
+
+	// FailoverCluster is the cluster to fail the workload over to
+	FailoverCluster string `json:"failoverCluster"`
+
+	// ProtectedNamespaces are the namespaces of the kube objects protected, for a VRG in the admin namespace
+	ProtectedNamespaces []string `json:"protectedNamespaces,omitempty"`
+
+	// PersistentVolumeClaims are the namespaced names of the protected PVCs
+	PersistentVolumeClaims []string `json:"persistentVolumeClaims,omitempty"`
+
+	// S3ProfileNames are the profiles of the s3 stores the protected state is stored in
+	S3ProfileNames []string `json:"s3ProfileNames,omitempty"`
+
+	// Markdown is the runbook as a markdown document, with the kubectl commands of each step
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// runbookExportInFlight is an export of the runbook of a DRPC running in the background
+type runbookExportInFlight struct {
+	mutex  sync.Mutex
+	done   bool
+	status *rmn.RunbookStatus
+}
+
+// runbookExport exports the runbook of the DRPC to the s3 stores of its DRClusters when its content changes, such as
+// when a PVC is protected or the workload is moved. The export runs in the background, off the reconcile, which it
+// requeues once it completes to record it in the DRPC status. A failed export is retried once the retry interval
+// elapsed.
+func (d *DRPCInstance) runbookExport() {
+	key := types.NamespacedName{Namespace: d.instance.GetNamespace(), Name: d.instance.GetName()}
+	if d.runbookExportRunning(key) {
+		return
+	}
+
+	cluster := d.readinessCheckCluster()
+	homeCluster := d.getCurrentHomeClusterName(cluster, d.drClusters)
+
+	if cluster == "" || homeCluster == "" {
+		return
+	}
+
+	s3ProfileNames := AvailableS3Profiles(d.drClusters)
+	runbook := d.runbook(cluster, homeCluster, s3ProfileNames)
+
+	hash, err := runbookHash(runbook)
+	if err != nil {
+		d.log.Info("Runbook hash failed", "error", err)
+
+		return
+	}
+
+	if !runbookExportDue(d.instance.Status.Runbook, hash) {
+		return
+	}
+
+	export := &runbookExportInFlight{}
+	d.reconciler.runbookExports.Store(key, export)
+
+	d.log.Info("Runbook export started", "hash", hash, "s3Profiles", s3ProfileNames)
+
+	go export.run(d.reconciler, key, runbook, hash, s3PathNamePrefix(d.vrgNamespace, d.instance.GetName()), d.log)
+}
+
+// runbookExportRunning records the export of the DRPC's runbook that completed in the background in the DRPC status,
+// and returns whether one is still running
+func (d *DRPCInstance) runbookExportRunning(key types.NamespacedName) bool {
+	value, ok := d.reconciler.runbookExports.Load(key)
+	if !ok {
+		return false
+	}
+
+	export, _ := value.(*runbookExportInFlight)
+
+	export.mutex.Lock()
+	defer export.mutex.Unlock()
+
+	if !export.done {
+		return true
+	}
+
+	d.reconciler.runbookExports.Delete(key)
+	d.instance.Status.Runbook = export.status
+
+	d.log.Info("Runbook exported", "hash", export.status.Hash, "s3Profiles", export.status.S3ProfileNames,
+		"errors", len(export.status.Errors))
+
+	return false
+}
+
+// run uploads the runbook, as a markdown document and as YAML, to the s3 stores of its profiles, and requeues the
+// DRPC
+func (e *runbookExportInFlight) run(r *DRPlacementControlReconciler, key types.NamespacedName, runbook *Runbook,
+	hash, pathName string, log logr.Logger,
+) {
+	now := metav1.Now()
+	status := &rmn.RunbookStatus{Hash: hash, ExportTime: &now}
+
+	// The markdown document is exported on its own
+	withoutMarkdown := *runbook
+	withoutMarkdown.Markdown = ""
+
+	runbookYAML, err := yaml.Marshal(withoutMarkdown)
+	if err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("failed to encode runbook: %v", err))
+	}
+
+	for _, s3ProfileName := range runbook.S3ProfileNames {
+		if err != nil {
+			break
+		}
+
+		if err := runbookUpload(r, s3ProfileName, pathName, []byte(runbook.Markdown), runbookYAML, log); err != nil {
+			status.Errors = append(status.Errors, fmt.Sprintf("s3 profile %s: %v", s3ProfileName, err))
+
+			continue
+		}
+
+		status.S3ProfileNames = append(status.S3ProfileNames, s3ProfileName)
+	}
+
+	e.mutex.Lock()
+	e.status, e.done = status, true
+	e.mutex.Unlock()
+
+	select {
+	case r.runbookExportEvents <- event.GenericEvent{Object: &rmn.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+	}}:
+	default:
+		// The export is recorded on the DRPC's next reconcile
+	}
+}
+
+func runbookUpload(r *DRPlacementControlReconciler, s3ProfileName, pathName string, markdown, runbookYAML []byte,
+	log logr.Logger,
+) error {
+	objectStorer, _, err := r.ObjStoreGetter.ObjectStore(context.Background(), r.APIReader, s3ProfileName,
+		"runbook", log)
+	if err != nil {
+		return fmt.Errorf("failed to get object store: %w", err)
+	}
+
+	if err := objectStorer.UploadObjectRaw(pathName+runbookKeyMarkdown, markdown, "text/markdown"); err != nil {
+		return err
+	}
+
+	return objectStorer.UploadObjectRaw(pathName+runbookKeyYAML, runbookYAML, "application/yaml")
+}
+
+// runbookExportDue returns whether the runbook of the hash is to be exported, as it changed since the export last
+// recorded, or that export failed and the retry interval elapsed
+func runbookExportDue(status *rmn.RunbookStatus, hash string) bool {
+	if status == nil || status.Hash != hash {
+		return true
+	}
+
+	return len(status.Errors) > 0 &&
+		(status.ExportTime == nil || time.Since(status.ExportTime.Time) >= runbookExportRetryInterval)
+}
+
+func runbookHash(runbook *Runbook) (string, error) {
+	runbookJSON, err := json.Marshal(runbook)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(runbookJSON)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// runbook returns the runbook to fail the workload of the DRPC over from its home cluster to a cluster
+func (d *DRPCInstance) runbook(cluster, homeCluster string, s3ProfileNames []string) *Runbook {
+	runbook := &Runbook{
+		DRPlacementControl:     d.instance.GetNamespace() + "/" + d.instance.GetName(),
+		DRPolicy:               d.drPolicy.GetName(),
+		VolumeReplicationGroup: d.vrgNamespace + "/" + d.instance.GetName(),
+		HomeCluster:            homeCluster,
+		FailoverCluster:        cluster,
+		S3ProfileNames:         s3ProfileNames,
+	}
+
+	if d.instance.Spec.ProtectedNamespaces != nil {
+		runbook.ProtectedNamespaces = *d.instance.Spec.ProtectedNamespaces
+	}
+
+	if vrg := d.failoverPlanVRG(cluster, homeCluster); vrg != nil {
+		for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+			runbook.PersistentVolumeClaims = append(runbook.PersistentVolumeClaims,
+				protectedPVC.Namespace+"/"+protectedPVC.Name)
+		}
+	}
+
+	runbook.Markdown = d.runbookMarkdown(runbook)
+
+	return runbook
+}
+
+// runbookMarkdown returns the steps of the runbook as a markdown document
+func (d *DRPCInstance) runbookMarkdown(runbook *Runbook) string {
+	vrgName := d.instance.GetName()
+	cacheName := vrgLocalCacheKey(types.NamespacedName{Namespace: d.vrgNamespace, Name: vrgName}).Name
+	md := &strings.Builder{}
+
+	fmt.Fprintf(md, "# Failover runbook of DRPlacementControl %s\n\n", runbook.DRPlacementControl)
+	fmt.Fprintf(md, "Fails the workload over from cluster `%s` to cluster `%s` of DRPolicy `%s` without the hub.\n\n",
+		runbook.HomeCluster, runbook.FailoverCluster, runbook.DRPolicy)
+
+	fmt.Fprintf(md, "## 1. Stop the workload on cluster %s\n\n", runbook.HomeCluster)
+
+	if d.drType == DRTypeSync {
+		fmt.Fprintf(md, "Fence cluster `%s` in its storage, if it is still reachable, before the volumes are "+
+			"promoted on cluster `%s`.\n\n", runbook.HomeCluster, runbook.FailoverCluster)
+	}
+
+	fmt.Fprintf(md, "If cluster `%s` is reachable, stop its work agent, for it not to reapply the VRG as Primary, "+
+		"and demote its VRG:\n\n", runbook.HomeCluster)
+	fmt.Fprintf(md, "```sh\nkubectl --context %s -n %s scale deployment %s --replicas 0\n", runbook.HomeCluster,
+		workAgentNamespaceName, workAgentDeploymentName)
+	fmt.Fprintf(md, "kubectl --context %s -n %s patch volumereplicationgroup %s --type merge "+
+		"-p '{\"spec\":{\"replicationState\":\"secondary\"}}'\n```\n\n", runbook.HomeCluster, d.vrgNamespace, vrgName)
+
+	fmt.Fprintf(md, "## 2. Promote the VRG on cluster %s\n\n", runbook.FailoverCluster)
+	fmt.Fprintf(md, "Promote the VRG from its local cache, which creates it if it is not found:\n\n")
+	fmt.Fprintf(md, "```sh\nkubectl --context %s -n %s annotate configmap %s %s=true\n```\n\n",
+		runbook.FailoverCluster, drClusterOperatorNamespaceNameOrDefault(d.ramenConfig), cacheName,
+		VRGLocalCachePromoteAnnotation)
+	fmt.Fprintf(md, "Or, if the VRG is found, patch it:\n\n")
+	fmt.Fprintf(md, "```sh\nkubectl --context %s -n %s patch volumereplicationgroup %s --type merge "+
+		"-p '{\"spec\":{\"replicationState\":\"primary\",\"action\":\"Failover\"}}'\n```\n\n",
+		runbook.FailoverCluster, d.vrgNamespace, vrgName)
+
+	fmt.Fprintf(md, "## 3. Wait for the workload to be recovered on cluster %s\n\n", runbook.FailoverCluster)
+	fmt.Fprintf(md, "```sh\n")
+
+	for _, conditionType := range []string{VRGConditionTypeDataReady, VRGConditionTypeClusterDataReady} {
+		fmt.Fprintf(md, "kubectl --context %s -n %s wait volumereplicationgroup %s --for condition=%s "+
+			"--timeout 30m\n", runbook.FailoverCluster, d.vrgNamespace, vrgName, conditionType)
+	}
+
+	fmt.Fprintf(md, "```\n\n")
+
+	if len(runbook.PersistentVolumeClaims) > 0 {
+		fmt.Fprintf(md, "Check that the protected PVCs are bound:\n\n```sh\n")
+
+		for _, pvc := range runbook.PersistentVolumeClaims {
+			namespace, name, _ := strings.Cut(pvc, "/")
+			fmt.Fprintf(md, "kubectl --context %s -n %s get persistentvolumeclaim %s\n", runbook.FailoverCluster,
+				namespace, name)
+		}
+
+		fmt.Fprintf(md, "```\n\n")
+	}
+
+	fmt.Fprintf(md, "## 4. Reconcile the hub\n\n")
+	fmt.Fprintf(md, "Once the hub is available, record the failover in the DRPlacementControl:\n\n")
+	fmt.Fprintf(md, "```sh\nkubectl -n %s patch drplacementcontrol %s --type merge "+
+		"-p '{\"spec\":{\"action\":\"Failover\",\"failoverCluster\":\"%s\"}}'\n```\n\n",
+		d.instance.GetNamespace(), d.instance.GetName(), runbook.FailoverCluster)
+	fmt.Fprintf(md, "Once it reports the failover completed, start the work agent of cluster `%s`, if it was "+
+		"stopped, which applies the VRG as Secondary:\n\n", runbook.HomeCluster)
+	fmt.Fprintf(md, "```sh\nkubectl --context %s -n %s scale deployment %s --replicas 1\n```\n",
+		runbook.HomeCluster, workAgentNamespaceName, workAgentDeploymentName)
+
+	return md.String()
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the export of the failover runbooks of DRPCs off their reconciles
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/yaml"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// memoryObjectStoreGetter gets the in memory object stores of its profiles, and fails for the others
+type memoryObjectStoreGetter map[string]memoryObjectStorer
+
+func (m memoryObjectStoreGetter) ObjectStore(ctx context.Context, r client.Reader, s3ProfileName string,
+	callerTag string, log logr.Logger,
+) (ObjectStorer, rmn.S3StoreProfile, error) {
+	objectStorer, ok := m[s3ProfileName]
+	if !ok {
+		return nil, rmn.S3StoreProfile{}, fmt.Errorf("s3 profile %s not found", s3ProfileName)
+	}
+
+	return objectStorer, rmn.S3StoreProfile{S3ProfileName: s3ProfileName}, nil
+}
+
+var _ = Describe("DRPC_Runbook", func() {
+	key := types.NamespacedName{Namespace: "app", Name: "drpc"}
+
+	var (
+		objectStorer memoryObjectStorer
+		r            *DRPlacementControlReconciler
+		d            *DRPCInstance
+		runbook      *Runbook
+	)
+
+	BeforeEach(func() {
+		objectStorer = memoryObjectStorer{}
+		r = &DRPlacementControlReconciler{
+			ObjStoreGetter:      memoryObjectStoreGetter{"east": objectStorer},
+			runbookExportEvents: make(chan event.GenericEvent, 1),
+		}
+		d = &DRPCInstance{
+			reconciler:   r,
+			log:          ctrl.Log.WithName("drpc-runbook-test"),
+			instance:     &rmn.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}},
+			drPolicy:     &rmn.DRPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy"}},
+			ramenConfig:  &rmn.RamenConfig{},
+			vrgNamespace: "app",
+			drType:       DRTypeAsync,
+		}
+		runbook = &Runbook{
+			DRPlacementControl:     "app/drpc",
+			DRPolicy:               "policy",
+			VolumeReplicationGroup: "app/drpc",
+			HomeCluster:            "east",
+			FailoverCluster:        "west",
+			PersistentVolumeClaims: []string{"app/db"},
+			S3ProfileNames:         []string{"east", "west"},
+		}
+		runbook.Markdown = d.runbookMarkdown(runbook)
+	})
+
+	Describe("runbookExportInFlight", func() {
+		It("uploads the runbook as markdown and YAML, and requeues the DRPC to record the export", func() {
+			export := &runbookExportInFlight{}
+			r.runbookExports.Store(key, export)
+			Expect(d.runbookExportRunning(key)).To(BeTrue())
+
+			export.run(r, key, runbook, "hash", s3PathNamePrefix("app", "drpc"), d.log)
+
+			Expect(string(objectStorer["app/drpc/"+runbookKeyMarkdown])).To(Equal(runbook.Markdown))
+
+			exported := &Runbook{}
+			Expect(yaml.Unmarshal(objectStorer["app/drpc/"+runbookKeyYAML], exported)).To(Succeed())
+			Expect(exported.Markdown).To(BeEmpty())
+			Expect(exported.PersistentVolumeClaims).To(Equal(runbook.PersistentVolumeClaims))
+
+			Expect(r.runbookExportEvents).To(Receive(HaveField("Object.GetName()", key.Name)))
+
+			Expect(d.runbookExportRunning(key)).To(BeFalse())
+			Expect(d.instance.Status.Runbook).To(And(HaveField("Hash", "hash"),
+				HaveField("S3ProfileNames", []string{"east"}), HaveField("Errors", HaveLen(1))))
+
+			_, ok := r.runbookExports.Load(key)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("runbookExportDue", func() {
+		It("exports a runbook changed, or failed to once the retry interval elapsed", func() {
+			now := metav1.Now()
+			before := metav1.NewTime(now.Add(-runbookExportRetryInterval - time.Second))
+
+			Expect(runbookExportDue(nil, "hash")).To(BeTrue())
+			Expect(runbookExportDue(&rmn.RunbookStatus{Hash: "old", ExportTime: &now}, "hash")).To(BeTrue())
+			Expect(runbookExportDue(&rmn.RunbookStatus{Hash: "hash", ExportTime: &before}, "hash")).To(BeFalse())
+			Expect(runbookExportDue(&rmn.RunbookStatus{Hash: "hash", ExportTime: &now, Errors: []string{"failed"}},
+				"hash")).To(BeFalse())
+			Expect(runbookExportDue(&rmn.RunbookStatus{Hash: "hash", ExportTime: &before, Errors: []string{"failed"}},
+				"hash")).To(BeTrue())
+		})
+	})
+
+	Describe("runbookMarkdown", func() {
+		It("stops the work agent of the home cluster before demoting its VRG, and starts it once failed over", func() {
+			scaleDown := fmt.Sprintf("kubectl --context east -n %s scale deployment %s --replicas 0",
+				workAgentNamespaceName, workAgentDeploymentName)
+			demote := "kubectl --context east -n app patch volumereplicationgroup drpc"
+			scaleUp := fmt.Sprintf("kubectl --context east -n %s scale deployment %s --replicas 1",
+				workAgentNamespaceName, workAgentDeploymentName)
+
+			Expect(runbook.Markdown).To(And(ContainSubstring(scaleDown), ContainSubstring(demote),
+				ContainSubstring(scaleUp)))
+			Expect(strings.Index(runbook.Markdown, scaleDown)).To(BeNumerically("<",
+				strings.Index(runbook.Markdown, demote)))
+			Expect(strings.Index(runbook.Markdown, scaleUp)).To(BeNumerically(">",
+				strings.Index(runbook.Markdown, "drplacementcontrol drpc")))
+		})
+	})
+})
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// DRPCStateGenerationsAnnotation requests a listing of the generations of the protected state stored for a DRPC when
+// set to a value that differs from the request of the most recent listing in the DRPC status, such as a timestamp
+const DRPCStateGenerationsAnnotation = "drplacementcontrol.ramendr.openshift.io/state-generations"
+
+// StateGenerationKindKubeObjectsCapture is the kind of the state generation of a capture of kube objects
+const StateGenerationKindKubeObjectsCapture = "KubeObjectsCapture"
+
+// stateGenerationsList lists the generations of the protected state stored in the s3 stores of the DRClusters when
+// requested, and records the listing in the DRPC status
+func (d *DRPCInstance) stateGenerationsList() {
+	request, ok := d.instance.GetAnnotations()[DRPCStateGenerationsAnnotation]
+	if !ok {
+		d.instance.Status.StateGenerations = nil
+
+		return
+	}
+
+	if d.instance.Status.StateGenerations != nil && d.instance.Status.StateGenerations.Request == request {
+		return
+	}
+
+	now := metav1.Now()
+	listing := &rmn.StateGenerationsListing{Request: request, ListTime: &now}
+
+	for _, s3ProfileName := range AvailableS3Profiles(d.drClusters) {
+		generations, err := d.stateGenerations(s3ProfileName)
+		if err != nil {
+			listing.Errors = append(listing.Errors, fmt.Sprintf("s3 profile %s: %v", s3ProfileName, err))
+
+			continue
+		}
+
+		listing.Generations = append(listing.Generations, generations...)
+	}
+
+	d.log.Info("State generations listed", "request", request, "generations", len(listing.Generations),
+		"errors", len(listing.Errors))
+
+	d.instance.Status.StateGenerations = listing
+}
+
+// stateGenerations returns the generations of the protected state stored in the s3 store of a profile
+func (d *DRPCInstance) stateGenerations(s3ProfileName string) ([]rmn.StateGeneration, error) {
+	objectStorer, _, err := d.reconciler.ObjStoreGetter.ObjectStore(
+		d.ctx, d.reconciler.APIReader, s3ProfileName, "state generations", d.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object store: %w", err)
+	}
+
+	pathName := s3PathNamePrefix(d.vrgNamespace, d.instance.GetName())
+
+	objects, err := objectStorer.ListObjects(pathName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects with prefix %s: %w", pathName, err)
+	}
+
+	return stateGenerations(s3ProfileName, pathName, objects), nil
+}
+
+// stateGenerations groups the objects stored in a VRG's path into generations: one per kube objects capture, and one
+// per kind of the other objects, sorted by kind and name
+func stateGenerations(s3ProfileName, pathName string, objects []ObjectInfo) []rmn.StateGeneration {
+	generations := []rmn.StateGeneration{}
+	indexes := map[string]int{}
+
+	for _, object := range objects {
+		kind, name := stateGenerationKindAndName(strings.TrimPrefix(object.Key, pathName))
+
+		index, ok := indexes[kind+"/"+name]
+		if !ok {
+			index = len(generations)
+			indexes[kind+"/"+name] = index
+			generations = append(generations, rmn.StateGeneration{S3ProfileName: s3ProfileName, Kind: kind, Name: name})
+		}
+
+		generation := &generations[index]
+		generation.Objects++
+		generation.SizeBytes += object.Size
+
+		if generation.LastModified == nil || object.LastModified.After(generation.LastModified.Time) {
+			generation.LastModified = &metav1.Time{Time: object.LastModified}
+		}
+	}
+
+	sort.Slice(generations, func(i, j int) bool {
+		if generations[i].Kind != generations[j].Kind {
+			return generations[i].Kind < generations[j].Kind
+		}
+
+		return generations[i].Name < generations[j].Name
+	})
+
+	return generations
+}
+
+// stateGenerationKindAndName returns the kind and name of the generation of an object, given its key relative to
+// its VRG's path: <kubeObjectsPathName><number>/... for a kube objects capture,
+// <kubeObjectsPathName><kubeObjectsCapturesPathName><sequence>/... for the group captures of a differential capture,
+// or <package>.<kind>/<name> for a typed object
+func stateGenerationKindAndName(key string) (string, string) {
+	if captureKey, ok := strings.CutPrefix(key, kubeObjectsPathName); ok {
+		if sequenceKey, ok := strings.CutPrefix(captureKey, kubeObjectsCapturesPathName); ok {
+			sequence, _, _ := strings.Cut(sequenceKey, "/")
+
+			return StateGenerationKindKubeObjectsCapture, kubeObjectsCapturesPathName + sequence
+		}
+
+		number, _, _ := strings.Cut(captureKey, "/")
+
+		return StateGenerationKindKubeObjectsCapture, number
+	}
+
+	typeName, _, _ := strings.Cut(key, "/")
+
+	return typeName[strings.LastIndex(typeName, ".")+1:], ""
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the grouping of the objects stored for a DRPC into state generations
+package controllers //nolint: testpackage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_StateGenerations", func() {
+	Describe("stateGenerationKindAndName", func() {
+		It("names kube objects captures by number or sequence, and typed objects by kind", func() {
+			kindAndName := func(key string) []string {
+				kind, name := stateGenerationKindAndName(key)
+
+				return []string{kind, name}
+			}
+
+			Expect(kindAndName("kube-objects/3/velero/backups/app--vrg--3--0--s3/app--vrg--3--0--s3.tar.gz")).To(
+				Equal([]string{StateGenerationKindKubeObjectsCapture, "3"}))
+			Expect(kindAndName("kube-objects/captures/7/velero/backups/app--vrg--c7--s3/manifest")).To(
+				Equal([]string{StateGenerationKindKubeObjectsCapture, "captures/7"}))
+			Expect(kindAndName("v1.PersistentVolume/pv-1")).To(Equal([]string{"PersistentVolume", ""}))
+			Expect(kindAndName("ramendr.openshift.io.VolumeReplicationGroup/vrg")).To(
+				Equal([]string{"VolumeReplicationGroup", ""}))
+		})
+	})
+
+	Describe("stateGenerations", func() {
+		It("sums the objects of each generation, with their latest modification, sorted by kind and name", func() {
+			early := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			late := early.Add(time.Hour)
+			pathName := s3PathNamePrefix("app", "drpc")
+
+			generations := stateGenerations("s3", pathName, []ObjectInfo{
+				{Key: pathName + "v1.PersistentVolume/pv-2", Size: 2, LastModified: late},
+				{Key: pathName + "kube-objects/2/velero/backups/b/b.tar.gz", Size: 10, LastModified: early},
+				{Key: pathName + "v1.PersistentVolume/pv-1", Size: 1, LastModified: early},
+				{Key: pathName + "kube-objects/1/velero/backups/a/a.tar.gz", Size: 5, LastModified: early},
+				{Key: pathName + "kube-objects/1/manifest", Size: 1, LastModified: late},
+			})
+
+			Expect(generations).To(Equal([]rmn.StateGeneration{
+				{
+					S3ProfileName: "s3", Kind: StateGenerationKindKubeObjectsCapture, Name: "1",
+					Objects: 2, SizeBytes: 6, LastModified: &metav1.Time{Time: late},
+				},
+				{
+					S3ProfileName: "s3", Kind: StateGenerationKindKubeObjectsCapture, Name: "2",
+					Objects: 1, SizeBytes: 10, LastModified: &metav1.Time{Time: early},
+				},
+				{
+					S3ProfileName: "s3", Kind: "PersistentVolume",
+					Objects: 2, SizeBytes: 3, LastModified: &metav1.Time{Time: late},
+				},
+			}))
+		})
+
+		It("lists no generations for a DRPC without stored objects", func() {
+			Expect(stateGenerations("s3", "app/drpc/", nil)).To(BeEmpty())
+		})
+	})
+
+	Describe("stateGenerationsList", func() {
+		It("removes the listing once it is no longer requested, and does not list again for the same request", func() {
+			d := &DRPCInstance{
+				log:      ctrl.Log.WithName("drpc-state-generations-test"),
+				instance: &rmn.DRPlacementControl{},
+			}
+			d.instance.Status.StateGenerations = &rmn.StateGenerationsListing{Request: "1"}
+			d.stateGenerationsList()
+			Expect(d.instance.Status.StateGenerations).To(BeNil())
+
+			listing := &rmn.StateGenerationsListing{Request: "1"}
+			d.instance.Annotations = map[string]string{DRPCStateGenerationsAnnotation: "1"}
+			d.instance.Status.StateGenerations = listing
+			d.stateGenerationsList()
+			Expect(d.instance.Status.StateGenerations).To(BeIdenticalTo(listing))
+		})
+	})
+})
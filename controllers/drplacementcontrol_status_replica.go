@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// drpcStatusReplicaResyncInterval is how often the status replica recomputes the metrics of a DRPC, for its sync
+// status to follow the age of its last group sync
+const drpcStatusReplicaResyncInterval = time.Minute
+
+// DRPCStatusReplicaReconciler computes the metrics of DRPCs from their status, without writing to the API server,
+// for a status-only replica of the hub operator to serve them to dashboards alongside the reconciling leader
+type DRPCStatusReplicaReconciler struct {
+	client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+
+	// drPolicies are the DRPolicies of the DRPCs whose metrics are set, to delete the metrics of deleted DRPCs
+	drPolicies      map[types.NamespacedName]*rmn.DRPolicy
+	drPoliciesMutex sync.Mutex
+}
+
+// SetupWithManager sets up the status replica reconciler of DRPCs with the manager
+func (r *DRPCStatusReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.drPolicies = map[types.NamespacedName]*rmn.DRPolicy{}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("drpcstatusreplica").
+		For(&rmn.DRPlacementControl{}).
+		Complete(r)
+}
+
+func (r *DRPCStatusReplicaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("DRPC", req.NamespacedName)
+
+	drpc := &rmn.DRPlacementControl{}
+	if err := r.Get(ctx, req.NamespacedName, drpc); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			r.metricsDelete(req.NamespacedName, drpc)
+		}
+
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if isBeingDeleted(drpc, nil) {
+		r.metricsDelete(req.NamespacedName, drpc)
+
+		return ctrl.Result{}, nil
+	}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	_, ramenConfig, err := ConfigMapGet(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// The DRPC reconciler builds the metrics from the same status; its writes fail with the status replica's
+	// read-only client, so only its computations are reused
+	drpcReconciler := &DRPlacementControlReconciler{Client: r.Client, APIReader: r.APIReader, Log: r.Log}
+
+	drpcReconciler.updateDataSyncedCondition(ctx, drpc, nil, ramenConfig, log)
+
+	if err := drpcReconciler.setDRPCMetrics(ctx, drpc, log); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.drPoliciesMutex.Lock()
+	r.drPolicies[req.NamespacedName] = drPolicy
+	r.drPoliciesMutex.Unlock()
+
+	return ctrl.Result{RequeueAfter: drpcStatusReplicaResyncInterval}, nil
+}
+
+// metricsDelete deletes the metrics of a deleted DRPC, by the DRPolicy they were set with
+func (r *DRPCStatusReplicaReconciler) metricsDelete(namespacedName types.NamespacedName,
+	drpc *rmn.DRPlacementControl,
+) {
+	r.drPoliciesMutex.Lock()
+	drPolicy, found := r.drPolicies[namespacedName]
+	delete(r.drPolicies, namespacedName)
+	r.drPoliciesMutex.Unlock()
+
+	if !found {
+		return
+	}
+
+	drpc.Namespace, drpc.Name = namespacedName.Namespace, namespacedName.Name
+
+	deleteDRPCMetrics(drPolicy, drpc)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	syncWarningFactorDefault  = 2
+	syncCriticalFactorDefault = 3
+)
+
+// Values of the sync status metric
+const (
+	syncStatusUnknown  = -1
+	syncStatusOnTime   = 0
+	syncStatusLagging  = 1
+	syncStatusCritical = 2
+)
+
+// updateDataSyncedCondition sets the DataSynced condition and the sync status metric of an async DRPC that is
+// deployed, failed over or relocated, by the age of its last group sync against the sync thresholds of the
+// RamenConfig, and removes them otherwise. They are kept as they are if the RamenConfig failed to be read.
+func (r *DRPlacementControlReconciler) updateDataSyncedCondition(ctx context.Context, drpc *rmn.DRPlacementControl,
+	userPlacement client.Object, ramenConfig *rmn.RamenConfig, log logr.Logger,
+) {
+	if isBeingDeleted(drpc, userPlacement) || ramenConfig == nil {
+		return
+	}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		log.Info("Failed to get DRPolicy", "error", err)
+
+		return
+	}
+
+	labels := SyncStatusMetricLabels(drPolicy, drpc)
+
+	interval, err := rmnutil.GetSecondsFromSchedulingInterval(drPolicy)
+	if err != nil || interval == 0 || !drpcInFinalPhase(drpc) {
+		meta.RemoveStatusCondition(&drpc.Status.Conditions, rmn.ConditionDataSynced)
+		DeleteSyncStatusMetric(labels)
+
+		return
+	}
+
+	status := setDataSyncedCondition(drpc, time.Duration(interval)*time.Second,
+		syncWarningFactor(ramenConfig), syncCriticalFactor(ramenConfig))
+
+	NewSyncStatusMetric(labels).SyncStatus.Set(float64(status))
+}
+
+func setDataSyncedCondition(drpc *rmn.DRPlacementControl, interval time.Duration, warningFactor, criticalFactor int,
+) int {
+	lastSyncTime := drpc.Status.LastGroupSyncTime
+	if lastSyncTime == nil {
+		addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionDataSynced, drpc.Generation,
+			metav1.ConditionUnknown, rmn.ReasonSyncUnknown, "No group sync reported")
+
+		return syncStatusUnknown
+	}
+
+	// The message does not include the age, so that the condition changes only as the thresholds are crossed
+	msg := fmt.Sprintf("Last group sync at %s", lastSyncTime.UTC().Format(time.RFC3339))
+
+	if duration := drpc.Status.LastGroupSyncDuration; duration != nil {
+		msg += fmt.Sprintf(" took %s", duration.Duration)
+	}
+
+	if bytes := drpc.Status.LastGroupSyncBytes; bytes != nil {
+		msg += fmt.Sprintf(" and synced %s", resource.NewQuantity(*bytes, resource.BinarySI))
+	}
+
+	age := time.Since(lastSyncTime.Time)
+	status, reason, conditionStatus := syncStatusOnTime, rmn.ReasonSyncOnTime, metav1.ConditionTrue
+
+	switch {
+	case age > interval*time.Duration(criticalFactor):
+		status, reason, conditionStatus = syncStatusCritical, rmn.ReasonSyncCritical, metav1.ConditionFalse
+		msg += fmt.Sprintf(", more than %d scheduling intervals of %s ago", criticalFactor, interval)
+	case age > interval*time.Duration(warningFactor):
+		status, reason, conditionStatus = syncStatusLagging, rmn.ReasonSyncLagging, metav1.ConditionFalse
+		msg += fmt.Sprintf(", more than %d scheduling intervals of %s ago", warningFactor, interval)
+	default:
+		msg += fmt.Sprintf(", within %d scheduling intervals of %s", warningFactor, interval)
+	}
+
+	addOrUpdateCondition(&drpc.Status.Conditions, rmn.ConditionDataSynced, drpc.Generation, conditionStatus,
+		reason, msg)
+
+	return status
+}
+
+//nolint:exhaustive
+func drpcInFinalPhase(drpc *rmn.DRPlacementControl) bool {
+	switch drpc.Status.Phase {
+	case rmn.Deployed, rmn.FailedOver, rmn.Relocated:
+		return true
+	default:
+		return false
+	}
+}
+
+func syncWarningFactor(ramenConfig *rmn.RamenConfig) int {
+	if ramenConfig.SyncThresholds.WarningFactor == 0 {
+		return syncWarningFactorDefault
+	}
+
+	return ramenConfig.SyncThresholds.WarningFactor
+}
+
+func syncCriticalFactor(ramenConfig *rmn.RamenConfig) int {
+	if ramenConfig.SyncThresholds.CriticalFactor == 0 {
+		return syncCriticalFactorDefault
+	}
+
+	return ramenConfig.SyncThresholds.CriticalFactor
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the DataSynced condition and sync status metric of DRPCs
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_SyncStatus", func() {
+	interval := 5 * time.Minute
+
+	DescribeTable("setDataSyncedCondition",
+		func(age time.Duration, status int, conditionStatus metav1.ConditionStatus, reason, message string) {
+			drpc := &rmn.DRPlacementControl{}
+			if age != 0 {
+				lastSyncTime := metav1.NewTime(time.Now().Add(-age))
+				drpc.Status.LastGroupSyncTime = &lastSyncTime
+			}
+
+			Expect(setDataSyncedCondition(drpc, interval, 2, 3)).To(Equal(status))
+
+			condition := findCondition(drpc.Status.Conditions, rmn.ConditionDataSynced)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(conditionStatus))
+			Expect(condition.Reason).To(Equal(reason))
+			Expect(condition.Message).To(ContainSubstring(message))
+		},
+		Entry("no group sync", time.Duration(0), syncStatusUnknown, metav1.ConditionUnknown, rmn.ReasonSyncUnknown,
+			"No group sync reported"),
+		Entry("on time", time.Minute, syncStatusOnTime, metav1.ConditionTrue, rmn.ReasonSyncOnTime,
+			"within 2 scheduling intervals of 5m0s"),
+		Entry("lagging", 11*time.Minute, syncStatusLagging, metav1.ConditionFalse, rmn.ReasonSyncLagging,
+			"more than 2 scheduling intervals of 5m0s ago"),
+		Entry("critical", 16*time.Minute, syncStatusCritical, metav1.ConditionFalse, rmn.ReasonSyncCritical,
+			"more than 3 scheduling intervals of 5m0s ago"),
+	)
+
+	It("reports the duration and bytes of the last group sync, but not its age", func() {
+		lastSyncTime := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		bytes := int64(3 << 20)
+		drpc := &rmn.DRPlacementControl{Status: rmn.DRPlacementControlStatus{
+			LastGroupSyncTime:     &lastSyncTime,
+			LastGroupSyncDuration: &metav1.Duration{Duration: 90 * time.Second},
+			LastGroupSyncBytes:    &bytes,
+		}}
+
+		setDataSyncedCondition(drpc, interval, 2, 3)
+		Expect(findCondition(drpc.Status.Conditions, rmn.ConditionDataSynced).Message).To(Equal(
+			"Last group sync at 2024-01-02T03:04:05Z took 1m30s and synced 3Mi, " +
+				"more than 3 scheduling intervals of 5m0s ago"))
+	})
+
+	It("defaults the sync thresholds", func() {
+		ramenConfig := &rmn.RamenConfig{}
+		Expect(syncWarningFactor(ramenConfig)).To(Equal(syncWarningFactorDefault))
+		Expect(syncCriticalFactor(ramenConfig)).To(Equal(syncCriticalFactorDefault))
+
+		ramenConfig.SyncThresholds.WarningFactor = 4
+		ramenConfig.SyncThresholds.CriticalFactor = 6
+		Expect(syncWarningFactor(ramenConfig)).To(Equal(4))
+		Expect(syncCriticalFactor(ramenConfig)).To(Equal(6))
+	})
+
+	Describe("updateDataSyncedCondition", func() {
+		var (
+			r        *DRPlacementControlReconciler
+			drPolicy *rmn.DRPolicy
+			drpc     *rmn.DRPlacementControl
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+			drPolicy = &rmn.DRPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "sync-status-policy"},
+				Spec:       rmn.DRPolicySpec{SchedulingInterval: "5m"},
+			}
+			r = &DRPlacementControlReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				drPolicy).Build()}
+
+			lastSyncTime := metav1.Now()
+			drpc = &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "sync-status-drpc"},
+				Spec: rmn.DRPlacementControlSpec{
+					DRPolicyRef: corev1.ObjectReference{Name: drPolicy.Name},
+				},
+				Status: rmn.DRPlacementControlStatus{Phase: rmn.Deployed, LastGroupSyncTime: &lastSyncTime},
+			}
+
+			DeferCleanup(func() {
+				DeleteSyncStatusMetric(SyncStatusMetricLabels(drPolicy, drpc))
+			})
+		})
+
+		update := func(ramenConfig *rmn.RamenConfig) {
+			r.updateDataSyncedCondition(context.TODO(), drpc, nil, ramenConfig,
+				ctrl.Log.WithName("drpc-sync-status-test"))
+		}
+		metric := func() float64 {
+			return testutil.ToFloat64(NewSyncStatusMetric(SyncStatusMetricLabels(drPolicy, drpc)).SyncStatus)
+		}
+
+		It("sets the condition and the metric of a deployed DRPC, and removes them while it fails over", func() {
+			update(&rmn.RamenConfig{})
+			Expect(findCondition(drpc.Status.Conditions, rmn.ConditionDataSynced).Reason).To(Equal(
+				rmn.ReasonSyncOnTime))
+			Expect(metric()).To(Equal(float64(syncStatusOnTime)))
+
+			drpc.Status.Phase = rmn.FailingOver
+			update(&rmn.RamenConfig{})
+			Expect(findCondition(drpc.Status.Conditions, rmn.ConditionDataSynced)).To(BeNil())
+			Expect(DeleteSyncStatusMetric(SyncStatusMetricLabels(drPolicy, drpc))).To(BeFalse())
+		})
+
+		It("keeps the condition as it is without the RamenConfig", func() {
+			drpc.Status.Phase = rmn.FailingOver
+			drpc.Status.Conditions = []metav1.Condition{{Type: rmn.ConditionDataSynced, Reason: rmn.ReasonSyncLagging}}
+
+			update(nil)
+			Expect(findCondition(drpc.Status.Conditions, rmn.ConditionDataSynced).Reason).To(Equal(
+				rmn.ReasonSyncLagging))
+		})
+	})
+})
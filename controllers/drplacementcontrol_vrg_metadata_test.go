@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the labels and annotations a DRPC stamps onto its VRG ManifestWorks
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_VRGMetadata", func() {
+	drpc := func(vrgMetadata *rmn.VRGMetadata) *rmn.DRPlacementControl {
+		return &rmn.DRPlacementControl{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"},
+			Spec:       rmn.DRPlacementControlSpec{VRGMetadata: vrgMetadata},
+		}
+	}
+	drpcAnnotations := map[string]string{DRPCNameAnnotation: "drpc", DRPCNamespaceAnnotation: "app"}
+
+	DescribeTable("vrgManifestWorkMetadata",
+		func(vrgMetadata *rmn.VRGMetadata, labels, annotations map[string]string) {
+			mwLabels, mwAnnotations := vrgManifestWorkMetadata(drpc(vrgMetadata))
+			Expect(mwLabels).To(Equal(labels))
+			Expect(mwAnnotations).To(Equal(annotations))
+		},
+		Entry("the DRPC annotations without metadata", nil, map[string]string{}, drpcAnnotations),
+		Entry("the labels and annotations of the metadata", &rmn.VRGMetadata{
+			Labels:      map[string]string{"cost-center": "a"},
+			Annotations: map[string]string{"backup": "exclude"},
+		}, map[string]string{"cost-center": "a"}, map[string]string{
+			DRPCNameAnnotation: "drpc", DRPCNamespaceAnnotation: "app", "backup": "exclude",
+		}),
+		Entry("the DRPC annotations the metadata does not override", &rmn.VRGMetadata{
+			Annotations: map[string]string{DRPCNameAnnotation: "other"},
+		}, map[string]string{}, drpcAnnotations),
+	)
+})
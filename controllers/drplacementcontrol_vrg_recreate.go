@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// VRGRecreatedAnnotation is set on a VRG recreated by its DRPC, to the time it was recreated. It changes the
+// ManifestWork of the VRG, so that the work agent applies it again.
+const VRGRecreatedAnnotation = "drplacementcontrol.ramendr.openshift.io/vrg-recreated"
+
+// VRGRecreateBackoff is how long after recreating a VRG its DRPC waits for a view of it, before recreating it again
+const VRGRecreateBackoff = time.Minute * 5
+
+// vrgsRecreate recreates the VRGs of a DRPC that were deleted from their managed clusters while their ManifestWorks
+// still exist, such as by hand, leaving the workload unprotected. It only does so once the DRPC has completed its
+// current action, when the ManifestWorks are expected to match the VRGs on the managed clusters.
+func (d *DRPCInstance) vrgsRecreate() {
+	if d.instance.Status.Progression != rmn.ProgressionCompleted || rmnutil.ResourceIsDeleted(d.instance) {
+		return
+	}
+
+	for i := range d.drClusters {
+		cluster := d.drClusters[i].Name
+
+		if _, ok := d.vrgs[cluster]; ok || rmnutil.ResourceIsDeleted(&d.drClusters[i]) {
+			continue
+		}
+
+		if err := d.vrgRecreate(cluster); err != nil {
+			d.log.Info("VRG not recreated", "cluster", cluster, "error", err)
+		}
+	}
+}
+
+// vrgRecreate recreates the VRG of a cluster if its ManifestWork was applied, and the VRG was viewed as not found
+// since then
+func (d *DRPCInstance) vrgRecreate(cluster string) error {
+	mw, err := d.mwu.FindManifestWorkByType(rmnutil.MWTypeVRG, cluster)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	vrg, err := rmnutil.ExtractVRGFromManifestWork(mw)
+	if err != nil {
+		return err
+	}
+
+	since := vrgRecreateSince(mw, vrg)
+	if since == nil {
+		return nil
+	}
+
+	deleted, err := d.vrgDeletedSince(cluster, *since)
+	if err != nil || !deleted {
+		return err
+	}
+
+	rmnutil.AddAnnotation(vrg, VRGRecreatedAnnotation, time.Now().UTC().Format(time.RFC3339))
+
+	labels, annotations := vrgManifestWorkMetadata(d.instance)
+
+	if err := d.mwu.CreateOrUpdateVRGManifestWork(d.instance.Name, d.vrgNamespace, cluster, *vrg,
+		labels, annotations); err != nil {
+		return fmt.Errorf("failed to update VRG ManifestWork: %w", err)
+	}
+
+	msg := fmt.Sprintf("VRG %s/%s was deleted from cluster %s while its DRPC exists, recreated it as %s",
+		d.vrgNamespace, d.instance.Name, cluster, vrg.Spec.ReplicationState)
+
+	d.log.Info(msg)
+	rmnutil.ReportIfNotPresent(d.reconciler.eventRecorder, d.instance, corev1.EventTypeWarning,
+		rmnutil.EventReasonVRGRecreated, msg)
+
+	return nil
+}
+
+// vrgRecreateSince returns the time after which a view of a VRG not found tells that the VRG was deleted, or nil
+// if the work agent has not applied the current generation of its ManifestWork. That is the time the ManifestWork
+// was applied, or VRGRecreateBackoff after the VRG was last recreated, as the agent applies the recreated VRG, and
+// the view reports it, a while after the ManifestWork is updated, while the Applied condition does not transition.
+func vrgRecreateSince(mw *ocmworkv1.ManifestWork, vrg *rmn.VolumeReplicationGroup) *metav1.Time {
+	applied := meta.FindStatusCondition(mw.Status.Conditions, ocmworkv1.WorkApplied)
+	if rmnutil.ResourceIsDeleted(mw) || applied == nil || applied.Status != metav1.ConditionTrue ||
+		applied.ObservedGeneration != mw.Generation {
+		return nil
+	}
+
+	since := applied.LastTransitionTime.DeepCopy()
+
+	recreated, err := time.Parse(time.RFC3339, vrg.GetAnnotations()[VRGRecreatedAnnotation])
+	if err == nil && since.Time.Before(recreated.Add(VRGRecreateBackoff)) {
+		since = &metav1.Time{Time: recreated.Add(VRGRecreateBackoff)}
+	}
+
+	return since
+}
+
+// vrgDeletedSince returns whether the VRG of a cluster is viewed as not found, in a view refreshed after a time. A
+// view pruned since its DRPC read the VRG status from the ManifestWork feedback is created again, to be refreshed.
+func (d *DRPCInstance) vrgDeletedSince(cluster string, since metav1.Time) (bool, error) {
+	viewTime, err := d.reconciler.MCVGetter.GetVRGViewTime(d.instance.Name, d.vrgNamespace, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	annotations := map[string]string{
+		DRPCNameAnnotation:      d.instance.Name,
+		DRPCNamespaceAnnotation: d.instance.Namespace,
+	}
+
+	if viewTime == nil {
+		_, err = d.reconciler.MCVGetter.GetVRGFromManagedCluster(d.instance.Name, d.vrgNamespace, cluster, annotations)
+		if errors.IsNotFound(err) {
+			err = nil
+		}
+
+		return false, err
+	}
+
+	if !since.Before(viewTime) {
+		return false, nil
+	}
+
+	_, err = d.reconciler.MCVGetter.GetVRGFromManagedCluster(d.instance.Name, d.vrgNamespace, cluster, annotations)
+	if err == nil {
+		return false, nil
+	}
+
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+
+	return false, err
+}
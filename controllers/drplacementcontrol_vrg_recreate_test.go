@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the recreation of VRGs deleted from managed clusters
+package controllers //nolint: testpackage
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("DRPC_VRGRecreate", func() {
+	applied := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var (
+		mw  *ocmworkv1.ManifestWork
+		vrg *rmn.VolumeReplicationGroup
+	)
+
+	recreatedAt := func(recreated time.Time) {
+		vrg.SetAnnotations(map[string]string{VRGRecreatedAnnotation: recreated.Format(time.RFC3339)})
+	}
+
+	BeforeEach(func() {
+		mw = &ocmworkv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+		mw.Status.Conditions = []metav1.Condition{{
+			Type: ocmworkv1.WorkApplied, Status: metav1.ConditionTrue, ObservedGeneration: 2,
+			LastTransitionTime: metav1.NewTime(applied),
+		}}
+		vrg = &rmn.VolumeReplicationGroup{}
+	})
+
+	It("waits for a view since the ManifestWork was applied", func() {
+		Expect(vrgRecreateSince(mw, vrg).Time).To(Equal(applied))
+	})
+
+	It("waits for the work agent to apply the current generation of the ManifestWork", func() {
+		mw.Generation = 3
+		Expect(vrgRecreateSince(mw, vrg)).To(BeNil())
+
+		mw.Generation = 2
+		mw.Status.Conditions[0].Status = metav1.ConditionFalse
+		Expect(vrgRecreateSince(mw, vrg)).To(BeNil())
+	})
+
+	It("backs off from a VRG it recreated, although the Applied condition does not transition", func() {
+		recreated := applied.Add(time.Hour)
+		recreatedAt(recreated)
+		Expect(vrgRecreateSince(mw, vrg).Time).To(Equal(recreated.Add(VRGRecreateBackoff)))
+	})
+
+	It("ignores a recreation older than the ManifestWork application", func() {
+		recreatedAt(applied.Add(-time.Hour))
+		Expect(vrgRecreateSince(mw, vrg).Time).To(Equal(applied))
+	})
+})
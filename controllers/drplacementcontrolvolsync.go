@@ -5,6 +5,7 @@ package controllers
 
 import (
 	"fmt"
+	"reflect"
 
 	rmn "github.com/ramendr/ramen/api/v1alpha1"
 	rmnutil "github.com/ramendr/ramen/controllers/util"
@@ -37,7 +38,12 @@ func (d *DRPCInstance) EnsureVolSyncReplicationSetup(homeCluster string) error {
 		return err
 	}
 
-	return d.ensureVolSyncReplicationDestination(homeCluster)
+	err = d.ensureVolSyncReplicationDestination(homeCluster)
+	if err != nil {
+		return err
+	}
+
+	return d.ensureVolSyncSyncthingPeers()
 }
 
 func (d *DRPCInstance) ensureVolSyncReplicationCommon(srcCluster string) error {
@@ -263,6 +269,7 @@ func (d *DRPCInstance) updateVRGSpec(clusterName string, tgtVRG *rmn.VolumeRepli
 	}
 
 	vrg.Spec.VolSync.RDSpec = tgtVRG.Spec.VolSync.RDSpec
+	vrg.Spec.VolSync.SyncthingPeers = d.syncthingPeers(clusterName)
 
 	vrgClientManifest, err := d.mwu.GenerateManifest(vrg)
 	if err != nil {
@@ -273,7 +280,7 @@ func (d *DRPCInstance) updateVRGSpec(clusterName string, tgtVRG *rmn.VolumeRepli
 
 	mw.Spec.Workload.Manifests[0] = *vrgClientManifest
 
-	err = d.reconciler.Update(d.ctx, mw)
+	err = d.mwu.UpdateManifestWork(mw)
 	if err != nil {
 		return fmt.Errorf("failed to update MW (%w)", err)
 	}
@@ -303,15 +310,12 @@ func (d *DRPCInstance) createVolSyncDestManifestWork(clusterToSkip string) error
 				d.instance.Namespace, dstCluster)
 		}
 
-		annotations := make(map[string]string)
-
-		annotations[DRPCNameAnnotation] = d.instance.Name
-		annotations[DRPCNamespaceAnnotation] = d.instance.Namespace
+		labels, annotations := vrgManifestWorkMetadata(d.instance)
 
 		vrg := d.generateVRG(dstCluster, rmn.Secondary)
 		if err := d.mwu.CreateOrUpdateVRGManifestWork(
 			d.instance.Name, d.vrgNamespace,
-			dstCluster, vrg, annotations); err != nil {
+			dstCluster, vrg, labels, annotations); err != nil {
 			d.log.Error(err, "failed to create or update VolumeReplicationGroup manifest")
 
 			return fmt.Errorf("failed to create or update VolumeReplicationGroup manifest in namespace %s (%w)", dstCluster, err)
@@ -375,7 +379,7 @@ func (d *DRPCInstance) ResetVolSyncRDOnPrimary(clusterName string) error {
 
 	mw.Spec.Workload.Manifests[0] = *vrgClientManifest
 
-	err = d.reconciler.Update(d.ctx, mw)
+	err = d.mwu.UpdateManifestWork(mw)
 	if err != nil {
 		return fmt.Errorf("failed to update MW (%w)", err)
 	}
@@ -384,3 +388,69 @@ func (d *DRPCInstance) ResetVolSyncRDOnPrimary(clusterName string) error {
 
 	return nil
 }
+
+// syncthingPeers returns the syncthing devices reported by the VRGs of the clusters other than the cluster, for the
+// PVCs replicated by the Syncthing mover on the cluster to connect to. VolSync generates the device IDs, so they are
+// exchanged through the VRG status and spec of each cluster.
+func (d *DRPCInstance) syncthingPeers(clusterName string) []rmn.VolSyncSyncthingDevice {
+	var peers []rmn.VolSyncSyncthingDevice
+
+	for cluster, vrg := range d.vrgs {
+		if cluster == clusterName || vrg == nil {
+			continue
+		}
+
+		peers = append(peers, vrg.Status.SyncthingDevices...)
+	}
+
+	volsync.SortSyncthingDevices(peers)
+
+	return peers
+}
+
+// ensureVolSyncSyncthingPeers updates the syncthing peers of the VRG of each cluster, whatever its replication state,
+// when the syncthing devices reported by the other clusters changed
+func (d *DRPCInstance) ensureVolSyncSyncthingPeers() error {
+	moverConfig := d.volSyncMoverConfig()
+	if moverConfig == nil || moverConfig.Mover != rmn.VolSyncMoverSyncthing {
+		return nil
+	}
+
+	for _, clusterName := range rmnutil.DRPolicyClusterNames(d.drPolicy) {
+		mw, err := d.mwu.FindManifestWorkByType(rmnutil.MWTypeVRG, clusterName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get VRG MW, in namespace %s (%w)", clusterName, err)
+		}
+
+		vrg, err := rmnutil.ExtractVRGFromManifestWork(mw)
+		if err != nil {
+			return err
+		}
+
+		peers := d.syncthingPeers(clusterName)
+		if reflect.DeepEqual(vrg.Spec.VolSync.SyncthingPeers, peers) {
+			continue
+		}
+
+		vrg.Spec.VolSync.SyncthingPeers = peers
+
+		vrgClientManifest, err := d.mwu.GenerateManifest(vrg)
+		if err != nil {
+			return fmt.Errorf("failed to generate VRG manifest (%w)", err)
+		}
+
+		mw.Spec.Workload.Manifests[0] = *vrgClientManifest
+
+		if err := d.mwu.UpdateManifestWork(mw); err != nil {
+			return fmt.Errorf("failed to update MW (%w)", err)
+		}
+
+		d.log.Info("Updated VRG syncthing peers", "cluster", clusterName, "peers", len(peers))
+	}
+
+	return nil
+}
@@ -4,13 +4,18 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"text/template"
 
 	"github.com/go-logr/logr"
 	rmn "github.com/ramendr/ramen/api/v1alpha1"
 	"github.com/ramendr/ramen/controllers/util"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var drClustersMutex sync.Mutex
@@ -24,10 +29,13 @@ func propagateS3Secret(
 ) error {
 	drClustersMutex.Lock()
 	defer drClustersMutex.Unlock()
+	defer drClustersS3SecretsMetricsSet(drpolicy, drclusters, secretsUtil, hubOperatorRamenConfig, log)
 
 	for _, clusterName := range util.DRPolicyClusterNames(drpolicy) {
 		if err := drClusterSecretsDeploy(clusterName, drpolicy, drclusters, secretsUtil,
 			hubOperatorRamenConfig, log); err != nil {
+			S3SecretDistributionFailure(DRClusterS3SecretsMetricLabels(clusterName))
+
 			return err
 		}
 	}
@@ -35,6 +43,63 @@ func propagateS3Secret(
 	return nil
 }
 
+// drClustersS3SecretsMetricsSet sets the number of s3 secrets expected on each cluster of a policy, as required by
+// all the policies of the cluster other than a deleted one, and the number of those present on it, as reported by
+// the OCM policies delivering them
+func drClustersS3SecretsMetricsSet(
+	drpolicy *rmn.DRPolicy,
+	drclusters *rmn.DRClusterList,
+	secretsUtil *util.SecretsUtil,
+	ramenConfig *rmn.RamenConfig,
+	log logr.Logger,
+) {
+	clusterNames := util.DRPolicyClusterNames(drpolicy)
+
+	if !ramenConfig.DrClusterOperator.DeploymentAutomationEnabled ||
+		!ramenConfig.DrClusterOperator.S3SecretDistributionEnabled {
+		for _, clusterName := range clusterNames {
+			DeleteDRClusterS3SecretsMetrics(DRClusterS3SecretsMetricLabels(clusterName))
+		}
+
+		return
+	}
+
+	drpolicies := rmn.DRPolicyList{}
+	if err := secretsUtil.Client.List(secretsUtil.Ctx, &drpolicies); err != nil {
+		log.Error(err, "unable to list drpolicies for s3 secrets metrics")
+
+		return
+	}
+
+	var ignorePolicy *rmn.DRPolicy
+	if util.ResourceIsDeleted(drpolicy) {
+		ignorePolicy = drpolicy
+	}
+
+	for _, clusterName := range clusterNames {
+		expected := drClusterListMustHaveSecrets(drpolicies, drclusters, clusterName, ignorePolicy, ramenConfig)
+		present := 0
+
+		for _, secretName := range expected.List() {
+			delivered, err := secretsUtil.SecretDeliveredToCluster(secretName, clusterName, RamenOperatorNamespace(),
+				util.SecretFormatRamen)
+			if err != nil {
+				log.Error(err, "unable to determine s3 secret presence", "cluster", clusterName, "secret", secretName)
+
+				continue
+			}
+
+			if delivered {
+				present++
+			}
+		}
+
+		metrics := NewDRClusterS3SecretsMetrics(DRClusterS3SecretsMetricLabels(clusterName))
+		metrics.S3SecretsExpected.Set(float64(expected.Len()))
+		metrics.S3SecretsPresent.Set(float64(present))
+	}
+}
+
 func drClusterSecretsDeploy(
 	clusterName string,
 	drpolicy *rmn.DRPolicy,
@@ -59,12 +124,20 @@ func drClusterSecretsDeploy(
 		log.Info("Received partial list", "err", err)
 	}
 
+	targetSecretRefs, err := drClusterSecretRefs(rmnCfg)
+	if err != nil {
+		return fmt.Errorf("cannot add secrets to drcluster '%v': %w", clusterName, err)
+	}
+
 	for _, secretName := range drPolicySecrets.List() {
-		if err := secretsUtil.AddSecretToCluster(
+		targetSecretRef := targetSecretRefs[secretName]
+
+		if err := secretsUtil.AddSecretToClusterAs(
 			secretName,
 			clusterName,
 			RamenOperatorNamespace(),
-			drClusterOperatorNamespaceNameOrDefault(rmnCfg),
+			targetSecretRef.Name,
+			targetSecretRef.Namespace,
 			util.SecretFormatRamen,
 			"",
 		); err != nil {
@@ -72,11 +145,12 @@ func drClusterSecretsDeploy(
 		}
 
 		if !rmnCfg.KubeObjectProtection.Disabled && rmnCfg.KubeObjectProtection.VeleroNamespaceName != "" {
-			if err := secretsUtil.AddSecretToCluster(
+			if err := secretsUtil.AddSecretToClusterAs(
 				secretName,
 				clusterName,
 				RamenOperatorNamespace(),
-				drClusterOperatorNamespaceNameOrDefault(rmnCfg),
+				targetSecretRef.Name,
+				targetSecretRef.Namespace,
 				util.SecretFormatVelero,
 				rmnCfg.KubeObjectProtection.VeleroNamespaceName,
 			); err != nil {
@@ -86,6 +160,44 @@ func drClusterSecretsDeploy(
 		}
 	}
 
+	return drClusterSecretsPrune(clusterName, drclusters, secretsUtil, rmnCfg, log)
+}
+
+// drClusterSecretsPrune removes a cluster from the delivery of the s3 secrets it no longer requires, as an s3 profile
+// of its policies changed or was removed from the configuration, for the policies delivering them to prune them
+// from the cluster
+func drClusterSecretsPrune(
+	clusterName string,
+	drclusters *rmn.DRClusterList,
+	secretsUtil *util.SecretsUtil,
+	rmnCfg *rmn.RamenConfig,
+	log logr.Logger,
+) error {
+	drpolicies := rmn.DRPolicyList{}
+	if err := secretsUtil.Client.List(secretsUtil.Ctx, &drpolicies); err != nil {
+		return fmt.Errorf("drpolicies list: %w", err)
+	}
+
+	mustHaveS3Secrets := drClusterListMustHaveSecrets(drpolicies, drclusters, clusterName, nil, rmnCfg)
+
+	deliveredS3Secrets, err := secretsUtil.SecretsPlacedOnCluster(clusterName, RamenOperatorNamespace(),
+		util.SecretFormatRamen)
+	if err != nil {
+		return err
+	}
+
+	for _, s3SecretToDelete := range deliveredS3Secrets {
+		if mustHaveS3Secrets.Has(s3SecretToDelete) {
+			continue
+		}
+
+		log.Info("Removing s3 secret no longer required", "cluster", clusterName, "secret", s3SecretToDelete)
+
+		if err := deleteSecretFromCluster(s3SecretToDelete, clusterName, rmnCfg, secretsUtil); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -100,6 +212,7 @@ func drPolicyUndeploy(
 
 	drClustersMutex.Lock()
 	defer drClustersMutex.Unlock()
+	defer drClustersS3SecretsMetricsSet(drpolicy, drclusters, secretsUtil, ramenConfig, log)
 
 	if err := secretsUtil.Client.List(secretsUtil.Ctx, &drpolicies); err != nil {
 		return fmt.Errorf("drpolicies list: %w", err)
@@ -145,6 +258,8 @@ func drClustersUndeploySecrets(
 
 			// Delete s3profile secret from current cluster
 			if err := deleteSecretFromCluster(s3SecretToDelete, clusterName, ramenConfig, secretsUtil); err != nil {
+				S3SecretDistributionFailure(DRClusterS3SecretsMetricLabels(clusterName))
+
 				return err
 			}
 		}
@@ -237,7 +352,104 @@ func drPolicySecretNames(drpolicy *rmn.DRPolicy,
 	return secretNames, err
 }
 
-// Delete s3profile secret from cluster
+// drClusterSecretTemplateValues are the values the templates of the s3 secrets distributed to the dr-clusters are
+// executed with
+type drClusterSecretTemplateValues struct {
+	SecretName    string
+	S3ProfileName string
+	HubNamespace  string
+}
+
+// drClusterSecretRefs returns the names and namespaces of the s3 secrets distributed to the dr-clusters, by the name
+// of the s3 secret on the hub. A secret is distributed with the same name and namespace to all dr-clusters, and set
+// in the s3 profiles of the dr-cluster operator configuration, so the profiles sharing a secret must resolve the
+// templates to the same name and namespace.
+func drClusterSecretRefs(ramenConfig *rmn.RamenConfig) (map[string]corev1.SecretReference, error) {
+	secretRefs := map[string]corev1.SecretReference{}
+	errs := []error{}
+
+	for i := range ramenConfig.S3StoreProfiles {
+		s3StoreProfile := &ramenConfig.S3StoreProfiles[i]
+
+		secretRef, err := drClusterSecretRef(s3StoreProfile, ramenConfig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("s3 profile %s: %w", s3StoreProfile.S3ProfileName, err))
+
+			continue
+		}
+
+		if other, ok := secretRefs[s3StoreProfile.S3SecretRef.Name]; ok && other != secretRef {
+			errs = append(errs, fmt.Errorf("s3 profile %s: secret %s is distributed as %s/%s, not %s/%s, for "+
+				"another profile", s3StoreProfile.S3ProfileName, s3StoreProfile.S3SecretRef.Name,
+				other.Namespace, other.Name, secretRef.Namespace, secretRef.Name))
+
+			continue
+		}
+
+		secretRefs[s3StoreProfile.S3SecretRef.Name] = secretRef
+	}
+
+	return secretRefs, errors.Join(errs...)
+}
+
+// drClusterSecretRef returns the name and namespace of the s3 secret of an s3 profile distributed to the
+// dr-clusters, from the templates configured
+func drClusterSecretRef(s3StoreProfile *rmn.S3StoreProfile, ramenConfig *rmn.RamenConfig,
+) (corev1.SecretReference, error) {
+	secretRef := corev1.SecretReference{
+		Name:      s3StoreProfile.S3SecretRef.Name,
+		Namespace: drClusterOperatorNamespaceNameOrDefault(ramenConfig),
+	}
+	values := drClusterSecretTemplateValues{
+		SecretName:    s3StoreProfile.S3SecretRef.Name,
+		S3ProfileName: s3StoreProfile.S3ProfileName,
+		HubNamespace:  RamenOperatorNamespace(),
+	}
+
+	var err error
+
+	if ramenConfig.DrClusterOperator.S3SecretNameTemplate != "" {
+		secretRef.Name, err = drClusterSecretTemplateExecute("name",
+			ramenConfig.DrClusterOperator.S3SecretNameTemplate, values, validation.IsDNS1123Subdomain)
+		if err != nil {
+			return secretRef, err
+		}
+	}
+
+	if ramenConfig.DrClusterOperator.S3SecretNamespaceTemplate != "" {
+		secretRef.Namespace, err = drClusterSecretTemplateExecute("namespace",
+			ramenConfig.DrClusterOperator.S3SecretNamespaceTemplate, values, validation.IsDNS1123Label)
+		if err != nil {
+			return secretRef, err
+		}
+	}
+
+	return secretRef, nil
+}
+
+func drClusterSecretTemplateExecute(kind, text string, values drClusterSecretTemplateValues,
+	validate func(string) []string,
+) (string, error) {
+	tmpl, err := template.New(kind).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("s3 secret %s template %q parse: %w", kind, text, err)
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, values); err != nil {
+		return "", fmt.Errorf("s3 secret %s template %q execute: %w", kind, text, err)
+	}
+
+	if errs := validate(builder.String()); len(errs) != 0 {
+		return "", fmt.Errorf("s3 secret %s %q of template %q invalid: %s", kind, builder.String(), text,
+			strings.Join(errs, ", "))
+	}
+
+	return builder.String(), nil
+}
+
+// Delete s3profile secret from cluster. The policies delivering a secret are named after the secret on the hub,
+// so they are found regardless of the name and namespace templates the secret was distributed with.
 func deleteSecretFromCluster(
 	s3SecretToDelete, clusterName string,
 	ramenConfig *rmn.RamenConfig,
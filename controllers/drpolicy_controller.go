@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/workqueue"
+	gppv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +38,7 @@ type DRPolicyReconciler struct {
 	Scheme            *runtime.Scheme
 	ObjectStoreGetter ObjectStoreGetter
 	RateLimiter       *workqueue.RateLimiter
+	notifier          *util.Notifier
 }
 
 // ReasonValidationFailed is set when the DRPolicy could not be validated or is not valid
@@ -124,6 +126,10 @@ func (r *DRPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
+	if err := u.failureDomainsCheck(drclusters, ramenConfig); err != nil {
+		return ctrl.Result{}, fmt.Errorf("validate: %w", u.validatedSetFalse(ReasonValidationFailed, err))
+	}
+
 	if err := u.addLabelsAndFinalizers(); err != nil {
 		return ctrl.Result{}, fmt.Errorf("finalizer add update: %w", u.validatedSetFalse("FinalizerAddFailed", err))
 	}
@@ -145,11 +151,24 @@ func (r *DRPolicyReconciler) reconcile(drpolicy *ramen.DRPolicy,
 	ramenConfig *ramen.RamenConfig,
 	log logr.Logger,
 ) (ctrl.Result, error) {
+	requeueAfter, err := s3OIDCCredentialsRefresh(secretsUtil.Ctx, secretsUtil.Client, drpolicy, drclusters,
+		ramenConfig, log)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("s3 credentials refresh: %w", err)
+	}
+
 	if err := propagateS3Secret(drpolicy, drclusters, secretsUtil, ramenConfig, log); err != nil {
+		r.notifier.Notify(r.APIReader, RamenOperatorNamespace(), ramenConfig.Notifications, util.Notification{
+			Event:   ramen.NotificationEventSecretDistributionFailed,
+			Kind:    "DRPolicy",
+			Name:    drpolicy.Name,
+			Message: err.Error(),
+		}, log)
+
 		return ctrl.Result{}, fmt.Errorf("drpolicy deploy: %w", err)
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *DRPolicyReconciler) initiateDRPolicyMetrics(drpolicy *ramen.DRPolicy, drclusters *ramen.DRClusterList) error {
@@ -378,13 +397,20 @@ func (u *drpolicyUpdater) statusConditionSet(conditionType string,
 	reason, message string,
 ) error {
 	conditions := &u.object.Status.Conditions
+	health, nextStep := u.object.Status.Health, u.object.Status.NextStep
 
-	if util.GenericStatusConditionSet(u.object, conditions, conditionType,
-		status, reason, message, u.log) {
-		return u.statusUpdate()
+	updated := util.GenericStatusConditionSet(u.object, conditions, conditionType,
+		status, reason, message, u.log)
+
+	setDRPolicyHealth(u.object)
+
+	if updated || health != u.object.Status.Health || nextStep != u.object.Status.NextStep {
+		if err := u.statusUpdate(); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return healthLabelUpdate(u.ctx, u.client, u.object, u.object.Status.Health)
 }
 
 func (u *drpolicyUpdater) statusUpdate() error {
@@ -408,6 +434,8 @@ func (u *drpolicyUpdater) finalizerRemove() error {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DRPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.notifier = util.NewNotifier()
+
 	controller := ctrl.NewControllerManagedBy(mgr)
 	if r.RateLimiter != nil {
 		controller.WithOptions(ctrlcontroller.Options{
@@ -432,6 +460,11 @@ func (r *DRPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.drClusterMapFunc),
 			builder.WithPredicates(util.CreateOrDeleteOrResourceVersionUpdatePredicate{}),
 		).
+		Watches(
+			&gppv1.Policy{},
+			handler.EnqueueRequestsFromMapFunc(r.policyMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
 
@@ -482,6 +515,57 @@ func (r *DRPolicyReconciler) secretMapFunc(ctx context.Context, secret client.Ob
 	return requests
 }
 
+// policyMapFunc reconciles the DRPolicies whose clusters use the s3 secret an OCM policy delivers on a change to its
+// compliance, to update the number of s3 secrets present on their clusters
+func (r *DRPolicyReconciler) policyMapFunc(ctx context.Context, policy client.Object) []reconcile.Request {
+	// Policies delivering s3 secrets are in the same namespace as the secrets
+	if policy.GetNamespace() != RamenOperatorNamespace() {
+		return []reconcile.Request{}
+	}
+
+	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	drclusters := &ramen.DRClusterList{}
+	if err := r.Client.List(ctx, drclusters); err != nil {
+		return []reconcile.Request{}
+	}
+
+	drpolicies := &ramen.DRPolicyList{}
+	if err := r.Client.List(ctx, drpolicies); err != nil {
+		return []reconcile.Request{}
+	}
+
+	return drPoliciesDeliveredSecretBy(drpolicies, drclusters, ramenConfig, policy.GetName())
+}
+
+// drPoliciesDeliveredSecretBy returns the requests of the DRPolicies whose clusters use an s3 secret delivered by the
+// OCM policy of a name
+func drPoliciesDeliveredSecretBy(drpolicies *ramen.DRPolicyList, drclusters *ramen.DRClusterList,
+	ramenConfig *ramen.RamenConfig, policyName string,
+) []reconcile.Request {
+	requests := []reconcile.Request{}
+
+	for i := range drpolicies.Items {
+		drpolicy := &drpolicies.Items[i]
+
+		// the secrets of the clusters with profiles are still delivered when the profile of another is missing
+		secretNames, _ := drPolicySecretNames(drpolicy, drclusters, ramenConfig)
+
+		for _, secretName := range secretNames.List() {
+			if name, _, _, _ := util.GeneratePolicyResourceNames(secretName, util.SecretFormatRamen); name == policyName {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: drpolicy.Name}})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
 func (r *DRPolicyReconciler) drClusterMapFunc(ctx context.Context, drcluster client.Object) []reconcile.Request {
 	drpolicies := &ramen.DRPolicyList{}
 	if err := r.Client.List(context.TODO(), drpolicies); err != nil {
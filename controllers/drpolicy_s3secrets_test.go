@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the templates and removal of the s3 secrets distributed to the dr-clusters
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	cpcv1 "open-cluster-management.io/config-policy-controller/api/v1"
+	gppv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("DRPolicy_S3Secrets", func() {
+	s3StoreProfile := func(profileName, secretName string) ramen.S3StoreProfile {
+		return ramen.S3StoreProfile{
+			S3ProfileName: profileName,
+			S3SecretRef:   corev1.SecretReference{Name: secretName},
+		}
+	}
+
+	Describe("drClusterSecretRefs", func() {
+		It("resolves the templates for each s3 profile", func() {
+			ramenConfig := &ramen.RamenConfig{S3StoreProfiles: []ramen.S3StoreProfile{
+				s3StoreProfile("east", "east-secret"),
+				s3StoreProfile("west", "west-secret"),
+			}}
+			ramenConfig.DrClusterOperator.NamespaceName = "ramen-dr-cluster"
+			ramenConfig.DrClusterOperator.S3SecretNameTemplate = "{{.S3ProfileName}}-{{.SecretName}}"
+			ramenConfig.DrClusterOperator.S3SecretNamespaceTemplate = "ramen-s3"
+
+			Expect(drClusterSecretRefs(ramenConfig)).To(Equal(map[string]corev1.SecretReference{
+				"east-secret": {Name: "east-east-secret", Namespace: "ramen-s3"},
+				"west-secret": {Name: "west-west-secret", Namespace: "ramen-s3"},
+			}))
+
+			ramenConfig.DrClusterOperator.S3SecretNamespaceTemplate = ""
+			secretRefs, err := drClusterSecretRefs(ramenConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretRefs["east-secret"].Namespace).To(Equal("ramen-dr-cluster"))
+		})
+
+		It("requires the s3 profiles sharing a secret to resolve to the same name and namespace", func() {
+			ramenConfig := &ramen.RamenConfig{S3StoreProfiles: []ramen.S3StoreProfile{
+				s3StoreProfile("east", "s3-secret"),
+				s3StoreProfile("west", "s3-secret"),
+			}}
+			ramenConfig.DrClusterOperator.S3SecretNameTemplate = "dr-{{.SecretName}}"
+
+			Expect(drClusterSecretRefs(ramenConfig)).To(Equal(map[string]corev1.SecretReference{
+				"s3-secret": {Name: "dr-s3-secret", Namespace: drClusterOperatorNamespaceNameOrDefault(ramenConfig)},
+			}))
+
+			ramenConfig.DrClusterOperator.S3SecretNameTemplate = "{{.S3ProfileName}}"
+			_, err := drClusterSecretRefs(ramenConfig)
+			Expect(err).To(MatchError(ContainSubstring("s3 profile west: secret s3-secret is distributed as")))
+
+			ramenConfig.DrClusterOperator.S3SecretNameTemplate = "{{.Bucket}}"
+			_, err = drClusterSecretRefs(ramenConfig)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("secret delivery", func() {
+		const (
+			namespace  = "ramen-hub"
+			secretName = "s3-secret"
+		)
+
+		var secretsUtil *util.SecretsUtil
+
+		configPolicy := func() *cpcv1.ConfigurationPolicy {
+			policy := &gppv1.Policy{}
+			Expect(secretsUtil.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: secretName},
+				policy)).To(Succeed())
+			Expect(policy.Spec.PolicyTemplates).To(HaveLen(1))
+
+			configPolicy := &cpcv1.ConfigurationPolicy{}
+			Expect(json.Unmarshal(policy.Spec.PolicyTemplates[0].ObjectDefinition.Raw, configPolicy)).To(Succeed())
+
+			return configPolicy
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(gppv1.AddToScheme(scheme)).To(Succeed())
+			Expect(plrv1.AddToScheme(scheme)).To(Succeed())
+
+			client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: secretName},
+			}).Build()
+			secretsUtil = &util.SecretsUtil{
+				Client:    client,
+				APIReader: client,
+				Ctx:       context.TODO(),
+				Log:       ctrl.Log.WithName("drpolicy-s3secrets-test"),
+			}
+		})
+
+		It("removes the secret delivered with a previous name, and prunes the secret delivered", func() {
+			for _, targetName := range []string{"first", "second", "third"} {
+				Expect(secretsUtil.AddSecretToClusterAs(secretName, "east", namespace, targetName, "ramen-s3",
+					util.SecretFormatRamen, "")).To(Succeed())
+			}
+
+			objectTemplates := configPolicy().Spec.ObjectTemplates
+			Expect(configPolicy().Spec.PruneObjectBehavior).To(BeEquivalentTo("DeleteIfCreated"))
+			Expect(objectTemplates).To(HaveLen(3))
+			Expect(objectTemplates[0].ComplianceType).To(Equal(cpcv1.MustHave))
+			Expect(string(objectTemplates[0].ObjectDefinition.Raw)).To(ContainSubstring(`"name":"third"`))
+
+			for _, objectTemplate := range objectTemplates[1:] {
+				Expect(objectTemplate.ComplianceType).To(Equal(cpcv1.MustNotHave))
+				Expect(string(objectTemplate.ObjectDefinition.Raw)).To(Or(
+					ContainSubstring(`"name":"first"`), ContainSubstring(`"name":"second"`)))
+			}
+
+			Expect(secretsUtil.AddSecretToClusterAs(secretName, "east", namespace, "first", "ramen-s3",
+				util.SecretFormatRamen, "")).To(Succeed())
+			Expect(configPolicy().Spec.ObjectTemplates).To(HaveLen(3))
+			Expect(string(configPolicy().Spec.ObjectTemplates[0].ObjectDefinition.Raw)).To(
+				ContainSubstring(`"name":"first"`))
+		})
+
+		It("lists the secrets placed on a cluster until it is removed from their delivery", func() {
+			Expect(secretsUtil.AddSecretToClusterAs(secretName, "east", namespace, secretName, "ramen-s3",
+				util.SecretFormatRamen, "")).To(Succeed())
+			Expect(secretsUtil.AddSecretToClusterAs(secretName, "west", namespace, secretName, "ramen-s3",
+				util.SecretFormatRamen, "")).To(Succeed())
+
+			Expect(secretsUtil.SecretsPlacedOnCluster("east", namespace, util.SecretFormatRamen)).To(
+				ConsistOf(secretName))
+
+			Expect(secretsUtil.RemoveSecretFromCluster(secretName, "east", namespace, util.SecretFormatRamen)).To(
+				Succeed())
+			Expect(secretsUtil.SecretsPlacedOnCluster("east", namespace, util.SecretFormatRamen)).To(BeEmpty())
+			Expect(secretsUtil.SecretsPlacedOnCluster("west", namespace, util.SecretFormatRamen)).To(
+				ConsistOf(secretName))
+		})
+	})
+
+	Describe("drPoliciesDeliveredSecretBy", func() {
+		It("requests the DRPolicies whose clusters use the secret the policy delivers", func() {
+			ramenConfig := &ramen.RamenConfig{S3StoreProfiles: []ramen.S3StoreProfile{
+				s3StoreProfile("east", "east-secret"),
+				s3StoreProfile("west", "west-secret"),
+				s3StoreProfile("north", "north-secret"),
+			}}
+			drCluster := func(name, s3ProfileName string) ramen.DRCluster {
+				return ramen.DRCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       ramen.DRClusterSpec{S3ProfileName: s3ProfileName},
+				}
+			}
+			drPolicy := func(name string, clusterNames ...string) ramen.DRPolicy {
+				return ramen.DRPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Spec:       ramen.DRPolicySpec{DRClusters: clusterNames},
+				}
+			}
+			drclusters := &ramen.DRClusterList{Items: []ramen.DRCluster{
+				drCluster("east", "east"), drCluster("west", "west"), drCluster("north", "north"),
+				drCluster("south", "missing"),
+			}}
+			drpolicies := &ramen.DRPolicyList{Items: []ramen.DRPolicy{
+				drPolicy("east-west", "east", "west"),
+				drPolicy("west-north", "west", "north"),
+				drPolicy("east-south", "east", "south"),
+			}}
+			names := func(policyName string) []string {
+				names := []string{}
+				for _, request := range drPoliciesDeliveredSecretBy(drpolicies, drclusters, ramenConfig, policyName) {
+					names = append(names, request.Name)
+				}
+
+				return names
+			}
+			policyName, _, _, _ := util.GeneratePolicyResourceNames("west-secret", util.SecretFormatRamen)
+			Expect(names(policyName)).To(ConsistOf("east-west", "west-north"))
+
+			policyName, _, _, _ = util.GeneratePolicyResourceNames("east-secret", util.SecretFormatRamen)
+			Expect(names(policyName)).To(ConsistOf("east-west", "east-south"))
+
+			policyName, _, _, _ = util.GeneratePolicyResourceNames("west-secret", util.SecretFormatVelero)
+			Expect(names(policyName)).To(BeEmpty())
+		})
+	})
+})
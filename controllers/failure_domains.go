@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// regionClusterClaimName and zoneClusterClaimName are the cluster claims of the region and zone of a managed
+	// cluster, the region and zone of its DRCluster default to
+	regionClusterClaimName = "region.open-cluster-management.io"
+	zoneClusterClaimName   = "zone.open-cluster-management.io"
+
+	DRPolicyConditionReasonFailureDomainsDistinct = "Distinct"
+	DRPolicyConditionReasonFailureDomainShared    = "Shared"
+	DRPolicyConditionReasonZoneUnknown            = "ZoneUnknown"
+)
+
+// failureDomainSet sets the region and zone of the DRCluster status to those of its spec, or to those its managed
+// cluster claims. The region and zone last claimed are kept if the managed cluster cannot be read.
+func (u *drclusterInstance) failureDomainSet() {
+	if u.object.Spec.Region != "" && u.object.Spec.Zone != "" {
+		u.object.Status.Region, u.object.Status.Zone = u.object.Spec.Region, u.object.Spec.Zone
+
+		return
+	}
+
+	managedCluster := &ocmclv1.ManagedCluster{}
+	if err := u.reconciler.Get(u.ctx, types.NamespacedName{Name: u.object.Name}, managedCluster); err != nil {
+		u.log.Info("Region and zone claims not read", "error", err)
+
+		return
+	}
+
+	u.object.Status.Region = ramen.Region(clusterClaimOrDefault(managedCluster, regionClusterClaimName,
+		string(u.object.Spec.Region)))
+	u.object.Status.Zone = clusterClaimOrDefault(managedCluster, zoneClusterClaimName, u.object.Spec.Zone)
+}
+
+// clusterClaimOrDefault returns the value, unless empty, or the value of the cluster claim of a managed cluster
+func clusterClaimOrDefault(managedCluster *ocmclv1.ManagedCluster, claimName, value string) string {
+	if value != "" {
+		return value
+	}
+
+	for _, claim := range managedCluster.Status.ClusterClaims {
+		if claim.Name == claimName {
+			return claim.Value
+		}
+	}
+
+	return ""
+}
+
+// drClusterZone returns the zone of a DRCluster, as set in its spec, or as last reported in its status, as claimed
+// by its managed cluster, otherwise
+func drClusterZone(drcluster *ramen.DRCluster) string {
+	if drcluster.Spec.Zone != "" {
+		return drcluster.Spec.Zone
+	}
+
+	return drcluster.Status.Zone
+}
+
+// drPolicyFailureDomains returns the pairs of clusters of the DRPolicy that share a failure domain, being in the
+// same zone of a region, and the pairs in the same region whose zones are not known. Clusters in different regions
+// are in different failure domains.
+func drPolicyFailureDomains(drpolicy *ramen.DRPolicy, drclusters []ramen.DRCluster) (shared, unknown []string) {
+	members := []*ramen.DRCluster{}
+
+	for _, clusterName := range util.DRPolicyClusterNames(drpolicy) {
+		for i := range drclusters {
+			if drclusters[i].Name == clusterName {
+				members = append(members, &drclusters[i])
+			}
+		}
+	}
+
+	for i := range members {
+		for _, peer := range members[i+1:] {
+			region := util.DRClusterRegion(members[i])
+			if region != util.DRClusterRegion(peer) {
+				continue
+			}
+
+			zone, peerZone := drClusterZone(members[i]), drClusterZone(peer)
+
+			switch {
+			case zone == "" || peerZone == "":
+				unknown = append(unknown, fmt.Sprintf("%s and %s in region %s", members[i].Name, peer.Name, region))
+			case zone == peerZone:
+				shared = append(shared, fmt.Sprintf("%s and %s in zone %s of region %s", members[i].Name, peer.Name,
+					zone, region))
+			}
+		}
+	}
+
+	return shared, unknown
+}
+
+// failureDomainsCheck sets the FailureDomainsDistinct condition of the DRPolicy, and returns an error if its
+// clusters share a failure domain and the check is configured to refuse it, unless the DRPolicy was validated
+// before, so that the DRPolicies already in use are not invalidated
+func (u *drpolicyUpdater) failureDomainsCheck(drclusters *ramen.DRClusterList, ramenConfig *ramen.RamenConfig,
+) error {
+	shared, unknown := drPolicyFailureDomains(u.object, drclusters.Items)
+
+	switch {
+	case len(shared) > 0:
+		message := "Clusters share a failure domain: " + strings.Join(shared, ", ")
+
+		if err := u.statusConditionSet(ramen.DRPolicyFailureDomainsDistinct, metav1.ConditionFalse,
+			DRPolicyConditionReasonFailureDomainShared, message); err != nil {
+			return err
+		}
+
+		u.log.Info(message)
+
+		if ramenConfig.FailureDomainCheck.Mode == ramen.FailureDomainCheckRefuse &&
+			!controllerutil.ContainsFinalizer(u.object, drPolicyFinalizerName) {
+			return fmt.Errorf("%s", message)
+		}
+
+		return nil
+	case len(unknown) > 0:
+		return u.statusConditionSet(ramen.DRPolicyFailureDomainsDistinct, metav1.ConditionUnknown,
+			DRPolicyConditionReasonZoneUnknown, "Zones of clusters not known: "+strings.Join(unknown, ", "))
+	default:
+		return u.statusConditionSet(ramen.DRPolicyFailureDomainsDistinct, metav1.ConditionTrue,
+			DRPolicyConditionReasonFailureDomainsDistinct, "Clusters are in distinct failure domains")
+	}
+}
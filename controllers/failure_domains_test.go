@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the checks that the clusters of a DRPolicy are in distinct failure domains
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("FailureDomains", func() {
+	drcluster := func(name string, region ramen.Region, zone string) ramen.DRCluster {
+		return ramen.DRCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ramen.DRClusterSpec{Region: region, Zone: zone},
+		}
+	}
+	drpolicy := func() *ramen.DRPolicy {
+		return &ramen.DRPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "east-west"},
+			Spec:       ramen.DRPolicySpec{DRClusters: []string{"east", "west"}},
+		}
+	}
+
+	Describe("drPolicyFailureDomains", func() {
+		It("reports the clusters in the same zone of a region, or whose zones are not known", func() {
+			shared, unknown := drPolicyFailureDomains(drpolicy(), []ramen.DRCluster{
+				drcluster("east", "us", "dc1"), drcluster("west", "us", "dc1"),
+			})
+			Expect(shared).To(Equal([]string{"east and west in zone dc1 of region us"}))
+			Expect(unknown).To(BeEmpty())
+
+			shared, unknown = drPolicyFailureDomains(drpolicy(), []ramen.DRCluster{
+				drcluster("east", "us", "dc1"), drcluster("west", "us", ""),
+			})
+			Expect(shared).To(BeEmpty())
+			Expect(unknown).To(Equal([]string{"east and west in region us"}))
+		})
+
+		It("takes the regions and zones the managed clusters claim, unless set in the spec", func() {
+			east, west := drcluster("east", "", ""), drcluster("west", "", "")
+			east.Status.Region, east.Status.Zone = "us", "dc1"
+			west.Status.Region, west.Status.Zone = "eu", "dc1"
+
+			shared, unknown := drPolicyFailureDomains(drpolicy(), []ramen.DRCluster{east, west})
+			Expect(shared).To(BeEmpty())
+			Expect(unknown).To(BeEmpty())
+
+			west.Spec.Region = "us"
+			shared, _ = drPolicyFailureDomains(drpolicy(), []ramen.DRCluster{east, west})
+			Expect(shared).To(HaveLen(1))
+		})
+	})
+
+	It("sets the region and zone a managed cluster claims, unless set in the spec", func() {
+		scheme := runtime.NewScheme()
+		Expect(ocmclv1.AddToScheme(scheme)).To(Succeed())
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&ocmclv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "east"},
+			Status: ocmclv1.ManagedClusterStatus{ClusterClaims: []ocmclv1.ManagedClusterClaim{
+				{Name: regionClusterClaimName, Value: "us"},
+				{Name: zoneClusterClaimName, Value: "dc1"},
+			}},
+		}).Build()
+		object := drcluster("east", "", "dc2")
+		u := &drclusterInstance{
+			ctx:        context.TODO(),
+			object:     &object,
+			log:        ctrl.Log.WithName("failure-domains-test"),
+			reconciler: &DRClusterReconciler{Client: c},
+		}
+
+		u.failureDomainSet()
+		Expect(object.Status.Region).To(Equal(ramen.Region("us")))
+		Expect(object.Status.Zone).To(Equal("dc2"))
+	})
+
+	Describe("failureDomainsCheck", func() {
+		var ramenConfig *ramen.RamenConfig
+
+		check := func(policy *ramen.DRPolicy) error {
+			scheme := runtime.NewScheme()
+			Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).WithStatusSubresource(policy).Build()
+			u := &drpolicyUpdater{context.TODO(), policy, c, ctrl.Log.WithName("failure-domains-test")}
+
+			return u.failureDomainsCheck(&ramen.DRClusterList{Items: []ramen.DRCluster{
+				drcluster("east", "us", "dc1"), drcluster("west", "us", "dc1"),
+			}}, ramenConfig)
+		}
+
+		BeforeEach(func() {
+			ramenConfig = &ramen.RamenConfig{}
+			ramenConfig.FailureDomainCheck.Mode = ramen.FailureDomainCheckRefuse
+		})
+
+		It("refuses a new DRPolicy whose clusters share a failure domain", func() {
+			policy := drpolicy()
+			Expect(check(policy)).To(MatchError(ContainSubstring("share a failure domain")))
+			Expect(policy.Status.Conditions).To(ContainElement(And(
+				HaveField("Type", ramen.DRPolicyFailureDomainsDistinct),
+				HaveField("Reason", DRPolicyConditionReasonFailureDomainShared))))
+		})
+
+		It("only reports a DRPolicy validated before whose clusters share a failure domain", func() {
+			policy := drpolicy()
+			policy.Finalizers = []string{drPolicyFinalizerName}
+			Expect(check(policy)).To(Succeed())
+			Expect(policy.Status.Conditions).To(ContainElement(
+				HaveField("Status", metav1.ConditionFalse)))
+		})
+	})
+})
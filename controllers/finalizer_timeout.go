@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// ReasonDeletionTimedOut is the condition reason reported when a deletion waits on dependent resources for longer
+// than the configured finalizer timeout
+const ReasonDeletionTimedOut = "DeletionTimedOut"
+
+// finalizerTimeout returns the configured finalizer timeout, or zero if deletions wait indefinitely
+func finalizerTimeout(ramenConfig *rmn.RamenConfig) time.Duration {
+	if ramenConfig == nil || ramenConfig.FinalizerTimeout.Seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(ramenConfig.FinalizerTimeout.Seconds) * time.Second
+}
+
+// finalizerTimeoutElapsed returns true if a deletion that started at the deletion timestamp has waited for longer
+// than the configured finalizer timeout
+func finalizerTimeoutElapsed(ramenConfig *rmn.RamenConfig, deletionTimestamp *metav1.Time) bool {
+	timeout := finalizerTimeout(ramenConfig)
+
+	return timeout != 0 && deletionTimestamp != nil && time.Since(deletionTimestamp.Time) > timeout
+}
+
+// finalizerTimeoutRemaining returns the time left until the finalizer timeout elapses for a deletion that started at
+// the deletion timestamp, or zero if it elapsed or deletions wait indefinitely
+func finalizerTimeoutRemaining(ramenConfig *rmn.RamenConfig, deletionTimestamp *metav1.Time) time.Duration {
+	timeout := finalizerTimeout(ramenConfig)
+	if timeout == 0 || deletionTimestamp == nil {
+		return 0
+	}
+
+	if remaining := timeout - time.Since(deletionTimestamp.Time); remaining > 0 {
+		return remaining
+	}
+
+	return 0
+}
+
+// finalizerTimeoutRequeue requeues a deletion waiting on dependent resources once the finalizer timeout elapses, for
+// the wait to be reported or the cleanup forced without another event to trigger it
+func finalizerTimeoutRequeue(result *ctrl.Result, ramenConfig *rmn.RamenConfig, deletionTimestamp *metav1.Time) {
+	remaining := finalizerTimeoutRemaining(ramenConfig, deletionTimestamp)
+	if remaining == 0 {
+		return
+	}
+
+	// Elapse the timeout, as time.Since is exclusive of it
+	remaining += time.Second
+
+	if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+		result.RequeueAfter = remaining
+	}
+}
+
+// finalizerForceCleanup returns true if a deletion that started at the deletion timestamp is to stop waiting on
+// dependent resources, and force its cleanup
+func finalizerForceCleanup(ramenConfig *rmn.RamenConfig, deletionTimestamp *metav1.Time) bool {
+	return ramenConfig.FinalizerTimeout.ForceCleanup && finalizerTimeoutElapsed(ramenConfig, deletionTimestamp)
+}
+
+// finalizerTimeoutMessage describes a deletion waiting past the finalizer timeout, and the recourse available
+func finalizerTimeoutMessage(ramenConfig *rmn.RamenConfig, err error) string {
+	return fmt.Sprintf("Deletion waiting for longer than %v: %v. Set finalizerTimeout.forceCleanup in the"+
+		" ramen config to force the cleanup, leaving the dependent resources behind", finalizerTimeout(ramenConfig), err)
+}
+
+// deletionWait returns true if the VRG deletion is to keep waiting on a dependent resource that failed to clean up
+// with the error passed in, and until then requeues the result once the finalizer timeout elapses. Once it elapses,
+// the wait is reported in the VRG status, or if configured, the deletion is forced and reported with an event.
+func (v *VRGInstance) deletionWait(result *ctrl.Result, err error) bool {
+	if !finalizerTimeoutElapsed(v.ramenConfig, v.instance.GetDeletionTimestamp()) {
+		finalizerTimeoutRequeue(result, v.ramenConfig, v.instance.GetDeletionTimestamp())
+
+		return true
+	}
+
+	if !v.ramenConfig.FinalizerTimeout.ForceCleanup {
+		msg := finalizerTimeoutMessage(v.ramenConfig, err)
+		v.log.Info(msg)
+
+		if addOrUpdateCondition(&v.instance.Status.Conditions, VRGConditionTypeDataReady, v.instance.Generation,
+			metav1.ConditionFalse, ReasonDeletionTimedOut, msg) {
+			v.updateVRGStatus(ctrl.Result{})
+		}
+
+		return true
+	}
+
+	msg := fmt.Sprintf("Deletion forced after the finalizer timeout, leaving resources behind: %v", err)
+	v.log.Info(msg)
+	rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+		rmnutil.EventReasonDeleteForced, msg)
+
+	return false
+}
+
+// pvcsForceUnprotectVolRep releases PVCs from VolRep protection without waiting for their VolumeReplications to be
+// deleted, which are left behind
+func (v *VRGInstance) pvcsForceUnprotectVolRep(pvcs []corev1.PersistentVolumeClaim) {
+	for idx := range pvcs {
+		pvc := &pvcs[idx]
+		log := logWithPvcName(v.log, pvc)
+
+		if !containsString(pvc.Finalizers, PvcVRFinalizerProtected) {
+			continue
+		}
+
+		if err := v.preparePVCForVRDeletion(pvc, log); err != nil {
+			log.Info("PVC force unprotect failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the escalation of deletions waiting past the finalizer timeout
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("FinalizerTimeout", func() {
+	ramenConfig := func(seconds int64, forceCleanup bool) *ramen.RamenConfig {
+		ramenConfig := &ramen.RamenConfig{}
+		ramenConfig.FinalizerTimeout.Seconds = seconds
+		ramenConfig.FinalizerTimeout.ForceCleanup = forceCleanup
+
+		return ramenConfig
+	}
+	deletedAgo := func(ago time.Duration) *metav1.Time {
+		deleted := metav1.NewTime(time.Now().Add(-ago))
+
+		return &deleted
+	}
+
+	DescribeTable("finalizerTimeoutElapsed and finalizerForceCleanup",
+		func(ramenConfig *ramen.RamenConfig, deletionTimestamp *metav1.Time, elapsed, forceCleanup bool) {
+			Expect(finalizerTimeoutElapsed(ramenConfig, deletionTimestamp)).To(Equal(elapsed))
+			Expect(finalizerForceCleanup(ramenConfig, deletionTimestamp)).To(Equal(forceCleanup))
+		},
+		Entry("no timeout", ramenConfig(0, true), deletedAgo(time.Hour), false, false),
+		Entry("a negative timeout", ramenConfig(-1, true), deletedAgo(time.Hour), false, false),
+		Entry("no deletion", ramenConfig(60, true), nil, false, false),
+		Entry("a deletion within the timeout", ramenConfig(60, true), deletedAgo(time.Second), false, false),
+		Entry("a deletion past the timeout", ramenConfig(60, false), deletedAgo(time.Hour), true, false),
+		Entry("a deletion past the timeout forced", ramenConfig(60, true), deletedAgo(time.Hour), true, true),
+	)
+
+	Describe("finalizerTimeoutRequeue", func() {
+		It("requeues a deletion within the timeout once it elapses, unless requeued sooner", func() {
+			result := ctrl.Result{}
+			finalizerTimeoutRequeue(&result, ramenConfig(60, false), deletedAgo(20*time.Second))
+			Expect(result.RequeueAfter).To(BeNumerically("~", 41*time.Second, time.Second))
+
+			result = ctrl.Result{RequeueAfter: time.Second}
+			finalizerTimeoutRequeue(&result, ramenConfig(60, false), deletedAgo(20*time.Second))
+			Expect(result.RequeueAfter).To(Equal(time.Second))
+		})
+
+		It("leaves the result of a deletion past the timeout, or without one, as is", func() {
+			result := ctrl.Result{Requeue: true}
+			finalizerTimeoutRequeue(&result, ramenConfig(60, true), deletedAgo(time.Hour))
+			Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+
+			finalizerTimeoutRequeue(&result, ramenConfig(0, true), deletedAgo(time.Second))
+			Expect(result).To(Equal(ctrl.Result{Requeue: true}))
+		})
+	})
+
+	Describe("VRG deletion", func() {
+		const namespace = "app"
+
+		var (
+			c           client.Client
+			vrgInstance *VRGInstance
+		)
+
+		BeforeEach(func() {
+			c = fake.NewClientBuilder().WithObjects(
+				&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{
+					Name: "pv-data", Annotations: map[string]string{pvcVRAnnotationArchivedKey: "archived"},
+				}},
+				&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: namespace, Name: "data", Finalizers: []string{PvcVRFinalizerProtected},
+						Annotations: map[string]string{pvcVRAnnotationProtectedKey: "protected"},
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{VolumeName: "pv-data"},
+				},
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "logs"}},
+			).Build()
+			vrgInstance = vrgInstanceFake(c, "finalizer-timeout-test", ramen.VolumeReplicationGroupSpec{
+				Async: &ramen.VRGAsyncSpec{},
+			})
+			vrgInstance.reconciler.eventRecorder = rmnutil.NewEventReporter(record.NewFakeRecorder(10))
+			vrgInstance.instance.DeletionTimestamp = deletedAgo(time.Hour)
+		})
+
+		Describe("deletionWait", func() {
+			It("waits within the timeout, requeued once it elapses", func() {
+				vrgInstance.ramenConfig = ramenConfig(7200, true)
+				result := ctrl.Result{}
+
+				Expect(vrgInstance.deletionWait(&result, errors.New("not deleted"))).To(BeTrue())
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+
+			It("waits past the timeout, reported in the status, unless the cleanup is forced", func() {
+				vrgInstance.ramenConfig = ramenConfig(60, false)
+
+				Expect(vrgInstance.deletionWait(&ctrl.Result{}, errors.New("not deleted"))).To(BeTrue())
+				condition := findCondition(vrgInstance.instance.Status.Conditions, VRGConditionTypeDataReady)
+				Expect(condition).ToNot(BeNil())
+				Expect(condition.Reason).To(Equal(ReasonDeletionTimedOut))
+				Expect(condition.Message).To(ContainSubstring("not deleted"))
+
+				vrgInstance.ramenConfig.FinalizerTimeout.ForceCleanup = true
+				Expect(vrgInstance.deletionWait(&ctrl.Result{}, errors.New("not deleted"))).To(BeFalse())
+			})
+		})
+
+		Describe("pvcsForceUnprotectVolRep", func() {
+			It("releases the PVCs protected by VolRep and their PVs, and skips the others", func() {
+				pvcs := &corev1.PersistentVolumeClaimList{}
+				Expect(c.List(context.TODO(), pvcs)).To(Succeed())
+
+				vrgInstance.pvcsForceUnprotectVolRep(pvcs.Items)
+
+				pvc := &corev1.PersistentVolumeClaim{}
+				Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "data"}, pvc)).To(Succeed())
+				Expect(pvc.Finalizers).To(BeEmpty())
+				Expect(pvc.Annotations).ToNot(HaveKey(pvcVRAnnotationProtectedKey))
+
+				pv := &corev1.PersistentVolume{}
+				Expect(c.Get(context.TODO(), types.NamespacedName{Name: "pv-data"}, pv)).To(Succeed())
+				Expect(pv.Annotations).ToNot(HaveKey(pvcVRAnnotationArchivedKey))
+			})
+		})
+	})
+
+	Describe("vrgsDeletionWait", func() {
+		r := &DRPlacementControlReconciler{
+			Log:           ctrl.Log.WithName("finalizer-timeout-test"),
+			eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+		}
+		drpc := &ramen.DRPlacementControl{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"}}
+		vrgs := map[string]*ramen.VolumeReplicationGroup{"east": {}}
+
+		DescribeTable("waits while VRGs remain or may remain, unless the cleanup is forced",
+			func(vrgs map[string]*ramen.VolumeReplicationGroup, vrgsErr error, forceCleanup, wait bool) {
+				err := r.vrgsDeletionWait(drpc, vrgs, vrgsErr, forceCleanup, r.Log)
+				if wait {
+					Expect(err).To(MatchError(ContainSubstring("waiting for VRGs count to go to zero")))
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+				}
+			},
+			Entry("no VRGs", nil, nil, false, false),
+			Entry("VRGs remaining", vrgs, nil, false, true),
+			Entry("VRGs that failed to be retrieved", nil, errors.New("unreachable"), false, true),
+			Entry("VRGs remaining forced", vrgs, nil, true, false),
+			Entry("VRGs that failed to be retrieved forced", nil, errors.New("unreachable"), true, false),
+		)
+	})
+})
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HealthLabel is set on DRPlacementControl and DRPolicy resources to their status health, so that UIs may select
+// resources by health without inspecting their conditions
+const HealthLabel = "ramendr.openshift.io/health"
+
+const healthNextStepNone = "No action required"
+
+// setDRPCHealth summarizes the DRPC conditions and progression into status health and next step
+func setDRPCHealth(drpc *rmn.DRPlacementControl) {
+	drpc.Status.Health, drpc.Status.NextStep = drpcHealth(drpc)
+}
+
+//nolint:cyclop
+func drpcHealth(drpc *rmn.DRPlacementControl) (rmn.Health, string) {
+	available := meta.FindStatusCondition(drpc.Status.Conditions, rmn.ConditionAvailable)
+	peerReady := meta.FindStatusCondition(drpc.Status.Conditions, rmn.ConditionPeerReady)
+	protected := meta.FindStatusCondition(drpc.Status.Conditions, rmn.ConditionProtected)
+
+	switch {
+	case available == nil || available.Status == metav1.ConditionUnknown:
+		return rmn.HealthUnknown, "Wait for the workload placement to be reconciled"
+	case available.Status == metav1.ConditionFalse && available.Reason != rmn.ReasonProgressing:
+		return rmn.HealthDegraded, fmt.Sprintf("Resolve the workload availability failure: %s", available.Message)
+	case available.Status == metav1.ConditionFalse:
+		return rmn.HealthProgressing, drpcProgressingNextStep(drpc)
+	}
+
+	switch {
+	case protected == nil || protected.Status == metav1.ConditionUnknown:
+		msg := "Wait for the workload protection status to be reported"
+		if protected != nil {
+			msg = fmt.Sprintf("Check access to the workload cluster: %s", protected.Message)
+		}
+
+		return rmn.HealthUnknown, msg
+	case protected.Status == metav1.ConditionFalse && protected.Reason == rmn.ReasonProtectedError:
+		return rmn.HealthDegraded, fmt.Sprintf("Resolve the workload protection failure: %s", protected.Message)
+	case protected.Status == metav1.ConditionFalse:
+		return rmn.HealthProgressing, fmt.Sprintf("Wait for the workload to be protected: %s", protected.Message)
+	}
+
+	if peerReady == nil || peerReady.Status != metav1.ConditionTrue {
+		return rmn.HealthProgressing, "Wait for the peer cluster to be ready before the next failover or relocate"
+	}
+
+	if drpc.Status.Progression != "" && drpc.Status.Progression != rmn.ProgressionCompleted {
+		return rmn.HealthProgressing, drpcProgressingNextStep(drpc)
+	}
+
+	return rmn.HealthHealthy, healthNextStepNone
+}
+
+func drpcProgressingNextStep(drpc *rmn.DRPlacementControl) string {
+	if drpc.Status.Progression == rmn.ProgressionWaitOnUserToCleanUp {
+		return "Remove the workload from the cluster it was relocated from"
+	}
+
+	if drpc.Spec.Action == "" {
+		return fmt.Sprintf("Wait for deployment to complete, currently %s", drpc.Status.Progression)
+	}
+
+	return fmt.Sprintf("Wait for %s to complete, currently %s", drpc.Spec.Action, drpc.Status.Progression)
+}
+
+// setDRPolicyHealth summarizes the DRPolicy conditions into status health and next step
+func setDRPolicyHealth(drpolicy *rmn.DRPolicy) {
+	validated := meta.FindStatusCondition(drpolicy.Status.Conditions, rmn.DRPolicyValidated)
+
+	switch {
+	case validated == nil || validated.Status == metav1.ConditionUnknown:
+		drpolicy.Status.Health = rmn.HealthUnknown
+		drpolicy.Status.NextStep = "Wait for the DRPolicy to be validated"
+	case validated.Status == metav1.ConditionFalse:
+		drpolicy.Status.Health = rmn.HealthDegraded
+		drpolicy.Status.NextStep = fmt.Sprintf("Resolve the DRPolicy validation failure: %s", validated.Message)
+	default:
+		drpolicy.Status.Health = rmn.HealthHealthy
+		drpolicy.Status.NextStep = healthNextStepNone
+	}
+}
+
+// healthLabelUpdate sets the health label on the object, if it differs from the health passed in
+func healthLabelUpdate(ctx context.Context, c client.Client, obj client.Object, health rmn.Health) error {
+	if health == "" || obj.GetLabels()[HealthLabel] == string(health) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[HealthLabel] = string(health)
+	obj.SetLabels(labels)
+
+	if err := c.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to update %s label of %s (%w)", HealthLabel, obj.GetName(), err)
+	}
+
+	return nil
+}
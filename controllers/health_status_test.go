@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the health summarized from the status of DRPCs and DRPolicies
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("HealthStatus", func() {
+	condition := func(conditionType string, status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: "detail"}
+	}
+	available := condition(rmn.ConditionAvailable, metav1.ConditionTrue, rmn.ReasonSuccess)
+	protected := condition(rmn.ConditionProtected, metav1.ConditionTrue, rmn.ReasonSuccess)
+	peerReady := condition(rmn.ConditionPeerReady, metav1.ConditionTrue, rmn.ReasonSuccess)
+
+	DescribeTable("drpcHealth",
+		func(action rmn.DRAction, progression rmn.ProgressionStatus, conditions []metav1.Condition,
+			health rmn.Health, nextStep string,
+		) {
+			drpc := &rmn.DRPlacementControl{
+				Spec:   rmn.DRPlacementControlSpec{Action: action},
+				Status: rmn.DRPlacementControlStatus{Progression: progression, Conditions: conditions},
+			}
+
+			actualHealth, actualNextStep := drpcHealth(drpc)
+			Expect(actualHealth).To(Equal(health))
+			Expect(actualNextStep).To(Equal(nextStep))
+		},
+		Entry("no conditions", rmn.DRAction(""), rmn.ProgressionStatus(""), nil,
+			rmn.HealthUnknown, "Wait for the workload placement to be reconciled"),
+		Entry("availability unknown", rmn.DRAction(""), rmn.ProgressionStatus(""), []metav1.Condition{
+			condition(rmn.ConditionAvailable, metav1.ConditionUnknown, rmn.ReasonProgressing),
+		}, rmn.HealthUnknown, "Wait for the workload placement to be reconciled"),
+		Entry("unavailable", rmn.ActionFailover, rmn.ProgressionStatus(""), []metav1.Condition{
+			condition(rmn.ConditionAvailable, metav1.ConditionFalse, rmn.ReasonNotStarted),
+		}, rmn.HealthDegraded, "Resolve the workload availability failure: detail"),
+		Entry("becoming available while failing over", rmn.ActionFailover, rmn.ProgressionFailingOverToCluster,
+			[]metav1.Condition{condition(rmn.ConditionAvailable, metav1.ConditionFalse, rmn.ReasonProgressing)},
+			rmn.HealthProgressing, "Wait for Failover to complete, currently FailingOverToCluster"),
+		Entry("becoming available while deploying", rmn.DRAction(""), rmn.ProgressionCreatingMW,
+			[]metav1.Condition{condition(rmn.ConditionAvailable, metav1.ConditionFalse, rmn.ReasonProgressing)},
+			rmn.HealthProgressing, "Wait for deployment to complete, currently CreatingMW"),
+		Entry("protection not reported", rmn.DRAction(""), rmn.ProgressionCompleted,
+			[]metav1.Condition{available}, rmn.HealthUnknown, "Wait for the workload protection status to be reported"),
+		Entry("protection unknown", rmn.DRAction(""), rmn.ProgressionCompleted, []metav1.Condition{
+			available, condition(rmn.ConditionProtected, metav1.ConditionUnknown, rmn.ReasonProtectedUnknown),
+		}, rmn.HealthUnknown, "Check access to the workload cluster: detail"),
+		Entry("protection failed", rmn.DRAction(""), rmn.ProgressionCompleted, []metav1.Condition{
+			available, condition(rmn.ConditionProtected, metav1.ConditionFalse, rmn.ReasonProtectedError),
+		}, rmn.HealthDegraded, "Resolve the workload protection failure: detail"),
+		Entry("protection in progress", rmn.DRAction(""), rmn.ProgressionCompleted, []metav1.Condition{
+			available, condition(rmn.ConditionProtected, metav1.ConditionFalse, rmn.ReasonProtectedProgressing),
+		}, rmn.HealthProgressing, "Wait for the workload to be protected: detail"),
+		Entry("peer not ready", rmn.DRAction(""), rmn.ProgressionCompleted, []metav1.Condition{
+			available, protected, condition(rmn.ConditionPeerReady, metav1.ConditionFalse, rmn.ReasonProgressing),
+		}, rmn.HealthProgressing, "Wait for the peer cluster to be ready before the next failover or relocate"),
+		Entry("waiting on the user to clean up", rmn.ActionRelocate, rmn.ProgressionWaitOnUserToCleanUp,
+			[]metav1.Condition{available, protected, peerReady},
+			rmn.HealthProgressing, "Remove the workload from the cluster it was relocated from"),
+		Entry("relocating", rmn.ActionRelocate, rmn.ProgressionRunningFinalSync,
+			[]metav1.Condition{available, protected, peerReady},
+			rmn.HealthProgressing, "Wait for Relocate to complete, currently RunningFinalSync"),
+		Entry("completed", rmn.ActionRelocate, rmn.ProgressionCompleted,
+			[]metav1.Condition{available, protected, peerReady}, rmn.HealthHealthy, healthNextStepNone),
+		Entry("deployed", rmn.DRAction(""), rmn.ProgressionStatus(""),
+			[]metav1.Condition{available, protected, peerReady}, rmn.HealthHealthy, healthNextStepNone),
+	)
+
+	DescribeTable("setDRPolicyHealth",
+		func(conditions []metav1.Condition, health rmn.Health, nextStep string) {
+			drpolicy := &rmn.DRPolicy{Status: rmn.DRPolicyStatus{Conditions: conditions}}
+
+			setDRPolicyHealth(drpolicy)
+			Expect(drpolicy.Status.Health).To(Equal(health))
+			Expect(drpolicy.Status.NextStep).To(Equal(nextStep))
+		},
+		Entry("not validated", nil, rmn.HealthUnknown, "Wait for the DRPolicy to be validated"),
+		Entry("validation unknown", []metav1.Condition{
+			condition(rmn.DRPolicyValidated, metav1.ConditionUnknown, rmn.ReasonProgressing),
+		}, rmn.HealthUnknown, "Wait for the DRPolicy to be validated"),
+		Entry("invalid", []metav1.Condition{
+			condition(rmn.DRPolicyValidated, metav1.ConditionFalse, "ValidationFailed"),
+		}, rmn.HealthDegraded, "Resolve the DRPolicy validation failure: detail"),
+		Entry("validated", []metav1.Condition{
+			condition(rmn.DRPolicyValidated, metav1.ConditionTrue, "Succeeded"),
+		}, rmn.HealthHealthy, healthNextStepNone),
+	)
+
+	Describe("healthLabelUpdate", func() {
+		var (
+			c        client.Client
+			drpolicy *rmn.DRPolicy
+			patches  int
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+			drpolicy = &rmn.DRPolicy{ObjectMeta: metav1.ObjectMeta{
+				Name: "policy", Labels: map[string]string{"app": "web"},
+			}}
+			patches = 0
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(drpolicy).WithInterceptorFuncs(
+				interceptor.Funcs{Patch: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					patch client.Patch, opts ...client.PatchOption,
+				) error {
+					patches++
+
+					return c.Patch(ctx, obj, patch, opts...)
+				}},
+			).Build()
+			Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(drpolicy), drpolicy)).To(Succeed())
+		})
+
+		It("labels the object with its health, and patches it only once the health changes", func() {
+			Expect(healthLabelUpdate(context.TODO(), c, drpolicy, rmn.HealthDegraded)).To(Succeed())
+			Expect(healthLabelUpdate(context.TODO(), c, drpolicy, rmn.HealthDegraded)).To(Succeed())
+			Expect(patches).To(Equal(1))
+
+			Expect(healthLabelUpdate(context.TODO(), c, drpolicy, rmn.HealthHealthy)).To(Succeed())
+			Expect(patches).To(Equal(2))
+
+			Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(drpolicy), drpolicy)).To(Succeed())
+			Expect(drpolicy.Labels).To(Equal(map[string]string{"app": "web", HealthLabel: string(rmn.HealthHealthy)}))
+		})
+
+		It("leaves the object unlabeled without a health", func() {
+			Expect(healthLabelUpdate(context.TODO(), c, drpolicy, "")).To(Succeed())
+			Expect(patches).To(BeZero())
+			Expect(drpolicy.Labels).ToNot(HaveKey(HealthLabel))
+		})
+	})
+})
@@ -61,6 +61,10 @@ type RecoverSpec struct {
 	RestoreStatus *velero.RestoreStatusSpec `json:"restoreStatus,omitempty"`
 	//+optional
 	ExistingResourcePolicy velero.PolicyType `json:"existingResourcePolicy,omitempty"`
+
+	// Name of a resource modifier in the request namespace
+	//+optional
+	ResourceModifierName string `json:"resourceModifierName,omitempty"`
 }
 
 type Spec struct {
@@ -73,6 +77,25 @@ type Spec struct {
 
 	//+optional
 	IncludeClusterResources *bool `json:"includeClusterResources,omitempty"`
+
+	//+optional
+	VolumesSpec `json:",inline"`
+}
+
+// VolumesSpec specifies how a protect request backs up volume data and how long it is kept
+type VolumesSpec struct {
+	//+optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	//+optional
+	SnapshotVolumes *bool `json:"snapshotVolumes,omitempty"`
+
+	//+optional
+	DefaultVolumesToFsBackup *bool `json:"defaultVolumesToFsBackup,omitempty"`
+
+	// Name of a resource policy in the request namespace
+	//+optional
+	ResourcePolicyName string `json:"resourcePolicyName,omitempty"`
 }
 
 type KubeResourcesSpec struct {
@@ -103,6 +126,56 @@ type HookSpec struct {
 
 	//+optional
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	HookRun `json:",inline"`
+}
+
+// HookRun is how a hook runs, if not by running its command in the pods it selects
+type HookRun struct {
+	// Job is the job a hook of type job runs
+	//+optional
+	Job *HookJobSpec `json:"job,omitempty"`
+
+	// HTTP is the endpoint a hook of type http calls
+	//+optional
+	HTTP *HookHTTPSpec `json:"http,omitempty"`
+}
+
+type HookJobSpec struct {
+	// HookName is the name of the hook whose operation is named by the hook spec
+	HookName string `json:"hookName"`
+
+	Namespace string `json:"namespace"`
+
+	Image string `json:"image"`
+
+	// OnErrorContinue continues the workflow when the job fails, rather than failing it
+	//+optional
+	OnErrorContinue bool `json:"onErrorContinue,omitempty"`
+}
+
+type HookHTTPSpec struct {
+	// HookName is the name of the hook whose operation is named by the hook spec
+	HookName string `json:"hookName"`
+
+	URL string `json:"url"`
+
+	// SecretName is the name of the secret, in the VRG's namespace, of the token, or username and password, and
+	// CA bundle to call the endpoint with
+	//+optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Retries is how many more times a failed call is made before the hook fails
+	//+optional
+	Retries int32 `json:"retries,omitempty"`
+
+	// ExpectedStatusCodes are the status codes of a successful call, or any 2xx status code if empty
+	//+optional
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+
+	// OnErrorContinue continues the workflow when the call fails, rather than failing it
+	//+optional
+	OnErrorContinue bool `json:"onErrorContinue,omitempty"`
 }
 
 func RequestProcessingErrorCreate(s string) RequestProcessingError { return RequestProcessingError{s} }
@@ -112,6 +185,12 @@ func (RequestProcessingError) Is(err error) bool                   { return true
 type RequestsManager interface {
 	ProtectsPath() string
 	RecoversPath() string
+	// ProtectedResourcesListKey returns the key, relative to ProtectsPath, of the list of the resources captured by a
+	// protect request, a map of group/version/Kind to namespace/name of each object
+	ProtectedResourcesListKey(protectRequestName string) string
+	// ProtectedResourcesArchiveKey returns the key, relative to ProtectsPath, of the archive of the resources
+	// captured by a protect request, a gzipped tar of the JSON of each object
+	ProtectedResourcesArchiveKey(protectRequestName string) string
 	ProtectRequestNew() ProtectRequest
 	RecoverRequestNew() RecoverRequest
 	ProtectRequestCreate(
@@ -21,6 +21,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -61,6 +63,14 @@ type RequestsManager struct{}
 func (RequestsManager) ProtectsPath() string { return protectsPath }
 func (RequestsManager) RecoversPath() string { return recoversPath }
 
+func (RequestsManager) ProtectedResourcesListKey(protectRequestName string) string {
+	return protectRequestName + "/" + protectRequestName + "-resource-list.json.gz"
+}
+
+func (RequestsManager) ProtectedResourcesArchiveKey(protectRequestName string) string {
+	return protectRequestName + "/" + protectRequestName + ".tar.gz"
+}
+
 func (RequestsManager) ProtectRequestNew() kubeobjects.ProtectRequest {
 	return BackupRequest{&velero.Backup{TypeMeta: backupTypeMeta()}}
 }
@@ -205,6 +215,7 @@ func backupDummyCreateAndRestore(
 			w, s3Url, s3BucketName, s3RegionName, s3KeyPrefix, secretKeyRef,
 			caCertificates,
 			backupSpecDummy(),
+			"",
 			requestNamespaceName, backupName,
 			labels,
 			annotations,
@@ -265,7 +276,7 @@ func backupRestore(
 	labels map[string]string,
 ) (*velero.Restore, error) {
 	restore := restore(backup.Namespace, restoreName, recoverSpec, backup.Name, labels)
-	if err := w.objectCreate(restore); err != nil {
+	if err := w.restoreCreate(restore, recoverSpec.ResourceModifierName); err != nil {
 		return nil, err
 	}
 
@@ -359,6 +370,7 @@ func backupRealCreate(
 		w, s3Url, s3BucketName, s3RegionName, s3KeyPrefix, secretKeyRef,
 		caCertificates,
 		getBackupSpecFromObjectsSpec(objectsSpec),
+		objectsSpec.ResourcePolicyName,
 		requestNamespaceName, captureName,
 		labels,
 		annotations,
@@ -374,11 +386,12 @@ func getBackupSpecFromObjectsSpec(objectsSpec kubeobjects.Spec) velero.BackupSpe
 			"replicationsources.volsync.backube", "replicationdestinations.volsync.backube"),
 		LabelSelector:           objectsSpec.LabelSelector,
 		OrLabelSelectors:        objectsSpec.OrLabelSelectors,
-		TTL:                     metav1.Duration{}, // TODO: set default here
+		TTL:                     dereferenceOrZeroValueIfNil(objectsSpec.TTL),
 		IncludeClusterResources: objectsSpec.IncludeClusterResources,
 		Hooks:                   getBackupHooks(objectsSpec.KubeResourcesSpec.Hooks),
 		VolumeSnapshotLocations: []string{},
-		DefaultVolumesToRestic:  new(bool),
+		SnapshotVolumes:         objectsSpec.SnapshotVolumes,
+		DefaultVolumesToRestic:  falseIfNil(objectsSpec.DefaultVolumesToFsBackup),
 		OrderedResources:        map[string]string{},
 	}
 }
@@ -416,6 +429,14 @@ func dereferenceOrZeroValueIfNil[T any](pointer *T) (t T) {
 	return *pointer
 }
 
+func falseIfNil(pointer *bool) *bool {
+	if pointer == nil {
+		return new(bool)
+	}
+
+	return pointer
+}
+
 func backupRealStatusProcess(
 	backup *velero.Backup,
 	log logr.Logger,
@@ -480,6 +501,7 @@ func backupRequestCreate(
 	secretKeyRef *corev1.SecretKeySelector,
 	caCertificates []byte,
 	backupSpec velero.BackupSpec,
+	resourcePolicyName string,
 	requestsNamespaceName string,
 	requestName string,
 	labels map[string]string,
@@ -495,10 +517,64 @@ func backupRequestCreate(
 	}
 
 	backupSpec.StorageLocation = requestName
-	backupSpec.SnapshotVolumes = new(bool)
+	backupSpec.SnapshotVolumes = falseIfNil(backupSpec.SnapshotVolumes)
 	backupRequest := backupRequest(requestsNamespaceName, requestName, backupSpec, labels, annotations)
 
-	return backupLocation, backupRequest, w.Create(w.ctx, backupRequest)
+	return backupLocation, backupRequest, w.backupCreate(backupRequest, resourcePolicyName)
+}
+
+// backupCreate creates a backup referencing a resource policy, if named, by its config map's name. As resource
+// policies are newer than the Velero API this is built with, the reference is set in the unstructured backup.
+func (w objectWriter) backupCreate(backup *velero.Backup, resourcePolicyName string) error {
+	if resourcePolicyName == "" {
+		return w.Create(w.ctx, backup)
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(backup)
+	if err != nil {
+		return pkgerrors.Wrap(err, "backup to unstructured")
+	}
+
+	if err := unstructured.SetNestedStringMap(object, map[string]string{
+		"kind": "configmap",
+		"name": resourcePolicyName,
+	}, "spec", "resourcePolicy"); err != nil {
+		return pkgerrors.Wrap(err, "backup resource policy set")
+	}
+
+	backupUnstructured := &unstructured.Unstructured{Object: object}
+	if err := w.Create(w.ctx, backupUnstructured); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(backupUnstructured.Object, backup)
+}
+
+// restoreCreate creates a restore referencing a resource modifier, if named, by its config map's name. As resource
+// modifiers are newer than the Velero API this is built with, the reference is set in the unstructured restore.
+func (w objectWriter) restoreCreate(restore *velero.Restore, resourceModifierName string) error {
+	if resourceModifierName == "" {
+		return w.objectCreate(restore)
+	}
+
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(restore)
+	if err != nil {
+		return pkgerrors.Wrap(err, "restore to unstructured")
+	}
+
+	if err := unstructured.SetNestedStringMap(object, map[string]string{
+		"kind": "configmap",
+		"name": resourceModifierName,
+	}, "spec", "resourceModifier"); err != nil {
+		return pkgerrors.Wrap(err, "restore resource modifier set")
+	}
+
+	restoreUnstructured := &unstructured.Unstructured{Object: object}
+	if err := w.objectCreate(restoreUnstructured); err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(restoreUnstructured.Object, restore)
 }
 
 func (w objectWriter) backupObjectsDelete(
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8sleaderelection "k8s.io/client-go/tools/leaderelection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	leaderElectionLeaseDurationDefault = 15 * time.Second
+	leaderElectionRenewDeadlineDefault = 10 * time.Second
+	leaderElectionRetryPeriodDefault   = 2 * time.Second
+)
+
+// LeaderElectionGroupControllers are the names of the hub controllers that may be in a leader election group
+var LeaderElectionGroupControllers = []string{
+	"DRPlacementControl",
+	"DRPolicy",
+	"DRCluster",
+	"MaintenanceRelocate",
+	"DRBulkAction",
+	"DRPCJanitor",
+}
+
+// LeaderElectionGroups runs the controllers of each configured group under a lease of its own, instead of the
+// lease of the manager, so that the replicas of the operator share the controllers between them. As with the lease
+// of the manager, the loss of the lease of a group stops the manager, and with it all the controllers of the replica,
+// for the replica to restart and stand for the leases again.
+type LeaderElectionGroups struct {
+	mgr    ctrl.Manager
+	groups map[string]*leaderElectionGroup
+	unused map[string]struct{}
+}
+
+// NewLeaderElectionGroups adds a runnable, to the manager, per group, that elects its leader and starts the
+// runnables of its controllers on it. The groups are ignored if the manager does not elect a leader.
+func NewLeaderElectionGroups(mgr ctrl.Manager, options *ctrl.Options, groups []rmn.LeaderElectionGroup,
+	log logr.Logger,
+) (*LeaderElectionGroups, error) {
+	g := &LeaderElectionGroups{
+		mgr:    mgr,
+		groups: map[string]*leaderElectionGroup{},
+		unused: map[string]struct{}{},
+	}
+
+	if !options.LeaderElection {
+		return g, nil
+	}
+
+	for _, group := range groups {
+		leaderElectionGroup := &leaderElectionGroup{
+			name:    group.Name,
+			options: options,
+			mgr:     mgr,
+			log:     log.WithValues("group", group.Name),
+		}
+
+		if err := mgr.Add(leaderElectionGroup); err != nil {
+			return nil, fmt.Errorf("leader election group %s add failed, %w", group.Name, err)
+		}
+
+		for _, controller := range group.Controllers {
+			g.groups[controller] = leaderElectionGroup
+			g.unused[controller] = struct{}{}
+		}
+	}
+
+	return g, nil
+}
+
+// Manager returns the manager to set up a controller with: one that adds its runnables to the controller's group,
+// if it is in one, or the manager otherwise
+func (g *LeaderElectionGroups) Manager(controller string) ctrl.Manager {
+	group, ok := g.groups[controller]
+	if !ok {
+		return g.mgr
+	}
+
+	delete(g.unused, controller)
+
+	return &leaderElectionGroupManager{Manager: g.mgr, group: group}
+}
+
+// Unused returns the names of the controllers of the groups that were not set up with their manager
+func (g *LeaderElectionGroups) Unused() []string {
+	unused := []string{}
+
+	for _, controller := range LeaderElectionGroupControllers {
+		if _, ok := g.unused[controller]; ok {
+			unused = append(unused, controller)
+		}
+	}
+
+	return unused
+}
+
+// leaderElectionGroupManager is a manager whose runnables are added to a leader election group
+type leaderElectionGroupManager struct {
+	ctrl.Manager
+	group *leaderElectionGroup
+}
+
+// Add adds the runnable to the group, to start once the group's lease is acquired
+func (m *leaderElectionGroupManager) Add(runnable manager.Runnable) error {
+	m.group.add(runnable)
+
+	return nil
+}
+
+// leaderElectionGroup is a runnable that holds the lease of a group, and runs the group's runnables while it does
+type leaderElectionGroup struct {
+	name    string
+	options *ctrl.Options
+	mgr     ctrl.Manager
+	log     logr.Logger
+
+	runnables []manager.Runnable
+	// runnablesCtx is the context of the runnables while the lease is held, nil otherwise
+	runnablesCtx   context.Context
+	runnablesErrs  chan error
+	runnablesWait  sync.WaitGroup
+	runnablesMutex sync.Mutex
+}
+
+// add adds a runnable to the group, and starts it if the group's lease is held
+func (g *leaderElectionGroup) add(runnable manager.Runnable) {
+	g.runnablesMutex.Lock()
+	defer g.runnablesMutex.Unlock()
+
+	g.runnables = append(g.runnables, runnable)
+
+	if g.runnablesCtx != nil {
+		g.runnableStart(runnable)
+	}
+}
+
+// runnableStart starts a runnable, and reports the error it returns, if any. The caller holds the runnables mutex.
+func (g *leaderElectionGroup) runnableStart(runnable manager.Runnable) {
+	ctx, errs := g.runnablesCtx, g.runnablesErrs
+
+	g.runnablesWait.Add(1)
+
+	go func() {
+		defer g.runnablesWait.Done()
+
+		if err := runnable.Start(ctx); err != nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}()
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. The group elects its own leader.
+func (g *leaderElectionGroup) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It returns an error, to stop the manager, if the lease is lost or a runnable
+// fails.
+func (g *leaderElectionGroup) Start(ctx context.Context) error {
+	lock, err := leaderelection.NewResourceLock(g.mgr.GetConfig(), g.mgr, leaderelection.Options{
+		LeaderElection:             true,
+		LeaderElectionResourceLock: g.options.LeaderElectionResourceLock,
+		LeaderElectionNamespace:    g.options.LeaderElectionNamespace,
+		LeaderElectionID:           g.options.LeaderElectionID + "-" + g.name,
+	})
+	if err != nil {
+		return fmt.Errorf("leader election group %s lock create failed, %w", g.name, err)
+	}
+
+	electorCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	runnablesErr := make(chan error, 1)
+
+	elector, err := k8sleaderelection.NewLeaderElector(k8sleaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   durationPointerOrDefault(g.options.LeaseDuration, leaderElectionLeaseDurationDefault),
+		RenewDeadline:   durationPointerOrDefault(g.options.RenewDeadline, leaderElectionRenewDeadlineDefault),
+		RetryPeriod:     durationPointerOrDefault(g.options.RetryPeriod, leaderElectionRetryPeriodDefault),
+		ReleaseOnCancel: g.options.LeaderElectionReleaseOnCancel,
+		Name:            lock.Identity(),
+		Callbacks: k8sleaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				g.log.Info("Lease acquired, starting runnables", "lease", lock.Describe())
+
+				if err := g.runnablesStart(ctx); err != nil {
+					runnablesErr <- err
+
+					cancel()
+				}
+			},
+			OnStoppedLeading: func() {
+				g.log.Info("Lease released", "lease", lock.Describe())
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("leader election group %s elector create failed, %w", g.name, err)
+	}
+
+	elector.Run(electorCtx)
+
+	select {
+	case err := <-runnablesErr:
+		return fmt.Errorf("leader election group %s runnable failed, %w", g.name, err)
+	default:
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return fmt.Errorf("leader election group %s lease lost", g.name)
+}
+
+// runnablesStart starts the runnables of the group, and those added to it until the context is done, and returns
+// once they all return after it is, or on the first error
+func (g *leaderElectionGroup) runnablesStart(ctx context.Context) error {
+	errs := make(chan error, 1)
+
+	g.runnablesMutex.Lock()
+	g.runnablesCtx, g.runnablesErrs = ctx, errs
+
+	for _, runnable := range g.runnables {
+		g.runnableStart(runnable)
+	}
+
+	g.runnablesMutex.Unlock()
+
+	var err error
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+	}
+
+	g.runnablesMutex.Lock()
+	g.runnablesCtx, g.runnablesErrs = nil, nil
+	g.runnablesMutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	g.runnablesWait.Wait()
+
+	select {
+	case err = <-errs:
+	default:
+	}
+
+	return err
+}
+
+func durationPointerOrDefault(duration *time.Duration, defaultDuration time.Duration) time.Duration {
+	if duration == nil {
+		return defaultDuration
+	}
+
+	return *duration
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the leader election groups of the hub controllers
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// runnablesManager is a manager that only keeps the runnables added to it
+type runnablesManager struct {
+	ctrl.Manager
+	runnables []manager.Runnable
+}
+
+func (m *runnablesManager) Add(runnable manager.Runnable) error {
+	m.runnables = append(m.runnables, runnable)
+
+	return nil
+}
+
+// startedRunnable reports its start, and runs until its context is done, or returns its error
+type startedRunnable struct {
+	started chan struct{}
+	err     error
+}
+
+func (r *startedRunnable) Start(ctx context.Context) error {
+	close(r.started)
+
+	if r.err != nil {
+		return r.err
+	}
+
+	<-ctx.Done()
+
+	return nil
+}
+
+var _ = Describe("LeaderElectionGroups", func() {
+	var mgr *runnablesManager
+
+	log := ctrl.Log.WithName("leader-election-groups-test")
+	groups := []rmn.LeaderElectionGroup{
+		{Name: "placement", Controllers: []string{"DRPlacementControl", "DRPCJanitor"}},
+		{Name: "clusters", Controllers: []string{"DRCluster"}},
+	}
+	runnable := func(err error) *startedRunnable {
+		return &startedRunnable{started: make(chan struct{}), err: err}
+	}
+
+	BeforeEach(func() {
+		mgr = &runnablesManager{}
+	})
+
+	Describe("Manager and Unused", func() {
+		It("adds the runnables of the controllers of a group to the group, and of the others to the manager", func() {
+			g, err := NewLeaderElectionGroups(mgr, &ctrl.Options{LeaderElection: true}, groups, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mgr.runnables).To(HaveLen(2))
+			Expect(g.Unused()).To(Equal([]string{"DRPlacementControl", "DRCluster", "DRPCJanitor"}))
+
+			Expect(g.Manager("DRPolicy")).To(BeIdenticalTo(mgr))
+			Expect(g.Manager("DRPlacementControl").Add(runnable(nil))).To(Succeed())
+			Expect(g.Manager("DRCluster").Add(runnable(nil))).To(Succeed())
+
+			Expect(mgr.runnables).To(HaveLen(2))
+			Expect(g.groups["DRPlacementControl"].runnables).To(HaveLen(1))
+			Expect(g.groups["DRCluster"].runnables).To(HaveLen(1))
+			Expect(g.Unused()).To(Equal([]string{"DRPCJanitor"}))
+		})
+
+		It("ignores the groups without leader election by the manager", func() {
+			g, err := NewLeaderElectionGroups(mgr, &ctrl.Options{}, groups, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mgr.runnables).To(BeEmpty())
+			Expect(g.Manager("DRPlacementControl")).To(BeIdenticalTo(mgr))
+			Expect(g.Unused()).To(BeEmpty())
+		})
+	})
+
+	Describe("runnablesStart", func() {
+		var group *leaderElectionGroup
+
+		BeforeEach(func() {
+			group = &leaderElectionGroup{name: "placement", mgr: mgr, log: log}
+		})
+
+		It("runs the runnables, including those added once started, until the lease is lost", func() {
+			before, after := runnable(nil), runnable(nil)
+			group.add(before)
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			done := make(chan error)
+
+			go func() { done <- group.runnablesStart(ctx) }()
+
+			Eventually(before.started).Should(BeClosed())
+			group.add(after)
+			Eventually(after.started).Should(BeClosed())
+			Consistently(done).ShouldNot(Receive())
+
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+
+			late := runnable(nil)
+			group.add(late)
+			Consistently(late.started).ShouldNot(BeClosed())
+		})
+
+		It("returns the error of a runnable", func() {
+			group.add(runnable(nil))
+			group.add(runnable(errors.New("failed")))
+
+			ctx, cancel := context.WithCancel(context.TODO())
+			defer cancel()
+
+			Expect(group.runnablesStart(ctx)).To(MatchError("failed"))
+		})
+	})
+})
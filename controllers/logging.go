@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	uberzap "go.uber.org/zap"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// Keys of the values logged by the hub and cluster operators
+const (
+	logKeyDRPC          = "drpc"
+	logKeyVRG           = "vrg"
+	logKeyCluster       = "cluster"
+	logKeyAction        = "action"
+	logKeyStep          = "step"
+	logKeyCorrelationID = "correlationID"
+)
+
+// DRPCCorrelationIDAnnotation is set on a VRG to the correlation ID of the action of its DRPC, to log it along with
+// the cluster operator's reconciles of the action
+const DRPCCorrelationIDAnnotation = "drplacementcontrol.ramendr.openshift.io/correlation-id"
+
+// actionCorrelationID returns the ID that correlates the logs of the hub and cluster operators for the current action
+// of a DRPC, or an empty string if no action started. It is derived from the start time of the action, so it is the
+// same across reconciles and operator restarts.
+func actionCorrelationID(drpc *rmn.DRPlacementControl) string {
+	if drpc.Status.ActionStartTime == nil {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(string(drpc.UID) + "/" + drpc.Status.ActionStartTime.UTC().String()))
+
+	return hex.EncodeToString(hash[:8])
+}
+
+// logSwitch is the level and encoder of the operator's logs, which its configuration changes without a restart
+type logSwitch struct {
+	level        uberzap.AtomicLevel
+	encoder      atomic.Pointer[zapcore.Encoder]
+	startupLevel zapcore.Level
+	startupOpts  zap.Options
+	development  bool
+	out          zapcore.WriteSyncer
+}
+
+var operatorLogSwitch *logSwitch
+
+// LogCore wraps the core of the operator's logger, built of its command line options, to log at the level of its
+// configuration and, if it configures one, with its encoder. The core writes the entries as is otherwise.
+func LogCore(core zapcore.Core, opts *zap.Options) zapcore.Core {
+	s := &logSwitch{
+		level:       uberzap.NewAtomicLevel(),
+		startupOpts: *opts,
+		development: opts.Development,
+		out:         zapcore.Lock(os.Stderr),
+	}
+
+	if opts.DestWriter != nil {
+		s.out = zapcore.AddSync(opts.DestWriter)
+	}
+
+	s.startupLevel = zapcore.InfoLevel
+	if opts.Development {
+		s.startupLevel = zapcore.DebugLevel
+	}
+
+	switch level := opts.Level.(type) {
+	case uberzap.AtomicLevel:
+		s.startupLevel = level.Level()
+	case zapcore.Level:
+		s.startupLevel = level
+	}
+
+	s.level.SetLevel(s.startupLevel)
+	operatorLogSwitch = s
+
+	return &switchedCore{logSwitch: s, core: core}
+}
+
+func (s *logSwitch) newEncoder(encoding string, opts *zap.Options) zapcore.Encoder {
+	encoderConfigOptions := append([]zap.EncoderConfigOption{}, opts.EncoderConfigOptions...)
+	if opts.TimeEncoder != nil {
+		encoderConfigOptions = append(encoderConfigOptions, func(ec *zapcore.EncoderConfig) {
+			ec.EncodeTime = opts.TimeEncoder
+		})
+	}
+
+	encoderOpts := &zap.Options{}
+
+	if encoding == "console" {
+		zap.ConsoleEncoder(encoderConfigOptions...)(encoderOpts)
+	} else {
+		zap.JSONEncoder(encoderConfigOptions...)(encoderOpts)
+	}
+
+	return encoderOpts.Encoder
+}
+
+// logLevelParse parses a log level of the configuration, as the --zap-log-level flag does
+func logLevelParse(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+
+	verbosity, err := strconv.Atoi(level)
+	if err != nil || verbosity < 0 {
+		return 0, fmt.Errorf("log level %q is not one of debug, info, error, or a non-negative integer", level)
+	}
+
+	return zapcore.Level(-verbosity), nil
+}
+
+func logConfigValidate(logConfig *rmn.LogConfig) error {
+	if logConfig == nil {
+		return nil
+	}
+
+	if logConfig.Level != "" {
+		if _, err := logLevelParse(logConfig.Level); err != nil {
+			return err
+		}
+	}
+
+	switch logConfig.Encoder {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("log encoder %q is not one of json, console", logConfig.Encoder)
+	}
+
+	return nil
+}
+
+// logConfigApply sets the level and encoder of the operator's logs to those of its configuration, or to those of
+// its command line options for those not configured. Invalid values are ignored, having failed validation.
+func logConfigApply(logConfig *rmn.LogConfig) {
+	s := operatorLogSwitch
+	if s == nil {
+		return
+	}
+
+	if logConfig == nil {
+		logConfig = &rmn.LogConfig{}
+	}
+
+	level := s.startupLevel
+	if parsed, err := logLevelParse(logConfig.Level); logConfig.Level != "" && err == nil {
+		level = parsed
+	}
+
+	var encoder *zapcore.Encoder
+
+	if logConfig.Encoder == "json" || logConfig.Encoder == "console" {
+		configured := s.newEncoder(logConfig.Encoder, &s.startupOpts)
+		encoder = &configured
+	}
+
+	s.level.SetLevel(level)
+	s.encoder.Store(encoder)
+}
+
+// switchedCore is a zap core that logs at the current level of its switch, and writes with the core it wraps unless
+// the switch has an encoder
+type switchedCore struct {
+	*logSwitch
+	core   zapcore.Core
+	fields []zapcore.Field
+	// encoded is the encoder of the switch with the fields added, kept for as long as the switch has the encoder
+	encoded atomic.Pointer[switchedEncoder]
+}
+
+type switchedEncoder struct {
+	from    *zapcore.Encoder
+	encoder zapcore.Encoder
+}
+
+func (c *switchedCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+func (c *switchedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &switchedCore{
+		logSwitch: c.logSwitch,
+		core:      c.core.With(fields),
+		fields:    append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *switchedCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+
+	return checked
+}
+
+func (c *switchedCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	from := c.encoder.Load()
+	if from == nil {
+		return c.core.Write(entry, fields)
+	}
+
+	encoded := c.encoded.Load()
+	if encoded == nil || encoded.from != from {
+		encoder := (*from).Clone()
+		for i := range c.fields {
+			c.fields[i].AddTo(encoder)
+		}
+
+		encoded = &switchedEncoder{from: from, encoder: &zap.KubeAwareEncoder{Encoder: encoder, Verbose: c.development}}
+		c.encoded.Store(encoded)
+	}
+
+	buffer, err := encoded.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+
+	defer buffer.Free()
+
+	if _, err := c.out.Write(buffer.Bytes()); err != nil {
+		return err
+	}
+
+	if entry.Level > zapcore.ErrorLevel {
+		return c.out.Sync()
+	}
+
+	return nil
+}
+
+func (c *switchedCore) Sync() error {
+	if err := c.core.Sync(); err != nil {
+		return err
+	}
+
+	return c.out.Sync()
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the configuration of the operator's logs
+package controllers //nolint: testpackage
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	uberzap "go.uber.org/zap"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("Logging", func() {
+	DescribeTable("logLevelParse",
+		func(level string, expected zapcore.Level) {
+			Expect(logLevelParse(level)).To(Equal(expected))
+		},
+		Entry("debug", "debug", zapcore.DebugLevel),
+		Entry("info", "info", zapcore.InfoLevel),
+		Entry("error", "error", zapcore.ErrorLevel),
+		Entry("a verbosity", "3", zapcore.Level(-3)),
+		Entry("no verbosity", "0", zapcore.InfoLevel),
+	)
+
+	DescribeTable("logConfigValidate",
+		func(logConfig *rmn.LogConfig, expected string) {
+			err := logConfigValidate(logConfig)
+			if expected == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expected)))
+			}
+		},
+		Entry("no configuration", nil, ""),
+		Entry("no level nor encoder", &rmn.LogConfig{}, ""),
+		Entry("a level and an encoder", &rmn.LogConfig{Level: "2", Encoder: "console"}, ""),
+		Entry("an unknown level", &rmn.LogConfig{Level: "warn"}, `log level "warn"`),
+		Entry("a negative verbosity", &rmn.LogConfig{Level: "-1"}, `log level "-1"`),
+		Entry("an unknown encoder", &rmn.LogConfig{Encoder: "text"}, `log encoder "text"`),
+	)
+
+	Describe("actionCorrelationID", func() {
+		drpc := func(uid string, started *metav1.Time) *rmn.DRPlacementControl {
+			return &rmn.DRPlacementControl{
+				ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)},
+				Status:     rmn.DRPlacementControlStatus{ActionStartTime: started},
+			}
+		}
+		started := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+		startedLater := metav1.NewTime(started.Add(time.Minute))
+
+		It("is empty until an action starts", func() {
+			Expect(actionCorrelationID(drpc("a", nil))).To(BeEmpty())
+		})
+
+		It("is the same for an action, and differs across actions and DRPCs", func() {
+			id := actionCorrelationID(drpc("a", &started))
+			Expect(id).To(HaveLen(16))
+			Expect(actionCorrelationID(drpc("a", &started))).To(Equal(id))
+			Expect(actionCorrelationID(drpc("a", &startedLater))).ToNot(Equal(id))
+			Expect(actionCorrelationID(drpc("b", &started))).ToNot(Equal(id))
+		})
+	})
+
+	Describe("LogCore", func() {
+		var (
+			observed *observer.ObservedLogs
+			out      *bytes.Buffer
+			logger   *uberzap.Logger
+		)
+
+		BeforeEach(func() {
+			previous := operatorLogSwitch
+			DeferCleanup(func() { operatorLogSwitch = previous })
+
+			var core zapcore.Core
+
+			core, observed = observer.New(zapcore.DebugLevel)
+			out = &bytes.Buffer{}
+			logger = uberzap.New(LogCore(core, &zap.Options{DestWriter: out})).With(uberzap.String("drpc", "app/drpc"))
+		})
+
+		It("writes with the core it wraps, at the level of the configuration", func() {
+			logger.Debug("hidden")
+			logger.Info("shown")
+
+			logConfigApply(&rmn.LogConfig{Level: "debug"})
+			logger.Debug("debug")
+
+			Expect(observed.All()).To(HaveLen(2))
+			Expect(observed.All()[0].Message).To(Equal("shown"))
+			Expect(observed.All()[0].ContextMap()).To(HaveKeyWithValue("drpc", "app/drpc"))
+			Expect(observed.All()[1].Message).To(Equal("debug"))
+			Expect(out.Len()).To(BeZero())
+		})
+
+		It("writes with the encoder of the configuration, until it no longer has one", func() {
+			logConfigApply(&rmn.LogConfig{Encoder: "json"})
+			logger.Info("first")
+			logger.Info("second")
+
+			Expect(observed.All()).To(BeEmpty())
+			Expect(out.String()).To(ContainSubstring(`"msg":"first","drpc":"app/drpc"`))
+			Expect(out.String()).To(ContainSubstring(`"msg":"second","drpc":"app/drpc"`))
+
+			logConfigApply(nil)
+			logger.Info("third")
+
+			Expect(observed.All()).To(HaveLen(1))
+			Expect(out.String()).ToNot(ContainSubstring("third"))
+		})
+	})
+})
@@ -0,0 +1,414 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// maintenanceRelocateLeadTimeDefault is how long before a maintenance window its DRPCs start being relocated
+	maintenanceRelocateLeadTimeDefault = time.Hour
+
+	// maintenanceRelocatePollInterval is how often relocations in progress are checked, besides on DRPC updates
+	maintenanceRelocatePollInterval = time.Minute
+)
+
+// MaintenanceRelocateReconciler relocates the DRPCs of a MaintenanceRelocate off its cluster before its window,
+// holds them off the cluster during the window, and relocates them back after it
+type MaintenanceRelocateReconciler struct {
+	client.Client
+	APIReader     client.Reader
+	Log           logr.Logger
+	eventRecorder *rmnutil.EventReporter
+}
+
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=maintenancerelocates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=ramendr.openshift.io,resources=maintenancerelocates/status,verbs=get;update;patch
+
+func (r *MaintenanceRelocateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("MaintenanceRelocate", req.NamespacedName.Name, "rid", uuid.New())
+
+	mr := &rmn.MaintenanceRelocate{}
+	if err := r.APIReader.Get(ctx, req.NamespacedName, mr); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !mr.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status := mr.Status.DeepCopy()
+
+	if mr.Status.ObservedGeneration != mr.Generation && maintenanceRelocatePlannable(mr.Status.Phase) {
+		r.plan(ctx, mr, log)
+	}
+
+	requeueAfter := r.advance(ctx, mr, time.Now(), log)
+
+	mr.Status.Summary = maintenanceRelocateSummary(mr.Status.DRPCs)
+
+	if !reflect.DeepEqual(status, &mr.Status) {
+		now := metav1.Now()
+		mr.Status.LastUpdateTime = &now
+
+		if err := r.Status().Update(ctx, mr); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update MaintenanceRelocate %s status (%w)", mr.Name, err)
+		}
+
+		log.Info("Status updated", "phase", mr.Status.Phase, "summary", mr.Status.Summary)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// maintenanceRelocatePlannable returns true if no DRPC was relocated yet, so that the relocations may be planned
+// again for a changed spec
+func maintenanceRelocatePlannable(phase rmn.MaintenanceRelocatePhase) bool {
+	return phase == "" || phase == rmn.MaintenanceRelocatePlanned || phase == rmn.MaintenanceRelocatePending
+}
+
+// plan sets the target cluster of each DRPC running on the cluster under maintenance to the peer cluster of its
+// DRPolicy, and skips the rest
+func (r *MaintenanceRelocateReconciler) plan(ctx context.Context, mr *rmn.MaintenanceRelocate, log logr.Logger) {
+	planned := map[rmn.DRPCReference]bool{}
+	mr.Status.DRPCs = make([]rmn.MaintenanceRelocateDRPCStatus, 0, len(mr.Spec.DRPCs))
+
+	for _, ref := range mr.Spec.DRPCs {
+		if planned[ref] {
+			continue
+		}
+
+		planned[ref] = true
+
+		mr.Status.DRPCs = append(mr.Status.DRPCs, r.planDRPC(ctx, mr.Spec.Cluster, ref, log))
+	}
+
+	mr.Status.ObservedGeneration = mr.Generation
+	mr.Status.Phase = rmn.MaintenanceRelocatePending
+
+	if mr.Spec.DryRun {
+		mr.Status.Phase = rmn.MaintenanceRelocatePlanned
+	}
+}
+
+func (r *MaintenanceRelocateReconciler) planDRPC(ctx context.Context, cluster string, ref rmn.DRPCReference,
+	log logr.Logger,
+) rmn.MaintenanceRelocateDRPCStatus {
+	entry := rmn.MaintenanceRelocateDRPCStatus{DRPCReference: ref, State: rmn.MaintenanceRelocateDRPCPending}
+
+	drpc, err := drpcReferenceGet(ctx, r.APIReader, ref)
+	if err != nil {
+		entry.State, entry.Message = drpcReferenceGetFailed(err, rmn.MaintenanceRelocateDRPCSkipped,
+			rmn.MaintenanceRelocateDRPCFailed)
+
+		return entry
+	}
+
+	if current := drpc.Status.PreferredDecision.ClusterName; current != cluster {
+		entry.State = rmn.MaintenanceRelocateDRPCSkipped
+		entry.Message = fmt.Sprintf("DRPC is running on cluster %q", current)
+
+		return entry
+	}
+
+	drPolicy, err := GetDRPolicy(ctx, r.Client, drpc, log)
+	if err != nil {
+		entry.State = rmn.MaintenanceRelocateDRPCFailed
+		entry.Message = fmt.Sprintf("failed to get DRPolicy %s: %v", drpc.Spec.DRPolicyRef.Name, err)
+
+		return entry
+	}
+
+	for _, peer := range rmnutil.DRPolicyClusterNames(drPolicy) {
+		if peer != cluster {
+			entry.TargetCluster = peer
+
+			return entry
+		}
+	}
+
+	entry.State = rmn.MaintenanceRelocateDRPCFailed
+	entry.Message = fmt.Sprintf("DRPolicy %s has no peer cluster of cluster %s", drPolicy.Name, cluster)
+
+	return entry
+}
+
+// advance moves the DRPCs, and the phase, along by the time relative to the window, and returns when to check on
+// them next
+func (r *MaintenanceRelocateReconciler) advance(ctx context.Context, mr *rmn.MaintenanceRelocate, now time.Time,
+	log logr.Logger,
+) time.Duration {
+	if mr.Spec.DryRun {
+		mr.Status.Phase = rmn.MaintenanceRelocatePlanned
+
+		return 0
+	}
+
+	leadTime := maintenanceRelocateLeadTimeDefault
+	if mr.Spec.LeadTime != nil {
+		leadTime = mr.Spec.LeadTime.Duration
+	}
+
+	evacuateTime := mr.Spec.WindowStart.Add(-leadTime)
+
+	switch {
+	case now.Before(evacuateTime):
+		mr.Status.Phase = rmn.MaintenanceRelocatePending
+
+		return evacuateTime.Sub(now)
+	case now.Before(mr.Spec.WindowEnd.Time) && mr.Status.Phase != rmn.MaintenanceRelocateReturning &&
+		mr.Status.Phase != rmn.MaintenanceRelocateCompleted:
+		if r.evacuate(ctx, mr, log) {
+			mr.Status.Phase = rmn.MaintenanceRelocateHolding
+
+			return mr.Spec.WindowEnd.Sub(now)
+		}
+
+		mr.Status.Phase = rmn.MaintenanceRelocateEvacuating
+
+		return maintenanceRelocatePollInterval
+	default:
+		if r.returnBack(ctx, mr, log) {
+			mr.Status.Phase = rmn.MaintenanceRelocateCompleted
+
+			return 0
+		}
+
+		mr.Status.Phase = rmn.MaintenanceRelocateReturning
+
+		return maintenanceRelocatePollInterval
+	}
+}
+
+// evacuate relocates the DRPCs off the cluster, and returns true once none is left to relocate
+func (r *MaintenanceRelocateReconciler) evacuate(ctx context.Context, mr *rmn.MaintenanceRelocate,
+	log logr.Logger,
+) bool {
+	done := true
+
+	for i := range mr.Status.DRPCs {
+		entry := &mr.Status.DRPCs[i]
+
+		switch entry.State {
+		case rmn.MaintenanceRelocateDRPCPending:
+			r.relocate(ctx, mr, entry, entry.TargetCluster, rmn.MaintenanceRelocateDRPCRelocating, log)
+		case rmn.MaintenanceRelocateDRPCRelocating:
+			r.relocateCheck(ctx, entry, entry.TargetCluster, rmn.MaintenanceRelocateDRPCRelocated)
+		default:
+			continue
+		}
+
+		if entry.State == rmn.MaintenanceRelocateDRPCPending || entry.State == rmn.MaintenanceRelocateDRPCRelocating {
+			done = false
+		}
+	}
+
+	return done
+}
+
+// returnBack relocates the DRPCs relocated off the cluster back to it, and returns true once none is left to
+// relocate. DRPCs whose relocation off the cluster did not start before the window ended are skipped.
+func (r *MaintenanceRelocateReconciler) returnBack(ctx context.Context, mr *rmn.MaintenanceRelocate,
+	log logr.Logger,
+) bool {
+	done := true
+
+	for i := range mr.Status.DRPCs {
+		entry := &mr.Status.DRPCs[i]
+
+		switch entry.State {
+		case rmn.MaintenanceRelocateDRPCPending:
+			entry.State = rmn.MaintenanceRelocateDRPCSkipped
+			entry.Message = "window ended before the DRPC was relocated"
+		case rmn.MaintenanceRelocateDRPCRelocating:
+			r.relocateCheck(ctx, entry, entry.TargetCluster, rmn.MaintenanceRelocateDRPCRelocated)
+		}
+
+		switch entry.State {
+		case rmn.MaintenanceRelocateDRPCRelocated:
+			r.relocate(ctx, mr, entry, mr.Spec.Cluster, rmn.MaintenanceRelocateDRPCReturning, log)
+		case rmn.MaintenanceRelocateDRPCReturning:
+			r.relocateCheck(ctx, entry, mr.Spec.Cluster, rmn.MaintenanceRelocateDRPCReturned)
+		}
+
+		switch entry.State {
+		case rmn.MaintenanceRelocateDRPCRelocating, rmn.MaintenanceRelocateDRPCRelocated,
+			rmn.MaintenanceRelocateDRPCReturning:
+			done = false
+		}
+	}
+
+	return done
+}
+
+// relocate requests the relocation of a DRPC to a cluster, once the DRPC completed the action in progress, and
+// moves it to the relocating state
+func (r *MaintenanceRelocateReconciler) relocate(ctx context.Context, mr *rmn.MaintenanceRelocate,
+	entry *rmn.MaintenanceRelocateDRPCStatus, cluster string, relocating rmn.MaintenanceRelocateDRPCState,
+	log logr.Logger,
+) {
+	drpc, err := drpcReferenceGet(ctx, r.APIReader, entry.DRPCReference)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			entry.State, entry.Message = drpcReferenceGetFailed(err, rmn.MaintenanceRelocateDRPCSkipped,
+				rmn.MaintenanceRelocateDRPCFailed)
+		}
+
+		return
+	}
+
+	if !drpcActionCompleted(drpc) {
+		entry.Message = fmt.Sprintf("waiting on DRPC to complete its action, phase %s progression %s",
+			drpc.Status.Phase, drpc.Status.Progression)
+
+		return
+	}
+
+	if err := drpcActionLimited(ctx, r.APIReader, drpc, rmn.ActionRelocate); err != nil {
+		entry.State = rmn.MaintenanceRelocateDRPCFailed
+		entry.Message = err.Error()
+
+		return
+	}
+
+	requested, err := drpcActionRequest(ctx, r.Client, drpc, rmn.ActionRelocate, cluster)
+	if err != nil {
+		entry.Message = err.Error()
+
+		return
+	}
+
+	if requested {
+		log.Info("DRPC relocation requested", "drpc", entry.Name, "namespace", entry.Namespace, "cluster", cluster)
+		rmnutil.ReportIfNotPresent(r.eventRecorder, drpc, corev1.EventTypeNormal,
+			rmnutil.EventReasonMaintenanceRelocate, fmt.Sprintf("Relocating to cluster %s for the maintenance "+
+				"of cluster %s by MaintenanceRelocate %s", cluster, mr.Spec.Cluster, mr.Name))
+	}
+
+	entry.State = relocating
+	entry.Message = ""
+}
+
+// relocateCheck moves a DRPC to the relocated state once it completed its relocation to a cluster, and to the
+// failed state if its action was changed meanwhile
+func (r *MaintenanceRelocateReconciler) relocateCheck(ctx context.Context, entry *rmn.MaintenanceRelocateDRPCStatus,
+	cluster string, relocated rmn.MaintenanceRelocateDRPCState,
+) {
+	drpc, err := drpcReferenceGet(ctx, r.APIReader, entry.DRPCReference)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			entry.State, entry.Message = drpcReferenceGetFailed(err, rmn.MaintenanceRelocateDRPCSkipped,
+				rmn.MaintenanceRelocateDRPCFailed)
+		}
+
+		return
+	}
+
+	completed, err := drpcActionCompletedTo(drpc, rmn.ActionRelocate, cluster)
+	if err != nil {
+		entry.State = rmn.MaintenanceRelocateDRPCFailed
+		entry.Message = err.Error()
+
+		return
+	}
+
+	if !completed {
+		return
+	}
+
+	now := metav1.Now()
+	entry.State = relocated
+
+	if relocated == rmn.MaintenanceRelocateDRPCReturned {
+		entry.ReturnedTime = &now
+	} else {
+		entry.RelocatedTime = &now
+	}
+}
+
+func maintenanceRelocateSummary(entries []rmn.MaintenanceRelocateDRPCStatus) rmn.MaintenanceRelocateSummary {
+	summary := rmn.MaintenanceRelocateSummary{}
+
+	for i := range entries {
+		switch entries[i].State {
+		case rmn.MaintenanceRelocateDRPCPending:
+			summary.Pending++
+		case rmn.MaintenanceRelocateDRPCRelocating:
+			summary.Relocating++
+		case rmn.MaintenanceRelocateDRPCRelocated:
+			summary.Relocated++
+		case rmn.MaintenanceRelocateDRPCReturning:
+			summary.Returning++
+		case rmn.MaintenanceRelocateDRPCReturned:
+			summary.Returned++
+		case rmn.MaintenanceRelocateDRPCSkipped:
+			summary.Skipped++
+		case rmn.MaintenanceRelocateDRPCFailed:
+			summary.Failed++
+		}
+	}
+
+	return summary
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *MaintenanceRelocateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("maintenancerelocate"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rmn.MaintenanceRelocate{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&rmn.DRPlacementControl{},
+			handler.EnqueueRequestsFromMapFunc(r.drpcMapFunc),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// drpcMapFunc returns the MaintenanceRelocates referring to the DRPC
+func (r *MaintenanceRelocateReconciler) drpcMapFunc(ctx context.Context, drpc client.Object) []reconcile.Request {
+	mrs := &rmn.MaintenanceRelocateList{}
+	if err := r.Client.List(ctx, mrs); err != nil {
+		r.Log.Info("Failed to list MaintenanceRelocates", "error", err)
+
+		return []reconcile.Request{}
+	}
+
+	ref := rmn.DRPCReference{Namespace: drpc.GetNamespace(), Name: drpc.GetName()}
+	requests := []reconcile.Request{}
+
+	for i := range mrs.Items {
+		for _, mrRef := range mrs.Items[i].Spec.DRPCs {
+			if mrRef == ref {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name: mrs.Items[i].Name,
+				}})
+
+				break
+			}
+		}
+	}
+
+	return requests
+}
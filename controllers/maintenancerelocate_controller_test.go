@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the relocations of DRPCs off a cluster for its maintenance, and back
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// drpcOn returns a DRPC that completed its deployment to a cluster
+func drpcOn(name, cluster string, labels map[string]string) *rmn.DRPlacementControl {
+	return &rmn.DRPlacementControl{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: labels},
+		Spec: rmn.DRPlacementControlSpec{
+			DRPolicyRef:      corev1.ObjectReference{Name: "east-west"},
+			PreferredCluster: cluster,
+		},
+		Status: rmn.DRPlacementControlStatus{
+			Phase:             rmn.Deployed,
+			Progression:       rmn.ProgressionCompleted,
+			PreferredDecision: rmn.PlacementDecision{ClusterName: cluster},
+		},
+	}
+}
+
+// drpcActionComplete completes the action of a DRPC on the cluster its spec requests
+func drpcActionComplete(c client.Client, name string) {
+	drpc := &rmn.DRPlacementControl{}
+	Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, drpc)).To(Succeed())
+
+	cluster, phase := drpcActionCluster(drpc, drpc.Spec.Action)
+	drpc.Status.Phase = phase
+	drpc.Status.Progression = rmn.ProgressionCompleted
+	drpc.Status.PreferredDecision.ClusterName = cluster
+	drpc.Status.ObservedGeneration = drpc.Generation
+	Expect(c.Update(context.TODO(), drpc)).To(Succeed())
+}
+
+func drpcActionTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(rmn.AddToScheme(scheme)).To(Succeed())
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, &rmn.DRPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "east-west"},
+		Spec:       rmn.DRPolicySpec{DRClusters: []string{"east", "west"}},
+	})...).Build()
+}
+
+var _ = Describe("MaintenanceRelocate", func() {
+	var (
+		c     client.Client
+		r     *MaintenanceRelocateReconciler
+		mr    *rmn.MaintenanceRelocate
+		start time.Time
+	)
+
+	states := func() []rmn.MaintenanceRelocateDRPCState {
+		states := []rmn.MaintenanceRelocateDRPCState{}
+		for _, entry := range mr.Status.DRPCs {
+			states = append(states, entry.State)
+		}
+
+		return states
+	}
+	drpcSpec := func(name string) rmn.DRPlacementControlSpec {
+		drpc := &rmn.DRPlacementControl{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, drpc)).To(Succeed())
+
+		return drpc.Spec
+	}
+	advance := func(now time.Time) rmn.MaintenanceRelocatePhase {
+		r.advance(context.TODO(), mr, now, r.Log)
+
+		return mr.Status.Phase
+	}
+
+	BeforeEach(func() {
+		c = drpcActionTestClient(drpcOn("on-east", "east", nil), drpcOn("on-west", "west", nil))
+		r = &MaintenanceRelocateReconciler{
+			Client:        c,
+			APIReader:     c,
+			Log:           ctrl.Log.WithName("maintenancerelocate-test"),
+			eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+		}
+		start = time.Now().Add(2 * time.Hour)
+		mr = &rmn.MaintenanceRelocate{
+			ObjectMeta: metav1.ObjectMeta{Name: "east-maintenance", Generation: 1},
+			Spec: rmn.MaintenanceRelocateSpec{
+				Cluster: "east",
+				DRPCs: []rmn.DRPCReference{
+					{Namespace: "app", Name: "on-east"},
+					{Namespace: "app", Name: "on-west"},
+					{Namespace: "app", Name: "absent"},
+					{Namespace: "app", Name: "on-east"},
+				},
+				WindowStart: metav1.NewTime(start),
+				WindowEnd:   metav1.NewTime(start.Add(time.Hour)),
+			},
+		}
+		r.plan(context.TODO(), mr, r.Log)
+	})
+
+	It("plans to relocate the DRPCs running on the cluster to their peer cluster once each", func() {
+		Expect(mr.Status.Phase).To(Equal(rmn.MaintenanceRelocatePending))
+		Expect(mr.Status.DRPCs).To(HaveLen(3))
+		Expect(mr.Status.DRPCs[0].TargetCluster).To(Equal("west"))
+		Expect(states()).To(Equal([]rmn.MaintenanceRelocateDRPCState{
+			rmn.MaintenanceRelocateDRPCPending, rmn.MaintenanceRelocateDRPCSkipped, rmn.MaintenanceRelocateDRPCSkipped,
+		}))
+	})
+
+	It("relocates no DRPC for a dry run", func() {
+		mr.Spec.DryRun = true
+		Expect(advance(start)).To(Equal(rmn.MaintenanceRelocatePlanned))
+		Expect(drpcSpec("on-east").Action).To(BeEmpty())
+	})
+
+	It("relocates the DRPCs off the cluster before the window, and back after it", func() {
+		Expect(advance(start.Add(-2 * time.Hour))).To(Equal(rmn.MaintenanceRelocatePending))
+		Expect(drpcSpec("on-east").Action).To(BeEmpty())
+
+		Expect(advance(start.Add(-time.Minute))).To(Equal(rmn.MaintenanceRelocateEvacuating))
+		Expect(drpcSpec("on-east")).To(HaveField("PreferredCluster", "west"))
+		Expect(drpcSpec("on-east")).To(HaveField("Action", rmn.ActionRelocate))
+
+		drpcActionComplete(c, "on-east")
+		Expect(advance(start)).To(Equal(rmn.MaintenanceRelocateHolding))
+		Expect(mr.Status.DRPCs[0].State).To(Equal(rmn.MaintenanceRelocateDRPCRelocated))
+
+		Expect(advance(start.Add(2 * time.Hour))).To(Equal(rmn.MaintenanceRelocateReturning))
+		Expect(drpcSpec("on-east")).To(HaveField("PreferredCluster", "east"))
+
+		drpcActionComplete(c, "on-east")
+		Expect(advance(start.Add(2 * time.Hour))).To(Equal(rmn.MaintenanceRelocateCompleted))
+		Expect(mr.Status.DRPCs[0].State).To(Equal(rmn.MaintenanceRelocateDRPCReturned))
+	})
+
+	It("fails a DRPC whose action is changed during its relocation", func() {
+		Expect(advance(start)).To(Equal(rmn.MaintenanceRelocateEvacuating))
+
+		drpc := &rmn.DRPlacementControl{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: "on-east"}, drpc)).To(Succeed())
+		drpc.Spec.Action = rmn.ActionFailover
+		Expect(c.Update(context.TODO(), drpc)).To(Succeed())
+
+		Expect(advance(start)).To(Equal(rmn.MaintenanceRelocateHolding))
+		Expect(mr.Status.DRPCs[0].State).To(Equal(rmn.MaintenanceRelocateDRPCFailed))
+		Expect(mr.Status.DRPCs[0].Message).To(ContainSubstring("Failover"))
+	})
+
+	It("skips the DRPCs not relocated off the cluster before the window ended", func() {
+		Expect(advance(start.Add(2 * time.Hour))).To(Equal(rmn.MaintenanceRelocateCompleted))
+		Expect(mr.Status.DRPCs[0].State).To(Equal(rmn.MaintenanceRelocateDRPCSkipped))
+		Expect(drpcSpec("on-east").Action).To(BeEmpty())
+	})
+})
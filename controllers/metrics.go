@@ -22,6 +22,17 @@ const (
 	LastSyncDurationSeconds  = "last_sync_duration_seconds"
 	LastSyncDataBytes        = "last_sync_data_bytes"
 	WorkloadProtectionStatus = "workload_protection_status"
+	SyncStatus               = "sync_status"
+)
+
+const (
+	DRClusterOperatorVersionSkew = "drcluster_operator_version_skew"
+)
+
+const (
+	DRClusterS3SecretsExpected        = "drcluster_s3_secrets_expected"
+	DRClusterS3SecretsPresent         = "drcluster_s3_secrets_present"
+	S3SecretDistributionFailuresTotal = "s3_secret_distribution_failures_total"
 )
 
 type SyncTimeMetrics struct {
@@ -40,10 +51,23 @@ type SyncDataBytesMetrics struct {
 	LastSyncDataBytes prometheus.Gauge
 }
 
+type SyncStatusMetrics struct {
+	SyncStatus prometheus.Gauge
+}
+
 type WorkloadProtectionMetrics struct {
 	WorkloadProtectionStatus prometheus.Gauge
 }
 
+type DRClusterVersionSkewMetrics struct {
+	VersionSkew prometheus.Gauge
+}
+
+type DRClusterS3SecretsMetrics struct {
+	S3SecretsExpected prometheus.Gauge
+	S3SecretsPresent  prometheus.Gauge
+}
+
 type SyncMetrics struct {
 	SyncTimeMetrics
 	SyncDurationMetrics
@@ -85,11 +109,28 @@ var (
 		SchedulingInterval, // Value from DRPolicy
 	}
 
+	syncStatusMetricLabels = []string{
+		ObjType,      // Name of the type of the resource [drpc]
+		ObjName,      // Name of the resoure [drpc-name]
+		ObjNamespace, // DRPC namespace
+		Policyname,   // DRPolicy name
+	}
+
 	workloadProtectionStatusLabels = []string{
 		ObjType,      // Name of the type of the resource [drpc]
 		ObjName,      // Name of the resoure [drpc-name]
 		ObjNamespace, // DRPC namespace
 	}
+
+	drClusterVersionSkewMetricLabels = []string{
+		ObjType, // Name of the type of the resource [drcluster]
+		ObjName, // Name of the resource [drcluster-name]
+	}
+
+	drClusterS3SecretsMetricLabels = []string{
+		ObjType, // Name of the type of the resource [drcluster]
+		ObjName, // Name of the resource [drcluster-name]
+	}
 )
 
 var (
@@ -129,6 +170,15 @@ var (
 		syncDataBytesMetricLabels,
 	)
 
+	syncStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      SyncStatus,
+			Namespace: metricNamespace,
+			Help:      "Age of the last group sync against the sync thresholds: -1 unknown, 0 on time, 1 lagging, 2 critical",
+		},
+		syncStatusMetricLabels,
+	)
+
 	workloadProtectionStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name:      WorkloadProtectionStatus,
@@ -137,6 +187,42 @@ var (
 		},
 		workloadProtectionStatusLabels,
 	)
+
+	drClusterVersionSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      DRClusterOperatorVersionSkew,
+			Namespace: metricNamespace,
+			Help:      "Version of the dr-cluster operator relative to the hub operator's: -1 older, 0 same, 1 newer",
+		},
+		drClusterVersionSkewMetricLabels,
+	)
+
+	drClusterS3SecretsExpected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      DRClusterS3SecretsExpected,
+			Namespace: metricNamespace,
+			Help:      "Number of s3 secrets the DRPolicies of a DRCluster require on its cluster",
+		},
+		drClusterS3SecretsMetricLabels,
+	)
+
+	drClusterS3SecretsPresent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name:      DRClusterS3SecretsPresent,
+			Namespace: metricNamespace,
+			Help:      "Number of the s3 secrets expected on the cluster of a DRCluster that are present on it",
+		},
+		drClusterS3SecretsMetricLabels,
+	)
+
+	s3SecretDistributionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:      S3SecretDistributionFailuresTotal,
+			Namespace: metricNamespace,
+			Help:      "Total number of failures to distribute s3 secrets to, or remove them from, a DRCluster",
+		},
+		drClusterS3SecretsMetricLabels,
+	)
 )
 
 // lastSyncTime metrics reports value from lastGrpupSyncTime taken from DRPC status
@@ -215,6 +301,26 @@ func DeleteSyncDataBytesMetric(labels prometheus.Labels) bool {
 	return lastSyncDataBytes.Delete(labels)
 }
 
+// syncStatus Metric reports the DataSynced condition of the DRPC status
+func SyncStatusMetricLabels(drPolicy *rmn.DRPolicy, drpc *rmn.DRPlacementControl) prometheus.Labels {
+	return prometheus.Labels{
+		ObjType:      "DRPlacementControl",
+		ObjName:      drpc.Name,
+		ObjNamespace: drpc.Namespace,
+		Policyname:   drPolicy.Name,
+	}
+}
+
+func NewSyncStatusMetric(labels prometheus.Labels) SyncStatusMetrics {
+	return SyncStatusMetrics{
+		SyncStatus: syncStatus.With(labels),
+	}
+}
+
+func DeleteSyncStatusMetric(labels prometheus.Labels) bool {
+	return syncStatus.Delete(labels)
+}
+
 // workloadProtectionStatus Metric reports information regarding workload protection condition from DRPC
 func WorkloadProtectionStatusLabels(drpc *rmn.DRPlacementControl) prometheus.Labels {
 	return prometheus.Labels{
@@ -234,11 +340,62 @@ func DeleteWorkloadProtectionStatusMetric(labels prometheus.Labels) bool {
 	return workloadProtectionStatus.Delete(labels)
 }
 
+// drClusterVersionSkew Metric reports the version skew of a DRCluster's operator, from the DRCluster status
+func DRClusterVersionSkewMetricLabels(drCluster *rmn.DRCluster) prometheus.Labels {
+	return prometheus.Labels{
+		ObjType: "DRCluster",
+		ObjName: drCluster.Name,
+	}
+}
+
+func NewDRClusterVersionSkewMetric(labels prometheus.Labels) DRClusterVersionSkewMetrics {
+	return DRClusterVersionSkewMetrics{
+		VersionSkew: drClusterVersionSkew.With(labels),
+	}
+}
+
+func DeleteDRClusterVersionSkewMetric(labels prometheus.Labels) bool {
+	return drClusterVersionSkew.Delete(labels)
+}
+
+// drClusterS3Secrets Metrics report the s3 secrets expected and present on the cluster of a DRCluster, and the
+// failures to distribute them, when s3 secret distribution is enabled
+func DRClusterS3SecretsMetricLabels(clusterName string) prometheus.Labels {
+	return prometheus.Labels{
+		ObjType: "DRCluster",
+		ObjName: clusterName,
+	}
+}
+
+func NewDRClusterS3SecretsMetrics(labels prometheus.Labels) DRClusterS3SecretsMetrics {
+	return DRClusterS3SecretsMetrics{
+		S3SecretsExpected: drClusterS3SecretsExpected.With(labels),
+		S3SecretsPresent:  drClusterS3SecretsPresent.With(labels),
+	}
+}
+
+func DeleteDRClusterS3SecretsMetrics(labels prometheus.Labels) bool {
+	expectedDeleted := drClusterS3SecretsExpected.Delete(labels)
+	presentDeleted := drClusterS3SecretsPresent.Delete(labels)
+	failuresDeleted := s3SecretDistributionFailures.Delete(labels)
+
+	return expectedDeleted || presentDeleted || failuresDeleted
+}
+
+func S3SecretDistributionFailure(labels prometheus.Labels) {
+	s3SecretDistributionFailures.With(labels).Inc()
+}
+
 func init() {
 	// Register custom metrics with the global prometheus registry
 	metrics.Registry.MustRegister(dRPolicySyncInterval)
 	metrics.Registry.MustRegister(lastSyncTime)
 	metrics.Registry.MustRegister(lastSyncDuration)
 	metrics.Registry.MustRegister(lastSyncDataBytes)
+	metrics.Registry.MustRegister(syncStatus)
 	metrics.Registry.MustRegister(workloadProtectionStatus)
+	metrics.Registry.MustRegister(drClusterVersionSkew)
+	metrics.Registry.MustRegister(drClusterS3SecretsExpected)
+	metrics.Registry.MustRegister(drClusterS3SecretsPresent)
+	metrics.Registry.MustRegister(s3SecretDistributionFailures)
 }
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	runtimepprof "runtime/pprof"
+	"time"
+
+	"github.com/go-logr/logr"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// ProfilingPath is the path, on the metrics server, of the pprof endpoints
+	ProfilingPath = "/debug/pprof/"
+
+	profilingCheckIntervalDefault      = time.Minute
+	profilingMinCaptureIntervalDefault = 30 * time.Minute
+	profilingCPUProfileDurationDefault = 30 * time.Second
+
+	profilingKeyPrefix           = "diagnostics/"
+	profilingReconcileTimeMetric = "controller_runtime_reconcile_time_seconds"
+)
+
+// ProfilingHandlers returns the pprof endpoints to serve on the metrics server, by path
+func ProfilingHandlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		ProfilingPath:             http.HandlerFunc(pprof.Index),
+		ProfilingPath + "cmdline": http.HandlerFunc(pprof.Cmdline),
+		ProfilingPath + "profile": http.HandlerFunc(pprof.Profile),
+		ProfilingPath + "symbol":  http.HandlerFunc(pprof.Symbol),
+		ProfilingPath + "trace":   http.HandlerFunc(pprof.Trace),
+	}
+}
+
+// ProfileCapture is a profile of the operator captured while its reconciles were slow, as uploaded to the S3 store
+type ProfileCapture struct {
+	// Pod is the name of the pod of the operator the profile is of
+	Pod string `json:"pod"`
+
+	// Type of the profile: cpu or heap
+	Type string `json:"type"`
+
+	// Controller is the controller whose average reconcile time exceeded the threshold
+	Controller string `json:"controller"`
+
+	// AverageReconcileTime is the average reconcile time of the controller over the check interval
+	AverageReconcileTime metav1.Duration `json:"averageReconcileTime"`
+
+	// CaptureTime is when the profile was captured
+	CaptureTime metav1.Time `json:"captureTime"`
+
+	// Profile is the profile in the pprof format
+	Profile []byte `json:"profile"`
+}
+
+// Profiler captures the CPU and heap profiles of the operator, and uploads them to an S3 store, when the average
+// reconcile time of a controller over a check interval exceeds the threshold of the profiling configuration
+type Profiler struct {
+	APIReader      client.Reader
+	ObjStoreGetter ObjectStoreGetter
+	Log            logr.Logger
+	Profiling      rmn.Profiling
+
+	reconcileTimes map[string]profilerReconcileTime
+	captureTime    time.Time
+}
+
+// profilerReconcileTime is the cumulative reconcile time and count of a controller
+type profilerReconcileTime struct {
+	sum   float64
+	count uint64
+}
+
+// SetupWithManager adds the profiler to the manager, unless its configuration does not capture profiles
+func (p *Profiler) SetupWithManager(mgr ctrl.Manager) error {
+	if p.Profiling.CaptureThreshold == nil || p.Profiling.S3ProfileName == "" {
+		return nil
+	}
+
+	return mgr.Add(p)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. Each replica profiles itself.
+func (p *Profiler) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable
+func (p *Profiler) Start(ctx context.Context) error {
+	interval := durationOrDefault(p.Profiling.CheckInterval, profilingCheckIntervalDefault)
+
+	p.Log.Info("Starting", "interval", interval, "threshold", p.Profiling.CaptureThreshold.Duration)
+
+	wait.UntilWithContext(ctx, p.check, interval)
+
+	return nil
+}
+
+// check captures the profiles if a controller's average reconcile time since the previous check exceeds the
+// threshold, and the previous capture is older than the least time between captures
+func (p *Profiler) check(ctx context.Context) {
+	reconcileTimes, err := profilerReconcileTimesGather()
+	if err != nil {
+		p.Log.Info("Reconcile times gather failed", "error", err)
+
+		return
+	}
+
+	previous := p.reconcileTimes
+	p.reconcileTimes = reconcileTimes
+
+	if previous == nil {
+		return
+	}
+
+	controller, average := profilerSlowestController(previous, reconcileTimes)
+	if average <= p.Profiling.CaptureThreshold.Duration {
+		return
+	}
+
+	minCaptureInterval := durationOrDefault(p.Profiling.MinCaptureInterval, profilingMinCaptureIntervalDefault)
+	if time.Since(p.captureTime) < minCaptureInterval {
+		return
+	}
+
+	p.captureTime = time.Now()
+
+	p.Log.Info("Reconciles slow, capturing profiles", "controller", controller, "average", average)
+
+	if err := p.capture(ctx, controller, average); err != nil {
+		p.Log.Info("Profiles capture failed", "error", err)
+	}
+}
+
+// profilerReconcileTimesGather returns the cumulative reconcile times of the controllers, by controller name
+func profilerReconcileTimesGather() (map[string]profilerReconcileTime, error) {
+	metricFamilies, err := metrics.Registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	reconcileTimes := map[string]profilerReconcileTime{}
+
+	for _, metricFamily := range metricFamilies {
+		if metricFamily.GetName() != profilingReconcileTimeMetric {
+			continue
+		}
+
+		for _, metric := range metricFamily.GetMetric() {
+			reconcileTimes[profilerMetricLabel(metric, "controller")] = profilerReconcileTime{
+				sum:   metric.GetHistogram().GetSampleSum(),
+				count: metric.GetHistogram().GetSampleCount(),
+			}
+		}
+	}
+
+	return reconcileTimes, nil
+}
+
+func profilerMetricLabel(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+
+	return ""
+}
+
+// profilerSlowestController returns the controller with the highest average reconcile time between two gathers,
+// and its average
+func profilerSlowestController(previous, current map[string]profilerReconcileTime) (string, time.Duration) {
+	slowest := ""
+
+	var slowestAverage time.Duration
+
+	for controller, reconcileTime := range current {
+		count := reconcileTime.count - previous[controller].count
+		if count == 0 {
+			continue
+		}
+
+		sum := reconcileTime.sum - previous[controller].sum
+
+		average := time.Duration(sum / float64(count) * float64(time.Second))
+		if average > slowestAverage {
+			slowest, slowestAverage = controller, average
+		}
+	}
+
+	return slowest, slowestAverage
+}
+
+// capture captures the CPU profile for its duration, and then the heap profile, and uploads both
+func (p *Profiler) capture(ctx context.Context, controller string, average time.Duration) error {
+	objectStorer, _, err := p.ObjStoreGetter.ObjectStore(ctx, p.APIReader, p.Profiling.S3ProfileName, "profiler",
+		p.Log)
+	if err != nil {
+		return fmt.Errorf("failed to get object store of s3 profile %s, %w", p.Profiling.S3ProfileName, err)
+	}
+
+	pod, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("failed to get pod name, %w", err)
+	}
+
+	cpuProfile := &bytes.Buffer{}
+	if err := runtimepprof.StartCPUProfile(cpuProfile); err != nil {
+		return fmt.Errorf("failed to start cpu profile, %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(durationOrDefault(p.Profiling.CPUProfileDuration, profilingCPUProfileDurationDefault)):
+	}
+
+	runtimepprof.StopCPUProfile()
+
+	heapProfile := &bytes.Buffer{}
+	if err := runtimepprof.Lookup("heap").WriteTo(heapProfile, 0); err != nil {
+		return fmt.Errorf("failed to write heap profile, %w", err)
+	}
+
+	captureTime := metav1.Now()
+	keyPrefix := fmt.Sprintf("%s%s/%s/%s/", profilingKeyPrefix, ControllerType, pod,
+		captureTime.UTC().Format("20060102T150405Z"))
+
+	for profileType, profile := range map[string]*bytes.Buffer{"cpu": cpuProfile, "heap": heapProfile} {
+		if err := objectStorer.UploadObject(keyPrefix+profileType, ProfileCapture{
+			Pod:                  pod,
+			Type:                 profileType,
+			Controller:           controller,
+			AverageReconcileTime: metav1.Duration{Duration: average},
+			CaptureTime:          captureTime,
+			Profile:              profile.Bytes(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	p.Log.Info("Profiles uploaded", "s3Profile", p.Profiling.S3ProfileName, "keyPrefix", keyPrefix)
+
+	return nil
+}
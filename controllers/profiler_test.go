@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the capture of the operator's profiles on slow reconciles
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("Profiler", func() {
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+
+	DescribeTable("profilerSlowestController",
+		func(previous, current map[string]profilerReconcileTime, controller string, average time.Duration) {
+			actualController, actualAverage := profilerSlowestController(previous, current)
+			Expect(actualController).To(Equal(controller))
+			Expect(actualAverage).To(Equal(average))
+		},
+		Entry("no reconciles", map[string]profilerReconcileTime{}, map[string]profilerReconcileTime{}, "",
+			time.Duration(0)),
+		Entry("no reconciles since the previous gather", map[string]profilerReconcileTime{
+			"drpc": {sum: 10, count: 5},
+		}, map[string]profilerReconcileTime{
+			"drpc": {sum: 10, count: 5},
+		}, "", time.Duration(0)),
+		Entry("the average since the previous gather", map[string]profilerReconcileTime{
+			"drpc": {sum: 100, count: 10},
+			"vrg":  {sum: 1, count: 10},
+		}, map[string]profilerReconcileTime{
+			"drpc": {sum: 101, count: 20},
+			"vrg":  {sum: 5, count: 12},
+		}, "vrg", 2*time.Second),
+		Entry("a controller first reconciled since the previous gather", map[string]profilerReconcileTime{},
+			map[string]profilerReconcileTime{"drpc": {sum: 3, count: 2}}, "drpc", 1500*time.Millisecond),
+	)
+
+	DescribeTable("profilingValidate",
+		func(profiling *rmn.Profiling, expected []string) {
+			errs := profilingValidate(profiling, map[string]struct{}{"s3": {}})
+
+			messages := []string{}
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+
+			Expect(messages).To(Equal(expected))
+		},
+		Entry("no profiling", nil, []string{}),
+		Entry("endpoints only", &rmn.Profiling{}, []string{}),
+		Entry("captures", &rmn.Profiling{CaptureThreshold: duration(time.Second), S3ProfileName: "s3"}, []string{}),
+		Entry("captures to an unknown s3 profile", &rmn.Profiling{CaptureThreshold: duration(time.Second)},
+			[]string{`profiling s3ProfileName "" is not an s3 profile`}),
+		Entry("durations that are not positive", &rmn.Profiling{
+			CheckInterval: duration(0), CPUProfileDuration: duration(-time.Second),
+		}, []string{"profiling checkInterval 0s is not positive", "profiling cpuProfileDuration -1s is not positive"}),
+	)
+
+	Describe("SetupWithManager", func() {
+		It("captures profiles only with a threshold and an s3 profile", func() {
+			mgr := &runnablesManager{}
+
+			Expect((&Profiler{}).SetupWithManager(mgr)).To(Succeed())
+			Expect((&Profiler{Profiling: rmn.Profiling{CaptureThreshold: duration(time.Second)}}).SetupWithManager(
+				mgr)).To(Succeed())
+			Expect(mgr.runnables).To(BeEmpty())
+
+			profiler := &Profiler{Profiling: rmn.Profiling{CaptureThreshold: duration(time.Second), S3ProfileName: "s3"}}
+			Expect(profiler.SetupWithManager(mgr)).To(Succeed())
+			Expect(mgr.runnables).To(ConsistOf(profiler))
+			Expect(profiler.NeedLeaderElection()).To(BeFalse())
+		})
+	})
+
+	Describe("capture", func() {
+		It("uploads the cpu and heap profiles with the controller that was slow", func() {
+			objectStorer := memoryObjectStorer{}
+			profiler := &Profiler{
+				ObjStoreGetter: memoryObjectStoreGetter{"s3": objectStorer},
+				Log:            ctrl.Log.WithName("profiler-test"),
+				Profiling:      rmn.Profiling{S3ProfileName: "s3", CPUProfileDuration: duration(time.Millisecond)},
+			}
+
+			Expect(profiler.capture(context.TODO(), "drpc", 3*time.Second)).To(Succeed())
+
+			keys, err := objectStorer.ListKeys(profilingKeyPrefix)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(keys).To(HaveLen(2))
+
+			for _, key := range keys {
+				profileType := key[strings.LastIndex(key, "/")+1:]
+				Expect(profileType).To(BeElementOf("cpu", "heap"))
+
+				capture := &ProfileCapture{}
+				Expect(objectStorer.DownloadObject(key, capture)).To(Succeed())
+				Expect(capture.Type).To(Equal(profileType))
+				Expect(capture.Controller).To(Equal("drpc"))
+				Expect(capture.AverageReconcileTime.Duration).To(Equal(3 * time.Second))
+				Expect(capture.Profile).ToNot(BeEmpty())
+			}
+		})
+
+		It("fails without the object store", func() {
+			profiler := &Profiler{
+				ObjStoreGetter: memoryObjectStoreGetter{},
+				Log:            ctrl.Log.WithName("profiler-test"),
+				Profiling:      rmn.Profiling{S3ProfileName: "s3"},
+			}
+
+			Expect(profiler.capture(context.TODO(), "drpc", time.Second)).To(MatchError(
+				ContainSubstring("failed to get object store of s3 profile s3")))
+		})
+	})
+})
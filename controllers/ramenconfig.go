@@ -74,6 +74,14 @@ func LoadControllerConfig(configFile string,
 	for profileName, s3Profile := range ramenConfig.S3StoreProfiles {
 		log.Info("s3 profile", "key", profileName, "value", s3Profile)
 	}
+
+	if err := logConfigValidate(ramenConfig.Log); err != nil {
+		log.Info("Log configuration ignored", "error", err)
+
+		return
+	}
+
+	logConfigApply(ramenConfig.Log)
 }
 
 // Read the RamenConfig file mounted in the local file system.  This file is
@@ -169,6 +177,10 @@ func s3StoreProfileFormatCheck(s3StoreProfile *ramendrv1alpha1.S3StoreProfile) (
 		return err
 	}
 
+	if s3StoreProfile.OIDC != nil {
+		return s3OIDCProfileFormatCheck(s3StoreProfile)
+	}
+
 	return nil
 }
 
@@ -240,6 +252,17 @@ func RamenOperatorNamespace() string {
 	return os.Getenv("POD_NAMESPACE")
 }
 
+// RamenOperatorUsername returns the name the API server authenticates the operator's service account by, or "" if
+// its service account is not set in its environment
+func RamenOperatorUsername() string {
+	serviceAccountName := os.Getenv("POD_SERVICE_ACCOUNT")
+	if serviceAccountName == "" {
+		return ""
+	}
+
+	return "system:serviceaccount:" + RamenOperatorNamespace() + ":" + serviceAccountName
+}
+
 func RamenOperandsNamespace(config ramendrv1alpha1.RamenConfig) string {
 	return config.RamenOpsNamespace
 }
@@ -0,0 +1,523 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// ConditionConfigApplied reports whether the operator runs with the latest configuration
+	ConditionConfigApplied = "ConfigApplied"
+
+	ReasonConfigApplied          = "Applied"
+	ReasonConfigParseFailed      = "ParseFailed"
+	ReasonConfigValidationFailed = "ValidationFailed"
+	ReasonConfigRestartPending   = "RestartPending"
+	ReasonConfigRestarting       = "Restarting"
+
+	// ramenConfigFileSyncInterval is how often a configuration change that requires a restart is checked for in the
+	// mounted configuration file, which the kubelet updates some time after the config map changes
+	ramenConfigFileSyncInterval = 30 * time.Second
+)
+
+// RamenConfigReconciler hot-reloads the operator's configuration. Most fields are read from the config map each
+// time they are used, and so apply once the config map changes; the log configuration is applied to the operator's
+// logger as it changes. The fields that configure the controllers
+// themselves, like their watches and number of workers, are read at startup only; for these, the operator is
+// restarted, by calling Restart, once the mounted configuration file has synced with the config map. The outcome,
+// including validation errors, is reported in the ConfigApplied condition, which is recorded as an event on the
+// config map as it changes and, on a dr cluster, in the DRClusterOperatorStatus. Config maps have no generation, so
+// the condition's observed generation counts the changes to the configuration content since the operator started.
+type RamenConfigReconciler struct {
+	client.Client
+	APIReader client.Reader
+	Log       logr.Logger
+
+	// StartupConfig is the configuration the operator was started with
+	StartupConfig *ramendrv1alpha1.RamenConfig
+
+	// Restart stops the operator, to have it restarted with the latest configuration
+	Restart func()
+
+	eventRecorder *rmnutil.EventReporter
+
+	mutex      sync.Mutex
+	configHash string
+	condition  *metav1.Condition
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RamenConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("ramenconfig"))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("ramenconfig").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return object.GetNamespace() == RamenOperatorNamespace() && object.GetName() == ramenConfigMapName()
+			}),
+			predicate.ResourceVersionChangedPredicate{},
+		)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+func (r *RamenConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("ConfigMap", req.NamespacedName)
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.APIReader.Get(ctx, req.NamespacedName, configMap); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	result, restart, reason, msg := r.configApply(configMap.Data[ConfigMapRamenConfigKeyName], log)
+
+	condition, changed := r.conditionUpdate(ramenConfigHash(configMap.Data[ConfigMapRamenConfigKeyName]), reason,
+		fmt.Sprintf("%s; config map resource version %s", msg, configMap.ResourceVersion))
+	if changed {
+		log.Info("Configuration condition changed", "generation", condition.ObservedGeneration,
+			"reason", condition.Reason, "message", condition.Message)
+
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+
+		rmnutil.ReportIfNotPresent(r.eventRecorder, configMap, eventType, condition.Reason, condition.Message)
+	}
+
+	if restart {
+		log.Info("Restarting to apply configuration", "generation", condition.ObservedGeneration, "fields", msg)
+		r.Restart()
+	}
+
+	return result, nil
+}
+
+// conditionUpdate sets the ConfigApplied condition for a configuration content, and returns it and whether it changed
+func (r *RamenConfigReconciler) conditionUpdate(configHash, reason, msg string) (metav1.Condition, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	condition := metav1.Condition{
+		Type:               ConditionConfigApplied,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            msg,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	if reason == ReasonConfigApplied {
+		condition.Status = metav1.ConditionTrue
+	}
+
+	if r.condition != nil {
+		condition.ObservedGeneration = r.condition.ObservedGeneration
+
+		if r.condition.Status == condition.Status {
+			condition.LastTransitionTime = r.condition.LastTransitionTime
+		}
+	}
+
+	if configHash != r.configHash {
+		condition.ObservedGeneration++
+		r.configHash = configHash
+	}
+
+	changed := r.condition == nil || r.condition.ObservedGeneration != condition.ObservedGeneration ||
+		r.condition.Reason != condition.Reason || r.condition.Message != condition.Message
+	r.condition = &condition
+
+	return condition, changed
+}
+
+// ConfigAppliedCondition returns the ConfigApplied condition, or nil until the configuration is first reconciled
+func (r *RamenConfigReconciler) ConfigAppliedCondition() *metav1.Condition {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.condition == nil {
+		return nil
+	}
+
+	condition := *r.condition
+
+	return &condition
+}
+
+// configApply re-validates the configuration, and determines whether it applies with or without a restart. It
+// returns the reason and message of the ConfigApplied condition.
+func (r *RamenConfigReconciler) configApply(configData string, log logr.Logger,
+) (result ctrl.Result, restart bool, reason, msg string) {
+	ramenConfig := &ramendrv1alpha1.RamenConfig{}
+	if err := yaml.Unmarshal([]byte(configData), ramenConfig); err != nil {
+		return result, false, ReasonConfigParseFailed, err.Error()
+	}
+
+	if err := ramenConfigValidate(ramenConfig); err != nil {
+		log.Info("Configuration invalid", "error", err)
+
+		return result, false, ReasonConfigValidationFailed, err.Error()
+	}
+
+	logConfigApply(ramenConfig.Log)
+
+	// Without a configuration file, the operator starts with defaults, which a restart does not change
+	if r.StartupConfig == nil || cachedRamenConfigFileName == "" {
+		return result, false, ReasonConfigApplied, "Configuration applied"
+	}
+
+	fields := ramenConfigRestartFieldsChanged(r.StartupConfig, ramenConfig)
+	if len(fields) == 0 {
+		return result, false, ReasonConfigApplied, "Configuration applied"
+	}
+
+	msg = "Restart required to apply " + strings.Join(fields, ", ")
+
+	// The operator loads its configuration from the mounted file at startup, so a restart is deferred until the
+	// file has the configuration of the config map, to not restart with the previous configuration.
+	fileConfig, err := ReadRamenConfigFile(log)
+	if err != nil || len(ramenConfigRestartFieldsChanged(&fileConfig, ramenConfig)) != 0 {
+		result.RequeueAfter = ramenConfigFileSyncInterval
+
+		return result, false, ReasonConfigRestartPending, msg + "; waiting for the configuration file to sync"
+	}
+
+	return result, true, ReasonConfigRestarting, msg
+}
+
+// ramenConfigValidate returns an error listing the problems found in a configuration
+func ramenConfigValidate(ramenConfig *ramendrv1alpha1.RamenConfig) error {
+	errs := []error{}
+
+	if ramenConfig.RamenControllerType != "" && ramenConfig.RamenControllerType != ControllerType {
+		errs = append(errs, fmt.Errorf("controller type %s differs from the operator's, %s",
+			ramenConfig.RamenControllerType, ControllerType))
+	}
+
+	s3ProfileNames := map[string]struct{}{}
+
+	for i := range ramenConfig.S3StoreProfiles {
+		s3StoreProfile := &ramenConfig.S3StoreProfiles[i]
+
+		if _, ok := s3ProfileNames[s3StoreProfile.S3ProfileName]; ok {
+			errs = append(errs, fmt.Errorf("s3 profile %s is defined more than once", s3StoreProfile.S3ProfileName))
+		}
+
+		s3ProfileNames[s3StoreProfile.S3ProfileName] = struct{}{}
+
+		if err := s3StoreProfileFormatCheck(s3StoreProfile); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if _, err := drClusterSecretRefs(ramenConfig); err != nil {
+		errs = append(errs, err)
+	}
+
+	if ramenConfig.MaxConcurrentReconciles < 0 {
+		errs = append(errs, fmt.Errorf("maxConcurrentReconciles %d is negative", ramenConfig.MaxConcurrentReconciles))
+	}
+
+	if ramenConfig.FinalizerTimeout.Seconds < 0 {
+		errs = append(errs, fmt.Errorf("finalizerTimeout seconds %d is negative", ramenConfig.FinalizerTimeout.Seconds))
+	}
+
+	if ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds < 0 {
+		errs = append(errs, fmt.Errorf("kubeObjectProtection operatorsReadyTimeoutSeconds %d is negative",
+			ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds))
+	}
+
+	if _, err := kubeObjectsAPIVersionPrioritiesParse(ramenConfig.KubeObjectProtection.APIVersionPriorities); err != nil {
+		errs = append(errs, fmt.Errorf("kubeObjectProtection apiVersionPriorities invalid: %w", err))
+	}
+
+	switch ramenConfig.FailoverCapacityCheck.Mode {
+	case "", ramendrv1alpha1.FailoverCapacityCheckWarn, ramendrv1alpha1.FailoverCapacityCheckRefuse:
+	default:
+		errs = append(errs, fmt.Errorf("failoverCapacityCheck mode %s is not one of %s, %s",
+			ramenConfig.FailoverCapacityCheck.Mode,
+			ramendrv1alpha1.FailoverCapacityCheckWarn, ramendrv1alpha1.FailoverCapacityCheckRefuse))
+	}
+
+	switch ramenConfig.FailureDomainCheck.Mode {
+	case "", ramendrv1alpha1.FailureDomainCheckWarn, ramendrv1alpha1.FailureDomainCheckRefuse:
+	default:
+		errs = append(errs, fmt.Errorf("failureDomainCheck mode %s is not one of %s, %s",
+			ramenConfig.FailureDomainCheck.Mode,
+			ramendrv1alpha1.FailureDomainCheckWarn, ramendrv1alpha1.FailureDomainCheckRefuse))
+	}
+
+	if err := operatorProfileValidate(ramenConfig.OperatorProfile); err != nil {
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, drFreezeValidate(ramenConfig.DRFreeze, ramenConfig.DRPCApprovalWebhookEnabled)...)
+	errs = append(errs, rmnutil.NotificationsValidate(ramenConfig.Notifications)...)
+
+	if thresholds := ramenConfig.SyncThresholds; thresholds.WarningFactor < 0 || thresholds.CriticalFactor < 0 {
+		errs = append(errs, fmt.Errorf("syncThresholds warningFactor %d or criticalFactor %d is negative",
+			thresholds.WarningFactor, thresholds.CriticalFactor))
+	} else if syncCriticalFactor(ramenConfig) < syncWarningFactor(ramenConfig) {
+		errs = append(errs, fmt.Errorf("syncThresholds criticalFactor %d is less than warningFactor %d",
+			syncCriticalFactor(ramenConfig), syncWarningFactor(ramenConfig)))
+	}
+
+	if ramenConfig.Standalone.Enabled &&
+		(ControllerType != ramendrv1alpha1.DRClusterType || ramenConfig.Standalone.ClusterName == "") {
+		errs = append(errs, fmt.Errorf("standalone mode requires controller type %s and a cluster name",
+			ramendrv1alpha1.DRClusterType))
+	}
+
+	if _, err := labels.Parse(ramenConfig.HealthReport.MirrorDaemonLabelSelector); err != nil {
+		errs = append(errs, fmt.Errorf("healthReport mirrorDaemonLabelSelector: %w", err))
+	}
+
+	if upgrade := ramenConfig.DrClusterOperatorUpgrade; upgrade != nil {
+		if _, err := semver.ParseTolerant(upgrade.Version); err != nil {
+			errs = append(errs, fmt.Errorf("drClusterOperatorUpgrade version: %w", err))
+		}
+
+		if upgrade.HealthCheckTimeout != nil && upgrade.HealthCheckTimeout.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("drClusterOperatorUpgrade healthCheckTimeout %v is not positive",
+				upgrade.HealthCheckTimeout.Duration))
+		}
+	}
+
+	if rollout := ramenConfig.DrClusterObjectsRollout; rollout != nil &&
+		rollout.HealthCheckTimeout != nil && rollout.HealthCheckTimeout.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("drClusterObjectsRollout healthCheckTimeout %v is not positive",
+			rollout.HealthCheckTimeout.Duration))
+	}
+
+	if addOn := ramenConfig.DrClusterOperatorAddOn; addOn != nil {
+		if ramenConfig.DrClusterOperatorUpgrade != nil {
+			errs = append(errs, errors.New("drClusterOperatorUpgrade rolls out a Subscription, so it may not be "+
+				"set along with drClusterOperatorAddOn"))
+		}
+
+		for _, placement := range addOn.Placements {
+			if placement.Namespace == "" || placement.Name == "" {
+				errs = append(errs, fmt.Errorf("drClusterOperatorAddOn placement %q requires a namespace and a name",
+					placement.Namespace+"/"+placement.Name))
+			}
+		}
+	}
+
+	errs = append(errs, profilingValidate(ramenConfig.Profiling, s3ProfileNames)...)
+	errs = append(errs, leaderElectionGroupsValidate(ramenConfig.LeaderElectionGroups)...)
+	errs = append(errs, arrayReplicationPluginsValidate(ramenConfig.ArrayReplicationPlugins)...)
+	errs = append(errs, veleroItemActionConfigsValidate(ramenConfig.VeleroItemActionConfigs)...)
+	errs = append(errs, uploadRedactionsValidate(ramenConfig.UploadRedactions)...)
+
+	if err := logConfigValidate(ramenConfig.Log); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func arrayReplicationPluginsValidate(plugins []ramendrv1alpha1.ArrayReplicationPlugin) []error {
+	errs := []error{}
+	names := map[string]struct{}{
+		SyncReplicationProviderStretched:         {},
+		SyncReplicationProviderVolumeReplication: {},
+	}
+
+	for _, plugin := range plugins {
+		if plugin.Name == "" || plugin.Endpoint == "" {
+			errs = append(errs, fmt.Errorf("arrayReplicationPlugin %q requires a name and an endpoint", plugin.Name))
+
+			continue
+		}
+
+		if _, ok := names[plugin.Name]; ok {
+			errs = append(errs, fmt.Errorf("arrayReplicationPlugin name %s is not unique among sync replication providers",
+				plugin.Name))
+		}
+
+		names[plugin.Name] = struct{}{}
+	}
+
+	return errs
+}
+
+// veleroItemActionConfigsValidate returns the problems found in the Velero item action configurations, as Velero
+// fails to apply a plugin configured more than once
+func veleroItemActionConfigsValidate(itemActionConfigs []ramendrv1alpha1.VeleroItemActionConfig) []error {
+	errs := []error{}
+	plugins := map[string]struct{}{}
+
+	for _, itemActionConfig := range itemActionConfigs {
+		for _, msg := range validation.IsQualifiedName(itemActionConfig.Plugin) {
+			errs = append(errs, fmt.Errorf("veleroItemActionConfig plugin %q: %s", itemActionConfig.Plugin, msg))
+		}
+
+		switch itemActionConfig.Kind {
+		case ramendrv1alpha1.VeleroItemActionKindBackup, ramendrv1alpha1.VeleroItemActionKindRestore:
+		default:
+			errs = append(errs, fmt.Errorf("veleroItemActionConfig plugin %s kind %q is not one of %s, %s",
+				itemActionConfig.Plugin, itemActionConfig.Kind,
+				ramendrv1alpha1.VeleroItemActionKindBackup, ramendrv1alpha1.VeleroItemActionKindRestore))
+		}
+
+		if _, ok := plugins[itemActionConfig.Plugin]; ok {
+			errs = append(errs, fmt.Errorf("veleroItemActionConfig plugin %s is configured more than once",
+				itemActionConfig.Plugin))
+		}
+
+		plugins[itemActionConfig.Plugin] = struct{}{}
+	}
+
+	return errs
+}
+
+// profilingValidate returns the problems found in a profiling configuration
+func profilingValidate(profiling *ramendrv1alpha1.Profiling, s3ProfileNames map[string]struct{}) []error {
+	errs := []error{}
+
+	if profiling == nil {
+		return errs
+	}
+
+	for _, field := range []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"captureThreshold", profiling.CaptureThreshold},
+		{"checkInterval", profiling.CheckInterval},
+		{"minCaptureInterval", profiling.MinCaptureInterval},
+		{"cpuProfileDuration", profiling.CPUProfileDuration},
+	} {
+		if field.duration != nil && field.duration.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("profiling %s %v is not positive", field.name, field.duration.Duration))
+		}
+	}
+
+	if profiling.CaptureThreshold != nil {
+		if _, ok := s3ProfileNames[profiling.S3ProfileName]; !ok {
+			errs = append(errs, fmt.Errorf("profiling s3ProfileName %q is not an s3 profile",
+				profiling.S3ProfileName))
+		}
+	}
+
+	return errs
+}
+
+// leaderElectionGroupsValidate returns the problems found in the leader election groups of a configuration
+func leaderElectionGroupsValidate(groups []ramendrv1alpha1.LeaderElectionGroup) []error {
+	errs := []error{}
+
+	if len(groups) != 0 && ControllerType != ramendrv1alpha1.DRHubType {
+		errs = append(errs, fmt.Errorf("leaderElectionGroups require controller type %s", ramendrv1alpha1.DRHubType))
+	}
+
+	names := map[string]struct{}{}
+	controllers := map[string]string{}
+
+	for _, group := range groups {
+		for _, msg := range validation.IsDNS1123Label(group.Name) {
+			errs = append(errs, fmt.Errorf("leaderElectionGroup name %q: %s", group.Name, msg))
+		}
+
+		if _, ok := names[group.Name]; ok {
+			errs = append(errs, fmt.Errorf("leaderElectionGroup name %s is defined more than once", group.Name))
+		}
+
+		names[group.Name] = struct{}{}
+
+		if len(group.Controllers) == 0 {
+			errs = append(errs, fmt.Errorf("leaderElectionGroup %s has no controllers", group.Name))
+		}
+
+		for _, controller := range group.Controllers {
+			if !slices.Contains(LeaderElectionGroupControllers, controller) {
+				errs = append(errs, fmt.Errorf("leaderElectionGroup %s controller %s is not one of %s", group.Name,
+					controller, strings.Join(LeaderElectionGroupControllers, ", ")))
+			}
+
+			if other, ok := controllers[controller]; ok {
+				errs = append(errs, fmt.Errorf("leaderElectionGroup %s controller %s is also in group %s", group.Name,
+					controller, other))
+			}
+
+			controllers[controller] = group.Name
+		}
+	}
+
+	return errs
+}
+
+// ramenConfigRestartFieldsChanged returns the names of the fields, read at startup only, that differ between two
+// configurations
+func ramenConfigRestartFieldsChanged(old, cur *ramendrv1alpha1.RamenConfig) []string {
+	fields := []string{}
+
+	for _, field := range []struct {
+		name     string
+		old, cur interface{}
+	}{
+		{"ramenControllerType", old.RamenControllerType, cur.RamenControllerType},
+		{"controller manager configuration", old.ControllerManagerConfigurationSpec,
+			cur.ControllerManagerConfigurationSpec},
+		{"maxConcurrentReconciles", old.MaxConcurrentReconciles, cur.MaxConcurrentReconciles},
+		{"volSync.disabled", old.VolSync.Disabled, cur.VolSync.Disabled},
+		{"kubeObjectProtection.disabled", old.KubeObjectProtection.Disabled, cur.KubeObjectProtection.Disabled},
+		{"arrayReplicationPlugins", old.ArrayReplicationPlugins, cur.ArrayReplicationPlugins},
+		{"profiling", old.Profiling, cur.Profiling},
+		{"leaderElectionGroups", old.LeaderElectionGroups, cur.LeaderElectionGroups},
+		{"operatorProfile", old.OperatorProfile, cur.OperatorProfile},
+		{"drpcActionPermissionsWebhookEnabled", old.DRPCActionPermissionsWebhookEnabled,
+			cur.DRPCActionPermissionsWebhookEnabled},
+		{"drpcDefaultDRPolicyWebhookEnabled", old.DRPCDefaultDRPolicyWebhookEnabled,
+			cur.DRPCDefaultDRPolicyWebhookEnabled},
+		{"drpcApprovalWebhookEnabled", old.DRPCApprovalWebhookEnabled, cur.DRPCApprovalWebhookEnabled},
+	} {
+		if !reflect.DeepEqual(field.old, field.cur) {
+			fields = append(fields, field.name)
+		}
+	}
+
+	return fields
+}
+
+func ramenConfigHash(configData string) string {
+	hash := sha256.Sum256([]byte(configData))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func ramenConfigMapName() string {
+	if ControllerType == ramendrv1alpha1.DRHubType {
+		return HubOperatorConfigMapName
+	}
+
+	return DrClusterOperatorConfigMapName
+}
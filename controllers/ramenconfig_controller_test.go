@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the ConfigApplied condition of the operator's configuration
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RamenConfig_ConfigApplied", func() {
+	It("counts the changes to the configuration content in the observed generation", func() {
+		r := &RamenConfigReconciler{}
+		Expect(r.ConfigAppliedCondition()).To(BeNil())
+
+		condition, changed := r.conditionUpdate(ramenConfigHash("a"), ReasonConfigApplied, "applied")
+		Expect(changed).To(BeTrue())
+		Expect(condition.ObservedGeneration).To(BeEquivalentTo(1))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		_, changed = r.conditionUpdate(ramenConfigHash("a"), ReasonConfigApplied, "applied")
+		Expect(changed).To(BeFalse())
+
+		condition, changed = r.conditionUpdate(ramenConfigHash("b"), ReasonConfigValidationFailed, "invalid")
+		Expect(changed).To(BeTrue())
+		Expect(condition.ObservedGeneration).To(BeEquivalentTo(2))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(r.ConfigAppliedCondition()).To(Equal(&condition))
+	})
+
+	It("reports invalid configurations without applying them", func() {
+		r := &RamenConfigReconciler{}
+
+		_, restart, reason, _ := r.configApply("maxConcurrentReconciles: -1", r.Log)
+		Expect(restart).To(BeFalse())
+		Expect(reason).To(Equal(ReasonConfigValidationFailed))
+
+		_, _, reason, _ = r.configApply("maxConcurrentReconciles: [", r.Log)
+		Expect(reason).To(Equal(ReasonConfigParseFailed))
+	})
+})
@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	recipe "github.com/ramendr/recipe/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// RecipeValidatingWebhookPath is the path the recipe validating webhook is served at
+const RecipeValidatingWebhookPath = "/validate-ramendr-openshift-io-v1alpha1-recipe"
+
+//+kubebuilder:webhook:path=/validate-ramendr-openshift-io-v1alpha1-recipe,mutating=false,failurePolicy=fail,sideEffects=None,groups=ramendr.openshift.io,resources=recipes,verbs=create;update,versions=v1alpha1,name=vrecipe.ramendr.openshift.io,admissionReviewVersions=v1
+
+// RecipeValidator rejects the creation or update of a recipe that the VRGs referring to it would fail to protect
+// or recover with, when their workloads are protected rather than when they fail over
+type RecipeValidator struct {
+	Reader      client.Reader
+	RamenConfig *ramen.RamenConfig
+	Log         logr.Logger
+}
+
+var _ admission.CustomValidator = &RecipeValidator{}
+
+func (v *RecipeValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+func (v *RecipeValidator) ValidateUpdate(ctx context.Context, _, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+func (v *RecipeValidator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *RecipeValidator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*recipe.Recipe)
+	if !ok {
+		return nil, fmt.Errorf("expected a recipe, got %T", obj)
+	}
+
+	log := v.Log.WithValues("recipe", r.Namespace+"/"+r.Name)
+
+	warnings, err := RecipeValidate(ctx, v.Reader, r, *v.RamenConfig, log)
+	if err != nil {
+		log.Info("Recipe rejected", "error", err)
+	}
+
+	return warnings, err
+}
+
+// RecipeValidate evaluates a recipe as each VRG referring to it would, expanded with its parameters, and returns the
+// errors found: workflow steps referring to missing groups or hooks, recover groups referring to missing capture
+// groups, parameters neither defined nor defaulted, invalid label selectors, and namespaces the VRG may not
+// protect. A recipe no VRG refers to is evaluated without parameters, skipping the label selectors that refer to
+// them, with a warning.
+func RecipeValidate(ctx context.Context, reader client.Reader, r *recipe.Recipe, ramenConfig ramen.RamenConfig,
+	log logr.Logger,
+) (admission.Warnings, error) {
+	vrgs, err := recipeVRGs(ctx, reader, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vrgs) == 0 {
+		return admission.Warnings{"recipe is not referred to by a VolumeReplicationGroup, so its parameters " +
+				"are not expanded and the namespaces it refers to are not checked"},
+			recipeValidateExpanded(*r.DeepCopy(), true)
+	}
+
+	errs := []error{}
+
+	for i := range vrgs {
+		vrg := &vrgs[i]
+
+		if err := recipeValidateForVRG(*r.DeepCopy(), *vrg, ramenConfig, log); err != nil {
+			errs = append(errs, fmt.Errorf("for VolumeReplicationGroup %s/%s: %w", vrg.Namespace, vrg.Name, err))
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// recipeVRGs returns the VRGs referring to a recipe
+func recipeVRGs(ctx context.Context, reader client.Reader, r *recipe.Recipe,
+) ([]ramen.VolumeReplicationGroup, error) {
+	vrgList := &ramen.VolumeReplicationGroupList{}
+	if err := reader.List(ctx, vrgList); err != nil {
+		return nil, fmt.Errorf("failed to list VolumeReplicationGroups: %w", err)
+	}
+
+	vrgs := []ramen.VolumeReplicationGroup{}
+
+	for _, vrg := range vrgList.Items {
+		if vrg.Spec.KubeObjectProtection == nil || vrg.Spec.KubeObjectProtection.RecipeRef == nil {
+			continue
+		}
+
+		recipeRef := vrg.Spec.KubeObjectProtection.RecipeRef
+		if recipeRef.Name == r.Name && recipeRef.Namespace == r.Namespace {
+			vrgs = append(vrgs, vrg)
+		}
+	}
+
+	return vrgs, nil
+}
+
+func recipeValidateForVRG(r recipe.Recipe, vrg ramen.VolumeReplicationGroup, ramenConfig ramen.RamenConfig,
+	log logr.Logger,
+) error {
+	var recipeElements RecipeElements
+
+	undefined, err := RecipeParametersUndefined(r, vrg.Spec.KubeObjectProtection.RecipeParameters)
+	if err != nil {
+		return err
+	}
+
+	if len(undefined) > 0 {
+		return fmt.Errorf("recipe parameters %v are neither defined nor defaulted", undefined)
+	}
+
+	err = recipeElementsEvaluate(r, vrg, ramenConfig, log, &recipeElements,
+		func(expanded recipe.Recipe, recipeElements *RecipeElements, vrg ramen.VolumeReplicationGroup,
+			ramenConfig ramen.RamenConfig,
+		) error {
+			if err := recipeValidateExpanded(expanded, false); err != nil {
+				return err
+			}
+
+			return recipeWorkflowsGet(expanded, recipeElements, vrg, ramenConfig)
+		},
+	)
+
+	return err
+}
+
+// recipeValidateExpanded validates the workflows and label selectors of an expanded recipe, skipping the label
+// selectors that refer to parameters if it was not expanded
+func recipeValidateExpanded(r recipe.Recipe, unexpanded bool) error {
+	errs := []error{}
+
+	if r.Spec.CaptureWorkflow != nil {
+		if _, err := getCaptureGroups(r); err != nil {
+			errs = append(errs, fmt.Errorf("capture workflow: %w", err))
+		}
+	}
+
+	if r.Spec.RecoverWorkflow != nil {
+		if _, err := getRecoverGroups(r); err != nil {
+			errs = append(errs, fmt.Errorf("recover workflow: %w", err))
+		}
+
+		errs = append(errs, recipeBackupRefsValidate(r)...)
+	}
+
+	selectors := map[string]*metav1.LabelSelector{}
+
+	if r.Spec.Volumes != nil {
+		selectors["volumes"] = r.Spec.Volumes.LabelSelector
+	}
+
+	for _, group := range r.Spec.Groups {
+		selectors["group "+group.Name] = group.LabelSelector
+	}
+
+	for _, hook := range r.Spec.Hooks {
+		selectors["hook "+hook.Name] = hook.LabelSelector
+	}
+
+	for name, selector := range selectors {
+		if selector == nil || (unexpanded && recipeSelectorParameterized(selector)) {
+			continue
+		}
+
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			errs = append(errs, fmt.Errorf("%s label selector: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// recipeBackupRefsValidate returns an error for each group of the recover workflow that refers to a group that
+// is not in the capture workflow
+func recipeBackupRefsValidate(r recipe.Recipe) []error {
+	captured := map[string]bool{}
+
+	if r.Spec.CaptureWorkflow != nil {
+		for _, step := range r.Spec.CaptureWorkflow.Sequence {
+			captured[step["group"]] = true
+		}
+	}
+
+	errs := []error{}
+
+	for _, step := range r.Spec.RecoverWorkflow.Sequence {
+		groupName, ok := step["group"]
+		if !ok {
+			continue
+		}
+
+		for _, group := range r.Spec.Groups {
+			if group.Name == groupName && group.BackupRef != "" && !captured[group.BackupRef] {
+				errs = append(errs, fmt.Errorf("recover workflow: group %s backupRef %s is not a group of the "+
+					"capture workflow", group.Name, group.BackupRef))
+			}
+		}
+	}
+
+	return errs
+}
+
+func recipeSelectorParameterized(selector *metav1.LabelSelector) bool {
+	selectorJSON, err := json.Marshal(selector)
+
+	return err == nil && strings.Contains(string(selectorJSON), "$")
+}
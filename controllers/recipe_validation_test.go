@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the validation of recipes on admission
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recipe "github.com/ramendr/recipe/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_RecipeValidation", func() {
+	var (
+		objects []client.Object
+		r       *recipe.Recipe
+	)
+
+	validator := func() *RecipeValidator {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(ramen.AddToScheme(scheme)).To(Succeed())
+		Expect(recipe.AddToScheme(scheme)).To(Succeed())
+
+		return &RecipeValidator{
+			Reader:      fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+			RamenConfig: &ramen.RamenConfig{},
+			Log:         ctrl.Log.WithName("recipe-validation-test"),
+		}
+	}
+	vrg := func(parameters map[string][]string) *ramen.VolumeReplicationGroup {
+		return &ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+			Spec: ramen.VolumeReplicationGroupSpec{
+				KubeObjectProtection: &ramen.KubeObjectProtectionSpec{
+					RecipeRef:        &ramen.RecipeRef{Namespace: "app", Name: "recipe"},
+					RecipeParameters: parameters,
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		objects = nil
+		r = &recipe.Recipe{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "recipe"},
+			Spec: recipe.RecipeSpec{
+				Groups: []*recipe.Group{
+					{Name: "config", Type: "resource", IncludedResourceTypes: []string{"configmaps"}},
+					{Name: "config-restore", Type: "resource", BackupRef: "config"},
+				},
+				Volumes: &recipe.Group{
+					Name: "volumes", Type: "volume",
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "$appname"}},
+				},
+				CaptureWorkflow: &recipe.Workflow{Sequence: []map[string]string{{"group": "config"}}},
+				RecoverWorkflow: &recipe.Workflow{Sequence: []map[string]string{{"group": "config-restore"}}},
+			},
+		}
+	})
+
+	It("admits a recipe that each VRG referring to it defines the parameters of", func() {
+		objects = append(objects, vrg(map[string][]string{"appname": {"busybox"}}))
+		warnings, err := validator().ValidateCreate(context.TODO(), r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("rejects a recipe with parameters a VRG referring to it does not define", func() {
+		objects = append(objects, vrg(nil))
+		_, err := validator().ValidateUpdate(context.TODO(), r, r)
+		Expect(err).To(MatchError(And(ContainSubstring("app/vrg"), ContainSubstring("appname"))))
+	})
+
+	It("admits a recipe no VRG refers to with a warning, without expanding its parameters", func() {
+		warnings, err := validator().ValidateCreate(context.TODO(), r)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(HaveLen(1))
+	})
+
+	It("rejects a recover group referring to a group the capture workflow does not capture", func() {
+		r.Spec.CaptureWorkflow.Sequence = []map[string]string{}
+		_, err := validator().ValidateCreate(context.TODO(), r)
+		Expect(err).To(MatchError(ContainSubstring("backupRef config is not a group of the capture workflow")))
+	})
+
+	It("rejects a workflow referring to a missing group", func() {
+		r.Spec.RecoverWorkflow.Sequence = []map[string]string{{"group": "missing"}}
+		_, err := validator().ValidateCreate(context.TODO(), r)
+		Expect(err).To(MatchError(ContainSubstring("recover workflow")))
+	})
+
+	It("rejects an invalid label selector", func() {
+		r.Spec.Groups[0].LabelSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: "Bogus"},
+		}}
+		_, err := validator().ValidateCreate(context.TODO(), r)
+		Expect(err).To(MatchError(ContainSubstring("group config label selector")))
+	})
+
+	It("admits the deletion of any object, and rejects the admission of an object that is not a recipe", func() {
+		_, err := validator().ValidateDelete(context.TODO(), &corev1.ConfigMap{})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = validator().ValidateCreate(context.TODO(), &corev1.ConfigMap{})
+		Expect(err).To(HaveOccurred())
+	})
+})
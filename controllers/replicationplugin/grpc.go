@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replicationplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ServiceName is the gRPC service of a plugin. Its messages are encoded in JSON, with the content subtype json, for
+// plugins to be implemented without generated code, in any language.
+const ServiceName = "ramendr.replication.v1alpha1.ArrayReplication"
+
+// PromoteRequest is the request of the Promote method
+type PromoteRequest struct {
+	VolumeGroup VolumeGroup `json:"volumeGroup"`
+	Force       bool        `json:"force,omitempty"`
+}
+
+// VolumeGroupRequest is the request of the Demote, Resync and Status methods
+type VolumeGroupRequest struct {
+	VolumeGroup VolumeGroup `json:"volumeGroup"`
+}
+
+// Empty is the response of the Promote, Demote and Resync methods
+type Empty struct{}
+
+// jsonCodec encodes the messages of the client and server of a plugin only. It is forced on their calls rather than
+// registered, for the other gRPC clients and servers of the process not to be affected.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
+
+// Client is the Provider of a plugin, that it calls over gRPC
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient returns the client of the plugin serving on the endpoint, such as unix:///plugins/array.sock. The
+// connection is established on the first call.
+func NewClient(endpoint string) (*Client, error) {
+	conn, err := grpc.Dial(endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial replication plugin %s (%w)", endpoint, err)
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the plugin
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) Promote(ctx context.Context, group VolumeGroup, force bool) error {
+	return c.conn.Invoke(ctx, methodName("Promote"), &PromoteRequest{VolumeGroup: group, Force: force}, &Empty{})
+}
+
+func (c *Client) Demote(ctx context.Context, group VolumeGroup) error {
+	return c.conn.Invoke(ctx, methodName("Demote"), &VolumeGroupRequest{VolumeGroup: group}, &Empty{})
+}
+
+func (c *Client) Resync(ctx context.Context, group VolumeGroup) error {
+	return c.conn.Invoke(ctx, methodName("Resync"), &VolumeGroupRequest{VolumeGroup: group}, &Empty{})
+}
+
+func (c *Client) Status(ctx context.Context, group VolumeGroup) (*VolumeGroupStatus, error) {
+	status := &VolumeGroupStatus{}
+	if err := c.conn.Invoke(ctx, methodName("Status"), &VolumeGroupRequest{VolumeGroup: group}, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+func methodName(method string) string {
+	return "/" + ServiceName + "/" + method
+}
+
+// Serve serves the provider, as a plugin, on the listener, such as of a unix socket shared with the dr-cluster
+// operator, until the listener is closed
+func Serve(listener net.Listener, provider Provider) error {
+	server := grpc.NewServer(grpc.ForceServerCodec(jsonCodec{}))
+	server.RegisterService(&serviceDesc, provider)
+
+	return server.Serve(listener)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Provider)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Promote",
+			Handler: unaryHandler("Promote", func() interface{} { return &PromoteRequest{} },
+				func(ctx context.Context, provider Provider, req interface{}) (interface{}, error) {
+					request := req.(*PromoteRequest)
+
+					return &Empty{}, provider.Promote(ctx, request.VolumeGroup, request.Force)
+				}),
+		},
+		{
+			MethodName: "Demote",
+			Handler: unaryHandler("Demote", func() interface{} { return &VolumeGroupRequest{} },
+				func(ctx context.Context, provider Provider, req interface{}) (interface{}, error) {
+					return &Empty{}, provider.Demote(ctx, req.(*VolumeGroupRequest).VolumeGroup)
+				}),
+		},
+		{
+			MethodName: "Resync",
+			Handler: unaryHandler("Resync", func() interface{} { return &VolumeGroupRequest{} },
+				func(ctx context.Context, provider Provider, req interface{}) (interface{}, error) {
+					return &Empty{}, provider.Resync(ctx, req.(*VolumeGroupRequest).VolumeGroup)
+				}),
+		},
+		{
+			MethodName: "Status",
+			Handler: unaryHandler("Status", func() interface{} { return &VolumeGroupRequest{} },
+				func(ctx context.Context, provider Provider, req interface{}) (interface{}, error) {
+					return provider.Status(ctx, req.(*VolumeGroupRequest).VolumeGroup)
+				}),
+		},
+	},
+	Metadata: "replicationplugin",
+}
+
+func unaryHandler(method string, request func() interface{},
+	call func(context.Context, Provider, interface{}) (interface{}, error),
+) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+		interceptor grpc.UnaryServerInterceptor,
+	) (interface{}, error) {
+		req := request()
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(ctx, srv.(Provider), req)
+		}
+
+		if interceptor == nil {
+			return handler(ctx, req)
+		}
+
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{Server: srv, FullMethod: methodName(method)}, handler)
+	}
+}
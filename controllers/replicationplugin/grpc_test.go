@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replicationplugin_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ramendr/ramen/controllers/replicationplugin"
+	"google.golang.org/grpc/encoding"
+)
+
+type fakeProvider struct {
+	states map[string]replicationplugin.VolumeGroupState
+	forced bool
+}
+
+func (p *fakeProvider) Promote(_ context.Context, group replicationplugin.VolumeGroup, force bool) error {
+	p.states[group.ID] = replicationplugin.VolumeGroupPrimary
+	p.forced = force
+
+	return nil
+}
+
+func (p *fakeProvider) Demote(_ context.Context, group replicationplugin.VolumeGroup) error {
+	p.states[group.ID] = replicationplugin.VolumeGroupSecondary
+
+	return nil
+}
+
+func (p *fakeProvider) Resync(context.Context, replicationplugin.VolumeGroup) error {
+	return errors.New("resync failed")
+}
+
+func (p *fakeProvider) Status(_ context.Context, group replicationplugin.VolumeGroup,
+) (*replicationplugin.VolumeGroupStatus, error) {
+	state, ok := p.states[group.ID]
+	if !ok {
+		state = replicationplugin.VolumeGroupUnknown
+	}
+
+	return &replicationplugin.VolumeGroupStatus{State: state, Synced: len(group.VolumeHandles) > 0}, nil
+}
+
+var _ = Describe("gRPC plugin", func() {
+	var (
+		provider *fakeProvider
+		client   *replicationplugin.Client
+		group    replicationplugin.VolumeGroup
+	)
+
+	BeforeEach(func() {
+		socket := filepath.Join(GinkgoT().TempDir(), "plugin.sock")
+		listener, err := net.Listen("unix", socket)
+		Expect(err).ToNot(HaveOccurred())
+
+		provider = &fakeProvider{states: map[string]replicationplugin.VolumeGroupState{}}
+
+		go func() {
+			defer GinkgoRecover()
+			_ = replicationplugin.Serve(listener, provider)
+		}()
+		DeferCleanup(listener.Close)
+
+		client, err = replicationplugin.NewClient("unix://" + socket)
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(client.Close)
+
+		group = replicationplugin.VolumeGroup{ID: "ns/vrg/sc", VolumeHandles: []string{"vol-1", "vol-2"}}
+	})
+
+	It("calls the provider of the plugin", func(ctx context.Context) {
+		status, err := client.Status(ctx, group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.State).To(Equal(replicationplugin.VolumeGroupUnknown))
+		Expect(status.Synced).To(BeTrue())
+
+		Expect(client.Promote(ctx, group, true)).To(Succeed())
+		Expect(provider.forced).To(BeTrue())
+
+		status, err = client.Status(ctx, group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.State).To(Equal(replicationplugin.VolumeGroupPrimary))
+
+		Expect(client.Demote(ctx, group)).To(Succeed())
+		Expect(provider.states[group.ID]).To(Equal(replicationplugin.VolumeGroupSecondary))
+	})
+
+	It("returns the errors of the provider", func(ctx context.Context) {
+		Expect(client.Resync(ctx, group)).To(MatchError(ContainSubstring("resync failed")))
+	})
+
+	It("does not register its codec for the other clients and servers of the process", func() {
+		Expect(encoding.GetCodec("json")).To(BeNil())
+	})
+})
@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replicationplugin defines the interface of array based replication providers, and the gRPC mechanism
+// that plugs them, as sidecars of the dr-cluster operator, into the VRG orchestration
+package replicationplugin
+
+import (
+	"context"
+	"time"
+)
+
+// VolumeGroupState is the replication state of a volume group on the array of a cluster
+type VolumeGroupState string
+
+const (
+	VolumeGroupPrimary   = VolumeGroupState("Primary")
+	VolumeGroupSecondary = VolumeGroupState("Secondary")
+	VolumeGroupUnknown   = VolumeGroupState("Unknown")
+)
+
+// VolumeGroup is a group of volumes that are promoted, demoted and resynced together, such as those of a VRG of a
+// storage class
+type VolumeGroup struct {
+	// ID identifies the group across reconciles and clusters
+	ID string `json:"id"`
+
+	// VolumeHandles are the CSI volume handles of the volumes of the group
+	VolumeHandles []string `json:"volumeHandles"`
+
+	// Parameters are the parameters of the storage class of the volumes
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// VolumeGroupStatus is the replication status of a volume group on the array of a cluster
+type VolumeGroupStatus struct {
+	State VolumeGroupState `json:"state"`
+
+	// Synced is true if the volumes are in sync with their peers
+	Synced bool `json:"synced"`
+
+	// ResyncRequired is true if the secondary volumes diverged from their peers and are to be resynced
+	ResyncRequired bool `json:"resyncRequired,omitempty"`
+
+	// LastSyncTime is the time the volumes were last in sync with their peers, if known
+	LastSyncTime *time.Time `json:"lastSyncTime,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+// Provider replicates volume groups with the replication of an array. Each operation is idempotent, as the VRG
+// repeats it until the status of the group reports it is complete.
+type Provider interface {
+	// Promote makes the volumes of the group primary, and so writable, on this cluster. Force promotes them even if
+	// their peers are not reachable, as on failover.
+	Promote(ctx context.Context, group VolumeGroup, force bool) error
+
+	// Demote makes the volumes of the group secondary on this cluster
+	Demote(ctx context.Context, group VolumeGroup) error
+
+	// Resync resynchronizes the secondary volumes of the group with their peers
+	Resync(ctx context.Context, group VolumeGroup) error
+
+	// Status returns the replication status of the group on this cluster
+	Status(ctx context.Context, group VolumeGroup) (*VolumeGroupStatus, error)
+}
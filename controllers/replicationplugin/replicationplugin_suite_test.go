@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package replicationplugin_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestReplicationPlugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ReplicationPlugin Suite")
+}
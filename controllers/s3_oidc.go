@@ -0,0 +1,300 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	s3OIDCClientIDKey     = "OIDC_CLIENT_ID"
+	s3OIDCClientSecretKey = "OIDC_CLIENT_SECRET"
+
+	// S3CredentialsExpirationAnnotation is set on the secret of an OIDC S3 profile to the time its short-lived
+	// credentials expire
+	S3CredentialsExpirationAnnotation = "ramendr.openshift.io/s3-credentials-expiration"
+
+	s3OIDCDurationDefault      = time.Hour
+	s3OIDCRefreshBeforeDefault = 10 * time.Minute
+
+	// s3OIDCRefreshIntervalMin is the least time between refreshes of credentials, for a refreshBefore that exceeds
+	// the duration of the credentials not to refresh them continuously
+	s3OIDCRefreshIntervalMin = time.Minute
+
+	s3OIDCRoleSessionName = "ramen"
+	s3OIDCRequestTimeout  = 30 * time.Second
+)
+
+// s3OIDCCredentialsRefresh refreshes the short-lived credentials of the OIDC S3 profiles of the clusters of the
+// DRPolicy that are about to expire, and returns how long until the first of them is to be refreshed next, or zero
+// if none of the profiles is an OIDC one
+func s3OIDCCredentialsRefresh(ctx context.Context, c client.Client, drpolicy *ramen.DRPolicy,
+	drclusters *ramen.DRClusterList, ramenConfig *ramen.RamenConfig, log logr.Logger,
+) (time.Duration, error) {
+	var next time.Duration
+
+	clusterNames := util.DRPolicyClusterNames(drpolicy)
+
+	for i := range drclusters.Items {
+		if !slices.Contains(clusterNames, drclusters.Items[i].Name) {
+			continue
+		}
+
+		s3StoreProfile := RamenConfigS3StoreProfilePointerGet(ramenConfig, drclusters.Items[i].Spec.S3ProfileName)
+		if s3StoreProfile == nil || s3StoreProfile.OIDC == nil {
+			continue
+		}
+
+		refreshAfter, err := s3OIDCSecretRefresh(ctx, c, s3StoreProfile, log)
+		if err != nil {
+			return 0, fmt.Errorf("s3 profile %s: %w", s3StoreProfile.S3ProfileName, err)
+		}
+
+		if next == 0 || refreshAfter < next {
+			next = refreshAfter
+		}
+	}
+
+	return next, nil
+}
+
+// s3OIDCSecretRefresh updates the secret of an OIDC S3 profile with new short-lived credentials, if its credentials
+// expire within the profile's refreshBefore, and returns how long until they are to be refreshed next. The update
+// has the policies delivering the secret to the clusters templated again.
+func s3OIDCSecretRefresh(ctx context.Context, c client.Client, s3StoreProfile *ramen.S3StoreProfile,
+	log logr.Logger,
+) (time.Duration, error) {
+	secret, err := s3SecretGet(ctx, c, s3StoreProfile.S3SecretRef)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshBefore := durationOrDefault(s3StoreProfile.OIDC.RefreshBefore, s3OIDCRefreshBeforeDefault)
+
+	expirationAnnotation := secret.GetAnnotations()[S3CredentialsExpirationAnnotation]
+	if expiration, err := time.Parse(time.RFC3339, expirationAnnotation); err == nil {
+		if refreshAfter := time.Until(expiration) - refreshBefore; refreshAfter > 0 {
+			return refreshAfter, nil
+		}
+	}
+
+	token, err := s3OIDCTokenRequest(ctx, s3StoreProfile, secret.Data[s3OIDCClientIDKey],
+		secret.Data[s3OIDCClientSecretKey])
+	if err != nil {
+		return 0, err
+	}
+
+	stsCredentials, err := s3OIDCRoleAssume(ctx, s3StoreProfile, token)
+	if err != nil {
+		return 0, err
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	secret.Data["AWS_ACCESS_KEY_ID"] = []byte(aws.StringValue(stsCredentials.AccessKeyId))
+	secret.Data["AWS_SECRET_ACCESS_KEY"] = []byte(aws.StringValue(stsCredentials.SecretAccessKey))
+	secret.Data[util.S3SessionTokenKey] = []byte(aws.StringValue(stsCredentials.SessionToken))
+
+	expiration := aws.TimeValue(stsCredentials.Expiration)
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+
+	secret.Annotations[S3CredentialsExpirationAnnotation] = expiration.UTC().Format(time.RFC3339)
+
+	if err := c.Update(ctx, secret); err != nil {
+		return 0, fmt.Errorf("failed to update secret %s/%s with refreshed credentials, %w", secret.Namespace,
+			secret.Name, err)
+	}
+
+	log.Info("S3 credentials refreshed", "profile", s3StoreProfile.S3ProfileName, "secret", secret.Name,
+		"expiration", expiration)
+
+	refreshAfter := time.Until(expiration) - refreshBefore
+	if refreshAfter < s3OIDCRefreshIntervalMin {
+		refreshAfter = s3OIDCRefreshIntervalMin
+	}
+
+	return refreshAfter, nil
+}
+
+// s3OIDCTokenRequest returns an access token of the OIDC provider of the profile for its client's credentials
+func s3OIDCTokenRequest(ctx context.Context, s3StoreProfile *ramen.S3StoreProfile, clientID, clientSecret []byte,
+) (string, error) {
+	if len(clientID) == 0 || len(clientSecret) == 0 {
+		return "", fmt.Errorf("secret %s is missing its keys %s and %s", s3StoreProfile.S3SecretRef.Name,
+			s3OIDCClientIDKey, s3OIDCClientSecretKey)
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {string(clientID)},
+		"client_secret": {string(clientSecret)},
+	}
+
+	if s3StoreProfile.OIDC.Scope != "" {
+		form.Set("scope", s3StoreProfile.OIDC.Scope)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s3OIDCRequestTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s3StoreProfile.OIDC.TokenEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("token request for %s, %w", s3StoreProfile.OIDC.TokenEndpoint, err)
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient, err := s3OIDCHTTPClient(s3StoreProfile)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("token request to %s failed, %w", s3StoreProfile.OIDC.TokenEndpoint, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request to %s failed with status %s", s3StoreProfile.OIDC.TokenEndpoint,
+			response.Status)
+	}
+
+	body := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("token response of %s invalid, %w", s3StoreProfile.OIDC.TokenEndpoint, err)
+	}
+
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("token response of %s has no access token", s3StoreProfile.OIDC.TokenEndpoint)
+	}
+
+	return body.AccessToken, nil
+}
+
+// s3OIDCRoleAssume returns short-lived credentials of the role of the profile, from the security token service of
+// its object store, for an OIDC token
+func s3OIDCRoleAssume(ctx context.Context, s3StoreProfile *ramen.S3StoreProfile, token string,
+) (*sts.Credentials, error) {
+	stsEndpoint := stringOrDefault(s3StoreProfile.OIDC.STSEndpoint, s3StoreProfile.S3CompatibleEndpoint)
+
+	options := session.Options{Config: aws.Config{
+		Credentials: credentials.AnonymousCredentials,
+		Endpoint:    aws.String(stsEndpoint),
+		Region:      aws.String(s3StoreProfile.S3Region),
+	}}
+
+	// the profile's CA certificates take precedence over those of AWS_CA_BUNDLE
+	if len(s3StoreProfile.CACertificates) > 0 {
+		options.CustomCABundle = bytes.NewReader(s3StoreProfile.CACertificates)
+	}
+
+	stsSession, err := session.NewSessionWithOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new session for %s, %w", stsEndpoint, err)
+	}
+
+	duration := durationOrDefault(s3StoreProfile.OIDC.Duration, s3OIDCDurationDefault)
+
+	ctx, cancel := context.WithTimeout(ctx, s3OIDCRequestTimeout)
+	defer cancel()
+
+	output, err := sts.New(stsSession).AssumeRoleWithWebIdentityWithContext(ctx, &sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(s3StoreProfile.OIDC.RoleARN),
+		RoleSessionName:  aws.String(s3OIDCRoleSessionName),
+		WebIdentityToken: aws.String(token),
+		DurationSeconds:  aws.Int64(int64(duration.Seconds())),
+	})
+	if err != nil {
+		return nil, processAwsError(fmt.Errorf("failed to assume role %s at %s", s3StoreProfile.OIDC.RoleARN,
+			stsEndpoint), err)
+	}
+
+	if output.Credentials == nil || output.Credentials.Expiration == nil {
+		return nil, fmt.Errorf("role %s assumed at %s without credentials", s3StoreProfile.OIDC.RoleARN, stsEndpoint)
+	}
+
+	return output.Credentials, nil
+}
+
+// s3OIDCHTTPClient returns the client of the requests to the OIDC provider of an S3 profile, trusting the profile's
+// CA certificates, if any, to verify its endpoint
+func s3OIDCHTTPClient(s3StoreProfile *ramen.S3StoreProfile) (*http.Client, error) {
+	httpClient := &http.Client{}
+
+	if len(s3StoreProfile.CACertificates) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(s3StoreProfile.CACertificates) {
+			return nil, fmt.Errorf("s3 profile %s caCertificates has no certificates", s3StoreProfile.S3ProfileName)
+		}
+
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+		}
+	}
+
+	return httpClient, nil
+}
+
+// s3OIDCProfileFormatCheck returns an error if the OIDC settings of an S3 profile are incomplete
+func s3OIDCProfileFormatCheck(s3StoreProfile *ramen.S3StoreProfile) error {
+	oidc := s3StoreProfile.OIDC
+
+	if _, err := url.ParseRequestURI(oidc.TokenEndpoint); err != nil {
+		return fmt.Errorf("invalid oidc token endpoint <%s> in profile %s, reason: %w", oidc.TokenEndpoint,
+			s3StoreProfile.S3ProfileName, err)
+	}
+
+	if oidc.RoleARN == "" {
+		return fmt.Errorf("oidc role ARN has not been configured in s3 profile %s", s3StoreProfile.S3ProfileName)
+	}
+
+	if oidc.STSEndpoint != "" {
+		if _, err := url.ParseRequestURI(oidc.STSEndpoint); err != nil {
+			return fmt.Errorf("invalid oidc sts endpoint <%s> in profile %s, reason: %w", oidc.STSEndpoint,
+				s3StoreProfile.S3ProfileName, err)
+		}
+	}
+
+	return nil
+}
+
+func durationOrDefault(duration *metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if duration == nil || duration.Duration <= 0 {
+		return defaultDuration
+	}
+
+	return duration.Duration
+}
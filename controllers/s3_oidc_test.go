@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the requests of short-lived S3 credentials to endpoints of a private CA
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("S3_OIDC", func() {
+	var (
+		server         *httptest.Server
+		s3StoreProfile *ramen.S3StoreProfile
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+
+			if r.Form.Get("grant_type") == "client_credentials" {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"access_token":"token"}`))
+
+				return
+			}
+
+			Expect(r.Form.Get("WebIdentityToken")).To(Equal("token"))
+			w.Header().Set("Content-Type", "text/xml")
+			_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse>
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>access</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+		}))
+		DeferCleanup(server.Close)
+
+		s3StoreProfile = &ramen.S3StoreProfile{
+			S3ProfileName:        "east",
+			S3CompatibleEndpoint: server.URL,
+			S3Region:             "us-east-1",
+			OIDC: &ramen.S3OIDCProfile{
+				TokenEndpoint: server.URL + "/token",
+				RoleARN:       "arn:aws:iam:::role/ramen",
+			},
+			CACertificates: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		}
+	})
+
+	It("requests the token and assumes the role at endpoints of the profile's CA", func() {
+		token, err := s3OIDCTokenRequest(context.TODO(), s3StoreProfile, []byte("id"), []byte("secret"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("token"))
+
+		credentials, err := s3OIDCRoleAssume(context.TODO(), s3StoreProfile, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.StringValue(credentials.AccessKeyId)).To(Equal("access"))
+		Expect(aws.StringValue(credentials.SessionToken)).To(Equal("session"))
+	})
+
+	It("fails to verify the endpoints without the profile's CA", func() {
+		s3StoreProfile.CACertificates = nil
+
+		_, err := s3OIDCTokenRequest(context.TODO(), s3StoreProfile, []byte("id"), []byte("secret"))
+		Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+		_, err = s3OIDCRoleAssume(context.TODO(), s3StoreProfile, "token")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails for CA certificates that are not", func() {
+		s3StoreProfile.CACertificates = []byte("ca")
+
+		_, err := s3OIDCTokenRequest(context.TODO(), s3StoreProfile, []byte("id"), []byte("secret"))
+		Expect(err).To(MatchError(ContainSubstring("caCertificates")))
+
+		_, err = s3OIDCRoleAssume(context.TODO(), s3StoreProfile, "token")
+		Expect(err).To(HaveOccurred())
+	})
+})
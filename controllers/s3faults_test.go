@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+type objectStoreOperation string
+
+const (
+	objectStoreOperationUpload   = objectStoreOperation("upload")
+	objectStoreOperationDownload = objectStoreOperation("download")
+	objectStoreOperationList     = objectStoreOperation("list")
+	objectStoreOperationDelete   = objectStoreOperation("delete")
+)
+
+// objectStoreFault is a fault injected into the operations of a fake object store on keys with a prefix
+type objectStoreFault struct {
+	operation objectStoreOperation
+	keyPrefix string
+	// latency delays each matching call, whether it fails or not
+	latency time.Duration
+	// every fails one in every so many matching calls, starting with the last of the first so many, rather than
+	// every call, when greater than one
+	every int
+	// corrupt downloads the object from a truncated encoding, so that its decoding fails
+	corrupt bool
+	// err is returned by a failing call that is not corrupt, or an aws internal error if nil
+	err error
+
+	calls int
+}
+
+// objectStoreFaults are the faults injected into a fake object store, shared by its copies
+type objectStoreFaults struct {
+	mutex  sync.Mutex
+	faults []*objectStoreFault
+}
+
+// fakeObjectStorerFaults returns the faults of the fake object store of a profile
+func fakeObjectStorerFaults(s3ProfileName string) *objectStoreFaults {
+	objectStorer, ok := fakeObjectStorers[s3ProfileName]
+	if !ok {
+		panic(fmt.Sprintf("fake object store of profile %s not created", s3ProfileName))
+	}
+
+	return objectStorer.faults
+}
+
+// inject adds faults, with their call counts reset
+func (f *objectStoreFaults) inject(faults ...objectStoreFault) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for i := range faults {
+		fault := faults[i]
+		fault.calls = 0
+		f.faults = append(f.faults, &fault)
+	}
+}
+
+// clear removes all faults
+func (f *objectStoreFaults) clear() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.faults = nil
+}
+
+// apply delays a call of an operation on a key by the latency of its matching faults, and returns whether its
+// download is to be corrupted, or the error it is to fail with, if any
+func (f *objectStoreFaults) apply(operation objectStoreOperation, key string) (bool, error) {
+	f.mutex.Lock()
+
+	var (
+		latency time.Duration
+		corrupt bool
+		err     error
+	)
+
+	for _, fault := range f.faults {
+		if fault.operation != operation || !strings.HasPrefix(key, fault.keyPrefix) {
+			continue
+		}
+
+		fault.calls++
+		latency += fault.latency
+
+		if corrupt || err != nil || (fault.every > 1 && fault.calls%fault.every != 0) {
+			continue
+		}
+
+		if fault.corrupt {
+			corrupt = true
+
+			continue
+		}
+
+		err = fault.err
+		if err == nil {
+			err = awserr.New("InternalError", fmt.Sprintf("fake %s fault", operation), nil)
+		}
+	}
+
+	f.mutex.Unlock()
+	time.Sleep(latency)
+
+	return corrupt, err
+}
+
+// corruptDownload decodes an object into objectPointer from the first half of its json encoding, and fails as a
+// download of an object whose stored data is corrupt would
+func corruptDownload(key string, object, objectPointer interface{}) error {
+	objectJSON, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(objectJSON[:len(objectJSON)/2], objectPointer)
+	if err == nil {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("failed to decode json decoder of %s, %w", key, err)
+}
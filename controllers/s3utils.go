@@ -23,6 +23,7 @@ import (
 	"github.com/go-logr/logr"
 	errorswrapper "github.com/pkg/errors"
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -91,13 +92,23 @@ type ObjectStoreGetter interface {
 
 type ObjectStorer interface {
 	UploadObject(key string, object interface{}) error
+	UploadObjectRaw(key string, data []byte, contentType string) error
 	DownloadObject(key string, objectPointer interface{}) error
+	DownloadObjectRaw(key string) ([]byte, error)
 	ListKeys(keyPrefix string) (keys []string, err error)
+	ListObjects(keyPrefix string) (objects []ObjectInfo, err error)
 	DeleteObject(key string) error
 	DeleteObjects(key ...string) error
 	DeleteObjectsWithKeyPrefix(keyPrefix string) error
 }
 
+// ObjectInfo describes an object in an object store
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
 // S3ObjectStoreGetter returns a concrete type that implements
 // the ObjectStoreGetter interface, allowing the concrete type
 // to be not exported.
@@ -124,7 +135,7 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 			s3ProfileName, callerTag, err)
 	}
 
-	accessID, secretAccessKey, err := GetS3Secret(ctx, r, s3StoreProfile.S3SecretRef)
+	secret, err := s3SecretGet(ctx, r, s3StoreProfile.S3SecretRef)
 	if err != nil {
 		return nil, s3StoreProfile, fmt.Errorf("failed to get secret %v for caller %s, %w",
 			s3StoreProfile.S3SecretRef, callerTag, err)
@@ -135,8 +146,8 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 
 	// Create an S3 client session
 	s3Session, err := session.NewSession(&aws.Config{
-		Credentials: credentials.NewStaticCredentials(string(accessID),
-			string(secretAccessKey), ""),
+		Credentials: credentials.NewStaticCredentials(string(secret.Data["AWS_ACCESS_KEY_ID"]),
+			string(secret.Data["AWS_SECRET_ACCESS_KEY"]), string(secret.Data[util.S3SessionTokenKey])),
 		Endpoint:         aws.String(s3Endpoint),
 		Region:           aws.String(s3Region),
 		DisableSSL:       aws.Bool(true),
@@ -175,7 +186,20 @@ func GetS3Secret(ctx context.Context, r client.Reader,
 	secretRef corev1.SecretReference) (
 	s3AccessID, s3SecretAccessKey []byte, err error,
 ) {
-	secret := corev1.Secret{}
+	secret, err := s3SecretGet(ctx, r, secretRef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s3AccessID = secret.Data["AWS_ACCESS_KEY_ID"]
+	s3SecretAccessKey = secret.Data["AWS_SECRET_ACCESS_KEY"]
+
+	return
+}
+
+// s3SecretGet returns the secret of an S3 profile, from the ramen operator's namespace if the reference has none
+func s3SecretGet(ctx context.Context, r client.Reader, secretRef corev1.SecretReference) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
 	namepacedName := types.NamespacedName{Namespace: "", Name: secretRef.Name}
 
 	if secretRef.Namespace == "" {
@@ -184,15 +208,12 @@ func GetS3Secret(ctx context.Context, r client.Reader,
 		namepacedName.Namespace = secretRef.Namespace
 	}
 
-	if err := r.Get(ctx, namepacedName, &secret); err != nil {
-		return nil, nil, fmt.Errorf("failed to get secret %v, %w",
+	if err := r.Get(ctx, namepacedName, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %v, %w",
 			secretRef, err)
 	}
 
-	s3AccessID = secret.Data["AWS_ACCESS_KEY_ID"]
-	s3SecretAccessKey = secret.Data["AWS_SECRET_ACCESS_KEY"]
-
-	return
+	return secret, nil
 }
 
 type s3ObjectStore struct {
@@ -438,6 +459,28 @@ func (s *s3ObjectStore) UploadObject(key string,
 	return nil
 }
 
+// UploadObjectRaw uploads the given data to the bucket with the given key as is, for it to be read without ramen,
+// with the given content type
+func (s *s3ObjectStore) UploadObjectRaw(key string, data []byte, contentType string) error {
+	bucket := s.s3Bucket
+
+	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
+	defer cancel()
+
+	if _, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      &bucket,
+		Key:         &key,
+		Body:        bytes.NewReader(data),
+		ContentType: &contentType,
+	}); err != nil {
+		errMsgPrefix := fmt.Errorf("failed to upload data of %s:%s", bucket, key)
+
+		return processAwsError(errMsgPrefix, err)
+	}
+
+	return nil
+}
+
 // downloadPVs downloads all PVs in the bucket.
 // - Downloads PVs with the given key prefix.
 // - If bucket doesn't exists, will return ErrCodeNoSuchBucket "NoSuchBucket"
@@ -508,6 +551,24 @@ func DownloadTypedObjects(s ObjectStorer, keyPrefix string, objectsPointer inter
 // - Refer to aws documentation of s3.ListObjectsV2Input for more list options
 func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 	keys []string, err error,
+) {
+	objects, err := s.ListObjects(keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+
+	return keys, nil
+}
+
+// ListObjects lists the keys, sizes and modification times of the objects with
+// the given keyPrefix in the bucket.
+// - If bucket doesn't exists, will return ErrCodeNoSuchBucket "NoSuchBucket"
+func (s *s3ObjectStore) ListObjects(keyPrefix string) (
+	objects []ObjectInfo, err error,
 ) {
 	var nextContinuationToken *string
 
@@ -529,7 +590,11 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 		}
 
 		for _, entry := range result.Contents {
-			keys = append(keys, *entry.Key)
+			objects = append(objects, ObjectInfo{
+				Key:          aws.StringValue(entry.Key),
+				Size:         aws.Int64Value(entry.Size),
+				LastModified: aws.TimeValue(entry.LastModified),
+			})
 		}
 
 		if *result.IsTruncated {
@@ -539,7 +604,7 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 		}
 	}
 
-	return keys, nil
+	return objects, nil
 }
 
 // DownloadObject downloads an object from the bucket with the given key,
@@ -560,21 +625,13 @@ func (s *s3ObjectStore) DownloadObject(key string,
 	downloadContent interface{},
 ) error {
 	bucket := s.s3Bucket
-	writerAt := &aws.WriteAtBuffer{}
 
-	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
-	defer cancel()
-
-	if _, err := s.downloader.DownloadWithContext(ctx, writerAt, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	}); err != nil {
-		errMsgPrefix := fmt.Errorf("failed to download data of %s:%s", bucket, key)
-
-		return processAwsError(errMsgPrefix, err)
+	data, err := s.DownloadObjectRaw(key)
+	if err != nil {
+		return err
 	}
 
-	gzReader, err := gzip.NewReader(bytes.NewReader(writerAt.Bytes()))
+	gzReader, err := gzip.NewReader(bytes.NewReader(data))
 	if err != nil && !errorswrapper.Is(err, io.EOF) {
 		return fmt.Errorf("failed to unzip data of %s:%s, %w",
 			bucket, key, err)
@@ -593,6 +650,27 @@ func (s *s3ObjectStore) DownloadObject(key string,
 	return nil
 }
 
+// DownloadObjectRaw downloads the data of an object as stored, for objects that are not gzipped json blobs, such
+// as those uploaded by other tools
+func (s *s3ObjectStore) DownloadObjectRaw(key string) ([]byte, error) {
+	bucket := s.s3Bucket
+	writerAt := &aws.WriteAtBuffer{}
+
+	ctx, cancel := context.WithDeadline(context.TODO(), time.Now().Add(s3Timeout))
+	defer cancel()
+
+	if _, err := s.downloader.DownloadWithContext(ctx, writerAt, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	}); err != nil {
+		errMsgPrefix := fmt.Errorf("failed to download data of %s:%s", bucket, key)
+
+		return nil, processAwsError(errMsgPrefix, err)
+	}
+
+	return writerAt.Bytes(), nil
+}
+
 func (s *s3ObjectStore) DeleteObject(key string) error {
 	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
 		Bucket: aws.String(s.s3Bucket),
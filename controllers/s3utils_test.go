@@ -5,10 +5,14 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -17,6 +21,8 @@ import (
 	. "github.com/onsi/gomega"
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
 	"github.com/ramendr/ramen/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -72,6 +78,7 @@ func (fakeObjectStoreGetter) ObjectStore(
 			name:       s3ProfileName,
 			bucketName: s3StoreProfile.S3Bucket,
 			objects:    make(map[string]interface{}),
+			faults:     &objectStoreFaults{},
 		}
 		fakeObjectStorers[s3ProfileName] = objectStorer
 	}
@@ -83,6 +90,7 @@ type fakeObjectStorer struct {
 	name       string
 	bucketName string
 	objects    map[string]interface{}
+	faults     *objectStoreFaults
 }
 
 func (f fakeObjectStorer) UploadObject(key string, object interface{}) error {
@@ -90,12 +98,25 @@ func (f fakeObjectStorer) UploadObject(key string, object interface{}) error {
 		return awserr.New(s3.ErrCodeInvalidObjectState, "fake error uploading object", fmt.Errorf("fake error"))
 	}
 
+	if _, err := f.faults.apply(objectStoreOperationUpload, key); err != nil {
+		return err
+	}
+
 	f.objects[key] = object
 
 	return nil
 }
 
+func (f fakeObjectStorer) UploadObjectRaw(key string, data []byte, contentType string) error {
+	return f.UploadObject(key, data)
+}
+
 func (f fakeObjectStorer) DownloadObject(key string, objectPointer interface{}) error {
+	corrupt, err := f.faults.apply(objectStoreOperationDownload, key)
+	if err != nil {
+		return err
+	}
+
 	object, ok := f.objects[key]
 
 	objectDestination := reflect.ValueOf(objectPointer).Elem()
@@ -105,6 +126,10 @@ func (f fakeObjectStorer) DownloadObject(key string, objectPointer interface{})
 		return fs.ErrNotExist
 	}
 
+	if corrupt {
+		return corruptDownload(key, object, objectPointer)
+	}
+
 	objectSource := reflect.ValueOf(object)
 	Expect(objectSource.IsValid()).To(BeTrue())
 	objectDestination.Set(objectSource)
@@ -112,11 +137,33 @@ func (f fakeObjectStorer) DownloadObject(key string, objectPointer interface{})
 	return nil
 }
 
+// DownloadObjectRaw returns an object uploaded as bytes as is, and the JSON encoding of any other
+func (f fakeObjectStorer) DownloadObjectRaw(key string) ([]byte, error) {
+	if _, err := f.faults.apply(objectStoreOperationDownload, key); err != nil {
+		return nil, err
+	}
+
+	object, ok := f.objects[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+
+	if data, ok := object.([]byte); ok {
+		return data, nil
+	}
+
+	return json.Marshal(object)
+}
+
 func (f fakeObjectStorer) ListKeys(keyPrefix string) ([]string, error) {
 	if f.bucketName == bucketListFail {
 		return nil, fmt.Errorf("Failing bucket listing")
 	}
 
+	if _, err := f.faults.apply(objectStoreOperationList, keyPrefix); err != nil {
+		return nil, err
+	}
+
 	keys := []string{}
 
 	for k := range f.objects {
@@ -128,7 +175,31 @@ func (f fakeObjectStorer) ListKeys(keyPrefix string) ([]string, error) {
 	return keys, nil
 }
 
+func (f fakeObjectStorer) ListObjects(keyPrefix string) ([]controllers.ObjectInfo, error) {
+	keys, err := f.ListKeys(keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]controllers.ObjectInfo, 0, len(keys))
+
+	for _, key := range keys {
+		objectJSON, err := json.Marshal(f.objects[key])
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, controllers.ObjectInfo{Key: key, Size: int64(len(objectJSON))})
+	}
+
+	return objects, nil
+}
+
 func (f fakeObjectStorer) DeleteObject(key string) error {
+	if _, err := f.faults.apply(objectStoreOperationDelete, key); err != nil {
+		return err
+	}
+
 	delete(f.objects, key)
 
 	return nil
@@ -136,6 +207,10 @@ func (f fakeObjectStorer) DeleteObject(key string) error {
 
 func (f fakeObjectStorer) DeleteObjects(keys ...string) error {
 	for _, key := range keys {
+		if _, err := f.faults.apply(objectStoreOperationDelete, key); err != nil {
+			return err
+		}
+
 		delete(f.objects, key)
 	}
 
@@ -143,6 +218,10 @@ func (f fakeObjectStorer) DeleteObjects(keys ...string) error {
 }
 
 func (f fakeObjectStorer) DeleteObjectsWithKeyPrefix(keyPrefix string) error {
+	if _, err := f.faults.apply(objectStoreOperationDelete, keyPrefix); err != nil {
+		return err
+	}
+
 	for key := range f.objects {
 		if strings.HasPrefix(key, keyPrefix) {
 			delete(f.objects, key)
@@ -195,4 +274,58 @@ var _ = Describe("FakeObjectStorer", func() {
 			Expect(objectStorer.DeleteObject(key2)).To(Succeed())
 		})
 	})
+	Context("Faults", func() {
+		var faults *objectStoreFaults
+		BeforeEach(func() {
+			faults = fakeObjectStorerFaults(s3Profiles[objS3ProfileNumber].S3ProfileName)
+		})
+		AfterEach(func() {
+			faults.clear()
+		})
+		It("should fail every other upload of a key with the fault's prefix", func() {
+			faults.inject(objectStoreFault{operation: objectStoreOperationUpload, keyPrefix: key1, every: 2})
+			Expect(objectStorer.UploadObject(key1, object)).To(Succeed())
+			Expect(objectStorer.UploadObject(key1, object)).To(HaveOccurred())
+			Expect(objectStorer.UploadObject(key1, object)).To(Succeed())
+			Expect(objectStorer.UploadObject(key, object)).To(Succeed())
+			Expect(objectStorer.UploadObject(key2, object)).To(HaveOccurred())
+		})
+		It("should fail a download of a corrupt object with a decoding error", func() {
+			pv := corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv"}}
+			Expect(controllers.UploadPV(objectStorer, key, pv.Name, pv)).To(Succeed())
+			faults.inject(objectStoreFault{operation: objectStoreOperationDownload, corrupt: true})
+			pv1 := corev1.PersistentVolume{}
+			err := controllers.DownloadTypedObject(objectStorer, key, pv.Name, &pv1)
+			Expect(err).To(HaveOccurred())
+			var syntaxError *json.SyntaxError
+			Expect(errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue(), err.Error())
+		})
+		It("should fail to download objects of a type whose listing fails", func() {
+			vrg := ramen.VolumeReplicationGroup{ObjectMeta: metav1.ObjectMeta{Name: "vrg"}}
+			Expect(objectStorer.UploadObject(controllers.TypedObjectKey(key, vrg.Name, vrg), vrg)).To(Succeed())
+			faults.inject(objectStoreFault{
+				operation: objectStoreOperationList,
+				keyPrefix: controllers.TypedObjectKey(key, "", vrg),
+				err:       fs.ErrPermission,
+			})
+			_, err := controllers.DownloadVRGs(objectStorer, key)
+			Expect(err).To(MatchError(fs.ErrPermission))
+			_, err = controllers.DownloadVRGs(objectStorer, key1)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		It("should not delete an object whose deletion fails", func() {
+			Expect(objectStorer.UploadObject(key, object)).To(Succeed())
+			faults.inject(objectStoreFault{operation: objectStoreOperationDelete})
+			Expect(objectStorer.DeleteObject(key)).To(HaveOccurred())
+			var object1 string
+			Expect(objectStorer.DownloadObject(key, &object1)).To(Succeed())
+		})
+		It("should delay calls by the fault's latency", func() {
+			const latency = 50 * time.Millisecond
+			faults.inject(objectStoreFault{operation: objectStoreOperationUpload, latency: latency, every: 1000})
+			start := time.Now()
+			Expect(objectStorer.UploadObject(key, object)).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">=", latency))
+		})
+	})
 })
@@ -40,6 +40,36 @@ const (
 	// type is removed from VRG status.
 	VRGTotalConditions = 4
 
+	// PV cluster data has not drifted. This condition is only reported by a Secondary VRG, and indicates whether
+	// the PV cluster data in the S3 store is consistent with the PVs on the cluster, so that a restore on
+	// failover or relocate will not run into conflicts. It is not counted in VRGTotalConditions as it is not
+	// set initially.
+	VRGConditionTypeNoClusterDataDrift = "NoClusterDataDrift"
+
+	// Namespace resource quotas and limit ranges are sufficient. This condition is only reported by a Primary VRG
+	// that recovers cluster data, and indicates whether the resource quotas and limit ranges of the protected
+	// namespaces admit the workload sized by the VRG it recovers from. It is not counted in VRGTotalConditions as it
+	// is not set initially.
+	VRGConditionTypeNamespaceQuotasSufficient = "NamespaceQuotasSufficient"
+
+	// Kube object versions are supported. This condition is only reported by a Primary VRG that recovers kube
+	// objects, and indicates whether this cluster serves the kinds of the objects of the capture it recovers from,
+	// at the versions they were captured at or at others Velero restores them at. It is not counted in
+	// VRGTotalConditions as it is not set initially.
+	VRGConditionTypeKubeObjectVersionsSupported = "KubeObjectVersionsSupported"
+
+	// Access modes of the restored PVCs are preserved. This condition is only reported, as False, by a Primary VRG
+	// that rewrote the access modes of the PVCs it restored with the access mode mappings of the cluster, and lists
+	// those PVCs. It is not counted in VRGTotalConditions as it is not set initially.
+	VRGConditionTypeAccessModesPreserved = "AccessModesPreserved"
+
+	// Operators recovery conditions. These conditions are only reported by a Primary VRG that recovers operators,
+	// subscribed to in the protected namespaces, before their custom resources, and indicate whether each step of
+	// waiting for the recovered operators to be ready is complete. They are not counted in VRGTotalConditions.
+	VRGConditionTypeOperatorsInstalled      = "OperatorsInstalled"
+	VRGConditionTypeOperatorCRDsEstablished = "OperatorCRDsEstablished"
+	VRGConditionTypeOperatorWebhooksReady   = "OperatorWebhooksReady"
+
 	// VolSync related conditions. These conditions are only applicable
 	// at individual PVCs and not generic VRG conditions.
 	VRGConditionTypeVolSyncRepSourceSetup      = "ReplicationSourceSetup"
@@ -69,6 +99,16 @@ const (
 	VRGConditionReasonVolSyncFinalSyncInProgress  = "Syncing"
 	VRGConditionReasonVolSyncFinalSyncComplete    = "Synced"
 	VRGConditionReasonClusterDataAnnotationFailed = "AnnotationFailed"
+	VRGConditionReasonNoDrift                     = "NoDrift"
+	VRGConditionReasonDriftDetected               = "DriftDetected"
+	VRGConditionReasonWaiting                     = "Waiting"
+	VRGConditionReasonTimedOut                    = "TimedOut"
+	VRGConditionReasonQuotasSufficient            = "QuotasSufficient"
+	VRGConditionReasonQuotasInsufficient          = "QuotasInsufficient"
+	VRGConditionReasonAccessModesMapped           = "AccessModesMapped"
+	VRGConditionReasonVersionsSupported           = "VersionsSupported"
+	VRGConditionReasonVersionsUnsupported         = "VersionsUnsupported"
+	VRGConditionReasonVersionsUnchecked           = "VersionsUnchecked"
 )
 
 const clusterDataProtectedTrueMessage = "Kube objects protected"
@@ -291,6 +331,90 @@ func setVRGClusterDataErrorCondition(conditions *[]metav1.Condition, observedGen
 }
 
 // sets conditions when PV cluster data is protected
+// sets conditions when Secondary VRG detects (or does not detect) drift in PV cluster data
+func setVRGNoClusterDataDriftCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	drifted bool, message string,
+) {
+	condition := metav1.Condition{
+		Type:               VRGConditionTypeNoClusterDataDrift,
+		Reason:             VRGConditionReasonNoDrift,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+	}
+
+	if drifted {
+		condition.Reason = VRGConditionReasonDriftDetected
+		condition.Status = metav1.ConditionFalse
+	}
+
+	setStatusCondition(conditions, condition)
+}
+
+// sets conditions when Primary VRG finds the namespace resource quotas and limit ranges (in)sufficient
+func setVRGNamespaceQuotasSufficientCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	sufficient bool, message string,
+) {
+	condition := metav1.Condition{
+		Type:               VRGConditionTypeNamespaceQuotasSufficient,
+		Reason:             VRGConditionReasonQuotasSufficient,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+	}
+
+	if !sufficient {
+		condition.Reason = VRGConditionReasonQuotasInsufficient
+		condition.Status = metav1.ConditionFalse
+	}
+
+	setStatusCondition(conditions, condition)
+}
+
+// sets conditions when Primary VRG finds the kinds of the kube objects it recovers (un)supported
+func setVRGKubeObjectVersionsSupportedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	supported bool, message string,
+) {
+	condition := metav1.Condition{
+		Type:               VRGConditionTypeKubeObjectVersionsSupported,
+		Reason:             VRGConditionReasonVersionsSupported,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionTrue,
+		Message:            message,
+	}
+
+	if !supported {
+		condition.Reason = VRGConditionReasonVersionsUnsupported
+		condition.Status = metav1.ConditionFalse
+	}
+
+	setStatusCondition(conditions, condition)
+}
+
+// sets conditions when the VRG could not check the versions of the kinds of the kube objects it recovers
+func setVRGKubeObjectVersionsUncheckedCondition(conditions *[]metav1.Condition, observedGeneration int64,
+	message string,
+) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeKubeObjectVersionsSupported,
+		Reason:             VRGConditionReasonVersionsUnchecked,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionUnknown,
+		Message:            message,
+	})
+}
+
+// sets conditions when Primary VRG rewrote the access modes of the PVCs it restored
+func setVRGAccessModesMappedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
+	setStatusCondition(conditions, metav1.Condition{
+		Type:               VRGConditionTypeAccessModesPreserved,
+		Reason:             VRGConditionReasonAccessModesMapped,
+		ObservedGeneration: observedGeneration,
+		Status:             metav1.ConditionFalse,
+		Message:            message,
+	})
+}
+
 func setVRGClusterDataProtectedCondition(conditions *[]metav1.Condition, observedGeneration int64, message string) {
 	setStatusCondition(conditions, *newVRGClusterDataProtectedCondition(observedGeneration, message))
 }
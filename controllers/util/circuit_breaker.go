@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	ClusterCircuitBreakerFailureThreshold = 5
+	ClusterCircuitBreakerBaseBackoff      = 10 * time.Second
+	ClusterCircuitBreakerMaxBackoff       = 5 * time.Minute
+)
+
+// ErrClusterCircuitOpen is returned for an operation against a managed cluster whose circuit breaker is open
+var ErrClusterCircuitOpen = errors.New("managed cluster circuit breaker is open")
+
+// ClusterCircuitBreaker tracks consecutive failures of operations against each managed cluster. Once the failures
+// reach the threshold the cluster's breaker opens, and operations against the cluster are refused until a backoff
+// elapses. A single probe operation is then allowed per backoff: its success closes the breaker, and its failure
+// reopens it with double the backoff, up to a maximum. A nil breaker allows all operations.
+type ClusterCircuitBreaker struct {
+	mutex            sync.Mutex
+	clusters         map[string]*clusterCircuit
+	failureThreshold int
+	baseBackoff      time.Duration
+	maxBackoff       time.Duration
+	onStateChange    func(cluster string, open bool)
+}
+
+type clusterCircuit struct {
+	failures  int
+	openUntil time.Time
+	backoff   time.Duration
+	lastError string
+}
+
+// ClusterCircuitState is the state of a managed cluster's circuit breaker
+type ClusterCircuitState struct {
+	Open       bool
+	RetryAfter time.Duration
+	Failures   int
+	LastError  string
+}
+
+func NewClusterCircuitBreaker(failureThreshold int, baseBackoff, maxBackoff time.Duration) *ClusterCircuitBreaker {
+	return &ClusterCircuitBreaker{
+		clusters:         map[string]*clusterCircuit{},
+		failureThreshold: failureThreshold,
+		baseBackoff:      baseBackoff,
+		maxBackoff:       maxBackoff,
+	}
+}
+
+// OnStateChange sets a function called, without the breaker locked, as a cluster's breaker opens or closes
+func (b *ClusterCircuitBreaker) OnStateChange(onStateChange func(cluster string, open bool)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.onStateChange = onStateChange
+}
+
+// Allow returns ErrClusterCircuitOpen if operations against the cluster are to be refused
+func (b *ClusterCircuitBreaker) Allow(cluster string) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	circuit, ok := b.clusters[cluster]
+	if !ok || circuit.failures < b.failureThreshold {
+		return nil
+	}
+
+	if retryAfter := time.Until(circuit.openUntil); retryAfter > 0 {
+		return fmt.Errorf("%w for cluster %s, retry after %v, last error: %s",
+			ErrClusterCircuitOpen, cluster, retryAfter.Round(time.Second), circuit.lastError)
+	}
+
+	// Allow this operation as the probe, and refuse others until it completes or another backoff elapses
+	circuit.openUntil = time.Now().Add(circuit.backoff)
+
+	return nil
+}
+
+// RecordSuccess closes the cluster's breaker
+func (b *ClusterCircuitBreaker) RecordSuccess(cluster string) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+
+	circuit, ok := b.clusters[cluster]
+	opened := ok && circuit.failures >= b.failureThreshold
+	onStateChange := b.onStateChange
+
+	delete(b.clusters, cluster)
+	b.mutex.Unlock()
+
+	if opened && onStateChange != nil {
+		onStateChange(cluster, false)
+	}
+}
+
+// RecordFailure counts a failed operation against the cluster, opening its breaker once the failures reach the
+// threshold, and doubling the backoff on each failure after that
+func (b *ClusterCircuitBreaker) RecordFailure(cluster string, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	opened := b.recordFailure(cluster, err)
+	onStateChange := b.onStateChange
+	b.mutex.Unlock()
+
+	if opened && onStateChange != nil {
+		onStateChange(cluster, true)
+	}
+}
+
+// recordFailure returns true if the failure opened the cluster's breaker
+func (b *ClusterCircuitBreaker) recordFailure(cluster string, err error) bool {
+	circuit, ok := b.clusters[cluster]
+	if !ok {
+		circuit = &clusterCircuit{}
+		b.clusters[cluster] = circuit
+	}
+
+	circuit.failures++
+
+	if err != nil {
+		circuit.lastError = err.Error()
+	}
+
+	if circuit.failures < b.failureThreshold {
+		return false
+	}
+
+	switch {
+	case circuit.backoff == 0:
+		circuit.backoff = b.baseBackoff
+	case circuit.backoff < b.maxBackoff:
+		circuit.backoff *= 2
+		if circuit.backoff > b.maxBackoff {
+			circuit.backoff = b.maxBackoff
+		}
+	}
+
+	circuit.openUntil = time.Now().Add(circuit.backoff)
+
+	return circuit.failures == b.failureThreshold
+}
+
+// State returns the state of the cluster's breaker
+func (b *ClusterCircuitBreaker) State(cluster string) ClusterCircuitState {
+	if b == nil {
+		return ClusterCircuitState{}
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	circuit, ok := b.clusters[cluster]
+	if !ok {
+		return ClusterCircuitState{}
+	}
+
+	state := ClusterCircuitState{
+		Open:      circuit.failures >= b.failureThreshold,
+		Failures:  circuit.failures,
+		LastError: circuit.lastError,
+	}
+
+	if state.Open {
+		state.RetryAfter = time.Until(circuit.openUntil)
+		if state.RetryAfter < 0 {
+			state.RetryAfter = 0
+		}
+	}
+
+	return state
+}
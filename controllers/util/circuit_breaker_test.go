@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("ClusterCircuitBreaker", func() {
+	const (
+		cluster   = "cluster1"
+		threshold = 3
+		backoff   = 50 * time.Millisecond
+	)
+
+	errFailure := errors.New("view failed")
+
+	var breaker *util.ClusterCircuitBreaker
+
+	BeforeEach(func() {
+		breaker = util.NewClusterCircuitBreaker(threshold, backoff, 4*backoff)
+	})
+
+	recordFailures := func(count int) {
+		for i := 0; i < count; i++ {
+			breaker.RecordFailure(cluster, errFailure)
+		}
+	}
+
+	It("allows operations until failures reach the threshold", func() {
+		recordFailures(threshold - 1)
+		Expect(breaker.Allow(cluster)).To(Succeed())
+		Expect(breaker.State(cluster).Open).To(BeFalse())
+
+		recordFailures(1)
+		Expect(errors.Is(breaker.Allow(cluster), util.ErrClusterCircuitOpen)).To(BeTrue())
+		Expect(breaker.State(cluster).Open).To(BeTrue())
+		Expect(breaker.State(cluster).LastError).To(Equal(errFailure.Error()))
+		Expect(breaker.Allow("cluster2")).To(Succeed())
+	})
+
+	It("allows a single probe once the backoff elapses, and closes on its success", func() {
+		recordFailures(threshold)
+		Eventually(func() error { return breaker.Allow(cluster) }, 4*backoff, backoff/10).Should(Succeed())
+		Expect(errors.Is(breaker.Allow(cluster), util.ErrClusterCircuitOpen)).To(BeTrue())
+
+		breaker.RecordSuccess(cluster)
+		Expect(breaker.Allow(cluster)).To(Succeed())
+		Expect(breaker.State(cluster)).To(Equal(util.ClusterCircuitState{}))
+	})
+
+	It("doubles the backoff on each failed probe, up to the maximum", func() {
+		recordFailures(threshold)
+		Expect(breaker.State(cluster).RetryAfter).To(BeNumerically("<=", backoff))
+
+		recordFailures(1)
+		Expect(breaker.State(cluster).RetryAfter).To(BeNumerically(">", backoff))
+
+		recordFailures(3)
+		Expect(breaker.State(cluster).RetryAfter).To(BeNumerically("<=", 4*backoff))
+	})
+
+	It("reports opening and closing", func() {
+		changes := []bool{}
+		breaker.OnStateChange(func(_ string, open bool) { changes = append(changes, open) })
+
+		recordFailures(threshold + 1)
+		breaker.RecordSuccess(cluster)
+		breaker.RecordSuccess(cluster)
+		Expect(changes).To(Equal([]bool{true, false}))
+	})
+
+	It("allows all operations if nil", func() {
+		var nilBreaker *util.ClusterCircuitBreaker
+
+		nilBreaker.RecordFailure(cluster, errFailure)
+		Expect(nilBreaker.Allow(cluster)).To(Succeed())
+	})
+})
@@ -26,6 +26,16 @@ func DRPolicyClusterNamesAsASet(drpolicy *rmn.DRPolicy) sets.String {
 	return sets.NewString(DRPolicyClusterNames(drpolicy)...)
 }
 
+// DRClusterRegion returns the region of a DRCluster, as set in its spec, or as last reported in its status, as
+// claimed by its managed cluster, otherwise
+func DRClusterRegion(drCluster *rmn.DRCluster) rmn.Region {
+	if drCluster.Spec.Region != "" {
+		return drCluster.Spec.Region
+	}
+
+	return drCluster.Status.Region
+}
+
 func DrpolicyRegionNames(drpolicy *rmn.DRPolicy, drClusters []rmn.DRCluster) []string {
 	regionNames := make([]string, len(DRPolicyClusterNames(drpolicy)))
 
@@ -34,7 +44,7 @@ func DrpolicyRegionNames(drpolicy *rmn.DRPolicy, drClusters []rmn.DRCluster) []s
 
 		for _, drCluster := range drClusters {
 			if drCluster.Name == v {
-				regionName = string(drCluster.Spec.Region)
+				regionName = string(DRClusterRegion(&drCluster))
 			}
 		}
 
@@ -29,6 +29,9 @@ const (
 	// EventReasonPVCListFailed is used when VRG fails to get the list of PVCs
 	EventReasonPVCListFailed = "PVCListFailed"
 
+	// EventReasonPVCSkipped is used when VRG skips protecting PVCs due to their StorageClass
+	EventReasonPVCSkipped = "PVCSkipped"
+
 	// EventReasonVRCreateFailed is used when VRG fails to create VolRep resource
 	EventReasonVRCreateFailed = "VRCreateFailed"
 
@@ -55,6 +58,23 @@ const (
 	// EventReasonSecondarySuccess is an event generated when VRG is successfully
 	// processed as Primary.
 	EventReasonDeleteSuccess = "VRGDeleteSuccess"
+
+	// EventReasonStandalonePeerPrimary is used when a standalone VRG is promoted while its
+	// peer cluster last reported itself as Primary
+	EventReasonStandalonePeerPrimary = "StandalonePeerPrimary"
+
+	// EventReasonNamespaceQuotasInsufficient is used when the resource quotas or limit ranges of a namespace a VRG
+	// recovers to block the recovery of its workload
+	EventReasonNamespaceQuotasInsufficient = "NamespaceQuotasInsufficient"
+
+	// EventReasonKubeObjectVersionsUnsupported is used when the cluster a VRG recovers to serves none of the versions
+	// of the kinds of some of the kube objects it recovers
+	EventReasonKubeObjectVersionsUnsupported = "KubeObjectVersionsUnsupported"
+
+	// EventReasonPolicyExemptionNotAllowed is used when a VRG requests an exemption from an admission policy that the
+	// operator configuration does not allow
+	EventReasonPolicyExemptionNotAllowed = "PolicyExemptionNotAllowed"
+
 	// TODO: Add any additional events (or remove one of existing ones above) if necessary.
 
 	// Events for DRPC Reconciler
@@ -90,6 +110,59 @@ const (
 	// EventReasonSwitchFailed is generated when DRPC fails to switch the cluster
 	// where the app is placed
 	EventReasonSwitchFailed = "DRPCClusterSwitchFailed"
+
+	// EventReasonDeleteForced is generated when DRPC or VRG deletion stops waiting on dependent
+	// resources after the finalizer timeout, leaving them behind
+	EventReasonDeleteForced = "DeleteForced"
+
+	// EventReasonFailoverCapacityInsufficient is generated when DRPC fails over to a cluster whose allocatable
+	// resources are short of the workload requests
+	EventReasonFailoverCapacityInsufficient = "FailoverCapacityInsufficient"
+
+	// EventReasonPeerCapacityInsufficient is generated when the storage classes of the peer cluster of a DRPC lack
+	// the capacity for the PVCs protected on its home cluster
+	EventReasonPeerCapacityInsufficient = "PeerCapacityInsufficient"
+
+	// EventReasonDRPolicyDefaultConflict is generated when a DRPC refers to a DRPolicy other than the default
+	// DRPolicy of its namespace or cluster sets
+	EventReasonDRPolicyDefaultConflict = "DRPolicyDefaultConflict"
+
+	// EventReasonVRGRecreated is generated when DRPC recreates a VRG that was deleted from a managed cluster while
+	// its ManifestWork still exists
+	EventReasonVRGRecreated = "DRPCVRGRecreated"
+
+	// EventReasonDRActionHeld is generated when a DR freeze holds the action of a DRPC until approvers sign it off
+	EventReasonDRActionHeld = "DRActionHeld"
+
+	// EventReasonDRActionApproved is generated when the action of a DRPC held by a DR freeze is approved
+	EventReasonDRActionApproved = "DRActionApproved"
+
+	// EventReasonDRActionRolledBack is generated when the action of a DRPC exceeds its timeout and is rolled back
+	EventReasonDRActionRolledBack = "DRActionRolledBack"
+
+	// Events for DRPC janitor
+
+	// EventReasonOrphanDeleted is generated when a resource left behind by a deleted DRPC is deleted
+	EventReasonOrphanDeleted = "DRPCOrphanDeleted"
+
+	// EventReasonOrphanDisowned is generated when DRPC annotations and finalizer are removed from a user
+	// placement left behind by a deleted DRPC
+	EventReasonOrphanDisowned = "DRPCOrphanDisowned"
+
+	// EventReasonStaleViewDeleted is generated when a ManagedClusterView of a DRPC that no longer reads it is
+	// deleted
+	EventReasonStaleViewDeleted = "DRPCStaleViewDeleted"
+
+	// Events for MaintenanceRelocate
+
+	// EventReasonMaintenanceRelocate is generated on a DRPC when a MaintenanceRelocate relocates it off a cluster
+	// for its maintenance, or back to it after the maintenance
+	EventReasonMaintenanceRelocate = "MaintenanceRelocate"
+
+	// Events for DRBulkAction
+
+	// EventReasonDRBulkAction is generated on a DRPC when a DRBulkAction applies its action to it
+	EventReasonDRBulkAction = "DRBulkAction"
 )
 
 // EventReporter is custom events reporter type which allows user to limit the events
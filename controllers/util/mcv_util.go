@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	errorswrapper "github.com/pkg/errors"
@@ -21,15 +22,39 @@ import (
 	csiaddonsv1alpha1 "github.com/csi-addons/kubernetes-csi-addons/apis/csiaddons/v1alpha1"
 	rmn "github.com/ramendr/ramen/api/v1alpha1"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+const (
+	// MCVLastReadAnnotation is the time a view was last read, for views no longer read to be pruned
+	MCVLastReadAnnotation = "ramendr.openshift.io/mcv-last-read"
+
+	// MCVLastReadRefreshInterval is how old the last read time of a view gets before a read refreshes it, to not
+	// update a view on each read
+	MCVLastReadRefreshInterval = 10 * time.Minute
+)
+
 // begin MCV code
 type ManagedClusterViewGetter interface {
 	GetVRGFromManagedCluster(
 		resourceName, resourceNamespace, managedCluster string,
 		annotations map[string]string) (*rmn.VolumeReplicationGroup, error)
 
+	// GetVRGFromManifestWorkFeedback returns the VRG of a managed cluster with the status fed back to its
+	// ManifestWork by the work agent, or nil if the agent has not fed back a current status
+	GetVRGFromManifestWorkFeedback(resourceName, resourceNamespace, managedCluster string,
+	) (*rmn.VolumeReplicationGroup, error)
+
+	// GetVRGViewTime returns the time the view of a VRG on a managed cluster was last refreshed, or nil if there is
+	// no view of the VRG
+	GetVRGViewTime(resourceName, resourceNamespace, managedCluster string) (*metav1.Time, error)
+
+	// GetVRGFeedbackTime returns the time the VRG status fed back to the ManifestWork of a VRG on a managed cluster
+	// is current as of, or nil if the work agent has not fed back a current status
+	GetVRGFeedbackTime(resourceName, resourceNamespace, managedCluster string) (*metav1.Time, error)
+
 	GetNFFromManagedCluster(
 		resourceName, resourceNamespace, managedCluster string,
 		annotations map[string]string) (*csiaddonsv1alpha1.NetworkFence, error)
@@ -40,6 +65,12 @@ type ManagedClusterViewGetter interface {
 
 	ListMModesMCVs(managedCluster string) (*viewv1beta1.ManagedClusterViewList, error)
 
+	GetDRClusterOperatorStatusFromManagedCluster(
+		managedCluster string,
+		annotations map[string]string) (*rmn.DRClusterOperatorStatus, error)
+
+	DeleteDRClusterOperatorStatusManagedClusterView(clusterName string) error
+
 	GetResource(mcv *viewv1beta1.ManagedClusterView, resource interface{}) error
 
 	DeleteManagedClusterView(clusterName, mcvName string, logger logr.Logger) error
@@ -57,6 +88,9 @@ type ManagedClusterViewGetter interface {
 type ManagedClusterViewGetterImpl struct {
 	client.Client
 	APIReader client.Reader
+
+	// Breaker, if set, refuses views of a managed cluster after repeated failures to view its resources
+	Breaker *ClusterCircuitBreaker
 }
 
 func (m ManagedClusterViewGetterImpl) GetVRGFromManagedCluster(resourceName, resourceNamespace, managedCluster string,
@@ -85,6 +119,99 @@ func (m ManagedClusterViewGetterImpl) GetVRGFromManagedCluster(resourceName, res
 	return vrg, err
 }
 
+// GetVRGFromManifestWorkFeedback returns the VRG of the ManifestWork of a VRG with the status fed back by the work
+// agent, or nil if there is no ManifestWork or the agent has not fed back a current status
+func (m ManagedClusterViewGetterImpl) GetVRGFromManifestWorkFeedback(resourceName, resourceNamespace,
+	managedCluster string,
+) (*rmn.VolumeReplicationGroup, error) {
+	mw := &ocmworkv1.ManifestWork{}
+	key := types.NamespacedName{
+		Name:      ManifestWorkName(resourceName, resourceNamespace, MWTypeVRG),
+		Namespace: managedCluster,
+	}
+
+	if err := m.Get(context.TODO(), key, mw); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errorswrapper.Wrap(err, "failed to get ManifestWork")
+	}
+
+	return ExtractVRGFromManifestWorkFeedback(mw)
+}
+
+// GetVRGViewTime returns the time the view agent last wrote the status of the view of a VRG, or the creation time of
+// a view that was never refreshed
+func (m ManagedClusterViewGetterImpl) GetVRGViewTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	mcv := &viewv1beta1.ManagedClusterView{}
+	key := types.NamespacedName{
+		Name:      BuildManagedClusterViewName(resourceName, resourceNamespace, MWTypeVRG),
+		Namespace: managedCluster,
+	}
+
+	if err := m.Get(context.TODO(), key, mcv); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errorswrapper.Wrap(err, "failed to get ManagedClusterView")
+	}
+
+	return ManagedClusterViewTime(mcv), nil
+}
+
+// GetVRGFeedbackTime returns the time the status fed back to the ManifestWork of a VRG is current as of, by the
+// availability of its managed cluster, or nil if there is no ManifestWork or the agent has not fed back a current
+// status
+func (m ManagedClusterViewGetterImpl) GetVRGFeedbackTime(resourceName, resourceNamespace, managedCluster string,
+) (*metav1.Time, error) {
+	mw := &ocmworkv1.ManifestWork{}
+	key := types.NamespacedName{
+		Name:      ManifestWorkName(resourceName, resourceNamespace, MWTypeVRG),
+		Namespace: managedCluster,
+	}
+
+	if err := m.Get(context.TODO(), key, mw); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, errorswrapper.Wrap(err, "failed to get ManifestWork")
+	}
+
+	cluster := &ocmclv1.ManagedCluster{}
+	if err := m.Get(context.TODO(), types.NamespacedName{Name: managedCluster}, cluster); err != nil {
+		return nil, errorswrapper.Wrap(err, "failed to get ManagedCluster")
+	}
+
+	return VRGFeedbackTime(mw, cluster)
+}
+
+// ManagedClusterViewTime returns the time of the latest write of the status of a view, by any field manager, or of
+// its latest condition transition if its managed fields are not tracked, or its creation time if neither is later.
+// The conditions of a view of a steady resource do not transition, so their times alone tell the age of its last
+// transition rather than of its last refresh.
+func ManagedClusterViewTime(mcv *viewv1beta1.ManagedClusterView) *metav1.Time {
+	viewTime := mcv.CreationTimestamp.DeepCopy()
+
+	for i := range mcv.ManagedFields {
+		entry := &mcv.ManagedFields[i]
+		if entry.Subresource == "status" && entry.Time != nil && viewTime.Before(entry.Time) {
+			viewTime = entry.Time.DeepCopy()
+		}
+	}
+
+	for i := range mcv.Status.Conditions {
+		if viewTime.Before(&mcv.Status.Conditions[i].LastTransitionTime) {
+			viewTime = mcv.Status.Conditions[i].LastTransitionTime.DeepCopy()
+		}
+	}
+
+	return viewTime
+}
+
 func (m ManagedClusterViewGetterImpl) GetNFFromManagedCluster(resourceName, resourceNamespace, managedCluster string,
 	annotations map[string]string,
 ) (*csiaddonsv1alpha1.NetworkFence, error) {
@@ -145,6 +272,31 @@ func (m ManagedClusterViewGetterImpl) GetMModeFromManagedCluster(resourceName, m
 	return mMode, err
 }
 
+func (m ManagedClusterViewGetterImpl) GetDRClusterOperatorStatusFromManagedCluster(managedCluster string,
+	annotations map[string]string,
+) (*rmn.DRClusterOperatorStatus, error) {
+	logger := ctrl.Log.WithName("MCV").WithValues("cluster", managedCluster)
+	// get DRClusterOperatorStatus through ManagedClusterView
+	mcvMeta := metav1.ObjectMeta{
+		Name:        BuildManagedClusterViewName(rmn.DRClusterOperatorStatusName, "", MWTypeOpSt),
+		Namespace:   managedCluster,
+		Annotations: annotations,
+	}
+
+	mcvViewscope := viewv1beta1.ViewScope{
+		Kind:    "DRClusterOperatorStatus",
+		Group:   rmn.GroupVersion.Group,
+		Version: rmn.GroupVersion.Version,
+		Name:    rmn.DRClusterOperatorStatusName,
+	}
+
+	operatorStatus := &rmn.DRClusterOperatorStatus{}
+
+	err := m.getManagedClusterResource(mcvMeta, mcvViewscope, operatorStatus, logger)
+
+	return operatorStatus, err
+}
+
 func (m ManagedClusterViewGetterImpl) ListMModesMCVs(cluster string) (*viewv1beta1.ManagedClusterViewList, error) {
 	matchLabels := map[string]string{
 		MModesLabel: "",
@@ -222,6 +374,10 @@ Returns: error if encountered (nil if no error occurred). See results on interfa
 func (m ManagedClusterViewGetterImpl) getManagedClusterResource(
 	meta metav1.ObjectMeta, viewscope viewv1beta1.ViewScope, resource interface{}, logger logr.Logger,
 ) error {
+	if err := m.Breaker.Allow(meta.Namespace); err != nil {
+		return errorswrapper.Wrap(err, "getManagedClusterResource refused")
+	}
+
 	// create MCV first
 	mcv, err := m.getOrCreateManagedClusterView(meta, viewscope, logger)
 	if err != nil {
@@ -231,7 +387,16 @@ func (m ManagedClusterViewGetterImpl) getManagedClusterResource(
 	logger.Info(fmt.Sprintf("Get managedClusterResource Returned the following MCV Conditions: %v",
 		mcv.Status.Conditions))
 
-	return m.GetResource(mcv, resource)
+	err = m.GetResource(mcv, resource)
+
+	// A resource that is not found was viewed successfully
+	if err == nil || errors.IsNotFound(err) {
+		m.Breaker.RecordSuccess(meta.Namespace)
+	} else {
+		m.Breaker.RecordFailure(meta.Namespace, err)
+	}
+
+	return err
 }
 
 // This function is temporarily used to parse the MCV.Status.Conditions[0].Messagefield for known error strings,
@@ -325,11 +490,19 @@ func (m ManagedClusterViewGetterImpl) getOrCreateManagedClusterView(
 		logger.Info(fmt.Sprintf("Creating ManagedClusterView %s with scope %s",
 			key, viewscope.Name))
 
+		// The annotations are copied, to not add the last read time to the caller's
+		mcv.Annotations = map[string]string{MCVLastReadAnnotation: time.Now().UTC().Format(time.RFC3339)}
+		for key, value := range meta.Annotations {
+			mcv.Annotations[key] = value
+		}
+
 		if err := m.Create(context.TODO(), mcv); err != nil {
 			return nil, errorswrapper.Wrap(err, "failed to create ManagedClusterView")
 		}
 	}
 
+	m.lastReadRefresh(mcv, logger)
+
 	if mcv.Spec.Scope != viewscope {
 		// Expected once when uprading ramen if scope format or details have changed.
 		logger.Info(fmt.Sprintf("Updating ManagedClusterView %s scope %s to %s",
@@ -344,6 +517,32 @@ func (m ManagedClusterViewGetterImpl) getOrCreateManagedClusterView(
 	return mcv, nil
 }
 
+// lastReadRefresh updates the last read time of a view, if it is older than the refresh interval. A failure is
+// logged only, as the view was read.
+func (m ManagedClusterViewGetterImpl) lastReadRefresh(mcv *viewv1beta1.ManagedClusterView, logger logr.Logger) {
+	if lastRead := MCVLastRead(mcv); lastRead != nil && time.Since(*lastRead) < MCVLastReadRefreshInterval {
+		return
+	}
+
+	patch := client.MergeFrom(mcv.DeepCopy())
+
+	AddAnnotation(mcv, MCVLastReadAnnotation, time.Now().UTC().Format(time.RFC3339))
+
+	if err := m.Patch(context.TODO(), mcv, patch); err != nil {
+		logger.Info("Failed to refresh ManagedClusterView last read time", "name", mcv.Name, "error", err)
+	}
+}
+
+// MCVLastRead returns the last read time of a view, or nil if it is not annotated with a valid one
+func MCVLastRead(mcv *viewv1beta1.ManagedClusterView) *time.Time {
+	lastRead, err := time.Parse(time.RFC3339, mcv.GetAnnotations()[MCVLastReadAnnotation])
+	if err != nil {
+		return nil
+	}
+
+	return &lastRead
+}
+
 func (m ManagedClusterViewGetterImpl) DeleteVRGManagedClusterView(
 	resourceName, resourceNamespace, clusterName, resourceType string,
 ) error {
@@ -371,6 +570,13 @@ func (m ManagedClusterViewGetterImpl) DeleteNFManagedClusterView(
 	return m.DeleteManagedClusterView(clusterName, mcvNameNF, logger)
 }
 
+func (m ManagedClusterViewGetterImpl) DeleteDRClusterOperatorStatusManagedClusterView(clusterName string) error {
+	logger := ctrl.Log.WithName("MCV").WithValues("cluster", clusterName)
+	mcvName := BuildManagedClusterViewName(rmn.DRClusterOperatorStatusName, "", MWTypeOpSt)
+
+	return m.DeleteManagedClusterView(clusterName, mcvName, logger)
+}
+
 func (m ManagedClusterViewGetterImpl) DeleteManagedClusterView(clusterName, mcvName string, logger logr.Logger) error {
 	logger.Info("Delete ManagedClusterView from", "namespace", clusterName, "name", mcvName)
 
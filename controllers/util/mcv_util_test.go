@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("MCVLastRead", func() {
+	mcv := func(annotations map[string]string) *viewv1beta1.ManagedClusterView {
+		return &viewv1beta1.ManagedClusterView{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	It("is the annotated last read time", func() {
+		lastRead := rmnutil.MCVLastRead(mcv(map[string]string{
+			rmnutil.MCVLastReadAnnotation: "2024-01-02T03:04:05Z",
+		}))
+		Expect(lastRead).ToNot(BeNil())
+		Expect(*lastRead).To(BeTemporally("==", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	})
+
+	It("is nil without a valid annotation", func() {
+		Expect(rmnutil.MCVLastRead(mcv(nil))).To(BeNil())
+		Expect(rmnutil.MCVLastRead(mcv(map[string]string{rmnutil.MCVLastReadAnnotation: "yesterday"}))).To(BeNil())
+	})
+})
+
+var _ = Describe("ManagedClusterView last read time", func() {
+	var (
+		c      client.Client
+		getter rmnutil.ManagedClusterViewGetterImpl
+		key    types.NamespacedName
+	)
+
+	read := func(annotations map[string]string) *viewv1beta1.ManagedClusterView {
+		// The view reports no result in the fake client, only the view itself is checked
+		_, _ = getter.GetNamespaceFromManagedCluster("app", "east", "app", annotations)
+
+		mcv := &viewv1beta1.ManagedClusterView{}
+		Expect(c.Get(context.TODO(), key, mcv)).To(Succeed())
+
+		return mcv
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(viewv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).Build()
+		getter = rmnutil.ManagedClusterViewGetterImpl{Client: c, APIReader: c}
+		key = types.NamespacedName{
+			Namespace: "east",
+			Name:      rmnutil.BuildManagedClusterViewName("app", "app", rmnutil.MWTypeNS),
+		}
+	})
+
+	It("is set when the view is created, without adding it to the caller's annotations", func() {
+		annotations := map[string]string{"example.com/owner": "app"}
+
+		mcv := read(annotations)
+		Expect(mcv.Annotations).To(HaveKeyWithValue("example.com/owner", "app"))
+		Expect(rmnutil.MCVLastRead(mcv)).ToNot(BeNil())
+		Expect(*rmnutil.MCVLastRead(mcv)).To(BeTemporally("~", time.Now(), time.Minute))
+		Expect(annotations).ToNot(HaveKey(rmnutil.MCVLastReadAnnotation))
+	})
+
+	It("is refreshed by a read only once older than the refresh interval", func() {
+		mcv := read(nil)
+
+		recent := time.Now().Add(-rmnutil.MCVLastReadRefreshInterval / 2).UTC().Format(time.RFC3339)
+		mcv.Annotations[rmnutil.MCVLastReadAnnotation] = recent
+		Expect(c.Update(context.TODO(), mcv)).To(Succeed())
+		Expect(read(nil).Annotations).To(HaveKeyWithValue(rmnutil.MCVLastReadAnnotation, recent))
+
+		stale := time.Now().Add(-2 * rmnutil.MCVLastReadRefreshInterval).UTC().Format(time.RFC3339)
+		mcv = read(nil)
+		mcv.Annotations[rmnutil.MCVLastReadAnnotation] = stale
+		Expect(c.Update(context.TODO(), mcv)).To(Succeed())
+		Expect(*rmnutil.MCVLastRead(read(nil))).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+})
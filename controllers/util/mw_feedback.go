@@ -0,0 +1,293 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// ManifestStatusFeedbackSynced is the condition the work agent sets on a manifest once it synced the status
+	// feedback of the manifest's resource
+	ManifestStatusFeedbackSynced = "StatusFeedbackSynced"
+
+	vrgFeedbackConditionPrefix = "condition-"
+)
+
+// vrgFeedbackFields are the JSON names of the VRG status fields the work agent feeds back. The fields that are
+// lists or objects are fed back as raw JSON, which agents that do not support it, or that exceed its size limit,
+// fail to sync.
+var vrgFeedbackFields = []string{
+	"state",
+	"observedGeneration",
+	"lastUpdateTime",
+	"prepareForFinalSyncComplete",
+	"finalSyncComplete",
+	"lastGroupSyncTime",
+	"lastGroupSyncDuration",
+	"lastGroupSyncBytes",
+	"protectedPVCs",
+	"kubeObjectProtection",
+	"skippedPVCs",
+	"workloadRequests",
+	"namespaceSizings",
+	"s3Transfer",
+	"workloadImages",
+	"workloadWindowsImages",
+	"failoverPreparation",
+}
+
+// vrgFeedbackConditionTypes are the types of all the VRG conditions, which the work agent feeds back each on its
+// own, to keep each value within the raw JSON size limit. A VRG condition type not listed is not read by the hub
+// once it reads the VRG status from the feedback.
+var vrgFeedbackConditionTypes = []string{
+	"DataReady",
+	"DataProtected",
+	"ClusterDataReady",
+	"ClusterDataProtected",
+	"NoClusterDataDrift",
+	"NamespaceQuotasSufficient",
+	"KubeObjectVersionsSupported",
+	"AccessModesPreserved",
+	"OperatorsInstalled",
+	"OperatorCRDsEstablished",
+	"OperatorWebhooksReady",
+	"ReplicationSourceSetup",
+	"FinalSyncInProgress",
+	"ReplicationDestinationSetup",
+	"PVsRestored",
+}
+
+// vrgManifestConfig returns the configuration of the VRG manifest of a ManifestWork, for the work agent to feed
+// back the status of the VRG
+func vrgManifestConfig(vrg *rmn.VolumeReplicationGroup) ocmworkv1.ManifestConfigOption {
+	jsonPaths := make([]ocmworkv1.JsonPath, 0, len(vrgFeedbackFields)+len(vrgFeedbackConditionTypes))
+
+	for _, field := range vrgFeedbackFields {
+		jsonPaths = append(jsonPaths, ocmworkv1.JsonPath{Name: field, Path: "." + field})
+	}
+
+	for _, conditionType := range vrgFeedbackConditionTypes {
+		jsonPaths = append(jsonPaths, ocmworkv1.JsonPath{
+			Name: vrgFeedbackConditionPrefix + conditionType,
+			Path: fmt.Sprintf(`.conditions[?(@.type=="%s")]`, conditionType),
+		})
+	}
+
+	return ocmworkv1.ManifestConfigOption{
+		ResourceIdentifier: ocmworkv1.ResourceIdentifier{
+			Group:     rmn.GroupVersion.Group,
+			Resource:  "volumereplicationgroups",
+			Name:      vrg.Name,
+			Namespace: vrg.Namespace,
+		},
+		FeedbackRules: []ocmworkv1.FeedbackRule{{
+			Type:      ocmworkv1.JSONPathsType,
+			JsonPaths: jsonPaths,
+		}},
+	}
+}
+
+// ExtractVRGFromManifestWorkFeedback returns the VRG of a ManifestWork with the status the work agent fed back, or
+// nil if the agent has not fed back the status of the VRG as applied from the current ManifestWork. The generation
+// of the VRG is not fed back, so a VRG whose status was last updated before the ManifestWork was may not have
+// observed the spec of the ManifestWork, and nil is returned for it to be viewed instead. The status of a VRG
+// updated since is that of the spec of the ManifestWork, whose generation is the generation its status observed.
+func ExtractVRGFromManifestWorkFeedback(mw *ocmworkv1.ManifestWork) (*rmn.VolumeReplicationGroup, error) {
+	applied := meta.FindStatusCondition(mw.Status.Conditions, ocmworkv1.WorkApplied)
+	if ResourceIsDeleted(mw) || applied == nil || applied.Status != metav1.ConditionTrue ||
+		applied.ObservedGeneration != mw.Generation {
+		return nil, nil
+	}
+
+	vrg, err := ExtractVRGFromManifestWork(mw)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := vrgManifestCondition(mw, vrg)
+	if manifest == nil || !meta.IsStatusConditionTrue(manifest.Conditions, ManifestStatusFeedbackSynced) {
+		return nil, nil
+	}
+
+	status := map[string]json.RawMessage{}
+	conditions := []json.RawMessage{}
+
+	for _, value := range manifest.StatusFeedbacks.Values {
+		valueJSON, err := feedbackValueJSON(value.Value)
+		if err != nil {
+			return nil, fmt.Errorf("VRG status feedback %s: %w", value.Name, err)
+		}
+
+		if strings.HasPrefix(value.Name, vrgFeedbackConditionPrefix) {
+			conditions, err = feedbackConditionsAppend(conditions, valueJSON)
+			if err != nil {
+				return nil, fmt.Errorf("VRG status feedback %s: %w", value.Name, err)
+			}
+
+			continue
+		}
+
+		status[value.Name] = valueJSON
+	}
+
+	// A VRG that has not reported its state yet is viewed instead
+	if _, ok := status["state"]; !ok {
+		return nil, nil
+	}
+
+	if len(conditions) != 0 {
+		if status["conditions"], err = json.Marshal(conditions); err != nil {
+			return nil, err
+		}
+	}
+
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(statusJSON, &vrg.Status); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal VRG status feedback (%w)", err)
+	}
+
+	if vrg.Status.LastUpdateTime.Before(manifestWorkSpecTime(mw)) {
+		return nil, nil
+	}
+
+	vrg.Generation = vrg.Status.ObservedGeneration
+
+	return vrg, nil
+}
+
+// VRGFeedbackTime returns the time the VRG status fed back to a ManifestWork is current as of, or nil if the work
+// agent has not fed back a current status. The agent feeds back the status as it changes, so it is current as long
+// as the hub hears from the agent of its cluster, and as of the time the cluster became unavailable otherwise, or
+// the latest write of the status of the ManifestWork if later.
+func VRGFeedbackTime(mw *ocmworkv1.ManifestWork, managedCluster *ocmclv1.ManagedCluster) (*metav1.Time, error) {
+	vrg, err := ExtractVRGFromManifestWorkFeedback(mw)
+	if err != nil || vrg == nil {
+		return nil, err
+	}
+
+	available := meta.FindStatusCondition(managedCluster.Status.Conditions, ocmclv1.ManagedClusterConditionAvailable)
+	if available != nil && available.Status == metav1.ConditionTrue {
+		now := metav1.Now()
+
+		return &now, nil
+	}
+
+	feedbackTime := manifestWorkStatusTime(mw)
+	if available != nil && feedbackTime.Before(&available.LastTransitionTime) {
+		feedbackTime = available.LastTransitionTime.DeepCopy()
+	}
+
+	return feedbackTime, nil
+}
+
+// manifestWorkSpecTime returns the time of the latest write of a ManifestWork other than of its status, or its
+// creation time if its managed fields are not tracked
+func manifestWorkSpecTime(mw *ocmworkv1.ManifestWork) *metav1.Time {
+	specTime := mw.CreationTimestamp.DeepCopy()
+
+	for i := range mw.ManagedFields {
+		entry := &mw.ManagedFields[i]
+		if entry.Subresource == "" && entry.Time != nil && specTime.Before(entry.Time) {
+			specTime = entry.Time.DeepCopy()
+		}
+	}
+
+	return specTime
+}
+
+// manifestWorkStatusTime returns the time of the latest write of the status of a ManifestWork, or of the latest
+// transition of its conditions or of those of its manifests, whichever is later
+func manifestWorkStatusTime(mw *ocmworkv1.ManifestWork) *metav1.Time {
+	statusTime := mw.CreationTimestamp.DeepCopy()
+	later := func(t *metav1.Time) {
+		if t != nil && statusTime.Before(t) {
+			statusTime = t.DeepCopy()
+		}
+	}
+
+	for i := range mw.ManagedFields {
+		if mw.ManagedFields[i].Subresource == "status" {
+			later(mw.ManagedFields[i].Time)
+		}
+	}
+
+	for i := range mw.Status.Conditions {
+		later(&mw.Status.Conditions[i].LastTransitionTime)
+	}
+
+	for i := range mw.Status.ResourceStatus.Manifests {
+		manifest := &mw.Status.ResourceStatus.Manifests[i]
+		for j := range manifest.Conditions {
+			later(&manifest.Conditions[j].LastTransitionTime)
+		}
+	}
+
+	return statusTime
+}
+
+// vrgManifestCondition returns the status of the VRG manifest of a ManifestWork, or nil if it is not reported
+func vrgManifestCondition(mw *ocmworkv1.ManifestWork, vrg *rmn.VolumeReplicationGroup,
+) *ocmworkv1.ManifestCondition {
+	for i := range mw.Status.ResourceStatus.Manifests {
+		manifest := &mw.Status.ResourceStatus.Manifests[i]
+
+		if manifest.ResourceMeta.Group == rmn.GroupVersion.Group &&
+			manifest.ResourceMeta.Kind == "VolumeReplicationGroup" &&
+			manifest.ResourceMeta.Name == vrg.Name &&
+			manifest.ResourceMeta.Namespace == vrg.Namespace {
+			return manifest
+		}
+	}
+
+	return nil
+}
+
+// feedbackConditionsAppend appends the conditions of a status feedback value, the result of a JSONPath filter,
+// which is a condition or a list of them
+func feedbackConditionsAppend(conditions []json.RawMessage, valueJSON json.RawMessage) ([]json.RawMessage, error) {
+	if !strings.HasPrefix(strings.TrimSpace(string(valueJSON)), "[") {
+		return append(conditions, valueJSON), nil
+	}
+
+	list := []json.RawMessage{}
+	if err := json.Unmarshal(valueJSON, &list); err != nil {
+		return nil, err
+	}
+
+	return append(conditions, list...), nil
+}
+
+// feedbackValueJSON returns the JSON of a status feedback value
+func feedbackValueJSON(value ocmworkv1.FieldValue) (json.RawMessage, error) {
+	switch {
+	case value.Type == ocmworkv1.Integer && value.Integer != nil:
+		return json.Marshal(*value.Integer)
+	case value.Type == ocmworkv1.String && value.String != nil:
+		return json.Marshal(*value.String)
+	case value.Type == ocmworkv1.Boolean && value.Boolean != nil:
+		return json.Marshal(*value.Boolean)
+	case value.Type == ocmworkv1.JsonRaw && value.JsonRaw != nil:
+		if !json.Valid([]byte(*value.JsonRaw)) {
+			return nil, fmt.Errorf("invalid raw JSON")
+		}
+
+		return json.RawMessage(*value.JsonRaw), nil
+	}
+
+	return nil, fmt.Errorf("value of type %s not set", value.Type)
+}
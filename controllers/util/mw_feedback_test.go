@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+)
+
+var _ = Describe("ExtractVRGFromManifestWorkFeedback", func() {
+	var mw *ocmworkv1.ManifestWork
+
+	BeforeEach(func() {
+		vrg := rmn.VolumeReplicationGroup{
+			TypeMeta:   metav1.TypeMeta{Kind: "VolumeReplicationGroup", APIVersion: rmn.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app-ns"},
+			Spec:       rmn.VolumeReplicationGroupSpec{ReplicationState: rmn.Primary},
+		}
+
+		manifest, err := (&rmnutil.MWUtil{}).GenerateManifest(vrg)
+		Expect(err).NotTo(HaveOccurred())
+
+		state := string(rmn.PrimaryState)
+		observedGeneration := int64(2)
+		condition := `{"type":"DataReady","status":"True","observedGeneration":2,` +
+			`"lastTransitionTime":"2024-01-01T00:00:00Z","reason":"Ready","message":"ready"}`
+
+		mw = &ocmworkv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-app-ns-vrg-mw", Namespace: "cluster1", Generation: 1},
+			Spec: ocmworkv1.ManifestWorkSpec{
+				Workload: ocmworkv1.ManifestsTemplate{Manifests: []ocmworkv1.Manifest{*manifest}},
+			},
+			Status: ocmworkv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{{
+					Type:               ocmworkv1.WorkApplied,
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+				}},
+				ResourceStatus: ocmworkv1.ManifestResourceStatus{Manifests: []ocmworkv1.ManifestCondition{{
+					ResourceMeta: ocmworkv1.ManifestResourceMeta{
+						Group:     rmn.GroupVersion.Group,
+						Kind:      "VolumeReplicationGroup",
+						Name:      "app",
+						Namespace: "app-ns",
+					},
+					StatusFeedbacks: ocmworkv1.StatusFeedbackResult{Values: []ocmworkv1.FeedbackValue{
+						{Name: "state", Value: ocmworkv1.FieldValue{Type: ocmworkv1.String, String: &state}},
+						{
+							Name:  "observedGeneration",
+							Value: ocmworkv1.FieldValue{Type: ocmworkv1.Integer, Integer: &observedGeneration},
+						},
+						{
+							Name:  "condition-DataReady",
+							Value: ocmworkv1.FieldValue{Type: ocmworkv1.JsonRaw, JsonRaw: &condition},
+						},
+					}},
+					Conditions: []metav1.Condition{{
+						Type:   rmnutil.ManifestStatusFeedbackSynced,
+						Status: metav1.ConditionTrue,
+					}},
+				}}},
+			},
+		}
+	})
+
+	It("returns the VRG with the status fed back", func() {
+		vrg, err := rmnutil.ExtractVRGFromManifestWorkFeedback(mw)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vrg).NotTo(BeNil())
+		Expect(vrg.Spec.ReplicationState).To(Equal(rmn.Primary))
+		Expect(vrg.Status.State).To(Equal(rmn.PrimaryState))
+		Expect(vrg.Generation).To(Equal(int64(2)))
+		Expect(vrg.Status.Conditions).To(HaveLen(1))
+		Expect(vrg.Status.Conditions[0].Type).To(Equal("DataReady"))
+		Expect(vrg.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("returns nil if the ManifestWork was not applied at its generation", func() {
+		mw.Generation = 2
+
+		Expect(rmnutil.ExtractVRGFromManifestWorkFeedback(mw)).To(BeNil())
+	})
+
+	It("returns nil if the status feedback is not synced", func() {
+		mw.Status.ResourceStatus.Manifests[0].Conditions[0].Status = metav1.ConditionFalse
+
+		Expect(rmnutil.ExtractVRGFromManifestWorkFeedback(mw)).To(BeNil())
+	})
+
+	It("returns nil if the VRG status was last updated before the ManifestWork, for its generation to be viewed", func() {
+		updated := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		written := metav1.NewTime(updated.Add(time.Minute))
+		lastUpdateTime := `"` + updated.UTC().Format(time.RFC3339) + `"`
+		feedbackValueAdd(mw, "lastUpdateTime", lastUpdateTime)
+		mw.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "ramen", Time: &written}}
+
+		Expect(rmnutil.ExtractVRGFromManifestWorkFeedback(mw)).To(BeNil())
+
+		written = metav1.NewTime(updated.Add(-time.Minute))
+
+		vrg, err := rmnutil.ExtractVRGFromManifestWorkFeedback(mw)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vrg).To(HaveField("Generation", int64(2)))
+	})
+
+	It("returns the VolSync conditions fed back", func() {
+		feedbackValueAdd(mw, "condition-PVsRestored", `{"type":"PVsRestored","status":"True",`+
+			`"lastTransitionTime":"2024-01-01T00:00:00Z","reason":"Restored","message":"restored"}`)
+
+		vrg, err := rmnutil.ExtractVRGFromManifestWorkFeedback(mw)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(vrg.Status.Conditions).To(ContainElement(HaveField("Type", "PVsRestored")))
+	})
+
+	Describe("VRGFeedbackTime", func() {
+		var cluster *ocmclv1.ManagedCluster
+
+		unavailable := metav1.NewTime(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC))
+		written := metav1.NewTime(unavailable.Add(-time.Hour))
+
+		BeforeEach(func() {
+			cluster = &ocmclv1.ManagedCluster{Status: ocmclv1.ManagedClusterStatus{Conditions: []metav1.Condition{{
+				Type:   ocmclv1.ManagedClusterConditionAvailable,
+				Status: metav1.ConditionTrue,
+			}}}}
+			mw.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "work-agent", Time: &written, Subresource: "status"}}
+		})
+
+		It("times the feedback of an available cluster as current", func() {
+			feedbackTime, err := rmnutil.VRGFeedbackTime(mw, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(feedbackTime.Time).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
+		It("times the feedback of a cluster unavailable by when it became unavailable", func() {
+			cluster.Status.Conditions[0].Status = metav1.ConditionUnknown
+			cluster.Status.Conditions[0].LastTransitionTime = unavailable
+			Expect(rmnutil.VRGFeedbackTime(mw, cluster)).To(Equal(&unavailable))
+
+			cluster.Status.Conditions = nil
+			Expect(rmnutil.VRGFeedbackTime(mw, cluster)).To(Equal(&written))
+		})
+
+		It("does not time a status not fed back", func() {
+			mw.Status.ResourceStatus.Manifests[0].Conditions[0].Status = metav1.ConditionFalse
+			Expect(rmnutil.VRGFeedbackTime(mw, cluster)).To(BeNil())
+		})
+	})
+})
+
+// feedbackValueAdd adds a raw JSON value to the status fed back to the VRG manifest of a ManifestWork
+func feedbackValueAdd(mw *ocmworkv1.ManifestWork, name, value string) {
+	manifest := &mw.Status.ResourceStatus.Manifests[0]
+	manifest.StatusFeedbacks.Values = append(manifest.StatusFeedbacks.Values, ocmworkv1.FeedbackValue{
+		Name:  name,
+		Value: ocmworkv1.FieldValue{Type: ocmworkv1.JsonRaw, JsonRaw: &value},
+	})
+}
@@ -8,10 +8,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/go-logr/logr"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
 	errorswrapper "github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -21,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -31,6 +33,9 @@ import (
 const (
 	DrClusterManifestWorkName = "ramen-dr-cluster"
 
+	// DrClusterVeleroManifestWorkName is the name of the ManifestWork that deploys Velero to a dr-cluster
+	DrClusterVeleroManifestWorkName = "ramen-dr-cluster-velero"
+
 	// ManifestWorkNameFormat is a formated a string used to generate the manifest name
 	// The format is name-namespace-type-mw where:
 	// - name is the DRPC name
@@ -44,6 +49,11 @@ const (
 	MWTypeNS    string = "ns"
 	MWTypeNF    string = "nf"
 	MWTypeMMode string = "mmode"
+	MWTypeOpSt  string = "opst"
+
+	// MWMetadataKeysAnnotation records the keys of the labels and annotations Ramen set on a ManifestWork, for those
+	// it no longer sets to be removed, while those set by others are retained
+	MWMetadataKeysAnnotation = "ramendr.openshift.io/metadata-keys"
 )
 
 type MWUtil struct {
@@ -53,6 +63,10 @@ type MWUtil struct {
 	Log             logr.Logger
 	InstName        string
 	TargetNamespace string
+
+	// Breaker, if set, refuses ManifestWork writes for a managed cluster after repeated failures to view its
+	// resources or to write its ManifestWorks
+	Breaker *ClusterCircuitBreaker
 }
 
 func ManifestWorkName(name, namespace, mwType string) string {
@@ -114,9 +128,9 @@ func IsManifestInAppliedState(mw *ocmworkv1.ManifestWork) bool {
 
 func (mwu *MWUtil) CreateOrUpdateVRGManifestWork(
 	name, namespace, homeCluster string,
-	vrg rmn.VolumeReplicationGroup, annotations map[string]string,
+	vrg rmn.VolumeReplicationGroup, labels, annotations map[string]string,
 ) error {
-	manifestWork, err := mwu.generateVRGManifestWork(name, namespace, homeCluster, vrg, annotations)
+	manifestWork, err := mwu.generateVRGManifestWork(name, namespace, homeCluster, vrg, labels, annotations)
 	if err != nil {
 		return err
 	}
@@ -125,7 +139,7 @@ func (mwu *MWUtil) CreateOrUpdateVRGManifestWork(
 }
 
 func (mwu *MWUtil) generateVRGManifestWork(name, namespace, homeCluster string,
-	vrg rmn.VolumeReplicationGroup, annotations map[string]string,
+	vrg rmn.VolumeReplicationGroup, labels, annotations map[string]string,
 ) (*ocmworkv1.ManifestWork, error) {
 	vrgClientManifest, err := mwu.generateVRGManifest(vrg)
 	if err != nil {
@@ -136,11 +150,20 @@ func (mwu *MWUtil) generateVRGManifestWork(name, namespace, homeCluster string,
 
 	manifests := []ocmworkv1.Manifest{*vrgClientManifest}
 
-	return mwu.newManifestWork(
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	mw := mwu.newManifestWork(
 		fmt.Sprintf(ManifestWorkNameFormat, name, namespace, MWTypeVRG),
 		homeCluster,
-		map[string]string{},
-		manifests, annotations), nil
+		labels,
+		manifests, annotations)
+
+	// The work agent feeds back the VRG status, for the hub to read it without a ManagedClusterView
+	mw.Spec.ManifestConfigs = []ocmworkv1.ManifestConfigOption{vrgManifestConfig(&vrg)}
+
+	return mw, nil
 }
 
 func (mwu *MWUtil) generateVRGManifest(vrg rmn.VolumeReplicationGroup) (*ocmworkv1.Manifest, error) {
@@ -336,15 +359,7 @@ func (mwu *MWUtil) CreateOrUpdateDrClusterManifestWork(
 	clusterName string,
 	objectsToAppend []interface{}, annotations map[string]string,
 ) error {
-	objects := append(
-		[]interface{}{
-			vrgClusterRole,
-			vrgClusterRoleBinding,
-			mModeClusterRole,
-			mModeClusterRoleBinding,
-		},
-		objectsToAppend...,
-	)
+	objects := append(drClusterManifestWorkObjects(), objectsToAppend...)
 
 	manifests := make([]ocmworkv1.Manifest, len(objects))
 
@@ -370,6 +385,87 @@ func (mwu *MWUtil) CreateOrUpdateDrClusterManifestWork(
 	)
 }
 
+// CreateOrUpdateDrClusterVeleroManifestWork deploys the objects of Velero to a dr-cluster, apart from the objects of
+// the dr-cluster operator, so that Velero stays deployed regardless of their rollouts
+func (mwu *MWUtil) CreateOrUpdateDrClusterVeleroManifestWork(
+	clusterName string,
+	objects []interface{}, annotations map[string]string,
+) error {
+	manifests := make([]ocmworkv1.Manifest, len(objects))
+
+	for i, object := range objects {
+		manifest, err := mwu.GenerateManifest(object)
+		if err != nil {
+			return err
+		}
+
+		manifests[i] = *manifest
+	}
+
+	return mwu.createOrUpdateManifestWork(
+		mwu.newManifestWork(
+			DrClusterVeleroManifestWorkName,
+			clusterName,
+			map[string]string{},
+			manifests, annotations,
+		),
+		clusterName,
+	)
+}
+
+// DrClusterManifestWorkAppendedManifests returns the manifests of the objects appended to the dr-cluster
+// ManifestWork, except those of the kinds excluded. The objects the ManifestWork begins with are recognized by their
+// kind and name rather than their position, so that those deployed by other versions of the operator are skipped.
+func DrClusterManifestWorkAppendedManifests(mw *ocmworkv1.ManifestWork, excludedKinds ...string,
+) ([]json.RawMessage, error) {
+	fixed := map[schema.GroupKind]map[string]struct{}{}
+
+	for _, fixedObject := range drClusterManifestWorkObjects() {
+		object, ok := fixedObject.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("dr-cluster manifest work object %T is not a kube object", fixedObject)
+		}
+
+		gvk := object.GetObjectKind().GroupVersionKind()
+		if fixed[gvk.GroupKind()] == nil {
+			fixed[gvk.GroupKind()] = map[string]struct{}{}
+		}
+
+		fixed[gvk.GroupKind()][object.GetName()] = struct{}{}
+	}
+
+	raws := make([]json.RawMessage, 0, len(mw.Spec.Workload.Manifests))
+
+	for _, manifest := range mw.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(manifest.Raw, obj); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON. Error %w", err)
+		}
+
+		if _, ok := fixed[obj.GroupVersionKind().GroupKind()][obj.GetName()]; ok {
+			continue
+		}
+
+		if slices.Contains(excludedKinds, obj.GetKind()) {
+			continue
+		}
+
+		raws = append(raws, json.RawMessage(manifest.Raw))
+	}
+
+	return raws, nil
+}
+
+// drClusterManifestWorkObjects returns the objects the dr-cluster ManifestWork begins with
+func drClusterManifestWorkObjects() []interface{} {
+	return []interface{}{
+		vrgClusterRole,
+		vrgClusterRoleBinding,
+		mModeClusterRole,
+		mModeClusterRoleBinding,
+	}
+}
+
 var (
 	vrgClusterRole = &rbacv1.ClusterRole{
 		TypeMeta:   metav1.TypeMeta{Kind: "ClusterRole", APIVersion: "rbac.authorization.k8s.io/v1"},
@@ -465,13 +561,42 @@ func (mwu *MWUtil) newManifestWork(name string, mcNamespace string,
 	return mw
 }
 
+// clusterWrite writes ManifestWorks for a managed cluster unless its circuit breaker is open, counting a failed write
+// against the cluster. A successful write does not close the breaker, as the hub accepts ManifestWorks for a cluster
+// that is down; views of the cluster's resources probe it.
+func (mwu *MWUtil) clusterWrite(managedCluster string, write func() error) error {
+	if err := mwu.Breaker.Allow(managedCluster); err != nil {
+		return errorswrapper.Wrap(err, "ManifestWork write refused")
+	}
+
+	err := write()
+	if err != nil {
+		mwu.Breaker.RecordFailure(managedCluster, err)
+	}
+
+	return err
+}
+
 func (mwu *MWUtil) createOrUpdateManifestWork(
 	mw *ocmworkv1.ManifestWork,
 	managedClusternamespace string,
+) error {
+	return mwu.clusterWrite(managedClusternamespace, func() error {
+		return mwu.createOrUpdateManifestWorkUnguarded(mw, managedClusternamespace)
+	})
+}
+
+func (mwu *MWUtil) createOrUpdateManifestWorkUnguarded(
+	mw *ocmworkv1.ManifestWork,
+	managedClusternamespace string,
 ) error {
 	key := types.NamespacedName{Name: mw.Name, Namespace: managedClusternamespace}
 	foundMW := &ocmworkv1.ManifestWork{}
 
+	if err := metadataKeysRecord(mw); err != nil {
+		return err
+	}
+
 	err := mwu.Client.Get(mwu.Ctx, key, foundMW)
 	if err != nil {
 		if !errors.IsNotFound(err) {
@@ -483,23 +608,138 @@ func (mwu *MWUtil) createOrUpdateManifestWork(
 		return mwu.Client.Create(mwu.Ctx, mw)
 	}
 
-	if !reflect.DeepEqual(foundMW.Spec, mw.Spec) {
-		mwu.Log.Info("Updating ManifestWork", "name", mw.Name, "namespace", foundMW.Namespace)
+	if reflect.DeepEqual(foundMW.Spec, mw.Spec) && !manifestWorkMetadataUpdate(foundMW, mw) {
+		return nil
+	}
 
-		return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			if err := mwu.Client.Get(mwu.Ctx, key, foundMW); err != nil {
-				return err
-			}
+	mwu.Log.Info("Updating ManifestWork", "name", mw.Name, "namespace", foundMW.Namespace)
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := mwu.Client.Get(mwu.Ctx, key, foundMW); err != nil {
+			return err
+		}
+
+		mw.Spec.DeepCopyInto(&foundMW.Spec)
+		manifestWorkMetadataUpdate(foundMW, mw)
+
+		return mwu.Client.Update(mwu.Ctx, foundMW)
+	})
+}
+
+// UpdateManifestWork updates a ManifestWork, unless the circuit breaker of its managed cluster is open
+func (mwu *MWUtil) UpdateManifestWork(mw *ocmworkv1.ManifestWork) error {
+	return mwu.clusterWrite(mw.Namespace, func() error {
+		return mwu.Client.Update(mwu.Ctx, mw)
+	})
+}
+
+// metadataKeys are the keys of the labels and annotations Ramen set on a ManifestWork
+type metadataKeys struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
 
-			mw.Spec.DeepCopyInto(&foundMW.Spec)
+// metadataKeysRecord records the keys of the labels and annotations of a ManifestWork to create or update in its
+// annotations
+func metadataKeysRecord(mw *ocmworkv1.ManifestWork) error {
+	annotations := make(map[string]string, len(mw.GetAnnotations())+1)
+	for k, v := range mw.GetAnnotations() {
+		if k != MWMetadataKeysAnnotation {
+			annotations[k] = v
+		}
+	}
 
-			return mwu.Client.Update(mwu.Ctx, foundMW)
-		})
+	value, err := json.Marshal(metadataKeys{
+		Labels:      sortedKeys(mw.GetLabels()),
+		Annotations: sortedKeys(annotations),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata keys of ManifestWork %s (%w)", mw.Name, err)
 	}
 
+	annotations[MWMetadataKeysAnnotation] = string(value)
+	mw.SetAnnotations(annotations)
+
 	return nil
 }
 
+// metadataKeysRecorded returns the keys of the labels and annotations recorded in the annotations of a ManifestWork,
+// or none if it has no valid record, as do those created before the keys were recorded
+func metadataKeysRecorded(mw *ocmworkv1.ManifestWork) metadataKeys {
+	keys := metadataKeys{}
+
+	if err := json.Unmarshal([]byte(mw.GetAnnotations()[MWMetadataKeysAnnotation]), &keys); err != nil {
+		return metadataKeys{}
+	}
+
+	return keys
+}
+
+// manifestWorkMetadataUpdate sets the labels and annotations of the desired ManifestWork on the current one, and
+// removes those recorded as set before that are no longer desired. Returns true if it changed them.
+func manifestWorkMetadataUpdate(current, desired *ocmworkv1.ManifestWork) bool {
+	previous := metadataKeysRecorded(current)
+	labels := metadataUpdate(current.GetLabels(), desired.GetLabels(), previous.Labels)
+	annotations := metadataUpdate(current.GetAnnotations(), desired.GetAnnotations(), previous.Annotations)
+
+	if metadataEqual(current.GetLabels(), labels) && metadataEqual(current.GetAnnotations(), annotations) {
+		return false
+	}
+
+	current.SetLabels(labels)
+	current.SetAnnotations(annotations)
+
+	return true
+}
+
+// metadataUpdate returns the current key/values with those desired set, and those previously set and no longer
+// desired removed, retaining any other existing keys
+func metadataUpdate(current, desired map[string]string, previous []string) map[string]string {
+	updated := make(map[string]string, len(current)+len(desired))
+
+	for k, v := range current {
+		updated[k] = v
+	}
+
+	for _, k := range previous {
+		if _, ok := desired[k]; !ok {
+			delete(updated, k)
+		}
+	}
+
+	for k, v := range desired {
+		updated[k] = v
+	}
+
+	return updated
+}
+
+// metadataEqual returns true if both have the same key/values, where none equals empty
+func metadataEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range b {
+		if av, ok := a[k]; !ok || av != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func (mwu *MWUtil) GetVRGManifestWorkCount(drClusters []string) int {
 	count := 0
 
@@ -537,6 +777,12 @@ func (mwu *MWUtil) deleteManifestWorkWrapper(fromCluster string, mwType string)
 }
 
 func (mwu *MWUtil) DeleteManifestWork(mwName, mwNamespace string) error {
+	return mwu.clusterWrite(mwNamespace, func() error {
+		return mwu.deleteManifestWork(mwName, mwNamespace)
+	})
+}
+
+func (mwu *MWUtil) deleteManifestWork(mwName, mwNamespace string) error {
 	mwu.Log.Info("Delete ManifestWork from", "namespace", mwNamespace, "name", mwName)
 
 	mw := &ocmworkv1.ManifestWork{}
@@ -4,13 +4,22 @@
 package util_test
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
 	rmnutil "github.com/ramendr/ramen/controllers/util"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 var _ = Describe("IsManifestInAppliedState", func() {
@@ -260,3 +269,170 @@ var _ = Describe("IsManifestInAppliedState", func() {
 		})
 	})
 })
+
+var _ = Describe("MWUtil ManifestWork writes", func() {
+	const cluster = "cluster1"
+
+	errUpdate := errors.New("update failed")
+
+	var (
+		mw       *ocmworkv1.ManifestWork
+		breaker  *rmnutil.ClusterCircuitBreaker
+		updates  int
+		mwu      *rmnutil.MWUtil
+		failures bool
+	)
+
+	BeforeEach(func() {
+		mw = &ocmworkv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Namespace: cluster, Name: "mw"}}
+		breaker = rmnutil.NewClusterCircuitBreaker(2, time.Hour, time.Hour)
+		updates = 0
+		failures = false
+
+		scheme := runtime.NewScheme()
+		Expect(ocmworkv1.AddToScheme(scheme)).To(Succeed())
+
+		mwu = &rmnutil.MWUtil{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mw.DeepCopy()).WithInterceptorFuncs(
+				interceptor.Funcs{Update: func(ctx context.Context, c client.WithWatch, obj client.Object,
+					opts ...client.UpdateOption,
+				) error {
+					updates++
+					if failures {
+						return errUpdate
+					}
+
+					return c.Update(ctx, obj, opts...)
+				}},
+			).Build(),
+			Ctx:     context.TODO(),
+			Log:     logr.Discard(),
+			Breaker: breaker,
+		}
+		Expect(mwu.Client.Get(mwu.Ctx, client.ObjectKeyFromObject(mw), mw)).To(Succeed())
+	})
+
+	It("opens the breaker of the cluster after repeated write failures, and refuses writes", func() {
+		failures = true
+
+		Expect(mwu.UpdateManifestWork(mw)).To(MatchError(errUpdate))
+		Expect(mwu.UpdateManifestWork(mw)).To(MatchError(errUpdate))
+		Expect(breaker.State(cluster).Open).To(BeTrue())
+
+		Expect(mwu.UpdateManifestWork(mw)).To(MatchError(rmnutil.ErrClusterCircuitOpen))
+		Expect(updates).To(Equal(2))
+
+		Expect(mwu.DeleteManifestWork(mw.Name, cluster)).To(MatchError(rmnutil.ErrClusterCircuitOpen))
+		Expect(mwu.Client.Get(mwu.Ctx, client.ObjectKeyFromObject(mw), mw)).To(Succeed())
+	})
+
+	It("does not close the breaker on a successful write", func() {
+		breaker.RecordFailure(cluster, errUpdate)
+		Expect(mwu.UpdateManifestWork(mw)).To(Succeed())
+		Expect(breaker.State(cluster).Failures).To(Equal(1))
+	})
+
+	It("writes without a breaker", func() {
+		mwu.Breaker = nil
+		Expect(mwu.UpdateManifestWork(mw)).To(Succeed())
+		Expect(mwu.DeleteManifestWork(mw.Name, cluster)).To(Succeed())
+	})
+})
+
+var _ = Describe("MWUtil VRG ManifestWork metadata", func() {
+	const cluster = "cluster1"
+
+	var mwu *rmnutil.MWUtil
+
+	vrg := rmn.VolumeReplicationGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "drpc"}}
+	mwGet := func() *ocmworkv1.ManifestWork {
+		mw := &ocmworkv1.ManifestWork{}
+		Expect(mwu.Client.Get(mwu.Ctx, types.NamespacedName{
+			Namespace: cluster, Name: rmnutil.ManifestWorkName("drpc", "app", rmnutil.MWTypeVRG),
+		}, mw)).To(Succeed())
+
+		return mw
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(ocmworkv1.AddToScheme(scheme)).To(Succeed())
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		mwu = &rmnutil.MWUtil{Client: c, APIReader: c, Ctx: context.TODO(), Log: logr.Discard()}
+	})
+
+	It("stamps the labels and annotations, and removes those no longer stamped, retaining those of others", func() {
+		Expect(mwu.CreateOrUpdateVRGManifestWork("drpc", "app", cluster, vrg,
+			map[string]string{"cost-center": "a", "team": "db"},
+			map[string]string{"drpc-name": "drpc", "backup": "exclude"})).To(Succeed())
+
+		mw := mwGet()
+		Expect(mw.Labels).To(Equal(map[string]string{"cost-center": "a", "team": "db"}))
+		Expect(mw.Annotations).To(HaveKeyWithValue("backup", "exclude"))
+		Expect(mw.Annotations).To(HaveKeyWithValue(rmnutil.MWMetadataKeysAnnotation,
+			`{"labels":["cost-center","team"],"annotations":["backup","drpc-name"]}`))
+
+		mw.Labels["owner"] = "policy-engine"
+		mw.Annotations["reviewed"] = "true"
+		Expect(mwu.Client.Update(mwu.Ctx, mw)).To(Succeed())
+
+		Expect(mwu.CreateOrUpdateVRGManifestWork("drpc", "app", cluster, vrg,
+			map[string]string{"cost-center": "b"}, map[string]string{"drpc-name": "drpc"})).To(Succeed())
+
+		mw = mwGet()
+		Expect(mw.Labels).To(Equal(map[string]string{"cost-center": "b", "owner": "policy-engine"}))
+		Expect(mw.Annotations).To(Equal(map[string]string{
+			"drpc-name": "drpc", "reviewed": "true",
+			rmnutil.MWMetadataKeysAnnotation: `{"labels":["cost-center"],"annotations":["drpc-name"]}`,
+		}))
+	})
+
+	It("retains the labels and annotations of a ManifestWork that recorded none it stamped", func() {
+		Expect(mwu.CreateOrUpdateVRGManifestWork("drpc", "app", cluster, vrg,
+			map[string]string{"team": "db"}, nil)).To(Succeed())
+
+		mw := mwGet()
+		delete(mw.Annotations, rmnutil.MWMetadataKeysAnnotation)
+		Expect(mwu.Client.Update(mwu.Ctx, mw)).To(Succeed())
+
+		Expect(mwu.CreateOrUpdateVRGManifestWork("drpc", "app", cluster, vrg, nil, nil)).To(Succeed())
+		Expect(mwGet().Labels).To(Equal(map[string]string{"team": "db"}))
+	})
+})
+
+var _ = Describe("DrClusterManifestWorkAppendedManifests", func() {
+	manifest := func(object string) ocmworkv1.Manifest {
+		return ocmworkv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(object)}}
+	}
+
+	It("skips the objects the ManifestWork begins with by kind and name, wherever they are", func() {
+		configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"ramen-dr-cluster-operator-config"}}`
+		clusterRole := `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"other"}}`
+		mw := &ocmworkv1.ManifestWork{Spec: ocmworkv1.ManifestWorkSpec{Workload: ocmworkv1.ManifestsTemplate{
+			Manifests: []ocmworkv1.Manifest{
+				manifest(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole",` +
+					`"metadata":{"name":"open-cluster-management:klusterlet-work-sa:agent:volrepgroup-edit"}}`),
+				manifest(configMap),
+				manifest(clusterRole),
+				manifest(`{"apiVersion":"operators.coreos.com/v1alpha1","kind":"Subscription",` +
+					`"metadata":{"name":"ramen"}}`),
+			},
+		}}}
+
+		raws, err := rmnutil.DrClusterManifestWorkAppendedManifests(mw, "Subscription")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raws).To(HaveLen(2))
+		Expect(string(raws[0])).To(Equal(configMap))
+		Expect(string(raws[1])).To(Equal(clusterRole))
+	})
+
+	It("fails for a manifest that is not an object", func() {
+		mw := &ocmworkv1.ManifestWork{Spec: ocmworkv1.ManifestWorkSpec{Workload: ocmworkv1.ManifestsTemplate{
+			Manifests: []ocmworkv1.Manifest{manifest(`[]`)},
+		}}}
+
+		_, err := rmnutil.DrClusterManifestWorkAppendedManifests(mw)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,292 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	NotificationMinIntervalDefault = 10 * time.Minute
+	notificationSendTimeout        = 30 * time.Second
+	notificationSMTPPortDefault    = 587
+	notificationTemplateDefault    = "{{.Event}} {{.Kind}} {{.Namespace}}/{{.Name}}" +
+		"{{if .Cluster}} cluster {{.Cluster}}{{end}}: {{.Message}}"
+)
+
+// Notification is a DR event of an object that messages are sent on
+type Notification struct {
+	Event     rmn.NotificationEvent `json:"event"`
+	Kind      string                `json:"kind"`
+	Namespace string                `json:"namespace,omitempty"`
+	Name      string                `json:"name"`
+	Cluster   string                `json:"cluster,omitempty"`
+	Message   string                `json:"message"`
+	Time      time.Time             `json:"time"`
+}
+
+// Notifier sends messages on DR events to the sinks configured in RamenConfig. Messages are sent in the background,
+// so that reconciles do not wait on the sinks, and those of an event of an object to a sink are rate limited to one
+// per minimum interval. A nil notifier sends no messages.
+type Notifier struct {
+	mutex      sync.Mutex
+	lastSent   map[string]time.Time
+	httpClient *http.Client
+}
+
+func NewNotifier() *Notifier {
+	return &Notifier{
+		lastSent:   map[string]time.Time{},
+		httpClient: &http.Client{Timeout: notificationSendTimeout},
+	}
+}
+
+// Notify sends messages on a notification to the sinks subscribed to its event. Secrets of the sinks are read from
+// the namespace.
+func (n *Notifier) Notify(reader client.Reader, namespace string, config *rmn.Notifications,
+	notification Notification, log logr.Logger,
+) {
+	if n == nil || config == nil {
+		return
+	}
+
+	if notification.Time.IsZero() {
+		notification.Time = time.Now()
+	}
+
+	for i := range config.Sinks {
+		sink := config.Sinks[i]
+		if len(sink.Events) != 0 && !slices.Contains(sink.Events, notification.Event) {
+			continue
+		}
+
+		if !n.allow(sink.Name, notification, notificationMinInterval(config)) {
+			continue
+		}
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notificationSendTimeout)
+			defer cancel()
+
+			if err := n.send(ctx, reader, namespace, &sink, notification); err != nil {
+				log.Info("Notification send failed", "sink", sink.Name, "event", notification.Event,
+					"error", err)
+
+				return
+			}
+
+			log.Info("Notification sent", "sink", sink.Name, "event", notification.Event)
+		}()
+	}
+}
+
+// allow returns true, and records the time, if no message of the event of the object was sent to the sink within
+// the minimum interval. The times of messages sent before the minimum interval no longer limit any message, and
+// are dropped, so that those of deleted objects and removed sinks are not kept.
+func (n *Notifier) allow(sinkName string, notification Notification, minInterval time.Duration) bool {
+	key := strings.Join([]string{sinkName, string(notification.Event), notification.Kind, notification.Namespace,
+		notification.Name}, "/")
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	for sentKey, sent := range n.lastSent {
+		if notification.Time.Sub(sent) >= minInterval {
+			delete(n.lastSent, sentKey)
+		}
+	}
+
+	if _, ok := n.lastSent[key]; ok {
+		return false
+	}
+
+	n.lastSent[key] = notification.Time
+
+	return true
+}
+
+func (n *Notifier) send(ctx context.Context, reader client.Reader, namespace string, sink *rmn.NotificationSink,
+	notification Notification,
+) error {
+	message, err := NotificationMessage(sink.Template, notification)
+	if err != nil {
+		return err
+	}
+
+	secret := map[string][]byte{}
+
+	if sink.SecretName != "" {
+		secretObject := &corev1.Secret{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: sink.SecretName},
+			secretObject); err != nil {
+			return fmt.Errorf("failed to get secret %s/%s (%w)", namespace, sink.SecretName, err)
+		}
+
+		secret = secretObject.Data
+	}
+
+	url := sink.URL
+	if value, ok := secret["url"]; ok {
+		url = string(value)
+	}
+
+	switch sink.Type {
+	case rmn.NotificationSinkSlack:
+		return n.post(ctx, url, map[string]string{"text": message})
+	case rmn.NotificationSinkWebhook:
+		return n.post(ctx, url, struct {
+			Notification
+			Text string `json:"text"`
+		}{notification, message})
+	case rmn.NotificationSinkSMTP:
+		return notificationMail(sink.SMTP, string(secret["username"]), string(secret["password"]), notification,
+			message)
+	}
+
+	return fmt.Errorf("sink type %s is not one of %s, %s, %s", sink.Type, rmn.NotificationSinkSlack,
+		rmn.NotificationSinkWebhook, rmn.NotificationSinkSMTP)
+}
+
+func (n *Notifier) post(ctx context.Context, url string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("post response status %s", response.Status)
+	}
+
+	return nil
+}
+
+func notificationMail(config *rmn.SMTPNotificationSink, username, password string, notification Notification,
+	message string,
+) error {
+	if config == nil {
+		return fmt.Errorf("sink type %s has no smtp configuration", rmn.NotificationSinkSMTP)
+	}
+
+	port := config.Port
+	if port == 0 {
+		port = notificationSMTPPortDefault
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, config.Host)
+	}
+
+	mail := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: Ramen %s %s/%s\r\n\r\n%s\r\n", config.From,
+		strings.Join(config.To, ", "), notification.Event, notification.Namespace, notification.Name, message)
+
+	return smtp.SendMail(net.JoinHostPort(config.Host, strconv.Itoa(port)), auth, config.From, config.To,
+		[]byte(mail))
+}
+
+// NotificationMessage returns the message of a notification, as executed by a sink's template, or by the default
+// template if it has none
+func NotificationMessage(text string, notification Notification) (string, error) {
+	if text == "" {
+		text = notificationTemplateDefault
+	}
+
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("notification template parse: %w", err)
+	}
+
+	message := &strings.Builder{}
+	if err := tmpl.Execute(message, notification); err != nil {
+		return "", fmt.Errorf("notification template execute: %w", err)
+	}
+
+	return message.String(), nil
+}
+
+func notificationMinInterval(config *rmn.Notifications) time.Duration {
+	if config.MinInterval == nil {
+		return NotificationMinIntervalDefault
+	}
+
+	return config.MinInterval.Duration
+}
+
+// NotificationsValidate returns the errors of the notifications configuration
+func NotificationsValidate(config *rmn.Notifications) []error {
+	if config == nil {
+		return nil
+	}
+
+	errs := []error{}
+	names := map[string]bool{}
+
+	for i := range config.Sinks {
+		sink := &config.Sinks[i]
+
+		if names[sink.Name] {
+			errs = append(errs, fmt.Errorf("notifications sink name %q is not unique", sink.Name))
+		}
+
+		names[sink.Name] = true
+
+		switch sink.Type {
+		case rmn.NotificationSinkSlack, rmn.NotificationSinkWebhook:
+			if sink.URL == "" && sink.SecretName == "" {
+				errs = append(errs, fmt.Errorf("notifications sink %s has neither url nor secretName", sink.Name))
+			}
+		case rmn.NotificationSinkSMTP:
+			if sink.SMTP == nil || sink.SMTP.Host == "" || sink.SMTP.From == "" || len(sink.SMTP.To) == 0 {
+				errs = append(errs, fmt.Errorf("notifications sink %s smtp host, from and to are required",
+					sink.Name))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("notifications sink %s type %s is not one of %s, %s, %s", sink.Name,
+				sink.Type, rmn.NotificationSinkSlack, rmn.NotificationSinkWebhook, rmn.NotificationSinkSMTP))
+		}
+
+		if _, err := NotificationMessage(sink.Template, Notification{}); err != nil {
+			errs = append(errs, fmt.Errorf("notifications sink %s: %w", sink.Name, err))
+		}
+	}
+
+	if config.MinInterval != nil && config.MinInterval.Duration < 0 {
+		errs = append(errs, fmt.Errorf("notifications minInterval %v is negative", config.MinInterval.Duration))
+	}
+
+	return errs
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rmn "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("Notifier", func() {
+	var (
+		server   *httptest.Server
+		mutex    sync.Mutex
+		received []map[string]string
+	)
+
+	posts := func() []map[string]string {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]map[string]string{}, received...)
+	}
+
+	BeforeEach(func() {
+		received = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]string{}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+
+			mutex.Lock()
+			received = append(received, body)
+			mutex.Unlock()
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	notification := util.Notification{
+		Event:     rmn.NotificationEventFailoverStarted,
+		Kind:      "DRPlacementControl",
+		Namespace: "busybox-sample",
+		Name:      "busybox-drpc",
+		Cluster:   "east",
+		Message:   "Failing over the application and VRG",
+	}
+
+	It("executes the default template", func() {
+		message, err := util.NotificationMessage("", notification)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(message).To(Equal("FailoverStarted DRPlacementControl busybox-sample/busybox-drpc cluster east: " +
+			"Failing over the application and VRG"))
+	})
+
+	It("posts to the subscribed sinks once per minimum interval", func() {
+		config := &rmn.Notifications{
+			Sinks: []rmn.NotificationSink{
+				{Name: "slack", Type: rmn.NotificationSinkSlack, URL: server.URL, Template: "{{.Name}} {{.Event}}"},
+				{
+					Name: "rpo", Type: rmn.NotificationSinkWebhook, URL: server.URL,
+					Events: []rmn.NotificationEvent{rmn.NotificationEventRPOViolated},
+				},
+			},
+			MinInterval: &metav1.Duration{Duration: time.Hour},
+		}
+		notifier := util.NewNotifier()
+
+		notifier.Notify(nil, "", config, notification, logr.Discard())
+		notifier.Notify(nil, "", config, notification, logr.Discard())
+
+		Eventually(posts, timeout, interval).Should(HaveLen(1))
+		Consistently(posts, 10*interval, interval).Should(HaveLen(1))
+		Expect(posts()[0]).To(Equal(map[string]string{"text": "busybox-drpc FailoverStarted"}))
+	})
+
+	It("posts again once the minimum interval has passed", func() {
+		config := &rmn.Notifications{
+			Sinks:       []rmn.NotificationSink{{Name: "slack", Type: rmn.NotificationSinkSlack, URL: server.URL}},
+			MinInterval: &metav1.Duration{Duration: time.Hour},
+		}
+		notifier := util.NewNotifier()
+		sent := notification
+		sent.Time = time.Now()
+
+		notifier.Notify(nil, "", config, sent, logr.Discard())
+		Eventually(posts, timeout, interval).Should(HaveLen(1))
+
+		sent.Time = sent.Time.Add(time.Hour)
+		notifier.Notify(nil, "", config, sent, logr.Discard())
+		Eventually(posts, timeout, interval).Should(HaveLen(2))
+	})
+
+	It("validates the sinks", func() {
+		Expect(util.NotificationsValidate(&rmn.Notifications{
+			Sinks: []rmn.NotificationSink{
+				{Name: "a", Type: rmn.NotificationSinkSlack},
+				{Name: "a", Type: rmn.NotificationSinkSMTP},
+				{Name: "b", Type: "Pager", URL: server.URL, Template: "{{.Event"},
+			},
+		})).To(HaveLen(5))
+	})
+})
@@ -6,6 +6,7 @@ package util
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/go-logr/logr"
 	"golang.org/x/exp/slices"
@@ -25,8 +26,35 @@ const (
 
 	PodVolumePVCClaimIndexName    string = "spec.volumes.persistentVolumeClaim.claimName"
 	VolumeAttachmentToPVIndexName string = "spec.source.persistentVolumeName"
+
+	// PVCPriorityLabel, set on a PVC to high or low, has its data synced before or after that of the PVCs of
+	// normal priority, the default
+	PVCPriorityLabel  = "ramendr.openshift.io/dr-priority"
+	PVCPriorityHigh   = "high"
+	PVCPriorityNormal = "normal"
+	PVCPriorityLow    = "low"
 )
 
+// PVCPriorityRank returns the rank of the priority of a PVC from its labels: 0 for high, 1 for normal, and 2 for low.
+// An unknown priority is normal.
+func PVCPriorityRank(pvcLabels map[string]string) int {
+	switch pvcLabels[PVCPriorityLabel] {
+	case PVCPriorityHigh:
+		return 0
+	case PVCPriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SortPVCsByPriority sorts PVCs by the rank of their priority, keeping the order of PVCs of the same priority
+func SortPVCsByPriority(pvcs []corev1.PersistentVolumeClaim) {
+	sort.SliceStable(pvcs, func(i, j int) bool {
+		return PVCPriorityRank(pvcs[i].GetLabels()) < PVCPriorityRank(pvcs[j].GetLabels())
+	})
+}
+
 func ListPVCsByPVCSelector(
 	ctx context.Context,
 	k8sClient client.Client,
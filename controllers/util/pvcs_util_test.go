@@ -17,6 +17,32 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var _ = Describe("PVCPriorityRank", func() {
+	It("ranks high before normal before low, and an unknown priority as normal", func() {
+		Expect(util.PVCPriorityRank(map[string]string{util.PVCPriorityLabel: util.PVCPriorityHigh})).To(Equal(0))
+		Expect(util.PVCPriorityRank(map[string]string{util.PVCPriorityLabel: util.PVCPriorityNormal})).To(Equal(1))
+		Expect(util.PVCPriorityRank(map[string]string{util.PVCPriorityLabel: util.PVCPriorityLow})).To(Equal(2))
+		Expect(util.PVCPriorityRank(map[string]string{util.PVCPriorityLabel: "urgent"})).To(Equal(1))
+		Expect(util.PVCPriorityRank(nil)).To(Equal(1))
+	})
+
+	It("sorts PVCs by the rank of their priority, keeping the order of PVCs of the same priority", func() {
+		pvc := func(name, priority string) corev1.PersistentVolumeClaim {
+			return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+				Name: name, Labels: map[string]string{util.PVCPriorityLabel: priority},
+			}}
+		}
+		pvcs := []corev1.PersistentVolumeClaim{
+			pvc("a", util.PVCPriorityLow), pvc("b", ""), pvc("c", util.PVCPriorityHigh), pvc("d", util.PVCPriorityNormal),
+			pvc("e", util.PVCPriorityHigh),
+		}
+
+		util.SortPVCsByPriority(pvcs)
+		Expect(pvcs).To(HaveExactElements(HavePVCName("c"), HavePVCName("e"), HavePVCName("b"), HavePVCName("d"),
+			HavePVCName("a")))
+	})
+})
+
 var _ = Describe("PVCS_Util", func() {
 	var testNamespace *corev1.Namespace
 	var testCtx context.Context
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrReadOnlyClient is returned by the writes of a read-only client
+var ErrReadOnlyClient = errors.New("client is read-only")
+
+// readOnlyClient is a client whose writes, including those of subresources, fail without reaching the API server
+type readOnlyClient struct {
+	client.Client
+}
+
+// NewReadOnlyClient is a client.NewClientFunc that returns a read-only client
+func NewReadOnlyClient(config *rest.Config, options client.Options) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadOnlyClient(c), nil
+}
+
+// ReadOnlyClient returns a client that reads with the client, and fails its writes with ErrReadOnlyClient
+func ReadOnlyClient(c client.Client) client.Client {
+	return readOnlyClient{Client: c}
+}
+
+func (readOnlyClient) Create(context.Context, client.Object, ...client.CreateOption) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlyClient) Update(context.Context, client.Object, ...client.UpdateOption) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlyClient) Patch(context.Context, client.Object, client.Patch, ...client.PatchOption) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlyClient) Delete(context.Context, client.Object, ...client.DeleteOption) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlyClient) DeleteAllOf(context.Context, client.Object, ...client.DeleteAllOfOption) error {
+	return ErrReadOnlyClient
+}
+
+func (c readOnlyClient) Status() client.SubResourceWriter {
+	return readOnlySubResourceClient{SubResourceClient: c.Client.SubResource("status")}
+}
+
+func (c readOnlyClient) SubResource(subResource string) client.SubResourceClient {
+	return readOnlySubResourceClient{SubResourceClient: c.Client.SubResource(subResource)}
+}
+
+// readOnlySubResourceClient is a subresource client whose writes fail without reaching the API server
+type readOnlySubResourceClient struct {
+	client.SubResourceClient
+}
+
+func (readOnlySubResourceClient) Create(context.Context, client.Object, client.Object,
+	...client.SubResourceCreateOption,
+) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlySubResourceClient) Update(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+	return ErrReadOnlyClient
+}
+
+func (readOnlySubResourceClient) Patch(context.Context, client.Object, client.Patch,
+	...client.SubResourcePatchOption,
+) error {
+	return ErrReadOnlyClient
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/ramendr/ramen/controllers/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ReadOnlyClient", func() {
+	var (
+		ctx       context.Context
+		configMap *corev1.ConfigMap
+		c         client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cm"},
+			Data:       map[string]string{"key": "value"},
+		}
+		c = util.ReadOnlyClient(fake.NewClientBuilder().WithObjects(configMap.DeepCopy()).Build())
+	})
+
+	It("reads", func() {
+		read := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(configMap), read)).To(Succeed())
+		Expect(read.Data).To(Equal(configMap.Data))
+	})
+
+	It("fails writes", func() {
+		created := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "created"}}
+		Expect(c.Create(ctx, created)).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.Update(ctx, configMap)).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.Patch(ctx, configMap, client.MergeFrom(configMap))).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.Delete(ctx, configMap)).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.DeleteAllOf(ctx, &corev1.ConfigMap{})).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.Status().Update(ctx, configMap)).To(MatchError(util.ErrReadOnlyClient))
+		Expect(c.Get(ctx, client.ObjectKeyFromObject(created), created)).NotTo(Succeed())
+	})
+})
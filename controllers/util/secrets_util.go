@@ -10,7 +10,9 @@ import (
 	//nolint:gosec
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	errorswrapper "github.com/pkg/errors"
@@ -45,6 +47,10 @@ const (
 	SecretPolicyFinalizer string = "drpolicies.ramendr.openshift.io/policy-protection"
 
 	VeleroSecretKeyNameDefault = "ramengenerated"
+
+	// S3SessionTokenKey is the key of the session token of the short-lived credentials of an S3 secret, which is
+	// delivered to the clusters along with the credentials if present
+	S3SessionTokenKey = "AWS_SESSION_TOKEN"
 )
 
 // TargetSecretFormat defines the secret format to deliver to the cluster
@@ -274,14 +280,16 @@ func (in *localSecret) DeepCopyInto(out *localSecret) {
 	}
 }
 
-func newS3ConfigurationSecret(s3SecretRef corev1.SecretReference, targetns string) *localSecret {
-	return &localSecret{
+func newS3ConfigurationSecret(s3SecretRef corev1.SecretReference, targetName, targetns string,
+	sessionToken bool,
+) *localSecret {
+	secret := &localSecret{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s3SecretRef.Name,
+			Name:      targetName,
 			Namespace: targetns,
 		},
 		Data: map[string]string{
@@ -295,9 +303,24 @@ func newS3ConfigurationSecret(s3SecretRef corev1.SecretReference, targetns strin
 				"\"AWS_SECRET_ACCESS_KEY\" hub}}",
 		},
 	}
+
+	if sessionToken {
+		secret.Data[S3SessionTokenKey] = "{{hub fromSecret " +
+			"\"" + s3SecretRef.Namespace + "\"" + " " +
+			"\"" + s3SecretRef.Name + "\"" + " " +
+			"\"" + S3SessionTokenKey + "\" hub}}"
+	}
+
+	return secret
 }
 
-func newVeleroSecret(s3SecretRef corev1.SecretReference, fromNS, veleroNS, keyName string) *localSecret {
+func newVeleroSecret(s3SecretRef corev1.SecretReference, fromNS, veleroNS, keyName string,
+	sessionToken bool,
+) *localSecret {
+	if sessionToken {
+		return newVeleroSessionSecret(s3SecretRef, fromNS, veleroNS, keyName)
+	}
+
 	return &localSecret{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Secret",
@@ -327,7 +350,35 @@ func newVeleroSecret(s3SecretRef corev1.SecretReference, fromNS, veleroNS, keyNa
 	}
 }
 
-func newConfigurationPolicy(name string, object *runtime.RawExtension) *cpcv1.ConfigurationPolicy {
+// newVeleroSessionSecret is newVeleroSecret for short-lived credentials, whose session token is looked up too
+func newVeleroSessionSecret(s3SecretRef corev1.SecretReference, fromNS, veleroNS, keyName string) *localSecret {
+	lookup := "(lookup \"v1\" \"Secret\" \"" + fromNS + "\" \"" + s3SecretRef.Name + "\")"
+
+	return &localSecret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateVeleroSecretName(s3SecretRef.Name),
+			Namespace: veleroNS,
+		},
+		Data: map[string]string{
+			keyName: "{{ (printf \"[default]\\n  aws_access_key_id = %s\\n  aws_secret_access_key = %s\\n" +
+				"  aws_session_token = %s\\n\" " +
+				"(" + lookup + ".data.AWS_ACCESS_KEY_ID | base64dec) " +
+				"(" + lookup + ".data.AWS_SECRET_ACCESS_KEY | base64dec) " +
+				"(" + lookup + ".data." + S3SessionTokenKey + " | base64dec)" +
+				") | base64enc }}",
+		},
+	}
+}
+
+// newConfigurationPolicy returns a configuration policy delivering an object, and removing the objects of the
+// removed object templates, if any. The objects delivered are pruned as the policy is removed from a cluster.
+func newConfigurationPolicy(name string, object *runtime.RawExtension,
+	removed ...*cpcv1.ObjectTemplate,
+) *cpcv1.ConfigurationPolicy {
 	return &cpcv1.ConfigurationPolicy{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "ConfigurationPolicy",
@@ -337,14 +388,15 @@ func newConfigurationPolicy(name string, object *runtime.RawExtension) *cpcv1.Co
 			Name: name,
 		},
 		Spec: &cpcv1.ConfigurationPolicySpec{
-			RemediationAction: cpcv1.Enforce,
-			Severity:          "high",
-			ObjectTemplates: []*cpcv1.ObjectTemplate{
+			RemediationAction:   cpcv1.Enforce,
+			Severity:            "high",
+			PruneObjectBehavior: "DeleteIfCreated",
+			ObjectTemplates: append([]*cpcv1.ObjectTemplate{
 				{
 					ComplianceType:   cpcv1.MustHave,
 					ObjectDefinition: *object,
 				},
-			},
+			}, removed...),
 		},
 	}
 }
@@ -376,7 +428,7 @@ func newPolicy(name, namespace, triggerValue string, object runtime.RawExtension
 
 func (sutil *SecretsUtil) createPolicyResources(
 	secret *corev1.Secret,
-	cluster, namespace, targetNS string,
+	cluster, namespace, targetName, targetNS string,
 	format TargetSecretFormat,
 	veleroNS string,
 ) error {
@@ -412,7 +464,8 @@ func (sutil *SecretsUtil) createPolicyResources(
 
 	// Create a Policy object for the secret
 	configObject := newConfigurationPolicy(configPolicyName,
-		sutil.policyObject(secret.Name, namespace, targetNS, format, veleroNS))
+		sutil.policyObject(secret.Name, namespace, targetName, targetNS, format, veleroNS,
+			secretHasSessionToken(secret)))
 
 	sutil.Log.Info("Initializing secret policy trigger", "secret", secret.Name, "trigger", secret.ResourceVersion)
 
@@ -438,19 +491,23 @@ func (sutil *SecretsUtil) createPolicyResources(
 }
 
 func (sutil *SecretsUtil) policyObject(
-	secretName, secretNS, targetNS string,
+	secretName, secretNS, targetName, targetNS string,
 	format TargetSecretFormat,
 	veleroNS string,
+	sessionToken bool,
 ) *runtime.RawExtension {
 	s3SecretRef := corev1.SecretReference{Name: secretName, Namespace: secretNS}
 	object := &runtime.RawExtension{}
 
 	switch format {
 	case SecretFormatRamen:
-		object = &runtime.RawExtension{Object: newS3ConfigurationSecret(s3SecretRef, targetNS)}
+		object = &runtime.RawExtension{Object: newS3ConfigurationSecret(s3SecretRef, targetName, targetNS,
+			sessionToken)}
 	case SecretFormatVelero:
+		// The velero formatted secret is looked up from the secret delivered to the cluster
 		object = &runtime.RawExtension{
-			Object: newVeleroSecret(s3SecretRef, targetNS, veleroNS, VeleroSecretKeyNameDefault),
+			Object: newVeleroSecret(corev1.SecretReference{Name: targetName}, targetNS, veleroNS,
+				VeleroSecretKeyNameDefault, sessionToken),
 		}
 	default:
 		panic(unknownFormat)
@@ -459,6 +516,14 @@ func (sutil *SecretsUtil) policyObject(
 	return object
 }
 
+// secretHasSessionToken returns whether the secret has short-lived credentials, whose session token is to be
+// delivered too
+func secretHasSessionToken(secret *corev1.Secret) bool {
+	_, ok := secret.Data[S3SessionTokenKey]
+
+	return ok
+}
+
 func (sutil *SecretsUtil) deletePolicyResources(
 	secret *corev1.Secret,
 	namespace string,
@@ -686,7 +751,19 @@ func (sutil *SecretsUtil) AddSecretToCluster(
 	format TargetSecretFormat,
 	veleroNS string,
 ) error {
-	sutil.Log.Info("Add Secret", "cluster", clusterName, "secret", secretName, "format", format)
+	return sutil.AddSecretToClusterAs(secretName, clusterName, namespace, secretName, targetNS, format, veleroNS)
+}
+
+// AddSecretToClusterAs is AddSecretToCluster, delivering the secret with the name targetName. The secret is
+// delivered with the same name and namespace to all clusters, so a change to targetName or targetNS updates the
+// policy of the secret for all of them, which then removes the secret delivered with the previous name or namespace.
+func (sutil *SecretsUtil) AddSecretToClusterAs(
+	secretName, clusterName, namespace, targetName, targetNS string,
+	format TargetSecretFormat,
+	veleroNS string,
+) error {
+	sutil.Log.Info("Add Secret", "cluster", clusterName, "secret", secretName, "format", format,
+		"target", targetNS+"/"+targetName)
 
 	if len(secretName)+len(namespace)+len(".")+formatPrefixLen > policyNameLengthLimit {
 		return fmt.Errorf("secret namespace.name (%s.%s) length exceeds maximum character limit (%d)",
@@ -719,12 +796,157 @@ func (sutil *SecretsUtil) AddSecretToCluster(
 			return errorswrapper.Wrap(err, "failed to get placementRule object")
 		}
 
-		return sutil.createPolicyResources(secret, clusterName, namespace, targetNS, format, veleroNS)
+		return sutil.createPolicyResources(secret, clusterName, namespace, targetName, targetNS, format, veleroNS)
+	}
+
+	if err := sutil.updatePolicyObject(secret, namespace, targetName, targetNS, format, veleroNS); err != nil {
+		return err
 	}
 
 	return sutil.updatePolicyResources(plRule, secret, clusterName, namespace, format, true)
 }
 
+// updatePolicyObject updates the object delivered by the policy of a secret, if it differs from the one for the
+// target name and namespaces passed in
+func (sutil *SecretsUtil) updatePolicyObject(
+	secret *corev1.Secret,
+	namespace, targetName, targetNS string,
+	format TargetSecretFormat,
+	veleroNS string,
+) error {
+	policyName, _, _, configPolicyName := GeneratePolicyResourceNames(secret.Name, format)
+
+	policyObject := &gppv1.Policy{}
+	if err := sutil.Client.Get(sutil.Ctx,
+		types.NamespacedName{Namespace: namespace, Name: policyName},
+		policyObject); err != nil {
+		return errorswrapper.Wrap(err, fmt.Sprintf("unable to get policy (secret: %s)", secret.Name))
+	}
+
+	object := sutil.policyObject(secret.Name, namespace, targetName, targetNS, format, veleroNS,
+		secretHasSessionToken(secret))
+
+	var removed []*cpcv1.ObjectTemplate
+
+	if len(policyObject.Spec.PolicyTemplates) == 1 {
+		removed = policyObjectsRemoved(policyObject.Spec.PolicyTemplates[0].ObjectDefinition.Raw, *object)
+	}
+
+	configObject := newConfigurationPolicy(configPolicyName, object, removed...)
+
+	configObjectJSON, err := json.Marshal(configObject)
+	if err != nil {
+		return errorswrapper.Wrap(err, fmt.Sprintf("unable to marshal configuration policy (secret: %s)", secret.Name))
+	}
+
+	if len(policyObject.Spec.PolicyTemplates) == 1 &&
+		jsonEqual(policyObject.Spec.PolicyTemplates[0].ObjectDefinition.Raw, configObjectJSON) {
+		return nil
+	}
+
+	sutil.Log.Info("Updating secret policy target", "secret", secret.Name, "target", targetNS+"/"+targetName)
+
+	policyObject.Spec.PolicyTemplates = []*gppv1.PolicyTemplate{
+		{ObjectDefinition: runtime.RawExtension{Object: configObject}},
+	}
+
+	if err := sutil.Client.Update(sutil.Ctx, policyObject); err != nil {
+		return errorswrapper.Wrap(err, fmt.Sprintf("unable to update policy (secret: %s)", secret.Name))
+	}
+
+	return nil
+}
+
+// policyObjectsRemoved returns the object templates removing the secrets a configuration policy delivers or removes,
+// other than the object it is to deliver, for the policy to remove the secret delivered with a previous name or
+// namespace from the clusters
+func policyObjectsRemoved(configPolicyJSON []byte, object runtime.RawExtension) []*cpcv1.ObjectTemplate {
+	configPolicy := &cpcv1.ConfigurationPolicy{}
+	if err := json.Unmarshal(configPolicyJSON, configPolicy); err != nil || configPolicy.Spec == nil {
+		return nil
+	}
+
+	delivered, err := objectDefinitionMetadata(object)
+	if err != nil {
+		return nil
+	}
+
+	removed := []*cpcv1.ObjectTemplate{}
+
+	for _, objectTemplate := range configPolicy.Spec.ObjectTemplates {
+		if objectTemplate == nil {
+			continue
+		}
+
+		objectMetadata, err := objectDefinitionMetadata(objectTemplate.ObjectDefinition)
+		if err != nil || objectMetadata.Kind != delivered.Kind ||
+			(objectMetadata.Name == delivered.Name && objectMetadata.Namespace == delivered.Namespace) {
+			continue
+		}
+
+		removed = append(removed, &cpcv1.ObjectTemplate{
+			ComplianceType: cpcv1.MustNotHave,
+			ObjectDefinition: runtime.RawExtension{Object: &localSecret{
+				TypeMeta:   objectMetadata.TypeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: objectMetadata.Name, Namespace: objectMetadata.Namespace},
+			}},
+		})
+	}
+
+	return removed
+}
+
+func objectDefinitionMetadata(objectDefinition runtime.RawExtension) (*metav1.PartialObjectMetadata, error) {
+	objectJSON, err := json.Marshal(objectDefinition)
+	if err != nil {
+		return nil, err
+	}
+
+	objectMetadata := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(objectJSON, objectMetadata); err != nil {
+		return nil, err
+	}
+
+	return objectMetadata, nil
+}
+
+// jsonEqual returns whether two JSON documents are semantically equal, ignoring the order of object keys and
+// null valued keys, which the API server may drop
+func jsonEqual(a, b []byte) bool {
+	var aValue, bValue interface{}
+
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false
+	}
+
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(jsonNullsDrop(aValue), jsonNullsDrop(bValue))
+}
+
+func jsonNullsDrop(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, elementValue := range typedValue {
+			if elementValue == nil {
+				delete(typedValue, key)
+
+				continue
+			}
+
+			typedValue[key] = jsonNullsDrop(elementValue)
+		}
+	case []interface{}:
+		for i := range typedValue {
+			typedValue[i] = jsonNullsDrop(typedValue[i])
+		}
+	}
+
+	return value
+}
+
 // RemoveSecretFromCluster removes the secret (secretName) in namespace, from clusterName in the format requested.
 // If this was the last cluster that required the secret to be delivered in the requested format, then the related
 // policy resources are also deleted as part of the removal.
@@ -762,3 +984,70 @@ func (sutil *SecretsUtil) RemoveSecretFromCluster(
 
 	return sutil.updatePolicyResources(plRule, secret, clusterName, namespace, format, false)
 }
+
+// SecretsPlacedOnCluster returns the names of the secrets in namespace delivered to clusterName in the format
+// requested, as listed in the placement rules of the policies delivering them
+func (sutil *SecretsUtil) SecretsPlacedOnCluster(
+	clusterName, namespace string,
+	format TargetSecretFormat,
+) ([]string, error) {
+	secrets := corev1.SecretList{}
+	if err := sutil.Client.List(sutil.Ctx, &secrets, client.InNamespace(namespace)); err != nil {
+		return nil, errorswrapper.Wrap(err, "failed to list secrets")
+	}
+
+	secretNames := []string{}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !controllerutil.ContainsFinalizer(secret, SecretFinalizer(format)) {
+			continue
+		}
+
+		plRule := &plrv1.PlacementRule{}
+		if err := sutil.APIReader.Get(sutil.Ctx, types.NamespacedName{
+			Namespace: namespace,
+			Name:      generatePolicyPlacementName(secret.Name, format),
+		}, plRule); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, errorswrapper.Wrap(err, "failed to get placementRule object")
+		}
+
+		if found, _ := inspectClusters(plRule.Spec.Clusters, clusterName, false); found {
+			secretNames = append(secretNames, secret.Name)
+		}
+	}
+
+	return secretNames, nil
+}
+
+// SecretDeliveredToCluster returns whether the secret (secretName) in namespace is present on clusterName in the
+// format requested, as reported by the compliance of the cluster with the policy that delivers it
+func (sutil *SecretsUtil) SecretDeliveredToCluster(
+	secretName, clusterName, namespace string,
+	format TargetSecretFormat,
+) (bool, error) {
+	policyName, _, _, _ := GeneratePolicyResourceNames(secretName, format)
+
+	policyObject := &gppv1.Policy{}
+	if err := sutil.Client.Get(sutil.Ctx,
+		types.NamespacedName{Namespace: namespace, Name: policyName},
+		policyObject); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, errorswrapper.Wrap(err, fmt.Sprintf("unable to get policy (secret: %s)", secretName))
+	}
+
+	for _, clusterStatus := range policyObject.Status.Status {
+		if clusterStatus != nil && clusterStatus.ClusterName == clusterName {
+			return clusterStatus.ComplianceState == gppv1.Compliant, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	"golang.org/x/exp/slices"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+// localVolumeProvisioners are the provisioners of local persistent volumes, common on edge clusters where only
+// local storage exists. They support neither volume snapshots nor volume replication.
+var localVolumeProvisioners = []string{
+	"kubernetes.io/no-provisioner",
+	"rancher.io/local-path",
+	"openebs.io/local",
+}
+
+func storageClassLocalVolumes(storageClass *storagev1.StorageClass) bool {
+	return slices.Contains(localVolumeProvisioners, storageClass.Provisioner)
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// MoverSpecFieldsAnnotation records the mover spec fields last patched onto a ReplicationSource or
+// ReplicationDestination, that the VolSync API in use has no fields for
+const MoverSpecFieldsAnnotation = "ramendr.openshift.io/mover-spec-fields"
+
+// moverSpecFieldNames are the names of the mover spec fields that are set by a merge patch
+var moverSpecFieldNames = []string{"moverAffinity"}
+
+// moverSpecFields returns the configured mover spec fields, by name, that the VolSync API in use has no fields for
+func (v *VSHandler) moverSpecFields() map[string]interface{} {
+	return map[string]interface{}{"moverAffinity": moverAffinity(v.moverScheduling)}
+}
+
+// moverAffinity returns a node affinity requiring Linux nodes, and the node labels of the helper pod scheduling node
+// selector, as VolSync mover specs take an affinity rather than a node selector. VolSync movers take no tolerations.
+// Mover images are Linux images, so the movers of PVCs of Windows workloads, such as SMB PVCs, must not be scheduled
+// on the Windows nodes the workload runs on.
+func moverAffinity(scheduling *ramendrv1alpha1.HelperPodSchedulingSpec) *corev1.Affinity {
+	nodeSelector := map[string]string{corev1.LabelOSStable: string(corev1.Linux)}
+
+	if scheduling != nil {
+		for key, value := range scheduling.NodeSelector {
+			nodeSelector[key] = value
+		}
+	}
+
+	keys := make([]string, 0, len(nodeSelector))
+	for key := range nodeSelector {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(keys))
+	for _, key := range keys {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{nodeSelector[key]},
+		})
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: requirements}},
+			},
+		},
+	}
+}
+
+func rdMoverSpecName(rd *volsyncv1alpha1.ReplicationDestination) string {
+	if rd.Spec.Restic != nil {
+		return "restic"
+	}
+
+	return "rsyncTLS"
+}
+
+func rsMoverSpecName(rs *volsyncv1alpha1.ReplicationSource) string {
+	if rs.Spec.Restic != nil {
+		return "restic"
+	}
+
+	if rs.Spec.Syncthing != nil {
+		return "syncthing"
+	}
+
+	return "rsyncTLS"
+}
+
+// moverSpecFieldsPatch merge patches the mover spec fields onto the mover spec of a ReplicationSource or
+// ReplicationDestination, when they changed since last patched, or when the object was just created or updated, as
+// the typed objects drop them. Fields no longer configured are removed. VolSync releases whose mover specs lack the
+// fields prune them.
+func (v *VSHandler) moverSpecFieldsPatch(obj client.Object, moverSpecName string, op ctrlutil.OperationResult,
+) error {
+	fields := v.moverSpecFields()
+
+	value := ""
+
+	if len(fields) > 0 {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return fmt.Errorf("failed to marshal mover spec fields (%w)", err)
+		}
+
+		value = string(data)
+	}
+
+	previous := obj.GetAnnotations()[MoverSpecFieldsAnnotation]
+	if previous == value && (op == ctrlutil.OperationResultNone || value == "") {
+		return nil
+	}
+
+	moverSpec := map[string]interface{}{}
+	for _, name := range moverSpecFieldNames {
+		moverSpec[name] = fields[name]
+	}
+
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{MoverSpecFieldsAnnotation: annotation},
+		},
+		"spec": map[string]interface{}{moverSpecName: moverSpec},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal mover spec fields patch (%w)", err)
+	}
+
+	if err := v.client.Patch(v.ctx, obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to patch mover spec fields of %s (%w)",
+			getKindAndName(v.client.Scheme(), obj), err)
+	}
+
+	v.log.V(1).Info("Mover spec fields patched", "obj", getKindAndName(v.client.Scheme(), obj), "fields", value)
+
+	return nil
+}
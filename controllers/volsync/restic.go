@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	"fmt"
+
+	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// Manual trigger used on a restic ReplicationDestination to pull the latest backup before a restore
+const ResticRestoreTriggerString string = "vrg-restore"
+
+// moverFor returns the VolSync mover to use for a PVC of the given storage class
+func (v *VSHandler) moverFor(storageClassName *string) ramendrv1alpha1.VolSyncMover {
+	if v.moverConfig.Mover == "" || v.moverConfig.Mover == ramendrv1alpha1.VolSyncMoverRsyncTLS {
+		return ramendrv1alpha1.VolSyncMoverRsyncTLS
+	}
+
+	if len(v.moverConfig.StorageClassNames) == 0 {
+		return v.moverConfig.Mover
+	}
+
+	if storageClassName == nil {
+		return ramendrv1alpha1.VolSyncMoverRsyncTLS
+	}
+
+	for _, scName := range v.moverConfig.StorageClassNames {
+		if scName == *storageClassName {
+			return v.moverConfig.Mover
+		}
+	}
+
+	return ramendrv1alpha1.VolSyncMoverRsyncTLS
+}
+
+func getResticRepositorySecretName(repository, pvcName string) string {
+	return fmt.Sprintf("%s-%s", repository, pvcName)
+}
+
+func (v *VSHandler) resticRDSpec(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec,
+	volumeOptions volsyncv1alpha1.ReplicationDestinationVolumeOptions,
+) (*volsyncv1alpha1.ReplicationDestinationResticSpec, error) {
+	if v.moverConfig.Restic == nil {
+		return nil, fmt.Errorf("restic mover configuration missing for pvc %s", rdSpec.ProtectedPVC.Name)
+	}
+
+	return &volsyncv1alpha1.ReplicationDestinationResticSpec{
+		ReplicationDestinationVolumeOptions: volumeOptions,
+		Repository: getResticRepositorySecretName(v.moverConfig.Restic.Repository,
+			rdSpec.ProtectedPVC.Name),
+		CacheCapacity:        v.moverConfig.Restic.CacheCapacity,
+		MoverSecurityContext: v.moverConfig.MoverSecurityContext,
+	}, nil
+}
+
+// setResticRDSpec sets a restic spec on the ReplicationDestination, scheduled to pull backups at the same
+// interval the source pushes them
+func (v *VSHandler) setResticRDSpec(rd *volsyncv1alpha1.ReplicationDestination,
+	rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec,
+	volumeOptions volsyncv1alpha1.ReplicationDestinationVolumeOptions,
+) error {
+	restic, err := v.resticRDSpec(rdSpec, volumeOptions)
+	if err != nil {
+		return err
+	}
+
+	rd.Spec.RsyncTLS = nil
+	rd.Spec.Restic = restic
+
+	if rd.Spec.Trigger != nil && rd.Spec.Trigger.Manual == ResticRestoreTriggerString {
+		// Restore of the latest backup is requested, leave the trigger as is
+		return nil
+	}
+
+	scheduleCronSpec, err := v.getScheduleCronSpec()
+	if err != nil {
+		return err
+	}
+
+	rd.Spec.Trigger = &volsyncv1alpha1.ReplicationDestinationTriggerSpec{
+		Schedule: scheduleCronSpec,
+	}
+
+	return nil
+}
+
+func (v *VSHandler) resticRSSpec(rsSpec ramendrv1alpha1.VolSyncReplicationSourceSpec,
+	volumeOptions volsyncv1alpha1.ReplicationSourceVolumeOptions,
+) (*volsyncv1alpha1.ReplicationSourceResticSpec, error) {
+	if v.moverConfig.Restic == nil {
+		return nil, fmt.Errorf("restic mover configuration missing for pvc %s", rsSpec.ProtectedPVC.Name)
+	}
+
+	return &volsyncv1alpha1.ReplicationSourceResticSpec{
+		ReplicationSourceVolumeOptions: volumeOptions,
+		Repository: getResticRepositorySecretName(v.moverConfig.Restic.Repository,
+			rsSpec.ProtectedPVC.Name),
+		CacheCapacity:        v.moverConfig.Restic.CacheCapacity,
+		PruneIntervalDays:    v.moverConfig.Restic.PruneIntervalDays,
+		MoverSecurityContext: v.moverConfig.MoverSecurityContext,
+	}, nil
+}
+
+// ensureResticRDRestoredLatest triggers a restic ReplicationDestination to pull the latest backup from the
+// repository, and returns true once that pull is complete. Unlike rsync, where the source pushes data to the
+// destination, a restic destination only pulls on its schedule, and may not have the final sync data otherwise.
+func (v *VSHandler) ensureResticRDRestoredLatest(rd *volsyncv1alpha1.ReplicationDestination) (bool, error) {
+	if rd.Status != nil && rd.Status.LastManualSync == ResticRestoreTriggerString {
+		return true, nil
+	}
+
+	if rd.Spec.Trigger == nil || rd.Spec.Trigger.Manual != ResticRestoreTriggerString {
+		v.log.Info("Triggering restic ReplicationDestination to restore the latest backup", "rd", rd.GetName())
+
+		rd.Spec.Trigger = &volsyncv1alpha1.ReplicationDestinationTriggerSpec{
+			Manual: ResticRestoreTriggerString,
+		}
+
+		if err := v.client.Update(v.ctx, rd); err != nil {
+			return false, fmt.Errorf("failed to trigger restic ReplicationDestination %s (%w)", rd.GetName(), err)
+		}
+
+		return false, v.moverSpecFieldsPatch(rd, rdMoverSpecName(rd), ctrlutil.OperationResultUpdated)
+	}
+
+	return false, nil
+}
+
+// rdVolumeOptions returns the volume options of the mover configured in the ReplicationDestination
+func rdVolumeOptions(rd *volsyncv1alpha1.ReplicationDestination,
+) *volsyncv1alpha1.ReplicationDestinationVolumeOptions {
+	switch {
+	case rd.Spec.Restic != nil:
+		return &rd.Spec.Restic.ReplicationDestinationVolumeOptions
+	case rd.Spec.RsyncTLS != nil:
+		return &rd.Spec.RsyncTLS.ReplicationDestinationVolumeOptions
+	default:
+		return &volsyncv1alpha1.ReplicationDestinationVolumeOptions{}
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// SMBCSIDriverName is the provisioner of the SMB CSI driver, commonly used for Windows workload volumes. It
+// supports neither volume snapshots nor volume replication.
+const SMBCSIDriverName = "smb.csi.k8s.io"
+
+// PersistentVolumeClaimKind is the kind of a ReplicationDestination latest image when syncing directly into a PVC
+const PersistentVolumeClaimKind = "PersistentVolumeClaim"
+
+// storageClassSnapshotsUnsupported returns true if volumes of the storage class cannot be snapshotted: SMB volumes,
+// and local volumes if the mover is configured to protect them
+func (v *VSHandler) storageClassSnapshotsUnsupported(storageClass *storagev1.StorageClass) bool {
+	return storageClass.Provisioner == SMBCSIDriverName ||
+		v.moverConfig.LocalVolumes && storageClassLocalVolumes(storageClass)
+}
+
+// snapshotsUnsupported returns true if volumes of the storage class cannot be snapshotted. Such volumes are
+// synced directly from the source PVC into the destination PVC, and the destination PVC is used as is on failover
+// or relocate.
+func (v *VSHandler) snapshotsUnsupported(storageClassName *string) bool {
+	storageClass, err := v.getStorageClass(storageClassName)
+	if err != nil {
+		return false
+	}
+
+	return v.storageClassSnapshotsUnsupported(storageClass)
+}
+
+// CopyMethodDirectFor returns true if a PVC of the given storage class is synced directly into the application PVC
+// on the destination, either as configured for all PVCs or because the storage class does not support snapshots
+func (v *VSHandler) CopyMethodDirectFor(storageClassName *string) bool {
+	return v.IsCopyMethodDirect() || v.snapshotsUnsupported(storageClassName)
+}
+
+// volumeSnapshotClassNameFor returns the VolumeSnapshotClass for volumes of the storage class, or nil if the storage
+// class does not support snapshots
+func (v *VSHandler) volumeSnapshotClassNameFor(storageClass *storagev1.StorageClass) (*string, error) {
+	if v.storageClassSnapshotsUnsupported(storageClass) {
+		return nil, nil
+	}
+
+	volumeSnapshotClassName, err := v.getVolumeSnapshotClassFromPVCStorageClass(storageClass)
+	if err != nil {
+		return nil, err
+	}
+
+	return &volumeSnapshotClassName, nil
+}
+
+func isLatestImageDirect(latestImage *corev1.TypedLocalObjectReference) bool {
+	return latestImage != nil && latestImage.Name != "" && latestImage.Kind == PersistentVolumeClaimKind
+}
+
+// ensurePVCFromDirectRD prepares the application PVC that a ReplicationDestination syncs directly into for use by
+// the application. The ReplicationDestination is paused so that it no longer writes to the PVC. Without a
+// snapshot to roll back to, the PVC holds the data of the last sync, or of a sync interrupted by the pause.
+func (v *VSHandler) ensurePVCFromDirectRD(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec) error {
+	if _, err := v.pauseRD(getReplicationDestinationName(rdSpec.ProtectedPVC.Name),
+		rdSpec.ProtectedPVC.Namespace); err != nil {
+		return err
+	}
+
+	pvc, err := v.getPVC(util.ProtectedPVCNamespacedName(rdSpec.ProtectedPVC))
+	if err != nil {
+		return err
+	}
+
+	return v.addBackOCMAnnotationsAndUpdate(pvc, rdSpec.ProtectedPVC.Annotations)
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
+)
+
+// rsyncUnit is the multiplier of each rsync human-readable number suffix
+const rsyncUnit = 1000
+
+// rsyncSentRegex matches the summary line of an rsync run, e.g. "sent 1,003 bytes  received 5,657 bytes ...", which
+// the rsync-tls mover keeps in the ReplicationSource mover status logs
+var rsyncSentRegex = regexp.MustCompile(`(?m)^\s*sent\s+([0-9.,]+)([KMGT]?)\s+bytes\s+received\s`)
+
+// RsyncTLSSyncBytes returns the bytes sent by the last rsync-tls sync of a ReplicationSource, or nil if they are not
+// reported. rsync compares the source, or its snapshot, against the copy of the previous sync on the peer cluster, so
+// only the changes since are sent.
+func RsyncTLSSyncBytes(rs *volsyncv1alpha1.ReplicationSource) *int64 {
+	if rs.Spec.RsyncTLS == nil || rs.Status == nil || rs.Status.LatestMoverStatus == nil ||
+		rs.Status.LatestMoverStatus.Result != volsyncv1alpha1.MoverResultSuccessful {
+		return nil
+	}
+
+	return RsyncSentBytes(rs.Status.LatestMoverStatus.Logs)
+}
+
+// RsyncSentBytes returns the sum of the bytes sent reported by the rsync summary lines of the logs, or nil if there
+// are none. The mover runs rsync more than once per sync. Numbers are in rsync human-readable format, with thousands
+// separators or a suffix in units of 1000.
+func RsyncSentBytes(logs string) *int64 {
+	matches := rsyncSentRegex.FindAllStringSubmatch(logs, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var sent int64
+
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil {
+			return nil
+		}
+
+		exponent := 0
+		if match[2] != "" {
+			exponent = strings.Index("KMGT", match[2]) + 1
+		}
+
+		sent += int64(math.Round(value * math.Pow(rsyncUnit, float64(exponent))))
+	}
+
+	return &sent
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package volsync
+
+import (
+	"fmt"
+	"sort"
+
+	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// syncthingDataPort is the port of the data service VolSync creates for a syncthing ReplicationSource
+const syncthingDataPort = 22000
+
+// SetSyncthingPeers sets the syncthing devices of the peer clusters, that the syncthing ReplicationSources of the
+// PVCs connect to
+func (v *VSHandler) SetSyncthingPeers(peers []ramendrv1alpha1.VolSyncSyncthingDevice) {
+	v.syncthingPeers = peers
+}
+
+// IsSyncthing returns whether PVCs of the storage class are replicated by the Syncthing mover
+func (v *VSHandler) IsSyncthing(storageClassName *string) bool {
+	return v.moverFor(storageClassName) == ramendrv1alpha1.VolSyncMoverSyncthing
+}
+
+func (v *VSHandler) syncthingEnabled() bool {
+	return v.moverConfig.Mover == ramendrv1alpha1.VolSyncMoverSyncthing
+}
+
+func getSyncthingDataServiceName(rsName string) string {
+	// This is the name VolSync will use for the service
+	return fmt.Sprintf("volsync-%s-data", rsName)
+}
+
+// getRemoteSyncthingAddress returns the address of the syncthing data service of a PVC on the peer cluster, as
+// exported to the cluster set
+func getRemoteSyncthingAddress(pvcName, namespace string) string {
+	return fmt.Sprintf("tcp://%s.%s.svc.clusterset.local:%d",
+		getSyncthingDataServiceName(getReplicationSourceName(pvcName)), namespace, syncthingDataPort)
+}
+
+// syncthingRSSpec returns a syncthing spec connecting to the syncthing devices of the PVC on the peer clusters
+func (v *VSHandler) syncthingRSSpec(protectedPVC ramendrv1alpha1.ProtectedPVC,
+) *volsyncv1alpha1.ReplicationSourceSyncthingSpec {
+	peers := []volsyncv1alpha1.SyncthingPeer{}
+
+	for _, peer := range v.syncthingPeers {
+		if peer.Namespace != protectedPVC.Namespace || peer.Name != protectedPVC.Name {
+			continue
+		}
+
+		peers = append(peers, volsyncv1alpha1.SyncthingPeer{
+			Address: getRemoteSyncthingAddress(protectedPVC.Name, protectedPVC.Namespace),
+			ID:      peer.ID,
+		})
+	}
+
+	return &volsyncv1alpha1.ReplicationSourceSyncthingSpec{
+		Peers:                peers,
+		ServiceType:          v.getRsyncServiceType(),
+		MoverSecurityContext: v.moverConfig.MoverSecurityContext,
+	}
+}
+
+// ReconcileSyncthingReplica reconciles the copy of a PVC replicated by the Syncthing mover on a secondary cluster:
+// the PVC, and a syncthing ReplicationSource keeping it in sync with the PVC on the primary cluster, whose data
+// service is exported to the cluster set. Unlike the other movers, syncthing replicates in both directions and has
+// no ReplicationDestination. Returns the ReplicationSource once its syncthing device is reported.
+func (v *VSHandler) ReconcileSyncthingReplica(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec,
+) (*volsyncv1alpha1.ReplicationSource, error) {
+	if !rdSpec.ProtectedPVC.ProtectedByVolSync {
+		return nil, fmt.Errorf("protectedPVC %s is not VolSync Enabled", rdSpec.ProtectedPVC.Name)
+	}
+
+	if err := v.DeleteRD(rdSpec.ProtectedPVC.Name, rdSpec.ProtectedPVC.Namespace); err != nil {
+		return nil, err
+	}
+
+	if err := v.EnsurePVCforDirectCopy(v.ctx, rdSpec); err != nil {
+		return nil, err
+	}
+
+	rs, err := v.createOrUpdateRS(ramendrv1alpha1.VolSyncReplicationSourceSpec{ProtectedPVC: rdSpec.ProtectedPVC},
+		"", false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.reconcileServiceExport(rs, getSyncthingDataServiceName(rs.GetName())); err != nil {
+		return nil, err
+	}
+
+	if rs.Status == nil || rs.Status.Syncthing == nil || rs.Status.Syncthing.ID == "" {
+		v.log.V(1).Info("ReplicationSource waiting for syncthing device ID ...", "rs", rs.GetName())
+
+		return nil, nil
+	}
+
+	return rs, nil
+}
+
+// ensureSyncthingReplicaPVC returns the copy of a PVC replicated by the Syncthing mover, which is recovered as is
+func (v *VSHandler) ensureSyncthingReplicaPVC(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec) error {
+	pvc, err := v.getPVC(util.ProtectedPVCNamespacedName(rdSpec.ProtectedPVC))
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("syncthing copy of pvc %s not found", util.ProtectedPVCNamespacedName(rdSpec.ProtectedPVC))
+		}
+
+		return err
+	}
+
+	return v.removeOCMAnnotationsAndUpdate(pvc)
+}
+
+// SyncthingPeersConnected returns whether a syncthing ReplicationSource is connected to all its peers. Syncthing
+// keeps the copies of a PVC in sync continuously while they are connected, and reports no sync completion.
+func SyncthingPeersConnected(rs *volsyncv1alpha1.ReplicationSource) bool {
+	if rs.Spec.Syncthing == nil || len(rs.Spec.Syncthing.Peers) == 0 ||
+		rs.Status == nil || rs.Status.Syncthing == nil {
+		return false
+	}
+
+	connected := map[string]bool{}
+	for _, peer := range rs.Status.Syncthing.Peers {
+		connected[peer.ID] = peer.Connected
+	}
+
+	for _, peer := range rs.Spec.Syncthing.Peers {
+		if !connected[peer.ID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SyncthingDevices returns the syncthing devices of the ReplicationSources of the VRG in the namespaces, sorted
+func (v *VSHandler) SyncthingDevices(namespaces []string) ([]ramendrv1alpha1.VolSyncSyncthingDevice, error) {
+	if !v.syncthingEnabled() {
+		return nil, nil
+	}
+
+	var devices []ramendrv1alpha1.VolSyncSyncthingDevice
+
+	for _, namespace := range namespaces {
+		rsList, err := v.listRSByOwner(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx := range rsList.Items {
+			rs := &rsList.Items[idx]
+
+			if rs.Spec.Syncthing == nil || rs.Status == nil || rs.Status.Syncthing == nil ||
+				rs.Status.Syncthing.ID == "" {
+				continue
+			}
+
+			devices = append(devices, ramendrv1alpha1.VolSyncSyncthingDevice{
+				Namespace: rs.GetNamespace(),
+				Name:      rs.Spec.SourcePVC,
+				ID:        rs.Status.Syncthing.ID,
+			})
+		}
+	}
+
+	SortSyncthingDevices(devices)
+
+	return devices, nil
+}
+
+// SortSyncthingDevices sorts syncthing devices by PVC namespace and name, and device ID
+func SortSyncthingDevices(devices []ramendrv1alpha1.VolSyncSyncthingDevice) {
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Namespace != devices[j].Namespace {
+			return devices[i].Namespace < devices[j].Namespace
+		}
+
+		if devices[i].Name != devices[j].Name {
+			return devices[i].Name < devices[j].Name
+		}
+
+		return devices[i].ID < devices[j].ID
+	})
+}
@@ -73,11 +73,15 @@ type VSHandler struct {
 	destinationCopyMethod       volsyncv1alpha1.CopyMethodType
 	volumeSnapshotClassList     *snapv1.VolumeSnapshotClassList
 	vrgInAdminNamespace         bool
+	moverConfig                 ramendrv1alpha1.VolSyncMoverConfig
+	moverScheduling             *ramendrv1alpha1.HelperPodSchedulingSpec
+	syncthingPeers              []ramendrv1alpha1.VolSyncSyncthingDevice
+	priorityRankHighest         int
 }
 
 func NewVSHandler(ctx context.Context, client client.Client, log logr.Logger, owner metav1.Object,
-	asyncSpec *ramendrv1alpha1.VRGAsyncSpec, defaultCephFSCSIDriverName string, copyMethod string,
-	adminNamespaceVRG bool,
+	asyncSpec *ramendrv1alpha1.VRGAsyncSpec, moverConfig *ramendrv1alpha1.VolSyncMoverConfig,
+	defaultCephFSCSIDriverName string, copyMethod string, adminNamespaceVRG bool,
 ) *VSHandler {
 	vsHandler := &VSHandler{
 		ctx:                        ctx,
@@ -90,6 +94,10 @@ func NewVSHandler(ctx context.Context, client client.Client, log logr.Logger, ow
 		vrgInAdminNamespace:        adminNamespaceVRG,
 	}
 
+	if moverConfig != nil {
+		vsHandler.moverConfig = *moverConfig
+	}
+
 	if asyncSpec != nil {
 		vsHandler.schedulingInterval = asyncSpec.SchedulingInterval
 		vsHandler.volumeSnapshotClassSelector = asyncSpec.VolumeSnapshotClassSelector
@@ -98,6 +106,17 @@ func NewVSHandler(ctx context.Context, client client.Client, log logr.Logger, ow
 	return vsHandler
 }
 
+// SetMoverScheduling sets the scheduling hints of the helper pods, applied to the mover pods as a node affinity
+func (v *VSHandler) SetMoverScheduling(scheduling *ramendrv1alpha1.HelperPodSchedulingSpec) {
+	v.moverScheduling = scheduling
+}
+
+// SetPriorityRankHighest sets the rank of the highest priority of the PVCs replicated. The scheduled syncs of PVCs
+// of a lower priority are delayed by a minute per rank below it, for those of higher priority to start first.
+func (v *VSHandler) SetPriorityRankHighest(rank int) {
+	v.priorityRankHighest = rank
+}
+
 // returns replication destination only if create/update is successful and the RD is considered available.
 // Callers should assume getting a nil replication destination back means they should retry/requeue.
 //
@@ -147,9 +166,11 @@ func (v *VSHandler) ReconcileRD(
 		return nil, err
 	}
 
-	err = v.reconcileServiceExportForRD(rd)
-	if err != nil {
-		return nil, err
+	if rd.Spec.Restic == nil {
+		err = v.reconcileServiceExport(rd, getLocalServiceNameForRD(rd.GetName()))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if !rdStatusReady(rd, l) {
@@ -170,6 +191,11 @@ func rdStatusReady(rd *volsyncv1alpha1.ReplicationDestination, log logr.Logger)
 		return false
 	}
 
+	if rd.Spec.Restic != nil {
+		// restic pulls from the repository, there is no address to wait for
+		return true
+	}
+
 	if rd.Status.RsyncTLS == nil || rd.Status.RsyncTLS.Address == nil {
 		log.V(1).Info("ReplicationDestination waiting for Address ...")
 
@@ -185,11 +211,21 @@ func (v *VSHandler) createOrUpdateRD(
 ) {
 	l := v.log.WithValues("rdSpec", rdSpec)
 
-	volumeSnapshotClassName, err := v.GetVolumeSnapshotClassFromPVCStorageClass(rdSpec.ProtectedPVC.StorageClassName)
+	storageClass, err := v.getStorageClass(rdSpec.ProtectedPVC.StorageClassName)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeSnapshotClassName, err := v.volumeSnapshotClassNameFor(storageClass)
 	if err != nil {
 		return nil, err
 	}
 
+	copyMethod := volsyncv1alpha1.CopyMethodSnapshot
+	if volumeSnapshotClassName == nil {
+		copyMethod = volsyncv1alpha1.CopyMethodDirect
+	}
+
 	pvcAccessModes := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce} // Default value
 	if len(rdSpec.ProtectedPVC.AccessModes) > 0 {
 		pvcAccessModes = rdSpec.ProtectedPVC.AccessModes
@@ -216,18 +252,26 @@ func (v *VSHandler) createOrUpdateRD(
 		util.AddAnnotation(rd, OwnerNameAnnotation, v.owner.GetName())
 		util.AddAnnotation(rd, OwnerNamespaceAnnotation, v.owner.GetNamespace())
 
+		volumeOptions := volsyncv1alpha1.ReplicationDestinationVolumeOptions{
+			CopyMethod:              copyMethod,
+			Capacity:                rdSpec.ProtectedPVC.Resources.Requests.Storage(),
+			StorageClassName:        rdSpec.ProtectedPVC.StorageClassName,
+			AccessModes:             pvcAccessModes,
+			VolumeSnapshotClassName: volumeSnapshotClassName,
+			DestinationPVC:          dstPVC,
+		}
+
+		if v.moverFor(rdSpec.ProtectedPVC.StorageClassName) == ramendrv1alpha1.VolSyncMoverRestic {
+			return v.setResticRDSpec(rd, rdSpec, volumeOptions)
+		}
+
+		rd.Spec.Restic = nil
 		rd.Spec.RsyncTLS = &volsyncv1alpha1.ReplicationDestinationRsyncTLSSpec{
-			ServiceType: v.getRsyncServiceType(),
-			KeySecret:   &pskSecretName,
+			ServiceType:          v.getRsyncServiceType(),
+			KeySecret:            &pskSecretName,
+			MoverSecurityContext: v.moverConfig.MoverSecurityContext,
 
-			ReplicationDestinationVolumeOptions: volsyncv1alpha1.ReplicationDestinationVolumeOptions{
-				CopyMethod:              volsyncv1alpha1.CopyMethodSnapshot,
-				Capacity:                rdSpec.ProtectedPVC.Resources.Requests.Storage(),
-				StorageClassName:        rdSpec.ProtectedPVC.StorageClassName,
-				AccessModes:             pvcAccessModes,
-				VolumeSnapshotClassName: &volumeSnapshotClassName,
-				DestinationPVC:          dstPVC,
-			},
+			ReplicationDestinationVolumeOptions: volumeOptions,
 		}
 
 		return nil
@@ -238,6 +282,10 @@ func (v *VSHandler) createOrUpdateRD(
 
 	l.V(1).Info("ReplicationDestination createOrUpdate Complete", "op", op)
 
+	if err := v.moverSpecFieldsPatch(rd, rdMoverSpecName(rd), op); err != nil {
+		return nil, err
+	}
+
 	return rd, nil
 }
 
@@ -329,6 +377,13 @@ func (v *VSHandler) ReconcileRS(rsSpec ramendrv1alpha1.VolSyncReplicationSourceS
 		return false, replicationSource, err
 	}
 
+	if replicationSource.Spec.Syncthing != nil {
+		err = v.reconcileServiceExport(replicationSource, getSyncthingDataServiceName(replicationSource.GetName()))
+		if err != nil {
+			return false, replicationSource, err
+		}
+	}
+
 	//
 	// For final sync only - check status to make sure the final sync is complete
 	// and also run cleanup (removes PVC we just ran the final sync from)
@@ -402,6 +457,12 @@ func (v *VSHandler) validatePVCBeforeRS(rsSpec ramendrv1alpha1.VolSyncReplicatio
 }
 
 func isFinalSyncComplete(replicationSource *volsyncv1alpha1.ReplicationSource, log logr.Logger) bool {
+	if replicationSource.Spec.Syncthing != nil {
+		// Syncthing syncs continuously with no manual trigger, the PVC is in sync once its use stopped while the
+		// peers are connected
+		return SyncthingPeersConnected(replicationSource)
+	}
+
 	if replicationSource.Status == nil || replicationSource.Status.LastManualSync != FinalSyncTriggerString {
 		log.V(1).Info("ReplicationSource running final sync - waiting for status ...")
 
@@ -415,7 +476,8 @@ func isFinalSyncComplete(replicationSource *volsyncv1alpha1.ReplicationSource, l
 
 func (v *VSHandler) cleanupAfterRSFinalSync(rsSpec ramendrv1alpha1.VolSyncReplicationSourceSpec) error {
 	// Final sync is done, make sure PVC is cleaned up, Skip if we are using CopyMethodDirect
-	if v.IsCopyMethodDirect() {
+	if v.CopyMethodDirectFor(rsSpec.ProtectedPVC.StorageClassName) ||
+		v.IsSyncthing(rsSpec.ProtectedPVC.StorageClassName) {
 		v.log.Info("Preserving PVC to use for CopyMethodDirect", "pvcName", rsSpec.ProtectedPVC.Name)
 
 		return nil
@@ -437,7 +499,7 @@ func (v *VSHandler) createOrUpdateRS(rsSpec ramendrv1alpha1.VolSyncReplicationSo
 		return nil, err
 	}
 
-	volumeSnapshotClassName, err := v.getVolumeSnapshotClassFromPVCStorageClass(storageClass)
+	volumeSnapshotClassName, err := v.volumeSnapshotClassNameFor(storageClass)
 	if err != nil {
 		return nil, err
 	}
@@ -488,23 +550,54 @@ func (v *VSHandler) createOrUpdateRS(rsSpec ramendrv1alpha1.VolSyncReplicationSo
 
 				return err
 			}
+
+			delay := util.PVCPriorityRank(rsSpec.ProtectedPVC.Labels) - v.priorityRankHighest
+			if delay > 0 {
+				scheduleCronSpec = CronSpecDelayed(*scheduleCronSpec, delay)
+			}
+
 			rs.Spec.Trigger = &volsyncv1alpha1.ReplicationSourceTriggerSpec{
 				Schedule: scheduleCronSpec,
 			}
 		}
 
+		volumeOptions := volsyncv1alpha1.ReplicationSourceVolumeOptions{
+			CopyMethod:              v.sourceCopyMethod(storageClass),
+			VolumeSnapshotClassName: volumeSnapshotClassName,
+			StorageClassName:        rsSpec.ProtectedPVC.StorageClassName,
+			AccessModes:             rsSpec.ProtectedPVC.AccessModes,
+		}
+
+		switch v.moverFor(&storageClass.Name) {
+		case ramendrv1alpha1.VolSyncMoverRestic:
+			restic, err := v.resticRSSpec(rsSpec, volumeOptions)
+			if err != nil {
+				return err
+			}
+
+			rs.Spec.RsyncTLS = nil
+			rs.Spec.Syncthing = nil
+			rs.Spec.Restic = restic
+
+			return nil
+		case ramendrv1alpha1.VolSyncMoverSyncthing:
+			// Syncthing syncs continuously, it has no trigger
+			rs.Spec.Trigger = nil
+			rs.Spec.RsyncTLS = nil
+			rs.Spec.Restic = nil
+			rs.Spec.Syncthing = v.syncthingRSSpec(rsSpec.ProtectedPVC)
+
+			return nil
+		}
+
+		rs.Spec.Restic = nil
+		rs.Spec.Syncthing = nil
 		rs.Spec.RsyncTLS = &volsyncv1alpha1.ReplicationSourceRsyncTLSSpec{
-			KeySecret: &pskSecretName,
-			Address:   &remoteAddress,
+			KeySecret:            &pskSecretName,
+			Address:              &remoteAddress,
+			MoverSecurityContext: v.moverConfig.MoverSecurityContext,
 
-			ReplicationSourceVolumeOptions: volsyncv1alpha1.ReplicationSourceVolumeOptions{
-				// Always using CopyMethod of snapshot for now - could use 'Clone' CopyMethod for specific
-				// storage classes that support it in the future
-				CopyMethod:              volsyncv1alpha1.CopyMethodSnapshot,
-				VolumeSnapshotClassName: &volumeSnapshotClassName,
-				StorageClassName:        rsSpec.ProtectedPVC.StorageClassName,
-				AccessModes:             rsSpec.ProtectedPVC.AccessModes,
-			},
+			ReplicationSourceVolumeOptions: volumeOptions,
 		}
 
 		return nil
@@ -515,6 +608,10 @@ func (v *VSHandler) createOrUpdateRS(rsSpec ramendrv1alpha1.VolSyncReplicationSo
 
 	l.V(1).Info("ReplicationSource createOrUpdate Complete", "op", op)
 
+	if err := v.moverSpecFieldsPatch(rs, rsMoverSpecName(rs), op); err != nil {
+		return nil, err
+	}
+
 	return rs, nil
 }
 
@@ -884,16 +981,16 @@ func (v *VSHandler) CleanupRDNotInSpecList(rdSpecList []ramendrv1alpha1.VolSyncR
 	return nil
 }
 
-// Make sure a ServiceExport exists to export the service for this RD to remote clusters
+// Make sure a ServiceExport exists to export the service of this RD, or syncthing RS, to remote clusters
 // See: https://access.redhat.com/documentation/en-us/red_hat_advanced_cluster_management_for_kubernetes/
 // 2.4/html/services/services-overview#enable-service-discovery-submariner
-func (v *VSHandler) reconcileServiceExportForRD(rd *volsyncv1alpha1.ReplicationDestination) error {
+func (v *VSHandler) reconcileServiceExport(owner client.Object, serviceName string) error {
 	// Using unstructured to avoid needing to require serviceexport in client scheme
 	svcExport := &unstructured.Unstructured{}
 	svcExport.Object = map[string]interface{}{
 		"metadata": map[string]interface{}{
-			"name":      getLocalServiceNameForRD(rd.GetName()), // Get name of the local service (this needs to be exported)
-			"namespace": rd.GetNamespace(),
+			"name":      serviceName, // Name of the local service (this needs to be exported)
+			"namespace": owner.GetNamespace(),
 		},
 	}
 	svcExport.SetGroupVersionKind(schema.GroupVersionKind{
@@ -903,10 +1000,10 @@ func (v *VSHandler) reconcileServiceExportForRD(rd *volsyncv1alpha1.ReplicationD
 	})
 
 	op, err := ctrlutil.CreateOrUpdate(v.ctx, v.client, svcExport, func() error {
-		// Make this ServiceExport owned by the replication destination itself rather than the VRG
+		// Make this ServiceExport owned by the replication destination (or source) itself rather than the VRG
 		// This way on relocate scenarios or failover/failback, when the RD is cleaned up the associated
 		// ServiceExport will get cleaned up with it.
-		if err := ctrlutil.SetOwnerReference(rd, svcExport, v.client.Scheme()); err != nil {
+		if err := ctrlutil.SetOwnerReference(owner, svcExport, v.client.Scheme()); err != nil {
 			v.log.Error(err, "unable to set controller reference", "resource", svcExport)
 
 			return fmt.Errorf("%w", err)
@@ -918,8 +1015,8 @@ func (v *VSHandler) reconcileServiceExportForRD(rd *volsyncv1alpha1.ReplicationD
 	v.log.V(1).Info("ServiceExport createOrUpdate Complete", "op", op)
 
 	if err != nil {
-		v.log.Error(err, "error creating or updating ServiceExport", "replication destination name", rd.GetName(),
-			"namespace", rd.GetNamespace())
+		v.log.Error(err, "error creating or updating ServiceExport", "owner name", owner.GetName(),
+			"namespace", owner.GetNamespace())
 
 		return fmt.Errorf("error creating or updating ServiceExport (%w)", err)
 	}
@@ -973,11 +1070,35 @@ func (v *VSHandler) listByOwner(list client.ObjectList, objNamespace string) err
 
 func (v *VSHandler) EnsurePVCfromRD(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec, failoverAction bool,
 ) error {
+	if v.IsSyncthing(rdSpec.ProtectedPVC.StorageClassName) {
+		return v.ensureSyncthingReplicaPVC(rdSpec)
+	}
+
+	rd, err := v.getRD(rdSpec.ProtectedPVC.Name, rdSpec.ProtectedPVC.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if rd != nil && rd.Spec.Restic != nil {
+		restored, err := v.ensureResticRDRestoredLatest(rd)
+		if err != nil {
+			return err
+		}
+
+		if !restored {
+			return fmt.Errorf("waiting for ReplicationDestination %s to restore the latest restic backup", rd.GetName())
+		}
+	}
+
 	latestImage, err := v.getRDLatestImage(rdSpec.ProtectedPVC.Name, rdSpec.ProtectedPVC.Namespace)
 	if err != nil {
 		return err
 	}
 
+	if isLatestImageDirect(latestImage) {
+		return v.ensurePVCFromDirectRD(rdSpec)
+	}
+
 	if !isLatestImageReady(latestImage) {
 		noSnapErr := fmt.Errorf("unable to find LatestImage from ReplicationDestination %s", rdSpec.ProtectedPVC.Name)
 		v.log.Error(noSnapErr, "No latestImage", "rdSpec", rdSpec)
@@ -1573,6 +1694,35 @@ func ConvertSchedulingIntervalToCronSpec(schedulingInterval string) (*string, er
 	return &cronSpec, nil
 }
 
+// CronSpecDelayed returns a cronspec, converted from a scheduling interval, delayed by a number of minutes. The
+// cronspec is returned as is if the delay is not less than its interval in minutes.
+func CronSpecDelayed(cronSpec string, minutes int) *string {
+	fields := strings.Fields(cronSpec)
+	if len(fields) == 0 {
+		return &cronSpec
+	}
+
+	if step, found := strings.CutPrefix(fields[0], "*/"); found {
+		stepInt, err := strconv.Atoi(step)
+		if err != nil || minutes >= stepInt {
+			return &cronSpec
+		}
+
+		fields[0] = fmt.Sprintf("%d-59/%d", minutes, stepInt)
+	} else {
+		minute, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return &cronSpec
+		}
+
+		fields[0] = strconv.Itoa((minute + minutes) % 60)
+	}
+
+	delayed := strings.Join(fields, " ")
+
+	return &delayed
+}
+
 func (v *VSHandler) IsRSDataProtected(pvcName, pvcNamespace string) (bool, error) {
 	l := v.log.WithValues("pvcName", pvcName)
 
@@ -1628,12 +1778,12 @@ func (v *VSHandler) IsRDDataProtected(pvcName, pvcNamespace string) (bool, error
 		return false, err
 	}
 
-	return isLatestImageReady(latestImage), nil
+	return isLatestImageReady(latestImage) || isLatestImageDirect(latestImage), nil
 }
 
 func (v *VSHandler) PrecreateDestPVCIfEnabled(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec,
 ) (*string, error) {
-	if !v.IsCopyMethodDirect() {
+	if !v.CopyMethodDirectFor(rdSpec.ProtectedPVC.StorageClassName) {
 		v.log.Info("Using default copyMethod of Snapshot")
 
 		return nil, nil // use default copyMethod
@@ -1666,6 +1816,20 @@ func (v *VSHandler) PrecreateDestPVCIfEnabled(rdSpec ramendrv1alpha1.VolSyncRepl
 	return &rdSpec.ProtectedPVC.Name, nil
 }
 
+// sourceCopyMethod returns the CopyMethod for a ReplicationSource, defaults to Snapshot if not configured, and is
+// Direct for storage classes that do not support snapshots
+func (v *VSHandler) sourceCopyMethod(storageClass *storagev1.StorageClass) volsyncv1alpha1.CopyMethodType {
+	if v.storageClassSnapshotsUnsupported(storageClass) {
+		return volsyncv1alpha1.CopyMethodDirect
+	}
+
+	if v.moverConfig.CopyMethod == "" {
+		return volsyncv1alpha1.CopyMethodSnapshot
+	}
+
+	return volsyncv1alpha1.CopyMethodType(v.moverConfig.CopyMethod)
+}
+
 func (v *VSHandler) IsCopyMethodDirect() bool {
 	return v.destinationCopyMethod == volsyncv1alpha1.CopyMethodDirect
 }
@@ -2017,7 +2181,9 @@ func (v *VSHandler) createReadOnlyPVCFromSnapshot(rd *volsyncv1alpha1.Replicatio
 		},
 	}
 
-	pvcRequestedCapacity := rd.Spec.RsyncTLS.Capacity
+	volumeOptions := rdVolumeOptions(rd)
+
+	pvcRequestedCapacity := volumeOptions.Capacity
 	if snapRestoreSize != nil {
 		if pvcRequestedCapacity == nil || snapRestoreSize.Cmp(*pvcRequestedCapacity) > 0 {
 			pvcRequestedCapacity = snapRestoreSize
@@ -2036,7 +2202,7 @@ func (v *VSHandler) createReadOnlyPVCFromSnapshot(rd *volsyncv1alpha1.Replicatio
 
 		if pvc.CreationTimestamp.IsZero() { // set immutable fields
 			pvc.Spec.AccessModes = accessModes
-			pvc.Spec.StorageClassName = rd.Spec.RsyncTLS.StorageClassName
+			pvc.Spec.StorageClassName = volumeOptions.StorageClassName
 
 			// Only set when initially creating
 			pvc.Spec.DataSource = &snapshotRef
@@ -4,6 +4,7 @@
 package volsync_test
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	volsyncv1alpha1 "github.com/backube/volsync/api/v1alpha1"
 	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
@@ -74,6 +76,125 @@ var _ = Describe("VolSync Handler - utils", func() {
 			Expect(err).To((HaveOccurred()))
 		})
 	})
+
+	Context("When delaying a cronspec by a number of minutes", func() {
+		It("Should offset the minute of a cronspec of a step of minutes", func() {
+			Expect(*volsync.CronSpecDelayed("*/10 * * * *", 2)).To(Equal("2-59/10 * * * *"))
+		})
+		It("Should offset the minute of a cronspec of a step of hours or days", func() {
+			Expect(*volsync.CronSpecDelayed("0 */12 * * *", 1)).To(Equal("1 */12 * * *"))
+			Expect(*volsync.CronSpecDelayed("0 0 */13 * *", 2)).To(Equal("2 0 */13 * *"))
+		})
+		It("Should not delay a cronspec by its step or more", func() {
+			Expect(*volsync.CronSpecDelayed("*/2 * * * *", 2)).To(Equal("*/2 * * * *"))
+		})
+		It("Should not delay a cronspec it cannot parse", func() {
+			Expect(*volsync.CronSpecDelayed("", 1)).To(Equal(""))
+			Expect(*volsync.CronSpecDelayed("*/x * * * *", 1)).To(Equal("*/x * * * *"))
+			Expect(*volsync.CronSpecDelayed("@hourly", 1)).To(Equal("@hourly"))
+		})
+	})
+
+	Context("When parsing the bytes sent by rsync from the mover logs", func() {
+		It("Should sum the bytes sent of each rsync run", func() {
+			logs := "Total bytes sent: 833.81K\n" +
+				"sent 833.81K bytes  received 397.18K bytes  30.39K bytes/sec\n" +
+				"total size is 1.16G  speedup is 944.57\n" +
+				"sent 1,003 bytes  received 5,657 bytes  13,320.00 bytes/sec\n" +
+				"rsync completed in 2s\n"
+			sent := volsync.RsyncSentBytes(logs)
+			Expect(sent).NotTo(BeNil())
+			Expect(*sent).To(Equal(int64(833810 + 1003)))
+		})
+		It("Should not report bytes sent when there is no rsync summary", func() {
+			Expect(volsync.RsyncSentBytes("rsync completed in 2s\n")).To(BeNil())
+		})
+		It("Should not report bytes sent of a failed sync", func() {
+			rs := &volsyncv1alpha1.ReplicationSource{
+				Spec: volsyncv1alpha1.ReplicationSourceSpec{
+					RsyncTLS: &volsyncv1alpha1.ReplicationSourceRsyncTLSSpec{},
+				},
+				Status: &volsyncv1alpha1.ReplicationSourceStatus{
+					LatestMoverStatus: &volsyncv1alpha1.MoverStatus{
+						Result: volsyncv1alpha1.MoverResultFailed,
+						Logs:   "sent 1,003 bytes  received 5,657 bytes  13,320.00 bytes/sec\n",
+					},
+				},
+			}
+			Expect(volsync.RsyncTLSSyncBytes(rs)).To(BeNil())
+
+			rs.Status.LatestMoverStatus.Result = volsyncv1alpha1.MoverResultSuccessful
+			Expect(*volsync.RsyncTLSSyncBytes(rs)).To(Equal(int64(1003)))
+		})
+	})
+
+	Context("When checking the peers of a syncthing ReplicationSource are connected", func() {
+		var rs *volsyncv1alpha1.ReplicationSource
+
+		BeforeEach(func() {
+			rs = &volsyncv1alpha1.ReplicationSource{
+				Spec: volsyncv1alpha1.ReplicationSourceSpec{
+					Syncthing: &volsyncv1alpha1.ReplicationSourceSyncthingSpec{
+						Peers: []volsyncv1alpha1.SyncthingPeer{{ID: "peer-a"}, {ID: "peer-b"}},
+					},
+				},
+				Status: &volsyncv1alpha1.ReplicationSourceStatus{
+					Syncthing: &volsyncv1alpha1.ReplicationSourceSyncthingStatus{
+						Peers: []volsyncv1alpha1.SyncthingPeerStatus{
+							{ID: "peer-a", Connected: true},
+							{ID: "peer-b", Connected: true},
+						},
+					},
+				},
+			}
+		})
+		It("Should be connected when all peers are connected", func() {
+			Expect(volsync.SyncthingPeersConnected(rs)).To(BeTrue())
+		})
+		It("Should not be connected when a peer is disconnected", func() {
+			rs.Status.Syncthing.Peers[1].Connected = false
+			Expect(volsync.SyncthingPeersConnected(rs)).To(BeFalse())
+		})
+		It("Should not be connected when a peer has no status yet", func() {
+			rs.Status.Syncthing.Peers = rs.Status.Syncthing.Peers[:1]
+			Expect(volsync.SyncthingPeersConnected(rs)).To(BeFalse())
+		})
+		It("Should not be connected when there are no peers", func() {
+			rs.Spec.Syncthing.Peers = nil
+			Expect(volsync.SyncthingPeersConnected(rs)).To(BeFalse())
+		})
+	})
+
+	Context("When choosing the copy method for the storage class of a PVC", func() {
+		var c client.Client
+
+		storageClassName := func(name string) *string { return &name }
+		copyMethodDirectFor := func(moverConfig *ramendrv1alpha1.VolSyncMoverConfig, storageClass string) bool {
+			vsHandler := volsync.NewVSHandler(context.TODO(), c, logger, nil, &ramendrv1alpha1.VRGAsyncSpec{},
+				moverConfig, "none", "Snapshot", false)
+
+			return vsHandler.CopyMethodDirectFor(storageClassName(storageClass))
+		}
+
+		BeforeEach(func() {
+			c = fake.NewClientBuilder().WithObjects(
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "ceph"}, Provisioner: "rbd.csi.ceph.com"},
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "smb"}, Provisioner: volsync.SMBCSIDriverName},
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "local"}, Provisioner: "rancher.io/local-path"},
+			).Build()
+		})
+
+		It("Should copy directly volumes that cannot be snapshotted", func() {
+			Expect(copyMethodDirectFor(nil, "ceph")).To(BeFalse())
+			Expect(copyMethodDirectFor(nil, "smb")).To(BeTrue())
+			Expect(copyMethodDirectFor(nil, "missing")).To(BeFalse())
+		})
+		It("Should copy directly local volumes only if configured to protect them", func() {
+			Expect(copyMethodDirectFor(nil, "local")).To(BeFalse())
+			Expect(copyMethodDirectFor(&ramendrv1alpha1.VolSyncMoverConfig{LocalVolumes: true}, "local")).To(BeTrue())
+			Expect(copyMethodDirectFor(&ramendrv1alpha1.VolSyncMoverConfig{LocalVolumes: true}, "ceph")).To(BeFalse())
+		})
+	})
 })
 
 var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
@@ -87,7 +208,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 			var vsHandler *volsync.VSHandler
 
 			BeforeEach(func() {
-				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, "none", "Snapshot", false)
+				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, nil, "none", "Snapshot", false)
 			})
 
 			It("GetVolumeSnapshotClasses() should find all volume snapshot classes", func() {
@@ -116,7 +237,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 					},
 				}
 
-				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, "none", "Snapshot", false)
+				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, nil, "none", "Snapshot", false)
 			})
 
 			It("GetVolumeSnapshotClasses() should find matching volume snapshot classes", func() {
@@ -159,7 +280,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 					},
 				}
 
-				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, "none", "Snapshot", false)
+				vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec, nil, "none", "Snapshot", false)
 			})
 
 			It("GetVolumeSnapshotClasses() should find matching volume snapshot classes", func() {
@@ -216,7 +337,7 @@ var _ = Describe("VolSync Handler - Volume Replication Class tests", func() {
 
 			// Initialize a vshandler
 			vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, nil, asyncSpec,
-				"openshift-storage.cephfs.csi.ceph.com", "Snapshot", false)
+				nil, "openshift-storage.cephfs.csi.ceph.com", "Snapshot", false)
 		})
 
 		JustBeforeEach(func() {
@@ -431,7 +552,7 @@ var _ = Describe("VolSync_Handler", func() {
 		Expect(ownerCm.GetName()).NotTo(BeEmpty())
 		owner = ownerCm
 
-		vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, asyncSpec, "none", "Snapshot", false)
+		vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, asyncSpec, nil, "none", "Snapshot", false)
 	})
 
 	AfterEach(func() {
@@ -651,6 +772,33 @@ var _ = Describe("VolSync_Handler", func() {
 						})
 					})
 				})
+
+				Context("When helper pod scheduling is set", func() {
+					JustBeforeEach(func() {
+						vsHandler.SetMoverScheduling(&ramendrv1alpha1.HelperPodSchedulingSpec{
+							NodeSelector: map[string]string{"zone": "b", "disk": "ssd"},
+						})
+
+						var err error
+						_, err = vsHandler.ReconcileRD(rdSpec)
+						Expect(err).ToNot(HaveOccurred())
+
+						Eventually(func() error {
+							return k8sClient.Get(ctx, types.NamespacedName{
+								Name:      rdSpec.ProtectedPVC.Name,
+								Namespace: testNamespace.GetName(),
+							}, createdRD)
+						}, maxWait, interval).Should(Succeed())
+					})
+
+					It("Should record the node selector patched onto the ReplicationDestination as a node affinity", func() {
+						Expect(createdRD.GetAnnotations()).To(HaveKeyWithValue(volsync.MoverSpecFieldsAnnotation,
+							`{"moverAffinity":{"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":`+
+								`{"nodeSelectorTerms":[{"matchExpressions":[{"key":"disk","operator":"In","values":["ssd"]},`+
+								`{"key":"kubernetes.io/os","operator":"In","values":["linux"]},`+
+								`{"key":"zone","operator":"In","values":["b"]}]}]}}}}`))
+					})
+				})
 			})
 
 			Context("With CopyMethod 'Direct'", func() {
@@ -658,7 +806,7 @@ var _ = Describe("VolSync_Handler", func() {
 
 				BeforeEach(func() {
 					rdSpec.ProtectedPVC.Namespace = testNamespace.GetName()
-					vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, asyncSpec, "none", "Direct", false)
+					vsHandler = volsync.NewVSHandler(ctx, k8sClient, logger, owner, asyncSpec, nil, "none", "Direct", false)
 				})
 
 				It("PrecreateDestPVCIfEnabled() should return CopyMethod Snapshot and App PVC name", func() {
@@ -1472,7 +1620,7 @@ var _ = Describe("VolSync_Handler", func() {
 			Expect(k8sClient.Create(ctx, otherOwnerCm)).To(Succeed())
 			Expect(otherOwnerCm.GetName()).NotTo(BeEmpty())
 			otherVSHandler := volsync.NewVSHandler(ctx, k8sClient, logger, otherOwnerCm, asyncSpec,
-				"none", "Snapshot", false)
+				nil, "none", "Snapshot", false)
 
 			for i := 0; i < 2; i++ {
 				otherOwnerRdSpec := ramendrv1alpha1.VolSyncReplicationDestinationSpec{
@@ -1667,7 +1815,7 @@ var _ = Describe("VolSync_Handler", func() {
 			Expect(k8sClient.Create(ctx, otherOwnerCm)).To(Succeed())
 			Expect(otherOwnerCm.GetName()).NotTo(BeEmpty())
 			otherVSHandler := volsync.NewVSHandler(ctx, k8sClient, logger, otherOwnerCm, asyncSpec,
-				"none", "Snapshot", false)
+				nil, "none", "Snapshot", false)
 
 			for i := 0; i < 2; i++ {
 				otherOwnerRsSpec := ramendrv1alpha1.VolSyncReplicationSourceSpec{
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,6 +19,7 @@ import (
 	"github.com/ramendr/ramen/controllers/kubeobjects"
 	"github.com/ramendr/ramen/controllers/kubeobjects/velero"
 	"golang.org/x/exp/maps" // TODO replace with "maps" in go1.21+
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -27,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -55,6 +58,22 @@ type VolumeReplicationGroupReconciler struct {
 	kubeObjects         kubeobjects.RequestsManager
 	RateLimiter         *workqueue.RateLimiter
 	veleroCRsAreWatched bool
+
+	// clusterDataDriftChecks holds the cluster data last downloaded for a drift check, keyed by VRG namespaced name
+	clusterDataDriftChecks sync.Map
+
+	// standalonePeerStates holds the peer state last uploaded in standalone mode, keyed by VRG namespaced name
+	standalonePeerStates sync.Map
+
+	// clusterDataDownloads holds the cluster data downloads running in the background, keyed by VRG namespaced name
+	clusterDataDownloads sync.Map
+
+	// hookCalls holds the calls of the endpoints of recipe hooks running in the background, keyed by VRG namespaced
+	// name and hook step
+	hookCalls sync.Map
+
+	// discovery discovers the kinds of the objects a differential capture group hashes
+	discovery discovery.DiscoveryInterface
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -63,6 +82,13 @@ func (r *VolumeReplicationGroupReconciler) SetupWithManager(
 ) error {
 	r.eventRecorder = rmnutil.NewEventReporter(mgr.GetEventRecorderFor("controller_VolumeReplicationGroup"))
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client (%w)", err)
+	}
+
+	r.discovery = discoveryClient
+
 	r.Log.Info("Adding VolumeReplicationGroup controller")
 
 	rateLimiter := workqueue.NewMaxOfRateLimiter(
@@ -100,6 +126,9 @@ func (r *VolumeReplicationGroupReconciler) SetupWithManager(
 		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.configMapFun)).
 		Owns(&volrep.VolumeReplication{})
 
+	rmnutil.OwnsAcrossNamespaces(ctrlBuilder, r.Scheme, &appsv1.DaemonSet{},
+		builder.WithPredicates(rmnutil.ResourceVersionUpdatePredicate{}, failoverPreparationImagePullPredicate()))
+
 	if !ramenConfig.VolSync.Disabled {
 		r.Log.Info("VolSync enabled; adding owns and watches")
 		ctrlBuilder = r.addVolsyncOwnsAndWatches(ctrlBuilder)
@@ -142,6 +171,14 @@ func (r *VolumeReplicationGroupReconciler) configMapFun(
 ) []reconcile.Request {
 	log := ctrl.Log.WithName("configmap").WithName("VolumeReplicationGroup")
 
+	if configmap.GetLabels()[VRGLocalCacheLabel] == "true" {
+		if configmap.GetAnnotations()[VRGLocalCachePromoteAnnotation] != "true" {
+			return []reconcile.Request{}
+		}
+
+		return []reconcile.Request{{NamespacedName: rmnutil.OwnerNamespacedName(configmap)}}
+	}
+
 	if configmap.GetName() != DrClusterOperatorConfigMapName || configmap.GetNamespace() != RamenOperatorNamespace() {
 		return []reconcile.Request{}
 	}
@@ -236,6 +273,12 @@ func updateEventDecision(oldPVC, newPVC *corev1.PersistentVolumeClaim, log logr.
 		return requeue
 	}
 
+	if oldExcluded, newExcluded := pvcExcluded(oldPVC), pvcExcluded(newPVC); oldExcluded != newExcluded {
+		predicateLog.Info("Reconciling due to exclusion change", "before", oldExcluded, "after", newExcluded)
+
+		return requeue
+	}
+
 	// If finalizers change then deep equal of spec fails to catch it, we may want more
 	// conditions here, compare finalizers and also status.phase to catch bound PVCs
 	if !reflect.DeepEqual(oldPVC.Spec, newPVC.Spec) {
@@ -365,19 +408,38 @@ func filterPVC(reader client.Reader, pvc *corev1.PersistentVolumeClaim, log logr
 // +kubebuilder:rbac:groups=replication.storage.openshift.io,resources=volumereplicationclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch
-// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=create;delete;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;create;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch;update;patch;create
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=volsync.backube,resources=replicationdestinations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=volsync.backube,resources=replicationsources,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;update;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotcontents,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshotclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=kyverno.io,resources=policyexceptions,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=multicluster.x-k8s.io,resources=serviceexports,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;create;patch;update
-// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete;deletecollection
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=ramendr.openshift.io,resources=recipes,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;delete;deletecollection;get;list;update;watch
 // +kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods;services;configmaps;secrets;persistentvolumeclaims;serviceaccounts,verbs=get;list;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments;replicasets;statefulsets;daemonsets;controllerrevisions,verbs=get;list;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;patch
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;httproutes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=constraints.gatekeeper.sh,resources=*,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions;clusterserviceversions,verbs=get;list
+// +kubebuilder:rbac:groups=core,resources=limitranges,verbs=list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -390,7 +452,7 @@ func filterPVC(reader client.Reader, pvc *corev1.PersistentVolumeClaim, log logr
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.7.0/pkg/reconcile
 // nolint: funlen
 func (r *VolumeReplicationGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	log := r.Log.WithValues("VolumeReplicationGroup", req.NamespacedName, "rid", uuid.New())
+	log := r.Log.WithValues(logKeyVRG, req.NamespacedName, "rid", uuid.New())
 
 	log.Info("Entering reconcile loop")
 
@@ -414,7 +476,9 @@ func (r *VolumeReplicationGroupReconciler) Reconcile(ctx context.Context, req ct
 		if errors.IsNotFound(err) {
 			log.Info("Resource not found")
 
-			return ctrl.Result{}, nil
+			_, err := r.localCachePromote(ctx, req.NamespacedName, nil, log)
+
+			return ctrl.Result{}, err
 		}
 
 		log.Error(err, "Failed to get resource")
@@ -423,6 +487,17 @@ func (r *VolumeReplicationGroupReconciler) Reconcile(ctx context.Context, req ct
 			req.NamespacedName, err)
 	}
 
+	if promoted, err := r.localCachePromote(ctx, req.NamespacedName, v.instance, log); err != nil || promoted {
+		return ctrl.Result{}, err
+	}
+
+	log = log.WithValues(logKeyAction, v.instance.Spec.Action)
+	if correlationID := v.instance.GetAnnotations()[DRPCCorrelationIDAnnotation]; correlationID != "" {
+		log = log.WithValues(logKeyCorrelationID, correlationID)
+	}
+
+	v.log = log
+
 	_, ramenConfig, err := ConfigMapGet(ctx, r.APIReader)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get Ramen configmap: %w", err)
@@ -438,8 +513,10 @@ func (r *VolumeReplicationGroupReconciler) Reconcile(ctx context.Context, req ct
 	}
 
 	v.volSyncHandler = volsync.NewVSHandler(ctx, r.Client, log, v.instance,
-		v.instance.Spec.Async, cephFSCSIDriverNameOrDefault(v.ramenConfig),
+		v.instance.Spec.Async, v.instance.Spec.VolSync.MoverConfig, cephFSCSIDriverNameOrDefault(v.ramenConfig),
 		volSyncDestinationCopyMethodOrDefault(v.ramenConfig), adminNamespaceVRG)
+	v.volSyncHandler.SetMoverScheduling(v.instance.Spec.HelperPodScheduling)
+	v.volSyncHandler.SetSyncthingPeers(v.instance.Spec.VolSync.SyncthingPeers)
 
 	if v.instance.Status.ProtectedPVCs == nil {
 		v.instance.Status.ProtectedPVCs = []ramendrv1alpha1.ProtectedPVC{}
@@ -483,6 +560,12 @@ type VRGInstance struct {
 	objectStorers        map[string]cachedObjectStorer
 	s3StoreAccessors     []s3StoreAccessor
 	result               ctrl.Result
+
+	restoreCheckpoint      *restoreCheckpoint
+	restoreCheckpointStore ObjectStorer
+
+	// accessModesMappedPVCs are saved with, and resumed from, the restore checkpoint
+	accessModesMappedPVCs []string
 }
 
 const (
@@ -504,6 +587,15 @@ const (
 	// StorageClass label
 	StorageIDLabel = "ramendr.openshift.io/storageid"
 
+	// StorageClass annotations, set to "true" by storage admins to exclude PVCs of the class from protection,
+	// or to require that they be protected using VolSync
+	StorageClassDRExcludedAnnotation        = "ramendr.openshift.io/dr-excluded"
+	StorageClassDRRequiresVolSyncAnnotation = "ramendr.openshift.io/dr-requires-volsync"
+
+	// PVC annotation, set to "true" by application owners to exclude a PVC that matches the PVC selector, such as
+	// a scratch or cache volume, from protection
+	PVCDRExcludedAnnotation = "ramendr.openshift.io/exclude"
+
 	// VolumeReplicationClass label
 	VolumeReplicationIDLabel = "ramendr.openshift.io/replicationid"
 
@@ -515,6 +607,15 @@ func (v *VRGInstance) requeue() {
 	v.result.Requeue = true
 }
 
+// workloadNamespaces returns the protected namespaces, or the VRG namespace if none are specified
+func (v *VRGInstance) workloadNamespaces() []string {
+	if v.instance.Spec.ProtectedNamespaces != nil && len(*v.instance.Spec.ProtectedNamespaces) > 0 {
+		return *v.instance.Spec.ProtectedNamespaces
+	}
+
+	return []string{v.instance.GetNamespace()}
+}
+
 // nolint: cyclop
 func (v *VRGInstance) processVRG() ctrl.Result {
 	if err := v.validateVRGState(); err != nil {
@@ -561,6 +662,8 @@ func (v *VRGInstance) processVRG() ctrl.Result {
 		return v.dataError(err, "Failed to add finalizer to VolumeReplicationGroup", true)
 	}
 
+	v.localCacheUpdate()
+
 	switch {
 	case v.instance.Spec.ReplicationState == ramendrv1alpha1.Primary:
 		return v.processAsPrimary()
@@ -606,6 +709,9 @@ func (v *VRGInstance) validateVRGMode() error {
 }
 
 func (v *VRGInstance) clusterDataRestore(result *ctrl.Result) (int, error) {
+	v.namespaceQuotasCheck()
+	v.kubeObjectVersionsCheck()
+
 	v.log.Info("Restoring PVs and PVCs")
 
 	numRestoredForVS, err := v.restorePVsAndPVCsForVolSync()
@@ -628,7 +734,9 @@ func (v *VRGInstance) clusterDataRestore(result *ctrl.Result) (int, error) {
 		msg = "Nothing to restore"
 	}
 
+	v.accessModesPreservedConditionUpdate()
 	setVRGClusterDataReadyCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+	v.restoreCheckpointDelete()
 
 	return numRestoredForVS + numRestoredForVR, nil
 }
@@ -659,6 +767,12 @@ func (v *VRGInstance) updatePVCList() error {
 		return err
 	}
 
+	if !rmnutil.ResourceIsDeleted(v.instance) {
+		v.filterPVCsUsingAnnotations(pvcList)
+	}
+
+	rmnutil.SortPVCsByPriority(pvcList.Items)
+
 	if v.instance.Spec.Async == nil || v.instance.Spec.VolSync.Disabled {
 		v.volRepPVCs = make([]corev1.PersistentVolumeClaim, len(pvcList.Items))
 		total := copy(v.volRepPVCs, pvcList.Items)
@@ -699,7 +813,10 @@ func (v *VRGInstance) updatePVCList() error {
 }
 
 func (v *VRGInstance) updateReplicationClassList() error {
-	labelSelector := v.instance.Spec.Async.ReplicationClassSelector
+	labelSelector := metav1.LabelSelector{}
+	if v.instance.Spec.Async != nil {
+		labelSelector = v.instance.Spec.Async.ReplicationClassSelector
+	}
 
 	v.log.Info("Fetching VolumeReplicationClass", "labeled", labels.Set(labelSelector.MatchLabels))
 	listOptions := []client.ListOption{
@@ -752,6 +869,12 @@ func (v *VRGInstance) separatePVCsUsingStorageClassProvisioner(pvcList *corev1.P
 
 		replicationClassMatchFound := false
 
+		if storageClassRequiresVolSync(storageClass) || v.cephFSReadWriteManySnapshotProtected(pvc, storageClass) {
+			v.volSyncPVCs = append(v.volSyncPVCs, *pvc)
+
+			continue
+		}
+
 		for _, replicationClass := range v.replClassList.Items {
 			if storageClass.Provisioner == replicationClass.Spec.Provisioner {
 				v.volRepPVCs = append(v.volRepPVCs, *pvc)
@@ -799,14 +922,26 @@ func (v *VRGInstance) processForDeletion() ctrl.Result {
 	if v.deleteVRGHandleMode(); v.result.Requeue {
 		v.log.Info("Requeuing as reconciling VolumeReplication for deletion failed")
 
-		return v.result
+		if v.deletionWait(&v.result, fmt.Errorf("VolumeReplications not deleted")) {
+			return v.result
+		}
+
+		v.pvcsForceUnprotectVolRep(v.volRepPVCs)
+	}
+
+	if err := v.failoverPreparationStop(v.instance.Spec.ReplicationState != ramendrv1alpha1.Primary); err != nil {
+		v.log.Info("Failover preparation stop failed", "error", err)
+
+		return ctrl.Result{Requeue: true}
 	}
 
 	result := ctrl.Result{}
 	if err := v.kubeObjectsProtectionDelete(&result); err != nil {
 		v.log.Info("Kube objects protection deletion failed", "error", err)
 
-		return result
+		if v.deletionWait(&result, err) {
+			return result
+		}
 	}
 
 	if v.instance.Spec.ReplicationState == ramendrv1alpha1.Primary {
@@ -824,6 +959,11 @@ func (v *VRGInstance) processForDeletion() ctrl.Result {
 		return ctrl.Result{Requeue: true}
 	}
 
+	v.clusterDataDriftCheckForget()
+	v.standalonePeerStateForget()
+	v.clusterDataDownloadCancel()
+	v.localCacheDeletedMark()
+
 	rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeNormal,
 		rmnutil.EventReasonDeleteSuccess, "Deletion Success")
 
@@ -883,6 +1023,16 @@ func (v *VRGInstance) processAsPrimary() ctrl.Result {
 
 	defer v.log.Info("Exiting processing VolumeReplicationGroup")
 
+	v.clusterDataDriftCheckForget()
+
+	if err := v.failoverPreparationStop(false); err != nil {
+		v.log.Info("Failover preparation stop failed", "error", err)
+	}
+
+	if v.standalonePromotionWait() {
+		return v.updateVRGStatus(v.result)
+	}
+
 	if err := v.pvcsDeselectedUnprotect(); err != nil {
 		return v.dataError(err, "PVCs deselected unprotect failed", v.result.Requeue)
 	}
@@ -891,6 +1041,10 @@ func (v *VRGInstance) processAsPrimary() ctrl.Result {
 		v.result.Requeue = true
 
 		numOfRestoredRes, err := v.clusterDataRestore(&v.result)
+		if clusterDataDownloading(err) {
+			return v.updateVRGStatus(v.result)
+		}
+
 		if err != nil {
 			return v.clusterDataError(err, "Failed to restore PVs/PVCs", v.result)
 		}
@@ -951,6 +1105,7 @@ func (v *VRGInstance) reconcileAsPrimary() {
 
 	vrg := v.instance
 	v.result.Requeue = v.reconcileVolSyncAsPrimary(&finalSyncPrepared.volSync)
+	v.syncthingDevicesUpdate()
 	v.reconcileVolRepsAsPrimary()
 	v.kubeObjectsProtectPrimary(&v.result)
 	v.vrgObjectProtect(&v.result)
@@ -1036,8 +1191,24 @@ func (v *VRGInstance) processAsSecondary() ctrl.Result {
 
 	v.instance.Status.LastGroupSyncTime = nil
 
+	meta.RemoveStatusCondition(&v.instance.Status.Conditions, VRGConditionTypeNamespaceQuotasSufficient)
+	meta.RemoveStatusCondition(&v.instance.Status.Conditions, VRGConditionTypeAccessModesPreserved)
+	v.clusterDataDownloadCancel()
+
 	result := v.reconcileAsSecondary()
 
+	v.clusterDataDriftCheck(&result)
+	v.kubeObjectsRestorePreview()
+	v.failoverPrepare(&result)
+
+	if v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied != nil {
+		if err := v.policyExemptionsRemove(true); err != nil {
+			v.log.Info("Policy exemptions remove failed", "error", err)
+
+			result.Requeue = true
+		}
+	}
+
 	// If requeue is false, then VRG was successfully processed as Secondary.
 	// Hence the event to be generated is Success of type normal.
 	// Expectation is that, if something failed and requeue is true, then
@@ -1054,6 +1225,7 @@ func (v *VRGInstance) reconcileAsSecondary() ctrl.Result {
 	vrg := v.instance
 	result := ctrl.Result{}
 	result.Requeue = v.reconcileVolSyncAsSecondary() || result.Requeue
+	v.syncthingDevicesUpdate()
 	result.Requeue = v.reconcileVolRepsAsSecondary() || result.Requeue
 
 	if vrg.Spec.Action == ramendrv1alpha1.VRGActionRelocate {
@@ -1120,6 +1292,7 @@ func (v *VRGInstance) updateVRGStatus(result ctrl.Result) ctrl.Result {
 	v.log.Info("Updating VRG status")
 
 	v.updateStatusState()
+	v.standalonePeerStatePublish(&result)
 
 	v.instance.Status.ObservedGeneration = v.instance.Generation
 
@@ -1282,6 +1455,9 @@ func (v *VRGInstance) updateVRGConditions() {
 	v.updateVRGLastGroupSyncTime()
 	v.updateVRGLastGroupSyncDuration()
 	v.updateLastGroupSyncBytes()
+	v.updateWorkloadRequests()
+	v.updateWorkloadImages()
+	v.updateNamespaceSizings()
 }
 
 func (v *VRGInstance) vrgReadyStatus(reason string) *metav1.Condition {
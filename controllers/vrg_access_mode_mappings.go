@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// accessModeMappingsSelected returns the access mode mappings of the VRG that select a PVC with the labels
+func (v *VRGInstance) accessModeMappingsSelected(pvcLabels map[string]string) []ramen.AccessModeMapping {
+	mappings := []ramen.AccessModeMapping{}
+
+	for _, mapping := range v.instance.Spec.AccessModeMappings {
+		if mapping.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(mapping.Selector)
+			if err != nil {
+				v.log.Info("Access mode mapping selector invalid", "source", mapping.Source, "error", err)
+
+				continue
+			}
+
+			if !selector.Matches(labels.Set(pvcLabels)) {
+				continue
+			}
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings
+}
+
+// accessModesMapped returns the access modes rewritten by the mappings, without duplicates, and whether any were
+// rewritten. The access modes passed are not modified.
+func accessModesMapped(accessModes []corev1.PersistentVolumeAccessMode, mappings []ramen.AccessModeMapping,
+) ([]corev1.PersistentVolumeAccessMode, bool) {
+	mapped := make([]corev1.PersistentVolumeAccessMode, 0, len(accessModes))
+	rewritten := false
+
+	for _, accessMode := range accessModes {
+		for _, mapping := range mappings {
+			if mapping.Source == accessMode {
+				accessMode = mapping.Target
+				rewritten = true
+
+				break
+			}
+		}
+
+		if !slices.Contains(mapped, accessMode) {
+			mapped = append(mapped, accessMode)
+		}
+	}
+
+	return mapped, rewritten
+}
+
+// pvcAccessModesMap rewrites the access modes of a PVC restored to this cluster, and of the PV it is bound to if
+// found in the PVs, and records the PVC for the AccessModesPreserved condition
+func (v *VRGInstance) pvcAccessModesMap(pvc *corev1.PersistentVolumeClaim, pvList []corev1.PersistentVolume) {
+	mappings := v.accessModeMappingsSelected(pvc.GetLabels())
+	if len(mappings) == 0 {
+		return
+	}
+
+	accessModes, rewritten := accessModesMapped(pvc.Spec.AccessModes, mappings)
+	if !rewritten {
+		return
+	}
+
+	v.accessModesMappedRecord(pvc.Namespace, pvc.Name, pvc.Spec.AccessModes, accessModes)
+	pvc.Spec.AccessModes = accessModes
+
+	for idx := range pvList {
+		pv := &pvList[idx]
+		if pv.Name != pvc.Spec.VolumeName {
+			continue
+		}
+
+		pv.Spec.AccessModes, _ = accessModesMapped(pv.Spec.AccessModes, mappings)
+
+		v.log.Info("PV access modes mapped", "PV", pv.Name, "accessModes", pv.Spec.AccessModes)
+	}
+}
+
+// rdSpecAccessModesMapped returns the replication destination spec with the access modes of its PVC mapped for this
+// cluster
+func (v *VRGInstance) rdSpecAccessModesMapped(rdSpec ramen.VolSyncReplicationDestinationSpec,
+) ramen.VolSyncReplicationDestinationSpec {
+	mappings := v.accessModeMappingsSelected(rdSpec.ProtectedPVC.Labels)
+	if len(mappings) == 0 {
+		return rdSpec
+	}
+
+	accessModes, rewritten := accessModesMapped(rdSpec.ProtectedPVC.AccessModes, mappings)
+	if rewritten {
+		v.accessModesMappedRecord(rdSpec.ProtectedPVC.Namespace, rdSpec.ProtectedPVC.Name,
+			rdSpec.ProtectedPVC.AccessModes, accessModes)
+		rdSpec.ProtectedPVC.AccessModes = accessModes
+	}
+
+	return rdSpec
+}
+
+func (v *VRGInstance) accessModesMappedRecord(namespace, name string,
+	source, target []corev1.PersistentVolumeAccessMode,
+) {
+	v.log.Info("PVC access modes mapped", "PVC", namespace+"/"+name, "source", source, "target", target)
+
+	message := fmt.Sprintf("%s/%s %v to %v", namespace, name, source, target)
+	if !slices.Contains(v.accessModesMappedPVCs, message) {
+		v.accessModesMappedPVCs = append(v.accessModesMappedPVCs, message)
+	}
+}
+
+// accessModesPreservedConditionUpdate reports the PVCs whose access modes were mapped by the restore in the
+// AccessModesPreserved condition, which is removed if none were
+func (v *VRGInstance) accessModesPreservedConditionUpdate() {
+	if len(v.accessModesMappedPVCs) == 0 {
+		meta.RemoveStatusCondition(&v.instance.Status.Conditions, VRGConditionTypeAccessModesPreserved)
+
+		return
+	}
+
+	setVRGAccessModesMappedCondition(&v.instance.Status.Conditions, v.instance.Generation,
+		"Access modes of PVCs mapped: "+strings.Join(v.accessModesMappedPVCs, ", "))
+}
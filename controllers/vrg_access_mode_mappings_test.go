@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the access mode mappings of the PVCs restored, and their resume from checkpoints
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_AccessModeMappings", func() {
+	const (
+		rwx = corev1.ReadWriteMany
+		rwo = corev1.ReadWriteOnce
+		rox = corev1.ReadOnlyMany
+	)
+
+	var (
+		v   *VRGInstance
+		pvc *corev1.PersistentVolumeClaim
+	)
+
+	vrgInstance := func(vrg *ramen.VolumeReplicationGroup) *VRGInstance {
+		return &VRGInstance{
+			instance:       vrg,
+			namespacedName: "app/vrg",
+			log:            ctrl.Log.WithName("vrg-access-mode-mappings-test"),
+		}
+	}
+
+	BeforeEach(func() {
+		v = vrgInstance(&ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg", UID: "uid", Generation: 2},
+			Spec: ramen.VolumeReplicationGroupSpec{AccessModeMappings: []ramen.AccessModeMapping{
+				{Source: rwx, Target: rwo, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}}},
+				{Source: rox, Target: rwo},
+			}},
+		})
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db", Labels: map[string]string{"app": "db"}},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{rwx, rox},
+				VolumeName:  "pv",
+			},
+		}
+	})
+
+	Describe("accessModesMapped", func() {
+		It("maps the access modes of the mappings, without duplicates, and keeps the others", func() {
+			mappings := v.instance.Spec.AccessModeMappings
+			accessModes := []corev1.PersistentVolumeAccessMode{rwx, rox}
+
+			mapped, rewritten := accessModesMapped(accessModes, mappings)
+			Expect(mapped).To(Equal([]corev1.PersistentVolumeAccessMode{rwo}))
+			Expect(rewritten).To(BeTrue())
+			Expect(accessModes).To(Equal([]corev1.PersistentVolumeAccessMode{rwx, rox}))
+
+			mapped, rewritten = accessModesMapped([]corev1.PersistentVolumeAccessMode{rwo}, mappings)
+			Expect(mapped).To(Equal([]corev1.PersistentVolumeAccessMode{rwo}))
+			Expect(rewritten).To(BeFalse())
+		})
+	})
+
+	Describe("accessModeMappingsSelected", func() {
+		It("selects the mappings without a selector, and those whose selector matches the labels", func() {
+			Expect(v.accessModeMappingsSelected(pvc.Labels)).To(HaveLen(2))
+			Expect(v.accessModeMappingsSelected(nil)).To(ConsistOf(HaveField("Source", rox)))
+		})
+	})
+
+	Describe("pvcAccessModesMap", func() {
+		It("maps the access modes of a PVC and its PV, and reports the PVC in the AccessModesPreserved condition",
+			func() {
+				pvList := []corev1.PersistentVolume{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "pv"},
+						Spec:       corev1.PersistentVolumeSpec{AccessModes: []corev1.PersistentVolumeAccessMode{rwx}},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "other"},
+						Spec:       corev1.PersistentVolumeSpec{AccessModes: []corev1.PersistentVolumeAccessMode{rwx}},
+					},
+				}
+
+				v.pvcAccessModesMap(pvc, pvList)
+				Expect(pvc.Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{rwo}))
+				Expect(pvList[0].Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{rwo}))
+				Expect(pvList[1].Spec.AccessModes).To(Equal([]corev1.PersistentVolumeAccessMode{rwx}))
+
+				v.accessModesPreservedConditionUpdate()
+				condition := meta.FindStatusCondition(v.instance.Status.Conditions, VRGConditionTypeAccessModesPreserved)
+				Expect(condition).To(HaveValue(And(
+					HaveField("Status", metav1.ConditionFalse),
+					HaveField("Reason", VRGConditionReasonAccessModesMapped),
+					HaveField("Message", ContainSubstring("app/db")),
+				)))
+			})
+
+		It("does not report the AccessModesPreserved condition if no access modes were mapped", func() {
+			pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{rwo}
+			v.pvcAccessModesMap(pvc, nil)
+			v.accessModesPreservedConditionUpdate()
+			Expect(meta.FindStatusCondition(v.instance.Status.Conditions, VRGConditionTypeAccessModesPreserved)).
+				To(BeNil())
+		})
+	})
+
+	Describe("restoreCheckpoint", func() {
+		It("resumes the PVCs whose access modes were mapped before the restore was interrupted", func() {
+			objectStore := memoryObjectStorer{}
+
+			v.restoreCheckpointLoad(objectStore, "profile")
+			v.pvcAccessModesMap(pvc, nil)
+			v.restoreCheckpointSave()
+
+			resumed := vrgInstance(v.instance.DeepCopy())
+			resumed.restoreCheckpointLoad(objectStore, "profile")
+			Expect(resumed.accessModesMappedPVCs).To(Equal(v.accessModesMappedPVCs))
+
+			resumed.accessModesPreservedConditionUpdate()
+			Expect(meta.FindStatusCondition(resumed.instance.Status.Conditions, VRGConditionTypeAccessModesPreserved)).
+				To(HaveValue(HaveField("Message", ContainSubstring("app/db"))))
+		})
+
+		It("does not resume the PVCs mapped by a restore of another generation of the VRG", func() {
+			objectStore := memoryObjectStorer{}
+
+			v.restoreCheckpointLoad(objectStore, "profile")
+			v.pvcAccessModesMap(pvc, nil)
+			v.restoreCheckpointSave()
+
+			restarted := vrgInstance(v.instance.DeepCopy())
+			restarted.instance.Generation++
+			restarted.restoreCheckpointLoad(objectStore, "profile")
+			Expect(restarted.accessModesMappedPVCs).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/replicationplugin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ArrayReplicationPluginsRegister registers the plugin of each array based replication provider as a sync
+// replication provider, for storage classes to select it by name
+func ArrayReplicationPluginsRegister(plugins []ramendrv1alpha1.ArrayReplicationPlugin) error {
+	for _, plugin := range plugins {
+		client, err := replicationplugin.NewClient(plugin.Endpoint)
+		if err != nil {
+			return err
+		}
+
+		syncReplicationProviderRegister(plugin.Name, arrayReplicationSyncProvider{provider: client})
+	}
+
+	return nil
+}
+
+// arrayReplicationSyncProvider replicates the volumes of a VRG with the replication of an array, through a plugin.
+// The volumes of the VRG of a storage class are a volume group, promoted, demoted and resynced together.
+type arrayReplicationSyncProvider struct {
+	provider replicationplugin.Provider
+}
+
+// volumeGroup returns the volume group of the PVC: the volumes of the PVCs of the VRG of its storage class
+func (arrayReplicationSyncProvider) volumeGroup(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+) (replicationplugin.VolumeGroup, error) {
+	group := replicationplugin.VolumeGroup{}
+
+	storageClass, err := v.getStorageClass(pvcNamespacedName)
+	if err != nil {
+		return group, err
+	}
+
+	group.ID = fmt.Sprintf("%s/%s/%s", v.instance.Namespace, v.instance.Name, storageClass.GetName())
+	group.Parameters = storageClass.Parameters
+
+	for idx := range v.volRepPVCs {
+		pvc := &v.volRepPVCs[idx]
+
+		if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != storageClass.GetName() {
+			continue
+		}
+
+		pv, err := v.getPVFromPVC(pvc)
+		if err != nil {
+			return group, err
+		}
+
+		if pv.Spec.CSI == nil {
+			return group, fmt.Errorf("pv %s of pvc %s/%s is not a CSI volume", pv.GetName(), pvc.Namespace, pvc.Name)
+		}
+
+		group.VolumeHandles = append(group.VolumeHandles, pv.Spec.CSI.VolumeHandle)
+	}
+
+	return group, nil
+}
+
+func (a arrayReplicationSyncProvider) status(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+) (replicationplugin.VolumeGroup, *replicationplugin.VolumeGroupStatus, error) {
+	group, err := a.volumeGroup(v, pvcNamespacedName)
+	if err != nil {
+		return group, nil, fmt.Errorf("failed to get the volume group of pvc %s (%w)", pvcNamespacedName, err)
+	}
+
+	status, err := a.provider.Status(v.ctx, group)
+	if err != nil {
+		return group, nil, fmt.Errorf("failed to get the status of volume group %s (%w)", group.ID, err)
+	}
+
+	return group, status, nil
+}
+
+// processAsPrimary promotes the volume group, forcibly on failover, until the array reports it primary
+func (a arrayReplicationSyncProvider) processAsPrimary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	log logr.Logger,
+) (bool, bool, error) {
+	group, status, err := a.status(v, pvcNamespacedName)
+	if err != nil {
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonErrorUnknown, err.Error())
+
+		return true, false, err
+	}
+
+	if status.State != replicationplugin.VolumeGroupPrimary {
+		force := v.instance.Spec.Action == ramendrv1alpha1.VRGActionFailover
+
+		log.Info("Promoting volume group", "group", group.ID, "state", status.State, "force", force)
+
+		if err := a.provider.Promote(v.ctx, group, force); err != nil {
+			v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+				VRGConditionReasonError, fmt.Sprintf("Failed to promote volume group %s: %v", group.ID, err))
+
+			return true, false, fmt.Errorf("failed to promote volume group %s (%w)", group.ID, err)
+		}
+
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonProgressing, fmt.Sprintf("Promoting volume group %s", group.ID))
+
+		return true, false, nil
+	}
+
+	msg := "PVC in the VolumeReplicationGroup is ready for use"
+	v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name, VRGConditionReasonReady, msg)
+	v.updatePVCDataProtectedCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name, VRGConditionReasonReady,
+		msg)
+	v.updatePVCLastSyncTime(pvcNamespacedName.Namespace, pvcNamespacedName.Name, metaTime(status.LastSyncTime))
+
+	return false, true, nil
+}
+
+// processAsSecondary demotes the volume group, and resyncs it if required, until the array reports it in sync
+func (a arrayReplicationSyncProvider) processAsSecondary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	log logr.Logger,
+) (bool, bool, error) {
+	group, status, err := a.status(v, pvcNamespacedName)
+	if err != nil {
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonErrorUnknown, err.Error())
+
+		return true, false, err
+	}
+
+	switch {
+	case status.State != replicationplugin.VolumeGroupSecondary:
+		log.Info("Demoting volume group", "group", group.ID, "state", status.State)
+
+		err = a.provider.Demote(v.ctx, group)
+	case status.ResyncRequired:
+		log.Info("Resyncing volume group", "group", group.ID)
+
+		err = a.provider.Resync(v.ctx, group)
+	case !status.Synced:
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonReplicating, fmt.Sprintf("Volume group %s is syncing: %s", group.ID, status.Message))
+		v.updatePVCDataProtectedCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonReplicating, fmt.Sprintf("Volume group %s is syncing", group.ID))
+
+		return true, false, nil
+	default:
+		msg := "Volume group of the pvc as Secondary is in sync with Primary"
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonReplicated, msg)
+		v.updatePVCDataProtectedCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonDataProtected, msg)
+		v.updatePVCLastSyncTime(pvcNamespacedName.Namespace, pvcNamespacedName.Name, metaTime(status.LastSyncTime))
+
+		return false, true, nil
+	}
+
+	if err != nil {
+		v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+			VRGConditionReasonError, fmt.Sprintf("Failed to replicate volume group %s as secondary: %v", group.ID, err))
+
+		return true, false, fmt.Errorf("failed to replicate volume group %s as secondary (%w)", group.ID, err)
+	}
+
+	v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+		VRGConditionReasonProgressing, fmt.Sprintf("Replicating volume group %s as secondary", group.ID))
+
+	return true, false, nil
+}
+
+func (arrayReplicationSyncProvider) volumesShared() bool { return false }
+
+func metaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+
+	return &metav1.Time{Time: *t}
+}
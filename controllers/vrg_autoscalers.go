@@ -0,0 +1,290 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+const (
+	// AutoscalerPinnedOnRecoveryAnnotation is set on the HorizontalPodAutoscalers whose maximum replicas were pinned
+	// to their minimum replicas as they were recovered, to the maximum replicas to restore once the recovered
+	// workload is ready
+	AutoscalerPinnedOnRecoveryAnnotation = "ramendr.openshift.io/pinned-on-recovery"
+
+	autoscalersResource = "horizontalpodautoscalers.autoscaling"
+)
+
+// autoscalerScaleTargetResourceModifier is the Velero resource modifier that restores the workload an autoscaler
+// scales at the autoscaler's minimum replicas
+const autoscalerScaleTargetResourceModifier = `- conditions:
+    groupResource: %s
+    resourceNameRegex: '^%s$'
+    namespaces:
+    - %s
+  patches:
+  - operation: replace
+    path: /spec/replicas
+    value: "%d"
+`
+
+func autoscalersPinned(vrg ramen.VolumeReplicationGroup) bool {
+	return vrg.Spec.KubeObjectProtection != nil && vrg.Spec.KubeObjectProtection.PinRecoveredScale
+}
+
+// recoverWorkflowAutoscalersFirst, if the VRG pins the recovered autoscalers, excludes the HorizontalPodAutoscalers
+// from the groups of a recover workflow that recover every kind, and inserts before the first of them, for each
+// capture they recover from, a group that recovers its autoscalers, so that they are pinned, and the workloads they
+// scale restored at their minimum replicas, before the workloads start. Groups that run a hook, recover from a
+// capture taken at recovery, or list the kinds they recover, are kept as they are.
+func recoverWorkflowAutoscalersFirst(workflow []kubeobjects.RecoverSpec, vrg ramen.VolumeReplicationGroup,
+) []kubeobjects.RecoverSpec {
+	if !autoscalersPinned(vrg) {
+		return workflow
+	}
+
+	separated := make([]kubeobjects.RecoverSpec, 0, len(workflow)+1)
+	backupNames := sets.New[string]()
+
+	for _, group := range workflow {
+		if group.BackupName == ramen.ReservedBackupName || kubeObjectsHookRunnable(group.Spec) != nil ||
+			(len(group.IncludedResources) != 0 && !containsString(group.IncludedResources, "*")) {
+			separated = append(separated, group)
+
+			continue
+		}
+
+		if !backupNames.Has(group.BackupName) {
+			backupNames.Insert(group.BackupName)
+
+			separated = append(separated, kubeobjects.RecoverSpec{
+				BackupName: group.BackupName,
+				Spec: kubeobjects.Spec{
+					KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+						IncludedNamespaces: group.IncludedNamespaces,
+						IncludedResources:  []string{autoscalersResource},
+					},
+					LabelSelector: group.LabelSelector,
+				},
+				NamespaceMapping: group.NamespaceMapping,
+			})
+		}
+
+		group.ExcludedResources = append(append([]string{}, group.ExcludedResources...), autoscalersResource)
+		separated = append(separated, group)
+	}
+
+	return separated
+}
+
+// autoscalersRecovered lists the recovered HorizontalPodAutoscalers from the cache, ordered by namespace and name
+func (v *VRGInstance) autoscalersRecovered() ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := []autoscalingv2.HorizontalPodAutoscaler{}
+
+	for _, listOptions := range namespacesListOptions {
+		autoscalers := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := v.reconciler.List(v.ctx, autoscalers, listOptions); err != nil {
+			return nil, fmt.Errorf("failed to list horizontal pod autoscalers in namespace %s (%w)",
+				listOptions.Namespace, err)
+		}
+
+		sort.Slice(autoscalers.Items, func(i, j int) bool {
+			return autoscalers.Items[i].Name < autoscalers.Items[j].Name
+		})
+
+		recovered = append(recovered, autoscalers.Items...)
+	}
+
+	return recovered, nil
+}
+
+// autoscalersPinRecovered pins the HorizontalPodAutoscalers recovered so far at their minimum replicas, by lowering
+// their maximum replicas to it, and annotates them with the maximum replicas to restore once the recovered workload
+// is ready. It is called before each recover group is submitted, so that the autoscalers recovered ahead of the
+// workloads are pinned before the workloads start. Autoscalers already annotated, as captured while pinned, are not
+// pinned again, for their original maximum to be restored.
+func (v *VRGInstance) autoscalersPinRecovered() error {
+	if !autoscalersPinned(*v.instance) {
+		return nil
+	}
+
+	autoscalers, err := v.autoscalersRecovered()
+	if err != nil {
+		return err
+	}
+
+	for i := range autoscalers {
+		if err := v.autoscalerPin(&autoscalers[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) autoscalerPin(autoscaler *autoscalingv2.HorizontalPodAutoscaler) error {
+	if _, ok := autoscaler.GetAnnotations()[AutoscalerPinnedOnRecoveryAnnotation]; ok {
+		return nil
+	}
+
+	minReplicas := autoscalerMinReplicas(autoscaler)
+
+	patch := client.MergeFrom(autoscaler.DeepCopy())
+
+	if autoscaler.Annotations == nil {
+		autoscaler.Annotations = map[string]string{}
+	}
+
+	autoscaler.Annotations[AutoscalerPinnedOnRecoveryAnnotation] = strconv.Itoa(int(autoscaler.Spec.MaxReplicas))
+	autoscaler.Spec.MaxReplicas = minReplicas
+
+	if err := v.reconciler.Patch(v.ctx, autoscaler, patch); err != nil {
+		return fmt.Errorf("failed to pin horizontal pod autoscaler %s/%s (%w)", autoscaler.Namespace,
+			autoscaler.Name, err)
+	}
+
+	v.log.Info("Recovered horizontal pod autoscaler pinned", "name", autoscaler.Name,
+		"namespace", autoscaler.Namespace, "replicas", minReplicas)
+
+	return nil
+}
+
+// autoscalersScaleTargetsResourceModifiers returns the Velero resource modifiers that restore the workloads the
+// recovered HorizontalPodAutoscalers scale at the autoscalers' minimum replicas, for the workloads recovered after
+// their autoscalers to start pinned. Workloads of kinds the cluster does not serve are skipped.
+func (v *VRGInstance) autoscalersScaleTargetsResourceModifiers() (string, error) {
+	if !autoscalersPinned(*v.instance) {
+		return "", nil
+	}
+
+	autoscalers, err := v.autoscalersRecovered()
+	if err != nil {
+		return "", err
+	}
+
+	var resourceModifiers strings.Builder
+
+	for i := range autoscalers {
+		autoscaler := &autoscalers[i]
+		target := autoscaler.Spec.ScaleTargetRef
+
+		groupVersion, err := schema.ParseGroupVersion(target.APIVersion)
+		if err != nil {
+			return "", fmt.Errorf("horizontal pod autoscaler %s/%s scale target api version %s invalid (%w)",
+				autoscaler.Namespace, autoscaler.Name, target.APIVersion, err)
+		}
+
+		groupKind := schema.GroupKind{Group: groupVersion.Group, Kind: target.Kind}
+
+		mapping, err := v.reconciler.RESTMapper().RESTMapping(groupKind, groupVersion.Version)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return "", fmt.Errorf("failed to map kind %s (%w)", groupKind, err)
+		}
+
+		fmt.Fprintf(&resourceModifiers, autoscalerScaleTargetResourceModifier,
+			mapping.Resource.GroupResource().String(), regexp.QuoteMeta(target.Name), autoscaler.Namespace,
+			autoscalerMinReplicas(autoscaler))
+	}
+
+	return resourceModifiers.String(), nil
+}
+
+// autoscalersUnpin restores the maximum replicas of the HorizontalPodAutoscalers that were pinned as they were
+// recovered, re-enabling autoscaling, once the recovered workload is ready. The autoscalers are listed from the
+// cache, as this is called on every reconcile.
+func (v *VRGInstance) autoscalersUnpin() error {
+	pinned := []*autoscalingv2.HorizontalPodAutoscaler{}
+
+	for _, namespace := range v.workloadNamespaces() {
+		autoscalers := &autoscalingv2.HorizontalPodAutoscalerList{}
+		if err := v.reconciler.List(v.ctx, autoscalers, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list horizontal pod autoscalers in namespace %s (%w)", namespace, err)
+		}
+
+		for i := range autoscalers.Items {
+			if _, ok := autoscalers.Items[i].GetAnnotations()[AutoscalerPinnedOnRecoveryAnnotation]; ok {
+				pinned = append(pinned, &autoscalers.Items[i])
+			}
+		}
+	}
+
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	ready, err := v.workloadReady()
+	if err != nil || !ready {
+		return err
+	}
+
+	errs := []error{}
+
+	for _, autoscaler := range pinned {
+		if err := v.autoscalerUnpin(autoscaler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// autoscalerUnpin restores the maximum replicas of a pinned autoscaler from its annotation, and removes the
+// annotation. An annotation that is not a maximum at least the minimum replicas is kept, and the autoscaler left
+// pinned, until it is corrected, for the autoscaler not to be left pinned unnoticed.
+func (v *VRGInstance) autoscalerUnpin(autoscaler *autoscalingv2.HorizontalPodAutoscaler) error {
+	annotation := autoscaler.Annotations[AutoscalerPinnedOnRecoveryAnnotation]
+
+	maxReplicas, err := strconv.ParseInt(annotation, 10, 32)
+	if err != nil || int32(maxReplicas) < autoscalerMinReplicas(autoscaler) {
+		return fmt.Errorf("horizontal pod autoscaler %s/%s left pinned: annotation %s value %q is not a maximum "+
+			"replicas at least its minimum replicas", autoscaler.Namespace, autoscaler.Name,
+			AutoscalerPinnedOnRecoveryAnnotation, annotation)
+	}
+
+	patch := client.MergeFrom(autoscaler.DeepCopy())
+
+	autoscaler.Spec.MaxReplicas = int32(maxReplicas)
+	delete(autoscaler.Annotations, AutoscalerPinnedOnRecoveryAnnotation)
+
+	if err := v.reconciler.Patch(v.ctx, autoscaler, patch); err != nil {
+		return fmt.Errorf("failed to unpin horizontal pod autoscaler %s/%s (%w)", autoscaler.Namespace,
+			autoscaler.Name, err)
+	}
+
+	v.log.Info("Recovered horizontal pod autoscaler unpinned", "name", autoscaler.Name,
+		"namespace", autoscaler.Namespace, "maxReplicas", autoscaler.Spec.MaxReplicas)
+
+	return nil
+}
+
+func autoscalerMinReplicas(autoscaler *autoscalingv2.HorizontalPodAutoscaler) int32 {
+	if autoscaler.Spec.MinReplicas == nil {
+		return 1
+	}
+
+	return *autoscaler.Spec.MinReplicas
+}
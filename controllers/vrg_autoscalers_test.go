@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the pinning of the scale of recovered autoscalers and their workloads
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_Autoscalers", func() {
+	const namespace = "app"
+
+	var (
+		c           client.Client
+		vrgInstance *VRGInstance
+	)
+
+	restoredLabels := map[string]string{veleroRestoreNameLabel: "restore"}
+	minReplicas := int32(2)
+	autoscaler := func(name string, labels, annotations map[string]string, kind string,
+	) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, Labels: labels, Annotations: annotations,
+			},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+					APIVersion: "apps/v1", Kind: kind, Name: name + ".v1",
+				},
+				MinReplicas: &minReplicas,
+				MaxReplicas: 10,
+			},
+		}
+	}
+	autoscalerGet := func(name string) *autoscalingv2.HorizontalPodAutoscaler {
+		autoscaler := &autoscalingv2.HorizontalPodAutoscaler{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, autoscaler)).
+			To(Succeed())
+
+		return autoscaler
+	}
+
+	BeforeEach(func() {
+		restMapper := meta.NewDefaultRESTMapper(nil)
+		restMapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+
+		c = fake.NewClientBuilder().WithRESTMapper(restMapper).WithObjects(
+			autoscaler("web", restoredLabels, nil, "Deployment"),
+			autoscaler("pinned", restoredLabels, map[string]string{AutoscalerPinnedOnRecoveryAnnotation: "6"},
+				"Deployment"),
+			autoscaler("custom", restoredLabels, nil, "Rollout"),
+			autoscaler("existing", nil, nil, "Deployment"),
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web.v1"},
+				Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse},
+				}},
+			},
+		).Build()
+		vrgInstance = vrgInstanceFake(c, "vrg-autoscalers-test", ramen.VolumeReplicationGroupSpec{
+			KubeObjectProtection: &ramen.KubeObjectProtectionSpec{PinRecoveredScale: true},
+		})
+	})
+
+	Describe("recoverWorkflowAutoscalersFirst", func() {
+		group := func(backupName string, includedResources ...string) kubeobjects.RecoverSpec {
+			return kubeobjects.RecoverSpec{BackupName: backupName, Spec: kubeobjects.Spec{
+				KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+					IncludedNamespaces: []string{namespace},
+					IncludedResources:  includedResources,
+				},
+			}}
+		}
+		workflow := []kubeobjects.RecoverSpec{group("config", "configmaps"), group("app"), group("app", "*")}
+
+		It("keeps the workflow of a VRG that does not pin the recovered autoscalers", func() {
+			vrgInstance.instance.Spec.KubeObjectProtection.PinRecoveredScale = false
+			Expect(recoverWorkflowAutoscalersFirst(workflow, *vrgInstance.instance)).To(Equal(workflow))
+		})
+
+		It("recovers the autoscalers of each capture before the groups that recover every kind", func() {
+			separated := recoverWorkflowAutoscalersFirst(workflow, *vrgInstance.instance)
+			Expect(separated).To(HaveLen(4))
+			Expect(separated[0]).To(Equal(workflow[0]))
+			Expect(separated[1]).To(Equal(group("app", autoscalersResource)))
+			Expect(separated[2].ExcludedResources).To(Equal([]string{autoscalersResource}))
+			Expect(separated[3].ExcludedResources).To(Equal([]string{autoscalersResource}))
+			Expect(workflow[1].ExcludedResources).To(BeEmpty())
+		})
+	})
+
+	Describe("autoscalersPinRecovered", func() {
+		It("pins the recovered autoscalers not pinned yet at their minimum replicas", func() {
+			Expect(vrgInstance.autoscalersPinRecovered()).To(Succeed())
+
+			web := autoscalerGet("web")
+			Expect(web.Spec.MaxReplicas).To(BeEquivalentTo(2))
+			Expect(web.Annotations).To(HaveKeyWithValue(AutoscalerPinnedOnRecoveryAnnotation, "10"))
+			Expect(autoscalerGet("pinned").Annotations).To(HaveKeyWithValue(AutoscalerPinnedOnRecoveryAnnotation, "6"))
+			Expect(autoscalerGet("existing").Annotations).To(BeEmpty())
+		})
+
+		It("pins none for a VRG that does not pin the recovered autoscalers", func() {
+			vrgInstance.instance.Spec.KubeObjectProtection.PinRecoveredScale = false
+			Expect(vrgInstance.autoscalersPinRecovered()).To(Succeed())
+			Expect(autoscalerGet("web").Spec.MaxReplicas).To(BeEquivalentTo(10))
+		})
+	})
+
+	Describe("autoscalersScaleTargetsResourceModifiers", func() {
+		It("restores the workloads of the recovered autoscalers of kinds served at their minimum replicas", func() {
+			resourceModifiers, err := vrgInstance.autoscalersScaleTargetsResourceModifiers()
+			Expect(err).ToNot(HaveOccurred())
+
+			rules := []map[string]interface{}{}
+			Expect(yaml.Unmarshal([]byte(resourceModifiers), &rules)).To(Succeed())
+			Expect(rules).To(HaveLen(2))
+			Expect(rules).To(HaveEach(HaveKeyWithValue("patches", []interface{}{map[string]interface{}{
+				"operation": "replace", "path": "/spec/replicas", "value": "2",
+			}})))
+			Expect(rules[0]).To(HaveKeyWithValue("conditions", map[string]interface{}{
+				"groupResource":     "deployments.apps",
+				"resourceNameRegex": `^pinned\.v1$`,
+				"namespaces":        []interface{}{namespace},
+			}))
+			Expect(rules[1]).To(HaveKeyWithValue("conditions", HaveKeyWithValue("resourceNameRegex", `^web\.v1$`)))
+
+			Expect(yaml.Unmarshal([]byte(cronJobsSuspendResourceModifiers+resourceModifiers),
+				&map[string]interface{}{})).To(Succeed())
+		})
+	})
+
+	Describe("autoscalersUnpin", func() {
+		It("restores the maximum replicas of the pinned autoscalers once the workload is ready", func() {
+			Expect(vrgInstance.autoscalersPinRecovered()).To(Succeed())
+			Expect(vrgInstance.autoscalersUnpin()).To(Succeed())
+			Expect(autoscalerGet("web").Spec.MaxReplicas).To(BeEquivalentTo(2))
+
+			deployment := &appsv1.Deployment{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "web.v1"}, deployment)).
+				To(Succeed())
+			deployment.Status.Conditions[0].Status = corev1.ConditionTrue
+			Expect(c.Status().Update(context.TODO(), deployment)).To(Succeed())
+
+			Expect(vrgInstance.autoscalersUnpin()).To(Succeed())
+
+			web := autoscalerGet("web")
+			Expect(web.Spec.MaxReplicas).To(BeEquivalentTo(10))
+			Expect(web.Annotations).ToNot(HaveKey(AutoscalerPinnedOnRecoveryAnnotation))
+			Expect(autoscalerGet("pinned").Spec.MaxReplicas).To(BeEquivalentTo(6))
+		})
+
+		It("leaves an autoscaler whose annotation is invalid pinned and annotated, and unpins the others", func() {
+			Expect(vrgInstance.autoscalersPinRecovered()).To(Succeed())
+
+			custom := autoscalerGet("custom")
+			custom.Annotations[AutoscalerPinnedOnRecoveryAnnotation] = "1"
+			Expect(c.Update(context.TODO(), custom)).To(Succeed())
+			Expect(c.Delete(context.TODO(), &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web.v1"},
+			})).To(Succeed())
+
+			Expect(vrgInstance.autoscalersUnpin()).To(MatchError(ContainSubstring("custom left pinned")))
+
+			custom = autoscalerGet("custom")
+			Expect(custom.Spec.MaxReplicas).To(BeEquivalentTo(2))
+			Expect(custom.Annotations).To(HaveKeyWithValue(AutoscalerPinnedOnRecoveryAnnotation, "1"))
+			Expect(autoscalerGet("web").Spec.MaxReplicas).To(BeEquivalentTo(10))
+		})
+	})
+})
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+const (
+	// JobRecoverLabel, set to "true" on a job, has the job recovered, and so run again, on failover or relocation.
+	// Other jobs, such as one-shot migration jobs, and the jobs of cron jobs, which the recovered cron jobs create
+	// again, are not recovered.
+	JobRecoverLabel = "ramendr.openshift.io/recover-job"
+
+	// CronJobSuspendedOnRecoveryAnnotation is set, by the restore, on the cron jobs it suspended as it recovered
+	// them, for them to be resumed once the recovered workload is verified
+	CronJobSuspendedOnRecoveryAnnotation = "ramendr.openshift.io/suspended-on-recovery"
+
+	// VRGResumeCronJobsAnnotation requests to resume the cron jobs suspended as they were recovered when set to a
+	// value that differs from the request they were last resumed for in the VRG status, such as a timestamp
+	VRGResumeCronJobsAnnotation = "volumereplicationgroups.ramendr.openshift.io/resume-cron-jobs"
+
+	jobsResource = "jobs.batch"
+)
+
+// cronJobsSuspendResourceModifiers are the Velero resource modifiers that suspend the cron jobs, that are not
+// suspended, as they are restored, and annotate them as suspended on recovery, so that none runs before the
+// recovered workload is verified. A patch whose test fails is skipped: the annotations are added to the cron jobs
+// that have none only. The annotation's value is not a boolean, as Velero would not quote it.
+const cronJobsSuspendResourceModifiers = `version: v1
+resourceModifierRules:
+- conditions:
+    groupResource: cronjobs.batch
+  patches:
+  - operation: test
+    path: /metadata/annotations
+    value: "null"
+  - operation: add
+    path: /metadata/annotations
+    value: "{}"
+- conditions:
+    groupResource: cronjobs.batch
+  patches:
+  - operation: test
+    path: /spec/suspend
+    value: "false"
+  - operation: replace
+    path: /spec/suspend
+    value: "true"
+  - operation: add
+    path: /metadata/annotations/ramendr.openshift.io~1suspended-on-recovery
+    value: restore
+`
+
+// recoverWorkflowJobsSeparate excludes the jobs from the groups of a recover workflow that recover every kind, and
+// appends a group, for each capture they recover from, that recovers the jobs labeled to be recovered only. Groups
+// that run a hook, recover from a capture taken at recovery, or list the kinds they recover, are kept as they are.
+func recoverWorkflowJobsSeparate(workflow []kubeobjects.RecoverSpec) []kubeobjects.RecoverSpec {
+	separated := make([]kubeobjects.RecoverSpec, 0, len(workflow))
+	jobsGroups := []kubeobjects.RecoverSpec{}
+	backupNames := sets.New[string]()
+
+	for _, group := range workflow {
+		if group.BackupName == ramen.ReservedBackupName || kubeObjectsHookRunnable(group.Spec) != nil ||
+			(len(group.IncludedResources) != 0 && !containsString(group.IncludedResources, "*")) {
+			separated = append(separated, group)
+
+			continue
+		}
+
+		group.ExcludedResources = append(append([]string{}, group.ExcludedResources...), jobsResource)
+		separated = append(separated, group)
+
+		if backupNames.Has(group.BackupName) {
+			continue
+		}
+
+		backupNames.Insert(group.BackupName)
+
+		jobsGroups = append(jobsGroups, kubeobjects.RecoverSpec{
+			BackupName: group.BackupName,
+			Spec: kubeobjects.Spec{
+				KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+					IncludedNamespaces: group.IncludedNamespaces,
+					IncludedResources:  []string{jobsResource},
+				},
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{JobRecoverLabel: "true"}},
+			},
+			NamespaceMapping: group.NamespaceMapping,
+		})
+	}
+
+	return append(separated, jobsGroups...)
+}
+
+// cronJobsResume resumes the cron jobs that were suspended as they were recovered once requested, and records the
+// request in the VRG status, so that they are resumed once per request
+func (v *VRGInstance) cronJobsResume() error {
+	status := &v.instance.Status.KubeObjectProtection
+
+	request, ok := v.instance.GetAnnotations()[VRGResumeCronJobsAnnotation]
+	if !ok || status.CronJobsResumed == request {
+		return nil
+	}
+
+	suspend := false
+
+	for _, namespace := range v.workloadNamespaces() {
+		cronJobs := &batchv1.CronJobList{}
+		if err := v.reconciler.APIReader.List(v.ctx, cronJobs, client.InNamespace(namespace)); err != nil {
+			return fmt.Errorf("failed to list cron jobs in namespace %s (%w)", namespace, err)
+		}
+
+		for i := range cronJobs.Items {
+			cronJob := &cronJobs.Items[i]
+			if _, ok := cronJob.GetAnnotations()[CronJobSuspendedOnRecoveryAnnotation]; !ok {
+				continue
+			}
+
+			delete(cronJob.Annotations, CronJobSuspendedOnRecoveryAnnotation)
+			cronJob.Spec.Suspend = &suspend
+
+			if err := v.reconciler.Update(v.ctx, cronJob); err != nil {
+				return fmt.Errorf("failed to resume cron job %s/%s (%w)", cronJob.Namespace, cronJob.Name, err)
+			}
+
+			v.log.Info("Recovered cron job resumed", "name", cronJob.Name, "namespace", cronJob.Namespace)
+		}
+	}
+
+	status.CronJobsResumed = request
+
+	return nil
+}
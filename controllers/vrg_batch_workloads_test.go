@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the recovery of jobs and the resumption of the cron jobs suspended on recovery
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_BatchWorkloads", func() {
+	Describe("recoverWorkflowJobsSeparate", func() {
+		It("recovers the labeled jobs after the groups of their capture that recover every kind", func() {
+			group := func(backupName string, includedResources ...string) kubeobjects.RecoverSpec {
+				return kubeobjects.RecoverSpec{BackupName: backupName, Spec: kubeobjects.Spec{
+					KubeResourcesSpec: kubeobjects.KubeResourcesSpec{IncludedResources: includedResources},
+				}}
+			}
+
+			workflow := recoverWorkflowJobsSeparate([]kubeobjects.RecoverSpec{
+				group("config", "configmaps"),
+				group("app"),
+				group("app", "*"),
+				group(ramen.ReservedBackupName),
+			})
+			Expect(workflow).To(HaveLen(5))
+			Expect(workflow[0].ExcludedResources).To(BeEmpty())
+			Expect(workflow[1].ExcludedResources).To(Equal([]string{jobsResource}))
+			Expect(workflow[2].ExcludedResources).To(Equal([]string{jobsResource}))
+			Expect(workflow[3].ExcludedResources).To(BeEmpty())
+			Expect(workflow[4].BackupName).To(Equal("app"))
+			Expect(workflow[4].IncludedResources).To(Equal([]string{jobsResource}))
+			Expect(workflow[4].LabelSelector.MatchLabels).To(Equal(map[string]string{JobRecoverLabel: "true"}))
+		})
+	})
+
+	Describe("cronJobsResume", func() {
+		var vrgInstance *VRGInstance
+
+		suspended := func(name string) bool {
+			cronJob := &batchv1.CronJob{}
+			Expect(vrgInstance.reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: name},
+				cronJob)).To(Succeed())
+
+			return *cronJob.Spec.Suspend
+		}
+
+		BeforeEach(func() {
+			suspend := true
+			c := fake.NewClientBuilder().WithObjects(
+				&batchv1.CronJob{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "recovered", Annotations: map[string]string{
+						CronJobSuspendedOnRecoveryAnnotation: "restore",
+					}},
+					Spec: batchv1.CronJobSpec{Suspend: &suspend},
+				},
+				&batchv1.CronJob{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "suspended"},
+					Spec:       batchv1.CronJobSpec{Suspend: &suspend},
+				},
+			).Build()
+			vrgInstance = vrgInstanceFake(c, "vrg-batch-workloads-test", ramen.VolumeReplicationGroupSpec{})
+		})
+
+		It("resumes the cron jobs suspended on recovery once per request", func() {
+			Expect(vrgInstance.cronJobsResume()).To(Succeed())
+			Expect(suspended("recovered")).To(BeTrue())
+
+			vrgInstance.instance.Annotations = map[string]string{VRGResumeCronJobsAnnotation: "1"}
+			Expect(vrgInstance.cronJobsResume()).To(Succeed())
+			Expect(suspended("recovered")).To(BeFalse())
+			Expect(suspended("suspended")).To(BeTrue())
+			Expect(vrgInstance.instance.Status.KubeObjectProtection.CronJobsResumed).To(Equal("1"))
+		})
+
+		It("does not resume the cron jobs suspended on a later recovery for the same request", func() {
+			vrgInstance.instance.Annotations = map[string]string{VRGResumeCronJobsAnnotation: "1"}
+			vrgInstance.instance.Status.KubeObjectProtection.CronJobsResumed = "1"
+			Expect(vrgInstance.cronJobsResume()).To(Succeed())
+			Expect(suspended("recovered")).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// clusterDataDownloadPollInterval is how often a VRG reconciles, and reports the progress, while its cluster data is
+// downloaded in the background
+const clusterDataDownloadPollInterval = 5 * time.Second
+
+var errClusterDataDownloading = errors.New("cluster data download in progress")
+
+func clusterDataDownloading(err error) bool {
+	return errors.Is(err, errClusterDataDownloading)
+}
+
+// clusterDataDownload downloads the PVs and PVCs of a VRG from an S3 store in the background, so that reconciles
+// of a VRG with many PVs are not blocked for the duration of the download
+type clusterDataDownload struct {
+	s3ProfileName string
+	startTime     metav1.Time
+	cancel        context.CancelFunc
+
+	mutex     sync.Mutex
+	completed int
+	total     int
+	done      bool
+	pvs       []corev1.PersistentVolume
+	pvcs      []corev1.PersistentVolumeClaim
+	err       error
+}
+
+// clusterDataDownloadProfiles returns the S3 profiles to restore the cluster data from in this attempt. An attempt
+// settles on the profile of the download in the VRG status, past the profiles that it tried before, until the
+// download completes, so that it is not restarted with other profiles, nor after an operator restart.
+func (v *VRGInstance) clusterDataDownloadProfiles() []string {
+	s3Profiles := v.instance.Spec.S3Profiles
+
+	transfer := v.instance.Status.S3Transfer
+	if transfer == nil || transfer.Operation != ramen.S3TransferOperationDownload {
+		return s3Profiles
+	}
+
+	if i := slices.Index(s3Profiles, transfer.S3ProfileName); i >= 0 {
+		return s3Profiles[i:]
+	}
+
+	return s3Profiles
+}
+
+// clusterDataDownloaded returns the PVs and PVCs of the VRG in the S3 store. The download starts in the background
+// on the first call, and errClusterDataDownloading is returned, with the download progress in the VRG status,
+// until a later call finds it complete. A download in the VRG status that is no longer running, such as after an
+// operator restart, is resumed from the start with its start time.
+func (v *VRGInstance) clusterDataDownloaded(objectStore ObjectStorer, s3ProfileName string, result *ctrl.Result,
+) ([]corev1.PersistentVolume, []corev1.PersistentVolumeClaim, error) {
+	if value, ok := v.reconciler.clusterDataDownloads.Load(v.namespacedName); ok {
+		download, _ := value.(*clusterDataDownload)
+		if download.s3ProfileName == s3ProfileName {
+			return v.clusterDataDownloadProgress(download, result)
+		}
+
+		v.clusterDataDownloadCancel()
+	}
+
+	startTime := metav1.Now()
+	if transfer := v.instance.Status.S3Transfer; transfer != nil &&
+		transfer.Operation == ramen.S3TransferOperationDownload && transfer.S3ProfileName == s3ProfileName {
+		startTime = transfer.StartTime
+
+		v.log.Info("Cluster data download resumed", "profile", s3ProfileName, "completed", transfer.Completed,
+			"total", transfer.Total)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	download := &clusterDataDownload{s3ProfileName: s3ProfileName, startTime: startTime, cancel: cancel}
+	v.reconciler.clusterDataDownloads.Store(v.namespacedName, download)
+
+	v.log.Info("Cluster data download started", "profile", s3ProfileName)
+
+	go download.run(ctx, objectStore, v.s3KeyPrefix())
+
+	return v.clusterDataDownloadProgress(download, result)
+}
+
+func (v *VRGInstance) clusterDataDownloadProgress(download *clusterDataDownload, result *ctrl.Result,
+) ([]corev1.PersistentVolume, []corev1.PersistentVolumeClaim, error) {
+	download.mutex.Lock()
+	defer download.mutex.Unlock()
+
+	if !download.done {
+		v.instance.Status.S3Transfer = &ramen.S3TransferStatus{
+			Operation:     ramen.S3TransferOperationDownload,
+			S3ProfileName: download.s3ProfileName,
+			Completed:     download.completed,
+			Total:         download.total,
+			StartTime:     download.startTime,
+		}
+
+		delaySetIfLess(result, clusterDataDownloadPollInterval, v.log)
+
+		return nil, nil, errClusterDataDownloading
+	}
+
+	v.reconciler.clusterDataDownloads.Delete(v.namespacedName)
+	v.instance.Status.S3Transfer = nil
+
+	v.log.Info("Cluster data download completed", "profile", download.s3ProfileName, "objects", download.completed,
+		"duration", time.Since(download.startTime.Time), "error", download.err)
+
+	return download.pvs, download.pvcs, download.err
+}
+
+// clusterDataDownloadCancel cancels a cluster data download in progress, such as when the VRG is deleted or is no
+// longer primary
+func (v *VRGInstance) clusterDataDownloadCancel() {
+	value, ok := v.reconciler.clusterDataDownloads.LoadAndDelete(v.namespacedName)
+	if !ok {
+		return
+	}
+
+	download, _ := value.(*clusterDataDownload)
+	download.cancel()
+	v.instance.Status.S3Transfer = nil
+
+	v.log.Info("Cluster data download canceled", "profile", download.s3ProfileName)
+}
+
+func (d *clusterDataDownload) run(ctx context.Context, objectStore ObjectStorer, keyPrefix string) {
+	pvs, pvcs, err := d.download(ctx, objectStore, keyPrefix)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.pvs, d.pvcs, d.err = pvs, pvcs, err
+	d.done = true
+}
+
+func (d *clusterDataDownload) download(ctx context.Context, objectStore ObjectStorer, keyPrefix string,
+) ([]corev1.PersistentVolume, []corev1.PersistentVolumeClaim, error) {
+	pvKeys, err := objectStore.ListKeys(typedKey(keyPrefix, "", reflect.TypeOf(corev1.PersistentVolume{})))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list PV keys, %w", err)
+	}
+
+	pvcKeys, err := objectStore.ListKeys(typedKey(keyPrefix, "", reflect.TypeOf(corev1.PersistentVolumeClaim{})))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list PVC keys, %w", err)
+	}
+
+	d.mutex.Lock()
+	d.total = len(pvKeys) + len(pvcKeys)
+	d.mutex.Unlock()
+
+	pvs := make([]corev1.PersistentVolume, len(pvKeys))
+	for i := range pvKeys {
+		if err := d.downloadObject(ctx, objectStore, pvKeys[i], &pvs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pvcs := make([]corev1.PersistentVolumeClaim, len(pvcKeys))
+	for i := range pvcKeys {
+		if err := d.downloadObject(ctx, objectStore, pvcKeys[i], &pvcs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return pvs, pvcs, nil
+}
+
+func (d *clusterDataDownload) downloadObject(ctx context.Context, objectStore ObjectStorer, key string,
+	objectPointer interface{},
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := objectStore.DownloadObject(key, objectPointer); err != nil {
+		return fmt.Errorf("unable to DownloadObject of key %s, %w", key, err)
+	}
+
+	d.mutex.Lock()
+	d.completed++
+	d.mutex.Unlock()
+
+	return nil
+}
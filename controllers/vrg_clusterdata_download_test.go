@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the background download of cluster data
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// gatedObjectStorer is a memory object store whose keys are listed once its gate is open
+type gatedObjectStorer struct {
+	memoryObjectStorer
+	gate chan struct{}
+}
+
+func (g gatedObjectStorer) ListKeys(keyPrefix string) ([]string, error) {
+	<-g.gate
+
+	return g.memoryObjectStorer.ListKeys(keyPrefix)
+}
+
+var _ = Describe("VRG_ClusterDataDownload", func() {
+	var (
+		store      gatedObjectStorer
+		vrg        *ramen.VolumeReplicationGroup
+		reconciler *VolumeReplicationGroupReconciler
+	)
+
+	vrgInstance := func() *VRGInstance {
+		return &VRGInstance{
+			reconciler:     reconciler,
+			ctx:            context.TODO(),
+			log:            ctrl.Log.WithName("vrg-clusterdata-download-test"),
+			instance:       vrg,
+			namespacedName: vrg.Namespace + "/" + vrg.Name,
+		}
+	}
+
+	BeforeEach(func() {
+		vrg = &ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "vrg"},
+			Spec:       ramen.VolumeReplicationGroupSpec{S3Profiles: []string{"east", "west"}},
+		}
+		reconciler = &VolumeReplicationGroupReconciler{}
+		store = gatedObjectStorer{memoryObjectStorer{}, make(chan struct{})}
+
+		keyPrefix := vrgInstance().s3KeyPrefix()
+		pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv"}}
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "pvc"}}
+		Expect(store.UploadObject(typedKey(keyPrefix, pv.Name, reflect.TypeOf(*pv)), pv)).To(Succeed())
+		Expect(store.UploadObject(typedKey(keyPrefix, pvc.Name, reflect.TypeOf(*pvc)), pvc)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		close(store.gate)
+	})
+
+	Describe("clusterDataDownloadProfiles", func() {
+		It("settles on the profile of the download in progress", func() {
+			Expect(vrgInstance().clusterDataDownloadProfiles()).To(Equal([]string{"east", "west"}))
+
+			vrg.Status.S3Transfer = &ramen.S3TransferStatus{
+				Operation: ramen.S3TransferOperationDownload, S3ProfileName: "west",
+			}
+			Expect(vrgInstance().clusterDataDownloadProfiles()).To(Equal([]string{"west"}))
+
+			vrg.Status.S3Transfer.S3ProfileName = "removed"
+			Expect(vrgInstance().clusterDataDownloadProfiles()).To(Equal([]string{"east", "west"}))
+		})
+	})
+
+	Describe("clusterDataDownloaded", func() {
+		It("downloads the PVs and PVCs in the background, with the progress in the VRG status", func() {
+			result := &ctrl.Result{}
+
+			_, _, err := vrgInstance().clusterDataDownloaded(store, "west", result)
+			Expect(clusterDataDownloading(err)).To(BeTrue())
+			Expect(vrg.Status.S3Transfer).NotTo(BeNil())
+			Expect(vrg.Status.S3Transfer.S3ProfileName).To(Equal("west"))
+			Expect(result.RequeueAfter).To(Equal(clusterDataDownloadPollInterval))
+
+			var (
+				pvs  []corev1.PersistentVolume
+				pvcs []corev1.PersistentVolumeClaim
+			)
+
+			store.gate <- struct{}{}
+			store.gate <- struct{}{}
+
+			Eventually(func() error {
+				pvs, pvcs, err = vrgInstance().clusterDataDownloaded(store, "west", result)
+
+				return err
+			}, time.Second, time.Millisecond*10).Should(Succeed())
+			Expect(pvs).To(HaveLen(1))
+			Expect(pvcs).To(HaveLen(1))
+			Expect(vrg.Status.S3Transfer).To(BeNil())
+		})
+
+		It("resumes a download in the VRG status that is no longer running with its start time", func() {
+			startTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			vrg.Status.S3Transfer = &ramen.S3TransferStatus{
+				Operation: ramen.S3TransferOperationDownload, S3ProfileName: "west", StartTime: startTime,
+			}
+
+			v := vrgInstance()
+			defer v.clusterDataDownloadCancel()
+
+			_, _, err := v.clusterDataDownloaded(store, "west", &ctrl.Result{})
+			Expect(clusterDataDownloading(err)).To(BeTrue())
+			Expect(vrg.Status.S3Transfer.StartTime).To(Equal(startTime))
+		})
+	})
+})
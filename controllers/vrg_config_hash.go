@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConfigHashAnnotation is set, if requested, on the pod templates of the recovered deployments and stateful sets to a
+// hash of the contents of the config maps and secrets they reference. As it changes, the pods are restarted with the
+// recovered configuration, even if they started before it was recovered.
+const ConfigHashAnnotation = "ramendr.openshift.io/config-hash"
+
+// configHashesAnnotate annotates the pod templates of the recovered deployments and stateful sets with the hash of
+// their configuration, if requested
+func (v *VRGInstance) configHashesAnnotate() error {
+	if v.instance.Spec.KubeObjectProtection == nil || !v.instance.Spec.KubeObjectProtection.AnnotateConfigHashes {
+		return nil
+	}
+
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		deployments := &appsv1.DeploymentList{}
+		if err := v.reconciler.APIReader.List(v.ctx, deployments, listOptions); err != nil {
+			return fmt.Errorf("failed to list deployments in namespace %s (%w)", listOptions.Namespace, err)
+		}
+
+		for i := range deployments.Items {
+			deployment := &deployments.Items[i]
+			if err := v.podTemplateConfigHashAnnotate(deployment, &deployment.Spec.Template); err != nil {
+				return err
+			}
+		}
+
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := v.reconciler.APIReader.List(v.ctx, statefulSets, listOptions); err != nil {
+			return fmt.Errorf("failed to list stateful sets in namespace %s (%w)", listOptions.Namespace, err)
+		}
+
+		for i := range statefulSets.Items {
+			statefulSet := &statefulSets.Items[i]
+			if err := v.podTemplateConfigHashAnnotate(statefulSet, &statefulSet.Spec.Template); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// podTemplateConfigHashAnnotate annotates the pod template, of the object, with the hash of its configuration, unless
+// already annotated with it
+func (v *VRGInstance) podTemplateConfigHashAnnotate(object client.Object, template *corev1.PodTemplateSpec) error {
+	configHash, err := v.podSpecConfigHash(object.GetNamespace(), &template.Spec)
+	if err != nil {
+		return err
+	}
+
+	if template.GetAnnotations()[ConfigHashAnnotation] == configHash {
+		return nil
+	}
+
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+
+	template.Annotations[ConfigHashAnnotation] = configHash
+
+	if err := v.reconciler.Patch(v.ctx, object, patch); err != nil {
+		return fmt.Errorf("failed to annotate %s/%s with its config hash (%w)", object.GetNamespace(),
+			object.GetName(), err)
+	}
+
+	v.log.Info("Recovered workload annotated with its config hash", "name", object.GetName(),
+		"namespace", object.GetNamespace(), "kind", fmt.Sprintf("%T", object), "hash", configHash)
+
+	return nil
+}
+
+// podSpecConfigHash returns a hash of the contents of the config maps and secrets the pod spec references. Those
+// that are not found, such as optional ones, are hashed by name.
+func (v *VRGInstance) podSpecConfigHash(namespace string, spec *corev1.PodSpec) (string, error) {
+	configMapNames, secretNames := podSpecConfigReferences(spec)
+	configHash := sha256.New()
+
+	for _, name := range configMapNames {
+		configMap := &corev1.ConfigMap{}
+		if err := v.reconciler.APIReader.Get(v.ctx, types.NamespacedName{Namespace: namespace, Name: name},
+			configMap); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return "", fmt.Errorf("failed to get config map %s/%s (%w)", namespace, name, err)
+			}
+
+			fmt.Fprintf(configHash, "configmap %s absent\n", name)
+
+			continue
+		}
+
+		fmt.Fprintf(configHash, "configmap %s\n", name)
+		configHashWrite(configHash, configMap.Data, configMap.BinaryData)
+	}
+
+	for _, name := range secretNames {
+		secret := &corev1.Secret{}
+		if err := v.reconciler.APIReader.Get(v.ctx, types.NamespacedName{Namespace: namespace, Name: name},
+			secret); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return "", fmt.Errorf("failed to get secret %s/%s (%w)", namespace, name, err)
+			}
+
+			fmt.Fprintf(configHash, "secret %s absent\n", name)
+
+			continue
+		}
+
+		fmt.Fprintf(configHash, "secret %s\n", name)
+		configHashWrite(configHash, nil, secret.Data)
+	}
+
+	return hex.EncodeToString(configHash.Sum(nil)), nil
+}
+
+// configHashWrite writes the data to the hash in the order of its keys
+func configHashWrite(configHash hash.Hash, data map[string]string, binaryData map[string][]byte) {
+	keys := make([]string, 0, len(data)+len(binaryData))
+
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	for key := range binaryData {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := binaryData[key]
+		if !ok {
+			value = []byte(data[key])
+		}
+
+		fmt.Fprintf(configHash, "%s %d\n", key, len(value))
+		configHash.Write(value)
+	}
+}
+
+// podSpecConfigReferences returns the sorted names of the config maps and secrets the pod spec references in its
+// volumes and in the environment of its containers
+func podSpecConfigReferences(spec *corev1.PodSpec) ([]string, []string) {
+	configMaps, secrets := map[string]struct{}{}, map[string]struct{}{}
+
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps[volume.ConfigMap.Name] = struct{}{}
+		}
+
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = struct{}{}
+		}
+
+		if volume.Projected == nil {
+			continue
+		}
+
+		for _, source := range volume.Projected.Sources {
+			if source.ConfigMap != nil {
+				configMaps[source.ConfigMap.Name] = struct{}{}
+			}
+
+			if source.Secret != nil {
+				secrets[source.Secret.Name] = struct{}{}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+
+	for i := range containers {
+		for _, envFrom := range containers[i].EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps[envFrom.ConfigMapRef.Name] = struct{}{}
+			}
+
+			if envFrom.SecretRef != nil {
+				secrets[envFrom.SecretRef.Name] = struct{}{}
+			}
+		}
+
+		for _, env := range containers[i].Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[env.ValueFrom.ConfigMapKeyRef.Name] = struct{}{}
+			}
+
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[env.ValueFrom.SecretKeyRef.Name] = struct{}{}
+			}
+		}
+	}
+
+	return sortedKeys(configMaps), sortedKeys(secrets)
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the config hashes of the pod templates of recovered workloads
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_ConfigHash", func() {
+	Describe("podSpecConfigReferences", func() {
+		It("lists the config maps and secrets of the volumes and environments, sorted and once each", func() {
+			configMaps, secrets := podSpecConfigReferences(&corev1.PodSpec{
+				Volumes: []corev1.Volume{
+					{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+					}}},
+					{VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{{Secret: &corev1.SecretProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "tls"},
+						}}},
+					}}},
+				},
+				InitContainers: []corev1.Container{{EnvFrom: []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "env"},
+					},
+				}}}},
+				Containers: []corev1.Container{{Env: []corev1.EnvVar{
+					{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+					}}},
+					{Name: "MODE", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+					}}},
+				}}},
+			})
+			Expect(configMaps).To(Equal([]string{"env", "settings"}))
+			Expect(secrets).To(Equal([]string{"credentials", "tls"}))
+		})
+	})
+
+	Describe("configHashesAnnotate", func() {
+		var vrgInstance *VRGInstance
+
+		deployment := func() *appsv1.Deployment {
+			deployment := &appsv1.Deployment{}
+			Expect(vrgInstance.reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "web"},
+				deployment)).To(Succeed())
+
+			return deployment
+		}
+		configHash := func() string {
+			return deployment().Spec.Template.Annotations[ConfigHashAnnotation]
+		}
+
+		BeforeEach(func() {
+			c := fake.NewClientBuilder().WithObjects(
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "app", Name: "web", Labels: map[string]string{veleroRestoreNameLabel: "restore"},
+					},
+					Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{{VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "settings"},
+							},
+						}}},
+					}}},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "settings"},
+					Data:       map[string]string{"mode": "primary"},
+				},
+			).Build()
+			vrgInstance = vrgInstanceFake(c, "vrg-config-hash-test", ramen.VolumeReplicationGroupSpec{
+				KubeObjectProtection: &ramen.KubeObjectProtectionSpec{AnnotateConfigHashes: true},
+			})
+		})
+
+		It("annotates the recovered workloads with a hash that changes with the contents of their config", func() {
+			Expect(vrgInstance.configHashesAnnotate()).To(Succeed())
+			hash := configHash()
+			Expect(hash).ToNot(BeEmpty())
+
+			resourceVersion := deployment().ResourceVersion
+			Expect(vrgInstance.configHashesAnnotate()).To(Succeed())
+			Expect(deployment().ResourceVersion).To(Equal(resourceVersion))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(vrgInstance.reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "settings"},
+				configMap)).To(Succeed())
+			configMap.Data["mode"] = "secondary"
+			Expect(vrgInstance.reconciler.Update(context.TODO(), configMap)).To(Succeed())
+
+			Expect(vrgInstance.configHashesAnnotate()).To(Succeed())
+			Expect(configHash()).ToNot(Equal(hash))
+		})
+
+		It("does not annotate unless requested", func() {
+			vrgInstance.instance.Spec.KubeObjectProtection.AnnotateConfigHashes = false
+			Expect(vrgInstance.configHashesAnnotate()).To(Succeed())
+			Expect(configHash()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// clusterDataDriftCheckInterval is the minimum time between two checks of the PV cluster data in the S3 store
+// against the PVs on a secondary cluster
+const clusterDataDriftCheckInterval = 10 * time.Minute
+
+// clusterDataDriftCache is the cluster data last downloaded from an S3 store for a drift check, and the version of
+// the objects it was downloaded from
+type clusterDataDriftCache struct {
+	version string
+	pvs     []corev1.PersistentVolume
+	pvcs    []corev1.PersistentVolumeClaim
+}
+
+// clusterDataDriftCheck compares the PV and PVC cluster data in the S3 store against the PVs on this, secondary,
+// cluster and reports any drift that would cause a restore to fail, or lose data, in the NoClusterDataDrift
+// condition. The result is kept in the status, so a restart neither repeats a check within the interval nor forgets
+// the PVs present before. Detected drift does not fail the reconcile.
+func (v *VRGInstance) clusterDataDriftCheck(result *ctrl.Result) {
+	if len(v.volRepPVCs) == 0 && len(v.instance.Status.ProtectedPVCs) == 0 {
+		return
+	}
+
+	interval := clusterDataDriftCheckIntervalFor(v.ramenConfig)
+	lastCheck := v.instance.Status.ClusterDataDrift
+
+	if lastCheck != nil {
+		if elapsed := time.Since(lastCheck.LastCheckTime.Time); elapsed < interval {
+			// Conditions may have been reset by the secondary reconcile, retain the last result
+			v.clusterDataDriftConditionSet(lastCheck.Drifts)
+			delaySetIfLess(result, interval-elapsed, v.log)
+
+			return
+		}
+	}
+
+	pvsPresent := map[string]struct{}{}
+	if lastCheck != nil {
+		for _, pvName := range lastCheck.PresentPVs {
+			pvsPresent[pvName] = struct{}{}
+		}
+	}
+
+	drifts, pvsPresent, err := v.clusterDataDriftDetect(pvsPresent)
+	if err != nil {
+		v.log.Info("Cluster data drift check failed", "error", err)
+		delaySetIfLess(result, interval, v.log)
+
+		return
+	}
+
+	v.clusterDataDriftConditionSet(drifts)
+
+	v.instance.Status.ClusterDataDrift = &ramen.ClusterDataDriftStatus{
+		LastCheckTime: metav1.Now(),
+		Drifts:        drifts,
+		PresentPVs:    sortedKeys(pvsPresent),
+	}
+
+	delaySetIfLess(result, interval, v.log)
+}
+
+func (v *VRGInstance) clusterDataDriftConditionSet(drifts []string) {
+	msg := "PV cluster data is consistent with the cluster"
+	if len(drifts) != 0 {
+		msg = "PV cluster data drift detected: " + strings.Join(drifts, "; ")
+
+		v.log.Info(msg)
+	}
+
+	setVRGNoClusterDataDriftCondition(&v.instance.Status.Conditions, v.instance.Generation, len(drifts) != 0, msg)
+}
+
+// clusterDataDriftCheckForget removes the result of the last check, which no longer applies once the VRG is primary
+// or deleted
+func (v *VRGInstance) clusterDataDriftCheckForget() {
+	meta.RemoveStatusCondition(&v.instance.Status.Conditions, VRGConditionTypeNoClusterDataDrift)
+	v.instance.Status.ClusterDataDrift = nil
+	v.reconciler.clusterDataDriftChecks.Delete(v.namespacedName)
+}
+
+// clusterDataDriftDetect returns a description of each drift detected, using the first accessible S3 store, and the
+// names of the PVs of the cluster data that exist, or existed, on the cluster
+func (v *VRGInstance) clusterDataDriftDetect(pvsPresent map[string]struct{}) ([]string, map[string]struct{}, error) {
+	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		objectStore, _, err := v.reconciler.ObjStoreGetter.ObjectStore(
+			v.ctx, v.reconciler.APIReader, s3ProfileName, v.namespacedName, v.log)
+		if err != nil {
+			v.log.Info("Object store inaccessible for cluster data drift check", "profile", s3ProfileName,
+				"error", err)
+
+			continue
+		}
+
+		cache, err := v.clusterDataDriftCacheGet(objectStore, s3ProfileName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return v.clusterDataDriftDetectInClusterData(cache.pvs, cache.pvcs, pvsPresent)
+	}
+
+	return nil, nil, fmt.Errorf("no accessible S3 store in profiles %v", v.instance.Spec.S3Profiles)
+}
+
+// clusterDataDriftCacheGet returns the PV and PVC cluster data in the object store. It lists their objects, and
+// downloads them only if they changed since the last download, as they change only when the protection on the primary
+// cluster uploads them.
+func (v *VRGInstance) clusterDataDriftCacheGet(objectStore ObjectStorer, s3ProfileName string,
+) (*clusterDataDriftCache, error) {
+	version := s3ProfileName
+
+	for _, objectType := range []reflect.Type{
+		reflect.TypeOf(corev1.PersistentVolume{}), reflect.TypeOf(corev1.PersistentVolumeClaim{}),
+	} {
+		objects, err := objectStore.ListObjects(typedKey(v.s3KeyPrefix(), "", objectType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %v cluster data: %w", objectType, err)
+		}
+
+		for _, object := range objects {
+			version += fmt.Sprintf("\n%s %d %s", object.Key, object.Size,
+				object.LastModified.UTC().Format(time.RFC3339Nano))
+		}
+	}
+
+	if value, ok := v.reconciler.clusterDataDriftChecks.Load(v.namespacedName); ok {
+		if cache, _ := value.(*clusterDataDriftCache); cache != nil && cache.version == version {
+			return cache, nil
+		}
+	}
+
+	pvList, err := downloadPVs(objectStore, v.s3KeyPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download PV cluster data: %w", err)
+	}
+
+	pvcList, err := downloadPVCs(objectStore, v.s3KeyPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download PVC cluster data: %w", err)
+	}
+
+	cache := &clusterDataDriftCache{version: version, pvs: pvList, pvcs: pvcList}
+	v.reconciler.clusterDataDriftChecks.Store(v.namespacedName, cache)
+
+	return cache, nil
+}
+
+// clusterDataDriftDetectInClusterData returns the drifts of the cluster data, and the names of its PVs that exist, or
+// existed, on the cluster. A PV that existed on the cluster at a previous check, and no longer does, was deleted,
+// possibly with its volume. It is reported until the PV is recreated, or its cluster data removed.
+func (v *VRGInstance) clusterDataDriftDetectInClusterData(pvList []corev1.PersistentVolume,
+	pvcList []corev1.PersistentVolumeClaim, pvsPresent map[string]struct{},
+) ([]string, map[string]struct{}, error) {
+	drifts := []string{}
+	pvNames := make(map[string]struct{}, len(pvList))
+	pvsPresentNow := map[string]struct{}{}
+
+	for idx := range pvList {
+		pv := &pvList[idx]
+		pvNames[pv.GetName()] = struct{}{}
+
+		drift, present, err := v.pvDrift(pv)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, presentBefore := pvsPresent[pv.GetName()]
+
+		if !present && presentBefore {
+			drift = fmt.Sprintf("PV %s was deleted from the cluster", pv.GetName())
+		}
+
+		if present || presentBefore {
+			pvsPresentNow[pv.GetName()] = struct{}{}
+		}
+
+		if drift != "" {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	for idx := range pvcList {
+		pvc := &pvcList[idx]
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		if _, ok := pvNames[pvc.Spec.VolumeName]; !ok {
+			drifts = append(drifts, fmt.Sprintf("PVC %s/%s is missing PV %s in the S3 store",
+				pvc.GetNamespace(), pvc.GetName(), pvc.Spec.VolumeName))
+		}
+	}
+
+	return drifts, pvsPresentNow, nil
+}
+
+// pvDrift compares a PV from the S3 store against the same PV on the cluster, and returns whether one exists. A PV
+// that does not exist on the cluster is not a drift by itself, as it is created on restore.
+func (v *VRGInstance) pvDrift(pv *corev1.PersistentVolume) (string, bool, error) {
+	existingPV := &corev1.PersistentVolume{}
+	if err := v.reconciler.Get(v.ctx, types.NamespacedName{Name: pv.GetName()}, existingPV); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("failed to get PV %s: %w", pv.GetName(), err)
+	}
+
+	switch {
+	case rmnutil.ResourceIsDeleted(existingPV):
+		return fmt.Sprintf("PV %s is being deleted", pv.GetName()), true, nil
+	case existingPV.Spec.PersistentVolumeReclaimPolicy != pv.Spec.PersistentVolumeReclaimPolicy:
+		return fmt.Sprintf("PV %s reclaim policy changed from %s to %s", pv.GetName(),
+			pv.Spec.PersistentVolumeReclaimPolicy, existingPV.Spec.PersistentVolumeReclaimPolicy), true, nil
+	case existingPV.Status.Phase == corev1.VolumeBound && !v.pvMatches(existingPV, pv):
+		return fmt.Sprintf("PV %s is bound and does not match its cluster data", pv.GetName()), true, nil
+	}
+
+	return "", true, nil
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the check of the PV cluster data in the S3 store against the PVs of the cluster
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_Drift", func() {
+	var (
+		c           client.Client
+		store       memoryObjectStorer
+		vrgInstance *VRGInstance
+	)
+
+	pv := func(name string, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: reclaimPolicy},
+		}
+	}
+	pvUpload := func(name string, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) {
+		Expect(UploadPV(store, vrgInstance.s3KeyPrefix(), name, *pv(name, reclaimPolicy))).To(Succeed())
+	}
+	pvcUpload := func(name, volumeName string) {
+		Expect(UploadPVC(store, vrgInstance.s3KeyPrefix(), name, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+		})).To(Succeed())
+	}
+	vrgInstanceNew := func(status ramen.VolumeReplicationGroupStatus) *VRGInstance {
+		vrgInstance := vrgInstanceFake(c, "vrg-drift-test", ramen.VolumeReplicationGroupSpec{
+			S3Profiles: []string{NoS3StoreAvailable, "west", "east"},
+		})
+		vrgInstance.reconciler.ObjStoreGetter = memoryObjectStoreGetter{"east": store}
+		vrgInstance.namespacedName = "app/vrg"
+		vrgInstance.volRepPVCs = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "data"}}}
+		vrgInstance.instance.Status = status
+
+		return vrgInstance
+	}
+	condition := func() *metav1.Condition {
+		return meta.FindStatusCondition(vrgInstance.instance.Status.Conditions, VRGConditionTypeNoClusterDataDrift)
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithObjects(
+			pv("pv-data", corev1.PersistentVolumeReclaimDelete),
+			pv("pv-logs", corev1.PersistentVolumeReclaimRetain),
+		).Build()
+		store = memoryObjectStorer{}
+		vrgInstance = vrgInstanceNew(ramen.VolumeReplicationGroupStatus{})
+
+		pvUpload("pv-data", corev1.PersistentVolumeReclaimRetain)
+		pvUpload("pv-logs", corev1.PersistentVolumeReclaimRetain)
+		pvUpload("pv-cache", corev1.PersistentVolumeReclaimRetain)
+		pvcUpload("data", "pv-data")
+		pvcUpload("orphan", "pv-missing")
+		pvcUpload("pending", "")
+	})
+
+	Describe("clusterDataDriftDetect", func() {
+		It("reports a changed reclaim policy and a PVC missing its PV, and the PVs present", func() {
+			drifts, pvsPresent, err := vrgInstance.clusterDataDriftDetect(map[string]struct{}{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifts).To(ConsistOf(
+				"PV pv-data reclaim policy changed from Retain to Delete",
+				"PVC app/orphan is missing PV pv-missing in the S3 store",
+			))
+			Expect(sortedKeys(pvsPresent)).To(Equal([]string{"pv-data", "pv-logs"}))
+		})
+
+		It("reports a PV present before that was deleted, until it is recreated", func() {
+			Expect(c.Delete(context.TODO(), pv("pv-logs", ""))).To(Succeed())
+
+			drifts, pvsPresent, err := vrgInstance.clusterDataDriftDetect(map[string]struct{}{"pv-logs": {}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drifts).To(ContainElement("PV pv-logs was deleted from the cluster"))
+			Expect(pvsPresent).To(HaveKey("pv-logs"))
+		})
+
+		It("downloads the cluster data again only once it changes in the S3 store", func() {
+			cacheGet := func() *clusterDataDriftCache {
+				value, ok := vrgInstance.reconciler.clusterDataDriftChecks.Load(vrgInstance.namespacedName)
+				Expect(ok).To(BeTrue())
+
+				return value.(*clusterDataDriftCache)
+			}
+
+			_, _, err := vrgInstance.clusterDataDriftDetect(nil)
+			Expect(err).ToNot(HaveOccurred())
+			cache := cacheGet()
+
+			_, _, err = vrgInstance.clusterDataDriftDetect(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cacheGet()).To(BeIdenticalTo(cache))
+
+			pvUpload("pv-data", corev1.PersistentVolumeReclaimRecycle)
+			drifts, _, err := vrgInstance.clusterDataDriftDetect(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cacheGet()).ToNot(BeIdenticalTo(cache))
+			Expect(drifts).To(ContainElement("PV pv-data reclaim policy changed from Recycle to Delete"))
+		})
+
+		It("fails without an accessible S3 store", func() {
+			vrgInstance.reconciler.ObjStoreGetter = memoryObjectStoreGetter{}
+
+			_, _, err := vrgInstance.clusterDataDriftDetect(nil)
+			Expect(err).To(MatchError(ContainSubstring("no accessible S3 store")))
+		})
+	})
+
+	Describe("clusterDataDriftCheck", func() {
+		It("reports the drift in the condition, and keeps the result in the status", func() {
+			result := ctrl.Result{}
+			vrgInstance.clusterDataDriftCheck(&result)
+
+			Expect(condition().Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition().Message).To(ContainSubstring("reclaim policy changed"))
+			Expect(vrgInstance.instance.Status.ClusterDataDrift.PresentPVs).To(Equal([]string{"pv-data", "pv-logs"}))
+			Expect(vrgInstance.instance.Status.ClusterDataDrift.Drifts).To(HaveLen(2))
+			Expect(result.RequeueAfter).To(Equal(clusterDataDriftCheckInterval))
+		})
+
+		It("restores the condition of a check within the interval from the status, without checking", func() {
+			lastCheck := &ramen.ClusterDataDriftStatus{
+				LastCheckTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+			}
+			vrgInstance.instance.Status.ClusterDataDrift = lastCheck
+			result := ctrl.Result{}
+
+			vrgInstance.clusterDataDriftCheck(&result)
+
+			Expect(condition().Status).To(Equal(metav1.ConditionTrue))
+			Expect(vrgInstance.instance.Status.ClusterDataDrift).To(BeIdenticalTo(lastCheck))
+			Expect(result.RequeueAfter).To(BeNumerically("~", clusterDataDriftCheckInterval-time.Minute, time.Second))
+		})
+
+		It("detects the deletion of a PV present at a check before a restart", func() {
+			vrgInstance = vrgInstanceNew(ramen.VolumeReplicationGroupStatus{
+				ClusterDataDrift: &ramen.ClusterDataDriftStatus{
+					LastCheckTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+					PresentPVs:    []string{"pv-data", "pv-logs"},
+				},
+			})
+			Expect(c.Delete(context.TODO(), pv("pv-logs", ""))).To(Succeed())
+
+			vrgInstance.clusterDataDriftCheck(&ctrl.Result{})
+
+			Expect(condition().Message).To(ContainSubstring("PV pv-logs was deleted from the cluster"))
+			Expect(vrgInstance.instance.Status.ClusterDataDrift.PresentPVs).To(Equal([]string{"pv-data", "pv-logs"}))
+		})
+
+		It("forgets the result once primary", func() {
+			vrgInstance.clusterDataDriftCheck(&ctrl.Result{})
+			vrgInstance.clusterDataDriftCheckForget()
+
+			Expect(condition()).To(BeNil())
+			Expect(vrgInstance.instance.Status.ClusterDataDrift).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,603 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	velero "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	velerolabel "github.com/vmware-tanzu/velero/pkg/label"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// failoverPreparationStagedResources are the kinds staged on a cluster prepared for failover. They start nothing, so
+// are staged ahead of the failover without scaling up the workload.
+var failoverPreparationStagedResources = []string{"secrets", "configmaps", "serviceaccounts"}
+
+const (
+	// failoverPreparationImagePullLabel labels the daemon sets, and their pods, that pull the images of a workload on
+	// a cluster prepared for failover
+	failoverPreparationImagePullLabel = "ramendr.openshift.io/image-pull"
+
+	// failoverPreparationImagePullOSLabel labels the image pull daemon sets, and their pods, with the operating
+	// system of the nodes they pull the images on
+	failoverPreparationImagePullOSLabel = "ramendr.openshift.io/image-pull-os"
+)
+
+// imagePullCommands are the commands the image pull containers run, by operating system, to keep the images in use
+// once pulled, so that they are not garbage collected
+var imagePullCommands = map[corev1.OSName][]string{
+	corev1.Linux:   {"sleep", "infinity"},
+	corev1.Windows: {"cmd", "/c", "ping -t localhost > NUL"},
+}
+
+// updateWorkloadImages records the container images of the workload of a primary VRG in its status, those of its
+// Windows pods apart, for the hub to prepare a failover cluster
+func (v *VRGInstance) updateWorkloadImages() {
+	if v.instance.Spec.ReplicationState != ramen.Primary {
+		return
+	}
+
+	images, err := v.workloadImages()
+	if err != nil {
+		v.log.Info("Workload images get failed", "error", err)
+
+		return
+	}
+
+	v.instance.Status.WorkloadImages = sortedKeys(images[corev1.Linux])
+	v.instance.Status.WorkloadWindowsImages = sortedKeys(images[corev1.Windows])
+}
+
+// podOS returns the operating system of the nodes a pod runs on, as set by its OS field, or else its node selector
+func podOS(pod *corev1.Pod) corev1.OSName {
+	if pod.Spec.OS != nil {
+		return pod.Spec.OS.Name
+	}
+
+	if pod.Spec.NodeSelector[corev1.LabelOSStable] == string(corev1.Windows) {
+		return corev1.Windows
+	}
+
+	return corev1.Linux
+}
+
+// workloadImages returns the container images of the running pods of the protected namespaces, by operating system
+func (v *VRGInstance) workloadImages() (map[corev1.OSName]map[string]struct{}, error) {
+	images := map[corev1.OSName]map[string]struct{}{corev1.Linux: {}, corev1.Windows: {}}
+
+	for _, namespace := range v.workloadNamespaces() {
+		pods := &corev1.PodList{}
+		if err := v.reconciler.List(v.ctx, pods, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s (%w)", namespace, err)
+		}
+
+		for idx := range pods.Items {
+			pod := &pods.Items[idx]
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed ||
+				pod.GetLabels()[failoverPreparationImagePullLabel] != "" {
+				continue
+			}
+
+			osImages := images[podOS(pod)]
+			if osImages == nil {
+				continue
+			}
+
+			for cidx := range pod.Spec.InitContainers {
+				osImages[pod.Spec.InitContainers[cidx].Image] = struct{}{}
+			}
+
+			for cidx := range pod.Spec.Containers {
+				osImages[pod.Spec.Containers[cidx].Image] = struct{}{}
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// failoverPrepare prepares the cluster of a secondary VRG to take over its workload, as requested by the hub: it
+// stages the Secrets, ConfigMaps and ServiceAccounts of the latest kube objects capture, and pulls the images of the
+// workload on the nodes. A preparation no longer requested is undone.
+func (v *VRGInstance) failoverPrepare(result *ctrl.Result) {
+	spec := v.instance.Spec.PrepareForFailover
+	if spec == nil {
+		if err := v.failoverPreparationStop(true); err != nil {
+			v.log.Info("Failover preparation stop failed", "error", err)
+
+			result.Requeue = true
+		}
+
+		return
+	}
+
+	status := v.instance.Status.FailoverPreparation
+	if status == nil {
+		status = &ramen.VRGFailoverPreparationStatus{}
+		v.instance.Status.FailoverPreparation = status
+	}
+
+	status.Message = ""
+
+	if err := v.failoverPreparationStage(status); err != nil {
+		status.Message = err.Error()
+
+		if !errors.Is(err, kubeobjects.RequestProcessingError{}) {
+			v.log.Info("Failover preparation staging failed", "error", err)
+
+			result.Requeue = true
+		}
+	}
+
+	imagesPulled, err := v.failoverPreparationImagesPull(corev1.Linux, spec.Images)
+	if err != nil {
+		v.log.Info("Failover preparation image pull failed", "error", err)
+
+		status.Message = err.Error()
+		result.Requeue = true
+
+		return
+	}
+
+	windowsImagesPulled, err := v.failoverPreparationImagesPull(corev1.Windows, spec.WindowsImages)
+	if err != nil {
+		v.log.Info("Failover preparation Windows image pull failed", "error", err)
+
+		status.Message = err.Error()
+		result.Requeue = true
+
+		return
+	}
+
+	status.ImagesPulled = append(imagesPulled, windowsImagesPulled...)
+
+	if images := len(spec.Images) + len(spec.WindowsImages); len(status.ImagesPulled) != images {
+		if status.Message == "" {
+			status.Message = fmt.Sprintf("%d of %d images pulled", len(status.ImagesPulled), images)
+		}
+
+		result.Requeue = true
+	}
+}
+
+// failoverPreparationStage stages the Secrets, ConfigMaps and ServiceAccounts of the capture to recover from, unless
+// staged already, recovering them with the groups of the recover workflow, with the existing objects updated
+func (v *VRGInstance) failoverPreparationStage(status *ramen.VRGFailoverPreparationStatus) error {
+	if v.kubeObjectProtectionDisabled("failover preparation") {
+		return nil
+	}
+
+	vrg := v.instance
+
+	for _, accessor := range v.s3StoreAccessors {
+		sourceVrg := &ramen.VolumeReplicationGroup{}
+		pathName := s3PathNamePrefix(vrg.Namespace, vrg.Name)
+
+		if err := vrgObjectDownload(accessor.ObjectStorer, pathName, sourceVrg); err != nil {
+			v.log.Info("Failover preparation VRG download failed", "profile", accessor.S3ProfileName, "error", err)
+
+			continue
+		}
+
+		captureToRecoverFromIdentifier := sourceVrg.Status.KubeObjectProtection.CaptureToRecoverFrom
+		if captureToRecoverFromIdentifier == nil {
+			return errors.New("no kube objects capture to stage")
+		}
+
+		if status.StagedCapture != nil && *status.StagedCapture == captureToRecoverFromIdentifier.Number {
+			return nil
+		}
+
+		if err := v.failoverPreparationStageCapture(accessor, captureToRecoverFromIdentifier); err != nil {
+			return err
+		}
+
+		number := captureToRecoverFromIdentifier.Number
+		status.StagedCapture = &number
+
+		v.log.Info("Failover preparation kube objects staged", "number", number)
+
+		return nil
+	}
+
+	return fmt.Errorf("no accessible S3 store in profiles %v", vrg.Spec.S3Profiles)
+}
+
+func (v *VRGInstance) failoverPreparationStageCapture(accessor s3StoreAccessor,
+	captureToRecoverFromIdentifier *ramen.KubeObjectsCaptureIdentifier,
+) error {
+	vrg := v.instance
+	veleroNamespaceName := v.veleroNamespaceName()
+	labels := util.OwnerLabels(vrg)
+
+	captureRequests, err := v.reconciler.kubeObjects.ProtectRequestsGet(
+		v.ctx, v.reconciler.APIReader, veleroNamespaceName, labels)
+	if err != nil {
+		return fmt.Errorf("failed to query kube objects capture requests (%w)", err)
+	}
+
+	recoverRequestsStruct, err := v.reconciler.kubeObjects.RecoverRequestsGet(
+		v.ctx, v.reconciler.APIReader, veleroNamespaceName, labels)
+	if err != nil {
+		return fmt.Errorf("failed to query kube objects recover requests (%w)", err)
+	}
+
+	recoverRequests := kubeobjects.RequestsMapKeyedByName(recoverRequestsStruct)
+	requests := []kubeobjects.Request{}
+
+	for groupNumber, recoverGroup := range v.recipeElements.RecoverWorkflow {
+		recoverSpec, ok := failoverPreparationRecoverSpec(recoverGroup)
+		if !ok {
+			continue
+		}
+
+		recoverName := failoverPreparationRecoverName(vrg, groupNumber)
+
+		request, ok := recoverRequests[recoverName]
+		if !ok {
+			pathName, captureName := v.kubeObjectsRecoverCapturePathNameAndName(
+				vrg.Namespace, vrg.Name, captureToRecoverFromIdentifier, recoverGroup.BackupName, accessor.S3ProfileName)
+
+			if _, err := v.reconciler.kubeObjects.RecoverRequestCreate(
+				v.ctx, v.reconciler.Client, v.log,
+				accessor.S3CompatibleEndpoint, accessor.S3Bucket, accessor.S3Region, pathName,
+				accessor.VeleroNamespaceSecretKeyRef,
+				accessor.CACertificates,
+				recoverSpec, veleroNamespaceName,
+				captureName, kubeobjects.RequestsMapKeyedByName(captureRequests)[captureName],
+				recoverName,
+				labels, map[string]string{},
+			); err != nil {
+				return fmt.Errorf("failed to stage kube objects group %d (%w)", groupNumber, err)
+			}
+
+			return kubeobjects.RequestProcessingErrorCreate(
+				fmt.Sprintf("staging kube objects group %d of capture %d", groupNumber,
+					captureToRecoverFromIdentifier.Number))
+		}
+
+		if err := request.Status(v.log); err != nil {
+			if !errors.Is(err, kubeobjects.RequestProcessingError{}) {
+				if err := request.Deallocate(v.ctx, v.reconciler.Client, v.log); err != nil {
+					v.log.Error(err, "Failover preparation recover request deallocate error")
+				}
+			}
+
+			return fmt.Errorf("staging kube objects group %d of capture %d: %w", groupNumber,
+				captureToRecoverFromIdentifier.Number, err)
+		}
+
+		requests = append(requests, request)
+	}
+
+	for _, request := range requests {
+		if err := request.Deallocate(v.ctx, v.reconciler.Client, v.log); err != nil {
+			return fmt.Errorf("failed to deallocate kube objects recover request %s (%w)", request.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// failoverPreparationRecoverSpec returns the spec of a recover group restricted to the staged kinds, unless it runs
+// a hook, recovers from a capture taken at recovery, or recovers none of the staged kinds
+func failoverPreparationRecoverSpec(recoverGroup kubeobjects.RecoverSpec) (kubeobjects.RecoverSpec, bool) {
+	if recoverGroup.BackupName == ramen.ReservedBackupName || kubeObjectsHookRunnable(recoverGroup.Spec) != nil {
+		return kubeobjects.RecoverSpec{}, false
+	}
+
+	included := []string{}
+
+	for _, resource := range failoverPreparationStagedResources {
+		if (len(recoverGroup.IncludedResources) == 0 || containsString(recoverGroup.IncludedResources, resource) ||
+			containsString(recoverGroup.IncludedResources, "*")) &&
+			!containsString(recoverGroup.ExcludedResources, resource) {
+			included = append(included, resource)
+		}
+	}
+
+	if len(included) == 0 {
+		return kubeobjects.RecoverSpec{}, false
+	}
+
+	recoverSpec := recoverGroup
+	recoverSpec.IncludedResources = included
+	recoverSpec.Hooks = nil
+	recoverSpec.RestoreStatus = nil
+	recoverSpec.ExistingResourcePolicy = velero.PolicyTypeUpdate
+
+	return recoverSpec, true
+}
+
+func failoverPreparationRecoverName(vrg *ramen.VolumeReplicationGroup, groupNumber int) string {
+	return kubeObjectsRecoverName(kubeObjectsRecoverNamePrefix(vrg.Namespace, vrg.Name)+"--prepare", groupNumber)
+}
+
+// failoverPreparationImagesPull runs a pod on each node of the operating system pulling the images, and returns those
+// pulled on all of them
+func (v *VRGInstance) failoverPreparationImagesPull(osName corev1.OSName, images []string) ([]string, error) {
+	daemonSet := v.failoverPreparationImagePullDaemonSet(osName)
+
+	if len(images) == 0 {
+		return nil, v.failoverPreparationImagePullDelete(daemonSet)
+	}
+
+	pullSecrets, err := v.failoverPreparationImagePullSecrets(daemonSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(v.ctx, v.reconciler.Client, daemonSet, func() error {
+		v.failoverPreparationImagePullSpec(daemonSet, osName, images, pullSecrets)
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create or update image pull daemon set %s/%s (%w)",
+			daemonSet.Namespace, daemonSet.Name, err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := v.reconciler.List(v.ctx, pods, client.InNamespace(daemonSet.Namespace),
+		client.MatchingLabels(daemonSet.Spec.Selector.MatchLabels)); err != nil {
+		return nil, fmt.Errorf("failed to list image pull pods in namespace %s (%w)", daemonSet.Namespace, err)
+	}
+
+	if daemonSet.Status.DesiredNumberScheduled == 0 || len(pods.Items) < int(daemonSet.Status.DesiredNumberScheduled) {
+		return nil, nil
+	}
+
+	pulled := []string{}
+
+	for idx, image := range images {
+		if imagePulled(pods.Items, imagePullContainerName(idx)) {
+			pulled = append(pulled, image)
+		}
+	}
+
+	return pulled, nil
+}
+
+// imagePulled returns whether the image of a container is pulled on the nodes of the pods, as it is once the
+// container status reports its image ID, even if the container fails to run
+func imagePulled(pods []corev1.Pod, containerName string) bool {
+	for idx := range pods {
+		pulled := false
+
+		for _, containerStatus := range pods[idx].Status.ContainerStatuses {
+			if containerStatus.Name == containerName && containerStatus.ImageID != "" {
+				pulled = true
+			}
+		}
+
+		if !pulled {
+			return false
+		}
+	}
+
+	return true
+}
+
+func imagePullContainerName(index int) string {
+	return "image-" + strconv.Itoa(index)
+}
+
+func (v *VRGInstance) failoverPreparationImagePullDaemonSet(osName corev1.OSName) *appsv1.DaemonSet {
+	labels := util.OwnerLabels(v.instance)
+	labels[failoverPreparationImagePullLabel] = v.instance.Name
+	labels[failoverPreparationImagePullOSLabel] = string(osName)
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      v.instance.Name + "-image-pull-" + string(osName),
+			Namespace: v.workloadNamespaces()[0],
+			Labels:    labels,
+		},
+	}
+}
+
+// failoverPreparationImagePullPredicate filters the daemon sets watched to those pulling images for a VRG
+func failoverPreparationImagePullPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		_, ok := object.GetLabels()[failoverPreparationImagePullLabel]
+
+		return ok
+	})
+}
+
+// failoverPreparationImagePullSpec sets the spec of the image pull daemon set, a pod on each node of the operating
+// system, with a container per image that idles once pulled. Linux pods are scheduled like the VRG's helper pods.
+// Windows pods tolerate the taint Windows nodes commonly have, os=windows:NoSchedule.
+func (v *VRGInstance) failoverPreparationImagePullSpec(daemonSet *appsv1.DaemonSet, osName corev1.OSName,
+	images []string, pullSecrets []corev1.LocalObjectReference,
+) {
+	selector := map[string]string{
+		failoverPreparationImagePullLabel:   v.instance.Name,
+		failoverPreparationImagePullOSLabel: string(osName),
+	}
+
+	if daemonSet.Spec.Selector == nil {
+		daemonSet.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	}
+
+	containers := make([]corev1.Container, len(images))
+	for idx, image := range images {
+		containers[idx] = corev1.Container{
+			Name:            imagePullContainerName(idx),
+			Image:           image,
+			Command:         imagePullCommands[osName],
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1m"),
+					corev1.ResourceMemory: resource.MustParse("4Mi"),
+				},
+			},
+		}
+	}
+
+	daemonSet.Spec.Template = corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: selector},
+		Spec: corev1.PodSpec{
+			Containers:       containers,
+			ImagePullSecrets: pullSecrets,
+			OS:               &corev1.PodOS{Name: osName},
+			NodeSelector:     map[string]string{corev1.LabelOSStable: string(osName)},
+		},
+	}
+
+	if osName == corev1.Windows {
+		daemonSet.Spec.Template.Spec.Tolerations = []corev1.Toleration{{
+			Key:      "os",
+			Operator: corev1.TolerationOpEqual,
+			Value:    string(corev1.Windows),
+			Effect:   corev1.TaintEffectNoSchedule,
+		}}
+
+		return
+	}
+
+	if scheduling := v.instance.Spec.HelperPodScheduling; scheduling != nil {
+		for key, value := range scheduling.NodeSelector {
+			daemonSet.Spec.Template.Spec.NodeSelector[key] = value
+		}
+
+		daemonSet.Spec.Template.Spec.Tolerations = scheduling.Tolerations
+	}
+}
+
+// failoverPreparationImagePullSecrets returns the docker config Secrets of the namespace, staged with the workload's
+// other Secrets, to pull its images with
+func (v *VRGInstance) failoverPreparationImagePullSecrets(namespace string) ([]corev1.LocalObjectReference, error) {
+	secrets := &corev1.SecretList{}
+	if err := v.reconciler.List(v.ctx, secrets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list secrets in namespace %s (%w)", namespace, err)
+	}
+
+	pullSecrets := []corev1.LocalObjectReference{}
+
+	for idx := range secrets.Items {
+		secret := &secrets.Items[idx]
+		if secret.Type == corev1.SecretTypeDockerConfigJson || secret.Type == corev1.SecretTypeDockercfg {
+			pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret.Name})
+		}
+	}
+
+	sort.Slice(pullSecrets, func(i, j int) bool { return pullSecrets[i].Name < pullSecrets[j].Name })
+
+	return pullSecrets, nil
+}
+
+func (v *VRGInstance) failoverPreparationImagePullDelete(daemonSet *appsv1.DaemonSet) error {
+	if err := v.reconciler.Delete(v.ctx, daemonSet); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete image pull daemon set %s/%s (%w)", daemonSet.Namespace, daemonSet.Name,
+			err)
+	}
+
+	return nil
+}
+
+// failoverPreparationStop undoes the preparation of the cluster for failover, if any: it deletes the image pull pods
+// and, unless the workload was failed over to the cluster, the staged objects
+func (v *VRGInstance) failoverPreparationStop(unstage bool) error {
+	if v.instance.Status.FailoverPreparation == nil {
+		return nil
+	}
+
+	for _, osName := range []corev1.OSName{corev1.Linux, corev1.Windows} {
+		if err := v.failoverPreparationImagePullDelete(v.failoverPreparationImagePullDaemonSet(osName)); err != nil {
+			return err
+		}
+	}
+
+	if err := v.failoverPreparationRecoverRequestsDeallocate(); err != nil {
+		return err
+	}
+
+	if unstage {
+		if err := v.failoverPreparationUnstage(); err != nil {
+			return err
+		}
+	}
+
+	v.instance.Status.FailoverPreparation = nil
+
+	v.log.Info("Failover preparation stopped", "unstaged", unstage)
+
+	return nil
+}
+
+// failoverPreparationRecoverRequestsDeallocate deallocates the recover requests of a staging still in progress
+func (v *VRGInstance) failoverPreparationRecoverRequestsDeallocate() error {
+	if v.kubeObjectProtectionDisabled("failover preparation stop") {
+		return nil
+	}
+
+	recoverRequests, err := v.reconciler.kubeObjects.RecoverRequestsGet(
+		v.ctx, v.reconciler.APIReader, v.veleroNamespaceName(), util.OwnerLabels(v.instance))
+	if err != nil {
+		return fmt.Errorf("failed to query kube objects recover requests (%w)", err)
+	}
+
+	requests := kubeobjects.RequestsMapKeyedByName(recoverRequests)
+
+	for groupNumber := range v.recipeElements.RecoverWorkflow {
+		request, ok := requests[failoverPreparationRecoverName(v.instance, groupNumber)]
+		if !ok {
+			continue
+		}
+
+		if err := request.Deallocate(v.ctx, v.reconciler.Client, v.log); err != nil {
+			return fmt.Errorf("failed to deallocate kube objects recover request %s (%w)", request.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// failoverPreparationUnstage deletes the objects staged by the recover requests of the failover preparation
+func (v *VRGInstance) failoverPreparationUnstage() error {
+	restoreNames := make([]string, len(v.recipeElements.RecoverWorkflow))
+	for groupNumber := range v.recipeElements.RecoverWorkflow {
+		restoreNames[groupNumber] = velerolabel.GetValidName(failoverPreparationRecoverName(v.instance, groupNumber))
+	}
+
+	if len(restoreNames) == 0 {
+		return nil
+	}
+
+	staged, err := labels.NewRequirement(veleroRestoreNameLabel, selection.In, restoreNames)
+	if err != nil {
+		return err
+	}
+
+	selector := client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*staged)}
+
+	for _, namespace := range v.workloadNamespaces() {
+		for _, object := range []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}, &corev1.ServiceAccount{}} {
+			if err := v.reconciler.DeleteAllOf(v.ctx, object, client.InNamespace(namespace), selector); err != nil {
+				return fmt.Errorf("failed to delete staged %T in namespace %s (%w)", object, namespace, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the preparation of a cluster for failover
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	velero "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_FailoverPreparation", func() {
+	DescribeTable("podOS",
+		func(spec corev1.PodSpec, expected corev1.OSName) {
+			Expect(podOS(&corev1.Pod{Spec: spec})).To(Equal(expected))
+		},
+		Entry("unspecified", corev1.PodSpec{}, corev1.Linux),
+		Entry("by the OS field", corev1.PodSpec{OS: &corev1.PodOS{Name: corev1.Windows}}, corev1.Windows),
+		Entry("by the node selector", corev1.PodSpec{
+			NodeSelector: map[string]string{corev1.LabelOSStable: string(corev1.Windows)},
+		}, corev1.Windows),
+		Entry("by the OS field over the node selector", corev1.PodSpec{
+			OS:           &corev1.PodOS{Name: corev1.Linux},
+			NodeSelector: map[string]string{corev1.LabelOSStable: string(corev1.Windows)},
+		}, corev1.Linux),
+	)
+
+	Describe("updateWorkloadImages", func() {
+		pod := func(name string, phase corev1.PodPhase, labels map[string]string, osName corev1.OSName,
+			images ...string,
+		) *corev1.Pod {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: labels},
+				Spec: corev1.PodSpec{
+					OS:             &corev1.PodOS{Name: osName},
+					InitContainers: []corev1.Container{{Name: "init", Image: images[0]}},
+				},
+				Status: corev1.PodStatus{Phase: phase},
+			}
+			for _, image := range images[1:] {
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: image, Image: image})
+			}
+
+			return pod
+		}
+
+		It("records the images of the running pods of a primary VRG, by operating system", func() {
+			c := fake.NewClientBuilder().WithObjects(
+				pod("web", corev1.PodRunning, nil, corev1.Linux, "init:1", "web:1", "sidecar:1"),
+				pod("web-2", corev1.PodPending, nil, corev1.Linux, "init:1", "web:1"),
+				pod("iis", corev1.PodRunning, nil, corev1.Windows, "init-win:1", "iis:1"),
+				pod("job", corev1.PodSucceeded, nil, corev1.Linux, "init:1", "job:1"),
+				pod("pull", corev1.PodRunning, map[string]string{failoverPreparationImagePullLabel: "vrg"},
+					corev1.Linux, "init:1", "pull:1"),
+			).Build()
+			v := vrgInstanceFake(c, "vrg-failover-preparation-test",
+				ramen.VolumeReplicationGroupSpec{ReplicationState: ramen.Secondary})
+
+			v.updateWorkloadImages()
+			Expect(v.instance.Status.WorkloadImages).To(BeEmpty())
+
+			v.instance.Spec.ReplicationState = ramen.Primary
+			v.updateWorkloadImages()
+			Expect(v.instance.Status.WorkloadImages).To(Equal([]string{"init:1", "sidecar:1", "web:1"}))
+			Expect(v.instance.Status.WorkloadWindowsImages).To(Equal([]string{"iis:1", "init-win:1"}))
+		})
+	})
+
+	Describe("imagePulled", func() {
+		pod := func(statuses ...corev1.ContainerStatus) corev1.Pod {
+			return corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: statuses}}
+		}
+		pulled := corev1.ContainerStatus{Name: "image-0", ImageID: "sha256:0123"}
+		pulling := corev1.ContainerStatus{Name: "image-0"}
+
+		It("is pulled once the containers of all the pods report the image ID", func() {
+			Expect(imagePulled([]corev1.Pod{pod(pulled), pod(pulled)}, "image-0")).To(BeTrue())
+			Expect(imagePulled([]corev1.Pod{pod(pulled), pod(pulling)}, "image-0")).To(BeFalse())
+			Expect(imagePulled([]corev1.Pod{pod(pulled), pod()}, "image-0")).To(BeFalse())
+			Expect(imagePulled([]corev1.Pod{pod(pulled)}, "image-1")).To(BeFalse())
+		})
+	})
+
+	DescribeTable("failoverPreparationRecoverSpec",
+		func(recoverGroup kubeobjects.RecoverSpec, staged bool, included []string) {
+			recoverSpec, ok := failoverPreparationRecoverSpec(recoverGroup)
+			Expect(ok).To(Equal(staged))
+
+			if !staged {
+				return
+			}
+
+			Expect(recoverSpec.IncludedResources).To(Equal(included))
+			Expect(recoverSpec.ExistingResourcePolicy).To(Equal(velero.PolicyTypeUpdate))
+			Expect(recoverSpec.Hooks).To(BeNil())
+			Expect(recoverSpec.RestoreStatus).To(BeNil())
+		},
+		Entry("all resources", kubeobjects.RecoverSpec{BackupName: "b"}, true, failoverPreparationStagedResources),
+		Entry("all resources by a wildcard", kubeobjects.RecoverSpec{
+			BackupName: "b", Spec: kubeobjects.Spec{KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				IncludedResources: []string{"*"}, ExcludedResources: []string{"configmaps"},
+			}},
+		}, true, []string{"secrets", "serviceaccounts"}),
+		Entry("some staged resources", kubeobjects.RecoverSpec{
+			BackupName: "b", Spec: kubeobjects.Spec{KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				IncludedResources: []string{"deployments", "secrets"},
+			}},
+		}, true, []string{"secrets"}),
+		Entry("no staged resources", kubeobjects.RecoverSpec{
+			BackupName: "b", Spec: kubeobjects.Spec{KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				IncludedResources: []string{"deployments"},
+			}},
+		}, false, nil),
+		Entry("a capture taken at recovery", kubeobjects.RecoverSpec{BackupName: ramen.ReservedBackupName}, false, nil),
+		Entry("a hook", kubeobjects.RecoverSpec{
+			BackupName: "b", Spec: kubeobjects.Spec{KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				Hooks: []kubeobjects.HookSpec{{
+					Name: "h", HookRun: kubeobjects.HookRun{Job: &kubeobjects.HookJobSpec{HookName: "h"}},
+				}},
+			}},
+		}, false, nil),
+	)
+
+	Describe("failoverPreparationProgress", func() {
+		var d *DRPCInstance
+
+		capture := int64(3)
+
+		BeforeEach(func() {
+			d = &DRPCInstance{
+				instance: &ramen.DRPlacementControl{Spec: ramen.DRPlacementControlSpec{
+					KubeObjectProtection: &ramen.KubeObjectProtectionSpec{},
+				}},
+				vrgNamespace: "app",
+				vrgs:         map[string]*ramen.VolumeReplicationGroup{},
+			}
+		})
+
+		It("is prepared once the kube objects are staged and the images pulled on the failover cluster", func() {
+			prepared, msg := d.failoverPreparationProgress("west", []string{"web:1"})
+			Expect(prepared).To(BeFalse())
+			Expect(msg).To(Equal("waiting for cluster west to start preparing"))
+
+			status := &ramen.VRGFailoverPreparationStatus{Message: "staging"}
+			d.vrgs["west"] = &ramen.VolumeReplicationGroup{
+				Status: ramen.VolumeReplicationGroupStatus{FailoverPreparation: status},
+			}
+			prepared, msg = d.failoverPreparationProgress("west", []string{"web:1"})
+			Expect(prepared).To(BeFalse())
+			Expect(msg).To(Equal("staging kube objects on cluster west: staging"))
+
+			status.StagedCapture = &capture
+			status.Message = "pulling"
+			prepared, msg = d.failoverPreparationProgress("west", []string{"web:1"})
+			Expect(prepared).To(BeFalse())
+			Expect(msg).To(Equal("pulling images on cluster west: pulling"))
+
+			status.ImagesPulled = []string{"web:1"}
+			prepared, msg = d.failoverPreparationProgress("west", []string{"web:1"})
+			Expect(prepared).To(BeTrue())
+			Expect(msg).To(ContainSubstring("capture 3 are staged, and 1 images are pulled"))
+		})
+
+		It("does not wait for the kube objects staged without kube object protection", func() {
+			d.instance.Spec.KubeObjectProtection = nil
+			d.vrgs["west"] = &ramen.VolumeReplicationGroup{Status: ramen.VolumeReplicationGroupStatus{
+				FailoverPreparation: &ramen.VRGFailoverPreparationStatus{},
+			}}
+
+			prepared, msg := d.failoverPreparationProgress("west", nil)
+			Expect(prepared).To(BeTrue())
+			Expect(msg).To(ContainSubstring("kube objects are not protected"))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+const (
+	helmReleaseOwnerLabel   = "owner"
+	helmReleaseOwner        = "helm"
+	helmReleaseNameLabel    = "name"
+	helmReleaseVersionLabel = "version"
+
+	// helmReleasesGroupName is the name of the capture group of the release Secrets of Helm, added to the capture
+	// workflow of a recipe
+	helmReleasesGroupName = "helm-releases"
+)
+
+// helmReleaseSecretSelector selects the Secrets Helm stores the revisions of its releases in
+func helmReleaseSecretSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{helmReleaseOwnerLabel: helmReleaseOwner},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: helmReleaseNameLabel, Operator: metav1.LabelSelectorOpExists},
+			{Key: helmReleaseVersionLabel, Operator: metav1.LabelSelectorOpExists},
+		},
+	}
+}
+
+// recipeWorkflowsHelmReleasesAdd adds a group of the release Secrets of Helm in the namespaces of a recipe's capture
+// workflow, or the VRG's if it names none, to the workflow, and its recovery first to the recover workflow, for helm
+// to manage the recovered releases. The objects of a release are recovered with the label and annotations Helm
+// adopts them by, so only the release Secrets, which the groups of a recipe may not select, are required for helm
+// upgrade to manage them.
+func recipeWorkflowsHelmReleasesAdd(recipeElements *RecipeElements, vrgNamespace string) {
+	namespaces := []string{}
+
+	for _, captureSpec := range recipeElements.CaptureWorkflow {
+		for _, namespace := range captureSpec.IncludedNamespaces {
+			if !containsString(namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{vrgNamespace}
+	}
+
+	spec := kubeobjects.Spec{
+		KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+			IncludedNamespaces: namespaces,
+			IncludedResources:  []string{secretsResource},
+		},
+		LabelSelector: helmReleaseSecretSelector(),
+	}
+
+	recipeElements.CaptureWorkflow = append(recipeElements.CaptureWorkflow, kubeobjects.CaptureSpec{
+		Name: helmReleasesGroupName,
+		Spec: spec,
+	})
+	recipeElements.RecoverWorkflow = append([]kubeobjects.RecoverSpec{{
+		BackupName: helmReleasesGroupName,
+		Spec:       spec,
+	}}, recipeElements.RecoverWorkflow...)
+}
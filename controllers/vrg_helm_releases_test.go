@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the capture of the release Secrets of Helm with the workflows of recipes
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	recipe "github.com/ramendr/recipe/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_HelmReleases", func() {
+	vrg := ramen.VolumeReplicationGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+		Spec:       ramen.VolumeReplicationGroupSpec{KubeObjectProtection: &ramen.KubeObjectProtectionSpec{}},
+	}
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+	recipeWithWorkflows := func(namespaces ...string) recipe.Recipe {
+		return recipe.Recipe{Spec: recipe.RecipeSpec{
+			Groups: []*recipe.Group{
+				{Name: "config", Type: "resource", IncludedNamespaces: namespaces, LabelSelector: appSelector},
+				{Name: "restore", Type: "resource", BackupRef: "config", IncludedResourceTypes: []string{"*"}},
+			},
+			CaptureWorkflow: &recipe.Workflow{Sequence: []map[string]string{{"group": "config"}}},
+			RecoverWorkflow: &recipe.Workflow{Sequence: []map[string]string{{"group": "restore"}}},
+		}}
+	}
+
+	It("captures the release Secrets with the objects selected by the default workflow", func() {
+		selected := vrg.DeepCopy()
+		selected.Spec.KubeObjectProtection.KubeObjectSelector = appSelector
+
+		captureWorkflow := captureWorkflowDefault(*selected, ramen.RamenConfig{})
+		Expect(captureWorkflow).To(HaveLen(1))
+		Expect(captureWorkflow[0].OrLabelSelectors).To(Equal([]*metav1.LabelSelector{
+			appSelector, helmReleaseSecretSelector(),
+		}))
+	})
+
+	It("captures the release Secrets of the namespaces of a recipe capture workflow, and recovers them first", func() {
+		recipeElements := &RecipeElements{}
+		Expect(recipeWorkflowsGet(recipeWithWorkflows("app", "db"), recipeElements, vrg, ramen.RamenConfig{})).
+			To(Succeed())
+
+		Expect(recipeElements.CaptureWorkflow).To(HaveLen(2))
+		helmReleases := recipeElements.CaptureWorkflow[1]
+		Expect(helmReleases.Name).To(Equal(helmReleasesGroupName))
+		Expect(helmReleases.IncludedNamespaces).To(Equal([]string{"app", "db"}))
+		Expect(helmReleases.IncludedResources).To(Equal([]string{secretsResource}))
+		Expect(helmReleases.LabelSelector).To(Equal(helmReleaseSecretSelector()))
+
+		Expect(recipeElements.RecoverWorkflow).ToNot(BeEmpty())
+		Expect(recipeElements.RecoverWorkflow[0].BackupName).To(Equal(helmReleasesGroupName))
+		Expect(recipeElements.RecoverWorkflow[0].Spec).To(Equal(helmReleases.Spec))
+		Expect(recipeElements.RecoverWorkflow[1:]).To(HaveEach(HaveField("BackupName", "config")))
+	})
+
+	It("captures the release Secrets of the namespace of the VRG for a recipe whose groups name none", func() {
+		recipeElements := &RecipeElements{}
+		Expect(recipeWorkflowsGet(recipeWithWorkflows(), recipeElements, vrg, ramen.RamenConfig{})).To(Succeed())
+		Expect(recipeElements.CaptureWorkflow[1].IncludedNamespaces).To(Equal([]string{"app"}))
+	})
+
+	It("does not add a group to the default capture workflow of a recipe, which captures the release Secrets", func() {
+		withoutCapture := recipeWithWorkflows("app")
+		withoutCapture.Spec.CaptureWorkflow = nil
+
+		recipeElements := &RecipeElements{}
+		Expect(recipeWorkflowsGet(withoutCapture, recipeElements, vrg, ramen.RamenConfig{})).To(Succeed())
+		Expect(recipeElements.CaptureWorkflow).To(Equal(captureWorkflowDefault(vrg, ramen.RamenConfig{})))
+		Expect(recipeElements.RecoverWorkflow).ToNot(ContainElement(HaveField("BackupName", helmReleasesGroupName)))
+	})
+})
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// veleroRestoreNameLabel is set by Velero on the objects it restores
+	veleroRestoreNameLabel = "velero.io/restore-name"
+
+	// veleroChangeImageNameLabelKey labels the config map of Velero's restore item action that rewrites the images
+	// of the pods and pod templates it restores. Velero uses the only config map so labeled in its namespace.
+	veleroChangeImageNameLabelKey = "velero.io/change-image-name"
+
+	imageRegistryMirrorsConfigMapName = "ramen-image-registry-mirrors"
+)
+
+// imageRegistryMirrorsConfigMapApply configures Velero to rewrite the images of the workload it restores to the
+// image registry mirrors, so that its pods pull from the mirrors from the start. The mirrors are those of the cluster,
+// so the config map is shared by the VRGs recovering to it, and is not owned by any.
+func (v *VRGInstance) imageRegistryMirrorsConfigMapApply() error {
+	key := client.ObjectKey{Namespace: v.veleroNamespaceName(), Name: imageRegistryMirrorsConfigMapName}
+	data := imageRegistryMirrorsChangeImageNameData(v.instance.Spec.ImageRegistryMirrors)
+	current := &corev1.ConfigMap{}
+
+	if err := v.reconciler.Get(v.ctx, key, current); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get image registry mirrors config map %s (%w)", key, err)
+		}
+
+		if len(data) == 0 {
+			return nil
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels: map[string]string{
+					veleroPluginConfigLabelKey:    "",
+					veleroChangeImageNameLabelKey: "RestoreItemAction",
+				},
+			},
+			Data: data,
+		}
+
+		if err := v.reconciler.Create(v.ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create image registry mirrors config map %s (%w)", key, err)
+		}
+
+		v.log.Info("Image registry mirrors config map created", "configMap", key.String())
+
+		return nil
+	}
+
+	if len(data) == 0 {
+		if err := v.reconciler.Delete(v.ctx, current); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete image registry mirrors config map %s (%w)", key, err)
+		}
+
+		v.log.Info("Image registry mirrors config map deleted", "configMap", key.String())
+
+		return nil
+	}
+
+	if reflect.DeepEqual(current.Data, data) {
+		return nil
+	}
+
+	current.Data = data
+
+	if err := v.reconciler.Update(v.ctx, current); err != nil {
+		return fmt.Errorf("failed to update image registry mirrors config map %s (%w)", key, err)
+	}
+
+	v.log.Info("Image registry mirrors config map updated", "configMap", key.String())
+
+	return nil
+}
+
+// imageRegistryMirrorsChangeImageNameData returns the cases of Velero's change image name restore item action for
+// the mirrors. Velero replaces the first case it finds contained in an image, in no particular order, so a source
+// is matched up to a path separator, and the sources within other sources are left to the rewrite after the restore.
+func imageRegistryMirrorsChangeImageNameData(mirrors []ramen.ImageRegistryMirror) map[string]string {
+	data := map[string]string{}
+
+	for i := range mirrors {
+		source := strings.TrimSuffix(mirrors[i].Source, "/") + "/"
+		if source == "/" || imageRegistryMirrorsSourceNested(source, mirrors) {
+			continue
+		}
+
+		data["case"+strconv.Itoa(i)] = source + "," + strings.TrimSuffix(mirrors[i].Mirror, "/") + "/"
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return data
+}
+
+// imageRegistryMirrorsSourceNested returns true if a longer source of the mirrors contains the source
+func imageRegistryMirrorsSourceNested(source string, mirrors []ramen.ImageRegistryMirror) bool {
+	for i := range mirrors {
+		other := strings.TrimSuffix(mirrors[i].Source, "/") + "/"
+		if len(other) > len(source) && strings.Contains(other, source) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restoredObjectsListOptions returns the options to list the objects Velero restored, one for each of the protected
+// namespaces, or for the VRG namespace if none are specified
+func (v *VRGInstance) restoredObjectsListOptions() ([]*client.ListOptions, error) {
+	restored, err := labels.NewRequirement(veleroRestoreNameLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.NewSelector().Add(*restored)
+
+	namespaces := v.workloadNamespaces()
+	listOptions := make([]*client.ListOptions, len(namespaces))
+	for i, namespace := range namespaces {
+		listOptions[i] = &client.ListOptions{Namespace: namespace, LabelSelector: selector}
+	}
+
+	return listOptions, nil
+}
+
+// imageRegistryMirrorsApply rewrites the container images of the recovered pod templates, and of the recovered pods
+// that are not owned, to the image registry mirrors, those that Velero did not rewrite as it restored them. Job pod
+// templates are immutable, and are not rewritten.
+func (v *VRGInstance) imageRegistryMirrorsApply() error {
+	if len(v.instance.Spec.ImageRegistryMirrors) == 0 {
+		return nil
+	}
+
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		objects, err := v.imageRegistryMirrorsObjectsList(listOptions)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range objects {
+			if !podSpecImagesMirror(object.podSpec, v.instance.Spec.ImageRegistryMirrors) {
+				continue
+			}
+
+			if err := v.reconciler.Update(v.ctx, object.object); err != nil {
+				return fmt.Errorf("failed to rewrite images of %T %s/%s (%w)", object.object,
+					object.object.GetNamespace(), object.object.GetName(), err)
+			}
+
+			v.log.Info("Images rewritten to registry mirrors", "kind", fmt.Sprintf("%T", object.object),
+				"name", object.object.GetName(), "namespace", object.object.GetNamespace())
+		}
+	}
+
+	return nil
+}
+
+type podSpecObject struct {
+	object  client.Object
+	podSpec *corev1.PodSpec
+}
+
+func (v *VRGInstance) imageRegistryMirrorsObjectsList(listOptions *client.ListOptions) ([]podSpecObject, error) {
+	deployments := &appsv1.DeploymentList{}
+	statefulSets := &appsv1.StatefulSetList{}
+	daemonSets := &appsv1.DaemonSetList{}
+	cronJobs := &batchv1.CronJobList{}
+	pods := &corev1.PodList{}
+
+	for _, list := range []client.ObjectList{deployments, statefulSets, daemonSets, cronJobs, pods} {
+		if err := v.reconciler.List(v.ctx, list, listOptions); err != nil {
+			return nil, fmt.Errorf("failed to list %T in namespace %s (%w)", list, listOptions.Namespace, err)
+		}
+	}
+
+	objects := []podSpecObject{}
+
+	for i := range deployments.Items {
+		objects = append(objects, podSpecObject{&deployments.Items[i], &deployments.Items[i].Spec.Template.Spec})
+	}
+
+	for i := range statefulSets.Items {
+		objects = append(objects, podSpecObject{&statefulSets.Items[i], &statefulSets.Items[i].Spec.Template.Spec})
+	}
+
+	for i := range daemonSets.Items {
+		objects = append(objects, podSpecObject{&daemonSets.Items[i], &daemonSets.Items[i].Spec.Template.Spec})
+	}
+
+	for i := range cronJobs.Items {
+		objects = append(objects,
+			podSpecObject{&cronJobs.Items[i], &cronJobs.Items[i].Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	for i := range pods.Items {
+		if len(pods.Items[i].GetOwnerReferences()) == 0 {
+			objects = append(objects, podSpecObject{&pods.Items[i], &pods.Items[i].Spec})
+		}
+	}
+
+	return objects, nil
+}
+
+// podSpecImagesMirror rewrites the container images of the pod spec to the mirrors, and returns true if any image
+// was rewritten
+func podSpecImagesMirror(podSpec *corev1.PodSpec, mirrors []ramen.ImageRegistryMirror) bool {
+	rewritten := false
+
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			if image, ok := imageMirror(containers[i].Image, mirrors); ok {
+				containers[i].Image = image
+				rewritten = true
+			}
+		}
+	}
+
+	return rewritten
+}
+
+// imageMirror returns the image rewritten to the mirror of the longest source that it is in
+func imageMirror(image string, mirrors []ramen.ImageRegistryMirror) (string, bool) {
+	var match *ramen.ImageRegistryMirror
+
+	for i := range mirrors {
+		source := strings.TrimSuffix(mirrors[i].Source, "/")
+		if source == "" || !strings.HasPrefix(image, source) {
+			continue
+		}
+
+		// Match whole path elements only, the source ending at a separator or at the tag or digest
+		if rest := image[len(source):]; rest != "" && !strings.ContainsAny(rest[:1], "/:@") {
+			continue
+		}
+
+		if match == nil || len(source) > len(strings.TrimSuffix(match.Source, "/")) {
+			match = &mirrors[i]
+		}
+	}
+
+	if match == nil {
+		return image, false
+	}
+
+	source := strings.TrimSuffix(match.Source, "/")
+
+	return strings.TrimSuffix(match.Mirror, "/") + image[len(source):], true
+}
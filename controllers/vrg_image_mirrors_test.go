@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the rewrite of images to registry mirrors
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_ImageRegistryMirrors", func() {
+	mirrors := []ramen.ImageRegistryMirror{
+		{Source: "registry.east.example.com", Mirror: "registry.west.example.com"},
+		{Source: "registry.east.example.com/apps/", Mirror: "mirror.west.example.com/east-apps"},
+		{Source: "quay.io/team/db", Mirror: "registry.west.example.com/db"},
+	}
+
+	Describe("imageMirror", func() {
+		It("rewrites an image to the mirror of the longest source it is in", func() {
+			for image, expected := range map[string]string{
+				"registry.east.example.com/tools/curl:8":  "registry.west.example.com/tools/curl:8",
+				"registry.east.example.com/apps/web:1.0":  "mirror.west.example.com/east-apps/web:1.0",
+				"quay.io/team/db:15":                      "registry.west.example.com/db:15",
+				"quay.io/team/db@sha256:0123456789abcdef": "registry.west.example.com/db@sha256:0123456789abcdef",
+			} {
+				rewritten, ok := imageMirror(image, mirrors)
+				Expect(ok).To(BeTrue(), image)
+				Expect(rewritten).To(Equal(expected))
+			}
+		})
+
+		It("matches whole path elements only", func() {
+			for _, image := range []string{
+				"quay.io/team/dbadmin:1",
+				"registry.east.example.com.evil.io/web:1.0",
+				"docker.io/library/busybox",
+			} {
+				rewritten, ok := imageMirror(image, mirrors)
+				Expect(ok).To(BeFalse(), image)
+				Expect(rewritten).To(Equal(image))
+			}
+		})
+	})
+
+	Describe("podSpecImagesMirror", func() {
+		It("rewrites the images of the init containers and the containers", func() {
+			podSpec := &corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init", Image: "quay.io/team/db:15"}},
+				Containers: []corev1.Container{
+					{Name: "web", Image: "registry.east.example.com/apps/web:1.0"},
+					{Name: "sidecar", Image: "docker.io/library/busybox"},
+				},
+			}
+
+			Expect(podSpecImagesMirror(podSpec, mirrors)).To(BeTrue())
+			Expect(podSpec.InitContainers[0].Image).To(Equal("registry.west.example.com/db:15"))
+			Expect(podSpec.Containers[0].Image).To(Equal("mirror.west.example.com/east-apps/web:1.0"))
+			Expect(podSpec.Containers[1].Image).To(Equal("docker.io/library/busybox"))
+			Expect(podSpecImagesMirror(podSpec, mirrors)).To(BeFalse())
+		})
+	})
+
+	Describe("imageRegistryMirrorsChangeImageNameData", func() {
+		It("leaves the sources within other sources to the rewrite after the restore", func() {
+			Expect(imageRegistryMirrorsChangeImageNameData(mirrors)).To(Equal(map[string]string{
+				"case1": "registry.east.example.com/apps/,mirror.west.example.com/east-apps/",
+				"case2": "quay.io/team/db/,registry.west.example.com/db/",
+			}))
+			Expect(imageRegistryMirrorsChangeImageNameData(mirrors[:1])).To(Equal(map[string]string{
+				"case0": "registry.east.example.com/,registry.west.example.com/",
+			}))
+			Expect(imageRegistryMirrorsChangeImageNameData(nil)).To(BeNil())
+		})
+	})
+
+	Describe("restoredObjectsListOptions", func() {
+		It("selects the restored objects of the protected namespaces, or of the VRG namespace without them", func() {
+			vrgInstance := &VRGInstance{instance: &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ramen-ops", Name: "vrg"},
+			}}
+			namespaces := func() []string {
+				listOptions, err := vrgInstance.restoredObjectsListOptions()
+				Expect(err).ToNot(HaveOccurred())
+
+				namespaces := []string{}
+				for _, options := range listOptions {
+					Expect(options.LabelSelector.String()).To(Equal(veleroRestoreNameLabel))
+					namespaces = append(namespaces, options.Namespace)
+				}
+
+				return namespaces
+			}
+
+			Expect(namespaces()).To(Equal([]string{"ramen-ops"}))
+
+			vrgInstance.instance.Spec.ProtectedNamespaces = &[]string{"web", "db"}
+			Expect(namespaces()).To(Equal([]string{"web", "db"}))
+		})
+	})
+
+	Describe("imageRegistryMirrorsConfigMapApply and imageRegistryMirrorsApply", func() {
+		var (
+			vrg        *ramen.VolumeReplicationGroup
+			reconciler *VolumeReplicationGroupReconciler
+		)
+
+		vrgInstance := func() *VRGInstance {
+			return &VRGInstance{
+				reconciler:  reconciler,
+				ctx:         context.TODO(),
+				log:         ctrl.Log.WithName("vrg-image-mirrors-test"),
+				instance:    vrg,
+				ramenConfig: &ramen.RamenConfig{},
+			}
+		}
+		configMapKey := types.NamespacedName{
+			Namespace: VeleroNamespaceNameDefault,
+			Name:      imageRegistryMirrorsConfigMapName,
+		}
+		restoredLabels := map[string]string{veleroRestoreNameLabel: "restore"}
+		podTemplate := func(image string) corev1.PodTemplateSpec {
+			return corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "c", Image: image}}},
+			}
+		}
+
+		BeforeEach(func() {
+			vrg = &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+				Spec:       ramen.VolumeReplicationGroupSpec{ImageRegistryMirrors: mirrors},
+			}
+			reconciler = &VolumeReplicationGroupReconciler{
+				Client: fake.NewClientBuilder().WithObjects(
+					&appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "restored", Labels: restoredLabels},
+						Spec:       appsv1.DeploymentSpec{Template: podTemplate("registry.east.example.com/tools/curl:8")},
+					},
+					&appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "deployed"},
+						Spec:       appsv1.DeploymentSpec{Template: podTemplate("registry.east.example.com/tools/curl:8")},
+					},
+				).Build(),
+			}
+		})
+
+		It("configures Velero to rewrite the images it restores, until there are no mirrors", func() {
+			Expect(vrgInstance().imageRegistryMirrorsConfigMapApply()).To(Succeed())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(reconciler.Get(context.TODO(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Labels).To(HaveKeyWithValue(veleroChangeImageNameLabelKey, "RestoreItemAction"))
+			Expect(configMap.Labels).To(HaveKey(veleroPluginConfigLabelKey))
+			Expect(configMap.Data).To(Equal(imageRegistryMirrorsChangeImageNameData(mirrors)))
+
+			vrg.Spec.ImageRegistryMirrors = mirrors[:1]
+			Expect(vrgInstance().imageRegistryMirrorsConfigMapApply()).To(Succeed())
+			Expect(reconciler.Get(context.TODO(), configMapKey, configMap)).To(Succeed())
+			Expect(configMap.Data).To(HaveLen(1))
+
+			vrg.Spec.ImageRegistryMirrors = nil
+			Expect(vrgInstance().imageRegistryMirrorsConfigMapApply()).To(Succeed())
+			Expect(reconciler.Get(context.TODO(), configMapKey, configMap)).NotTo(Succeed())
+			Expect(vrgInstance().imageRegistryMirrorsConfigMapApply()).To(Succeed())
+		})
+
+		It("rewrites the images that Velero left of the restored workloads only", func() {
+			Expect(vrgInstance().imageRegistryMirrorsApply()).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "restored"},
+				deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(
+				Equal("registry.west.example.com/tools/curl:8"))
+
+			Expect(reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "deployed"},
+				deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(
+				Equal("registry.east.example.com/tools/curl:8"))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// vrgInstanceFake returns an instance of the reconcile of VRG app/vrg of the spec passed in, whose reconciler reads
+// and writes objects with the client passed in, for the white box testing of its steps with a fake client
+func vrgInstanceFake(c client.Client, logName string, spec ramen.VolumeReplicationGroupSpec) *VRGInstance {
+	return &VRGInstance{
+		reconciler: &VolumeReplicationGroupReconciler{Client: c, APIReader: c},
+		ctx:        context.TODO(),
+		log:        ctrl.Log.WithName(logName),
+		instance: &ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+			Spec:       spec,
+		},
+	}
+}
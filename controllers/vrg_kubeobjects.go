@@ -31,12 +31,24 @@ func kubeObjectsCaptureInterval(kubeObjectProtectionSpec *ramen.KubeObjectProtec
 	return kubeObjectProtectionSpec.CaptureInterval.Duration
 }
 
+// kubeObjectsPathName is the path, relative to a VRG's path, of its kube objects captures
+const kubeObjectsPathName = "kube-objects/"
+
+// kubeObjectsHookRunPollInterval is how often a hook that runs by itself, and is not watched, is checked
+const kubeObjectsHookRunPollInterval = 10 * time.Second
+
+// Workflows of a recipe, as told to the hooks they run
+const (
+	recipeWorkflowCapture = "capture"
+	recipeWorkflowRecover = "recover"
+)
+
 func kubeObjectsCapturePathNamesAndNamePrefix(
 	namespaceName, vrgName string, captureNumber int64, kubeObjects kubeobjects.RequestsManager,
 ) (string, string, string) {
 	const numberBase = 10
 	number := strconv.FormatInt(captureNumber, numberBase)
-	pathName := s3PathNamePrefix(namespaceName, vrgName) + "kube-objects/" + number + "/"
+	pathName := s3PathNamePrefix(namespaceName, vrgName) + kubeObjectsPathName + number + "/"
 
 	return pathName,
 		pathName + kubeObjects.ProtectsPath(),
@@ -93,6 +105,39 @@ func (v *VRGInstance) kubeObjectsProtect(
 		return
 	}
 
+	if err := v.kubeObjectsVeleroConfigMapsApply(false); err != nil {
+		v.log.Error(err, "Kube objects Velero config maps apply error")
+		v.kubeObjectsCaptureFailed("KubeObjectsVeleroConfigError", err.Error())
+
+		result.Requeue = true
+
+		return
+	}
+
+	if err := v.cronJobsResume(); err != nil {
+		v.log.Info("Recovered cron jobs resume failed", "error", err)
+
+		result.Requeue = true
+	}
+
+	if err := v.policyExemptionsRemove(false); err != nil {
+		v.log.Info("Policy exemptions remove failed", "error", err)
+
+		result.Requeue = true
+	}
+
+	if err := v.autoscalersUnpin(); err != nil {
+		v.log.Info("Recovered horizontal pod autoscalers unpin failed", "error", err)
+
+		result.Requeue = true
+	}
+
+	if err := v.schedulingGatesLift(); err != nil {
+		v.log.Info("Recovered workloads scheduling gates lift failed", "error", err)
+
+		result.Requeue = true
+	}
+
 	vrg := v.instance
 	status := &vrg.Status.KubeObjectProtection
 
@@ -244,11 +289,27 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResume(
 	requestsCompletedCount := 0
 	annotations := map[string]string{vrgGenerationKey: strconv.FormatInt(generation, vrgGenerationNumberBase)}
 
+	var captureInProgress *ramen.KubeObjectsCaptureIdentifier
+
+	if v.kubeObjectsDifferentialCaptureEnabled() {
+		var err error
+
+		captureInProgress, err = v.kubeObjectsCaptureInProgress(captureNumber, log)
+		if err != nil {
+			log.Error(err, "Kube objects differential capture start error")
+			v.kubeObjectsCaptureStatusFalse("KubeObjectsCaptureError", err.Error())
+
+			result.Requeue = true
+
+			return
+		}
+	}
+
 	for groupNumber, captureGroup := range groups {
 		log1 := log.WithValues("group", groupNumber, "name", captureGroup.Name)
 		requestsCompletedCount += v.kubeObjectsGroupCapture(
 			result, captureGroup, pathName, capturePathName, namePrefix, veleroNamespaceName,
-			captureInProgressStatusUpdate,
+			captureInProgress, captureInProgressStatusUpdate,
 			labels, annotations, requests, log,
 		)
 		requestsProcessedCount += len(v.s3StoreAccessors)
@@ -260,7 +321,22 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResume(
 		}
 	}
 
-	request0 := requests[kubeObjectsCaptureName(namePrefix, groups[0].Name, v.s3StoreAccessors[0].S3ProfileName)]
+	startTime, requestAnnotations := metav1.Now(), annotations
+
+	// the first group with a request, hooks that run by themselves and unchanged groups of a differential capture
+	// having none, starts the capture
+	for _, captureGroup := range groups {
+		request0, ok := requests[kubeObjectsCaptureName(namePrefix, captureGroup.Name, v.s3StoreAccessors[0].S3ProfileName)]
+		if !ok {
+			continue
+		}
+
+		startTime, requestAnnotations = request0.StartTime(), request0.Object().GetAnnotations()
+
+		break
+	}
+
+	v.kubeObjectsCaptureManifestsWrite(captureNumber, pathName, capturePathName, namePrefix, startTime, log)
 
 	v.kubeObjectsCaptureComplete(
 		result,
@@ -269,8 +345,8 @@ func (v *VRGInstance) kubeObjectsCaptureStartOrResume(
 		veleroNamespaceName,
 		interval,
 		labels,
-		request0.StartTime(),
-		request0.Object().GetAnnotations(),
+		startTime,
+		requestAnnotations,
 	)
 }
 
@@ -278,10 +354,60 @@ func (v *VRGInstance) kubeObjectsGroupCapture(
 	result *ctrl.Result,
 	captureGroup kubeobjects.CaptureSpec,
 	pathName, capturePathName, namePrefix, veleroNamespaceName string,
+	captureInProgress *ramen.KubeObjectsCaptureIdentifier,
 	captureInProgressStatusUpdate captureInProgressStatusUpdate,
 	labels, annotations map[string]string, requests map[string]kubeobjects.Request,
 	log logr.Logger,
 ) (requestsCompletedCount int) {
+	if hook := kubeObjectsHookRunnable(captureGroup.Spec); hook != nil {
+		completed, err := v.kubeObjectsHookRun(result, *hook, recipeWorkflowCapture, namePrefix+"--"+captureGroup.Name,
+			labels, log)
+		if err != nil {
+			log.Error(err, "Kube objects group capture hook error")
+			v.kubeObjectsCaptureStatusFalse("KubeObjectsCaptureError", err.Error())
+
+			result.Requeue = true
+
+			return
+		}
+
+		captureInProgressStatusUpdate()
+
+		if completed {
+			// the hook completes the group for every profile, having no requests of its own
+			requestsCompletedCount = len(v.s3StoreAccessors)
+		}
+
+		return
+	}
+
+	if captureInProgress != nil {
+		group, err := v.kubeObjectsCaptureGroupIdentify(captureGroup, captureInProgress, namePrefix, log)
+		if err != nil {
+			log.Error(err, "Kube objects group hash error")
+			v.kubeObjectsCaptureStatusFalse("KubeObjectsCaptureError", err.Error())
+
+			result.Requeue = true
+
+			return
+		}
+
+		captureInProgressStatusUpdate()
+
+		if group.Sequence != captureInProgress.Sequence {
+			// unchanged, the group refers to a previous capture for every profile, having no requests of its own
+			return len(v.s3StoreAccessors)
+		}
+
+		pathName = kubeObjectsDifferentialCapturePathName(v.instance.Namespace, v.instance.Name, group.Sequence)
+		capturePathName = pathName + v.reconciler.kubeObjects.ProtectsPath()
+	}
+
+	objectsSpec := captureGroup.Spec
+	objectsSpec.VolumesSpec = v.kubeObjectsVolumesSpec()
+	objectsSpec.ExcludedResources = append(append([]string{}, objectsSpec.ExcludedResources...),
+		v.uploadRedactionsExcludedResources()...)
+
 	for _, s3StoreAccessor := range v.s3StoreAccessors {
 		requestName := kubeObjectsCaptureName(namePrefix, captureGroup.Name, s3StoreAccessor.S3ProfileName)
 		log1 := log.WithValues("profile", s3StoreAccessor.S3ProfileName)
@@ -292,7 +418,7 @@ func (v *VRGInstance) kubeObjectsGroupCapture(
 				v.ctx, v.reconciler.Client, v.log,
 				s3StoreAccessor.S3CompatibleEndpoint, s3StoreAccessor.S3Bucket, s3StoreAccessor.S3Region,
 				pathName, s3StoreAccessor.VeleroNamespaceSecretKeyRef, s3StoreAccessor.CACertificates,
-				captureGroup.Spec, veleroNamespaceName, requestName,
+				objectsSpec, veleroNamespaceName, requestName,
 				labels, annotations,
 			); err != nil {
 				log1.Error(err, "Kube objects group capture request submit error")
@@ -377,9 +503,24 @@ func (v *VRGInstance) kubeObjectsCaptureComplete(
 		StartGeneration: startGeneration,
 	}
 
+	captureInProgress := vrg.Status.KubeObjectProtection.CaptureInProgress
+	if captureInProgress != nil && v.kubeObjectsDifferentialCaptureEnabled() {
+		(*captureToRecoverFromIdentifier).Sequence = captureInProgress.Sequence
+		(*captureToRecoverFromIdentifier).FullSequence = captureInProgress.FullSequence
+		(*captureToRecoverFromIdentifier).Groups = captureInProgress.Groups
+	}
+
 	v.vrgObjectProtectThrottled(
 		result,
 		func() {
+			vrg.Status.KubeObjectProtection.CaptureInProgress = nil
+
+			if (captureToRecoverFromIdentifierCurrent != nil && len(captureToRecoverFromIdentifierCurrent.Groups) > 0) ||
+				len((*captureToRecoverFromIdentifier).Groups) > 0 {
+				v.kubeObjectsDifferentialCapturesDelete(*captureToRecoverFromIdentifier,
+					captureToRecoverFromIdentifierCurrent)
+			}
+
 			v.kubeObjectsCaptureIdentifierUpdateComplete(
 				result,
 				captureStartConditionally,
@@ -415,6 +556,16 @@ func (v *VRGInstance) kubeObjectsCaptureIdentifierUpdateComplete(
 		return
 	}
 
+	if err := v.kubeObjectsHookJobsDelete(labels); err != nil {
+		v.log.Error(err, "Kube objects capture hook jobs delete error",
+			"number", captureToRecoverFromIdentifier.Number)
+		v.kubeObjectsCaptureFailed("KubeObjectsCaptureRequestsDeleteError", err.Error())
+
+		result.Requeue = true
+
+		return
+	}
+
 	v.kubeObjectsCaptureStatus(metav1.ConditionTrue, VRGConditionReasonUploaded, clusterDataProtectedTrueMessage)
 
 	captureStartTimeSince := time.Since(captureToRecoverFromIdentifier.StartTime.Time)
@@ -478,6 +629,31 @@ func (v *VRGInstance) kubeObjectsRecover(result *ctrl.Result,
 	}
 
 	vrg.Status.KubeObjectProtection.CaptureToRecoverFrom = captureToRecoverFromIdentifier
+
+	if err := v.kubeObjectsVeleroConfigMapsApply(true); err != nil {
+		v.log.Error(err, "Kube objects Velero config maps apply error")
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.kubeObjectsAPIVersionPrioritiesApply(); err != nil {
+		v.log.Error(err, "Kube objects API version priorities apply error")
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.imageRegistryMirrorsConfigMapApply(); err != nil {
+		v.log.Error(err, "Image registry mirrors config map apply error")
+
+		result.Requeue = true
+
+		return err
+	}
+
 	veleroNamespaceName := v.veleroNamespaceName()
 	labels := util.OwnerLabels(vrg)
 	log := v.log.WithValues("number", captureToRecoverFromIdentifier.Number, "profile", localS3StoreAccessor.S3ProfileName)
@@ -536,6 +712,8 @@ func (v *VRGInstance) getRecoverOrProtectRequest(
 		backupName := fmt.Sprintf("%s-restore-%d", recoverGroup.BackupName, groupNumber)
 		captureName := kubeObjectsCaptureName(namePrefix, backupName, s3StoreAccessor.S3ProfileName)
 		request, ok := captureRequests[captureName]
+		objectsSpec := recoverGroup.Spec
+		objectsSpec.VolumesSpec = v.kubeObjectsVolumesSpec()
 
 		return request, ok, func() (kubeobjects.Request, error) {
 				return v.reconciler.kubeObjects.ProtectRequestCreate(
@@ -543,7 +721,7 @@ func (v *VRGInstance) getRecoverOrProtectRequest(
 					s3StoreAccessor.S3CompatibleEndpoint, s3StoreAccessor.S3Bucket, s3StoreAccessor.S3Region, pathName,
 					s3StoreAccessor.VeleroNamespaceSecretKeyRef,
 					s3StoreAccessor.CACertificates,
-					recoverGroup.Spec, veleroNamespaceName,
+					objectsSpec, veleroNamespaceName,
 					captureName,
 					labels, annotations)
 			},
@@ -555,11 +733,12 @@ func (v *VRGInstance) getRecoverOrProtectRequest(
 	recoverNamePrefix := kubeObjectsRecoverNamePrefix(vrg.Namespace, vrg.Name)
 	recoverName := kubeObjectsRecoverName(recoverNamePrefix, groupNumber)
 	recoverRequest, ok := recoverRequests[recoverName]
+	recoverGroup.ResourceModifierName = kubeObjectsResourceModifierName(vrg.Namespace, vrg.Name)
 
 	return recoverRequest, ok, func() (kubeobjects.Request, error) {
-			pathName, _, captureNamePrefix := kubeObjectsCapturePathNamesAndNamePrefix(
-				sourceVrgNamespaceName, sourceVrgName, captureToRecoverFromIdentifier.Number, v.reconciler.kubeObjects)
-			captureName := kubeObjectsCaptureName(captureNamePrefix, recoverGroup.BackupName, s3StoreAccessor.S3ProfileName)
+			pathName, captureName := v.kubeObjectsRecoverCapturePathNameAndName(
+				sourceVrgNamespaceName, sourceVrgName, captureToRecoverFromIdentifier,
+				recoverGroup.BackupName, s3StoreAccessor.S3ProfileName)
 			captureRequest := captureRequests[captureName]
 
 			return v.reconciler.kubeObjects.RecoverRequestCreate(
@@ -579,6 +758,21 @@ func (v *VRGInstance) getRecoverOrProtectRequest(
 		}
 }
 
+// kubeObjectsRecoverCapturePathNameAndName returns the S3 path name and the name of the capture a recover group
+// recovers from, the differential capture of its group if any
+func (v *VRGInstance) kubeObjectsRecoverCapturePathNameAndName(sourceVrgNamespaceName, sourceVrgName string,
+	captureToRecoverFromIdentifier *ramen.KubeObjectsCaptureIdentifier, backupName, s3ProfileName string,
+) (string, string) {
+	pathName, _, captureNamePrefix := kubeObjectsCapturePathNamesAndNamePrefix(
+		sourceVrgNamespaceName, sourceVrgName, captureToRecoverFromIdentifier.Number, v.reconciler.kubeObjects)
+	if group := kubeObjectsCaptureGroupFind(captureToRecoverFromIdentifier.Groups, backupName); group != nil {
+		pathName = kubeObjectsDifferentialCapturePathName(sourceVrgNamespaceName, sourceVrgName, group.Sequence)
+		captureNamePrefix = group.NamePrefix
+	}
+
+	return pathName, kubeObjectsCaptureName(captureNamePrefix, backupName, s3ProfileName)
+}
+
 func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 	result *ctrl.Result, s3StoreAccessor s3StoreAccessor,
 	sourceVrgNamespaceName, sourceVrgName string,
@@ -586,11 +780,52 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 	captureRequests, recoverRequests map[string]kubeobjects.Request,
 	veleroNamespaceName string, labels map[string]string, log logr.Logger,
 ) error {
+	if err := v.policyExemptionsApply(); err != nil {
+		log.Info("Policy exemptions apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
 	groups := v.recipeElements.RecoverWorkflow
 	requests := make([]kubeobjects.Request, len(groups))
 
 	for groupNumber, recoverGroup := range groups {
 		log1 := log.WithValues("group", groupNumber, "name", recoverGroup.BackupName)
+
+		if v.restoreCheckpointGroupRecovered(captureToRecoverFromIdentifier.Number, groupNumber) {
+			log1.Info("Kube objects group recovered as of checkpoint")
+
+			if err := v.kubeObjectsGroupRecoveredOperatorsReady(recoverGroup, result, log1); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if hook := kubeObjectsHookRunnable(recoverGroup.Spec); hook != nil {
+			completed, err := v.kubeObjectsHookRun(result, *hook, recipeWorkflowRecover,
+				kubeObjectsRecoverName(kubeObjectsRecoverNamePrefix(v.instance.Namespace, v.instance.Name), groupNumber),
+				labels, log1)
+			if err != nil {
+				log1.Error(err, "Kube objects group recover hook error")
+
+				result.Requeue = true
+
+				return err
+			}
+
+			if !completed {
+				return kubeobjects.RequestProcessingErrorCreate("hook running")
+			}
+
+			log1.Info("Kube objects group recovered by hook")
+			v.restoreCheckpointGroupSet(captureToRecoverFromIdentifier.Number, groupNumber)
+
+			continue
+		}
+
 		request, ok, submit, cleanup := v.getRecoverOrProtectRequest(
 			captureRequests, recoverRequests, s3StoreAccessor,
 			sourceVrgNamespaceName, sourceVrgName,
@@ -601,6 +836,38 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 		var err error
 
 		if !ok {
+			if err := v.secretRewritesApply(); err != nil {
+				log1.Info("Secret rewrites apply failed", "error", err)
+
+				result.Requeue = true
+
+				return err
+			}
+
+			if err := v.autoscalersPinRecovered(); err != nil {
+				log1.Info("Recovered horizontal pod autoscalers pin failed", "error", err)
+
+				result.Requeue = true
+
+				return err
+			}
+
+			if err := v.schedulingGatesLift(); err != nil {
+				log1.Info("Recovered workloads scheduling gates lift failed", "error", err)
+
+				result.Requeue = true
+
+				return err
+			}
+
+			if err := v.kubeObjectsVeleroConfigMapsApply(true); err != nil {
+				log1.Info("Kube objects Velero config maps apply failed", "error", err)
+
+				result.Requeue = true
+
+				return err
+			}
+
 			_, err = submit()
 			if err == nil {
 				log1.Info("Kube objects group recover request submitted")
@@ -612,6 +879,11 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 			if err == nil {
 				log1.Info("Kube objects group recovered", "start", request.StartTime(), "end", request.EndTime())
 				requests[groupNumber] = request
+				v.restoreCheckpointGroupSet(captureToRecoverFromIdentifier.Number, groupNumber)
+
+				if err := v.kubeObjectsGroupRecoveredOperatorsReady(recoverGroup, result, log1); err != nil {
+					return err
+				}
 
 				continue
 			}
@@ -634,13 +906,107 @@ func (v *VRGInstance) kubeObjectsRecoveryStartOrResume(
 		return err
 	}
 
-	startTime := requests[0].StartTime()
+	startTime := v.restoreCheckpointStartTime()
+	if len(requests) != 0 && requests[0] != nil {
+		startTime = requests[0].StartTime()
+	}
+
 	duration := time.Since(startTime.Time)
 	log.Info("Kube objects recovered", "groups", len(groups), "start", startTime, "duration", duration)
 
+	if err := v.autoscalersPinRecovered(); err != nil {
+		log.Info("Recovered horizontal pod autoscalers pin failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.configHashesAnnotate(); err != nil {
+		log.Info("Recovered workloads config hashes annotate failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.serviceOverridesApply(); err != nil {
+		log.Info("Service overrides apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.imageRegistryMirrorsApply(); err != nil {
+		log.Info("Image registry mirrors apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.networkAttachmentsMap(); err != nil {
+		log.Info("Network attachment mappings apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.routeTranslationsApply(); err != nil {
+		log.Info("Route translations apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.secretRewritesApply(); err != nil {
+		log.Info("Secret rewrites apply failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.ownerReferencesRelink(); err != nil {
+		log.Info("Owner references re-link failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.kubeObjectsHookJobsDelete(labels); err != nil {
+		log.Info("Recover hook jobs delete failed", "error", err)
+
+		result.Requeue = true
+
+		return err
+	}
+
 	return v.kubeObjectsRecoverRequestsDelete(result, veleroNamespaceName, labels)
 }
 
+// kubeObjectsGroupRecoveredOperatorsReady waits, once a group that recovers operators is recovered, for the operators
+// to be ready before the groups after it recover their custom resources
+func (v *VRGInstance) kubeObjectsGroupRecoveredOperatorsReady(recoverGroup kubeobjects.RecoverSpec,
+	result *ctrl.Result, log logr.Logger,
+) error {
+	if !recoverGroupOperators(recoverGroup) {
+		return nil
+	}
+
+	if err := v.operatorsRecoveredReady(log); err != nil {
+		result.Requeue = true
+
+		return err
+	}
+
+	return nil
+}
+
 func (v *VRGInstance) kubeObjectsRecoverRequestsDelete(
 	result *ctrl.Result, veleroNamespaceName string, labels map[string]string,
 ) error {
@@ -683,11 +1049,33 @@ func (v *VRGInstance) kubeObjectsProtectionDelete(result *ctrl.Result) error {
 
 	vrg := v.instance
 
-	return v.kubeObjectsRecoverRequestsDelete(
+	if err := v.kubeObjectsRecoverRequestsDelete(
 		result,
 		v.veleroNamespaceName(),
 		util.OwnerLabels(vrg),
-	)
+	); err != nil {
+		return err
+	}
+
+	if err := v.kubeObjectsVeleroConfigMapsDelete(util.OwnerLabels(vrg),
+		func(string) bool { return true },
+	); err != nil {
+		v.log.Error(err, "Kube objects Velero config maps delete error")
+
+		result.Requeue = true
+
+		return err
+	}
+
+	if err := v.policyExemptionsRemove(true); err != nil {
+		v.log.Error(err, "Policy exemptions remove error")
+
+		result.Requeue = true
+
+		return err
+	}
+
+	return nil
 }
 
 func kubeObjectsRequestsWatch(
@@ -769,7 +1157,12 @@ func getResourceAndConvertToCaptureGroup(
 			return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "Recipe.Spec"}, resourceType)
 		}
 
-		return convertRecipeHookToCaptureSpec(*hook, *op)
+		run, err := recipeHookRun(recipe, *hook, *op)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertRecipeHookToCaptureSpec(*hook, *op, run)
 	}
 
 	return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "Recipe.Spec"}, resourceType)
@@ -795,7 +1188,12 @@ func getResourceAndConvertToRecoverGroup(
 			return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "Recipe.Spec"}, resourceType)
 		}
 
-		return convertRecipeHookToRecoverSpec(*hook, *op)
+		run, err := recipeHookRun(recipe, *hook, *op)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertRecipeHookToRecoverSpec(*hook, *op, run)
 	}
 
 	return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "Recipe.Spec"}, resourceType)
@@ -835,13 +1233,71 @@ func getHookAndOpFromRecipe(recipe *Recipe.Recipe, name string) (*Recipe.Hook, *
 	return nil, nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "Recipe.Spec.Hook.Name"}, name)
 }
 
+// recipeHookRun returns how a recipe hook runs, if not by running its command in the pods it selects
+func recipeHookRun(recipe Recipe.Recipe, hook Recipe.Hook, op Recipe.Operation) (kubeobjects.HookRun, error) {
+	job, err := recipeHookJobSpec(recipe, hook, op)
+	if err != nil {
+		return kubeobjects.HookRun{}, err
+	}
+
+	http, err := recipeHookHTTPSpec(recipe, hook, op)
+	if err != nil {
+		return kubeobjects.HookRun{}, err
+	}
+
+	return kubeobjects.HookRun{Job: job, HTTP: http}, nil
+}
+
+// recipeHookOnErrorContinue returns whether a hook operation's failure continues its workflow, rather than failing it
+func recipeHookOnErrorContinue(hook Recipe.Hook, op Recipe.Operation) bool {
+	onError := op.OnError
+	if onError == "" {
+		onError = hook.OnError
+	}
+
+	return onError == recipeOnErrorContinue
+}
+
+// kubeObjectsHookRunnable returns the hook of a workflow step that runs by itself, as a job or http call, rather
+// than by a request, or nil if the step does not
+func kubeObjectsHookRunnable(spec kubeobjects.Spec) *kubeobjects.HookSpec {
+	if len(spec.Hooks) == 0 || (spec.Hooks[0].Job == nil && spec.Hooks[0].HTTP == nil) {
+		return nil
+	}
+
+	return &spec.Hooks[0]
+}
+
+// kubeObjectsHookRun runs a hook that runs by itself for the capture or recovery named by prefix, of a workflow,
+// and returns whether it completed, having scheduled a reconcile to check it again if not, as it is not watched
+func (v *VRGInstance) kubeObjectsHookRun(result *ctrl.Result, hook kubeobjects.HookSpec, workflow, prefix string,
+	labels map[string]string, log logr.Logger,
+) (bool, error) {
+	var (
+		completed bool
+		err       error
+	)
+
+	if hook.HTTP != nil {
+		completed, err = v.kubeObjectsHookCall(hook, workflow, prefix, log)
+	} else {
+		completed, err = v.kubeObjectsHookJobRun(hook, prefix, labels, log)
+	}
+
+	if err == nil && !completed {
+		delaySetIfLess(result, kubeObjectsHookRunPollInterval, log)
+	}
+
+	return completed, err
+}
+
 // TODO: complete functionality - add Hook support to KubeResourcesSpec, then copy in Velero object creation
 func convertRecipeHookToCaptureSpec(
-	hook Recipe.Hook, op Recipe.Operation) (*kubeobjects.CaptureSpec, error,
+	hook Recipe.Hook, op Recipe.Operation, run kubeobjects.HookRun) (*kubeobjects.CaptureSpec, error,
 ) {
 	hookName := hook.Name + "-" + op.Name
 
-	hooks := getHookSpecFromHook(hook, op)
+	hooks := getHookSpecFromHook(hook, op, run)
 
 	captureSpec := kubeobjects.CaptureSpec{
 		Name: hookName,
@@ -860,8 +1316,9 @@ func convertRecipeHookToCaptureSpec(
 	return &captureSpec, nil
 }
 
-func convertRecipeHookToRecoverSpec(hook Recipe.Hook, op Recipe.Operation) (*kubeobjects.RecoverSpec, error) {
-	hooks := getHookSpecFromHook(hook, op)
+func convertRecipeHookToRecoverSpec(hook Recipe.Hook, op Recipe.Operation, run kubeobjects.HookRun,
+) (*kubeobjects.RecoverSpec, error) {
+	hooks := getHookSpecFromHook(hook, op, run)
 
 	return &kubeobjects.RecoverSpec{
 		// BackupName: arbitrary fixed string to designate that this is will be a Backup, not Restore, object
@@ -879,7 +1336,8 @@ func convertRecipeHookToRecoverSpec(hook Recipe.Hook, op Recipe.Operation) (*kub
 	}, nil
 }
 
-func getHookSpecFromHook(hook Recipe.Hook, op Recipe.Operation) []kubeobjects.HookSpec {
+func getHookSpecFromHook(hook Recipe.Hook, op Recipe.Operation, run kubeobjects.HookRun,
+) []kubeobjects.HookSpec {
 	return []kubeobjects.HookSpec{
 		{
 			Name:          op.Name,
@@ -888,6 +1346,7 @@ func getHookSpecFromHook(hook Recipe.Hook, op Recipe.Operation) []kubeobjects.Ho
 			Container:     &op.Container,
 			Command:       op.Command,
 			LabelSelector: hook.LabelSelector,
+			HookRun:       run,
 		},
 	}
 }
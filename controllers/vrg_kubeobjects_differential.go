@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+// kubeObjectsCapturesPathName is the path, relative to a VRG's kube objects path, of the group captures of its
+// differential captures, by sequence. A group capture is kept as long as a capture refers to it, unlike the
+// numbered captures, which are overwritten every other capture.
+const kubeObjectsCapturesPathName = "captures/"
+
+const kubeObjectsFullCaptureIntervalDefault = 12
+
+// kubeObjectsHashExcludedResources change without changing what a group recovers, so they are not hashed, as
+// Velero does not capture them by default either
+var kubeObjectsHashExcludedResources = []string{"events", "events.events.k8s.io", "leases.coordination.k8s.io"}
+
+func kubeObjectsDifferentialCapturesPathName(namespaceName, vrgName string) string {
+	return s3PathNamePrefix(namespaceName, vrgName) + kubeObjectsPathName + kubeObjectsCapturesPathName
+}
+
+func kubeObjectsDifferentialCapturePathName(namespaceName, vrgName string, sequence int64) string {
+	return kubeObjectsDifferentialCapturesPathName(namespaceName, vrgName) +
+		strconv.FormatInt(sequence, vrgGenerationNumberBase) + "/"
+}
+
+func (v *VRGInstance) kubeObjectsDifferentialCaptureEnabled() bool {
+	return v.ramenConfig.KubeObjectProtection.DifferentialCapture.Enabled
+}
+
+func kubeObjectsFullCaptureInterval(ramenConfig *ramen.RamenConfig) int64 {
+	if interval := ramenConfig.KubeObjectProtection.DifferentialCapture.FullCaptureInterval; interval > 0 {
+		return interval
+	}
+
+	return kubeObjectsFullCaptureIntervalDefault
+}
+
+func kubeObjectsCaptureGroupFind(groups []ramen.KubeObjectsCaptureGroupIdentifier, name string,
+) *ramen.KubeObjectsCaptureGroupIdentifier {
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i]
+		}
+	}
+
+	return nil
+}
+
+// kubeObjectsCaptureInProgress returns the differential capture in progress, which is a full capture if none of the
+// previous captures was, within the full capture interval. Its sequence follows both that of the capture to recover
+// from and the last one in the s3 stores, so that a cluster a VRG fails over or relocates to does not overwrite the
+// group captures that the captures of the cluster it was on refer to. Once started, its path is cleared of the group
+// captures of a previous attempt.
+func (v *VRGInstance) kubeObjectsCaptureInProgress(captureNumber int64, log logr.Logger,
+) (*ramen.KubeObjectsCaptureIdentifier, error) {
+	status := &v.instance.Status.KubeObjectProtection
+
+	previous := status.CaptureToRecoverFrom
+	if previous == nil {
+		previous = &ramen.KubeObjectsCaptureIdentifier{}
+	}
+
+	if inProgress := status.CaptureInProgress; inProgress != nil && inProgress.Number == captureNumber &&
+		inProgress.Sequence > previous.Sequence {
+		return inProgress, nil
+	}
+
+	sequenceLast, err := v.kubeObjectsDifferentialCaptureSequenceLast()
+	if err != nil {
+		return nil, err
+	}
+
+	sequence := max(previous.Sequence, sequenceLast) + 1
+
+	fullSequence := previous.FullSequence
+	if len(previous.Groups) == 0 || sequence-previous.FullSequence >= kubeObjectsFullCaptureInterval(v.ramenConfig) {
+		fullSequence = sequence
+	}
+
+	if kubeObjectsCaptureRefers(previous, sequence) {
+		return nil, fmt.Errorf("differential capture %d is referred to by the capture to recover from", sequence)
+	}
+
+	pathName := kubeObjectsDifferentialCapturePathName(v.instance.Namespace, v.instance.Name, sequence)
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		if err := s3StoreAccessor.ObjectStorer.DeleteObjectsWithKeyPrefix(pathName); err != nil {
+			return nil, fmt.Errorf("failed to delete differential capture %d of s3 profile %s (%w)",
+				sequence, s3StoreAccessor.S3ProfileName, err)
+		}
+	}
+
+	status.CaptureInProgress = &ramen.KubeObjectsCaptureIdentifier{
+		Number:       captureNumber,
+		Sequence:     sequence,
+		FullSequence: fullSequence,
+	}
+
+	log.Info("Kube objects differential capture start", "sequence", sequence, "full", fullSequence == sequence)
+
+	return status.CaptureInProgress, nil
+}
+
+// kubeObjectsDifferentialCaptureSequenceLast returns the largest sequence of the differential captures of the VRG
+// in any of its s3 stores, or 0 if there are none
+func (v *VRGInstance) kubeObjectsDifferentialCaptureSequenceLast() (int64, error) {
+	pathName := kubeObjectsDifferentialCapturesPathName(v.instance.Namespace, v.instance.Name)
+
+	var last int64
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		keys, err := s3StoreAccessor.ObjectStorer.ListKeys(pathName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list differential captures of s3 profile %s (%w)",
+				s3StoreAccessor.S3ProfileName, err)
+		}
+
+		for _, key := range keys {
+			sequenceName, _, _ := strings.Cut(strings.TrimPrefix(key, pathName), "/")
+
+			sequence, err := strconv.ParseInt(sequenceName, vrgGenerationNumberBase, 64)
+			if err == nil && sequence > last {
+				last = sequence
+			}
+		}
+	}
+
+	return last, nil
+}
+
+// kubeObjectsCaptureRefers returns whether any group of a capture refers to the group captures of a sequence
+func kubeObjectsCaptureRefers(identifier *ramen.KubeObjectsCaptureIdentifier, sequence int64) bool {
+	for _, group := range identifier.Groups {
+		if group.Sequence == sequence {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kubeObjectsCaptureGroupIdentify returns a group of the differential capture in progress. When the group is first
+// reached, it is hashed, and it refers to its capture of the capture to recover from if its hash is unchanged,
+// unless the capture in progress is a full capture, or is captured otherwise.
+func (v *VRGInstance) kubeObjectsCaptureGroupIdentify(
+	captureGroup kubeobjects.CaptureSpec, inProgress *ramen.KubeObjectsCaptureIdentifier, namePrefix string,
+	log logr.Logger,
+) (ramen.KubeObjectsCaptureGroupIdentifier, error) {
+	if group := kubeObjectsCaptureGroupFind(inProgress.Groups, captureGroup.Name); group != nil {
+		return *group, nil
+	}
+
+	hash, err := v.kubeObjectsGroupHash(captureGroup.Spec)
+	if err != nil {
+		return ramen.KubeObjectsCaptureGroupIdentifier{}, err
+	}
+
+	group := ramen.KubeObjectsCaptureGroupIdentifier{
+		Name:       captureGroup.Name,
+		Sequence:   inProgress.Sequence,
+		NamePrefix: namePrefix,
+		Hash:       hash,
+	}
+
+	if previous := v.instance.Status.KubeObjectProtection.CaptureToRecoverFrom; previous != nil &&
+		hash != "" && inProgress.FullSequence != inProgress.Sequence {
+		if previousGroup := kubeObjectsCaptureGroupFind(previous.Groups, captureGroup.Name); previousGroup != nil &&
+			previousGroup.Hash == hash {
+			group = *previousGroup
+
+			log.Info("Kube objects group unchanged", "sequence", group.Sequence)
+		}
+	}
+
+	inProgress.Groups = append(inProgress.Groups, group)
+
+	return group, nil
+}
+
+// kubeObjectsGroupHash returns the hash of the identities, generations, labels and annotations of the objects a
+// group captures, or an empty string if the group includes cluster resources, all namespaces, or kinds the operator
+// may not list, whose objects are not listed. An object without a generation contributes its resource version
+// instead, so that changes to its data are detected, while changes to the status of an object with a generation
+// are not.
+func (v *VRGInstance) kubeObjectsGroupHash(spec kubeobjects.Spec) (string, error) {
+	if (spec.IncludeClusterResources != nil && *spec.IncludeClusterResources) ||
+		len(spec.IncludedNamespaces) == 0 || slices.Contains(spec.IncludedNamespaces, "*") {
+		return "", nil
+	}
+
+	kinds, err := v.kubeObjectsGroupKinds(spec.IncludedResources, spec.ExcludedResources)
+	if err != nil {
+		return "", err
+	}
+
+	selectors, err := kubeObjectsGroupSelectors(spec)
+	if err != nil {
+		return "", err
+	}
+
+	entries := []string{}
+
+	for _, namespace := range spec.IncludedNamespaces {
+		for _, gvk := range kinds {
+			for _, selector := range selectors {
+				objects := &metav1.PartialObjectMetadataList{}
+				objects.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+				if err := v.reconciler.APIReader.List(v.ctx, objects,
+					client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector},
+				); err != nil {
+					if k8serrors.IsForbidden(err) {
+						return "", nil
+					}
+
+					return "", fmt.Errorf("failed to list %s in namespace %s (%w)", gvk.Kind, namespace, err)
+				}
+
+				for i := range objects.Items {
+					entry, err := kubeObjectsHashEntry(gvk, &objects.Items[i])
+					if err != nil {
+						return "", err
+					}
+
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+
+	sort.Strings(entries)
+	entries = slices.Compact(entries)
+
+	hash := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func kubeObjectsHashEntry(gvk schema.GroupVersionKind, object *metav1.PartialObjectMetadata) (string, error) {
+	version := object.GetResourceVersion()
+	if generation := object.GetGeneration(); generation > 0 {
+		version = strconv.FormatInt(generation, vrgGenerationNumberBase)
+	}
+
+	metadataJSON, err := json.Marshal([]map[string]string{object.GetLabels(), object.GetAnnotations()})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata of %s %s/%s (%w)",
+			gvk.Kind, object.GetNamespace(), object.GetName(), err)
+	}
+
+	return strings.Join([]string{
+		gvk.String(), object.GetNamespace(), object.GetName(), string(object.GetUID()), version, string(metadataJSON),
+	}, " "), nil
+}
+
+// kubeObjectsGroupKinds returns the namespaced kinds a group captures, as Velero matches its included and excluded
+// resources: by resource name, optionally qualified by group, kind or short name
+func (v *VRGInstance) kubeObjectsGroupKinds(includedResources, excludedResources []string,
+) ([]schema.GroupVersionKind, error) {
+	resourceLists, err := v.reconciler.discovery.ServerPreferredNamespacedResources()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover namespaced resources (%w)", err)
+	}
+
+	excludedResources = append(append([]string{}, excludedResources...), kubeObjectsHashExcludedResources...)
+	kinds := []schema.GroupVersionKind{}
+
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resource := range resourceList.APIResources {
+			if !slices.Contains(resource.Verbs, "list") ||
+				(len(includedResources) > 0 && !kubeObjectsResourceMatch(includedResources, resource, groupVersion.Group)) ||
+				kubeObjectsResourceMatch(excludedResources, resource, groupVersion.Group) {
+				continue
+			}
+
+			kinds = append(kinds, groupVersion.WithKind(resource.Kind))
+		}
+	}
+
+	return kinds, nil
+}
+
+func kubeObjectsResourceMatch(names []string, resource metav1.APIResource, group string) bool {
+	for _, name := range names {
+		if name == "*" || name == resource.Name || name == resource.Name+"."+group ||
+			strings.EqualFold(name, resource.Kind) || slices.Contains(resource.ShortNames, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kubeObjectsGroupSelectors returns the label selectors of a group, any of which selects an object
+func kubeObjectsGroupSelectors(spec kubeobjects.Spec) ([]labels.Selector, error) {
+	labelSelectors := spec.OrLabelSelectors
+	if spec.LabelSelector != nil {
+		labelSelectors = append([]*metav1.LabelSelector{spec.LabelSelector}, labelSelectors...)
+	}
+
+	if len(labelSelectors) == 0 {
+		return []labels.Selector{labels.Everything()}, nil
+	}
+
+	selectors := make([]labels.Selector, 0, len(labelSelectors))
+
+	for _, labelSelector := range labelSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		selectors = append(selectors, selector)
+	}
+
+	return selectors, nil
+}
+
+// kubeObjectsDifferentialCapturesDelete deletes, from each s3 store, the group captures of the differential captures
+// that none of the identified captures refer to. A failed deletion is logged, to be retried after the next capture.
+func (v *VRGInstance) kubeObjectsDifferentialCapturesDelete(identifiers ...*ramen.KubeObjectsCaptureIdentifier) {
+	referred := map[string]bool{}
+
+	for _, identifier := range identifiers {
+		if identifier == nil {
+			continue
+		}
+
+		for _, group := range identifier.Groups {
+			referred[strconv.FormatInt(group.Sequence, vrgGenerationNumberBase)] = true
+		}
+	}
+
+	pathName := kubeObjectsDifferentialCapturesPathName(v.instance.Namespace, v.instance.Name)
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		log := v.log.WithValues("profile", s3StoreAccessor.S3ProfileName)
+
+		keys, err := s3StoreAccessor.ObjectStorer.ListKeys(pathName)
+		if err != nil {
+			log.Error(err, "Kube objects differential captures list error")
+
+			continue
+		}
+
+		deleted := map[string]bool{}
+
+		for _, key := range keys {
+			sequence, _, _ := strings.Cut(strings.TrimPrefix(key, pathName), "/")
+			if referred[sequence] || deleted[sequence] {
+				continue
+			}
+
+			deleted[sequence] = true
+
+			if err := s3StoreAccessor.ObjectStorer.DeleteObjectsWithKeyPrefix(pathName + sequence + "/"); err != nil {
+				log.Error(err, "Kube objects differential capture delete error", "sequence", sequence)
+
+				continue
+			}
+
+			log.Info("Kube objects differential capture deleted", "sequence", sequence)
+		}
+	}
+}
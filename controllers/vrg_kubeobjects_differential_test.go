@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the sequences of the differential kube objects captures
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_KubeObjectsDifferentialCapture", func() {
+	var (
+		east, west memoryObjectStorer
+		v          *VRGInstance
+	)
+
+	log := ctrl.Log.WithName("vrg-kubeobjects-differential-test")
+	groupCaptureKey := func(sequence int64) string {
+		return kubeObjectsDifferentialCapturePathName("app", "vrg", sequence) + "velero/backups/app--vrg/manifest"
+	}
+
+	BeforeEach(func() {
+		east, west = memoryObjectStorer{}, memoryObjectStorer{}
+		v = &VRGInstance{
+			ctx:         context.TODO(),
+			log:         log,
+			instance:    &ramen.VolumeReplicationGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"}},
+			ramenConfig: &ramen.RamenConfig{},
+			s3StoreAccessors: []s3StoreAccessor{
+				{S3StoreProfile: ramen.S3StoreProfile{S3ProfileName: "east"}, ObjectStorer: east},
+				{S3StoreProfile: ramen.S3StoreProfile{S3ProfileName: "west"}, ObjectStorer: west},
+			},
+		}
+	})
+
+	It("starts with a full capture following the capture to recover from", func() {
+		v.instance.Status.KubeObjectProtection.CaptureToRecoverFrom = &ramen.KubeObjectsCaptureIdentifier{
+			Sequence: 3, FullSequence: 1,
+		}
+
+		inProgress, err := v.kubeObjectsCaptureInProgress(0, log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress.Sequence).To(BeEquivalentTo(4))
+		Expect(inProgress.FullSequence).To(BeEquivalentTo(4))
+	})
+
+	It("follows the last sequence in any s3 store, keeping the group captures another cluster refers to", func() {
+		east[groupCaptureKey(1)] = []byte("1")
+		west[groupCaptureKey(1)] = []byte("1")
+		west[groupCaptureKey(5)] = []byte("5")
+
+		inProgress, err := v.kubeObjectsCaptureInProgress(0, log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress.Sequence).To(BeEquivalentTo(6))
+		Expect(east).To(HaveKey(groupCaptureKey(1)))
+		Expect(west).To(HaveKey(groupCaptureKey(1)))
+		Expect(west).To(HaveKey(groupCaptureKey(5)))
+	})
+
+	It("continues the capture in progress, and restarts one of another capture number", func() {
+		inProgress, err := v.kubeObjectsCaptureInProgress(0, log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress.Sequence).To(BeEquivalentTo(1))
+
+		east[groupCaptureKey(1)] = []byte("1")
+		Expect(v.kubeObjectsCaptureInProgress(0, log)).To(BeIdenticalTo(inProgress))
+
+		inProgress, err = v.kubeObjectsCaptureInProgress(1, log)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inProgress.Sequence).To(BeEquivalentTo(2))
+	})
+
+	It("refers to the group captures of the capture to recover from only", func() {
+		identifier := &ramen.KubeObjectsCaptureIdentifier{Groups: []ramen.KubeObjectsCaptureGroupIdentifier{
+			{Name: "config", Sequence: 2}, {Name: "apps", Sequence: 4},
+		}}
+		Expect(kubeObjectsCaptureRefers(identifier, 2)).To(BeTrue())
+		Expect(kubeObjectsCaptureRefers(identifier, 3)).To(BeFalse())
+	})
+
+	It("deletes the group captures that no capture refers to", func() {
+		for _, sequence := range []int64{1, 2, 3} {
+			east[groupCaptureKey(sequence)] = []byte{}
+		}
+
+		v.kubeObjectsDifferentialCapturesDelete(&ramen.KubeObjectsCaptureIdentifier{
+			Groups: []ramen.KubeObjectsCaptureGroupIdentifier{{Name: "config", Sequence: 1}, {Name: "apps", Sequence: 3}},
+		}, nil)
+		Expect(east).To(HaveKey(groupCaptureKey(1)))
+		Expect(east).ToNot(HaveKey(groupCaptureKey(2)))
+		Expect(east).To(HaveKey(groupCaptureKey(3)))
+	})
+})
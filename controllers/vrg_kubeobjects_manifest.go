@@ -0,0 +1,282 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// kubeObjectsCaptureManifestName is the name of the manifest of a capture, stored in the capture's path
+const kubeObjectsCaptureManifestName = "manifest"
+
+// kubeObjectsCaptureManifest lists the objects of a capture, to check that a capture is complete and to diff
+// captures, without downloading their archives
+type kubeObjectsCaptureManifest struct {
+	Number    int64                             `json:"number"`
+	StartTime metav1.Time                       `json:"startTime"`
+	Objects   int                               `json:"objects"`
+	Kinds     map[string]int                    `json:"kinds"`
+	Checksum  string                            `json:"checksum"`
+	Groups    []kubeObjectsCaptureGroupManifest `json:"groups"`
+}
+
+type kubeObjectsCaptureGroupManifest struct {
+	Name     string         `json:"name"`
+	Objects  int            `json:"objects"`
+	Kinds    map[string]int `json:"kinds"`
+	Checksum string         `json:"checksum"`
+	// Resources are the namespace/name of the objects of each group/version/Kind
+	Resources map[string][]string `json:"resources"`
+	// ObjectChecksums are the SHA-256 of the content of each object, keyed by resource/namespace/name, or
+	// resource/name for a cluster-scoped object
+	ObjectChecksums map[string]string `json:"objectChecksums"`
+	// ImmutableFieldsChecksums are the SHA-256 of the immutable fields of the objects of the kinds with any, keyed as
+	// ObjectChecksums, for a restore preview to compare with those of the existing objects
+	ImmutableFieldsChecksums map[string]string `json:"immutableFieldsChecksums,omitempty"`
+}
+
+// kubeObjectsCaptureManifestsWrite writes the manifest of a complete capture to each s3 store, and sets the VRG's
+// manifest summary to that of the first. A manifest that fails to be built or written is logged, rather than
+// failing the capture.
+func (v *VRGInstance) kubeObjectsCaptureManifestsWrite(
+	captureNumber int64, pathName, capturePathName, namePrefix string, startTime metav1.Time,
+	log logr.Logger,
+) {
+	status := &v.instance.Status.KubeObjectProtection
+	if summary := status.LastCaptureManifest; summary != nil &&
+		summary.Number == captureNumber && summary.StartTime.Equal(&startTime) {
+		return
+	}
+
+	var summary *ramen.KubeObjectsCaptureManifestSummary
+
+	for i, s3StoreAccessor := range v.s3StoreAccessors {
+		log1 := log.WithValues("profile", s3StoreAccessor.S3ProfileName)
+
+		manifest, err := v.kubeObjectsCaptureManifest(
+			s3StoreAccessor, captureNumber, capturePathName, namePrefix, startTime)
+		if err != nil {
+			log1.Error(err, "Kube objects capture manifest build error")
+
+			return
+		}
+
+		if err := s3StoreAccessor.ObjectStorer.UploadObject(
+			pathName+kubeObjectsCaptureManifestName, manifest,
+		); err != nil {
+			log1.Error(err, "Kube objects capture manifest upload error")
+
+			return
+		}
+
+		log1.Info("Kube objects capture manifest uploaded", "objects", manifest.Objects, "checksum", manifest.Checksum)
+
+		if i == 0 {
+			summary = &ramen.KubeObjectsCaptureManifestSummary{
+				Number:    manifest.Number,
+				StartTime: manifest.StartTime,
+				Objects:   manifest.Objects,
+				Kinds:     manifest.Kinds,
+				Checksum:  manifest.Checksum,
+			}
+		}
+	}
+
+	status.LastCaptureManifest = summary
+}
+
+// kubeObjectsCaptureManifest builds the manifest of a capture from the lists of the resources its requests captured
+func (v *VRGInstance) kubeObjectsCaptureManifest(
+	s3StoreAccessor s3StoreAccessor, captureNumber int64, capturePathName, namePrefix string, startTime metav1.Time,
+) (*kubeObjectsCaptureManifest, error) {
+	manifest := &kubeObjectsCaptureManifest{
+		Number:    captureNumber,
+		StartTime: startTime,
+		Kinds:     map[string]int{},
+	}
+	groupsObjectChecksums := make([]map[string]string, 0, len(v.recipeElements.CaptureWorkflow))
+
+	for _, captureGroup := range v.recipeElements.CaptureWorkflow {
+		if kubeObjectsHookRunnable(captureGroup.Spec) != nil {
+			continue
+		}
+
+		groupCapturePathName, groupNamePrefix := capturePathName, namePrefix
+
+		// a group of a differential capture is captured in the path of its sequence, maybe that of a previous one
+		if captureInProgress := v.instance.Status.KubeObjectProtection.CaptureInProgress; captureInProgress != nil {
+			if group := kubeObjectsCaptureGroupFind(captureInProgress.Groups, captureGroup.Name); group != nil {
+				groupCapturePathName = kubeObjectsDifferentialCapturePathName(
+					v.instance.Namespace, v.instance.Name, group.Sequence) + v.reconciler.kubeObjects.ProtectsPath()
+				groupNamePrefix = group.NamePrefix
+			}
+		}
+
+		requestName := kubeObjectsCaptureName(groupNamePrefix, captureGroup.Name, s3StoreAccessor.S3ProfileName)
+		key := groupCapturePathName + v.reconciler.kubeObjects.ProtectedResourcesListKey(requestName)
+		resources := map[string][]string{}
+
+		if err := s3StoreAccessor.ObjectStorer.DownloadObject(key, &resources); err != nil {
+			return nil, fmt.Errorf("failed to download resources list of capture group %s (%w)", captureGroup.Name, err)
+		}
+
+		group := kubeObjectsCaptureGroupManifest{
+			Name:      captureGroup.Name,
+			Kinds:     map[string]int{},
+			Resources: resources,
+		}
+
+		for kind, names := range resources {
+			sort.Strings(names)
+
+			group.Kinds[kind] = len(names)
+			group.Objects += len(names)
+			manifest.Kinds[kind] += len(names)
+		}
+
+		archiveKey := groupCapturePathName + v.reconciler.kubeObjects.ProtectedResourcesArchiveKey(requestName)
+
+		archive, err := s3StoreAccessor.ObjectStorer.DownloadObjectRaw(archiveKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download archive of capture group %s (%w)", captureGroup.Name, err)
+		}
+
+		group.ObjectChecksums, group.ImmutableFieldsChecksums, err = kubeObjectsArchiveChecksums(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive of capture group %s (%w)", captureGroup.Name, err)
+		}
+
+		checksum, err := kubeObjectsChecksum(group.ObjectChecksums)
+		if err != nil {
+			return nil, err
+		}
+
+		group.Checksum = checksum
+		manifest.Objects += group.Objects
+		manifest.Groups = append(manifest.Groups, group)
+		groupsObjectChecksums = append(groupsObjectChecksums, group.ObjectChecksums)
+	}
+
+	checksum, err := kubeObjectsChecksum(groupsObjectChecksums)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest.Checksum = checksum
+
+	return manifest, nil
+}
+
+// kubeObjectsArchiveChecksums returns the checksum of the content of each object of a capture archive, a gzipped tar
+// with the JSON of each object at resources/<resource>/namespaces/<namespace>/<name>.json, or
+// resources/<resource>/cluster/<name>.json for a cluster-scoped object, and the checksum of the immutable fields of
+// each object of a kind with any. The copies of the objects in the directories of their API versions are skipped, so
+// each object is summed once, in its preferred version.
+func kubeObjectsArchiveChecksums(archive []byte) (map[string]string, map[string]string, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer gzipReader.Close()
+
+	checksums := map[string]string{}
+	immutableFieldsChecksums := map[string]string{}
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return checksums, immutableFieldsChecksums, nil
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, ok := kubeObjectsArchiveObjectKey(header)
+		if !ok {
+			continue
+		}
+
+		object := map[string]interface{}{}
+		if err := json.NewDecoder(tarReader).Decode(&object); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", header.Name, err)
+		}
+
+		resource, _, _ := strings.Cut(key, "/")
+		if immutable, ok := kubeObjectsImmutableFieldsOfResource(resource); ok {
+			immutableFieldsChecksums[key], err = kubeObjectsImmutableFieldsChecksum(object, immutable)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to sum the immutable fields of %s: %w", header.Name, err)
+			}
+		}
+
+		checksums[key], err = kubeObjectsChecksum(kubeObjectContent(object))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// kubeObjectsArchiveObjectKey returns the resource/namespace/name, or resource/name, of the object of an archive
+// file, or false if it is not an object outside the directories of the API versions
+func kubeObjectsArchiveObjectKey(header *tar.Header) (string, bool) {
+	if header.Typeflag != tar.TypeReg || path.Ext(header.Name) != ".json" {
+		return "", false
+	}
+
+	elements := strings.Split(strings.TrimSuffix(path.Clean(header.Name), ".json"), "/")
+
+	switch {
+	case len(elements) == 5 && elements[0] == "resources" && elements[2] == "namespaces":
+		return elements[1] + "/" + elements[3] + "/" + elements[4], true
+	case len(elements) == 4 && elements[0] == "resources" && elements[2] == "cluster":
+		return elements[1] + "/" + elements[3], true
+	}
+
+	return "", false
+}
+
+// kubeObjectContent returns an object without its status and the metadata the API server sets on every write, so
+// that captures of the same content have the same checksum
+func kubeObjectContent(object map[string]interface{}) map[string]interface{} {
+	delete(object, "status")
+
+	for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "generation", "managedFields",
+		"selfLink"} {
+		unstructured.RemoveNestedField(object, "metadata", field)
+	}
+
+	return object
+}
+
+// kubeObjectsChecksum returns the SHA-256 of the JSON encoding of objects, whose maps are encoded in key order
+func kubeObjectsChecksum(objects interface{}) (string, error) {
+	objectsJSON, err := json.Marshal(objects)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal captured objects: %w", err)
+	}
+
+	hash := sha256.Sum256(objectsJSON)
+
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the checksums of the objects of kube objects captures
+package controllers //nolint: testpackage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VRG_KubeObjectsCaptureManifest", func() {
+	archive := func(files map[string]string) []byte {
+		buffer := &bytes.Buffer{}
+		gzipWriter := gzip.NewWriter(buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+
+		for name, content := range files {
+			Expect(tarWriter.WriteHeader(&tar.Header{
+				Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content)),
+			})).To(Succeed())
+			_, err := tarWriter.Write([]byte(content))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+
+		return buffer.Bytes()
+	}
+	configMap := func(resourceVersion, mode string) string {
+		return `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"namespace":"app","name":"settings",` +
+			`"resourceVersion":"` + resourceVersion + `"},"data":{"mode":"` + mode + `"}}`
+	}
+	checksums := func(resourceVersion, mode string) map[string]string {
+		checksums, _, err := kubeObjectsArchiveChecksums(archive(map[string]string{
+			"metadata/version": "1",
+			"resources/configmaps/namespaces/app/settings.json":                     configMap(resourceVersion, mode),
+			"resources/configmaps/v1-preferredversion/namespaces/app/settings.json": configMap(resourceVersion, mode),
+			"resources/namespaces/cluster/app.json":                                 `{"kind":"Namespace"}`,
+		}))
+		Expect(err).ToNot(HaveOccurred())
+
+		return checksums
+	}
+
+	It("sums the content of each object once, regardless of the metadata the API server sets", func() {
+		primary := checksums("1", "primary")
+		Expect(primary).To(HaveLen(2))
+		Expect(primary).To(HaveKey("configmaps/app/settings"))
+		Expect(primary).To(HaveKey("namespaces/app"))
+
+		Expect(checksums("2", "primary")).To(Equal(primary))
+		Expect(checksums("1", "secondary")["configmaps/app/settings"]).ToNot(
+			Equal(primary["configmaps/app/settings"]))
+	})
+
+	It("sums the immutable fields of the objects of the kinds with any", func() {
+		_, immutableFieldsChecksums, err := kubeObjectsArchiveChecksums(archive(map[string]string{
+			"resources/configmaps/namespaces/app/settings.json": configMap("1", "primary"),
+			"resources/services/namespaces/app/lb.json": `{"apiVersion":"v1","kind":"Service",` +
+				`"metadata":{"namespace":"app","name":"lb"},"spec":{"clusterIP":"None"}}`,
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(immutableFieldsChecksums).To(HaveLen(1))
+		Expect(immutableFieldsChecksums).To(HaveKey("services/app/lb"))
+	})
+
+	It("fails for an archive that is not gzipped", func() {
+		_, _, err := kubeObjectsArchiveChecksums([]byte("{}"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -94,7 +94,7 @@ var _ = Describe("VRG_KubeObjectProtection", func() {
 					IncludeClusterResources: new(bool),
 				},
 			}
-			converted, err := convertRecipeHookToCaptureSpec(*hook, *hook.Ops[0])
+			converted, err := convertRecipeHookToCaptureSpec(*hook, *hook.Ops[0], kubeobjects.HookRun{})
 
 			Expect(err).To(BeNil())
 			Expect(converted).To(Equal(targetCaptureSpec))
@@ -123,12 +123,56 @@ var _ = Describe("VRG_KubeObjectProtection", func() {
 					IncludeClusterResources: new(bool),
 				},
 			}
-			converted, err := convertRecipeHookToRecoverSpec(*hook, *hook.Ops[0])
+			converted, err := convertRecipeHookToRecoverSpec(*hook, *hook.Ops[0], kubeobjects.HookRun{})
 
 			Expect(err).To(BeNil())
 			Expect(converted).To(Equal(targetRecoverSpec))
 		})
 
+		It("Hook of type job to its job, from the recipe's annotation", func() {
+			hook.Type = "job"
+			hook.OnError = "continue"
+			recipe := Recipe.Recipe{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					RecipeJobHooksAnnotation: `{"hook-single": {"image": "quiesce:latest"}}`,
+				},
+			}}
+			job, err := recipeHookJobSpec(recipe, *hook, *hook.Ops[0])
+
+			Expect(err).To(BeNil())
+			Expect(job.HookName).To(Equal(hook.Name))
+			Expect(job.Namespace).To(Equal(namespaceName))
+			Expect(job.Image).To(Equal("quiesce:latest"))
+			Expect(job.OnErrorContinue).To(BeTrue())
+		})
+
+		It("Hook of type job without an image in the recipe's annotation to an error", func() {
+			hook.Type = "job"
+			_, err := recipeHookJobSpec(Recipe.Recipe{}, *hook, *hook.Ops[0])
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Hook of type http to its endpoint, from the recipe's annotation", func() {
+			hook.Type = "http"
+			recipe := Recipe.Recipe{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					RecipeHTTPHooksAnnotation: `{"hook-single": {"url": "https://itsm.example.com/changes", ` +
+						`"secretName": "itsm-auth", "retries": 3, "expectedStatusCodes": [201]}}`,
+				},
+			}}
+			http, err := recipeHookHTTPSpec(recipe, *hook, *hook.Ops[0])
+
+			Expect(err).To(BeNil())
+			Expect(http.HookName).To(Equal(hook.Name))
+			Expect(http.URL).To(Equal("https://itsm.example.com/changes"))
+			Expect(http.SecretName).To(Equal("itsm-auth"))
+			Expect(http.Retries).To(Equal(int32(3)))
+			Expect(hookCallStatusCodeExpected(201, http.ExpectedStatusCodes)).To(BeTrue())
+			Expect(hookCallStatusCodeExpected(200, http.ExpectedStatusCodes)).To(BeFalse())
+			Expect(hookCallStatusCodeExpected(204, nil)).To(BeTrue())
+		})
+
 		It("Group to CaptureSpec", func() {
 			targetCaptureSpec := &kubeobjects.CaptureSpec{
 				Name: group.Name,
@@ -168,5 +212,48 @@ var _ = Describe("VRG_KubeObjectProtection", func() {
 			Expect(err).To(BeNil())
 			Expect(converted).To(Equal(targetRecoverSpec))
 		})
+
+		It("Group resources matched for differential capture as Velero does", func() {
+			deployments := metav1.APIResource{Name: "deployments", Kind: "Deployment", ShortNames: []string{"deploy"}}
+
+			Expect(kubeObjectsResourceMatch([]string{"deployments"}, deployments, "apps")).To(BeTrue())
+			Expect(kubeObjectsResourceMatch([]string{"deployments.apps"}, deployments, "apps")).To(BeTrue())
+			Expect(kubeObjectsResourceMatch([]string{"deploy"}, deployments, "apps")).To(BeTrue())
+			Expect(kubeObjectsResourceMatch([]string{"deployment"}, deployments, "apps")).To(BeTrue())
+			Expect(kubeObjectsResourceMatch([]string{"deployments.extensions"}, deployments, "apps")).To(BeFalse())
+			Expect(kubeObjectsResourceMatch([]string{"pods"}, deployments, "apps")).To(BeFalse())
+		})
+
+		It("Capture manifest kinds parsed to their group, version and kind", func() {
+			gvk, err := kubeObjectsManifestKindParse("apps/v1/Deployment")
+
+			Expect(err).To(BeNil())
+			Expect(gvk.Group).To(Equal("apps"))
+			Expect(gvk.Version).To(Equal("v1"))
+			Expect(gvk.Kind).To(Equal("Deployment"))
+
+			gvk, err = kubeObjectsManifestKindParse("v1/ConfigMap")
+
+			Expect(err).To(BeNil())
+			Expect(gvk.Group).To(BeEmpty())
+			Expect(gvk.Kind).To(Equal("ConfigMap"))
+
+			_, err = kubeObjectsManifestKindParse("ConfigMap")
+
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Group of cluster resources hashed empty, to be captured every differential capture", func() {
+			group.IncludeClusterResources = new(bool)
+			*group.IncludeClusterResources = true
+			captureSpec, err := convertRecipeGroupToCaptureSpec(*group)
+
+			Expect(err).To(BeNil())
+
+			hash, err := (&VRGInstance{}).kubeObjectsGroupHash(captureSpec.Spec)
+
+			Expect(err).To(BeNil())
+			Expect(hash).To(BeEmpty())
+		})
 	})
 })
@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	veleroPluginConfigLabelKey            = "velero.io/plugin-config"
+	kubeObjectsResourcePolicyNameSuffix   = "--resource-policy"
+	kubeObjectsResourceModifierNameSuffix = "--resource-modifier"
+	kubeObjectsItemActionConfigLabelKey   = "ramendr.openshift.io/velero-item-action"
+	kubeObjectsItemActionConfigPrefix     = "ramen-item-action-"
+)
+
+// kubeObjectsResourcePolicyName returns the name of the copy of a VRG's resource policy config map in the Velero
+// namespace
+func kubeObjectsResourcePolicyName(vrgNamespaceName, vrgName string) string {
+	return kubeObjectsVeleroConfigMapName(vrgNamespaceName, vrgName, kubeObjectsResourcePolicyNameSuffix)
+}
+
+// kubeObjectsResourceModifierName returns the name of the config map of the resource modifiers of a VRG's
+// recoveries in the Velero namespace
+func kubeObjectsResourceModifierName(vrgNamespaceName, vrgName string) string {
+	return kubeObjectsVeleroConfigMapName(vrgNamespaceName, vrgName, kubeObjectsResourceModifierNameSuffix)
+}
+
+// kubeObjectsVeleroConfigMapName returns the name of a VRG's config map in the Velero namespace. Names beyond the
+// length limit of a config map are shortened, with a hash of the VRG's namespace and name to keep them unique.
+func kubeObjectsVeleroConfigMapName(vrgNamespaceName, vrgName, nameSuffix string) string {
+	name := vrgNamespaceName + "--" + vrgName + nameSuffix
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(vrgNamespaceName + "/" + vrgName))
+	suffix := "-" + hex.EncodeToString(hash[:])[:16] + nameSuffix
+
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.") + suffix
+}
+
+func kubeObjectsItemActionConfigName(index int) string {
+	return kubeObjectsItemActionConfigPrefix + strconv.Itoa(index)
+}
+
+// kubeObjectsVolumesSpec returns how the captures of a VRG back up volume data and how long they are kept
+func (v *VRGInstance) kubeObjectsVolumesSpec() kubeobjects.VolumesSpec {
+	vrg := v.instance
+
+	veleroSpec := vrg.Spec.KubeObjectProtection.Velero
+	if veleroSpec == nil {
+		return kubeobjects.VolumesSpec{}
+	}
+
+	volumesSpec := kubeobjects.VolumesSpec{TTL: veleroSpec.BackupTTL}
+	enabled := true
+
+	switch veleroSpec.DefaultVolumeBackupMethod {
+	case ramen.VolumeBackupMethodSnapshot:
+		volumesSpec.SnapshotVolumes = &enabled
+	case ramen.VolumeBackupMethodFsBackup:
+		volumesSpec.DefaultVolumesToFsBackup = &enabled
+	}
+
+	if veleroSpec.ResourcePolicyConfigMapName != "" {
+		volumesSpec.ResourcePolicyName = kubeObjectsResourcePolicyName(vrg.Namespace, vrg.Name)
+	}
+
+	return volumesSpec
+}
+
+// kubeObjectsVeleroConfigMapsApply copies a VRG's resource policy configuration to a config map in the Velero
+// namespace, or deletes it once no longer specified, deploys the resource modifiers of its recoveries while
+// recovering, including those that gate the workloads whose networks are mapped and those of the workloads of the
+// autoscalers recovered so far, or deletes them otherwise, and
+// applies the item action configurations of the operator's configuration
+func (v *VRGInstance) kubeObjectsVeleroConfigMapsApply(recovering bool) error {
+	vrg := v.instance
+	veleroNamespaceName := v.veleroNamespaceName()
+	desired := map[string]*corev1.ConfigMap{}
+
+	if veleroSpec := vrg.Spec.KubeObjectProtection.Velero; veleroSpec != nil &&
+		veleroSpec.ResourcePolicyConfigMapName != "" {
+		source := &corev1.ConfigMap{}
+		key := types.NamespacedName{Namespace: vrg.Namespace, Name: veleroSpec.ResourcePolicyConfigMapName}
+
+		if err := v.reconciler.Get(v.ctx, key, source); err != nil {
+			return fmt.Errorf("failed to get Velero resource policy config map %s (%w)", key, err)
+		}
+
+		configMap := v.kubeObjectsVeleroConfigMap(veleroNamespaceName,
+			kubeObjectsResourcePolicyName(vrg.Namespace, vrg.Name), util.OwnerLabels(vrg))
+		configMap.Data = source.Data
+		desired[configMap.Name] = configMap
+	}
+
+	if recovering {
+		scaleTargetsResourceModifiers, err := v.autoscalersScaleTargetsResourceModifiers()
+		if err != nil {
+			return err
+		}
+
+		configMap := v.kubeObjectsVeleroConfigMap(veleroNamespaceName,
+			kubeObjectsResourceModifierName(vrg.Namespace, vrg.Name), util.OwnerLabels(vrg))
+		configMap.Data = map[string]string{
+			"resource-modifiers.yaml": cronJobsSuspendResourceModifiers + v.networksMappedResourceModifiers() +
+				v.volumesReadyResourceModifiers() + scaleTargetsResourceModifiers,
+		}
+		desired[configMap.Name] = configMap
+	}
+
+	for _, configMap := range desired {
+		if err := v.kubeObjectsVeleroConfigMapApply(configMap); err != nil {
+			return err
+		}
+	}
+
+	if err := v.kubeObjectsVeleroConfigMapsDelete(util.OwnerLabels(vrg), func(name string) bool {
+		_, ok := desired[name]
+
+		return !ok
+	}); err != nil {
+		return err
+	}
+
+	return v.kubeObjectsItemActionConfigMapsApply()
+}
+
+// kubeObjectsItemActionConfigMapsApply deploys the item action configurations of the operator's configuration as
+// config maps to the Velero namespace, and deletes those no longer configured. They apply to all the backups or
+// restores of Velero, so they are not owned by a VRG.
+func (v *VRGInstance) kubeObjectsItemActionConfigMapsApply() error {
+	labels := map[string]string{kubeObjectsItemActionConfigLabelKey: ""}
+	desired := map[string]struct{}{}
+
+	for i, itemActionConfig := range v.ramenConfig.VeleroItemActionConfigs {
+		configMap := v.kubeObjectsVeleroConfigMap(v.veleroNamespaceName(), kubeObjectsItemActionConfigName(i),
+			map[string]string{
+				kubeObjectsItemActionConfigLabelKey: "",
+				veleroPluginConfigLabelKey:          "",
+				itemActionConfig.Plugin:             string(itemActionConfig.Kind),
+			})
+		configMap.Data = itemActionConfig.Data
+
+		if err := v.kubeObjectsVeleroConfigMapApply(configMap); err != nil {
+			return err
+		}
+
+		desired[configMap.Name] = struct{}{}
+	}
+
+	return v.kubeObjectsVeleroConfigMapsDelete(labels, func(name string) bool {
+		_, ok := desired[name]
+
+		return !ok
+	})
+}
+
+func (v *VRGInstance) kubeObjectsVeleroConfigMap(namespaceName, name string, labels map[string]string,
+) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespaceName,
+			Name:      name,
+			Labels:    labels,
+		},
+	}
+}
+
+func (v *VRGInstance) kubeObjectsVeleroConfigMapApply(configMap *corev1.ConfigMap) error {
+	key := client.ObjectKeyFromObject(configMap)
+	current := &corev1.ConfigMap{}
+
+	if err := v.reconciler.Get(v.ctx, key, current); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Velero config map %s (%w)", key, err)
+		}
+
+		if err := v.reconciler.Create(v.ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create Velero config map %s (%w)", key, err)
+		}
+
+		v.log.Info("Velero config map created", "configMap", key.String())
+
+		return nil
+	}
+
+	if reflect.DeepEqual(current.Labels, configMap.Labels) && reflect.DeepEqual(current.Data, configMap.Data) {
+		return nil
+	}
+
+	current.Labels = configMap.Labels
+	current.Data = configMap.Data
+
+	if err := v.reconciler.Update(v.ctx, current); err != nil {
+		return fmt.Errorf("failed to update Velero config map %s (%w)", key, err)
+	}
+
+	v.log.Info("Velero config map updated", "configMap", key.String())
+
+	return nil
+}
+
+// kubeObjectsVeleroConfigMapsDelete deletes the config maps with the labels passed in, in the Velero namespace, whose
+// names are selected
+func (v *VRGInstance) kubeObjectsVeleroConfigMapsDelete(labels map[string]string, selected func(string) bool) error {
+	configMaps := &corev1.ConfigMapList{}
+
+	if err := v.reconciler.List(v.ctx, configMaps,
+		client.InNamespace(v.veleroNamespaceName()),
+		client.MatchingLabels(labels),
+	); err != nil {
+		return fmt.Errorf("failed to list Velero config maps (%w)", err)
+	}
+
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if !selected(configMap.Name) {
+			continue
+		}
+
+		if err := v.reconciler.Delete(v.ctx, configMap); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Velero config map %s (%w)", configMap.Name, err)
+		}
+
+		v.log.Info("Velero config map deleted", "configMap", configMap.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the Velero config maps of the kube object captures and recoveries
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_KubeObjectsVeleroConfigMaps", func() {
+	Describe("kubeObjectsResourcePolicyName", func() {
+		It("keeps names within the length limit of a config map, unique to the VRG", func() {
+			Expect(kubeObjectsResourcePolicyName("app", "vrg")).To(Equal("app--vrg--resource-policy"))
+
+			namespaceName := strings.Repeat("n", validation.DNS1123LabelMaxLength)
+			vrgName := strings.Repeat("v.", validation.DNS1123SubdomainMaxLength/2)
+			name := kubeObjectsResourcePolicyName(namespaceName, vrgName)
+			Expect(name).To(HaveLen(validation.DNS1123SubdomainMaxLength - 1))
+			Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+			Expect(kubeObjectsResourcePolicyName(namespaceName, vrgName+"x")).ToNot(Equal(name))
+		})
+	})
+
+	Describe("kubeObjectsVeleroConfigMapsApply", func() {
+		var (
+			vrg         *ramen.VolumeReplicationGroup
+			ramenConfig *ramen.RamenConfig
+			reconciler  *VolumeReplicationGroupReconciler
+		)
+
+		vrgInstance := func() *VRGInstance {
+			return &VRGInstance{
+				reconciler:  reconciler,
+				ctx:         context.TODO(),
+				log:         ctrl.Log.WithName("vrg-kubeobjects-velero-test"),
+				instance:    vrg,
+				ramenConfig: ramenConfig,
+			}
+		}
+		veleroConfigMapNames := func() []string {
+			configMaps := &corev1.ConfigMapList{}
+			Expect(reconciler.List(context.TODO(), configMaps,
+				client.InNamespace(VeleroNamespaceNameDefault))).To(Succeed())
+
+			names := []string{}
+			for _, configMap := range configMaps.Items {
+				names = append(names, configMap.Name)
+			}
+
+			return names
+		}
+
+		BeforeEach(func() {
+			vrg = &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+				Spec: ramen.VolumeReplicationGroupSpec{KubeObjectProtection: &ramen.KubeObjectProtectionSpec{
+					Velero: &ramen.KubeObjectVeleroSpec{ResourcePolicyConfigMapName: "policies"},
+				}},
+			}
+			ramenConfig = &ramen.RamenConfig{VeleroItemActionConfigs: []ramen.VeleroItemActionConfig{{
+				Plugin: "velero.io/change-storage-class",
+				Kind:   ramen.VeleroItemActionKindRestore,
+				Data:   map[string]string{"standard": "fast"},
+			}}}
+			reconciler = &VolumeReplicationGroupReconciler{
+				Client: fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "policies"},
+					Data:       map[string]string{"policies.yaml": "version: v1"},
+				}).Build(),
+			}
+		})
+
+		It("copies the resource policies of the VRG, and deploys the item actions of the configuration", func() {
+			Expect(vrgInstance().kubeObjectsVeleroConfigMapsApply(false)).To(Succeed())
+			Expect(veleroConfigMapNames()).To(ConsistOf("app--vrg--resource-policy", "ramen-item-action-0"))
+
+			configMap := &corev1.ConfigMap{}
+			Expect(reconciler.Get(context.TODO(), client.ObjectKey{
+				Namespace: VeleroNamespaceNameDefault, Name: "ramen-item-action-0",
+			}, configMap)).To(Succeed())
+			Expect(configMap.Labels).To(HaveKeyWithValue("velero.io/change-storage-class", "RestoreItemAction"))
+			Expect(configMap.Labels).To(HaveKey(veleroPluginConfigLabelKey))
+			Expect(configMap.Data).To(Equal(map[string]string{"standard": "fast"}))
+		})
+
+		It("deploys the resource modifiers while recovering only", func() {
+			Expect(vrgInstance().kubeObjectsVeleroConfigMapsApply(true)).To(Succeed())
+			Expect(veleroConfigMapNames()).To(ContainElement("app--vrg--resource-modifier"))
+
+			Expect(vrgInstance().kubeObjectsVeleroConfigMapsApply(false)).To(Succeed())
+			Expect(veleroConfigMapNames()).ToNot(ContainElement("app--vrg--resource-modifier"))
+		})
+
+		It("deletes the config maps no longer specified or configured", func() {
+			Expect(vrgInstance().kubeObjectsVeleroConfigMapsApply(false)).To(Succeed())
+
+			vrg.Spec.KubeObjectProtection.Velero = nil
+			ramenConfig.VeleroItemActionConfigs = nil
+			Expect(vrgInstance().kubeObjectsVeleroConfigMapsApply(false)).To(Succeed())
+			Expect(veleroConfigMapNames()).To(BeEmpty())
+		})
+	})
+
+	Describe("veleroItemActionConfigsValidate", func() {
+		It("requires a plugin to be configured once, with a valid kind", func() {
+			itemActionConfigs := []ramen.VeleroItemActionConfig{
+				{Plugin: "velero.io/change-storage-class", Kind: ramen.VeleroItemActionKindRestore},
+				{Plugin: "velero.io/change-image-name", Kind: ramen.VeleroItemActionKindRestore},
+			}
+			Expect(veleroItemActionConfigsValidate(itemActionConfigs)).To(BeEmpty())
+
+			itemActionConfigs = append(itemActionConfigs,
+				ramen.VeleroItemActionConfig{Plugin: "velero.io/change-storage-class", Kind: "ItemAction"})
+			Expect(veleroItemActionConfigsValidate(itemActionConfigs)).To(HaveLen(2))
+		})
+	})
+})
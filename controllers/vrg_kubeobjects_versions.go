@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// kubeObjectsAPIVersionPrioritiesConfigMapName is the name of the config map of Velero's EnableAPIGroupVersions
+	// feature, in the Velero namespace, of the versions to restore the objects of resources at
+	kubeObjectsAPIVersionPrioritiesConfigMapName = "enableapigroupversions"
+	kubeObjectsAPIVersionPrioritiesKey           = "restoreResourcesVersionPriority"
+
+	// kubeObjectsAPIVersionPrioritiesLabel marks the config map as written by the VRGs, which do not overwrite a
+	// config map without it
+	kubeObjectsAPIVersionPrioritiesLabel = "ramendr.openshift.io/api-version-priorities"
+
+	// veleroFeatureAPIGroupVersions is the Velero feature that captures the objects of a kind at every version the
+	// cluster serves, and restores them at one the cluster recovered to serves
+	veleroFeatureAPIGroupVersions = "EnableAPIGroupVersions"
+)
+
+// kubeObjectsAPIVersionPrioritiesDefault are the versions of the resources whose objects are compatible across
+// their versions, which a capture of a cluster serving several of them has each of
+var kubeObjectsAPIVersionPrioritiesDefault = map[string][]string{
+	"cronjobs.batch":                       {"v1", "v1beta1"},
+	"endpointslices.discovery.k8s.io":      {"v1", "v1beta1"},
+	"horizontalpodautoscalers.autoscaling": {"v2", "v2beta2", "v1"},
+	"poddisruptionbudgets.policy":          {"v1", "v1beta1"},
+}
+
+// kubeObjectsAPIVersionPrioritiesParse parses lines of <resource>.<group>=<version>,<version>
+func kubeObjectsAPIVersionPrioritiesParse(priorities string) (map[string][]string, error) {
+	parsed := map[string][]string{}
+
+	for _, line := range strings.Split(priorities, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		resource, versions, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(resource) == "" || strings.TrimSpace(versions) == "" {
+			return nil, fmt.Errorf("line %q is not <resource>.<group>=<version>,<version>", line)
+		}
+
+		for _, version := range strings.Split(versions, ",") {
+			if version = strings.TrimSpace(version); version == "" {
+				return nil, fmt.Errorf("line %q has an empty version", line)
+			}
+
+			parsed[strings.TrimSpace(resource)] = append(parsed[strings.TrimSpace(resource)], version)
+		}
+	}
+
+	return parsed, nil
+}
+
+// kubeObjectsAPIVersionPriorities returns the version priorities of the known resources, overridden and extended by
+// those of the operator configuration, in Velero's format
+func (v *VRGInstance) kubeObjectsAPIVersionPriorities() (string, error) {
+	configured, err := kubeObjectsAPIVersionPrioritiesParse(v.ramenConfig.KubeObjectProtection.APIVersionPriorities)
+	if err != nil {
+		return "", err
+	}
+
+	priorities := map[string][]string{}
+
+	for resource, versions := range kubeObjectsAPIVersionPrioritiesDefault {
+		priorities[resource] = versions
+	}
+
+	for resource, versions := range configured {
+		priorities[resource] = versions
+	}
+
+	lines := make([]string, 0, len(priorities))
+	for resource, versions := range priorities {
+		lines = append(lines, resource+"="+strings.Join(versions, ","))
+	}
+
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// kubeObjectsAPIVersionPrioritiesApply writes the version priorities to Velero's enableapigroupversions config map,
+// for Velero to restore the objects of a resource at a version this cluster serves when it does not serve the one
+// they were captured at. A config map not written by the VRGs is left as is.
+func (v *VRGInstance) kubeObjectsAPIVersionPrioritiesApply() error {
+	priorities, err := v.kubeObjectsAPIVersionPriorities()
+	if err != nil {
+		return err
+	}
+
+	key := types.NamespacedName{
+		Namespace: v.veleroNamespaceName(),
+		Name:      kubeObjectsAPIVersionPrioritiesConfigMapName,
+	}
+	configMap := &corev1.ConfigMap{}
+
+	if err := v.reconciler.APIReader.Get(v.ctx, key, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Velero config map %s (%w)", key, err)
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels:    map[string]string{kubeObjectsAPIVersionPrioritiesLabel: ""},
+			},
+			Data: map[string]string{kubeObjectsAPIVersionPrioritiesKey: priorities},
+		}
+
+		if err := v.reconciler.Create(v.ctx, configMap); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create Velero config map %s (%w)", key, err)
+		}
+
+		v.log.Info("Velero API version priorities config map created", "configMap", key.String())
+
+		return nil
+	}
+
+	if _, ok := configMap.GetLabels()[kubeObjectsAPIVersionPrioritiesLabel]; !ok ||
+		configMap.Data[kubeObjectsAPIVersionPrioritiesKey] == priorities {
+		return nil
+	}
+
+	configMap.Data = map[string]string{kubeObjectsAPIVersionPrioritiesKey: priorities}
+
+	if err := v.reconciler.Update(v.ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update Velero config map %s (%w)", key, err)
+	}
+
+	v.log.Info("Velero API version priorities config map updated", "configMap", key.String())
+
+	return nil
+}
+
+// kubeObjectVersionsCheck checks the kinds of the objects of the capture to recover from against the versions this
+// cluster serves, and reports in the KubeObjectVersionsSupported condition the kinds served at other versions only,
+// which Velero restores at a version this cluster serves, and the kinds not served at all, whose objects fail to be
+// recovered. It does not fail the recovery.
+func (v *VRGInstance) kubeObjectVersionsCheck() {
+	if v.kubeObjectProtectionDisabled("versions check") {
+		return
+	}
+
+	condition := meta.FindStatusCondition(v.instance.Status.Conditions, VRGConditionTypeKubeObjectVersionsSupported)
+	if condition != nil && condition.ObservedGeneration == v.instance.Generation {
+		return
+	}
+
+	manifest, err := v.kubeObjectsRecoverManifestDownload()
+	if err != nil {
+		v.log.Info("Kube object versions check failed", "error", err)
+		setVRGKubeObjectVersionsUncheckedCondition(&v.instance.Status.Conditions, v.instance.Generation,
+			fmt.Sprintf("Kinds of the kube objects not checked: %v", err))
+
+		return
+	}
+
+	kinds := make([]string, 0, len(manifest.Kinds))
+	for kind := range manifest.Kinds {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	converted, unsupported := []string{}, []string{}
+
+	for _, kind := range kinds {
+		gvk, err := kubeObjectsManifestKindParse(kind)
+		if err != nil {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%v)", kind, err))
+
+			continue
+		}
+
+		if _, err := v.reconciler.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			continue
+		}
+
+		mapping, err := v.reconciler.RESTMapper().RESTMapping(gvk.GroupKind())
+		if err != nil {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%d objects)", kind, manifest.Kinds[kind]))
+
+			continue
+		}
+
+		converted = append(converted, fmt.Sprintf("%s as %s", kind, mapping.GroupVersionKind.Version))
+	}
+
+	msg := "Kinds of the kube objects are served at the versions they were captured at"
+
+	if len(converted) != 0 {
+		enabled, err := v.veleroFeatureEnabled(veleroFeatureAPIGroupVersions)
+
+		switch {
+		case err != nil:
+			msg = fmt.Sprintf("Kinds of the kube objects are served at other versions, which Velero restores them "+
+				"at only if its %s feature is enabled on both clusters, which failed to be checked on this one (%v): %s",
+				veleroFeatureAPIGroupVersions, err, strings.Join(converted, ", "))
+		case !enabled:
+			unsupported = append(unsupported, fmt.Sprintf("%s, served at other versions, which Velero restores "+
+				"them at only with its %s feature, not enabled on this cluster", strings.Join(converted, ", "),
+				veleroFeatureAPIGroupVersions))
+			converted = nil
+		default:
+			msg = fmt.Sprintf("Kinds of the kube objects are served at other versions, which Velero restores them "+
+				"at with its %s feature, if enabled on the cluster they were captured on as well: %s",
+				veleroFeatureAPIGroupVersions, strings.Join(converted, ", "))
+		}
+	}
+
+	if len(unsupported) != 0 {
+		msg = "Kinds of the kube objects are not served, and their objects fail to be recovered: " +
+			strings.Join(unsupported, ", ")
+
+		if len(converted) != 0 {
+			msg += "; served at other versions: " + strings.Join(converted, ", ")
+		}
+
+		v.log.Info(msg)
+		rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonKubeObjectVersionsUnsupported, msg)
+	}
+
+	setVRGKubeObjectVersionsSupportedCondition(&v.instance.Status.Conditions, v.instance.Generation,
+		len(unsupported) == 0, msg)
+}
+
+// veleroFeatureEnabled returns whether the Velero server of this cluster is run with a feature enabled
+func (v *VRGInstance) veleroFeatureEnabled(feature string) (bool, error) {
+	deployment := &appsv1.Deployment{}
+	key := types.NamespacedName{Namespace: v.veleroNamespaceName(), Name: veleroDeploymentName}
+
+	if err := v.reconciler.Get(v.ctx, key, deployment); err != nil {
+		return false, fmt.Errorf("failed to get Velero deployment %s (%w)", key, err)
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == veleroDeploymentName {
+			return veleroFeaturesEnabled(container.Args, feature), nil
+		}
+	}
+
+	return false, fmt.Errorf("no %s container in Velero deployment %s", veleroDeploymentName, key)
+}
+
+// veleroFeaturesEnabled returns whether the arguments of the Velero server enable a feature, in its --features
+// comma separated list
+func veleroFeaturesEnabled(args []string, feature string) bool {
+	for i, arg := range args {
+		features, ok := strings.CutPrefix(arg, "--features=")
+		if !ok && arg == "--features" && i+1 < len(args) {
+			features, ok = args[i+1], true
+		}
+
+		if ok && containsString(strings.Split(features, ","), feature) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// kubeObjectsRecoverManifestDownload downloads the manifest of the capture to recover from, from the first accessible
+// S3 store
+func (v *VRGInstance) kubeObjectsRecoverManifestDownload() (*kubeObjectsCaptureManifest, error) {
+	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		objectStore, _, err := v.reconciler.ObjStoreGetter.ObjectStore(
+			v.ctx, v.reconciler.APIReader, s3ProfileName, v.namespacedName, v.log)
+		if err != nil {
+			v.log.Info("Object store inaccessible for kube object versions check", "profile", s3ProfileName,
+				"error", err)
+
+			continue
+		}
+
+		return v.kubeObjectsRestorePreviewManifest(objectStore)
+	}
+
+	return nil, fmt.Errorf("no accessible S3 store in profiles %v", v.instance.Spec.S3Profiles)
+}
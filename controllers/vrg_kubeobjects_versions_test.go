@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the versions Velero restores the kube objects at
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_KubeObjectsVersions", func() {
+	DescribeTable("kubeObjectsAPIVersionPrioritiesParse",
+		func(priorities string, expected map[string][]string) {
+			Expect(kubeObjectsAPIVersionPrioritiesParse(priorities)).To(Equal(expected))
+		},
+		Entry("none", "", map[string][]string{}),
+		Entry("the versions of resources, ignoring spaces and empty lines",
+			"rockbands.music.example.io=v2,v1\n\n  widgets.example.io = v1beta2 , v1beta1 \n",
+			map[string][]string{
+				"rockbands.music.example.io": {"v2", "v1"},
+				"widgets.example.io":         {"v1beta2", "v1beta1"},
+			}),
+	)
+
+	DescribeTable("kubeObjectsAPIVersionPrioritiesParse of invalid priorities",
+		func(priorities, expected string) {
+			_, err := kubeObjectsAPIVersionPrioritiesParse(priorities)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("a line without versions", "rockbands.music.example.io", "is not <resource>.<group>="),
+		Entry("a line without a resource", "=v1", "is not <resource>.<group>="),
+		Entry("an empty version", "rockbands.music.example.io=v2,,v1", "has an empty version"),
+	)
+
+	Describe("kubeObjectsAPIVersionPriorities", func() {
+		vrgInstance := func(priorities string) *VRGInstance {
+			ramenConfig := &ramen.RamenConfig{}
+			ramenConfig.KubeObjectProtection.APIVersionPriorities = priorities
+
+			return &VRGInstance{ramenConfig: ramenConfig}
+		}
+
+		It("returns the version priorities of the known resources in Velero's format", func() {
+			Expect(vrgInstance("").kubeObjectsAPIVersionPriorities()).To(Equal(
+				"cronjobs.batch=v1,v1beta1\n" +
+					"endpointslices.discovery.k8s.io=v1,v1beta1\n" +
+					"horizontalpodautoscalers.autoscaling=v2,v2beta2,v1\n" +
+					"poddisruptionbudgets.policy=v1,v1beta1\n"))
+		})
+
+		It("overrides and extends them with those of the operator configuration", func() {
+			Expect(vrgInstance("rockbands.music.example.io=v2,v1\ncronjobs.batch=v1").kubeObjectsAPIVersionPriorities()).
+				To(Equal("cronjobs.batch=v1\n" +
+					"endpointslices.discovery.k8s.io=v1,v1beta1\n" +
+					"horizontalpodautoscalers.autoscaling=v2,v2beta2,v1\n" +
+					"poddisruptionbudgets.policy=v1,v1beta1\n" +
+					"rockbands.music.example.io=v2,v1\n"))
+		})
+
+		It("returns an error for invalid priorities of the operator configuration", func() {
+			_, err := vrgInstance("rockbands.music.example.io").kubeObjectsAPIVersionPriorities()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	DescribeTable("veleroFeaturesEnabled",
+		func(args []string, enabled bool) {
+			Expect(veleroFeaturesEnabled(args, veleroFeatureAPIGroupVersions)).To(Equal(enabled))
+		},
+		Entry("without features", []string{"server", "--uploader-type=kopia"}, false),
+		Entry("a list of features that includes it", []string{"server", "--features=EnableCSI,EnableAPIGroupVersions"},
+			true),
+		Entry("a list of features as the next argument", []string{"server", "--features", "EnableAPIGroupVersions"},
+			true),
+		Entry("a list of other features", []string{"server", "--features=EnableCSI"}, false),
+	)
+
+	Describe("veleroFeatureEnabled", func() {
+		It("returns whether the Velero deployment's server enables a feature", func() {
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: VeleroNamespaceNameDefault, Name: veleroDeploymentName,
+			}}
+			deployment.Spec.Template.Spec.Containers = []corev1.Container{
+				{Name: "sidecar", Args: []string{"--features=" + veleroFeatureAPIGroupVersions}},
+				{Name: veleroDeploymentName, Args: []string{"server"}},
+			}
+			c := fake.NewClientBuilder().WithObjects(deployment).Build()
+			v := &VRGInstance{
+				reconciler:  &VolumeReplicationGroupReconciler{Client: c, APIReader: c},
+				ctx:         context.TODO(),
+				log:         ctrl.Log.WithName("vrg-kubeobjects-versions-test"),
+				ramenConfig: &ramen.RamenConfig{},
+			}
+
+			Expect(v.veleroFeatureEnabled(veleroFeatureAPIGroupVersions)).To(BeFalse())
+
+			deployment.Spec.Template.Spec.Containers[1].Args = append(deployment.Spec.Template.Spec.Containers[1].Args,
+				"--features="+veleroFeatureAPIGroupVersions)
+			Expect(c.Update(context.TODO(), deployment)).To(Succeed())
+			Expect(v.veleroFeatureEnabled(veleroFeatureAPIGroupVersions)).To(BeTrue())
+
+			v.ramenConfig.KubeObjectProtection.VeleroNamespaceName = "oadp"
+			_, err := v.veleroFeatureEnabled(veleroFeatureAPIGroupVersions)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,315 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// VRGLocalCacheLabel labels the config maps, in the ramen operator's namespace, that cache the last applied spec
+	// of a VRG and the S3 profiles it uses, for the VRG to be promoted without the hub
+	VRGLocalCacheLabel = "ramendr.openshift.io/vrg-local-cache"
+
+	// VRGLocalCachePromoteAnnotation, set to "true" on the local cache of a VRG by an administrator while the hub
+	// is not reachable, promotes the VRG to Primary with a failover, creating it from the cache if it is not found
+	VRGLocalCachePromoteAnnotation = "ramendr.openshift.io/promote"
+
+	// vrgLocalCacheDeletedAnnotation is set on the local cache of a VRG to the time the VRG was deleted. The cache
+	// is kept, as the VRG may have been deleted because the hub is lost.
+	vrgLocalCacheDeletedAnnotation = "ramendr.openshift.io/vrg-deleted"
+
+	// vrgLocalCachePromotedAnnotation is set on the local cache of a VRG promoted from it to the generation of the
+	// VRG it promoted. Until a later generation of the VRG is Primary, as once the hub fails it over to this cluster,
+	// the VRG is promoted again whenever the hub reapplies it as Secondary.
+	vrgLocalCachePromotedAnnotation = "ramendr.openshift.io/vrg-promoted-generation"
+
+	vrgLocalCacheNamePrefix = "ramen-vrg-cache-"
+
+	// vrgLocalCacheNameHashLength is the length of the hash of the VRG's namespace and name suffixed to the name of
+	// its local cache, for the names of the caches of distinct VRGs not to collide once joined or truncated
+	vrgLocalCacheNameHashLength = 16
+
+	vrgLocalCacheKeyVRG             = "vrg"
+	vrgLocalCacheKeyS3StoreProfiles = "s3StoreProfiles"
+
+	lastAppliedConfigurationAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// vrgLocalCacheKey returns the key of the local cache of a VRG, named after the VRG's namespace and name, truncated
+// for the name not to exceed the length of an object name, and suffixed with a hash of them
+func vrgLocalCacheKey(vrgNamespacedName types.NamespacedName) types.NamespacedName {
+	hash := sha256.Sum256([]byte(vrgNamespacedName.String()))
+	suffix := "-" + hex.EncodeToString(hash[:])[:vrgLocalCacheNameHashLength]
+
+	name := vrgNamespacedName.Namespace + "-" + vrgNamespacedName.Name
+	if maxLength := validation.DNS1123SubdomainMaxLength - len(vrgLocalCacheNamePrefix) - len(suffix); len(name) >
+		maxLength {
+		name = strings.TrimRight(name[:maxLength], "-.")
+	}
+
+	return types.NamespacedName{
+		Namespace: RamenOperatorNamespace(),
+		Name:      vrgLocalCacheNamePrefix + name + suffix,
+	}
+}
+
+// localCacheUpdate caches the spec of the VRG, and the S3 profiles it uses, in a config map of the ramen operator's
+// namespace, when they change. A failure to cache does not fail the reconcile.
+func (v *VRGInstance) localCacheUpdate() {
+	data, err := v.localCacheData()
+	if err != nil {
+		v.log.Info("Local cache encode failed", "error", err)
+
+		return
+	}
+
+	key := vrgLocalCacheKey(client.ObjectKeyFromObject(v.instance))
+	labels := rmnutil.OwnerLabels(v.instance)
+	labels[VRGLocalCacheLabel] = "true"
+
+	configMap := &corev1.ConfigMap{}
+	if err := v.reconciler.Client.Get(v.ctx, key, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			v.log.Info("Local cache get failed", "error", err)
+
+			return
+		}
+
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Labels: labels},
+			Data:       data,
+		}
+
+		if err := v.reconciler.Create(v.ctx, configMap); err != nil {
+			v.log.Info("Local cache create failed", "error", err)
+
+			return
+		}
+
+		v.log.Info("Local cache created", "configMap", key.String())
+
+		return
+	}
+
+	_, deleted := configMap.GetAnnotations()[vrgLocalCacheDeletedAnnotation]
+	if !deleted && reflect.DeepEqual(configMap.Data, data) {
+		return
+	}
+
+	rmnutil.ObjectLabelsSet(configMap, labels)
+	delete(configMap.Annotations, vrgLocalCacheDeletedAnnotation)
+	configMap.Data = data
+
+	if err := v.reconciler.Update(v.ctx, configMap); err != nil {
+		v.log.Info("Local cache update failed", "error", err)
+
+		return
+	}
+
+	v.log.Info("Local cache updated", "configMap", key.String())
+}
+
+func (v *VRGInstance) localCacheData() (map[string]string, error) {
+	vrg := &ramen.VolumeReplicationGroup{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "VolumeReplicationGroup",
+			APIVersion: ramen.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   v.instance.Namespace,
+			Name:        v.instance.Name,
+			Labels:      v.instance.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: v.instance.Spec,
+	}
+
+	for key, value := range v.instance.GetAnnotations() {
+		if key != lastAppliedConfigurationAnnotation {
+			vrg.Annotations[key] = value
+		}
+	}
+
+	s3StoreProfiles := []ramen.S3StoreProfile{}
+
+	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+		if s3StoreProfile := RamenConfigS3StoreProfilePointerGet(v.ramenConfig, s3ProfileName); s3StoreProfile != nil {
+			s3StoreProfiles = append(s3StoreProfiles, *s3StoreProfile)
+		}
+	}
+
+	vrgJSON, err := json.Marshal(vrg)
+	if err != nil {
+		return nil, err
+	}
+
+	s3StoreProfilesJSON, err := json.Marshal(s3StoreProfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		vrgLocalCacheKeyVRG:             string(vrgJSON),
+		vrgLocalCacheKeyS3StoreProfiles: string(s3StoreProfilesJSON),
+	}, nil
+}
+
+// localCacheDeletedMark annotates the local cache of a deleted VRG with the time it was deleted
+func (v *VRGInstance) localCacheDeletedMark() {
+	key := vrgLocalCacheKey(client.ObjectKeyFromObject(v.instance))
+
+	configMap := &corev1.ConfigMap{}
+	if err := v.reconciler.Client.Get(v.ctx, key, configMap); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			v.log.Info("Local cache get failed", "error", err)
+		}
+
+		return
+	}
+
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+
+	configMap.Annotations[vrgLocalCacheDeletedAnnotation] = metav1.Now().UTC().Format(metav1.RFC3339Micro)
+
+	if err := v.reconciler.Update(v.ctx, configMap); err != nil {
+		v.log.Info("Local cache update failed", "error", err)
+	}
+}
+
+// localCachePromote promotes the VRG to Primary, with a failover, if its local cache is annotated to be, creating
+// it from the cache if it is not found, and replaces the annotation with the generation of the VRG it promoted. It
+// returns whether the VRG was promoted, or promoted again as the hub reapplied it as Secondary.
+func (r *VolumeReplicationGroupReconciler) localCachePromote(ctx context.Context,
+	vrgNamespacedName types.NamespacedName, vrg *ramen.VolumeReplicationGroup, log logr.Logger,
+) (bool, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, vrgLocalCacheKey(vrgNamespacedName), configMap); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	_, promoted := configMap.GetAnnotations()[vrgLocalCachePromotedAnnotation]
+
+	switch {
+	case configMap.GetAnnotations()[VRGLocalCachePromoteAnnotation] == "true":
+	case promoted && vrg != nil:
+		return r.localCachePromotionKeep(ctx, configMap, vrg, log)
+	default:
+		return false, nil
+	}
+
+	switch {
+	case vrg == nil:
+		vrg = &ramen.VolumeReplicationGroup{}
+		if err := json.Unmarshal([]byte(configMap.Data[vrgLocalCacheKeyVRG]), vrg); err != nil {
+			return false, fmt.Errorf("local cache %s of VRG invalid: %w", configMap.Name, err)
+		}
+
+		vrg.Spec.ReplicationState = ramen.Primary
+		vrg.Spec.Action = ramen.VRGActionFailover
+
+		if err := r.Create(ctx, vrg); err != nil {
+			return false, fmt.Errorf("failed to create VRG from local cache %s: %w", configMap.Name, err)
+		}
+
+		log.Info("VRG created from local cache as Primary", "configMap", configMap.Name)
+	case vrg.Spec.ReplicationState != ramen.Primary:
+		if err := r.localCachePromoteVRG(ctx, vrg); err != nil {
+			return false, err
+		}
+
+		log.Info("VRG promoted to Primary from local cache", "configMap", configMap.Name)
+	}
+
+	delete(configMap.Annotations, VRGLocalCachePromoteAnnotation)
+
+	return true, r.localCachePromotedSet(ctx, configMap, vrg)
+}
+
+// localCachePromotionKeep promotes the VRG promoted from its local cache again if the hub reapplied its previous,
+// Secondary, spec, for the VRG not to be demoted until the DRPC is failed over to this cluster. Once a later
+// generation of the VRG than the one promoted is Primary, as the hub failed it over, the cache's annotation of the
+// promoted generation is removed. It returns whether the VRG was promoted again.
+func (r *VolumeReplicationGroupReconciler) localCachePromotionKeep(ctx context.Context, configMap *corev1.ConfigMap,
+	vrg *ramen.VolumeReplicationGroup, log logr.Logger,
+) (bool, error) {
+	generation, err := strconv.ParseInt(configMap.Annotations[vrgLocalCachePromotedAnnotation], 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("local cache %s annotation %s invalid: %w", configMap.Name,
+			vrgLocalCachePromotedAnnotation, err)
+	}
+
+	switch {
+	case vrg.Spec.ReplicationState != ramen.Primary:
+		if err := r.localCachePromoteVRG(ctx, vrg); err != nil {
+			return false, err
+		}
+
+		log.Info("VRG promoted from local cache promoted again, until the hub fails it over to this cluster",
+			"configMap", configMap.Name, "promotedGeneration", generation)
+
+		return true, r.localCachePromotedSet(ctx, configMap, vrg)
+	case vrg.Generation > generation:
+		delete(configMap.Annotations, vrgLocalCachePromotedAnnotation)
+
+		if err := r.Update(ctx, configMap); err != nil {
+			return false, fmt.Errorf("failed to remove promoted annotation of local cache %s: %w", configMap.Name,
+				err)
+		}
+
+		log.Info("VRG promoted from local cache failed over by the hub", "configMap", configMap.Name)
+	}
+
+	return false, nil
+}
+
+func (r *VolumeReplicationGroupReconciler) localCachePromoteVRG(ctx context.Context,
+	vrg *ramen.VolumeReplicationGroup,
+) error {
+	vrg.Spec.ReplicationState = ramen.Primary
+	vrg.Spec.Action = ramen.VRGActionFailover
+
+	if err := r.Update(ctx, vrg); err != nil {
+		return fmt.Errorf("failed to promote VRG: %w", err)
+	}
+
+	return nil
+}
+
+// localCachePromotedSet annotates the local cache with the generation of the VRG promoted from it
+func (r *VolumeReplicationGroupReconciler) localCachePromotedSet(ctx context.Context, configMap *corev1.ConfigMap,
+	vrg *ramen.VolumeReplicationGroup,
+) error {
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+
+	configMap.Annotations[vrgLocalCachePromotedAnnotation] = strconv.FormatInt(vrg.Generation, 10)
+
+	if err := r.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to update promote annotations of local cache %s: %w", configMap.Name, err)
+	}
+
+	return nil
+}
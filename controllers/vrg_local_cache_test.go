@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the local cache of the VRGs their promotion without the hub proceeds from
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_LocalCache", func() {
+	vrgKey := types.NamespacedName{Namespace: "app", Name: "vrg"}
+
+	Describe("vrgLocalCacheKey", func() {
+		It("names the caches of distinct VRGs distinctly", func() {
+			Expect(vrgLocalCacheKey(types.NamespacedName{Namespace: "a-b", Name: "c"}).Name).ToNot(
+				Equal(vrgLocalCacheKey(types.NamespacedName{Namespace: "a", Name: "b-c"}).Name))
+		})
+
+		It("truncates the names of the caches of VRGs of long names to valid names", func() {
+			long := types.NamespacedName{Namespace: strings.Repeat("n", 63), Name: strings.Repeat("v.", 126) + "v"}
+
+			key := vrgLocalCacheKey(long)
+			Expect(validation.IsDNS1123Subdomain(key.Name)).To(BeEmpty())
+			Expect(key.Name).To(HavePrefix(vrgLocalCacheNamePrefix + long.Namespace + "-v.v"))
+
+			long.Name += "w"
+			Expect(vrgLocalCacheKey(long).Name).ToNot(Equal(key.Name))
+		})
+	})
+
+	Describe("localCachePromote", func() {
+		var (
+			c         client.Client
+			r         *VolumeReplicationGroupReconciler
+			configMap *corev1.ConfigMap
+		)
+
+		vrgGet := func() *ramen.VolumeReplicationGroup {
+			vrg := &ramen.VolumeReplicationGroup{}
+			Expect(c.Get(context.TODO(), vrgKey, vrg)).To(Succeed())
+
+			return vrg
+		}
+		annotations := func() map[string]string {
+			Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+
+			return configMap.Annotations
+		}
+		promote := func(vrg *ramen.VolumeReplicationGroup) bool {
+			promoted, err := r.localCachePromote(context.TODO(), vrgKey, vrg, r.Log)
+			Expect(err).ToNot(HaveOccurred())
+
+			return promoted
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+			key := vrgLocalCacheKey(vrgKey)
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   key.Namespace,
+					Name:        key.Name,
+					Annotations: map[string]string{VRGLocalCachePromoteAnnotation: "true"},
+				},
+				Data: map[string]string{
+					vrgLocalCacheKeyVRG: `{"metadata":{"namespace":"app","name":"vrg"},` +
+						`"spec":{"replicationState":"secondary","s3Profiles":["east"]}}`,
+				},
+			}
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+			r = &VolumeReplicationGroupReconciler{Client: c, APIReader: c, Log: ctrl.Log.WithName("vrg-local-cache-test")}
+		})
+
+		It("creates the VRG not found from the cache as Primary", func() {
+			Expect(promote(nil)).To(BeTrue())
+			Expect(vrgGet().Spec).To(And(HaveField("ReplicationState", ramen.Primary),
+				HaveField("Action", ramen.VRGActionFailover), HaveField("S3Profiles", []string{"east"})))
+			Expect(annotations()).To(And(Not(HaveKey(VRGLocalCachePromoteAnnotation)),
+				HaveKey(vrgLocalCachePromotedAnnotation)))
+		})
+
+		It("promotes the VRG again while the hub reapplies it as Secondary, until it fails it over", func() {
+			vrg := &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: vrgKey.Namespace, Name: vrgKey.Name, Generation: 2},
+				Spec:       ramen.VolumeReplicationGroupSpec{ReplicationState: ramen.Secondary},
+			}
+			Expect(c.Create(context.TODO(), vrg)).To(Succeed())
+
+			Expect(promote(vrgGet())).To(BeTrue())
+			Expect(vrgGet().Spec.ReplicationState).To(Equal(ramen.Primary))
+			Expect(annotations()).To(HaveKeyWithValue(vrgLocalCachePromotedAnnotation, "2"))
+			Expect(promote(vrgGet())).To(BeFalse())
+
+			vrg = vrgGet()
+			vrg.Spec.ReplicationState = ramen.Secondary
+			vrg.Spec.Action = ""
+			vrg.Generation = 3
+			Expect(c.Update(context.TODO(), vrg)).To(Succeed())
+
+			Expect(promote(vrgGet())).To(BeTrue())
+			Expect(vrgGet().Spec).To(And(HaveField("ReplicationState", ramen.Primary),
+				HaveField("Action", ramen.VRGActionFailover)))
+			Expect(annotations()).To(HaveKeyWithValue(vrgLocalCachePromotedAnnotation, "3"))
+
+			vrg = vrgGet()
+			vrg.Generation = 4
+			Expect(c.Update(context.TODO(), vrg)).To(Succeed())
+
+			Expect(promote(vrgGet())).To(BeFalse())
+			Expect(annotations()).ToNot(HaveKey(vrgLocalCachePromotedAnnotation))
+
+			vrg = vrgGet()
+			vrg.Spec.ReplicationState = ramen.Secondary
+			Expect(c.Update(context.TODO(), vrg)).To(Succeed())
+			Expect(promote(vrgGet())).To(BeFalse())
+			Expect(vrgGet().Spec.ReplicationState).To(Equal(ramen.Secondary))
+		})
+	})
+})
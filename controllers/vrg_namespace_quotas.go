@@ -0,0 +1,466 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// storageClassRequestsStorage is the suffix of the resource quota resource name of the storage requested of a
+// storage class, prefixed by the storage class name
+const storageClassRequestsStorage = ".storageclass.storage.k8s.io/requests.storage"
+
+// updateNamespaceSizings records, in the status of a primary VRG, what the workload of each protected namespace
+// requires of the resource quotas and limit ranges of a namespace it is recovered to
+func (v *VRGInstance) updateNamespaceSizings() {
+	if v.instance.Spec.ReplicationState != ramen.Primary {
+		return
+	}
+
+	sizings, err := v.namespaceSizings()
+	if err != nil {
+		v.log.Info("Namespace sizings get failed", "error", err)
+
+		return
+	}
+
+	v.instance.Status.NamespaceSizings = sizings
+}
+
+func (v *VRGInstance) namespaceSizings() ([]ramen.NamespaceSizing, error) {
+	namespaces := v.workloadNamespaces()
+	sizings := make([]ramen.NamespaceSizing, 0, len(namespaces))
+
+	for _, namespace := range namespaces {
+		sizing := ramen.NamespaceSizing{
+			Namespace:                     namespace,
+			Usage:                         corev1.ResourceList{},
+			ContainerMaximums:             corev1.ResourceList{},
+			PersistentVolumeClaimMaximums: corev1.ResourceList{},
+		}
+
+		if err := v.namespaceSizingPods(&sizing); err != nil {
+			return nil, err
+		}
+
+		services := &corev1.ServiceList{}
+		if err := v.reconciler.List(v.ctx, services, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list services in namespace %s (%w)", namespace, err)
+		}
+
+		resourceListCount(sizing.Usage, corev1.ResourceServices, len(services.Items))
+		v.namespaceSizingPVCs(&sizing)
+
+		quotas := &corev1.ResourceQuotaList{}
+		if err := v.reconciler.List(v.ctx, quotas, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list resource quotas in namespace %s (%w)", namespace, err)
+		}
+
+		for idx := range quotas.Items {
+			quota := &quotas.Items[idx]
+			if resourceQuotaScoped(quota) {
+				continue
+			}
+
+			sizing.ResourceQuotas = append(sizing.ResourceQuotas, ramen.NamespaceResourceQuota{
+				Name: quota.GetName(),
+				Hard: quota.Spec.Hard,
+			})
+		}
+
+		sizings = append(sizings, sizing)
+	}
+
+	return sizings, nil
+}
+
+// namespaceSizingPods adds the requests and limits of the containers of the running pods of a namespace
+func (v *VRGInstance) namespaceSizingPods(sizing *ramen.NamespaceSizing) error {
+	pods := &corev1.PodList{}
+	if err := v.reconciler.List(v.ctx, pods, client.InNamespace(sizing.Namespace)); err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s (%w)", sizing.Namespace, err)
+	}
+
+	podCount := 0
+
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		podCount++
+
+		for cidx := range pod.Spec.Containers {
+			resources := pod.Spec.Containers[cidx].Resources
+
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				request, requested := resources.Requests[name]
+				if requested {
+					resourceListAddAs(sizing.Usage, name, request)
+					resourceListAddAs(sizing.Usage, corev1.ResourceName("requests."+name), request)
+				}
+
+				limit, limited := resources.Limits[name]
+				if limited {
+					resourceListAddAs(sizing.Usage, corev1.ResourceName("limits."+name), limit)
+					resourceListMaximize(sizing.ContainerMaximums, name, limit)
+				} else if requested {
+					resourceListMaximize(sizing.ContainerMaximums, name, request)
+				}
+			}
+		}
+	}
+
+	resourceListCount(sizing.Usage, corev1.ResourcePods, podCount)
+
+	return nil
+}
+
+// namespaceSizingPVCs adds the storage requests of the protected PVCs of a namespace, in total and per storage class
+func (v *VRGInstance) namespaceSizingPVCs(sizing *ramen.NamespaceSizing) {
+	pvcCount := 0
+	storageClassPVCCounts := map[string]int{}
+
+	for idx := range v.instance.Status.ProtectedPVCs {
+		pvc := &v.instance.Status.ProtectedPVCs[idx]
+		if pvc.Namespace != sizing.Namespace {
+			continue
+		}
+
+		pvcCount++
+
+		storage, ok := pvc.Resources.Requests[corev1.ResourceStorage]
+		if !ok {
+			continue
+		}
+
+		resourceListAddAs(sizing.Usage, corev1.ResourceRequestsStorage, storage)
+		resourceListMaximize(sizing.PersistentVolumeClaimMaximums, corev1.ResourceStorage, storage)
+
+		if pvc.StorageClassName != nil && *pvc.StorageClassName != "" {
+			storageClassPVCCounts[*pvc.StorageClassName]++
+			resourceListAddAs(sizing.Usage,
+				corev1.ResourceName(*pvc.StorageClassName+storageClassRequestsStorage), storage)
+		}
+	}
+
+	resourceListCount(sizing.Usage, corev1.ResourcePersistentVolumeClaims, pvcCount)
+
+	for storageClassName, count := range storageClassPVCCounts {
+		resourceListCount(sizing.Usage,
+			corev1.ResourceName(storageClassName+".storageclass.storage.k8s.io/persistentvolumeclaims"), count)
+	}
+}
+
+// namespaceQuotasCheck checks, once per generation of a VRG recovering to this cluster, the resource quotas and
+// limit ranges of the protected namespaces against the sizings recorded by the primary VRG, and creates or adjusts
+// resource quotas as its quota policy has it. The resource quotas and limit ranges that block the recovery are
+// reported in the NamespaceQuotasSufficient condition; they do not fail the recovery.
+func (v *VRGInstance) namespaceQuotasCheck() {
+	condition := meta.FindStatusCondition(v.instance.Status.Conditions, VRGConditionTypeNamespaceQuotasSufficient)
+	if condition != nil && condition.ObservedGeneration == v.instance.Generation {
+		return
+	}
+
+	sizings, err := v.namespaceSizingsDownload()
+	if err != nil {
+		v.log.Info("Namespace quotas check failed", "error", err)
+
+		return
+	}
+
+	if len(sizings) == 0 {
+		return
+	}
+
+	policy := v.namespaceQuotaPolicy()
+	blockers := []string{}
+
+	for idx := range sizings {
+		namespaceBlockers, err := v.namespaceQuotasReconcile(&sizings[idx], policy)
+		if err != nil {
+			v.log.Info("Namespace quotas check failed", "namespace", sizings[idx].Namespace, "error", err)
+
+			return
+		}
+
+		blockers = append(blockers, namespaceBlockers...)
+	}
+
+	msg := "Resource quotas and limit ranges are sufficient for the workload"
+	if len(blockers) != 0 {
+		msg = "Resource quotas and limit ranges block the workload: " + strings.Join(blockers, "; ")
+
+		v.log.Info(msg)
+		rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonNamespaceQuotasInsufficient, msg)
+	}
+
+	setVRGNamespaceQuotasSufficientCondition(&v.instance.Status.Conditions, v.instance.Generation,
+		len(blockers) == 0, msg)
+}
+
+// namespaceQuotaPolicy returns the quota policy of the VRG, Report by default, and Create in place of Adjust unless
+// the operator's configuration allows the VRGs to raise the resource quotas of their namespaces
+func (v *VRGInstance) namespaceQuotaPolicy() ramen.QuotaPolicy {
+	policy := ramen.QuotaPolicyReport
+	if v.instance.Spec.KubeObjectProtection != nil && v.instance.Spec.KubeObjectProtection.QuotaPolicy != "" {
+		policy = v.instance.Spec.KubeObjectProtection.QuotaPolicy
+	}
+
+	if policy == ramen.QuotaPolicyAdjust && !v.ramenConfig.KubeObjectProtection.QuotaAdjustEnabled {
+		v.log.Info("Namespace quota policy Adjust not enabled, resource quotas are created only")
+
+		return ramen.QuotaPolicyCreate
+	}
+
+	return policy
+}
+
+// namespaceSizingsDownload returns the sizings recorded by the primary VRG, from the first accessible S3 store
+func (v *VRGInstance) namespaceSizingsDownload() ([]ramen.NamespaceSizing, error) {
+	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		objectStore, _, err := v.reconciler.ObjStoreGetter.ObjectStore(
+			v.ctx, v.reconciler.APIReader, s3ProfileName, v.namespacedName, v.log)
+		if err != nil {
+			v.log.Info("Object store inaccessible for namespace quotas check", "profile", s3ProfileName,
+				"error", err)
+
+			continue
+		}
+
+		vrg := v.instance
+		sourceVrg := &ramen.VolumeReplicationGroup{}
+
+		if err := vrgObjectDownload(objectStore, s3PathNamePrefix(vrg.Namespace, vrg.Name), sourceVrg); err != nil {
+			return nil, fmt.Errorf("failed to download VRG: %w", err)
+		}
+
+		return sourceVrg.Status.NamespaceSizings, nil
+	}
+
+	return nil, fmt.Errorf("no accessible S3 store in profiles %v", v.instance.Spec.S3Profiles)
+}
+
+// namespaceQuotasReconcile creates the missing resource quotas of a namespace, and raises the hard limits of those
+// that block the recovery, as the quota policy has it, and returns a description of each resource quota and limit
+// range that blocks the recovery
+func (v *VRGInstance) namespaceQuotasReconcile(sizing *ramen.NamespaceSizing, policy ramen.QuotaPolicy,
+) ([]string, error) {
+	quotas := &corev1.ResourceQuotaList{}
+	if err := v.reconciler.List(v.ctx, quotas, client.InNamespace(sizing.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas (%w)", err)
+	}
+
+	if policy != ramen.QuotaPolicyReport {
+		created, err := v.namespaceQuotasCreate(sizing, quotas)
+		if err != nil {
+			return nil, err
+		}
+
+		quotas.Items = append(quotas.Items, created...)
+	}
+
+	blockers := []string{}
+
+	for idx := range quotas.Items {
+		quota := &quotas.Items[idx]
+		if resourceQuotaScoped(quota) {
+			continue
+		}
+
+		exceeded := resourceQuotaExceeded(quota, sizing.Usage)
+		if len(exceeded) == 0 {
+			continue
+		}
+
+		if policy == ramen.QuotaPolicyAdjust {
+			for name, total := range exceeded {
+				quota.Spec.Hard[name] = total
+			}
+
+			if err := v.reconciler.Update(v.ctx, quota); err != nil {
+				return nil, fmt.Errorf("failed to update resource quota %s (%w)", quota.GetName(), err)
+			}
+
+			v.log.Info("Resource quota adjusted", "namespace", quota.GetNamespace(), "name", quota.GetName(),
+				"hard", quota.Spec.Hard)
+
+			continue
+		}
+
+		blockers = append(blockers, resourceQuotaBlockers(quota, sizing.Usage, exceeded)...)
+	}
+
+	limitRanges := &corev1.LimitRangeList{}
+	if err := v.reconciler.List(v.ctx, limitRanges, client.InNamespace(sizing.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list limit ranges (%w)", err)
+	}
+
+	for idx := range limitRanges.Items {
+		blockers = append(blockers, limitRangeBlockers(&limitRanges.Items[idx], sizing)...)
+	}
+
+	return blockers, nil
+}
+
+// namespaceQuotasCreate creates the resource quotas of a sizing that a namespace is missing, unless the namespace is
+// yet to be created, and returns those created
+func (v *VRGInstance) namespaceQuotasCreate(sizing *ramen.NamespaceSizing, quotas *corev1.ResourceQuotaList,
+) ([]corev1.ResourceQuota, error) {
+	existing := map[string]bool{}
+	for idx := range quotas.Items {
+		existing[quotas.Items[idx].GetName()] = true
+	}
+
+	created := []corev1.ResourceQuota{}
+
+	for _, capturedQuota := range sizing.ResourceQuotas {
+		if existing[capturedQuota.Name] {
+			continue
+		}
+
+		quota := corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: capturedQuota.Name, Namespace: sizing.Namespace},
+			Spec:       corev1.ResourceQuotaSpec{Hard: capturedQuota.Hard.DeepCopy()},
+		}
+
+		if err := v.reconciler.Create(v.ctx, &quota); err != nil {
+			if k8serrors.IsNotFound(err) {
+				v.log.Info("Resource quota not created, namespace not found", "namespace", sizing.Namespace,
+					"name", capturedQuota.Name)
+
+				return created, nil
+			}
+
+			return nil, fmt.Errorf("failed to create resource quota %s (%w)", capturedQuota.Name, err)
+		}
+
+		v.log.Info("Resource quota created", "namespace", sizing.Namespace, "name", capturedQuota.Name)
+
+		created = append(created, quota)
+	}
+
+	return created, nil
+}
+
+// resourceQuotaExceeded returns the totals, of the usage of a quota and of a workload, that exceed its hard limits
+func resourceQuotaExceeded(quota *corev1.ResourceQuota, usage corev1.ResourceList) corev1.ResourceList {
+	exceeded := corev1.ResourceList{}
+
+	for name, hard := range quota.Spec.Hard {
+		needed, ok := usage[name]
+		if !ok {
+			continue
+		}
+
+		total := quota.Status.Used[name]
+		total.Add(needed)
+
+		if total.Cmp(hard) > 0 {
+			exceeded[name] = total
+		}
+	}
+
+	return exceeded
+}
+
+func resourceQuotaBlockers(quota *corev1.ResourceQuota, usage, exceeded corev1.ResourceList) []string {
+	names := make([]string, 0, len(exceeded))
+	for name := range exceeded {
+		names = append(names, string(name))
+	}
+
+	sort.Strings(names)
+
+	blockers := make([]string, 0, len(names))
+
+	for _, name := range names {
+		resourceName := corev1.ResourceName(name)
+		used := quota.Status.Used[resourceName]
+		needed := usage[resourceName]
+		hard := quota.Spec.Hard[resourceName]
+
+		blockers = append(blockers, fmt.Sprintf("ResourceQuota %s/%s %s: %s used and %s to recover exceed %s hard",
+			quota.GetNamespace(), quota.GetName(), name, used.String(), needed.String(), hard.String()))
+	}
+
+	return blockers
+}
+
+// limitRangeBlockers returns a description of each maximum of a limit range, for containers or PVCs, that the
+// largest container or PVC of a workload exceeds
+func limitRangeBlockers(limitRange *corev1.LimitRange, sizing *ramen.NamespaceSizing) []string {
+	blockers := []string{}
+
+	for _, item := range limitRange.Spec.Limits {
+		var maximums corev1.ResourceList
+
+		switch item.Type {
+		case corev1.LimitTypeContainer:
+			maximums = sizing.ContainerMaximums
+		case corev1.LimitTypePersistentVolumeClaim:
+			maximums = sizing.PersistentVolumeClaimMaximums
+		default:
+			continue
+		}
+
+		for name, limit := range item.Max {
+			maximum, ok := maximums[name]
+			if !ok || maximum.Cmp(limit) <= 0 {
+				continue
+			}
+
+			blockers = append(blockers, fmt.Sprintf("LimitRange %s/%s %s %s: %s to recover exceeds %s maximum",
+				limitRange.GetNamespace(), limitRange.GetName(), item.Type, name, maximum.String(), limit.String()))
+		}
+	}
+
+	sort.Strings(blockers)
+
+	return blockers
+}
+
+// resourceQuotaScoped returns whether a resource quota applies only to the objects that match its scopes, which
+// sizings do not distinguish
+func resourceQuotaScoped(quota *corev1.ResourceQuota) bool {
+	return len(quota.Spec.Scopes) != 0 || quota.Spec.ScopeSelector != nil
+}
+
+func resourceListAddAs(sum corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	total := sum[name]
+	total.Add(quantity)
+	sum[name] = total
+}
+
+func resourceListMaximize(maximums corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+	if maximum, ok := maximums[name]; !ok || quantity.Cmp(maximum) > 0 {
+		maximums[name] = quantity.DeepCopy()
+	}
+}
+
+func resourceListCount(sum corev1.ResourceList, name corev1.ResourceName, count int) {
+	if count == 0 {
+		return
+	}
+
+	sum[name] = *resource.NewQuantity(int64(count), resource.DecimalSI)
+}
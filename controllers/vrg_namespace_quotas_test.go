@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the reconciliation of namespace resource quotas with the workload recovered
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_NamespaceQuotas", func() {
+	var (
+		c      client.Client
+		v      *VRGInstance
+		sizing *ramen.NamespaceSizing
+	)
+
+	quota := func(name, hard, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+			Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(hard)}},
+			Status:     corev1.ResourceQuotaStatus{Used: corev1.ResourceList{corev1.ResourcePods: resource.MustParse(used)}},
+		}
+	}
+	quotaPolicy := func(policy ramen.QuotaPolicy) {
+		v.instance.Spec.KubeObjectProtection = &ramen.KubeObjectProtectionSpec{QuotaPolicy: policy}
+	}
+	quotaHard := func(name string) string {
+		quota := &corev1.ResourceQuota{}
+		Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, quota)).To(Succeed())
+
+		return quota.Spec.Hard.Pods().String()
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithObjects(
+			quota("pods", "5", "3"),
+			&corev1.LimitRange{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "containers"},
+				Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+					Type: corev1.LimitTypeContainer,
+					Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				}}},
+			},
+		).Build()
+		v = &VRGInstance{
+			reconciler:  &VolumeReplicationGroupReconciler{Client: c, APIReader: c},
+			ctx:         context.TODO(),
+			log:         ctrl.Log.WithName("vrg-namespace-quotas-test"),
+			instance:    &ramen.VolumeReplicationGroup{},
+			ramenConfig: &ramen.RamenConfig{},
+		}
+		sizing = &ramen.NamespaceSizing{
+			Namespace:         "app",
+			Usage:             corev1.ResourceList{corev1.ResourcePods: resource.MustParse("4")},
+			ContainerMaximums: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			ResourceQuotas: []ramen.NamespaceResourceQuota{
+				{Name: "pods", Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}},
+				{Name: "services", Hard: corev1.ResourceList{corev1.ResourceServices: resource.MustParse("2")}},
+			},
+		}
+	})
+
+	Describe("namespaceQuotaPolicy", func() {
+		It("defaults to Report", func() {
+			Expect(v.namespaceQuotaPolicy()).To(Equal(ramen.QuotaPolicyReport))
+		})
+
+		It("handles Adjust as Create unless the operator's configuration enables it", func() {
+			quotaPolicy(ramen.QuotaPolicyAdjust)
+			Expect(v.namespaceQuotaPolicy()).To(Equal(ramen.QuotaPolicyCreate))
+
+			v.ramenConfig.KubeObjectProtection.QuotaAdjustEnabled = true
+			Expect(v.namespaceQuotaPolicy()).To(Equal(ramen.QuotaPolicyAdjust))
+		})
+	})
+
+	Describe("namespaceQuotasReconcile", func() {
+		It("reports the resource quotas and limit ranges that block the recovery", func() {
+			blockers, err := v.namespaceQuotasReconcile(sizing, ramen.QuotaPolicyReport)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockers).To(Equal([]string{
+				"ResourceQuota app/pods pods: 3 used and 4 to recover exceed 5 hard",
+				"LimitRange app/containers Container memory: 2Gi to recover exceeds 1Gi maximum",
+			}))
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: "services"},
+				&corev1.ResourceQuota{})).ToNot(Succeed())
+		})
+
+		It("creates the missing resource quotas, without raising those that block the recovery", func() {
+			blockers, err := v.namespaceQuotasReconcile(sizing, ramen.QuotaPolicyCreate)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockers).To(HaveLen(2))
+			Expect(quotaHard("pods")).To(Equal("5"))
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: "services"},
+				&corev1.ResourceQuota{})).To(Succeed())
+		})
+
+		It("raises the hard limits of the resource quotas that block the recovery for Adjust", func() {
+			blockers, err := v.namespaceQuotasReconcile(sizing, ramen.QuotaPolicyAdjust)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blockers).To(ConsistOf(ContainSubstring("LimitRange")))
+			Expect(quotaHard("pods")).To(Equal("7"))
+		})
+	})
+
+	It("skips scoped resource quotas", func() {
+		scoped := quota("scoped", "1", "1")
+		scoped.Spec.Scopes = []corev1.ResourceQuotaScope{corev1.ResourceQuotaScopeBestEffort}
+		Expect(resourceQuotaScoped(scoped)).To(BeTrue())
+		Expect(resourceQuotaScoped(quota("pods", "1", "1"))).To(BeFalse())
+	})
+})
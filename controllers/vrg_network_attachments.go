@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// networksAnnotation lists the NetworkAttachmentDefinitions Multus attaches a pod to, either as a comma separated
+// list of [namespace/]name[@interface], or as a JSON list of network selection elements
+const networksAnnotation = "k8s.v1.cni.cncf.io/networks"
+
+// SchedulingGateNetworksMapped gates the scheduling of the pods of the recovered workloads, while the VRG maps the
+// networks they attach to, until their pod templates are rewritten
+const SchedulingGateNetworksMapped = "ramendr.openshift.io/networks-mapped"
+
+// networksMappedResourceModifiers returns, if the VRG maps networks, the Velero resource modifiers that gate the
+// scheduling of the pods of the deployments, stateful sets and daemon sets restored to the protected namespaces, so
+// that none starts attached to a network before its pod template is rewritten. The cron jobs are suspended as they
+// are restored.
+func (v *VRGInstance) networksMappedResourceModifiers() string {
+	if len(v.instance.Spec.NetworkAttachmentMappings) == 0 {
+		return ""
+	}
+
+	return v.schedulingGateResourceModifiers(SchedulingGateNetworksMapped)
+}
+
+// networkAttachmentsMap, once the recover groups complete, rewrites the networks the recovered pod templates attach
+// to with the network attachment mappings, and lifts the networks mapped scheduling gate they were restored with.
+// The pods created gated, which never started, are then deleted, for their workloads to create them again from the
+// rewritten templates. The gates are lifted whether or not networks are still mapped, for no workload restored gated
+// to remain gated. Job pod templates are immutable, and are not rewritten.
+func (v *VRGInstance) networkAttachmentsMap() error {
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		objects, err := v.podTemplateObjectsList(listOptions)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range objects {
+			if err := v.podTemplateNetworksMap(object); err != nil {
+				return err
+			}
+		}
+
+		if err := v.networksMappedGatedPodsDelete(listOptions.Namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) podTemplateNetworksMap(object podTemplateObject) error {
+	log := v.log.WithValues("kind", fmt.Sprintf("%T", object.object), "name", object.object.GetName(),
+		"namespace", object.object.GetNamespace())
+
+	gated := podSchedulingGated(&object.template.Spec, SchedulingGateNetworksMapped)
+	podSchedulingGateRemove(&object.template.Spec, SchedulingGateNetworksMapped)
+
+	rewritten := false
+
+	if networks, ok := object.template.Annotations[networksAnnotation]; ok &&
+		len(v.instance.Spec.NetworkAttachmentMappings) != 0 {
+		mapped, ok, err := networksMapped(networks, v.instance.Spec.NetworkAttachmentMappings)
+		if err != nil {
+			log.Info("Networks annotation invalid, not rewritten", "error", err)
+		}
+
+		if ok {
+			object.template.Annotations[networksAnnotation] = mapped
+			rewritten = true
+		}
+	}
+
+	if !gated && !rewritten {
+		return nil
+	}
+
+	if err := v.reconciler.Update(v.ctx, object.object); err != nil {
+		return fmt.Errorf("failed to rewrite networks of %T %s/%s (%w)", object.object,
+			object.object.GetNamespace(), object.object.GetName(), err)
+	}
+
+	log.Info("Networks mapped", "networks", object.template.Annotations[networksAnnotation], "rewritten", rewritten)
+
+	return nil
+}
+
+// networksMappedGatedPodsDelete deletes the pods of a namespace gated until their networks are mapped
+func (v *VRGInstance) networksMappedGatedPodsDelete(namespace string) error {
+	pods := &corev1.PodList{}
+	if err := v.reconciler.List(v.ctx, pods, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s (%w)", namespace, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !podSchedulingGated(&pod.Spec, SchedulingGateNetworksMapped) || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if err := v.reconciler.Delete(v.ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s/%s gated until its networks are mapped (%w)", namespace,
+				pod.Name, err)
+		}
+
+		v.log.Info("Pod gated until its networks are mapped deleted", "name", pod.Name, "namespace", namespace)
+	}
+
+	return nil
+}
+
+type podTemplateObject struct {
+	object   client.Object
+	template *corev1.PodTemplateSpec
+}
+
+// podTemplateObjectsList lists the deployments, stateful sets, daemon sets and cron jobs from the cache
+func (v *VRGInstance) podTemplateObjectsList(listOptions *client.ListOptions) ([]podTemplateObject, error) {
+	deployments := &appsv1.DeploymentList{}
+	statefulSets := &appsv1.StatefulSetList{}
+	daemonSets := &appsv1.DaemonSetList{}
+	cronJobs := &batchv1.CronJobList{}
+
+	for _, list := range []client.ObjectList{deployments, statefulSets, daemonSets, cronJobs} {
+		if err := v.reconciler.List(v.ctx, list, listOptions); err != nil {
+			return nil, fmt.Errorf("failed to list %T in namespace %s (%w)", list, listOptions.Namespace, err)
+		}
+	}
+
+	objects := []podTemplateObject{}
+
+	for i := range deployments.Items {
+		objects = append(objects, podTemplateObject{&deployments.Items[i], &deployments.Items[i].Spec.Template})
+	}
+
+	for i := range statefulSets.Items {
+		objects = append(objects, podTemplateObject{&statefulSets.Items[i], &statefulSets.Items[i].Spec.Template})
+	}
+
+	for i := range daemonSets.Items {
+		objects = append(objects, podTemplateObject{&daemonSets.Items[i], &daemonSets.Items[i].Spec.Template})
+	}
+
+	for i := range cronJobs.Items {
+		objects = append(objects,
+			podTemplateObject{&cronJobs.Items[i], &cronJobs.Items[i].Spec.JobTemplate.Spec.Template})
+	}
+
+	return objects, nil
+}
+
+// networksMapped returns the networks annotation with the networks rewritten by the mappings, and whether any were
+// rewritten
+func networksMapped(networks string, mappings []ramen.NetworkAttachmentMapping) (string, bool, error) {
+	if strings.HasPrefix(strings.TrimSpace(networks), "[") {
+		return networksJSONMapped(networks, mappings)
+	}
+
+	elements := strings.Split(networks, ",")
+	rewritten := false
+
+	for i, element := range elements {
+		element = strings.TrimSpace(element)
+		network, iface, _ := strings.Cut(element, "@")
+
+		target, ok := networkMapped(network, mappings)
+		if !ok {
+			continue
+		}
+
+		if iface != "" {
+			target += "@" + iface
+		}
+
+		elements[i] = target
+		rewritten = true
+	}
+
+	return strings.Join(elements, ","), rewritten, nil
+}
+
+// networksJSONMapped rewrites the networks of a JSON list of network selection elements, preserving their other
+// fields
+func networksJSONMapped(networks string, mappings []ramen.NetworkAttachmentMapping) (string, bool, error) {
+	elements := []map[string]interface{}{}
+	if err := json.Unmarshal([]byte(networks), &elements); err != nil {
+		return networks, false, err
+	}
+
+	rewritten := false
+
+	for _, element := range elements {
+		name, _ := element["name"].(string)
+		namespace, _ := element["namespace"].(string)
+
+		network := name
+		if namespace != "" {
+			network = namespace + "/" + name
+		}
+
+		target, ok := networkMapped(network, mappings)
+		if !ok {
+			continue
+		}
+
+		targetNamespace, targetName, namespaced := strings.Cut(target, "/")
+		if namespaced {
+			element["namespace"] = targetNamespace
+			element["name"] = targetName
+		} else {
+			delete(element, "namespace")
+			element["name"] = target
+		}
+
+		rewritten = true
+	}
+
+	if !rewritten {
+		return networks, false, nil
+	}
+
+	mapped, err := json.Marshal(elements)
+	if err != nil {
+		return networks, false, err
+	}
+
+	return string(mapped), true, nil
+}
+
+// networkMapped returns the target of the mapping whose source is the network, as [namespace/]name
+func networkMapped(network string, mappings []ramen.NetworkAttachmentMapping) (string, bool) {
+	for _, mapping := range mappings {
+		if mapping.Source == network {
+			return mapping.Target, true
+		}
+	}
+
+	return network, false
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the mappings of the networks the recovered workloads attach to
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_NetworkAttachments", func() {
+	mappings := []ramen.NetworkAttachmentMapping{
+		{Source: "storage-vlan", Target: "storage-vlan-east"},
+		{Source: "infra/sriov-net", Target: "infra/sriov-net-east"},
+		{Source: "infra/macvlan", Target: "macvlan-east"},
+	}
+
+	DescribeTable("networksMapped",
+		func(networks, expected string, rewritten bool) {
+			mapped, ok, err := networksMapped(networks, mappings)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(Equal(rewritten))
+			Expect(mapped).To(Equal(expected))
+		},
+		Entry("a network mapped", "storage-vlan", "storage-vlan-east", true),
+		Entry("a network of a namespace mapped, with its interface", "infra/sriov-net@net1",
+			"infra/sriov-net-east@net1", true),
+		Entry("the networks of a list mapped, keeping the others", "storage-vlan, other,infra/sriov-net",
+			"storage-vlan-east, other,infra/sriov-net-east", true),
+		Entry("no network mapped", "other@eth1", "other@eth1", false),
+		Entry("a network of another namespace not mapped", "apps/storage-vlan", "apps/storage-vlan", false),
+	)
+
+	DescribeTable("networksJSONMapped",
+		func(networks, expected string, rewritten bool) {
+			mapped, ok, err := networksMapped(networks, mappings)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ok).To(Equal(rewritten))
+
+			if !rewritten {
+				Expect(mapped).To(Equal(networks))
+
+				return
+			}
+
+			Expect(mapped).To(MatchJSON(expected))
+		},
+		Entry("a network mapped, keeping its other fields", `[{"name":"storage-vlan","interface":"net1","ips":["10.0.0.2"]}]`,
+			`[{"name":"storage-vlan-east","interface":"net1","ips":["10.0.0.2"]}]`, true),
+		Entry("a network of a namespace mapped", ` [{"name":"sriov-net","namespace":"infra"}]`,
+			`[{"name":"sriov-net-east","namespace":"infra"}]`, true),
+		Entry("a network of a namespace mapped to one of the pod's namespace", `[{"name":"macvlan","namespace":"infra"}]`,
+			`[{"name":"macvlan-east"}]`, true),
+		Entry("no network mapped", `[{"name":"storage-vlan","namespace":"apps"}]`, "", false),
+	)
+
+	It("fails to map an invalid JSON list of networks", func() {
+		_, ok, err := networksMapped(`[{"name":`, mappings)
+		Expect(err).To(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("networkAttachmentsMap", func() {
+		const namespace = "app"
+
+		var (
+			c           client.Client
+			vrgInstance *VRGInstance
+		)
+
+		restoredLabels := map[string]string{veleroRestoreNameLabel: "restore"}
+		deployment := func(name string, labels, annotations map[string]string, gates ...string) *appsv1.Deployment {
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, Labels: labels,
+			}}
+			deployment.Spec.Template.Annotations = annotations
+
+			for _, gate := range gates {
+				deployment.Spec.Template.Spec.SchedulingGates = append(deployment.Spec.Template.Spec.SchedulingGates,
+					corev1.PodSchedulingGate{Name: gate})
+			}
+
+			return deployment
+		}
+		deploymentGet := func(name string) *appsv1.Deployment {
+			deployment := &appsv1.Deployment{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, deployment)).
+				To(Succeed())
+
+			return deployment
+		}
+		pod := func(name string, gates ...string) *corev1.Pod {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+
+			for _, gate := range gates {
+				pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: gate})
+			}
+
+			return pod
+		}
+
+		BeforeEach(func() {
+			c = fake.NewClientBuilder().WithObjects(
+				deployment("web", restoredLabels, map[string]string{networksAnnotation: "storage-vlan@net1"},
+					SchedulingGateNetworksMapped, SchedulingGateVolumesReady),
+				deployment("plain", restoredLabels, nil, SchedulingGateNetworksMapped),
+				deployment("existing", nil, map[string]string{networksAnnotation: "storage-vlan"}),
+				pod("web-1", SchedulingGateNetworksMapped),
+				pod("plain-1", SchedulingGateVolumesReady),
+			).Build()
+			vrgInstance = vrgInstanceFake(c, "vrg-network-attachments-test", ramen.VolumeReplicationGroupSpec{
+				NetworkAttachmentMappings: mappings,
+			})
+		})
+
+		It("rewrites the networks of the recovered pod templates, lifts their gate and deletes the gated pods", func() {
+			Expect(vrgInstance.networkAttachmentsMap()).To(Succeed())
+
+			web := deploymentGet("web")
+			Expect(web.Spec.Template.Annotations).To(HaveKeyWithValue(networksAnnotation, "storage-vlan-east@net1"))
+			Expect(web.Spec.Template.Spec.SchedulingGates).To(Equal([]corev1.PodSchedulingGate{
+				{Name: SchedulingGateVolumesReady},
+			}))
+			Expect(deploymentGet("plain").Spec.Template.Spec.SchedulingGates).To(BeEmpty())
+			Expect(deploymentGet("existing").Spec.Template.Annotations).To(
+				HaveKeyWithValue(networksAnnotation, "storage-vlan"))
+
+			pods := &corev1.PodList{}
+			Expect(c.List(context.TODO(), pods)).To(Succeed())
+			Expect(pods.Items).To(ConsistOf(HaveField("Name", "plain-1")))
+		})
+
+		It("lifts the gates of the recovered pod templates once no networks are mapped", func() {
+			vrgInstance.instance.Spec.NetworkAttachmentMappings = nil
+			Expect(vrgInstance.networkAttachmentsMap()).To(Succeed())
+
+			web := deploymentGet("web")
+			Expect(web.Spec.Template.Annotations).To(HaveKeyWithValue(networksAnnotation, "storage-vlan@net1"))
+			Expect(web.Spec.Template.Spec.SchedulingGates).To(HaveLen(1))
+		})
+
+		It("gates the pods of the restored workloads only while networks are mapped", func() {
+			rules := []map[string]interface{}{}
+			Expect(yaml.Unmarshal([]byte(vrgInstance.networksMappedResourceModifiers()), &rules)).To(Succeed())
+			Expect(rules).To(HaveLen(6))
+			Expect(rules[1]).To(HaveKeyWithValue("conditions", map[string]interface{}{
+				"groupResource": "deployments.apps",
+				"namespaces":    []interface{}{namespace},
+			}))
+			Expect(rules[1]).To(HaveKeyWithValue("patches", []interface{}{map[string]interface{}{
+				"operation": "add",
+				"path":      "/spec/template/spec/schedulingGates/-",
+				"value":     `{"name": "` + SchedulingGateNetworksMapped + `"}`,
+			}}))
+
+			Expect(yaml.Unmarshal([]byte(cronJobsSuspendResourceModifiers+vrgInstance.networksMappedResourceModifiers()),
+				&map[string]interface{}{})).To(Succeed())
+
+			vrgInstance.instance.Spec.NetworkAttachmentMappings = nil
+			Expect(vrgInstance.networksMappedResourceModifiers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"golang.org/x/exp/slices"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+// operatorsReadyTimeoutDefault is how long, by default, each step of waiting for the recovered operators to be ready
+// waits before the recovery continues regardless
+const operatorsReadyTimeoutDefault = 10 * time.Minute
+
+// recoverGroupOperators returns whether a recover group recovers operators, for the groups after it to wait for them
+// to be ready
+func recoverGroupOperators(recoverGroup kubeobjects.RecoverSpec) bool {
+	return slices.Contains(recoverGroup.IncludedResources, "subscriptions.operators.coreos.com")
+}
+
+// operatorsRecoveredReady returns a processing error, for the recovery to resume later, until the operators subscribed
+// to in the protected namespaces are installed, the custom resource definitions they own are established, and the
+// services of their webhooks are ready, each step reported in a condition and timing out on its own
+func (v *VRGInstance) operatorsRecoveredReady(log logr.Logger) error {
+	subscriptions, err := v.operatorSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	clusterServiceVersions, waiting, err := v.operatorsInstalled(subscriptions)
+	if err != nil {
+		return err
+	}
+
+	if !v.operatorsRecoveryStepDone(VRGConditionTypeOperatorsInstalled, waiting, log) {
+		return kubeobjects.RequestProcessingErrorCreate("waiting for operators to be installed")
+	}
+
+	waiting, err = v.operatorCRDsEstablished(clusterServiceVersions)
+	if err != nil {
+		return err
+	}
+
+	if !v.operatorsRecoveryStepDone(VRGConditionTypeOperatorCRDsEstablished, waiting, log) {
+		return kubeobjects.RequestProcessingErrorCreate("waiting for operator custom resource definitions")
+	}
+
+	waiting, err = v.operatorWebhooksReady()
+	if err != nil {
+		return err
+	}
+
+	if !v.operatorsRecoveryStepDone(VRGConditionTypeOperatorWebhooksReady, waiting, log) {
+		return kubeobjects.RequestProcessingErrorCreate("waiting for operator webhooks")
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) operatorSubscriptions() ([]operatorsv1alpha1.Subscription, error) {
+	subscriptions := []operatorsv1alpha1.Subscription{}
+
+	for _, namespace := range v.workloadNamespaces() {
+		list := &operatorsv1alpha1.SubscriptionList{}
+		if err := v.reconciler.APIReader.List(v.ctx, list, client.InNamespace(namespace)); err != nil {
+			// OLM is not installed
+			if meta.IsNoMatchError(err) {
+				return nil, nil
+			}
+
+			return nil, fmt.Errorf("failed to list subscriptions in namespace %s (%w)", namespace, err)
+		}
+
+		subscriptions = append(subscriptions, list.Items...)
+	}
+
+	return subscriptions, nil
+}
+
+// operatorsInstalled returns the installed cluster service versions of the subscriptions, and those not yet installed
+func (v *VRGInstance) operatorsInstalled(subscriptions []operatorsv1alpha1.Subscription,
+) ([]operatorsv1alpha1.ClusterServiceVersion, []string, error) {
+	clusterServiceVersions := []operatorsv1alpha1.ClusterServiceVersion{}
+	waiting := []string{}
+
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+
+		if subscription.Status.InstalledCSV == "" {
+			waiting = append(waiting, fmt.Sprintf("subscription %s/%s", subscription.Namespace, subscription.Name))
+
+			continue
+		}
+
+		key := types.NamespacedName{Namespace: subscription.Namespace, Name: subscription.Status.InstalledCSV}
+		clusterServiceVersion := &operatorsv1alpha1.ClusterServiceVersion{}
+
+		if err := v.reconciler.APIReader.Get(v.ctx, key, clusterServiceVersion); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return nil, nil, fmt.Errorf("failed to get cluster service version %s (%w)", key, err)
+			}
+
+			waiting = append(waiting, fmt.Sprintf("cluster service version %s", key))
+
+			continue
+		}
+
+		if clusterServiceVersion.Status.Phase != operatorsv1alpha1.CSVPhaseSucceeded {
+			waiting = append(waiting, fmt.Sprintf("cluster service version %s in phase %s", key,
+				clusterServiceVersion.Status.Phase))
+
+			continue
+		}
+
+		clusterServiceVersions = append(clusterServiceVersions, *clusterServiceVersion)
+	}
+
+	return clusterServiceVersions, waiting, nil
+}
+
+// operatorCRDsEstablished returns the custom resource definitions owned by the cluster service versions that are not
+// yet established
+func (v *VRGInstance) operatorCRDsEstablished(clusterServiceVersions []operatorsv1alpha1.ClusterServiceVersion,
+) ([]string, error) {
+	waiting := []string{}
+
+	for i := range clusterServiceVersions {
+		for _, owned := range clusterServiceVersions[i].Spec.CustomResourceDefinitions.Owned {
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := v.reconciler.APIReader.Get(v.ctx, types.NamespacedName{Name: owned.Name}, crd); err != nil {
+				if !k8serrors.IsNotFound(err) {
+					return nil, fmt.Errorf("failed to get custom resource definition %s (%w)", owned.Name, err)
+				}
+
+				waiting = append(waiting, "custom resource definition "+owned.Name)
+
+				continue
+			}
+
+			if !crdEstablished(crd) {
+				waiting = append(waiting, "custom resource definition "+owned.Name)
+			}
+		}
+	}
+
+	return waiting, nil
+}
+
+func crdEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// operatorWebhooksReady returns the webhooks, served in the protected namespaces, whose services have no ready
+// endpoints
+func (v *VRGInstance) operatorWebhooksReady() ([]string, error) {
+	services, err := webhookServices(v.ctx, v.reconciler.APIReader)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := v.workloadNamespaces()
+	waiting := []string{}
+
+	for key, webhookName := range services {
+		if !slices.Contains(namespaces, key.Namespace) {
+			continue
+		}
+
+		ready, err := serviceEndpointsReady(v.ctx, v.reconciler.APIReader, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ready {
+			waiting = append(waiting, fmt.Sprintf("webhook %s (service %s)", webhookName, key))
+		}
+	}
+
+	return waiting, nil
+}
+
+// operatorsRecoveryStepDone sets the condition of a step of waiting for the recovered operators to be ready, and
+// returns whether the step is done, either as nothing is waited for or as the wait timed out
+func (v *VRGInstance) operatorsRecoveryStepDone(conditionType string, waiting []string, log logr.Logger) bool {
+	conditions := &v.instance.Status.Conditions
+	condition := metav1.Condition{
+		Type:               conditionType,
+		ObservedGeneration: v.instance.Generation,
+	}
+
+	if len(waiting) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = VRGConditionReasonReady
+		condition.Message = "Recovered operators are ready"
+		setStatusCondition(conditions, condition)
+
+		return true
+	}
+
+	condition.Status = metav1.ConditionFalse
+	condition.Message = "Waiting for " + strings.Join(waiting, ", ")
+	timeout := operatorsReadyTimeout(v.ramenConfig)
+
+	if existing := findCondition(*conditions, conditionType); existing != nil &&
+		existing.Status == metav1.ConditionFalse && time.Since(existing.LastTransitionTime.Time) > timeout {
+		condition.Reason = VRGConditionReasonTimedOut
+		condition.Message = fmt.Sprintf("Timed out after %v: %s", timeout, condition.Message)
+		setStatusCondition(conditions, condition)
+		log.Info("Recovered operators wait timed out, recovery continues", "step", conditionType,
+			"waiting", waiting)
+
+		return true
+	}
+
+	condition.Reason = VRGConditionReasonWaiting
+	setStatusCondition(conditions, condition)
+	log.Info("Waiting for recovered operators", "step", conditionType, "waiting", waiting)
+
+	return false
+}
+
+func operatorsReadyTimeout(ramenConfig *ramen.RamenConfig) time.Duration {
+	if ramenConfig == nil || ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds <= 0 {
+		return operatorsReadyTimeoutDefault
+	}
+
+	return time.Duration(ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds) * time.Second
+}
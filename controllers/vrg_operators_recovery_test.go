@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the wait for the recovered operators to be ready
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_OperatorsRecovery", func() {
+	log := ctrl.Log.WithName("vrg-operators-recovery-test")
+
+	It("recovers the operators first with the groups that recover their subscriptions", func() {
+		Expect(recoverGroupOperators(kubeobjects.RecoverSpec{Spec: kubeobjects.Spec{
+			KubeResourcesSpec: kubeobjects.KubeResourcesSpec{IncludedResources: operatorResources},
+		}})).To(BeTrue())
+		Expect(recoverGroupOperators(kubeobjects.RecoverSpec{})).To(BeFalse())
+	})
+
+	It("waits for each step for the configured time, or 10 minutes", func() {
+		Expect(operatorsReadyTimeout(nil)).To(Equal(operatorsReadyTimeoutDefault))
+
+		ramenConfig := &ramen.RamenConfig{}
+		Expect(operatorsReadyTimeout(ramenConfig)).To(Equal(10 * time.Minute))
+
+		ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds = 30
+		Expect(operatorsReadyTimeout(ramenConfig)).To(Equal(30 * time.Second))
+	})
+
+	Describe("operatorsRecoveredReady", func() {
+		var (
+			c client.Client
+			v *VRGInstance
+		)
+
+		conditionReason := func(conditionType string) string {
+			condition := findCondition(v.instance.Status.Conditions, conditionType)
+			Expect(condition).ToNot(BeNil(), conditionType)
+
+			return condition.Reason
+		}
+		waiting := func(conditionType, message string) {
+			err := v.operatorsRecoveredReady(log)
+			Expect(err).To(BeAssignableToTypeOf(kubeobjects.RequestProcessingError{}))
+			Expect(conditionReason(conditionType)).To(Equal(VRGConditionReasonWaiting))
+			Expect(findCondition(v.instance.Status.Conditions, conditionType).Message).To(ContainSubstring(message))
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+			Expect(operatorsv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+			c = fake.NewClientBuilder().WithScheme(scheme).Build()
+			v = vrgInstanceFake(c, "vrg-operators-recovery-test", ramen.VolumeReplicationGroupSpec{})
+			v.ramenConfig = &ramen.RamenConfig{}
+		})
+
+		It("is ready without subscriptions", func() {
+			Expect(v.operatorsRecoveredReady(log)).To(Succeed())
+			Expect(v.instance.Status.Conditions).To(BeEmpty())
+		})
+
+		It("waits for the operators to be installed, their CRDs established, and their webhooks ready", func() {
+			subscription := &operatorsv1alpha1.Subscription{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db"}}
+			Expect(c.Create(context.TODO(), subscription)).To(Succeed())
+			waiting(VRGConditionTypeOperatorsInstalled, "subscription app/db")
+
+			subscription.Status.InstalledCSV = "db.v1"
+			Expect(c.Update(context.TODO(), subscription)).To(Succeed())
+			waiting(VRGConditionTypeOperatorsInstalled, "cluster service version app/db.v1")
+
+			csv := &operatorsv1alpha1.ClusterServiceVersion{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db.v1"},
+				Spec: operatorsv1alpha1.ClusterServiceVersionSpec{
+					CustomResourceDefinitions: operatorsv1alpha1.CustomResourceDefinitions{
+						Owned: []operatorsv1alpha1.CRDDescription{{Name: "databases.example.com"}},
+					},
+				},
+				Status: operatorsv1alpha1.ClusterServiceVersionStatus{Phase: operatorsv1alpha1.CSVPhaseInstalling},
+			}
+			Expect(c.Create(context.TODO(), csv)).To(Succeed())
+			waiting(VRGConditionTypeOperatorsInstalled, "in phase Installing")
+
+			csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+			Expect(c.Update(context.TODO(), csv)).To(Succeed())
+			waiting(VRGConditionTypeOperatorCRDsEstablished, "custom resource definition databases.example.com")
+			Expect(conditionReason(VRGConditionTypeOperatorsInstalled)).To(Equal(VRGConditionReasonReady))
+
+			Expect(c.Create(context.TODO(), &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "databases.example.com"},
+				Status: apiextensionsv1.CustomResourceDefinitionStatus{
+					Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+						{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+					},
+				},
+			})).To(Succeed())
+			Expect(c.Create(context.TODO(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "db"},
+				Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+					Name: "validate.databases.example.com",
+					Rules: []admissionregistrationv1.RuleWithOperations{{Rule: admissionregistrationv1.Rule{
+						APIGroups: []string{"*"}, Resources: []string{"*"},
+					}}},
+					ClientConfig: admissionregistrationv1.WebhookClientConfig{
+						Service: &admissionregistrationv1.ServiceReference{Namespace: "app", Name: "db-webhook"},
+					},
+				}},
+			})).To(Succeed())
+			waiting(VRGConditionTypeOperatorWebhooksReady, "webhook validate.databases.example.com")
+			Expect(conditionReason(VRGConditionTypeOperatorCRDsEstablished)).To(Equal(VRGConditionReasonReady))
+
+			Expect(c.Create(context.TODO(), &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "db-webhook"},
+				Subsets:    []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+			})).To(Succeed())
+			Expect(v.operatorsRecoveredReady(log)).To(Succeed())
+			Expect(conditionReason(VRGConditionTypeOperatorWebhooksReady)).To(Equal(VRGConditionReasonReady))
+		})
+	})
+
+	Describe("operatorsRecoveryStepDone", func() {
+		It("is done once nothing is waited for, or the wait timed out", func() {
+			v := vrgInstanceFake(nil, "vrg-operators-recovery-test", ramen.VolumeReplicationGroupSpec{})
+			v.ramenConfig = &ramen.RamenConfig{}
+			v.ramenConfig.KubeObjectProtection.OperatorsReadyTimeoutSeconds = 60
+
+			Expect(v.operatorsRecoveryStepDone(VRGConditionTypeOperatorsInstalled, []string{"a"}, log)).To(BeFalse())
+			Expect(v.operatorsRecoveryStepDone(VRGConditionTypeOperatorsInstalled, []string{"a"}, log)).To(BeFalse())
+
+			condition := findCondition(v.instance.Status.Conditions, VRGConditionTypeOperatorsInstalled)
+			condition.LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			Expect(v.operatorsRecoveryStepDone(VRGConditionTypeOperatorsInstalled, []string{"a"}, log)).To(BeTrue())
+
+			condition = findCondition(v.instance.Status.Conditions, VRGConditionTypeOperatorsInstalled)
+			Expect(condition.Reason).To(Equal(VRGConditionReasonTimedOut))
+			Expect(condition.Message).To(Equal("Timed out after 1m0s: Waiting for a"))
+
+			Expect(v.operatorsRecoveryStepDone(VRGConditionTypeOperatorsInstalled, nil, log)).To(BeTrue())
+			condition = findCondition(v.instance.Status.Conditions, VRGConditionTypeOperatorsInstalled)
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+})
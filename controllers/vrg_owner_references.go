@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ownerReferencesDependentKinds are the kinds of the recovered objects whose owner references are re-linked: the
+// dependents of the workload controllers, and of the workloads. The operator is granted to patch these kinds only.
+var ownerReferencesDependentKinds = []schema.GroupVersionKind{
+	corev1.SchemeGroupVersion.WithKind("Pod"),
+	corev1.SchemeGroupVersion.WithKind("Service"),
+	corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+	corev1.SchemeGroupVersion.WithKind("Secret"),
+	corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim"),
+	corev1.SchemeGroupVersion.WithKind("ServiceAccount"),
+	appsv1.SchemeGroupVersion.WithKind("Deployment"),
+	appsv1.SchemeGroupVersion.WithKind("ReplicaSet"),
+	appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+	appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+	appsv1.SchemeGroupVersion.WithKind("ControllerRevision"),
+	batchv1.SchemeGroupVersion.WithKind("Job"),
+	batchv1.SchemeGroupVersion.WithKind("CronJob"),
+	networkingv1.SchemeGroupVersion.WithKind("Ingress"),
+	policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"),
+	autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"),
+}
+
+// ownerReferencesRelink re-links the owner references of the recovered objects to their owners by kind and name, as
+// recovered owners are assigned new UIDs, and the garbage collector deletes a dependent whose owners' UIDs are all
+// absent. A reference to an owner that was not recovered is removed, for its dependent not to be deleted.
+func (v *VRGInstance) ownerReferencesRelink() error {
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		for _, gvk := range ownerReferencesDependentKinds {
+			objects := &metav1.PartialObjectMetadataList{}
+			objects.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+			if err := v.reconciler.APIReader.List(v.ctx, objects, listOptions); err != nil {
+				if meta.IsNoMatchError(err) {
+					continue
+				}
+
+				return fmt.Errorf("failed to list %s in namespace %s (%w)", gvk.Kind, listOptions.Namespace, err)
+			}
+
+			for i := range objects.Items {
+				object := &objects.Items[i]
+				object.SetGroupVersionKind(gvk)
+
+				if err := v.ownerReferencesRelinkObject(object); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ownerReferencesRelinkObject re-links the owner references of a recovered object to the UIDs its owners are read
+// with, uncached, just before. The patch is conditioned on the resource version the object was listed with, so that
+// it fails, to be retried, rather than restoring references the garbage collector or the owners' controllers changed
+// meanwhile.
+func (v *VRGInstance) ownerReferencesRelinkObject(object *metav1.PartialObjectMetadata) error {
+	ownerReferences := object.GetOwnerReferences()
+	if len(ownerReferences) == 0 {
+		return nil
+	}
+
+	log := v.log.WithValues("kind", object.Kind, "name", object.GetName(), "namespace", object.GetNamespace())
+	relinked := make([]metav1.OwnerReference, 0, len(ownerReferences))
+
+	for _, ownerReference := range ownerReferences {
+		uid, err := v.ownerUID(ownerReference, object.GetNamespace())
+		if err != nil {
+			if !k8serrors.IsForbidden(err) {
+				return err
+			}
+
+			log.Info("Owner reference not re-linked, as the owner kind may not be read",
+				"owner kind", ownerReference.Kind, "owner name", ownerReference.Name)
+
+			relinked = append(relinked, ownerReference)
+
+			continue
+		}
+
+		if uid == "" {
+			log.Info("Owner reference removed, as the owner was not recovered",
+				"owner kind", ownerReference.Kind, "owner name", ownerReference.Name)
+
+			continue
+		}
+
+		ownerReference.UID = uid
+		relinked = append(relinked, ownerReference)
+	}
+
+	if reflect.DeepEqual(ownerReferences, relinked) {
+		return nil
+	}
+
+	patch := client.MergeFromWithOptions(object.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	object.SetOwnerReferences(relinked)
+
+	if err := v.reconciler.Patch(v.ctx, object, patch); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to re-link owner references of %s %s/%s (%w)", object.Kind,
+			object.GetNamespace(), object.GetName(), err)
+	}
+
+	log.Info("Owner references re-linked")
+
+	return nil
+}
+
+// ownerUID returns the UID of the owner an owner reference refers to by kind and name, or an empty UID if the owner
+// does not exist
+func (v *VRGInstance) ownerUID(ownerReference metav1.OwnerReference, namespace string) (types.UID, error) {
+	gvk := schema.FromAPIVersionAndKind(ownerReference.APIVersion, ownerReference.Kind)
+
+	mapping, err := v.reconciler.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to map owner kind %s (%w)", gvk, err)
+	}
+
+	key := types.NamespacedName{Name: ownerReference.Name}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		key.Namespace = namespace
+	}
+
+	owner := &metav1.PartialObjectMetadata{}
+	owner.SetGroupVersionKind(gvk)
+
+	if err := v.reconciler.APIReader.Get(v.ctx, key, owner); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to get owner %s %s (%w)", gvk.Kind, key, err)
+	}
+
+	return owner.GetUID(), nil
+}
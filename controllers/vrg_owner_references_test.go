@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the re-link of the owner references of recovered objects
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_OwnerReferences", func() {
+	const namespace = "app"
+
+	var (
+		reconciler  *VolumeReplicationGroupReconciler
+		vrgInstance *VRGInstance
+	)
+
+	restoredLabels := map[string]string{veleroRestoreNameLabel: "restore"}
+	ownerReference := func(kind, name string, uid types.UID) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: uid}
+	}
+	replicaSet := func() *appsv1.ReplicaSet {
+		replicaSet := &appsv1.ReplicaSet{}
+		Expect(reconciler.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "web-1"},
+			replicaSet)).To(Succeed())
+
+		return replicaSet
+	}
+
+	BeforeEach(func() {
+		restMapper := meta.NewDefaultRESTMapper(nil)
+		restMapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+		restMapper.Add(appsv1.SchemeGroupVersion.WithKind("StatefulSet"), meta.RESTScopeNamespace)
+
+		c := fake.NewClientBuilder().WithRESTMapper(restMapper).WithObjects(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: "web", UID: "recovered", Labels: restoredLabels,
+			}},
+			&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: "web-1", Labels: restoredLabels,
+				OwnerReferences: []metav1.OwnerReference{
+					ownerReference("Deployment", "web", "captured"),
+					ownerReference("StatefulSet", "absent", "captured"),
+				},
+			}},
+		).Build()
+		reconciler = &VolumeReplicationGroupReconciler{Client: c, APIReader: c}
+		vrgInstance = &VRGInstance{
+			reconciler: reconciler,
+			ctx:        context.TODO(),
+			log:        ctrl.Log.WithName("vrg-owner-references-test"),
+			instance: &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "vrg"},
+			},
+		}
+	})
+
+	It("re-links the references to the recovered owners, and removes the others", func() {
+		Expect(vrgInstance.ownerReferencesRelink()).To(Succeed())
+		Expect(replicaSet().OwnerReferences).To(Equal([]metav1.OwnerReference{
+			ownerReference("Deployment", "web", "recovered"),
+		}))
+
+		Expect(vrgInstance.ownerReferencesRelink()).To(Succeed())
+		Expect(replicaSet().OwnerReferences).To(HaveLen(1))
+	})
+
+	It("does not patch a dependent changed since it was read", func() {
+		object := &metav1.PartialObjectMetadata{}
+		object.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))
+		Expect(reconciler.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "web-1"},
+			object)).To(Succeed())
+
+		changed := replicaSet()
+		changed.OwnerReferences = changed.OwnerReferences[:1]
+		Expect(reconciler.Update(context.TODO(), changed)).To(Succeed())
+
+		Expect(k8serrors.IsConflict(vrgInstance.ownerReferencesRelinkObject(object))).To(BeTrue())
+		Expect(replicaSet().OwnerReferences).To(Equal([]metav1.OwnerReference{
+			ownerReference("Deployment", "web", "captured"),
+		}))
+	})
+
+	It("re-links the owner references of the granted kinds only", func() {
+		for _, gvk := range ownerReferencesDependentKinds {
+			Expect(gvk.Group).To(BeElementOf("", appsv1.GroupName, "batch", "networking.k8s.io", "policy",
+				"autoscaling"), gvk.String())
+		}
+
+		Expect(ownerReferencesDependentKinds).To(ContainElement(corev1.SchemeGroupVersion.WithKind("Pod")))
+		Expect(ownerReferencesDependentKinds).ToNot(ContainElement(HaveField("Kind", "Node")))
+	})
+
+	It("skips a dependent deleted since it was read", func() {
+		object := &metav1.PartialObjectMetadata{}
+		object.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))
+		Expect(reconciler.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "web-1"},
+			object)).To(Succeed())
+
+		Expect(reconciler.Delete(context.TODO(), replicaSet())).To(Succeed())
+		Expect(vrgInstance.ownerReferencesRelinkObject(object)).To(Succeed())
+	})
+})
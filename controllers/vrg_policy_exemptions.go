@@ -0,0 +1,504 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// policyExemptionsAnnotation is set on the Gatekeeper constraints a VRG exempts its namespaces from, to a map of the
+// VRGs, as namespace/name, to the namespaces they added to the constraint's excluded namespaces, so that only those
+// are removed once the recovered workloads are ready
+const policyExemptionsAnnotation = "volumereplicationgroups.ramendr.openshift.io/exempted-namespaces"
+
+const policyExemptionsTimeoutDefault = time.Hour
+
+var (
+	kyvernoPolicyExceptionGroupKind = schema.GroupKind{Group: "kyverno.io", Kind: "PolicyException"}
+
+	gatekeeperConstraintsGroup = "constraints.gatekeeper.sh"
+)
+
+func (v *VRGInstance) policyExemptions() []ramen.PolicyExemption {
+	if v.instance.Spec.KubeObjectProtection == nil {
+		return nil
+	}
+
+	return v.instance.Spec.KubeObjectProtection.PolicyExemptions
+}
+
+// policyExemptionAllowed returns whether the operator configuration allows the exemption: a Kyverno ClusterPolicy by
+// name, or a Gatekeeper constraint as kind/name
+func (v *VRGInstance) policyExemptionAllowed(exemption *ramen.PolicyExemption) bool {
+	name := exemption.Name
+	if exemption.Engine == ramen.PolicyEngineGatekeeper {
+		name = exemption.Kind + "/" + exemption.Name
+	}
+
+	return v.ramenConfig != nil && slices.Contains(v.ramenConfig.PolicyExemptionsAllowed, name)
+}
+
+func (v *VRGInstance) policyExemptionsTimeout() time.Duration {
+	if v.ramenConfig == nil || v.ramenConfig.PolicyExemptionsTimeout == nil {
+		return policyExemptionsTimeoutDefault
+	}
+
+	return v.ramenConfig.PolicyExemptionsTimeout.Duration
+}
+
+// policyExemptionsApply exempts the protected namespaces from the allowed admission policies of the VRG's exemptions,
+// for them not to refuse the objects about to be recovered. Exemptions not allowed, and of engines this cluster does
+// not serve, are skipped. The time they were first applied is recorded in the VRG's status until they are removed.
+func (v *VRGInstance) policyExemptionsApply() error {
+	exemptions := v.policyExemptions()
+	applied := false
+
+	for i := range exemptions {
+		exemption := &exemptions[i]
+
+		if !v.policyExemptionAllowed(exemption) {
+			msg := fmt.Sprintf("Policy exemption from %s %s not allowed", exemption.Engine, exemption.Name)
+
+			v.log.Info(msg)
+			rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+				rmnutil.EventReasonPolicyExemptionNotAllowed, msg)
+
+			continue
+		}
+
+		var err error
+
+		switch exemption.Engine {
+		case ramen.PolicyEngineKyverno:
+			err = v.kyvernoPolicyExceptionCreate(exemption)
+		case ramen.PolicyEngineGatekeeper:
+			err = v.gatekeeperConstraintExempt(exemption)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		applied = true
+	}
+
+	status := &v.instance.Status.KubeObjectProtection
+	if applied && status.PolicyExemptionsApplied == nil {
+		now := metav1.Now()
+		status.PolicyExemptionsApplied = &now
+	}
+
+	return nil
+}
+
+// policyExemptionsRemove removes the VRG's policy exemptions once the recovered workload is ready, or the policy
+// exemptions timeout elapsed since they were applied. If forced, as the VRG is deleted or demoted, they are removed
+// regardless, even if none are recorded as applied.
+func (v *VRGInstance) policyExemptionsRemove(force bool) error {
+	status := &v.instance.Status.KubeObjectProtection
+
+	if !force {
+		if status.PolicyExemptionsApplied == nil {
+			return nil
+		}
+
+		if elapsed := time.Since(status.PolicyExemptionsApplied.Time); elapsed < v.policyExemptionsTimeout() {
+			ready, err := v.workloadReady()
+			if err != nil || !ready {
+				return err
+			}
+		} else {
+			v.log.Info("Policy exemptions timed out before the recovered workload is ready", "elapsed", elapsed)
+		}
+	}
+
+	exceptions, err := v.kyvernoPolicyExceptionsList()
+	if err != nil {
+		return err
+	}
+
+	constraints, err := v.gatekeeperConstraintsExempted()
+	if err != nil {
+		return err
+	}
+
+	for i := range exceptions {
+		if err := v.reconciler.Delete(v.ctx, &exceptions[i]); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete policy exception %s/%s (%w)", exceptions[i].GetNamespace(),
+				exceptions[i].GetName(), err)
+		}
+
+		v.log.Info("Kyverno policy exception deleted", "name", exceptions[i].GetName())
+	}
+
+	for _, constraint := range constraints {
+		if err := v.gatekeeperConstraintUnexempt(constraint); err != nil {
+			return err
+		}
+	}
+
+	status.PolicyExemptionsApplied = nil
+
+	return nil
+}
+
+func (v *VRGInstance) kyvernoPolicyExceptionGroupVersionKind() (*schema.GroupVersionKind, error) {
+	mapping, err := v.reconciler.RESTMapper().RESTMapping(kyvernoPolicyExceptionGroupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to map kind %s (%w)", kyvernoPolicyExceptionGroupKind, err)
+	}
+
+	return &mapping.GroupVersionKind, nil
+}
+
+// kyvernoPolicyExceptionCreate creates a Kyverno PolicyException, in the VRG's namespace, of the rules of the
+// exemption's ClusterPolicy for the protected namespaces
+func (v *VRGInstance) kyvernoPolicyExceptionCreate(exemption *ramen.PolicyExemption) error {
+	gvk, err := v.kyvernoPolicyExceptionGroupVersionKind()
+	if err != nil || gvk == nil {
+		return err
+	}
+
+	ruleNames := exemption.Rules
+	if len(ruleNames) == 0 {
+		ruleNames = []string{"*"}
+	}
+
+	exception := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"exceptions": []interface{}{map[string]interface{}{
+				"policyName": exemption.Name,
+				"ruleNames":  stringsToInterfaces(ruleNames),
+			}},
+			"match": map[string]interface{}{
+				"any": []interface{}{map[string]interface{}{
+					"resources": map[string]interface{}{"namespaces": stringsToInterfaces(v.workloadNamespaces())},
+				}},
+			},
+		},
+	}}
+	exception.SetGroupVersionKind(*gvk)
+	exception.SetNamespace(v.instance.Namespace)
+	exception.SetName(v.instance.Name + "-" + exemption.Name)
+	exception.SetLabels(rmnutil.OwnerLabels(v.instance))
+
+	if err := v.reconciler.Create(v.ctx, exception); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to create policy exception %s/%s (%w)", exception.GetNamespace(),
+			exception.GetName(), err)
+	}
+
+	v.log.Info("Kyverno policy exception created", "name", exception.GetName(), "policy", exemption.Name)
+
+	return nil
+}
+
+// kyvernoPolicyExceptionsList returns the metadata of the Kyverno PolicyExceptions the VRG created, from the cache
+func (v *VRGInstance) kyvernoPolicyExceptionsList() ([]metav1.PartialObjectMetadata, error) {
+	gvk, err := v.kyvernoPolicyExceptionGroupVersionKind()
+	if err != nil || gvk == nil {
+		return nil, err
+	}
+
+	exceptions := &metav1.PartialObjectMetadataList{}
+	exceptions.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := v.reconciler.List(v.ctx, exceptions, client.InNamespace(v.instance.Namespace),
+		client.MatchingLabels(rmnutil.OwnerLabels(v.instance))); err != nil {
+		return nil, fmt.Errorf("failed to list policy exceptions in namespace %s (%w)", v.instance.Namespace, err)
+	}
+
+	for i := range exceptions.Items {
+		exceptions.Items[i].SetGroupVersionKind(*gvk)
+	}
+
+	return exceptions.Items, nil
+}
+
+// gatekeeperConstraintGet returns the Gatekeeper constraint of the exemption, for the VRG to patch, or nil if its
+// kind is not served, it is not found, or whether the VRG exempted its namespaces from it, as its cached metadata
+// records, differs from exempted. Only a constraint to patch is read from the API server.
+func (v *VRGInstance) gatekeeperConstraintGet(exemption *ramen.PolicyExemption, exempted bool,
+) (*unstructured.Unstructured, error) {
+	groupKind := schema.GroupKind{Group: gatekeeperConstraintsGroup, Kind: exemption.Kind}
+
+	mapping, err := v.reconciler.RESTMapper().RESTMapping(groupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to map kind %s (%w)", groupKind, err)
+	}
+
+	key := types.NamespacedName{Name: exemption.Name}
+	metadata := &metav1.PartialObjectMetadata{}
+	metadata.SetGroupVersionKind(mapping.GroupVersionKind)
+
+	if err := v.reconciler.Get(v.ctx, key, metadata); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s %s (%w)", exemption.Kind, exemption.Name, err)
+	}
+
+	if v.gatekeeperConstraintExempted(metadata) != exempted {
+		return nil, nil
+	}
+
+	constraint := &unstructured.Unstructured{}
+	constraint.SetGroupVersionKind(mapping.GroupVersionKind)
+
+	if err := v.reconciler.APIReader.Get(v.ctx, key, constraint); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to get %s %s (%w)", exemption.Kind, exemption.Name, err)
+	}
+
+	return constraint, nil
+}
+
+// gatekeeperConstraintExempted returns whether the constraint's annotation records namespaces the VRG exempted
+func (v *VRGInstance) gatekeeperConstraintExempted(constraint client.Object) bool {
+	exempted := map[string][]string{}
+	if err := json.Unmarshal([]byte(constraint.GetAnnotations()[policyExemptionsAnnotation]), &exempted); err != nil {
+		return false
+	}
+
+	_, ok := exempted[v.instance.Namespace+"/"+v.instance.Name]
+
+	return ok
+}
+
+// gatekeeperConstraintExempt adds the protected namespaces to the excluded namespaces of the exemption's Gatekeeper
+// constraint, and records the ones it added in the constraint's annotation
+func (v *VRGInstance) gatekeeperConstraintExempt(exemption *ramen.PolicyExemption) error {
+	constraint, err := v.gatekeeperConstraintGet(exemption, false)
+	if err != nil || constraint == nil {
+		return err
+	}
+
+	exempted, err := gatekeeperConstraintExemptedNamespaces(constraint)
+	if err != nil {
+		return err
+	}
+
+	key := v.instance.Namespace + "/" + v.instance.Name
+	if _, ok := exempted[key]; ok {
+		return nil
+	}
+
+	excluded, _, err := unstructured.NestedStringSlice(constraint.Object, "spec", "match", "excludedNamespaces")
+	if err != nil {
+		return fmt.Errorf("%s %s excluded namespaces invalid (%w)", exemption.Kind, exemption.Name, err)
+	}
+
+	added := []string{}
+
+	for _, namespace := range v.workloadNamespaces() {
+		if !slices.Contains(excluded, namespace) {
+			excluded = append(excluded, namespace)
+			added = append(added, namespace)
+		}
+	}
+
+	exempted[key] = added
+
+	return v.gatekeeperConstraintPatch(constraint, excluded, exempted)
+}
+
+// gatekeeperConstraintsExempted returns the Gatekeeper constraints of the VRG's exemptions that it exempted its
+// namespaces from
+func (v *VRGInstance) gatekeeperConstraintsExempted() ([]*unstructured.Unstructured, error) {
+	constraints := []*unstructured.Unstructured{}
+	exemptions := v.policyExemptions()
+
+	for i := range exemptions {
+		if exemptions[i].Engine != ramen.PolicyEngineGatekeeper {
+			continue
+		}
+
+		constraint, err := v.gatekeeperConstraintGet(&exemptions[i], true)
+		if err != nil {
+			return nil, err
+		}
+
+		if constraint != nil {
+			constraints = append(constraints, constraint)
+		}
+	}
+
+	return constraints, nil
+}
+
+// gatekeeperConstraintUnexempt removes the namespaces the VRG added from the excluded namespaces of the Gatekeeper
+// constraint, except for those another VRG also added
+func (v *VRGInstance) gatekeeperConstraintUnexempt(constraint *unstructured.Unstructured) error {
+	exempted, err := gatekeeperConstraintExemptedNamespaces(constraint)
+	if err != nil {
+		return err
+	}
+
+	key := v.instance.Namespace + "/" + v.instance.Name
+	added := exempted[key]
+
+	delete(exempted, key)
+
+	excluded, _, err := unstructured.NestedStringSlice(constraint.Object, "spec", "match", "excludedNamespaces")
+	if err != nil {
+		return fmt.Errorf("%s %s excluded namespaces invalid (%w)", constraint.GetKind(), constraint.GetName(), err)
+	}
+
+	remaining := make([]string, 0, len(excluded))
+
+	for _, namespace := range excluded {
+		if !slices.Contains(added, namespace) || gatekeeperNamespaceExempted(exempted, namespace) {
+			remaining = append(remaining, namespace)
+		}
+	}
+
+	return v.gatekeeperConstraintPatch(constraint, remaining, exempted)
+}
+
+// gatekeeperNamespaceExempted returns whether a VRG added the namespace to a constraint's excluded namespaces
+func gatekeeperNamespaceExempted(exempted map[string][]string, namespace string) bool {
+	for _, namespaces := range exempted {
+		if slices.Contains(namespaces, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *VRGInstance) gatekeeperConstraintPatch(constraint *unstructured.Unstructured, excluded []string,
+	exempted map[string][]string,
+) error {
+	patched := constraint.DeepCopy()
+
+	if err := unstructured.SetNestedStringSlice(patched.Object, excluded, "spec", "match",
+		"excludedNamespaces"); err != nil {
+		return err
+	}
+
+	annotations := patched.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if len(exempted) == 0 {
+		delete(annotations, policyExemptionsAnnotation)
+	} else {
+		value, err := json.Marshal(exempted)
+		if err != nil {
+			return err
+		}
+
+		annotations[policyExemptionsAnnotation] = string(value)
+	}
+
+	patched.SetAnnotations(annotations)
+
+	if err := v.reconciler.Patch(v.ctx, patched, client.MergeFrom(constraint)); err != nil {
+		return fmt.Errorf("failed to patch %s %s excluded namespaces (%w)", constraint.GetKind(),
+			constraint.GetName(), err)
+	}
+
+	v.log.Info("Gatekeeper constraint excluded namespaces patched", "kind", constraint.GetKind(),
+		"name", constraint.GetName(), "excludedNamespaces", excluded)
+
+	return nil
+}
+
+func gatekeeperConstraintExemptedNamespaces(constraint *unstructured.Unstructured) (map[string][]string, error) {
+	exempted := map[string][]string{}
+
+	value, ok := constraint.GetAnnotations()[policyExemptionsAnnotation]
+	if !ok {
+		return exempted, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &exempted); err != nil {
+		return nil, fmt.Errorf("%s %s annotation %s invalid (%w)", constraint.GetKind(), constraint.GetName(),
+			policyExemptionsAnnotation, err)
+	}
+
+	return exempted, nil
+}
+
+// workloadReady returns whether the deployments of the protected namespaces are available and their stateful sets'
+// replicas are ready
+func (v *VRGInstance) workloadReady() (bool, error) {
+	for _, namespace := range v.workloadNamespaces() {
+		deployments := &appsv1.DeploymentList{}
+		if err := v.reconciler.APIReader.List(v.ctx, deployments, client.InNamespace(namespace)); err != nil {
+			return false, fmt.Errorf("failed to list deployments in namespace %s (%w)", namespace, err)
+		}
+
+		for i := range deployments.Items {
+			if !deploymentAvailable(&deployments.Items[i]) {
+				return false, nil
+			}
+		}
+
+		statefulSets := &appsv1.StatefulSetList{}
+		if err := v.reconciler.APIReader.List(v.ctx, statefulSets, client.InNamespace(namespace)); err != nil {
+			return false, fmt.Errorf("failed to list stateful sets in namespace %s (%w)", namespace, err)
+		}
+
+		for i := range statefulSets.Items {
+			statefulSet := &statefulSets.Items[i]
+			if statefulSet.Spec.Replicas != nil && statefulSet.Status.ReadyReplicas < *statefulSet.Spec.Replicas {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	interfaces := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		interfaces = append(interfaces, value)
+	}
+
+	return interfaces
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the exemptions of the protected namespaces from admission policies while recovered
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("VRG_PolicyExemptions", func() {
+	var (
+		c          client.Client
+		v          *VRGInstance
+		deployment *appsv1.Deployment
+	)
+
+	constraintGVK := schema.GroupVersionKind{
+		Group: gatekeeperConstraintsGroup, Version: "v1beta1", Kind: "K8sRequiredLabels",
+	}
+	excludedNamespaces := func() []string {
+		constraint := &unstructured.Unstructured{}
+		constraint.SetGroupVersionKind(constraintGVK)
+		Expect(c.Get(context.TODO(), client.ObjectKey{Name: "must-have-owner"}, constraint)).To(Succeed())
+
+		excluded, _, err := unstructured.NestedStringSlice(constraint.Object, "spec", "match", "excludedNamespaces")
+		Expect(err).ToNot(HaveOccurred())
+
+		return excluded
+	}
+	deploymentAvailableSet := func() {
+		deployment.Status.Conditions = []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		}
+		Expect(c.Status().Update(context.TODO(), deployment)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{constraintGVK.GroupVersion()})
+		restMapper.Add(constraintGVK, meta.RESTScopeRoot)
+
+		constraint := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"match": map[string]interface{}{"excludedNamespaces": []interface{}{"kube-system"}},
+			},
+		}}
+		constraint.SetGroupVersionKind(constraintGVK)
+		constraint.SetName("must-have-owner")
+
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "shop"}}
+		c = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(restMapper).
+			WithObjects(constraint, deployment).WithStatusSubresource(deployment).Build()
+		v = &VRGInstance{
+			reconciler: &VolumeReplicationGroupReconciler{
+				Client:        c,
+				APIReader:     c,
+				eventRecorder: rmnutil.NewEventReporter(record.NewFakeRecorder(10)),
+			},
+			ctx:         context.TODO(),
+			log:         ctrl.Log.WithName("vrg-policy-exemptions-test"),
+			ramenConfig: &ramen.RamenConfig{PolicyExemptionsAllowed: []string{"K8sRequiredLabels/must-have-owner"}},
+			instance: &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+				Spec: ramen.VolumeReplicationGroupSpec{KubeObjectProtection: &ramen.KubeObjectProtectionSpec{
+					PolicyExemptions: []ramen.PolicyExemption{
+						{Engine: ramen.PolicyEngineGatekeeper, Kind: "K8sRequiredLabels", Name: "must-have-owner"},
+						{Engine: ramen.PolicyEngineKyverno, Name: "require-labels"},
+					},
+				}},
+			},
+		}
+	})
+
+	It("exempts the protected namespaces from the allowed policies once, and records when", func() {
+		Expect(v.policyExemptionsApply()).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "app"}))
+
+		applied := v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied
+		Expect(applied).ToNot(BeNil())
+
+		Expect(v.policyExemptionsApply()).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "app"}))
+		Expect(v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied).To(Equal(applied))
+	})
+
+	It("exempts the protected namespaces from no policy not allowed", func() {
+		v.ramenConfig.PolicyExemptionsAllowed = nil
+
+		Expect(v.policyExemptionsApply()).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system"}))
+		Expect(v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied).To(BeNil())
+	})
+
+	It("removes the exemptions once the recovered workload is ready", func() {
+		Expect(v.policyExemptionsApply()).To(Succeed())
+
+		Expect(v.policyExemptionsRemove(false)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "app"}))
+		Expect(v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied).ToNot(BeNil())
+
+		deploymentAvailableSet()
+		Expect(v.policyExemptionsRemove(false)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system"}))
+		Expect(v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied).To(BeNil())
+	})
+
+	It("removes the exemptions once they timed out, if the recovered workload is not ready", func() {
+		Expect(v.policyExemptionsApply()).To(Succeed())
+
+		applied := metav1.NewTime(time.Now().Add(-policyExemptionsTimeoutDefault))
+		v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied = &applied
+		Expect(v.policyExemptionsRemove(false)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system"}))
+
+		v.ramenConfig.PolicyExemptionsTimeout = &metav1.Duration{Duration: 2 * policyExemptionsTimeoutDefault}
+		Expect(v.policyExemptionsTimeout()).To(Equal(2 * policyExemptionsTimeoutDefault))
+	})
+
+	It("removes no exemptions not recorded as applied, unless forced", func() {
+		Expect(v.policyExemptionsApply()).To(Succeed())
+		deploymentAvailableSet()
+
+		v.instance.Status.KubeObjectProtection.PolicyExemptionsApplied = nil
+		Expect(v.policyExemptionsRemove(false)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "app"}))
+
+		Expect(v.policyExemptionsRemove(true)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system"}))
+	})
+
+	It("keeps the namespaces another VRG exempted", func() {
+		Expect(v.policyExemptionsApply()).To(Succeed())
+
+		other := &VRGInstance{
+			reconciler: v.reconciler, ctx: v.ctx, log: v.log, ramenConfig: v.ramenConfig,
+			instance: v.instance.DeepCopy(),
+		}
+		other.instance.Name = "other"
+		other.instance.Spec.ProtectedNamespaces = &[]string{"app", "web"}
+		Expect(other.policyExemptionsApply()).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "app", "web"}))
+
+		Expect(v.policyExemptionsRemove(true)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system", "web"}))
+
+		Expect(other.policyExemptionsRemove(true)).To(Succeed())
+		Expect(excludedNamespaces()).To(Equal([]string{"kube-system"}))
+	})
+})
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// cleanupPVForRestore cleans up the PV for restore, and translates its topology for this cluster
+func (v *VRGInstance) cleanupPVForRestore(pv *corev1.PersistentVolume) {
+	cleanupPVForRestore(pv)
+
+	if len(v.instance.Spec.TopologyTranslations) == 0 {
+		return
+	}
+
+	if pvTopologyTranslate(pv, v.instance.Spec.TopologyTranslations) {
+		v.log.Info("PV topology translated", "PV", pv.GetName())
+	}
+}
+
+// pvTopologyTranslate rewrites the required node affinity terms and the topology labels of the PV, and returns true
+// if any were rewritten
+func pvTopologyTranslate(pv *corev1.PersistentVolume, translations []ramen.TopologyTranslation) bool {
+	translated := false
+
+	if pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+		terms := pv.Spec.NodeAffinity.Required.NodeSelectorTerms
+		for i := range terms {
+			for j := range terms[i].MatchExpressions {
+				translated = nodeSelectorRequirementTranslate(&terms[i].MatchExpressions[j], translations) ||
+					translated
+			}
+		}
+	}
+
+	labels := map[string]string{}
+
+	for key, value := range pv.GetLabels() {
+		translation := topologyTranslationFind(translations, key, value)
+		if translation == nil {
+			labels[key] = value
+
+			continue
+		}
+
+		targetKey, targetValue := topologyTranslationTarget(translation, key, value)
+		labels[targetKey] = targetValue
+		translated = true
+	}
+
+	if translated {
+		pv.SetLabels(labels)
+	}
+
+	return translated
+}
+
+func nodeSelectorRequirementTranslate(requirement *corev1.NodeSelectorRequirement,
+	translations []ramen.TopologyTranslation,
+) bool {
+	targetKey := ""
+	values := make([]string, 0, len(requirement.Values))
+
+	for _, value := range requirement.Values {
+		translation := topologyTranslationFind(translations, requirement.Key, value)
+		if translation == nil {
+			values = append(values, value)
+
+			continue
+		}
+
+		key, targetValue := topologyTranslationTarget(translation, requirement.Key, value)
+		if targetKey == "" {
+			targetKey = key
+		}
+
+		if !slices.Contains(values, targetValue) {
+			values = append(values, targetValue)
+		}
+	}
+
+	// Exists and DoesNotExist requirements have no values, and translate their key only
+	if len(requirement.Values) == 0 {
+		if translation := topologyTranslationFind(translations, requirement.Key, ""); translation != nil {
+			targetKey, _ = topologyTranslationTarget(translation, requirement.Key, "")
+		}
+	}
+
+	if targetKey == "" {
+		return false
+	}
+
+	requirement.Key = targetKey
+	requirement.Values = values
+
+	return true
+}
+
+// topologyTranslationFind returns the translation of the value of the key, preferring one for the value over one
+// for all values
+func topologyTranslationFind(translations []ramen.TopologyTranslation, key, value string,
+) *ramen.TopologyTranslation {
+	var match *ramen.TopologyTranslation
+
+	for i := range translations {
+		translation := &translations[i]
+		if translation.Key != key {
+			continue
+		}
+
+		if translation.Value == value && value != "" {
+			return translation
+		}
+
+		if translation.Value == "" && match == nil {
+			match = translation
+		}
+	}
+
+	return match
+}
+
+func topologyTranslationTarget(translation *ramen.TopologyTranslation, key, value string) (string, string) {
+	if translation.TargetKey != "" {
+		key = translation.TargetKey
+	}
+
+	if translation.TargetValue != "" {
+		value = translation.TargetValue
+	}
+
+	return key, value
+}
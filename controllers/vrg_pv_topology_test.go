@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the translation of PV topologies
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_PVTopology", func() {
+	const zoneKey = "topology.kubernetes.io/zone"
+
+	translations := []ramen.TopologyTranslation{
+		{Key: zoneKey, Value: "us-east-1a", TargetValue: "eu-west-1a"},
+		{Key: zoneKey, TargetValue: "eu-west-1b"},
+		{Key: "topology.rbd.csi.ceph.com/zone", TargetKey: "topology.cephfs.csi.ceph.com/zone"},
+	}
+
+	pv := func(expressions ...corev1.NodeSelectorRequirement) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pv",
+				Labels: map[string]string{zoneKey: "us-east-1a", "app": "db"},
+			},
+			Spec: corev1.PersistentVolumeSpec{
+				NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: expressions}},
+				}},
+			},
+		}
+	}
+
+	Describe("topologyTranslationFind", func() {
+		It("prefers the translation of the value over the translation of all values", func() {
+			Expect(topologyTranslationFind(translations, zoneKey, "us-east-1a")).To(Equal(&translations[0]))
+			Expect(topologyTranslationFind(translations, zoneKey, "us-east-1c")).To(Equal(&translations[1]))
+			Expect(topologyTranslationFind(translations, "kubernetes.io/hostname", "node")).To(BeNil())
+		})
+	})
+
+	Describe("pvTopologyTranslate", func() {
+		It("translates the values of the node affinity and of the labels, without duplicates", func() {
+			translated := pv(corev1.NodeSelectorRequirement{
+				Key:      zoneKey,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			})
+
+			Expect(pvTopologyTranslate(translated, translations)).To(BeTrue())
+			Expect(translated.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values).To(
+				Equal([]string{"eu-west-1a", "eu-west-1b"}))
+			Expect(translated.GetLabels()).To(Equal(map[string]string{zoneKey: "eu-west-1a", "app": "db"}))
+		})
+
+		It("translates the keys of the requirements without values", func() {
+			translated := pv(corev1.NodeSelectorRequirement{
+				Key:      "topology.rbd.csi.ceph.com/zone",
+				Operator: corev1.NodeSelectorOpExists,
+			})
+			translated.Labels = nil
+
+			Expect(pvTopologyTranslate(translated, translations)).To(BeTrue())
+			Expect(translated.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Key).To(
+				Equal("topology.cephfs.csi.ceph.com/zone"))
+		})
+
+		It("leaves a PV without translated topology unchanged", func() {
+			untranslated := pv(corev1.NodeSelectorRequirement{
+				Key:      "kubernetes.io/hostname",
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   []string{"node"},
+			})
+			untranslated.Labels = map[string]string{"app": "db"}
+			expected := untranslated.DeepCopy()
+
+			Expect(pvTopologyTranslate(untranslated, translations)).To(BeFalse())
+			Expect(untranslated).To(Equal(expected))
+
+			untranslated.Spec.NodeAffinity = nil
+			Expect(pvTopologyTranslate(untranslated, translations)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the PVCs skipped from protection by their and their storage classes' annotations
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+var _ = Describe("VRG_PVCFilter", func() {
+	storageClass := func(name string, annotations map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+
+	DescribeTable("storageClassExcluded and storageClassRequiresVolSync",
+		func(annotations map[string]string, excluded, requiresVolSync bool) {
+			Expect(storageClassExcluded(storageClass("sc", annotations))).To(Equal(excluded))
+			Expect(storageClassRequiresVolSync(storageClass("sc", annotations))).To(Equal(requiresVolSync))
+		},
+		Entry("no annotations", nil, false, false),
+		Entry("excluded", map[string]string{StorageClassDRExcludedAnnotation: "true"}, true, false),
+		Entry("not excluded", map[string]string{StorageClassDRExcludedAnnotation: "false"}, false, false),
+		Entry("requiring VolSync", map[string]string{StorageClassDRRequiresVolSyncAnnotation: "true"}, false, true),
+		Entry("not requiring VolSync", map[string]string{StorageClassDRRequiresVolSyncAnnotation: "yes"}, false, false),
+	)
+
+	Describe("filterPVCsUsingAnnotations", func() {
+		pvc := func(name string, storageClassName *string, annotations map[string]string) corev1.PersistentVolumeClaim {
+			return corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Annotations: annotations},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: storageClassName},
+			}
+		}
+		className := func(name string) *string {
+			return &name
+		}
+		skipped := func(name, reason string) ramen.SkippedPVC {
+			return ramen.SkippedPVC{Namespace: "app", Name: name, Reason: reason}
+		}
+
+		DescribeTable("selects the PVCs to protect, and reports those skipped in the status",
+			func(spec ramen.VolumeReplicationGroupSpec, selected []string, expected []ramen.SkippedPVC) {
+				c := fake.NewClientBuilder().WithObjects(
+					storageClass("standard", nil),
+					storageClass("scratch", map[string]string{StorageClassDRExcludedAnnotation: "true"}),
+					storageClass("cephfs", map[string]string{StorageClassDRRequiresVolSyncAnnotation: "true"}),
+				).Build()
+				vrgInstance := vrgInstanceFake(c, "vrg-pvc-filter-test", spec)
+				vrgInstance.reconciler.eventRecorder = rmnutil.NewEventReporter(record.NewFakeRecorder(10))
+				vrgInstance.storageClassCache = map[string]*storagev1.StorageClass{}
+				vrgInstance.instance.Status.SkippedPVCs = []ramen.SkippedPVC{skipped("gone", "PVCExcluded")}
+
+				pvcList := &corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{
+					pvc("data", className("standard"), nil),
+					pvc("cache", className("standard"), map[string]string{PVCDRExcludedAnnotation: "true"}),
+					pvc("logs", className("standard"), map[string]string{PVCDRExcludedAnnotation: "false"}),
+					pvc("tmp", className("scratch"), nil),
+					pvc("shared", className("cephfs"), nil),
+					pvc("unclassed", nil, nil),
+					pvc("default-class", className(""), nil),
+					pvc("unknown-class", className("missing"), nil),
+				}}
+
+				vrgInstance.filterPVCsUsingAnnotations(pvcList)
+
+				names := []string{}
+				for i := range pvcList.Items {
+					names = append(names, pvcList.Items[i].Name)
+				}
+
+				Expect(names).To(Equal(selected))
+
+				for i := range vrgInstance.instance.Status.SkippedPVCs {
+					Expect(vrgInstance.instance.Status.SkippedPVCs[i].Message).ToNot(BeEmpty())
+					vrgInstance.instance.Status.SkippedPVCs[i].Message = ""
+				}
+
+				Expect(vrgInstance.instance.Status.SkippedPVCs).To(Equal(expected))
+			},
+			Entry("with VolSync", ramen.VolumeReplicationGroupSpec{Async: &ramen.VRGAsyncSpec{}},
+				[]string{"data", "logs", "shared", "unclassed", "default-class", "unknown-class"},
+				[]ramen.SkippedPVC{
+					skipped("cache", ramen.SkippedPVCReasonPVCExcluded),
+					skipped("tmp", ramen.SkippedPVCReasonStorageClassExcluded),
+				},
+			),
+			Entry("with VolSync disabled", ramen.VolumeReplicationGroupSpec{
+				Async: &ramen.VRGAsyncSpec{}, VolSync: ramen.VolSyncSpec{Disabled: true},
+			},
+				[]string{"data", "logs", "unclassed", "default-class", "unknown-class"},
+				[]ramen.SkippedPVC{
+					skipped("cache", ramen.SkippedPVCReasonPVCExcluded),
+					skipped("tmp", ramen.SkippedPVCReasonStorageClassExcluded),
+					skipped("shared", ramen.SkippedPVCReasonVolSyncRequired),
+				},
+			),
+			Entry("without async replication", ramen.VolumeReplicationGroupSpec{},
+				[]string{"data", "logs", "unclassed", "default-class", "unknown-class"},
+				[]ramen.SkippedPVC{
+					skipped("cache", ramen.SkippedPVCReasonPVCExcluded),
+					skipped("tmp", ramen.SkippedPVCReasonStorageClassExcluded),
+					skipped("shared", ramen.SkippedPVCReasonVolSyncRequired),
+				},
+			),
+		)
+	})
+})
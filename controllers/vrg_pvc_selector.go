@@ -4,8 +4,14 @@
 package controllers
 
 import (
+	"fmt"
+
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type PvcSelector struct {
@@ -44,3 +50,124 @@ func getPVCSelector(vrg ramen.VolumeReplicationGroup, ramenConfig ramen.RamenCon
 
 	return selector
 }
+
+func pvcExcluded(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.GetAnnotations()[PVCDRExcludedAnnotation] == "true"
+}
+
+func storageClassExcluded(storageClass *storagev1.StorageClass) bool {
+	return storageClass.GetAnnotations()[StorageClassDRExcludedAnnotation] == "true"
+}
+
+func storageClassRequiresVolSync(storageClass *storagev1.StorageClass) bool {
+	return storageClass.GetAnnotations()[StorageClassDRRequiresVolSyncAnnotation] == "true"
+}
+
+// cephFSReadWriteManySnapshotProtected returns true if the PVC is a ReadWriteMany CephFS PVC that the VRG is
+// configured to protect using VolSync snapshots instead of volume replication
+func (v *VRGInstance) cephFSReadWriteManySnapshotProtected(pvc *corev1.PersistentVolumeClaim,
+	storageClass *storagev1.StorageClass,
+) bool {
+	moverConfig := v.instance.Spec.VolSync.MoverConfig
+	if moverConfig == nil || !moverConfig.CephFSReadWriteManySnapshots ||
+		v.instance.Spec.Async == nil || v.instance.Spec.VolSync.Disabled {
+		return false
+	}
+
+	if storageClass.Provisioner != cephFSCSIDriverNameOrDefault(v.ramenConfig) {
+		return false
+	}
+
+	for _, accessMode := range pvc.Spec.AccessModes {
+		if accessMode == corev1.ReadWriteMany {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterPVCsUsingAnnotations removes PVCs that are annotated as excluded from protection, or whose storage class
+// excludes them from protection or requires a protection mechanism that the VRG cannot use, from the list. Removed
+// PVCs are reported in the VRG status with the reason they were skipped.
+func (v *VRGInstance) filterPVCsUsingAnnotations(pvcList *corev1.PersistentVolumeClaimList) {
+	volSyncAvailable := v.instance.Spec.Async != nil && !v.instance.Spec.VolSync.Disabled
+	selected := make([]corev1.PersistentVolumeClaim, 0, len(pvcList.Items))
+	var skipped []ramen.SkippedPVC
+
+	for idx := range pvcList.Items {
+		pvc := &pvcList.Items[idx]
+
+		if pvcExcluded(pvc) {
+			skipped = append(skipped, ramen.SkippedPVC{
+				Namespace: pvc.GetNamespace(),
+				Name:      pvc.GetName(),
+				Reason:    ramen.SkippedPVCReasonPVCExcluded,
+				Message:   fmt.Sprintf("PVC is annotated %s", PVCDRExcludedAnnotation),
+			})
+
+			continue
+		}
+
+		storageClass := v.storageClassForPVC(pvc)
+		if storageClass == nil {
+			// Let the PVC through, protection reports any storage class errors
+			selected = append(selected, *pvc)
+
+			continue
+		}
+
+		switch {
+		case storageClassExcluded(storageClass):
+			skipped = append(skipped, ramen.SkippedPVC{
+				Namespace: pvc.GetNamespace(),
+				Name:      pvc.GetName(),
+				Reason:    ramen.SkippedPVCReasonStorageClassExcluded,
+				Message:   fmt.Sprintf("StorageClass %s is annotated as excluded from DR", storageClass.GetName()),
+			})
+		case storageClassRequiresVolSync(storageClass) && !volSyncAvailable:
+			skipped = append(skipped, ramen.SkippedPVC{
+				Namespace: pvc.GetNamespace(),
+				Name:      pvc.GetName(),
+				Reason:    ramen.SkippedPVCReasonVolSyncRequired,
+				Message: fmt.Sprintf("StorageClass %s requires VolSync, which is not in use by the VRG",
+					storageClass.GetName()),
+			})
+		default:
+			selected = append(selected, *pvc)
+		}
+	}
+
+	if len(skipped) != 0 {
+		v.log.Info("Skipping PVCs based on annotations", "skipped", skipped)
+
+		rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+			rmnutil.EventReasonPVCSkipped, fmt.Sprintf("%d PVC(s) skipped from protection", len(skipped)))
+	}
+
+	pvcList.Items = selected
+	v.instance.Status.SkippedPVCs = skipped
+}
+
+// storageClassForPVC returns the storage class of a PVC, or nil if it cannot be determined
+func (v *VRGInstance) storageClassForPVC(pvc *corev1.PersistentVolumeClaim) *storagev1.StorageClass {
+	scName := pvc.Spec.StorageClassName
+	if scName == nil || *scName == "" {
+		return nil
+	}
+
+	if storageClass, ok := v.storageClassCache[*scName]; ok {
+		return storageClass
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := v.reconciler.Get(v.ctx, types.NamespacedName{Name: *scName}, storageClass); err != nil {
+		v.log.Info("Failed to get the storageclass", "name", *scName, "error", err)
+
+		return nil
+	}
+
+	v.storageClassCache[*scName] = storageClass
+
+	return storageClass
+}
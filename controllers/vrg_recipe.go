@@ -16,6 +16,7 @@ import (
 	"github.com/ramendr/ramen/controllers/util"
 	recipe "github.com/ramendr/recipe/api/v1alpha1"
 	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -47,14 +48,96 @@ func captureWorkflowDefault(vrg ramen.VolumeReplicationGroup, ramenConfig ramen.
 		},
 	}
 
+	// The release Secrets of Helm are captured with the objects selected, for helm to manage the recovered releases
 	if vrg.Spec.KubeObjectProtection.KubeObjectSelector != nil {
-		captureSpecs[0].Spec.LabelSelector = vrg.Spec.KubeObjectProtection.KubeObjectSelector
+		captureSpecs[0].Spec.OrLabelSelectors = []*metav1.LabelSelector{
+			vrg.Spec.KubeObjectProtection.KubeObjectSelector,
+			helmReleaseSecretSelector(),
+		}
 	}
 
 	return captureSpecs
 }
 
-func recoverWorkflowDefault() []kubeobjects.RecoverSpec { return []kubeobjects.RecoverSpec{{}} }
+// identityResources are the kinds of the identities the workloads in a namespace run as: their service accounts,
+// the roles bound to them, and the secrets they pull images with. Secrets cannot be told apart by type in a
+// resource filter, so all of them are included.
+var identityResources = []string{
+	"serviceaccounts",
+	"roles.rbac.authorization.k8s.io",
+	"rolebindings.rbac.authorization.k8s.io",
+	"secrets",
+}
+
+// networkResources are the kinds of the network policies of the workloads in a namespace, and of the cluster
+// network prerequisites they attach to or egress through. NetworkAttachmentDefinitions and EgressFirewalls are
+// captured with the other objects, so if the VRG selects its kube objects they are only captured when labeled to
+// match its selector.
+var networkResources = []string{
+	"networkpolicies.networking.k8s.io",
+	"egressfirewalls.k8s.ovn.org",
+	"network-attachment-definitions.k8s.cni.cncf.io",
+}
+
+// operatorResources are the kinds of the operators subscribed to in a namespace. The cluster service versions and
+// install plans are not, as OLM creates them again for the subscriptions.
+var operatorResources = []string{
+	"catalogsources.operators.coreos.com",
+	"operatorgroups.operators.coreos.com",
+	"subscriptions.operators.coreos.com",
+}
+
+// recoverWorkflowDefault recovers the network policies and prerequisites first, so that the workloads do not start
+// with broken or unrestricted networking, and then the other kube objects. If configured to recover the identities
+// first, it recovers them before anything else, so that the workloads do not fail to start for lack of them. If
+// configured to recover the operators first, it recovers the operators before the other kube objects, and them once
+// the operators are ready, including the custom resources of the operators.
+func recoverWorkflowDefault(ramenConfig ramen.RamenConfig) []kubeobjects.RecoverSpec {
+	workflow := []kubeobjects.RecoverSpec{}
+	excludedResources := []string{}
+
+	if ramenConfig.KubeObjectProtection.IdentitiesRecoverFirst {
+		workflow = append(workflow, kubeobjects.RecoverSpec{
+			Spec: kubeobjects.Spec{
+				KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+					IncludedResources: identityResources,
+				},
+			},
+		})
+		excludedResources = append(excludedResources, identityResources...)
+	}
+
+	workflow = append(workflow, kubeobjects.RecoverSpec{
+		Spec: kubeobjects.Spec{
+			KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				IncludedResources: networkResources,
+			},
+		},
+	})
+	excludedResources = append(excludedResources, networkResources...)
+
+	if ramenConfig.KubeObjectProtection.OperatorsRecoverFirst {
+		workflow = append(workflow, kubeobjects.RecoverSpec{
+			Spec: kubeobjects.Spec{
+				KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+					IncludedResources: operatorResources,
+				},
+			},
+		})
+		excludedResources = append(append(excludedResources,
+			"clusterserviceversions.operators.coreos.com",
+			"installplans.operators.coreos.com",
+		), operatorResources...)
+	}
+
+	return append(workflow, kubeobjects.RecoverSpec{
+		Spec: kubeobjects.Spec{
+			KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+				ExcludedResources: excludedResources,
+			},
+		},
+	})
+}
 
 func GetPVCSelector(ctx context.Context, reader client.Reader, vrg ramen.VolumeReplicationGroup,
 	ramenConfig ramen.RamenConfig,
@@ -94,7 +177,8 @@ func recipeVolumesAndOptionallyWorkflowsGet(ctx context.Context, reader client.R
 		*recipeElements = RecipeElements{
 			PvcSelector:     getPVCSelector(vrg, ramenConfig, nil, nil),
 			CaptureWorkflow: captureWorkflowDefault(vrg, ramenConfig),
-			RecoverWorkflow: recoverWorkflowDefault(),
+			RecoverWorkflow: recoverWorkflowJobsSeparate(recoverWorkflowSecretsFirst(
+				recoverWorkflowAutoscalersFirst(recoverWorkflowDefault(ramenConfig), vrg), vrg)),
 		}
 
 		return nil
@@ -105,11 +189,25 @@ func recipeVolumesAndOptionallyWorkflowsGet(ctx context.Context, reader client.R
 		Name:      vrg.Spec.KubeObjectProtection.RecipeRef.Name,
 	}
 
+	if ramenConfig.OperatorProfile == ramen.OperatorProfileEdge {
+		return fmt.Errorf("recipe %v: recipes are not supported by the %s operator profile",
+			recipeNamespacedName.String(), ramen.OperatorProfileEdge)
+	}
+
 	recipe := recipe.Recipe{}
 	if err := reader.Get(ctx, recipeNamespacedName, &recipe); err != nil {
 		return fmt.Errorf("recipe %v get error: %w", recipeNamespacedName.String(), err)
 	}
 
+	return recipeElementsEvaluate(recipe, vrg, ramenConfig, log, recipeElements, workflowsGet)
+}
+
+// recipeElementsEvaluate expands a recipe with the parameters of a VRG referring to it, and evaluates the elements
+// the VRG protects and recovers with it
+func recipeElementsEvaluate(recipe recipe.Recipe, vrg ramen.VolumeReplicationGroup,
+	ramenConfig ramen.RamenConfig, log logr.Logger, recipeElements *RecipeElements,
+	workflowsGet func(recipe.Recipe, *RecipeElements, ramen.VolumeReplicationGroup, ramen.RamenConfig) error,
+) error {
 	if err := RecipeParametersExpand(&recipe, vrg.Spec.KubeObjectProtection.RecipeParameters, log); err != nil {
 		return err
 	}
@@ -145,7 +243,11 @@ func RecipeParametersExpand(recipe *recipe.Recipe, parameters map[string][]strin
 	}
 
 	s1 := string(bytes)
-	s2 := parametersExpand(s1, parameters)
+	s2, undefined := parametersExpand(s1, parameters)
+
+	if len(undefined) > 0 {
+		log.Info("Recipe parameters undefined, expanded to empty strings", "parameters", undefined)
+	}
 
 	if err = json.Unmarshal([]byte(s2), spec); err != nil {
 		return fmt.Errorf("recipe spec %v json unmarshal error: %w", s2, err)
@@ -156,12 +258,42 @@ func RecipeParametersExpand(recipe *recipe.Recipe, parameters map[string][]strin
 	return nil
 }
 
-func parametersExpand(s string, parameters map[string][]string) string {
-	return os.Expand(s, func(key string) string {
-		values := parameters[key]
+// RecipeParametersUndefined returns the names of the parameters a recipe refers to that have neither a value nor a
+// default
+func RecipeParametersUndefined(recipe recipe.Recipe, parameters map[string][]string) ([]string, error) {
+	bytes, err := json.Marshal(recipe.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("recipe %s json marshal error: %w", recipe.GetName(), err)
+	}
+
+	_, undefined := parametersExpand(string(bytes), parameters)
+
+	return undefined, nil
+}
+
+// parametersExpand replaces the references to parameters in s with their values, joined to expand to a list of
+// strings in json, and returns the names of those not defined. A reference of the form ${name:-default} expands
+// to default if the parameter has no values, so that a recipe can be shared by applications that
+// need not all define it.
+func parametersExpand(s string, parameters map[string][]string) (string, []string) {
+	undefined := []string{}
+
+	expanded := os.Expand(s, func(key string) string {
+		name, defaultValue, defaulted := strings.Cut(key, ":-")
+
+		values, ok := parameters[name]
+		if len(values) == 0 && defaulted {
+			return defaultValue
+		}
+
+		if !ok && !slices.Contains(undefined, name) {
+			undefined = append(undefined, name)
+		}
 
 		return strings.Join(values, `","`)
 	})
+
+	return expanded, undefined
 }
 
 func recipeWorkflowsGet(recipe recipe.Recipe, recipeElements *RecipeElements, vrg ramen.VolumeReplicationGroup,
@@ -179,7 +311,7 @@ func recipeWorkflowsGet(recipe recipe.Recipe, recipeElements *RecipeElements, vr
 	}
 
 	if recipe.Spec.RecoverWorkflow == nil {
-		recipeElements.RecoverWorkflow = recoverWorkflowDefault()
+		recipeElements.RecoverWorkflow = recoverWorkflowDefault(ramenConfig)
 	} else {
 		recipeElements.RecoverWorkflow, err = getRecoverGroups(recipe)
 		if err != nil {
@@ -187,6 +319,13 @@ func recipeWorkflowsGet(recipe recipe.Recipe, recipeElements *RecipeElements, vr
 		}
 	}
 
+	if recipe.Spec.CaptureWorkflow != nil {
+		recipeWorkflowsHelmReleasesAdd(recipeElements, vrg.Namespace)
+	}
+
+	recipeElements.RecoverWorkflow = recoverWorkflowJobsSeparate(recoverWorkflowSecretsFirst(
+		recoverWorkflowAutoscalersFirst(recipeElements.RecoverWorkflow, vrg), vrg))
+
 	return err
 }
 
@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the default recover workflow of VRGs without a recipe
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_RecoverWorkflowDefault", func() {
+	It("recovers the identities with the other objects unless configured to recover them first", func() {
+		ramenConfig := ramen.RamenConfig{}
+		workflow := recoverWorkflowDefault(ramenConfig)
+		Expect(workflow).To(HaveLen(2))
+		Expect(workflow[1].ExcludedResources).ToNot(ContainElements(identityResources))
+
+		ramenConfig.KubeObjectProtection.IdentitiesRecoverFirst = true
+		workflow = recoverWorkflowDefault(ramenConfig)
+		Expect(workflow).To(HaveLen(3))
+		Expect(workflow[0].IncludedResources).To(Equal(identityResources))
+		Expect(workflow[2].ExcludedResources).To(ContainElements(identityResources))
+	})
+
+	It("recovers the operators, and then their custom resources, only if configured to", func() {
+		ramenConfig := ramen.RamenConfig{}
+		ramenConfig.KubeObjectProtection.OperatorsRecoverFirst = true
+		workflow := recoverWorkflowDefault(ramenConfig)
+		Expect(workflow).To(HaveLen(3))
+		Expect(workflow[1].IncludedResources).To(Equal(operatorResources))
+		Expect(workflow[2].ExcludedResources).To(ContainElements(operatorResources))
+	})
+})
@@ -0,0 +1,372 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+	Recipe "github.com/ramendr/recipe/api/v1alpha1"
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RecipeHTTPHooksAnnotation is set on a recipe to the endpoint of each of its hooks of type http, keyed by hook
+// name, in json, as recipe hooks have no fields for them. For example:
+// {"change-ticket": {"url": "https://itsm.example.com/api/changes", "secretName": "itsm-auth", "retries": 3,
+// "expectedStatusCodes": [200, 201]}}
+const RecipeHTTPHooksAnnotation = "ramendr.openshift.io/recipe-http-hooks"
+
+const (
+	recipeHookTypeHTTP = "http"
+
+	hookCallTimeoutDefault = 10 * time.Second
+	// hookCallTimeoutMaximum bounds the timeout of a hook's operation, as its call runs in the background
+	hookCallTimeoutMaximum = time.Minute
+	// hookCallResponseBodyLengthMaximum is how much of the body of a failed call's response is reported
+	hookCallResponseBodyLengthMaximum = 256
+
+	// Keys of the secret of a hook of type http
+	hookCallSecretKeyToken    = "token"
+	hookCallSecretKeyUsername = "username"
+	hookCallSecretKeyPassword = "password"
+	hookCallSecretKeyCABundle = "ca.crt"
+)
+
+type recipeHTTPHook struct {
+	URL                 string `json:"url"`
+	SecretName          string `json:"secretName,omitempty"`
+	Retries             int32  `json:"retries,omitempty"`
+	ExpectedStatusCodes []int  `json:"expectedStatusCodes,omitempty"`
+}
+
+// HookCallPayload is the json body posted by the calls of the recipe hooks of type http
+type HookCallPayload struct {
+	// App is the namespace and name of the VRG protecting the application
+	App string `json:"app"`
+	// Action is the VRG's action, Failover or Relocate, if any
+	Action string `json:"action,omitempty"`
+	// Workflow is the recipe's workflow, capture or recover, the hook is a step of
+	Workflow string `json:"workflow"`
+	// Step is the name of the hook and its operation, as referred to by the workflow
+	Step string `json:"step"`
+	// Run identifies the capture or recovery, the same for the retries of a call
+	Run string `json:"run"`
+}
+
+// recipeHookHTTPSpec returns the endpoint a recipe hook calls, if it is of type http, from the recipe's annotation
+func recipeHookHTTPSpec(recipe Recipe.Recipe, hook Recipe.Hook, op Recipe.Operation,
+) (*kubeobjects.HookHTTPSpec, error) {
+	if hook.Type != recipeHookTypeHTTP {
+		return nil, nil
+	}
+
+	httpHooks := map[string]recipeHTTPHook{}
+
+	if annotation, ok := recipe.GetAnnotations()[RecipeHTTPHooksAnnotation]; ok {
+		if err := json.Unmarshal([]byte(annotation), &httpHooks); err != nil {
+			return nil, fmt.Errorf("recipe %s annotation %s json unmarshal error: %w",
+				recipe.GetName(), RecipeHTTPHooksAnnotation, err)
+		}
+	}
+
+	httpHook, ok := httpHooks[hook.Name]
+	if !ok || httpHook.URL == "" {
+		return nil, fmt.Errorf("recipe %s hook %s of type http has no url in annotation %s",
+			recipe.GetName(), hook.Name, RecipeHTTPHooksAnnotation)
+	}
+
+	return &kubeobjects.HookHTTPSpec{
+		HookName:            hook.Name,
+		URL:                 httpHook.URL,
+		SecretName:          httpHook.SecretName,
+		Retries:             httpHook.Retries,
+		ExpectedStatusCodes: httpHook.ExpectedStatusCodes,
+		OnErrorContinue:     recipeHookOnErrorContinue(hook, op),
+	}, nil
+}
+
+// hookCallInFlight is a call of a hook's endpoint running in the background, so that reconciles are not blocked
+// for the duration of the call
+type hookCallInFlight struct {
+	run        string
+	mutex      sync.Mutex
+	done       bool
+	statusCode int
+	err        error
+}
+
+// kubeObjectsHookCall calls a hook's endpoint for the capture or recovery named by prefix, once per reconcile until
+// a call succeeds or its retries are exhausted, and returns whether it completed. Each call starts in the
+// background, and its result is processed by a later reconcile. A call whose retries are exhausted returns an error,
+// and its attempts are reset so that it is called again when the capture or recovery is retried, unless its hook
+// continues on error. Its attempts and result are reported in the VRG's status.
+func (v *VRGInstance) kubeObjectsHookCall(hook kubeobjects.HookSpec, workflow, prefix string, log logr.Logger,
+) (bool, error) {
+	status := v.hookCallStatus(hook.HTTP.HookName+"/"+hook.Name, prefix)
+	log = log.WithValues("hook", status.Name, "url", hook.HTTP.URL)
+
+	if status.Succeeded || (hook.HTTP.OnErrorContinue && status.Attempts > hook.HTTP.Retries) {
+		return true, nil
+	}
+
+	done, statusCode, err := v.hookCallResult(hook, HookCallPayload{
+		App:      v.instance.Namespace + "/" + v.instance.Name,
+		Action:   string(v.instance.Spec.Action),
+		Workflow: workflow,
+		Step:     status.Name,
+		Run:      prefix,
+	}, log)
+	if !done {
+		return false, nil
+	}
+
+	now := metav1.Now()
+	status.Attempts++
+	status.StatusCode = int32(statusCode)
+	status.Time = &now
+	status.Message = ""
+
+	if err == nil {
+		status.Succeeded = true
+
+		log.Info("Hook called", "statusCode", statusCode, "attempts", status.Attempts)
+
+		return true, nil
+	}
+
+	status.Message = err.Error()
+
+	if status.Attempts <= hook.HTTP.Retries {
+		log.Info("Hook call failed, to be retried", "error", err, "attempts", status.Attempts)
+
+		return false, nil
+	}
+
+	if hook.HTTP.OnErrorContinue {
+		log.Info("Hook call failed, continuing", "error", err, "attempts", status.Attempts)
+
+		return true, nil
+	}
+
+	status.Attempts = 0
+
+	return false, fmt.Errorf("hook %s call failed: %w", status.Name, err)
+}
+
+// hookCallResult returns whether the call of a hook's endpoint for a run is done, and its result if it is. The call is
+// started in the background if none is running for the run, and is done immediately if it cannot be started.
+func (v *VRGInstance) hookCallResult(hook kubeobjects.HookSpec, payload HookCallPayload, log logr.Logger,
+) (bool, int, error) {
+	key := v.namespacedName + "/" + payload.Step
+
+	if value, ok := v.reconciler.hookCalls.Load(key); ok {
+		call, _ := value.(*hookCallInFlight)
+		if call.run == payload.Run {
+			call.mutex.Lock()
+			defer call.mutex.Unlock()
+
+			if !call.done {
+				log.Info("Hook call running")
+
+				return false, 0, nil
+			}
+
+			v.reconciler.hookCalls.Delete(key)
+
+			return true, call.statusCode, call.err
+		}
+	}
+
+	request, client, cancel, err := v.hookCallRequest(hook, payload)
+	if err != nil {
+		v.reconciler.hookCalls.Delete(key)
+
+		return true, 0, err
+	}
+
+	call := &hookCallInFlight{run: payload.Run}
+	v.reconciler.hookCalls.Store(key, call)
+
+	log.Info("Hook call started")
+
+	go func() {
+		defer cancel()
+
+		statusCode, err := hookCall(client, request, hook.HTTP.ExpectedStatusCodes)
+
+		call.mutex.Lock()
+		defer call.mutex.Unlock()
+
+		call.statusCode, call.err, call.done = statusCode, err, true
+	}()
+
+	return false, 0, nil
+}
+
+// hookCallStatus returns the status of a hook's calls for a run, reset if the run is not the one reported
+func (v *VRGInstance) hookCallStatus(name, run string) *ramen.KubeObjectsHookCallStatus {
+	hookCalls := &v.instance.Status.KubeObjectProtection.HookCalls
+
+	for i := range *hookCalls {
+		status := &(*hookCalls)[i]
+		if status.Name != name {
+			continue
+		}
+
+		if status.Run != run {
+			*status = ramen.KubeObjectsHookCallStatus{Name: name, Run: run}
+		}
+
+		return status
+	}
+
+	*hookCalls = append(*hookCalls, ramen.KubeObjectsHookCallStatus{Name: name, Run: run})
+
+	return &(*hookCalls)[len(*hookCalls)-1]
+}
+
+// hookCallRequest returns the request that posts a payload to a hook's endpoint, and the client to send it with,
+// and the function that cancels it once it is sent. The endpoint must be served over https, by one of the hosts
+// the operator is configured to call, with a certificate the CA bundle of the hook's secret verifies. Redirects
+// are not followed, so that they cannot lead to another host.
+func (v *VRGInstance) hookCallRequest(hook kubeobjects.HookSpec, payload HookCallPayload,
+) (*http.Request, *http.Client, context.CancelFunc, error) {
+	endpoint, err := url.Parse(hook.HTTP.URL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("url parse error: %w", err)
+	}
+
+	if endpoint.Scheme != "https" {
+		return nil, nil, nil, fmt.Errorf("url scheme %q is not https", endpoint.Scheme)
+	}
+
+	if !hookCallHostAllowed(endpoint.Host, v.ramenConfig.KubeObjectProtection.HookCallHosts) {
+		return nil, nil, nil, fmt.Errorf("url host %s is not one of the hosts hooks are allowed to call",
+			endpoint.Host)
+	}
+
+	if hook.HTTP.SecretName == "" {
+		return nil, nil, nil, fmt.Errorf("no secret with the CA bundle to verify the endpoint with")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("payload json marshal error: %w", err)
+	}
+
+	timeout := hookCallTimeoutDefault
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+
+	if timeout > hookCallTimeoutMaximum {
+		timeout = hookCallTimeoutMaximum
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		cancel()
+
+		return nil, nil, nil, fmt.Errorf("request create error: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	if err := v.hookCallAuthenticate(hook.HTTP.SecretName, request, client); err != nil {
+		cancel()
+
+		return nil, nil, nil, err
+	}
+
+	return request, client, cancel, nil
+}
+
+// hookCallHostAllowed returns whether a host, as host or host:port, is one of a comma-separated list of them
+func hookCallHostAllowed(host, hosts string) bool {
+	for _, allowed := range strings.Split(hosts, ",") {
+		if allowed = strings.TrimSpace(allowed); allowed != "" && strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hookCall sends a request to a hook's endpoint, and returns the status code of its response, and an error if the
+// call failed or its status code is not expected
+func hookCall(client *http.Client, request *http.Request, expectedStatusCodes []int) (int, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+
+	defer response.Body.Close()
+
+	if hookCallStatusCodeExpected(response.StatusCode, expectedStatusCodes) {
+		return response.StatusCode, nil
+	}
+
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, hookCallResponseBodyLengthMaximum))
+
+	return response.StatusCode, fmt.Errorf("unexpected status %s: %s", response.Status, responseBody)
+}
+
+func hookCallStatusCodeExpected(statusCode int, expectedStatusCodes []int) bool {
+	if len(expectedStatusCodes) == 0 {
+		return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+	}
+
+	return slices.Contains(expectedStatusCodes, statusCode)
+}
+
+// hookCallAuthenticate sets a call's credentials, a bearer token or a username and password, and the CA bundle to
+// verify its endpoint with, which it requires, from a secret in the VRG's namespace
+func (v *VRGInstance) hookCallAuthenticate(secretName string, request *http.Request, client *http.Client) error {
+	secret := &corev1.Secret{}
+	if err := v.reconciler.APIReader.Get(v.ctx,
+		types.NamespacedName{Namespace: v.instance.Namespace, Name: secretName}, secret,
+	); err != nil {
+		return fmt.Errorf("secret %s/%s get error: %w", v.instance.Namespace, secretName, err)
+	}
+
+	if token, ok := secret.Data[hookCallSecretKeyToken]; ok {
+		request.Header.Set("Authorization", "Bearer "+string(token))
+	} else if username, ok := secret.Data[hookCallSecretKeyUsername]; ok {
+		request.SetBasicAuth(string(username), string(secret.Data[hookCallSecretKeyPassword]))
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(secret.Data[hookCallSecretKeyCABundle]) {
+		return fmt.Errorf("secret %s/%s key %s has no certificates", v.instance.Namespace, secretName,
+			hookCallSecretKeyCABundle)
+	}
+
+	client.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+	}
+
+	return nil
+}
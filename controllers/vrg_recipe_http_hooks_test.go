@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the calls of recipe hooks of type http
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_RecipeHTTPHooks", func() {
+	var (
+		server      *httptest.Server
+		calls       atomic.Int32
+		vrgInstance *VRGInstance
+		hook        kubeobjects.HookSpec
+	)
+
+	call := func() (bool, error) {
+		return vrgInstance.kubeObjectsHookCall(hook, "capture", "run-1", vrgInstance.log)
+	}
+	callCompleted := func() bool {
+		completed, err := call()
+		Expect(err).ToNot(HaveOccurred())
+
+		return completed
+	}
+
+	BeforeEach(func() {
+		calls.Store(0)
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+
+			if r.URL.Path == "/redirect" {
+				http.Redirect(w, r, "https://elsewhere.example.com/", http.StatusFound)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		serverURL, err := url.Parse(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		c := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "itsm"},
+			Data: map[string][]byte{
+				hookCallSecretKeyToken: []byte("token"),
+				hookCallSecretKeyCABundle: pem.EncodeToMemory(&pem.Block{
+					Type: "CERTIFICATE", Bytes: server.Certificate().Raw,
+				}),
+			},
+		}).Build()
+		ramenConfig := &ramen.RamenConfig{}
+		ramenConfig.KubeObjectProtection.HookCallHosts = "itsm.example.com, " + serverURL.Host
+		vrgInstance = &VRGInstance{
+			reconciler:     &VolumeReplicationGroupReconciler{Client: c, APIReader: c},
+			ctx:            context.TODO(),
+			log:            ctrl.Log.WithName("vrg-recipe-http-hooks-test"),
+			namespacedName: "app/vrg",
+			instance: &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+			},
+			ramenConfig: ramenConfig,
+		}
+		hook = kubeobjects.HookSpec{Name: "open", HookRun: kubeobjects.HookRun{HTTP: &kubeobjects.HookHTTPSpec{
+			HookName: "change-ticket", URL: server.URL + "/changes", SecretName: "itsm",
+		}}}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("calls an allowed https endpoint in the background, and completes once the call succeeded", func() {
+		Expect(callCompleted()).To(BeFalse())
+		Eventually(callCompleted).Should(BeTrue())
+		Expect(calls.Load()).To(Equal(int32(1)))
+
+		status := vrgInstance.instance.Status.KubeObjectProtection.HookCalls[0]
+		Expect(status.Succeeded).To(BeTrue())
+		Expect(status.StatusCode).To(Equal(int32(http.StatusCreated)))
+		Expect(status.Attempts).To(Equal(int32(1)))
+	})
+
+	It("does not follow a redirect", func() {
+		hook.HTTP.URL = server.URL + "/redirect"
+		hook.HTTP.OnErrorContinue = true
+		Expect(callCompleted()).To(BeFalse())
+		Eventually(callCompleted).Should(BeTrue())
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(vrgInstance.instance.Status.KubeObjectProtection.HookCalls[0].Succeeded).To(BeFalse())
+	})
+
+	It("fails a call of an endpoint not served over https without calling it", func() {
+		hook.HTTP.URL = "http" + server.URL[len("https"):]
+		_, err := call()
+		Expect(err).To(MatchError(ContainSubstring("not https")))
+		Expect(calls.Load()).To(BeZero())
+	})
+
+	It("fails a call of a host that is not allowed without calling it", func() {
+		vrgInstance.ramenConfig.KubeObjectProtection.HookCallHosts = "itsm.example.com"
+		_, err := call()
+		Expect(err).To(MatchError(ContainSubstring("not one of the hosts")))
+		Expect(calls.Load()).To(BeZero())
+	})
+
+	It("fails a call without a CA bundle to verify the endpoint with", func() {
+		hook.HTTP.SecretName = ""
+		_, err := call()
+		Expect(err).To(MatchError(ContainSubstring("CA bundle")))
+		Expect(calls.Load()).To(BeZero())
+	})
+
+	It("matches the allowed hosts exactly", func() {
+		Expect(hookCallHostAllowed("itsm.example.com", "ITSM.example.com")).To(BeTrue())
+		Expect(hookCallHostAllowed("itsm.example.com:8443", "itsm.example.com")).To(BeFalse())
+		Expect(hookCallHostAllowed("itsm.example.com.evil.com", "itsm.example.com")).To(BeFalse())
+		Expect(hookCallHostAllowed("", "")).To(BeFalse())
+	})
+})
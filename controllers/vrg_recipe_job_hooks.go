@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+	Recipe "github.com/ramendr/recipe/api/v1alpha1"
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RecipeJobHooksAnnotation is set on a recipe to the image of each of its hooks of type job, keyed by hook name, in
+// json, as recipe hooks have no field for it. For example: {"quiesce": {"image": "quay.io/example/quiesce"}}
+const RecipeJobHooksAnnotation = "ramendr.openshift.io/recipe-job-hooks"
+
+const (
+	recipeHookTypeJob     = "job"
+	recipeOnErrorContinue = "continue"
+	hookJobContainerName  = "hook"
+	hookJobNamePrefix     = "ramen-hook-"
+	hookJobNameHashLength = 16
+)
+
+type recipeJobHook struct {
+	Image string `json:"image"`
+}
+
+// recipeHookJobSpec returns the job a recipe hook runs, if it is of type job, from the recipe's annotation
+func recipeHookJobSpec(recipe Recipe.Recipe, hook Recipe.Hook, op Recipe.Operation,
+) (*kubeobjects.HookJobSpec, error) {
+	if hook.Type != recipeHookTypeJob {
+		return nil, nil
+	}
+
+	jobHooks := map[string]recipeJobHook{}
+
+	if annotation, ok := recipe.GetAnnotations()[RecipeJobHooksAnnotation]; ok {
+		if err := json.Unmarshal([]byte(annotation), &jobHooks); err != nil {
+			return nil, fmt.Errorf("recipe %s annotation %s json unmarshal error: %w",
+				recipe.GetName(), RecipeJobHooksAnnotation, err)
+		}
+	}
+
+	jobHook, ok := jobHooks[hook.Name]
+	if !ok || jobHook.Image == "" {
+		return nil, fmt.Errorf("recipe %s hook %s of type job has no image in annotation %s",
+			recipe.GetName(), hook.Name, RecipeJobHooksAnnotation)
+	}
+
+	return &kubeobjects.HookJobSpec{
+		HookName:        hook.Name,
+		Namespace:       hook.Namespace,
+		Image:           jobHook.Image,
+		OnErrorContinue: recipeHookOnErrorContinue(hook, op),
+	}, nil
+}
+
+// kubeObjectsHookJob returns the hook of a workflow step that runs as a job, or nil if the step does not
+func kubeObjectsHookJob(spec kubeobjects.Spec) *kubeobjects.HookSpec {
+	if len(spec.Hooks) == 0 || spec.Hooks[0].Job == nil {
+		return nil
+	}
+
+	return &spec.Hooks[0]
+}
+
+// kubeObjectsHookJobNamespaces returns the namespaces the hooks of the workflows that run as jobs run in
+func (v *VRGInstance) kubeObjectsHookJobNamespaces() []string {
+	namespaces := []string{}
+	add := func(spec kubeobjects.Spec) {
+		if hook := kubeObjectsHookJob(spec); hook != nil && !slices.Contains(namespaces, hook.Job.Namespace) {
+			namespaces = append(namespaces, hook.Job.Namespace)
+		}
+	}
+
+	for _, captureGroup := range v.recipeElements.CaptureWorkflow {
+		add(captureGroup.Spec)
+	}
+
+	for _, recoverGroup := range v.recipeElements.RecoverWorkflow {
+		add(recoverGroup.Spec)
+	}
+
+	return namespaces
+}
+
+// hookJobName returns the name of the job that runs a hook for the capture or recovery named by prefix
+func hookJobName(prefix string, hook kubeobjects.HookSpec) string {
+	hash := sha256.Sum256([]byte(prefix + "/" + hook.Job.HookName + "/" + hook.Name))
+
+	return hookJobNamePrefix + hex.EncodeToString(hash[:])[:hookJobNameHashLength]
+}
+
+// kubeObjectsHookJobRun runs a hook as a job for the capture or recovery named by prefix, and returns whether it
+// completed. A job that fails returns an error, and is deleted so that it runs again, unless its hook continues on
+// error. Its exit code and the tail of its log are reported in the VRG's status once it completes or fails.
+func (v *VRGInstance) kubeObjectsHookJobRun(hook kubeobjects.HookSpec, prefix string, labels map[string]string,
+	log logr.Logger,
+) (bool, error) {
+	name := hookJobName(prefix, hook)
+	log = log.WithValues("hook", hook.Job.HookName+"/"+hook.Name, "job", hook.Job.Namespace+"/"+name)
+
+	job := &batchv1.Job{}
+
+	err := v.reconciler.APIReader.Get(v.ctx, types.NamespacedName{Namespace: hook.Job.Namespace, Name: name}, job)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, fmt.Errorf("hook job %s/%s get error: %w", hook.Job.Namespace, name, err)
+		}
+
+		if err := v.hookJobCreate(hook, name, labels); err != nil {
+			return false, err
+		}
+
+		log.Info("Hook job created")
+
+		return false, nil
+	}
+
+	if job.GetDeletionTimestamp() != nil {
+		log.Info("Hook job deleting")
+
+		return false, nil
+	}
+
+	succeeded, failed := hookJobFinished(job)
+	if !succeeded && !failed {
+		log.Info("Hook job running")
+
+		return false, nil
+	}
+
+	status := v.hookJobStatusSet(hook, job, succeeded)
+	log.Info("Hook job finished", "succeeded", succeeded, "exitCode", status.ExitCode)
+
+	if succeeded || hook.Job.OnErrorContinue {
+		return true, nil
+	}
+
+	if err := v.hookJobsDelete(hook.Job.Namespace, labels, name); err != nil {
+		log.Error(err, "Hook job delete error")
+	}
+
+	return false, fmt.Errorf("hook job %s/%s failed: %s", hook.Job.Namespace, name, status.Log)
+}
+
+func hookJobFinished(job *batchv1.Job) (succeeded, failed bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			succeeded = true
+		case batchv1.JobFailed:
+			failed = true
+		}
+	}
+
+	return succeeded, failed
+}
+
+// hookJobCreate creates a hook's job, running as the service account the administrator configured for hook jobs,
+// which must exist in the hook's namespace
+func (v *VRGInstance) hookJobCreate(hook kubeobjects.HookSpec, name string, labels map[string]string) error {
+	serviceAccountName := v.ramenConfig.KubeObjectProtection.HookJobServiceAccountName
+	if serviceAccountName == "" {
+		return fmt.Errorf("hook job %s/%s not created: no service account for hook jobs is configured",
+			hook.Job.Namespace, name)
+	}
+
+	if err := v.reconciler.APIReader.Get(v.ctx,
+		types.NamespacedName{Namespace: hook.Job.Namespace, Name: serviceAccountName}, &corev1.ServiceAccount{},
+	); err != nil {
+		return fmt.Errorf("hook job %s/%s service account %s get error: %w", hook.Job.Namespace, name,
+			serviceAccountName, err)
+	}
+
+	job := v.hookJob(hook, metav1.ObjectMeta{Name: name, Namespace: hook.Job.Namespace, Labels: labels},
+		serviceAccountName)
+
+	if err := v.reconciler.Client.Create(v.ctx, job); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("hook job %s/%s create error: %w", hook.Job.Namespace, name, err)
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) hookJob(hook kubeobjects.HookSpec, objectMeta metav1.ObjectMeta, serviceAccountName string,
+) *batchv1.Job {
+	backoffLimit := int32(0)
+	containerName := hookJobContainerName
+
+	if hook.Container != nil && *hook.Container != "" {
+		containerName = *hook.Container
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: objectMeta,
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: objectMeta.Labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: serviceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:                     containerName,
+						Image:                    hook.Job.Image,
+						Command:                  hook.Command,
+						TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+					}},
+				},
+			},
+		},
+	}
+
+	if hook.Timeout != nil {
+		activeDeadlineSeconds := int64(hook.Timeout.Seconds())
+		job.Spec.ActiveDeadlineSeconds = &activeDeadlineSeconds
+	}
+
+	if scheduling := v.instance.Spec.HelperPodScheduling; scheduling != nil {
+		job.Spec.Template.Spec.NodeSelector = scheduling.NodeSelector
+		job.Spec.Template.Spec.Tolerations = scheduling.Tolerations
+	}
+
+	return job
+}
+
+// hookJobStatusSet reports the exit code and the tail of the log of a finished hook job in the VRG's status, from
+// the termination state of the container of its pod
+func (v *VRGInstance) hookJobStatusSet(hook kubeobjects.HookSpec, job *batchv1.Job, succeeded bool,
+) ramen.KubeObjectsHookJobStatus {
+	status := ramen.KubeObjectsHookJobStatus{
+		Name:           hook.Job.HookName + "/" + hook.Name,
+		JobName:        job.Name,
+		Succeeded:      succeeded,
+		CompletionTime: job.Status.CompletionTime,
+	}
+
+	if status.CompletionTime == nil {
+		now := metav1.Now()
+		status.CompletionTime = &now
+	}
+
+	pods := &corev1.PodList{}
+	if err := v.reconciler.APIReader.List(v.ctx, pods, client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		v.log.Error(err, "Hook job pods list error", "job", job.Namespace+"/"+job.Name)
+	}
+
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if terminated := containerStatus.State.Terminated; terminated != nil {
+				exitCode := terminated.ExitCode
+				status.ExitCode = &exitCode
+				status.Log = terminated.Message
+			}
+		}
+	}
+
+	hookJobs := &v.instance.Status.KubeObjectProtection.HookJobs
+
+	for i := range *hookJobs {
+		if (*hookJobs)[i].Name == status.Name {
+			(*hookJobs)[i] = status
+
+			return status
+		}
+	}
+
+	*hookJobs = append(*hookJobs, status)
+
+	return status
+}
+
+// kubeObjectsHookJobsDelete deletes the jobs that ran the hooks of a capture or recovery, once it completed
+func (v *VRGInstance) kubeObjectsHookJobsDelete(labels map[string]string) error {
+	for _, namespace := range v.kubeObjectsHookJobNamespaces() {
+		if err := v.hookJobsDelete(namespace, labels, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hookJobsDelete deletes the hook jobs of a namespace with labels, and with a name if it is not empty, one by one
+// rather than as a collection, so that the operator need not be permitted to delete every job of a namespace
+func (v *VRGInstance) hookJobsDelete(namespace string, labels map[string]string, name string) error {
+	jobs := &batchv1.JobList{}
+	if err := v.reconciler.APIReader.List(v.ctx, jobs, client.InNamespace(namespace),
+		client.MatchingLabels(labels),
+	); err != nil {
+		return fmt.Errorf("hook jobs list error in namespace %s: %w", namespace, err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if name != "" && job.Name != name {
+			continue
+		}
+
+		if err := v.reconciler.Client.Delete(v.ctx, job,
+			client.PropagationPolicy(metav1.DeletePropagationBackground),
+		); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("hook job %s/%s delete error: %w", namespace, job.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the jobs that run recipe hooks of type job
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_RecipeJobHooks", func() {
+	var (
+		vrgInstance *VRGInstance
+		hook        kubeobjects.HookSpec
+	)
+
+	labels := map[string]string{"capture": "1"}
+	job := func(name string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: labels}}
+	}
+	jobs := func() []string {
+		jobList := &batchv1.JobList{}
+		Expect(vrgInstance.reconciler.List(context.TODO(), jobList, client.InNamespace("app"))).To(Succeed())
+
+		names := []string{}
+		for _, job := range jobList.Items {
+			names = append(names, job.Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		c := fake.NewClientBuilder().WithObjects(
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "hooks"}},
+		).Build()
+		vrgInstance = &VRGInstance{
+			reconciler:  &VolumeReplicationGroupReconciler{Client: c, APIReader: c},
+			ctx:         context.TODO(),
+			log:         ctrl.Log.WithName("vrg-recipe-job-hooks-test"),
+			instance:    &ramen.VolumeReplicationGroup{},
+			ramenConfig: &ramen.RamenConfig{},
+		}
+		hook = kubeobjects.HookSpec{
+			Name:    "quiesce",
+			Command: []string{"/bin/quiesce"},
+			HookRun: kubeobjects.HookRun{Job: &kubeobjects.HookJobSpec{
+				HookName: "db", Namespace: "app", Image: "quay.io/example/quiesce",
+			}},
+		}
+	})
+
+	It("does not run a job unless a service account for hook jobs is configured", func() {
+		Expect(vrgInstance.hookJobCreate(hook, "hook", labels)).To(MatchError(ContainSubstring("no service account")))
+		Expect(jobs()).To(BeEmpty())
+	})
+
+	It("does not run a job in a namespace without the configured service account", func() {
+		vrgInstance.ramenConfig.KubeObjectProtection.HookJobServiceAccountName = "missing"
+		Expect(vrgInstance.hookJobCreate(hook, "hook", labels)).To(MatchError(ContainSubstring("missing")))
+		Expect(jobs()).To(BeEmpty())
+	})
+
+	It("runs a job as the configured service account, creating no service account or role", func() {
+		vrgInstance.ramenConfig.KubeObjectProtection.HookJobServiceAccountName = "hooks"
+		Expect(vrgInstance.hookJobCreate(hook, "hook", labels)).To(Succeed())
+
+		created := &batchv1.Job{}
+		Expect(vrgInstance.reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "hook"},
+			created)).To(Succeed())
+		Expect(created.Spec.Template.Spec.ServiceAccountName).To(Equal("hooks"))
+		Expect(created.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/example/quiesce"))
+
+		serviceAccounts := &corev1.ServiceAccountList{}
+		Expect(vrgInstance.reconciler.List(context.TODO(), serviceAccounts)).To(Succeed())
+		Expect(serviceAccounts.Items).To(HaveLen(1))
+	})
+
+	It("schedules the job with the node selector and tolerations of the helper pods", func() {
+		vrgInstance.ramenConfig.KubeObjectProtection.HookJobServiceAccountName = "hooks"
+		vrgInstance.instance.Spec.HelperPodScheduling = &ramen.HelperPodSchedulingSpec{
+			NodeSelector: map[string]string{"zone": "b"},
+			Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		}
+		Expect(vrgInstance.hookJobCreate(hook, "hook", labels)).To(Succeed())
+
+		created := &batchv1.Job{}
+		Expect(vrgInstance.reconciler.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "hook"},
+			created)).To(Succeed())
+		Expect(created.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"zone": "b"}))
+		Expect(created.Spec.Template.Spec.Tolerations).To(Equal(vrgInstance.instance.Spec.HelperPodScheduling.Tolerations))
+	})
+
+	It("deletes the labeled jobs of a namespace, or the one named", func() {
+		for _, name := range []string{"first", "second"} {
+			Expect(vrgInstance.reconciler.Create(context.TODO(), job(name))).To(Succeed())
+		}
+
+		Expect(vrgInstance.reconciler.Create(context.TODO(), &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "unlabeled"},
+		})).To(Succeed())
+
+		Expect(vrgInstance.hookJobsDelete("app", labels, "first")).To(Succeed())
+		Expect(jobs()).To(ConsistOf("second", "unlabeled"))
+
+		Expect(vrgInstance.hookJobsDelete("app", labels, "")).To(Succeed())
+		Expect(jobs()).To(ConsistOf("unlabeled"))
+	})
+})
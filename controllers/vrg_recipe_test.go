@@ -433,6 +433,17 @@ var _ = Describe("VolumeReplicationGroupRecipe", func() {
 						recipeExpanded = &*r
 						Expect(controllers.RecipeParametersExpand(recipeExpanded, vrgRecipeParameters(), testLogger)).To(Succeed())
 					})
+					Context("with a hook referring to a parameter not defined, with a default", func() {
+						BeforeEach(func() {
+							recipeHooksDefine(hook("${nsUndefined:-" + nsNamesSlice[0] + "}"))
+						})
+						It("expands the parameter to its default", func() {
+							Expect(recipeExpanded.Spec.Hooks[0].Namespace).To(Equal(nsNamesSlice[0]))
+						})
+						It("reports no parameters undefined", func() {
+							Expect(controllers.RecipeParametersUndefined(*r, vrgRecipeParameters())).To(BeEmpty())
+						})
+					})
 					It("expands a parameter list enclosed in double quotes to a single string with quotes preserved", func() {
 						Skip("feature not supported")
 						Expect(recipeExpanded.Spec.Hooks[0].Ops[0].Command).To(Equal(`"` + strings.Join(nsNamesSlice, ",") + `"`))
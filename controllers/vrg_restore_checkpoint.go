@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const restoreCheckpointS3ObjectNameSuffix = "a"
+
+// restoreCheckpoint records the progress of a VRG cluster data restore in the S3 store, so that a restore
+// interrupted by an operator restart resumes from its last completed step, and a failed step is retried without
+// repeating the steps before it. A checkpoint applies to the VRG UID and generation that wrote it only.
+type restoreCheckpoint struct {
+	VRGUID        types.UID   `json:"vrgUID"`
+	VRGGeneration int64       `json:"vrgGeneration"`
+	StartTime     metav1.Time `json:"startTime"`
+
+	// RestoredPVCs are the namespaced names of the PVCs restored along with their PVs
+	RestoredPVCs []string `json:"restoredPVCs,omitempty"`
+
+	// CaptureNumber is the kube objects capture that RecoveredGroups were recovered from
+	CaptureNumber int64 `json:"captureNumber,omitempty"`
+
+	// RecoveredGroups are the indices of the recover workflow groups recovered
+	RecoveredGroups []int `json:"recoveredGroups,omitempty"`
+
+	// AccessModesMappedPVCs are the PVCs whose access modes were mapped, for the AccessModesPreserved condition
+	AccessModesMappedPVCs []string `json:"accessModesMappedPVCs,omitempty"`
+}
+
+// restoreCheckpointLoad loads the restore checkpoint from the object store, or starts a new one if none exists for
+// this VRG instance
+func (v *VRGInstance) restoreCheckpointLoad(objectStore ObjectStorer, s3ProfileName string) {
+	checkpoint := &restoreCheckpoint{}
+
+	err := DownloadTypedObject(objectStore, v.s3KeyPrefix(), restoreCheckpointS3ObjectNameSuffix, checkpoint)
+	if err != nil || checkpoint.VRGUID != v.instance.UID || checkpoint.VRGGeneration != v.instance.Generation {
+		checkpoint = &restoreCheckpoint{
+			VRGUID:        v.instance.UID,
+			VRGGeneration: v.instance.Generation,
+			StartTime:     metav1.Now(),
+		}
+	} else {
+		v.log.Info("Resuming restore from checkpoint", "profile", s3ProfileName,
+			"elapsed", time.Since(checkpoint.StartTime.Time).Round(time.Second),
+			"pvcs", len(checkpoint.RestoredPVCs), "groups", checkpoint.RecoveredGroups)
+
+		for _, message := range checkpoint.AccessModesMappedPVCs {
+			if !slices.Contains(v.accessModesMappedPVCs, message) {
+				v.accessModesMappedPVCs = append(v.accessModesMappedPVCs, message)
+			}
+		}
+	}
+
+	v.restoreCheckpoint = checkpoint
+	v.restoreCheckpointStore = objectStore
+}
+
+// restoreCheckpointSave saves the restore checkpoint. A failure to save is not a restore failure, at worst the
+// steps since the last saved checkpoint are repeated.
+func (v *VRGInstance) restoreCheckpointSave() {
+	if v.restoreCheckpoint == nil || v.restoreCheckpointStore == nil {
+		return
+	}
+
+	v.restoreCheckpoint.AccessModesMappedPVCs = v.accessModesMappedPVCs
+
+	if err := uploadTypedObject(v.restoreCheckpointStore, v.s3KeyPrefix(), restoreCheckpointS3ObjectNameSuffix,
+		*v.restoreCheckpoint); err != nil {
+		v.log.Info("Restore checkpoint save failed", "error", err)
+	}
+}
+
+// restoreCheckpointDelete deletes the restore checkpoint once the restore is complete
+func (v *VRGInstance) restoreCheckpointDelete() {
+	if v.restoreCheckpoint == nil || v.restoreCheckpointStore == nil {
+		return
+	}
+
+	if err := DeleteTypedObject(v.restoreCheckpointStore, v.s3KeyPrefix(), restoreCheckpointS3ObjectNameSuffix,
+		*v.restoreCheckpoint); err != nil {
+		v.log.Info("Restore checkpoint delete failed", "error", err)
+
+		return
+	}
+
+	v.log.Info("Restore complete", "duration", time.Since(v.restoreCheckpoint.StartTime.Time).Round(time.Second))
+
+	v.restoreCheckpoint = nil
+	v.restoreCheckpointStore = nil
+}
+
+// restoreCheckpointStartTime returns the time the restore started, or now if there is no checkpoint
+func (v *VRGInstance) restoreCheckpointStartTime() metav1.Time {
+	if v.restoreCheckpoint == nil {
+		return metav1.Now()
+	}
+
+	return v.restoreCheckpoint.StartTime
+}
+
+func (v *VRGInstance) restoreCheckpointPVCsRestored() bool {
+	return v.restoreCheckpoint != nil && len(v.restoreCheckpoint.RestoredPVCs) != 0
+}
+
+func (v *VRGInstance) restoreCheckpointPVCsSet(pvcList []corev1.PersistentVolumeClaim) {
+	if v.restoreCheckpoint == nil || len(pvcList) == 0 {
+		return
+	}
+
+	v.restoreCheckpoint.RestoredPVCs = make([]string, 0, len(pvcList))
+	for idx := range pvcList {
+		v.restoreCheckpoint.RestoredPVCs = append(v.restoreCheckpoint.RestoredPVCs,
+			types.NamespacedName{Namespace: pvcList[idx].Namespace, Name: pvcList[idx].Name}.String())
+	}
+
+	v.restoreCheckpointSave()
+}
+
+func (v *VRGInstance) restoreCheckpointGroupRecovered(captureNumber int64, groupNumber int) bool {
+	return v.restoreCheckpoint != nil && v.restoreCheckpoint.CaptureNumber == captureNumber &&
+		slices.Contains(v.restoreCheckpoint.RecoveredGroups, groupNumber)
+}
+
+func (v *VRGInstance) restoreCheckpointGroupSet(captureNumber int64, groupNumber int) {
+	if v.restoreCheckpoint == nil {
+		return
+	}
+
+	if v.restoreCheckpoint.CaptureNumber != captureNumber {
+		v.restoreCheckpoint.CaptureNumber = captureNumber
+		v.restoreCheckpoint.RecoveredGroups = nil
+	}
+
+	v.restoreCheckpoint.RecoveredGroups = append(v.restoreCheckpoint.RecoveredGroups, groupNumber)
+
+	v.restoreCheckpointSave()
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the checkpoints of the VRG cluster data restores
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_RestoreCheckpoint", func() {
+	var store memoryObjectStorer
+
+	vrgInstance := func(generation int64) *VRGInstance {
+		v := vrgInstanceFake(fake.NewClientBuilder().Build(), "vrg-restore-checkpoint-test",
+			ramen.VolumeReplicationGroupSpec{})
+		v.instance.UID = types.UID("vrg-uid")
+		v.instance.Generation = generation
+		v.namespacedName = v.instance.Namespace + "/" + v.instance.Name
+
+		return v
+	}
+	pvc := func(name string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name}}
+	}
+
+	BeforeEach(func() {
+		store = memoryObjectStorer{}
+	})
+
+	It("starts a restore without a checkpoint, and saves its progress", func() {
+		v := vrgInstance(1)
+		v.restoreCheckpointLoad(store, "profile")
+		Expect(v.restoreCheckpointPVCsRestored()).To(BeFalse())
+		Expect(store).To(BeEmpty())
+
+		v.restoreCheckpointPVCsSet([]corev1.PersistentVolumeClaim{pvc("data"), pvc("logs")})
+		v.restoreCheckpointGroupSet(3, 0)
+		v.accessModesMappedPVCs = []string{"app/data"}
+		v.restoreCheckpointGroupSet(3, 1)
+		Expect(store).To(HaveLen(1))
+
+		resumed := vrgInstance(1)
+		resumed.restoreCheckpointLoad(store, "profile")
+		Expect(resumed.restoreCheckpointPVCsRestored()).To(BeTrue())
+		Expect(resumed.restoreCheckpoint.RestoredPVCs).To(Equal([]string{"app/data", "app/logs"}))
+		Expect(resumed.restoreCheckpointStartTime().Unix()).To(Equal(v.restoreCheckpointStartTime().Unix()))
+		Expect(resumed.restoreCheckpointGroupRecovered(3, 1)).To(BeTrue())
+		Expect(resumed.restoreCheckpointGroupRecovered(3, 2)).To(BeFalse())
+		Expect(resumed.restoreCheckpointGroupRecovered(4, 1)).To(BeFalse())
+		Expect(resumed.accessModesMappedPVCs).To(Equal([]string{"app/data"}))
+	})
+
+	It("restarts the groups recovered from another capture", func() {
+		v := vrgInstance(1)
+		v.restoreCheckpointLoad(store, "profile")
+		v.restoreCheckpointGroupSet(3, 0)
+		v.restoreCheckpointGroupSet(4, 1)
+
+		Expect(v.restoreCheckpointGroupRecovered(3, 0)).To(BeFalse())
+		Expect(v.restoreCheckpointGroupRecovered(4, 1)).To(BeTrue())
+		Expect(v.restoreCheckpoint.RecoveredGroups).To(Equal([]int{1}))
+	})
+
+	It("ignores the checkpoint of another generation of the VRG", func() {
+		v := vrgInstance(1)
+		v.restoreCheckpointLoad(store, "profile")
+		v.restoreCheckpointPVCsSet([]corev1.PersistentVolumeClaim{pvc("data")})
+
+		other := vrgInstance(2)
+		other.restoreCheckpointLoad(store, "profile")
+		Expect(other.restoreCheckpointPVCsRestored()).To(BeFalse())
+		Expect(other.restoreCheckpoint.VRGGeneration).To(Equal(int64(2)))
+	})
+
+	It("protects the PVCs restored as of the checkpoint without restoring them again", func() {
+		v := vrgInstance(1)
+		for _, name := range []string{"data", "logs", "cache"} {
+			Expect(UploadPVC(store, v.s3KeyPrefix(), "app/"+name, pvc(name))).To(Succeed())
+		}
+
+		v.restoreCheckpointLoad(store, "profile")
+		v.restoreCheckpointPVCsSet([]corev1.PersistentVolumeClaim{pvc("data"), pvc("logs")})
+
+		resumed := vrgInstance(1)
+		resumed.restoreCheckpointLoad(store, "profile")
+		Expect(resumed.restoredPVCsFromObjectStore(store, "profile")).To(Equal(2))
+		Expect(resumed.volRepPVCs).To(HaveLen(2))
+	})
+
+	It("deletes the checkpoint once the restore is complete", func() {
+		v := vrgInstance(1)
+		v.restoreCheckpointLoad(store, "profile")
+		v.restoreCheckpointPVCsSet([]corev1.PersistentVolumeClaim{pvc("data")})
+		Expect(store).To(HaveLen(1))
+
+		v.restoreCheckpointDelete()
+		Expect(store).To(BeEmpty())
+		Expect(v.restoreCheckpoint).To(BeNil())
+		Expect(v.restoreCheckpointPVCsRestored()).To(BeFalse())
+
+		v.restoreCheckpointDelete()
+		v.restoreCheckpointGroupSet(3, 0)
+		Expect(store).To(BeEmpty())
+	})
+})
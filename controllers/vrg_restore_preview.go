@@ -0,0 +1,385 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// VRGRestorePreviewAnnotation requests a preview of the recovery of a VRG's kube objects on its cluster when set to
+// a value that differs from the request of the most recent preview in the VRG status, such as a timestamp.
+// Previewing does not recover any object.
+const VRGRestorePreviewAnnotation = "volumereplicationgroups.ramendr.openshift.io/restore-preview"
+
+// kubeObjectsRestoreConflictsMaximum is how many conflicts a preview lists, to bound the size of the VRG status
+const kubeObjectsRestoreConflictsMaximum = 100
+
+// kubeObjectsImmutable are the fields of a kind whose update fails if they differ from those of the existing object
+type kubeObjectsImmutable struct {
+	// resource is the resource the kind's objects are captured as, in a capture archive
+	resource string
+
+	// fields are the paths of the immutable fields
+	fields []string
+
+	// mutableFields are the paths, within the immutable fields, of those that may be updated
+	mutableFields []string
+}
+
+// kubeObjectsImmutableFields are the immutable fields of the kinds, keyed by group/version/Kind
+var kubeObjectsImmutableFields = map[string]kubeObjectsImmutable{
+	"v1/PersistentVolumeClaim": {"persistentvolumeclaims", []string{"spec"}, []string{"spec.resources.requests"}},
+	"v1/PersistentVolume":      {"persistentvolumes", []string{"spec.persistentVolumeSource"}, nil},
+	"v1/Service":               {"services", []string{"spec.clusterIP"}, nil},
+	"batch/v1/Job":             {"jobs.batch", []string{"spec.selector", "spec.template"}, nil},
+	"apps/v1/Deployment":       {"deployments.apps", []string{"spec.selector"}, nil},
+	"apps/v1/DaemonSet":        {"daemonsets.apps", []string{"spec.selector"}, nil},
+	"apps/v1/ReplicaSet":       {"replicasets.apps", []string{"spec.selector"}, nil},
+	"apps/v1/StatefulSet": {"statefulsets.apps", []string{"spec"}, []string{
+		"spec.replicas", "spec.template", "spec.updateStrategy", "spec.minReadySeconds",
+		"spec.persistentVolumeClaimRetentionPolicy",
+	}},
+}
+
+func (immutable kubeObjectsImmutable) String() string {
+	fields := strings.Join(immutable.fields, ", ")
+	if len(immutable.mutableFields) == 0 {
+		return fields
+	}
+
+	return fields + ", except " + strings.Join(immutable.mutableFields, ", ")
+}
+
+// kubeObjectsImmutableFieldsOfResource returns the immutable fields of the kind captured as a resource
+func kubeObjectsImmutableFieldsOfResource(resource string) (kubeObjectsImmutable, bool) {
+	for _, immutable := range kubeObjectsImmutableFields {
+		if immutable.resource == resource {
+			return immutable, true
+		}
+	}
+
+	return kubeObjectsImmutable{}, false
+}
+
+// kubeObjectsImmutableFieldsChecksum returns the checksum of the immutable fields of an object
+func kubeObjectsImmutableFieldsChecksum(object map[string]interface{}, immutable kubeObjectsImmutable,
+) (string, error) {
+	fields := map[string]interface{}{}
+
+	for _, field := range immutable.fields {
+		path := strings.Split(field, ".")
+
+		value, found, err := unstructured.NestedFieldCopy(object, path...)
+		if err != nil {
+			return "", err
+		}
+
+		if !found {
+			continue
+		}
+
+		if err := unstructured.SetNestedField(fields, value, path...); err != nil {
+			return "", err
+		}
+	}
+
+	for _, field := range immutable.mutableFields {
+		unstructured.RemoveNestedField(fields, strings.Split(field, ".")...)
+	}
+
+	return kubeObjectsChecksum(fields)
+}
+
+// kubeObjectsRestorePreview previews the recovery of the capture to recover from on this cluster when requested,
+// and records the preview in the VRG status
+func (v *VRGInstance) kubeObjectsRestorePreview() {
+	status := &v.instance.Status.KubeObjectProtection
+
+	request, ok := v.instance.GetAnnotations()[VRGRestorePreviewAnnotation]
+	if !ok {
+		status.RestorePreview = nil
+
+		return
+	}
+
+	if status.RestorePreview != nil && status.RestorePreview.Request == request {
+		return
+	}
+
+	now := metav1.Now()
+	preview := &ramen.KubeObjectsRestorePreview{Request: request, PreviewTime: &now}
+
+	if err := v.kubeObjectsRestorePreviewMake(preview); err != nil {
+		preview.Errors = append(preview.Errors, err.Error())
+	}
+
+	v.log.Info("Kube objects restore previewed", "request", request, "number", preview.CaptureNumber,
+		"objects", preview.Objects, "conflicts", preview.ConflictCount, "errors", len(preview.Errors))
+
+	status.RestorePreview = preview
+}
+
+// kubeObjectsRestorePreviewMake previews the recovery of the capture to recover from, using the manifest of the
+// capture in the first accessible S3 store
+func (v *VRGInstance) kubeObjectsRestorePreviewMake(preview *ramen.KubeObjectsRestorePreview) error {
+	if v.kubeObjectProtectionDisabled("restore preview") {
+		return errors.New("kube object protection is disabled")
+	}
+
+	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+		if s3ProfileName == NoS3StoreAvailable {
+			continue
+		}
+
+		objectStore, _, err := v.reconciler.ObjStoreGetter.ObjectStore(
+			v.ctx, v.reconciler.APIReader, s3ProfileName, v.namespacedName, v.log)
+		if err != nil {
+			v.log.Info("Object store inaccessible for restore preview", "profile", s3ProfileName, "error", err)
+
+			continue
+		}
+
+		manifest, err := v.kubeObjectsRestorePreviewManifest(objectStore)
+		if err != nil {
+			return err
+		}
+
+		preview.CaptureNumber = manifest.Number
+		preview.Objects = manifest.Objects
+
+		for i := range manifest.Groups {
+			v.kubeObjectsRestorePreviewGroup(preview, &manifest.Groups[i])
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no accessible S3 store in profiles %v", v.instance.Spec.S3Profiles)
+}
+
+// kubeObjectsRestorePreviewManifest downloads the manifest of the capture to recover from, as identified by the VRG
+// stored by the primary cluster
+func (v *VRGInstance) kubeObjectsRestorePreviewManifest(objectStore ObjectStorer,
+) (*kubeObjectsCaptureManifest, error) {
+	vrg := v.instance
+
+	sourceVrg := &ramen.VolumeReplicationGroup{}
+	if err := vrgObjectDownload(objectStore, s3PathNamePrefix(vrg.Namespace, vrg.Name), sourceVrg); err != nil {
+		return nil, fmt.Errorf("failed to download VRG: %w", err)
+	}
+
+	captureToRecoverFromIdentifier := sourceVrg.Status.KubeObjectProtection.CaptureToRecoverFrom
+	if captureToRecoverFromIdentifier == nil {
+		return nil, errors.New("no capture to recover from")
+	}
+
+	pathName, _, _ := kubeObjectsCapturePathNamesAndNamePrefix(
+		vrg.Namespace, vrg.Name, captureToRecoverFromIdentifier.Number, v.reconciler.kubeObjects)
+	manifest := &kubeObjectsCaptureManifest{}
+
+	if err := objectStore.DownloadObject(pathName+kubeObjectsCaptureManifestName, manifest); err != nil {
+		return nil, fmt.Errorf("failed to download manifest of capture %d: %w",
+			captureToRecoverFromIdentifier.Number, err)
+	}
+
+	if !manifest.StartTime.Equal(&captureToRecoverFromIdentifier.StartTime) {
+		return nil, fmt.Errorf("manifest of capture %d is not of the capture to recover from, started at %v",
+			captureToRecoverFromIdentifier.Number, captureToRecoverFromIdentifier.StartTime)
+	}
+
+	return manifest, nil
+}
+
+// kubeObjectsRestorePreviewGroup adds the conflicts of the objects of a capture group, listing the objects of each of
+// its kinds once per namespace
+func (v *VRGInstance) kubeObjectsRestorePreviewGroup(preview *ramen.KubeObjectsRestorePreview,
+	group *kubeObjectsCaptureGroupManifest,
+) {
+	kinds := make([]string, 0, len(group.Resources))
+	for kind := range group.Resources {
+		kinds = append(kinds, kind)
+	}
+
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		names := group.Resources[kind]
+		sort.Strings(names)
+
+		if err := v.kubeObjectsRestorePreviewKind(preview, kind, names, group.ImmutableFieldsChecksums); err != nil {
+			preview.Errors = append(preview.Errors, fmt.Sprintf("%s: %v", kind, err))
+		}
+	}
+}
+
+// kubeObjectsRestorePreviewKind adds the conflicts of the objects of a kind. An existing object of a kind with
+// immutable fields conflicts with those of the capture if their checksums differ, or if the capture's manifest has
+// none, as it was written before they were.
+func (v *VRGInstance) kubeObjectsRestorePreviewKind(preview *ramen.KubeObjectsRestorePreview,
+	kind string, names []string, immutableFieldsChecksums map[string]string,
+) error {
+	gvk, err := kubeObjectsManifestKindParse(kind)
+	if err != nil {
+		return err
+	}
+
+	immutable, immutableFields := kubeObjectsImmutableFields[kind]
+
+	// the checksums of the immutable fields of the existing objects, or empty for a kind without
+	existing := map[string]map[string]string{}
+
+	for _, name := range names {
+		namespaceName, _, namespaced := strings.Cut(name, "/")
+		if !namespaced {
+			namespaceName = ""
+		}
+
+		if _, ok := existing[namespaceName]; ok {
+			continue
+		}
+
+		objects, err := v.kubeObjectsRestorePreviewList(gvk, namespaceName, immutableFields)
+		if err != nil {
+			if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+				kubeObjectsRestoreConflictAdd(preview, ramen.KubeObjectsRestoreConflict{
+					Kind:    kind,
+					Reason:  ramen.KubeObjectsRestoreConflictKindNotServed,
+					Message: fmt.Sprintf("%d objects fail to be recovered", len(names)),
+				})
+
+				return nil
+			}
+
+			return err
+		}
+
+		existing[namespaceName] = map[string]string{}
+
+		for _, object := range objects {
+			checksum := ""
+
+			if immutableFields {
+				checksum, err = kubeObjectsImmutableFieldsChecksum(object.(*unstructured.Unstructured).Object, immutable)
+				if err != nil {
+					return err
+				}
+			}
+
+			existing[namespaceName][object.GetName()] = checksum
+		}
+	}
+
+	for _, name := range names {
+		namespaceName, objectName, namespaced := strings.Cut(name, "/")
+		if !namespaced {
+			namespaceName, objectName = "", name
+		}
+
+		checksum, ok := existing[namespaceName][objectName]
+		if !ok {
+			continue
+		}
+
+		conflict := ramen.KubeObjectsRestoreConflict{
+			Kind:    kind,
+			Name:    name,
+			Reason:  ramen.KubeObjectsRestoreConflictExists,
+			Message: "exists, and is kept, or overwritten if recovered with an existing resource policy of update",
+		}
+
+		if immutableFields {
+			capturedChecksum, captured := immutableFieldsChecksums[immutable.resource+"/"+name]
+
+			switch {
+			case !captured:
+				conflict.Reason = ramen.KubeObjectsRestoreConflictImmutableFields
+				conflict.Message = "exists, and fails to be overwritten if its immutable fields differ: " +
+					immutable.String()
+			case capturedChecksum != checksum:
+				conflict.Reason = ramen.KubeObjectsRestoreConflictImmutableFields
+				conflict.Message = "exists, and fails to be overwritten, as its immutable fields differ: " +
+					immutable.String()
+			}
+		}
+
+		kubeObjectsRestoreConflictAdd(preview, conflict)
+	}
+
+	return nil
+}
+
+// kubeObjectsRestorePreviewList lists the objects of a kind in a namespace, or cluster scoped ones, with their content
+// if their immutable fields are compared, or their metadata only otherwise
+func (v *VRGInstance) kubeObjectsRestorePreviewList(gvk schema.GroupVersionKind, namespaceName string, content bool,
+) ([]client.Object, error) {
+	listGVK := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+
+	if content {
+		objects := &unstructured.UnstructuredList{}
+		objects.SetGroupVersionKind(listGVK)
+
+		if err := v.reconciler.APIReader.List(v.ctx, objects, client.InNamespace(namespaceName)); err != nil {
+			return nil, err
+		}
+
+		items := make([]client.Object, len(objects.Items))
+		for i := range objects.Items {
+			items[i] = &objects.Items[i]
+		}
+
+		return items, nil
+	}
+
+	objects := &metav1.PartialObjectMetadataList{}
+	objects.SetGroupVersionKind(listGVK)
+
+	if err := v.reconciler.APIReader.List(v.ctx, objects, client.InNamespace(namespaceName)); err != nil {
+		return nil, err
+	}
+
+	items := make([]client.Object, len(objects.Items))
+	for i := range objects.Items {
+		items[i] = &objects.Items[i]
+	}
+
+	return items, nil
+}
+
+func kubeObjectsRestoreConflictAdd(preview *ramen.KubeObjectsRestorePreview,
+	conflict ramen.KubeObjectsRestoreConflict,
+) {
+	preview.ConflictCount++
+
+	if len(preview.Conflicts) < kubeObjectsRestoreConflictsMaximum {
+		preview.Conflicts = append(preview.Conflicts, conflict)
+	}
+}
+
+// kubeObjectsManifestKindParse parses a kind of a capture manifest, group/version/Kind, or version/Kind for the core
+// group
+func kubeObjectsManifestKindParse(kind string) (schema.GroupVersionKind, error) {
+	index := strings.LastIndex(kind, "/")
+	if index < 0 {
+		return schema.GroupVersionKind{}, fmt.Errorf("kind %s has no version", kind)
+	}
+
+	groupVersion, err := schema.ParseGroupVersion(kind[:index])
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+
+	return groupVersion.WithKind(kind[index+1:]), nil
+}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the conflicts of the objects of a capture with those of a cluster
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_RestorePreview", func() {
+	service := func(name, clusterIP string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+			Spec:       corev1.ServiceSpec{ClusterIP: clusterIP, Ports: []corev1.ServicePort{{Port: 80}}},
+		}
+	}
+	immutableFieldsChecksum := func(object interface{}, kind string) string {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+		Expect(err).ToNot(HaveOccurred())
+
+		checksum, err := kubeObjectsImmutableFieldsChecksum(content, kubeObjectsImmutableFields[kind])
+		Expect(err).ToNot(HaveOccurred())
+
+		return checksum
+	}
+
+	Describe("kubeObjectsImmutableFieldsChecksum", func() {
+		It("sums the immutable fields only, except those that may be updated", func() {
+			pvc := func(storage string, labels map[string]string, class string) *corev1.PersistentVolumeClaim {
+				return &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "data", Labels: labels},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &class,
+						Resources: corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(storage),
+						}},
+					},
+				}
+			}
+			checksum := immutableFieldsChecksum(pvc("1Gi", nil, "fast"), "v1/PersistentVolumeClaim")
+
+			Expect(immutableFieldsChecksum(pvc("2Gi", map[string]string{"app": "db"}, "fast"),
+				"v1/PersistentVolumeClaim")).To(Equal(checksum))
+			Expect(immutableFieldsChecksum(pvc("1Gi", nil, "slow"), "v1/PersistentVolumeClaim")).ToNot(
+				Equal(checksum))
+		})
+	})
+
+	Describe("kubeObjectsRestorePreviewKind", func() {
+		var v *VRGInstance
+
+		BeforeEach(func() {
+			c := fake.NewClientBuilder().WithObjects(service("headless", "None"), service("frontend", "10.0.0.1"),
+				service("backend", "10.0.0.2"),
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "settings"}}).Build()
+			v = vrgInstanceFake(c, "vrg-restore-preview-test", ramen.VolumeReplicationGroupSpec{})
+		})
+
+		It("reports the existing objects whose immutable fields differ from those captured", func() {
+			preview := &ramen.KubeObjectsRestorePreview{}
+			Expect(v.kubeObjectsRestorePreviewKind(preview, "v1/Service",
+				[]string{"app/absent", "app/backend", "app/frontend", "app/headless"}, map[string]string{
+					"services/app/frontend": immutableFieldsChecksum(service("frontend", "10.0.0.1"), "v1/Service"),
+					"services/app/headless": immutableFieldsChecksum(service("headless", "10.0.0.3"), "v1/Service"),
+				})).To(Succeed())
+
+			Expect(preview.ConflictCount).To(Equal(3))
+			Expect(preview.Conflicts).To(Equal([]ramen.KubeObjectsRestoreConflict{
+				{
+					Kind: "v1/Service", Name: "app/backend", Reason: ramen.KubeObjectsRestoreConflictImmutableFields,
+					Message: "exists, and fails to be overwritten if its immutable fields differ: spec.clusterIP",
+				},
+				{
+					Kind: "v1/Service", Name: "app/frontend", Reason: ramen.KubeObjectsRestoreConflictExists,
+					Message: "exists, and is kept, or overwritten if recovered with an existing resource policy of update",
+				},
+				{
+					Kind: "v1/Service", Name: "app/headless", Reason: ramen.KubeObjectsRestoreConflictImmutableFields,
+					Message: "exists, and fails to be overwritten, as its immutable fields differ: spec.clusterIP",
+				},
+			}))
+		})
+
+		It("reports the existing objects of kinds without immutable fields as existing", func() {
+			preview := &ramen.KubeObjectsRestorePreview{}
+			Expect(v.kubeObjectsRestorePreviewKind(preview, "v1/ConfigMap", []string{"app/absent", "app/settings"},
+				nil)).To(Succeed())
+			Expect(preview.Conflicts).To(ConsistOf(HaveField("Reason", ramen.KubeObjectsRestoreConflictExists)))
+		})
+	})
+})
@@ -0,0 +1,248 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var (
+	ingressGroupKind   = schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}
+	gatewayGroupKind   = schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "Gateway"}
+	httpRouteGroupKind = schema.GroupKind{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute"}
+)
+
+// routeTranslationsApply rewrites the hostnames of the recovered Ingresses, Gateways and HTTPRoutes to the domains
+// of this cluster, and the gateway classes of the recovered Gateways to those of this cluster. The kinds this cluster
+// does not serve are skipped.
+func (v *VRGInstance) routeTranslationsApply() error {
+	if len(v.instance.Spec.DomainSuffixTranslations) == 0 && len(v.instance.Spec.GatewayClassTranslations) == 0 {
+		return nil
+	}
+
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, groupKind := range []schema.GroupKind{ingressGroupKind, gatewayGroupKind, httpRouteGroupKind} {
+		mapping, err := v.reconciler.RESTMapper().RESTMapping(groupKind)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to map kind %s (%w)", groupKind, err)
+		}
+
+		for _, listOptions := range namespacesListOptions {
+			if err := v.routeTranslationsApplyKind(mapping.GroupVersionKind, listOptions); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// routeTranslationsApplyKind rewrites the recovered objects of a kind in a namespace. The recovered objects are
+// listed from the cache by their metadata, and only their content is read from the API server.
+func (v *VRGInstance) routeTranslationsApplyKind(gvk schema.GroupVersionKind, listOptions *client.ListOptions,
+) error {
+	objects := &metav1.PartialObjectMetadataList{}
+	objects.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := v.reconciler.List(v.ctx, objects, listOptions); err != nil {
+		return fmt.Errorf("failed to list %s in namespace %s (%w)", gvk.Kind, listOptions.Namespace, err)
+	}
+
+	for i := range objects.Items {
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(gvk)
+
+		if err := v.reconciler.APIReader.Get(v.ctx, client.ObjectKeyFromObject(&objects.Items[i]), object); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get %s %s/%s (%w)", gvk.Kind, objects.Items[i].GetNamespace(),
+				objects.Items[i].GetName(), err)
+		}
+
+		translated := object.DeepCopy()
+
+		rewritten, err := routeTranslate(translated, v.instance.Spec.DomainSuffixTranslations,
+			v.instance.Spec.GatewayClassTranslations)
+		if err != nil {
+			return fmt.Errorf("failed to translate %s %s/%s (%w)", gvk.Kind, object.GetNamespace(),
+				object.GetName(), err)
+		}
+
+		if !rewritten {
+			continue
+		}
+
+		if err := v.reconciler.Patch(v.ctx, translated, client.MergeFrom(object)); err != nil {
+			return fmt.Errorf("failed to rewrite %s %s/%s (%w)", gvk.Kind, object.GetNamespace(),
+				object.GetName(), err)
+		}
+
+		v.log.Info("Hostnames and gateway class rewritten", "kind", gvk.Kind, "name", object.GetName(),
+			"namespace", object.GetNamespace())
+	}
+
+	return nil
+}
+
+// routeTranslate rewrites the hostnames of an Ingress, Gateway or HTTPRoute, and the gateway class of a Gateway, and
+// returns true if any was rewritten
+func routeTranslate(object *unstructured.Unstructured, domainSuffixTranslations []ramen.DomainSuffixTranslation,
+	gatewayClassTranslations []ramen.GatewayClassTranslation,
+) (bool, error) {
+	hostname := func(value interface{}) (interface{}, bool) {
+		host, ok := value.(string)
+		if !ok {
+			return value, false
+		}
+
+		return hostnameTranslate(host, domainSuffixTranslations)
+	}
+
+	switch object.GroupVersionKind().GroupKind() {
+	case ingressGroupKind:
+		rules, err := unstructuredSliceRewrite(object, hostname, "spec", "rules", "host")
+		if err != nil {
+			return false, err
+		}
+
+		tls, err := unstructuredSliceRewrite(object, func(value interface{}) (interface{}, bool) {
+			return stringsRewrite(value, hostname)
+		}, "spec", "tls", "hosts")
+
+		return rules || tls, err
+	case gatewayGroupKind:
+		listeners, err := unstructuredSliceRewrite(object, hostname, "spec", "listeners", "hostname")
+		if err != nil {
+			return false, err
+		}
+
+		className, _, err := unstructured.NestedString(object.Object, "spec", "gatewayClassName")
+		if err != nil {
+			return false, err
+		}
+
+		for _, translation := range gatewayClassTranslations {
+			if translation.Source == className && translation.Target != className {
+				return true, unstructured.SetNestedField(object.Object, translation.Target, "spec", "gatewayClassName")
+			}
+		}
+
+		return listeners, nil
+	case httpRouteGroupKind:
+		hostnames, found, err := unstructured.NestedFieldNoCopy(object.Object, "spec", "hostnames")
+		if err != nil || !found {
+			return false, err
+		}
+
+		rewritten, ok := stringsRewrite(hostnames, hostname)
+		if !ok {
+			return false, nil
+		}
+
+		return true, unstructured.SetNestedField(object.Object, rewritten, "spec", "hostnames")
+	}
+
+	return false, nil
+}
+
+// unstructuredSliceRewrite rewrites a field of each item of a slice field, and returns true if any was rewritten
+func unstructuredSliceRewrite(object *unstructured.Unstructured, rewrite func(interface{}) (interface{}, bool),
+	fields ...string,
+) (bool, error) {
+	sliceFields, itemField := fields[:len(fields)-1], fields[len(fields)-1]
+
+	items, found, err := unstructured.NestedSlice(object.Object, sliceFields...)
+	if err != nil || !found {
+		return false, err
+	}
+
+	rewritten := false
+
+	for i := range items {
+		item, ok := items[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		value, ok := item[itemField]
+		if !ok {
+			continue
+		}
+
+		if item[itemField], ok = rewrite(value); ok {
+			rewritten = true
+		}
+	}
+
+	if !rewritten {
+		return false, nil
+	}
+
+	return true, unstructured.SetNestedSlice(object.Object, items, sliceFields...)
+}
+
+// stringsRewrite rewrites each string of a slice, and returns true if any was rewritten
+func stringsRewrite(value interface{}, rewrite func(interface{}) (interface{}, bool)) (interface{}, bool) {
+	values, ok := value.([]interface{})
+	if !ok {
+		return value, false
+	}
+
+	rewritten := false
+
+	for i := range values {
+		if values[i], ok = rewrite(values[i]); ok {
+			rewritten = true
+		}
+	}
+
+	return values, rewritten
+}
+
+// hostnameTranslate returns the hostname rewritten to the target of the longest source domain suffix it is in,
+// unless it is in the target domain already, so that a hostname is rewritten once
+func hostnameTranslate(hostname string, translations []ramen.DomainSuffixTranslation) (string, bool) {
+	var match *ramen.DomainSuffixTranslation
+
+	for i := range translations {
+		source := translations[i].Source
+		if source == "" || !hostnameInDomain(hostname, source) {
+			continue
+		}
+
+		if match == nil || len(source) > len(match.Source) {
+			match = &translations[i]
+		}
+	}
+
+	if match == nil || hostnameInDomain(hostname, match.Target) {
+		return hostname, false
+	}
+
+	return strings.TrimSuffix(hostname, match.Source) + match.Target, true
+}
+
+func hostnameInDomain(hostname, domain string) bool {
+	return hostname == domain || strings.HasSuffix(hostname, "."+domain)
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the rewriting of the hostnames and gateway classes of recovered routes
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_RouteTranslations", func() {
+	domains := []ramen.DomainSuffixTranslation{
+		{Source: "apps.east.example.com", Target: "apps.west.example.com"},
+		{Source: "east.example.com", Target: "west.example.com"},
+	}
+	gatewayClasses := []ramen.GatewayClassTranslation{{Source: "east-lb", Target: "west-lb"}}
+
+	Describe("hostnameTranslate", func() {
+		translate := func(hostname string) []interface{} {
+			translated, ok := hostnameTranslate(hostname, domains)
+
+			return []interface{}{translated, ok}
+		}
+
+		It("rewrites the hostnames of the longest source domain suffix", func() {
+			Expect(translate("shop.apps.east.example.com")).To(Equal([]interface{}{"shop.apps.west.example.com", true}))
+			Expect(translate("*.apps.east.example.com")).To(Equal([]interface{}{"*.apps.west.example.com", true}))
+			Expect(translate("api.east.example.com")).To(Equal([]interface{}{"api.west.example.com", true}))
+			Expect(translate("east.example.com")).To(Equal([]interface{}{"west.example.com", true}))
+		})
+
+		It("keeps the hostnames outside the source domains, or in the target domain already", func() {
+			Expect(translate("shop.apps.west.example.com")).To(Equal([]interface{}{"shop.apps.west.example.com", false}))
+			Expect(translate("shopeast.example.com")).To(Equal([]interface{}{"shopeast.example.com", false}))
+			Expect(translate("example.com")).To(Equal([]interface{}{"example.com", false}))
+		})
+
+		It("rewrites a hostname once, whose target domain is in its source domain", func() {
+			translations := []ramen.DomainSuffixTranslation{{Source: "example.com", Target: "dr.example.com"}}
+
+			translated, ok := hostnameTranslate("shop.example.com", translations)
+			Expect(ok).To(BeTrue())
+			Expect(translated).To(Equal("shop.dr.example.com"))
+
+			_, ok = hostnameTranslate(translated, translations)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("routeTranslate", func() {
+		object := func(apiVersion, kind string, spec map[string]interface{}) *unstructured.Unstructured {
+			return &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": apiVersion, "kind": kind, "spec": spec,
+			}}
+		}
+
+		It("rewrites the hosts of the rules and TLS of an Ingress", func() {
+			ingress := object("networking.k8s.io/v1", "Ingress", map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"host": "shop.apps.east.example.com"}},
+				"tls": []interface{}{map[string]interface{}{
+					"hosts": []interface{}{"shop.apps.east.example.com", "other.example.org"},
+				}},
+			})
+
+			Expect(routeTranslate(ingress, domains, gatewayClasses)).To(BeTrue())
+			Expect(ingress.Object["spec"]).To(Equal(map[string]interface{}{
+				"rules": []interface{}{map[string]interface{}{"host": "shop.apps.west.example.com"}},
+				"tls": []interface{}{map[string]interface{}{
+					"hosts": []interface{}{"shop.apps.west.example.com", "other.example.org"},
+				}},
+			}))
+		})
+
+		It("rewrites the listener hostnames and gateway class of a Gateway", func() {
+			gateway := object("gateway.networking.k8s.io/v1", "Gateway", map[string]interface{}{
+				"gatewayClassName": "east-lb",
+				"listeners":        []interface{}{map[string]interface{}{"hostname": "*.apps.east.example.com"}},
+			})
+
+			Expect(routeTranslate(gateway, domains, gatewayClasses)).To(BeTrue())
+			Expect(gateway.Object["spec"]).To(Equal(map[string]interface{}{
+				"gatewayClassName": "west-lb",
+				"listeners":        []interface{}{map[string]interface{}{"hostname": "*.apps.west.example.com"}},
+			}))
+		})
+
+		It("rewrites the hostnames of an HTTPRoute, and reports an unchanged one", func() {
+			route := object("gateway.networking.k8s.io/v1", "HTTPRoute", map[string]interface{}{
+				"hostnames": []interface{}{"shop.apps.east.example.com"},
+			})
+
+			Expect(routeTranslate(route, domains, gatewayClasses)).To(BeTrue())
+			Expect(route.Object["spec"]).To(HaveKeyWithValue("hostnames",
+				[]interface{}{"shop.apps.west.example.com"}))
+			Expect(routeTranslate(route, domains, gatewayClasses)).To(BeFalse())
+		})
+	})
+
+	Describe("routeTranslationsApply", func() {
+		ingress := func(name string, labels map[string]string) *networkingv1.Ingress {
+			return &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: labels},
+				Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{
+					{Host: "shop.apps.east.example.com"},
+				}},
+			}
+		}
+
+		It("rewrites the recovered Ingresses of the protected namespaces only", func() {
+			restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{networkingv1.SchemeGroupVersion})
+			restMapper.Add(networkingv1.SchemeGroupVersion.WithKind("Ingress"), meta.RESTScopeNamespace)
+
+			c := fake.NewClientBuilder().WithRESTMapper(restMapper).WithObjects(
+				ingress("recovered", map[string]string{veleroRestoreNameLabel: "restore"}),
+				ingress("created", nil),
+			).Build()
+			v := vrgInstanceFake(c, "vrg-route-translations-test", ramen.VolumeReplicationGroupSpec{
+				DomainSuffixTranslations: domains,
+			})
+
+			Expect(v.routeTranslationsApply()).To(Succeed())
+
+			host := func(name string) string {
+				object := &networkingv1.Ingress{}
+				Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, object)).To(Succeed())
+
+				return object.Spec.Rules[0].Host
+			}
+			Expect(host("recovered")).To(Equal("shop.apps.west.example.com"))
+			Expect(host("created")).To(Equal("shop.apps.east.example.com"))
+		})
+	})
+})
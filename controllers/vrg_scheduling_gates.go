@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// SchedulingGateVolumesReady gates the scheduling of the pods of the recovered workloads until the PVCs they mount
+// are ready
+const SchedulingGateVolumesReady = "ramendr.openshift.io/volumes-ready"
+
+// schedulingGateResourceModifier is the Velero resource modifier that gates the scheduling of the pods of the
+// restored objects of a kind. A patch whose test fails is skipped: the first adds the scheduling gates of the pod
+// templates that have none.
+const schedulingGateResourceModifier = `- conditions:
+    groupResource: %[1]s
+    namespaces:
+%[2]s
+  patches:
+  - operation: test
+    path: /spec/template/spec/schedulingGates
+    value: "null"
+  - operation: add
+    path: /spec/template/spec/schedulingGates
+    value: "[]"
+- conditions:
+    groupResource: %[1]s
+    namespaces:
+%[2]s
+  patches:
+  - operation: add
+    path: /spec/template/spec/schedulingGates/-
+    value: '{"name": "%[3]s"}'
+`
+
+// schedulingGateResourceModifiers returns the Velero resource modifiers that gate the scheduling of the pods of the
+// deployments, stateful sets and daemon sets restored to the protected namespaces, as they are restored
+func (v *VRGInstance) schedulingGateResourceModifiers(gate string) string {
+	namespaces := "    - " + strings.Join(v.workloadNamespaces(), "\n    - ")
+
+	var resourceModifiers strings.Builder
+
+	for _, groupResource := range []string{"deployments.apps", "statefulsets.apps", "daemonsets.apps"} {
+		fmt.Fprintf(&resourceModifiers, schedulingGateResourceModifier, groupResource, namespaces, gate)
+	}
+
+	return resourceModifiers.String()
+}
+
+func schedulingGatedOnVolumes(vrg ramen.VolumeReplicationGroup) bool {
+	return vrg.Spec.KubeObjectProtection != nil && vrg.Spec.KubeObjectProtection.GateSchedulingOnVolumes
+}
+
+// volumesReadyResourceModifiers returns, if the VRG gates scheduling on volumes, the Velero resource modifiers that
+// restore the deployments, stateful sets and daemon sets with the volumes ready scheduling gate, so that none of
+// their pods is scheduled before the gate is lifted
+func (v *VRGInstance) volumesReadyResourceModifiers() string {
+	if !schedulingGatedOnVolumes(*v.instance) {
+		return ""
+	}
+
+	return v.schedulingGateResourceModifiers(SchedulingGateVolumesReady)
+}
+
+type gatedWorkload struct {
+	object   client.Object
+	template *corev1.PodTemplateSpec
+	claims   []string
+}
+
+// schedulingGatesLift, if the VRG gates scheduling on volumes, lifts the volumes ready scheduling gate from the pod
+// templates of the recovered workloads whose PVCs are all ready. It is called before each recover group is
+// submitted, for the hooks of the later groups to find the pods of the workloads recovered before them running, and
+// on every reconcile of a primary VRG, so the workloads are listed from the cache. The pods of the lifted stateful
+// sets and daemon sets that were created gated, and never started, are then deleted, for them to be created again
+// from the lifted templates, a stateful set not replacing a pod that is not ready; a deployment rolls its gated pods
+// over itself. The pods are not lifted themselves, for a pod not to start only to be replaced as its template is.
+func (v *VRGInstance) schedulingGatesLift() error {
+	if !schedulingGatedOnVolumes(*v.instance) {
+		return nil
+	}
+
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		workloads, err := v.gatedWorkloadsList(listOptions)
+		if err != nil {
+			return err
+		}
+
+		lifted := sets.New[types.UID]()
+
+		for _, workload := range workloads {
+			if err := v.schedulingGateLift(workload); err != nil {
+				return err
+			}
+
+			if !podSchedulingGated(&workload.template.Spec, SchedulingGateVolumesReady) {
+				lifted.Insert(workload.object.GetUID())
+			}
+		}
+
+		if err := v.volumesReadyGatedPodsDelete(listOptions.Namespace, lifted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) schedulingGateLift(workload gatedWorkload) error {
+	if !podSchedulingGated(&workload.template.Spec, SchedulingGateVolumesReady) {
+		return nil
+	}
+
+	namespace := workload.object.GetNamespace()
+
+	ready, err := v.claimsReady(namespace, workload.claims)
+	if err != nil || !ready {
+		return err
+	}
+
+	podSchedulingGateRemove(&workload.template.Spec, SchedulingGateVolumesReady)
+
+	if err := v.reconciler.Update(v.ctx, workload.object); err != nil {
+		return fmt.Errorf("failed to lift scheduling gate of %T %s/%s (%w)", workload.object, namespace,
+			workload.object.GetName(), err)
+	}
+
+	v.log.Info("Recovered workload scheduling gate lifted", "kind", fmt.Sprintf("%T", workload.object),
+		"name", workload.object.GetName(), "namespace", namespace)
+
+	return nil
+}
+
+// volumesReadyGatedPodsDelete deletes the pods gated until their volumes are ready whose controller is one of the
+// lifted workloads
+func (v *VRGInstance) volumesReadyGatedPodsDelete(namespace string, lifted sets.Set[types.UID]) error {
+	if lifted.Len() == 0 {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := v.reconciler.List(v.ctx, pods, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s (%w)", namespace, err)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		controller := metav1.GetControllerOf(pod)
+
+		if controller == nil || !lifted.Has(controller.UID) ||
+			!podSchedulingGated(&pod.Spec, SchedulingGateVolumesReady) || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if err := v.reconciler.Delete(v.ctx, pod); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod %s/%s gated until its volumes are ready (%w)", namespace,
+				pod.Name, err)
+		}
+
+		v.log.Info("Pod gated until its volumes are ready deleted", "name", pod.Name, "namespace", namespace)
+	}
+
+	return nil
+}
+
+// gatedWorkloadsList lists the deployments, stateful sets and daemon sets from the cache, with the PVCs their pods
+// mount. The PVCs of the claim templates of a stateful set are those of its replicas.
+func (v *VRGInstance) gatedWorkloadsList(listOptions client.ListOption) ([]gatedWorkload, error) {
+	deployments := &appsv1.DeploymentList{}
+	statefulSets := &appsv1.StatefulSetList{}
+	daemonSets := &appsv1.DaemonSetList{}
+
+	for _, list := range []client.ObjectList{deployments, statefulSets, daemonSets} {
+		if err := v.reconciler.List(v.ctx, list, listOptions); err != nil {
+			return nil, fmt.Errorf("failed to list %T (%w)", list, err)
+		}
+	}
+
+	workloads := []gatedWorkload{}
+
+	for i := range deployments.Items {
+		template := &deployments.Items[i].Spec.Template
+		workloads = append(workloads, gatedWorkload{&deployments.Items[i], template, podSpecClaims(&template.Spec)})
+	}
+
+	for i := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[i]
+		template := &statefulSet.Spec.Template
+		workloads = append(workloads, gatedWorkload{statefulSet, template,
+			append(podSpecClaims(&template.Spec), statefulSetClaims(statefulSet)...)})
+	}
+
+	for i := range daemonSets.Items {
+		template := &daemonSets.Items[i].Spec.Template
+		workloads = append(workloads, gatedWorkload{&daemonSets.Items[i], template, podSpecClaims(&template.Spec)})
+	}
+
+	return workloads, nil
+}
+
+func statefulSetClaims(statefulSet *appsv1.StatefulSet) []string {
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	claims := []string{}
+
+	for _, claimTemplate := range statefulSet.Spec.VolumeClaimTemplates {
+		for ordinal := int32(0); ordinal < replicas; ordinal++ {
+			claims = append(claims, fmt.Sprintf("%s-%s-%d", claimTemplate.Name, statefulSet.Name, ordinal))
+		}
+	}
+
+	return claims
+}
+
+func podSpecClaims(podSpec *corev1.PodSpec) []string {
+	claims := []string{}
+
+	for _, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+
+	return claims
+}
+
+// claimsReady returns whether the PVCs are bound, or wait for the first pod that mounts them to be scheduled to be
+// bound, and, for those the VRG protects, whether their data is ready
+func (v *VRGInstance) claimsReady(namespace string, claims []string) (bool, error) {
+	for _, claim := range claims {
+		pvc := &corev1.PersistentVolumeClaim{}
+
+		if err := v.reconciler.Get(v.ctx, types.NamespacedName{Namespace: namespace, Name: claim}, pvc); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, fmt.Errorf("failed to get PVC %s/%s (%w)", namespace, claim, err)
+		}
+
+		if pvc.Status.Phase != corev1.ClaimBound {
+			waitsForFirstConsumer, err := v.claimWaitsForFirstConsumer(pvc)
+			if err != nil || !waitsForFirstConsumer {
+				return false, err
+			}
+		}
+
+		protectedPVC := v.findProtectedPVC(namespace, claim)
+		if protectedPVC == nil {
+			continue
+		}
+
+		condition := meta.FindStatusCondition(protectedPVC.Conditions, VRGConditionTypeDataReady)
+		if condition != nil && condition.Status != metav1.ConditionTrue {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// claimWaitsForFirstConsumer returns whether a pending PVC's storage class binds its volumes only once a pod that
+// mounts it is scheduled, for its pods not to be gated until it is bound, which they would be forever
+func (v *VRGInstance) claimWaitsForFirstConsumer(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Status.Phase != corev1.ClaimPending || pvc.Spec.StorageClassName == nil ||
+		*pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := v.reconciler.Get(v.ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName},
+		storageClass); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get storage class %s of PVC %s/%s (%w)", *pvc.Spec.StorageClassName,
+			pvc.Namespace, pvc.Name, err)
+	}
+
+	return storageClass.VolumeBindingMode != nil &&
+		*storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer, nil
+}
+
+func podSchedulingGated(podSpec *corev1.PodSpec, name string) bool {
+	for _, gate := range podSpec.SchedulingGates {
+		if gate.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func podSchedulingGateRemove(podSpec *corev1.PodSpec, name string) {
+	gates := []corev1.PodSchedulingGate{}
+
+	for _, gate := range podSpec.SchedulingGates {
+		if gate.Name != name {
+			gates = append(gates, gate)
+		}
+	}
+
+	podSpec.SchedulingGates = gates
+}
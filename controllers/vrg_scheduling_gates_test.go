@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the scheduling gates of the recovered workloads until their volumes are ready
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_SchedulingGates", func() {
+	const namespace = "app"
+
+	var (
+		c           client.Client
+		vrgInstance *VRGInstance
+	)
+
+	restoredLabels := map[string]string{veleroRestoreNameLabel: "restore"}
+	gates := []corev1.PodSchedulingGate{{Name: SchedulingGateVolumesReady}}
+	claimVolume := func(claim string) corev1.Volume {
+		return corev1.Volume{Name: claim, VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim},
+		}}
+	}
+	pvc := func(name, storageClassName string, phase corev1.PersistentVolumeClaimPhase,
+	) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	storageClass := func(name string, volumeBindingMode storagev1.VolumeBindingMode) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: name},
+			VolumeBindingMode: &volumeBindingMode,
+		}
+	}
+	deployment := func(name string, claims ...string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: name, Labels: restoredLabels, UID: types.UID(name),
+		}}
+		deployment.Spec.Template.Spec.SchedulingGates = gates
+
+		for _, claim := range claims {
+			deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, claimVolume(claim))
+		}
+
+		return deployment
+	}
+	replicas := int32(2)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "db", Labels: restoredLabels, UID: "db"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{SchedulingGates: gates}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+			},
+		},
+	}
+	pod := func(name string, controller types.UID) *corev1.Pod {
+		isController := true
+
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1", Kind: "StatefulSet", Name: string(controller), UID: controller,
+					Controller: &isController,
+				}},
+			},
+			Spec: corev1.PodSpec{SchedulingGates: gates},
+		}
+	}
+	templateGated := func(object client.Object) bool {
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(object), object)).To(Succeed())
+
+		switch object := object.(type) {
+		case *appsv1.Deployment:
+			return podSchedulingGated(&object.Spec.Template.Spec, SchedulingGateVolumesReady)
+		case *appsv1.StatefulSet:
+			return podSchedulingGated(&object.Spec.Template.Spec, SchedulingGateVolumesReady)
+		}
+
+		return false
+	}
+	podNames := func() []string {
+		pods := &corev1.PodList{}
+		Expect(c.List(context.TODO(), pods)).To(Succeed())
+
+		names := []string{}
+		for i := range pods.Items {
+			names = append(names, pods.Items[i].Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		c = fake.NewClientBuilder().WithObjects(
+			storageClass("immediate", storagev1.VolumeBindingImmediate),
+			storageClass("wffc", storagev1.VolumeBindingWaitForFirstConsumer),
+			pvc("bound", "immediate", corev1.ClaimBound),
+			pvc("pending", "immediate", corev1.ClaimPending),
+			pvc("first-consumer", "wffc", corev1.ClaimPending),
+			pvc("data-db-0", "immediate", corev1.ClaimBound),
+			pvc("data-db-1", "immediate", corev1.ClaimBound),
+			deployment("web", "bound", "first-consumer"),
+			deployment("waiting", "bound", "pending"),
+			deployment("stateless"),
+			statefulSet.DeepCopy(),
+			pod("db-0", "db"),
+			pod("waiting-1", "waiting"),
+		).Build()
+		vrgInstance = vrgInstanceFake(c, "vrg-scheduling-gates-test", ramen.VolumeReplicationGroupSpec{
+			KubeObjectProtection: &ramen.KubeObjectProtectionSpec{GateSchedulingOnVolumes: true},
+		})
+	})
+
+	Describe("claimsReady", func() {
+		It("returns whether the PVCs are bound or wait for their first consumer", func() {
+			Expect(vrgInstance.claimsReady(namespace, []string{"bound", "first-consumer"})).To(BeTrue())
+			Expect(vrgInstance.claimsReady(namespace, []string{"bound", "pending"})).To(BeFalse())
+			Expect(vrgInstance.claimsReady(namespace, []string{"missing"})).To(BeFalse())
+		})
+
+		It("returns whether the data of the PVCs the VRG protects is ready", func() {
+			vrgInstance.instance.Status.ProtectedPVCs = []ramen.ProtectedPVC{{
+				Namespace: namespace, Name: "bound",
+				Conditions: []metav1.Condition{{Type: VRGConditionTypeDataReady, Status: metav1.ConditionFalse}},
+			}}
+			Expect(vrgInstance.claimsReady(namespace, []string{"bound"})).To(BeFalse())
+
+			vrgInstance.instance.Status.ProtectedPVCs[0].Conditions[0].Status = metav1.ConditionTrue
+			Expect(vrgInstance.claimsReady(namespace, []string{"bound"})).To(BeTrue())
+		})
+	})
+
+	Describe("schedulingGatesLift", func() {
+		It("lifts the gate of the workloads whose PVCs are ready, and deletes the gated pods of those lifted", func() {
+			Expect(vrgInstance.schedulingGatesLift()).To(Succeed())
+
+			Expect(templateGated(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"}})).
+				To(BeFalse())
+			Expect(templateGated(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: "stateless",
+			}})).To(BeFalse())
+			Expect(templateGated(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: "waiting",
+			}})).To(BeTrue())
+			Expect(templateGated(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "db"}})).
+				To(BeFalse())
+			Expect(podNames()).To(ConsistOf("waiting-1"))
+		})
+
+		It("keeps a stateful set gated until the PVCs of all its replicas are ready", func() {
+			Expect(c.Delete(context.TODO(), pvc("data-db-1", "", ""))).To(Succeed())
+			Expect(vrgInstance.schedulingGatesLift()).To(Succeed())
+
+			Expect(templateGated(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "db"}})).
+				To(BeTrue())
+			Expect(podNames()).To(ConsistOf("db-0", "waiting-1"))
+		})
+
+		It("lifts none for a VRG that does not gate scheduling on volumes", func() {
+			vrgInstance.instance.Spec.KubeObjectProtection.GateSchedulingOnVolumes = false
+			Expect(vrgInstance.schedulingGatesLift()).To(Succeed())
+
+			Expect(templateGated(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"}})).
+				To(BeTrue())
+			Expect(podNames()).To(HaveLen(2))
+		})
+	})
+
+	Describe("volumesReadyResourceModifiers", func() {
+		It("gates the pods of the restored workloads only for a VRG that gates scheduling on volumes", func() {
+			rules := []map[string]interface{}{}
+			Expect(yaml.Unmarshal([]byte(vrgInstance.volumesReadyResourceModifiers()), &rules)).To(Succeed())
+			Expect(rules).To(HaveLen(6))
+			Expect(rules[5]).To(HaveKeyWithValue("conditions", map[string]interface{}{
+				"groupResource": "daemonsets.apps",
+				"namespaces":    []interface{}{namespace},
+			}))
+			Expect(rules[5]).To(HaveKeyWithValue("patches", []interface{}{map[string]interface{}{
+				"operation": "add",
+				"path":      "/spec/template/spec/schedulingGates/-",
+				"value":     `{"name": "` + SchedulingGateVolumesReady + `"}`,
+			}}))
+
+			vrgInstance.instance.Spec.KubeObjectProtection.GateSchedulingOnVolumes = false
+			Expect(vrgInstance.volumesReadyResourceModifiers()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+const (
+	// secretRewrittenAnnotation is set on a recovered Secret to the name of the restore it was recovered by, once
+	// its values are rewritten, so that they are rewritten once per recovery
+	secretRewrittenAnnotation = "volumereplicationgroups.ramendr.openshift.io/secret-rewritten"
+
+	secretsResource = "secrets"
+)
+
+// secretRewriteCompiled is a secret rewrite with its selector and pattern parsed
+type secretRewriteCompiled struct {
+	selector    labels.Selector
+	keys        []string
+	pattern     *regexp.Regexp
+	replacement []byte
+}
+
+// validateSecretRewrites returns an error if a selector or pattern of the secret rewrites of a DRCluster is invalid
+func validateSecretRewrites(drcluster *ramen.DRCluster) error {
+	_, err := secretRewritesCompile(drcluster.Spec.SecretRewrites)
+
+	return err
+}
+
+func secretRewritesCompile(rewrites []ramen.SecretRewrite) ([]secretRewriteCompiled, error) {
+	compiled := make([]secretRewriteCompiled, 0, len(rewrites))
+
+	for i := range rewrites {
+		rewrite := &rewrites[i]
+
+		pattern, err := regexp.Compile(rewrite.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("secret rewrite %d pattern %q invalid: %w", i, rewrite.Pattern, err)
+		}
+
+		selector := labels.Everything()
+		if rewrite.Selector != nil {
+			if selector, err = metav1.LabelSelectorAsSelector(rewrite.Selector); err != nil {
+				return nil, fmt.Errorf("secret rewrite %d selector invalid: %w", i, err)
+			}
+		}
+
+		compiled = append(compiled, secretRewriteCompiled{
+			selector:    selector,
+			keys:        rewrite.Keys,
+			pattern:     pattern,
+			replacement: []byte(rewrite.Replacement),
+		})
+	}
+
+	return compiled, nil
+}
+
+// recoverWorkflowSecretsFirst, if the VRG rewrites the values of the recovered Secrets, excludes the Secrets from
+// the groups of a recover workflow that recover every kind, and inserts before the first of them, for each capture
+// they recover from, a group that recovers its Secrets, so that the Secrets are rewritten before the workloads that
+// consume them start. Groups that run a hook, recover from a capture taken at recovery, list the kinds they recover,
+// or exclude the Secrets already, are kept as they are.
+func recoverWorkflowSecretsFirst(workflow []kubeobjects.RecoverSpec, vrg ramen.VolumeReplicationGroup,
+) []kubeobjects.RecoverSpec {
+	if len(vrg.Spec.SecretRewrites) == 0 {
+		return workflow
+	}
+
+	separated := make([]kubeobjects.RecoverSpec, 0, len(workflow)+1)
+	backupNames := sets.New[string]()
+
+	for _, group := range workflow {
+		if group.BackupName == ramen.ReservedBackupName || kubeObjectsHookRunnable(group.Spec) != nil ||
+			(len(group.IncludedResources) != 0 && !containsString(group.IncludedResources, "*")) ||
+			containsString(group.ExcludedResources, secretsResource) {
+			separated = append(separated, group)
+
+			continue
+		}
+
+		if !backupNames.Has(group.BackupName) {
+			backupNames.Insert(group.BackupName)
+
+			separated = append(separated, kubeobjects.RecoverSpec{
+				BackupName: group.BackupName,
+				Spec: kubeobjects.Spec{
+					KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+						IncludedNamespaces: group.IncludedNamespaces,
+						IncludedResources:  []string{secretsResource},
+					},
+					LabelSelector: group.LabelSelector,
+				},
+				NamespaceMapping: group.NamespaceMapping,
+			})
+		}
+
+		group.ExcludedResources = append(append([]string{}, group.ExcludedResources...), secretsResource)
+		separated = append(separated, group)
+	}
+
+	return separated
+}
+
+// secretRewritesApply rewrites the values of the recovered Secrets, such as connection strings that embed the
+// endpoints of the region of the cluster they were protected on, to the endpoints of this cluster's region
+func (v *VRGInstance) secretRewritesApply() error {
+	if len(v.instance.Spec.SecretRewrites) == 0 {
+		return nil
+	}
+
+	rewrites, err := secretRewritesCompile(v.instance.Spec.SecretRewrites)
+	if err != nil {
+		return err
+	}
+
+	namespacesListOptions, err := v.restoredObjectsListOptions()
+	if err != nil {
+		return err
+	}
+
+	for _, listOptions := range namespacesListOptions {
+		secrets := &corev1.SecretList{}
+		if err := v.reconciler.APIReader.List(v.ctx, secrets, listOptions); err != nil {
+			return fmt.Errorf("failed to list secrets in namespace %s (%w)", listOptions.Namespace, err)
+		}
+
+		for i := range secrets.Items {
+			if err := v.secretRewrite(&secrets.Items[i], rewrites); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) secretRewrite(secret *corev1.Secret, rewrites []secretRewriteCompiled) error {
+	restoreName := secret.GetLabels()[veleroRestoreNameLabel]
+	if secret.GetAnnotations()[secretRewrittenAnnotation] == restoreName {
+		return nil
+	}
+
+	rewritten := secret.DeepCopy()
+
+	keys := secretValuesRewrite(rewritten, rewrites)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if rewritten.Annotations == nil {
+		rewritten.Annotations = map[string]string{}
+	}
+
+	rewritten.Annotations[secretRewrittenAnnotation] = restoreName
+
+	if err := v.reconciler.Patch(v.ctx, rewritten, client.MergeFrom(secret)); err != nil {
+		return fmt.Errorf("failed to rewrite secret %s/%s (%w)", secret.Namespace, secret.Name, err)
+	}
+
+	v.log.Info("Secret values rewritten", "name", secret.Name, "namespace", secret.Namespace, "keys", keys)
+
+	return nil
+}
+
+// secretValuesRewrite rewrites the values of a Secret by the rewrites that select it, in order, and returns the
+// keys of the values rewritten
+func secretValuesRewrite(secret *corev1.Secret, rewrites []secretRewriteCompiled) []string {
+	keys := []string{}
+
+	for i := range rewrites {
+		rewrite := &rewrites[i]
+		if !rewrite.selector.Matches(labels.Set(secret.GetLabels())) {
+			continue
+		}
+
+		for key, value := range secret.Data {
+			if len(rewrite.keys) != 0 && !slices.Contains(rewrite.keys, key) {
+				continue
+			}
+
+			if !rewrite.pattern.Match(value) {
+				continue
+			}
+
+			secret.Data[key] = rewrite.pattern.ReplaceAll(value, rewrite.replacement)
+
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the rewriting of the values of recovered secrets
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/kubeobjects"
+)
+
+var _ = Describe("VRG_SecretRewrites", func() {
+	rewrites := []ramen.SecretRewrite{
+		{
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			Keys:        []string{"DATABASE_URL"},
+			Pattern:     `(db|cache)\.us-east-1\.example\.com`,
+			Replacement: "${1}.us-west-2.example.com",
+		},
+		{Pattern: `us-east-1`, Replacement: "us-west-2"},
+	}
+	secret := func(name string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: labels},
+			Data: map[string][]byte{
+				"DATABASE_URL": []byte("postgres://db.us-east-1.example.com/shop"),
+				"CACHE_URL":    []byte("redis://cache.us-east-1.example.com"),
+				"USER":         []byte("shop"),
+			},
+		}
+	}
+
+	Describe("secretValuesRewrite", func() {
+		It("rewrites the values of the keys of the secrets each rewrite selects, in order", func() {
+			compiled, err := secretRewritesCompile(rewrites[:1])
+			Expect(err).ToNot(HaveOccurred())
+
+			shop := secret("shop", map[string]string{"app": "shop"})
+			Expect(secretValuesRewrite(shop, compiled)).To(Equal([]string{"DATABASE_URL"}))
+			Expect(string(shop.Data["DATABASE_URL"])).To(Equal("postgres://db.us-west-2.example.com/shop"))
+			Expect(string(shop.Data["CACHE_URL"])).To(Equal("redis://cache.us-east-1.example.com"))
+
+			other := secret("other", map[string]string{"app": "other"})
+			Expect(secretValuesRewrite(other, compiled)).To(BeEmpty())
+
+			compiled, err = secretRewritesCompile(rewrites)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretValuesRewrite(other, compiled)).To(Equal([]string{"CACHE_URL", "DATABASE_URL"}))
+			Expect(string(other.Data["CACHE_URL"])).To(Equal("redis://cache.us-west-2.example.com"))
+		})
+
+		It("fails to compile an invalid pattern", func() {
+			_, err := secretRewritesCompile([]ramen.SecretRewrite{{Pattern: "("}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("recoverWorkflowSecretsFirst", func() {
+		group := func(backupName string, includedResources ...string) kubeobjects.RecoverSpec {
+			return kubeobjects.RecoverSpec{BackupName: backupName, Spec: kubeobjects.Spec{
+				KubeResourcesSpec: kubeobjects.KubeResourcesSpec{
+					IncludedNamespaces: []string{"app"},
+					IncludedResources:  includedResources,
+				},
+			}}
+		}
+		workflow := []kubeobjects.RecoverSpec{
+			group("config", "configmaps"),
+			group("app"),
+			group("app", "*"),
+			group(ramen.ReservedBackupName),
+		}
+
+		It("keeps the workflow of a VRG that rewrites no secrets", func() {
+			Expect(recoverWorkflowSecretsFirst(workflow, ramen.VolumeReplicationGroup{})).To(Equal(workflow))
+		})
+
+		It("recovers the secrets before the groups of their capture that recover every kind", func() {
+			vrg := ramen.VolumeReplicationGroup{Spec: ramen.VolumeReplicationGroupSpec{SecretRewrites: rewrites}}
+
+			separated := recoverWorkflowSecretsFirst(workflow, vrg)
+			Expect(separated).To(HaveLen(5))
+			Expect(separated[0]).To(Equal(workflow[0]))
+			Expect(separated[1].BackupName).To(Equal("app"))
+			Expect(separated[1].IncludedNamespaces).To(Equal([]string{"app"}))
+			Expect(separated[1].IncludedResources).To(Equal([]string{secretsResource}))
+			Expect(separated[2].ExcludedResources).To(Equal([]string{secretsResource}))
+			Expect(separated[3].ExcludedResources).To(Equal([]string{secretsResource}))
+			Expect(separated[4]).To(Equal(workflow[3]))
+			Expect(workflow[1].ExcludedResources).To(BeEmpty())
+		})
+
+		It("keeps the groups that exclude the secrets already, such as those after the identities", func() {
+			vrg := ramen.VolumeReplicationGroup{Spec: ramen.VolumeReplicationGroupSpec{SecretRewrites: rewrites}}
+			ramenConfig := ramen.RamenConfig{}
+			ramenConfig.KubeObjectProtection.IdentitiesRecoverFirst = true
+			identitiesFirst := recoverWorkflowDefault(ramenConfig)
+
+			Expect(recoverWorkflowSecretsFirst(identitiesFirst, vrg)).To(Equal(identitiesFirst))
+		})
+	})
+
+	Describe("secretRewritesApply", func() {
+		It("rewrites the recovered secrets once per restore", func() {
+			recovered := secret("recovered", map[string]string{veleroRestoreNameLabel: "restore-1"})
+			c := fake.NewClientBuilder().WithObjects(recovered, secret("created", nil)).Build()
+			v := vrgInstanceFake(c, "vrg-secret-rewrites-test", ramen.VolumeReplicationGroupSpec{
+				SecretRewrites: []ramen.SecretRewrite{{Pattern: `example\.com`, Replacement: "dr.example.com"}},
+			})
+			secretGet := func(name string) *corev1.Secret {
+				secret := &corev1.Secret{}
+				Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: name}, secret)).To(Succeed())
+
+				return secret
+			}
+
+			Expect(v.secretRewritesApply()).To(Succeed())
+			Expect(v.secretRewritesApply()).To(Succeed())
+
+			rewritten := secretGet("recovered")
+			Expect(string(rewritten.Data["CACHE_URL"])).To(Equal("redis://cache.us-east-1.dr.example.com"))
+			Expect(rewritten.Annotations).To(HaveKeyWithValue(secretRewrittenAnnotation, "restore-1"))
+			Expect(string(secretGet("created").Data["CACHE_URL"])).To(Equal("redis://cache.us-east-1.example.com"))
+
+			rewritten.Labels[veleroRestoreNameLabel] = "restore-2"
+			Expect(c.Update(context.TODO(), rewritten)).To(Succeed())
+			Expect(v.secretRewritesApply()).To(Succeed())
+			Expect(string(secretGet("recovered").Data["CACHE_URL"])).To(
+				Equal("redis://cache.us-east-1.dr.dr.example.com"))
+		})
+	})
+})
@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// serviceOverridesForCluster returns the kube object protection spec with only the service overrides that apply
+// to the cluster
+func serviceOverridesForCluster(spec *ramen.KubeObjectProtectionSpec, cluster string,
+) *ramen.KubeObjectProtectionSpec {
+	if spec == nil || len(spec.ServiceOverrides) == 0 {
+		return spec
+	}
+
+	spec = spec.DeepCopy()
+	overrides := spec.ServiceOverrides[:0]
+
+	for _, override := range spec.ServiceOverrides {
+		if serviceOverrideAppliesTo(override, cluster) {
+			overrides = append(overrides, override)
+		}
+	}
+
+	spec.ServiceOverrides = overrides
+
+	return spec
+}
+
+func serviceOverrideAppliesTo(override ramen.ServiceOverride, cluster string) bool {
+	return len(override.Clusters) == 0 || slices.Contains(override.Clusters, cluster)
+}
+
+// serviceOverridesApply applies the service overrides for this cluster to the recovered Services
+func (v *VRGInstance) serviceOverridesApply() error {
+	cluster := v.instance.GetAnnotations()[DestinationClusterAnnotationKey]
+
+	for _, override := range v.instance.Spec.KubeObjectProtection.ServiceOverrides {
+		if !serviceOverrideAppliesTo(override, cluster) {
+			continue
+		}
+
+		if err := v.serviceOverrideApply(override); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *VRGInstance) serviceOverrideApply(override ramen.ServiceOverride) error {
+	key := types.NamespacedName{Namespace: override.Namespace, Name: override.Name}
+	if key.Namespace == "" {
+		key.Namespace = v.instance.GetNamespace()
+	}
+
+	log := v.log.WithValues("service", key.String())
+
+	service := &corev1.Service{}
+	if err := v.reconciler.Get(v.ctx, key, service); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Service %s to override (%w)", key, err)
+		}
+
+		return v.serviceRecreateComplete(key)
+	}
+
+	if rmnutil.ResourceIsDeleted(service) {
+		return fmt.Errorf("waiting for Service %s to be deleted", key)
+	}
+
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		log.Info("Service override skipped, as the Service is not of type LoadBalancer", "type", service.Spec.Type)
+
+		return nil
+	}
+
+	overridden := service.DeepCopy()
+	serviceOverrideSet(overridden, override)
+
+	switch {
+	case reflect.DeepEqual(service, overridden):
+		return nil
+	case !reflect.DeepEqual(service.Spec.LoadBalancerClass, overridden.Spec.LoadBalancerClass):
+		return v.serviceRecreateStart(overridden)
+	}
+
+	if err := v.reconciler.Update(v.ctx, overridden); err != nil {
+		return fmt.Errorf("failed to override Service %s (%w)", key, err)
+	}
+
+	log.Info("Service overridden")
+
+	return nil
+}
+
+func serviceOverrideSet(service *corev1.Service, override ramen.ServiceOverride) {
+	for key, value := range override.Annotations {
+		if value == "" {
+			delete(service.Annotations, key)
+
+			continue
+		}
+
+		rmnutil.AddAnnotation(service, key, value)
+	}
+
+	if override.LoadBalancerClass != nil {
+		service.Spec.LoadBalancerClass = override.LoadBalancerClass
+	}
+
+	if override.LoadBalancerIP != "" {
+		service.Spec.LoadBalancerIP = override.LoadBalancerIP
+	}
+
+	if override.LoadBalancerSourceRanges != nil {
+		service.Spec.LoadBalancerSourceRanges = override.LoadBalancerSourceRanges
+	}
+}
+
+// serviceRecreateStart deletes a Service whose load balancer class is overridden, as the class of a Service cannot
+// be changed. The overridden Service is created by serviceRecreateComplete once the deletion completes, which may
+// wait for the load balancer to be cleaned up. The overridden Service is uploaded to the S3 stores of the VRG before
+// the Service is deleted, so that it is recreated even if the operator restarts meanwhile.
+func (v *VRGInstance) serviceRecreateStart(service *corev1.Service) error {
+	key := types.NamespacedName{Namespace: service.GetNamespace(), Name: service.GetName()}
+
+	recreate := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        service.GetName(),
+			Namespace:   service.GetNamespace(),
+			Labels:      service.GetLabels(),
+			Annotations: service.GetAnnotations(),
+		},
+		Spec: *service.Spec.DeepCopy(),
+	}
+
+	// Allocated on creation
+	recreate.Spec.ClusterIP = ""
+	recreate.Spec.ClusterIPs = nil
+
+	for idx := range recreate.Spec.Ports {
+		recreate.Spec.Ports[idx].NodePort = 0
+	}
+
+	if err := v.serviceRecreateUpload(key, recreate); err != nil {
+		return err
+	}
+
+	if err := v.reconciler.Delete(v.ctx, service); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Service %s to recreate it (%w)", key, err)
+	}
+
+	v.log.Info("Service deleted to recreate with its load balancer class overridden", "service", key.String())
+
+	return fmt.Errorf("waiting for Service %s to be deleted", key)
+}
+
+func (v *VRGInstance) serviceRecreateComplete(key types.NamespacedName) error {
+	service, err := v.serviceRecreateDownload(key)
+	if err != nil {
+		return err
+	}
+
+	if service == nil {
+		v.log.Info("Service override skipped, as the Service was not recovered", "service", key.String())
+
+		return nil
+	}
+
+	if err := v.reconciler.Create(v.ctx, service); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to recreate Service %s (%w)", key, err)
+	}
+
+	v.log.Info("Service recreated with its load balancer class overridden", "service", key.String())
+
+	return v.serviceRecreateDelete(key)
+}
+
+func serviceRecreateKeySuffix(key types.NamespacedName) string {
+	return key.Namespace + "/" + key.Name
+}
+
+// serviceRecreateUpload uploads a Service to recreate to each S3 store of the VRG
+func (v *VRGInstance) serviceRecreateUpload(key types.NamespacedName, service *corev1.Service) error {
+	if len(v.s3StoreAccessors) == 0 {
+		return fmt.Errorf("no S3 store to keep Service %s to recreate", key)
+	}
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		if err := uploadTypedObject(s3StoreAccessor.ObjectStorer, v.s3KeyPrefix(), serviceRecreateKeySuffix(key),
+			*service); err != nil {
+			return fmt.Errorf("failed to upload Service %s to recreate to S3 profile %s (%w)",
+				key, s3StoreAccessor.S3ProfileName, err)
+		}
+	}
+
+	return nil
+}
+
+// serviceRecreateDownload returns the Service to recreate from the first S3 store of the VRG that has it, or nil if
+// none has it
+func (v *VRGInstance) serviceRecreateDownload(key types.NamespacedName) (*corev1.Service, error) {
+	objectKey := typedKey(v.s3KeyPrefix(), serviceRecreateKeySuffix(key), reflect.TypeOf(corev1.Service{}))
+
+	var errs []error
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		keys, err := s3StoreAccessor.ObjectStorer.ListKeys(objectKey)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("S3 profile %s: %w", s3StoreAccessor.S3ProfileName, err))
+
+			continue
+		}
+
+		if !slices.Contains(keys, objectKey) {
+			continue
+		}
+
+		service := &corev1.Service{}
+		if err := s3StoreAccessor.ObjectStorer.DownloadObject(objectKey, service); err != nil {
+			errs = append(errs, fmt.Errorf("S3 profile %s: %w", s3StoreAccessor.S3ProfileName, err))
+
+			continue
+		}
+
+		return service, nil
+	}
+
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("failed to download Service %s to recreate (%w)", key, errors.Join(errs...))
+	}
+
+	return nil, nil
+}
+
+// serviceRecreateDelete deletes a recreated Service from the S3 stores of the VRG
+func (v *VRGInstance) serviceRecreateDelete(key types.NamespacedName) error {
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		if err := DeleteTypedObject(s3StoreAccessor.ObjectStorer, v.s3KeyPrefix(), serviceRecreateKeySuffix(key),
+			corev1.Service{}); err != nil {
+			return fmt.Errorf("failed to delete recreated Service %s from S3 profile %s (%w)",
+				key, s3StoreAccessor.S3ProfileName, err)
+		}
+	}
+
+	return nil
+}
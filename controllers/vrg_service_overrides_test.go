@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the recreation of overridden Services
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_ServiceOverrides", func() {
+	var (
+		store      memoryObjectStorer
+		service    *corev1.Service
+		override   ramen.ServiceOverride
+		vrg        *ramen.VolumeReplicationGroup
+		reconciler *VolumeReplicationGroupReconciler
+	)
+
+	vrgInstance := func() *VRGInstance {
+		return &VRGInstance{
+			reconciler:       reconciler,
+			ctx:              context.TODO(),
+			log:              ctrl.Log.WithName("vrg-service-overrides-test"),
+			instance:         vrg,
+			namespacedName:   vrg.Namespace + "/" + vrg.Name,
+			s3StoreAccessors: []s3StoreAccessor{{ObjectStorer: store}},
+		}
+	}
+
+	BeforeEach(func() {
+		store = memoryObjectStorer{}
+		vrg = &ramen.VolumeReplicationGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "vrg"}}
+		service = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "lb"},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: func(class string) *string { return &class }("east"),
+				ClusterIP:         "10.0.0.1",
+				Ports:             []corev1.ServicePort{{Port: 80, NodePort: 30080}},
+			},
+		}
+		override = ramen.ServiceOverride{
+			Namespace:         "app",
+			Name:              "lb",
+			LoadBalancerClass: func(class string) *string { return &class }("west"),
+		}
+		reconciler = &VolumeReplicationGroupReconciler{
+			Client: fake.NewClientBuilder().WithObjects(service.DeepCopy()).Build(),
+		}
+	})
+
+	Describe("serviceOverridesForCluster", func() {
+		It("keeps the overrides of the cluster, and those of all clusters", func() {
+			spec := &ramen.KubeObjectProtectionSpec{ServiceOverrides: []ramen.ServiceOverride{
+				{Name: "all"},
+				{Name: "east", Clusters: []string{"east"}},
+				{Name: "west", Clusters: []string{"west"}},
+			}}
+
+			overrides := serviceOverridesForCluster(spec, "east").ServiceOverrides
+			Expect(overrides).To(HaveLen(2))
+			Expect(overrides[0].Name).To(Equal("all"))
+			Expect(overrides[1].Name).To(Equal("east"))
+			Expect(spec.ServiceOverrides).To(HaveLen(3))
+		})
+	})
+
+	Describe("serviceOverrideApply", func() {
+		key := types.NamespacedName{Namespace: "app", Name: "lb"}
+
+		It("recreates a Service whose load balancer class is overridden, across an operator restart", func() {
+			Expect(vrgInstance().serviceOverrideApply(override)).To(MatchError(ContainSubstring("to be deleted")))
+			Expect(store).To(HaveLen(1))
+
+			deleted := &corev1.Service{}
+			Expect(reconciler.Get(context.TODO(), key, deleted)).NotTo(Succeed())
+
+			// A new instance has no memory of the deletion
+			Expect(vrgInstance().serviceOverrideApply(override)).To(Succeed())
+			Expect(store).To(BeEmpty())
+
+			recreated := &corev1.Service{}
+			Expect(reconciler.Get(context.TODO(), key, recreated)).To(Succeed())
+			Expect(recreated.Spec.LoadBalancerClass).To(Equal(override.LoadBalancerClass))
+			Expect(recreated.Spec.ClusterIP).To(BeEmpty())
+			Expect(recreated.Spec.Ports[0].NodePort).To(BeZero())
+		})
+
+		It("does not delete the Service when it cannot be kept to recreate", func() {
+			v := vrgInstance()
+			v.s3StoreAccessors = nil
+
+			Expect(v.serviceOverrideApply(override)).To(HaveOccurred())
+			Expect(reconciler.Get(context.TODO(), key, &corev1.Service{})).To(Succeed())
+		})
+
+		It("skips a Service neither recovered nor being recreated", func() {
+			override.Name = "missing"
+			Expect(vrgInstance().serviceOverrideApply(override)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+const (
+	// standalonePeerStateInterval is the maximum time between two uploads of an unchanged peer state
+	standalonePeerStateInterval = time.Minute
+
+	// standalonePeerStateStaleAfter is the age after which a peer state is considered to be from a cluster that
+	// is no longer reconciling the VRG, for example because it is down
+	standalonePeerStateStaleAfter = 5 * standalonePeerStateInterval
+)
+
+// standalonePeerState is the replication state of a VRG on one cluster, as published to the S3 stores in
+// standalone mode. In the absence of a hub, the peer states are used to order a promotion to Primary on one
+// cluster after the demotion to Secondary on the other.
+type standalonePeerState struct {
+	ClusterName      string                           `json:"clusterName"`
+	ReplicationState ramendrv1alpha1.ReplicationState `json:"replicationState"`
+	State            ramendrv1alpha1.State            `json:"state,omitempty"`
+	Action           ramendrv1alpha1.VRGAction        `json:"action,omitempty"`
+	UpdateTime       metav1.Time                      `json:"updateTime"`
+}
+
+func (s standalonePeerState) stale() bool {
+	return time.Since(s.UpdateTime.Time) > standalonePeerStateStaleAfter
+}
+
+func (v *VRGInstance) standaloneEnabled() bool {
+	return v.ramenConfig != nil && v.ramenConfig.Standalone.Enabled
+}
+
+// standalonePeerStatePublish uploads the VRG replication state of this cluster to the S3 stores, when it changes
+// and at least once every standalonePeerStateInterval as a heartbeat
+func (v *VRGInstance) standalonePeerStatePublish(result *ctrl.Result) {
+	if !v.standaloneEnabled() {
+		return
+	}
+
+	state := standalonePeerState{
+		ClusterName:      v.ramenConfig.Standalone.ClusterName,
+		ReplicationState: v.instance.Spec.ReplicationState,
+		State:            v.instance.Status.State,
+		Action:           v.instance.Spec.Action,
+	}
+
+	if value, ok := v.reconciler.standalonePeerStates.Load(v.namespacedName); ok {
+		last, _ := value.(standalonePeerState)
+		elapsed := time.Since(last.UpdateTime.Time)
+
+		if last.ReplicationState == state.ReplicationState && last.State == state.State &&
+			last.Action == state.Action && elapsed < standalonePeerStateInterval {
+			delaySetIfLess(result, standalonePeerStateInterval-elapsed, v.log)
+
+			return
+		}
+	}
+
+	state.UpdateTime = metav1.Now()
+	uploaded := false
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		if err := uploadTypedObject(s3StoreAccessor.ObjectStorer, v.s3KeyPrefix(), state.ClusterName,
+			state); err != nil {
+			v.log.Info("Peer state upload failed", "profile", s3StoreAccessor.S3ProfileName, "error", err)
+
+			continue
+		}
+
+		uploaded = true
+	}
+
+	if uploaded {
+		v.reconciler.standalonePeerStates.Store(v.namespacedName, state)
+	}
+
+	delaySetIfLess(result, standalonePeerStateInterval, v.log)
+}
+
+// standalonePeerStateForget deletes the VRG replication state of this cluster from the S3 stores
+func (v *VRGInstance) standalonePeerStateForget() {
+	v.reconciler.standalonePeerStates.Delete(v.namespacedName)
+
+	if !v.standaloneEnabled() {
+		return
+	}
+
+	state := standalonePeerState{}
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		if err := DeleteTypedObject(s3StoreAccessor.ObjectStorer, v.s3KeyPrefix(),
+			v.ramenConfig.Standalone.ClusterName, state); err != nil {
+			v.log.Info("Peer state delete failed", "profile", s3StoreAccessor.S3ProfileName, "error", err)
+		}
+	}
+}
+
+// standalonePeerStatesGet returns the VRG replication states of the peer clusters from the first S3 store that
+// is accessible
+func (v *VRGInstance) standalonePeerStatesGet() ([]standalonePeerState, error) {
+	var err error
+
+	for _, s3StoreAccessor := range v.s3StoreAccessors {
+		states := []standalonePeerState{}
+
+		if err = DownloadTypedObjects(s3StoreAccessor.ObjectStorer, v.s3KeyPrefix(), &states); err != nil {
+			v.log.Info("Peer states download failed", "profile", s3StoreAccessor.S3ProfileName, "error", err)
+
+			continue
+		}
+
+		peers := make([]standalonePeerState, 0, len(states))
+
+		for idx := range states {
+			if states[idx].ClusterName != v.ramenConfig.Standalone.ClusterName {
+				peers = append(peers, states[idx])
+			}
+		}
+
+		return peers, nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("no S3 store available")
+	}
+
+	return nil, err
+}
+
+// standalonePromotionWait returns true if, in standalone mode, the promotion of this VRG to Primary must wait for
+// a peer cluster:
+//   - Relocate waits for every peer to report it is Secondary
+//   - Failover proceeds regardless of the peers, with a warning if a peer still reports it is Primary
+//   - Initial deployment waits while a peer actively reports it is Primary
+func (v *VRGInstance) standalonePromotionWait() bool {
+	if !v.standaloneEnabled() || v.instance.Status.State == ramendrv1alpha1.PrimaryState {
+		return false
+	}
+
+	peers, err := v.standalonePeerStatesGet()
+	if err != nil {
+		if v.instance.Spec.Action == ramendrv1alpha1.VRGActionFailover {
+			return false
+		}
+
+		return v.standalonePromotionWaitFor(fmt.Sprintf("peer states unavailable: %v", err))
+	}
+
+	for idx := range peers {
+		peer := &peers[idx]
+
+		switch v.instance.Spec.Action {
+		case ramendrv1alpha1.VRGActionRelocate:
+			if peer.ReplicationState != ramendrv1alpha1.Secondary || peer.State != ramendrv1alpha1.SecondaryState {
+				return v.standalonePromotionWaitFor(fmt.Sprintf("peer cluster %s is not yet Secondary",
+					peer.ClusterName))
+			}
+		case ramendrv1alpha1.VRGActionFailover:
+			if peer.ReplicationState == ramendrv1alpha1.Primary && !peer.stale() {
+				rmnutil.ReportIfNotPresent(v.reconciler.eventRecorder, v.instance, corev1.EventTypeWarning,
+					rmnutil.EventReasonStandalonePeerPrimary,
+					fmt.Sprintf("Failing over while peer cluster %s reports it is Primary, as of %s",
+						peer.ClusterName, peer.UpdateTime.Format(time.RFC3339)))
+			}
+		default:
+			if peer.ReplicationState == ramendrv1alpha1.Primary && !peer.stale() {
+				return v.standalonePromotionWaitFor(fmt.Sprintf("peer cluster %s is Primary", peer.ClusterName))
+			}
+		}
+	}
+
+	return false
+}
+
+func (v *VRGInstance) standalonePromotionWaitFor(reason string) bool {
+	msg := "Waiting to promote to Primary, " + reason
+
+	v.log.Info(msg)
+	setVRGDataProgressingCondition(&v.instance.Status.Conditions, v.instance.Generation, msg)
+	delaySetIfLess(&v.result, standalonePeerStateInterval, v.log)
+
+	return true
+}
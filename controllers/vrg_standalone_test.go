@@ -0,0 +1,261 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the standalone peer state protocol
+package controllers //nolint: testpackage
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/controllers/util"
+)
+
+// memoryObjectStorer is an object store keeping the JSON encoding of its objects in memory
+type memoryObjectStorer map[string][]byte
+
+func (m memoryObjectStorer) UploadObject(key string, object interface{}) error {
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	m[key] = encoded
+
+	return nil
+}
+
+func (m memoryObjectStorer) UploadObjectRaw(key string, data []byte, contentType string) error {
+	m[key] = data
+
+	return nil
+}
+
+func (m memoryObjectStorer) DownloadObject(key string, objectPointer interface{}) error {
+	encoded, ok := m[key]
+	if !ok {
+		return fs.ErrNotExist
+	}
+
+	return json.Unmarshal(encoded, objectPointer)
+}
+
+func (m memoryObjectStorer) DownloadObjectRaw(key string) ([]byte, error) {
+	data, ok := m[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+
+	return data, nil
+}
+
+func (m memoryObjectStorer) ListKeys(keyPrefix string) ([]string, error) {
+	keys := []string{}
+
+	for key := range m {
+		if strings.HasPrefix(key, keyPrefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func (m memoryObjectStorer) ListObjects(keyPrefix string) ([]ObjectInfo, error) {
+	keys, err := m.ListKeys(keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ObjectInfo, len(keys))
+	for i, key := range keys {
+		objects[i] = ObjectInfo{Key: key, Size: int64(len(m[key]))}
+	}
+
+	return objects, nil
+}
+
+func (m memoryObjectStorer) DeleteObject(key string) error {
+	delete(m, key)
+
+	return nil
+}
+
+func (m memoryObjectStorer) DeleteObjects(keys ...string) error {
+	for _, key := range keys {
+		delete(m, key)
+	}
+
+	return nil
+}
+
+func (m memoryObjectStorer) DeleteObjectsWithKeyPrefix(keyPrefix string) error {
+	keys, err := m.ListKeys(keyPrefix)
+	if err != nil {
+		return err
+	}
+
+	return m.DeleteObjects(keys...)
+}
+
+var _ = Describe("VRG_Standalone", func() {
+	const clusterName = "east"
+
+	var (
+		store  memoryObjectStorer
+		vrg    *ramen.VolumeReplicationGroup
+		config *ramen.RamenConfig
+	)
+
+	vrgInstance := func(objectStorers ...ObjectStorer) *VRGInstance {
+		accessors := make([]s3StoreAccessor, len(objectStorers))
+		for i, objectStorer := range objectStorers {
+			accessors[i] = s3StoreAccessor{ObjectStorer: objectStorer}
+		}
+
+		return &VRGInstance{
+			reconciler: &VolumeReplicationGroupReconciler{
+				eventRecorder: util.NewEventReporter(record.NewFakeRecorder(10)),
+			},
+			ctx:              context.TODO(),
+			log:              ctrl.Log.WithName("vrg-standalone-test"),
+			instance:         vrg,
+			ramenConfig:      config,
+			namespacedName:   vrg.Namespace + "/" + vrg.Name,
+			s3StoreAccessors: accessors,
+		}
+	}
+
+	peerStatePut := func(peer standalonePeerState) {
+		v := vrgInstance(store)
+		Expect(uploadTypedObject(store, v.s3KeyPrefix(), peer.ClusterName, peer)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		store = memoryObjectStorer{}
+		vrg = &ramen.VolumeReplicationGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "vrg"},
+			Spec:       ramen.VolumeReplicationGroupSpec{ReplicationState: ramen.Primary},
+		}
+		config = &ramen.RamenConfig{}
+		config.Standalone.Enabled = true
+		config.Standalone.ClusterName = clusterName
+	})
+
+	Describe("standalonePeerStatePublish", func() {
+		It("uploads the state of this cluster and requeues for the heartbeat", func() {
+			v := vrgInstance(store)
+			result := ctrl.Result{}
+			v.standalonePeerStatePublish(&result)
+
+			Expect(result.RequeueAfter).To(Equal(standalonePeerStateInterval))
+
+			state := standalonePeerState{}
+			Expect(DownloadTypedObject(store, v.s3KeyPrefix(), clusterName, &state)).To(Succeed())
+			Expect(state.ClusterName).To(Equal(clusterName))
+			Expect(state.ReplicationState).To(Equal(ramen.Primary))
+		})
+		It("does not upload an unchanged state again before the heartbeat", func() {
+			v := vrgInstance(store)
+			v.standalonePeerStatePublish(&ctrl.Result{})
+			Expect(store).To(HaveLen(1))
+			Expect(store.DeleteObjectsWithKeyPrefix("")).To(Succeed())
+
+			v.standalonePeerStatePublish(&ctrl.Result{})
+			Expect(store).To(BeEmpty())
+
+			vrg.Spec.ReplicationState = ramen.Secondary
+			v.standalonePeerStatePublish(&ctrl.Result{})
+			Expect(store).To(HaveLen(1))
+		})
+		It("does nothing unless standalone mode is enabled", func() {
+			config.Standalone.Enabled = false
+			v := vrgInstance(store)
+			result := ctrl.Result{}
+			v.standalonePeerStatePublish(&result)
+
+			Expect(store).To(BeEmpty())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+
+	Describe("standalonePeerStatesGet", func() {
+		It("returns the states of the peers, not of this cluster", func() {
+			peerStatePut(standalonePeerState{ClusterName: clusterName, ReplicationState: ramen.Primary})
+			peerStatePut(standalonePeerState{ClusterName: "west", ReplicationState: ramen.Secondary})
+
+			peers, err := vrgInstance(store).standalonePeerStatesGet()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peers).To(HaveLen(1))
+			Expect(peers[0].ClusterName).To(Equal("west"))
+		})
+		It("fails without an S3 store", func() {
+			_, err := vrgInstance().standalonePeerStatesGet()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("standalonePromotionWait", func() {
+		It("waits for a relocation until the peers are secondary", func() {
+			vrg.Spec.Action = ramen.VRGActionRelocate
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Secondary, State: ramen.PrimaryState,
+				UpdateTime: metav1.Now(),
+			})
+
+			v := vrgInstance(store)
+			Expect(v.standalonePromotionWait()).To(BeTrue())
+			Expect(v.result.RequeueAfter).To(Equal(standalonePeerStateInterval))
+
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Secondary, State: ramen.SecondaryState,
+				UpdateTime: metav1.Now(),
+			})
+			Expect(vrgInstance(store).standalonePromotionWait()).To(BeFalse())
+		})
+		It("fails over while a peer is primary", func() {
+			vrg.Spec.Action = ramen.VRGActionFailover
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Primary, UpdateTime: metav1.Now(),
+			})
+
+			Expect(vrgInstance(store).standalonePromotionWait()).To(BeFalse())
+			Expect(vrgInstance().standalonePromotionWait()).To(BeFalse())
+		})
+		It("waits for an initial deployment while a peer is primary, unless its state is stale", func() {
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Primary, UpdateTime: metav1.Now(),
+			})
+			Expect(vrgInstance(store).standalonePromotionWait()).To(BeTrue())
+
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Primary,
+				UpdateTime: metav1.NewTime(time.Now().Add(-2 * standalonePeerStateStaleAfter)),
+			})
+			Expect(vrgInstance(store).standalonePromotionWait()).To(BeFalse())
+		})
+		It("waits for an initial deployment while the peer states are unavailable", func() {
+			Expect(vrgInstance().standalonePromotionWait()).To(BeTrue())
+		})
+		It("does not wait once primary", func() {
+			vrg.Status.State = ramen.PrimaryState
+			peerStatePut(standalonePeerState{
+				ClusterName: "west", ReplicationState: ramen.Primary, UpdateTime: metav1.Now(),
+			})
+			Expect(vrgInstance(store).standalonePromotionWait()).To(BeFalse())
+		})
+	})
+})
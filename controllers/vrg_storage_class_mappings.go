@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// storageClassMapping returns the mapping of a storage class on this cluster, if it is mapped
+func (v *VRGInstance) storageClassMapping(storageClassName string) *ramen.StorageClassMapping {
+	for i := range v.instance.Spec.StorageClassMappings {
+		if mapping := &v.instance.Spec.StorageClassMappings[i]; mapping.Source == storageClassName &&
+			mapping.Target != "" {
+			return mapping
+		}
+	}
+
+	return nil
+}
+
+// storageClassMapped returns the storage class that replaces a storage class on this cluster, or the storage class
+// if it is not mapped
+func (v *VRGInstance) storageClassMapped(storageClassName *string) *string {
+	if storageClassName == nil {
+		return nil
+	}
+
+	if mapping := v.storageClassMapping(*storageClassName); mapping != nil {
+		target := mapping.Target
+
+		return &target
+	}
+
+	return storageClassName
+}
+
+// pvcStorageClassMap rewrites the storage class of a PVC restored to this cluster
+func (v *VRGInstance) pvcStorageClassMap(pvc *corev1.PersistentVolumeClaim) {
+	storageClassName := v.storageClassMapped(pvc.Spec.StorageClassName)
+	if storageClassName == pvc.Spec.StorageClassName {
+		return
+	}
+
+	v.log.Info("PVC storage class mapped", "PVC", pvc.Namespace+"/"+pvc.Name, "source", *pvc.Spec.StorageClassName,
+		"target", *storageClassName)
+
+	pvc.Spec.StorageClassName = storageClassName
+}
+
+// pvStorageClassMap rewrites the storage class of a PV restored to this cluster, and its CSI driver, volume
+// attributes and secrets to those of the storage class that replaces it, for the driver of this cluster to use it
+func (v *VRGInstance) pvStorageClassMap(pv *corev1.PersistentVolume) error {
+	mapping := v.storageClassMapping(pv.Spec.StorageClassName)
+	if mapping == nil {
+		return nil
+	}
+
+	v.log.Info("PV storage class mapped", "PV", pv.Name, "source", pv.Spec.StorageClassName,
+		"target", mapping.Target)
+
+	pv.Spec.StorageClassName = mapping.Target
+
+	if pv.Spec.CSI == nil {
+		return nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	if err := v.reconciler.Get(v.ctx, types.NamespacedName{Name: mapping.Target}, storageClass); err != nil {
+		return fmt.Errorf("failed to get storage class %s of PV %s: %w", mapping.Target, pv.Name, err)
+	}
+
+	pvCSIMap(pv, storageClass, mapping.VolumeAttributes)
+
+	return nil
+}
+
+// pvCSIMap rewrites the CSI driver of a PV to the provisioner of a storage class, and its volume attributes and
+// secrets to the parameters of the storage class, and the volume attributes of its mapping. The volume attributes
+// of the PV are kept only if its driver is.
+func pvCSIMap(pv *corev1.PersistentVolume, storageClass *storagev1.StorageClass, volumeAttributes map[string]string) {
+	csi := pv.Spec.CSI
+
+	attributes := map[string]string{}
+	if csi.Driver == storageClass.Provisioner {
+		for key, value := range csi.VolumeAttributes {
+			attributes[key] = value
+		}
+	}
+
+	for key, value := range storageClass.Parameters {
+		if !strings.HasPrefix(key, csiParameterPrefix) {
+			attributes[key] = value
+		}
+	}
+
+	for key, value := range volumeAttributes {
+		attributes[key] = value
+	}
+
+	csi.Driver = storageClass.Provisioner
+	csi.VolumeAttributes = attributes
+
+	for _, secret := range []struct {
+		ref       **corev1.SecretReference
+		operation string
+	}{
+		{&csi.ControllerPublishSecretRef, "controller-publish"},
+		{&csi.NodeStageSecretRef, "node-stage"},
+		{&csi.NodePublishSecretRef, "node-publish"},
+		{&csi.ControllerExpandSecretRef, "controller-expand"},
+		{&csi.NodeExpandSecretRef, "node-expand"},
+	} {
+		*secret.ref = pvCSISecretRef(pv, storageClass.Parameters, secret.operation)
+	}
+}
+
+// csiParameterPrefix prefixes the storage class parameters reserved for the CSI external provisioner, such as its
+// secrets, which are not passed to the driver
+const csiParameterPrefix = "csi.storage.k8s.io/"
+
+// pvCSISecretRef returns the secret of a CSI operation named by the parameters of a storage class, with the
+// templates of the PV and its claim the external provisioner resolves, or nil if it is not named
+func pvCSISecretRef(pv *corev1.PersistentVolume, parameters map[string]string, operation string,
+) *corev1.SecretReference {
+	name, ok := parameters[csiParameterPrefix+operation+"-secret-name"]
+	if !ok {
+		return nil
+	}
+
+	replacements := []string{"${pv.name}", pv.Name}
+	if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+		replacements = append(replacements, "${pvc.namespace}", claimRef.Namespace, "${pvc.name}", claimRef.Name)
+	}
+
+	replacer := strings.NewReplacer(replacements...)
+
+	return &corev1.SecretReference{
+		Namespace: replacer.Replace(parameters[csiParameterPrefix+operation+"-secret-namespace"]),
+		Name:      replacer.Replace(name),
+	}
+}
+
+// rdSpecStorageClassMapped returns the replication destination spec with the storage class of its PVC mapped for
+// this cluster
+func (v *VRGInstance) rdSpecStorageClassMapped(rdSpec ramen.VolSyncReplicationDestinationSpec,
+) ramen.VolSyncReplicationDestinationSpec {
+	rdSpec.ProtectedPVC.StorageClassName = v.storageClassMapped(rdSpec.ProtectedPVC.StorageClassName)
+
+	return rdSpec
+}
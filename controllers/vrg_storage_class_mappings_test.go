@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the mappings of the storage classes of the PVs and PVCs restored to another driver
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_StorageClassMappings", func() {
+	var (
+		v  *VRGInstance
+		pv *corev1.PersistentVolume
+	)
+
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "cluster2-rbd"},
+		Provisioner: "rbd.csi.example.com",
+		Parameters: map[string]string{
+			"pool":                      "replicapool",
+			"csi.storage.k8s.io/fstype": "ext4",
+			"csi.storage.k8s.io/node-stage-secret-name":             "rbd-node-${pvc.namespace}",
+			"csi.storage.k8s.io/node-stage-secret-namespace":        "rook",
+			"csi.storage.k8s.io/controller-expand-secret-name":      "rbd-expand",
+			"csi.storage.k8s.io/controller-expand-secret-namespace": "rook",
+		},
+	}
+
+	BeforeEach(func() {
+		c := fake.NewClientBuilder().WithObjects(storageClass.DeepCopy()).Build()
+		v = vrgInstanceFake(c, "vrg-storage-class-mappings-test", ramen.VolumeReplicationGroupSpec{
+			StorageClassMappings: []ramen.StorageClassMapping{{
+				Source:           "cluster1-rbd",
+				Target:           "cluster2-rbd",
+				VolumeAttributes: map[string]string{"clusterID": "cluster2-ceph"},
+			}},
+		})
+		pv = &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "pv"},
+			Spec: corev1.PersistentVolumeSpec{
+				StorageClassName: "cluster1-rbd",
+				ClaimRef:         &corev1.ObjectReference{Namespace: "app", Name: "db"},
+				PersistentVolumeSource: corev1.PersistentVolumeSource{CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       "rbd.csi.ceph.com",
+					VolumeHandle: "volume",
+					VolumeAttributes: map[string]string{
+						"clusterID": "cluster1-ceph", "imageName": "csi-vol-1",
+					},
+					NodeStageSecretRef:   &corev1.SecretReference{Namespace: "ceph", Name: "node"},
+					NodePublishSecretRef: &corev1.SecretReference{Namespace: "ceph", Name: "publish"},
+				}},
+			},
+		}
+	})
+
+	Describe("drClusterSpec", func() {
+		It("returns the mappings of the DRCluster of a cluster, for the VRGs sent to it", func() {
+			d := &DRPCInstance{drClusters: []ramen.DRCluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "east"}},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "west"},
+					Spec:       ramen.DRClusterSpec{StorageClassMappings: v.instance.Spec.StorageClassMappings},
+				},
+			}}
+			Expect(d.drClusterSpec("west").StorageClassMappings).To(Equal(v.instance.Spec.StorageClassMappings))
+			Expect(d.drClusterSpec("east").StorageClassMappings).To(BeEmpty())
+			Expect(d.drClusterSpec("north")).To(Equal(ramen.DRClusterSpec{}))
+		})
+	})
+
+	Describe("storageClassMapped", func() {
+		It("maps a storage class mapped, and keeps the others", func() {
+			source, other := "cluster1-rbd", "cephfs"
+			Expect(v.storageClassMapped(&source)).To(HaveValue(Equal("cluster2-rbd")))
+			Expect(v.storageClassMapped(&other)).To(BeIdenticalTo(&other))
+			Expect(v.storageClassMapped(nil)).To(BeNil())
+		})
+	})
+
+	Describe("pvStorageClassMap", func() {
+		It("maps the storage class of a PV, and its CSI driver, volume attributes and secrets", func() {
+			Expect(v.pvStorageClassMap(pv)).To(Succeed())
+			Expect(pv.Spec.StorageClassName).To(Equal("cluster2-rbd"))
+			Expect(pv.Spec.CSI.Driver).To(Equal("rbd.csi.example.com"))
+			Expect(pv.Spec.CSI.VolumeHandle).To(Equal("volume"))
+			Expect(pv.Spec.CSI.VolumeAttributes).To(Equal(map[string]string{
+				"pool": "replicapool", "clusterID": "cluster2-ceph",
+			}))
+			Expect(pv.Spec.CSI.NodeStageSecretRef).To(HaveValue(Equal(
+				corev1.SecretReference{Namespace: "rook", Name: "rbd-node-app"})))
+			Expect(pv.Spec.CSI.ControllerExpandSecretRef).To(HaveValue(Equal(
+				corev1.SecretReference{Namespace: "rook", Name: "rbd-expand"})))
+			Expect(pv.Spec.CSI.NodePublishSecretRef).To(BeNil())
+		})
+
+		It("keeps the volume attributes of a PV whose driver is kept", func() {
+			pv.Spec.CSI.Driver = storageClass.Provisioner
+			Expect(v.pvStorageClassMap(pv)).To(Succeed())
+			Expect(pv.Spec.CSI.VolumeAttributes).To(Equal(map[string]string{
+				"pool": "replicapool", "clusterID": "cluster2-ceph", "imageName": "csi-vol-1",
+			}))
+		})
+
+		It("keeps a PV whose storage class is not mapped", func() {
+			pv.Spec.StorageClassName = "cephfs"
+			expected := pv.DeepCopy()
+			Expect(v.pvStorageClassMap(pv)).To(Succeed())
+			Expect(pv).To(Equal(expected))
+		})
+
+		It("fails to map the CSI driver of a PV to a storage class not found", func() {
+			v.instance.Spec.StorageClassMappings[0].Target = "missing"
+			Expect(v.pvStorageClassMap(pv)).To(MatchError(ContainSubstring("missing")))
+		})
+	})
+})
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	volrep "github.com/csi-addons/kubernetes-csi-addons/apis/replication.storage/v1alpha1"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// SyncReplicationProviderLabel, on a storage class, names the provider that replicates its volumes for the VRGs
+	// in sync mode, for a sync DRPolicy to protect the volumes of storage of different providers. Storage classes
+	// without it are of storage stretched across the clusters.
+	SyncReplicationProviderLabel = "ramendr.openshift.io/sync-replication-provider"
+
+	// SyncReplicationProviderStretched is the provider of storage stretched across the clusters, which share the
+	// volumes rather than replicate them
+	SyncReplicationProviderStretched = "stretched"
+
+	// SyncReplicationProviderVolumeReplication replicates the volumes with VolumeReplication resources of the
+	// VolumeReplicationClass of the storage class provisioner, such as those of arrays that replicate synchronously
+	SyncReplicationProviderVolumeReplication = "volumereplication"
+)
+
+// syncReplicationProvider replicates, for a VRG in sync mode, the volumes of the storage classes that select it
+type syncReplicationProvider interface {
+	// processAsPrimary and processAsSecondary bring the replication of the volume of a PVC to the VRG replication
+	// state, and return, as processVRAsPrimary does, whether to requeue, whether the state is reached, and any error
+	processAsPrimary(v *VRGInstance, pvcNamespacedName types.NamespacedName, log logr.Logger) (bool, bool, error)
+	processAsSecondary(v *VRGInstance, pvcNamespacedName types.NamespacedName, log logr.Logger) (bool, bool, error)
+
+	// volumesShared returns whether the clusters share the volumes rather than replicate them
+	volumesShared() bool
+}
+
+var syncReplicationProviders = map[string]syncReplicationProvider{}
+
+// syncReplicationProviderRegister registers a provider, for storage classes to select it by name
+func syncReplicationProviderRegister(name string, provider syncReplicationProvider) {
+	syncReplicationProviders[name] = provider
+}
+
+func init() {
+	syncReplicationProviderRegister(SyncReplicationProviderStretched, stretchedSyncReplicationProvider{})
+	syncReplicationProviderRegister(SyncReplicationProviderVolumeReplication, volRepSyncReplicationProvider{})
+}
+
+// syncReplicationProvider returns the provider selected by the storage class of the PVC
+func (v *VRGInstance) syncReplicationProvider(pvcNamespacedName types.NamespacedName,
+) (syncReplicationProvider, error) {
+	storageClass, err := v.getStorageClass(pvcNamespacedName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the storageclass of pvc %s (%w)", pvcNamespacedName, err)
+	}
+
+	name := storageClass.GetLabels()[SyncReplicationProviderLabel]
+	if name == "" {
+		name = SyncReplicationProviderStretched
+	}
+
+	provider, ok := syncReplicationProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("storageclass %s of pvc %s selects unknown sync replication provider %s",
+			storageClass.GetName(), pvcNamespacedName, name)
+	}
+
+	return provider, nil
+}
+
+// pvcVolumeReplicated returns whether the volume of the PVC is replicated with a VolumeReplication resource: always
+// for a VRG in async mode, and for a VRG in sync mode unless the provider of its storage class shares it
+func (v *VRGInstance) pvcVolumeReplicated(pvcNamespacedName types.NamespacedName) (bool, error) {
+	if v.instance.Spec.Async != nil {
+		return true, nil
+	}
+
+	provider, err := v.syncReplicationProvider(pvcNamespacedName)
+	if err != nil {
+		return false, err
+	}
+
+	return !provider.volumesShared(), nil
+}
+
+type stretchedSyncReplicationProvider struct{}
+
+// processAsPrimary only updates the PVC conditions, as the volume is available on either cluster
+func (stretchedSyncReplicationProvider) processAsPrimary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	_ logr.Logger,
+) (bool, bool, error) {
+	msg := "PVC in the VolumeReplicationGroup is ready for use"
+	v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name, VRGConditionReasonReady, msg)
+	v.updatePVCDataProtectedCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name, VRGConditionReasonReady,
+		msg)
+	v.updatePVCLastSyncTime(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+	v.updatePVCLastSyncDuration(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+	v.updatePVCLastSyncBytes(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+
+	return false, true, nil
+}
+
+// processAsSecondary only updates the PVC conditions, as the volume is in sync with the primary
+func (stretchedSyncReplicationProvider) processAsSecondary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	_ logr.Logger,
+) (bool, bool, error) {
+	msg := "VolumeReplication resource for the pvc as Secondary is in sync with Primary"
+	v.updatePVCDataReadyCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name, VRGConditionReasonReplicated,
+		msg)
+	v.updatePVCDataProtectedCondition(pvcNamespacedName.Namespace, pvcNamespacedName.Name,
+		VRGConditionReasonDataProtected, msg)
+	v.updatePVCLastSyncTime(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+	v.updatePVCLastSyncDuration(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+	v.updatePVCLastSyncBytes(pvcNamespacedName.Namespace, pvcNamespacedName.Name, nil)
+
+	return false, true, nil
+}
+
+func (stretchedSyncReplicationProvider) volumesShared() bool { return true }
+
+type volRepSyncReplicationProvider struct{}
+
+func (volRepSyncReplicationProvider) processAsPrimary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	log logr.Logger,
+) (bool, bool, error) {
+	return v.createOrUpdateVR(pvcNamespacedName, volrep.Primary, log)
+}
+
+func (volRepSyncReplicationProvider) processAsSecondary(v *VRGInstance, pvcNamespacedName types.NamespacedName,
+	log logr.Logger,
+) (bool, bool, error) {
+	return v.createOrUpdateVR(pvcNamespacedName, volrep.Secondary, log)
+}
+
+func (volRepSyncReplicationProvider) volumesShared() bool { return false }
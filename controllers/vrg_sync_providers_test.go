@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the selection of sync replication providers by storage class
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_SyncReplicationProviders", func() {
+	var vrgInstance *VRGInstance
+
+	pvc := func(name, storageClassName string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+		}
+	}
+	storageClass := func(name, provider string) *storagev1.StorageClass {
+		storageClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if provider != "" {
+			storageClass.Labels = map[string]string{SyncReplicationProviderLabel: provider}
+		}
+
+		return storageClass
+	}
+	name := func(name string) types.NamespacedName {
+		return types.NamespacedName{Namespace: "app", Name: name}
+	}
+
+	BeforeEach(func() {
+		vrgInstance = &VRGInstance{
+			ctx: context.TODO(),
+			log: ctrl.Log.WithName("vrg-sync-providers-test"),
+			instance: &ramen.VolumeReplicationGroup{
+				Spec: ramen.VolumeReplicationGroupSpec{Sync: &ramen.VRGSyncSpec{}},
+			},
+			volRepPVCs: []corev1.PersistentVolumeClaim{
+				pvc("stretched", "ceph-rbd"),
+				pvc("array", "array-sync"),
+				pvc("unknown", "other"),
+			},
+			storageClassCache: map[string]*storagev1.StorageClass{
+				"ceph-rbd":   storageClass("ceph-rbd", ""),
+				"array-sync": storageClass("array-sync", SyncReplicationProviderVolumeReplication),
+				"other":      storageClass("other", "other"),
+			},
+		}
+	})
+
+	It("selects the provider of the storage class, or the stretched storage provider by default", func() {
+		provider, err := vrgInstance.syncReplicationProvider(name("stretched"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider).To(Equal(stretchedSyncReplicationProvider{}))
+
+		provider, err = vrgInstance.syncReplicationProvider(name("array"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provider).To(Equal(volRepSyncReplicationProvider{}))
+
+		_, err = vrgInstance.syncReplicationProvider(name("unknown"))
+		Expect(err).To(MatchError(ContainSubstring("unknown sync replication provider other")))
+	})
+
+	It("replicates the volumes of the providers that do not share them, and every volume in async mode", func() {
+		Expect(vrgInstance.pvcVolumeReplicated(name("stretched"))).To(BeFalse())
+		Expect(vrgInstance.pvcVolumeReplicated(name("array"))).To(BeTrue())
+
+		vrgInstance.instance.Spec.Sync = nil
+		vrgInstance.instance.Spec.Async = &ramen.VRGAsyncSpec{}
+		Expect(vrgInstance.pvcVolumeReplicated(name("stretched"))).To(BeTrue())
+	})
+
+	It("fails for a PVC that is not protected by volume replication", func() {
+		_, err := vrgInstance.pvcVolumeReplicated(name("absent"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// uploadRedactionsManifestName is the name of the manifest of the redactions of a VRG's uploads, stored along with
+// the VRG
+const uploadRedactionsManifestName = "redactions"
+
+// uploadRedactionsManifest records the redactions of the objects uploaded for a VRG, for the fields absent from its
+// PVs and PVCs, and the kinds absent from its kube object captures, to be told apart from lost ones
+type uploadRedactionsManifest struct {
+	Redactions        []ramen.UploadRedaction `json:"redactions"`
+	ExcludedResources []string                `json:"excludedResources,omitempty"`
+}
+
+// uploadRedactionKindsRedactable are the kinds whose fields are redacted, as the VRG uploads them itself. The other
+// kinds are captured by Velero, which modifies the objects it restores only, not those it captures, and can only be
+// excluded.
+var uploadRedactionKindsRedactable = map[string]bool{
+	"PersistentVolume":      true,
+	"PersistentVolumeClaim": true,
+}
+
+func uploadRedactionsValidate(redactions []ramen.UploadRedaction) []error {
+	errs := []error{}
+
+	for _, redaction := range redactions {
+		kind := schema.GroupKind{Group: redaction.Group, Kind: redaction.Kind}.String()
+
+		if redaction.Kind == "" {
+			errs = append(errs, fmt.Errorf("uploadRedaction of group %q requires a kind", redaction.Group))
+
+			continue
+		}
+
+		redactable := redaction.Group == "" && uploadRedactionKindsRedactable[redaction.Kind]
+
+		if len(redaction.Fields) == 0 && redactable {
+			errs = append(errs, fmt.Errorf("uploadRedaction of %s requires fields, as its objects are protected "+
+				"by the VRGs and cannot be excluded", kind))
+		}
+
+		if len(redaction.Fields) != 0 && !redactable {
+			errs = append(errs, fmt.Errorf("uploadRedaction of %s may not list fields, as only the fields of "+
+				"PersistentVolumes and PersistentVolumeClaims are redacted; list none to exclude its objects", kind))
+		}
+
+		for _, field := range redaction.Fields {
+			if _, err := jsonPathFieldParse(field); err != nil {
+				errs = append(errs, fmt.Errorf("uploadRedaction of %s field %q invalid: %w", kind, field, err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// jsonPathFieldParse parses a JSONPath of a field into its names, such as .metadata.annotations['example.com/x']
+// into metadata, annotations and example.com/x
+func jsonPathFieldParse(path string) ([]string, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	names := []string{}
+
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "['") || strings.HasPrefix(path, `["`):
+			end := strings.Index(path[2:], path[1:2]+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket at %q", path)
+			}
+
+			names = append(names, path[2:2+end])
+			path = path[2+end+2:]
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+
+			if end < 0 {
+				end = len(path)
+			}
+
+			if end == 0 {
+				return nil, fmt.Errorf("empty name at %q", path)
+			}
+
+			names = append(names, path[:end])
+			path = path[end:]
+		default:
+			return nil, fmt.Errorf("expected . or [' at %q", path)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no field")
+	}
+
+	return names, nil
+}
+
+// uploadRedacted returns a copy of the object of a core kind with the fields of its redactions removed
+func uploadRedacted[T any](redactions []ramen.UploadRedaction, kind string, object T) (T, error) {
+	fields := [][]string{}
+
+	for _, redaction := range redactions {
+		if redaction.Group != "" || redaction.Kind != kind {
+			continue
+		}
+
+		for _, field := range redaction.Fields {
+			names, err := jsonPathFieldParse(field)
+			if err != nil {
+				return object, err
+			}
+
+			fields = append(fields, names)
+		}
+	}
+
+	if len(fields) == 0 {
+		return object, nil
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&object)
+	if err != nil {
+		return object, fmt.Errorf("failed to convert %s to redact it (%w)", kind, err)
+	}
+
+	for _, names := range fields {
+		unstructured.RemoveNestedField(content, names...)
+	}
+
+	var redacted T
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &redacted); err != nil {
+		return object, fmt.Errorf("failed to convert redacted %s (%w)", kind, err)
+	}
+
+	return redacted, nil
+}
+
+// uploadRedactionsExcludedResources returns the resources of the kinds the redactions exclude from the kube object
+// captures, as <resource>.<group>. Kinds that this cluster does not serve are skipped.
+func (v *VRGInstance) uploadRedactionsExcludedResources() []string {
+	resources := []string{}
+
+	for _, redaction := range v.ramenConfig.UploadRedactions {
+		if len(redaction.Fields) != 0 {
+			continue
+		}
+
+		groupKind := schema.GroupKind{Group: redaction.Group, Kind: redaction.Kind}
+
+		mapping, err := v.reconciler.RESTMapper().RESTMapping(groupKind)
+		if err != nil {
+			v.log.Info("Upload redaction kind not served, not excluded", "kind", groupKind.String(), "error", err)
+
+			continue
+		}
+
+		resources = append(resources, mapping.Resource.GroupResource().String())
+	}
+
+	return resources
+}
+
+// uploadRedactionsManifestUpload uploads the manifest of the redactions along with the VRG, if any are configured,
+// and deletes it otherwise, for a manifest of redactions since removed not to describe the following uploads
+func (v *VRGInstance) uploadRedactionsManifestUpload(objectStorer ObjectStorer) error {
+	key := s3PathNamePrefix(v.instance.Namespace, v.instance.Name) + uploadRedactionsManifestName
+
+	if len(v.ramenConfig.UploadRedactions) == 0 {
+		return objectStorer.DeleteObject(key)
+	}
+
+	manifest := uploadRedactionsManifest{
+		Redactions:        v.ramenConfig.UploadRedactions,
+		ExcludedResources: v.uploadRedactionsExcludedResources(),
+	}
+
+	return objectStorer.UploadObject(key, manifest)
+}
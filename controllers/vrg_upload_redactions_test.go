@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the redaction of the objects uploaded to the S3 stores
+package controllers //nolint: testpackage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_UploadRedactions", func() {
+	DescribeTable("jsonPathFieldParse",
+		func(path string, expected []string) {
+			Expect(jsonPathFieldParse(path)).To(Equal(expected))
+		},
+		Entry("a dotted path", ".spec.claimRef", []string{"spec", "claimRef"}),
+		Entry("a path from the root", "$.metadata.labels", []string{"metadata", "labels"}),
+		Entry("a name with dots and slashes in brackets", ".metadata.annotations['example.com/access-token']",
+			[]string{"metadata", "annotations", "example.com/access-token"}),
+		Entry("a name in double quoted brackets", `.metadata.annotations["example.com/x"].y`,
+			[]string{"metadata", "annotations", "example.com/x", "y"}),
+	)
+
+	DescribeTable("jsonPathFieldParse of invalid paths",
+		func(path, expected string) {
+			_, err := jsonPathFieldParse(path)
+			Expect(err).To(MatchError(ContainSubstring(expected)))
+		},
+		Entry("no field", "$", "no field"),
+		Entry("an empty name", ".metadata..labels", "empty name"),
+		Entry("an unterminated bracket", ".metadata['labels", "unterminated bracket"),
+		Entry("a name without a dot", "metadata", "expected . or ['"),
+	)
+
+	Describe("uploadRedacted", func() {
+		redactions := []ramen.UploadRedaction{
+			{Kind: "PersistentVolumeClaim", Fields: []string{
+				".metadata.annotations['example.com/access-token']", ".spec.dataSource",
+			}},
+			{Group: "example.com", Kind: "PersistentVolumeClaim", Fields: []string{".metadata.labels"}},
+			{Kind: "Secret"},
+		}
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "data",
+				Labels: map[string]string{"app": "web"},
+				Annotations: map[string]string{
+					"example.com/access-token": "secret",
+					"example.com/owner":        "team",
+				},
+			},
+		}
+
+		It("removes the fields of the redactions of the object's kind, skipping those it does not have", func() {
+			redacted, err := uploadRedacted(redactions, "PersistentVolumeClaim", pvc)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(redacted.Annotations).To(Equal(map[string]string{"example.com/owner": "team"}))
+			Expect(redacted.Labels).To(Equal(pvc.Labels))
+			Expect(pvc.Annotations).To(HaveKey("example.com/access-token"))
+		})
+
+		It("returns the object as is without redactions of its kind", func() {
+			Expect(uploadRedacted(redactions, "PersistentVolume", pvc)).To(Equal(pvc))
+		})
+
+		It("returns an error for an invalid field", func() {
+			_, err := uploadRedacted([]ramen.UploadRedaction{{Kind: "PersistentVolumeClaim", Fields: []string{"spec"}}},
+				"PersistentVolumeClaim", pvc)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("uploadRedactionsManifestUpload", func() {
+		It("uploads the manifest of the redactions, and deletes it once they are removed", func() {
+			store := memoryObjectStorer{}
+			vrgInstance := &VRGInstance{
+				instance: &ramen.VolumeReplicationGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"}},
+				ramenConfig: &ramen.RamenConfig{UploadRedactions: []ramen.UploadRedaction{
+					{Kind: "PersistentVolume", Fields: []string{".spec.claimRef.uid"}},
+				}},
+			}
+			key := s3PathNamePrefix("app", "vrg") + uploadRedactionsManifestName
+
+			Expect(vrgInstance.uploadRedactionsManifestUpload(store)).To(Succeed())
+
+			manifest := uploadRedactionsManifest{}
+			Expect(store.DownloadObject(key, &manifest)).To(Succeed())
+			Expect(manifest.Redactions).To(Equal(vrgInstance.ramenConfig.UploadRedactions))
+			Expect(manifest.ExcludedResources).To(BeEmpty())
+
+			vrgInstance.ramenConfig.UploadRedactions = nil
+			Expect(vrgInstance.uploadRedactionsManifestUpload(store)).To(Succeed())
+			Expect(store).ToNot(HaveKey(key))
+		})
+	})
+})
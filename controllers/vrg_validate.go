@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+
+	volrep "github.com/csi-addons/kubernetes-csi-addons/apis/replication.storage/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	rmnutil "github.com/ramendr/ramen/controllers/util"
+)
+
+// The ways a PVC selected by a VRG is protected
+const (
+	VRGValidationProtectedByVolRep  = "VolumeReplication"
+	VRGValidationProtectedByVolSync = "VolSync"
+)
+
+// VRGValidation reports the PVCs a VRG would select on a cluster, and how it would protect each, as validated
+// before the VRG is applied
+type VRGValidation struct {
+	// PVCs are the PVCs the VRG would select, with how each would be protected, or why it could not be
+	PVCs []VRGValidationPVC `json:"pvcs,omitempty"`
+
+	// SkippedPVCs are the PVCs its selector matches that the VRG would skip, as excluded from protection
+	SkippedPVCs []ramen.SkippedPVC `json:"skippedPVCs,omitempty"`
+
+	// Error is the error that failed the selection of the PVCs, for example a recipe that is not found
+	Error string `json:"error,omitempty"`
+}
+
+// VRGValidationPVC is a PVC a VRG would select, and how it would be protected
+type VRGValidationPVC struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// ProtectedBy is VolumeReplication or VolSync, or empty if the PVC is unprotectable
+	ProtectedBy string `json:"protectedBy,omitempty"`
+
+	// ReplicationClass is the VolumeReplicationClass the PVC would be replicated with
+	ReplicationClass string `json:"replicationClass,omitempty"`
+
+	// Unprotectable is why the PVC could not be protected
+	Unprotectable string `json:"unprotectable,omitempty"`
+}
+
+// Unprotectable returns whether the selection of the PVCs failed or a PVC could not be protected
+func (validation *VRGValidation) Unprotectable() bool {
+	if validation.Error != "" {
+		return true
+	}
+
+	for _, pvc := range validation.PVCs {
+		if pvc.Unprotectable != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VRGValidate selects the PVCs of a VRG on a cluster, as the VRG would once applied, and reports the
+// VolumeReplicationClass each PVC to replicate would match, the PVCs that would be protected with VolSync, and the
+// PVCs that could not be protected, without creating or updating anything
+func VRGValidate(ctx context.Context, c client.Client, vrg *ramen.VolumeReplicationGroup,
+	ramenConfig *ramen.RamenConfig, log logr.Logger,
+) *VRGValidation {
+	v := &VRGInstance{
+		reconciler: &VolumeReplicationGroupReconciler{
+			Client:    c,
+			APIReader: c,
+			Log:       log,
+			// Events are recorded to a broadcaster without sinks, and so are dropped
+			eventRecorder: rmnutil.NewEventReporter(record.NewBroadcaster().NewRecorder(c.Scheme(),
+				corev1.EventSource{Component: "vrg-validate"})),
+		},
+		ctx:               ctx,
+		log:               log,
+		instance:          vrg.DeepCopy(),
+		ramenConfig:       ramenConfig,
+		replClassList:     &volrep.VolumeReplicationClassList{},
+		storageClassCache: make(map[string]*storagev1.StorageClass),
+		namespacedName:    vrg.Namespace + "/" + vrg.Name,
+	}
+
+	validation := &VRGValidation{}
+
+	if err := RecipeElementsGet(ctx, c, *vrg, *ramenConfig, log, &v.recipeElements); err != nil {
+		validation.Error = err.Error()
+
+		return validation
+	}
+
+	if err := v.updatePVCList(); err != nil {
+		validation.Error = err.Error()
+
+		return validation
+	}
+
+	validation.SkippedPVCs = v.instance.Status.SkippedPVCs
+
+	for idx := range v.volRepPVCs {
+		pvc := &v.volRepPVCs[idx]
+		pvcValidation := vrgValidationPVC(pvc)
+
+		replicationClass, err := v.selectVolumeReplicationClass(types.NamespacedName{
+			Namespace: pvc.Namespace, Name: pvc.Name,
+		})
+		if err != nil {
+			pvcValidation.Unprotectable = err.Error()
+		} else {
+			pvcValidation.ProtectedBy = VRGValidationProtectedByVolRep
+			pvcValidation.ReplicationClass = replicationClass.Name
+		}
+
+		validation.PVCs = append(validation.PVCs, pvcValidation)
+	}
+
+	for idx := range v.volSyncPVCs {
+		pvc := &v.volSyncPVCs[idx]
+		pvcValidation := vrgValidationPVC(pvc)
+
+		if v.storageClassForPVC(pvc) == nil {
+			pvcValidation.Unprotectable = "storage class not found"
+		} else {
+			pvcValidation.ProtectedBy = VRGValidationProtectedByVolSync
+		}
+
+		validation.PVCs = append(validation.PVCs, pvcValidation)
+	}
+
+	return validation
+}
+
+func vrgValidationPVC(pvc *corev1.PersistentVolumeClaim) VRGValidationPVC {
+	pvcValidation := VRGValidationPVC{Namespace: pvc.Namespace, Name: pvc.Name}
+
+	if pvc.Spec.StorageClassName != nil {
+		pvcValidation.StorageClass = *pvc.Spec.StorageClassName
+	}
+
+	return pvcValidation
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the validation of a VRG before it is applied
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	volrep "github.com/csi-addons/kubernetes-csi-addons/apis/replication.storage/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_Validate", func() {
+	DescribeTable("Unprotectable",
+		func(validation VRGValidation, unprotectable bool) {
+			Expect(validation.Unprotectable()).To(Equal(unprotectable))
+		},
+		Entry("no PVCs", VRGValidation{}, false),
+		Entry("protected PVCs", VRGValidation{PVCs: []VRGValidationPVC{
+			{Name: "db", ProtectedBy: VRGValidationProtectedByVolRep},
+			{Name: "files", ProtectedBy: VRGValidationProtectedByVolSync},
+		}}, false),
+		Entry("an unprotectable PVC", VRGValidation{PVCs: []VRGValidationPVC{
+			{Name: "db", ProtectedBy: VRGValidationProtectedByVolRep},
+			{Name: "files", Unprotectable: "storage class not found"},
+		}}, true),
+		Entry("a selection error", VRGValidation{Error: "recipe not found"}, true),
+	)
+
+	Describe("VRGValidate", func() {
+		var (
+			c   client.Client
+			vrg *ramen.VolumeReplicationGroup
+		)
+
+		pvc := func(name, storageClassName string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: name, Labels: map[string]string{"app": "db"}},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+				Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+			}
+		}
+		validate := func() *VRGValidation {
+			return VRGValidate(context.TODO(), c, vrg, &ramen.RamenConfig{}, ctrl.Log.WithName("vrg-validate-test"))
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(volrep.AddToScheme(scheme)).To(Succeed())
+			Expect(ramen.AddToScheme(scheme)).To(Succeed())
+
+			c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "rbd"}, Provisioner: "rbd.csi.ceph.com"},
+				&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "cephfs"}, Provisioner: "cephfs.csi.ceph.com"},
+				&volrep.VolumeReplicationClass{
+					ObjectMeta: metav1.ObjectMeta{Name: "rbd-5m"},
+					Spec: volrep.VolumeReplicationClassSpec{
+						Provisioner: "rbd.csi.ceph.com",
+						Parameters:  map[string]string{"schedulingInterval": "5m"},
+					},
+				},
+				pvc("db", "rbd"),
+				pvc("files", "cephfs"),
+			).Build()
+
+			vrg = &ramen.VolumeReplicationGroup{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "vrg"},
+				Spec: ramen.VolumeReplicationGroupSpec{
+					PVCSelector:      metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					ReplicationState: ramen.Primary,
+					Async:            &ramen.VRGAsyncSpec{SchedulingInterval: "5m"},
+				},
+			}
+		})
+
+		It("reports how each PVC would be protected", func() {
+			validation := validate()
+			Expect(validation.Error).To(BeEmpty())
+			Expect(validation.PVCs).To(ConsistOf(
+				VRGValidationPVC{
+					Namespace: "app", Name: "db", StorageClass: "rbd",
+					ProtectedBy: VRGValidationProtectedByVolRep, ReplicationClass: "rbd-5m",
+				},
+				VRGValidationPVC{
+					Namespace: "app", Name: "files", StorageClass: "cephfs",
+					ProtectedBy: VRGValidationProtectedByVolSync,
+				},
+			))
+			Expect(validation.Unprotectable()).To(BeFalse())
+		})
+
+		It("reports the PVCs that would not match a VolumeReplicationClass of the VRG schedule", func() {
+			vrg.Spec.Async.SchedulingInterval = "1h"
+
+			validation := validate()
+			Expect(validation.PVCs).To(ContainElement(And(
+				HaveField("Name", "db"),
+				HaveField("ProtectedBy", BeEmpty()),
+				HaveField("Unprotectable", ContainSubstring("no VolumeReplicationClass found")),
+			)))
+			Expect(validation.Unprotectable()).To(BeTrue())
+		})
+
+		It("reports the selection error of a PVC without its storage class", func() {
+			Expect(c.Create(context.TODO(), pvc("logs", "missing"))).To(Succeed())
+
+			validation := validate()
+			Expect(validation.Error).To(ContainSubstring("failed to get the storageclass with name missing"))
+			Expect(validation.PVCs).To(BeEmpty())
+		})
+
+		It("does not update the VRG, nor create anything", func() {
+			validate()
+
+			Expect(vrg.Status.ProtectedPVCs).To(BeEmpty())
+
+			vrList := &volrep.VolumeReplicationList{}
+			Expect(c.List(context.TODO(), vrList)).To(Succeed())
+			Expect(vrList.Items).To(BeEmpty())
+		})
+	})
+})
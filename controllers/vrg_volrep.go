@@ -12,6 +12,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/go-logr/logr"
+	"golang.org/x/exp/slices"
 
 	volrep "github.com/csi-addons/kubernetes-csi-addons/apis/replication.storage/v1alpha1"
 	volrepController "github.com/csi-addons/kubernetes-csi-addons/controllers/replication.storage"
@@ -50,6 +51,13 @@ func (v *VRGInstance) reconcileVolRepsAsPrimary() {
 			continue
 		}
 
+		if v.instance.GetAnnotations()[VRGReplicationHeldAnnotation] == "true" {
+			v.updatePVCDataReadyCondition(pvc.Namespace, pvc.Name, VRGConditionReasonProgressing,
+				"Replication held for the storage capacity of the peer cluster to be checked")
+
+			continue
+		}
+
 		requeueResult, skip := v.preparePVCForVRProtection(pvc, log)
 		if requeueResult {
 			v.requeue()
@@ -426,9 +434,10 @@ func (v *VRGInstance) preparePVCForVRDeletion(pvc *corev1.PersistentVolumeClaim,
 
 		return err
 	}
-	// For Async mode, we want to change the retention policy back to delete
-	// and remove the annotation.
-	// For Sync mode, we don't want to set the retention policy to delete as
+	// For Async mode, and for Sync mode volumes that are replicated rather than
+	// shared, we want to change the retention policy back to delete and remove
+	// the annotation.
+	// For Sync mode shared volumes, we don't want to set the retention policy to delete as
 	// both the primary and the secondary VRG map to the same volume. The only
 	// state where a delete retention policy is required for the sync mode is
 	// when the VRG is primary.
@@ -436,7 +445,12 @@ func (v *VRGInstance) preparePVCForVRDeletion(pvc *corev1.PersistentVolumeClaim,
 	// to the current cluster, in case the PVC has been deleted (cases like STS the
 	// PVC may not be deleted). This is achieved by clearing the required claim ref.
 	// such that the PV can bind back to a recreated PVC. func ref.: updateExistingPVForSync
-	if v.instance.Spec.Async != nil || v.instance.Spec.ReplicationState == ramendrv1alpha1.Primary {
+	replicated, err := v.pvcVolumeReplicated(types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace})
+	if err != nil {
+		log.Info("Failed to determine whether the volume is replicated, assuming it is shared", "error", err)
+	}
+
+	if replicated || v.instance.Spec.ReplicationState == ramendrv1alpha1.Primary {
 		undoPVRetention(&pv)
 	}
 
@@ -635,7 +649,17 @@ func (v *VRGInstance) UploadPVandPVCtoS3Store(s3ProfileName string, pvc *corev1.
 func (v *VRGInstance) UploadPVAndPVCtoS3(s3ProfileName string, objectStore ObjectStorer,
 	pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim,
 ) error {
-	if err := UploadPV(objectStore, v.s3KeyPrefix(), pv.Name, *pv); err != nil {
+	redactedPV, err := uploadRedacted(v.ramenConfig.UploadRedactions, "PersistentVolume", *pv)
+	if err != nil {
+		return fmt.Errorf("failed to redact PV, failed to protect cluster data for PVC %s, %w", pvc.Name, err)
+	}
+
+	redactedPVC, err := uploadRedacted(v.ramenConfig.UploadRedactions, "PersistentVolumeClaim", *pvc)
+	if err != nil {
+		return fmt.Errorf("failed to redact PVC, failed to protect cluster data for PVC %s, %w", pvc.Name, err)
+	}
+
+	if err := UploadPV(objectStore, v.s3KeyPrefix(), pv.Name, redactedPV); err != nil {
 		var aerr awserr.Error
 		if errors.As(err, &aerr) {
 			// Treat any aws error as a persistent error
@@ -652,7 +676,7 @@ func (v *VRGInstance) UploadPVAndPVCtoS3(s3ProfileName string, objectStore Objec
 	pvcNamespacedName := types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}
 	pvcNamespacedNameString := pvcNamespacedName.String()
 
-	if err := UploadPVC(objectStore, v.s3KeyPrefix(), pvcNamespacedNameString, *pvc); err != nil {
+	if err := UploadPVC(objectStore, v.s3KeyPrefix(), pvcNamespacedNameString, redactedPVC); err != nil {
 		err := fmt.Errorf("error uploading PVC to s3Profile %s, failed to protect cluster data for PVC %s, %w",
 			s3ProfileName, pvcNamespacedNameString, err)
 
@@ -896,14 +920,20 @@ func (v *VRGInstance) reconcileMissingVR(pvc *corev1.PersistentVolumeClaim, log
 		vrMissing = true
 	)
 
-	if v.instance.Spec.Async == nil {
+	vrNamespacedName := types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
+
+	replicated, err := v.pvcVolumeReplicated(vrNamespacedName)
+	if err != nil {
+		log.Info("Failed to determine whether the volume is replicated", "error", err)
+	}
+
+	if !replicated {
 		return !vrMissing, !requeue
 	}
 
 	volRep := &volrep.VolumeReplication{}
-	vrNamespacedName := types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}
 
-	err := v.reconciler.Get(v.ctx, vrNamespacedName, volRep)
+	err = v.reconciler.Get(v.ctx, vrNamespacedName, volRep)
 	if err == nil {
 		if rmnutil.ResourceIsDeleted(volRep) {
 			log.Info("Requeuing due to processing a VR under deletion")
@@ -1010,25 +1040,12 @@ func (v *VRGInstance) processVRAsPrimary(vrNamespacedName types.NamespacedName,
 		return v.createOrUpdateVR(vrNamespacedName, volrep.Primary, log)
 	}
 
-	// TODO: createOrUpdateVR does two things. It modifies the VR and also
-	// updates the PVC Conditions. For the sync mode, we only want the latter.
-	// In the future, it would be better to refactor createOrUpdateVR into two
-	// functions. For now, we are only updating the conditions for the sync
-	// mode below. As there is no VolRep involved in sync mode, the
-	// availability is always true. Also, the refactor should work for the
-	// condition where both async and sync are enabled at the same time.
-	if v.instance.Spec.Sync != nil {
-		msg := "PVC in the VolumeReplicationGroup is ready for use"
-		v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReady, msg)
-		v.updatePVCDataProtectedCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReady, msg)
-		v.updatePVCLastSyncTime(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-		v.updatePVCLastSyncDuration(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-		v.updatePVCLastSyncBytes(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-
-		return false, true, nil
+	provider, err := v.syncReplicationProvider(vrNamespacedName)
+	if err != nil {
+		return true, false, err
 	}
 
-	return true, true, nil
+	return provider.processAsPrimary(v, vrNamespacedName, log)
 }
 
 // processVRAsSecondary processes VR to change its state to secondary, with the assumption that the
@@ -1042,26 +1059,12 @@ func (v *VRGInstance) processVRAsSecondary(vrNamespacedName types.NamespacedName
 		return v.createOrUpdateVR(vrNamespacedName, volrep.Secondary, log)
 	}
 
-	// TODO: createOrUpdateVR does two things. It modifies the VR and also
-	// updates the PVC Conditions. For the sync mode, we only want the latter.
-	// In the future, it would be better to refactor createOrUpdateVR into two
-	// functions. For now, we are only updating the conditions for the sync
-	// mode below. As there is no VolRep involved in sync mode, the
-	// availability is always true. Also, the refactor should work for the
-	// condition where both async and sync are enabled at the same time.
-	if v.instance.Spec.Sync != nil {
-		msg := "VolumeReplication resource for the pvc as Secondary is in sync with Primary"
-		v.updatePVCDataReadyCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonReplicated, msg)
-		v.updatePVCDataProtectedCondition(vrNamespacedName.Namespace, vrNamespacedName.Name, VRGConditionReasonDataProtected,
-			msg)
-		v.updatePVCLastSyncTime(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-		v.updatePVCLastSyncDuration(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-		v.updatePVCLastSyncBytes(vrNamespacedName.Namespace, vrNamespacedName.Name, nil)
-
-		return false, true, nil
+	provider, err := v.syncReplicationProvider(vrNamespacedName)
+	if err != nil {
+		return true, false, err
 	}
 
-	return true, true, nil
+	return provider.processAsSecondary(v, vrNamespacedName, log)
 }
 
 // createOrUpdateVR updates an existing VR resource if found, or creates it if required
@@ -1263,6 +1266,13 @@ func (v *VRGInstance) selectVolumeReplicationClass(
 			continue
 		}
 
+		// Sync replication is not scheduled, so the ReplicationClass of the pvc provisioner is selected
+		if v.instance.Spec.Async == nil {
+			v.log.Info(fmt.Sprintf("Found VolumeReplicationClass that matches provisioner %s", storageClass.Provisioner))
+
+			return replicationClass, nil
+		}
+
 		schedulingInterval, found := replicationClass.Spec.Parameters["schedulingInterval"]
 		if !found {
 			// schedule not present in parameters of this replicationClass.
@@ -1278,6 +1288,12 @@ func (v *VRGInstance) selectVolumeReplicationClass(
 		}
 	}
 
+	if v.instance.Spec.Async == nil {
+		v.log.Info(fmt.Sprintf("No VolumeReplicationClass found to match provisioner %s", storageClass.Provisioner))
+
+		return nil, fmt.Errorf("no VolumeReplicationClass found to match provisioner")
+	}
+
 	v.log.Info(fmt.Sprintf("No VolumeReplicationClass found to match provisioner and schedule %s/%s",
 		storageClass.Provisioner, v.instance.Spec.Async.SchedulingInterval))
 
@@ -1851,7 +1867,7 @@ func (v *VRGInstance) restorePVsAndPVCsFromS3(result *ctrl.Result) (int, error)
 	err := errors.New("s3Profiles empty")
 	NoS3 := false
 
-	for _, s3ProfileName := range v.instance.Spec.S3Profiles {
+	for _, s3ProfileName := range v.clusterDataDownloadProfiles() {
 		if s3ProfileName == NoS3StoreAvailable {
 			v.log.Info("NoS3 available to fetch")
 
@@ -1872,10 +1888,45 @@ func (v *VRGInstance) restorePVsAndPVCsFromS3(result *ctrl.Result) (int, error)
 			continue
 		}
 
+		v.restoreCheckpointLoad(objectStore, s3ProfileName)
+
+		if v.restoreCheckpointPVCsRestored() {
+			var pvcCount int
+
+			// PVs and PVCs were restored before the restore was interrupted, reload the PVCs to protect only
+			pvcCount, err = v.restoredPVCsFromObjectStore(objectStore, s3ProfileName)
+			if err != nil {
+				continue
+			}
+
+			v.volumeSnapshotsRestore(objectStore)
+
+			return pvcCount, v.kubeObjectsRecover(result, s3StoreProfile, objectStore)
+		}
+
 		var pvCount, pvcCount int
 
+		var pvList []corev1.PersistentVolume
+
+		var pvcList []corev1.PersistentVolumeClaim
+
+		pvList, pvcList, err = v.clusterDataDownloaded(objectStore, s3ProfileName, result)
+		if clusterDataDownloading(err) {
+			return 0, err
+		}
+
+		if err != nil {
+			v.log.Error(err, fmt.Sprintf("error fetching PV and PVC cluster data from S3 profile %s", s3ProfileName))
+
+			continue
+		}
+
+		for idx := range pvcList {
+			v.pvcAccessModesMap(&pvcList[idx], pvList)
+		}
+
 		// Restore all PVs found in the s3 store. If any failure, the next profile will be retried
-		pvCount, err = v.restorePVsFromObjectStore(objectStore, s3ProfileName)
+		pvCount, err = v.restorePVsFromObjectStore(pvList, s3ProfileName)
 		if err != nil {
 			continue
 		}
@@ -1887,7 +1938,7 @@ func (v *VRGInstance) restorePVsAndPVCsFromS3(result *ctrl.Result) (int, error)
 		// CrunchyDB is responsible for creating and managing the lifecycle of their own PVCs, a newly created
 		// PVC may cause a new PV to be created.
 		// Ignoring PVC restore errors helps with the upgrade from ODF-4.12.x to 4.13
-		pvcCount, err = v.restorePVCsFromObjectStore(objectStore, s3ProfileName)
+		pvcCount, err = v.restorePVCsFromObjectStore(pvcList, s3ProfileName)
 
 		if err != nil || pvCount != pvcCount {
 			v.log.Info(fmt.Sprintf("Warning: Mismatch in PV/PVC count %d/%d (%v)",
@@ -1897,6 +1948,9 @@ func (v *VRGInstance) restorePVsAndPVCsFromS3(result *ctrl.Result) (int, error)
 		}
 
 		v.log.Info(fmt.Sprintf("Restored %d PVs and %d PVCs using profile %s", pvCount, pvcCount, s3ProfileName))
+		v.restoreCheckpointPVCsSet(v.volRepPVCs[len(v.volRepPVCs)-pvcCount:])
+
+		v.volumeSnapshotsRestore(objectStore)
 
 		return pvCount + pvcCount, v.kubeObjectsRecover(result, s3StoreProfile, objectStore)
 	}
@@ -1910,17 +1964,11 @@ func (v *VRGInstance) restorePVsAndPVCsFromS3(result *ctrl.Result) (int, error)
 	return 0, err
 }
 
-func (v *VRGInstance) restorePVsFromObjectStore(objectStore ObjectStorer, s3ProfileName string) (int, error) {
-	pvList, err := downloadPVs(objectStore, v.s3KeyPrefix())
-	if err != nil {
-		v.log.Error(err, fmt.Sprintf("error fetching PV cluster data from S3 profile %s", s3ProfileName))
-
-		return 0, err
-	}
-
+func (v *VRGInstance) restorePVsFromObjectStore(pvList []corev1.PersistentVolume, s3ProfileName string,
+) (int, error) {
 	v.log.Info(fmt.Sprintf("Found %d PVs in s3 store using profile %s", len(pvList), s3ProfileName))
 
-	if err = v.checkPVClusterData(pvList); err != nil {
+	if err := v.checkPVClusterData(pvList); err != nil {
 		errMsg := fmt.Sprintf("Error found in PV cluster data in S3 store %s", s3ProfileName)
 		v.log.Info(errMsg)
 		v.log.Error(err, fmt.Sprintf("Resolve PV conflict in the S3 store %s to deploy the application", s3ProfileName))
@@ -1928,10 +1976,31 @@ func (v *VRGInstance) restorePVsFromObjectStore(objectStore ObjectStorer, s3Prof
 		return 0, fmt.Errorf("%s: %w", errMsg, err)
 	}
 
-	return restoreClusterDataObjects(v, pvList, "PV", cleanupPVForRestore, v.validateExistingPV)
+	for idx := range pvList {
+		if err := v.pvStorageClassMap(&pvList[idx]); err != nil {
+			return 0, err
+		}
+	}
+
+	return restoreClusterDataObjects(v, pvList, "PV", v.cleanupPVForRestore, v.validateExistingPV)
+}
+
+func (v *VRGInstance) restorePVCsFromObjectStore(pvcList []corev1.PersistentVolumeClaim, s3ProfileName string,
+) (int, error) {
+	v.log.Info(fmt.Sprintf("Found %d PVCs in s3 store using profile %s", len(pvcList), s3ProfileName))
+
+	for idx := range pvcList {
+		v.pvcStorageClassMap(&pvcList[idx])
+	}
+
+	v.volRepPVCs = append(v.volRepPVCs, pvcList...)
+
+	return restoreClusterDataObjects(v, pvcList, "PVC", cleanupPVCForRestore, v.validateExistingPVC)
 }
 
-func (v *VRGInstance) restorePVCsFromObjectStore(objectStore ObjectStorer, s3ProfileName string) (int, error) {
+// restoredPVCsFromObjectStore adds the PVCs in the s3 store that the restore checkpoint lists as restored to the
+// VolRep PVCs, without restoring them again
+func (v *VRGInstance) restoredPVCsFromObjectStore(objectStore ObjectStorer, s3ProfileName string) (int, error) {
 	pvcList, err := downloadPVCs(objectStore, v.s3KeyPrefix())
 	if err != nil {
 		v.log.Error(err, fmt.Sprintf("error fetching PVC cluster data from S3 profile %s", s3ProfileName))
@@ -1939,11 +2008,22 @@ func (v *VRGInstance) restorePVCsFromObjectStore(objectStore ObjectStorer, s3Pro
 		return 0, err
 	}
 
-	v.log.Info(fmt.Sprintf("Found %d PVCs in s3 store using profile %s", len(pvcList), s3ProfileName))
+	count := 0
 
-	v.volRepPVCs = append(v.volRepPVCs, pvcList...)
+	for idx := range pvcList {
+		pvcNamespacedName := types.NamespacedName{Namespace: pvcList[idx].Namespace, Name: pvcList[idx].Name}
+		if slices.Contains(v.restoreCheckpoint.RestoredPVCs, pvcNamespacedName.String()) {
+			v.pvcStorageClassMap(&pvcList[idx])
+			v.pvcAccessModesMap(&pvcList[idx], nil)
+			v.volRepPVCs = append(v.volRepPVCs, pvcList[idx])
+			count++
+		}
+	}
 
-	return restoreClusterDataObjects(v, pvcList, "PVC", cleanupPVCForRestore, v.validateExistingPVC)
+	v.log.Info(fmt.Sprintf("Skipped restore of %d PVs and PVCs restored as of checkpoint using profile %s",
+		count, s3ProfileName))
+
+	return count, nil
 }
 
 // checkPVClusterData returns an error if there are PVs in the input pvList
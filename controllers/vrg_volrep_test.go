@@ -461,6 +461,44 @@ var _ = Describe("VolumeReplicationGroupVolRepController", func() {
 					pvcsVerify(pvcNamesReselected, pvcProtectedVerify)
 				})
 			})
+			When("one selected is annotated excluded", func() {
+				var pvcNamesExcluded []types.NamespacedName
+				BeforeAll(func() {
+					pvcNamesExcluded = pvcNamesSelected[0:1]
+					pvcNamesSelected = pvcNamesSelected[1:]
+					DeferCleanup(func() {
+						pvcNamesDeselected = append(pvcNamesDeselected, pvcNamesExcluded...)
+					})
+					vrgResourceVersion = vrgResourceVersionGet()
+					forPVCs(pvcNamesExcluded, func(pvc corev1.PersistentVolumeClaim) {
+						annotations := pvc.GetAnnotations()
+						if annotations == nil {
+							annotations = map[string]string{}
+						}
+						annotations[vrgController.PVCDRExcludedAnnotation] = "true"
+						pvc.SetAnnotations(annotations)
+						Expect(k8sClient.Update(context.TODO(), &pvc)).To(Succeed())
+					})
+				})
+				It("updates the status", func() {
+					Eventually(vrgResourceVersionGet).ShouldNot(Equal(vrgResourceVersion))
+				})
+				It("keeps the selected protected", func() {
+					pvcsVerify(pvcNamesSelected, pvcProtectedVerify)
+				})
+				It("unprotects it", func() {
+					pvcsVerify(pvcNamesExcluded, pvcUnprotectedVerify)
+				})
+				It("reports it skipped", func() {
+					Eventually(func() []ramendrv1alpha1.SkippedPVC {
+						return vrgGet().Status.SkippedPVCs
+					}).Should(ContainElement(And(
+						HaveField("Namespace", pvcNamesExcluded[0].Namespace),
+						HaveField("Name", pvcNamesExcluded[0].Name),
+						HaveField("Reason", ramendrv1alpha1.SkippedPVCReasonPVCExcluded),
+					)))
+				})
+			})
 			When("all selected are deselected", func() {
 				BeforeAll(func() {
 					DeferCleanup(func() {
@@ -553,6 +591,49 @@ var _ = Describe("VolumeReplicationGroupVolRepController", func() {
 		})
 	})
 
+	// Test PV upload faults injected into an s3 store, expect ClusterDataProtected to be false while they are
+	// injected, and true once they are cleared
+	var vrgS3UploadFaultsTestCase *vrgTest
+	Context("in primary state", func() {
+		createTestTemplate := &template{
+			ClaimBindInfo:          corev1.ClaimBound,
+			VolumeBindInfo:         corev1.VolumeBound,
+			schedulingInterval:     "1h",
+			storageClassName:       "manual",
+			replicationClassName:   "test-replicationclass",
+			vrcProvisioner:         "manual.storage.com",
+			scProvisioner:          "manual.storage.com",
+			replicationClassLabels: map[string]string{"protection": "ramen"},
+			s3Profiles:             []string{s3Profiles[vrgS3ProfileNumber].S3ProfileName},
+		}
+		var faults *objectStoreFaults
+		It("sets up PVCs, PVs and VRGs (with an s3 store that fails the uploads of the VRG)", func() {
+			vrgS3UploadFaultsTestCase = newVRGTestCaseCreate(2, createTestTemplate, true, false)
+			faults = fakeObjectStorerFaults(s3Profiles[vrgS3ProfileNumber].S3ProfileName)
+			faults.inject(objectStoreFault{
+				operation: objectStoreOperationUpload,
+				keyPrefix: vrgS3UploadFaultsTestCase.s3KeyPrefix(),
+			})
+			vrgS3UploadFaultsTestCase.VRGTestCaseStart()
+		})
+		It("waits for VRG to create a VR for each PVC", func() {
+			vrgS3UploadFaultsTestCase.waitForVRCountToMatch(len(vrgS3UploadFaultsTestCase.pvcNames))
+			vrgS3UploadFaultsTestCase.promoteVolReps()
+		})
+		It("sets ClusterDataProtected to false while the uploads fail", func() {
+			vrgS3UploadFaultsTestCase.verifyVRGStatusExpectation(true, vrgController.VRGConditionReasonReady)
+			vrgS3UploadFaultsTestCase.verifyCachedUploadError()
+		})
+		It("sets ClusterDataProtected to true once the uploads succeed", func() {
+			faults.clear()
+			vrgS3UploadFaultsTestCase.clusterDataProtectedWait(metav1.ConditionTrue)
+		})
+		It("cleans up after testing", func() {
+			faults.clear()
+			vrgS3UploadFaultsTestCase.cleanupProtected()
+		})
+	})
+
 	// Test VRG finalizer removal during deletion is deferred till VR is deleted
 	var vrgVRDeleteEnsureTestCase *vrgTest
 	Context("in primary state", func() {
@@ -28,6 +28,7 @@ func (v *VRGInstance) restorePVsAndPVCsForVolSync() (int, error) {
 	numPVsRestored := 0
 
 	for _, rdSpec := range v.instance.Spec.VolSync.RDSpec {
+		rdSpec = v.rdSpecAccessModesMapped(v.rdSpecStorageClassMapped(rdSpec))
 		failoverAction := v.instance.Spec.Action == ramendrv1alpha1.VRGActionFailover
 		// Create a PVC from snapshot or for direct copy
 		err := v.volSyncHandler.EnsurePVCfromRD(rdSpec, failoverAction)
@@ -93,10 +94,29 @@ func (v *VRGInstance) reconcileVolSyncAsPrimary(finalSyncPrepared *bool) (requeu
 		return
 	}
 
+	// PVCs are sorted by priority. The final syncs of PVCs are run once those of PVCs of higher priority complete.
+	v.volSyncHandler.SetPriorityRankHighest(util.PVCPriorityRank(v.volSyncPVCs[0].GetLabels()))
+
+	pendingRank := -1
+
 	for _, pvc := range v.volSyncPVCs {
-		requeuePVC := v.reconcilePVCAsVolSyncPrimary(pvc)
+		rank := util.PVCPriorityRank(pvc.GetLabels())
+		runFinalSync := v.instance.Spec.RunFinalSync
+
+		if runFinalSync && pendingRank >= 0 && rank > pendingRank {
+			v.log.Info("Final sync waits for those of higher priority PVCs", "pvc", pvc.Name, "priorityRank", rank)
+
+			runFinalSync = false
+			requeue = true
+		}
+
+		requeuePVC := v.reconcilePVCAsVolSyncPrimary(pvc, runFinalSync)
 		if requeuePVC {
 			requeue = true
+
+			if pendingRank < 0 {
+				pendingRank = rank
+			}
 		}
 	}
 
@@ -112,7 +132,8 @@ func (v *VRGInstance) reconcileVolSyncAsPrimary(finalSyncPrepared *bool) (requeu
 	return requeue
 }
 
-func (v *VRGInstance) reconcilePVCAsVolSyncPrimary(pvc corev1.PersistentVolumeClaim) (requeue bool) {
+func (v *VRGInstance) reconcilePVCAsVolSyncPrimary(pvc corev1.PersistentVolumeClaim, runFinalSync bool,
+) (requeue bool) {
 	newProtectedPVC := &ramendrv1alpha1.ProtectedPVC{
 		Name:               pvc.Name,
 		Namespace:          pvc.Namespace,
@@ -141,13 +162,13 @@ func (v *VRGInstance) reconcilePVCAsVolSyncPrimary(pvc corev1.PersistentVolumeCl
 
 	err := v.volSyncHandler.PreparePVC(util.ProtectedPVCNamespacedName(*protectedPVC),
 		v.instance.Spec.PrepareForFinalSync,
-		v.volSyncHandler.IsCopyMethodDirect())
+		v.volSyncHandler.CopyMethodDirectFor(protectedPVC.StorageClassName))
 	if err != nil {
 		return true
 	}
 
 	// reconcile RS and if runFinalSync is true, then one final sync will be run
-	finalSyncComplete, rs, err := v.volSyncHandler.ReconcileRS(rsSpec, v.instance.Spec.RunFinalSync)
+	finalSyncComplete, rs, err := v.volSyncHandler.ReconcileRS(rsSpec, runFinalSync)
 	if err != nil {
 		v.log.Info(fmt.Sprintf("Failed to reconcile VolSync Replication Source for rsSpec %v. Error %v",
 			rsSpec, err))
@@ -167,9 +188,28 @@ func (v *VRGInstance) reconcilePVCAsVolSyncPrimary(pvc corev1.PersistentVolumeCl
 	if rs.Status != nil {
 		protectedPVC.LastSyncTime = rs.Status.LastSyncTime
 		protectedPVC.LastSyncDuration = rs.Status.LastSyncDuration
+		protectedPVC.LastSyncBytes = volsync.RsyncTLSSyncBytes(rs)
+	}
+
+	// Report per PVC data protection in the same manner as volume replication, so that consumers of the VRG
+	// status need not differentiate between the two schemes
+	if rs.Spec.Syncthing != nil {
+		if volsync.SyncthingPeersConnected(rs) {
+			setVRGAsDataProtectedCondition(&protectedPVC.Conditions, v.instance.Generation,
+				"PVC data kept in sync with the peer cluster")
+		} else {
+			setVRGDataProtectionProgressCondition(&protectedPVC.Conditions, v.instance.Generation,
+				"PVC data sync to the peer cluster waiting for the peer to connect")
+		}
+	} else if protectedPVC.LastSyncTime != nil && !protectedPVC.LastSyncTime.IsZero() {
+		setVRGAsDataProtectedCondition(&protectedPVC.Conditions, v.instance.Generation,
+			"PVC data synced to the peer cluster")
+	} else {
+		setVRGDataProtectionProgressCondition(&protectedPVC.Conditions, v.instance.Generation,
+			"PVC data sync to the peer cluster in progress")
 	}
 
-	return v.instance.Spec.RunFinalSync && !finalSyncComplete
+	return runFinalSync && !finalSyncComplete
 }
 
 func (v *VRGInstance) reconcileVolSyncAsSecondary() bool {
@@ -202,8 +242,15 @@ func (v *VRGInstance) reconcileRDSpecForDeletionOrReplication() bool {
 	requeue := false
 
 	for _, rdSpec := range v.instance.Spec.VolSync.RDSpec {
+		rdSpec = v.rdSpecAccessModesMapped(v.rdSpecStorageClassMapped(rdSpec))
 		v.log.Info("Reconcile RD as Secondary", "RDSpec", rdSpec)
 
+		if v.volSyncHandler.IsSyncthing(rdSpec.ProtectedPVC.StorageClassName) {
+			requeue = v.reconcileSyncthingReplica(rdSpec) || requeue
+
+			continue
+		}
+
 		rd, err := v.volSyncHandler.ReconcileRD(rdSpec)
 		if err != nil {
 			v.log.Error(err, "Failed to reconcile VolSync Replication Destination")
@@ -228,6 +275,39 @@ func (v *VRGInstance) reconcileRDSpecForDeletionOrReplication() bool {
 	return requeue
 }
 
+// reconcileSyncthingReplica reconciles the copy of a PVC replicated by the Syncthing mover, returns true to requeue
+// until its syncthing device is reported
+func (v *VRGInstance) reconcileSyncthingReplica(rdSpec ramendrv1alpha1.VolSyncReplicationDestinationSpec) bool {
+	rs, err := v.volSyncHandler.ReconcileSyncthingReplica(rdSpec)
+	if err != nil {
+		v.log.Error(err, "Failed to reconcile VolSync syncthing copy", "pvc", rdSpec.ProtectedPVC.Name)
+
+		return true
+	}
+
+	if rs == nil {
+		v.log.Info(fmt.Sprintf("ReconcileSyncthingReplica - ReplicationSource for %s is not ready. We'll retry...",
+			rdSpec.ProtectedPVC.Name))
+
+		return true
+	}
+
+	return false
+}
+
+// syncthingDevicesUpdate reports the syncthing devices of the PVCs replicated by the Syncthing mover on this
+// cluster, for the DRPC to set them as the syncthing peers of the VRGs of the peer clusters
+func (v *VRGInstance) syncthingDevicesUpdate() {
+	devices, err := v.volSyncHandler.SyncthingDevices(v.workloadNamespaces())
+	if err != nil {
+		v.log.Info("Failed to list syncthing devices", "error", err)
+
+		return
+	}
+
+	v.instance.Status.SyncthingDevices = devices
+}
+
 func (v *VRGInstance) aggregateVolSyncDataReadyCondition() *metav1.Condition {
 	dataReadyCondition := &metav1.Condition{
 		Status:             metav1.ConditionTrue,
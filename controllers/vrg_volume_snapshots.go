@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+const (
+	// volumeSnapshotsS3ObjectName is the name of the volume snapshots of a VRG's PVCs, stored along with the VRG
+	volumeSnapshotsS3ObjectName = "volumesnapshots"
+
+	// VolumeSnapshotClassReplicatedLabel marks the volume snapshot classes of the drivers whose snapshots are
+	// replicated with their volumes, so that the snapshot handles of one cluster are valid on the other
+	VolumeSnapshotClassReplicatedLabel = "ramendr.openshift.io/replicated-snapshots"
+
+	// VolumeSnapshotProtectedLabel marks the volume snapshots the VRG protects itself, which are labeled to be
+	// excluded from the kube object captures too
+	VolumeSnapshotProtectedLabel = "ramendr.openshift.io/volume-snapshot-protected"
+
+	veleroExcludeFromBackupLabel = "velero.io/exclude-from-backup"
+)
+
+// protectedVolumeSnapshot is a volume snapshot of a protected PVC with the content it is bound to
+type protectedVolumeSnapshot struct {
+	Snapshot snapv1.VolumeSnapshot        `json:"snapshot"`
+	Content  snapv1.VolumeSnapshotContent `json:"content"`
+}
+
+func (v *VRGInstance) volumeSnapshotsProtectionEnabled() bool {
+	return v.instance.Spec.KubeObjectProtection != nil && v.instance.Spec.KubeObjectProtection.ProtectVolumeSnapshots
+}
+
+// volumeSnapshotsProtect uploads the ready volume snapshots of the VolRep PVCs, with their contents, replacing those
+// uploaded before, so that snapshots deleted since are not recovered. The snapshots of VolSync are not protected.
+// The snapshots protected are labeled to be excluded from the kube object captures, for Velero not to recover them
+// as new snapshots of the PVCs, and those no longer protected unlabeled, for Velero to capture them again.
+func (v *VRGInstance) volumeSnapshotsProtect(objectStorer ObjectStorer) error {
+	if !v.volumeSnapshotsProtectionEnabled() || v.instance.Spec.ReplicationState != ramen.Primary {
+		return nil
+	}
+
+	snapshots, err := v.volumeSnapshotsList()
+	if err != nil {
+		return err
+	}
+
+	if err := objectStorer.UploadObject(v.s3KeyPrefix()+volumeSnapshotsS3ObjectName, snapshots); err != nil {
+		return fmt.Errorf("failed to upload volume snapshots (%w)", err)
+	}
+
+	v.log.Info("Volume snapshots protected", "count", len(snapshots))
+
+	return nil
+}
+
+func (v *VRGInstance) volumeSnapshotsList() ([]protectedVolumeSnapshot, error) {
+	claims := map[types.NamespacedName]bool{}
+	for idx := range v.volRepPVCs {
+		claims[types.NamespacedName{Namespace: v.volRepPVCs[idx].Namespace, Name: v.volRepPVCs[idx].Name}] = true
+	}
+
+	snapshots := []protectedVolumeSnapshot{}
+
+	for _, namespace := range v.workloadNamespaces() {
+		snapshotList := &snapv1.VolumeSnapshotList{}
+		if err := v.reconciler.APIReader.List(v.ctx, snapshotList, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list volume snapshots in namespace %s (%w)", namespace, err)
+		}
+
+		for idx := range snapshotList.Items {
+			snapshot := &snapshotList.Items[idx]
+
+			if !volumeSnapshotProtectable(snapshot, claims) {
+				if err := v.volumeSnapshotProtectedLabel(snapshot, false); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			content := &snapv1.VolumeSnapshotContent{}
+			if err := v.reconciler.APIReader.Get(v.ctx,
+				types.NamespacedName{Name: *snapshot.Status.BoundVolumeSnapshotContentName}, content); err != nil {
+				return nil, fmt.Errorf("failed to get volume snapshot content %s of volume snapshot %s/%s (%w)",
+					*snapshot.Status.BoundVolumeSnapshotContentName, namespace, snapshot.Name, err)
+			}
+
+			if content.Status == nil || content.Status.SnapshotHandle == nil {
+				continue
+			}
+
+			if err := v.volumeSnapshotProtectedLabel(snapshot, true); err != nil {
+				return nil, err
+			}
+
+			snapshots = append(snapshots, protectedVolumeSnapshot{Snapshot: *snapshot, Content: *content})
+		}
+	}
+
+	return snapshots, nil
+}
+
+// volumeSnapshotProtectedLabel labels a volume snapshot as protected by the VRG, and to be excluded from the kube
+// object captures, or removes the labels of a snapshot labeled so before
+func (v *VRGInstance) volumeSnapshotProtectedLabel(snapshot *snapv1.VolumeSnapshot, protected bool) error {
+	_, labeled := snapshot.GetLabels()[VolumeSnapshotProtectedLabel]
+	if labeled == protected {
+		return nil
+	}
+
+	if protected {
+		snapshot.SetLabels(labelsAdd(snapshot.GetLabels(), VolumeSnapshotProtectedLabel, veleroExcludeFromBackupLabel))
+	} else {
+		delete(snapshot.Labels, VolumeSnapshotProtectedLabel)
+		delete(snapshot.Labels, veleroExcludeFromBackupLabel)
+	}
+
+	if err := v.reconciler.Update(v.ctx, snapshot); err != nil {
+		return fmt.Errorf("failed to label volume snapshot %s/%s protected %t (%w)", snapshot.Namespace,
+			snapshot.Name, protected, err)
+	}
+
+	return nil
+}
+
+func labelsAdd(labels map[string]string, keys ...string) map[string]string {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	for _, key := range keys {
+		labels[key] = "true"
+	}
+
+	return labels
+}
+
+// volumeSnapshotProtectable returns whether the snapshot is a ready snapshot of one of the claims, not owned by
+// VolSync
+func volumeSnapshotProtectable(snapshot *snapv1.VolumeSnapshot, claims map[types.NamespacedName]bool) bool {
+	if snapshot.Spec.Source.PersistentVolumeClaimName == nil || snapshot.Status == nil ||
+		snapshot.Status.ReadyToUse == nil || !*snapshot.Status.ReadyToUse ||
+		snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		return false
+	}
+
+	claim := types.NamespacedName{Namespace: snapshot.Namespace, Name: *snapshot.Spec.Source.PersistentVolumeClaimName}
+	if !claims[claim] {
+		return false
+	}
+
+	for _, owner := range snapshot.OwnerReferences {
+		if strings.HasPrefix(owner.APIVersion, "volsync.backube/") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// volumeSnapshotsRestore recreates the protected volume snapshots whose drivers have a volume snapshot class on this
+// cluster labeled as replicating their snapshots, as pre-provisioned snapshots bound to contents of their snapshot
+// handles, named after the UIDs of the snapshots they were taken as. The contents retain the snapshots when deleted,
+// as the snapshots were taken on the other cluster. The snapshots of other drivers, and those that exist, are
+// skipped. Errors are logged, and do not fail the recovery.
+func (v *VRGInstance) volumeSnapshotsRestore(objectStore ObjectStorer) {
+	if !v.volumeSnapshotsProtectionEnabled() {
+		return
+	}
+
+	snapshots := []protectedVolumeSnapshot{}
+	if err := objectStore.DownloadObject(v.s3KeyPrefix()+volumeSnapshotsS3ObjectName, &snapshots); err != nil {
+		v.log.Info("Volume snapshots not restored, download failed", "error", err)
+
+		return
+	}
+
+	classes, err := v.volumeSnapshotClassesReplicated()
+	if err != nil {
+		v.log.Info("Volume snapshots not restored", "error", err)
+
+		return
+	}
+
+	restored := 0
+
+	for idx := range snapshots {
+		snapshot := &snapshots[idx].Snapshot
+		content := &snapshots[idx].Content
+		log := v.log.WithValues("name", snapshot.Name, "namespace", snapshot.Namespace)
+
+		className, ok := classes[content.Spec.Driver]
+		if !ok {
+			log.Info("Volume snapshot not restored, snapshots of its driver not replicated", "driver",
+				content.Spec.Driver)
+
+			continue
+		}
+
+		if err := v.volumeSnapshotRestore(snapshot, content, className); err != nil {
+			log.Info("Volume snapshot not restored", "error", err)
+
+			continue
+		}
+
+		restored++
+	}
+
+	v.log.Info("Volume snapshots restored", "count", restored, "protected", len(snapshots))
+}
+
+// volumeSnapshotClassesReplicated returns the names of the volume snapshot classes labeled as replicating their
+// snapshots, by driver
+func (v *VRGInstance) volumeSnapshotClassesReplicated() (map[string]string, error) {
+	classList := &snapv1.VolumeSnapshotClassList{}
+	if err := v.reconciler.APIReader.List(v.ctx, classList,
+		client.MatchingLabels{VolumeSnapshotClassReplicatedLabel: "true"}); err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshot classes (%w)", err)
+	}
+
+	classes := map[string]string{}
+	for _, class := range classList.Items {
+		classes[class.Driver] = class.Name
+	}
+
+	return classes, nil
+}
+
+func (v *VRGInstance) volumeSnapshotRestore(snapshot *snapv1.VolumeSnapshot, content *snapv1.VolumeSnapshotContent,
+	className string,
+) error {
+	key := types.NamespacedName{Namespace: snapshot.Namespace, Name: snapshot.Name}
+
+	if err := v.reconciler.APIReader.Get(v.ctx, key, &snapv1.VolumeSnapshot{}); err == nil {
+		v.log.Info("Volume snapshot exists, not restored", "name", snapshot.Name, "namespace", snapshot.Namespace)
+
+		return nil
+	} else if !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get volume snapshot (%w)", err)
+	}
+
+	restoredContent := &snapv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   volumeSnapshotContentRestoredName(snapshot),
+			Labels: content.Labels,
+		},
+		Spec: snapv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: corev1.ObjectReference{
+				Namespace: snapshot.Namespace,
+				Name:      snapshot.Name,
+			},
+			DeletionPolicy:          snapv1.VolumeSnapshotContentRetain,
+			Driver:                  content.Spec.Driver,
+			VolumeSnapshotClassName: &className,
+			Source: snapv1.VolumeSnapshotContentSource{
+				SnapshotHandle: content.Status.SnapshotHandle,
+			},
+		},
+	}
+
+	if err := v.volumeSnapshotContentCreate(restoredContent); err != nil {
+		return err
+	}
+
+	restoredSnapshot := &snapv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   snapshot.Namespace,
+			Name:        snapshot.Name,
+			Labels:      snapshot.Labels,
+			Annotations: snapshot.Annotations,
+		},
+		Spec: snapv1.VolumeSnapshotSpec{
+			Source: snapv1.VolumeSnapshotSource{
+				VolumeSnapshotContentName: &restoredContent.Name,
+			},
+			VolumeSnapshotClassName: &className,
+		},
+	}
+
+	if err := v.reconciler.Create(v.ctx, restoredSnapshot); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create volume snapshot (%w)", err)
+	}
+
+	return nil
+}
+
+func volumeSnapshotContentRestoredName(snapshot *snapv1.VolumeSnapshot) string {
+	return "ramen-snapcontent-" + string(snapshot.UID)
+}
+
+// volumeSnapshotContentCreate creates a volume snapshot content, or accepts one that exists only if it is of the
+// same snapshot handle and for the same snapshot, as created by a restore retried
+func (v *VRGInstance) volumeSnapshotContentCreate(content *snapv1.VolumeSnapshotContent) error {
+	err := v.reconciler.Create(v.ctx, content)
+	if err == nil {
+		return nil
+	}
+
+	if !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create volume snapshot content %s (%w)", content.Name, err)
+	}
+
+	existing := &snapv1.VolumeSnapshotContent{}
+	if err := v.reconciler.APIReader.Get(v.ctx, types.NamespacedName{Name: content.Name}, existing); err != nil {
+		return fmt.Errorf("failed to get volume snapshot content %s (%w)", content.Name, err)
+	}
+
+	if existing.Spec.Source.SnapshotHandle == nil ||
+		*existing.Spec.Source.SnapshotHandle != *content.Spec.Source.SnapshotHandle ||
+		existing.Spec.VolumeSnapshotRef.Namespace != content.Spec.VolumeSnapshotRef.Namespace ||
+		existing.Spec.VolumeSnapshotRef.Name != content.Spec.VolumeSnapshotRef.Name {
+		return fmt.Errorf("volume snapshot content %s exists for another snapshot handle or snapshot", content.Name)
+	}
+
+	return nil
+}
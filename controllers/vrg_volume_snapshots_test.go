@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+// white box testing desired for the protection of the volume snapshots of the protected PVCs
+package controllers //nolint: testpackage
+
+import (
+	"context"
+
+	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+var _ = Describe("VRG_VolumeSnapshots", func() {
+	const namespace = "app"
+
+	ready := true
+	notReady := false
+	snapshot := func(name, claim string, readyToUse *bool, labels map[string]string,
+		owners ...metav1.OwnerReference,
+	) *snapv1.VolumeSnapshot {
+		contentName := "snapcontent-" + name
+
+		return &snapv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace, Name: name, UID: types.UID(name), Labels: labels, OwnerReferences: owners,
+			},
+			Spec: snapv1.VolumeSnapshotSpec{Source: snapv1.VolumeSnapshotSource{PersistentVolumeClaimName: &claim}},
+			Status: &snapv1.VolumeSnapshotStatus{
+				ReadyToUse: readyToUse, BoundVolumeSnapshotContentName: &contentName,
+			},
+		}
+	}
+	content := func(name, handle string) *snapv1.VolumeSnapshotContent {
+		return &snapv1.VolumeSnapshotContent{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: snapv1.VolumeSnapshotContentSpec{
+				Driver: "rbd.csi.ceph.com",
+				Source: snapv1.VolumeSnapshotContentSource{SnapshotHandle: &handle},
+			},
+			Status: &snapv1.VolumeSnapshotContentStatus{SnapshotHandle: &handle},
+		}
+	}
+	claims := map[types.NamespacedName]bool{{Namespace: namespace, Name: "data"}: true}
+	volSyncOwner := metav1.OwnerReference{APIVersion: "volsync.backube/v1alpha1", Kind: "ReplicationSource", Name: "data"}
+
+	DescribeTable("volumeSnapshotProtectable",
+		func(snapshot *snapv1.VolumeSnapshot, protectable bool) {
+			Expect(volumeSnapshotProtectable(snapshot, claims)).To(Equal(protectable))
+		},
+		Entry("a ready snapshot of a protected PVC", snapshot("daily", "data", &ready, nil), true),
+		Entry("a snapshot not ready", snapshot("daily", "data", &notReady, nil), false),
+		Entry("a snapshot not known to be ready", snapshot("daily", "data", nil, nil), false),
+		Entry("a snapshot of another PVC", snapshot("daily", "logs", &ready, nil), false),
+		Entry("a snapshot of VolSync", snapshot("daily", "data", &ready, nil, volSyncOwner), false),
+		Entry("a snapshot without status", &snapv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "daily"},
+			Spec: snapv1.VolumeSnapshotSpec{Source: snapv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &[]string{"data"}[0],
+			}},
+		}, false),
+	)
+
+	var (
+		c           client.Client
+		vrgInstance *VRGInstance
+	)
+
+	snapshotGet := func(name string) *snapv1.VolumeSnapshot {
+		snapshot := &snapv1.VolumeSnapshot{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, snapshot)).To(Succeed())
+
+		return snapshot
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(snapv1.AddToScheme(scheme)).To(Succeed())
+
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			snapshot("daily", "data", &ready, nil),
+			snapshot("weekly", "logs", &ready, map[string]string{
+				VolumeSnapshotProtectedLabel: "true", veleroExcludeFromBackupLabel: "true", "app": "web",
+			}),
+			content("snapcontent-daily", "handle-daily"),
+			content("snapcontent-weekly", "handle-weekly"),
+		).Build()
+		vrgInstance = vrgInstanceFake(c, "vrg-volume-snapshots-test", ramen.VolumeReplicationGroupSpec{})
+		vrgInstance.volRepPVCs = []corev1.PersistentVolumeClaim{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "data"}},
+		}
+	})
+
+	Describe("volumeSnapshotsList", func() {
+		It("lists the protected snapshots, labeled to be excluded from the captures, and unlabels the others", func() {
+			snapshots, err := vrgInstance.volumeSnapshotsList()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(snapshots).To(HaveLen(1))
+			Expect(snapshots[0].Snapshot.Name).To(Equal("daily"))
+			Expect(snapshots[0].Content.Name).To(Equal("snapcontent-daily"))
+
+			Expect(snapshotGet("daily").Labels).To(Equal(map[string]string{
+				VolumeSnapshotProtectedLabel: "true", veleroExcludeFromBackupLabel: "true",
+			}))
+			Expect(snapshotGet("weekly").Labels).To(Equal(map[string]string{"app": "web"}))
+		})
+	})
+
+	Describe("volumeSnapshotRestore", func() {
+		restore := func(name string) error {
+			protected := snapshot(name, "data", &ready, nil)
+			protected.Namespace = "restored"
+
+			return vrgInstance.volumeSnapshotRestore(protected, content("snapcontent-"+name, "handle-"+name),
+				"rbd-replicated")
+		}
+
+		It("recreates a snapshot bound to a content of its handle named after its UID", func() {
+			Expect(restore("daily")).To(Succeed())
+
+			restored := &snapv1.VolumeSnapshot{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: "restored", Name: "daily"}, restored)).
+				To(Succeed())
+			Expect(*restored.Spec.Source.VolumeSnapshotContentName).To(Equal("ramen-snapcontent-daily"))
+
+			restoredContent := &snapv1.VolumeSnapshotContent{}
+			Expect(c.Get(context.TODO(), types.NamespacedName{Name: "ramen-snapcontent-daily"}, restoredContent)).
+				To(Succeed())
+			Expect(*restoredContent.Spec.Source.SnapshotHandle).To(Equal("handle-daily"))
+			Expect(restoredContent.Spec.DeletionPolicy).To(Equal(snapv1.VolumeSnapshotContentRetain))
+			Expect(restoredContent.Spec.VolumeSnapshotRef.Namespace).To(Equal("restored"))
+
+			Expect(c.Delete(context.TODO(), restored)).To(Succeed())
+			Expect(restore("daily")).To(Succeed())
+		})
+
+		It("fails to recreate a snapshot whose content name exists for another snapshot handle", func() {
+			other := content("ramen-snapcontent-daily", "handle-other")
+			other.Spec.VolumeSnapshotRef = corev1.ObjectReference{Namespace: "restored", Name: "daily"}
+			Expect(c.Create(context.TODO(), other)).To(Succeed())
+
+			Expect(restore("daily")).To(MatchError(ContainSubstring("exists for another snapshot handle")))
+		})
+
+		It("skips a snapshot that exists", func() {
+			existing := snapshot("daily", "data", &ready, nil)
+			existing.Namespace = "restored"
+			Expect(c.Create(context.TODO(), existing)).To(Succeed())
+
+			Expect(restore("daily")).To(Succeed())
+			Expect(c.Get(context.TODO(), types.NamespacedName{Name: "ramen-snapcontent-daily"},
+				&snapv1.VolumeSnapshotContent{})).ToNot(Succeed())
+		})
+	})
+})
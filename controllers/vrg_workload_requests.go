@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+)
+
+// updateWorkloadRequests records the resource requests of the workload of a primary VRG in its status, for the hub
+// to check that a failover cluster is able to host the workload
+func (v *VRGInstance) updateWorkloadRequests() {
+	if v.instance.Spec.ReplicationState != ramen.Primary {
+		return
+	}
+
+	requests, err := v.workloadRequests()
+	if err != nil {
+		v.log.Info("Workload requests get failed", "error", err)
+
+		return
+	}
+
+	if !resourceListsEqual(requests, v.instance.Status.WorkloadRequests) {
+		v.instance.Status.WorkloadRequests = requests
+	}
+}
+
+// workloadRequests returns the cpu and memory requested by the running pods of the protected namespaces, and the
+// storage requested by the protected PVCs
+func (v *VRGInstance) workloadRequests() (corev1.ResourceList, error) {
+	requests := corev1.ResourceList{}
+
+	for _, namespace := range v.workloadNamespaces() {
+		pods := &corev1.PodList{}
+		if err := v.reconciler.List(v.ctx, pods, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s (%w)", namespace, err)
+		}
+
+		for idx := range pods.Items {
+			pod := &pods.Items[idx]
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+
+			for cidx := range pod.Spec.Containers {
+				resourceListAdd(requests, pod.Spec.Containers[cidx].Resources.Requests,
+					corev1.ResourceCPU, corev1.ResourceMemory)
+			}
+		}
+	}
+
+	for idx := range v.instance.Status.ProtectedPVCs {
+		resourceListAdd(requests, v.instance.Status.ProtectedPVCs[idx].Resources.Requests, corev1.ResourceStorage)
+	}
+
+	return requests, nil
+}
+
+func resourceListAdd(sum, addend corev1.ResourceList, names ...corev1.ResourceName) {
+	for _, name := range names {
+		quantity, ok := addend[name]
+		if !ok {
+			continue
+		}
+
+		total := sum[name]
+		total.Add(quantity)
+		sum[name] = total
+	}
+}
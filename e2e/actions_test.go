@@ -5,9 +5,12 @@ package e2e_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ramendr/ramen/e2e/dractions"
 	"github.com/ramendr/ramen/e2e/testcontext"
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
 )
 
 func DeployAction(t *testing.T) {
@@ -18,6 +21,12 @@ func DeployAction(t *testing.T) {
 
 	if err := testCtx.Deployer.Deploy(testCtx.Workload); err != nil {
 		t.Error(err)
+
+		return
+	}
+
+	if err := dractions.ValidateWorkload(testCtx.Workload, testCtx.Deployer); err != nil {
+		t.Error(err)
 	}
 }
 
@@ -38,9 +47,28 @@ func FailoverAction(t *testing.T) {
 		t.Error(err)
 	}
 
+	rpo, err := dractions.DataSyncGap(testCtx.Workload, testCtx.Deployer)
+	if err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	start := time.Now()
+
 	if err := dractions.Failover(testCtx.Workload, testCtx.Deployer); err != nil {
 		t.Error(err)
+
+		return
 	}
+
+	if err := dractions.ValidateWorkload(testCtx.Workload, testCtx.Deployer); err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	checkBudget(t, testCtx.Workload, time.Since(start), rpo)
 }
 
 func RelocateAction(t *testing.T) {
@@ -49,9 +77,28 @@ func RelocateAction(t *testing.T) {
 		t.Error(err)
 	}
 
+	rpo, err := dractions.DataSyncGap(testCtx.Workload, testCtx.Deployer)
+	if err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	start := time.Now()
+
 	if err := dractions.Relocate(testCtx.Workload, testCtx.Deployer); err != nil {
 		t.Error(err)
+
+		return
+	}
+
+	if err := dractions.ValidateWorkload(testCtx.Workload, testCtx.Deployer); err != nil {
+		t.Error(err)
+
+		return
 	}
+
+	checkBudget(t, testCtx.Workload, time.Since(start), rpo)
 }
 
 func DisableAction(t *testing.T) {
@@ -75,3 +122,21 @@ func UndeployAction(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// checkBudget fails the test if the RTO or RPO of its action exceeds the workload's budget, or only logs it if budgets
+// are configured to be flaky
+func checkBudget(t *testing.T, w workloads.Workload, rto, rpo time.Duration) {
+	t.Helper()
+
+	t.Logf("RTO %v RPO %v", rto, rpo)
+
+	for _, exceeded := range util.GetBudget(w.GetName()).Exceeded(rto, rpo) {
+		if util.BudgetsFlaky() {
+			t.Logf("flaky: %s", exceeded)
+
+			continue
+		}
+
+		t.Error(exceeded)
+	}
+}
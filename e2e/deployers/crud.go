@@ -120,8 +120,8 @@ func deletePlacement(name, namespace string) error {
 	return nil
 }
 
-func createSubscription(s Subscription, w workloads.Workload) error {
-	name := GetCombinedName(s, w)
+func createSubscription(d Deployer, w workloads.Workload, placementKind string) error {
+	name := GetCombinedName(d, w)
 	namespace := name
 
 	labels := make(map[string]string)
@@ -132,7 +132,7 @@ func createSubscription(s Subscription, w workloads.Workload) error {
 	annotations["apps.open-cluster-management.io/github-path"] = w.GetPath()
 
 	placementRef := corev1.ObjectReference{
-		Kind: "Placement",
+		Kind: placementKind,
 		Name: name,
 	}
 
@@ -164,8 +164,8 @@ func createSubscription(s Subscription, w workloads.Workload) error {
 	return nil
 }
 
-func deleteSubscription(s Subscription, w workloads.Workload) error {
-	name := GetCombinedName(s, w)
+func deleteSubscription(d Deployer, w workloads.Workload) error {
+	name := GetCombinedName(d, w)
 	namespace := name
 
 	subscription := &subscriptionv1.Subscription{
@@ -187,6 +187,57 @@ func deleteSubscription(s Subscription, w workloads.Workload) error {
 	return nil
 }
 
+func createPlacementRule(name, namespace string) error {
+	labels := make(map[string]string)
+	labels[AppLabelKey] = name
+
+	var numClusters int32 = 1
+	placementRule := &placementrulev1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: placementrulev1.PlacementRuleSpec{
+			ClusterReplicas: &numClusters,
+			ClusterConditions: []placementrulev1.ClusterConditionFilter{
+				{Type: "ManagedClusterConditionAvailable", Status: metav1.ConditionTrue},
+			},
+		},
+	}
+
+	err := util.Ctx.Hub.CtrlClient.Create(context.Background(), placementRule)
+	if err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+
+		util.Ctx.Log.Info("placementRule " + placementRule.Name + " already Exists")
+	}
+
+	return nil
+}
+
+func deletePlacementRule(name, namespace string) error {
+	placementRule := &placementrulev1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	err := util.Ctx.Hub.CtrlClient.Delete(context.Background(), placementRule)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		util.Ctx.Log.Info("placementRule " + name + " not found")
+	}
+
+	return nil
+}
+
 func GetCombinedName(d Deployer, w workloads.Workload) string {
 	return strings.ToLower(d.GetName() + "-" + w.GetName() + "-" + w.GetAppName())
 }
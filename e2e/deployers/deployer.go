@@ -16,3 +16,18 @@ type Deployer interface {
 
 	GetName() string
 }
+
+const (
+	PlacementKindPlacement     = "Placement"
+	PlacementKindPlacementRule = "PlacementRule"
+)
+
+// GetPlacementKind returns the kind of the placement of the workloads of a deployer
+func GetPlacementKind(d Deployer) string {
+	switch d.(type) {
+	case PlacementRuleSubscription, *PlacementRuleSubscription:
+		return PlacementKindPlacementRule
+	default:
+		return PlacementKindPlacement
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deployers
+
+import (
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
+	subscriptionv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// PlacementRuleSubscription deploys a workload with a Subscription placed by a PlacementRule, the deprecated
+// placement API, to cover the code paths of ramen for it until they are removed
+type PlacementRuleSubscription struct{}
+
+func (s PlacementRuleSubscription) GetName() string {
+	return "PlacementRuleSubscription"
+}
+
+func (s PlacementRuleSubscription) Deploy(w workloads.Workload) error {
+	util.Ctx.Log.Info("enter Deploy " + w.GetName() + "/" + s.GetName())
+
+	name := GetCombinedName(s, w)
+	namespace := name
+
+	err := util.CreateNamespace(util.Ctx.Hub.CtrlClient, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = createPlacementRule(name, namespace)
+	if err != nil {
+		return err
+	}
+
+	err = createSubscription(s, w, PlacementKindPlacementRule)
+	if err != nil {
+		return err
+	}
+
+	return waitSubscriptionPhase(namespace, name, subscriptionv1.SubscriptionPropagated)
+}
+
+func (s PlacementRuleSubscription) Undeploy(w workloads.Workload) error {
+	util.Ctx.Log.Info("enter Undeploy " + w.GetName() + s.GetName())
+
+	name := GetCombinedName(s, w)
+	namespace := name
+
+	err := deleteSubscription(s, w)
+	if err != nil {
+		return err
+	}
+
+	err = deletePlacementRule(name, namespace)
+	if err != nil {
+		return err
+	}
+
+	return util.DeleteNamespace(util.Ctx.Hub.CtrlClient, namespace)
+}
@@ -46,7 +46,7 @@ func (s Subscription) Deploy(w workloads.Workload) error {
 		return err
 	}
 
-	err = createSubscription(s, w)
+	err = createSubscription(s, w, PlacementKindPlacement)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/ramendr/ramen/e2e/dractions"
+	"github.com/ramendr/ramen/e2e/testcontext"
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
+)
+
+// disruptiveTestContext returns the workload and deployer of the disruptive suite: the deployment for the first
+// storage, with the first deployer
+func disruptiveTestContext() testcontext.TestContext {
+	w := deployment.ForStorage(util.GetStorages()[0])
+
+	dw, _ := w.(workloads.Deployment)
+	dw.Name = "Disruptive"
+
+	return testcontext.TestContext{Workload: dw, Deployer: Deployers[0]}
+}
+
+// Disruptive restarts the hub operator in the middle of a failover, and rolls out the cluster operators and restarts
+// the hub operator in the middle of a relocate, as an upgrade in flight would, and checks that the actions complete
+// once the operators resume reconciling them
+func Disruptive(t *testing.T) {
+	t.Helper()
+
+	if !util.DisruptiveEnabled() {
+		t.Skip("disruptive suite not enabled")
+	}
+
+	c := disruptiveTestContext()
+	testcontext.AddTestContext(t.Name(), c.Workload, c.Deployer)
+
+	defer testcontext.DeleteTestContext(t.Name(), c.Workload, c.Deployer)
+
+	for _, step := range []struct {
+		name   string
+		action func(t *testing.T)
+	}{
+		{dractions.ActionDeploy, DeployAction},
+		{dractions.ActionEnable, EnableAction},
+		{"FailoverHubOperatorRestart", FailoverHubOperatorRestartAction},
+		{"RelocateOperatorsUpgrade", RelocateOperatorsUpgradeAction},
+		{dractions.ActionDisable, DisableAction},
+		{dractions.ActionUndeploy, UndeployAction},
+	} {
+		if !t.Run(step.name, step.action) {
+			t.Fatal(step.name + " failed")
+		}
+	}
+}
+
+func FailoverHubOperatorRestartAction(t *testing.T) {
+	testCtx, err := testcontext.GetTestContext(t.Name())
+	if err != nil {
+		t.Error(err)
+	}
+
+	if err := dractions.FailoverDisrupted(testCtx.Workload, testCtx.Deployer, util.RestartRamenHubOperator); err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	if err := dractions.ValidateWorkload(testCtx.Workload, testCtx.Deployer); err != nil {
+		t.Error(err)
+	}
+}
+
+func RelocateOperatorsUpgradeAction(t *testing.T) {
+	testCtx, err := testcontext.GetTestContext(t.Name())
+	if err != nil {
+		t.Error(err)
+	}
+
+	upgrade := func() error {
+		for _, cluster := range []util.Cluster{util.Ctx.C1, util.Ctx.C2} {
+			if err := util.RolloutRamenClusterOperator(cluster); err != nil {
+				return err
+			}
+		}
+
+		return util.RestartRamenHubOperator()
+	}
+
+	if err := dractions.RelocateDisrupted(testCtx.Workload, testCtx.Deployer, upgrade); err != nil {
+		t.Error(err)
+
+		return
+	}
+
+	if err := dractions.ValidateWorkload(testCtx.Workload, testCtx.Deployer); err != nil {
+		t.Error(err)
+	}
+}
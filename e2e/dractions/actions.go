@@ -36,6 +36,10 @@ func EnableProtection(w workloads.Workload, d deployers.Deployer) error {
 	placementName := name
 	drpcName := name
 
+	if deployers.GetPlacementKind(d) == deployers.PlacementKindPlacementRule {
+		return enablePlacementRuleProtection(name, namespace, drPolicyName, appname)
+	}
+
 	placement, placementDecisionName, err := waitPlacementDecision(util.Ctx.Hub.CtrlClient, namespace, placementName)
 	if err != nil {
 		return err
@@ -68,7 +72,8 @@ func EnableProtection(w workloads.Workload, d deployers.Deployer) error {
 
 	util.Ctx.Log.Info("create drpc " + drpcName)
 
-	drpc := generateDRPC(name, namespace, clusterName, drPolicyName, placementName, appname)
+	drpc := generateDRPC(name, namespace, clusterName, drPolicyName, deployers.PlacementKindPlacement, placementName,
+		appname)
 	if err = createDRPC(util.Ctx.Hub.CtrlClient, drpc); err != nil {
 		return err
 	}
@@ -101,6 +106,10 @@ func DisableProtection(w workloads.Workload, d deployers.Deployer) error {
 		return err
 	}
 
+	if deployers.GetPlacementKind(d) == deployers.PlacementKindPlacementRule {
+		return disablePlacementRuleProtection(namespace, placementName)
+	}
+
 	util.Ctx.Log.Info("get placement " + placementName)
 
 	placement, err := getPlacement(client, namespace, placementName)
@@ -115,6 +124,12 @@ func DisableProtection(w workloads.Workload, d deployers.Deployer) error {
 }
 
 func Failover(w workloads.Workload, d deployers.Deployer) error {
+	return FailoverDisrupted(w, d, nil)
+}
+
+// FailoverDisrupted fails over a workload, and runs disrupt, if not nil, once the failover is requested, before
+// waiting for it to complete
+func FailoverDisrupted(w workloads.Workload, d deployers.Deployer, disrupt func() error) error {
 	util.Ctx.Log.Info("enter DRActions Failover")
 
 	name := GetCombinedName(d, w)
@@ -148,7 +163,7 @@ func Failover(w workloads.Workload, d deployers.Deployer) error {
 		return err
 	}
 
-	targetCluster, err := getTargetCluster(client, namespace, name, drpolicy)
+	targetCluster, err := getTargetCluster(client, deployers.GetPlacementKind(d), namespace, name, drpolicy)
 	if err != nil {
 		return err
 	}
@@ -164,6 +179,12 @@ func Failover(w workloads.Workload, d deployers.Deployer) error {
 		return err
 	}
 
+	if disrupt != nil {
+		if err := disrupt(); err != nil {
+			return err
+		}
+	}
+
 	return waitDRPC(client, namespace, name, "FailedOver")
 }
 
@@ -172,6 +193,12 @@ func Failover(w workloads.Workload, d deployers.Deployer) error {
 // Relocate to Primary in DRPolicy as the PrimaryCluster
 // Update DRPC
 func Relocate(w workloads.Workload, d deployers.Deployer) error {
+	return RelocateDisrupted(w, d, nil)
+}
+
+// RelocateDisrupted relocates a workload, and runs disrupt, if not nil, once the relocate is requested, before
+// waiting for it to complete
+func RelocateDisrupted(w workloads.Workload, d deployers.Deployer, disrupt func() error) error {
 	util.Ctx.Log.Info("enter DRActions Relocate")
 
 	name := GetCombinedName(d, w)
@@ -205,7 +232,7 @@ func Relocate(w workloads.Workload, d deployers.Deployer) error {
 		return err
 	}
 
-	targetCluster, err := getTargetCluster(client, namespace, name, drpolicy)
+	targetCluster, err := getTargetCluster(client, deployers.GetPlacementKind(d), namespace, name, drpolicy)
 	if err != nil {
 		return err
 	}
@@ -222,9 +249,57 @@ func Relocate(w workloads.Workload, d deployers.Deployer) error {
 		return err
 	}
 
+	if disrupt != nil {
+		if err := disrupt(); err != nil {
+			return err
+		}
+	}
+
 	return waitDRPC(client, namespace, name, "Relocated")
 }
 
+// ValidateWorkload runs the validators of the workload, if it has any, on the cluster it is placed on, until they
+// pass or time out
+func ValidateWorkload(w workloads.Workload, d deployers.Deployer) error {
+	if len(workloads.Validators(w)) == 0 {
+		return nil
+	}
+
+	util.Ctx.Log.Info("enter DRActions ValidateWorkload " + w.GetName() + "/" + d.GetName())
+
+	name := GetCombinedName(d, w)
+	namespace := name
+
+	clusterName, err := getCurrentCluster(util.Ctx.Hub.CtrlClient, deployers.GetPlacementKind(d), namespace, name)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := util.GetManagedCluster(clusterName)
+	if err != nil {
+		return err
+	}
+
+	return waitWorkloadValid(w, cluster, namespace)
+}
+
+// DataSyncGap returns the age of the last sync of the data of the workload, the data a failover or relocate requested
+// now may lose, or zero if the DRPC did not report a sync
+func DataSyncGap(w workloads.Workload, d deployers.Deployer) (time.Duration, error) {
+	name := GetCombinedName(d, w)
+
+	drpc, err := getDRPC(util.Ctx.Hub.CtrlClient, name, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if drpc.Status.LastGroupSyncTime == nil {
+		return 0, nil
+	}
+
+	return time.Since(drpc.Status.LastGroupSyncTime.Time), nil
+}
+
 func GetCombinedName(d deployers.Deployer, w workloads.Workload) string {
 	return deployers.GetCombinedName(d, w)
 }
@@ -5,6 +5,7 @@ package dractions
 
 import (
 	"context"
+	"strings"
 
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
@@ -12,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,6 +29,22 @@ func getPlacement(ctrlClient client.Client, namespace, name string) (*clusterv1b
 	return placement, nil
 }
 
+func getPlacementRule(ctrlClient client.Client, namespace, name string) (*placementrulev1.PlacementRule, error) {
+	placementRule := &placementrulev1.PlacementRule{}
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+
+	err := ctrlClient.Get(context.Background(), key, placementRule)
+	if err != nil {
+		return nil, err
+	}
+
+	return placementRule, nil
+}
+
+func updatePlacementRule(ctrlClient client.Client, placementRule *placementrulev1.PlacementRule) error {
+	return ctrlClient.Update(context.Background(), placementRule)
+}
+
 func updatePlacement(ctrlClient client.Client, placement *clusterv1beta1.Placement) error {
 	err := ctrlClient.Update(context.Background(), placement)
 	if err != nil {
@@ -114,7 +132,8 @@ func getDRPolicy(ctrlClient client.Client, name string) (*ramen.DRPolicy, error)
 	return drpolicy, nil
 }
 
-func generateDRPC(name, namespace, clusterName, drPolicyName, placementName, appname string) *ramen.DRPlacementControl {
+func generateDRPC(name, namespace, clusterName, drPolicyName, placementKind, placementName, appname string,
+) *ramen.DRPlacementControl {
 	drpc := &ramen.DRPlacementControl{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DRPlacementControl",
@@ -131,7 +150,7 @@ func generateDRPC(name, namespace, clusterName, drPolicyName, placementName, app
 				Name: drPolicyName,
 			},
 			PlacementRef: v1.ObjectReference{
-				Kind: "placement",
+				Kind: strings.ToLower(placementKind),
 				Name: placementName,
 			},
 			PVCSelector: metav1.LabelSelector{
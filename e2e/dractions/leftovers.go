@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dractions
+
+import (
+	"context"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/e2e/deployers"
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+)
+
+// Actions of the test flow of a workload, in order
+const (
+	ActionDeploy   = "Deploy"
+	ActionEnable   = "Enable"
+	ActionFailover = "Failover"
+	ActionRelocate = "Relocate"
+	ActionDisable  = "Disable"
+	ActionUndeploy = "Undeploy"
+)
+
+// Leftover is what is left on the hub of a workload whose test flow was interrupted, found by the names the flow
+// gives its namespace, placement and DRPC
+type Leftover struct {
+	Namespace     bool
+	Placement     *clusterv1beta1.Placement
+	PlacementRule *placementrulev1.PlacementRule
+	DRPC          *ramen.DRPlacementControl
+}
+
+// Protected returns whether the workload's DRPC is left, or its placement is left handed over to ramen by the enabling
+// of its protection
+func (l Leftover) Protected() bool {
+	return l.DRPC != nil ||
+		(l.Placement != nil && l.Placement.Annotations[OcmSchedulingDisable] != "") ||
+		(l.PlacementRule != nil && l.PlacementRule.Spec.SchedulerName == RamenScheduler)
+}
+
+// placed returns whether the workload's placement is left
+func (l Leftover) placed() bool {
+	return l.Placement != nil || l.PlacementRule != nil
+}
+
+func GetLeftover(w workloads.Workload, d deployers.Deployer) (Leftover, error) {
+	name := GetCombinedName(d, w)
+	client := util.Ctx.Hub.CtrlClient
+	leftover := Leftover{}
+
+	err := client.Get(context.Background(), types.NamespacedName{Name: name}, &corev1.Namespace{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return leftover, nil
+		}
+
+		return leftover, err
+	}
+
+	leftover.Namespace = true
+
+	if leftover.Placement, err = getPlacement(client, name, name); err != nil && !errors.IsNotFound(err) {
+		return leftover, err
+	}
+
+	if leftover.PlacementRule, err = getPlacementRule(client, name, name); err != nil && !errors.IsNotFound(err) {
+		return leftover, err
+	}
+
+	if leftover.DRPC, err = getDRPC(client, name, name); err != nil && !errors.IsNotFound(err) {
+		return leftover, err
+	}
+
+	return leftover, nil
+}
+
+// ResumeAction returns the action to resume the test flow of a workload from: the action after the last that its DRPC
+// completes, once it completes it, or Deploy, whose steps are idempotent, if the workload is not protected. A workload
+// whose placement is annotated without a DRPC may have been enabled or disabled, and is resumed by disabling it.
+func ResumeAction(w workloads.Workload, d deployers.Deployer) (string, error) {
+	leftover, err := GetLeftover(w, d)
+	if err != nil {
+		return "", err
+	}
+
+	if !leftover.Protected() {
+		return ActionDeploy, nil
+	}
+
+	drpc := leftover.DRPC
+	if drpc == nil || !drpc.GetDeletionTimestamp().IsZero() {
+		return ActionDisable, nil
+	}
+
+	client := util.Ctx.Hub.CtrlClient
+
+	util.Ctx.Log.Info("drpc " + drpc.Name + " left with action " + string(drpc.Spec.Action))
+
+	switch drpc.Spec.Action {
+	case ramen.ActionFailover:
+		return ActionRelocate, waitDRPC(client, drpc.Namespace, drpc.Name, string(ramen.FailedOver))
+	case ramen.ActionRelocate:
+		return ActionDisable, waitDRPC(client, drpc.Namespace, drpc.Name, string(ramen.Relocated))
+	default:
+		return ActionFailover, waitDRPCReady(client, drpc.Namespace, drpc.Name)
+	}
+}
+
+// CleanupLeftover disables the protection of a workload and undeploys it, if anything is left of it
+func CleanupLeftover(w workloads.Workload, d deployers.Deployer) error {
+	leftover, err := GetLeftover(w, d)
+	if err != nil {
+		return err
+	}
+
+	if !leftover.Namespace {
+		return nil
+	}
+
+	util.Ctx.Log.Info("cleanup leftover " + GetCombinedName(d, w))
+
+	if leftover.Protected() {
+		if leftover.placed() {
+			if err := DisableProtection(w, d); err != nil {
+				return err
+			}
+		} else {
+			name := GetCombinedName(d, w)
+			if err := deleteDRPC(util.Ctx.Hub.CtrlClient, name, name); err != nil {
+				return err
+			}
+
+			if err := waitDRPCDeleted(util.Ctx.Hub.CtrlClient, name, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return d.Undeploy(w)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package dractions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ramendr/ramen/e2e/deployers"
+	"github.com/ramendr/ramen/e2e/util"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RamenScheduler is the scheduler of a PlacementRule whose decisions are made by ramen for its DRPC
+const RamenScheduler = "ramen"
+
+// enablePlacementRuleProtection protects a workload placed by a PlacementRule: once the PlacementRule is scheduled,
+// its scheduling is handed over to ramen, and a DRPC is created that prefers the cluster it is scheduled to
+func enablePlacementRuleProtection(name, namespace, drPolicyName, appname string) error {
+	client := util.Ctx.Hub.CtrlClient
+
+	placementRule, clusterName, err := waitPlacementRuleDecision(client, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	util.Ctx.Log.Info("update placementRule " + name + " scheduler to " + RamenScheduler)
+
+	placementRule.Spec.SchedulerName = RamenScheduler
+	if err := updatePlacementRule(client, placementRule); err != nil {
+		return err
+	}
+
+	util.Ctx.Log.Info("create drpc " + name)
+
+	drpc := generateDRPC(name, namespace, clusterName, drPolicyName, deployers.PlacementKindPlacementRule, name,
+		appname)
+	if err := createDRPC(client, drpc); err != nil {
+		return err
+	}
+
+	return waitDRPCReady(client, namespace, name)
+}
+
+// disablePlacementRuleProtection hands the scheduling of the PlacementRule of a workload whose DRPC is deleted back
+// to the default scheduler
+func disablePlacementRuleProtection(namespace, name string) error {
+	client := util.Ctx.Hub.CtrlClient
+
+	util.Ctx.Log.Info("get placementRule " + name)
+
+	placementRule, err := getPlacementRule(client, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	placementRule.Spec.SchedulerName = ""
+
+	util.Ctx.Log.Info("update placementRule " + name + " scheduler to default")
+
+	return updatePlacementRule(client, placementRule)
+}
+
+// return placementRule object, the cluster it is scheduled to, error
+func waitPlacementRuleDecision(client client.Client, namespace, name string,
+) (*placementrulev1.PlacementRule, string, error) {
+	startTime := time.Now()
+
+	for {
+		placementRule, err := getPlacementRule(client, namespace, name)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if len(placementRule.Status.Decisions) > 0 && placementRule.Status.Decisions[0].ClusterName != "" {
+			clusterName := placementRule.Status.Decisions[0].ClusterName
+			util.Ctx.Log.Info("placementRule " + name + " clusterName: " + clusterName)
+
+			return placementRule, clusterName, nil
+		}
+
+		if time.Since(startTime) > time.Second*time.Duration(util.Timeout) {
+			return nil, "", fmt.Errorf("could not get placementRule decision before timeout")
+		}
+
+		util.Ctx.Log.Info(fmt.Sprintf("could not get placementRule decision, retry in %v seconds", util.TimeInterval))
+		time.Sleep(time.Second * time.Duration(util.TimeInterval))
+	}
+}
@@ -4,11 +4,14 @@
 package dractions
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/e2e/deployers"
 	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"open-cluster-management.io/api/cluster/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -130,7 +133,13 @@ func waitDRPCPhase(client client.Client, namespace string, name string, phase st
 	}
 }
 
-func getCurrentCluster(client client.Client, namespace string, placementName string) (string, error) {
+func getCurrentCluster(client client.Client, placementKind, namespace string, placementName string) (string, error) {
+	if placementKind == deployers.PlacementKindPlacementRule {
+		_, clusterName, err := waitPlacementRuleDecision(client, namespace, placementName)
+
+		return clusterName, err
+	}
+
 	_, placementDecisionName, err := waitPlacementDecision(client, namespace, placementName)
 	if err != nil {
 		return "", err
@@ -147,8 +156,9 @@ func getCurrentCluster(client client.Client, namespace string, placementName str
 	return clusterName, nil
 }
 
-func getTargetCluster(client client.Client, namespace, placementName string, drpolicy *ramen.DRPolicy) (string, error) {
-	currentCluster, err := getCurrentCluster(client, namespace, placementName)
+func getTargetCluster(client client.Client, placementKind, namespace, placementName string, drpolicy *ramen.DRPolicy,
+) (string, error) {
+	currentCluster, err := getCurrentCluster(client, placementKind, namespace, placementName)
 	if err != nil {
 		return "", err
 	}
@@ -198,3 +208,24 @@ func waitDRPCDeleted(client client.Client, namespace string, name string) error
 		time.Sleep(time.Second * time.Duration(util.TimeInterval))
 	}
 }
+
+func waitWorkloadValid(w workloads.Workload, cluster util.Cluster, namespace string) error {
+	startTime := time.Now()
+
+	for {
+		err := workloads.Validate(context.Background(), w, cluster, namespace)
+		if err == nil {
+			util.Ctx.Log.Info("workload " + w.GetName() + " is valid on cluster " + cluster.Name)
+
+			return nil
+		}
+
+		if time.Since(startTime) > time.Second*time.Duration(util.Timeout) {
+			return fmt.Errorf("workload %s is not valid before timeout of %v: %w", w.GetName(), util.Timeout, err)
+		}
+
+		util.Ctx.Log.Info(fmt.Sprintf("workload %s is not valid yet, retry in %v seconds: %v", w.GetName(),
+			util.TimeInterval, err))
+		time.Sleep(time.Second * time.Duration(util.TimeInterval))
+	}
+}
@@ -3,6 +3,32 @@
 # SPDX-FileCopyrightText: The RamenDR authors
 # SPDX-License-Identifier: Apache-2.0
 
+# Usage: e2e-rdr.sh [--bootstrap] [go test flags]
+#
+# With --bootstrap, start a local drenv environment, deploy and configure
+# ramen in it, and run the tests in it. The environment is $E2E_ENV, by default
+# regional DR with mock storage, with profile names prefixed by $NAME_PREFIX.
+
+set -e
+
+if [ "$1" = "--bootstrap" ]; then
+    shift
+
+    env_file=$(realpath "${E2E_ENV:-../test/envs/regional-dr-mock-storage.yaml}")
+    env_name=$(sed -n 's/^name: *"\{0,1\}\([^"]*\)"\{0,1\} *$/\1/p' "$env_file")
+    config_dir="$HOME/.config/drenv/${NAME_PREFIX}${env_name}"
+
+    echo "Starting environment $env_file..."
+    (cd ../test && drenv start --name-prefix "$NAME_PREFIX" "$env_file")
+
+    echo "Deploying ramen..."
+    ramenctl deploy --name-prefix "$NAME_PREFIX" "$env_file"
+    ramenctl config --name-prefix "$NAME_PREFIX" "$env_file"
+
+    cat config.yaml "$config_dir/config.yaml" > "$config_dir/e2e-config.yaml"
+    set -- -configfile "$config_dir/e2e-config.yaml" "$@"
+fi
+
 echo "Running tests..."
 
 go test -timeout 0 -v "$@"
@@ -7,47 +7,104 @@ import (
 	"testing"
 
 	"github.com/ramendr/ramen/e2e/deployers"
+	"github.com/ramendr/ramen/e2e/dractions"
 	"github.com/ramendr/ramen/e2e/testcontext"
+	"github.com/ramendr/ramen/e2e/util"
 	"github.com/ramendr/ramen/e2e/workloads"
 )
 
-// Deployers = {"Subscription", "AppSet", "Imperative"}
+// Deployers = {"Subscription", "PlacementRuleSubscription", "AppSet", "Imperative"}
 // Workloads = {"Deployment", "STS", "DaemonSet"}
 // Classes   = {"rbd", "cephfs"}
 
+// deployment is tested with each storage, using the path of the storage
 var deployment = &workloads.Deployment{
-	Path:     "workloads/deployment/k8s-regional-rbd",
+	Path:     "workloads/deployment/k8s-regional",
 	Revision: "main",
 	AppName:  "busybox",
 	Name:     "Deployment",
+	Validators: []workloads.Validator{
+		workloads.DeploymentAvailable("busybox"),
+	},
+}
+
+// windowsDeployment is a Windows workload using an SMB CSI volume, run only if enabled in the configuration
+var windowsDeployment = &workloads.Deployment{
+	Path:     "workloads/deployment/k8s-regional-smb-windows",
+	Revision: "main",
+	AppName:  "windows-busybox",
+	Name:     "WindowsDeployment",
 }
 
 var Workloads = []workloads.Workload{deployment}
 
 var subscription = &deployers.Subscription{}
 
+// placementRuleSubscription covers the deprecated PlacementRule code paths until they are removed
+var placementRuleSubscription = &deployers.PlacementRuleSubscription{}
+
 // appset := &deployers.ApplicationSet{}
 // Deployers := []deployers.Deployer{subscription, appset}
 
-var Deployers = []deployers.Deployer{subscription}
+var Deployers = []deployers.Deployer{subscription, placementRuleSubscription}
+
+// matrixCell is a workload tested with every deployer, the variant of a workload for a storage
+type matrixCell struct {
+	workload workloads.Workload
+	// baseName is the name of the workload the variant is of, that skip rules match
+	baseName string
+	storage  util.Storage
+}
+
+// exhaustiveMatrix returns the workloads the exhaustive suite tests with every deployer: a variant of each workload
+// for each storage, and the windows workload if enabled
+func exhaustiveMatrix() []matrixCell {
+	cells := []matrixCell{}
+
+	for _, storage := range util.GetStorages() {
+		for _, w := range Workloads {
+			if sw, ok := w.(workloads.StorageWorkload); ok {
+				cells = append(cells, matrixCell{workload: sw.ForStorage(storage), baseName: w.GetName(),
+					storage: storage})
+			}
+		}
+	}
+
+	if util.WindowsWorkloadsEnabled() {
+		cells = append(cells, matrixCell{workload: windowsDeployment, baseName: windowsDeployment.GetName()})
+	}
+
+	return cells
+}
 
 func Exhaustive(t *testing.T) {
 	t.Helper()
 	t.Parallel()
 
-	for _, workload := range Workloads {
+	for _, cell := range exhaustiveMatrix() {
 		for _, deployer := range Deployers {
 			// assign workload and deployer to a local variable to avoid parallel test issue
 			// see https://go.dev/wiki/CommonMistakes
-			w := workload
+			c := cell
+			w := cell.workload
 			d := deployer
 
 			t.Run(w.GetName(), func(t *testing.T) {
 				t.Parallel()
 				t.Run(d.GetName(), func(t *testing.T) {
+					if c.storage.Skipped(d.GetName(), c.baseName) {
+						t.Skipf("skipped for storage %s", c.storage.Name)
+					}
+
 					t.Parallel()
+					t.Logf("storage %s, class %s, DR mechanism %s", c.storage.Name, c.storage.StorageClass,
+						c.storage.DRMechanism)
 					testcontext.AddTestContext(t.Name(), w, d)
-					runTestFlow(t)
+					if resumeMode {
+						resumeTestFlow(t, w, d)
+					} else {
+						runTestFlow(t)
+					}
 					testcontext.DeleteTestContext(t.Name(), w, d)
 				})
 			})
@@ -55,30 +112,39 @@ func Exhaustive(t *testing.T) {
 	}
 }
 
+// testFlow is the flow of actions each workload is tested with
+var testFlow = []struct {
+	name   string
+	action func(t *testing.T)
+}{
+	{dractions.ActionDeploy, DeployAction},
+	{dractions.ActionEnable, EnableAction},
+	{dractions.ActionFailover, FailoverAction},
+	{dractions.ActionRelocate, RelocateAction},
+	{dractions.ActionDisable, DisableAction},
+	{dractions.ActionUndeploy, UndeployAction},
+}
+
 func runTestFlow(t *testing.T) {
 	t.Helper()
 
-	if !t.Run("Deploy", DeployAction) {
-		t.Fatal("Deploy failed")
-	}
-
-	if !t.Run("Enable", EnableAction) {
-		t.Fatal("Enable failed")
-	}
+	runTestFlowFrom(t, dractions.ActionDeploy)
+}
 
-	if !t.Run("Failover", FailoverAction) {
-		t.Fatal("Failover failed")
-	}
+// runTestFlowFrom runs the test flow from an action, skipping the actions before it
+func runTestFlowFrom(t *testing.T, from string) {
+	t.Helper()
 
-	if !t.Run("Relocate", RelocateAction) {
-		t.Fatal("Relocate failed")
-	}
+	started := false
 
-	if !t.Run("Disable", DisableAction) {
-		t.Fatal("Disable failed")
-	}
+	for _, step := range testFlow {
+		started = started || step.name == from
+		if !started {
+			continue
+		}
 
-	if !t.Run("Undeploy", UndeployAction) {
-		t.Fatal("Undeploy failed")
+		if !t.Run(step.name, step.action) {
+			t.Fatal(step.name + " failed")
+		}
 	}
 }
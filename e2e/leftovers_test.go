@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"testing"
+
+	"github.com/ramendr/ramen/e2e/deployers"
+	"github.com/ramendr/ramen/e2e/dractions"
+	"github.com/ramendr/ramen/e2e/testcontext"
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
+)
+
+// leftoverTestContexts returns the workloads and deployers of all the suites, whose leftovers an interrupted run may
+// have left
+func leftoverTestContexts() []testcontext.TestContext {
+	contexts := []testcontext.TestContext{}
+
+	for _, cell := range exhaustiveMatrix() {
+		for _, d := range Deployers {
+			contexts = append(contexts, testcontext.TestContext{Workload: cell.workload, Deployer: d})
+		}
+	}
+
+	contexts = append(contexts, disruptiveTestContext())
+
+	return append(contexts, scaleTestContexts(util.GetScaleApps())...)
+}
+
+// Cleanup tears down what an interrupted run left of the workloads of all the suites
+func Cleanup(t *testing.T) {
+	t.Helper()
+
+	cleanupLeftovers(t, leftoverTestContexts())
+}
+
+func cleanupLeftovers(t *testing.T, contexts []testcontext.TestContext) {
+	t.Helper()
+
+	for _, testCtx := range contexts {
+		c := testCtx
+
+		t.Run(dractions.GetCombinedName(c.Deployer, c.Workload), func(t *testing.T) {
+			t.Parallel()
+
+			if err := dractions.CleanupLeftover(c.Workload, c.Deployer); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// resumeTestFlow runs the test flow of a workload from where an interrupted run left it
+func resumeTestFlow(t *testing.T, w workloads.Workload, d deployers.Deployer) {
+	t.Helper()
+
+	action, err := dractions.ResumeAction(w, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("resuming from %s", action)
+
+	runTestFlowFrom(t, action)
+}
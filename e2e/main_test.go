@@ -14,8 +14,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
+var (
+	cleanupMode bool
+	resumeMode  bool
+)
+
 func init() {
 	flag.StringVar(&util.ConfigFile, "configfile", "", "Path to the config file")
+	flag.BoolVar(&cleanupMode, "cleanup", false, "Tear down what an interrupted run left, instead of running the suites")
+	flag.BoolVar(&resumeMode, "resume", false, "Resume the workloads from where an interrupted run left them")
 }
 
 func TestMain(m *testing.M) {
@@ -48,11 +55,23 @@ type testDef struct {
 
 var Suites = []testDef{
 	{"Exhaustive", Exhaustive},
+	{"Scale", Scale},
+	{"Disruptive", Disruptive},
 }
 
 func TestSuites(t *testing.T) {
 	util.Ctx.Log.Info(t.Name())
 
+	if cleanupMode {
+		t.Run("Cleanup", Cleanup)
+
+		if err := util.EnsureChannelDeleted(); err != nil {
+			t.Fatalf("failed to ensure channel deleted: %v", err)
+		}
+
+		return
+	}
+
 	if err := util.EnsureChannel(); err != nil {
 		t.Fatalf("failed to ensure channel: %v", err)
 	}
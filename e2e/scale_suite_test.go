@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package e2e_test
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ramendr/ramen/e2e/deployers"
+	"github.com/ramendr/ramen/e2e/dractions"
+	"github.com/ramendr/ramen/e2e/testcontext"
+	"github.com/ramendr/ramen/e2e/util"
+	"github.com/ramendr/ramen/e2e/workloads"
+)
+
+type scalePhase struct {
+	name   string
+	action func(w workloads.Workload, d deployers.Deployer) error
+}
+
+// scalePhases are run on all the apps together, each phase once the previous converged on all of them
+var scalePhases = []scalePhase{
+	{"Deploy", func(w workloads.Workload, d deployers.Deployer) error { return d.Deploy(w) }},
+	{"Enable", dractions.EnableProtection},
+	{"Failover", func(w workloads.Workload, d deployers.Deployer) error {
+		if err := dractions.Failover(w, d); err != nil {
+			return err
+		}
+
+		return dractions.ValidateWorkload(w, d)
+	}},
+	{"Disable", dractions.DisableProtection},
+	{"Undeploy", func(w workloads.Workload, d deployers.Deployer) error { return d.Undeploy(w) }},
+}
+
+// scaleTimings are the durations of the phases of each app
+type scaleTimings struct {
+	mutex     sync.Mutex
+	durations map[string]map[string]time.Duration
+}
+
+func (s *scaleTimings) add(app, phase string, duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.durations[app] == nil {
+		s.durations[app] = map[string]time.Duration{}
+	}
+
+	s.durations[app][phase] = duration
+}
+
+// Scale deploys the configured number of small apps across the deployers, enables their protection and fails them
+// over in bulk, to catch regressions of the scalability of the hub, and reports the time each phase took for each app.
+// Resumed, it tears down what an interrupted run left of its apps, and runs again.
+func Scale(t *testing.T) {
+	t.Helper()
+
+	apps := util.GetScaleApps()
+	if apps == 0 {
+		t.Skip("scale apps not configured")
+	}
+
+	contexts := scaleTestContexts(apps)
+
+	if resumeMode && !t.Run("Cleanup", func(t *testing.T) { cleanupLeftovers(t, contexts) }) {
+		t.Fatal("Cleanup failed")
+	}
+	timings := &scaleTimings{durations: map[string]map[string]time.Duration{}}
+
+	t.Cleanup(func() { scaleTimingsReport(t, timings) })
+
+	for _, phase := range scalePhases {
+		p := phase
+
+		if !t.Run(p.name, func(t *testing.T) {
+			for _, testCtx := range contexts {
+				c := testCtx
+				name := dractions.GetCombinedName(c.Deployer, c.Workload)
+
+				t.Run(name, func(t *testing.T) {
+					t.Parallel()
+
+					start := time.Now()
+
+					if err := p.action(c.Workload, c.Deployer); err != nil {
+						t.Error(err)
+
+						return
+					}
+
+					timings.add(name, p.name, time.Since(start))
+				})
+			}
+		}) {
+			t.Fatalf("%s failed", p.name)
+		}
+	}
+}
+
+// scaleTestContexts returns the apps of the scale suite, copies of the deployment workload for the first storage,
+// across the deployers
+func scaleTestContexts(apps int) []testcontext.TestContext {
+	contexts := make([]testcontext.TestContext, apps)
+
+	for i := range contexts {
+		w, _ := deployment.ForStorage(util.GetStorages()[0]).(workloads.Deployment)
+		w.Name = fmt.Sprintf("Scale-%03d", i)
+
+		contexts[i] = testcontext.TestContext{Workload: w, Deployer: Deployers[i%len(Deployers)]}
+	}
+
+	return contexts
+}
+
+// scaleTimingsReport logs the time each phase took for each app, and the average and maximum of each phase
+func scaleTimingsReport(t *testing.T, timings *scaleTimings) {
+	t.Helper()
+
+	appNames := make([]string, 0, len(timings.durations))
+	for app := range timings.durations {
+		appNames = append(appNames, app)
+	}
+
+	sort.Strings(appNames)
+
+	header := fmt.Sprintf("%-40s", "APP")
+	for _, phase := range scalePhases {
+		header += fmt.Sprintf(" %10s", phase.name)
+	}
+
+	t.Log(header)
+
+	for _, app := range appNames {
+		line := fmt.Sprintf("%-40s", app)
+		for _, phase := range scalePhases {
+			line += fmt.Sprintf(" %10v", timings.durations[app][phase.name].Round(time.Second))
+		}
+
+		t.Log(line)
+	}
+
+	for _, phase := range scalePhases {
+		var total, maximum time.Duration
+
+		count := 0
+
+		for _, app := range appNames {
+			duration, ok := timings.durations[app][phase.name]
+			if !ok {
+				continue
+			}
+
+			total += duration
+			count++
+
+			if duration > maximum {
+				maximum = duration
+			}
+		}
+
+		if count > 0 {
+			t.Logf("%s: %d apps, average %v, maximum %v", phase.name, count,
+				(total / time.Duration(count)).Round(time.Second), maximum.Round(time.Second))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Budget is the RTO and RPO that a failover or relocate of a workload must meet, where zero is no budget
+type Budget struct {
+	// RTO is the time from the request of an action until the workload is ready and valid on the target cluster
+	RTO time.Duration
+	// RPO is the age of the last sync of the workload's data when an action is requested
+	RPO time.Duration
+}
+
+type BudgetsConfig struct {
+	Budget `mapstructure:",squash"`
+	// Flaky logs the budgets that are exceeded, rather than failing the test
+	Flaky bool
+	// Workloads are budgets of workloads, by name, that replace the default budget
+	Workloads map[string]Budget
+}
+
+// GetBudget returns the budget of a workload
+func GetBudget(workloadName string) Budget {
+	// viper lower cases map keys
+	if budget, ok := config.Budgets.Workloads[strings.ToLower(workloadName)]; ok {
+		return budget
+	}
+
+	return config.Budgets.Budget
+}
+
+// BudgetsFlaky returns whether exceeded budgets are logged, rather than failing the test
+func BudgetsFlaky() bool {
+	return config.Budgets.Flaky
+}
+
+// Exceeded returns a description of each of the budget's objectives that an action exceeded
+func (b Budget) Exceeded(rto, rpo time.Duration) []string {
+	exceeded := []string{}
+
+	if b.RTO != 0 && rto > b.RTO {
+		exceeded = append(exceeded, fmt.Sprintf("RTO %v exceeds budget %v", rto, b.RTO))
+	}
+
+	if b.RPO != 0 && rpo > b.RPO {
+		exceeded = append(exceeded, fmt.Sprintf("RPO %v exceeds budget %v", rpo, b.RPO))
+	}
+
+	return exceeded
+}
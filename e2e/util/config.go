@@ -17,6 +17,19 @@ type TestConfig struct {
 	Clusters         map[string]struct {
 		KubeconfigPath string `mapstructure:"kubeconfigpath" required:"true"`
 	} `mapstructure:"clusters" required:"true"`
+	// Windows enables workloads that require Windows worker nodes and the SMB CSI driver on the managed clusters
+	Windows bool `mapstructure:"windows"`
+	// Budgets are the RTO and RPO that failovers and relocates must meet
+	Budgets BudgetsConfig
+	// Storages are the storage types the workloads are tested with
+	Storages []Storage
+	// Disruptive enables the disruptive suite, which restarts the ramen operators during failover and relocate
+	Disruptive bool
+	// Scale configures the scale suite
+	Scale struct {
+		// Apps is the number of apps the scale suite protects and fails over, or zero to skip it
+		Apps int
+	}
 }
 
 var config = &TestConfig{}
@@ -54,6 +67,10 @@ func ReadConfig(log *logr.Logger, configFile string) error {
 		return fmt.Errorf("failed to unmarshal config: %v", err)
 	}
 
+	if err := validateStorages(config.Storages); err != nil {
+		return fmt.Errorf("invalid storages: %w", err)
+	}
+
 	if config.Clusters["hub"].KubeconfigPath == "" {
 		return fmt.Errorf("failed to find hub cluster in configuration")
 	}
@@ -80,3 +97,15 @@ func GetChannelNamespace() string {
 func GetGitURL() string {
 	return config.GitURL
 }
+
+func WindowsWorkloadsEnabled() bool {
+	return config.Windows
+}
+
+func GetScaleApps() int {
+	return config.Scale.Apps
+}
+
+func DisruptiveEnabled() bool {
+	return config.Disruptive
+}
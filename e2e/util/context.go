@@ -27,6 +27,8 @@ import (
 var ConfigFile string
 
 type Cluster struct {
+	// Name is the cluster of the current context of the kubeconfig, the name of the managed cluster
+	Name         string
 	K8sClientSet *kubernetes.Clientset
 	CtrlClient   client.Client
 }
@@ -94,6 +96,31 @@ func setupClient(kubeconfigPath string) (*kubernetes.Clientset, client.Client, e
 	return k8sClientSet, ctrlClient, nil
 }
 
+func clusterName(kubeconfigPath string) (string, error) {
+	kubeconfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load kubeconfig (%s): %w", kubeconfigPath, err)
+	}
+
+	kubeContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return "", fmt.Errorf("failed to find current context in kubeconfig (%s)", kubeconfigPath)
+	}
+
+	return kubeContext.Cluster, nil
+}
+
+// GetManagedCluster returns the managed cluster of the given name
+func GetManagedCluster(name string) (Cluster, error) {
+	for _, cluster := range []Cluster{Ctx.C1, Ctx.C2} {
+		if cluster.Name == name {
+			return cluster, nil
+		}
+	}
+
+	return Cluster{}, fmt.Errorf("managed cluster %s not found in configuration", name)
+}
+
 func NewContext(log *logr.Logger, configFile string) (*Context, error) {
 	var err error
 
@@ -114,10 +141,20 @@ func NewContext(log *logr.Logger, configFile string) (*Context, error) {
 		return nil, fmt.Errorf("failed to create clients for c1 cluster: %w", err)
 	}
 
+	ctx.C1.Name, err = clusterName(config.Clusters["c1"].KubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get name of c1 cluster: %w", err)
+	}
+
 	ctx.C2.K8sClientSet, ctx.C2.CtrlClient, err = setupClient(config.Clusters["c2"].KubeconfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clients for c2 cluster: %w", err)
 	}
 
+	ctx.C2.Name, err = clusterName(config.Clusters["c2"].KubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get name of c2 cluster: %w", err)
+	}
+
 	return ctx, nil
 }
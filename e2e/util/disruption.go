@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	ramenHubOperatorLabelSelector = "app=ramen-hub"
+	ramenClusterOperatorName      = "ramen-dr-cluster-operator"
+)
+
+// RestartRamenHubOperator deletes the pods of the ramen hub operator, and waits until a new one is running
+func RestartRamenHubOperator() error {
+	client := Ctx.Hub.K8sClientSet
+
+	namespace, err := GetRamenNameSpace(client)
+	if err != nil {
+		return err
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: ramenHubOperatorLabelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list ramen hub operator pods: %w", err)
+	}
+
+	deleted := map[string]bool{}
+
+	for _, pod := range pods.Items {
+		Ctx.Log.Info("delete ramen hub operator pod " + pod.Name)
+
+		if err := client.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name,
+			metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("failed to delete ramen hub operator pod %s: %w", pod.Name, err)
+		}
+
+		deleted[pod.Name] = true
+	}
+
+	startTime := time.Now()
+
+	for {
+		isRunning, podName, err := CheckRamenHubPodRunningStatus(client)
+		if err != nil {
+			return err
+		}
+
+		if isRunning && !deleted[podName] {
+			Ctx.Log.Info("ramen hub operator pod " + podName + " is running")
+
+			return nil
+		}
+
+		if time.Since(startTime) > time.Second*time.Duration(Timeout) {
+			return fmt.Errorf("ramen hub operator is not running again before timeout of %v", Timeout)
+		}
+
+		Ctx.Log.Info(fmt.Sprintf("ramen hub operator is not running again, retry in %v seconds", TimeInterval))
+		time.Sleep(time.Second * time.Duration(TimeInterval))
+	}
+}
+
+// RolloutRamenClusterOperator restarts the ramen cluster operator of a cluster with a rollout of its deployment, as
+// an upgrade would, and waits until the rollout completes
+func RolloutRamenClusterOperator(cluster Cluster) error {
+	client := cluster.K8sClientSet
+
+	namespace, err := GetRamenNameSpace(client)
+	if err != nil {
+		return err
+	}
+
+	deployments := client.AppsV1().Deployments(namespace)
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"kubectl.kubernetes.io/restartedAt":%q}}}}}`,
+		time.Now().Format(time.RFC3339))
+
+	Ctx.Log.Info("rollout " + ramenClusterOperatorName + " on cluster " + cluster.Name)
+
+	deployment, err := deployments.Patch(context.Background(), ramenClusterOperatorName, types.StrategicMergePatchType,
+		[]byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restart %s on cluster %s: %w", ramenClusterOperatorName, cluster.Name, err)
+	}
+
+	generation := deployment.Generation
+	startTime := time.Now()
+
+	for {
+		deployment, err = deployments.Get(context.Background(), ramenClusterOperatorName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		status := deployment.Status
+		if status.ObservedGeneration >= generation && status.UpdatedReplicas == replicas &&
+			status.Replicas == replicas && status.AvailableReplicas == replicas {
+			Ctx.Log.Info(ramenClusterOperatorName + " rolled out on cluster " + cluster.Name)
+
+			return nil
+		}
+
+		if time.Since(startTime) > time.Second*time.Duration(Timeout) {
+			return fmt.Errorf("%s is not rolled out on cluster %s before timeout of %v", ramenClusterOperatorName,
+				cluster.Name, Timeout)
+		}
+
+		Ctx.Log.Info(fmt.Sprintf("%s is not rolled out on cluster %s, retry in %v seconds", ramenClusterOperatorName,
+			cluster.Name, TimeInterval))
+		time.Sleep(time.Second * time.Duration(TimeInterval))
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DR mechanisms of storage types
+const (
+	DRMechanismVolRep   = "volrep"
+	DRMechanismVolSync  = "volsync"
+	DRMechanismExternal = "external"
+)
+
+// Storage is a storage type available on the managed clusters, that the workloads are tested with
+type Storage struct {
+	// Name is the suffix of the paths of the workloads using the storage in the samples repository, such as rbd or
+	// cephfs
+	Name string
+	// StorageClass is the name of the storage class of the storage
+	StorageClass string
+	// DRMechanism is how the data on the storage is replicated: volrep, volsync or external
+	DRMechanism string
+	// Skip are rules matching the tests that are not run with the storage
+	Skip []SkipRule
+}
+
+// SkipRule matches the tests of a deployer and a workload, where an empty name matches any
+type SkipRule struct {
+	Deployer string
+	Workload string
+}
+
+// defaultStorages are the storages tested if none are configured
+var defaultStorages = []Storage{
+	{Name: "rbd", StorageClass: "rook-ceph-block", DRMechanism: DRMechanismVolRep},
+}
+
+// GetStorages returns the configured storages, or the default ones
+func GetStorages() []Storage {
+	if len(config.Storages) == 0 {
+		return defaultStorages
+	}
+
+	return config.Storages
+}
+
+// Skipped returns whether the test of a workload by a deployer is skipped for the storage
+func (s Storage) Skipped(deployerName, workloadName string) bool {
+	for _, rule := range s.Skip {
+		if (rule.Deployer == "" || strings.EqualFold(rule.Deployer, deployerName)) &&
+			(rule.Workload == "" || strings.EqualFold(rule.Workload, workloadName)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateStorages(storages []Storage) error {
+	names := map[string]bool{}
+
+	for _, storage := range storages {
+		if storage.Name == "" {
+			return fmt.Errorf("storage without a name")
+		}
+
+		if names[storage.Name] {
+			return fmt.Errorf("storage %s is configured more than once", storage.Name)
+		}
+
+		names[storage.Name] = true
+
+		switch storage.DRMechanism {
+		case DRMechanismVolRep, DRMechanismVolSync, DRMechanismExternal:
+		default:
+			return fmt.Errorf("storage %s has invalid DR mechanism %q, expected one of %s, %s or %s", storage.Name,
+				storage.DRMechanism, DRMechanismVolRep, DRMechanismVolSync, DRMechanismExternal)
+		}
+	}
+
+	return nil
+}
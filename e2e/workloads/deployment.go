@@ -3,12 +3,16 @@
 
 package workloads
 
+import "github.com/ramendr/ramen/e2e/util"
+
 type Deployment struct {
 	// RepoURL  string
 	Path     string
 	Revision string
 	AppName  string
 	Name     string
+	// Validators check the workload after it is deployed, failed over and relocated
+	Validators []Validator
 }
 
 func (w Deployment) GetAppName() string {
@@ -31,6 +35,20 @@ func (w Deployment) GetRevision() string {
 	return w.Revision
 }
 
+func (w Deployment) GetValidators() []Validator {
+	return w.Validators
+}
+
+// ForStorage returns a copy of the deployment, with its name and path suffixed by the name of a storage, validated
+// to use the storage
+func (w Deployment) ForStorage(storage util.Storage) Workload {
+	w.Name += "-" + storage.Name
+	w.Path += "-" + storage.Name
+	w.Validators = append(append([]Validator{}, w.Validators...), StorageUsed(storage))
+
+	return w
+}
+
 func (w Deployment) Kustomize() error {
 	return nil
 }
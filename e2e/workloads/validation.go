@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: The RamenDR authors
+// SPDX-License-Identifier: Apache-2.0
+
+package workloads
+
+import (
+	"context"
+	"fmt"
+
+	ramen "github.com/ramendr/ramen/api/v1alpha1"
+	"github.com/ramendr/ramen/e2e/util"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Validator checks the correctness of a workload, such as with an HTTP probe, an SQL query or a checksum of its data
+type Validator interface {
+	// Validate checks the workload deployed to namespace on cluster
+	Validate(ctx context.Context, cluster util.Cluster, namespace string) error
+}
+
+// ValidatorFunc is a function that is a Validator
+type ValidatorFunc func(ctx context.Context, cluster util.Cluster, namespace string) error
+
+func (f ValidatorFunc) Validate(ctx context.Context, cluster util.Cluster, namespace string) error {
+	return f(ctx, cluster, namespace)
+}
+
+// ValidatingWorkload is a Workload that carries its own validators, run after it is deployed, failed over and
+// relocated
+type ValidatingWorkload interface {
+	Workload
+
+	GetValidators() []Validator
+}
+
+// Validators returns the validators of a workload, if it has any
+func Validators(w Workload) []Validator {
+	if v, ok := w.(ValidatingWorkload); ok {
+		return v.GetValidators()
+	}
+
+	return nil
+}
+
+// Validate runs the validators of a workload on cluster, and returns the error of the first that fails
+func Validate(ctx context.Context, w Workload, cluster util.Cluster, namespace string) error {
+	for i, validator := range Validators(w) {
+		if err := validator.Validate(ctx, cluster, namespace); err != nil {
+			return fmt.Errorf("workload %s validator %d failed on cluster %s: %w", w.GetName(), i, cluster.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// DeploymentAvailable returns a validator that checks that all the replicas of a deployment are available
+func DeploymentAvailable(name string) Validator {
+	return ValidatorFunc(func(ctx context.Context, cluster util.Cluster, namespace string) error {
+		deployment := &appsv1.Deployment{}
+		if err := cluster.CtrlClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, deployment); err != nil {
+			return err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		if deployment.Status.AvailableReplicas != replicas {
+			return fmt.Errorf("deployment %s/%s has %d of %d replicas available", namespace, name,
+				deployment.Status.AvailableReplicas, replicas)
+		}
+
+		return nil
+	})
+}
+
+// StorageUsed returns a validator that checks that the PVCs of a workload use the storage class of a storage, and,
+// once a VRG protects them, are replicated by the DR mechanism of the storage. The replication of a storage replicated
+// externally is not checked.
+func StorageUsed(storage util.Storage) Validator {
+	return ValidatorFunc(func(ctx context.Context, cluster util.Cluster, namespace string) error {
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		if err := cluster.CtrlClient.List(ctx, pvcs, client.InNamespace(namespace)); err != nil {
+			return err
+		}
+
+		for i := range pvcs.Items {
+			pvc := &pvcs.Items[i]
+			if storage.StorageClass != "" &&
+				(pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName != storage.StorageClass) {
+				return fmt.Errorf("pvc %s/%s does not use storage class %s of storage %s", namespace, pvc.Name,
+					storage.StorageClass, storage.Name)
+			}
+		}
+
+		vrgs := &ramen.VolumeReplicationGroupList{}
+		if err := cluster.CtrlClient.List(ctx, vrgs, client.InNamespace(namespace)); err != nil {
+			return err
+		}
+
+		for i := range vrgs.Items {
+			if err := pvcsReplicatedBy(&vrgs.Items[i], pvcs.Items, storage); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// pvcsReplicatedBy returns an error if a VRG does not protect each PVC with the DR mechanism of a storage
+func pvcsReplicatedBy(vrg *ramen.VolumeReplicationGroup, pvcs []corev1.PersistentVolumeClaim,
+	storage util.Storage,
+) error {
+	if storage.DRMechanism == util.DRMechanismExternal {
+		return nil
+	}
+
+	protectedByVolSync := map[string]bool{}
+	for _, protectedPVC := range vrg.Status.ProtectedPVCs {
+		protectedByVolSync[protectedPVC.Name] = protectedPVC.ProtectedByVolSync
+	}
+
+	for _, pvc := range pvcs {
+		volSync, ok := protectedByVolSync[pvc.Name]
+		if !ok {
+			return fmt.Errorf("pvc %s/%s is not protected by vrg %s yet", pvc.Namespace, pvc.Name, vrg.Name)
+		}
+
+		if volSync != (storage.DRMechanism == util.DRMechanismVolSync) {
+			return fmt.Errorf("pvc %s/%s is not replicated by %s of storage %s", pvc.Namespace, pvc.Name,
+				storage.DRMechanism, storage.Name)
+		}
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@
 
 package workloads
 
+import "github.com/ramendr/ramen/e2e/util"
+
 type Workload interface {
 	// Kustomize() error    // Can differ based on the workload, hence part of the Workload interface
 	// GetResources() error // Get the actual workload resources
@@ -14,3 +16,11 @@ type Workload interface {
 	GetPath() string
 	GetRevision() string
 }
+
+// StorageWorkload is a Workload with a variant for each storage type it can use
+type StorageWorkload interface {
+	Workload
+
+	// ForStorage returns the variant of the workload that uses a storage
+	ForStorage(storage util.Storage) Workload
+}
@@ -0,0 +1,44 @@
+#!/bin/sh
+
+# SPDX-FileCopyrightText: The RamenDR authors
+# SPDX-License-Identifier: Apache-2.0
+
+# Prints the AddOnTemplate that deploys the dr-cluster operator as the ramen-dr-cluster add-on, from the
+# dr-cluster kustomization, for the hub operator's drClusterOperatorAddOn. Its namespace and configuration are
+# left out, as the hub operator deploys them with the DRCluster's ManifestWork.
+#
+# Usage: hack/dr-cluster-addon-template.sh [template name] | kubectl --context hub apply -f -
+
+# shellcheck disable=2086
+set -e
+
+template_name=${1:-ramen-dr-cluster}
+kustomize=${KUSTOMIZE:-kustomize}
+
+cat <<TEMPLATE
+apiVersion: addon.open-cluster-management.io/v1alpha1
+kind: AddOnTemplate
+metadata:
+  name: $template_name
+spec:
+  addonName: ramen-dr-cluster
+  agentSpec:
+    workload:
+      manifests:
+TEMPLATE
+
+$kustomize build --load-restrictor LoadRestrictionsNone "$(dirname $0)"/../config/dr-cluster/default | awk '
+function flush() {
+	if (n > 0 && !skip) {
+		for (i = 1; i <= n; i++) {
+			print (i == 1 ? "      - " : "        ") lines[i]
+		}
+	}
+	n = 0
+	skip = 0
+}
+$0 == "---" { flush(); next }
+/^kind: (Namespace|ConfigMap)$/ { skip = 1 }
+{ lines[++n] = $0 }
+END { flush() }
+'
@@ -7,7 +7,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"sync/atomic"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -17,21 +19,28 @@ import (
 	volrep "github.com/csi-addons/kubernetes-csi-addons/apis/replication.storage/v1alpha1"
 	snapv1 "github.com/kubernetes-csi/external-snapshotter/client/v4/apis/volumesnapshot/v1"
 	clrapiv1beta1 "github.com/open-cluster-management-io/api/cluster/v1beta1"
-	ocmworkv1 "github.com/open-cluster-management/api/work/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	viewv1beta1 "github.com/stolostron/multicloud-operators-foundation/pkg/apis/view/v1beta1"
 	plrv1 "github.com/stolostron/multicloud-operators-placementrule/pkg/apis/apps/v1"
 	velero "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
 	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ocmclv1 "open-cluster-management.io/api/cluster/v1"
+	ocmclv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ocmworkv1 "open-cluster-management.io/api/work/v1"
 	cpcv1 "open-cluster-management.io/config-policy-controller/api/v1"
 	gppv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
 
 	ramendrv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
 
@@ -46,6 +55,8 @@ var (
 	scheme     = runtime.NewScheme()
 	setupLog   = ctrl.Log.WithName("setup")
 	configFile string
+	statusOnly bool
+	vrgFile    string
 )
 
 func init() {
@@ -73,6 +84,12 @@ func bindFlags(bindfuncs ...func(*flag.FlagSet)) {
 		"The controller will load its initial configuration from this file. "+
 			"Omit this flag to use the default configuration values. "+
 			"Command-line flags override configuration from this file.")
+	flag.BoolVar(&statusOnly, "status-only", false,
+		"Run a hub operator replica that serves the metrics and debug endpoints from the status of the resources, "+
+			"without leader election, reconciles or writes.")
+	flag.StringVar(&vrgFile, "validate-vrg", "",
+		"Validate the VolumeReplicationGroup manifest of this file against the cluster, print the PVCs it would "+
+			"select and how it would protect each, and exit, with status 1 if any could not be protected.")
 
 	for _, f := range bindfuncs {
 		f(flag.CommandLine)
@@ -99,6 +116,15 @@ func configureController(ramenConfig *ramendrv1alpha1.RamenConfig) error {
 
 	setupLog.Info("controller type", "type", controllers.ControllerType)
 
+	if ramenConfig.Standalone.Enabled {
+		if controllers.ControllerType != ramendrv1alpha1.DRClusterType || ramenConfig.Standalone.ClusterName == "" {
+			return fmt.Errorf("standalone mode requires controller type %s and a cluster name",
+				ramendrv1alpha1.DRClusterType)
+		}
+
+		setupLog.Info("standalone mode", "clusterName", ramenConfig.Standalone.ClusterName)
+	}
+
 	if controllers.ControllerType == ramendrv1alpha1.DRHubType {
 		utilruntime.Must(plrv1.AddToScheme(scheme))
 		utilruntime.Must(ocmworkv1.AddToScheme(scheme))
@@ -107,6 +133,8 @@ func configureController(ramenConfig *ramendrv1alpha1.RamenConfig) error {
 		utilruntime.Must(gppv1.AddToScheme(scheme))
 		utilruntime.Must(argocdv1alpha1hack.AddToScheme(scheme))
 		utilruntime.Must(clrapiv1beta1.AddToScheme(scheme))
+		utilruntime.Must(ocmclv1.AddToScheme(scheme))
+		utilruntime.Must(ocmclv1beta2.AddToScheme(scheme))
 		utilruntime.Must(recipe.AddToScheme(scheme))
 	} else {
 		utilruntime.Must(velero.AddToScheme(scheme))
@@ -115,11 +143,57 @@ func configureController(ramenConfig *ramendrv1alpha1.RamenConfig) error {
 		utilruntime.Must(snapv1.AddToScheme(scheme))
 		utilruntime.Must(recipe.AddToScheme(scheme))
 		utilruntime.Must(apiextensions.AddToScheme(scheme))
+		utilruntime.Must(operatorsv1alpha1.AddToScheme(scheme))
 	}
 
 	return nil
 }
 
+// validateVRG validates the VolumeReplicationGroup manifest of a file against the cluster, prints the validation,
+// and returns the exit status: 1 if a PVC could not be protected, 2 if the validation could not run
+func validateVRG(path string, ramenConfig *ramendrv1alpha1.RamenConfig) int {
+	utilruntime.Must(volrep.AddToScheme(scheme))
+	utilruntime.Must(recipe.AddToScheme(scheme))
+
+	vrg := &ramendrv1alpha1.VolumeReplicationGroup{}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = yaml.UnmarshalStrict(data, vrg)
+	}
+
+	if err != nil {
+		setupLog.Error(err, "unable to read VolumeReplicationGroup manifest", "file", path)
+
+		return 2
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+
+		return 2
+	}
+
+	validation := controllers.VRGValidate(context.Background(), c, vrg, ramenConfig,
+		ctrl.Log.WithName("validate").WithName("VolumeReplicationGroup"))
+
+	out, err := yaml.Marshal(validation)
+	if err != nil {
+		setupLog.Error(err, "unable to marshal validation")
+
+		return 2
+	}
+
+	fmt.Print(string(out))
+
+	if validation.Unprotectable() {
+		return 1
+	}
+
+	return 0
+}
+
 func newManager(options *ctrl.Options) (ctrl.Manager, error) {
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), *options)
 	if err != nil {
@@ -129,17 +203,119 @@ func newManager(options *ctrl.Options) (ctrl.Manager, error) {
 	return mgr, nil
 }
 
-func setupReconcilers(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.RamenConfig) {
+// configureDebugHandlers adds the debug endpoints to the metrics server; the DRPC debug endpoint serves once the
+// DRPC reconciler is set up
+func configureDebugHandlers(options *ctrl.Options,
+	ramenConfig *ramendrv1alpha1.RamenConfig,
+) *controllers.DRPCDebugHandler {
+	drpcDebugHandler := &controllers.DRPCDebugHandler{}
+
+	if options.Metrics.ExtraHandlers == nil {
+		options.Metrics.ExtraHandlers = map[string]http.Handler{}
+	}
+
+	if controllers.ControllerType == ramendrv1alpha1.DRHubType {
+		options.Metrics.ExtraHandlers[controllers.DRPCDebugPath] = drpcDebugHandler
+	}
+
+	if ramenConfig.Profiling != nil {
+		for path, handler := range controllers.ProfilingHandlers() {
+			options.Metrics.ExtraHandlers[path] = handler
+		}
+	}
+
+	return drpcDebugHandler
+}
+
+// configureStatusOnly configures the manager of a status-only replica of the hub operator: without leader
+// election, so that it runs alongside the leader, and with a client whose writes fail
+func configureStatusOnly(options *ctrl.Options) error {
+	if controllers.ControllerType != ramendrv1alpha1.DRHubType {
+		return fmt.Errorf("status-only replicas require controller type %s", ramendrv1alpha1.DRHubType)
+	}
+
+	options.LeaderElection = false
+	options.NewClient = rmnutil.NewReadOnlyClient
+
+	setupLog.Info("status-only replica")
+
+	return nil
+}
+
+// setupStatusOnly sets up the status replica reconciler of DRPCs, for their metrics, and the DRPC debug endpoint
+func setupStatusOnly(mgr ctrl.Manager, drpcDebugHandler *controllers.DRPCDebugHandler) {
+	if err := (&controllers.DRPCStatusReplicaReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DRPCStatusReplica"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DRPCStatusReplica")
+		os.Exit(1)
+	}
+
+	drpcDebugHandler.Reconciler = &controllers.DRPlacementControlReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
+		MCVGetter: rmnutil.ManagedClusterViewGetterImpl{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+		},
+		Scheme: mgr.GetScheme(),
+	}
+}
+
+func setupReconcilers(mgr ctrl.Manager, options *ctrl.Options, ramenConfig *ramendrv1alpha1.RamenConfig,
+	drpcDebugHandler *controllers.DRPCDebugHandler, restart func(),
+) {
+	ramenConfigReconciler := &controllers.RamenConfigReconciler{
+		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
+		Log:           ctrl.Log.WithName("controllers").WithName("RamenConfig"),
+		StartupConfig: ramenConfig,
+		Restart:       restart,
+	}
+	if err := ramenConfigReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RamenConfig")
+		os.Exit(1)
+	}
+
+	if ramenConfig.Profiling != nil {
+		if err := (&controllers.Profiler{
+			APIReader:      mgr.GetAPIReader(),
+			ObjStoreGetter: controllers.S3ObjectStoreGetter(),
+			Log:            ctrl.Log.WithName("controllers").WithName("Profiler"),
+			Profiling:      *ramenConfig.Profiling,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create runnable", "runnable", "Profiler")
+			os.Exit(1)
+		}
+	}
+
 	if controllers.ControllerType == ramendrv1alpha1.DRHubType {
-		setupReconcilersHub(mgr)
+		leaderElectionGroups, err := controllers.NewLeaderElectionGroups(mgr, options,
+			ramenConfig.LeaderElectionGroups, ctrl.Log.WithName("controllers").WithName("LeaderElectionGroups"))
+		if err != nil {
+			setupLog.Error(err, "unable to create leader election groups")
+			os.Exit(1)
+		}
+
+		setupReconcilersHub(mgr, ramenConfig, leaderElectionGroups, drpcDebugHandler)
+
+		if unused := leaderElectionGroups.Unused(); len(unused) != 0 {
+			setupLog.Error(nil, "leader election group controllers not set up", "controllers", unused)
+			os.Exit(1)
+		}
 	}
 
 	if controllers.ControllerType == ramendrv1alpha1.DRClusterType {
-		setupReconcilersCluster(mgr, ramenConfig)
+		setupReconcilersCluster(mgr, ramenConfig, ramenConfigReconciler.ConfigAppliedCondition)
 	}
 }
 
-func setupReconcilersCluster(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.RamenConfig) {
+func setupReconcilersCluster(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.RamenConfig,
+	configAppliedCondition func() *metav1.Condition,
+) {
 	if err := (&controllers.ProtectedVolumeReplicationGroupListReconciler{
 		Client:         mgr.GetClient(),
 		Scheme:         mgr.GetScheme(),
@@ -156,6 +332,11 @@ func setupReconcilersCluster(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.Rame
 		os.Exit(1)
 	}
 
+	if err := controllers.ArrayReplicationPluginsRegister(ramenConfig.ArrayReplicationPlugins); err != nil {
+		setupLog.Error(err, "unable to register array replication plugins")
+		os.Exit(1)
+	}
+
 	if err := (&controllers.VolumeReplicationGroupReconciler{
 		Client:         mgr.GetClient(),
 		APIReader:      mgr.GetAPIReader(),
@@ -166,20 +347,49 @@ func setupReconcilersCluster(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.Rame
 		setupLog.Error(err, "unable to create controller", "controller", "VolumeReplicationGroup")
 		os.Exit(1)
 	}
+
+	if err := (&controllers.DRClusterOperatorStatusReporter{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DRClusterOperatorStatusReporter"),
+		Interval:  controllers.DRClusterOperatorStatusReportIntervalFor(ramenConfig),
+
+		ConfigAppliedCondition: configAppliedCondition,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create runnable", "runnable", "DRClusterOperatorStatusReporter")
+		os.Exit(1)
+	}
+
+	if ramenConfig.KubeObjectProtection.RecipeValidationWebhookEnabled {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(&recipe.Recipe{}).WithValidator(&controllers.RecipeValidator{
+			Reader:      mgr.GetAPIReader(),
+			RamenConfig: ramenConfig,
+			Log:         ctrl.Log.WithName("webhooks").WithName("Recipe"),
+		}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Recipe")
+			os.Exit(1)
+		}
+	}
 }
 
-func setupReconcilersHub(mgr ctrl.Manager) {
+// setupReconcilersHub sets up the hub controllers, each with the manager of its leader election group
+func setupReconcilersHub(mgr ctrl.Manager, ramenConfig *ramendrv1alpha1.RamenConfig,
+	leaderElectionGroups *controllers.LeaderElectionGroups, drpcDebugHandler *controllers.DRPCDebugHandler,
+) {
 	if err := (&controllers.DRPolicyReconciler{
 		Client:            mgr.GetClient(),
 		APIReader:         mgr.GetAPIReader(),
 		Log:               ctrl.Log.WithName("controllers").WithName("DRPolicy"),
 		Scheme:            mgr.GetScheme(),
 		ObjectStoreGetter: controllers.S3ObjectStoreGetter(),
-	}).SetupWithManager(mgr); err != nil {
+	}).SetupWithManager(leaderElectionGroups.Manager("DRPolicy")); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DRPolicy")
 		os.Exit(1)
 	}
 
+	clusterBreaker := rmnutil.NewClusterCircuitBreaker(rmnutil.ClusterCircuitBreakerFailureThreshold,
+		rmnutil.ClusterCircuitBreakerBaseBackoff, rmnutil.ClusterCircuitBreakerMaxBackoff)
+
 	if err := (&controllers.DRClusterReconciler{
 		Client:    mgr.GetClient(),
 		APIReader: mgr.GetAPIReader(),
@@ -188,28 +398,90 @@ func setupReconcilersHub(mgr ctrl.Manager) {
 		MCVGetter: rmnutil.ManagedClusterViewGetterImpl{
 			Client:    mgr.GetClient(),
 			APIReader: mgr.GetAPIReader(),
+			Breaker:   clusterBreaker,
 		},
 		ObjectStoreGetter: controllers.S3ObjectStoreGetter(),
-	}).SetupWithManager(mgr); err != nil {
+		ClusterBreaker:    clusterBreaker,
+	}).SetupWithManager(leaderElectionGroups.Manager("DRCluster")); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DRCluster")
 		os.Exit(1)
 	}
 
-	if err := (&controllers.DRPlacementControlReconciler{
+	drpcReconciler := &controllers.DRPlacementControlReconciler{
 		Client:    mgr.GetClient(),
 		APIReader: mgr.GetAPIReader(),
 		Log:       ctrl.Log.WithName("controllers").WithName("DRPlacementControl"),
 		MCVGetter: rmnutil.ManagedClusterViewGetterImpl{
 			Client:    mgr.GetClient(),
 			APIReader: mgr.GetAPIReader(),
+			Breaker:   clusterBreaker,
 		},
 		Scheme:         mgr.GetScheme(),
 		Callback:       func(string, string) {},
 		ObjStoreGetter: controllers.S3ObjectStoreGetter(),
-	}).SetupWithManager(mgr); err != nil {
+		ClusterBreaker: clusterBreaker,
+	}
+	if err := drpcReconciler.SetupWithManager(leaderElectionGroups.Manager("DRPlacementControl")); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DRPlacementControl")
 		os.Exit(1)
 	}
+
+	drpcDebugHandler.Reconciler = drpcReconciler
+
+	if err := (&controllers.MaintenanceRelocateReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("MaintenanceRelocate"),
+	}).SetupWithManager(leaderElectionGroups.Manager("MaintenanceRelocate")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaintenanceRelocate")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.DRBulkActionReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DRBulkAction"),
+	}).SetupWithManager(leaderElectionGroups.Manager("DRBulkAction")); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DRBulkAction")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.DRPCJanitor{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       ctrl.Log.WithName("controllers").WithName("DRPCJanitor"),
+	}).SetupWithManager(leaderElectionGroups.Manager("DRPCJanitor")); err != nil {
+		setupLog.Error(err, "unable to create runnable", "runnable", "DRPCJanitor")
+		os.Exit(1)
+	}
+	if ramenConfig.DRPCActionPermissionsWebhookEnabled {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(&ramendrv1alpha1.DRPlacementControl{}).
+			WithValidator(&controllers.DRPCActionValidator{
+				APIReader: mgr.GetAPIReader(),
+				Log:       ctrl.Log.WithName("webhooks").WithName("DRPlacementControl"),
+			}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DRPlacementControl")
+			os.Exit(1)
+		}
+	}
+
+	if ramenConfig.DRPCDefaultDRPolicyWebhookEnabled {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(&ramendrv1alpha1.DRPlacementControl{}).
+			WithDefaulter(&controllers.DRPCDefaulter{
+				APIReader: mgr.GetAPIReader(),
+				Log:       ctrl.Log.WithName("webhooks").WithName("DRPlacementControl"),
+			}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DRPlacementControl")
+			os.Exit(1)
+		}
+	}
+
+	if ramenConfig.DRPCApprovalWebhookEnabled {
+		mgr.GetWebhookServer().Register(controllers.DRPCApprovalWebhookPath, admission.WithCustomValidator(
+			mgr.GetScheme(), &ramendrv1alpha1.DRPlacementControl{}, &controllers.DRPCApprovalValidator{
+				Log: ctrl.Log.WithName("webhooks").WithName("DRPlacementControlApproval"),
+			}))
+	}
 }
 
 func main() {
@@ -217,22 +489,50 @@ func main() {
 	bindFlags(logOpts.BindFlags)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(logOpts)))
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(logOpts), zap.RawZapOpts(uberzap.WrapCore(
+		func(core zapcore.Core) zapcore.Core { return controllers.LogCore(core, logOpts) },
+	))))
 
 	ctrlOptions, ramenConfig := buildOptions()
 
+	if vrgFile != "" {
+		os.Exit(validateVRG(vrgFile, ramenConfig))
+	}
+
 	if err := configureController(ramenConfig); err != nil {
 		setupLog.Error(err, "unable to configure controller")
 		os.Exit(1)
 	}
 
+	if statusOnly {
+		if err := configureStatusOnly(ctrlOptions); err != nil {
+			setupLog.Error(err, "unable to configure status-only replica")
+			os.Exit(1)
+		}
+	}
+
+	drpcDebugHandler := configureDebugHandlers(ctrlOptions, ramenConfig)
+
 	mgr, err := newManager(ctrlOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to Get new manager")
 		os.Exit(1)
 	}
 
-	setupReconcilers(mgr, ramenConfig)
+	// The manager is stopped to restart the operator when a configuration change requires it, and the operator then
+	// exits with an error, so that a restart loop shows as one
+	ctx, cancel := context.WithCancel(ctrl.SetupSignalHandler())
+	restarting := &atomic.Bool{}
+	restart := func() {
+		restarting.Store(true)
+		cancel()
+	}
+
+	if statusOnly {
+		setupStatusOnly(mgr, drpcDebugHandler)
+	} else {
+		setupReconcilers(mgr, ctrlOptions, ramenConfig, drpcDebugHandler, restart)
+	}
 
 	// +kubebuilder:scaffold:builder
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
@@ -247,8 +547,13 @@ func main() {
 
 	setupLog.Info("starting manager")
 
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	if restarting.Load() {
+		setupLog.Error(nil, "exiting to restart with the latest configuration")
+		os.Exit(1)
+	}
 }
@@ -11,6 +11,9 @@ from . import (
     undeploy,
     config,
     unconfig,
+    generations,
+    preview,
+    inventory,
 )
 
 LOG_FORMAT = "%(asctime)s %(levelname)-7s [%(name)s] %(message)s"
@@ -20,6 +23,9 @@ COMMANDS = [
     undeploy,
     config,
     unconfig,
+    generations,
+    preview,
+    inventory,
 ]
 
 log = logging.getLogger("ramenctl")
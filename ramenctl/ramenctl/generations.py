@@ -0,0 +1,93 @@
+# SPDX-FileCopyrightText: The RamenDR authors
+# SPDX-License-Identifier: Apache-2.0
+
+import json
+import time
+
+from drenv import kubectl
+
+from . import command
+
+ANNOTATION = "drplacementcontrol.ramendr.openshift.io/state-generations"
+
+
+def register(commands):
+    parser = commands.add_parser(
+        "generations",
+        help="List the protected state generations stored for a DRPC",
+    )
+    parser.set_defaults(func=run)
+    command.add_common_arguments(parser)
+    parser.add_argument(
+        "--namespace",
+        required=True,
+        help="The DRPC namespace",
+    )
+    parser.add_argument(
+        "--timeout",
+        type=int,
+        default=60,
+        help="Seconds to wait for the listing (default 60)",
+    )
+    parser.add_argument(
+        "drpc",
+        help="The DRPC name",
+    )
+
+
+def run(args):
+    env = command.env_info(args)
+    if not env["hub"]:
+        raise RuntimeError("State generations are listed by the hub")
+
+    listing = list_state_generations(env["hub"], args)
+
+    print(
+        f"{'PROFILE':<20} {'KIND':<28} {'NAME':<6} {'OBJECTS':>7} {'BYTES':>12} "
+        "LAST MODIFIED"
+    )
+    for g in listing.get("generations", []):
+        print(
+            f"{g['s3ProfileName']:<20} {g['kind']:<28} {g.get('name', ''):<6} "
+            f"{g['objects']:>7} {g['sizeBytes']:>12} {g.get('lastModified', '')}"
+        )
+
+    for error in listing.get("errors", []):
+        command.info("Listing failed: %s", error)
+
+
+def list_state_generations(hub, args):
+    """
+    Request a new listing of the state generations of the DRPC and return it
+    once the DRPC reports it.
+    """
+    drpc = f"drpc/{args.drpc}"
+    request = str(time.time_ns())
+
+    command.info("Requesting state generations of %s/%s", args.namespace, args.drpc)
+    kubectl.annotate(
+        drpc,
+        {ANNOTATION: request},
+        overwrite=True,
+        namespace=args.namespace,
+        context=hub,
+        log=command.debug,
+    )
+
+    command.debug("Waiting until %s reports request %s", drpc, request)
+    kubectl.wait(
+        drpc,
+        f"--for=jsonpath={{.status.stateGenerations.request}}={request}",
+        f"--namespace={args.namespace}",
+        f"--timeout={args.timeout}s",
+        context=hub,
+        log=command.debug,
+    )
+
+    out = kubectl.get(
+        drpc,
+        "--output=jsonpath={.status.stateGenerations}",
+        f"--namespace={args.namespace}",
+        context=hub,
+    )
+    return json.loads(out)
@@ -0,0 +1,121 @@
+# SPDX-FileCopyrightText: The RamenDR authors
+# SPDX-License-Identifier: Apache-2.0
+
+import csv
+import json
+import sys
+
+from drenv import kubectl
+
+from . import command
+
+FIELDS = [
+    "namespace",
+    "name",
+    "drPolicy",
+    "schedulingInterval",
+    "clusters",
+    "currentCluster",
+    "phase",
+    "lastGroupSyncTime",
+    "lastDrillTime",
+    "health",
+]
+
+
+def register(commands):
+    parser = commands.add_parser(
+        "inventory",
+        help="Export the inventory of the protected applications",
+    )
+    parser.set_defaults(func=run)
+    command.add_common_arguments(parser)
+    parser.add_argument(
+        "--format",
+        choices=["csv", "json"],
+        default="csv",
+        help="The inventory format (default csv)",
+    )
+    parser.add_argument(
+        "--output",
+        help="The file to write the inventory to (default standard output)",
+    )
+
+
+def run(args):
+    env = command.env_info(args)
+    if not env["hub"]:
+        raise RuntimeError("The protected applications are listed by the hub")
+
+    applications = inventory(env["hub"])
+    command.info("Exporting %d protected applications", len(applications))
+
+    if args.output:
+        with open(args.output, "w", newline="") as f:
+            write(f, applications, args.format)
+    else:
+        write(sys.stdout, applications, args.format)
+
+
+def inventory(hub):
+    """
+    Return the protected applications of the hub, one for each DRPC, with its
+    DRPolicy, the clusters the DRPolicy protects it on, the cluster it runs
+    on, and its last sync, drill and health.
+    """
+    policies = {
+        p["metadata"]["name"]: p for p in list_resources("drpolicy", hub)["items"]
+    }
+
+    applications = []
+    for drpc in list_resources("drpc", hub, all_namespaces=True)["items"]:
+        spec = drpc["spec"]
+        status = drpc.get("status", {})
+        policy_name = spec["drPolicyRef"]["name"]
+        policy = policies.get(policy_name, {}).get("spec", {})
+        preparation = status.get("failoverPreparation", {})
+
+        applications.append(
+            {
+                "namespace": drpc["metadata"]["namespace"],
+                "name": drpc["metadata"]["name"],
+                "drPolicy": policy_name,
+                "schedulingInterval": policy.get("schedulingInterval", ""),
+                "clusters": policy.get("drClusters", []),
+                "currentCluster": status.get("preferredDecision", {}).get(
+                    "clusterName", ""
+                ),
+                "phase": status.get("phase", ""),
+                "lastGroupSyncTime": status.get("lastGroupSyncTime", ""),
+                # Failover drills prepare the failover cluster without
+                # moving the workload.
+                "lastDrillTime": (
+                    preparation.get("lastTransitionTime", "")
+                    if preparation.get("prepared")
+                    else ""
+                ),
+                "health": status.get("health", ""),
+            }
+        )
+
+    applications.sort(key=lambda a: (a["namespace"], a["name"]))
+    return applications
+
+
+def list_resources(kind, hub, all_namespaces=False):
+    args = [kind, "--output=json"]
+    if all_namespaces:
+        args.append("--all-namespaces")
+    return json.loads(kubectl.get(*args, context=hub))
+
+
+def write(f, applications, format):
+    if format == "json":
+        json.dump(applications, f, indent=2)
+        f.write("\n")
+        return
+
+    writer = csv.DictWriter(f, fieldnames=FIELDS)
+    writer.writeheader()
+    for application in applications:
+        writer.writerow({**application, "clusters": " ".join(application["clusters"])})
@@ -0,0 +1,113 @@
+# SPDX-FileCopyrightText: The RamenDR authors
+# SPDX-License-Identifier: Apache-2.0
+
+import copy
+import io
+import json
+
+from ramenctl import inventory
+
+POLICY = {
+    "metadata": {"name": "dr-policy"},
+    "spec": {"schedulingInterval": "5m", "drClusters": ["dr1", "dr2"]},
+}
+
+DRPCS = [
+    {
+        "metadata": {"namespace": "app2", "name": "busybox"},
+        "spec": {"drPolicyRef": {"name": "missing-policy"}},
+    },
+    {
+        "metadata": {"namespace": "app1", "name": "busybox"},
+        "spec": {"drPolicyRef": {"name": "dr-policy"}},
+        "status": {
+            "preferredDecision": {"clusterName": "dr1"},
+            "phase": "Deployed",
+            "lastGroupSyncTime": "2024-01-02T03:04:05Z",
+            "failoverPreparation": {
+                "prepared": True,
+                "lastTransitionTime": "2024-01-01T00:00:00Z",
+            },
+            "health": "Healthy",
+        },
+    },
+]
+
+
+def fake_list_resources(drpcs):
+    def list_resources(kind, hub, all_namespaces=False):
+        assert hub == "hub"
+        if kind == "drpolicy":
+            return {"items": [POLICY]}
+        assert all_namespaces
+        return {"items": drpcs}
+
+    return list_resources
+
+
+def test_inventory(monkeypatch):
+    monkeypatch.setattr(inventory, "list_resources", fake_list_resources(DRPCS))
+    assert inventory.inventory("hub") == [
+        {
+            "namespace": "app1",
+            "name": "busybox",
+            "drPolicy": "dr-policy",
+            "schedulingInterval": "5m",
+            "clusters": ["dr1", "dr2"],
+            "currentCluster": "dr1",
+            "phase": "Deployed",
+            "lastGroupSyncTime": "2024-01-02T03:04:05Z",
+            "lastDrillTime": "2024-01-01T00:00:00Z",
+            "health": "Healthy",
+        },
+        {
+            "namespace": "app2",
+            "name": "busybox",
+            "drPolicy": "missing-policy",
+            "schedulingInterval": "",
+            "clusters": [],
+            "currentCluster": "",
+            "phase": "",
+            "lastGroupSyncTime": "",
+            "lastDrillTime": "",
+            "health": "",
+        },
+    ]
+
+
+def test_inventory_unprepared_drill(monkeypatch):
+    drpc = copy.deepcopy(DRPCS[1])
+    drpc["status"]["failoverPreparation"]["prepared"] = False
+    monkeypatch.setattr(inventory, "list_resources", fake_list_resources([drpc]))
+    assert inventory.inventory("hub")[0]["lastDrillTime"] == ""
+
+
+def test_write_csv():
+    applications = [
+        {
+            "namespace": "app1",
+            "name": "busybox",
+            "drPolicy": "dr-policy",
+            "schedulingInterval": "5m",
+            "clusters": ["dr1", "dr2"],
+            "currentCluster": "dr1",
+            "phase": "Deployed",
+            "lastGroupSyncTime": "",
+            "lastDrillTime": "",
+            "health": "Healthy",
+        }
+    ]
+    f = io.StringIO()
+    inventory.write(f, applications, "csv")
+    assert f.getvalue().splitlines() == [
+        ",".join(inventory.FIELDS),
+        "app1,busybox,dr-policy,5m,dr1 dr2,dr1,Deployed,,,Healthy",
+    ]
+
+
+def test_write_json():
+    applications = [{"namespace": "app1", "clusters": ["dr1", "dr2"]}]
+    f = io.StringIO()
+    inventory.write(f, applications, "json")
+    assert json.loads(f.getvalue()) == applications
+    assert f.getvalue().endswith("\n")
@@ -0,0 +1,98 @@
+# SPDX-FileCopyrightText: The RamenDR authors
+# SPDX-License-Identifier: Apache-2.0
+
+import json
+import time
+
+from drenv import kubectl
+
+from . import command
+
+ANNOTATION = "drplacementcontrol.ramendr.openshift.io/restore-preview"
+
+
+def register(commands):
+    parser = commands.add_parser(
+        "preview",
+        help="Preview the recovery of the kube objects of a DRPC on its failover cluster",
+    )
+    parser.set_defaults(func=run)
+    command.add_common_arguments(parser)
+    parser.add_argument(
+        "--namespace",
+        required=True,
+        help="The DRPC namespace",
+    )
+    parser.add_argument(
+        "--timeout",
+        type=int,
+        default=300,
+        help="Seconds to wait for the preview (default 300)",
+    )
+    parser.add_argument(
+        "drpc",
+        help="The DRPC name",
+    )
+
+
+def run(args):
+    env = command.env_info(args)
+    if not env["hub"]:
+        raise RuntimeError("Restores are previewed through the hub")
+
+    preview = restore_preview(env["hub"], args)
+
+    command.info(
+        "Capture %s of %s objects previewed on cluster %s: %s conflicts",
+        preview.get("captureNumber", 0),
+        preview.get("objects", 0),
+        preview.get("cluster", ""),
+        preview.get("conflictCount", 0),
+    )
+
+    print(f"{'REASON':<16} {'KIND':<40} {'NAME':<50} MESSAGE")
+    for c in preview.get("conflicts", []):
+        print(
+            f"{c['reason']:<16} {c['kind']:<40} {c.get('name', ''):<50} "
+            f"{c.get('message', '')}"
+        )
+
+    for error in preview.get("errors", []):
+        command.info("Preview failed: %s", error)
+
+
+def restore_preview(hub, args):
+    """
+    Request a new restore preview of the DRPC and return it once the DRPC
+    reports it.
+    """
+    drpc = f"drpc/{args.drpc}"
+    request = str(time.time_ns())
+
+    command.info("Requesting restore preview of %s/%s", args.namespace, args.drpc)
+    kubectl.annotate(
+        drpc,
+        {ANNOTATION: request},
+        overwrite=True,
+        namespace=args.namespace,
+        context=hub,
+        log=command.debug,
+    )
+
+    command.debug("Waiting until %s reports request %s", drpc, request)
+    kubectl.wait(
+        drpc,
+        f"--for=jsonpath={{.status.restorePreview.request}}={request}",
+        f"--namespace={args.namespace}",
+        f"--timeout={args.timeout}s",
+        context=hub,
+        log=command.debug,
+    )
+
+    out = kubectl.get(
+        drpc,
+        "--output=jsonpath={.status.restorePreview}",
+        f"--namespace={args.namespace}",
+        context=hub,
+    )
+    return json.loads(out)